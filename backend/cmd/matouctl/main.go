@@ -0,0 +1,334 @@
+// Command matouctl runs operator tasks against a MATOU deployment.
+//
+// Usage:
+//
+//	matouctl snapshot -out matou.snapshot.tar.gz
+//	matouctl verify matou.snapshot.tar.gz
+//	matouctl restore [-force] [-config-dir config] matou.snapshot.tar.gz
+//	matouctl migrate [-dry-run]
+//
+// The snapshot passphrase, which encrypts the identity and space keys, is
+// read from MATOU_SNAPSHOT_PASSPHRASE or -passphrase-file. The data
+// directory and at-rest passphrase are found the same way as the server
+// finds them. Stop the server, or put it in maintenance mode, before
+// taking a snapshot, and stop it before restoring or migrating.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/config"
+	"github.com/matou-dao/backend/internal/dataformat"
+	"github.com/matou-dao/backend/internal/secret"
+	"github.com/matou-dao/backend/internal/snapshot"
+)
+
+const usage = `Usage: matouctl <command> [flags]
+
+Commands:
+  snapshot   archive the data directory, keys and configs
+  verify     check a snapshot's integrity and passphrase without restoring
+  restore    restore a snapshot into the data directory
+  migrate    upgrade the data directory to this release's data format
+
+Run "matouctl <command> -h" for the command's flags.
+`
+
+// configFiles collects repeated -config flags
+type configFiles []string
+
+func (c *configFiles) String() string     { return strings.Join(*c, ",") }
+func (c *configFiles) Set(v string) error { *c = append(*c, v); return nil }
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "snapshot":
+		err = runSnapshot(os.Args[2:])
+	case "verify":
+		err = runVerify(os.Args[2:])
+	case "restore":
+		err = runRestore(os.Args[2:])
+	case "migrate":
+		err = runMigrate(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "matouctl %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+// resolveDataDir matches the server: MATOU_DATA_DIR, else ./data or
+// ./data-test
+func resolveDataDir(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if dir := os.Getenv("MATOU_DATA_DIR"); dir != "" {
+		return dir
+	}
+	if os.Getenv("MATOU_ENV") == "test" {
+		return "./data-test"
+	}
+	return "./data"
+}
+
+// defaultConfigFiles is the any-sync client config the server would load
+func defaultConfigFiles() []string {
+	if path := os.Getenv("MATOU_ANYSYNC_CONFIG"); path != "" {
+		return []string{path}
+	}
+	switch os.Getenv("MATOU_ENV") {
+	case "test":
+		return []string{"config/client-test.yml"}
+	case "production":
+		return []string{"config/client-production.yml"}
+	default:
+		return []string{"config/client-dev.yml"}
+	}
+}
+
+// snapshotPassphrase reads the snapshot passphrase from file, or else
+// MATOU_SNAPSHOT_PASSPHRASE
+func snapshotPassphrase(file string) (string, error) {
+	if file == "" {
+		if passphrase := os.Getenv("MATOU_SNAPSHOT_PASSPHRASE"); passphrase != "" {
+			return passphrase, nil
+		}
+		return "", fmt.Errorf("set MATOU_SNAPSHOT_PASSPHRASE or -passphrase-file")
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("reading passphrase file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// loadAtRest returns the at-rest sealer the server would use
+func loadAtRest() (*config.Config, *secret.Sealer, error) {
+	cfg, err := config.Load("", "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading config: %w", err)
+	}
+	passphrase, err := cfg.AtRest.ResolvePassphrase()
+	if err != nil {
+		return nil, nil, err
+	}
+	sealer, err := secret.NewSealer(passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, sealer, nil
+}
+
+func printManifest(m *snapshot.Manifest) {
+	var size int64
+	for _, f := range m.Files {
+		size += f.Size
+	}
+	fmt.Printf("   Format: %s\n", m.Format)
+	fmt.Printf("   Created: %s\n", m.CreatedAt.Format(time.RFC3339))
+	fmt.Printf("   Files: %d (%d bytes)\n", len(m.Files), size)
+	if m.Keys != nil {
+		fmt.Printf("   Keys: identity and %d space key set(s)\n", m.Keys.SpaceKeys)
+	} else {
+		fmt.Println("   Keys: none (no identity configured)")
+	}
+}
+
+func runSnapshot(args []string) error {
+	fs := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	out := fs.String("out", "", "archive to write (default matou-snapshot-{time}.tar.gz)")
+	dataDirFlag := fs.String("data-dir", "", "data directory (defaults to MATOU_DATA_DIR, ./data or ./data-test)")
+	passphraseFile := fs.String("passphrase-file", "", "file holding the snapshot passphrase")
+	var configs configFiles
+	fs.Var(&configs, "config", "config file to include (repeatable; defaults to the any-sync client config)")
+	fs.Parse(args)
+
+	passphrase, err := snapshotPassphrase(*passphraseFile)
+	if err != nil {
+		return err
+	}
+	cfg, atRest, err := loadAtRest()
+	if err != nil {
+		return err
+	}
+	if len(configs) == 0 {
+		for _, file := range defaultConfigFiles() {
+			if _, err := os.Stat(file); err == nil {
+				configs = append(configs, file)
+			}
+		}
+	}
+
+	// Rotated log files share the log file's prefix
+	var exclude []string
+	if logPath := cfg.Server.LogFile.Path; logPath != "" && !filepath.IsAbs(logPath) {
+		logPath = filepath.ToSlash(filepath.Clean(logPath))
+		exclude = append(exclude, logPath, strings.TrimSuffix(logPath, path.Ext(logPath))+"-*")
+	}
+
+	now := time.Now()
+	if *out == "" {
+		*out = fmt.Sprintf("matou-snapshot-%s.tar.gz", now.UTC().Format("20060102T150405Z"))
+	}
+	dataDir := resolveDataDir(*dataDirFlag)
+	fmt.Printf("Snapshotting %s to %s...\n", dataDir, *out)
+
+	// Write next to the target and rename, so a failed run never leaves a
+	// partial archive that looks like a snapshot
+	tmp := *out + ".partial"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	manifest, err := snapshot.Create(f, snapshot.Options{
+		DataDir:     dataDir,
+		ConfigFiles: configs,
+		Exclude:     exclude,
+		AtRest:      atRest,
+		Passphrase:  passphrase,
+		Now:         now,
+	})
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, *out)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	fmt.Println("  Snapshot written")
+	printManifest(manifest)
+	return nil
+}
+
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	passphraseFile := fs.String("passphrase-file", "", "file holding the snapshot passphrase")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected one snapshot archive")
+	}
+
+	passphrase, err := snapshotPassphrase(*passphraseFile)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Printf("Verifying %s...\n", fs.Arg(0))
+	manifest, err := snapshot.Verify(f, passphrase)
+	if err != nil {
+		return err
+	}
+	fmt.Println("  Snapshot matches its manifest")
+	printManifest(manifest)
+	return nil
+}
+
+func runRestore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	dataDirFlag := fs.String("data-dir", "", "data directory (defaults to MATOU_DATA_DIR, ./data or ./data-test)")
+	configDir := fs.String("config-dir", "config", "directory to restore config files into (empty to skip them)")
+	passphraseFile := fs.String("passphrase-file", "", "file holding the snapshot passphrase")
+	force := fs.Bool("force", false, "replace a data directory that isn't empty (the old one is kept aside)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected one snapshot archive")
+	}
+
+	passphrase, err := snapshotPassphrase(*passphraseFile)
+	if err != nil {
+		return err
+	}
+	_, atRest, err := loadAtRest()
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dataDir := resolveDataDir(*dataDirFlag)
+	fmt.Printf("Restoring %s into %s...\n", fs.Arg(0), dataDir)
+	manifest, err := snapshot.Restore(f, snapshot.RestoreOptions{
+		DataDir:    dataDir,
+		ConfigDir:  *configDir,
+		AtRest:     atRest,
+		Passphrase: passphrase,
+		Force:      *force,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println("  Snapshot restored; start the server to use it")
+	printManifest(manifest)
+	return nil
+}
+
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dataDirFlag := fs.String("data-dir", "", "data directory (defaults to MATOU_DATA_DIR, ./data or ./data-test)")
+	dryRun := fs.Bool("dry-run", false, "list the migrations without applying them")
+	fs.Parse(args)
+
+	dataDir := resolveDataDir(*dataDirFlag)
+	if _, err := os.Stat(dataDir); err != nil {
+		return err
+	}
+	format, steps, err := dataformat.Plan(dataDir)
+	if err != nil {
+		return err
+	}
+	if len(steps) == 0 {
+		fmt.Printf("%s is already in data format %d\n", dataDir, format)
+		return nil
+	}
+
+	fmt.Printf("%s is in data format %d; %d migration(s) to format %d:\n", dataDir, format, len(steps), dataformat.Current)
+	for _, step := range steps {
+		fmt.Printf("   %d -> %d: %s\n", step.From, step.From+1, step.Description)
+	}
+	if *dryRun {
+		return nil
+	}
+
+	fmt.Println("Migrating (make sure the server is stopped and a snapshot is taken)...")
+	_, err = dataformat.Migrate(dataDir, func(step dataformat.Migration) {
+		fmt.Printf("  Format %d -> %d...\n", step.From, step.From+1)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  Data directory is in format %d; start the server to use it\n", dataformat.Current)
+	return nil
+}
@@ -0,0 +1,124 @@
+// Command seed populates a development data directory with synthetic
+// members, credentials, endorsements, and profiles.
+//
+// Usage:
+//
+//	go run ./cmd/seed -members 200
+//	MATOU_ENV=test go run ./cmd/seed -members 50 -seed 42
+//
+// This is a development tool only. It refuses to run with MATOU_ENV=production.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	anysyncTesting "github.com/matou-dao/backend/internal/anysync/testing"
+	"github.com/matou-dao/backend/internal/api"
+	"github.com/matou-dao/backend/internal/seed"
+)
+
+func main() {
+	defaults := seed.DefaultOptions()
+
+	members := flag.Int("members", defaults.Members, "number of synthetic members to generate")
+	seedValue := flag.Int64("seed", 0, "random seed for deterministic output (0 = time-based)")
+	endorsements := flag.Float64("endorsements", defaults.MeanEndorsements, "mean endorsements given per member")
+	stewards := flag.Float64("stewards", defaults.StewardRatio, "fraction of members holding a steward credential")
+	invites := flag.Float64("invites", defaults.InviteRatio, "fraction of members invited by another member")
+	dataDirFlag := flag.String("data-dir", "", "data directory (defaults to MATOU_DATA_DIR, ./data or ./data-test)")
+	fakeSpaces := flag.Bool("fake-spaces", false, "also route data through the in-memory space layer and report timings")
+	flag.Parse()
+
+	env := os.Getenv("MATOU_ENV")
+	if env == "production" {
+		log.Fatal("seed is a development tool and cannot run with MATOU_ENV=production")
+	}
+
+	dataDir := *dataDirFlag
+	if dataDir == "" {
+		dataDir = os.Getenv("MATOU_DATA_DIR")
+	}
+	if dataDir == "" {
+		if env == "test" {
+			dataDir = "./data-test"
+		} else {
+			dataDir = "./data"
+		}
+	}
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		log.Fatalf("Failed to create data directory: %v", err)
+	}
+
+	// Issue membership credentials from the configured org when available so
+	// the trust graph roots at the real org node.
+	orgAID := api.NewOrgConfigHandler(dataDir, nil).GetOrgAID()
+
+	opts := &seed.Options{
+		Members:          *members,
+		OrgAID:           orgAID,
+		Seed:             *seedValue,
+		Span:             defaults.Span,
+		MeanEndorsements: *endorsements,
+		StewardRatio:     *stewards,
+		InviteRatio:      *invites,
+	}
+
+	fmt.Printf("Generating %d synthetic members...\n", opts.Members)
+	start := time.Now()
+	ds := seed.NewGenerator(opts).Generate()
+	fmt.Printf("  Generated in %s\n", time.Since(start).Round(time.Millisecond))
+	fmt.Printf("   Org AID: %s\n", ds.OrgAID)
+
+	store, err := anystore.NewLocalStore(anystore.DefaultConfig(dataDir))
+	if err != nil {
+		log.Fatalf("Failed to open local store: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	start = time.Now()
+	if err := ds.WriteToStore(ctx, store); err != nil {
+		log.Fatalf("Failed to write credentials: %v", err)
+	}
+	if err := store.Flush(ctx); err != nil {
+		log.Fatalf("Failed to flush store: %v", err)
+	}
+	fmt.Printf("  Wrote %d credentials to %s in %s\n", len(ds.Credentials), store.Path(), time.Since(start).Round(time.Millisecond))
+
+	if *fakeSpaces {
+		client := anysyncTesting.NewMockAnySyncClient()
+		client.DataDir = dataDir
+		sm := anysync.NewSpaceManager(client, &anysync.SpaceManagerConfig{
+			CommunitySpaceID:         "seed-community",
+			CommunityReadOnlySpaceID: "seed-community-readonly",
+			OrgAID:                   ds.OrgAID,
+		})
+		start = time.Now()
+		if err := ds.WriteToSpaces(ctx, sm, anysyncTesting.NewMockSpaceStore()); err != nil {
+			log.Fatalf("Failed to write to space layer: %v", err)
+		}
+		fmt.Printf("  Routed through in-memory space layer in %s (%d spaces, %d documents)\n",
+			time.Since(start).Round(time.Millisecond), len(client.Spaces), len(client.SyncDocumentCalls))
+	}
+
+	fmt.Println()
+	fmt.Println("Summary:")
+	summary := ds.Summary()
+	schemas := make([]string, 0, len(summary))
+	for schema := range summary {
+		schemas = append(schemas, schema)
+	}
+	sort.Strings(schemas)
+	for _, schema := range schemas {
+		fmt.Printf("  %-28s %d\n", schema, summary[schema])
+	}
+	fmt.Printf("  %-28s %d\n", "profiles", len(ds.Profiles))
+}
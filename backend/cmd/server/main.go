@@ -1,28 +1,77 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	// Members' digest timezones resolve on machines without zoneinfo
+	_ "time/tzdata"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/matou-dao/backend/internal/anysync"
 	"github.com/matou-dao/backend/internal/anystore"
 	"github.com/matou-dao/backend/internal/api"
+	"github.com/matou-dao/backend/internal/audit"
+	"github.com/matou-dao/backend/internal/bootstrap"
+	"github.com/matou-dao/backend/internal/cluster"
 	"github.com/matou-dao/backend/internal/config"
+	"github.com/matou-dao/backend/internal/dataformat"
 	"github.com/matou-dao/backend/internal/email"
+	"github.com/matou-dao/backend/internal/flags"
 	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/imaging"
 	"github.com/matou-dao/backend/internal/keri"
+	"github.com/matou-dao/backend/internal/lifecycle"
+	"github.com/matou-dao/backend/internal/logging"
+	"github.com/matou-dao/backend/internal/metrics"
+	"github.com/matou-dao/backend/internal/outbound"
+	"github.com/matou-dao/backend/internal/rpc"
+	"github.com/matou-dao/backend/internal/secret"
+	"github.com/matou-dao/backend/internal/sqlstore"
 	bgSync "github.com/matou-dao/backend/internal/sync"
+	"github.com/matou-dao/backend/internal/trust"
 	matouTypes "github.com/matou-dao/backend/internal/types"
 )
 
+// openLogFile redirects stdout and stderr into a rotating log file. Relative
+// paths are resolved against the data directory. Returns nil when no log
+// file is configured.
+func openLogFile(lc config.LogFileConfig, dataDir string) (*logging.Output, error) {
+	if lc.Path == "" {
+		return nil, nil
+	}
+	path := lc.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dataDir, path)
+	}
+	file, err := logging.OpenRotatingFile(path, logging.RotateOptions{
+		MaxSize:    int64(lc.MaxSizeMB) << 20,
+		MaxAge:     lc.MaxAge,
+		MaxBackups: lc.MaxBackups,
+		Compress:   lc.Compress,
+	})
+	if err != nil {
+		return nil, err
+	}
+	output, err := logging.Redirect(file, !lc.Quiet)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	fmt.Printf("Logging to %s (max %d MB, %s, %d backups)\n", path, lc.MaxSizeMB, lc.MaxAge, lc.MaxBackups)
+	return output, nil
+}
+
 // fetchAndSaveAnySyncConfig fetches the any-sync client config from the config
 // server and writes it to disk as YAML.
 func fetchAndSaveAnySyncConfig(configServerURL, targetPath string) error {
@@ -72,7 +121,52 @@ func fetchAndSaveAnySyncConfig(configServerURL, targetPath string) error {
 	return nil
 }
 
+// resolveAnySyncConfigPath returns the any-sync client config file for the
+// environment, unless MATOU_ANYSYNC_CONFIG overrides it
+func resolveAnySyncConfigPath(isTest, isProd bool) string {
+	if path := os.Getenv("MATOU_ANYSYNC_CONFIG"); path != "" {
+		return path
+	}
+	switch {
+	case isTest:
+		// Test network uses ports 2001-2006
+		return "config/client-test.yml"
+	case isProd:
+		// Production network uses remote any-sync nodes
+		return "config/client-production.yml"
+	default:
+		// Dev network uses ports 1001-1006
+		return "config/client-dev.yml"
+	}
+}
+
+// listen opens a listener for the given config. Stale Unix socket files
+// left behind by a previous run are removed first.
+func listen(lc config.ListenerConfig) (net.Listener, error) {
+	if lc.Network == "unix" {
+		if err := os.Remove(lc.Address); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("removing stale socket: %w", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(lc.Address), 0755); err != nil {
+			return nil, fmt.Errorf("creating socket directory: %w", err)
+		}
+	}
+	return net.Listen(lc.Network, lc.Address)
+}
+
+// accessLogOptions converts the logging config to access log options
+func accessLogOptions(lc config.LoggingConfig) api.AccessLogOptions {
+	return api.AccessLogOptions{
+		LogBodies:       lc.LogBodies,
+		SampleRates:     lc.SampleRates,
+		SensitiveFields: lc.RedactFields,
+	}
+}
+
 func main() {
+	selfTest := flag.Bool("selftest", false, "check key derivation, storage, coordinator, KERIA and SMTP, print a report and exit")
+	flag.Parse()
+
 	// Detect environment: "test" uses isolated data, configs, and ports
 	// "production" uses production configs (for Electron builds)
 	env := os.Getenv("MATOU_ENV")
@@ -103,23 +197,66 @@ func main() {
 		log.Fatalf("Failed to create data directory: %v", err)
 	}
 
-	// Load server configuration (SMTP, KERI URLs, etc.)
+	// Refuse data a newer release wrote or an older one needs migrating
+	// from, before anything reads or writes it
+	dataFormat, err := dataformat.Check(dataDir)
+	if err != nil {
+		log.Fatalf("Incompatible data directory: %v", err)
+	}
+
+	// Load server configuration (SMTP, KERI URLs, etc.). The same load is
+	// repeated when config.yaml changes.
+	configPath := os.Getenv("MATOU_CONFIG")
+	if configPath == "" {
+		configPath = "config/config.yaml"
+	}
+	loadConfig := func() (*config.Config, error) {
+		cfg, err := config.Load(configPath, "")
+		if err != nil {
+			return nil, err
+		}
+
+		// Test mode uses port 9080 to avoid conflicting with dev server on 8080
+		if isTest {
+			cfg.Server.Port = 9080
+		}
+
+		// Allow port override from environment (used by Electron to allocate dynamic ports)
+		if portStr := os.Getenv("MATOU_SERVER_PORT"); portStr != "" {
+			if port, parseErr := strconv.Atoi(portStr); parseErr == nil {
+				cfg.Server.Port = port
+			}
+		}
+		return cfg, nil
+	}
 	fmt.Println("Loading configuration...")
-	cfg, err := config.Load("", "")
+	cfg, err := loadConfig()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	// Test mode uses port 9080 to avoid conflicting with dev server on 8080
-	if isTest {
-		cfg.Server.Port = 9080
+	// Route outbound HTTP through the configured proxy and CAs
+	outboundSettings, err := outbound.New(cfg.Outbound)
+	if err != nil {
+		log.Fatalf("Failed to configure outbound connections: %v", err)
+	}
+	outboundSettings.Install()
+	if cfg.Outbound.Proxy != "" {
+		fmt.Printf("Outbound proxy: %s (no proxy: %s)\n", outbound.Redact(cfg.Outbound.Proxy), cfg.Outbound.NoProxy)
+	}
+	if cfg.Outbound.CABundle != "" {
+		fmt.Printf("Trusting CA bundle: %s\n", cfg.Outbound.CABundle)
 	}
 
-	// Allow port override from environment (used by Electron to allocate dynamic ports)
-	if portStr := os.Getenv("MATOU_SERVER_PORT"); portStr != "" {
-		if port, parseErr := strconv.Atoi(portStr); parseErr == nil {
-			cfg.Server.Port = port
-		}
+	// Post-deployment verification: run the checks and exit
+	if *selfTest {
+		os.Exit(runSelfTest(cfg, dataDir, resolveAnySyncConfigPath(isTest, isProd)))
+	}
+
+	// Copy all output to a rotating log file when configured
+	logOutput, err := openLogFile(cfg.Server.LogFile, dataDir)
+	if err != nil {
+		log.Fatalf("Failed to open log file: %v", err)
 	}
 
 	// Initialize org config handler - single source of truth for organization identity
@@ -155,8 +292,27 @@ func main() {
 
 	// Initialize user identity (per-user mode)
 	fmt.Println("Initializing user identity...")
-	userIdentity := identity.New(dataDir)
-	if userIdentity.IsConfigured() {
+	passphrase, err := cfg.AtRest.ResolvePassphrase()
+	if err != nil {
+		log.Fatalf("Failed to load identity passphrase: %v", err)
+	}
+	sealer, err := secret.NewSealer(passphrase)
+	if err != nil {
+		log.Fatalf("Failed to set up at-rest encryption: %v", err)
+	}
+	anysync.SetKeySealer(sealer)
+	if sealer.Enabled() {
+		fmt.Println("  At-rest encryption enabled for identity and space keys")
+		if sealed, err := anysync.SealSpaceKeySets(dataDir); err != nil {
+			fmt.Printf("  Warning: failed to encrypt space keys: %v\n", err)
+		} else if sealed > 0 {
+			fmt.Printf("  Encrypted %d plaintext space key file(s)\n", sealed)
+		}
+	}
+	userIdentity := identity.NewSealed(dataDir, sealer)
+	if err := userIdentity.Locked(); err != nil {
+		fmt.Printf("  Identity is locked: %v\n", err)
+	} else if userIdentity.IsConfigured() {
 		fmt.Printf("  Identity loaded from disk\n")
 		fmt.Printf("   AID: %s\n", userIdentity.GetAID())
 		fmt.Printf("   Peer ID: %s\n", userIdentity.GetPeerID())
@@ -169,20 +325,7 @@ func main() {
 	fmt.Println("Initializing any-sync client...")
 
 	// Select config file based on environment
-	anysyncConfigPath := os.Getenv("MATOU_ANYSYNC_CONFIG")
-	if anysyncConfigPath == "" {
-		switch {
-		case isTest:
-			// Test network uses ports 2001-2006
-			anysyncConfigPath = "config/client-test.yml"
-		case isProd:
-			// Production network uses remote any-sync nodes
-			anysyncConfigPath = "config/client-production.yml"
-		default:
-			// Dev network uses ports 1001-1006
-			anysyncConfigPath = "config/client-dev.yml"
-		}
-	}
+	anysyncConfigPath := resolveAnySyncConfigPath(isTest, isProd)
 
 	// If the config file doesn't exist, try fetching it from the config server
 	if _, err := os.Stat(anysyncConfigPath); os.IsNotExist(err) {
@@ -216,11 +359,11 @@ func main() {
 	}
 
 	sdkClient, err := anysync.NewSDKClient(anysyncConfigPath, sdkOpts)
+	sdkOpts.Mnemonic.Zero()
 	if err != nil {
 		log.Fatalf("Failed to create any-sync SDK client: %v", err)
 	}
 	var anysyncClient anysync.AnySyncClient = sdkClient
-	defer sdkClient.Close()
 
 	fmt.Printf("  any-sync client initialized\n")
 	fmt.Printf("   Network ID: %s\n", anysyncClient.GetNetworkID())
@@ -257,10 +400,39 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create local store: %v", err)
 	}
-	defer store.Close()
 
 	fmt.Printf("  Local storage initialized\n")
 	fmt.Printf("   Data directory: %s\n", dataDir)
+	fmt.Printf("   Data format: %d\n", dataFormat)
+
+	// Space records, peer mappings, presence, inbox items, invites and
+	// preferences can live in a managed database for org deployments
+	var recordStore anystore.RecordStore = store
+	var sqlRecords *sqlstore.Store
+	switch cfg.Store.Records.Driver {
+	case sqlstore.DriverSQLite, sqlstore.DriverPostgres:
+		sqlRecords, err = sqlstore.Open(context.Background(), cfg.Store.Records.Driver, cfg.Store.Records.DSN)
+		if err != nil {
+			log.Fatalf("Failed to open record store: %v", err)
+		}
+		recordStore = sqlRecords
+		fmt.Printf("   Records: %s database\n", cfg.Store.Records.Driver)
+	default:
+		fmt.Printf("   Records: anystore\n")
+	}
+
+	// In cluster mode replicas elect a leader through the shared record
+	// store; followers serve reads and forward writes to it
+	var elector *cluster.Elector
+	var replicaRouter *api.ReplicaRouter
+	if cfg.Cluster.Enabled {
+		if sqlRecords == nil {
+			log.Fatalf("Cluster mode needs a shared record store (store.records.driver sqlite or postgres)")
+		}
+		elector = cluster.NewElector(sqlRecords, cfg.Cluster.NodeURL, cfg.Cluster.LeaseTTL)
+		replicaRouter = api.NewReplicaRouter(elector)
+		fmt.Printf("   Cluster node: %s\n", cfg.Cluster.NodeURL)
+	}
 	fmt.Println()
 
 	// Determine community space ID: prefer runtime config from identity, fall back to org config
@@ -291,10 +463,27 @@ func main() {
 		AdminSpaceID:             adminSpaceID,
 		OrgAID:                   orgAID,
 	})
-	spaceStore := anystore.NewSpaceStoreAdapter(store)
+	spaceStore := anystore.NewSpaceStoreAdapter(recordStore)
+	compactChanges, _ := anysync.ParseChangeEncoding(cfg.AnySync.ChangeEncoding)
+	spaceManager.SetCompactEncoding(compactChanges)
+	spaceManager.SetPlacementSource(orgConfigHandler)
 
 	fmt.Printf("  Space manager initialized\n")
 	fmt.Printf("   Community Space ID: %s\n", communitySpaceID)
+	if compactChanges {
+		fmt.Println("   Change encoding: compact")
+	}
+	// Cache file blocks on disk so repeated downloads don't hit the filenode
+	if fileManager := spaceManager.FileManager(); fileManager != nil && cfg.Files.CacheMB > 0 {
+		blockCache, err := anysync.NewBlockCache(filepath.Join(dataDir, "blocks"), int64(cfg.Files.CacheMB)<<20)
+		if err != nil {
+			fmt.Printf("   Warning: file block cache disabled: %v\n", err)
+		} else {
+			fileManager.SetBlockCache(blockCache)
+			stats := blockCache.Stats()
+			fmt.Printf("   File block cache: %d blocks, %d of %d MB\n", stats.Blocks, stats.Bytes>>20, cfg.Files.CacheMB)
+		}
+	}
 	fmt.Println()
 
 	// Verify community space (log warning if not configured)
@@ -305,14 +494,32 @@ func main() {
 
 	// Initialize KERI client (config-only, no KERIA connection needed)
 	fmt.Println("Initializing KERI client...")
+	// Witness pool from server config, used until the org config sets its own
+	var defaultWitnesses *keri.WitnessPool
+	if len(cfg.KERI.Witnesses) > 0 {
+		defaultWitnesses = &keri.WitnessPool{Threshold: cfg.KERI.WitnessThreshold}
+		for _, w := range cfg.KERI.Witnesses {
+			defaultWitnesses.Witnesses = append(defaultWitnesses.Witnesses, keri.Witness{AID: w.AID, URL: w.URL})
+		}
+		if err := defaultWitnesses.Validate(); err != nil {
+			log.Fatalf("Invalid KERI witness config: %v", err)
+		}
+	}
 	keriClient, err := keri.NewClient(&keri.Config{
-		OrgAID:   orgConfigHandler.GetOrgAID(),
-		OrgAlias: orgConfigHandler.GetOrgName(), // Use name as alias
-		OrgName:  orgConfigHandler.GetOrgName(),
+		OrgAID:    orgConfigHandler.GetOrgAID(),
+		OrgAlias:  orgConfigHandler.GetOrgName(), // Use name as alias
+		OrgName:   orgConfigHandler.GetOrgName(),
+		OrgOOBI:   orgConfigHandler.GetOrgOOBI(),
+		CESRURL:   cfg.KERI.CESRURL,
+		Witnesses: defaultWitnesses,
 	})
 	if err != nil {
 		log.Fatalf("Failed to create KERI client: %v", err)
 	}
+	keriClient.SetRoleTemplateSource(orgConfigHandler)
+	keriClient.SetEndorsementTypeSource(orgConfigHandler)
+	keriClient.SetWitnessPoolSource(orgConfigHandler)
+	keriClient.SetKeyHistory(keri.NewKeyHistory(dataDir))
 
 	fmt.Printf("  KERI client initialized\n")
 	if !orgConfigHandler.IsConfigured() {
@@ -328,23 +535,342 @@ func main() {
 	fmt.Printf("  Type registry initialized with %d types\n", len(typeRegistry.All()))
 	fmt.Println()
 
+	// Initialize feature flags (config defaults + persisted runtime overrides)
+	featureFlags := flags.New(cfg.Features, dataDir)
+	if enabled := featureFlags.EnabledNames(); len(enabled) > 0 {
+		fmt.Printf("  Feature flags enabled: %s\n", strings.Join(enabled, ", "))
+		fmt.Println()
+	}
+
 	// Create event broker for SSE
 	eventBroker := api.NewEventBroker()
 
 	// Create API handlers
 	credHandler := api.NewCredentialsHandler(keriClient, store)
+	oobiHandler := api.NewOOBIHandler(keriClient)
+	witnessesHandler := api.NewWitnessesHandler(keriClient, orgConfigHandler)
+	rotationHandler := api.NewRotationHandler(keriClient, orgConfigHandler)
 	syncHandler := api.NewSyncHandler(keriClient, store, spaceManager, spaceStore, userIdentity)
+	presenceTracker := api.NewPresenceTracker(spaceManager, recordStore, userIdentity)
+	syncHandler.SetPresence(presenceTracker)
 	trustHandler := api.NewTrustHandler(store, orgConfigHandler.GetOrgAID(), spaceManager)
+	trustHandler.SetFlags(featureFlags)
+	trustHandler.SetTermNoticeWindow(cfg.Terms.NoticeWindow)
+	trustWeights, err := trust.ParseWeights(cfg.Trust.Weights)
+	if err != nil {
+		log.Fatalf("Invalid trust weights in %s: %v", configPath, err)
+	}
+	trustHandler.SetDefaultWeights(trustWeights)
+	trustHandler.SetWeightsSource(orgConfigHandler)
+	trustHandler.SetAlgorithmSource(orgConfigHandler)
+	trustHandler.SetFederationSource(orgConfigHandler)
+	// Community credentials are hydrated into the cache in the background,
+	// so trust requests only read the tree while the cache is behind
+	credentialHydrator := bgSync.NewCredentialHydrator(bgSync.DefaultHydrateInterval, spaceManager, store)
+	sdkClient.OnHeadUpdate(credentialHydrator.NotifyHeadUpdate)
+	trustHandler.SetCommunityCache(credentialHydrator)
+	if cfg.Trust.MaterializeInterval > 0 {
+		trustHandler.EnableMaterializedGraph(cfg.Trust.MaterializeInterval)
+	}
 	healthHandler := api.NewHealthHandler(store, spaceStore, orgConfigHandler.GetOrgAID(), orgConfigHandler.GetAdminAID())
 	spacesHandler := api.NewSpacesHandler(spaceManager, store, userIdentity)
+	spacesHandler.SetRecordStore(recordStore)
+	reencryptHandler := api.NewReencryptHandler(spaceManager)
 	emailSender := email.NewSender(cfg.SMTP)
-	invitesHandler := api.NewInvitesHandler(emailSender)
+	emailSender.SetDialer(outboundSettings.Dial)
+	configReloader := config.NewReloader(configPath, cfg, loadConfig)
+	configReloader.OnReload(func(c *config.Config) { emailSender.SetConfig(c.SMTP) })
+	configReloader.OnReload(func(c *config.Config) {
+		weights, err := trust.ParseWeights(c.Trust.Weights)
+		if err != nil {
+			fmt.Printf("[Config] Keeping trust weights: %v\n", err)
+			return
+		}
+		trustHandler.SetDefaultWeights(weights)
+	})
+	effectiveConfigHandler := api.NewEffectiveConfigHandler(configReloader, orgConfigHandler)
+	invitesHandler := api.NewInvitesHandler(emailSender, spaceManager, recordStore)
 	bookingHandler := api.NewBookingHandler(emailSender)
 	notificationsHandler := api.NewNotificationsHandler(emailSender)
 	identityHandler := api.NewIdentityHandler(userIdentity, sdkClient, spaceManager, spaceStore)
+	backupHandler := api.NewBackupHandler(dataDir, sealer, userIdentity)
+	orchestrator := bootstrap.NewOrchestrator(spaceManager, spaceStore, userIdentity)
+	orchestrator.SetOrgConfig(orgConfigHandler)
+	spacesHandler.SetBootstrap(orchestrator)
+	identityHandler.SetBootstrap(orchestrator)
+	bootstrapHandler := api.NewBootstrapHandler(orchestrator)
+	onboardingHandler := api.NewOnboardingHandler(store, spaceManager, userIdentity)
+	onboardingHandler.SetRecordStore(recordStore)
 	eventsHandler := api.NewEventsHandler(eventBroker)
 	profilesHandler := api.NewProfilesHandler(spaceManager, userIdentity, typeRegistry)
+	endorsementsHandler := api.NewEndorsementsHandler(spaceManager, userIdentity, trustHandler)
+	endorsementsHandler.SetEndorsementRegistry(keriClient)
+	digestHandler := api.NewDigestHandler(spaceManager, userIdentity, endorsementsHandler, recordStore, emailSender)
+	taxonomyHandler := api.NewTaxonomyHandler(spaceManager, userIdentity)
+	profilesHandler.SetTaxonomy(taxonomyHandler)
+	matchHandler := api.NewMatchHandler(spaceManager, userIdentity, trustHandler, taxonomyHandler)
+	projectsHandler := api.NewProjectsHandler(spaceManager, userIdentity, trustHandler)
+	calendarHandler := api.NewCalendarHandler(spaceManager, userIdentity, trustHandler)
+	contributionsHandler := api.NewContributionsHandler(spaceManager, userIdentity, trustHandler)
+	profilesHandler.SetContributions(contributionsHandler)
+	memberAccess := api.NewMemberAccessGranter(spaceManager, store)
+	memberAccess.SetRecordStore(recordStore)
+	memberAccess.SetEvents(eventBroker)
+	profilesHandler.SetMemberAccess(memberAccess)
+	trustHandler.SetContributionSource(contributionsHandler)
+	grantsHandler := api.NewGrantsHandler(spaceManager, userIdentity, trustHandler)
+	receiptsHandler := api.NewReceiptsHandler(spaceManager, userIdentity)
+	approvalsHandler := api.NewApprovalsHandler(spaceManager, userIdentity, keriClient)
+	approvalsHandler.SetPolicy(orgConfigHandler)
+	approvalsHandler.SetReceipts(receiptsHandler)
+	credHandler.SetReceipts(receiptsHandler)
+	memberAccess.SetReceipts(receiptsHandler)
+	credHandler.SetEvents(eventBroker)
+	schemasHandler := api.NewSchemasHandler(spaceManager, userIdentity)
+	credHandler.SetSchemas(schemasHandler)
+	syncHandler.SetEvents(eventBroker)
+	spacesHandler.SetEvents(eventBroker)
+	invitesHandler.SetEvents(eventBroker)
+	reencryptHandler.SetEvents(eventBroker)
+	projectsHandler.SetEvents(eventBroker)
+	receiptsHandler.SetEvents(eventBroker)
+	// Credential issuance and revocation, org config changes, ACL changes
+	// and identity resets are recorded in the audit log, and replicated to
+	// the admin space's audit tree on admins' backends
+	auditLog := audit.NewLog(store)
+	auditHandler := api.NewAuditHandler(auditLog)
+	auditLedger := api.NewAuditLedger(spaceManager)
+	auditLog.SetReplicator(auditLedger)
+	orgConfigHandler.SetAudit(auditLog)
+	receiptsHandler.SetAudit(auditLog)
+	memberAccess.SetAudit(auditLog)
+	spacesHandler.SetAudit(auditLog)
+	identityHandler.SetAudit(auditLog)
+	descriptorHandler := api.NewDescriptorHandler(orgConfigHandler, spaceManager)
 	filesHandler := api.NewFilesHandler(spaceManager.FileManager(), spaceManager)
+	filesHandler.SetQuota(int64(cfg.Files.UserQuotaMB) << 20)
+	filesHandler.SetMaxUploadSize(int64(cfg.Files.MaxUploadMB) << 20)
+	renditions := make([]imaging.Spec, 0, len(cfg.Files.Renditions))
+	for _, r := range cfg.Files.Renditions {
+		renditions = append(renditions, imaging.Spec{Name: r.Name, Size: r.Size})
+	}
+	filesHandler.SetRenditions(renditions)
+	flagsHandler := api.NewFlagsHandler(featureFlags)
+	maintenanceMode := api.NewMaintenanceMode()
+	maintenanceHandler := api.NewMaintenanceHandler(maintenanceMode)
+	syncTestHandler := api.NewSyncTestHandler(spaceManager, cfg.SyncTest.PeerURL, cfg.SyncTest.Timeout)
+	historyHandler := api.NewHistoryHandler(spaceManager)
+	inboxHandler := api.NewInboxHandler(spaceManager, store, userIdentity)
+	inboxHandler.SetRecordStore(recordStore)
+	inboxHandler.SetEvents(eventBroker)
+	inboxHandler.SetReceipts(receiptsHandler)
+	storeHandler := api.NewStoreHandler(store, anystore.VacuumOptions{
+		TrustCacheRetention:   cfg.Store.TrustCacheRetention,
+		InboxRetention:        cfg.Store.InboxRetention,
+		PresenceRetention:     cfg.Store.PresenceRetention,
+		TrustHistoryRetention: cfg.Store.TrustHistoryRetention,
+	})
+	healthHandler.SetFlags(featureFlags)
+
+	// Trust graph builds beyond the concurrency budget are shed so cheap
+	// routes stay responsive under load
+	var loadShedder *api.LoadShedder
+	if cfg.Access.ExpensiveConcurrency > 0 {
+		loadShedder = api.NewLoadShedder(cfg.Access.ExpensiveConcurrency, cfg.Access.ShedStaleFor)
+		if len(cfg.Access.ShedRoutes) > 0 {
+			loadShedder.Shed(cfg.Access.ShedRoutes...)
+		} else {
+			loadShedder.Shed(api.DefaultShedRoutes...)
+		}
+		healthHandler.SetLoadShedder(loadShedder)
+	}
+	accessControl := api.NewAccessControl(userIdentity, trustHandler, cfg.Access.GuestRequestsPerMinute, cfg.Access.MemberRequestsPerMinute)
+
+	// Trust graph builds, credential issuance and uploads also have
+	// token-bucket budgets per client address and per AID
+	configuredLimits := make([]api.RouteLimit, len(cfg.Access.RouteLimits))
+	for i, l := range cfg.Access.RouteLimits {
+		configuredLimits[i] = api.RouteLimit{Name: l.Name, Routes: l.Routes, Methods: l.Methods, PerIP: l.PerIP, PerAID: l.PerAID, Burst: l.Burst}
+	}
+	routeLimiter := api.NewRouteLimiter(api.MergeRouteLimits(api.DefaultRouteLimits, configuredLimits))
+
+	// signify can reach KERIA through the backend instead of its own ports
+	keriaProxy, err := api.NewKERIAProxyHandler(cfg.KERI.AdminURL, cfg.KERI.BootURL, dataDir, userIdentity, cfg.Access.KERIAProxyRequestsPerMinute)
+	if err != nil {
+		log.Fatalf("Failed to create KERIA proxy: %v", err)
+	}
+
+	// API authentication: every route needs an API key or AID token unless
+	// listed here as public; config routes override these requirements
+	apiKeys := make([]api.APIKey, len(cfg.Auth.APIKeys))
+	for i, k := range cfg.Auth.APIKeys {
+		apiKeys[i] = api.APIKey{Name: k.Name, Key: k.Key, Admin: k.Admin}
+	}
+	authenticator := api.NewAuthenticator(api.AuthOptions{
+		Enabled:     cfg.Auth.Enabled,
+		APIKeys:     apiKeys,
+		TokenMaxAge: cfg.Auth.TokenMaxAge,
+	}, recordStore, userIdentity)
+	authenticator.SetAdminSource(orgConfigHandler)
+	// Scoped tokens for integrations that can't sign AID tokens
+	serviceTokens := api.NewServiceTokens(dataDir)
+	authenticator.SetServiceTokens(serviceTokens)
+	// Access tiers follow the authenticated caller, not the local identity
+	accessControl.SetAuthenticator(authenticator)
+	for _, route := range []string{"/health", "/info", "/metrics", "/.well-known/", "/api/v1/org/health", "/api/v1/cluster", "/api/v1/public/"} {
+		authenticator.Require(route, api.AuthPublic)
+	}
+	for _, route := range []string{
+		"/api/v1/admin/",
+		"POST /api/v1/bootstrap",
+		"POST /api/v1/org/config",
+		"DELETE /api/v1/org/config",
+		"POST /api/v1/credentials/participation",
+		"/api/v1/credentials/approvals",
+		"/api/v1/credentials/approvals/",
+		"POST /api/v1/orgs",
+		"DELETE /api/v1/orgs/",
+		"POST /api/v1/oobi/resolve",
+		"POST /api/v1/keri/witnesses",
+		"PUT /api/v1/keri/witnesses/",
+		"DELETE /api/v1/keri/witnesses/",
+		"POST /api/v1/keri/rotate",
+		"/api/v1/spaces/reencrypt",
+		"/api/v1/spaces/reencrypt/status",
+		"DELETE /api/v1/spaces/",
+		"GET /api/v1/spaces",
+		"GET /api/v1/invites",
+		"POST /api/v1/invites",
+		"GET /api/v1/invites/",
+		"DELETE /api/v1/invites/",
+		"/api/v1/invites/email",
+		"/api/v1/config/",
+		"PUT /api/v1/trust/weights",
+		"DELETE /api/v1/trust/weights",
+		"/api/v1/trust/anomalies",
+		"/api/v1/audit",
+		"/api/v1/audit/ledger",
+		// init-member records the peer whose AID tokens are accepted
+		"POST /api/v1/profiles/init-member",
+		"PUT /api/v1/taxonomy/",
+		"PUT /api/v1/schemas/",
+		"POST /api/v1/calendar/events",
+		"POST /api/v1/contributions",
+		"/api/v1/receipts",
+		"/api/v1/grants",
+		"/api/v1/grants/",
+		// Calendar cancel and attendance, join request decisions and
+		// credential delivery status sit under resource IDs, so their
+		// handlers check for an admin
+	} {
+		authenticator.Require(route, api.AuthAdmin)
+	}
+	// Grant summaries stay readable by every member (and guests)
+	authenticator.Require("GET /api/v1/grants/summaries", api.AuthUser)
+	for route, level := range cfg.Auth.Routes {
+		// Levels were checked by config validation
+		authLevel, _ := api.ParseAuthLevel(level)
+		authenticator.Require(route, authLevel)
+	}
+	if authenticator.Enabled() {
+		fmt.Printf("API authentication enabled (%d API keys)\n", len(apiKeys))
+	}
+
+	// Requests acting as an AID must be signed by its current keys, as
+	// recorded in its KEL on KERIA
+	var keyStates api.KeyStateSource
+	if cfg.KERI.RequireSignatures {
+		keyResolver := keri.NewKeyStateResolver(cfg.KERI.CESRURL, cfg.KERI.KeyStateTTL)
+		rotationHandler.SetKeyStateCache(keyResolver)
+		keyStates = keyResolver
+		fmt.Printf("Request signatures required (key state from %s)\n", cfg.KERI.CESRURL)
+	}
+	signatureVerifier := api.NewSignatureVerifier(keyStates, cfg.KERI.SignatureMaxSkew, api.SignedRoutes)
+
+	// Admins changing org state take a lock so concurrent edits don't race;
+	// leases are mirrored to the admin space for admins on other backends
+	locks := api.NewLockManager(userIdentity)
+	locks.SetStore(api.NewAdminSpaceLockStore(spaceManager))
+	for route, resource := range map[string]string{
+		"POST /api/v1/org/config":                "org-config",
+		"DELETE /api/v1/org/config":              "org-config",
+		"PUT /api/v1/taxonomy/":                  "org-config",
+		"PUT /api/v1/schemas/":                   "org-config",
+		"POST /api/v1/credentials/participation": "issuance",
+		"POST /api/v1/credentials/approvals/":    "issuance",
+		"POST /api/v1/bootstrap":                 "spaces",
+		"POST /api/v1/keri/witnesses":            "org-config",
+		"PUT /api/v1/keri/witnesses/":            "org-config",
+		"DELETE /api/v1/keri/witnesses/":         "org-config",
+		"POST /api/v1/keri/rotate":               "org-config",
+		"POST /api/v1/spaces/reencrypt":          "spaces",
+		"DELETE /api/v1/spaces/":                 "spaces",
+		"PUT /api/v1/trust/weights":              "org-config",
+		"DELETE /api/v1/trust/weights":           "org-config",
+	} {
+		locks.Guard(route, resource)
+	}
+
+	// Long polls on the trust summary and member directory wait for each
+	// org's store or community credential tree to change
+	watcher := api.NewWatcher(api.NewDataVersion(store, spaceManager), cfg.Server.WatchTimeout)
+
+	// Additional organizations served from this backend. Each gets its own
+	// config, credential cache, spaces and trust graph under {dataDir}/orgs/{aid};
+	// the remaining routes serve the primary org only.
+	orgRegistry := api.NewOrgRegistry(dataDir, orgConfigHandler, func(tenantConfig *api.OrgConfigHandler, tenantDir string) (http.Handler, error) {
+		tenantStore, err := anystore.NewLocalStore(anystore.DefaultConfig(tenantDir))
+		if err != nil {
+			return nil, fmt.Errorf("creating local store: %w", err)
+		}
+		tenantData := tenantConfig.GetConfig()
+		tenantSpaces := anysync.NewSpaceManager(anysyncClient, &anysync.SpaceManagerConfig{
+			CommunitySpaceID:         tenantData.CommunitySpaceID,
+			CommunityReadOnlySpaceID: tenantData.ReadOnlySpaceID,
+			AdminSpaceID:             tenantData.AdminSpaceID,
+			OrgAID:                   tenantData.Organization.AID,
+		})
+		tenantKERI, err := keri.NewClient(&keri.Config{
+			OrgAID:    tenantData.Organization.AID,
+			OrgAlias:  tenantData.Organization.Name,
+			OrgName:   tenantData.Organization.Name,
+			OrgOOBI:   tenantData.Organization.OOBI,
+			CESRURL:   cfg.KERI.CESRURL,
+			Witnesses: defaultWitnesses,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating KERI client: %w", err)
+		}
+		tenantKERI.SetRoleTemplateSource(tenantConfig)
+		tenantKERI.SetEndorsementTypeSource(tenantConfig)
+		tenantKERI.SetWitnessPoolSource(tenantConfig)
+		tenantKERI.SetKeyHistory(keri.NewKeyHistory(tenantDir))
+		tenantTrust := api.NewTrustHandler(tenantStore, tenantData.Organization.AID, tenantSpaces)
+		tenantTrust.SetTermNoticeWindow(cfg.Terms.NoticeWindow)
+		tenantTrust.SetFlags(featureFlags)
+		tenantTrust.ShareDefaultWeights(trustHandler)
+		tenantTrust.SetWeightsSource(tenantConfig)
+		tenantTrust.SetAlgorithmSource(tenantConfig)
+		tenantTrust.SetFederationSource(tenantConfig)
+		watcher.SetOrgVersion(tenantData.Organization.AID, api.NewDataVersion(tenantStore, tenantSpaces))
+
+		tenantMux := http.NewServeMux()
+		tenantConfig.RegisterRoutes(tenantMux)
+		api.NewCredentialsHandler(tenantKERI, tenantStore).RegisterRoutes(tenantMux)
+		api.NewOOBIHandler(tenantKERI).RegisterRoutes(tenantMux)
+		api.NewWitnessesHandler(tenantKERI, tenantConfig).RegisterRoutes(tenantMux)
+		api.NewRotationHandler(tenantKERI, tenantConfig).RegisterRoutes(tenantMux)
+		tenantTrust.RegisterRoutes(tenantMux)
+		api.NewPublicStatsHandler(tenantTrust).RegisterRoutes(tenantMux)
+		api.NewGraphQLHandler(tenantTrust).RegisterRoutes(tenantMux)
+		api.NewDescriptorHandler(tenantConfig, tenantSpaces).RegisterRoutes(tenantMux)
+		return api.ConsistencyMiddleware(api.NewConsistency(nil, tenantStore, cfg.Server.ConsistencyTimeout), tenantMux), nil
+	})
+	if loaded, err := orgRegistry.Load(); err != nil {
+		fmt.Printf("Warning: failed to load additional organizations: %v\n", err)
+	} else if loaded > 0 {
+		fmt.Printf("Serving %d additional organization(s)\n", loaded)
+	}
 
 	// Create HTTP server
 	mux := http.NewServeMux()
@@ -352,6 +878,11 @@ func main() {
 	// Health check endpoint (with sync/trust status)
 	mux.HandleFunc("/health", api.CORSHandler(healthHandler.HandleHealth))
 
+	// Prometheus metrics
+	if cfg.Metrics.Enabled {
+		mux.Handle("/metrics", metrics.Handler())
+	}
+
 	// Info endpoint
 	mux.HandleFunc("/info", api.CORSHandler(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -381,62 +912,223 @@ func main() {
 
 	// Register API routes
 	credHandler.RegisterRoutes(mux)
+	oobiHandler.RegisterRoutes(mux)
+	witnessesHandler.RegisterRoutes(mux)
+	rotationHandler.RegisterRoutes(mux)
 	syncHandler.RegisterRoutes(mux)
 	trustHandler.RegisterRoutes(mux)
+	api.NewPublicStatsHandler(trustHandler).RegisterRoutes(mux)
+	api.NewGraphQLHandler(trustHandler).RegisterRoutes(mux)
 	spacesHandler.RegisterRoutes(mux)
+	reencryptHandler.RegisterRoutes(mux)
 	invitesHandler.RegisterRoutes(mux)
 	bookingHandler.RegisterRoutes(mux)
 	identityHandler.RegisterRoutes(mux)
+	backupHandler.RegisterRoutes(mux)
+	bootstrapHandler.RegisterRoutes(mux)
 	eventsHandler.RegisterRoutes(mux)
 	profilesHandler.RegisterRoutes(mux)
+	memberAccess.RegisterRoutes(mux)
+	endorsementsHandler.RegisterRoutes(mux)
+	digestHandler.RegisterRoutes(mux)
+	taxonomyHandler.RegisterRoutes(mux)
+	schemasHandler.RegisterRoutes(mux)
+	matchHandler.RegisterRoutes(mux)
+	projectsHandler.RegisterRoutes(mux)
+	calendarHandler.RegisterRoutes(mux)
+	contributionsHandler.RegisterRoutes(mux)
+	// Grants are voted on through governance, which is experimental
+	grantsMux := http.NewServeMux()
+	grantsHandler.RegisterRoutes(grantsMux)
+	governance := api.RequireFeature(featureFlags, flags.Governance, grantsMux.ServeHTTP)
+	mux.HandleFunc("/api/v1/grants", governance)
+	mux.HandleFunc("/api/v1/grants/", governance)
+	receiptsHandler.RegisterRoutes(mux)
+	approvalsHandler.RegisterRoutes(mux)
+	descriptorHandler.RegisterRoutes(mux)
 	filesHandler.RegisterRoutes(mux)
 	notificationsHandler.RegisterRoutes(mux)
 	orgConfigHandler.RegisterRoutes(mux)
+	effectiveConfigHandler.RegisterRoutes(mux)
+	flagsHandler.RegisterRoutes(mux)
+	maintenanceHandler.RegisterRoutes(mux)
+	syncTestHandler.RegisterRoutes(mux)
+	historyHandler.RegisterRoutes(mux)
+	inboxHandler.RegisterRoutes(mux)
+	storeHandler.RegisterRoutes(mux)
+	auditHandler.RegisterRoutes(mux)
+	auditLedger.RegisterRoutes(mux)
+	accessControl.RegisterRoutes(mux)
+	onboardingHandler.RegisterRoutes(mux)
+	keriaProxy.RegisterRoutes(mux)
+	locks.RegisterRoutes(mux)
+	orgRegistry.RegisterRoutes(mux)
+	serviceTokens.RegisterRoutes(mux)
+	replicaRouter.RegisterRoutes(mux)
 
 	// Start server
-	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
-	fmt.Printf("Starting HTTP server on %s\n", addr)
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	fmt.Println("Starting HTTP server")
 	fmt.Println()
 	fmt.Println("Endpoints:")
 	fmt.Println("  GET  /health                       - Health check")
+	fmt.Println("  GET  /api/v1/cluster               - This replica's cluster role and leader")
 	fmt.Println("  GET  /info                         - System information")
+	fmt.Println("  GET  /.well-known/matou.json       - Signed community descriptor")
+	if cfg.Metrics.Enabled {
+		fmt.Println("  GET  /metrics                      - Prometheus metrics")
+	}
 	fmt.Println()
 	fmt.Println("  Identity (per-user mode):")
 	fmt.Println("  POST /api/v1/identity/set          - Set user identity (triggers SDK restart)")
 	fmt.Println("  GET  /api/v1/identity              - Get current identity status")
 	fmt.Println("  DELETE /api/v1/identity             - Clear identity (logout/reset)")
+	fmt.Println("  POST /api/v1/identity/backup/export - Download an encrypted backup of identity and space keys")
+	fmt.Println("  POST /api/v1/identity/backup/import - Restore a backup (restart to use the keys)")
+	fmt.Println("  GET  /api/v1/access                - Access tier (guest/member) and rate limit")
+	fmt.Println("  GET  /api/v1/onboarding/state      - Onboarding progress (per-user state machine)")
+	fmt.Println("  POST /api/v1/onboarding/advance    - Report a completed onboarding step")
 	fmt.Println()
 	fmt.Println("  Credentials:")
 	fmt.Println("  GET  /api/v1/org                   - Organization info for frontend")
 	fmt.Println("  GET  /api/v1/credentials           - List stored credentials")
 	fmt.Println("  POST /api/v1/credentials           - Store credential from frontend")
 	fmt.Println("  GET  /api/v1/credentials/{said}    - Get credential by SAID")
+	fmt.Println("  GET  /api/v1/credentials/{said}/status - Delivery status from receipts (steward)")
 	fmt.Println("  POST /api/v1/credentials/validate  - Validate credential structure")
+	fmt.Println("  POST /api/v1/credentials/verify    - Verify signature, issuer key state, revocation and schema")
 	fmt.Println("  GET  /api/v1/credentials/roles     - List available roles")
+	fmt.Println("  POST /api/v1/credentials/participation - Pre-filled participation credential")
+	fmt.Println("  GET  /api/v1/oobi/generate         - OOBI for the org (or ?aid=)")
+	fmt.Println("  POST /api/v1/oobi/resolve          - Resolve a member's OOBI and return its key state (admin)")
+	fmt.Println("  GET  /api/v1/keri/witnesses        - Org witness pool, current witnesses and rotation plan")
+	fmt.Println("  POST /api/v1/keri/witnesses        - Add a witness to the pool (admin)")
+	fmt.Println("  DELETE /api/v1/keri/witnesses/{aid} - Remove a witness from the pool (admin)")
+	fmt.Println("  PUT  /api/v1/keri/witnesses/threshold - Set the witness receipt threshold (admin)")
+	fmt.Println("  GET  /api/v1/keri/witnesses/receipts - Witness receipts for the org AID's latest event")
+	fmt.Println("  POST /api/v1/keri/rotate           - Record a key rotation of the org or an admin AID (admin)")
+	fmt.Println("  GET  /api/v1/receipts              - Issuance receipt ledger with verification (steward)")
+	fmt.Println("  POST /api/v1/receipts              - Record issuance/delivery/revocation receipt (steward)")
+	fmt.Println("  GET  /api/v1/credentials/approvals            - Issuance approval queue (steward)")
+	fmt.Println("  POST /api/v1/credentials/approvals            - Request approval for a high-privilege issuance (steward)")
+	fmt.Println("  GET  /api/v1/credentials/approvals/{id}       - Approval, with pre-filled credential once approved (steward)")
+	fmt.Println("  POST /api/v1/credentials/approvals/{id}/approve - Approve as a second steward")
+	fmt.Println("  POST /api/v1/credentials/approvals/{id}/reject  - Reject or withdraw")
+	fmt.Println("  POST /api/v1/credentials/approvals/{id}/issued  - Link the issued credential SAID")
 	fmt.Println()
 	fmt.Println("  Sync:")
 	fmt.Println("  POST /api/v1/sync/credentials      - Sync credentials from KERIA")
 	fmt.Println("  POST /api/v1/sync/kel              - Sync KEL from KERIA")
-	fmt.Println("  GET  /api/v1/community/members     - List community members")
+	fmt.Println("  GET  /api/v1/community/members     - List community members (?watch=true waits for a change)")
 	fmt.Println("  GET  /api/v1/community/credentials - List community-visible credentials")
 	fmt.Println()
 	fmt.Println("  Trust Graph:")
 	fmt.Println("  GET  /api/v1/trust/graph           - Get trust graph (full or filtered)")
+	fmt.Println("  GET  /api/v1/trust/graph/export    - Export trust graph (graphml, dot, csv)")
+	fmt.Println("  GET  /api/v1/trust/snapshot        - Audit snapshot of graph and scores")
+	fmt.Println("  POST /api/v1/trust/snapshot/verify - Verify an audit snapshot")
 	fmt.Println("  GET  /api/v1/trust/score/{aid}     - Get trust score for an AID")
+	fmt.Println("  GET  /api/v1/trust/score/{aid}/history - Get an AID's trust score history")
 	fmt.Println("  GET  /api/v1/trust/scores          - Get top trust scores")
-	fmt.Println("  GET  /api/v1/trust/summary         - Get trust graph summary")
+	fmt.Println("  GET  /api/v1/trust/path            - Shortest credential paths between two AIDs")
+	fmt.Println("  GET  /api/v1/trust/anomalies       - Suspicious trust graph patterns (admin)")
+	fmt.Println("  GET  /api/v1/trust/summary         - Get trust graph summary (?watch=true waits for a change)")
+	fmt.Println("  GET  /api/v1/trust/algorithms      - List trust scoring algorithms")
+	fmt.Println("  GET  /api/v1/trust/weights         - Get trust score weights")
+	fmt.Println("  PUT  /api/v1/trust/weights         - Set the org's trust score weights (admin)")
+	fmt.Println("  DELETE /api/v1/trust/weights       - Reset the org's trust score weights (admin)")
+	fmt.Println("  GET  /api/v1/trust/terms           - List term-limited roles")
+	fmt.Println("  GET  /api/v1/trust/federation      - List federated peer orgs")
+	fmt.Println("  GET  /api/v1/public/stats          - Anonymous aggregate community stats")
+	fmt.Println("  POST /api/v1/trust/federation/kel  - Cache a peer org's KEL")
+	fmt.Println("  POST /api/v1/trust/federation/discover - Read another community's descriptor")
+	fmt.Println("  GET  /api/v1/members/match         - Find collaborators by skills and trust")
+	fmt.Println("  POST /api/v1/graphql               - Query the trust graph with GraphQL")
+	fmt.Println()
+	fmt.Println("  Taxonomy:")
+	fmt.Println("  GET  /api/v1/taxonomy                 - Skills and interests vocabularies")
+	fmt.Println("  PUT  /api/v1/taxonomy/{kind}          - Replace a vocabulary (admin)")
+	fmt.Println()
+	fmt.Println("  Schemas:")
+	fmt.Println("  GET  /api/v1/schemas                  - Registered credential schemas")
+	fmt.Println("  GET  /api/v1/schemas/{said}           - Get a credential schema")
+	fmt.Println("  PUT  /api/v1/schemas/{said}           - Register or update a schema (admin)")
+	fmt.Println()
+	fmt.Println("  Endorsements:")
+	fmt.Println("  POST /api/v1/endorsements/request                - Ask a peer for an endorsement")
+	fmt.Println("  GET  /api/v1/endorsements/requests               - List incoming/outgoing requests")
+	fmt.Println("  POST /api/v1/endorsements/requests/{id}/accept   - Accept and pre-fill issuance")
+	fmt.Println("  POST /api/v1/endorsements/requests/{id}/decline  - Decline privately")
+	fmt.Println("  GET  /api/v1/endorsements/types                  - Endorsement types and categories")
+	fmt.Println("  GET  /api/v1/endorsements/categories             - All endorsement categories")
+	fmt.Println("  GET  /api/v1/digest                              - Weekly digest schedule and preview")
+	fmt.Println("  POST /api/v1/digest/send                         - Email the past week's digest now")
+	fmt.Println()
+	fmt.Println("  Projects:")
+	fmt.Println("  GET  /api/v1/projects                        - List projects")
+	fmt.Println("  POST /api/v1/projects                        - Create project and its space")
+	fmt.Println("  GET  /api/v1/projects/{id}                   - Get project and roster")
+	fmt.Println("  POST /api/v1/projects/{id}/join              - Join (role credential check)")
+	fmt.Println("  POST /api/v1/projects/{id}/leave             - Leave project")
+	fmt.Println("  POST /api/v1/projects/{id}/members           - Add or promote member (lead)")
+	fmt.Println("  DELETE /api/v1/projects/{id}/members/{aid}   - Remove member")
+	fmt.Println("  POST /api/v1/projects/{id}/space/invite      - Invite member to project space")
+	fmt.Println("  POST /api/v1/projects/{id}/space/join        - Join project space")
+	fmt.Println("  GET  /api/v1/projects/{id}/files             - List/upload (POST) project files")
+	fmt.Println()
+	fmt.Println("  Calendar:")
+	fmt.Println("  GET  /api/v1/calendar/events                    - List events (?upcoming=true)")
+	fmt.Println("  POST /api/v1/calendar/events                    - Schedule event (admin)")
+	fmt.Println("  GET  /api/v1/calendar/events/{id}               - Get event with RSVPs")
+	fmt.Println("  POST /api/v1/calendar/events/{id}/cancel        - Cancel event (admin)")
+	fmt.Println("  POST /api/v1/calendar/events/{id}/rsvp          - RSVP going/maybe/not_going")
+	fmt.Println("  POST /api/v1/calendar/events/{id}/attendance    - Record attendance (admin)")
+	fmt.Println("  GET  /api/v1/calendar/events/{id}/credentials   - Pre-filled attendance credentials")
+	fmt.Println()
+	fmt.Println("  Contributions:")
+	fmt.Println("  GET  /api/v1/contributions                   - List contributions (?aid=&category=)")
+	fmt.Println("  POST /api/v1/contributions                   - Record contribution (steward)")
+	fmt.Println("  GET  /api/v1/contributions/{id}              - Get contribution")
+	fmt.Println("  GET  /api/v1/contributions/summary           - Per-member contribution summaries")
+	fmt.Println("  GET  /api/v1/contributions/summary/{aid}     - Member's contribution summary")
+	fmt.Println()
+	fmt.Println("  Grants:")
+	fmt.Println("  GET  /api/v1/grants                          - List grant requests (steward, ?status=)")
+	fmt.Println("  POST /api/v1/grants                          - Submit grant request (steward)")
+	fmt.Println("  GET  /api/v1/grants/{id}                     - Get grant request (steward)")
+	fmt.Println("  POST /api/v1/grants/{id}/review              - Move grant to next review state (steward)")
+	fmt.Println("  GET  /api/v1/grants/summaries                - Redacted public grant summaries")
 	fmt.Println()
 	fmt.Println("  Spaces (any-sync):")
 	fmt.Println("  POST /api/v1/spaces/community                - Create community space")
+	fmt.Println("  POST /api/v1/bootstrap                       - Create or recover all spaces")
+	fmt.Println("  GET  /api/v1/bootstrap/status                - Per-step bootstrap status")
 	fmt.Println("  GET  /api/v1/spaces/community                - Get community space info")
 	fmt.Println("  POST /api/v1/spaces/private                  - Create private space")
 	fmt.Println("  POST /api/v1/spaces/community/invite         - Generate invite for user")
 	fmt.Println("  POST /api/v1/spaces/community/join           - Join community with invite key")
+	fmt.Println("  POST /api/v1/spaces/join                     - Join a space as a credentialed member")
+	fmt.Println("  POST /api/v1/spaces/{id}/requests            - Ask to join a space (reviewed by admins)")
+	fmt.Println("  GET  /api/v1/spaces/{id}/requests            - List join requests (admin)")
+	fmt.Println("  POST /api/v1/spaces/{id}/requests/{rid}/approve - Approve a join request (admin)")
+	fmt.Println("  POST /api/v1/spaces/{id}/requests/{rid}/reject  - Reject a join request (admin)")
 	fmt.Println("  GET  /api/v1/spaces/community/verify-access  - Verify community access")
 	fmt.Println("  GET  /api/v1/spaces/sync-status              - Check space sync readiness")
+	fmt.Println("  GET  /api/v1/spaces                          - List known spaces with head and coordinator status (admin)")
+	fmt.Println("  POST /api/v1/spaces/reencrypt                 - Re-key a space and rewrite its content (admin)")
+	fmt.Println("  GET  /api/v1/spaces/reencrypt/status          - Re-encryption progress (admin)")
+	fmt.Println("  DELETE /api/v1/spaces/{id}                    - Delete a space and its files (admin, ?local=true keeps it on the network)")
 	fmt.Println()
 	fmt.Println("  Invites:")
 	fmt.Println("  POST /api/v1/invites/send-email       - Email invite code to user")
+	fmt.Println("  POST /api/v1/invites                  - Create an expiring, use-limited invite (admin)")
+	fmt.Println("  GET  /api/v1/invites                  - List issued invites with use counts (admin)")
+	fmt.Println("  GET  /api/v1/invites/{id}             - Get an issued invite (admin)")
+	fmt.Println("  DELETE /api/v1/invites/{id}           - Revoke an invite (admin)")
+	fmt.Println("  POST /api/v1/invites/email            - Email an invite link with retries (admin)")
+	fmt.Println("  GET  /api/v1/invites/email            - List invite email deliveries (admin)")
 	fmt.Println()
 	fmt.Println("  Notifications:")
 	fmt.Println("  POST /api/v1/notifications/registration-submitted - Notify onboarding of new registration")
@@ -450,30 +1142,299 @@ func main() {
 	fmt.Println("  GET  /api/v1/profiles/{type}/{id}     - Get specific profile")
 	fmt.Println("  GET  /api/v1/profiles/me              - Get current user's profiles")
 	fmt.Println("  POST /api/v1/profiles/init-member     - Initialize member profiles (admin)")
+	fmt.Println("  GET  /api/v1/objects/{space}/{id}/history - Change history of an object")
+	fmt.Println()
+	fmt.Println("  Inbox:")
+	fmt.Println("  GET  /api/v1/inbox                    - List items drained from my inbox")
+	fmt.Println("  POST /api/v1/inbox                    - Create and publish my inbox space")
+	fmt.Println("  POST /api/v1/inbox/deliver            - Deposit a sealed item in a member's inbox")
+	fmt.Println("  POST /api/v1/inbox/drain              - Drain my inbox now")
 	fmt.Println()
 	fmt.Println("  Files:")
 	fmt.Println("  POST /api/v1/files/upload             - Upload file (avatar)")
+	fmt.Println("  GET  /api/v1/files/quota              - My upload quota usage")
+	fmt.Println("  POST /api/v1/files/uploads            - Start a resumable chunked upload")
+	fmt.Println("  GET  /api/v1/files/uploads/{id}       - Chunked upload progress")
+	fmt.Println("  PUT  /api/v1/files/uploads/{id}       - Append a chunk at ?offset=")
+	fmt.Println("  POST /api/v1/files/uploads/{id}/complete - Finish a chunked upload")
+	fmt.Println("  DELETE /api/v1/files/uploads/{id}     - Abort a chunked upload")
+	fmt.Println("  GET  /api/v1/admin/files/cache        - File block cache stats (admin)")
+	fmt.Println("  DELETE /api/v1/admin/files/cache[/{ref}] - Drop cached blocks (admin)")
 	fmt.Println("  GET  /api/v1/files/{ref}              - Download file by ref")
 	fmt.Println()
 	fmt.Println("  Events:")
-	fmt.Println("  GET  /api/v1/events                   - SSE event stream")
+	fmt.Println("  GET  /api/v1/events                   - SSE / WebSocket event stream")
 	fmt.Println()
 	fmt.Println("  Org Config:")
 	fmt.Println("  GET  /api/v1/org/config               - Get org configuration")
 	fmt.Println("  POST /api/v1/org/config               - Save org configuration")
 	fmt.Println("  GET  /api/v1/org/health               - Config service health")
+	fmt.Println("  GET  /api/v1/config/effective         - Running config.yaml and org config (admin)")
+	fmt.Println()
+	fmt.Println("  Organizations (multi-tenant):")
+	fmt.Println("  GET  /api/v1/orgs                     - Organizations served by this backend")
+	fmt.Println("  POST /api/v1/orgs                     - Register an additional organization")
+	fmt.Println("  GET  /api/v1/orgs/{aid}               - Organization summary")
+	fmt.Println("  DELETE /api/v1/orgs/{aid}             - Stop serving an organization")
+	fmt.Println("  *    /api/v1/orgs/{aid}/...           - Org-scoped route (or send X-Org-AID)")
+	fmt.Println()
+	fmt.Println("  Feature Flags:")
+	fmt.Println("  GET  /api/v1/admin/flags              - List feature flags")
+	fmt.Println("  PUT  /api/v1/admin/flags/{name}       - Enable/disable a feature flag")
+	fmt.Println("  DELETE /api/v1/admin/flags/{name}     - Reset flag to configured default")
+	fmt.Println()
+	fmt.Println("  Maintenance:")
+	fmt.Println("  GET  /api/v1/admin/maintenance        - Get maintenance mode status")
+	fmt.Println("  POST /api/v1/admin/maintenance        - Enable/disable maintenance mode")
+	fmt.Println()
+	fmt.Println("  Sync Test:")
+	fmt.Println("  POST /api/v1/admin/sync-test          - Measure probe propagation to nodes and peer backend")
+	fmt.Println("  GET  /api/v1/admin/sync-test/probes/{id} - Read a peer's sync probe")
+	fmt.Println()
+	fmt.Println("  Store:")
+	fmt.Println("  GET  /api/v1/admin/store/stats        - Database size and per-collection usage")
+	fmt.Println("  POST /api/v1/admin/store/vacuum       - Prune expired caches and old records")
+	fmt.Println("  POST /api/v1/admin/spaces/gc          - Remove local storage of deleted spaces")
+	fmt.Println("  POST /api/v1/admin/acl/reconcile      - Reconcile community ACL with memberships")
+	fmt.Println()
+	fmt.Println("  Audit:")
+	fmt.Println("  GET  /api/v1/audit                    - Audit log of privileged operations (admin)")
+	fmt.Println("  GET  /api/v1/audit/ledger             - Shared admin space audit log with verification (admin)")
+	fmt.Println()
+	fmt.Println("  KERIA Proxy:")
+	fmt.Println("  *    /api/v1/keria/*                  - Forward signify requests to the KERIA admin API")
+	fmt.Println("  POST /api/v1/keria/boot               - Forward agent boot to the KERIA boot API")
+	fmt.Println("  GET  /api/v1/admin/keria/bindings     - List signify controller bindings")
+	fmt.Println("  DELETE /api/v1/admin/keria/bindings/{controller} - Release a controller binding")
+	fmt.Println()
+	fmt.Println("  Coordination Locks:")
+	fmt.Println("  GET  /api/v1/admin/locks              - List guarded resources and held locks")
+	fmt.Println("  GET  /api/v1/admin/locks/{resource}   - Get a resource's lock status")
+	fmt.Println("  POST /api/v1/admin/locks/{resource}   - Take a lease on a resource")
+	fmt.Println("  DELETE /api/v1/admin/locks/{resource} - Release a lease (?force=true for another admin's)")
+	fmt.Println()
+	fmt.Println("  Service Tokens:")
+	fmt.Println("  GET  /api/v1/admin/tokens             - List service tokens")
+	fmt.Println("  POST /api/v1/admin/tokens             - Issue a scoped service token")
+	fmt.Println("  DELETE /api/v1/admin/tokens/{id}      - Revoke a service token")
 	fmt.Println()
 
 	// Start background sync worker
 	syncWorkerConfig := bgSync.DefaultConfig()
 	syncWorkerConfig.CommunitySpaceID = communitySpaceID
 	syncWorker := bgSync.NewWorker(syncWorkerConfig, spaceManager, store, eventBroker)
+	syncWorker.SetMaintenance(maintenanceMode)
+	syncWorker.SetIdentity(userIdentity)
 	syncWorker.Start()
-	defer syncWorker.Stop()
 
-	// Wrap with CORS middleware
-	handler := api.CORSMiddleware(mux)
-	if err := http.ListenAndServe(addr, handler); err != nil {
-		log.Fatalf("Server failed: %v", err)
+	// Start credential hydrator to keep the credential cache in step with the community tree
+	credentialHydrator.SetMaintenance(maintenanceMode)
+	credentialHydrator.Start()
+
+	// Rewrite existing trees into the configured change encoding
+	if cfg.AnySync.MigrateChanges {
+		go func() {
+			for _, spaceID := range []string{communitySpaceID, communityReadOnlySpaceID, adminSpaceID} {
+				if spaceID == "" {
+					continue
+				}
+				migration, err := spaceManager.MigrateChangeEncoding(context.Background(), spaceID, compactChanges)
+				if err != nil {
+					fmt.Printf("[Encoding] Warning: migrating space %s: %v\n", spaceID, err)
+					continue
+				}
+				failed := 0
+				for _, tree := range migration.Trees {
+					if tree.Error != "" {
+						failed++
+						fmt.Printf("[Encoding] Warning: tree %s in space %s not migrated: %s\n", tree.OldID, spaceID, tree.Error)
+					}
+				}
+				fmt.Printf("[Encoding] Space %s: %d of %d trees rewritten as %s, %d failed\n",
+					spaceID, len(migration.Trees)-failed, migration.TreesChecked, migration.Encoding, failed)
+			}
+		}()
+	}
+
+	// Start term expiry watcher for term-limited roles
+	termWatcher := bgSync.NewTermWatcher(&bgSync.TermWatcherConfig{
+		Interval:     cfg.Terms.CheckInterval,
+		NoticeWindow: cfg.Terms.NoticeWindow,
+	}, store, eventBroker)
+	termWatcher.SetMaintenance(maintenanceMode)
+
+	// Start credential expirer so credentials past their expiresAt are marked expired
+	credentialExpirer := bgSync.NewCredentialExpirer(cfg.Terms.ExpiryInterval, store, eventBroker)
+	credentialExpirer.SetMaintenance(maintenanceMode)
+	credentialExpirer.Start()
+
+	// Start presence watcher for member last-seen tracking
+	presenceWatcher := bgSync.NewPresenceWatcher(bgSync.DefaultPresenceInterval, presenceTracker)
+	presenceWatcher.SetMaintenance(maintenanceMode)
+
+	// Start inbox watcher to drain items delivered while offline
+	inboxWatcher := bgSync.NewInboxWatcher(bgSync.DefaultInboxInterval, inboxHandler)
+	inboxWatcher.SetMaintenance(maintenanceMode)
+
+	// Start store vacuumer to prune caches and old records
+	storeVacuumer := bgSync.NewStoreVacuumer(cfg.Store.VacuumInterval, storeHandler)
+	storeVacuumer.SetMaintenance(maintenanceMode)
+	storeVacuumer.Start()
+
+	// Start trust history recorder to keep score history for charts
+	trustHistory := bgSync.NewTrustHistoryRecorder(cfg.Trust.HistoryInterval, trustHandler)
+	trustHistory.SetMaintenance(maintenanceMode)
+	trustHistory.Start()
+
+	// Start trust graph materializer so trust reads load the stored graph
+	trustGraph := bgSync.NewTrustGraphMaterializer(cfg.Trust.MaterializeInterval, trustHandler)
+	trustGraph.SetMaintenance(maintenanceMode)
+	trustGraph.Start()
+
+	// Start space collector to remove storage of spaces deleted on the network
+	spaceCollector := bgSync.NewSpaceCollector(cfg.AnySync.SpaceGCInterval, spacesHandler)
+	spaceCollector.SetMaintenance(maintenanceMode)
+	spaceCollector.Start()
+
+	// Start ACL reconciler to keep community access in line with memberships
+	aclReconciler := bgSync.NewACLReconciler(cfg.AnySync.ACLReconcileInterval, memberAccess)
+	aclReconciler.SetMaintenance(maintenanceMode)
+
+	// Start invite sweeper to revoke invites once used up or expired
+	inviteSweeper := bgSync.NewInviteSweeper(cfg.AnySync.InviteSweepInterval, invitesHandler)
+	inviteSweeper.SetMaintenance(maintenanceMode)
+
+	// Start digest scheduler to email the member's weekly digest in their timezone
+	digestScheduler := bgSync.NewDigestScheduler(bgSync.DefaultDigestInterval, digestHandler)
+	digestScheduler.SetMaintenance(maintenanceMode)
+
+	// Workers that write to the spaces or shared records run on one replica
+	// only: the leader in cluster mode, otherwise this backend
+	leaderWorkers := []interface {
+		Start()
+		Stop()
+	}{termWatcher, presenceWatcher, inboxWatcher, aclReconciler, inviteSweeper, digestScheduler}
+	if elector != nil {
+		elector.OnChange(func(leader bool) {
+			for _, worker := range leaderWorkers {
+				if leader {
+					worker.Start()
+				} else {
+					worker.Stop()
+				}
+			}
+		})
+		elector.Start()
+	} else {
+		for _, worker := range leaderWorkers {
+			worker.Start()
+		}
+	}
+
+	// Wrap with read-your-writes, org routing, locks, timeout, signature, load shedding, long polling, route rate limits, guest access, maintenance, authentication, CORS, replica forwarding and (optional) metrics and access log middleware
+	routeTimeouts := api.NewRouteTimeouts(cfg.Server.RequestTimeout, cfg.Server.RouteTimeouts)
+	consistency := api.NewConsistency(spaceManager.ObjectTreeManager(), store, cfg.Server.ConsistencyTimeout)
+	var handler http.Handler = api.CORSMiddleware(api.OrgMiddleware(orgRegistry, api.AuthMiddleware(authenticator, api.MaintenanceMiddleware(maintenanceMode, api.AccessMiddleware(accessControl, api.RouteLimitMiddleware(routeLimiter, api.WatchMiddleware(watcher, api.LoadSheddingMiddleware(loadShedder, api.SignatureMiddleware(signatureVerifier, api.TimeoutMiddleware(routeTimeouts, api.LockMiddleware(locks, orgRegistry.Dispatch(api.ConsistencyMiddleware(consistency, mux)))))))))))))
+	handler = api.ReplicaMiddleware(replicaRouter, handler)
+	if cfg.Metrics.Enabled {
+		handler = api.MetricsMiddleware(mux, handler)
+	}
+	// Access logging is always installed so a reload can turn it on
+	accessLog := api.NewAccessLog(cfg.Logging.AccessLog, accessLogOptions(cfg.Logging))
+	configReloader.OnReload(func(c *config.Config) {
+		accessLog.Configure(c.Logging.AccessLog, accessLogOptions(c.Logging))
+	})
+	handler = accessLog.Middleware(handler)
+	if cfg.Logging.AccessLog {
+		fmt.Printf("Access logging enabled (bodies: %t)\n", cfg.Logging.LogBodies)
+	}
+
+	// Bind every configured listener; each gets its own http.Server so
+	// route groups can be restricted per listener
+	lifecycleManager := lifecycle.NewManager(cfg.Server.ShutdownTimeout)
+	for _, lc := range cfg.Server.ResolvedListeners() {
+		ln, err := listen(lc)
+		if err != nil {
+			log.Fatalf("Failed to listen on %s (%s %s): %v", lc.Name, lc.Network, lc.Address, err)
+		}
+		server := &http.Server{
+			Handler:           api.RouteGroupMiddleware(lc.Routes, handler),
+			ReadTimeout:       cfg.Server.ReadTimeout,
+			ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+			WriteTimeout:      cfg.Server.WriteTimeout,
+			IdleTimeout:       cfg.Server.IdleTimeout,
+		}
+		server.RegisterOnShutdown(eventBroker.Close)
+		server.RegisterOnShutdown(watcher.Close)
+		lifecycleManager.Serve(lc.Name, server, ln)
+		if len(lc.Routes) > 0 {
+			fmt.Printf("Listening on %s %s (%s) routes: %s\n", lc.Network, lc.Address, lc.Name, strings.Join(lc.Routes, ", "))
+		} else {
+			fmt.Printf("Listening on %s %s (%s)\n", lc.Network, lc.Address, lc.Name)
+		}
+	}
+
+	// Serve the gRPC API on its own listener; calls run through the same
+	// handler chain as the REST routes they mirror
+	if cfg.GRPC.Enabled() {
+		lc := cfg.GRPC.Listener()
+		ln, err := listen(lc)
+		if err != nil {
+			log.Fatalf("Failed to listen on %s (%s %s): %v", lc.Name, lc.Network, lc.Address, err)
+		}
+		lifecycleManager.Serve(lc.Name, rpc.NewServer(handler), ln)
+		fmt.Printf("Serving gRPC on %s %s\n", lc.Network, lc.Address)
+	}
+
+	// Apply config.yaml and org-config.yaml edits without a restart
+	if configWatcher, err := config.NewWatcher(); err != nil {
+		fmt.Printf("Config reload disabled: %v\n", err)
+	} else {
+		if err := configWatcher.Watch(configPath, func() { configReloader.Reload() }); err != nil {
+			fmt.Printf("Not watching %s: %v\n", configPath, err)
+		}
+		if err := configWatcher.Watch(orgConfigHandler.Path(), func() {
+			if _, err := orgConfigHandler.Reload(); err != nil {
+				fmt.Printf("[OrgConfig] Keeping running config: %v\n", err)
+			}
+		}); err != nil {
+			fmt.Printf("Not watching %s: %v\n", orgConfigHandler.Path(), err)
+		}
+		go configWatcher.Run()
+		lifecycleManager.OnShutdown("config watcher", configWatcher.Close)
+	}
+
+	// After HTTP is drained, stop the background workers before the
+	// any-sync app and stores they read from
+	if elector != nil {
+		// Hand leadership over first; losing it stops the leader-only workers
+		lifecycleManager.OnShutdown("leader election", func() error { elector.Stop(); return nil })
+	}
+	lifecycleManager.OnShutdown("space re-encryption", func() error { reencryptHandler.Shutdown(); return nil })
+	lifecycleManager.OnShutdown("sync worker", func() error { syncWorker.Stop(); return nil })
+	lifecycleManager.OnShutdown("credential hydrator", func() error { credentialHydrator.Stop(); return nil })
+	lifecycleManager.OnShutdown("term watcher", func() error { termWatcher.Stop(); return nil })
+	lifecycleManager.OnShutdown("credential expirer", func() error { credentialExpirer.Stop(); return nil })
+	lifecycleManager.OnShutdown("presence watcher", func() error { presenceWatcher.Stop(); return nil })
+	lifecycleManager.OnShutdown("inbox watcher", func() error { inboxWatcher.Stop(); return nil })
+	lifecycleManager.OnShutdown("store vacuumer", func() error { storeVacuumer.Stop(); return nil })
+	lifecycleManager.OnShutdown("trust history recorder", func() error { trustHistory.Stop(); return nil })
+	lifecycleManager.OnShutdown("trust graph materializer", func() error { trustGraph.Stop(); return nil })
+	lifecycleManager.OnShutdown("space collector", func() error { spaceCollector.Stop(); return nil })
+	lifecycleManager.OnShutdown("ACL reconciler", func() error { aclReconciler.Stop(); return nil })
+	lifecycleManager.OnShutdown("invite sweeper", func() error { inviteSweeper.Stop(); return nil })
+	lifecycleManager.OnShutdown("digest scheduler", func() error { digestScheduler.Stop(); return nil })
+	lifecycleManager.OnShutdown("any-sync client", sdkClient.Close)
+	lifecycleManager.OnShutdown("KERI client", keriClient.Close)
+	lifecycleManager.OnShutdown("local store", store.Close)
+	if sqlRecords != nil {
+		lifecycleManager.OnShutdown("record store", sqlRecords.Close)
+	}
+	if logOutput != nil {
+		lifecycleManager.OnShutdown("log file", logOutput.Close)
+	}
+
+	if err := lifecycleManager.Run(context.Background()); err != nil {
+		log.Fatalf("Server stopped with errors: %v", err)
 	}
 }
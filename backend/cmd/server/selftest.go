@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anyproto/any-sync/util/crypto"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/config"
+	"github.com/matou-dao/backend/internal/email"
+	"github.com/matou-dao/backend/internal/selftest"
+)
+
+// runSelfTest runs the --selftest checks against this deployment's config
+// and prints a pass/fail report. It returns the process exit code: 0 when
+// every check passed or was skipped. Nothing is written outside temporary
+// directories in the data directory, so it is safe to run next to a live
+// server.
+func runSelfTest(cfg *config.Config, dataDir, anysyncConfigPath string) int {
+	fmt.Println("Running self-test...")
+	fmt.Println()
+
+	checks := []selftest.Check{
+		{Name: "key derivation", Run: checkKeyDerivation},
+		{Name: "anystore read/write", Run: func(ctx context.Context) error {
+			return checkStore(ctx, dataDir)
+		}},
+		{Name: "coordinator ping", Timeout: 30 * time.Second, Run: func(ctx context.Context) error {
+			return checkCoordinator(dataDir, anysyncConfigPath)
+		}},
+		selftest.HTTPCheck("KERIA admin", keriaHealthURL(cfg.KERI.AdminURL)),
+		selftest.HTTPCheck("KERIA boot", keriaHealthURL(cfg.KERI.BootURL)),
+		{Name: "SMTP connect", Run: func(ctx context.Context) error {
+			if cfg.SMTP.Host == "" {
+				return selftest.Skip("SMTP not configured")
+			}
+			return email.NewSender(cfg.SMTP).Ping(ctx)
+		}},
+	}
+
+	results := selftest.Run(context.Background(), checks)
+	if !selftest.Report(os.Stdout, results) {
+		return 1
+	}
+	return 0
+}
+
+// checkKeyDerivation derives keys from a fresh mnemonic twice and checks
+// they match, sign and verify, and agree with the space key derivation
+func checkKeyDerivation(ctx context.Context) error {
+	mnemonic, err := crypto.NewMnemonicGenerator().WithWordCount(12)
+	if err != nil {
+		return fmt.Errorf("generating mnemonic: %w", err)
+	}
+
+	first, err := anysync.DeriveKeyFromMnemonic(string(mnemonic), 0)
+	if err != nil {
+		return err
+	}
+	second, err := anysync.DeriveKeyFromMnemonic(string(mnemonic), 0)
+	if err != nil {
+		return err
+	}
+	if !first.Equals(second) {
+		return fmt.Errorf("derivation is not deterministic")
+	}
+
+	msg := []byte("matou selftest")
+	sig, err := first.Sign(msg)
+	if err != nil {
+		return fmt.Errorf("signing: %w", err)
+	}
+	if ok, err := second.GetPublic().Verify(msg, sig); err != nil || !ok {
+		return fmt.Errorf("signature did not verify (%v)", err)
+	}
+
+	// Space index 0 signs with the identity key at derivation index 0
+	keys, err := anysync.DeriveSpaceKeySet(string(mnemonic), 0)
+	if err != nil {
+		return err
+	}
+	if !keys.SigningKey.Equals(first) {
+		return fmt.Errorf("space signing key does not match identity key")
+	}
+	return nil
+}
+
+// checkStore writes and reads back a value in a scratch store in the data
+// directory, which checks the directory is writable and any-store works
+func checkStore(ctx context.Context, dataDir string) error {
+	dir, err := os.MkdirTemp(dataDir, "selftest-store-")
+	if err != nil {
+		return fmt.Errorf("data directory not writable: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := anystore.NewLocalStore(anystore.DefaultConfig(dir))
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	want := fmt.Sprintf("selftest-%d", time.Now().UnixNano())
+	if err := store.SetPreference(ctx, "selftest", want); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	got, err := store.GetPreference(ctx, "selftest")
+	if err != nil {
+		return fmt.Errorf("read: %w", err)
+	}
+	if got != want {
+		return fmt.Errorf("read back %v, wrote %s", got, want)
+	}
+	return nil
+}
+
+// checkCoordinator starts an any-sync client with a throwaway peer key and
+// pings the coordinator
+func checkCoordinator(dataDir, configPath string) error {
+	if _, err := os.Stat(configPath); err != nil {
+		return fmt.Errorf("any-sync config: %w", err)
+	}
+	dir, err := os.MkdirTemp(dataDir, "selftest-anysync-")
+	if err != nil {
+		return fmt.Errorf("data directory not writable: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	client, err := anysync.NewSDKClient(configPath, &anysync.ClientOptions{
+		DataDir:     dir,
+		PeerKeyPath: filepath.Join(dir, "peer.key"),
+	})
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.Ping()
+}
+
+// keriaHealthURL returns KERIA's health endpoint for a base URL
+func keriaHealthURL(base string) string {
+	if base == "" {
+		return ""
+	}
+	return strings.TrimSuffix(base, "/") + "/health"
+}
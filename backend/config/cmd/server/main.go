@@ -338,7 +338,7 @@ func main() {
 	healthHandler := api.NewHealthHandler(store, spaceStore, orgConfigHandler.GetOrgAID(), orgConfigHandler.GetAdminAID())
 	spacesHandler := api.NewSpacesHandler(spaceManager, store, userIdentity)
 	emailSender := email.NewSender(cfg.SMTP)
-	invitesHandler := api.NewInvitesHandler(emailSender)
+	invitesHandler := api.NewInvitesHandler(emailSender, spaceManager, store)
 	bookingHandler := api.NewBookingHandler(emailSender)
 	notificationsHandler := api.NewNotificationsHandler(emailSender)
 	identityHandler := api.NewIdentityHandler(userIdentity, sdkClient, spaceManager, spaceStore)
@@ -7,16 +7,29 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	anystore "github.com/anyproto/any-store"
 	"github.com/anyproto/any-store/anyenc"
+
+	"github.com/matou-dao/backend/internal/metrics"
 )
 
 // LocalStore wraps an any-store database for MATOU local storage needs.
 type LocalStore struct {
 	db     anystore.DB
 	dbPath string
+
+	// revision counts writes made through the store, see Revision; mu
+	// keeps it in step with what snapshots see
+	mu       sync.RWMutex
+	revision atomic.Uint64
+
+	// credentialRevision counts writes to the credentials cache only, see
+	// CredentialRevision
+	credentialRevision atomic.Uint64
 }
 
 // Config holds configuration for the local store.
@@ -55,10 +68,17 @@ func NewLocalStore(cfg *Config) (*LocalStore, error) {
 		return nil, fmt.Errorf("failed to open any-store database: %w", err)
 	}
 
-	return &LocalStore{
+	store := &LocalStore{
 		db:     db,
 		dbPath: cfg.DBPath,
-	}, nil
+	}
+	for name := range collectionIndexes {
+		if err := store.ensureIndexes(ctx, name); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return store, nil
 }
 
 // Close closes the database connection.
@@ -82,6 +102,15 @@ const (
 	CollectionKELCache         = "kel_cache"
 	CollectionSyncIndex        = "sync_index"
 	CollectionSpaces           = "spaces"
+	CollectionPeerMappings     = "peer_mappings"
+	CollectionMemberPresence   = "member_presence"
+	CollectionInbox            = "inbox"
+	CollectionInvites          = "invites"
+	CollectionEmailDeliveries  = "email_deliveries"
+	CollectionTrustHistory     = "trust_score_history"
+	CollectionTrustNodes       = "trust_nodes"
+	CollectionTrustEdges       = "trust_edges"
+	CollectionAuditLog         = "audit_log"
 )
 
 // CredentialsCache returns the credentials cache collection.
@@ -109,6 +138,51 @@ func (s *LocalStore) SyncIndex(ctx context.Context) (anystore.Collection, error)
 	return s.db.Collection(ctx, CollectionSyncIndex)
 }
 
+// PeerMappings returns the AID to any-sync peer ID mapping collection.
+func (s *LocalStore) PeerMappings(ctx context.Context) (anystore.Collection, error) {
+	return s.db.Collection(ctx, CollectionPeerMappings)
+}
+
+// MemberPresence returns the member last-seen collection.
+func (s *LocalStore) MemberPresence(ctx context.Context) (anystore.Collection, error) {
+	return s.db.Collection(ctx, CollectionMemberPresence)
+}
+
+// Inbox returns the collection of items drained from the member's inbox space.
+func (s *LocalStore) Inbox(ctx context.Context) (anystore.Collection, error) {
+	return s.db.Collection(ctx, CollectionInbox)
+}
+
+// Invites returns the collection of invites issued from this backend.
+func (s *LocalStore) Invites(ctx context.Context) (anystore.Collection, error) {
+	return s.db.Collection(ctx, CollectionInvites)
+}
+
+// EmailDeliveries returns the collection of invite email delivery attempts.
+func (s *LocalStore) EmailDeliveries(ctx context.Context) (anystore.Collection, error) {
+	return s.db.Collection(ctx, CollectionEmailDeliveries)
+}
+
+// TrustHistory returns the trust score history collection.
+func (s *LocalStore) TrustHistory(ctx context.Context) (anystore.Collection, error) {
+	return s.db.Collection(ctx, CollectionTrustHistory)
+}
+
+// TrustNodes returns the materialized trust graph node collection.
+func (s *LocalStore) TrustNodes(ctx context.Context) (anystore.Collection, error) {
+	return s.db.Collection(ctx, CollectionTrustNodes)
+}
+
+// TrustEdges returns the materialized trust graph edge collection.
+func (s *LocalStore) TrustEdges(ctx context.Context) (anystore.Collection, error) {
+	return s.db.Collection(ctx, CollectionTrustEdges)
+}
+
+// AuditLog returns the audit log collection.
+func (s *LocalStore) AuditLog(ctx context.Context) (anystore.Collection, error) {
+	return s.db.Collection(ctx, CollectionAuditLog)
+}
+
 // CachedCredential represents a cached ACDC credential.
 type CachedCredential struct {
 	ID         string    `json:"id"`         // SAID of the credential
@@ -119,6 +193,10 @@ type CachedCredential struct {
 	CachedAt   time.Time `json:"cachedAt"`   // When it was cached
 	ExpiresAt  time.Time `json:"expiresAt"`  // Cache expiration
 	Verified   bool      `json:"verified"`   // Whether signature was verified
+
+	// ExpiredAt is when the expiration job found the credential's own
+	// expiresAt claim had passed
+	ExpiredAt *time.Time `json:"expiredAt,omitempty"`
 }
 
 // TrustGraphNode represents a cached trust graph node.
@@ -139,24 +217,133 @@ type UserPreference struct {
 	UpdatedAt time.Time `json:"updatedAt"` // Last update time
 }
 
-// StoreCredential caches a credential locally.
+// PeerMapping maps a member's AID to the any-sync peer ID their backend
+// signs with, which is their identity in space ACLs.
+type PeerMapping struct {
+	AID       string    `json:"id"`        // AID (used as document ID)
+	PeerID    string    `json:"peerId"`    // any-sync peer ID
+	UpdatedAt time.Time `json:"updatedAt"` // When the mapping was recorded
+}
+
+// MemberPresenceRecord records when a member was last seen active.
+type MemberPresenceRecord struct {
+	AID          string    `json:"id"`           // AID (used as document ID)
+	LastActiveAt time.Time `json:"lastActiveAt"` // Most recent activity seen
+	Source       string    `json:"source"`       // Signal that reported it (acl, heartbeat, sync)
+}
+
+// InboxRecord is an item drained from the member's inbox space, opened.
+type InboxRecord struct {
+	ID         string          `json:"id"`         // Inbox item object ID
+	SenderAID  string          `json:"senderAid"`  // Who deposited the item
+	Kind       string          `json:"kind"`       // credential, notification or message
+	Payload    json.RawMessage `json:"payload"`    // Decrypted item payload
+	CreatedAt  time.Time       `json:"createdAt"`  // When the sender deposited it
+	ReceivedAt time.Time       `json:"receivedAt"` // When this backend drained it
+}
+
+// InviteRecord tracks an invite issued from this backend. The invite key
+// itself is only handed out when the invite is created.
+type InviteRecord struct {
+	ID         string     `json:"id"`                  // ACL invite record ID
+	SpaceID    string     `json:"spaceId"`             // Space the invite joins
+	Scope      string     `json:"scope"`               // community, readonly or space
+	Permission string     `json:"permission"`          // reader or writer
+	MaxUses    int        `json:"maxUses"`             // Joins allowed (0 = unlimited)
+	Uses       int        `json:"uses"`                // Joins seen in the space ACL
+	Status     string     `json:"status"`              // active, used, expired or revoked
+	CreatedBy  string     `json:"createdBy,omitempty"` // AID of the admin who created it
+	CreatedAt  time.Time  `json:"createdAt"`           // When it was created
+	ExpiresAt  time.Time  `json:"expiresAt"`           // When it stops being accepted
+	ClosedAt   *time.Time `json:"closedAt,omitempty"`  // When it was revoked in the ACL
+}
+
+// EmailDeliveryRecord tracks sending an invite email to one recipient.
+type EmailDeliveryRecord struct {
+	ID        string     `json:"id"`                  // Random delivery ID
+	InviteID  string     `json:"inviteId"`            // Invite the email carries
+	To        string     `json:"to"`                  // Recipient address
+	Status    string     `json:"status"`              // pending, sent or failed
+	Attempts  int        `json:"attempts"`            // Sends tried so far
+	LastError string     `json:"lastError,omitempty"` // Error from the latest failed attempt
+	CreatedBy string     `json:"createdBy,omitempty"` // AID of the admin who sent it
+	CreatedAt time.Time  `json:"createdAt"`           // When it was queued
+	UpdatedAt time.Time  `json:"updatedAt"`           // When the latest attempt finished
+	SentAt    *time.Time `json:"sentAt,omitempty"`    // When the SMTP server accepted it
+}
+
+// TrustScorePoint is one AID's trust score as recorded by a history
+// snapshot. Points of one snapshot share TakenAt.
+type TrustScorePoint struct {
+	ID        string    `json:"id"`         // AID and snapshot time (used as document ID)
+	AID       string    `json:"aid"`        // Scored AID
+	Score     float64   `json:"score"`      // Trust score
+	Depth     int       `json:"graphDepth"` // Distance from the org
+	Algorithm string    `json:"algorithm"`  // Algorithm the score was calculated with
+	TakenAt   time.Time `json:"takenAt"`    // When the snapshot was taken
+}
+
+// StoreCredential caches a credential locally. Re-storing a credential with
+// the same content, as the sync worker does after a restart, keeps the
+// expiration job's ExpiredAt mark.
 func (s *LocalStore) StoreCredential(ctx context.Context, cred *CachedCredential) error {
+	defer metrics.ObserveStoreQuery("store_credential", time.Now())
+	defer s.writing()()
+
 	coll, err := s.CredentialsCache(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get credentials collection: %w", err)
 	}
 
+	if cred.ExpiredAt == nil {
+		if doc, err := coll.FindId(ctx, cred.ID); err == nil {
+			var existing CachedCredential
+			if json.Unmarshal([]byte(doc.Value().String()), &existing) == nil &&
+				existing.ExpiredAt != nil && sameCredentialContent(&existing, cred) {
+				kept := *cred
+				kept.ExpiredAt = existing.ExpiredAt
+				cred = &kept
+			}
+		}
+	}
+
 	data, err := json.Marshal(cred)
 	if err != nil {
 		return fmt.Errorf("failed to marshal credential: %w", err)
 	}
 
 	doc := anyenc.MustParseJson(string(data))
-	return coll.UpsertOne(ctx, doc)
+	return s.credentialsWrote(s.wrote(coll.UpsertOne(ctx, doc)))
+}
+
+// sameCredentialContent reports whether two cached copies of a credential
+// have the same issuer, subject, schema and data. Data is compared as JSON,
+// since a stored copy reads back as generic maps.
+func sameCredentialContent(a, b *CachedCredential) bool {
+	if a.IssuerAID != b.IssuerAID || a.SubjectAID != b.SubjectAID || a.SchemaID != b.SchemaID {
+		return false
+	}
+	return canonicalJSON(a.Data) == canonicalJSON(b.Data)
+}
+
+// canonicalJSON encodes v with map keys sorted, whatever its Go type
+func canonicalJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return ""
+	}
+	data, _ = json.Marshal(generic)
+	return string(data)
 }
 
 // GetCredential retrieves a cached credential by SAID.
 func (s *LocalStore) GetCredential(ctx context.Context, said string) (*CachedCredential, error) {
+	defer metrics.ObserveStoreQuery("get_credential", time.Now())
+
 	coll, err := s.CredentialsCache(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get credentials collection: %w", err)
@@ -177,6 +364,9 @@ func (s *LocalStore) GetCredential(ctx context.Context, said string) (*CachedCre
 
 // StoreTrustNode caches a trust graph node.
 func (s *LocalStore) StoreTrustNode(ctx context.Context, node *TrustGraphNode) error {
+	defer metrics.ObserveStoreQuery("store_trust_node", time.Now())
+	defer s.writing()()
+
 	coll, err := s.TrustGraphCache(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get trust graph collection: %w", err)
@@ -188,11 +378,13 @@ func (s *LocalStore) StoreTrustNode(ctx context.Context, node *TrustGraphNode) e
 	}
 
 	doc := anyenc.MustParseJson(string(data))
-	return coll.UpsertOne(ctx, doc)
+	return s.wrote(coll.UpsertOne(ctx, doc))
 }
 
 // GetTrustNode retrieves a cached trust graph node by AID.
 func (s *LocalStore) GetTrustNode(ctx context.Context, aid string) (*TrustGraphNode, error) {
+	defer metrics.ObserveStoreQuery("get_trust_node", time.Now())
+
 	coll, err := s.TrustGraphCache(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get trust graph collection: %w", err)
@@ -211,8 +403,467 @@ func (s *LocalStore) GetTrustNode(ctx context.Context, aid string) (*TrustGraphN
 	return &node, nil
 }
 
+// StorePeerMapping records the peer ID for an AID, replacing any previous one.
+func (s *LocalStore) StorePeerMapping(ctx context.Context, mapping *PeerMapping) error {
+	defer metrics.ObserveStoreQuery("store_peer_mapping", time.Now())
+	defer s.writing()()
+
+	coll, err := s.PeerMappings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get peer mappings collection: %w", err)
+	}
+
+	data, err := json.Marshal(mapping)
+	if err != nil {
+		return fmt.Errorf("failed to marshal peer mapping: %w", err)
+	}
+
+	doc := anyenc.MustParseJson(string(data))
+	return s.wrote(coll.UpsertOne(ctx, doc))
+}
+
+// GetPeerMapping retrieves the peer mapping for an AID.
+func (s *LocalStore) GetPeerMapping(ctx context.Context, aid string) (*PeerMapping, error) {
+	defer metrics.ObserveStoreQuery("get_peer_mapping", time.Now())
+
+	coll, err := s.PeerMappings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get peer mappings collection: %w", err)
+	}
+
+	doc, err := coll.FindId(ctx, aid)
+	if err != nil {
+		return nil, fmt.Errorf("peer mapping not found: %w", err)
+	}
+
+	var mapping PeerMapping
+	if err := json.Unmarshal([]byte(doc.Value().String()), &mapping); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal peer mapping: %w", err)
+	}
+
+	return &mapping, nil
+}
+
+// ListPeerMappings retrieves all recorded peer mappings.
+func (s *LocalStore) ListPeerMappings(ctx context.Context) ([]*PeerMapping, error) {
+	defer metrics.ObserveStoreQuery("list_peer_mappings", time.Now())
+
+	coll, err := s.PeerMappings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get peer mappings collection: %w", err)
+	}
+
+	iter, err := coll.Find(nil).Iter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query peer mappings: %w", err)
+	}
+	defer iter.Close()
+
+	var mappings []*PeerMapping
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			continue
+		}
+
+		var mapping PeerMapping
+		if err := json.Unmarshal([]byte(doc.Value().String()), &mapping); err != nil {
+			continue
+		}
+		mappings = append(mappings, &mapping)
+	}
+
+	return mappings, nil
+}
+
+// StoreMemberPresence records a member's last activity, replacing any
+// previous record.
+func (s *LocalStore) StoreMemberPresence(ctx context.Context, record *MemberPresenceRecord) error {
+	defer metrics.ObserveStoreQuery("store_member_presence", time.Now())
+	defer s.writing()()
+
+	coll, err := s.MemberPresence(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get member presence collection: %w", err)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal member presence: %w", err)
+	}
+
+	doc := anyenc.MustParseJson(string(data))
+	return s.wrote(coll.UpsertOne(ctx, doc))
+}
+
+// GetMemberPresence retrieves the presence record for an AID.
+func (s *LocalStore) GetMemberPresence(ctx context.Context, aid string) (*MemberPresenceRecord, error) {
+	defer metrics.ObserveStoreQuery("get_member_presence", time.Now())
+
+	coll, err := s.MemberPresence(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member presence collection: %w", err)
+	}
+
+	doc, err := coll.FindId(ctx, aid)
+	if err != nil {
+		return nil, fmt.Errorf("member presence not found: %w", err)
+	}
+
+	var record MemberPresenceRecord
+	if err := json.Unmarshal([]byte(doc.Value().String()), &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal member presence: %w", err)
+	}
+
+	return &record, nil
+}
+
+// ListMemberPresence retrieves all member presence records.
+func (s *LocalStore) ListMemberPresence(ctx context.Context) ([]*MemberPresenceRecord, error) {
+	defer metrics.ObserveStoreQuery("list_member_presence", time.Now())
+
+	coll, err := s.MemberPresence(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member presence collection: %w", err)
+	}
+
+	iter, err := coll.Find(nil).Iter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query member presence: %w", err)
+	}
+	defer iter.Close()
+
+	var records []*MemberPresenceRecord
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			continue
+		}
+
+		var record MemberPresenceRecord
+		if err := json.Unmarshal([]byte(doc.Value().String()), &record); err != nil {
+			continue
+		}
+		records = append(records, &record)
+	}
+
+	return records, nil
+}
+
+// StoreInboxItem records a drained inbox item, replacing any previous copy.
+func (s *LocalStore) StoreInboxItem(ctx context.Context, record *InboxRecord) error {
+	defer metrics.ObserveStoreQuery("store_inbox_item", time.Now())
+	defer s.writing()()
+
+	coll, err := s.Inbox(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get inbox collection: %w", err)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal inbox item: %w", err)
+	}
+
+	doc := anyenc.MustParseJson(string(data))
+	return s.wrote(coll.UpsertOne(ctx, doc))
+}
+
+// GetInboxItem retrieves a drained inbox item by ID.
+func (s *LocalStore) GetInboxItem(ctx context.Context, id string) (*InboxRecord, error) {
+	defer metrics.ObserveStoreQuery("get_inbox_item", time.Now())
+
+	coll, err := s.Inbox(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inbox collection: %w", err)
+	}
+
+	doc, err := coll.FindId(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("inbox item not found: %w", err)
+	}
+
+	var record InboxRecord
+	if err := json.Unmarshal([]byte(doc.Value().String()), &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal inbox item: %w", err)
+	}
+
+	return &record, nil
+}
+
+// ListInboxItems retrieves all drained inbox items.
+func (s *LocalStore) ListInboxItems(ctx context.Context) ([]*InboxRecord, error) {
+	defer metrics.ObserveStoreQuery("list_inbox_items", time.Now())
+
+	coll, err := s.Inbox(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inbox collection: %w", err)
+	}
+
+	iter, err := coll.Find(nil).Iter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query inbox: %w", err)
+	}
+	defer iter.Close()
+
+	var records []*InboxRecord
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			continue
+		}
+
+		var record InboxRecord
+		if err := json.Unmarshal([]byte(doc.Value().String()), &record); err != nil {
+			continue
+		}
+		records = append(records, &record)
+	}
+
+	return records, nil
+}
+
+// StoreInvite records an issued invite, replacing any previous copy.
+func (s *LocalStore) StoreInvite(ctx context.Context, record *InviteRecord) error {
+	defer metrics.ObserveStoreQuery("store_invite", time.Now())
+	defer s.writing()()
+
+	coll, err := s.Invites(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get invites collection: %w", err)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal invite: %w", err)
+	}
+
+	doc := anyenc.MustParseJson(string(data))
+	return s.wrote(coll.UpsertOne(ctx, doc))
+}
+
+// GetInvite retrieves an issued invite by its ACL record ID.
+func (s *LocalStore) GetInvite(ctx context.Context, id string) (*InviteRecord, error) {
+	defer metrics.ObserveStoreQuery("get_invite", time.Now())
+
+	coll, err := s.Invites(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invites collection: %w", err)
+	}
+
+	doc, err := coll.FindId(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("invite not found: %w", err)
+	}
+
+	var record InviteRecord
+	if err := json.Unmarshal([]byte(doc.Value().String()), &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal invite: %w", err)
+	}
+
+	return &record, nil
+}
+
+// ListInvites retrieves all issued invites.
+func (s *LocalStore) ListInvites(ctx context.Context) ([]*InviteRecord, error) {
+	defer metrics.ObserveStoreQuery("list_invites", time.Now())
+
+	coll, err := s.Invites(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invites collection: %w", err)
+	}
+
+	iter, err := coll.Find(nil).Iter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query invites: %w", err)
+	}
+	defer iter.Close()
+
+	var records []*InviteRecord
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			continue
+		}
+
+		var record InviteRecord
+		if err := json.Unmarshal([]byte(doc.Value().String()), &record); err != nil {
+			continue
+		}
+		records = append(records, &record)
+	}
+
+	return records, nil
+}
+
+// StoreEmailDelivery records an invite email delivery, replacing any
+// previous copy.
+func (s *LocalStore) StoreEmailDelivery(ctx context.Context, record *EmailDeliveryRecord) error {
+	defer metrics.ObserveStoreQuery("store_email_delivery", time.Now())
+	defer s.writing()()
+
+	coll, err := s.EmailDeliveries(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get email deliveries collection: %w", err)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal email delivery: %w", err)
+	}
+
+	doc := anyenc.MustParseJson(string(data))
+	return s.wrote(coll.UpsertOne(ctx, doc))
+}
+
+// GetEmailDelivery retrieves an invite email delivery by ID.
+func (s *LocalStore) GetEmailDelivery(ctx context.Context, id string) (*EmailDeliveryRecord, error) {
+	defer metrics.ObserveStoreQuery("get_email_delivery", time.Now())
+
+	coll, err := s.EmailDeliveries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email deliveries collection: %w", err)
+	}
+
+	doc, err := coll.FindId(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("email delivery not found: %w", err)
+	}
+
+	var record EmailDeliveryRecord
+	if err := json.Unmarshal([]byte(doc.Value().String()), &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal email delivery: %w", err)
+	}
+
+	return &record, nil
+}
+
+// ListEmailDeliveries retrieves all invite email deliveries.
+func (s *LocalStore) ListEmailDeliveries(ctx context.Context) ([]*EmailDeliveryRecord, error) {
+	defer metrics.ObserveStoreQuery("list_email_deliveries", time.Now())
+
+	coll, err := s.EmailDeliveries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email deliveries collection: %w", err)
+	}
+
+	iter, err := coll.Find(nil).Iter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query email deliveries: %w", err)
+	}
+	defer iter.Close()
+
+	var records []*EmailDeliveryRecord
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			continue
+		}
+
+		var record EmailDeliveryRecord
+		if err := json.Unmarshal([]byte(doc.Value().String()), &record); err != nil {
+			continue
+		}
+		records = append(records, &record)
+	}
+
+	return records, nil
+}
+
+// StoreTrustScores records a trust score snapshot. TakenAt is truncated to
+// the second so points sort by time as stored.
+func (s *LocalStore) StoreTrustScores(ctx context.Context, points []*TrustScorePoint) error {
+	defer metrics.ObserveStoreQuery("store_trust_scores", time.Now())
+	defer s.writing()()
+
+	coll, err := s.TrustHistory(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get trust history collection: %w", err)
+	}
+
+	for _, point := range points {
+		point.TakenAt = point.TakenAt.UTC().Truncate(time.Second)
+		point.ID = point.AID + "@" + point.TakenAt.Format(time.RFC3339)
+
+		data, err := json.Marshal(point)
+		if err != nil {
+			return fmt.Errorf("failed to marshal trust score: %w", err)
+		}
+		if err := s.wrote(coll.UpsertOne(ctx, anyenc.MustParseJson(string(data)))); err != nil {
+			return fmt.Errorf("failed to store trust score for %s: %w", point.AID, err)
+		}
+	}
+	return nil
+}
+
+// ListTrustScores retrieves an AID's recorded trust scores taken between
+// since and until, oldest first. A zero time leaves that end open.
+func (s *LocalStore) ListTrustScores(ctx context.Context, aid string, since, until time.Time) ([]*TrustScorePoint, error) {
+	defer metrics.ObserveStoreQuery("list_trust_scores", time.Now())
+
+	coll, err := s.TrustHistory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trust history collection: %w", err)
+	}
+
+	query := anyenc.MustParseJson(fmt.Sprintf(`{"aid": %q}`, aid))
+	iter, err := coll.Find(query).Sort("takenAt").Iter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trust history: %w", err)
+	}
+	defer iter.Close()
+
+	var points []*TrustScorePoint
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			continue
+		}
+
+		var point TrustScorePoint
+		if err := json.Unmarshal([]byte(doc.Value().String()), &point); err != nil {
+			continue
+		}
+		if (!since.IsZero() && point.TakenAt.Before(since)) || (!until.IsZero() && point.TakenAt.After(until)) {
+			continue
+		}
+		points = append(points, &point)
+	}
+
+	return points, nil
+}
+
+// LastTrustScoresAt returns when the latest trust score snapshot was taken,
+// or the zero time if none has been.
+func (s *LocalStore) LastTrustScoresAt(ctx context.Context) (time.Time, error) {
+	defer metrics.ObserveStoreQuery("last_trust_scores_at", time.Now())
+
+	coll, err := s.TrustHistory(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get trust history collection: %w", err)
+	}
+
+	iter, err := coll.Find(nil).Sort("-takenAt").Limit(1).Iter(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to query trust history: %w", err)
+	}
+	defer iter.Close()
+
+	if !iter.Next() {
+		return time.Time{}, nil
+	}
+	doc, err := iter.Doc()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get document: %w", err)
+	}
+	return time.Parse(time.RFC3339, doc.Value().GetString("takenAt"))
+}
+
 // SetPreference stores a user preference.
 func (s *LocalStore) SetPreference(ctx context.Context, key string, value any) error {
+	defer metrics.ObserveStoreQuery("set_preference", time.Now())
+	defer s.writing()()
+
 	coll, err := s.UserPreferences(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get preferences collection: %w", err)
@@ -230,11 +881,13 @@ func (s *LocalStore) SetPreference(ctx context.Context, key string, value any) e
 	}
 
 	doc := anyenc.MustParseJson(string(data))
-	return coll.UpsertOne(ctx, doc)
+	return s.wrote(coll.UpsertOne(ctx, doc))
 }
 
 // GetPreference retrieves a user preference.
 func (s *LocalStore) GetPreference(ctx context.Context, key string) (any, error) {
+	defer metrics.ObserveStoreQuery("get_preference", time.Now())
+
 	coll, err := s.UserPreferences(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get preferences collection: %w", err)
@@ -255,17 +908,100 @@ func (s *LocalStore) GetPreference(ctx context.Context, key string) (any, error)
 
 // ClearCache clears all cached data from a specific collection.
 func (s *LocalStore) ClearCache(ctx context.Context, collectionName string) error {
+	defer s.writing()()
+
 	coll, err := s.db.Collection(ctx, collectionName)
 	if err != nil {
 		return fmt.Errorf("failed to get collection: %w", err)
 	}
 
-	return coll.Drop(ctx)
+	err = coll.Drop(ctx)
+	if err == nil {
+		// The collection is recreated on next use, without its indexes
+		err = s.ensureIndexes(ctx, collectionName)
+	}
+	if collectionName == CollectionCredentialsCache {
+		return s.credentialsWrote(s.wrote(err))
+	}
+	return s.wrote(err)
+}
+
+// Collections lists every collection the store manages, in display order.
+func Collections() []string {
+	return []string{
+		CollectionCredentialsCache,
+		CollectionTrustGraphCache,
+		CollectionUserPreferences,
+		CollectionKELCache,
+		CollectionSyncIndex,
+		CollectionSpaces,
+		CollectionPeerMappings,
+		CollectionMemberPresence,
+		CollectionInbox,
+		CollectionInvites,
+		CollectionEmailDeliveries,
+		CollectionTrustHistory,
+		CollectionTrustNodes,
+		CollectionTrustEdges,
+		CollectionAuditLog,
+	}
+}
+
+// StoreStats reports the database size and per-collection usage.
+type StoreStats struct {
+	TotalSizeBytes int               `json:"totalSizeBytes"` // Database file size
+	DataSizeBytes  int               `json:"dataSizeBytes"`  // Excluding free pages
+	FreeSizeBytes  int               `json:"freeSizeBytes"`  // Free pages reused by later writes
+	Collections    []CollectionStats `json:"collections"`
 }
 
-// Stats returns database statistics.
-func (s *LocalStore) Stats(ctx context.Context) (anystore.DBStats, error) {
-	return s.db.Stats(ctx)
+// CollectionStats reports one collection's usage. SizeBytes is the encoded
+// JSON size of its documents, which approximates their share of the data.
+type CollectionStats struct {
+	Name      string `json:"name"`
+	Documents int    `json:"documents"`
+	SizeBytes int    `json:"sizeBytes"`
+}
+
+// Stats returns database statistics with per-collection document counts
+// and sizes.
+func (s *LocalStore) Stats(ctx context.Context) (*StoreStats, error) {
+	defer metrics.ObserveStoreQuery("stats", time.Now())
+
+	dbStats, err := s.db.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database stats: %w", err)
+	}
+	stats := &StoreStats{
+		TotalSizeBytes: dbStats.TotalSizeBytes,
+		DataSizeBytes:  dbStats.DataSizeBytes,
+		FreeSizeBytes:  dbStats.TotalSizeBytes - dbStats.DataSizeBytes,
+		Collections:    make([]CollectionStats, 0, len(Collections())),
+	}
+
+	for _, name := range Collections() {
+		coll, err := s.db.Collection(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get collection %s: %w", name, err)
+		}
+		iter, err := coll.Find(nil).Iter(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query collection %s: %w", name, err)
+		}
+		cs := CollectionStats{Name: name}
+		for iter.Next() {
+			doc, err := iter.Doc()
+			if err != nil {
+				continue
+			}
+			cs.Documents++
+			cs.SizeBytes += len(doc.Value().String())
+		}
+		iter.Close()
+		stats.Collections = append(stats.Collections, cs)
+	}
+
+	return stats, nil
 }
 
 // Flush forces a database flush to disk.
@@ -296,6 +1032,9 @@ func (s *LocalStore) Spaces(ctx context.Context) (anystore.Collection, error) {
 
 // SaveSpaceRecord saves a space record to the local store.
 func (s *LocalStore) SaveSpaceRecord(ctx context.Context, record *SpaceRecord) error {
+	defer metrics.ObserveStoreQuery("save_space_record", time.Now())
+	defer s.writing()()
+
 	coll, err := s.Spaces(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get spaces collection: %w", err)
@@ -307,11 +1046,29 @@ func (s *LocalStore) SaveSpaceRecord(ctx context.Context, record *SpaceRecord) e
 	}
 
 	doc := anyenc.MustParseJson(string(data))
-	return coll.UpsertOne(ctx, doc)
+	return s.wrote(coll.UpsertOne(ctx, doc))
+}
+
+// DeleteSpaceRecord removes a space record. A missing record is not an error.
+func (s *LocalStore) DeleteSpaceRecord(ctx context.Context, spaceID string) error {
+	defer metrics.ObserveStoreQuery("delete_space_record", time.Now())
+	defer s.writing()()
+
+	coll, err := s.Spaces(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get spaces collection: %w", err)
+	}
+
+	if err := s.wrote(coll.DeleteId(ctx, spaceID)); err != nil && err != anystore.ErrDocNotFound {
+		return fmt.Errorf("failed to delete space record: %w", err)
+	}
+	return nil
 }
 
 // GetSpaceByID retrieves a space record by space ID.
 func (s *LocalStore) GetSpaceByID(ctx context.Context, spaceID string) (*SpaceRecord, error) {
+	defer metrics.ObserveStoreQuery("get_space_by_id", time.Now())
+
 	coll, err := s.Spaces(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get spaces collection: %w", err)
@@ -332,6 +1089,8 @@ func (s *LocalStore) GetSpaceByID(ctx context.Context, spaceID string) (*SpaceRe
 
 // GetUserSpaceRecord retrieves a space record by user AID.
 func (s *LocalStore) GetUserSpaceRecord(ctx context.Context, userAID string) (*SpaceRecord, error) {
+	defer metrics.ObserveStoreQuery("get_user_space_record", time.Now())
+
 	coll, err := s.Spaces(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get spaces collection: %w", err)
@@ -365,6 +1124,8 @@ func (s *LocalStore) GetUserSpaceRecord(ctx context.Context, userAID string) (*S
 
 // ListAllSpaceRecords retrieves all space records.
 func (s *LocalStore) ListAllSpaceRecords(ctx context.Context) ([]*SpaceRecord, error) {
+	defer metrics.ObserveStoreQuery("list_all_space_records", time.Now())
+
 	coll, err := s.Spaces(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get spaces collection: %w", err)
@@ -406,36 +1167,15 @@ func (s *LocalStore) UpdateSpaceLastSync(ctx context.Context, spaceID string) er
 
 // GetAllCredentials retrieves all cached credentials from the store.
 func (s *LocalStore) GetAllCredentials(ctx context.Context) ([]*CachedCredential, error) {
-	coll, err := s.CredentialsCache(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get credentials collection: %w", err)
-	}
-
-	iter, err := coll.Find(nil).Iter(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query credentials: %w", err)
-	}
-	defer iter.Close()
-
-	var credentials []*CachedCredential
-	for iter.Next() {
-		doc, err := iter.Doc()
-		if err != nil {
-			continue
-		}
-
-		var cred CachedCredential
-		if err := json.Unmarshal([]byte(doc.Value().String()), &cred); err != nil {
-			continue
-		}
-		credentials = append(credentials, &cred)
-	}
+	defer metrics.ObserveStoreQuery("get_all_credentials", time.Now())
 
-	return credentials, nil
+	return s.findCredentials(ctx, nil)
 }
 
 // CountCredentials returns the count of cached credentials.
 func (s *LocalStore) CountCredentials(ctx context.Context) (int, error) {
+	defer metrics.ObserveStoreQuery("count_credentials", time.Now())
+
 	coll, err := s.CredentialsCache(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get credentials collection: %w", err)
@@ -451,6 +1191,8 @@ func (s *LocalStore) CountCredentials(ctx context.Context) (int, error) {
 
 // CountKELEvents returns the count of cached KEL events.
 func (s *LocalStore) CountKELEvents(ctx context.Context) (int, error) {
+	defer metrics.ObserveStoreQuery("count_kel_events", time.Now())
+
 	coll, err := s.KELCache(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get KEL collection: %w", err)
@@ -466,6 +1208,8 @@ func (s *LocalStore) CountKELEvents(ctx context.Context) (int, error) {
 
 // CountSpaces returns the count of spaces.
 func (s *LocalStore) CountSpaces(ctx context.Context) (int, error) {
+	defer metrics.ObserveStoreQuery("count_spaces", time.Now())
+
 	coll, err := s.Spaces(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get spaces collection: %w", err)
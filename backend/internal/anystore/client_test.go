@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -93,6 +94,65 @@ func TestCredentialsCRUD(t *testing.T) {
 	}
 }
 
+func TestCredentialQueries(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	for _, cred := range []*CachedCredential{
+		{ID: "ESAID1", IssuerAID: "EORG", SubjectAID: "EAID1", SchemaID: "EMembership"},
+		{ID: "ESAID2", IssuerAID: "EORG", SubjectAID: "EAID2", SchemaID: "EMembership"},
+		{ID: "ESAID3", IssuerAID: "EAID2", SubjectAID: "EAID1", SchemaID: "EEndorsement"},
+	} {
+		if err := store.StoreCredential(ctx, cred); err != nil {
+			t.Fatalf("failed to store credential: %v", err)
+		}
+	}
+
+	ids := func(creds []*CachedCredential, err error) string {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("failed to query credentials: %v", err)
+		}
+		var got []string
+		for _, c := range creds {
+			got = append(got, c.ID)
+		}
+		return strings.Join(got, ",")
+	}
+	if got := ids(store.GetCredentialsByIssuer(ctx, "EORG")); got != "ESAID1,ESAID2" {
+		t.Errorf("expected the org's credentials, got %s", got)
+	}
+	if got := ids(store.GetCredentialsBySubject(ctx, "EAID1")); got != "ESAID1,ESAID3" {
+		t.Errorf("expected EAID1's credentials, got %s", got)
+	}
+	if got := ids(store.GetCredentialsBySchema(ctx, "EEndorsement")); got != "ESAID3" {
+		t.Errorf("expected the endorsement, got %s", got)
+	}
+	if got := ids(store.GetCredentialsByIssuer(ctx, "EUNKNOWN")); got != "" {
+		t.Errorf("expected no credentials, got %s", got)
+	}
+
+	// Clearing the cache keeps the lookup indexes
+	if err := store.ClearCache(ctx, CollectionCredentialsCache); err != nil {
+		t.Fatalf("failed to clear cache: %v", err)
+	}
+	coll, err := store.CredentialsCache(ctx)
+	if err != nil {
+		t.Fatalf("failed to get credentials collection: %v", err)
+	}
+	indexed := make(map[string]bool)
+	for _, idx := range coll.GetIndexes() {
+		indexed[strings.Join(idx.Info().Fields, ",")] = true
+	}
+	for _, field := range []string{"issuerAID", "subjectAID", "schemaID"} {
+		if !indexed[field] {
+			t.Errorf("expected an index on %s, got %v", field, indexed)
+		}
+	}
+}
+
 func TestTrustNodeCRUD(t *testing.T) {
 	store := setupTestStore(t)
 	defer store.Close()
@@ -137,6 +197,338 @@ func TestTrustNodeCRUD(t *testing.T) {
 	}
 }
 
+func TestPeerMappingCRUD(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	if _, err := store.GetPeerMapping(ctx, "EAID123456789"); err == nil {
+		t.Error("expected error for unmapped AID")
+	}
+
+	for _, peerID := range []string{"12D3KooWOld", "12D3KooWNew"} {
+		if err := store.StorePeerMapping(ctx, &PeerMapping{
+			AID:       "EAID123456789",
+			PeerID:    peerID,
+			UpdatedAt: time.Now().UTC(),
+		}); err != nil {
+			t.Fatalf("failed to store peer mapping: %v", err)
+		}
+	}
+
+	// The latest mapping replaces the earlier one
+	mapping, err := store.GetPeerMapping(ctx, "EAID123456789")
+	if err != nil {
+		t.Fatalf("failed to get peer mapping: %v", err)
+	}
+	if mapping.PeerID != "12D3KooWNew" {
+		t.Errorf("expected peer ID 12D3KooWNew, got %s", mapping.PeerID)
+	}
+}
+
+func TestMemberPresenceCRUD(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	seen := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, aid := range []string{"EAID1", "EAID2"} {
+		if err := store.StoreMemberPresence(ctx, &MemberPresenceRecord{
+			AID:          aid,
+			LastActiveAt: seen,
+			Source:       "acl",
+		}); err != nil {
+			t.Fatalf("failed to store member presence: %v", err)
+		}
+	}
+
+	record, err := store.GetMemberPresence(ctx, "EAID1")
+	if err != nil {
+		t.Fatalf("failed to get member presence: %v", err)
+	}
+	if !record.LastActiveAt.Equal(seen) || record.Source != "acl" {
+		t.Errorf("unexpected presence record: %+v", record)
+	}
+
+	records, err := store.ListMemberPresence(ctx)
+	if err != nil {
+		t.Fatalf("failed to list member presence: %v", err)
+	}
+	if len(records) != 2 {
+		t.Errorf("expected 2 presence records, got %d", len(records))
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+	seen := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.StoreMemberPresence(ctx, &MemberPresenceRecord{AID: "EAID1", LastActiveAt: seen, Source: "acl"})
+
+	snapshot, err := store.Snapshot(ctx, CollectionMemberPresence, CollectionCredentialsCache)
+	if err != nil {
+		t.Fatalf("failed to take snapshot: %v", err)
+	}
+	if snapshot.Revision != 1 {
+		t.Errorf("expected snapshot at revision 1, got %d", snapshot.Revision)
+	}
+
+	// Writes after the snapshot don't show in it
+	if err := store.StoreMemberPresence(ctx, &MemberPresenceRecord{AID: "EAID2", LastActiveAt: seen, Source: "acl"}); err != nil {
+		t.Fatalf("failed to store member presence: %v", err)
+	}
+	records, err := store.ListMemberPresence(snapshot.Context())
+	if err != nil {
+		t.Fatalf("failed to list member presence in snapshot: %v", err)
+	}
+	if len(records) != 1 {
+		t.Errorf("expected 1 presence record in snapshot, got %d", len(records))
+	}
+	if _, err := store.GetAllCredentials(snapshot.Context()); err != nil {
+		t.Errorf("failed to read credentials in snapshot: %v", err)
+	}
+	if err := snapshot.Close(); err != nil {
+		t.Fatalf("failed to close snapshot: %v", err)
+	}
+
+	if store.Revision() != 2 {
+		t.Errorf("expected revision 2 after two writes, got %d", store.Revision())
+	}
+	records, _ = store.ListMemberPresence(ctx)
+	if len(records) != 2 {
+		t.Errorf("expected 2 presence records after the snapshot, got %d", len(records))
+	}
+
+	// Only credential writes advance the credential revision
+	if store.CredentialRevision() != 0 {
+		t.Errorf("expected credential revision 0 before any credential write, got %d", store.CredentialRevision())
+	}
+	store.StoreCredential(ctx, &CachedCredential{ID: "ESAID1", IssuerAID: "EORG", SubjectAID: "EAID1"})
+	store.ClearCache(ctx, CollectionCredentialsCache)
+	if store.CredentialRevision() != 2 {
+		t.Errorf("expected credential revision 2, got %d", store.CredentialRevision())
+	}
+}
+
+func TestInboxCRUD(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	received := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := store.StoreInboxItem(ctx, &InboxRecord{
+		ID:         "InboxItem-EORG-1",
+		SenderAID:  "EORG",
+		Kind:       "notification",
+		Payload:    []byte(`{"title":"Welcome"}`),
+		CreatedAt:  received.Add(-time.Hour),
+		ReceivedAt: received,
+	}); err != nil {
+		t.Fatalf("failed to store inbox item: %v", err)
+	}
+
+	record, err := store.GetInboxItem(ctx, "InboxItem-EORG-1")
+	if err != nil {
+		t.Fatalf("failed to get inbox item: %v", err)
+	}
+	if record.SenderAID != "EORG" || string(record.Payload) != `{"title":"Welcome"}` {
+		t.Errorf("unexpected inbox record: %+v", record)
+	}
+
+	records, err := store.ListInboxItems(ctx)
+	if err != nil {
+		t.Fatalf("failed to list inbox items: %v", err)
+	}
+	if len(records) != 1 {
+		t.Errorf("expected 1 inbox item, got %d", len(records))
+	}
+}
+
+func TestInvitesCRUD(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := store.StoreInvite(ctx, &InviteRecord{
+		ID:         "invite-record-1",
+		SpaceID:    "space-1",
+		Scope:      "community",
+		Permission: "writer",
+		MaxUses:    1,
+		Status:     "active",
+		CreatedAt:  created,
+		ExpiresAt:  created.Add(24 * time.Hour),
+	}); err != nil {
+		t.Fatalf("failed to store invite: %v", err)
+	}
+
+	record, err := store.GetInvite(ctx, "invite-record-1")
+	if err != nil {
+		t.Fatalf("failed to get invite: %v", err)
+	}
+	if record.SpaceID != "space-1" || record.MaxUses != 1 || !record.ExpiresAt.Equal(created.Add(24*time.Hour)) {
+		t.Errorf("unexpected invite record: %+v", record)
+	}
+
+	record.Uses, record.Status = 1, "used"
+	if err := store.StoreInvite(ctx, record); err != nil {
+		t.Fatalf("failed to update invite: %v", err)
+	}
+	records, err := store.ListInvites(ctx)
+	if err != nil {
+		t.Fatalf("failed to list invites: %v", err)
+	}
+	if len(records) != 1 || records[0].Status != "used" {
+		t.Errorf("expected 1 used invite, got %+v", records)
+	}
+}
+
+func TestEmailDeliveriesCRUD(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	if err := store.StoreEmailDelivery(ctx, &EmailDeliveryRecord{
+		ID:       "delivery-1",
+		InviteID: "invite-record-1",
+		To:       "tama@example.com",
+		Status:   "pending",
+	}); err != nil {
+		t.Fatalf("failed to store email delivery: %v", err)
+	}
+
+	record, err := store.GetEmailDelivery(ctx, "delivery-1")
+	if err != nil {
+		t.Fatalf("failed to get email delivery: %v", err)
+	}
+	record.Attempts, record.Status = 2, "sent"
+	if err := store.StoreEmailDelivery(ctx, record); err != nil {
+		t.Fatalf("failed to update email delivery: %v", err)
+	}
+	records, err := store.ListEmailDeliveries(ctx)
+	if err != nil {
+		t.Fatalf("failed to list email deliveries: %v", err)
+	}
+	if len(records) != 1 || records[0].Status != "sent" || records[0].Attempts != 2 {
+		t.Errorf("expected 1 sent delivery, got %+v", records)
+	}
+}
+
+func TestTrustHistory(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	if last, err := store.LastTrustScoresAt(ctx); err != nil || !last.IsZero() {
+		t.Fatalf("expected no snapshot yet, got %v (%v)", last, err)
+	}
+
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 2; i >= 0; i-- {
+		takenAt := day.Add(time.Duration(i) * 24 * time.Hour)
+		if err := store.StoreTrustScores(ctx, []*TrustScorePoint{
+			{AID: "EAID1", Score: float64(i + 1), Algorithm: "linear", TakenAt: takenAt.Add(300 * time.Millisecond)},
+			{AID: "EAID2", Score: 1, Algorithm: "linear", TakenAt: takenAt},
+		}); err != nil {
+			t.Fatalf("failed to store trust scores: %v", err)
+		}
+	}
+
+	points, err := store.ListTrustScores(ctx, "EAID1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to list trust scores: %v", err)
+	}
+	if len(points) != 3 {
+		t.Fatalf("expected 3 points, got %d", len(points))
+	}
+	for i, point := range points {
+		if point.Score != float64(i+1) || !point.TakenAt.Equal(day.Add(time.Duration(i)*24*time.Hour)) {
+			t.Errorf("point %d out of order or not truncated: %+v", i, point)
+		}
+	}
+
+	points, err = store.ListTrustScores(ctx, "EAID1", day.Add(time.Hour), day.Add(36*time.Hour))
+	if err != nil {
+		t.Fatalf("failed to list trust scores: %v", err)
+	}
+	if len(points) != 1 || points[0].Score != 2 {
+		t.Errorf("expected only the second day's point, got %+v", points)
+	}
+
+	last, err := store.LastTrustScoresAt(ctx)
+	if err != nil {
+		t.Fatalf("failed to get last snapshot: %v", err)
+	}
+	if !last.Equal(day.Add(48 * time.Hour)) {
+		t.Errorf("expected the last snapshot on the third day, got %v", last)
+	}
+}
+
+func TestTrustGraphRecords(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	confidence := 0.5
+	if err := store.StoreGraphNodes(ctx, []*GraphNodeRecord{
+		{AID: "EORG", Role: "Organization", Score: 1},
+		{AID: "EAID1", Role: "Member", Score: 5, Depth: 1},
+		{AID: "EAID2", Role: "Member", Score: 3, Depth: 1},
+	}); err != nil {
+		t.Fatalf("failed to store graph nodes: %v", err)
+	}
+	if err := store.StoreGraphEdges(ctx, []*GraphEdgeRecord{
+		{CredentialID: "ESAID1", From: "EORG", To: "EAID1", Type: "membership"},
+		{CredentialID: "ESAID2", From: "EORG", To: "EAID2", Type: "membership"},
+		{CredentialID: "ESAID3", From: "EAID2", To: "EAID1", Type: "endorsement", Confidence: &confidence},
+	}); err != nil {
+		t.Fatalf("failed to store graph edges: %v", err)
+	}
+
+	top, err := store.TopGraphNodes(ctx, 2)
+	if err != nil {
+		t.Fatalf("failed to get top graph nodes: %v", err)
+	}
+	if len(top) != 2 || top[0].AID != "EAID1" || top[1].AID != "EAID2" {
+		t.Errorf("expected EAID1 then EAID2, got %+v", top)
+	}
+
+	edges, err := store.ListGraphEdgesOf(ctx, "EAID2")
+	if err != nil {
+		t.Fatalf("failed to list graph edges: %v", err)
+	}
+	if len(edges) != 2 {
+		t.Fatalf("expected the edge EAID2 issued and the one it holds, got %d", len(edges))
+	}
+	for _, edge := range edges {
+		if edge.CredentialID == "ESAID3" && (edge.Confidence == nil || *edge.Confidence != 0.5) {
+			t.Errorf("expected the endorsement's confidence to be kept, got %+v", edge)
+		}
+	}
+
+	if err := store.DeleteGraphEdges(ctx, []string{"ESAID3", "EMISSING"}); err != nil {
+		t.Fatalf("failed to delete graph edges: %v", err)
+	}
+	if err := store.DeleteGraphNodes(ctx, []string{"EAID2"}); err != nil {
+		t.Fatalf("failed to delete graph nodes: %v", err)
+	}
+	nodes, _ := store.ListGraphNodes(ctx)
+	edges, _ = store.ListGraphEdges(ctx)
+	if len(nodes) != 2 || len(edges) != 2 {
+		t.Errorf("expected 2 nodes and 2 edges left, got %d and %d", len(nodes), len(edges))
+	}
+}
+
 func TestPreferencesCRUD(t *testing.T) {
 	store := setupTestStore(t)
 	defer store.Close()
@@ -253,6 +645,81 @@ func TestStats(t *testing.T) {
 	if stats.TotalSizeBytes < 0 {
 		t.Error("expected non-negative total size")
 	}
+
+	if err := store.StoreMemberPresence(ctx, &MemberPresenceRecord{AID: "EAID1", LastActiveAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("failed to store member presence: %v", err)
+	}
+	stats, err = store.Stats(ctx)
+	if err != nil {
+		t.Fatalf("failed to get stats: %v", err)
+	}
+	if len(stats.Collections) != len(Collections()) {
+		t.Fatalf("expected stats for %d collections, got %d", len(Collections()), len(stats.Collections))
+	}
+	for _, cs := range stats.Collections {
+		if cs.Name == CollectionMemberPresence && (cs.Documents != 1 || cs.SizeBytes == 0) {
+			t.Errorf("expected one presence document with a size, got %+v", cs)
+		}
+	}
+}
+
+func TestVacuum(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	creds := []*CachedCredential{
+		{ID: "ESAID-expired", CachedAt: now.Add(-48 * time.Hour), ExpiresAt: now.Add(-time.Hour)},
+		{ID: "ESAID-current", CachedAt: now, ExpiresAt: now.Add(time.Hour)},
+		{ID: "ESAID-forever", CachedAt: now.Add(-48 * time.Hour)},
+	}
+	for _, cred := range creds {
+		if err := store.StoreCredential(ctx, cred); err != nil {
+			t.Fatalf("failed to store credential: %v", err)
+		}
+	}
+	for id, received := range map[string]time.Time{
+		"InboxItem-old": now.Add(-60 * 24 * time.Hour),
+		"InboxItem-new": now,
+	} {
+		if err := store.StoreInboxItem(ctx, &InboxRecord{ID: id, Kind: "message", Payload: []byte(`{}`), ReceivedAt: received}); err != nil {
+			t.Fatalf("failed to store inbox item: %v", err)
+		}
+	}
+	if err := store.StoreMemberPresence(ctx, &MemberPresenceRecord{AID: "EAID-gone", LastActiveAt: now.Add(-400 * 24 * time.Hour)}); err != nil {
+		t.Fatalf("failed to store member presence: %v", err)
+	}
+	if err := store.StoreTrustScores(ctx, []*TrustScorePoint{
+		{AID: "EAID1", Score: 1, TakenAt: now.Add(-400 * 24 * time.Hour)},
+		{AID: "EAID1", Score: 2, TakenAt: now},
+	}); err != nil {
+		t.Fatalf("failed to store trust scores: %v", err)
+	}
+
+	result, err := store.Vacuum(ctx, VacuumOptions{InboxRetention: 30 * 24 * time.Hour, TrustHistoryRetention: 365 * 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("vacuum failed: %v", err)
+	}
+	if result.Removed[CollectionCredentialsCache] != 1 || result.Removed[CollectionInbox] != 1 || result.Removed[CollectionTrustHistory] != 1 {
+		t.Errorf("expected one expired credential, one old inbox item and one old trust score removed, got %v", result.Removed)
+	}
+	if _, ok := result.Removed[CollectionMemberPresence]; ok {
+		t.Error("presence should be kept without a retention")
+	}
+
+	if _, err := store.GetCredential(ctx, "ESAID-expired"); err == nil {
+		t.Error("expired credential should be pruned")
+	}
+	for _, id := range []string{"ESAID-current", "ESAID-forever"} {
+		if _, err := store.GetCredential(ctx, id); err != nil {
+			t.Errorf("credential %s should be kept: %v", id, err)
+		}
+	}
+	if _, err := store.GetInboxItem(ctx, "InboxItem-new"); err != nil {
+		t.Errorf("recent inbox item should be kept: %v", err)
+	}
 }
 
 func TestDefaultConfig(t *testing.T) {
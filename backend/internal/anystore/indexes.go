@@ -0,0 +1,97 @@
+package anystore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-store/anyenc"
+
+	"github.com/matou-dao/backend/internal/metrics"
+)
+
+// collectionIndexes lists the indexes each collection is queried by. They
+// are ensured when the store opens and when a collection is cleared.
+var collectionIndexes = map[string][]anystore.IndexInfo{
+	CollectionCredentialsCache: {
+		{Fields: []string{"issuerAID"}},
+		{Fields: []string{"subjectAID"}},
+		{Fields: []string{"schemaID"}},
+	},
+	CollectionTrustEdges: {
+		{Fields: []string{"from"}},
+		{Fields: []string{"to"}},
+	},
+	CollectionAuditLog: {
+		{Fields: []string{"action"}},
+		{Fields: []string{"actorAid"}},
+		{Fields: []string{"target"}},
+	},
+}
+
+// ensureIndexes creates a collection's indexes that don't exist yet.
+func (s *LocalStore) ensureIndexes(ctx context.Context, collectionName string) error {
+	indexes, ok := collectionIndexes[collectionName]
+	if !ok {
+		return nil
+	}
+	coll, err := s.db.Collection(ctx, collectionName)
+	if err != nil {
+		return fmt.Errorf("failed to get collection %s: %w", collectionName, err)
+	}
+	if err := coll.EnsureIndex(ctx, indexes...); err != nil {
+		return fmt.Errorf("failed to index collection %s: %w", collectionName, err)
+	}
+	return nil
+}
+
+// GetCredentialsByIssuer retrieves the cached credentials an AID issued.
+func (s *LocalStore) GetCredentialsByIssuer(ctx context.Context, issuerAID string) ([]*CachedCredential, error) {
+	defer metrics.ObserveStoreQuery("get_credentials_by_issuer", time.Now())
+	return s.findCredentials(ctx, anyenc.MustParseJson(fmt.Sprintf(`{"issuerAID": %q}`, issuerAID)))
+}
+
+// GetCredentialsBySubject retrieves the cached credentials issued to an AID.
+func (s *LocalStore) GetCredentialsBySubject(ctx context.Context, subjectAID string) ([]*CachedCredential, error) {
+	defer metrics.ObserveStoreQuery("get_credentials_by_subject", time.Now())
+	return s.findCredentials(ctx, anyenc.MustParseJson(fmt.Sprintf(`{"subjectAID": %q}`, subjectAID)))
+}
+
+// GetCredentialsBySchema retrieves the cached credentials of a schema.
+func (s *LocalStore) GetCredentialsBySchema(ctx context.Context, schemaID string) ([]*CachedCredential, error) {
+	defer metrics.ObserveStoreQuery("get_credentials_by_schema", time.Now())
+	return s.findCredentials(ctx, anyenc.MustParseJson(fmt.Sprintf(`{"schemaID": %q}`, schemaID)))
+}
+
+// findCredentials retrieves the cached credentials matching a filter, or
+// every one for a nil filter.
+func (s *LocalStore) findCredentials(ctx context.Context, filter any) ([]*CachedCredential, error) {
+	coll, err := s.CredentialsCache(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credentials collection: %w", err)
+	}
+
+	iter, err := coll.Find(filter).Iter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query credentials: %w", err)
+	}
+	defer iter.Close()
+
+	var credentials []*CachedCredential
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			continue
+		}
+
+		var cred CachedCredential
+		if err := json.Unmarshal([]byte(doc.Value().String()), &cred); err != nil {
+			continue
+		}
+		credentials = append(credentials, &cred)
+	}
+
+	return credentials, nil
+}
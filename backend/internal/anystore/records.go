@@ -0,0 +1,48 @@
+package anystore
+
+import "context"
+
+// RecordStore holds the records this backend is the source of truth for:
+// space records, peer mappings, member presence, inbox items, invites,
+// invite email deliveries and preferences. LocalStore keeps them in the anystore file; the sqlstore
+// package keeps them in a managed database for multi-user org deployments.
+//
+// Caches rebuilt from KERIA and the community spaces (credentials, trust
+// graph, KELs, sync index) are not part of it and always stay in the
+// anystore file.
+type RecordStore interface {
+	SaveSpaceRecord(ctx context.Context, record *SpaceRecord) error
+	DeleteSpaceRecord(ctx context.Context, spaceID string) error
+	GetSpaceByID(ctx context.Context, spaceID string) (*SpaceRecord, error)
+	GetUserSpaceRecord(ctx context.Context, userAID string) (*SpaceRecord, error)
+	ListAllSpaceRecords(ctx context.Context) ([]*SpaceRecord, error)
+	UpdateSpaceLastSync(ctx context.Context, spaceID string) error
+
+	StorePeerMapping(ctx context.Context, mapping *PeerMapping) error
+	GetPeerMapping(ctx context.Context, aid string) (*PeerMapping, error)
+	ListPeerMappings(ctx context.Context) ([]*PeerMapping, error)
+
+	StoreMemberPresence(ctx context.Context, record *MemberPresenceRecord) error
+	GetMemberPresence(ctx context.Context, aid string) (*MemberPresenceRecord, error)
+	ListMemberPresence(ctx context.Context) ([]*MemberPresenceRecord, error)
+
+	StoreInboxItem(ctx context.Context, record *InboxRecord) error
+	GetInboxItem(ctx context.Context, id string) (*InboxRecord, error)
+	ListInboxItems(ctx context.Context) ([]*InboxRecord, error)
+
+	StoreInvite(ctx context.Context, record *InviteRecord) error
+	GetInvite(ctx context.Context, id string) (*InviteRecord, error)
+	ListInvites(ctx context.Context) ([]*InviteRecord, error)
+
+	StoreEmailDelivery(ctx context.Context, record *EmailDeliveryRecord) error
+	GetEmailDelivery(ctx context.Context, id string) (*EmailDeliveryRecord, error)
+	ListEmailDeliveries(ctx context.Context) ([]*EmailDeliveryRecord, error)
+
+	SetPreference(ctx context.Context, key string, value any) error
+	GetPreference(ctx context.Context, key string) (any, error)
+
+	Close() error
+}
+
+// Ensure LocalStore implements RecordStore
+var _ RecordStore = (*LocalStore)(nil)
@@ -0,0 +1,93 @@
+package anystore
+
+import (
+	"context"
+	"fmt"
+
+	anystore "github.com/anyproto/any-store"
+)
+
+// Snapshot is a read-only view of the store at one point in time. Queries
+// run with its Context all see the same data, whatever is written
+// meanwhile. Close it once done, as it holds a read connection.
+type Snapshot struct {
+	tx anystore.ReadTx
+
+	// Revision is the store's revision the snapshot shows
+	Revision uint64
+}
+
+// Snapshot starts a read-only snapshot of the store. Collections can't be
+// created inside a snapshot, so the ones the caller will read are created
+// first if missing.
+func (s *LocalStore) Snapshot(ctx context.Context, collections ...string) (*Snapshot, error) {
+	for _, name := range collections {
+		if _, err := s.db.Collection(ctx, name); err != nil {
+			return nil, fmt.Errorf("failed to get collection %s: %w", name, err)
+		}
+	}
+
+	// Hold off writes until the snapshot has read once, which fixes the
+	// data it sees, so its revision is exact
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx, err := s.db.ReadTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start read snapshot: %w", err)
+	}
+	if _, err := s.db.GetCollectionNames(tx.Context()); err != nil {
+		tx.Commit()
+		return nil, fmt.Errorf("failed to start read snapshot: %w", err)
+	}
+	return &Snapshot{tx: tx, Revision: s.revision.Load()}, nil
+}
+
+// Context returns the context to run the snapshot's queries with
+func (sn *Snapshot) Context() context.Context {
+	return sn.tx.Context()
+}
+
+// Close ends the snapshot
+func (sn *Snapshot) Close() error {
+	return sn.tx.Commit()
+}
+
+// Revision counts the writes made through the store's methods since it was
+// opened. Snapshots at the same revision see the same data.
+func (s *LocalStore) Revision() uint64 {
+	return s.revision.Load()
+}
+
+// CredentialRevision counts the writes to the credentials cache since the
+// store was opened, so what is derived from credentials alone, like the
+// trust graph, can tell when it is out of date.
+func (s *LocalStore) CredentialRevision() uint64 {
+	return s.credentialRevision.Load()
+}
+
+// writing holds off new snapshots while a write and its revision bump are
+// in progress. Writes run concurrently with each other, so they share the
+// lock and snapshots take it exclusively:
+//
+//	defer s.writing()()
+func (s *LocalStore) writing() func() {
+	s.mu.RLock()
+	return s.mu.RUnlock
+}
+
+// wrote advances the revision after a successful write
+func (s *LocalStore) wrote(err error) error {
+	if err == nil {
+		s.revision.Add(1)
+	}
+	return err
+}
+
+// credentialsWrote also advances the credential revision after a
+// successful write to the credentials cache
+func (s *LocalStore) credentialsWrote(err error) error {
+	if err == nil {
+		s.credentialRevision.Add(1)
+	}
+	return err
+}
@@ -11,11 +11,11 @@ import (
 
 // SpaceStoreAdapter adapts LocalStore to implement anysync.SpaceStore interface
 type SpaceStoreAdapter struct {
-	store *LocalStore
+	store RecordStore
 }
 
 // NewSpaceStoreAdapter creates a new adapter for the LocalStore
-func NewSpaceStoreAdapter(store *LocalStore) *SpaceStoreAdapter {
+func NewSpaceStoreAdapter(store RecordStore) *SpaceStoreAdapter {
 	return &SpaceStoreAdapter{store: store}
 }
 
@@ -0,0 +1,218 @@
+package anystore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+	"github.com/anyproto/any-store/anyenc"
+
+	"github.com/matou-dao/backend/internal/metrics"
+)
+
+// GraphNodeRecord is a trust graph node as materialized from the
+// credentials cache, with the score it had then.
+type GraphNodeRecord struct {
+	AID             string         `json:"id"`                   // AID (used as document ID)
+	Alias           string         `json:"alias,omitempty"`      // Display name
+	Role            string         `json:"role"`                 // Role in the community
+	JoinedAt        time.Time      `json:"joinedAt"`             // When the AID joined
+	CredentialCount int            `json:"credentialCount"`      // Credentials naming the AID
+	Attributes      map[string]any `json:"attributes,omitempty"` // Custom role attributes
+	Score           float64        `json:"score"`                // Trust score when materialized
+	Depth           int            `json:"graphDepth"`           // Distance from the org
+}
+
+// GraphEdgeRecord is a trust graph edge as materialized from the
+// credentials cache.
+type GraphEdgeRecord struct {
+	CredentialID  string    `json:"id"`                   // Credential SAID (used as document ID)
+	From          string    `json:"from"`                 // Issuer AID
+	To            string    `json:"to"`                   // Subject AID
+	Type          string    `json:"type"`                 // Edge type
+	Bidirectional bool      `json:"bidirectional"`        // Whether the subject issued one back
+	CreatedAt     time.Time `json:"createdAt"`            // When the credential was issued
+	Confidence    *float64  `json:"confidence,omitempty"` // Endorser's confidence
+}
+
+// StoreGraphNodes upserts materialized trust graph nodes.
+func (s *LocalStore) StoreGraphNodes(ctx context.Context, nodes []*GraphNodeRecord) error {
+	defer metrics.ObserveStoreQuery("store_graph_nodes", time.Now())
+	defer s.writing()()
+
+	coll, err := s.TrustNodes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get trust nodes collection: %w", err)
+	}
+
+	for _, node := range nodes {
+		data, err := json.Marshal(node)
+		if err != nil {
+			return fmt.Errorf("failed to marshal trust node: %w", err)
+		}
+		if err := s.wrote(coll.UpsertOne(ctx, anyenc.MustParseJson(string(data)))); err != nil {
+			return fmt.Errorf("failed to store trust node %s: %w", node.AID, err)
+		}
+	}
+	return nil
+}
+
+// StoreGraphEdges upserts materialized trust graph edges.
+func (s *LocalStore) StoreGraphEdges(ctx context.Context, edges []*GraphEdgeRecord) error {
+	defer metrics.ObserveStoreQuery("store_graph_edges", time.Now())
+	defer s.writing()()
+
+	coll, err := s.TrustEdges(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get trust edges collection: %w", err)
+	}
+
+	for _, edge := range edges {
+		data, err := json.Marshal(edge)
+		if err != nil {
+			return fmt.Errorf("failed to marshal trust edge: %w", err)
+		}
+		if err := s.wrote(coll.UpsertOne(ctx, anyenc.MustParseJson(string(data)))); err != nil {
+			return fmt.Errorf("failed to store trust edge %s: %w", edge.CredentialID, err)
+		}
+	}
+	return nil
+}
+
+// DeleteGraphNodes removes materialized trust graph nodes by AID.
+func (s *LocalStore) DeleteGraphNodes(ctx context.Context, aids []string) error {
+	defer metrics.ObserveStoreQuery("delete_graph_nodes", time.Now())
+	return s.deleteIDs(ctx, CollectionTrustNodes, aids)
+}
+
+// DeleteGraphEdges removes materialized trust graph edges by credential SAID.
+func (s *LocalStore) DeleteGraphEdges(ctx context.Context, ids []string) error {
+	defer metrics.ObserveStoreQuery("delete_graph_edges", time.Now())
+	return s.deleteIDs(ctx, CollectionTrustEdges, ids)
+}
+
+// deleteIDs removes documents from a collection, ignoring ones already gone
+func (s *LocalStore) deleteIDs(ctx context.Context, collection string, ids []string) error {
+	defer s.writing()()
+
+	coll, err := s.db.Collection(ctx, collection)
+	if err != nil {
+		return fmt.Errorf("failed to get collection %s: %w", collection, err)
+	}
+	for _, id := range ids {
+		if err := s.wrote(coll.DeleteId(ctx, id)); err != nil && err != anystore.ErrDocNotFound {
+			return fmt.Errorf("failed to delete %s from %s: %w", id, collection, err)
+		}
+	}
+	return nil
+}
+
+// ListGraphNodes retrieves every materialized trust graph node.
+func (s *LocalStore) ListGraphNodes(ctx context.Context) ([]*GraphNodeRecord, error) {
+	defer metrics.ObserveStoreQuery("list_graph_nodes", time.Now())
+
+	coll, err := s.TrustNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trust nodes collection: %w", err)
+	}
+	return findGraphNodes(ctx, coll.Find(nil))
+}
+
+// TopGraphNodes retrieves the limit materialized trust graph nodes with the
+// highest scores, highest first.
+func (s *LocalStore) TopGraphNodes(ctx context.Context, limit int) ([]*GraphNodeRecord, error) {
+	defer metrics.ObserveStoreQuery("top_graph_nodes", time.Now())
+
+	coll, err := s.TrustNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trust nodes collection: %w", err)
+	}
+	return findGraphNodes(ctx, coll.Find(nil).Sort("-score", "id").Limit(uint(limit)))
+}
+
+func findGraphNodes(ctx context.Context, query anystore.Query) ([]*GraphNodeRecord, error) {
+	iter, err := query.Iter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trust nodes: %w", err)
+	}
+	defer iter.Close()
+
+	var nodes []*GraphNodeRecord
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			continue
+		}
+
+		var node GraphNodeRecord
+		if err := json.Unmarshal([]byte(doc.Value().String()), &node); err != nil {
+			continue
+		}
+		nodes = append(nodes, &node)
+	}
+
+	return nodes, nil
+}
+
+// ListGraphEdges retrieves every materialized trust graph edge.
+func (s *LocalStore) ListGraphEdges(ctx context.Context) ([]*GraphEdgeRecord, error) {
+	defer metrics.ObserveStoreQuery("list_graph_edges", time.Now())
+
+	coll, err := s.TrustEdges(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trust edges collection: %w", err)
+	}
+	return findGraphEdges(ctx, coll.Find(nil))
+}
+
+// ListGraphEdgesOf retrieves the materialized trust graph edges an AID
+// issued or holds.
+func (s *LocalStore) ListGraphEdgesOf(ctx context.Context, aid string) ([]*GraphEdgeRecord, error) {
+	defer metrics.ObserveStoreQuery("list_graph_edges_of", time.Now())
+
+	coll, err := s.TrustEdges(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trust edges collection: %w", err)
+	}
+
+	issued, err := findGraphEdges(ctx, coll.Find(anyenc.MustParseJson(fmt.Sprintf(`{"from": %q}`, aid))))
+	if err != nil {
+		return nil, err
+	}
+	held, err := findGraphEdges(ctx, coll.Find(anyenc.MustParseJson(fmt.Sprintf(`{"to": %q}`, aid))))
+	if err != nil {
+		return nil, err
+	}
+	for _, edge := range held {
+		if edge.From != aid {
+			issued = append(issued, edge)
+		}
+	}
+	return issued, nil
+}
+
+func findGraphEdges(ctx context.Context, query anystore.Query) ([]*GraphEdgeRecord, error) {
+	iter, err := query.Iter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trust edges: %w", err)
+	}
+	defer iter.Close()
+
+	var edges []*GraphEdgeRecord
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			continue
+		}
+
+		var edge GraphEdgeRecord
+		if err := json.Unmarshal([]byte(doc.Value().String()), &edge); err != nil {
+			continue
+		}
+		edges = append(edges, &edge)
+	}
+
+	return edges, nil
+}
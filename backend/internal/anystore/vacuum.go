@@ -0,0 +1,138 @@
+package anystore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	anystore "github.com/anyproto/any-store"
+
+	"github.com/matou-dao/backend/internal/metrics"
+)
+
+// VacuumOptions sets how long prunable data is kept. A zero retention keeps
+// that data forever. Expired credential cache entries are always pruned.
+type VacuumOptions struct {
+	TrustCacheRetention   time.Duration // Trust graph nodes, by cachedAt
+	InboxRetention        time.Duration // Drained inbox items, by receivedAt
+	PresenceRetention     time.Duration // Presence of inactive members, by lastActiveAt
+	TrustHistoryRetention time.Duration // Trust score history, by takenAt
+}
+
+// VacuumResult reports what a vacuum removed and how the database changed.
+type VacuumResult struct {
+	Removed     map[string]int `json:"removed"` // Documents removed by collection
+	SizeBefore  int            `json:"sizeBeforeBytes"`
+	SizeAfter   int            `json:"sizeAfterBytes"`
+	FreeAfter   int            `json:"freeAfterBytes"`
+	StartedAt   time.Time      `json:"startedAt"`
+	CompletedAt time.Time      `json:"completedAt"`
+}
+
+// pruneRule deletes documents of a collection whose timestamp field is set
+// and older than the cutoff
+type pruneRule struct {
+	collection string
+	field      string
+	cutoff     time.Time
+}
+
+// Vacuum prunes expired caches, old inbox and presence records and old
+// trust score history, then checkpoints the write-ahead log so it stops
+// growing. any-store doesn't expose SQLite's VACUUM, so the database file
+// keeps its size; the freed pages are reused by later writes instead of
+// growing the file.
+func (s *LocalStore) Vacuum(ctx context.Context, opts VacuumOptions) (*VacuumResult, error) {
+	defer metrics.ObserveStoreQuery("vacuum", time.Now())
+
+	result := &VacuumResult{
+		Removed:   make(map[string]int),
+		StartedAt: time.Now().UTC(),
+	}
+	before, err := s.db.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database stats: %w", err)
+	}
+	result.SizeBefore = before.TotalSizeBytes
+
+	now := time.Now().UTC()
+	rules := []pruneRule{
+		{CollectionCredentialsCache, "expiresAt", now},
+	}
+	if opts.TrustCacheRetention > 0 {
+		rules = append(rules, pruneRule{CollectionTrustGraphCache, "cachedAt", now.Add(-opts.TrustCacheRetention)})
+	}
+	if opts.InboxRetention > 0 {
+		rules = append(rules, pruneRule{CollectionInbox, "receivedAt", now.Add(-opts.InboxRetention)})
+	}
+	if opts.PresenceRetention > 0 {
+		rules = append(rules, pruneRule{CollectionMemberPresence, "lastActiveAt", now.Add(-opts.PresenceRetention)})
+	}
+	if opts.TrustHistoryRetention > 0 {
+		rules = append(rules, pruneRule{CollectionTrustHistory, "takenAt", now.Add(-opts.TrustHistoryRetention)})
+	}
+
+	for _, rule := range rules {
+		removed, err := s.prune(ctx, rule)
+		if err != nil {
+			return nil, err
+		}
+		if removed > 0 {
+			result.Removed[rule.collection] = removed
+		}
+	}
+
+	if err := s.db.Flush(ctx, 0, anystore.FlushModeCheckpointRestart); err != nil {
+		return nil, fmt.Errorf("failed to checkpoint database: %w", err)
+	}
+
+	after, err := s.db.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database stats: %w", err)
+	}
+	result.SizeAfter = after.TotalSizeBytes
+	result.FreeAfter = after.TotalSizeBytes - after.DataSizeBytes
+	result.CompletedAt = time.Now().UTC()
+	return result, nil
+}
+
+// prune deletes the documents matched by rule, returning how many
+func (s *LocalStore) prune(ctx context.Context, rule pruneRule) (int, error) {
+	defer s.writing()()
+
+	coll, err := s.db.Collection(ctx, rule.collection)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get collection %s: %w", rule.collection, err)
+	}
+
+	iter, err := coll.Find(nil).Iter(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query collection %s: %w", rule.collection, err)
+	}
+	var ids []string
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339Nano, doc.Value().GetString(rule.field))
+		if err != nil || ts.IsZero() || !ts.Before(rule.cutoff) {
+			continue
+		}
+		ids = append(ids, doc.Value().GetString("id"))
+	}
+	iter.Close()
+
+	removed := 0
+	for _, id := range ids {
+		err := s.wrote(coll.DeleteId(ctx, id))
+		if rule.collection == CollectionCredentialsCache {
+			err = s.credentialsWrote(err)
+		}
+		if err != nil {
+			return removed, fmt.Errorf("failed to delete %s from %s: %w", id, rule.collection, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -1,15 +1,23 @@
 // Package anysync provides any-sync integration for MATOU.
 // acl.go implements ACL management using the any-sync SDK's AclRecordBuilder
-// for cryptographic invite codes, join-without-approval, and permission checks.
+// for cryptographic invite codes, joins (with or without approval), and
+// permission checks.
 // It also provides application-layer policy helpers for KERI credential gating.
 package anysync
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/anyproto/any-sync/commonspace"
+	"github.com/anyproto/any-sync/commonspace/object/acl/aclrecordproto"
 	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+	"github.com/anyproto/any-sync/consensus/consensusproto"
 	"github.com/anyproto/any-sync/util/crypto"
+
+	"github.com/matou-dao/backend/internal/metrics"
 )
 
 // =============================================================================
@@ -35,10 +43,47 @@ func NewMatouACLManager(client AnySyncClient, keyManager *PeerKeyManager) *Matou
 // It encrypts the space's read key with the invite public key and returns
 // the invite private key, which should be shared out-of-band (e.g. as a
 // base58-encoded invite code).
-func (m *MatouACLManager) CreateOpenInvite(ctx context.Context, spaceID string, permissions list.AclPermissions) (crypto.PrivKey, error) {
+func (m *MatouACLManager) CreateOpenInvite(ctx context.Context, spaceID string, permissions list.AclPermissions) (inviteKey crypto.PrivKey, err error) {
+	defer metrics.ObserveSpaceOperation("create_invite", time.Now(), &err)
+
+	_, result, err := m.createInvite(ctx, spaceID, permissions)
+	if err != nil {
+		return nil, err
+	}
+	return result.InviteKey, nil
+}
+
+// CreateInvite creates an "anyone can join" invite like CreateOpenInvite,
+// and also returns the ID of its ACL record, which joins made with the
+// invite reference and which RevokeInvite takes.
+func (m *MatouACLManager) CreateInvite(ctx context.Context, spaceID string, permissions list.AclPermissions) (inviteID string, inviteKey crypto.PrivKey, err error) {
+	defer metrics.ObserveSpaceOperation("create_invite", time.Now(), &err)
+
+	space, result, err := m.createInvite(ctx, spaceID, permissions)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// The builder doesn't return the record ID, so find the invite by its
+	// public key now that the record has been applied.
+	acl := space.Acl()
+	acl.RLock()
+	defer acl.RUnlock()
+	if state := acl.AclState(); state != nil {
+		invitePub := result.InviteKey.GetPublic()
+		for _, invite := range state.Invites() {
+			if invite.Key != nil && invite.Key.Equals(invitePub) {
+				return invite.Id, result.InviteKey, nil
+			}
+		}
+	}
+	return "", nil, fmt.Errorf("invite record not found in space %s after adding it", spaceID)
+}
+
+func (m *MatouACLManager) createInvite(ctx context.Context, spaceID string, permissions list.AclPermissions) (commonspace.Space, list.InviteResult, error) {
 	space, err := m.client.GetSpace(ctx, spaceID)
 	if err != nil {
-		return nil, fmt.Errorf("getting space %s: %w", spaceID, err)
+		return nil, list.InviteResult{}, fmt.Errorf("getting space %s: %w", spaceID, err)
 	}
 
 	// Build the invite record while holding the ACL lock.
@@ -48,23 +93,68 @@ func (m *MatouACLManager) CreateOpenInvite(ctx context.Context, spaceID string,
 	result, err := builder.BuildInviteAnyone(permissions)
 	acl.Unlock()
 	if err != nil {
-		return nil, fmt.Errorf("building invite: %w", err)
+		return nil, list.InviteResult{}, fmt.Errorf("building invite: %w", err)
 	}
 
 	// Submit the invite record to the network (without the ACL lock —
 	// AddRecord internally re-acquires it after the network round-trip).
 	aclClient := space.AclClient()
 	if err := aclClient.AddRecord(ctx, result.InviteRec); err != nil {
-		return nil, fmt.Errorf("adding invite record: %w", err)
+		return nil, list.InviteResult{}, fmt.Errorf("adding invite record: %w", err)
 	}
 
-	return result.InviteKey, nil
+	return space, result, nil
+}
+
+// RevokeInvite revokes an invite by the ID of its ACL record, so its key
+// can no longer be used to join. Revoking an invite that no longer exists
+// is not an error. The caller must be an admin or owner of the space.
+func (m *MatouACLManager) RevokeInvite(ctx context.Context, spaceID, inviteID string) (err error) {
+	defer metrics.ObserveSpaceOperation("revoke_invite", time.Now(), &err)
+
+	space, err := m.client.GetSpace(ctx, spaceID)
+	if err != nil {
+		return fmt.Errorf("getting space %s: %w", spaceID, err)
+	}
+	if err := space.AclClient().RevokeInvite(ctx, inviteID); err != nil && !errors.Is(err, list.ErrNoSuchInvite) {
+		return fmt.Errorf("revoking invite %s: %w", inviteID, err)
+	}
+	return nil
+}
+
+// InviteRedemptions counts the joins made with each invite in a space,
+// keyed by invite record ID. Revoked invites keep their count.
+func (m *MatouACLManager) InviteRedemptions(ctx context.Context, spaceID string) (map[string]int, error) {
+	space, err := m.client.GetSpace(ctx, spaceID)
+	if err != nil {
+		return nil, fmt.Errorf("getting space %s: %w", spaceID, err)
+	}
+
+	acl := space.Acl()
+	acl.RLock()
+	defer acl.RUnlock()
+
+	redemptions := make(map[string]int)
+	for _, record := range acl.Records() {
+		data, ok := record.Model.(*aclrecordproto.AclData)
+		if !ok {
+			continue
+		}
+		for _, content := range data.GetAclContent() {
+			if join := content.GetInviteJoin(); join != nil {
+				redemptions[join.GetInviteRecordId()]++
+			}
+		}
+	}
+	return redemptions, nil
 }
 
 // JoinWithInvite joins a space using an invite key obtained out-of-band.
 // The invite key is used to decrypt the space's read key from the invite
 // record, then re-encrypt it with the joiner's own public key.
-func (m *MatouACLManager) JoinWithInvite(ctx context.Context, spaceID string, inviteKey crypto.PrivKey, metadata []byte) error {
+func (m *MatouACLManager) JoinWithInvite(ctx context.Context, spaceID string, inviteKey crypto.PrivKey, metadata []byte) (err error) {
+	defer metrics.ObserveSpaceOperation("join_space", time.Now(), &err)
+
 	space, err := m.client.GetSpace(ctx, spaceID)
 	if err != nil {
 		return fmt.Errorf("getting space %s: %w", spaceID, err)
@@ -88,6 +178,144 @@ func (m *MatouACLManager) JoinWithInvite(ctx context.Context, spaceID string, in
 	return aclClient.AddRecord(ctx, joinRec)
 }
 
+// RequestJoin joins a space with an invite key obtained out-of-band. The
+// invite's type is the space's approval policy: "anyone can join" invites
+// admit the caller immediately, while request-to-join invites submit a join
+// request for a space admin to approve. Returns true while approval is
+// pending. Nothing is submitted if the caller is already a member or has a
+// request pending, so retries are safe.
+func (m *MatouACLManager) RequestJoin(ctx context.Context, spaceID string, inviteKey crypto.PrivKey, metadata []byte) (pending bool, err error) {
+	defer metrics.ObserveSpaceOperation("request_join", time.Now(), &err)
+
+	space, err := m.client.GetSpace(ctx, spaceID)
+	if err != nil {
+		return false, fmt.Errorf("getting space %s: %w", spaceID, err)
+	}
+	identity := m.client.GetSigningKey().GetPublic()
+
+	acl := space.Acl()
+	acl.Lock()
+	state := acl.AclState()
+	if state == nil {
+		acl.Unlock()
+		return false, fmt.Errorf("ACL state not available for space %s", spaceID)
+	}
+	if !state.Permissions(identity).NoPermissions() {
+		acl.Unlock()
+		return false, nil
+	}
+	if _, err := state.JoinRecord(identity, false); err == nil {
+		acl.Unlock()
+		return true, nil
+	}
+
+	var (
+		found   bool
+		joinRec *consensusproto.RawRecord
+	)
+	for _, invite := range state.Invites() {
+		if !invite.Key.Equals(inviteKey.GetPublic()) {
+			continue
+		}
+		found = true
+		builder := acl.RecordBuilder()
+		if invite.Type == aclrecordproto.AclInviteType_AnyoneCanJoin {
+			joinRec, err = builder.BuildInviteJoinWithoutApprove(list.InviteJoinPayload{
+				InviteKey: inviteKey,
+				Metadata:  metadata,
+			})
+		} else {
+			pending = true
+			joinRec, err = builder.BuildRequestJoin(list.RequestJoinPayload{
+				InviteKey: inviteKey,
+				Metadata:  metadata,
+			})
+		}
+		break
+	}
+	acl.Unlock()
+	if !found {
+		return false, fmt.Errorf("invite not found in space %s ACL", spaceID)
+	}
+	if err != nil {
+		return false, fmt.Errorf("building join record: %w", err)
+	}
+
+	// Submit to the network without the ACL lock.
+	if err := space.AclClient().AddRecord(ctx, joinRec); err != nil {
+		return false, fmt.Errorf("adding join record: %w", err)
+	}
+	return pending, nil
+}
+
+// AwaitPermissions polls a space's ACL until the identity has permissions
+// (e.g. an admin approved its join request) or ctx is done.
+func (m *MatouACLManager) AwaitPermissions(ctx context.Context, spaceID string, identity crypto.PubKey, interval time.Duration) (list.AclPermissions, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Lookup errors are retried; the space may still be syncing
+		if perm, err := m.GetPermissions(ctx, spaceID, identity); err == nil && !perm.NoPermissions() {
+			return perm, nil
+		}
+		select {
+		case <-ctx.Done():
+			return list.AclPermissionsNone, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// SetAccountPermissions grants an identity permissions in a space's ACL.
+// Identities not yet in the ACL are added with the space read key encrypted
+// to them; existing members have their permissions changed. The caller must
+// be an admin or owner of the space.
+func (m *MatouACLManager) SetAccountPermissions(ctx context.Context, spaceID string, identity crypto.PubKey, permissions list.AclPermissions) error {
+	if permissions.NoPermissions() || permissions.IsOwner() {
+		return fmt.Errorf("cannot grant %v permissions", permissions)
+	}
+
+	space, err := m.client.GetSpace(ctx, spaceID)
+	if err != nil {
+		return fmt.Errorf("getting space %s: %w", spaceID, err)
+	}
+
+	acl := space.Acl()
+	acl.RLock()
+	state := acl.AclState()
+	if state == nil {
+		acl.RUnlock()
+		return fmt.Errorf("ACL state not available for space %s", spaceID)
+	}
+	current := state.Permissions(identity)
+	acl.RUnlock()
+
+	switch {
+	case current == permissions:
+		return nil
+	case current.IsOwner():
+		return fmt.Errorf("cannot change the space owner's permissions")
+	}
+
+	// The ACL client builds the record under the ACL lock, then submits it
+	// to the consensus node and applies it locally.
+	aclClient := space.AclClient()
+	if current.NoPermissions() {
+		err = aclClient.AddAccounts(ctx, list.AccountsAddPayload{
+			Additions: []list.AccountAdd{{Identity: identity, Permissions: permissions}},
+		})
+	} else {
+		err = aclClient.ChangePermissions(ctx, list.PermissionChangesPayload{
+			Changes: []list.PermissionChangePayload{{Identity: identity, Permissions: permissions}},
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("updating ACL for space %s: %w", spaceID, err)
+	}
+	return nil
+}
+
 // GetPermissions returns a user's permissions in a space.
 func (m *MatouACLManager) GetPermissions(ctx context.Context, spaceID string, identity crypto.PubKey) (list.AclPermissions, error) {
 	space, err := m.client.GetSpace(ctx, spaceID)
@@ -108,6 +336,115 @@ func (m *MatouACLManager) GetPermissions(ctx context.Context, spaceID string, id
 	return perm, nil
 }
 
+// Accounts returns the permissions of every account with access to a
+// space, keyed by account address.
+func (m *MatouACLManager) Accounts(ctx context.Context, spaceID string) (map[string]list.AclPermissions, error) {
+	space, err := m.client.GetSpace(ctx, spaceID)
+	if err != nil {
+		return nil, fmt.Errorf("getting space %s: %w", spaceID, err)
+	}
+
+	acl := space.Acl()
+	acl.RLock()
+	defer acl.RUnlock()
+
+	state := acl.AclState()
+	if state == nil {
+		return nil, fmt.Errorf("ACL state not available for space %s", spaceID)
+	}
+	accounts := make(map[string]list.AclPermissions)
+	for _, account := range state.CurrentAccounts() {
+		if account.PubKey == nil || account.Permissions.NoPermissions() {
+			continue
+		}
+		accounts[account.PubKey.Account()] = account.Permissions
+	}
+	return accounts, nil
+}
+
+// RemoveAccounts removes identities from a space's ACL. The read key is
+// rotated in the same record so removed accounts can't read later changes;
+// changes written before the removal stay readable to them. The caller must
+// be an admin or owner of the space.
+func (m *MatouACLManager) RemoveAccounts(ctx context.Context, spaceID string, identities []crypto.PubKey) (err error) {
+	defer metrics.ObserveSpaceOperation("remove_accounts", time.Now(), &err)
+
+	if len(identities) == 0 {
+		return nil
+	}
+	space, err := m.client.GetSpace(ctx, spaceID)
+	if err != nil {
+		return fmt.Errorf("getting space %s: %w", spaceID, err)
+	}
+
+	acl := space.Acl()
+	acl.RLock()
+	state := acl.AclState()
+	if state == nil {
+		acl.RUnlock()
+		return fmt.Errorf("ACL state not available for space %s", spaceID)
+	}
+	for _, identity := range identities {
+		if state.Permissions(identity).IsOwner() {
+			acl.RUnlock()
+			return fmt.Errorf("cannot remove the space owner")
+		}
+	}
+	acl.RUnlock()
+
+	readKey := crypto.NewAES()
+	metadataKey, _, err := crypto.GenerateRandomEd25519KeyPair()
+	if err != nil {
+		return fmt.Errorf("generating metadata key: %w", err)
+	}
+	if err := space.AclClient().RemoveAccounts(ctx, list.AccountRemovePayload{
+		Identities: identities,
+		Change:     list.ReadKeyChangePayload{MetadataKey: metadataKey, ReadKey: readKey},
+	}); err != nil {
+		return fmt.Errorf("removing accounts from space %s: %w", spaceID, err)
+	}
+
+	// Keep a persisted key set current so backups carry the new read key
+	if keys, err := LoadSpaceKeySet(m.client.GetDataDir(), spaceID); err == nil {
+		keys.ReadKey = readKey
+		if err := PersistSpaceKeySet(m.client.GetDataDir(), spaceID, keys); err != nil {
+			fmt.Printf("[ACL] Warning: failed to persist new read key for space %s: %v\n", spaceID, err)
+		}
+	}
+	return nil
+}
+
+// LastActivity returns the time of each identity's most recent ACL record
+// in a space, keyed by account address. Acceptors of join requests count
+// as active when they accepted.
+func (m *MatouACLManager) LastActivity(ctx context.Context, spaceID string) (map[string]time.Time, error) {
+	space, err := m.client.GetSpace(ctx, spaceID)
+	if err != nil {
+		return nil, fmt.Errorf("getting space %s: %w", spaceID, err)
+	}
+
+	acl := space.Acl()
+	acl.RLock()
+	defer acl.RUnlock()
+
+	activity := make(map[string]time.Time)
+	seen := func(identity crypto.PubKey, timestamp int64) {
+		if identity == nil || timestamp <= 0 {
+			return
+		}
+		at := time.Unix(timestamp, 0).UTC()
+		key := identity.Account()
+		if at.After(activity[key]) {
+			activity[key] = at
+		}
+	}
+	for _, record := range acl.Records() {
+		seen(record.Identity, record.Timestamp)
+		seen(record.AcceptorIdentity, record.AcceptorTimestamp)
+	}
+	return activity, nil
+}
+
 // =============================================================================
 // Application-layer ACL policy (KERI credential gating)
 // =============================================================================
@@ -240,7 +577,7 @@ func (m *ACLManager) ValidateAccess(policy *ACLPolicy, aid string, hasCredential
 	}
 }
 
-// GrantAccess adds a user to a space's ACL via the legacy AddToACL path.
+// GrantAccess adds a user to a space's ACL via AddToACL.
 func (m *ACLManager) GrantAccess(spaceID string, peerID string, aid string, permission ACLPermission) error {
 	var permissions []string
 	switch permission {
@@ -256,7 +593,7 @@ func (m *ACLManager) GrantAccess(spaceID string, peerID string, aid string, perm
 		return fmt.Errorf("cannot grant 'none' permission")
 	}
 
-	return m.client.AddToACL(nil, spaceID, peerID, permissions)
+	return m.client.AddToACL(context.Background(), spaceID, peerID, permissions)
 }
 
 // RevokeAccess removes a user from a space's ACL.
@@ -2,12 +2,15 @@ package anysync
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/anyproto/any-sync/commonspace"
 	"github.com/anyproto/any-sync/commonspace/acl/aclclient/mock_aclclient"
 	"github.com/anyproto/any-sync/commonspace/mock_commonspace"
+	"github.com/anyproto/any-sync/commonspace/object/accountdata"
 	"github.com/anyproto/any-sync/commonspace/object/acl/aclrecordproto"
 	"github.com/anyproto/any-sync/commonspace/object/acl/list"
 	"github.com/anyproto/any-sync/commonspace/object/acl/syncacl/mock_syncacl"
@@ -29,9 +32,12 @@ type mockAclRecordBuilder struct {
 	buildInviteJoinWithoutApproveResult *consensusproto.RawRecord
 	buildInviteJoinWithoutApproveErr    error
 
+	buildRequestJoinResult *consensusproto.RawRecord
+
 	// Track calls
 	buildInviteAnyoneCalls              []list.AclPermissions
 	buildInviteJoinWithoutApproveCalls  []list.InviteJoinPayload
+	buildRequestJoinCalls               []list.RequestJoinPayload
 }
 
 func (m *mockAclRecordBuilder) UnmarshallWithId(rawIdRecord *consensusproto.RawRecordWithId) (rec *list.AclRecord, err error) {
@@ -77,7 +83,11 @@ func (m *mockAclRecordBuilder) BuildInviteJoinWithoutApprove(payload list.Invite
 }
 
 func (m *mockAclRecordBuilder) BuildRequestJoin(payload list.RequestJoinPayload) (rawRecord *consensusproto.RawRecord, err error) {
-	return nil, fmt.Errorf("not implemented")
+	m.buildRequestJoinCalls = append(m.buildRequestJoinCalls, payload)
+	if m.buildRequestJoinResult == nil {
+		return nil, fmt.Errorf("not implemented")
+	}
+	return m.buildRequestJoinResult, nil
 }
 
 func (m *mockAclRecordBuilder) BuildRequestAccept(payload list.RequestAcceptPayload) (rawRecord *consensusproto.RawRecord, err error) {
@@ -321,6 +331,162 @@ func TestMatouACLManager_JoinWithInvite(t *testing.T) {
 	}
 }
 
+// newTestACLWithInvite creates an owner's ACL holding one invite, of the
+// open ("anyone can join") kind or the request-to-join kind
+func newTestACLWithInvite(t *testing.T, open bool) (list.AclList, crypto.PrivKey) {
+	t.Helper()
+
+	ownerKeys, err := accountdata.NewRandom()
+	if err != nil {
+		t.Fatalf("generating owner keys: %v", err)
+	}
+	aclList, err := list.NewInMemoryDerivedAcl("test-space", ownerKeys)
+	if err != nil {
+		t.Fatalf("creating ACL: %v", err)
+	}
+
+	var invite list.InviteResult
+	if open {
+		invite, err = aclList.RecordBuilder().BuildInviteAnyone(list.AclPermissionsWriter)
+	} else {
+		invite, err = aclList.RecordBuilder().BuildInvite()
+	}
+	if err != nil {
+		t.Fatalf("building invite: %v", err)
+	}
+	if err := aclList.AddRawRecord(list.WrapAclRecord(invite.InviteRec)); err != nil {
+		t.Fatalf("adding invite: %v", err)
+	}
+	return aclList, invite.InviteKey
+}
+
+func TestMatouACLManager_RequestJoin(t *testing.T) {
+	tests := []struct {
+		name        string
+		open        bool
+		wantPending bool
+	}{
+		{"open invite joins without approval", true, false},
+		{"request invite waits for approval", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			aclList, inviteKey := newTestACLWithInvite(t, tt.open)
+			peerKey, _, _ := crypto.GenerateRandomEd25519KeyPair()
+			joinRec := &consensusproto.RawRecord{Payload: []byte("join-record")}
+			builder := &mockAclRecordBuilder{
+				buildInviteJoinWithoutApproveResult: joinRec,
+				buildRequestJoinResult:              joinRec,
+			}
+
+			mockSpace := mock_commonspace.NewMockSpace(ctrl)
+			mockAcl := mock_syncacl.NewMockSyncAcl(ctrl)
+			mockAclClient := mock_aclclient.NewMockAclSpaceClient(ctrl)
+			client := &testACLClient{space: mockSpace, signingKey: peerKey}
+
+			mockSpace.EXPECT().Acl().Return(mockAcl)
+			mockAcl.EXPECT().Lock()
+			mockAcl.EXPECT().Unlock()
+			mockAcl.EXPECT().AclState().Return(aclList.AclState())
+			mockAcl.EXPECT().RecordBuilder().Return(builder)
+			mockSpace.EXPECT().AclClient().Return(mockAclClient)
+			mockAclClient.EXPECT().AddRecord(gomock.Any(), joinRec).Return(nil)
+
+			mgr := NewMatouACLManager(client, nil)
+			pending, err := mgr.RequestJoin(context.Background(), "test-space", inviteKey, []byte(`{"aid":"EUser123"}`))
+			if err != nil {
+				t.Fatalf("RequestJoin error: %v", err)
+			}
+			if pending != tt.wantPending {
+				t.Errorf("expected pending=%t, got %t", tt.wantPending, pending)
+			}
+
+			joins, requests := len(builder.buildInviteJoinWithoutApproveCalls), len(builder.buildRequestJoinCalls)
+			if tt.open && (joins != 1 || requests != 0) {
+				t.Errorf("expected a direct join, got %d joins and %d requests", joins, requests)
+			}
+			if !tt.open && (joins != 0 || requests != 1) {
+				t.Errorf("expected a join request, got %d joins and %d requests", joins, requests)
+			}
+		})
+	}
+}
+
+func TestMatouACLManager_RequestJoin_AlreadyMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	ownerKeys, _ := accountdata.NewRandom()
+	aclList, err := list.NewInMemoryDerivedAcl("test-space", ownerKeys)
+	if err != nil {
+		t.Fatalf("creating ACL: %v", err)
+	}
+	inviteKey, _, _ := crypto.GenerateRandomEd25519KeyPair()
+
+	mockSpace := mock_commonspace.NewMockSpace(ctrl)
+	mockAcl := mock_syncacl.NewMockSyncAcl(ctrl)
+	client := &testACLClient{space: mockSpace, signingKey: ownerKeys.SignKey}
+
+	// No record is built or submitted
+	mockSpace.EXPECT().Acl().Return(mockAcl)
+	mockAcl.EXPECT().Lock()
+	mockAcl.EXPECT().Unlock()
+	mockAcl.EXPECT().AclState().Return(aclList.AclState())
+
+	mgr := NewMatouACLManager(client, nil)
+	pending, err := mgr.RequestJoin(context.Background(), "test-space", inviteKey, nil)
+	if err != nil || pending {
+		t.Errorf("expected no-op for existing member, got pending=%t err=%v", pending, err)
+	}
+}
+
+func TestMatouACLManager_RequestJoin_UnknownInvite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	aclList, _ := newTestACLWithInvite(t, true)
+	otherKey, _, _ := crypto.GenerateRandomEd25519KeyPair()
+	peerKey, _, _ := crypto.GenerateRandomEd25519KeyPair()
+
+	mockSpace := mock_commonspace.NewMockSpace(ctrl)
+	mockAcl := mock_syncacl.NewMockSyncAcl(ctrl)
+	client := &testACLClient{space: mockSpace, signingKey: peerKey}
+
+	mockSpace.EXPECT().Acl().Return(mockAcl)
+	mockAcl.EXPECT().Lock()
+	mockAcl.EXPECT().Unlock()
+	mockAcl.EXPECT().AclState().Return(aclList.AclState())
+
+	mgr := NewMatouACLManager(client, nil)
+	if _, err := mgr.RequestJoin(context.Background(), "test-space", otherKey, nil); err == nil {
+		t.Error("expected error for an invite not in the ACL")
+	}
+}
+
+func TestMatouACLManager_AwaitPermissions_Timeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	aclList, _ := newTestACLWithInvite(t, false)
+	peerKey, _, _ := crypto.GenerateRandomEd25519KeyPair()
+
+	mockSpace := mock_commonspace.NewMockSpace(ctrl)
+	mockAcl := mock_syncacl.NewMockSyncAcl(ctrl)
+	client := &testACLClient{space: mockSpace}
+
+	mockSpace.EXPECT().Acl().Return(mockAcl).AnyTimes()
+	mockAcl.EXPECT().RLock().AnyTimes()
+	mockAcl.EXPECT().RUnlock().AnyTimes()
+	mockAcl.EXPECT().AclState().Return(aclList.AclState()).AnyTimes()
+
+	mgr := NewMatouACLManager(client, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := mgr.AwaitPermissions(ctx, "test-space", peerKey.GetPublic(), 10*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded while unapproved, got %v", err)
+	}
+}
+
 func TestMatouACLManager_JoinWithInvite_GetSpaceError(t *testing.T) {
 	client := &testACLClient{
 		getSpaceErr: fmt.Errorf("space not found"),
@@ -401,6 +567,223 @@ func TestMatouACLManager_GetPermissions_NilState(t *testing.T) {
 	}
 }
 
+func TestMatouACLManager_SetAccountPermissions_AddsNewAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// A real derived ACL supplies the state; the local account is its owner
+	ownerKeys, err := accountdata.NewRandom()
+	if err != nil {
+		t.Fatalf("generating owner keys: %v", err)
+	}
+	aclList, err := list.NewInMemoryDerivedAcl("test-space", ownerKeys)
+	if err != nil {
+		t.Fatalf("creating ACL: %v", err)
+	}
+	peer, _, _ := crypto.GenerateRandomEd25519KeyPair()
+
+	mockSpace := mock_commonspace.NewMockSpace(ctrl)
+	mockAcl := mock_syncacl.NewMockSyncAcl(ctrl)
+	mockAclClient := mock_aclclient.NewMockAclSpaceClient(ctrl)
+	client := &testACLClient{space: mockSpace}
+
+	mockSpace.EXPECT().Acl().Return(mockAcl)
+	mockAcl.EXPECT().RLock()
+	mockAcl.EXPECT().RUnlock()
+	mockAcl.EXPECT().AclState().Return(aclList.AclState())
+	mockSpace.EXPECT().AclClient().Return(mockAclClient)
+
+	var got list.AccountsAddPayload
+	mockAclClient.EXPECT().AddAccounts(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, payload list.AccountsAddPayload) error {
+			got = payload
+			return nil
+		})
+
+	mgr := NewMatouACLManager(client, nil)
+	if err := mgr.SetAccountPermissions(context.Background(), "test-space", peer.GetPublic(), list.AclPermissionsWriter); err != nil {
+		t.Fatalf("SetAccountPermissions error: %v", err)
+	}
+
+	if len(got.Additions) != 1 {
+		t.Fatalf("expected 1 addition, got %d", len(got.Additions))
+	}
+	if !got.Additions[0].Identity.Equals(peer.GetPublic()) {
+		t.Error("added identity does not match peer")
+	}
+	if got.Additions[0].Permissions != list.AclPermissionsWriter {
+		t.Errorf("expected Writer permissions, got %v", got.Additions[0].Permissions)
+	}
+}
+
+func TestMatouACLManager_LastActivity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	member, _, _ := crypto.GenerateRandomEd25519KeyPair()
+	admin, _, _ := crypto.GenerateRandomEd25519KeyPair()
+
+	mockSpace := mock_commonspace.NewMockSpace(ctrl)
+	mockAcl := mock_syncacl.NewMockSyncAcl(ctrl)
+	client := &testACLClient{space: mockSpace}
+
+	mockSpace.EXPECT().Acl().Return(mockAcl)
+	mockAcl.EXPECT().RLock()
+	mockAcl.EXPECT().RUnlock()
+	mockAcl.EXPECT().Records().Return([]*list.AclRecord{
+		{Identity: admin.GetPublic(), Timestamp: 100},
+		{Identity: member.GetPublic(), Timestamp: 200, AcceptorIdentity: admin.GetPublic(), AcceptorTimestamp: 300},
+		{Identity: member.GetPublic(), Timestamp: 150},
+	})
+
+	mgr := NewMatouACLManager(client, nil)
+	activity, err := mgr.LastActivity(context.Background(), "test-space")
+	if err != nil {
+		t.Fatalf("LastActivity error: %v", err)
+	}
+
+	if got := activity[member.GetPublic().Account()]; got.Unix() != 200 {
+		t.Errorf("expected member last active at 200, got %d", got.Unix())
+	}
+	if got := activity[admin.GetPublic().Account()]; got.Unix() != 300 {
+		t.Errorf("expected admin last active at 300 (acceptance), got %d", got.Unix())
+	}
+}
+
+func TestMatouACLManager_InviteRedemptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockSpace := mock_commonspace.NewMockSpace(ctrl)
+	mockAcl := mock_syncacl.NewMockSyncAcl(ctrl)
+	client := &testACLClient{space: mockSpace}
+
+	join := func(inviteID string) *aclrecordproto.AclContentValue {
+		return &aclrecordproto.AclContentValue{Value: &aclrecordproto.AclContentValue_InviteJoin{
+			InviteJoin: &aclrecordproto.AclAccountInviteJoin{InviteRecordId: inviteID},
+		}}
+	}
+	mockSpace.EXPECT().Acl().Return(mockAcl)
+	mockAcl.EXPECT().RLock()
+	mockAcl.EXPECT().RUnlock()
+	mockAcl.EXPECT().Records().Return([]*list.AclRecord{
+		{Model: &aclrecordproto.AclRoot{}},
+		{Model: &aclrecordproto.AclData{AclContent: []*aclrecordproto.AclContentValue{join("invite-1")}}},
+		{Model: &aclrecordproto.AclData{AclContent: []*aclrecordproto.AclContentValue{join("invite-1"), join("invite-2")}}},
+	})
+
+	mgr := NewMatouACLManager(client, nil)
+	redemptions, err := mgr.InviteRedemptions(context.Background(), "test-space")
+	if err != nil {
+		t.Fatalf("InviteRedemptions error: %v", err)
+	}
+	if redemptions["invite-1"] != 2 || redemptions["invite-2"] != 1 || len(redemptions) != 2 {
+		t.Errorf("unexpected redemptions %v", redemptions)
+	}
+}
+
+func TestMatouACLManager_RevokeInvite_AlreadyRevoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockSpace := mock_commonspace.NewMockSpace(ctrl)
+	mockAclClient := mock_aclclient.NewMockAclSpaceClient(ctrl)
+	client := &testACLClient{space: mockSpace}
+
+	mockSpace.EXPECT().AclClient().Return(mockAclClient).Times(2)
+	mockAclClient.EXPECT().RevokeInvite(gomock.Any(), "invite-1").Return(list.ErrNoSuchInvite)
+	mockAclClient.EXPECT().RevokeInvite(gomock.Any(), "invite-2").Return(fmt.Errorf("network error"))
+
+	mgr := NewMatouACLManager(client, nil)
+	if err := mgr.RevokeInvite(context.Background(), "test-space", "invite-1"); err != nil {
+		t.Errorf("expected revoking a missing invite to succeed, got %v", err)
+	}
+	if err := mgr.RevokeInvite(context.Background(), "test-space", "invite-2"); err == nil {
+		t.Error("expected error when the revoke record can't be added")
+	}
+}
+
+func TestMatouACLManager_SetAccountPermissions_Owner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	ownerKeys, _ := accountdata.NewRandom()
+	aclList, err := list.NewInMemoryDerivedAcl("test-space", ownerKeys)
+	if err != nil {
+		t.Fatalf("creating ACL: %v", err)
+	}
+
+	mockSpace := mock_commonspace.NewMockSpace(ctrl)
+	mockAcl := mock_syncacl.NewMockSyncAcl(ctrl)
+	client := &testACLClient{space: mockSpace}
+
+	mockSpace.EXPECT().Acl().Return(mockAcl)
+	mockAcl.EXPECT().RLock()
+	mockAcl.EXPECT().RUnlock()
+	mockAcl.EXPECT().AclState().Return(aclList.AclState())
+
+	mgr := NewMatouACLManager(client, nil)
+	err = mgr.SetAccountPermissions(context.Background(), "test-space", ownerKeys.SignKey.GetPublic(), list.AclPermissionsWriter)
+	if err == nil {
+		t.Fatal("expected error changing the owner's permissions")
+	}
+}
+
+func TestMatouACLManager_SetAccountPermissions_InvalidPermissions(t *testing.T) {
+	identity, _, _ := crypto.GenerateRandomEd25519KeyPair()
+	mgr := NewMatouACLManager(&testACLClient{}, nil)
+
+	for _, perms := range []list.AclPermissions{list.AclPermissionsNone, list.AclPermissionsOwner} {
+		if err := mgr.SetAccountPermissions(context.Background(), "test-space", identity.GetPublic(), perms); err == nil {
+			t.Errorf("expected error granting %v", perms)
+		}
+	}
+}
+
+func TestAclPermissionsFromStrings(t *testing.T) {
+	tests := []struct {
+		in      []string
+		want    list.AclPermissions
+		wantErr bool
+	}{
+		{[]string{"read"}, list.AclPermissionsReader, false},
+		{[]string{"read", "write"}, list.AclPermissionsWriter, false},
+		{[]string{"write", "read"}, list.AclPermissionsWriter, false},
+		{[]string{"read", "write", "admin"}, list.AclPermissionsAdmin, false},
+		{[]string{"admin", "write"}, list.AclPermissionsAdmin, false},
+		{[]string{"read", "write", "admin", "owner"}, list.AclPermissionsNone, true},
+		{[]string{"delete"}, list.AclPermissionsNone, true},
+		{nil, list.AclPermissionsNone, true},
+	}
+	for _, tt := range tests {
+		got, err := aclPermissionsFromStrings(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%v: error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%v: got %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeACLIdentity(t *testing.T) {
+	_, pub, _ := crypto.GenerateRandomEd25519KeyPair()
+
+	peerID, err := crypto.IdFromSigningPubKey(pub)
+	if err != nil {
+		t.Fatalf("deriving peer ID: %v", err)
+	}
+	for _, id := range []string{peerID.String(), pub.Account()} {
+		got, err := DecodeACLIdentity(id)
+		if err != nil {
+			t.Fatalf("DecodeACLIdentity(%s) error: %v", id, err)
+		}
+		if !got.Equals(pub) {
+			t.Errorf("DecodeACLIdentity(%s) returned a different key", id)
+		}
+	}
+
+	if _, err := DecodeACLIdentity("not-a-peer"); err == nil {
+		t.Error("expected error for invalid peer ID")
+	}
+}
+
 // =============================================================================
 // Test helper: minimal AnySyncClient for ACL tests
 // =============================================================================
@@ -410,6 +793,7 @@ func TestMatouACLManager_GetPermissions_NilState(t *testing.T) {
 type testACLClient struct {
 	space       commonspace.Space
 	getSpaceErr error
+	signingKey  crypto.PrivKey
 }
 
 var _ AnySyncClient = (*testACLClient)(nil)
@@ -436,15 +820,15 @@ func (c *testACLClient) DeriveSpaceID(_ context.Context, _ string, _ string, _ c
 func (c *testACLClient) AddToACL(_ context.Context, _ string, _ string, _ []string) error {
 	return fmt.Errorf("not implemented")
 }
-func (c *testACLClient) SyncDocument(_ context.Context, _ string, _ string, _ []byte) error {
-	return fmt.Errorf("not implemented")
+func (c *testACLClient) SyncDocument(_ context.Context, _ string, _ string, _ []byte) ([]string, error) {
+	return nil, fmt.Errorf("not implemented")
 }
 func (c *testACLClient) MakeSpaceShareable(_ context.Context, _ string) error { return nil }
 func (c *testACLClient) GetNetworkID() string     { return "" }
 func (c *testACLClient) GetCoordinatorURL() string { return "" }
 func (c *testACLClient) GetPeerID() string         { return "" }
 func (c *testACLClient) GetDataDir() string              { return "" }
-func (c *testACLClient) GetSigningKey() crypto.PrivKey   { return nil }
+func (c *testACLClient) GetSigningKey() crypto.PrivKey   { return c.signingKey }
 func (c *testACLClient) GetPool() pool.Pool              { return nil }
 func (c *testACLClient) GetNodeConf() nodeconf.Service { return nil }
 func (c *testACLClient) SetAccountFileLimits(ctx context.Context, identity string, limitBytes uint64) error {
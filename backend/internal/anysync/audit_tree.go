@@ -0,0 +1,415 @@
+// Package anysync provides any-sync integration for MATOU.
+// audit_tree.go keeps the org's shared audit log in a dedicated ObjectTree
+// in the admin space, so every admin's backend replicates the privileged
+// operations recorded by any of them. Admins append concurrently, so each
+// backend keeps its own chain: an entry is hash-chained to the previous
+// entry from the same node and signed with that node's peer key, so each
+// entry proves which admin's backend recorded it.
+package anysync
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/object/tree/treechangeproto"
+	"github.com/anyproto/any-sync/commonspace/objecttreebuilder"
+	"github.com/anyproto/any-sync/util/crypto"
+)
+
+// AuditChangeType is the DataType used for audit entry changes in ObjectTrees.
+const AuditChangeType = "matou.audit.v1"
+
+// AuditEntryChange is one field an audited operation changed
+type AuditEntryChange struct {
+	Path   string `json:"path"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// AuditEntryPayload is the data stored in each audit tree change. Before,
+// After and the changes' values are generic JSON, so the entry hashes the
+// same after being read back.
+type AuditEntryPayload struct {
+	Seq       int                `json:"seq"`      // Position in the node's chain
+	RecordID  string             `json:"recordId"` // The local audit record's ID
+	Action    string             `json:"action"`
+	Actor     string             `json:"actor"`
+	Target    string             `json:"target,omitempty"`
+	Before    any                `json:"before,omitempty"`
+	After     any                `json:"after,omitempty"`
+	Changes   []AuditEntryChange `json:"changes,omitempty"`
+	Node      string             `json:"node"` // Peer ID of the backend that recorded and signed it
+	Timestamp int64              `json:"timestamp"`
+	PrevHash  string             `json:"prevHash,omitempty"` // Hash of the node's previous entry
+
+	// Hash is the SHA-256 of the fields above; Signature is the node's
+	// Ed25519 signature over Hash. SignerKey is the hex-encoded public key,
+	// whose peer ID must be Node.
+	Hash      string `json:"hash"`
+	SignerKey string `json:"signerKey"`
+	Signature string `json:"signature"`
+}
+
+// digest returns the hex SHA-256 over the entry's content fields
+func (e *AuditEntryPayload) digest() (string, error) {
+	content := *e
+	content.Hash, content.SignerKey, content.Signature = "", "", ""
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// seal chains the entry to prev, the previous entry from the same node (nil
+// for the node's first entry), then hashes and signs it with the node's peer
+// key. Node is set to the key's peer ID.
+func (e *AuditEntryPayload) seal(prev *AuditEntryPayload, signingKey crypto.PrivKey) error {
+	if signingKey == nil {
+		return fmt.Errorf("signing key is required")
+	}
+	e.Node = signingKey.GetPublic().PeerId()
+	e.Seq, e.PrevHash = 1, ""
+	if prev != nil {
+		e.Seq = prev.Seq + 1
+		e.PrevHash = prev.Hash
+	}
+
+	hash, err := e.digest()
+	if err != nil {
+		return fmt.Errorf("hashing audit entry: %w", err)
+	}
+	sig, err := signingKey.Sign([]byte(hash))
+	if err != nil {
+		return fmt.Errorf("signing audit entry: %w", err)
+	}
+	pub, err := signingKey.GetPublic().Marshall()
+	if err != nil {
+		return fmt.Errorf("marshaling signer key: %w", err)
+	}
+
+	e.Hash = hash
+	e.SignerKey = hex.EncodeToString(pub)
+	e.Signature = hex.EncodeToString(sig)
+	return nil
+}
+
+// VerifyAuditEntries checks that each node's entries form an unbroken hash
+// chain from its first entry and that every entry's signature is valid.
+// Entries from different nodes may be interleaved in any order. Returns an
+// error naming the first entry that fails.
+func VerifyAuditEntries(entries []*AuditEntryPayload) error {
+	chains := make(map[string][]*AuditEntryPayload)
+	var nodes []string
+	for _, e := range entries {
+		if _, ok := chains[e.Node]; !ok {
+			nodes = append(nodes, e.Node)
+		}
+		chains[e.Node] = append(chains[e.Node], e)
+	}
+
+	for _, node := range nodes {
+		chain := chains[node]
+		sort.SliceStable(chain, func(i, j int) bool { return chain[i].Seq < chain[j].Seq })
+
+		var prev *AuditEntryPayload
+		for _, e := range chain {
+			wantSeq, wantPrev := 1, ""
+			if prev != nil {
+				wantSeq, wantPrev = prev.Seq+1, prev.Hash
+			}
+			if e.Seq != wantSeq || e.PrevHash != wantPrev {
+				return fmt.Errorf("audit entry %d from %s (%s): chain broken", e.Seq, node, e.Action)
+			}
+			if err := verifyAuditEntry(e); err != nil {
+				return fmt.Errorf("audit entry %d from %s (%s): %w", e.Seq, node, e.Action, err)
+			}
+			prev = e
+		}
+	}
+	return nil
+}
+
+// verifyAuditEntry checks an entry's hash and that it was signed by its node
+func verifyAuditEntry(e *AuditEntryPayload) error {
+	hash, err := e.digest()
+	if err != nil || hash != e.Hash {
+		return fmt.Errorf("hash mismatch")
+	}
+
+	pubBytes, err := hex.DecodeString(e.SignerKey)
+	if err != nil {
+		return fmt.Errorf("invalid signer key")
+	}
+	pub, err := crypto.UnmarshalEd25519PublicKeyProto(pubBytes)
+	if err != nil {
+		return fmt.Errorf("invalid signer key")
+	}
+	if pub.PeerId() != e.Node {
+		return fmt.Errorf("not signed by node")
+	}
+	sig, err := hex.DecodeString(e.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature")
+	}
+	if ok, err := pub.Verify([]byte(e.Hash), sig); err != nil || !ok {
+		return fmt.Errorf("bad signature")
+	}
+	return nil
+}
+
+// sortAuditEntries orders entries merged from several trees by time, then
+// by node and position in the node's chain
+func sortAuditEntries(entries []*AuditEntryPayload) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Timestamp != b.Timestamp {
+			return a.Timestamp < b.Timestamp
+		}
+		if a.Node != b.Node {
+			return a.Node < b.Node
+		}
+		return a.Seq < b.Seq
+	})
+}
+
+// AuditTreeManager manages the shared audit log. Entries live in their own
+// tree (not the shared credential/object tree), so it keeps its own cache of
+// the tree this node appends to. Two admins that start the log before
+// syncing each create a tree; a node appends to the tree with the lowest ID
+// it has seen, and reads merge them all.
+type AuditTreeManager struct {
+	client AnySyncClient
+	trees  *TreeCache
+	mu     sync.Mutex                    // serializes appends so this node's chain never forks
+	tips   map[string]*AuditEntryPayload // this node's last entry, by space
+}
+
+// NewAuditTreeManager creates a new AuditTreeManager.
+func NewAuditTreeManager(client AnySyncClient) *AuditTreeManager {
+	return &AuditTreeManager{
+		client: client,
+		trees:  NewTreeCache(),
+		tips:   make(map[string]*AuditEntryPayload),
+	}
+}
+
+// Append seals an entry onto the end of this node's audit chain in the space,
+// signed with the client's peer key, and adds it to the audit tree, creating
+// the tree on first use. signingKey is the space key that writes the tree.
+func (m *AuditTreeManager) Append(ctx context.Context, spaceID string, entry *AuditEntryPayload, signingKey crypto.PrivKey) (*AuditEntryPayload, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	peerKey := m.client.GetSigningKey()
+	if peerKey == nil {
+		return nil, fmt.Errorf("peer key not available")
+	}
+	node := peerKey.GetPublic().PeerId()
+
+	tree, err := m.getOrCreateTree(ctx, spaceID, signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("getting audit tree for space %s: %w", spaceID, err)
+	}
+
+	prev, ok := m.tips[spaceID]
+	if !ok {
+		// Only this node extends its chain, so the tip is read once
+		existing, err := m.ReadEntries(ctx, spaceID)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range existing {
+			if e.Node == node && (prev == nil || e.Seq > prev.Seq) {
+				prev = e
+			}
+		}
+	}
+
+	if entry.Timestamp == 0 {
+		entry.Timestamp = time.Now().Unix()
+	}
+	if err := entry.seal(prev, peerKey); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling audit entry: %w", err)
+	}
+
+	tree.Lock()
+	defer tree.Unlock()
+
+	if _, err := tree.AddContent(ctx, objecttree.SignableChangeContent{
+		Data:              data,
+		Key:               signingKey,
+		IsSnapshot:        false,
+		ShouldBeEncrypted: true,
+		Timestamp:         time.Now().Unix(),
+		DataType:          AuditChangeType,
+	}); err != nil {
+		return nil, fmt.Errorf("adding audit entry: %w", err)
+	}
+	m.tips[spaceID] = entry
+	return entry, nil
+}
+
+// ReadEntries reads the entries from every audit tree in a space, ordered by
+// time. A space without an audit tree has no entries.
+func (m *AuditTreeManager) ReadEntries(ctx context.Context, spaceID string) ([]*AuditEntryPayload, error) {
+	trees, err := m.discoverTrees(ctx, spaceID)
+	if err != nil {
+		return nil, nil
+	}
+
+	var entries []*AuditEntryPayload
+	for _, tree := range trees {
+		treeEntries, err := readAuditTree(tree)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, treeEntries...)
+	}
+	sortAuditEntries(entries)
+	return entries, nil
+}
+
+// readAuditTree reads the entries in one audit tree
+func readAuditTree(tree objecttree.ObjectTree) ([]*AuditEntryPayload, error) {
+	tree.Lock()
+	defer tree.Unlock()
+
+	var entries []*AuditEntryPayload
+	err := tree.IterateRoot(
+		func(change *objecttree.Change, decrypted []byte) (any, error) {
+			if change.DataType != AuditChangeType || len(decrypted) == 0 {
+				return nil, nil
+			}
+			var e AuditEntryPayload
+			if err := json.Unmarshal(decrypted, &e); err != nil {
+				return nil, fmt.Errorf("unmarshaling audit entry: %w", err)
+			}
+			return &e, nil
+		},
+		func(change *objecttree.Change) bool {
+			if e, ok := change.Model.(*AuditEntryPayload); ok {
+				entries = append(entries, e)
+			}
+			return true
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("iterating audit tree %s: %w", tree.Id(), err)
+	}
+	return entries, nil
+}
+
+// discoverTrees finds every audit tree in the space storage, including ones
+// created by other admins and synced to this peer, ordered by tree ID.
+func (m *AuditTreeManager) discoverTrees(ctx context.Context, spaceID string) ([]objecttree.ObjectTree, error) {
+	if m.client == nil {
+		return nil, fmt.Errorf("no client configured")
+	}
+	space, err := m.client.GetSpace(ctx, spaceID)
+	if err != nil {
+		return nil, fmt.Errorf("getting space: %w", err)
+	}
+
+	storedIds := space.StoredIds()
+	builder := space.TreeBuilder()
+	cached, hasCached := m.trees.Load(spaceID)
+
+	var trees []objecttree.ObjectTree
+	for _, treeID := range storedIds {
+		if hasCached && cached.Id() == treeID {
+			trees = append(trees, cached)
+			continue
+		}
+		tree, err := builder.BuildTree(ctx, treeID, objecttreebuilder.BuildTreeOpts{})
+		if err != nil {
+			continue
+		}
+
+		tree.Lock()
+		isAuditTree := false
+		_ = tree.IterateRoot(
+			func(change *objecttree.Change, decrypted []byte) (any, error) {
+				return nil, nil
+			},
+			func(change *objecttree.Change) bool {
+				if change.DataType == AuditChangeType {
+					isAuditTree = true
+					return false
+				}
+				if info, ok := change.Model.(*treechangeproto.TreeChangeInfo); ok {
+					if info.ChangeType == AuditChangeType {
+						isAuditTree = true
+						return false
+					}
+				}
+				return true
+			},
+		)
+		tree.Unlock()
+		if isAuditTree {
+			trees = append(trees, tree)
+		}
+	}
+	if len(trees) == 0 {
+		return nil, fmt.Errorf("no audit tree found in %d stored objects", len(storedIds))
+	}
+	sort.Slice(trees, func(i, j int) bool { return trees[i].Id() < trees[j].Id() })
+	return trees, nil
+}
+
+// getOrCreateTree returns the space's audit tree, creating it if needed.
+func (m *AuditTreeManager) getOrCreateTree(ctx context.Context, spaceID string, signingKey crypto.PrivKey) (objecttree.ObjectTree, error) {
+	if tree, ok := m.trees.Load(spaceID); ok {
+		return tree, nil
+	}
+	if trees, err := m.discoverTrees(ctx, spaceID); err == nil {
+		m.trees.Store(spaceID, trees[0])
+		return trees[0], nil
+	}
+
+	space, err := m.client.GetSpace(ctx, spaceID)
+	if err != nil {
+		return nil, fmt.Errorf("getting space %s: %w", spaceID, err)
+	}
+	treeBuilder := space.TreeBuilder()
+
+	seed := make([]byte, 32)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, fmt.Errorf("generating seed: %w", err)
+	}
+
+	storagePayload, err := treeBuilder.CreateTree(ctx, objecttree.ObjectTreeCreatePayload{
+		PrivKey:       signingKey,
+		ChangeType:    AuditChangeType,
+		ChangePayload: nil,
+		SpaceId:       spaceID,
+		IsEncrypted:   true,
+		Seed:          seed,
+		Timestamp:     time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating tree: %w", err)
+	}
+
+	tree, err := treeBuilder.PutTree(ctx, storagePayload, nil)
+	if err != nil {
+		return nil, fmt.Errorf("putting tree: %w", err)
+	}
+	m.trees.Store(spaceID, tree)
+	return tree, nil
+}
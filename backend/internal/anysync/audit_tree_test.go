@@ -0,0 +1,239 @@
+package anysync
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/anyproto/any-sync/commonspace/mock_commonspace"
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree/mock_objecttree"
+	"github.com/anyproto/any-sync/commonspace/objecttreebuilder/mock_objecttreebuilder"
+	"github.com/anyproto/any-sync/util/crypto"
+	"go.uber.org/mock/gomock"
+)
+
+func sealedAuditEntries(t *testing.T, key crypto.PrivKey, actions ...string) []*AuditEntryPayload {
+	t.Helper()
+	var entries []*AuditEntryPayload
+	var prev *AuditEntryPayload
+	for i, action := range actions {
+		e := &AuditEntryPayload{
+			RecordID: "record",
+			Action:   action,
+			Actor:    "EADMIN",
+			Target:   "ESAID",
+			After:    map[string]any{"status": "issued", "weight": 0.5},
+			Changes: []AuditEntryChange{
+				{Path: "status", After: "issued"},
+			},
+			Timestamp: int64(1700000000 + i),
+		}
+		if err := e.seal(prev, key); err != nil {
+			t.Fatalf("seal: %v", err)
+		}
+		entries = append(entries, e)
+		prev = e
+	}
+	return entries
+}
+
+func TestAuditEntryPayload_Seal(t *testing.T) {
+	key, _, err := crypto.GenerateRandomEd25519KeyPair()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	entries := sealedAuditEntries(t, key, "credential.issue", "credential.revoke")
+	if entries[0].Seq != 1 || entries[0].PrevHash != "" {
+		t.Errorf("first entry should start the chain, got seq %d prev %q", entries[0].Seq, entries[0].PrevHash)
+	}
+	if entries[1].Seq != 2 || entries[1].PrevHash != entries[0].Hash {
+		t.Errorf("second entry should link to the first")
+	}
+	if entries[0].Signature == "" || entries[0].SignerKey == "" {
+		t.Error("expected entry to be signed")
+	}
+	if entries[0].Node != key.GetPublic().PeerId() {
+		t.Errorf("expected the entry's node to be the signer's peer ID, got %s", entries[0].Node)
+	}
+
+	if err := (&AuditEntryPayload{}).seal(nil, nil); err == nil {
+		t.Error("expected error sealing without a key")
+	}
+}
+
+func TestVerifyAuditEntries(t *testing.T) {
+	key, _, err := crypto.GenerateRandomEd25519KeyPair()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	other, _, err := crypto.GenerateRandomEd25519KeyPair()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	if err := VerifyAuditEntries(nil); err != nil {
+		t.Errorf("empty log should verify, got %v", err)
+	}
+	if err := VerifyAuditEntries(sealedAuditEntries(t, key, "acl.grant", "acl.revoke", "org.config")); err != nil {
+		t.Errorf("valid log should verify, got %v", err)
+	}
+
+	// Entries are read back from the tree as JSON, so must still verify
+	entries := sealedAuditEntries(t, key, "acl.grant", "org.config")
+	var read []*AuditEntryPayload
+	for _, e := range entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		var decoded AuditEntryPayload
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		read = append(read, &decoded)
+	}
+	if err := VerifyAuditEntries(read); err != nil {
+		t.Errorf("log read back from JSON should verify, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(entries []*AuditEntryPayload)
+	}{
+		{"edited actor", func(e []*AuditEntryPayload) { e[1].Actor = "EMALLORY" }},
+		{"edited change", func(e []*AuditEntryPayload) { e[1].Changes[0].After = "revoked" }},
+		{"removed entry", func(e []*AuditEntryPayload) { e[1] = e[2] }},
+		{"forked", func(e []*AuditEntryPayload) { e[2].seal(e[0], key) }},
+		{"signed by another node", func(e []*AuditEntryPayload) {
+			sig, _ := other.Sign([]byte(e[1].Hash))
+			pub, _ := other.GetPublic().Marshall()
+			e[1].SignerKey, e[1].Signature = hex.EncodeToString(pub), hex.EncodeToString(sig)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := sealedAuditEntries(t, key, "acl.grant", "acl.revoke", "org.config")
+			tt.tamper(entries)
+			if err := VerifyAuditEntries(entries); err == nil {
+				t.Error("expected tampered log to fail verification")
+			}
+		})
+	}
+}
+
+func TestVerifyAuditEntries_ConcurrentWriters(t *testing.T) {
+	keys := make([]crypto.PrivKey, 2)
+	for i := range keys {
+		key, _, err := crypto.GenerateRandomEd25519KeyPair()
+		if err != nil {
+			t.Fatalf("generating key: %v", err)
+		}
+		keys[i] = key
+	}
+
+	// Each admin appends onto its own chain without seeing the other's
+	chains := make([][]*AuditEntryPayload, len(keys))
+	var wg sync.WaitGroup
+	for i := range keys {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var prev *AuditEntryPayload
+			for j := 0; j < 5; j++ {
+				e := &AuditEntryPayload{
+					RecordID:  fmt.Sprintf("record-%d-%d", i, j),
+					Action:    "acl.grant",
+					Actor:     fmt.Sprintf("EADMIN%d", i+1),
+					Target:    "space-1",
+					Timestamp: int64(1700000000 + j),
+				}
+				if err := e.seal(prev, keys[i]); err != nil {
+					t.Errorf("seal: %v", err)
+					return
+				}
+				chains[i] = append(chains[i], e)
+				prev = e
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Both chains start at seq 1, and reads interleave them by time
+	merged := append(append([]*AuditEntryPayload{}, chains[1]...), chains[0]...)
+	sortAuditEntries(merged)
+	if merged[0].Node == merged[1].Node || merged[0].Seq != 1 || merged[1].Seq != 1 {
+		t.Fatalf("expected the chains interleaved by time, got %s/%d then %s/%d",
+			merged[0].Node, merged[0].Seq, merged[1].Node, merged[1].Seq)
+	}
+	if err := VerifyAuditEntries(merged); err != nil {
+		t.Errorf("concurrent chains should verify, got %v", err)
+	}
+
+	// A node forking its own chain still fails
+	fork := &AuditEntryPayload{Action: "acl.revoke", Actor: "EADMIN1", Timestamp: 1700000010}
+	if err := fork.seal(chains[0][2], keys[0]); err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if err := VerifyAuditEntries(append(merged, fork)); err == nil {
+		t.Error("expected a forked chain to fail verification")
+	}
+}
+
+func TestAuditTreeManager_AppendCachesTip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	peerKey, _, _ := crypto.GenerateRandomEd25519KeyPair()
+	spaceKey, _, _ := crypto.GenerateRandomEd25519KeyPair()
+	mockSpace := mock_commonspace.NewMockSpace(ctrl)
+	mockTree := mock_objecttree.NewMockObjectTree(ctrl)
+
+	mgr := NewAuditTreeManager(&testACLClient{space: mockSpace, signingKey: peerKey})
+	mgr.trees.Store("admin-space", mockTree)
+
+	existing := sealedAuditEntries(t, peerKey, "acl.grant")
+	data, _ := json.Marshal(existing[0])
+
+	// The tree is read once, to find this node's tip; later appends use the cache
+	mockSpace.EXPECT().StoredIds().Return([]string{"audit-tree"})
+	mockSpace.EXPECT().TreeBuilder().Return(mock_objecttreebuilder.NewMockTreeBuilder(ctrl))
+	mockTree.EXPECT().Id().Return("audit-tree").AnyTimes()
+	mockTree.EXPECT().Lock().Times(3)
+	mockTree.EXPECT().Unlock().Times(3)
+	mockTree.EXPECT().IterateRoot(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(convert objecttree.ChangeConvertFunc, iterate objecttree.ChangeIterateFunc) error {
+			change := &objecttree.Change{DataType: AuditChangeType, Data: data}
+			model, err := convert(change, change.Data)
+			if err != nil {
+				return err
+			}
+			change.Model = model
+			iterate(change)
+			return nil
+		},
+	)
+	mockTree.EXPECT().AddContent(gomock.Any(), gomock.Any()).Return(objecttree.AddResult{}, nil).Times(2)
+
+	first, err := mgr.Append(context.Background(), "admin-space", &AuditEntryPayload{Action: "acl.revoke", Actor: "EADMIN"}, spaceKey)
+	if err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	second, err := mgr.Append(context.Background(), "admin-space", &AuditEntryPayload{Action: "org.config", Actor: "EADMIN"}, spaceKey)
+	if err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	if first.Seq != 2 || first.PrevHash != existing[0].Hash {
+		t.Errorf("expected the first append to follow the stored entry, got seq %d", first.Seq)
+	}
+	if second.Seq != 3 || second.PrevHash != first.Hash {
+		t.Errorf("expected the second append to follow the cached tip, got seq %d", second.Seq)
+	}
+	if err := VerifyAuditEntries(append(existing, first, second)); err != nil {
+		t.Errorf("expected the chain to verify, got %v", err)
+	}
+}
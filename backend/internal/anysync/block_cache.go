@@ -0,0 +1,309 @@
+// Package anysync provides any-sync integration for MATOU.
+// block_cache.go keeps recently used file blocks on disk, in front of the
+// filenode. Blocks are content-addressed, so a cached block never goes
+// stale; entries are only dropped to stay under the size limit, when they
+// fail their hash check, or when invalidated.
+package anysync
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anyproto/any-sync/commonfile/fileblockstore"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	"github.com/matou-dao/backend/internal/metrics"
+)
+
+// BlockCache is an on-disk LRU cache of file blocks, one file per block
+// named by its CID. Recency is kept in file modification times, so the
+// eviction order survives restarts.
+type BlockCache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // CID → element holding *blockCacheEntry
+	lru     *list.List               // most recently used at the front
+	size    int64
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// blockCacheEntry is a cached block
+type blockCacheEntry struct {
+	key  string
+	size int64
+}
+
+// BlockCacheStats describes the cache's contents and hit rate
+type BlockCacheStats struct {
+	Blocks   int   `json:"blocks"`
+	Bytes    int64 `json:"bytes"`
+	MaxBytes int64 `json:"maxBytes"`
+	Hits     int64 `json:"hits"`
+	Misses   int64 `json:"misses"`
+}
+
+// NewBlockCache opens the cache in dir, creating it if needed, and indexes
+// the blocks already there. Blocks over maxBytes are evicted, least
+// recently used first.
+func NewBlockCache(dir string, maxBytes int64) (*BlockCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("creating block cache directory: %w", err)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading block cache directory: %w", err)
+	}
+
+	type cachedFile struct {
+		entry   *blockCacheEntry
+		modTime time.Time
+	}
+	var cached []cachedFile
+	for _, f := range files {
+		info, err := f.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if strings.HasPrefix(f.Name(), "tmp-") {
+			// Left over from an interrupted write
+			os.Remove(filepath.Join(dir, f.Name()))
+			continue
+		}
+		if _, err := cid.Decode(f.Name()); err != nil {
+			continue
+		}
+		cached = append(cached, cachedFile{&blockCacheEntry{key: f.Name(), size: info.Size()}, info.ModTime()})
+	}
+	sort.Slice(cached, func(i, j int) bool { return cached[i].modTime.After(cached[j].modTime) })
+
+	c := &BlockCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		entries:  make(map[string]*list.Element, len(cached)),
+		lru:      list.New(),
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, f := range cached {
+		c.entries[f.entry.key] = c.lru.PushBack(f.entry)
+		c.size += f.entry.size
+	}
+	c.evictLocked()
+	return c, nil
+}
+
+// Get returns a cached block's data. A block whose data doesn't match its
+// CID is dropped and reported as a miss.
+func (c *BlockCache) Get(k cid.Cid) ([]byte, bool) {
+	key := k.String()
+	c.mu.Lock()
+	elem, ok := c.entries[key]
+	if ok {
+		c.lru.MoveToFront(elem)
+	}
+	c.mu.Unlock()
+
+	var data []byte
+	if ok {
+		data, ok = c.read(k, key)
+	}
+
+	metrics.CountBlockCacheLookup(ok)
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	now := time.Now()
+	os.Chtimes(c.path(key), now, now)
+	return data, true
+}
+
+// Put caches a block. Blocks larger than the whole cache aren't kept.
+func (c *BlockCache) Put(k cid.Cid, data []byte) {
+	size := int64(len(data))
+	if size > c.maxBytes {
+		return
+	}
+	key := k.String()
+	c.mu.Lock()
+	elem, ok := c.entries[key]
+	if ok {
+		c.lru.MoveToFront(elem)
+	}
+	c.mu.Unlock()
+	if ok {
+		return
+	}
+
+	// Write to a temporary name first so a crash can't leave a partial
+	// block under its CID
+	tmp, err := os.CreateTemp(c.dir, "tmp-*")
+	if err != nil {
+		fmt.Printf("[BlockCache] Warning: failed to cache block %s: %v\n", key, err)
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		fmt.Printf("[BlockCache] Warning: failed to cache block %s: %v\n", key, err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.entries[key] = c.lru.PushFront(&blockCacheEntry{key: key, size: size})
+		c.size += size
+	}
+	c.evictLocked()
+}
+
+// Invalidate drops a block from the cache
+func (c *BlockCache) Invalidate(k cid.Cid) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[k.String()]; ok {
+		c.removeLocked(elem)
+		metrics.SetBlockCacheSize(len(c.entries), c.size)
+	}
+}
+
+// Clear drops every block from the cache
+func (c *BlockCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.lru.Len() > 0 {
+		c.removeLocked(c.lru.Back())
+	}
+	metrics.SetBlockCacheSize(0, 0)
+}
+
+// Stats returns the cache's size and hit counts since startup
+func (c *BlockCache) Stats() BlockCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return BlockCacheStats{
+		Blocks:   len(c.entries),
+		Bytes:    c.size,
+		MaxBytes: c.maxBytes,
+		Hits:     c.hits.Load(),
+		Misses:   c.misses.Load(),
+	}
+}
+
+// evictLocked removes least recently used blocks until the cache fits its
+// limit. Callers hold mu.
+func (c *BlockCache) evictLocked() {
+	for c.size > c.maxBytes && c.lru.Len() > 0 {
+		c.removeLocked(c.lru.Back())
+		metrics.CountBlockCacheEviction()
+	}
+	metrics.SetBlockCacheSize(len(c.entries), c.size)
+}
+
+// removeLocked deletes a block's entry and file. Callers hold mu.
+func (c *BlockCache) removeLocked(elem *list.Element) {
+	entry := elem.Value.(*blockCacheEntry)
+	c.lru.Remove(elem)
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+	os.Remove(c.path(entry.key))
+}
+
+// read reads a cached block, dropping it if it can't be read or doesn't
+// match its CID
+func (c *BlockCache) read(k cid.Cid, key string) ([]byte, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err == nil {
+		if sum, err := k.Prefix().Sum(data); err == nil && sum.Equals(k) {
+			return data, true
+		}
+	}
+	c.Invalidate(k)
+	return nil, false
+}
+
+func (c *BlockCache) path(key string) string {
+	return filepath.Join(c.dir, key)
+}
+
+// Compile-time check that cachedBlockStore implements BlockStoreLocal.
+var _ fileblockstore.BlockStoreLocal = (*cachedBlockStore)(nil)
+
+// cachedBlockStore serves blocks from a BlockCache, fetching misses from
+// the filenode through a RemoteBlockStore. Blocks added are cached as they
+// are pushed, so a file is cached from the moment it is uploaded.
+type cachedBlockStore struct {
+	*RemoteBlockStore
+	cache *BlockCache
+}
+
+// Get returns a block from the cache, or fetches and caches it
+func (s *cachedBlockStore) Get(ctx context.Context, k cid.Cid) (blocks.Block, error) {
+	if data, ok := s.cache.Get(k); ok {
+		return blocks.NewBlockWithCid(data, k)
+	}
+	b, err := s.RemoteBlockStore.Get(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Put(k, b.RawData())
+	return b, nil
+}
+
+// GetMany fetches multiple blocks through the cache. Returns a channel
+// that yields blocks as they are retrieved.
+func (s *cachedBlockStore) GetMany(ctx context.Context, ks []cid.Cid) <-chan blocks.Block {
+	ch := make(chan blocks.Block, len(ks))
+	go func() {
+		defer close(ch)
+		for _, k := range ks {
+			b, err := s.Get(ctx, k)
+			if err != nil {
+				continue
+			}
+			select {
+			case ch <- b:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// Add pushes blocks to the filenode and caches them
+func (s *cachedBlockStore) Add(ctx context.Context, bs []blocks.Block) error {
+	if err := s.RemoteBlockStore.Add(ctx, bs); err != nil {
+		return err
+	}
+	for _, b := range bs {
+		s.cache.Put(b.Cid(), b.RawData())
+	}
+	return nil
+}
+
+// Delete drops a block from the cache
+func (s *cachedBlockStore) Delete(ctx context.Context, c cid.Cid) error {
+	s.cache.Invalidate(c)
+	return s.RemoteBlockStore.Delete(ctx, c)
+}
@@ -0,0 +1,109 @@
+package anysync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBlockCache(t *testing.T) {
+	cache, err := NewBlockCache(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatalf("NewBlockCache: %v", err)
+	}
+	data := []byte("avatar block")
+	k := makeCID(data)
+
+	if _, ok := cache.Get(k); ok {
+		t.Fatal("expected a miss before the block is cached")
+	}
+	cache.Put(k, data)
+	got, ok := cache.Get(k)
+	if !ok || string(got) != string(data) {
+		t.Fatalf("expected cached block, got %q %v", got, ok)
+	}
+
+	stats := cache.Stats()
+	if stats.Blocks != 1 || stats.Bytes != int64(len(data)) || stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	cache.Invalidate(k)
+	if _, ok := cache.Get(k); ok {
+		t.Error("expected invalidated block to miss")
+	}
+}
+
+func TestBlockCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache, _ := NewBlockCache(t.TempDir(), 10)
+	a, b, c := []byte("aaaa"), []byte("bbbb"), []byte("cccc")
+	cache.Put(makeCID(a), a)
+	cache.Put(makeCID(b), b)
+	cache.Get(makeCID(a))
+	cache.Put(makeCID(c), c)
+
+	if _, ok := cache.Get(makeCID(b)); ok {
+		t.Error("expected the least recently used block evicted")
+	}
+	for _, data := range [][]byte{a, c} {
+		if _, ok := cache.Get(makeCID(data)); !ok {
+			t.Errorf("expected %s kept", data)
+		}
+	}
+	if stats := cache.Stats(); stats.Bytes != 8 {
+		t.Errorf("expected 8 bytes cached, got %d", stats.Bytes)
+	}
+
+	cache.Put(makeCID([]byte("larger than the cache")), []byte("larger than the cache"))
+	if stats := cache.Stats(); stats.Blocks != 2 {
+		t.Errorf("expected a block larger than the cache not kept, got %d blocks", stats.Blocks)
+	}
+}
+
+func TestBlockCacheDropsCorruptBlocks(t *testing.T) {
+	dir := t.TempDir()
+	cache, _ := NewBlockCache(dir, 1024)
+	data := []byte("original")
+	k := makeCID(data)
+	cache.Put(k, data)
+
+	os.WriteFile(filepath.Join(dir, k.String()), []byte("tampered"), 0600)
+	if _, ok := cache.Get(k); ok {
+		t.Fatal("expected a block that doesn't match its CID to miss")
+	}
+	if _, err := os.Stat(filepath.Join(dir, k.String())); !os.IsNotExist(err) {
+		t.Error("expected the corrupt block removed")
+	}
+}
+
+func TestBlockCacheReopen(t *testing.T) {
+	dir := t.TempDir()
+	cache, _ := NewBlockCache(dir, 1024)
+	old, recent := []byte("old block"), []byte("recent block")
+	cache.Put(makeCID(old), old)
+	cache.Put(makeCID(recent), recent)
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(filepath.Join(dir, makeCID(old).String()), past, past)
+	os.WriteFile(filepath.Join(dir, "tmp-123"), []byte("partial"), 0600)
+
+	// Reopened with room for one block, the older one goes
+	reopened, err := NewBlockCache(dir, int64(len(recent)))
+	if err != nil {
+		t.Fatalf("NewBlockCache: %v", err)
+	}
+	if _, ok := reopened.Get(makeCID(recent)); !ok {
+		t.Error("expected the recently used block kept across restarts")
+	}
+	if _, ok := reopened.Get(makeCID(old)); ok {
+		t.Error("expected the older block evicted")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tmp-123")); !os.IsNotExist(err) {
+		t.Error("expected leftover temporary file removed")
+	}
+
+	reopened.Clear()
+	if stats := reopened.Stats(); stats.Blocks != 0 || stats.Bytes != 0 {
+		t.Errorf("expected an empty cache after Clear, got %+v", stats)
+	}
+}
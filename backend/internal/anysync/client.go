@@ -9,6 +9,8 @@ import (
 
 	"github.com/anyproto/any-sync/nodeconf"
 	"gopkg.in/yaml.v3"
+
+	"github.com/matou-dao/backend/internal/secret"
 )
 
 // ClientConfig represents the any-sync client.yml structure
@@ -32,7 +34,7 @@ type ClientOptions struct {
 	// PeerKeyPath is the path to store/load the peer key
 	PeerKeyPath string
 	// Mnemonic for deterministic key derivation (optional)
-	Mnemonic string
+	Mnemonic *secret.Mnemonic
 	// KeyIndex for mnemonic derivation (default 0)
 	KeyIndex uint32
 }
@@ -3,8 +3,11 @@ package anysync
 import (
 	"os"
 	"path/filepath"
+	"sort"
 	"testing"
 	"time"
+
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
 )
 
 func TestLoadClientConfig(t *testing.T) {
@@ -126,3 +129,42 @@ func TestSpaceCreateResult(t *testing.T) {
 		t.Errorf("unexpected space type: %s", result.SpaceType)
 	}
 }
+
+func TestSpaceSubscription(t *testing.T) {
+	msg, err := spaceSubscription("space-a", "space-b")
+	if err != nil {
+		t.Fatalf("spaceSubscription error: %v", err)
+	}
+
+	// Nodes read subscriptions from messages with no space or object ID
+	if msg.SpaceId != "" || msg.ObjectId != "" {
+		t.Errorf("expected untargeted message, got space=%q object=%q", msg.SpaceId, msg.ObjectId)
+	}
+
+	var sub spacesyncproto.SpaceSubscription
+	if err := sub.UnmarshalVT(msg.Payload); err != nil {
+		t.Fatalf("payload is not a SpaceSubscription: %v", err)
+	}
+	if sub.Action != spacesyncproto.SpaceSubscriptionAction_Subscribe {
+		t.Errorf("expected subscribe action, got %v", sub.Action)
+	}
+	if len(sub.SpaceIds) != 2 || sub.SpaceIds[0] != "space-a" || sub.SpaceIds[1] != "space-b" {
+		t.Errorf("unexpected space IDs: %v", sub.SpaceIds)
+	}
+}
+
+func TestSpaceResolver_SpaceIDs(t *testing.T) {
+	r := &sdkSpaceResolver{}
+	if ids := r.spaceIDs(); len(ids) != 0 {
+		t.Errorf("expected no spaces, got %v", ids)
+	}
+
+	r.cache.Store("space-b", nil)
+	r.cache.Store("space-a", nil)
+
+	ids := r.spaceIDs()
+	sort.Strings(ids)
+	if len(ids) != 2 || ids[0] != "space-a" || ids[1] != "space-b" {
+		t.Errorf("unexpected space IDs: %v", ids)
+	}
+}
@@ -56,6 +56,12 @@ func (c *TreeCache) Store(spaceID string, tree objecttree.ObjectTree) {
 	c.trees.Store(spaceID, tree)
 }
 
+// Delete drops the cached tree for a space, so the next access discovers
+// the space's current tree.
+func (c *TreeCache) Delete(spaceID string) {
+	c.trees.Delete(spaceID)
+}
+
 // CredentialTreeManager manages credential storage in ObjectTrees.
 // Each space has one credential tree for storing KERI credentials as
 // encrypted, signed CRDT changes. Peers must join the space via ACL
@@ -64,6 +70,7 @@ type CredentialTreeManager struct {
 	client     AnySyncClient
 	keyManager *PeerKeyManager
 	trees      *TreeCache
+	compact    bool
 }
 
 // NewCredentialTreeManager creates a new CredentialTreeManager with a shared TreeCache.
@@ -87,6 +94,12 @@ func (m *CredentialTreeManager) TreeCache() *TreeCache {
 	return m.trees
 }
 
+// SetCompactEncoding sets whether new credential changes use the compact
+// encoding
+func (m *CredentialTreeManager) SetCompactEncoding(compact bool) {
+	m.compact = compact
+}
+
 // CreateCredentialTree creates a new ObjectTree in a space for storing credentials.
 // The tree is encrypted — peers must join the space via ACL invite to receive the
 // ReadKey needed to decrypt credential data. Integrity is ensured by Ed25519 signatures.
@@ -137,7 +150,7 @@ func (m *CredentialTreeManager) AddCredential(ctx context.Context, spaceID strin
 		return "", fmt.Errorf("getting tree for space %s: %w", spaceID, err)
 	}
 
-	data, err := json.Marshal(cred)
+	data, dataType, err := encodeCredentialPayload(cred, m.compact)
 	if err != nil {
 		return "", fmt.Errorf("marshaling credential: %w", err)
 	}
@@ -151,7 +164,7 @@ func (m *CredentialTreeManager) AddCredential(ctx context.Context, spaceID strin
 		IsSnapshot:        false,
 		ShouldBeEncrypted: true,
 		Timestamp:         time.Now().Unix(),
-		DataType:          CredentialChangeType,
+		DataType:          dataType,
 	})
 	if err != nil {
 		return "", fmt.Errorf("adding content: %w", err)
@@ -169,15 +182,23 @@ func (m *CredentialTreeManager) AddCredential(ctx context.Context, spaceID strin
 // discovers credential trees from the space storage (supports reading trees
 // created by other peers and synced via tree nodes).
 func (m *CredentialTreeManager) ReadCredentials(ctx context.Context, spaceID string) ([]*CredentialPayload, error) {
+	creds, _, err := m.ReadCredentialsAtHeads(ctx, spaceID)
+	return creds, err
+}
+
+// ReadCredentialsAtHeads reads all credentials from a space's credential
+// tree, with the tree heads they were read at. The tree is locked for the
+// read, so the credentials are exactly those the heads cover.
+func (m *CredentialTreeManager) ReadCredentialsAtHeads(ctx context.Context, spaceID string) ([]*CredentialPayload, []string, error) {
 	tree, ok := m.trees.Load(spaceID)
 	if !ok {
 		// Try to discover and load a credential tree from the space storage
 		if err := m.discoverTree(ctx, spaceID); err != nil {
-			return nil, fmt.Errorf("no credential tree for space %s: %w", spaceID, err)
+			return nil, nil, fmt.Errorf("no credential tree for space %s: %w", spaceID, err)
 		}
 		tree, ok = m.trees.Load(spaceID)
 		if !ok {
-			return nil, fmt.Errorf("no credential tree for space %s", spaceID)
+			return nil, nil, fmt.Errorf("no credential tree for space %s", spaceID)
 		}
 	}
 	tree.Lock()
@@ -191,11 +212,15 @@ func (m *CredentialTreeManager) ReadCredentials(ctx context.Context, spaceID str
 			if len(decrypted) == 0 {
 				return nil, nil
 			}
-			var p CredentialPayload
-			if err := json.Unmarshal(decrypted, &p); err != nil {
+			// Compact changes of other types can't be read as credentials
+			if base, compact := baseDataType(change.DataType); compact && base != CredentialChangeType {
+				return nil, nil
+			}
+			p, err := decodeCredentialPayload(change.DataType, decrypted)
+			if err != nil {
 				return nil, fmt.Errorf("unmarshaling credential: %w", err)
 			}
-			return &p, nil
+			return p, nil
 		},
 		// iterate: collect all converted models
 		func(change *objecttree.Change) bool {
@@ -209,10 +234,22 @@ func (m *CredentialTreeManager) ReadCredentials(ctx context.Context, spaceID str
 		},
 	)
 	if err != nil {
-		return nil, fmt.Errorf("iterating tree: %w", err)
+		return nil, nil, fmt.Errorf("iterating tree: %w", err)
 	}
 
-	return creds, nil
+	return creds, append([]string(nil), tree.Heads()...), nil
+}
+
+// Heads returns the current heads of a space's credential tree, or nil if
+// the tree isn't loaded. Cheaper than a read when only checking for changes.
+func (m *CredentialTreeManager) Heads(spaceID string) []string {
+	tree, ok := m.trees.Load(spaceID)
+	if !ok {
+		return nil
+	}
+	tree.Lock()
+	defer tree.Unlock()
+	return append([]string(nil), tree.Heads()...)
 }
 
 // GetTreeID returns the ID of the credential tree for a space, or empty string if none.
@@ -255,7 +292,7 @@ func (m *CredentialTreeManager) discoverTree(ctx context.Context, spaceID string
 				return nil, nil
 			},
 			func(change *objecttree.Change) bool {
-				if change.DataType == CredentialChangeType || change.DataType == ObjectChangeType {
+				if base, _ := baseDataType(change.DataType); base == CredentialChangeType || base == ObjectChangeType {
 					isMatouTree = true
 					return false
 				}
@@ -0,0 +1,247 @@
+// Package anysync provides any-sync integration for MATOU.
+// encoding.go implements the compact change encoding: object and credential
+// payloads in protobuf wire format instead of JSON. A compact change carries
+// CompactEncodingSuffix on its DataType, so readers choose the decoder per
+// change and a tree can hold both encodings.
+package anysync
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// CompactEncodingSuffix is appended to a change's DataType when its payload
+// is compact-encoded, e.g. "matou.object.v1+pb".
+const CompactEncodingSuffix = "+pb"
+
+// Change encodings, as configured with anysync.changeEncoding
+const (
+	EncodingJSON    = "json"
+	EncodingCompact = "compact"
+)
+
+// Field numbers of the compact ObjectPayload. Numbers are never reused;
+// decoders skip fields they don't know.
+const (
+	objectFieldID        protowire.Number = 1
+	objectFieldType      protowire.Number = 2
+	objectFieldOwnerKey  protowire.Number = 3
+	objectFieldData      protowire.Number = 4
+	objectFieldTimestamp protowire.Number = 5
+	objectFieldVersion   protowire.Number = 6
+)
+
+// Field numbers of the compact CredentialPayload
+const (
+	credentialFieldSAID      protowire.Number = 1
+	credentialFieldIssuer    protowire.Number = 2
+	credentialFieldRecipient protowire.Number = 3
+	credentialFieldSchema    protowire.Number = 4
+	credentialFieldData      protowire.Number = 5
+	credentialFieldTimestamp protowire.Number = 6
+)
+
+// ParseChangeEncoding reports whether a configured encoding is compact.
+// Empty means JSON.
+func ParseChangeEncoding(encoding string) (bool, error) {
+	switch encoding {
+	case "", EncodingJSON:
+		return false, nil
+	case EncodingCompact:
+		return true, nil
+	}
+	return false, fmt.Errorf("unknown change encoding %q", encoding)
+}
+
+// changeDataType returns the DataType for a change of the given base type
+func changeDataType(base string, compact bool) string {
+	if compact {
+		return base + CompactEncodingSuffix
+	}
+	return base
+}
+
+// baseDataType strips the encoding suffix from a change's DataType and
+// reports whether the change is compact-encoded
+func baseDataType(dataType string) (string, bool) {
+	if base, ok := strings.CutSuffix(dataType, CompactEncodingSuffix); ok {
+		return base, true
+	}
+	return dataType, false
+}
+
+// encodeObjectPayload encodes an object change and returns its DataType
+func encodeObjectPayload(p *ObjectPayload, compact bool) ([]byte, string, error) {
+	dataType := changeDataType(ObjectChangeType, compact)
+	if !compact {
+		data, err := json.Marshal(p)
+		return data, dataType, err
+	}
+	var b []byte
+	b = appendString(b, objectFieldID, p.ID)
+	b = appendString(b, objectFieldType, p.Type)
+	b = appendString(b, objectFieldOwnerKey, p.OwnerKey)
+	b = appendBytes(b, objectFieldData, p.Data)
+	b = appendVarint(b, objectFieldTimestamp, uint64(p.Timestamp))
+	b = appendVarint(b, objectFieldVersion, uint64(p.Version))
+	return b, dataType, nil
+}
+
+// decodeObjectPayload decodes an object change of either encoding
+func decodeObjectPayload(dataType string, data []byte) (*ObjectPayload, error) {
+	var p ObjectPayload
+	if _, compact := baseDataType(dataType); !compact {
+		err := json.Unmarshal(data, &p)
+		return &p, err
+	}
+	err := consumeFields(data, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) {
+		switch {
+		case num == objectFieldID && typ == protowire.BytesType:
+			p.ID = string(value)
+		case num == objectFieldType && typ == protowire.BytesType:
+			p.Type = string(value)
+		case num == objectFieldOwnerKey && typ == protowire.BytesType:
+			p.OwnerKey = string(value)
+		case num == objectFieldData && typ == protowire.BytesType:
+			p.Data = json.RawMessage(append([]byte(nil), value...))
+		case num == objectFieldTimestamp && typ == protowire.VarintType:
+			p.Timestamp = int64(varint)
+		case num == objectFieldVersion && typ == protowire.VarintType:
+			p.Version = int(int64(varint))
+		}
+	})
+	return &p, err
+}
+
+// encodeCredentialPayload encodes a credential change and returns its DataType
+func encodeCredentialPayload(p *CredentialPayload, compact bool) ([]byte, string, error) {
+	dataType := changeDataType(CredentialChangeType, compact)
+	if !compact {
+		data, err := json.Marshal(p)
+		return data, dataType, err
+	}
+	var b []byte
+	b = appendString(b, credentialFieldSAID, p.SAID)
+	b = appendString(b, credentialFieldIssuer, p.Issuer)
+	b = appendString(b, credentialFieldRecipient, p.Recipient)
+	b = appendString(b, credentialFieldSchema, p.Schema)
+	b = appendBytes(b, credentialFieldData, p.Data)
+	b = appendVarint(b, credentialFieldTimestamp, uint64(p.Timestamp))
+	return b, dataType, nil
+}
+
+// decodeCredentialPayload decodes a credential change of either encoding
+func decodeCredentialPayload(dataType string, data []byte) (*CredentialPayload, error) {
+	var p CredentialPayload
+	if _, compact := baseDataType(dataType); !compact {
+		err := json.Unmarshal(data, &p)
+		return &p, err
+	}
+	err := consumeFields(data, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) {
+		switch {
+		case num == credentialFieldSAID && typ == protowire.BytesType:
+			p.SAID = string(value)
+		case num == credentialFieldIssuer && typ == protowire.BytesType:
+			p.Issuer = string(value)
+		case num == credentialFieldRecipient && typ == protowire.BytesType:
+			p.Recipient = string(value)
+		case num == credentialFieldSchema && typ == protowire.BytesType:
+			p.Schema = string(value)
+		case num == credentialFieldData && typ == protowire.BytesType:
+			p.Data = json.RawMessage(append([]byte(nil), value...))
+		case num == credentialFieldTimestamp && typ == protowire.VarintType:
+			p.Timestamp = int64(varint)
+		}
+	})
+	return &p, err
+}
+
+// reencodeChange converts an object or credential change into the target
+// encoding. Other changes, and changes already in it, are returned as-is.
+func reencodeChange(dataType string, data []byte, compact bool) ([]byte, string, error) {
+	base, isCompact := baseDataType(dataType)
+	if isCompact == compact {
+		return data, dataType, nil
+	}
+	switch base {
+	case ObjectChangeType:
+		p, err := decodeObjectPayload(dataType, data)
+		if err != nil {
+			return nil, "", fmt.Errorf("decoding object: %w", err)
+		}
+		return encodeObjectPayload(p, compact)
+	case CredentialChangeType:
+		p, err := decodeCredentialPayload(dataType, data)
+		if err != nil {
+			return nil, "", fmt.Errorf("decoding credential: %w", err)
+		}
+		return encodeCredentialPayload(p, compact)
+	}
+	return data, dataType, nil
+}
+
+// appendString appends a length-delimited string field, omitting empty values
+func appendString(b []byte, num protowire.Number, value string) []byte {
+	if value == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
+
+// appendBytes appends a length-delimited bytes field, omitting empty values
+func appendBytes(b []byte, num protowire.Number, value []byte) []byte {
+	if len(value) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, value)
+}
+
+// appendVarint appends a varint field, omitting zero
+func appendVarint(b []byte, num protowire.Number, value uint64) []byte {
+	if value == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, value)
+}
+
+// consumeFields walks the fields of a protobuf message. For each field,
+// field gets its length-delimited value or its varint; fields of other wire
+// types are skipped.
+func consumeFields(b []byte, field func(num protowire.Number, typ protowire.Type, value []byte, varint uint64)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("reading field tag: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+		switch typ {
+		case protowire.BytesType:
+			value, m := protowire.ConsumeBytes(b)
+			if m < 0 {
+				return fmt.Errorf("reading field %d: %w", num, protowire.ParseError(m))
+			}
+			field(num, typ, value, 0)
+			n = m
+		case protowire.VarintType:
+			value, m := protowire.ConsumeVarint(b)
+			if m < 0 {
+				return fmt.Errorf("reading field %d: %w", num, protowire.ParseError(m))
+			}
+			field(num, typ, nil, value)
+			n = m
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return fmt.Errorf("skipping field %d: %w", num, protowire.ParseError(n))
+			}
+		}
+		b = b[n:]
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Package anysync provides any-sync integration for MATOU.
+// encoding_migration.go rewrites a space's trees into another change
+// encoding. Tree changes are immutable, so a tree is migrated by copying it.
+package anysync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matou-dao/backend/internal/metrics"
+)
+
+// EncodingMigration is the outcome of migrating a space's change encoding
+type EncodingMigration struct {
+	SpaceID      string `json:"spaceId"`
+	Encoding     string `json:"encoding"`
+	TreesChecked int    `json:"treesChecked"`
+	// Trees lists the trees that were rewritten or failed to; trees already
+	// in the target encoding are left alone and not listed
+	Trees []TreeReencryption `json:"trees,omitempty"`
+}
+
+// SetCompactEncoding sets whether new object and credential changes are
+// written in the compact encoding. Reads accept both encodings either way.
+func (m *SpaceManager) SetCompactEncoding(compact bool) {
+	m.objTreeManager.SetCompactEncoding(compact)
+	m.credTreeManager.SetCompactEncoding(compact)
+}
+
+// MigrateChangeEncoding rewrites every tree in a space that holds object or
+// credential changes in the other encoding. Each such tree is copied with
+// its changes converted, signed with the space's signing key, and the old
+// tree is deleted; other change types are copied unchanged. Running it
+// again only touches trees that peers have since written the old encoding
+// into.
+//
+// Changes written to a tree while it is being copied are lost, so migrate
+// when peers are quiet. A tree that fails to copy is left in place and
+// reported.
+func (m *SpaceManager) MigrateChangeEncoding(ctx context.Context, spaceID string, compact bool) (migration *EncodingMigration, err error) {
+	defer metrics.ObserveSpaceOperation("migrate_change_encoding", time.Now(), &err)
+
+	migration = &EncodingMigration{SpaceID: spaceID, Encoding: EncodingJSON}
+	if compact {
+		migration.Encoding = EncodingCompact
+	}
+
+	space, err := m.client.GetSpace(ctx, spaceID)
+	if err != nil {
+		return migration, fmt.Errorf("getting space %s: %w", spaceID, err)
+	}
+	keys, err := LoadSpaceKeySet(m.client.GetDataDir(), spaceID)
+	if err != nil {
+		return migration, fmt.Errorf("loading key set for space %s: %w", spaceID, err)
+	}
+
+	convert := func(dataType string, data []byte) ([]byte, string, error) {
+		return reencodeChange(dataType, data, compact)
+	}
+	for _, treeID := range space.StoredIds() {
+		if err := ctx.Err(); err != nil {
+			return migration, err
+		}
+		migration.TreesChecked++
+		result := copyTree(ctx, space, treeID, keys.SigningKey, convert, func(int) {})
+		if result.NewID != "" || result.Error != "" {
+			migration.Trees = append(migration.Trees, result)
+		}
+	}
+
+	if len(migration.Trees) > 0 {
+		m.treeCache.Delete(spaceID)
+		m.receiptTreeManager.trees.Delete(spaceID)
+		if f, ok := m.client.(treeForgetter); ok {
+			f.ForgetTrees(spaceID)
+		}
+	}
+	return migration, nil
+}
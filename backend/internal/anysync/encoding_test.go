@@ -0,0 +1,124 @@
+package anysync
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestObjectPayloadEncoding(t *testing.T) {
+	obj := &ObjectPayload{
+		ID:        "profile-1",
+		Type:      "SharedProfile",
+		OwnerKey:  "ab12",
+		Data:      json.RawMessage(`{"name":"Aroha","skills":["weaving"]}`),
+		Timestamp: 1767225600,
+		Version:   3,
+	}
+
+	jsonData, jsonType, err := encodeObjectPayload(obj, false)
+	if err != nil || jsonType != ObjectChangeType {
+		t.Fatalf("expected JSON object change, got %s: %v", jsonType, err)
+	}
+	compactData, compactType, err := encodeObjectPayload(obj, true)
+	if err != nil || compactType != ObjectChangeType+CompactEncodingSuffix {
+		t.Fatalf("expected compact object change, got %s: %v", compactType, err)
+	}
+	if len(compactData) >= len(jsonData) {
+		t.Errorf("expected compact encoding smaller than JSON, got %d >= %d", len(compactData), len(jsonData))
+	}
+
+	for _, tc := range []struct {
+		dataType string
+		data     []byte
+	}{{jsonType, jsonData}, {compactType, compactData}} {
+		got, err := decodeObjectPayload(tc.dataType, tc.data)
+		if err != nil {
+			t.Fatalf("decoding %s: %v", tc.dataType, err)
+		}
+		if !reflect.DeepEqual(got, obj) {
+			t.Errorf("%s round trip: expected %+v, got %+v", tc.dataType, obj, got)
+		}
+	}
+}
+
+func TestCredentialPayloadEncoding(t *testing.T) {
+	cred := &CredentialPayload{
+		SAID:      "ESAID123",
+		Issuer:    "EIssuer",
+		Recipient: "ERecipient",
+		Schema:    "ESchema",
+		Data:      json.RawMessage(`{"role":"member"}`),
+		Timestamp: 1767225600,
+	}
+
+	data, dataType, err := encodeCredentialPayload(cred, true)
+	if err != nil || dataType != CredentialChangeType+CompactEncodingSuffix {
+		t.Fatalf("expected compact credential change, got %s: %v", dataType, err)
+	}
+	got, err := decodeCredentialPayload(dataType, data)
+	if err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+	if !reflect.DeepEqual(got, cred) {
+		t.Errorf("expected %+v, got %+v", cred, got)
+	}
+
+	if _, err := decodeCredentialPayload(dataType, []byte{0x0a, 0x05, 'E'}); err == nil {
+		t.Error("expected truncated compact credential rejected")
+	}
+}
+
+func TestCompactDecodingSkipsUnknownFields(t *testing.T) {
+	data, dataType, _ := encodeObjectPayload(&ObjectPayload{ID: "doc-1", Version: 2}, true)
+	data = protowire.AppendTag(data, 99, protowire.Fixed64Type)
+	data = protowire.AppendFixed64(data, 7)
+	data = protowire.AppendTag(data, 98, protowire.BytesType)
+	data = protowire.AppendString(data, "from a newer version")
+
+	got, err := decodeObjectPayload(dataType, data)
+	if err != nil {
+		t.Fatalf("expected unknown fields skipped, got %v", err)
+	}
+	if got.ID != "doc-1" || got.Version != 2 {
+		t.Errorf("expected known fields decoded, got %+v", got)
+	}
+}
+
+func TestReencodeChange(t *testing.T) {
+	obj := &ObjectPayload{ID: "doc-1", Type: DocumentType, Data: json.RawMessage(`{"a":1}`), Version: 1}
+	jsonData, _, _ := encodeObjectPayload(obj, false)
+
+	data, dataType, err := reencodeChange(ObjectChangeType, jsonData, true)
+	if err != nil || dataType != ObjectChangeType+CompactEncodingSuffix {
+		t.Fatalf("expected object converted to compact, got %s: %v", dataType, err)
+	}
+	back, backType, err := reencodeChange(dataType, data, false)
+	if err != nil || backType != ObjectChangeType {
+		t.Fatalf("expected object converted back to JSON, got %s: %v", backType, err)
+	}
+	if got, _ := decodeObjectPayload(backType, back); !reflect.DeepEqual(got, obj) {
+		t.Errorf("expected %+v after both conversions, got %+v", obj, got)
+	}
+
+	receipt := []byte(`{"said":"E1"}`)
+	if data, dataType, _ := reencodeChange(ReceiptChangeType, receipt, true); dataType != ReceiptChangeType || string(data) != string(receipt) {
+		t.Errorf("expected receipt change left alone, got %s", dataType)
+	}
+	if _, dataType, _ := reencodeChange(ObjectChangeType, jsonData, false); dataType != ObjectChangeType {
+		t.Errorf("expected change already in the target encoding left alone, got %s", dataType)
+	}
+}
+
+func TestParseChangeEncoding(t *testing.T) {
+	for encoding, want := range map[string]bool{"": false, EncodingJSON: false, EncodingCompact: true} {
+		if got, err := ParseChangeEncoding(encoding); err != nil || got != want {
+			t.Errorf("ParseChangeEncoding(%q) = %v, %v", encoding, got, err)
+		}
+	}
+	if _, err := ParseChangeEncoding("cbor"); err == nil {
+		t.Error("expected unknown encoding rejected")
+	}
+}
@@ -6,6 +6,7 @@ package anysync
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/anyproto/any-sync/commonfile/fileblockstore"
@@ -30,9 +31,10 @@ type RemoteBlockStore struct {
 	pool     pool.Pool
 	nodeConf nodeconf.Service
 
-	mu      sync.RWMutex
-	spaceId string
-	fileId  string
+	mu        sync.RWMutex
+	spaceId   string
+	fileId    string
+	fileNodes func(spaceId string) []string
 }
 
 // NewRemoteBlockStore creates a new RemoteBlockStore.
@@ -53,6 +55,14 @@ func (s *RemoteBlockStore) SetContext(spaceId, fileId string) {
 	s.fileId = fileId
 }
 
+// SetFileNodes sets the preferred file nodes for a space, tried before the
+// network's other file nodes
+func (s *RemoteBlockStore) SetFileNodes(fileNodes func(spaceId string) []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fileNodes = fileNodes
+}
+
 // getSpaceId returns the current spaceId, preferring the context value if set,
 // falling back to the stored field.
 func (s *RemoteBlockStore) getSpaceId(ctx context.Context) string {
@@ -75,13 +85,39 @@ func (s *RemoteBlockStore) getFileId(ctx context.Context) string {
 	return s.fileId
 }
 
-// getFilePeer returns a connected peer from the configured file nodes.
+// getFilePeer returns a connected peer from the configured file nodes,
+// trying the space's preferred file nodes first.
 func (s *RemoteBlockStore) getFilePeer(ctx context.Context) (peer.Peer, error) {
-	filePeers := s.nodeConf.FilePeers()
+	spaceId := s.getSpaceId(ctx)
+	s.mu.RLock()
+	fileNodes := s.fileNodes
+	s.mu.RUnlock()
+	var preferred []string
+	if fileNodes != nil {
+		preferred = fileNodes(spaceId)
+	}
+	return connectFilePeer(ctx, s.pool, s.nodeConf, preferred)
+}
+
+// connectFilePeer connects to one of the network's file nodes. Preferred
+// nodes are tried first, falling back to any file node if none connect.
+func connectFilePeer(ctx context.Context, p pool.Pool, nc nodeconf.Service, preferred []string) (peer.Peer, error) {
+	filePeers := nc.FilePeers()
 	if len(filePeers) == 0 {
 		return nil, fmt.Errorf("no file peers configured")
 	}
-	return s.pool.GetOneOf(ctx, filePeers)
+	var first []string
+	for _, id := range preferred {
+		if slices.Contains(filePeers, id) {
+			first = append(first, id)
+		}
+	}
+	if len(first) > 0 {
+		if fp, err := p.GetOneOf(ctx, first); err == nil {
+			return fp, nil
+		}
+	}
+	return p.GetOneOf(ctx, filePeers)
 }
 
 // Get fetches a single block from the filenode by CID.
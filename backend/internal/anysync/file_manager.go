@@ -30,6 +30,13 @@ type FileMeta struct {
 	Size        int64  `json:"size"`
 	UploadedBy  string `json:"uploadedBy"`
 	UploadedAt  int64  `json:"uploadedAt"`
+	// Owner is who the upload counts against for quotas: the caller's AID
+	// or credential name, or empty for the backend's own user
+	Owner string `json:"owner,omitempty"`
+	// Rendition names the scaled copy of an image this file is, if any
+	Rendition string `json:"rendition,omitempty"`
+	// Renditions maps rendition names to the CIDs of scaled copies
+	Renditions map[string]string `json:"renditions,omitempty"`
 }
 
 // FileManager combines FileHandler + RemoteBlockStore + ObjectTreeManager
@@ -41,6 +48,8 @@ type FileManager struct {
 	objTree    *ObjectTreeManager
 	pool       pool.Pool
 	nodeConf   nodeconf.Service
+	fileNodes  func(spaceID string) []string
+	cache      *BlockCache
 }
 
 // NewFileManager creates a new FileManager.
@@ -56,6 +65,25 @@ func NewFileManager(p pool.Pool, nc nodeconf.Service, objTree *ObjectTreeManager
 	}
 }
 
+// SetFileNodes sets the preferred file nodes for a space's files, tried
+// before the network's other file nodes
+func (m *FileManager) SetFileNodes(fileNodes func(spaceID string) []string) {
+	m.fileNodes = fileNodes
+	m.blockStore.SetFileNodes(fileNodes)
+}
+
+// SetBlockCache serves file blocks from a local cache, fetching only
+// misses from the filenode. Call before the FileManager is used.
+func (m *FileManager) SetBlockCache(cache *BlockCache) {
+	m.cache = cache
+	m.handler = fileservice.NewFileHandler(&cachedBlockStore{RemoteBlockStore: m.blockStore, cache: cache})
+}
+
+// BlockCache returns the local block cache, or nil if blocks aren't cached
+func (m *FileManager) BlockCache() *BlockCache {
+	return m.cache
+}
+
 // AddFile uploads a file to the filenode and records metadata in the ObjectTree.
 //
 // Flow:
@@ -67,6 +95,12 @@ func NewFileManager(p pool.Pool, nc nodeconf.Service, objTree *ObjectTreeManager
 //  5. FileMeta is written as an ObjectPayload into the community space's ObjectTree
 //  6. Returns the root CID string as the file reference
 func (m *FileManager) AddFile(ctx context.Context, spaceID string, reader io.Reader, contentType string, size int64, signingKey crypto.PrivKey) (string, error) {
+	return m.AddFileWithMeta(ctx, spaceID, reader, &FileMeta{ContentType: contentType, Size: size}, signingKey)
+}
+
+// AddFileWithMeta uploads a file like AddFile, recording the given
+// metadata. The CID, uploader and upload time are filled in.
+func (m *FileManager) AddFileWithMeta(ctx context.Context, spaceID string, reader io.Reader, meta *FileMeta, signingKey crypto.PrivKey) (string, error) {
 	fileId := uuid.New().String()
 
 	// Set spaceId and fileId on the blockstore directly — the IPFS DAG builder
@@ -86,14 +120,16 @@ func (m *FileManager) AddFile(ctx context.Context, spaceID string, reader io.Rea
 		return "", fmt.Errorf("binding blocks: %w", err)
 	}
 
-	// Write file metadata to the ObjectTree for P2P sync
+	return m.recordFile(ctx, spaceID, rootCID, meta, signingKey)
+}
+
+// recordFile writes a stored file's metadata to the ObjectTree for P2P
+// sync, filling in the CID, uploader and upload time. Returns the CID
+// string as the file reference.
+func (m *FileManager) recordFile(ctx context.Context, spaceID string, rootCID cid.Cid, meta *FileMeta, signingKey crypto.PrivKey) (string, error) {
 	cidStr := rootCID.String()
-	meta := &FileMeta{
-		CID:         cidStr,
-		ContentType: contentType,
-		Size:        size,
-		UploadedAt:  time.Now().Unix(),
-	}
+	meta.CID = cidStr
+	meta.UploadedAt = time.Now().Unix()
 	if signingKey != nil {
 		meta.UploadedBy = signingKey.GetPublic().Account()
 	}
@@ -126,12 +162,11 @@ func (m *FileManager) AddFile(ctx context.Context, spaceID string, reader io.Rea
 // its DAG children) with the fileId. We collect all DAG node CIDs by walking
 // the DAG service.
 func (m *FileManager) bindBlocks(ctx context.Context, spaceID, fileId string, rootCID cid.Cid) error {
-	filePeers := m.nodeConf.FilePeers()
-	if len(filePeers) == 0 {
-		return fmt.Errorf("no file peers configured")
+	var preferred []string
+	if m.fileNodes != nil {
+		preferred = m.fileNodes(spaceID)
 	}
-
-	p, err := m.pool.GetOneOf(ctx, filePeers)
+	p, err := connectFilePeer(ctx, m.pool, m.nodeConf, preferred)
 	if err != nil {
 		return fmt.Errorf("getting file peer: %w", err)
 	}
@@ -147,6 +182,52 @@ func (m *FileManager) bindBlocks(ctx context.Context, spaceID, fileId string, ro
 	})
 }
 
+// UnbindSpaceFiles deletes every file bound to a space on the filenode, so
+// the blocks only that space referenced can be reclaimed. Returns how many
+// files were unbound.
+func (m *FileManager) UnbindSpaceFiles(ctx context.Context, spaceID string) (int, error) {
+	var preferred []string
+	if m.fileNodes != nil {
+		preferred = m.fileNodes(spaceID)
+	}
+	p, err := connectFilePeer(ctx, m.pool, m.nodeConf, preferred)
+	if err != nil {
+		return 0, fmt.Errorf("getting file peer: %w", err)
+	}
+
+	var unbound int
+	err = p.DoDrpc(ctx, func(conn drpc.Conn) error {
+		client := fileproto.NewDRPCFileClient(conn)
+		stream, err := client.FilesGet(ctx, &fileproto.FilesGetRequest{SpaceId: spaceID})
+		if err != nil {
+			return err
+		}
+		var fileIDs []string
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			fileIDs = append(fileIDs, resp.FileId)
+		}
+		if len(fileIDs) == 0 {
+			return nil
+		}
+		if _, err := client.FilesDelete(ctx, &fileproto.FilesDeleteRequest{
+			SpaceId: spaceID,
+			FileIds: fileIDs,
+		}); err != nil {
+			return err
+		}
+		unbound = len(fileIDs)
+		return nil
+	})
+	return unbound, err
+}
+
 // GetFile downloads a file from the filenode by CID and returns a reader
 // along with the content type from the ObjectTree metadata.
 func (m *FileManager) GetFile(ctx context.Context, spaceID string, fileRef string) (io.ReadSeekCloser, string, error) {
@@ -188,3 +269,33 @@ func (m *FileManager) GetFileMeta(ctx context.Context, spaceID string, fileRef s
 	}
 	return &meta, nil
 }
+
+// Usage returns the total size of the files in a space that count against
+// an owner's quota: those with the given owner uploaded under the given
+// account key. Each CID is counted once.
+func (m *FileManager) Usage(ctx context.Context, spaceID string, owner string, uploadedBy string) (int64, error) {
+	if !m.objTree.HasObjectTree(ctx, spaceID) {
+		return 0, nil
+	}
+	objects, err := m.objTree.ReadObjectsByType(ctx, spaceID, FileMetaObjectType)
+	if err != nil {
+		return 0, fmt.Errorf("reading file metadata: %w", err)
+	}
+
+	var total int64
+	counted := make(map[string]bool)
+	for _, obj := range objects {
+		if counted[obj.ID] {
+			continue
+		}
+		var meta FileMeta
+		if err := json.Unmarshal(obj.Data, &meta); err != nil {
+			continue
+		}
+		if meta.Owner == owner && meta.UploadedBy == uploadedBy {
+			counted[obj.ID] = true
+			total += meta.Size
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,215 @@
+// Package anysync provides any-sync integration for MATOU.
+// file_upload.go stores a file uploaded in parts. Each part is pushed to the
+// filenode as it arrives, so an interrupted upload resumes from the last
+// stored part, and the file's DAG is built over the parts when it completes.
+package anysync
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anyproto/any-sync/commonfile/fileblockstore"
+	"github.com/anyproto/any-sync/commonfile/fileproto"
+	"github.com/anyproto/any-sync/commonfile/fileservice"
+	"github.com/anyproto/any-sync/util/crypto"
+	"github.com/google/uuid"
+	dag "github.com/ipfs/boxo/ipld/merkledag"
+	ft "github.com/ipfs/boxo/ipld/unixfs"
+	"github.com/ipfs/boxo/ipld/unixfs/importer/helpers"
+	unixfspb "github.com/ipfs/boxo/ipld/unixfs/pb"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/multiformats/go-multihash"
+	"storj.io/drpc"
+)
+
+// UploadChunkSize is the size of the blocks a chunked upload is stored in,
+// the same as files added in one piece. Every part but the last must be a
+// multiple of it.
+const UploadChunkSize = fileservice.ChunkSize
+
+// filePrefix is the CID prefix of file DAG nodes, as FileHandler uses
+var filePrefix = cid.Prefix{
+	Version:  1,
+	Codec:    cid.DagProtobuf,
+	MhType:   multihash.SHA2_256,
+	MhLength: -1,
+}
+
+// ChunkedUpload is a file being uploaded in parts. Only the list of stored
+// blocks is kept; the data is on the filenode. It is not safe for
+// concurrent use.
+type ChunkedUpload struct {
+	SpaceID string
+	FileID  string
+	// Received is the number of bytes stored so far
+	Received int64
+
+	leaves []fileBlock
+}
+
+// fileBlock is a stored node of a file DAG
+type fileBlock struct {
+	cid      cid.Cid
+	size     uint64 // encoded size of the node and its children, for links to it
+	fileSize uint64 // file bytes under it
+}
+
+// NewChunkedUpload starts a chunked upload to a space
+func (m *FileManager) NewChunkedUpload(spaceID string) *ChunkedUpload {
+	return &ChunkedUpload{SpaceID: spaceID, FileID: uuid.New().String()}
+}
+
+// AppendChunk stores the next part of an upload, split into
+// UploadChunkSize blocks. Once a part that isn't a multiple of
+// UploadChunkSize is stored the upload is complete, and further parts are
+// rejected.
+func (m *FileManager) AppendChunk(ctx context.Context, u *ChunkedUpload, data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("empty chunk")
+	}
+	if n := len(u.leaves); n > 0 && u.leaves[n-1].fileSize < UploadChunkSize {
+		return fmt.Errorf("upload already has its final chunk")
+	}
+
+	var nodes []blocks.Block
+	var leaves []fileBlock
+	for start := 0; start < len(data); start += UploadChunkSize {
+		chunk := data[start:min(start+UploadChunkSize, len(data))]
+		// The balanced layout stores the first block as a file and the
+		// rest as raw data
+		dataType := ft.TRaw
+		if len(u.leaves) == 0 && start == 0 {
+			dataType = ft.TFile
+		}
+		node, err := fileNode(dataType, chunk, nil)
+		if err != nil {
+			return err
+		}
+		nodes = append(nodes, node)
+		leaves = append(leaves, fileBlock{cid: node.Cid(), size: uint64(len(node.RawData())), fileSize: uint64(len(chunk))})
+	}
+
+	if err := m.blockStore.Add(uploadContext(ctx, u), nodes); err != nil {
+		return fmt.Errorf("storing chunk: %w", err)
+	}
+	u.leaves = append(u.leaves, leaves...)
+	u.Received += int64(len(data))
+	return nil
+}
+
+// CompleteChunkedUpload builds the file's DAG over the stored blocks,
+// binds it to the upload and records the metadata, like AddFileWithMeta.
+// Returns the root CID string as the file reference.
+func (m *FileManager) CompleteChunkedUpload(ctx context.Context, u *ChunkedUpload, meta *FileMeta, signingKey crypto.PrivKey) (string, error) {
+	if len(u.leaves) == 0 {
+		return "", fmt.Errorf("upload has no data")
+	}
+
+	root, nodes, err := buildFileDAG(u.leaves, helpers.DefaultLinksPerBlock)
+	if err != nil {
+		return "", fmt.Errorf("building file DAG: %w", err)
+	}
+	if len(nodes) > 0 {
+		if err := m.blockStore.Add(uploadContext(ctx, u), nodes); err != nil {
+			return "", fmt.Errorf("storing file DAG: %w", err)
+		}
+	}
+
+	if err := m.bindBlocks(ctx, u.SpaceID, u.FileID, root); err != nil {
+		return "", fmt.Errorf("binding blocks: %w", err)
+	}
+
+	meta.Size = u.Received
+	return m.recordFile(ctx, u.SpaceID, root, meta, signingKey)
+}
+
+// AbortChunkedUpload deletes the blocks stored for an upload from the
+// filenode
+func (m *FileManager) AbortChunkedUpload(ctx context.Context, u *ChunkedUpload) error {
+	if len(u.leaves) == 0 {
+		return nil
+	}
+	var preferred []string
+	if m.fileNodes != nil {
+		preferred = m.fileNodes(u.SpaceID)
+	}
+	p, err := connectFilePeer(ctx, m.pool, m.nodeConf, preferred)
+	if err != nil {
+		return fmt.Errorf("getting file peer: %w", err)
+	}
+
+	return p.DoDrpc(ctx, func(conn drpc.Conn) error {
+		client := fileproto.NewDRPCFileClient(conn)
+		_, err := client.FilesDelete(ctx, &fileproto.FilesDeleteRequest{
+			SpaceId: u.SpaceID,
+			FileIds: []string{u.FileID},
+		})
+		return err
+	})
+}
+
+// uploadContext carries an upload's space and file IDs to the blockstore.
+// Context values are used rather than SetContext so concurrent uploads
+// don't share them.
+func uploadContext(ctx context.Context, u *ChunkedUpload) context.Context {
+	ctx = fileblockstore.CtxWithSpaceId(ctx, u.SpaceID)
+	return fileblockstore.CtxWithFileId(ctx, u.FileID)
+}
+
+// fileNode creates a UnixFS node holding data, or linking to children when
+// data is nil
+func fileNode(dataType unixfspb.Data_DataType, data []byte, children []fileBlock) (*dag.ProtoNode, error) {
+	node := new(dag.ProtoNode)
+	if err := node.SetCidBuilder(filePrefix); err != nil {
+		return nil, err
+	}
+	fsNode := ft.NewFSNode(dataType)
+	if data != nil {
+		fsNode.SetData(data)
+	}
+	for _, child := range children {
+		if err := node.AddRawLink("", &ipld.Link{Cid: child.cid, Size: child.size}); err != nil {
+			return nil, err
+		}
+		fsNode.AddBlockSize(child.fileSize)
+	}
+	fsData, err := fsNode.GetBytes()
+	if err != nil {
+		return nil, err
+	}
+	node.SetData(fsData)
+	return node, nil
+}
+
+// buildFileDAG lays out a file's blocks like FileHandler.AddFile's balanced
+// layout: up to maxLinks children per node, adding levels until one node is
+// left. Returns the root and the new nodes to store; a file of one block is
+// its own root.
+func buildFileDAG(leaves []fileBlock, maxLinks int) (cid.Cid, []blocks.Block, error) {
+	level := leaves
+	var nodes []blocks.Block
+	for len(level) > 1 {
+		var next []fileBlock
+		for start := 0; start < len(level); start += maxLinks {
+			children := level[start:min(start+maxLinks, len(level))]
+			node, err := fileNode(ft.TFile, nil, children)
+			if err != nil {
+				return cid.Undef, nil, err
+			}
+			size, err := node.Size()
+			if err != nil {
+				return cid.Undef, nil, err
+			}
+			var fileSize uint64
+			for _, child := range children {
+				fileSize += child.fileSize
+			}
+			nodes = append(nodes, node)
+			next = append(next, fileBlock{cid: node.Cid(), size: size, fileSize: fileSize})
+		}
+		level = next
+	}
+	return level[0].cid, nodes, nil
+}
@@ -0,0 +1,111 @@
+package anysync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/anyproto/any-sync/commonfile/fileservice"
+	ft "github.com/ipfs/boxo/ipld/unixfs"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+)
+
+// memBlockStore is an in-memory fileblockstore.BlockStore
+type memBlockStore map[cid.Cid]blocks.Block
+
+func (s memBlockStore) Get(ctx context.Context, k cid.Cid) (blocks.Block, error) {
+	b, ok := s[k]
+	if !ok {
+		return nil, fmt.Errorf("block not found: %s", k)
+	}
+	return b, nil
+}
+
+func (s memBlockStore) GetMany(ctx context.Context, ks []cid.Cid) <-chan blocks.Block {
+	ch := make(chan blocks.Block, len(ks))
+	for _, k := range ks {
+		if b, ok := s[k]; ok {
+			ch <- b
+		}
+	}
+	close(ch)
+	return ch
+}
+
+func (s memBlockStore) Add(ctx context.Context, bs []blocks.Block) error {
+	for _, b := range bs {
+		s[b.Cid()] = b
+	}
+	return nil
+}
+
+func (s memBlockStore) Delete(ctx context.Context, c cid.Cid) error {
+	delete(s, c)
+	return nil
+}
+
+// storeLeaves stores data as file blocks the way AppendChunk does
+func storeLeaves(t *testing.T, store memBlockStore, data []byte) []fileBlock {
+	t.Helper()
+	var leaves []fileBlock
+	for start := 0; start < len(data); start += UploadChunkSize {
+		chunk := data[start:min(start+UploadChunkSize, len(data))]
+		dataType := ft.TRaw
+		if start == 0 {
+			dataType = ft.TFile
+		}
+		node, err := fileNode(dataType, chunk, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		store.Add(context.Background(), []blocks.Block{node})
+		leaves = append(leaves, fileBlock{cid: node.Cid(), size: uint64(len(node.RawData())), fileSize: uint64(len(chunk))})
+	}
+	return leaves
+}
+
+func TestBuildFileDAG(t *testing.T) {
+	data := make([]byte, 4*UploadChunkSize+1234)
+	rand.New(rand.NewSource(1)).Read(data)
+	store := memBlockStore{}
+	leaves := storeLeaves(t, store, data)
+
+	// Two links per node gives five leaves three levels of nodes
+	root, nodes, err := buildFileDAG(leaves, 2)
+	if err != nil {
+		t.Fatalf("buildFileDAG: %v", err)
+	}
+	if len(nodes) != 6 {
+		t.Errorf("expected 3+2+1 nodes over 5 leaves, got %d", len(nodes))
+	}
+	store.Add(context.Background(), nodes)
+
+	reader, err := fileservice.NewFileHandler(store).GetFile(context.Background(), root)
+	if err != nil {
+		t.Fatalf("GetFile: %v", err)
+	}
+	got, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("reading file: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("expected the %d bytes uploaded, read %d", len(data), len(got))
+	}
+}
+
+func TestBuildFileDAGSingleBlock(t *testing.T) {
+	store := memBlockStore{}
+	leaves := storeLeaves(t, store, []byte("small file"))
+
+	root, nodes, err := buildFileDAG(leaves, 174)
+	if err != nil {
+		t.Fatalf("buildFileDAG: %v", err)
+	}
+	if root != leaves[0].cid || len(nodes) != 0 {
+		t.Errorf("expected a one-block file to be its own root, got %s and %d nodes", root, len(nodes))
+	}
+}
@@ -0,0 +1,173 @@
+// Package anysync provides any-sync integration for MATOU.
+// inbox.go implements per-member inbox spaces. Each member derives an inbox
+// space from their mnemonic and publishes its address (space ID, a write
+// invite and an encryption key) in the community space. The org and other
+// members join the inbox as writers and deposit items sealed to the
+// recipient's encryption key, so items wait on the sync nodes until the
+// recipient's backend comes online and drains them.
+package anysync
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+	"github.com/anyproto/any-sync/util/crypto"
+
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+// InboxKeyIndex is the mnemonic derivation index for a member's inbox space
+// (0 = private, 1 = community, 2 = community-readonly, 3 = admin)
+const InboxKeyIndex = 4
+
+// Object types used for inboxes
+const (
+	// InboxAddressType is the community space object publishing a member's inbox
+	InboxAddressType = "InboxAddress"
+	// InboxItemType is an item deposited in a member's inbox space
+	InboxItemType = "InboxItem"
+)
+
+// Inbox item kinds
+const (
+	InboxKindCredential   = "credential"
+	InboxKindNotification = "notification"
+	InboxKindMessage      = "message"
+)
+
+// IsInboxKind reports whether kind is a known inbox item kind
+func IsInboxKind(kind string) bool {
+	switch kind {
+	case InboxKindCredential, InboxKindNotification, InboxKindMessage:
+		return true
+	}
+	return false
+}
+
+// InboxAddressID returns the community space object ID of a member's inbox address
+func InboxAddressID(aid string) string {
+	return "InboxAddress-" + aid
+}
+
+// InboxAddress tells senders where and how to deliver to a member's inbox
+type InboxAddress struct {
+	AID           string `json:"aid"`
+	SpaceID       string `json:"spaceId"`
+	InviteKey     string `json:"inviteKey"`     // base64-encoded write invite private key
+	EncryptionKey string `json:"encryptionKey"` // account address items are sealed to
+	UpdatedAt     string `json:"updatedAt"`
+}
+
+// InboxItem is an item deposited in a member's inbox. Only the recipient's
+// inbox key can open Sealed; any writer of the inbox can see the envelope.
+type InboxItem struct {
+	RecipientAID string `json:"recipientAid"`
+	SenderAID    string `json:"senderAid"`
+	Kind         string `json:"kind"`
+	Sealed       []byte `json:"sealed"`
+	CreatedAt    string `json:"createdAt"`
+}
+
+// DeriveInboxKey derives the key inbox items are sealed to. It is the
+// signing key of the inbox key set, which is otherwise unused because the
+// inbox space is signed with the peer key so its owner can issue invites.
+func DeriveInboxKey(mnemonic *secret.Mnemonic) (crypto.PrivKey, error) {
+	keys, err := DeriveSpaceKeySetFromSecret(mnemonic, InboxKeyIndex)
+	if err != nil {
+		return nil, fmt.Errorf("deriving inbox keys: %w", err)
+	}
+	return keys.SigningKey, nil
+}
+
+// SealInboxItem encrypts an item payload to a recipient's inbox encryption
+// key, given as an account address
+func SealInboxItem(encryptionKey string, payload []byte) ([]byte, error) {
+	key, err := crypto.DecodeAccountAddress(encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inbox encryption key: %w", err)
+	}
+	sealed, err := key.Encrypt(payload)
+	if err != nil {
+		return nil, fmt.Errorf("sealing inbox item: %w", err)
+	}
+	return sealed, nil
+}
+
+// OpenInboxItem decrypts an item sealed with SealInboxItem
+func OpenInboxItem(key crypto.PrivKey, sealed []byte) ([]byte, error) {
+	payload, err := key.Decrypt(sealed)
+	if err != nil {
+		return nil, fmt.Errorf("opening inbox item: %w", err)
+	}
+	return payload, nil
+}
+
+// CreateInboxSpace creates a member's inbox space from mnemonic-derived keys
+// and a write invite for senders. The peer key signs the space so this
+// backend can issue the invite. Returns the space and the invite key.
+func (m *SpaceManager) CreateInboxSpace(ctx context.Context, ownerAID string, mnemonic *secret.Mnemonic) (*Space, crypto.PrivKey, error) {
+	if ownerAID == "" {
+		return nil, nil, fmt.Errorf("owner AID is required")
+	}
+	if m.client == nil {
+		return nil, nil, fmt.Errorf("any-sync client not available")
+	}
+
+	keys, err := DeriveSpaceKeySetFromSecret(mnemonic, InboxKeyIndex)
+	if err != nil {
+		return nil, nil, fmt.Errorf("deriving inbox space keys: %w", err)
+	}
+	keys.SigningKey = m.client.GetSigningKey()
+
+	result, err := m.client.CreateSpaceWithKeys(ctx, ownerAID, SpaceTypeInbox, keys)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating inbox space: %w", err)
+	}
+
+	// Required before CreateOpenInvite can be used for senders
+	if err := m.client.MakeSpaceShareable(ctx, result.SpaceID); err != nil {
+		fmt.Printf("Warning: failed to make inbox space shareable: %v\n", err)
+	}
+	inviteKey, err := m.aclManager.CreateOpenInvite(ctx, result.SpaceID, list.AclPermissionsWriter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating inbox invite: %w", err)
+	}
+
+	if err := PersistSpaceKeySet(m.client.GetDataDir(), result.SpaceID, keys); err != nil {
+		return nil, nil, fmt.Errorf("persisting inbox space keys: %w", err)
+	}
+
+	return &Space{
+		SpaceID:   result.SpaceID,
+		OwnerAID:  ownerAID,
+		SpaceType: SpaceTypeInbox,
+		SpaceName: fmt.Sprintf("Inbox - %s", ownerAID),
+		CreatedAt: result.CreatedAt,
+		LastSync:  result.CreatedAt,
+	}, inviteKey, nil
+}
+
+// JoinInboxSpace joins another member's inbox as a writer with the invite
+// key from their inbox address, unless this backend already holds keys for it
+func (m *SpaceManager) JoinInboxSpace(ctx context.Context, spaceID string, inviteKey crypto.PrivKey, metadata []byte) error {
+	if m.client == nil {
+		return fmt.Errorf("any-sync client not available")
+	}
+	if _, err := LoadSpaceKeySet(m.client.GetDataDir(), spaceID); err == nil {
+		return nil
+	}
+	if err := m.aclManager.JoinWithInvite(ctx, spaceID, inviteKey, metadata); err != nil {
+		return fmt.Errorf("joining inbox space: %w", err)
+	}
+
+	keys, err := GenerateSpaceKeySet()
+	if err != nil {
+		return fmt.Errorf("generating inbox space keys: %w", err)
+	}
+	keys.SigningKey = m.client.GetSigningKey()
+	if err := PersistSpaceKeySet(m.client.GetDataDir(), spaceID, keys); err != nil {
+		return fmt.Errorf("persisting inbox space keys: %w", err)
+	}
+	return nil
+}
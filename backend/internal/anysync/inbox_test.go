@@ -0,0 +1,65 @@
+package anysync
+
+import (
+	"testing"
+
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+func TestDeriveInboxKey_Deterministic(t *testing.T) {
+	mnemonic := secret.NewMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about")
+
+	first, err := DeriveInboxKey(mnemonic)
+	if err != nil {
+		t.Fatalf("DeriveInboxKey failed: %v", err)
+	}
+	second, err := DeriveInboxKey(mnemonic)
+	if err != nil {
+		t.Fatalf("DeriveInboxKey failed: %v", err)
+	}
+	if first.GetPublic().Account() != second.GetPublic().Account() {
+		t.Error("expected the same inbox key from the same mnemonic")
+	}
+
+	private, _ := DeriveSpaceKeySetFromSecret(mnemonic, 0)
+	if private.SigningKey.GetPublic().Account() == first.GetPublic().Account() {
+		t.Error("inbox key should differ from the private space key")
+	}
+
+	if _, err := DeriveInboxKey(nil); err == nil {
+		t.Error("expected error for empty mnemonic")
+	}
+}
+
+func TestSealInboxItem_RoundTrip(t *testing.T) {
+	key, err := DeriveInboxKey(secret.NewMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"))
+	if err != nil {
+		t.Fatalf("DeriveInboxKey failed: %v", err)
+	}
+
+	payload := []byte(`{"said":"ESAID123","schema":"EMatouMembershipSchemaV1"}`)
+	sealed, err := SealInboxItem(key.GetPublic().Account(), payload)
+	if err != nil {
+		t.Fatalf("SealInboxItem failed: %v", err)
+	}
+	if string(sealed) == string(payload) {
+		t.Fatal("expected sealed item to differ from payload")
+	}
+
+	opened, err := OpenInboxItem(key, sealed)
+	if err != nil {
+		t.Fatalf("OpenInboxItem failed: %v", err)
+	}
+	if string(opened) != string(payload) {
+		t.Errorf("expected %s, got %s", payload, opened)
+	}
+
+	other, _ := GenerateSpaceKeySet()
+	if _, err := OpenInboxItem(other.SigningKey, sealed); err == nil {
+		t.Error("expected another key to fail to open the item")
+	}
+
+	if _, err := SealInboxItem("not-an-account", payload); err == nil {
+		t.Error("expected error for invalid encryption key")
+	}
+}
@@ -235,11 +235,14 @@ func TestIntegration_SyncDocument(t *testing.T) {
 		docID := "doc_" + time.Now().Format("20060102150405")
 		data := []byte(`{"type":"credential","data":"test"}`)
 
-		err := client.SyncDocument(ctx, spaceResult.SpaceID, docID, data)
+		heads, err := client.SyncDocument(ctx, spaceResult.SpaceID, docID, data)
 		if err != nil {
 			t.Fatalf("failed to sync document: %v", err)
 		}
-		t.Logf("Synced document %s to space %s", docID, spaceResult.SpaceID)
+		if len(heads) == 0 {
+			t.Fatal("expected head change IDs")
+		}
+		t.Logf("Synced document %s to space %s (heads %v)", docID, spaceResult.SpaceID, heads)
 	})
 }
 
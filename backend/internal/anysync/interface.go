@@ -32,12 +32,13 @@ type AnySyncClient interface {
 	// DeriveSpaceID returns the deterministic space ID without creating
 	DeriveSpaceID(ctx context.Context, ownerAID string, spaceType string, signingKey crypto.PrivKey) (string, error)
 
-	// Deprecated: AddToACL builds raw JSON as a proto record which is rejected
-	// by the consensus node. Use MatouACLManager.CreateOpenInvite/JoinWithInvite instead.
+	// AddToACL grants a peer (identified by its peer ID or account address)
+	// permissions in a space's ACL. The caller must be a space admin or owner.
 	AddToACL(ctx context.Context, spaceID string, peerID string, permissions []string) error
 
-	// SyncDocument syncs a document to a space
-	SyncDocument(ctx context.Context, spaceID string, docID string, data []byte) error
+	// SyncDocument writes a JSON document to a space's ObjectTree and
+	// returns the tree's new head change IDs
+	SyncDocument(ctx context.Context, spaceID string, docID string, data []byte) ([]string, error)
 
 	// GetNetworkID returns the any-sync network ID
 	GetNetworkID() string
@@ -9,24 +9,98 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/anyproto/any-sync/util/crypto"
+
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+// keySealer encrypts key bundles at rest. Nil leaves them in plaintext.
+var (
+	keySealerMu sync.RWMutex
+	keySealer   *secret.Sealer
 )
 
-// writeJSONFile marshals v to JSON and writes it to path
+// SetKeySealer makes PersistSpaceKeySet encrypt key bundles with sealer.
+// Bundles are process-wide files, so this is set once at startup.
+func SetKeySealer(sealer *secret.Sealer) {
+	keySealerMu.Lock()
+	defer keySealerMu.Unlock()
+	keySealer = sealer
+}
+
+func getKeySealer() *secret.Sealer {
+	keySealerMu.RLock()
+	defer keySealerMu.RUnlock()
+	return keySealer
+}
+
+// writeJSONFile marshals v to JSON, seals it if a key sealer is set, and
+// writes it to path
 func writeJSONFile(path string, v interface{}) error {
 	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshaling JSON: %w", err)
 	}
-	return os.WriteFile(path, data, 0644)
+	if data, err = getKeySealer().Seal(data); err != nil {
+		return fmt.Errorf("encrypting: %w", err)
+	}
+	return os.WriteFile(path, data, 0600)
 }
 
-// parseJSONFile unmarshals JSON data into v
+// parseJSONFile opens sealed data and unmarshals the JSON into v
 func parseJSONFile(data []byte, v interface{}) error {
+	data, err := getKeySealer().Open(data)
+	if err != nil {
+		return err
+	}
 	return json.Unmarshal(data, v)
 }
 
+// SealSpaceKeySets encrypts any plaintext key bundles under
+// {dataDir}/keys with the key sealer, returning how many were rewritten.
+// It does nothing when no sealer is set.
+func SealSpaceKeySets(dataDir string) (int, error) {
+	sealer := getKeySealer()
+	if !sealer.Enabled() {
+		return 0, nil
+	}
+	keysDir := filepath.Join(dataDir, "keys")
+	entries, err := os.ReadDir(keysDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("reading keys directory: %w", err)
+	}
+
+	sealed := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".keys") {
+			continue
+		}
+		path := filepath.Join(keysDir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return sealed, fmt.Errorf("reading %s: %w", entry.Name(), err)
+		}
+		if secret.IsSealed(data) {
+			continue
+		}
+		var bundle spaceKeyBundle
+		if err := json.Unmarshal(data, &bundle); err != nil {
+			return sealed, fmt.Errorf("parsing %s: %w", entry.Name(), err)
+		}
+		if err := writeJSONFile(path, bundle); err != nil {
+			return sealed, fmt.Errorf("sealing %s: %w", entry.Name(), err)
+		}
+		sealed++
+	}
+	return sealed, nil
+}
+
 // SpaceKeySet holds the four keys required by any-sync for space creation.
 type SpaceKeySet struct {
 	// SigningKey signs the space header and ACL root (Ed25519)
@@ -112,6 +186,14 @@ func DeriveSpaceKeySet(mnemonic string, spaceIndex uint32) (*SpaceKeySet, error)
 	}, nil
 }
 
+// DeriveSpaceKeySetFromSecret is DeriveSpaceKeySet for a secret.Mnemonic
+func DeriveSpaceKeySetFromSecret(mnemonic *secret.Mnemonic, spaceIndex uint32) (*SpaceKeySet, error) {
+	if mnemonic.IsEmpty() {
+		return nil, fmt.Errorf("mnemonic is empty")
+	}
+	return DeriveSpaceKeySet(mnemonic.Reveal(), spaceIndex)
+}
+
 // spaceKeyBundle is the on-disk format for a persisted SpaceKeySet.
 type spaceKeyBundle struct {
 	SigningKey   []byte `json:"signingKey"`
@@ -210,3 +292,13 @@ func LoadSpaceKeySet(dataDir, spaceID string) (*SpaceKeySet, error) {
 		MetadataKey:  metadataKey,
 	}, nil
 }
+
+// RemoveSpaceKeySet deletes {dataDir}/keys/{spaceID}.keys. A missing file is
+// not an error.
+func RemoveSpaceKeySet(dataDir, spaceID string) error {
+	keyPath := filepath.Join(dataDir, "keys", spaceID+".keys")
+	if err := os.Remove(keyPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing key file: %w", err)
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/matou-dao/backend/internal/secret"
 )
 
 func TestGenerateSpaceKeySet(t *testing.T) {
@@ -186,6 +188,46 @@ func TestPersistAndLoadSpaceKeySet(t *testing.T) {
 	}
 }
 
+func TestSealSpaceKeySets_MigratesPlaintext(t *testing.T) {
+	tmpDir := t.TempDir()
+	original, err := GenerateSpaceKeySet()
+	if err != nil {
+		t.Fatalf("GenerateSpaceKeySet failed: %v", err)
+	}
+	if err := PersistSpaceKeySet(tmpDir, "plain-space", original); err != nil {
+		t.Fatalf("PersistSpaceKeySet failed: %v", err)
+	}
+
+	sealer, _ := secret.NewSealer("passphrase")
+	SetKeySealer(sealer)
+	defer SetKeySealer(nil)
+
+	sealed, err := SealSpaceKeySets(tmpDir)
+	if err != nil || sealed != 1 {
+		t.Fatalf("expected 1 bundle sealed, got %d, %v", sealed, err)
+	}
+	data, _ := os.ReadFile(filepath.Join(tmpDir, "keys", "plain-space.keys"))
+	if !secret.IsSealed(data) {
+		t.Fatal("expected key bundle encrypted on disk")
+	}
+	if sealed, _ := SealSpaceKeySets(tmpDir); sealed != 0 {
+		t.Errorf("expected sealed bundles left alone, got %d rewritten", sealed)
+	}
+
+	loaded, err := LoadSpaceKeySet(tmpDir, "plain-space")
+	if err != nil {
+		t.Fatalf("LoadSpaceKeySet failed: %v", err)
+	}
+	if loaded.SigningKey.GetPublic().PeerId() != original.SigningKey.GetPublic().PeerId() {
+		t.Error("signing key mismatch after sealing")
+	}
+
+	SetKeySealer(nil)
+	if _, err := LoadSpaceKeySet(tmpDir, "plain-space"); err == nil {
+		t.Error("expected sealed bundle to fail to load without a passphrase")
+	}
+}
+
 func TestLoadSpaceKeySet_NotFound(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "keys_test_*")
 	if err != nil {
@@ -0,0 +1,71 @@
+package anysync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anyproto/any-sync/app/ldiff"
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/anyproto/any-sync/net/rpc/rpcerr"
+	"github.com/cespare/xxhash"
+	"storj.io/drpc"
+)
+
+// ResponsibleNodes returns the peer IDs of the tree nodes responsible for a space.
+func ResponsibleNodes(client AnySyncClient, spaceID string) []string {
+	nodeConf := client.GetNodeConf()
+	if nodeConf == nil {
+		return nil
+	}
+	return nodeConf.NodeIds(spaceID)
+}
+
+// NodeHasHeads reports whether the node with peerID holds treeID at exactly
+// heads. It asks the node for the single HeadSync range containing the tree,
+// so the node answers from its diff without sending any tree content.
+// A node that has not received the space yet reports false, not an error.
+func NodeHasHeads(ctx context.Context, client AnySyncClient, peerID, spaceID, treeID string, heads []string) (bool, error) {
+	nodePool := client.GetPool()
+	if nodePool == nil {
+		return false, fmt.Errorf("connection pool not available")
+	}
+	p, err := nodePool.Get(ctx, peerID)
+	if err != nil {
+		return false, fmt.Errorf("connecting to node %s: %w", peerID, err)
+	}
+
+	// ldiff positions elements by the xxhash of their ID
+	pos := xxhash.Sum64String(treeID)
+	var resp *spacesyncproto.HeadSyncResponse
+	err = p.DoDrpc(ctx, func(conn drpc.Conn) error {
+		var rpcErr error
+		resp, rpcErr = spacesyncproto.NewDRPCSpaceSyncClient(conn).HeadSync(ctx, &spacesyncproto.HeadSyncRequest{
+			SpaceId:  spaceID,
+			DiffType: spacesyncproto.DiffType_V3,
+			Ranges:   []*spacesyncproto.HeadSyncRange{{From: pos, To: pos, Elements: true}},
+		})
+		return rpcErr
+	})
+	if err != nil {
+		if errors.Is(rpcerr.Unwrap(err), spacesyncproto.ErrSpaceMissing) {
+			return false, nil
+		}
+		return false, fmt.Errorf("head sync with node %s: %w", peerID, err)
+	}
+
+	// The V3 diff stores the hash of the concatenated heads
+	hasher := ldiff.NewHasher()
+	defer ldiff.ReleaseHasher(hasher)
+	want := hasher.HashId(strings.Join(heads, ""))
+
+	for _, result := range resp.Results {
+		for _, el := range result.Elements {
+			if el.Id == treeID {
+				return el.Head == want, nil
+			}
+		}
+	}
+	return false, nil
+}
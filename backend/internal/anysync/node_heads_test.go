@@ -0,0 +1,101 @@
+package anysync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/anyproto/any-sync/app/ldiff"
+	"github.com/anyproto/any-sync/commonspace/headsync"
+	"github.com/anyproto/any-sync/commonspace/spacesyncproto"
+	"github.com/anyproto/any-sync/net/pool"
+	"storj.io/drpc"
+)
+
+// poolClient is a mock client with a connection pool
+type poolClient struct {
+	*mockAnySyncClient
+	pool pool.Pool
+}
+
+func (c *poolClient) GetPool() pool.Pool { return c.pool }
+
+// headSyncConn answers HeadSync requests from a node-side diff
+type headSyncConn struct {
+	drpc.Conn
+	diff     ldiff.Diff
+	spaceErr error
+}
+
+func (c *headSyncConn) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) error {
+	if c.spaceErr != nil {
+		return c.spaceErr
+	}
+	resp, err := headsync.HandleRangeRequest(ctx, c.diff, in.(*spacesyncproto.HeadSyncRequest))
+	if err != nil {
+		return err
+	}
+	out.(*spacesyncproto.HeadSyncResponse).Results = resp.Results
+	return nil
+}
+
+func newNodeHeadsClient(conn *headSyncConn) *poolClient {
+	return &poolClient{
+		mockAnySyncClient: newMockAnySyncClient(),
+		pool: &mockPool{peer: &mockPeer{doFn: func(ctx context.Context, do func(drpc.Conn) error) error {
+			return do(conn)
+		}}},
+	}
+}
+
+func TestNodeHasHeads(t *testing.T) {
+	hasher := ldiff.NewHasher()
+	defer ldiff.ReleaseHasher(hasher)
+
+	diff := ldiff.New(16, 16)
+	diff.Set(
+		ldiff.Element{Id: "tree-a", Head: hasher.HashId("head-1head-2")},
+		ldiff.Element{Id: "tree-b", Head: hasher.HashId("head-9")},
+	)
+	client := newNodeHeadsClient(&headSyncConn{diff: diff})
+	ctx := context.Background()
+
+	tests := []struct {
+		treeID string
+		heads  []string
+		want   bool
+	}{
+		{"tree-a", []string{"head-1", "head-2"}, true},
+		{"tree-a", []string{"head-3"}, false}, // node is behind
+		{"tree-c", []string{"head-1"}, false}, // node doesn't have the tree
+	}
+	for _, tt := range tests {
+		got, err := NodeHasHeads(ctx, client, "node-1", "space-1", tt.treeID, tt.heads)
+		if err != nil {
+			t.Fatalf("NodeHasHeads(%s) failed: %v", tt.treeID, err)
+		}
+		if got != tt.want {
+			t.Errorf("NodeHasHeads(%s, %v) = %t, want %t", tt.treeID, tt.heads, got, tt.want)
+		}
+	}
+}
+
+func TestNodeHasHeads_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	// A node without the space hasn't received the probe yet
+	missing := newNodeHeadsClient(&headSyncConn{spaceErr: spacesyncproto.ErrSpaceMissing})
+	if got, err := NodeHasHeads(ctx, missing, "node-1", "space-1", "tree-a", []string{"head-1"}); got || err != nil {
+		t.Errorf("expected (false, nil) for missing space, got (%t, %v)", got, err)
+	}
+
+	failing := newNodeHeadsClient(&headSyncConn{spaceErr: fmt.Errorf("connection reset")})
+	if _, err := NodeHasHeads(ctx, failing, "node-1", "space-1", "tree-a", []string{"head-1"}); err == nil || !strings.Contains(err.Error(), "node-1") {
+		t.Errorf("expected node error, got %v", err)
+	}
+
+	if _, err := NodeHasHeads(ctx, newMockAnySyncClient(), "node-1", "space-1", "tree-a", nil); err == nil {
+		t.Error("expected error without a connection pool")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
@@ -19,6 +20,9 @@ import (
 // ObjectChangeType is the DataType used for generic object changes in ObjectTrees.
 const ObjectChangeType = "matou.object.v1"
 
+// DocumentType is the object type for documents written with SyncDocument.
+const DocumentType = "document"
+
 // ObjectPayload is the data stored in each ObjectTree change for a generic object.
 type ObjectPayload struct {
 	ID        string          `json:"id"`        // Unique object ID
@@ -36,6 +40,7 @@ type ObjectTreeManager struct {
 	client     AnySyncClient
 	keyManager *PeerKeyManager
 	trees      *TreeCache
+	compact    bool
 }
 
 // NewObjectTreeManager creates a new ObjectTreeManager using a shared TreeCache.
@@ -47,6 +52,11 @@ func NewObjectTreeManager(client AnySyncClient, keyManager *PeerKeyManager, cach
 	}
 }
 
+// SetCompactEncoding sets whether new object changes use the compact encoding
+func (m *ObjectTreeManager) SetCompactEncoding(compact bool) {
+	m.compact = compact
+}
+
 // AddObject adds a generic object as a signed change to the space's tree.
 // If no tree exists yet, one is created automatically.
 func (m *ObjectTreeManager) AddObject(ctx context.Context, spaceID string, payload *ObjectPayload, signingKey crypto.PrivKey) (string, error) {
@@ -55,31 +65,92 @@ func (m *ObjectTreeManager) AddObject(ctx context.Context, spaceID string, paylo
 		return "", fmt.Errorf("getting tree for space %s: %w", spaceID, err)
 	}
 
-	data, err := json.Marshal(payload)
+	tree.Lock()
+	defer tree.Unlock()
+
+	heads, err := addObjectChange(ctx, tree, payload, signingKey, m.compact)
 	if err != nil {
-		return "", fmt.Errorf("marshaling object: %w", err)
+		return "", err
+	}
+	return heads[0], nil
+}
+
+// PutDocument writes data as the next version of a document and returns the
+// tree's heads after the write. Data must be JSON.
+//
+// The next version is read and the change added under one tree lock, and the
+// change builds on every current head, so concurrent updates already merged
+// in from other peers are joined rather than forked. When two peers write the
+// same version concurrently, ReadLatestByID resolves it the same way on both.
+func (m *ObjectTreeManager) PutDocument(ctx context.Context, spaceID string, docID string, data []byte, signingKey crypto.PrivKey) ([]string, error) {
+	if docID == "" {
+		return nil, fmt.Errorf("document ID is required")
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("document %s is not valid JSON", docID)
+	}
+
+	tree, err := m.getOrCreateTree(ctx, spaceID, signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("getting tree for space %s: %w", spaceID, err)
 	}
 
 	tree.Lock()
 	defer tree.Unlock()
 
+	objects, err := iterateObjects(tree)
+	if err != nil {
+		return nil, err
+	}
+	version := 1
+	for _, obj := range objects {
+		if obj.ID == docID && obj.Version >= version {
+			version = obj.Version + 1
+		}
+	}
+
+	ownerKey := ""
+	if signingKey != nil {
+		if pubKeyBytes, err := signingKey.GetPublic().Marshall(); err == nil {
+			ownerKey = fmt.Sprintf("%x", pubKeyBytes)
+		}
+	}
+
+	return addObjectChange(ctx, tree, &ObjectPayload{
+		ID:        docID,
+		Type:      DocumentType,
+		OwnerKey:  ownerKey,
+		Data:      data,
+		Timestamp: time.Now().Unix(),
+		Version:   version,
+	}, signingKey, m.compact)
+}
+
+// addObjectChange adds an encrypted object change to a locked tree and
+// returns the new heads
+func addObjectChange(ctx context.Context, tree objecttree.ObjectTree, payload *ObjectPayload, signingKey crypto.PrivKey, compact bool) ([]string, error) {
+	data, dataType, err := encodeObjectPayload(payload, compact)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling object: %w", err)
+	}
+
 	result, err := tree.AddContent(ctx, objecttree.SignableChangeContent{
 		Data:              data,
 		Key:               signingKey,
 		IsSnapshot:        false,
 		ShouldBeEncrypted: true,
 		Timestamp:         time.Now().Unix(),
-		DataType:          ObjectChangeType,
+		DataType:          dataType,
 	})
 	if err != nil {
-		return "", fmt.Errorf("adding content: %w", err)
+		return nil, fmt.Errorf("adding content: %w", err)
 	}
 
 	if len(result.Heads) == 0 {
-		return "", fmt.Errorf("no heads returned after adding content")
+		return nil, fmt.Errorf("no heads returned after adding content")
 	}
 
-	return result.Heads[0], nil
+	return result.Heads, nil
 }
 
 // ReadObjects reads all objects from a space's tree.
@@ -93,22 +164,27 @@ func (m *ObjectTreeManager) ReadObjects(ctx context.Context, spaceID string) ([]
 	tree.Lock()
 	defer tree.Unlock()
 
+	return iterateObjects(tree)
+}
+
+// iterateObjects collects the object changes of a locked tree in traversal order
+func iterateObjects(tree objecttree.ObjectTree) ([]*ObjectPayload, error) {
 	var objects []*ObjectPayload
 
-	err = tree.IterateRoot(
+	err := tree.IterateRoot(
 		func(change *objecttree.Change, decrypted []byte) (any, error) {
 			if len(decrypted) == 0 {
 				return nil, nil
 			}
 			// Only process object changes
-			if change.DataType != ObjectChangeType {
+			if base, _ := baseDataType(change.DataType); base != ObjectChangeType {
 				return nil, nil
 			}
-			var p ObjectPayload
-			if err := json.Unmarshal(decrypted, &p); err != nil {
+			p, err := decodeObjectPayload(change.DataType, decrypted)
+			if err != nil {
 				return nil, fmt.Errorf("unmarshaling object: %w", err)
 			}
-			return &p, nil
+			return p, nil
 		},
 		func(change *objecttree.Change) bool {
 			if change.Model == nil {
@@ -144,6 +220,8 @@ func (m *ObjectTreeManager) ReadObjectsByType(ctx context.Context, spaceID strin
 }
 
 // ReadLatestByID reads the latest version of a specific object by ID.
+// Concurrent writes of the same version resolve to the later timestamp;
+// remaining ties keep tree order, which is the same on every peer.
 func (m *ObjectTreeManager) ReadLatestByID(ctx context.Context, spaceID string, objectID string) (*ObjectPayload, error) {
 	all, err := m.ReadObjects(ctx, spaceID)
 	if err != nil {
@@ -153,7 +231,8 @@ func (m *ObjectTreeManager) ReadLatestByID(ctx context.Context, spaceID string,
 	var latest *ObjectPayload
 	for _, obj := range all {
 		if obj.ID == objectID {
-			if latest == nil || obj.Version > latest.Version {
+			if latest == nil || obj.Version > latest.Version ||
+				(obj.Version == latest.Version && obj.Timestamp > latest.Timestamp) {
 				latest = obj
 			}
 		}
@@ -165,6 +244,72 @@ func (m *ObjectTreeManager) ReadLatestByID(ctx context.Context, spaceID string,
 	return latest, nil
 }
 
+// ObjectChange is one version of an object together with the tree change
+// that carried it
+type ObjectChange struct {
+	ChangeID  string         // Tree change ID
+	AuthorKey string         // Account key that signed the change
+	Timestamp int64          // Change timestamp (Unix seconds)
+	Object    *ObjectPayload // The object version written by the change
+}
+
+// ReadObjectHistory returns every version of an object, oldest first:
+// by version, then timestamp for concurrent writes of the same version.
+func (m *ObjectTreeManager) ReadObjectHistory(ctx context.Context, spaceID string, objectID string) ([]*ObjectChange, error) {
+	tree, err := m.loadTree(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	tree.Lock()
+	defer tree.Unlock()
+
+	var history []*ObjectChange
+	err = tree.IterateRoot(
+		func(change *objecttree.Change, decrypted []byte) (any, error) {
+			if base, _ := baseDataType(change.DataType); len(decrypted) == 0 || base != ObjectChangeType {
+				return nil, nil
+			}
+			p, err := decodeObjectPayload(change.DataType, decrypted)
+			if err != nil {
+				return nil, fmt.Errorf("unmarshaling object: %w", err)
+			}
+			return p, nil
+		},
+		func(change *objecttree.Change) bool {
+			o, ok := change.Model.(*ObjectPayload)
+			if !ok || o.ID != objectID {
+				return true
+			}
+			entry := &ObjectChange{
+				ChangeID:  change.Id,
+				Timestamp: change.Timestamp,
+				Object:    o,
+			}
+			if change.Identity != nil {
+				entry.AuthorKey = change.Identity.Account()
+			}
+			history = append(history, entry)
+			return true
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("iterating tree: %w", err)
+	}
+	if len(history) == 0 {
+		return nil, fmt.Errorf("object %s not found in space %s", objectID, spaceID)
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		a, b := history[i].Object, history[j].Object
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.Timestamp < b.Timestamp
+	})
+	return history, nil
+}
+
 // HasObjectTree returns true if an ObjectTree exists for the given space,
 // either in cache or discoverable from the space storage.
 func (m *ObjectTreeManager) HasObjectTree(ctx context.Context, spaceID string) bool {
@@ -175,6 +320,35 @@ func (m *ObjectTreeManager) HasObjectTree(ctx context.Context, spaceID string) b
 	return err == nil
 }
 
+// TreeHeads returns the ID and current heads of a space's object tree.
+func (m *ObjectTreeManager) TreeHeads(ctx context.Context, spaceID string) (string, []string, error) {
+	tree, err := m.loadTree(ctx, spaceID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tree.Lock()
+	defer tree.Unlock()
+
+	heads := make([]string, len(tree.Heads()))
+	copy(heads, tree.Heads())
+	return tree.Id(), heads, nil
+}
+
+// HasChanges reports whether a space's tree contains every given change.
+// A tree that contains a change also contains all of its ancestors.
+func (m *ObjectTreeManager) HasChanges(ctx context.Context, spaceID string, changeIDs []string) (bool, error) {
+	tree, err := m.loadTree(ctx, spaceID)
+	if err != nil {
+		return false, err
+	}
+
+	tree.Lock()
+	defer tree.Unlock()
+
+	return tree.HasChanges(changeIDs...), nil
+}
+
 // loadTree loads an existing tree from the cache or discovers it from the space.
 func (m *ObjectTreeManager) loadTree(ctx context.Context, spaceID string) (objecttree.ObjectTree, error) {
 	if tree, ok := m.trees.Load(spaceID); ok {
@@ -219,7 +393,7 @@ func (m *ObjectTreeManager) discoverTree(ctx context.Context, spaceID string) er
 				return nil, nil
 			},
 			func(change *objecttree.Change) bool {
-				if change.DataType == ObjectChangeType {
+				if base, _ := baseDataType(change.DataType); base == ObjectChangeType {
 					isObjectTree = true
 					return false
 				}
@@ -0,0 +1,177 @@
+package anysync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree/mock_objecttree"
+	"github.com/anyproto/any-sync/util/crypto"
+	"go.uber.org/mock/gomock"
+)
+
+// expectObjects makes a mock tree iterate the given objects as object changes
+func expectObjects(mockTree *mock_objecttree.MockObjectTree, objects ...*ObjectPayload) {
+	mockTree.EXPECT().IterateRoot(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(convert objecttree.ChangeConvertFunc, iterate objecttree.ChangeIterateFunc) error {
+			for _, obj := range objects {
+				data, _ := json.Marshal(obj)
+				change := &objecttree.Change{DataType: ObjectChangeType, Data: data}
+				model, err := convert(change, change.Data)
+				if err != nil {
+					return err
+				}
+				change.Model = model
+				if !iterate(change) {
+					break
+				}
+			}
+			return nil
+		},
+	)
+}
+
+func TestObjectTreeManager_PutDocument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	signingKey, _, _ := crypto.GenerateRandomEd25519KeyPair()
+	mockTree := mock_objecttree.NewMockObjectTree(ctrl)
+
+	mgr := NewObjectTreeManager(nil, nil, NewTreeCache())
+	mgr.trees.Store("test-space", mockTree)
+
+	// Two versions of the document (one merged in from a peer) and another object
+	expectObjects(mockTree,
+		&ObjectPayload{ID: "doc-1", Type: DocumentType, Data: json.RawMessage(`{"n":1}`), Version: 1},
+		&ObjectPayload{ID: "other", Type: "SharedProfile", Data: json.RawMessage(`{}`), Version: 7},
+		&ObjectPayload{ID: "doc-1", Type: DocumentType, Data: json.RawMessage(`{"n":2}`), Version: 2},
+	)
+
+	mockTree.EXPECT().Lock()
+	mockTree.EXPECT().Unlock()
+	mockTree.EXPECT().AddContent(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, content objecttree.SignableChangeContent) (objecttree.AddResult, error) {
+			if content.DataType != ObjectChangeType {
+				t.Errorf("expected DataType %s, got %s", ObjectChangeType, content.DataType)
+			}
+			if !content.ShouldBeEncrypted {
+				t.Error("expected ShouldBeEncrypted=true")
+			}
+			var p ObjectPayload
+			if err := json.Unmarshal(content.Data, &p); err != nil {
+				t.Fatalf("data not valid JSON: %v", err)
+			}
+			if p.ID != "doc-1" || p.Type != DocumentType || p.Version != 3 {
+				t.Errorf("unexpected payload: id=%s type=%s version=%d", p.ID, p.Type, p.Version)
+			}
+			if string(p.Data) != `{"n":3}` {
+				t.Errorf("unexpected data: %s", p.Data)
+			}
+			if p.OwnerKey == "" {
+				t.Error("expected owner key")
+			}
+			return objecttree.AddResult{
+				OldHeads: []string{"head-a", "head-b"},
+				Heads:    []string{"head-c"},
+			}, nil
+		},
+	)
+
+	heads, err := mgr.PutDocument(context.Background(), "test-space", "doc-1", []byte(`{"n":3}`), signingKey)
+	if err != nil {
+		t.Fatalf("PutDocument error: %v", err)
+	}
+	if len(heads) != 1 || heads[0] != "head-c" {
+		t.Errorf("expected heads [head-c], got %v", heads)
+	}
+}
+
+func TestObjectTreeManager_PutDocument_InvalidJSON(t *testing.T) {
+	mgr := NewObjectTreeManager(nil, nil, NewTreeCache())
+
+	if _, err := mgr.PutDocument(context.Background(), "test-space", "doc-1", []byte("not json"), nil); err == nil {
+		t.Error("expected error for non-JSON document")
+	}
+	if _, err := mgr.PutDocument(context.Background(), "test-space", "", []byte(`{}`), nil); err == nil {
+		t.Error("expected error for missing document ID")
+	}
+}
+
+func TestObjectTreeManager_ReadLatestByID_ConcurrentVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockTree := mock_objecttree.NewMockObjectTree(ctrl)
+	mgr := NewObjectTreeManager(nil, nil, NewTreeCache())
+	mgr.trees.Store("test-space", mockTree)
+
+	// Two peers wrote version 2 concurrently; the later write wins
+	expectObjects(mockTree,
+		&ObjectPayload{ID: "doc-1", Data: json.RawMessage(`"v1"`), Version: 1, Timestamp: 100},
+		&ObjectPayload{ID: "doc-1", Data: json.RawMessage(`"peer-b"`), Version: 2, Timestamp: 300},
+		&ObjectPayload{ID: "doc-1", Data: json.RawMessage(`"peer-a"`), Version: 2, Timestamp: 200},
+	)
+	mockTree.EXPECT().Lock()
+	mockTree.EXPECT().Unlock()
+
+	latest, err := mgr.ReadLatestByID(context.Background(), "test-space", "doc-1")
+	if err != nil {
+		t.Fatalf("ReadLatestByID error: %v", err)
+	}
+	if string(latest.Data) != `"peer-b"` {
+		t.Errorf("expected later concurrent write to win, got %s", latest.Data)
+	}
+}
+
+func TestObjectTreeManager_ReadObjectHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	_, author, _ := crypto.GenerateRandomEd25519KeyPair()
+	mockTree := mock_objecttree.NewMockObjectTree(ctrl)
+	mgr := NewObjectTreeManager(nil, nil, NewTreeCache())
+	mgr.trees.Store("test-space", mockTree)
+
+	// Tree order differs from version order when peers' changes merge
+	objects := []*ObjectPayload{
+		{ID: "doc-1", Data: json.RawMessage(`"v2"`), Version: 2, Timestamp: 200},
+		{ID: "other", Data: json.RawMessage(`{}`), Version: 1},
+		{ID: "doc-1", Data: json.RawMessage(`"v1"`), Version: 1, Timestamp: 100},
+	}
+	mockTree.EXPECT().IterateRoot(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(convert objecttree.ChangeConvertFunc, iterate objecttree.ChangeIterateFunc) error {
+			for i, obj := range objects {
+				data, _ := json.Marshal(obj)
+				change := &objecttree.Change{
+					Id:        fmt.Sprintf("change-%d", i),
+					Timestamp: obj.Timestamp,
+					Identity:  author,
+					DataType:  ObjectChangeType,
+				}
+				change.Model, _ = convert(change, data)
+				iterate(change)
+			}
+			return nil
+		},
+	).Times(2)
+	mockTree.EXPECT().Lock().Times(2)
+	mockTree.EXPECT().Unlock().Times(2)
+
+	history, err := mgr.ReadObjectHistory(context.Background(), "test-space", "doc-1")
+	if err != nil {
+		t.Fatalf("ReadObjectHistory error: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 versions, got %d", len(history))
+	}
+	if history[0].ChangeID != "change-2" || history[1].ChangeID != "change-0" {
+		t.Errorf("expected versions oldest first, got %s, %s", history[0].ChangeID, history[1].ChangeID)
+	}
+	if history[0].AuthorKey != author.Account() || history[0].Timestamp != 100 {
+		t.Errorf("unexpected change metadata: %+v", history[0])
+	}
+
+	if _, err := mgr.ReadObjectHistory(context.Background(), "test-space", "missing"); err == nil {
+		t.Error("expected error for unknown object")
+	}
+}
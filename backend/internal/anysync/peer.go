@@ -13,6 +13,8 @@ import (
 	"path/filepath"
 
 	"github.com/anyproto/any-sync/util/crypto"
+
+	"github.com/matou-dao/backend/internal/secret"
 )
 
 // PeerKeyManager handles peer key generation, storage, and AID mapping
@@ -206,6 +208,23 @@ func GeneratePeerIDFromAID(aid string) string {
 	return "matou-" + hex.EncodeToString(hash[:8])
 }
 
+// DeriveKeyFromSecret is DeriveKeyFromMnemonic for a secret.Mnemonic.
+// The revealed phrase never leaves this call.
+func DeriveKeyFromSecret(mnemonic *secret.Mnemonic, index uint32) (crypto.PrivKey, error) {
+	if mnemonic.IsEmpty() {
+		return nil, fmt.Errorf("mnemonic is empty")
+	}
+	return DeriveKeyFromMnemonic(mnemonic.Reveal(), index)
+}
+
+// ValidateSecretMnemonic is ValidateMnemonic for a secret.Mnemonic
+func ValidateSecretMnemonic(mnemonic *secret.Mnemonic) error {
+	if mnemonic.IsEmpty() {
+		return fmt.Errorf("invalid mnemonic: empty")
+	}
+	return ValidateMnemonic(mnemonic.Reveal())
+}
+
 // ValidateMnemonic checks if a mnemonic is valid for key derivation
 func ValidateMnemonic(mnemonic string) error {
 	m := crypto.Mnemonic(mnemonic)
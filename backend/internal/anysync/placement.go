@@ -0,0 +1,171 @@
+// Package anysync provides any-sync integration for MATOU.
+// placement.go chooses where the network keeps a space. Tree nodes hold the
+// spaces whose replication key hashes to them, so a space is pinned to
+// preferred nodes by choosing its replication key at creation.
+package anysync
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/anyproto/any-sync/nodeconf"
+	"github.com/anyproto/go-chash"
+)
+
+// placementAttempts bounds how many replication keys are tried when
+// looking for one that lands on the preferred nodes
+const placementAttempts = 10000
+
+// SpacePlacement is where a type of space should be kept. It only has an
+// effect on networks with more tree nodes than a space is replicated to.
+type SpacePlacement struct {
+	// PreferNodes are tree node peer IDs that should hold the space, e.g.
+	// the nodes in the org's region
+	PreferNodes []string `json:"preferNodes,omitempty" yaml:"preferNodes,omitempty"`
+	// FileNodes are file node peer IDs tried first for the space's files
+	FileNodes []string `json:"fileNodes,omitempty" yaml:"fileNodes,omitempty"`
+	// Replicas is how many tree nodes should hold the space. The network
+	// replicates every space to the same number of nodes, so this can't
+	// raise it; a network with fewer is reported at creation.
+	Replicas int `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+}
+
+// PlacementSource supplies the placement per space type.
+// Implemented by api.OrgConfigHandler.
+type PlacementSource interface {
+	GetSpacePlacement() map[string]*SpacePlacement
+}
+
+// placementFor returns the placement for a space type, or nil
+func placementFor(source PlacementSource, spaceType string) *SpacePlacement {
+	if source == nil {
+		return nil
+	}
+	return source.GetSpacePlacement()[spaceType]
+}
+
+// placementSpaceTypes are the space types a placement can be set for
+var placementSpaceTypes = []string{
+	SpaceTypePrivate,
+	SpaceTypeCommunity,
+	SpaceTypeCommunityReadOnly,
+	SpaceTypeAdmin,
+	SpaceTypeProject,
+	SpaceTypeInbox,
+}
+
+// ValidateSpacePlacements checks placements are for known space types and
+// have no negative replica counts or empty node IDs
+func ValidateSpacePlacements(placements map[string]*SpacePlacement) error {
+	for spaceType, p := range placements {
+		if !slices.Contains(placementSpaceTypes, spaceType) {
+			return fmt.Errorf("spacePlacement: unknown space type %q", spaceType)
+		}
+		if p == nil {
+			continue
+		}
+		if p.Replicas < 0 {
+			return fmt.Errorf("spacePlacement.%s.replicas must not be negative", spaceType)
+		}
+		if slices.Contains(p.PreferNodes, "") || slices.Contains(p.FileNodes, "") {
+			return fmt.Errorf("spacePlacement.%s has an empty node ID", spaceType)
+		}
+	}
+	return nil
+}
+
+// PlacementResult is where a new space was placed
+type PlacementResult struct {
+	ReplicationKey uint64
+	// Nodes are the tree nodes responsible for the space
+	Nodes []string
+	// Preferred is how many of Nodes are preferred nodes
+	Preferred int
+}
+
+// placeSpace chooses the replication key for a new space. Starting from
+// base, it looks for the first key whose responsible tree nodes include as
+// many preferred nodes as possible, computing responsibility the way tree
+// nodes do. It assumes nodes lists the same tree nodes as the network.
+func placeSpace(nodes []nodeconf.Node, base uint64, p *SpacePlacement) (*PlacementResult, error) {
+	ring, err := chash.New(chash.Config{
+		PartitionCount:    nodeconf.PartitionCount,
+		ReplicationFactor: nodeconf.ReplicationFactor,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating hash ring: %w", err)
+	}
+	var members []chash.Member
+	preferable := 0
+	for _, n := range nodes {
+		if n.HasType(nodeconf.NodeTypeTree) {
+			members = append(members, n)
+			if slices.Contains(p.PreferNodes, n.PeerId) {
+				preferable++
+			}
+		}
+	}
+	if len(members) == 0 {
+		return &PlacementResult{ReplicationKey: base}, nil
+	}
+	if err := ring.AddMembers(members...); err != nil {
+		return nil, fmt.Errorf("adding tree nodes to hash ring: %w", err)
+	}
+
+	place := func(key uint64) *PlacementResult {
+		result := &PlacementResult{ReplicationKey: key}
+		for _, m := range ring.GetMembers(strconv.FormatUint(key, 36)) {
+			result.Nodes = append(result.Nodes, m.Id())
+			if slices.Contains(p.PreferNodes, m.Id()) {
+				result.Preferred++
+			}
+		}
+		return result
+	}
+	best := place(base)
+	target := min(preferable, len(best.Nodes))
+	for i := uint64(1); i < placementAttempts && best.Preferred < target; i++ {
+		if candidate := place(base + i); candidate.Preferred > best.Preferred {
+			best = candidate
+		}
+	}
+	return best, nil
+}
+
+// placementSetter is implemented by clients that create spaces on the
+// network (SDKClient)
+type placementSetter interface {
+	SetPlacementSource(source PlacementSource)
+}
+
+// SetPlacementSource sets where new spaces are placed and which file nodes
+// their files go to first, by space type
+func (m *SpaceManager) SetPlacementSource(source PlacementSource) {
+	if c, ok := m.client.(placementSetter); ok {
+		c.SetPlacementSource(source)
+	}
+	if m.fileManager != nil {
+		m.fileManager.SetFileNodes(func(spaceID string) []string {
+			if p := placementFor(source, m.spaceTypeOf(spaceID)); p != nil {
+				return p.FileNodes
+			}
+			return nil
+		})
+	}
+}
+
+// spaceTypeOf returns the type of a space that holds files. Files are only
+// stored in the org spaces and project spaces, so any space that isn't one
+// of the org's is taken to be a project.
+func (m *SpaceManager) spaceTypeOf(spaceID string) string {
+	switch spaceID {
+	case m.GetCommunitySpaceID():
+		return SpaceTypeCommunity
+	case m.GetCommunityReadOnlySpaceID():
+		return SpaceTypeCommunityReadOnly
+	case m.GetAdminSpaceID():
+		return SpaceTypeAdmin
+	}
+	return SpaceTypeProject
+}
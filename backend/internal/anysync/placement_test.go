@@ -0,0 +1,83 @@
+package anysync
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/anyproto/any-sync/nodeconf"
+)
+
+func testNetworkNodes(treeNodes int) []nodeconf.Node {
+	nodes := []nodeconf.Node{
+		{PeerId: "file-1", Types: []nodeconf.NodeType{nodeconf.NodeTypeFile}},
+		{PeerId: "coordinator-1", Types: []nodeconf.NodeType{nodeconf.NodeTypeCoordinator}},
+	}
+	for i := 1; i <= treeNodes; i++ {
+		nodes = append(nodes, nodeconf.Node{PeerId: fmt.Sprintf("tree-%d", i), Types: []nodeconf.NodeType{nodeconf.NodeTypeTree}})
+	}
+	return nodes
+}
+
+func TestPlaceSpace(t *testing.T) {
+	nodes := testNetworkNodes(8)
+	prefer := &SpacePlacement{PreferNodes: []string{"tree-2", "tree-7"}}
+
+	result, err := placeSpace(nodes, 12345, prefer)
+	if err != nil {
+		t.Fatalf("placeSpace: %v", err)
+	}
+	if len(result.Nodes) != nodeconf.ReplicationFactor {
+		t.Fatalf("expected %d responsible nodes, got %v", nodeconf.ReplicationFactor, result.Nodes)
+	}
+	if result.Preferred != 2 || !slices.Contains(result.Nodes, "tree-2") || !slices.Contains(result.Nodes, "tree-7") {
+		t.Errorf("expected both preferred nodes responsible, got %v", result.Nodes)
+	}
+	for _, id := range result.Nodes {
+		if !strings.HasPrefix(id, "tree-") {
+			t.Errorf("expected only tree nodes responsible, got %s", id)
+		}
+	}
+
+	again, _ := placeSpace(nodes, 12345, prefer)
+	if again.ReplicationKey != result.ReplicationKey {
+		t.Errorf("expected placement to be deterministic, got %d and %d", result.ReplicationKey, again.ReplicationKey)
+	}
+}
+
+func TestPlaceSpaceWithoutPreference(t *testing.T) {
+	result, err := placeSpace(testNetworkNodes(2), 42, &SpacePlacement{Replicas: 3})
+	if err != nil {
+		t.Fatalf("placeSpace: %v", err)
+	}
+	if result.ReplicationKey != 42 {
+		t.Errorf("expected the base key kept without preferred nodes, got %d", result.ReplicationKey)
+	}
+	if len(result.Nodes) != 2 {
+		t.Errorf("expected a two-node network to hold the space on both, got %v", result.Nodes)
+	}
+
+	result, _ = placeSpace(testNetworkNodes(0), 42, &SpacePlacement{PreferNodes: []string{"tree-1"}})
+	if result.ReplicationKey != 42 || len(result.Nodes) != 0 {
+		t.Errorf("expected the base key on a network without tree nodes, got %+v", result)
+	}
+}
+
+func TestValidateSpacePlacements(t *testing.T) {
+	if err := ValidateSpacePlacements(map[string]*SpacePlacement{
+		SpaceTypeCommunity: {PreferNodes: []string{"tree-1"}, Replicas: 3},
+		SpaceTypeProject:   nil,
+	}); err != nil {
+		t.Errorf("expected valid placements, got %v", err)
+	}
+	if err := ValidateSpacePlacements(map[string]*SpacePlacement{"shared": {}}); err == nil {
+		t.Error("expected unknown space type rejected")
+	}
+	if err := ValidateSpacePlacements(map[string]*SpacePlacement{SpaceTypeAdmin: {Replicas: -1}}); err == nil {
+		t.Error("expected negative replicas rejected")
+	}
+	if err := ValidateSpacePlacements(map[string]*SpacePlacement{SpaceTypeAdmin: {FileNodes: []string{""}}}); err == nil {
+		t.Error("expected empty node ID rejected")
+	}
+}
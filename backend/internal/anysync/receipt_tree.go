@@ -0,0 +1,446 @@
+// Package anysync provides any-sync integration for MATOU.
+// receipt_tree.go keeps an append-only ledger of credential issuance,
+// delivery and revocation receipts in a dedicated ObjectTree. Each receipt is hash-chained
+// to the previous one and signed with the space signing key, so the ledger
+// is tamper-evident independently of KERIA.
+package anysync
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/object/tree/treechangeproto"
+	"github.com/anyproto/any-sync/commonspace/objecttreebuilder"
+	"github.com/anyproto/any-sync/util/crypto"
+)
+
+// ReceiptChangeType is the DataType used for receipt changes in ObjectTrees.
+const ReceiptChangeType = "matou.receipt.v1"
+
+// Receipt actions. Issued, delivered, acknowledged and cached are the
+// delivery stages of a credential, in order: delivered is the IPEX grant or
+// inbox deposit, acknowledged the recipient's IPEX admit, and cached the
+// recipient backend's inbox confirmation that it holds the credential.
+const (
+	ReceiptIssued       = "issued"
+	ReceiptDelivered    = "delivered"
+	ReceiptAcknowledged = "acknowledged"
+	ReceiptCached       = "cached"
+	ReceiptRevoked      = "revoked"
+)
+
+// Approval actions record the two-person rule for high-privilege
+// issuances. The credential doesn't exist yet, so their SAID is the
+// approval request's ID.
+const (
+	ReceiptApprovalRequested = "approval_requested"
+	ReceiptApproved          = "approved"
+	ReceiptApprovalRejected  = "approval_rejected"
+)
+
+// IsApprovalAction checks if action is an approval receipt action
+func IsApprovalAction(action string) bool {
+	switch action {
+	case ReceiptApprovalRequested, ReceiptApproved, ReceiptApprovalRejected:
+		return true
+	}
+	return false
+}
+
+// DeliveryStages returns the credential delivery stages in order
+func DeliveryStages() []string {
+	return []string{ReceiptIssued, ReceiptDelivered, ReceiptAcknowledged, ReceiptCached}
+}
+
+// IsReceiptAction checks if action is a valid receipt action
+func IsReceiptAction(action string) bool {
+	if action == ReceiptRevoked {
+		return true
+	}
+	for _, stage := range DeliveryStages() {
+		if stage == action {
+			return true
+		}
+	}
+	return false
+}
+
+// ReceiptPayload is the data stored in each receipt tree change.
+type ReceiptPayload struct {
+	Seq       int    `json:"seq"`
+	Action    string `json:"action"`
+	SAID      string `json:"said"`
+	Schema    string `json:"schema,omitempty"`
+	Recipient string `json:"recipient"`
+	Actor     string `json:"actor"`
+	Timestamp int64  `json:"timestamp"`
+	PrevHash  string `json:"prevHash,omitempty"`
+
+	// Hash is the SHA-256 of the fields above; Signature is the signer's
+	// Ed25519 signature over Hash. SignerKey is the hex-encoded public key.
+	Hash      string `json:"hash"`
+	SignerKey string `json:"signerKey"`
+	Signature string `json:"signature"`
+}
+
+// digest returns the hex SHA-256 over the receipt's content fields
+func (r *ReceiptPayload) digest() (string, error) {
+	content := *r
+	content.Hash, content.SignerKey, content.Signature = "", "", ""
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// seal chains the receipt to prev (nil for the first receipt), then hashes
+// and signs it.
+func (r *ReceiptPayload) seal(prev *ReceiptPayload, signingKey crypto.PrivKey) error {
+	if signingKey == nil {
+		return fmt.Errorf("signing key is required")
+	}
+	r.Seq, r.PrevHash = 1, ""
+	if prev != nil {
+		r.Seq = prev.Seq + 1
+		r.PrevHash = prev.Hash
+	}
+
+	hash, err := r.digest()
+	if err != nil {
+		return fmt.Errorf("hashing receipt: %w", err)
+	}
+	sig, err := signingKey.Sign([]byte(hash))
+	if err != nil {
+		return fmt.Errorf("signing receipt: %w", err)
+	}
+	pub, err := signingKey.GetPublic().Marshall()
+	if err != nil {
+		return fmt.Errorf("marshaling signer key: %w", err)
+	}
+
+	r.Hash = hash
+	r.SignerKey = hex.EncodeToString(pub)
+	r.Signature = hex.EncodeToString(sig)
+	return nil
+}
+
+// VerifyReceipts checks that receipts form an unbroken hash chain from the
+// first receipt and that every receipt's signature is valid. Returns an
+// error naming the first receipt that fails.
+func VerifyReceipts(receipts []*ReceiptPayload) error {
+	var prev *ReceiptPayload
+	for i, r := range receipts {
+		wantSeq, wantPrev := 1, ""
+		if prev != nil {
+			wantSeq, wantPrev = prev.Seq+1, prev.Hash
+		}
+		if r.Seq != wantSeq || r.PrevHash != wantPrev {
+			return fmt.Errorf("receipt %d (%s): chain broken", i+1, r.SAID)
+		}
+
+		hash, err := r.digest()
+		if err != nil || hash != r.Hash {
+			return fmt.Errorf("receipt %d (%s): hash mismatch", i+1, r.SAID)
+		}
+
+		pubBytes, err := hex.DecodeString(r.SignerKey)
+		if err != nil {
+			return fmt.Errorf("receipt %d (%s): invalid signer key", i+1, r.SAID)
+		}
+		pub, err := crypto.UnmarshalEd25519PublicKeyProto(pubBytes)
+		if err != nil {
+			return fmt.Errorf("receipt %d (%s): invalid signer key", i+1, r.SAID)
+		}
+		sig, err := hex.DecodeString(r.Signature)
+		if err != nil {
+			return fmt.Errorf("receipt %d (%s): invalid signature", i+1, r.SAID)
+		}
+		if ok, err := pub.Verify([]byte(r.Hash), sig); err != nil || !ok {
+			return fmt.Errorf("receipt %d (%s): bad signature", i+1, r.SAID)
+		}
+		prev = r
+	}
+	return nil
+}
+
+// ReceiptTreeManager manages the receipt ledger. Receipts live in their own
+// tree (not the shared credential/object tree), so it keeps its own cache.
+type ReceiptTreeManager struct {
+	client AnySyncClient
+	trees  *TreeCache
+	mu     sync.Mutex // serializes appends so the chain never forks locally
+}
+
+// DeliveryStageReached records when a credential reached a delivery stage
+type DeliveryStageReached struct {
+	Stage     string `json:"stage"`
+	Actor     string `json:"actor"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// CredentialDelivery is a credential's delivery lifecycle, derived from its
+// receipts
+type CredentialDelivery struct {
+	SAID      string                 `json:"said"`
+	Schema    string                 `json:"schema,omitempty"`
+	Recipient string                 `json:"recipient,omitempty"`
+	Status    string                 `json:"status"` // Furthest stage reached, or revoked
+	Reached   []DeliveryStageReached `json:"reached"`
+	Pending   []string               `json:"pending"`
+	Revoked   bool                   `json:"revoked"`
+}
+
+// DeliveryFromReceipts derives a credential's delivery lifecycle from the
+// receipt ledger. A later stage implies the earlier ones even if their
+// receipts are missing (e.g. a recipient confirms before the steward
+// records the grant), so pending only lists stages after the furthest one.
+// Returns nil if the ledger has no receipts for said.
+func DeliveryFromReceipts(said string, receipts []*ReceiptPayload) *CredentialDelivery {
+	var delivery *CredentialDelivery
+	reached := make(map[string]*ReceiptPayload)
+	for _, r := range receipts {
+		if r.SAID != said {
+			continue
+		}
+		if delivery == nil {
+			delivery = &CredentialDelivery{SAID: said}
+		}
+		if delivery.Schema == "" {
+			delivery.Schema = r.Schema
+		}
+		if delivery.Recipient == "" {
+			delivery.Recipient = r.Recipient
+		}
+		if r.Action == ReceiptRevoked {
+			delivery.Revoked = true
+			continue
+		}
+		if _, ok := reached[r.Action]; !ok {
+			reached[r.Action] = r
+		}
+	}
+	if delivery == nil {
+		return nil
+	}
+
+	delivery.Reached = []DeliveryStageReached{}
+	delivery.Pending = []string{}
+	furthest := -1
+	stages := DeliveryStages()
+	for i, stage := range stages {
+		if r, ok := reached[stage]; ok {
+			delivery.Reached = append(delivery.Reached, DeliveryStageReached{
+				Stage:     stage,
+				Actor:     r.Actor,
+				Timestamp: r.Timestamp,
+			})
+			furthest = i
+		}
+	}
+	if furthest >= 0 {
+		delivery.Status = stages[furthest]
+		delivery.Pending = append(delivery.Pending, stages[furthest+1:]...)
+	}
+	if delivery.Revoked {
+		delivery.Status = ReceiptRevoked
+		delivery.Pending = []string{}
+	}
+	return delivery
+}
+
+// NewReceiptTreeManager creates a new ReceiptTreeManager.
+func NewReceiptTreeManager(client AnySyncClient) *ReceiptTreeManager {
+	return &ReceiptTreeManager{
+		client: client,
+		trees:  NewTreeCache(),
+	}
+}
+
+// Append seals a receipt onto the end of the space's receipt chain and adds
+// it to the receipt tree, creating the tree on first use.
+func (m *ReceiptTreeManager) Append(ctx context.Context, spaceID string, receipt *ReceiptPayload, signingKey crypto.PrivKey) (*ReceiptPayload, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tree, err := m.getOrCreateTree(ctx, spaceID, signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("getting receipt tree for space %s: %w", spaceID, err)
+	}
+
+	existing, err := m.ReadReceipts(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	var prev *ReceiptPayload
+	if len(existing) > 0 {
+		prev = existing[len(existing)-1]
+	}
+
+	if receipt.Timestamp == 0 {
+		receipt.Timestamp = time.Now().Unix()
+	}
+	if err := receipt.seal(prev, signingKey); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(receipt)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling receipt: %w", err)
+	}
+
+	tree.Lock()
+	defer tree.Unlock()
+
+	if _, err := tree.AddContent(ctx, objecttree.SignableChangeContent{
+		Data:              data,
+		Key:               signingKey,
+		IsSnapshot:        false,
+		ShouldBeEncrypted: true,
+		Timestamp:         time.Now().Unix(),
+		DataType:          ReceiptChangeType,
+	}); err != nil {
+		return nil, fmt.Errorf("adding receipt: %w", err)
+	}
+	return receipt, nil
+}
+
+// ReadReceipts reads all receipts from a space's receipt tree in chain order.
+// A space without a receipt tree has no receipts.
+func (m *ReceiptTreeManager) ReadReceipts(ctx context.Context, spaceID string) ([]*ReceiptPayload, error) {
+	tree, ok := m.trees.Load(spaceID)
+	if !ok {
+		if err := m.discoverTree(ctx, spaceID); err != nil {
+			return nil, nil
+		}
+		if tree, ok = m.trees.Load(spaceID); !ok {
+			return nil, nil
+		}
+	}
+	tree.Lock()
+	defer tree.Unlock()
+
+	var receipts []*ReceiptPayload
+	err := tree.IterateRoot(
+		func(change *objecttree.Change, decrypted []byte) (any, error) {
+			if change.DataType != ReceiptChangeType || len(decrypted) == 0 {
+				return nil, nil
+			}
+			var r ReceiptPayload
+			if err := json.Unmarshal(decrypted, &r); err != nil {
+				return nil, fmt.Errorf("unmarshaling receipt: %w", err)
+			}
+			return &r, nil
+		},
+		func(change *objecttree.Change) bool {
+			if r, ok := change.Model.(*ReceiptPayload); ok {
+				receipts = append(receipts, r)
+			}
+			return true
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("iterating receipt tree: %w", err)
+	}
+	return receipts, nil
+}
+
+// discoverTree finds a receipt tree in the space storage, e.g. one created
+// by another steward and synced to this peer.
+func (m *ReceiptTreeManager) discoverTree(ctx context.Context, spaceID string) error {
+	if m.client == nil {
+		return fmt.Errorf("no client configured")
+	}
+	space, err := m.client.GetSpace(ctx, spaceID)
+	if err != nil {
+		return fmt.Errorf("getting space: %w", err)
+	}
+
+	storedIds := space.StoredIds()
+	builder := space.TreeBuilder()
+
+	for _, treeID := range storedIds {
+		tree, err := builder.BuildTree(ctx, treeID, objecttreebuilder.BuildTreeOpts{})
+		if err != nil {
+			continue
+		}
+
+		tree.Lock()
+		isReceiptTree := false
+		_ = tree.IterateRoot(
+			func(change *objecttree.Change, decrypted []byte) (any, error) {
+				return nil, nil
+			},
+			func(change *objecttree.Change) bool {
+				if change.DataType == ReceiptChangeType {
+					isReceiptTree = true
+					return false
+				}
+				if info, ok := change.Model.(*treechangeproto.TreeChangeInfo); ok {
+					if info.ChangeType == ReceiptChangeType {
+						isReceiptTree = true
+						return false
+					}
+				}
+				return true
+			},
+		)
+		tree.Unlock()
+		if isReceiptTree {
+			m.trees.Store(spaceID, tree)
+			return nil
+		}
+	}
+	return fmt.Errorf("no receipt tree found in %d stored objects", len(storedIds))
+}
+
+// getOrCreateTree returns the space's receipt tree, creating it if needed.
+func (m *ReceiptTreeManager) getOrCreateTree(ctx context.Context, spaceID string, signingKey crypto.PrivKey) (objecttree.ObjectTree, error) {
+	if tree, ok := m.trees.Load(spaceID); ok {
+		return tree, nil
+	}
+	if err := m.discoverTree(ctx, spaceID); err == nil {
+		if tree, ok := m.trees.Load(spaceID); ok {
+			return tree, nil
+		}
+	}
+
+	space, err := m.client.GetSpace(ctx, spaceID)
+	if err != nil {
+		return nil, fmt.Errorf("getting space %s: %w", spaceID, err)
+	}
+	treeBuilder := space.TreeBuilder()
+
+	seed := make([]byte, 32)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, fmt.Errorf("generating seed: %w", err)
+	}
+
+	storagePayload, err := treeBuilder.CreateTree(ctx, objecttree.ObjectTreeCreatePayload{
+		PrivKey:       signingKey,
+		ChangeType:    ReceiptChangeType,
+		ChangePayload: nil,
+		SpaceId:       spaceID,
+		IsEncrypted:   true,
+		Seed:          seed,
+		Timestamp:     time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating tree: %w", err)
+	}
+
+	tree, err := treeBuilder.PutTree(ctx, storagePayload, nil)
+	if err != nil {
+		return nil, fmt.Errorf("putting tree: %w", err)
+	}
+	m.trees.Store(spaceID, tree)
+	return tree, nil
+}
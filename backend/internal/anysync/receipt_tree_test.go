@@ -0,0 +1,127 @@
+package anysync
+
+import (
+	"testing"
+
+	"github.com/anyproto/any-sync/util/crypto"
+)
+
+func sealedReceipts(t *testing.T, key crypto.PrivKey, saids ...string) []*ReceiptPayload {
+	t.Helper()
+	var receipts []*ReceiptPayload
+	var prev *ReceiptPayload
+	for i, said := range saids {
+		r := &ReceiptPayload{
+			Action:    ReceiptIssued,
+			SAID:      said,
+			Recipient: "ERECIPIENT",
+			Actor:     "ESTEWARD",
+			Timestamp: int64(1700000000 + i),
+		}
+		if err := r.seal(prev, key); err != nil {
+			t.Fatalf("seal: %v", err)
+		}
+		receipts = append(receipts, r)
+		prev = r
+	}
+	return receipts
+}
+
+func TestReceiptPayload_Seal(t *testing.T) {
+	key, _, err := crypto.GenerateRandomEd25519KeyPair()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	receipts := sealedReceipts(t, key, "ESAID1", "ESAID2")
+	if receipts[0].Seq != 1 || receipts[0].PrevHash != "" {
+		t.Errorf("first receipt should start the chain, got seq %d prev %q", receipts[0].Seq, receipts[0].PrevHash)
+	}
+	if receipts[1].Seq != 2 || receipts[1].PrevHash != receipts[0].Hash {
+		t.Errorf("second receipt should link to the first")
+	}
+	if receipts[0].Signature == "" || receipts[0].SignerKey == "" {
+		t.Error("expected receipt to be signed")
+	}
+
+	if err := (&ReceiptPayload{}).seal(nil, nil); err == nil {
+		t.Error("expected error sealing without a key")
+	}
+}
+
+func TestVerifyReceipts(t *testing.T) {
+	key, _, err := crypto.GenerateRandomEd25519KeyPair()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	otherKey, _, err := crypto.GenerateRandomEd25519KeyPair()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	if err := VerifyReceipts(nil); err != nil {
+		t.Errorf("empty ledger should verify, got %v", err)
+	}
+	if err := VerifyReceipts(sealedReceipts(t, key, "ESAID1", "ESAID2", "ESAID3")); err != nil {
+		t.Errorf("valid ledger should verify, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(receipts []*ReceiptPayload)
+	}{
+		{"edited recipient", func(r []*ReceiptPayload) { r[1].Recipient = "EMALLORY" }},
+		{"removed receipt", func(r []*ReceiptPayload) { r[1] = r[2] }},
+		{"reordered", func(r []*ReceiptPayload) { r[0], r[1] = r[1], r[0] }},
+		{"resealed by another key", func(r []*ReceiptPayload) {
+			r[1].Recipient = "EMALLORY"
+			r[1].seal(r[0], otherKey)
+			r[1].SignerKey = r[0].SignerKey
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipts := sealedReceipts(t, key, "ESAID1", "ESAID2", "ESAID3")
+			tt.tamper(receipts)
+			if err := VerifyReceipts(receipts); err == nil {
+				t.Error("expected tampering to be detected")
+			}
+		})
+	}
+}
+
+func TestDeliveryFromReceipts(t *testing.T) {
+	receipts := []*ReceiptPayload{
+		{Action: ReceiptIssued, SAID: "ESAID1", Schema: "EMatouMembershipSchemaV1", Recipient: "EBOB", Actor: "ESTEWARD", Timestamp: 1},
+		{Action: ReceiptIssued, SAID: "ESAID2", Recipient: "ECAROL", Actor: "ESTEWARD", Timestamp: 2},
+		{Action: ReceiptDelivered, SAID: "ESAID1", Recipient: "EBOB", Actor: "ESTEWARD", Timestamp: 3},
+		{Action: ReceiptCached, SAID: "ESAID2", Recipient: "ECAROL", Actor: "ECAROL", Timestamp: 4},
+		{Action: ReceiptRevoked, SAID: "ESAID3", Recipient: "EDAVE", Actor: "ESTEWARD", Timestamp: 5},
+	}
+
+	delivery := DeliveryFromReceipts("ESAID1", receipts)
+	if delivery == nil {
+		t.Fatal("expected delivery status for ESAID1")
+	}
+	if delivery.Status != ReceiptDelivered || delivery.Schema != "EMatouMembershipSchemaV1" || delivery.Recipient != "EBOB" {
+		t.Errorf("unexpected delivery: %+v", delivery)
+	}
+	if len(delivery.Reached) != 2 || len(delivery.Pending) != 2 || delivery.Pending[0] != ReceiptAcknowledged {
+		t.Errorf("expected issued and delivered reached, acknowledged and cached pending, got %+v", delivery)
+	}
+
+	// A later stage implies the ones before it
+	delivery = DeliveryFromReceipts("ESAID2", receipts)
+	if delivery.Status != ReceiptCached || len(delivery.Pending) != 0 {
+		t.Errorf("expected ESAID2 cached with nothing pending, got %+v", delivery)
+	}
+
+	delivery = DeliveryFromReceipts("ESAID3", receipts)
+	if !delivery.Revoked || delivery.Status != ReceiptRevoked || len(delivery.Pending) != 0 {
+		t.Errorf("expected ESAID3 revoked, got %+v", delivery)
+	}
+
+	if DeliveryFromReceipts("EUNKNOWN", receipts) != nil {
+		t.Error("expected nil for a credential without receipts")
+	}
+}
@@ -0,0 +1,330 @@
+// Package anysync provides any-sync integration for MATOU.
+// reencrypt.go re-protects a space whose content has leaked: it rotates the
+// space read key and rewrites every tree under the new key.
+package anysync
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace"
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
+	"github.com/anyproto/any-sync/commonspace/objecttreebuilder"
+	"github.com/anyproto/any-sync/util/crypto"
+
+	"github.com/matou-dao/backend/internal/metrics"
+)
+
+// Re-encryption phases, in the order ReencryptSpace runs them
+const (
+	ReencryptRevokingInvites = "revoking-invites"
+	ReencryptRotatingKey     = "rotating-key"
+	ReencryptCopyingTrees    = "copying-trees"
+	ReencryptDone            = "done"
+)
+
+// reencryptProgressEvery is how many copied changes pass between progress
+// reports within one tree
+const reencryptProgressEvery = 100
+
+// ReencryptOptions configures a space re-encryption
+type ReencryptOptions struct {
+	// Revoke lists identities that lose access. Every other account keeps
+	// its permissions and is re-added under the new read key.
+	Revoke []crypto.PubKey
+	// Progress, if set, is called as each phase starts, after each tree
+	// and periodically while a large tree is copied
+	Progress func(ReencryptProgress)
+}
+
+// TreeReencryption is the outcome of rewriting one tree
+type TreeReencryption struct {
+	OldID      string `json:"oldId"`
+	NewID      string `json:"newId,omitempty"`
+	ChangeType string `json:"changeType,omitempty"`
+	Changes    int    `json:"changes"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ReencryptProgress reports how far a space re-encryption has got
+type ReencryptProgress struct {
+	SpaceID        string `json:"spaceId"`
+	Phase          string `json:"phase"`
+	InvitesRevoked int    `json:"invitesRevoked"`
+	// Revoked is how many accounts were removed
+	Revoked int `json:"revoked"`
+	// Readers is how many accounts were re-added under the new read key
+	Readers int `json:"readers"`
+	// ReadKeyID is the ACL record that introduced the new read key
+	ReadKeyID     string             `json:"readKeyId,omitempty"`
+	TreesTotal    int                `json:"treesTotal"`
+	TreesDone     int                `json:"treesDone"`
+	ChangesCopied int                `json:"changesCopied"`
+	Trees         []TreeReencryption `json:"trees,omitempty"`
+}
+
+// treeForgetter is implemented by clients that cache trees by space
+// (SDKClient's document trees)
+type treeForgetter interface {
+	ForgetTrees(spaceID string)
+}
+
+// ReencryptSpace re-protects a space after its content leaked. It revokes
+// outstanding invites (each carries the old read key), rotates the read key
+// so only the remaining accounts receive the new one, then copies every
+// tree's content into a new tree encrypted with the new key and deletes
+// the old tree. Copies are signed with the space's signing key, so the
+// backend must hold the space's key set and be able to manage its ACL.
+//
+// Old changes stay readable to anyone holding the old key until tree nodes
+// drop the deleted trees. A tree that fails to copy is left in place and
+// reported; the returned error covers only failures before the copy phase.
+func (m *SpaceManager) ReencryptSpace(ctx context.Context, spaceID string, opts ReencryptOptions) (progress *ReencryptProgress, err error) {
+	defer metrics.ObserveSpaceOperation("reencrypt_space", time.Now(), &err)
+
+	progress = &ReencryptProgress{SpaceID: spaceID}
+	report := func() {
+		if opts.Progress != nil {
+			snapshot := *progress
+			snapshot.Trees = append([]TreeReencryption(nil), progress.Trees...)
+			opts.Progress(snapshot)
+		}
+	}
+
+	space, err := m.client.GetSpace(ctx, spaceID)
+	if err != nil {
+		return progress, fmt.Errorf("getting space %s: %w", spaceID, err)
+	}
+	keys, err := LoadSpaceKeySet(m.client.GetDataDir(), spaceID)
+	if err != nil {
+		return progress, fmt.Errorf("loading key set for space %s: %w", spaceID, err)
+	}
+
+	acl := space.Acl()
+	acl.RLock()
+	state := acl.AclState()
+	if state == nil {
+		acl.RUnlock()
+		return progress, fmt.Errorf("ACL state not available for space %s", spaceID)
+	}
+	if !state.Permissions(state.Identity()).CanManageAccounts() {
+		acl.RUnlock()
+		return progress, fmt.Errorf("not permitted to manage accounts in space %s", spaceID)
+	}
+	invites := len(state.InviteIds())
+	for _, identity := range opts.Revoke {
+		perms := state.Permissions(identity)
+		if perms.IsOwner() {
+			acl.RUnlock()
+			return progress, fmt.Errorf("cannot revoke the space owner")
+		}
+		if perms.NoPermissions() {
+			acl.RUnlock()
+			return progress, fmt.Errorf("%s has no access to space %s", identity.Account(), spaceID)
+		}
+	}
+	acl.RUnlock()
+
+	aclClient := space.AclClient()
+	if invites > 0 {
+		progress.Phase = ReencryptRevokingInvites
+		report()
+		if err := aclClient.RevokeAllInvites(ctx); err != nil {
+			return progress, fmt.Errorf("revoking invites: %w", err)
+		}
+		progress.InvitesRevoked = invites
+	}
+
+	progress.Phase = ReencryptRotatingKey
+	report()
+	readKey := crypto.NewAES()
+	metadataKey, _, err := crypto.GenerateRandomEd25519KeyPair()
+	if err != nil {
+		return progress, fmt.Errorf("generating metadata key: %w", err)
+	}
+	change := list.ReadKeyChangePayload{MetadataKey: metadataKey, ReadKey: readKey}
+	if len(opts.Revoke) > 0 {
+		err = aclClient.RemoveAccounts(ctx, list.AccountRemovePayload{Identities: opts.Revoke, Change: change})
+	} else {
+		acl.Lock()
+		record, buildErr := acl.RecordBuilder().BuildReadKeyChange(change)
+		acl.Unlock()
+		if buildErr != nil {
+			return progress, fmt.Errorf("building read key change: %w", buildErr)
+		}
+		err = aclClient.AddRecord(ctx, record)
+	}
+	if err != nil {
+		return progress, fmt.Errorf("rotating read key: %w", err)
+	}
+	progress.Revoked = len(opts.Revoke)
+
+	acl.RLock()
+	progress.ReadKeyID = acl.AclState().CurrentReadKeyId()
+	for _, account := range acl.AclState().CurrentAccounts() {
+		if !account.Permissions.NoPermissions() && !account.Permissions.IsOwner() {
+			progress.Readers++
+		}
+	}
+	acl.RUnlock()
+
+	// Backups and recovery restore the persisted key set, so it carries the
+	// new read key too
+	keys.ReadKey = readKey
+	if err := PersistSpaceKeySet(m.client.GetDataDir(), spaceID, keys); err != nil {
+		fmt.Printf("[Reencrypt] Warning: failed to persist new read key for space %s: %v\n", spaceID, err)
+	}
+
+	treeIDs := space.StoredIds()
+	progress.Phase = ReencryptCopyingTrees
+	progress.TreesTotal = len(treeIDs)
+	report()
+	for _, treeID := range treeIDs {
+		result := copyTree(ctx, space, treeID, keys.SigningKey, nil, func(copied int) {
+			progress.ChangesCopied += copied
+			report()
+		})
+		progress.Trees = append(progress.Trees, result)
+		progress.TreesDone++
+		report()
+	}
+
+	// Managers cache one tree per space; the next access discovers the copies
+	m.treeCache.Delete(spaceID)
+	m.receiptTreeManager.trees.Delete(spaceID)
+	if f, ok := m.client.(treeForgetter); ok {
+		f.ForgetTrees(spaceID)
+	}
+
+	progress.Phase = ReencryptDone
+	report()
+	return progress, nil
+}
+
+// copiedChange is a decrypted change waiting to be copied
+type copiedChange struct {
+	data      []byte
+	dataType  string
+	timestamp int64
+}
+
+// changeConverter rewrites a change's data and DataType as it is copied
+type changeConverter func(dataType string, data []byte) ([]byte, string, error)
+
+// copyTree copies one tree's changes into a new tree under the space's
+// current read key and deletes the old tree. If convert is set, each change
+// is passed through it, and a tree where convert changes nothing is left
+// alone (the result has no NewID). copied is called with the number of
+// changes copied since it was last called.
+func copyTree(ctx context.Context, space commonspace.Space, treeID string, signingKey crypto.PrivKey, convert changeConverter, copied func(int)) TreeReencryption {
+	result := TreeReencryption{OldID: treeID}
+	builder := space.TreeBuilder()
+
+	old, err := builder.BuildTree(ctx, treeID, objecttreebuilder.BuildTreeOpts{})
+	if err != nil {
+		result.Error = fmt.Sprintf("loading tree: %v", err)
+		return result
+	}
+
+	old.Lock()
+	if info := old.ChangeInfo(); info != nil {
+		result.ChangeType = info.ChangeType
+	}
+	var changes []copiedChange
+	err = old.IterateRoot(
+		func(change *objecttree.Change, decrypted []byte) (any, error) {
+			if len(decrypted) == 0 {
+				return nil, nil
+			}
+			return append([]byte(nil), decrypted...), nil
+		},
+		func(change *objecttree.Change) bool {
+			if data, ok := change.Model.([]byte); ok {
+				changes = append(changes, copiedChange{data: data, dataType: change.DataType, timestamp: change.Timestamp})
+			}
+			return true
+		},
+	)
+	old.Unlock()
+	if err != nil {
+		result.Error = fmt.Sprintf("reading tree: %v", err)
+		return result
+	}
+	if convert != nil {
+		converted := false
+		for i, change := range changes {
+			data, dataType, err := convert(change.dataType, change.data)
+			if err != nil {
+				result.Error = fmt.Sprintf("converting change %d of %d: %v", i+1, len(changes), err)
+				return result
+			}
+			converted = converted || dataType != change.dataType
+			changes[i].data, changes[i].dataType = data, dataType
+		}
+		if !converted {
+			return result
+		}
+	}
+
+	seed := make([]byte, 32)
+	if _, err := rand.Read(seed); err != nil {
+		result.Error = fmt.Sprintf("generating seed: %v", err)
+		return result
+	}
+	storagePayload, err := builder.CreateTree(ctx, objecttree.ObjectTreeCreatePayload{
+		PrivKey:     signingKey,
+		ChangeType:  result.ChangeType,
+		SpaceId:     space.Id(),
+		IsEncrypted: true,
+		Seed:        seed,
+		Timestamp:   time.Now().Unix(),
+	})
+	if err != nil {
+		result.Error = fmt.Sprintf("creating tree: %v", err)
+		return result
+	}
+	tree, err := builder.PutTree(ctx, storagePayload, nil)
+	if err != nil {
+		result.Error = fmt.Sprintf("putting tree: %v", err)
+		return result
+	}
+	result.NewID = tree.Id()
+
+	tree.Lock()
+	pending := 0
+	for _, change := range changes {
+		_, err = tree.AddContent(ctx, objecttree.SignableChangeContent{
+			Data:              change.data,
+			Key:               signingKey,
+			ShouldBeEncrypted: true,
+			Timestamp:         change.timestamp,
+			DataType:          change.dataType,
+		})
+		if err != nil {
+			break
+		}
+		result.Changes++
+		if pending++; pending == reencryptProgressEvery {
+			copied(pending)
+			pending = 0
+		}
+	}
+	tree.Unlock()
+	copied(pending)
+	if err != nil {
+		// The partial copy is deleted so readers don't see two trees
+		if delErr := space.DeleteTree(ctx, result.NewID); delErr != nil {
+			fmt.Printf("[Trees] Warning: failed to delete partial copy %s: %v\n", result.NewID, delErr)
+		}
+		result.Error = fmt.Sprintf("copying change %d of %d: %v", result.Changes+1, len(changes), err)
+		return result
+	}
+
+	if err := space.DeleteTree(ctx, treeID); err != nil {
+		result.Error = fmt.Sprintf("deleting old tree: %v", err)
+	}
+	return result
+}
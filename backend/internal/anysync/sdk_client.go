@@ -4,7 +4,6 @@ package anysync
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -17,7 +16,9 @@ import (
 	"github.com/anyproto/any-sync/commonspace"
 	"github.com/anyproto/any-sync/commonspace/config"
 	"github.com/anyproto/any-sync/commonspace/credentialprovider"
+	"github.com/anyproto/any-sync/commonspace/headsync/headstorage"
 	"github.com/anyproto/any-sync/commonspace/object/accountdata"
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
 	"github.com/anyproto/any-sync/commonspace/object/tree/objecttree"
 	"github.com/anyproto/any-sync/commonspace/object/tree/synctree"
 	"github.com/anyproto/any-sync/commonspace/object/tree/treestorage"
@@ -31,7 +32,6 @@ import (
 	"github.com/anyproto/any-sync/commonspace/sync/objectsync/objectmessages"
 	"github.com/anyproto/any-sync/commonspace/syncstatus"
 	"github.com/anyproto/any-sync/consensus/consensusclient"
-	"github.com/anyproto/any-sync/consensus/consensusproto"
 	"github.com/anyproto/any-sync/coordinator/coordinatorclient"
 	"github.com/anyproto/any-sync/coordinator/coordinatorproto"
 	"github.com/anyproto/any-sync/net/peer"
@@ -50,6 +50,9 @@ import (
 	"github.com/anyproto/go-chash"
 	anystore "github.com/anyproto/any-store"
 	"storj.io/drpc"
+
+	"github.com/matou-dao/backend/internal/metrics"
+	"github.com/matou-dao/backend/internal/secret"
 )
 
 // SDKClient provides full any-sync SDK integration with network connectivity
@@ -65,6 +68,14 @@ type SDKClient struct {
 	networkID       string
 	coordinatorURL  string
 	initialized     bool
+	trees           *TreeCache // Document trees written by SyncDocument
+
+	headMu        sync.RWMutex
+	headListeners []func(spaceID string)
+	lastHead      map[string]time.Time // When each space last had a HeadUpdate
+
+	placementMu sync.RWMutex
+	placement   PlacementSource
 }
 
 // NewSDKClient creates a new any-sync client with full network connectivity
@@ -101,6 +112,7 @@ func NewSDKClient(clientConfigPath string, opts *ClientOptions) (*SDKClient, err
 		networkID:      clientConfig.NetworkID,
 		coordinatorURL: coordinatorURL,
 		dataDir:        dataDir,
+		trees:          NewTreeCache(),
 	}
 
 	// Initialize peer key manager
@@ -109,7 +121,7 @@ func NewSDKClient(clientConfigPath string, opts *ClientOptions) (*SDKClient, err
 		keyPath = opts.PeerKeyPath
 	}
 
-	var mnemonic string
+	var mnemonic *secret.Mnemonic
 	var keyIndex uint32
 	if opts != nil {
 		mnemonic = opts.Mnemonic
@@ -118,7 +130,7 @@ func NewSDKClient(clientConfigPath string, opts *ClientOptions) (*SDKClient, err
 
 	peerMgr, err := NewPeerKeyManager(&PeerKeyConfig{
 		KeyPath:  keyPath,
-		Mnemonic: mnemonic,
+		Mnemonic: mnemonic.Reveal(),
 		KeyIndex: keyIndex,
 	})
 	if err != nil {
@@ -195,7 +207,7 @@ func (c *SDKClient) initFullSDK() error {
 	// Stream handler: opens/reads ObjectSyncStream for outgoing sync
 	// SpaceSync RPC: handles incoming RPCs from tree nodes (ObjectSyncRequestStream,
 	// HeadSync) so tree nodes can pull trees they learn about via HeadUpdate.
-	c.app.Register(newSDKStreamHandler())
+	c.app.Register(newSDKStreamHandler(c.notifyHeadUpdate))
 	c.app.Register(newSDKSpaceSyncRPC())
 
 	// Start the app
@@ -253,7 +265,9 @@ func (c *SDKClient) CreateSpace(ctx context.Context, ownerAID string, spaceType
 
 // CreateSpaceWithKeys creates a new space using a full SpaceKeySet and registers
 // it with the coordinator. Keys are persisted and the space is cached.
-func (c *SDKClient) CreateSpaceWithKeys(ctx context.Context, ownerAID string, spaceType string, keys *SpaceKeySet) (*SpaceCreateResult, error) {
+func (c *SDKClient) CreateSpaceWithKeys(ctx context.Context, ownerAID string, spaceType string, keys *SpaceKeySet) (result *SpaceCreateResult, err error) {
+	defer metrics.ObserveSpaceOperation("create_space", time.Now(), &err)
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -267,6 +281,23 @@ func (c *SDKClient) CreateSpaceWithKeys(ctx context.Context, ownerAID string, sp
 		return nil, fmt.Errorf("computing replication key: %w", err)
 	}
 
+	// Move the space onto preferred tree nodes if its type has a placement
+	c.placementMu.RLock()
+	placement := placementFor(c.placement, spaceType)
+	c.placementMu.RUnlock()
+	if placement != nil {
+		placed, err := placeSpace(c.GetNodeConf().Configuration().Nodes, repKey, placement)
+		if err != nil {
+			return nil, fmt.Errorf("placing space: %w", err)
+		}
+		repKey = placed.ReplicationKey
+		fmt.Printf("[any-sync SDK] Placing %s space on tree nodes %v (%d preferred)\n", spaceType, placed.Nodes, placed.Preferred)
+		if placement.Replicas > len(placed.Nodes) {
+			fmt.Printf("[any-sync SDK] Warning: %s spaces should have %d replicas, but the network keeps %d\n",
+				spaceType, placement.Replicas, len(placed.Nodes))
+		}
+	}
+
 	metadata := []byte(fmt.Sprintf(`{"owner":"%s","type":"%s","created":"%s"}`,
 		ownerAID, spaceType, time.Now().UTC().Format(time.RFC3339)))
 
@@ -329,7 +360,9 @@ func (c *SDKClient) CreateSpaceWithKeys(ctx context.Context, ownerAID string, sp
 // GetSpace returns an opened Space by ID. If not cached, it attempts to open
 // the space via the space service. Uses the shared space resolver to ensure
 // all components share the same Space instances.
-func (c *SDKClient) GetSpace(ctx context.Context, spaceID string) (commonspace.Space, error) {
+func (c *SDKClient) GetSpace(ctx context.Context, spaceID string) (space commonspace.Space, err error) {
+	defer metrics.ObserveSpaceOperation("open_space", time.Now(), &err)
+
 	resolver := c.app.MustComponent(spaceResolverCName).(*sdkSpaceResolver)
 	return resolver.GetSpace(ctx, spaceID)
 }
@@ -340,7 +373,9 @@ func (c *SDKClient) GetDataDir() string {
 }
 
 // DeriveSpace creates a deterministic space derived from the signing key
-func (c *SDKClient) DeriveSpace(ctx context.Context, ownerAID string, spaceType string, signingKey crypto.PrivKey) (*SpaceCreateResult, error) {
+func (c *SDKClient) DeriveSpace(ctx context.Context, ownerAID string, spaceType string, signingKey crypto.PrivKey) (result *SpaceCreateResult, err error) {
+	defer metrics.ObserveSpaceOperation("derive_space", time.Now(), &err)
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -438,52 +473,88 @@ func (c *SDKClient) DeriveSpaceIDWithKeys(ctx context.Context, ownerAID string,
 	return spaceID, nil
 }
 
-// Deprecated: AddToACL builds raw JSON as a proto record which is rejected by the
-// consensus node. Use MatouACLManager.CreateOpenInvite/JoinWithInvite instead.
-func (c *SDKClient) AddToACL(ctx context.Context, spaceID string, peerID string, permissions []string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// AddToACL grants a peer permissions in a space's ACL by submitting an
+// AccountsAdd (new member) or PermissionChanges (existing member) record
+// through the space's ACL client. The consensus node validates the record,
+// so the local identity must be an admin or owner of the space.
+//
+// peerID may be a peer ID or an account address; peers sign with their
+// account key, so both identify the same ACL identity. permissions is a set
+// of "read", "write" and "admin"; the highest one is granted.
+func (c *SDKClient) AddToACL(ctx context.Context, spaceID string, peerID string, permissions []string) (err error) {
+	defer metrics.ObserveSpaceOperation("acl_update", time.Now(), &err)
 
-	if !c.initialized {
+	c.mu.RLock()
+	initialized := c.initialized
+	c.mu.RUnlock()
+	if !initialized {
 		return fmt.Errorf("client not initialized")
 	}
 
-	// Build ACL record payload
-	payload := map[string]interface{}{
-		"peerId":      peerID,
-		"spaceId":     spaceID,
-		"permissions": permissions,
-		"timestamp":   time.Now().UTC().Format(time.RFC3339),
+	identity, err := DecodeACLIdentity(peerID)
+	if err != nil {
+		return err
 	}
-	data, err := json.Marshal(payload)
+	perms, err := aclPermissionsFromStrings(permissions)
 	if err != nil {
-		return fmt.Errorf("marshaling ACL record: %w", err)
+		return err
 	}
 
-	record := &consensusproto.RawRecord{
-		Payload: data,
+	if err := NewMatouACLManager(c, c.peerKeyManager).SetAccountPermissions(ctx, spaceID, identity, perms); err != nil {
+		return err
 	}
 
-	_, err = c.coordinator.AclAddRecord(ctx, spaceID, record)
+	fmt.Printf("[any-sync SDK] AddToACL: space=%s peer=%s permissions=%v\n", spaceID, peerID, permissions)
+	return nil
+}
+
+// DecodeACLIdentity decodes a peer ID or account address to the public key
+// the ACL identifies members by
+func DecodeACLIdentity(peerID string) (crypto.PubKey, error) {
+	if key, err := crypto.DecodeAccountAddress(peerID); err == nil {
+		return key, nil
+	}
+	key, err := crypto.DecodePeerId(peerID)
 	if err != nil {
-		// Tolerate "already exists" errors gracefully
-		errStr := err.Error()
-		if strings.Contains(errStr, "already exists") ||
-			strings.Contains(errStr, "duplicate") {
-			fmt.Printf("[any-sync SDK] AddToACL: peer %s already in ACL for space %s\n", peerID, spaceID)
-			return nil
-		}
-		return fmt.Errorf("adding ACL record via coordinator: %w", err)
+		return nil, fmt.Errorf("invalid peer ID %q: %w", peerID, err)
 	}
+	return key, nil
+}
 
-	fmt.Printf("[any-sync SDK] AddToACL: space=%s peer=%s permissions=%v\n", spaceID, peerID, permissions)
-	return nil
+// aclPermissionsFromStrings maps permission names to the highest matching
+// SDK permission. Ownership can't be granted this way.
+func aclPermissionsFromStrings(permissions []string) (list.AclPermissions, error) {
+	perms := list.AclPermissionsNone
+	for _, p := range permissions {
+		switch p {
+		case "read":
+			if perms.NoPermissions() {
+				perms = list.AclPermissionsReader
+			}
+		case "write":
+			if perms != list.AclPermissionsAdmin {
+				perms = list.AclPermissionsWriter
+			}
+		case "admin":
+			perms = list.AclPermissionsAdmin
+		case "owner":
+			return list.AclPermissionsNone, fmt.Errorf("ownership cannot be granted via the ACL")
+		default:
+			return list.AclPermissionsNone, fmt.Errorf("unknown permission %q", p)
+		}
+	}
+	if perms.NoPermissions() {
+		return list.AclPermissionsNone, fmt.Errorf("at least one permission is required")
+	}
+	return perms, nil
 }
 
 // MakeSpaceShareable marks a space as shareable on the coordinator,
 // enabling ACL invite operations (CreateOpenInvite / JoinWithInvite).
 // Must be called after space creation and propagation to tree nodes.
-func (c *SDKClient) MakeSpaceShareable(ctx context.Context, spaceID string) error {
+func (c *SDKClient) MakeSpaceShareable(ctx context.Context, spaceID string) (err error) {
+	defer metrics.ObserveSpaceOperation("make_shareable", time.Now(), &err)
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -529,41 +600,30 @@ func (c *SDKClient) SetAccountFileLimits(ctx context.Context, identity string, l
 	return nil
 }
 
-// SyncDocument syncs a document to a space.
-//
-// Deprecated: Use CredentialTreeManager.AddCredential instead. This method
-// exists for backward compatibility and logs a deprecation warning. All data
-// should go through ObjectTree-based operations for P2P sync support.
-func (c *SDKClient) SyncDocument(ctx context.Context, spaceID string, docID string, data []byte) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// SyncDocument writes a JSON document to a space's ObjectTree as an
+// encrypted, signed change and returns the tree's new head change IDs.
+// Writing an existing docID appends the next version; see
+// ObjectTreeManager.PutDocument for how concurrent updates merge.
+func (c *SDKClient) SyncDocument(ctx context.Context, spaceID string, docID string, data []byte) (heads []string, err error) {
+	defer metrics.ObserveSpaceOperation("sync_document", time.Now(), &err)
 
-	if !c.initialized {
-		return fmt.Errorf("client not initialized")
+	c.mu.RLock()
+	initialized := c.initialized
+	c.mu.RUnlock()
+	if !initialized {
+		return nil, fmt.Errorf("client not initialized")
 	}
 
-	fmt.Printf("[any-sync SDK] DEPRECATED: SyncDocument called for space=%s doc=%s — use CredentialTreeManager.AddCredential instead\n", spaceID, docID)
-
-	// Delegate to tree-based credential storage via the space's ObjectTree.
-	// Callers should migrate to CredentialTreeManager.AddCredential directly.
-	treeMgr := NewCredentialTreeManager(c, nil)
 	keys, err := LoadSpaceKeySet(c.dataDir, spaceID)
 	if err != nil {
-		return fmt.Errorf("loading space keys for tree sync: %w", err)
-	}
-
-	payload := &CredentialPayload{
-		SAID:      docID,
-		Data:      data,
-		Timestamp: time.Now().Unix(),
+		return nil, fmt.Errorf("loading space keys for tree sync: %w", err)
 	}
 
-	_, err = treeMgr.AddCredential(ctx, spaceID, payload, keys.SigningKey)
+	heads, err = NewObjectTreeManager(c, nil, c.trees).PutDocument(ctx, spaceID, docID, data, keys.SigningKey)
 	if err != nil {
-		return fmt.Errorf("adding credential via tree manager: %w", err)
+		return nil, fmt.Errorf("writing document %s: %w", docID, err)
 	}
-
-	return nil
+	return heads, nil
 }
 
 // Ping tests connectivity to the any-sync coordinator
@@ -585,6 +645,7 @@ func (c *SDKClient) Ping() error {
 			strings.Contains(errStr, "unknown") {
 			return nil
 		}
+		metrics.CountCoordinatorPingFailure()
 		return fmt.Errorf("coordinator unreachable: %w", err)
 	}
 	return nil
@@ -631,7 +692,7 @@ func (c *SDKClient) GetSigningKey() crypto.PrivKey {
 // with a mnemonic-derived key, and restarts the SDK with the new identity.
 // This is called by POST /api/v1/identity/set when the user's identity is
 // established (org setup, registration, or claim flow).
-func (c *SDKClient) Reinitialize(mnemonic string) error {
+func (c *SDKClient) Reinitialize(mnemonic *secret.Mnemonic) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -649,7 +710,7 @@ func (c *SDKClient) Reinitialize(mnemonic string) error {
 	}
 
 	// 2. Derive new peer key from mnemonic
-	privKey, err := DeriveKeyFromMnemonic(mnemonic, 0)
+	privKey, err := DeriveKeyFromSecret(mnemonic, 0)
 	if err != nil {
 		return fmt.Errorf("deriving key from mnemonic: %w", err)
 	}
@@ -667,7 +728,7 @@ func (c *SDKClient) Reinitialize(mnemonic string) error {
 	// 4. Create new PeerKeyManager with the derived key
 	peerMgr, err := NewPeerKeyManager(&PeerKeyConfig{
 		KeyPath:  keyPath,
-		Mnemonic: mnemonic,
+		Mnemonic: mnemonic.Reveal(),
 		KeyIndex: 0,
 	})
 	if err != nil {
@@ -692,6 +753,218 @@ func (c *SDKClient) GetPeerKeyManager() *PeerKeyManager {
 	return c.peerKeyManager
 }
 
+// ForgetTrees drops the cached document tree for a space, after its trees
+// were replaced (see SpaceManager.ReencryptSpace)
+func (c *SDKClient) ForgetTrees(spaceID string) {
+	c.trees.Delete(spaceID)
+}
+
+// MarkSpaceDeleted asks the coordinator to delete a space. The request is
+// signed with the account key, so only spaces this backend's account owns
+// can be deleted.
+func (c *SDKClient) MarkSpaceDeleted(ctx context.Context, spaceID string) (err error) {
+	defer metrics.ObserveSpaceOperation("delete_space", time.Now(), &err)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.initialized {
+		return fmt.Errorf("client not initialized")
+	}
+
+	conf, err := coordinatorproto.PrepareDeleteConfirmation(c.peerKeyManager.GetPrivKey(), spaceID, c.peerKeyManager.GetPeerID(), c.networkID)
+	if err != nil {
+		return fmt.Errorf("signing deletion: %w", err)
+	}
+	if err := c.coordinator.SpaceDelete(ctx, spaceID, conf); err != nil {
+		return fmt.Errorf("deleting space on coordinator: %w", err)
+	}
+
+	fmt.Printf("[any-sync SDK] MarkSpaceDeleted: %s\n", spaceID)
+	return nil
+}
+
+// RemoveSpaceStorage closes a space and removes its local storage. The
+// space is left as it is on the network, and is fetched again if reopened.
+func (c *SDKClient) RemoveSpaceStorage(ctx context.Context, spaceID string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.initialized {
+		return fmt.Errorf("client not initialized")
+	}
+
+	c.app.MustComponent(spaceResolverCName).(*sdkSpaceResolver).forget(spaceID)
+	c.trees.Delete(spaceID)
+	return c.storageProvider.(*sdkStorageProvider).DeleteSpaceStorage(ctx, spaceID)
+}
+
+// OrphanedSpaces returns the spaces stored locally that are no longer of
+// use: ones the coordinator has deleted or is deleting, and directories an
+// interrupted create left without a database
+func (c *SDKClient) OrphanedSpaces(ctx context.Context) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.initialized {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	provider := c.storageProvider.(*sdkStorageProvider)
+	ids, err := provider.SpaceIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	var orphaned, stored []string
+	for _, id := range ids {
+		if provider.SpaceExists(id) {
+			stored = append(stored, id)
+		} else {
+			orphaned = append(orphaned, id)
+		}
+	}
+
+	// The coordinator checks a bounded number of spaces per call
+	const batch = 100
+	for start := 0; start < len(stored); start += batch {
+		chunk := stored[start:min(start+batch, len(stored))]
+		statuses, _, err := c.coordinator.StatusCheckMany(ctx, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("checking space status: %w", err)
+		}
+		for i, status := range statuses {
+			switch status.GetStatus() {
+			case coordinatorproto.SpaceStatus_SpaceStatusPendingDeletion,
+				coordinatorproto.SpaceStatus_SpaceStatusDeletionStarted,
+				coordinatorproto.SpaceStatus_SpaceStatusDeleted:
+				if i < len(chunk) {
+					orphaned = append(orphaned, chunk[i])
+				}
+			}
+		}
+	}
+	return orphaned, nil
+}
+
+// LocalSpaces reports on every space stored locally: whether it is open,
+// its trees and head state, when a peer last pushed it a HeadUpdate, and
+// its status on the coordinator. If the coordinator can't be reached the
+// spaces are still reported, with an unknown coordinator status.
+func (c *SDKClient) LocalSpaces(ctx context.Context) ([]*LocalSpaceState, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.initialized {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	provider := c.storageProvider.(*sdkStorageProvider)
+	ids, err := provider.SpaceIDs()
+	if err != nil {
+		return nil, err
+	}
+	open := make(map[string]bool)
+	for _, id := range c.app.MustComponent(spaceResolverCName).(*sdkSpaceResolver).spaceIDs() {
+		open[id] = true
+	}
+	c.headMu.RLock()
+	lastHead := make(map[string]time.Time, len(c.lastHead))
+	for id, t := range c.lastHead {
+		lastHead[id] = t
+	}
+	c.headMu.RUnlock()
+
+	states := make([]*LocalSpaceState, 0, len(ids))
+	var stored []*LocalSpaceState
+	for _, id := range ids {
+		state := &LocalSpaceState{
+			SpaceID:        id,
+			Open:           open[id],
+			LastHeadUpdate: lastHead[id],
+		}
+		states = append(states, state)
+		if !provider.SpaceExists(id) {
+			state.CoordinatorStatus = SpaceStatusIncomplete
+			continue
+		}
+		stored = append(stored, state)
+
+		storage, err := provider.WaitSpaceStorage(ctx, id)
+		if err != nil {
+			state.Error = err.Error()
+			continue
+		}
+		if spaceState, err := storage.StateStorage().GetState(ctx); err == nil {
+			state.HeadsHash = spaceState.NewHash
+		}
+		err = storage.HeadStorage().IterateEntries(ctx, headstorage.IterOpts{}, func(entry headstorage.HeadsEntry) (bool, error) {
+			state.Trees++
+			return true, nil
+		})
+		if err != nil {
+			state.Error = err.Error()
+		}
+	}
+
+	// The coordinator checks a bounded number of spaces per call
+	const batch = 100
+	for start := 0; start < len(stored); start += batch {
+		chunk := stored[start:min(start+batch, len(stored))]
+		chunkIDs := make([]string, len(chunk))
+		for i, state := range chunk {
+			chunkIDs[i] = state.SpaceID
+		}
+		statuses, _, err := c.coordinator.StatusCheckMany(ctx, chunkIDs)
+		if err != nil {
+			fmt.Printf("[any-sync SDK] LocalSpaces: checking space status: %v\n", err)
+			for _, state := range chunk {
+				state.CoordinatorStatus = SpaceStatusUnknown
+			}
+			continue
+		}
+		for i, state := range chunk {
+			if i < len(statuses) {
+				state.CoordinatorStatus = coordinatorStatusName(statuses[i].GetStatus())
+			} else {
+				state.CoordinatorStatus = SpaceStatusUnknown
+			}
+		}
+	}
+	return states, nil
+}
+
+// OnHeadUpdate registers fn to be called with the space ID each time a peer
+// pushes a HeadUpdate for an open space. The update has only been queued
+// for the space's sync handler, so the new changes may take a moment to
+// land in the tree. fn runs on the stream's goroutine and must not block.
+func (c *SDKClient) OnHeadUpdate(fn func(spaceID string)) {
+	c.headMu.Lock()
+	defer c.headMu.Unlock()
+	c.headListeners = append(c.headListeners, fn)
+}
+
+// SetPlacementSource sets where new spaces are placed, by space type.
+// Derived spaces keep their derived placement.
+func (c *SDKClient) SetPlacementSource(source PlacementSource) {
+	c.placementMu.Lock()
+	defer c.placementMu.Unlock()
+	c.placement = source
+}
+
+func (c *SDKClient) notifyHeadUpdate(spaceID string) {
+	c.headMu.Lock()
+	if c.lastHead == nil {
+		c.lastHead = make(map[string]time.Time)
+	}
+	c.lastHead[spaceID] = time.Now().UTC()
+	listeners := c.headListeners
+	c.headMu.Unlock()
+	for _, fn := range listeners {
+		fn(spaceID)
+	}
+}
+
 // Close shuts down the SDK client
 func (c *SDKClient) Close() error {
 	c.mu.Lock()
@@ -823,11 +1096,72 @@ func (r *sdkSpaceResolver) GetSpace(ctx context.Context, spaceId string) (common
 		return nil, err
 	}
 	r.cache.Store(spaceId, sp)
+	r.subscribe(spaceId)
 	return sp, nil
 }
 
 func (r *sdkSpaceResolver) StoreSpace(spaceId string, space commonspace.Space) {
 	r.cache.Store(spaceId, space)
+	r.subscribe(spaceId)
+}
+
+// forget closes an open space and drops it from the cache
+func (r *sdkSpaceResolver) forget(spaceId string) {
+	if val, ok := r.cache.LoadAndDelete(spaceId); ok {
+		if err := val.(commonspace.Space).Close(); err != nil {
+			fmt.Printf("[any-sync SDK] Closing space %s: %v\n", spaceId, err)
+		}
+	}
+}
+
+// spaceIDs returns the IDs of all opened spaces
+func (r *sdkSpaceResolver) spaceIDs() []string {
+	var ids []string
+	r.cache.Range(func(key, _ any) bool {
+		ids = append(ids, key.(string))
+		return true
+	})
+	return ids
+}
+
+// subscribe asks the space's responsible nodes to push its HeadUpdates on
+// our streams. Streams opened later subscribe to every open space themselves
+// (see sdkStreamHandler.OpenStream). Best-effort: HeadSync still picks up
+// changes if the subscription fails.
+func (r *sdkSpaceResolver) subscribe(spaceId string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		msg, err := spaceSubscription(spaceId)
+		if err != nil {
+			fmt.Printf("[any-sync SDK] Building subscription for space %s: %v\n", spaceId, err)
+			return
+		}
+		provider := r.a.MustComponent(peermanager.CName).(peermanager.PeerManagerProvider)
+		pm, err := provider.NewPeerManager(ctx, spaceId)
+		if err != nil {
+			fmt.Printf("[any-sync SDK] Subscribing to space %s: %v\n", spaceId, err)
+			return
+		}
+		if err := pm.BroadcastMessage(ctx, msg); err != nil {
+			fmt.Printf("[any-sync SDK] Subscribing to space %s: %v\n", spaceId, err)
+		}
+	}()
+}
+
+// spaceSubscription builds the message that subscribes a stream to spaces'
+// HeadUpdates. Nodes only push HeadUpdates on streams tagged with the space.
+func spaceSubscription(spaceIds ...string) (*spacesyncproto.ObjectSyncMessage, error) {
+	sub := &spacesyncproto.SpaceSubscription{
+		SpaceIds: spaceIds,
+		Action:   spacesyncproto.SpaceSubscriptionAction_Subscribe,
+	}
+	payload, err := sub.MarshalVT()
+	if err != nil {
+		return nil, fmt.Errorf("marshaling space subscription: %w", err)
+	}
+	return &spacesyncproto.ObjectSyncMessage{Payload: payload}, nil
 }
 
 // sdkNodeConf implements nodeconf.Service with full configuration
@@ -1013,6 +1347,37 @@ func (p *sdkStorageProvider) SpaceExists(id string) bool {
 	return err == nil
 }
 
+// DeleteSpaceStorage closes a space's database and removes its directory
+func (p *sdkStorageProvider) DeleteSpaceStorage(ctx context.Context, id string) error {
+	if id == "" || filepath.Base(id) != id || id == "." || id == ".." {
+		return fmt.Errorf("invalid space ID %q", id)
+	}
+	if s, ok := p.spaces.LoadAndDelete(id); ok {
+		if err := s.(spacestorage.SpaceStorage).AnyStore().Close(); err != nil {
+			fmt.Printf("[any-sync SDK] Closing space database %s: %v\n", id, err)
+		}
+	}
+	if err := os.RemoveAll(filepath.Join(p.rootPath, id)); err != nil {
+		return fmt.Errorf("removing space storage %s: %w", id, err)
+	}
+	return nil
+}
+
+// SpaceIDs lists the spaces with a directory on disk
+func (p *sdkStorageProvider) SpaceIDs() ([]string, error) {
+	entries, err := os.ReadDir(p.rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading spaces directory: %w", err)
+	}
+	var ids []string
+	for _, e := range entries {
+		if e.IsDir() {
+			ids = append(ids, e.Name())
+		}
+	}
+	return ids, nil
+}
+
 // sdkPeerManagerProvider implements peermanager.PeerManagerProvider using real
 // network components for peer discovery and HeadUpdate broadcasting.
 type sdkPeerManagerProvider struct {
@@ -1171,15 +1536,18 @@ func (t *sdkTreeManager) DeleteTree(ctx context.Context, spaceId, treeId string)
 }
 
 // sdkStreamHandler implements streamhandler.StreamHandler for P2P sync.
-// It opens ObjectSyncStream DRPC streams and routes incoming HeadUpdate
-// messages to the correct space's sync service. Uses sdkSpaceResolver to
+// It opens ObjectSyncStream DRPC streams subscribed to the open spaces and
+// routes incoming HeadUpdate messages to the correct space's sync service. Uses sdkSpaceResolver to
 // share Space instances with other components.
 type sdkStreamHandler struct {
 	resolver   *sdkSpaceResolver
 	streamPool streampool.StreamPool
+	onHead     func(spaceID string)
 }
 
-func newSDKStreamHandler() *sdkStreamHandler { return &sdkStreamHandler{} }
+func newSDKStreamHandler(onHead func(spaceID string)) *sdkStreamHandler {
+	return &sdkStreamHandler{onHead: onHead}
+}
 
 func (s *sdkStreamHandler) Init(a *app.App) error {
 	s.resolver = a.MustComponent(spaceResolverCName).(*sdkSpaceResolver)
@@ -1200,6 +1568,18 @@ func (s *sdkStreamHandler) OpenStream(ctx context.Context, p peer.Peer) (drpc.St
 		return nil, nil, 0, err
 	}
 
+	// Subscribe the new stream to every open space so the node pushes their
+	// HeadUpdates to us instead of waiting for the next HeadSync
+	if spaceIds := s.resolver.spaceIDs(); len(spaceIds) > 0 {
+		msg, err := spaceSubscription(spaceIds...)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if err := stream.Send(msg); err != nil {
+			return nil, nil, 0, fmt.Errorf("subscribing stream to spaces: %w", err)
+		}
+	}
+
 	return stream, nil, 200, nil
 }
 
@@ -1227,7 +1607,13 @@ func (s *sdkStreamHandler) HandleMessage(ctx context.Context, peerId string, msg
 	if err != nil {
 		return fmt.Errorf("getting space %s: %w", spaceId, err)
 	}
-	return space.HandleMessage(ctx, headUpdate)
+	if err := space.HandleMessage(ctx, headUpdate); err != nil {
+		return err
+	}
+	if s.onHead != nil {
+		s.onHead(spaceId)
+	}
+	return nil
 }
 
 func (s *sdkStreamHandler) NewReadMessage() drpc.Message {
@@ -0,0 +1,132 @@
+// Package anysync provides any-sync integration for MATOU.
+// space_gc.go removes spaces: deleting one outright, and collecting local
+// storage left behind by spaces that no longer exist on the network.
+package anysync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var (
+	// ErrProtectedSpace is returned when asked to delete one of the org's
+	// own spaces, which the backend needs to run
+	ErrProtectedSpace = errors.New("space is one of the org's spaces and can't be deleted")
+	// ErrSpaceRemovalUnsupported is returned when the client keeps no
+	// local space storage to remove (e.g. the mock client)
+	ErrSpaceRemovalUnsupported = errors.New("client does not support removing spaces")
+)
+
+// spaceIDPattern matches space IDs, which name directories on disk
+var spaceIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// spaceRemover is implemented by clients that keep spaces in local storage
+// (SDKClient)
+type spaceRemover interface {
+	MarkSpaceDeleted(ctx context.Context, spaceID string) error
+	RemoveSpaceStorage(ctx context.Context, spaceID string) error
+	OrphanedSpaces(ctx context.Context) ([]string, error)
+}
+
+// SpaceDeletion is the outcome of deleting a space
+type SpaceDeletion struct {
+	SpaceID string `json:"spaceId"`
+	// MarkedDeleted is whether the coordinator was asked to delete the
+	// space; false when only the local copy was removed
+	MarkedDeleted bool `json:"markedDeleted"`
+	FilesUnbound  int  `json:"filesUnbound"`
+	// FileError is why the space's files couldn't be unbound, if they
+	// couldn't. The space is deleted regardless.
+	FileError string `json:"fileError,omitempty"`
+}
+
+// DeleteSpace deletes a space. The coordinator is asked to delete it first,
+// as only the owner can, so a space this backend doesn't own is left
+// untouched. Its files are then unbound from the filenode, and its local
+// storage and key set removed. With localOnly the space is left on the
+// network and only the local copy is removed; it is fetched again if the
+// space is used later.
+func (m *SpaceManager) DeleteSpace(ctx context.Context, spaceID string, localOnly bool) (*SpaceDeletion, error) {
+	if !spaceIDPattern.MatchString(spaceID) || spaceID == "." || spaceID == ".." {
+		return nil, fmt.Errorf("invalid space ID %q", spaceID)
+	}
+	if m.isOrgSpace(spaceID) {
+		return nil, ErrProtectedSpace
+	}
+	remover, ok := m.client.(spaceRemover)
+	if !ok {
+		return nil, ErrSpaceRemovalUnsupported
+	}
+
+	result := &SpaceDeletion{SpaceID: spaceID}
+	if !localOnly {
+		if err := remover.MarkSpaceDeleted(ctx, spaceID); err != nil {
+			return nil, err
+		}
+		result.MarkedDeleted = true
+
+		if m.fileManager != nil {
+			n, err := m.fileManager.UnbindSpaceFiles(ctx, spaceID)
+			if err != nil {
+				fmt.Printf("[SpaceManager] Warning: failed to unbind files of %s: %v\n", spaceID, err)
+				result.FileError = err.Error()
+			}
+			result.FilesUnbound = n
+		}
+	}
+
+	m.treeCache.Delete(spaceID)
+	if err := remover.RemoveSpaceStorage(ctx, spaceID); err != nil {
+		return result, fmt.Errorf("removing local storage: %w", err)
+	}
+	if !localOnly {
+		if err := RemoveSpaceKeySet(m.client.GetDataDir(), spaceID); err != nil {
+			return result, err
+		}
+	}
+
+	fmt.Printf("[SpaceManager] Deleted space %s (local only: %v, files unbound: %d)\n",
+		spaceID, localOnly, result.FilesUnbound)
+	return result, nil
+}
+
+// CollectGarbage removes the local storage of spaces the coordinator has
+// deleted, and of directories an interrupted space creation left behind.
+// Key sets are kept, as they are small and a space's keys can't be
+// recovered once removed. Returns the spaces whose storage was removed.
+func (m *SpaceManager) CollectGarbage(ctx context.Context) ([]string, error) {
+	remover, ok := m.client.(spaceRemover)
+	if !ok {
+		return nil, nil
+	}
+	orphaned, err := remover.OrphanedSpaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, spaceID := range orphaned {
+		if m.isOrgSpace(spaceID) {
+			fmt.Printf("[SpaceManager] Warning: org space %s is deleted on the coordinator, keeping local copy\n", spaceID)
+			continue
+		}
+		m.treeCache.Delete(spaceID)
+		if err := remover.RemoveSpaceStorage(ctx, spaceID); err != nil {
+			fmt.Printf("[SpaceManager] Warning: failed to remove storage of %s: %v\n", spaceID, err)
+			continue
+		}
+		removed = append(removed, spaceID)
+	}
+	return removed, nil
+}
+
+// isOrgSpace reports whether a space is one of the org's own spaces
+func (m *SpaceManager) isOrgSpace(spaceID string) bool {
+	switch spaceID {
+	case m.GetCommunitySpaceID(), m.GetCommunityReadOnlySpaceID(), m.GetAdminSpaceID():
+		return true
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+// Package anysync provides any-sync integration for MATOU.
+// space_status.go reports on the spaces this backend knows about, from its
+// space records and its local space storage.
+package anysync
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/anyproto/any-sync/coordinator/coordinatorproto"
+)
+
+// Coordinator statuses of a space, as reported in LocalSpaceState
+const (
+	SpaceStatusCreated         = "created"
+	SpaceStatusPendingDeletion = "pendingDeletion"
+	SpaceStatusDeletionStarted = "deletionStarted"
+	SpaceStatusDeleted         = "deleted"
+	SpaceStatusNotExists       = "notExists"
+	// SpaceStatusIncomplete is a space directory without a database, left
+	// by an interrupted create. The coordinator isn't asked about it.
+	SpaceStatusIncomplete = "incomplete"
+	// SpaceStatusUnknown is reported when the coordinator couldn't be asked
+	SpaceStatusUnknown = "unknown"
+)
+
+// coordinatorStatusName returns the name of a coordinator space status
+func coordinatorStatusName(status coordinatorproto.SpaceStatus) string {
+	switch status {
+	case coordinatorproto.SpaceStatus_SpaceStatusCreated:
+		return SpaceStatusCreated
+	case coordinatorproto.SpaceStatus_SpaceStatusPendingDeletion:
+		return SpaceStatusPendingDeletion
+	case coordinatorproto.SpaceStatus_SpaceStatusDeletionStarted:
+		return SpaceStatusDeletionStarted
+	case coordinatorproto.SpaceStatus_SpaceStatusDeleted:
+		return SpaceStatusDeleted
+	case coordinatorproto.SpaceStatus_SpaceStatusNotExists:
+		return SpaceStatusNotExists
+	}
+	return SpaceStatusUnknown
+}
+
+// LocalSpaceState is the state of a space's local storage
+type LocalSpaceState struct {
+	SpaceID string
+	// Open is whether the space has been opened since startup, and so
+	// takes part in HeadSync
+	Open bool
+	// HeadsHash is the space's hash over all its trees' heads, the value
+	// HeadSync compares with peers. Equal hashes mean the same heads.
+	HeadsHash         string
+	Trees             int
+	LastHeadUpdate    time.Time
+	CoordinatorStatus string
+	// Error is why the space's storage couldn't be read, if it couldn't
+	Error string
+}
+
+// spaceLister is implemented by clients that keep spaces in local storage
+// (SDKClient)
+type spaceLister interface {
+	LocalSpaces(ctx context.Context) ([]*LocalSpaceState, error)
+}
+
+// SpaceStatus describes a space known to this backend, from its space
+// record, its local storage or both
+type SpaceStatus struct {
+	SpaceID   string `json:"spaceId"`
+	SpaceType string `json:"spaceType,omitempty"`
+	SpaceName string `json:"spaceName,omitempty"`
+	OwnerAID  string `json:"ownerAid,omitempty"`
+	// Recorded is whether the space has a space record
+	Recorded bool `json:"recorded"`
+	// Stored is whether the space has local storage
+	Stored            bool       `json:"stored"`
+	Open              bool       `json:"open"`
+	HeadsHash         string     `json:"headsHash,omitempty"`
+	Trees             int        `json:"trees"`
+	CreatedAt         *time.Time `json:"createdAt,omitempty"`
+	LastSync          *time.Time `json:"lastSync,omitempty"`
+	CoordinatorStatus string     `json:"coordinatorStatus,omitempty"`
+	Error             string     `json:"error,omitempty"`
+}
+
+// ListSpaces describes every space in records or in local storage. The
+// org's own spaces are typed even without a record. LastSync is the later
+// of the record's sync time and the last HeadUpdate from a peer. Spaces
+// are sorted by type, then ID.
+func (m *SpaceManager) ListSpaces(ctx context.Context, records []*Space) ([]*SpaceStatus, error) {
+	byID := make(map[string]*SpaceStatus)
+	status := func(spaceID string) *SpaceStatus {
+		s, ok := byID[spaceID]
+		if !ok {
+			s = &SpaceStatus{SpaceID: spaceID}
+			byID[spaceID] = s
+		}
+		return s
+	}
+
+	for spaceType, spaceID := range map[string]string{
+		SpaceTypeCommunity:         m.GetCommunitySpaceID(),
+		SpaceTypeCommunityReadOnly: m.GetCommunityReadOnlySpaceID(),
+		SpaceTypeAdmin:             m.GetAdminSpaceID(),
+	} {
+		if spaceID != "" {
+			s := status(spaceID)
+			s.SpaceType = spaceType
+			s.OwnerAID = m.orgAID
+		}
+	}
+
+	for _, record := range records {
+		s := status(record.SpaceID)
+		s.Recorded = true
+		if record.SpaceType != "" {
+			s.SpaceType = record.SpaceType
+		}
+		s.SpaceName = record.SpaceName
+		if record.OwnerAID != "" {
+			s.OwnerAID = record.OwnerAID
+		}
+		if !record.CreatedAt.IsZero() {
+			createdAt := record.CreatedAt
+			s.CreatedAt = &createdAt
+		}
+		if !record.LastSync.IsZero() {
+			lastSync := record.LastSync
+			s.LastSync = &lastSync
+		}
+	}
+
+	if lister, ok := m.client.(spaceLister); ok {
+		local, err := lister.LocalSpaces(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, state := range local {
+			s := status(state.SpaceID)
+			s.Stored = true
+			s.Open = state.Open
+			s.HeadsHash = state.HeadsHash
+			s.Trees = state.Trees
+			s.CoordinatorStatus = state.CoordinatorStatus
+			s.Error = state.Error
+			if !state.LastHeadUpdate.IsZero() && (s.LastSync == nil || state.LastHeadUpdate.After(*s.LastSync)) {
+				lastHead := state.LastHeadUpdate
+				s.LastSync = &lastHead
+			}
+		}
+	}
+
+	spaces := make([]*SpaceStatus, 0, len(byID))
+	for _, s := range byID {
+		spaces = append(spaces, s)
+	}
+	sort.Slice(spaces, func(i, j int) bool {
+		if spaces[i].SpaceType != spaces[j].SpaceType {
+			return spaces[i].SpaceType < spaces[j].SpaceType
+		}
+		return spaces[i].SpaceID < spaces[j].SpaceID
+	})
+	return spaces, nil
+}
@@ -0,0 +1,86 @@
+package anysync
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// listingClient is a mock client with local space storage
+type listingClient struct {
+	*mockAnySyncClient
+	local []*LocalSpaceState
+}
+
+func (c *listingClient) LocalSpaces(ctx context.Context) ([]*LocalSpaceState, error) {
+	return c.local, nil
+}
+
+func TestSpaceManager_ListSpaces(t *testing.T) {
+	recordSync := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	headUpdate := recordSync.Add(time.Hour)
+	client := &listingClient{
+		mockAnySyncClient: newMockAnySyncClient(),
+		local: []*LocalSpaceState{
+			{SpaceID: "community-space", Open: true, HeadsHash: "hash-1", Trees: 3, LastHeadUpdate: headUpdate, CoordinatorStatus: SpaceStatusCreated},
+			{SpaceID: "private-space", CoordinatorStatus: SpaceStatusDeleted},
+			{SpaceID: "half-created", CoordinatorStatus: SpaceStatusIncomplete},
+		},
+	}
+	manager := NewSpaceManager(client, &SpaceManagerConfig{
+		CommunitySpaceID: "community-space",
+		OrgAID:           "EORG123",
+	})
+
+	spaces, err := manager.ListSpaces(context.Background(), []*Space{
+		{SpaceID: "private-space", OwnerAID: "EUSER1", SpaceType: SpaceTypePrivate, LastSync: recordSync},
+		{SpaceID: "community-space", LastSync: recordSync},
+	})
+	if err != nil {
+		t.Fatalf("ListSpaces failed: %v", err)
+	}
+	if len(spaces) != 3 {
+		t.Fatalf("expected 3 spaces, got %d", len(spaces))
+	}
+
+	byID := make(map[string]*SpaceStatus)
+	for _, s := range spaces {
+		byID[s.SpaceID] = s
+	}
+	community := byID["community-space"]
+	if community.SpaceType != SpaceTypeCommunity || community.OwnerAID != "EORG123" {
+		t.Errorf("expected the org's community space typed, got %+v", community)
+	}
+	if !community.Recorded || !community.Stored || !community.Open || community.Trees != 3 {
+		t.Errorf("expected the record and storage merged, got %+v", community)
+	}
+	if community.LastSync == nil || !community.LastSync.Equal(headUpdate) {
+		t.Errorf("expected the later HeadUpdate as last sync, got %v", community.LastSync)
+	}
+
+	private := byID["private-space"]
+	if private.OwnerAID != "EUSER1" || private.CoordinatorStatus != SpaceStatusDeleted {
+		t.Errorf("expected the private space's owner and status, got %+v", private)
+	}
+	if private.LastSync == nil || !private.LastSync.Equal(recordSync) {
+		t.Errorf("expected the record's sync time, got %v", private.LastSync)
+	}
+
+	if half := byID["half-created"]; half.Recorded || half.SpaceType != "" {
+		t.Errorf("expected an unrecorded space left untyped, got %+v", half)
+	}
+}
+
+func TestSpaceManager_ListSpaces_NoLocalStorage(t *testing.T) {
+	manager := NewSpaceManager(newMockAnySyncClient(), &SpaceManagerConfig{OrgAID: "EORG123"})
+
+	spaces, err := manager.ListSpaces(context.Background(), []*Space{
+		{SpaceID: "private-space", OwnerAID: "EUSER1", SpaceType: SpaceTypePrivate},
+	})
+	if err != nil {
+		t.Fatalf("ListSpaces failed: %v", err)
+	}
+	if len(spaces) != 1 || spaces[0].Stored || !spaces[0].Recorded {
+		t.Errorf("expected only the recorded space, got %+v", spaces)
+	}
+}
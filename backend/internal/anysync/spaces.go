@@ -9,6 +9,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/anyproto/any-sync/util/crypto"
 )
 
 // Space types
@@ -17,6 +19,8 @@ const (
 	SpaceTypeCommunity         = "community"
 	SpaceTypeCommunityReadOnly = "community-readonly"
 	SpaceTypeAdmin             = "admin"
+	SpaceTypeProject           = "project"
+	SpaceTypeInbox             = "inbox"
 )
 
 // Space represents an any-sync space
@@ -35,6 +39,8 @@ type SpaceManager struct {
 	aclManager               *MatouACLManager
 	credTreeManager          *CredentialTreeManager
 	objTreeManager           *ObjectTreeManager
+	receiptTreeManager       *ReceiptTreeManager
+	auditTreeManager         *AuditTreeManager
 	fileManager              *FileManager
 	treeCache                *TreeCache
 	communitySpaceID         string
@@ -70,6 +76,8 @@ func NewSpaceManager(client AnySyncClient, cfg *SpaceManagerConfig) *SpaceManage
 		aclManager:               NewMatouACLManager(client, nil),
 		credTreeManager:          NewCredentialTreeManager(client, nil, cache),
 		objTreeManager:           objTreeMgr,
+		receiptTreeManager:       NewReceiptTreeManager(client),
+		auditTreeManager:         NewAuditTreeManager(client),
 		fileManager:              fileMgr,
 		treeCache:                cache,
 		communitySpaceID:         cfg.CommunitySpaceID,
@@ -89,6 +97,16 @@ func (m *SpaceManager) CredentialTreeManager() *CredentialTreeManager {
 	return m.credTreeManager
 }
 
+// ReceiptTreeManager returns the credential receipt ledger manager.
+func (m *SpaceManager) ReceiptTreeManager() *ReceiptTreeManager {
+	return m.receiptTreeManager
+}
+
+// AuditTreeManager returns the shared audit log manager.
+func (m *SpaceManager) AuditTreeManager() *AuditTreeManager {
+	return m.auditTreeManager
+}
+
 // ObjectTreeManager returns the object tree manager.
 func (m *SpaceManager) ObjectTreeManager() *ObjectTreeManager {
 	return m.objTreeManager
@@ -160,6 +178,72 @@ func (m *SpaceManager) CreatePrivateSpace(ctx context.Context, userAID string) (
 	return space, nil
 }
 
+// CreateProjectSpace creates a shared space for a project or working group.
+// The local peer key signs the space so this backend can issue invites to
+// project members; the key set is persisted for later object and file writes.
+func (m *SpaceManager) CreateProjectSpace(ctx context.Context, ownerAID, projectName string) (*Space, error) {
+	if ownerAID == "" {
+		return nil, fmt.Errorf("owner AID is required")
+	}
+	if m.client == nil {
+		return nil, fmt.Errorf("any-sync client not available")
+	}
+
+	keys, err := GenerateSpaceKeySet()
+	if err != nil {
+		return nil, fmt.Errorf("generating project space keys: %w", err)
+	}
+	keys.SigningKey = m.client.GetSigningKey()
+
+	result, err := m.client.CreateSpaceWithKeys(ctx, ownerAID, SpaceTypeProject, keys)
+	if err != nil {
+		return nil, fmt.Errorf("creating project space: %w", err)
+	}
+
+	// Required before CreateOpenInvite can be used for members
+	if err := m.client.MakeSpaceShareable(ctx, result.SpaceID); err != nil {
+		fmt.Printf("Warning: failed to make project space shareable: %v\n", err)
+	}
+
+	if err := PersistSpaceKeySet(m.client.GetDataDir(), result.SpaceID, keys); err != nil {
+		return nil, fmt.Errorf("persisting project space keys: %w", err)
+	}
+
+	return &Space{
+		SpaceID:   result.SpaceID,
+		OwnerAID:  ownerAID,
+		SpaceType: SpaceTypeProject,
+		SpaceName: projectName,
+		CreatedAt: result.CreatedAt,
+		LastSync:  result.CreatedAt,
+	}, nil
+}
+
+// JoinProjectSpace joins a project space with an invite key from a project
+// lead and persists a key set so this backend can write to it.
+func (m *SpaceManager) JoinProjectSpace(ctx context.Context, spaceID string, inviteKey crypto.PrivKey, metadata []byte) error {
+	if m.client == nil {
+		return fmt.Errorf("any-sync client not available")
+	}
+
+	if err := m.client.MakeSpaceShareable(ctx, spaceID); err != nil {
+		fmt.Printf("Warning: failed to make project space shareable: %v\n", err)
+	}
+	if err := m.aclManager.JoinWithInvite(ctx, spaceID, inviteKey, metadata); err != nil {
+		return fmt.Errorf("joining project space: %w", err)
+	}
+
+	keys, err := GenerateSpaceKeySet()
+	if err != nil {
+		return fmt.Errorf("generating project space keys: %w", err)
+	}
+	keys.SigningKey = m.client.GetSigningKey()
+	if err := PersistSpaceKeySet(m.client.GetDataDir(), spaceID, keys); err != nil {
+		return fmt.Errorf("persisting project space keys: %w", err)
+	}
+	return nil
+}
+
 // GetOrCreatePrivateSpace gets an existing private space or creates a new one
 // The spaceStore parameter is used to check/store space records
 func (m *SpaceManager) GetOrCreatePrivateSpace(ctx context.Context, userAID string, spaceStore SpaceStore) (*Space, error) {
@@ -318,7 +402,8 @@ func (m *SpaceManager) addCredToSpace(ctx context.Context, spaceID string, cred
 		return fmt.Errorf("marshaling credential: %w", err)
 	}
 
-	return m.client.SyncDocument(ctx, spaceID, cred.SAID, data)
+	_, err = m.client.SyncDocument(ctx, spaceID, cred.SAID, data)
+	return err
 }
 
 // RouteCredential determines where a credential should be stored and syncs it.
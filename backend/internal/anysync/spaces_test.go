@@ -72,9 +72,12 @@ func (m *mockAnySyncClient) AddToACL(ctx context.Context, spaceID string, peerID
 	return m.addToACLErr
 }
 
-func (m *mockAnySyncClient) SyncDocument(ctx context.Context, spaceID string, docID string, data []byte) error {
+func (m *mockAnySyncClient) SyncDocument(ctx context.Context, spaceID string, docID string, data []byte) ([]string, error) {
 	m.syncDocumentCalls = append(m.syncDocumentCalls, syncDocCall{SpaceID: spaceID, DocID: docID, Data: data})
-	return m.syncDocErr
+	if m.syncDocErr != nil {
+		return nil, m.syncDocErr
+	}
+	return []string{"head-" + docID}, nil
 }
 
 func (m *mockAnySyncClient) MakeSpaceShareable(_ context.Context, _ string) error { return nil }
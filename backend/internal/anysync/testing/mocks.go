@@ -223,7 +223,7 @@ func (m *MockAnySyncClient) AddToACL(ctx context.Context, spaceID string, peerID
 }
 
 // SyncDocument implements AnySyncClient.SyncDocument
-func (m *MockAnySyncClient) SyncDocument(ctx context.Context, spaceID string, docID string, data []byte) error {
+func (m *MockAnySyncClient) SyncDocument(ctx context.Context, spaceID string, docID string, data []byte) ([]string, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -234,7 +234,7 @@ func (m *MockAnySyncClient) SyncDocument(ctx context.Context, spaceID string, do
 	})
 
 	if m.SyncDocumentError != nil {
-		return m.SyncDocumentError
+		return nil, m.SyncDocumentError
 	}
 
 	if _, ok := m.Documents[spaceID]; !ok {
@@ -242,7 +242,7 @@ func (m *MockAnySyncClient) SyncDocument(ctx context.Context, spaceID string, do
 	}
 
 	m.Documents[spaceID][docID] = data
-	return nil
+	return []string{fmt.Sprintf("head-%s-%d", docID, len(m.SyncDocumentCalls))}, nil
 }
 
 // GetNetworkID implements AnySyncClient.GetNetworkID
@@ -0,0 +1,329 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/matou-dao/backend/internal/identity"
+)
+
+// Access tiers
+const (
+	TierAnonymous = "anonymous" // No identity set yet
+	TierGuest     = "guest"     // Identity set, but no membership credential
+	TierMember    = "member"    // Credentialed community member
+)
+
+// tierCacheTTL is how long a resolved tier is reused before the trust
+// graph is checked again
+const tierCacheTTL = 15 * time.Second
+
+// guestRoutes are the requests served to guests and anonymous callers:
+// public and read-only content, plus the writes onboarding and requesting
+// membership need. Everything else is member-only. Each entry is
+// "METHOD path", or a bare path for any method; paths ending in "/" match
+// by prefix, and GET entries also serve HEAD.
+var guestRoutes = []string{
+	// Public and read-only content
+	"GET /info",
+	"GET /.well-known/",
+	"GET /api/v1/access",
+	"GET /api/v1/org",
+	"GET /api/v1/org/config",
+	"GET /api/v1/org/health",
+	"GET /api/v1/public/",
+	"GET /api/v1/types",
+	"GET /api/v1/types/",
+	"GET /api/v1/taxonomy",
+	"GET /api/v1/taxonomy/",
+	"GET /api/v1/schemas",
+	"GET /api/v1/schemas/",
+	"GET /api/v1/grants/summaries",
+	"GET /api/v1/oobi/generate", // applicants resolve the org's OOBI
+	"GET /api/v1/events",
+
+	// The caller's own identity, onboarding and membership request
+	"GET /api/v1/identity",
+	"POST /api/v1/identity/set",
+	"POST /api/v1/identity/backup/import",
+	"GET /api/v1/onboarding/state",
+	"POST /api/v1/onboarding/advance",
+	"GET /api/v1/profiles/me",
+	"POST /api/v1/sync/credentials",
+	"POST /api/v1/sync/kel",
+	"GET /api/v1/inbox", // applicants receive their credentials here
+	"POST /api/v1/inbox",
+	"POST /api/v1/inbox/drain",
+	"POST /api/v1/notifications/registration-submitted",
+	"/api/v1/keria/", // signify needs KERIA before membership exists
+}
+
+// tierRefreshRoutes can change the caller's tier (a new identity or a newly
+// synced membership credential), so the cached tier is dropped after them
+var tierRefreshRoutes = []string{
+	"/api/v1/identity/",
+	"/api/v1/sync/",
+}
+
+// cachedTier is a resolved tier and when it was checked
+type cachedTier struct {
+	tier      string
+	checkedAt time.Time
+}
+
+// AccessControl resolves the caller's access tier and enforces guest
+// restrictions and per-tier rate limits.
+type AccessControl struct {
+	userIdentity *identity.UserIdentity
+	trust        *TrustHandler
+	auth         *Authenticator
+	limits       map[string]int // Requests per minute by tier; 0 is unlimited
+	now          func() time.Time
+
+	mu      sync.Mutex
+	tiers   map[string]cachedTier // By AID
+	limiter *rateLimiter
+}
+
+// rateWindow counts requests in the current one-minute window
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// rateLimiter counts requests per key in fixed one-minute windows
+type rateLimiter struct {
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{windows: make(map[string]*rateWindow)}
+}
+
+// allow records a request for key against limit per minute. Returns false
+// and the time until the window resets when the limit is exceeded.
+func (l *rateLimiter) allow(key string, limit int, now time.Time) (bool, time.Duration) {
+	if limit <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	window := l.windows[key]
+	if window == nil || now.Sub(window.start) >= time.Minute {
+		// Drop stale windows so the map doesn't grow with every client
+		for k, w := range l.windows {
+			if now.Sub(w.start) >= time.Minute {
+				delete(l.windows, k)
+			}
+		}
+		window = &rateWindow{start: now}
+		l.windows[key] = window
+	}
+	if window.count >= limit {
+		return false, window.start.Add(time.Minute).Sub(now)
+	}
+	window.count++
+	return true, 0
+}
+
+// AccessStatus is the response for GET /api/v1/access
+type AccessStatus struct {
+	AID               string `json:"aid,omitempty"`
+	Tier              string `json:"tier"`
+	RequestsPerMinute int    `json:"requestsPerMinute,omitempty"` // 0 when unlimited
+	// CanRequestMembership is true for guests; they apply through
+	// POST /api/v1/notifications/registration-submitted
+	CanRequestMembership bool `json:"canRequestMembership"`
+}
+
+// NewAccessControl creates an access controller. guestLimit and memberLimit
+// are requests per minute; 0 disables the limit. Anonymous callers share the
+// guest limit.
+func NewAccessControl(userIdentity *identity.UserIdentity, trust *TrustHandler, guestLimit, memberLimit int) *AccessControl {
+	return &AccessControl{
+		userIdentity: userIdentity,
+		trust:        trust,
+		limits: map[string]int{
+			TierAnonymous: guestLimit,
+			TierGuest:     guestLimit,
+			TierMember:    memberLimit,
+		},
+		now:     time.Now,
+		tiers:   make(map[string]cachedTier),
+		limiter: newRateLimiter(),
+	}
+}
+
+// SetAuthenticator sets the authenticator whose principals identify callers.
+// Without one, or while authentication is off, the local user is the caller.
+func (a *AccessControl) SetAuthenticator(auth *Authenticator) {
+	a.auth = auth
+}
+
+// Tier returns the caller's access tier
+func (a *AccessControl) Tier(ctx context.Context) string {
+	_, tier := a.caller(ctx)
+	return tier
+}
+
+// caller returns the AID and access tier of the request's caller: the
+// principal the Auth middleware resolved, or the local user when
+// authentication is off. API keys and service tokens belong to operator
+// tooling, not an AID, and are served as members. Before the org is
+// configured there is no membership to check, so every AID is a member.
+func (a *AccessControl) caller(ctx context.Context) (string, string) {
+	aid := ""
+	if p := PrincipalFromContext(ctx); p != nil {
+		if p.AID == "" {
+			return "", TierMember
+		}
+		aid = p.AID
+	} else if (a.auth == nil || !a.auth.Enabled()) && a.userIdentity != nil {
+		aid = a.userIdentity.GetAID()
+	}
+	if aid == "" {
+		return "", TierAnonymous
+	}
+	if a.trust == nil || a.trust.orgAID == "" {
+		return aid, TierMember
+	}
+
+	a.mu.Lock()
+	if cached, ok := a.tiers[aid]; ok && a.now().Sub(cached.checkedAt) < tierCacheTTL {
+		a.mu.Unlock()
+		return aid, cached.tier
+	}
+	a.mu.Unlock()
+
+	tier := TierGuest
+	if graph, err := a.trust.buildGraph(ctx); err != nil {
+		fmt.Printf("[Access] Failed to build trust graph: %v\n", err)
+	} else if node := graph.GetNode(aid); node != nil && node.IsMember() {
+		tier = TierMember
+	}
+
+	a.mu.Lock()
+	a.tiers[aid] = cachedTier{tier: tier, checkedAt: a.now()}
+	a.mu.Unlock()
+	return aid, tier
+}
+
+// Invalidate drops the cached tier so the next request re-checks membership
+func (a *AccessControl) Invalidate(aid string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.tiers, aid)
+}
+
+// Status returns the caller's access status
+func (a *AccessControl) Status(ctx context.Context) AccessStatus {
+	var status AccessStatus
+	status.AID, status.Tier = a.caller(ctx)
+	status.RequestsPerMinute = a.limits[status.Tier]
+	status.CanRequestMembership = status.Tier == TierGuest
+	return status
+}
+
+// allow records a request against the tier's limit for a client. Returns
+// false and the time until the window resets when the limit is exceeded.
+func (a *AccessControl) allow(tier, client string) (bool, time.Duration) {
+	return a.limiter.allow(tier+"|"+client, a.limits[tier], a.now())
+}
+
+// HandleAccess handles GET /api/v1/access
+func (a *AccessControl) HandleAccess(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, a.Status(r.Context()))
+}
+
+// RegisterRoutes registers access routes on the mux
+func (a *AccessControl) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/access", a.HandleAccess)
+}
+
+// guestAllowed reports whether a guest may make the request
+func guestAllowed(r *http.Request) bool {
+	method := r.Method
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	for _, route := range guestRoutes {
+		routeMethod, path, ok := strings.Cut(route, " ")
+		if !ok {
+			routeMethod, path = "", route
+		}
+		if (routeMethod == "" || routeMethod == method) && matchesRoute(r.URL.Path, []string{path}) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesRoute matches a path against exact routes and "/"-suffixed prefixes
+func matchesRoute(path string, routes []string) bool {
+	for _, route := range routes {
+		if path == route || (strings.HasSuffix(route, "/") && strings.HasPrefix(path, route)) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientKey identifies the caller for rate limiting
+func clientKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// AccessMiddleware restricts guests and anonymous callers to guest routes
+// and applies per-tier rate limits. Preflight requests, health checks and
+// metrics scrapes are always served.
+func AccessMiddleware(a *AccessControl, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions || r.URL.Path == "/health" || r.URL.Path == "/metrics" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		aid, tier := a.caller(r.Context())
+		if ok, retry := a.allow(tier, clientKey(r)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retry.Seconds())+1))
+			writeJSON(w, http.StatusTooManyRequests, map[string]string{
+				"error": "rate limit exceeded",
+				"tier":  tier,
+			})
+			return
+		}
+
+		if tier != TierMember && !guestAllowed(r) {
+			writeJSON(w, http.StatusForbidden, map[string]string{
+				"error": "membership required",
+				"tier":  tier,
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+
+		if tier != TierMember && r.Method != http.MethodGet && matchesRoute(r.URL.Path, tierRefreshRoutes) {
+			a.Invalidate(aid)
+		}
+	})
+}
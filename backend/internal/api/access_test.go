@@ -0,0 +1,255 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+func newTestAccessControl(t *testing.T, aid string, trust *TrustHandler, guestLimit int) *AccessControl {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "access_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	userIdentity := identity.New(tmpDir)
+	if aid != "" {
+		if err := userIdentity.SetIdentity(aid, secret.NewMnemonic("test mnemonic")); err != nil {
+			t.Fatalf("SetIdentity failed: %v", err)
+		}
+	}
+	return NewAccessControl(userIdentity, trust, guestLimit, 0)
+}
+
+func TestAccessControl_Tier(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	tests := []struct {
+		name  string
+		aid   string
+		trust *TrustHandler
+		want  string
+	}{
+		{"no identity", "", NewTrustHandler(store, "EORG123", nil), TierAnonymous},
+		{"no membership credential", "EALICE", NewTrustHandler(store, "EORG123", nil), TierGuest},
+		{"org not configured", "EALICE", NewTrustHandler(store, "", nil), TierMember},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			access := newTestAccessControl(t, tt.aid, tt.trust, 0)
+			if got := access.Tier(context.Background()); got != tt.want {
+				t.Errorf("expected tier %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAccessControl_TierFromPrincipal(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	store.StoreCredential(context.Background(), &anystore.CachedCredential{
+		ID:         "ESAID001",
+		IssuerAID:  "EORG123",
+		SubjectAID: "EMEMBER1",
+		SchemaID:   "EMatouMembershipSchemaV1",
+		CachedAt:   time.Now(),
+		Data:       map[string]interface{}{"role": "Member"},
+	})
+
+	// The node's own identity is a member; callers must not inherit it
+	access := newTestAccessControl(t, "EMEMBER1", NewTrustHandler(store, "EORG123", nil), 0)
+	access.SetAuthenticator(NewAuthenticator(AuthOptions{Enabled: true}, nil, nil))
+
+	as := func(p *Principal) context.Context {
+		ctx := context.Background()
+		if p != nil {
+			ctx = context.WithValue(ctx, principalKey{}, p)
+		}
+		return ctx
+	}
+	tests := []struct {
+		name      string
+		principal *Principal
+		want      string
+	}{
+		{"no credential", nil, TierAnonymous},
+		{"guest token", &Principal{Kind: "token", AID: "EALICE"}, TierGuest},
+		{"member token", &Principal{Kind: "token", AID: "EMEMBER1"}, TierMember},
+		{"API key", &Principal{Kind: "apiKey", Name: "ops"}, TierMember},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := access.Tier(as(tt.principal)); got != tt.want {
+				t.Errorf("expected tier %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	status := access.Status(as(&Principal{Kind: "token", AID: "EALICE"}))
+	if status.AID != "EALICE" || status.Tier != TierGuest {
+		t.Errorf("expected the caller's status, got %+v", status)
+	}
+
+	// With authentication off the local user is the only caller
+	access.SetAuthenticator(NewAuthenticator(AuthOptions{}, nil, nil))
+	if got := access.Tier(context.Background()); got != TierMember {
+		t.Errorf("expected the local user's tier, got %s", got)
+	}
+}
+
+func TestGuestAllowed(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   bool
+	}{
+		{http.MethodGet, "/api/v1/identity", true},
+		{http.MethodPost, "/api/v1/identity/set", true},
+		{http.MethodPost, "/api/v1/sync/credentials", true},
+		{http.MethodPost, "/api/v1/notifications/registration-submitted", true},
+		{http.MethodGet, "/api/v1/grants/summaries", true},
+		{http.MethodGet, "/api/v1/taxonomy/skills", true},
+		{http.MethodPost, "/api/v1/taxonomy/skills", false},
+		{http.MethodGet, "/api/v1/grants", false},
+		{http.MethodGet, "/api/v1/community/members", false},
+		{http.MethodGet, "/api/v1/trust/graph", false},
+		{http.MethodGet, "/api/v1/profiles/SharedProfile", false},
+		{http.MethodGet, "/api/v1/organization", false},
+		{http.MethodGet, "/api/v1/org/config", true},
+		{http.MethodPost, "/api/v1/org/config", false},
+		{http.MethodHead, "/api/v1/schemas", true},
+		{http.MethodPut, "/api/v1/schemas/EMatouMembershipSchemaV1", false},
+		{http.MethodPost, "/api/v1/keria/identifiers", true},
+		{http.MethodDelete, "/api/v1/identity", false},
+		{http.MethodPost, "/api/v1/identity/backup/export", false},
+		{http.MethodGet, "/api/v1/credentials", false},
+		{http.MethodPost, "/api/v1/credentials", false},
+		{http.MethodGet, "/api/v1/admin/status", false},
+		{http.MethodPut, "/api/v1/config/routes", false},
+		{http.MethodGet, "/api/v1/spaces/community", false},
+		{http.MethodPost, "/api/v1/booking/send-email", false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if got := guestAllowed(req); got != tt.want {
+			t.Errorf("guestAllowed(%s %s) = %t, want %t", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAccessMiddleware_GuestRestricted(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	access := newTestAccessControl(t, "EALICE", NewTrustHandler(store, "EORG123", nil), 0)
+	handler := AccessMiddleware(access, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/community/members", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d", w.Code)
+	}
+	var body map[string]string
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body["tier"] != TierGuest {
+		t.Errorf("expected tier guest, got %q", body["tier"])
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/notifications/registration-submitted", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected registration request to pass, got %d", w.Code)
+	}
+}
+
+func TestAccessMiddleware_GuestRateLimit(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	access := newTestAccessControl(t, "EALICE", NewTrustHandler(store, "EORG123", nil), 2)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	access.now = func() time.Time { return now }
+	handler := AccessMiddleware(access, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/identity", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := serve(); w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected status 200, got %d", i+1, w.Code)
+		}
+	}
+	w := serve()
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header")
+	}
+
+	// Health checks are never limited
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	hw := httptest.NewRecorder()
+	handler.ServeHTTP(hw, req)
+	if hw.Code != http.StatusOK {
+		t.Errorf("expected health check to pass, got %d", hw.Code)
+	}
+
+	// The limit resets after a minute
+	now = now.Add(time.Minute)
+	if w := serve(); w.Code != http.StatusOK {
+		t.Errorf("expected status 200 after window reset, got %d", w.Code)
+	}
+}
+
+func TestHandleAccess(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	access := newTestAccessControl(t, "EALICE", NewTrustHandler(store, "EORG123", nil), 120)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/access", nil)
+	w := httptest.NewRecorder()
+	access.HandleAccess(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var status AccessStatus
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if status.Tier != TierGuest || status.AID != "EALICE" {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	if !status.CanRequestMembership || status.RequestsPerMinute != 120 {
+		t.Errorf("expected guest to be able to request membership at 120/min, got %+v", status)
+	}
+}
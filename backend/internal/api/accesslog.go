@@ -0,0 +1,280 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxLoggedBody caps how much of a request/response body is captured for logging
+const maxLoggedBody = 4096
+
+// redactedValue replaces sensitive field values in logged bodies
+const redactedValue = "[REDACTED]"
+
+// defaultSensitiveFields are JSON key fragments whose values are never logged.
+// Matching is case-insensitive on a substring of the key, so "mnemonic" also
+// covers "userMnemonic" and "signingKey" covers "signingKeyHex".
+var defaultSensitiveFields = []string{
+	"mnemonic",
+	"passcode",
+	"password",
+	"passphrase",
+	"bran",
+	"secret",
+	"seed",
+	"privatekey",
+	"signingkey",
+	"readkey",
+	"masterkey",
+	"metadatakey",
+	"invitekey",
+	"token",
+}
+
+// AccessLogOptions configures HTTP access logging
+type AccessLogOptions struct {
+	// LogBodies includes redacted JSON request/response bodies in log lines
+	LogBodies bool
+	// SampleRates maps path prefixes to the fraction of requests logged
+	// (longest prefix wins). Error responses are always logged.
+	SampleRates map[string]float64
+	// SensitiveFields are added to the built-in redaction list
+	SensitiveFields []string
+}
+
+// accessLogger writes one line per request with sensitive fields redacted
+type accessLogger struct {
+	opts      AccessLogOptions
+	sensitive []string
+	out       io.Writer
+	sample    func() float64
+}
+
+// AccessLogMiddleware logs each request's method, path, status, size, and
+// duration. With LogBodies, JSON bodies are logged after redacting mnemonics,
+// passcodes, and key material.
+func AccessLogMiddleware(opts AccessLogOptions, next http.Handler) http.Handler {
+	return newAccessLogger(opts, nil).wrap(next)
+}
+
+// AccessLog is access logging that can be turned on, off or reconfigured
+// while serving, e.g. when config.yaml is reloaded
+type AccessLog struct {
+	mu     sync.RWMutex
+	logger *accessLogger // nil when off
+}
+
+// NewAccessLog creates access logging, on when enabled
+func NewAccessLog(enabled bool, opts AccessLogOptions) *AccessLog {
+	a := &AccessLog{}
+	a.Configure(enabled, opts)
+	return a
+}
+
+// Configure replaces the options; requests already in flight are logged
+// with the old ones
+func (a *AccessLog) Configure(enabled bool, opts AccessLogOptions) {
+	var logger *accessLogger
+	if enabled {
+		logger = newAccessLogger(opts, nil)
+	}
+	a.mu.Lock()
+	a.logger = logger
+	a.mu.Unlock()
+}
+
+// Middleware logs requests as AccessLogMiddleware does while logging is on
+func (a *AccessLog) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.RLock()
+		logger := a.logger
+		a.mu.RUnlock()
+		if logger == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		logger.wrap(next).ServeHTTP(w, r)
+	})
+}
+
+func newAccessLogger(opts AccessLogOptions, out io.Writer) *accessLogger {
+	sensitive := append([]string{}, defaultSensitiveFields...)
+	for _, f := range opts.SensitiveFields {
+		sensitive = append(sensitive, strings.ToLower(f))
+	}
+	return &accessLogger{
+		opts:      opts,
+		sensitive: sensitive,
+		out:       out,
+		sample:    rand.Float64,
+	}
+}
+
+func (l *accessLogger) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		var reqBody []byte
+		if l.opts.LogBodies && r.Body != nil && isJSONContent(r.Header.Get("Content-Type")) {
+			// Read the body and hand the handler an identical copy
+			data, err := io.ReadAll(r.Body)
+			r.Body.Close()
+			r.Body = io.NopCloser(bytes.NewReader(data))
+			if err == nil {
+				reqBody = data
+			}
+		}
+
+		rec := &loggingResponseWriter{ResponseWriter: w, status: http.StatusOK, captureBody: l.opts.LogBodies}
+		next.ServeHTTP(rec, r)
+
+		if rec.status < 400 && l.sample() >= l.sampleRate(r.URL.Path) {
+			return
+		}
+
+		line := fmt.Sprintf("[HTTP] %s %s %d %dB %s",
+			r.Method, r.URL.Path, rec.status, rec.bytes, time.Since(start).Round(time.Millisecond))
+		if reqBody != nil {
+			line += " req=" + l.redactBody(reqBody, len(reqBody))
+		}
+		if rec.body.Len() > 0 && isJSONContent(rec.Header().Get("Content-Type")) {
+			line += " resp=" + l.redactBody(rec.body.Bytes(), rec.bytes)
+		}
+		if l.out != nil {
+			fmt.Fprintln(l.out, line)
+		} else {
+			fmt.Println(line)
+		}
+	})
+}
+
+// sampleRate returns the fraction of requests to log for a path
+func (l *accessLogger) sampleRate(path string) float64 {
+	rate := 1.0
+	longest := -1
+	for prefix, r := range l.opts.SampleRates {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			rate = r
+			longest = len(prefix)
+		}
+	}
+	return rate
+}
+
+// redactBody returns a compact JSON string with sensitive values replaced.
+// Bodies that are too large or not valid JSON are summarized by size only.
+func (l *accessLogger) redactBody(body []byte, size int) string {
+	if size > maxLoggedBody {
+		return fmt.Sprintf("<%d bytes>", size)
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Sprintf("<%d bytes>", size)
+	}
+	out, err := json.Marshal(l.redact(v))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes>", size)
+	}
+	return string(out)
+}
+
+// redact walks a decoded JSON value and masks sensitive fields
+func (l *accessLogger) redact(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			if l.isSensitive(k) {
+				val[k] = redactedValue
+			} else {
+				val[k] = l.redact(child)
+			}
+		}
+		return val
+	case []interface{}:
+		for i, child := range val {
+			val[i] = l.redact(child)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+func (l *accessLogger) isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, f := range l.sensitive {
+		if strings.Contains(key, f) {
+			return true
+		}
+	}
+	return false
+}
+
+func isJSONContent(contentType string) bool {
+	return strings.HasPrefix(contentType, "application/json")
+}
+
+// loggingResponseWriter records status, size, and (optionally) a bounded
+// copy of the response body. It forwards Flush so SSE keeps working, and
+// Hijack so WebSocket upgrades do.
+type loggingResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	captureBody bool
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (w *loggingResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *loggingResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	if w.captureBody && w.body.Len() < maxLoggedBody {
+		remaining := maxLoggedBody - w.body.Len()
+		if remaining > n {
+			remaining = n
+		}
+		w.body.Write(b[:remaining])
+	}
+	return n, err
+}
+
+// Flush implements http.Flusher
+func (w *loggingResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker. The upgraded connection is logged as 101.
+func (w *loggingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.status = http.StatusSwitchingProtocols
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAccessLog_RedactsSecrets(t *testing.T) {
+	var out bytes.Buffer
+	logger := newAccessLogger(AccessLogOptions{LogBodies: true}, &out)
+
+	var received string
+	handler := logger.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		writeJSON(w, http.StatusOK, map[string]string{"peerId": "12D3Koo", "signingKey": "abc"})
+	}))
+
+	body := `{"aid":"EAlice","mnemonic":"word1 word2 word3","nested":{"passcode":"hunter2"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/identity/set", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if received != body {
+		t.Errorf("handler should receive the original body, got %s", received)
+	}
+
+	line := out.String()
+	for _, secret := range []string{"word1", "hunter2", `"abc"`} {
+		if strings.Contains(line, secret) {
+			t.Errorf("log line leaked %q: %s", secret, line)
+		}
+	}
+	if !strings.Contains(line, "EAlice") || !strings.Contains(line, "12D3Koo") {
+		t.Errorf("expected non-sensitive fields in log line: %s", line)
+	}
+}
+
+func TestAccessLog_Sampling(t *testing.T) {
+	var out bytes.Buffer
+	logger := newAccessLogger(AccessLogOptions{
+		SampleRates: map[string]float64{"/health": 0},
+	}, &out)
+	logger.sample = func() float64 { return 0.5 }
+
+	status := http.StatusOK
+	handler := logger.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	if out.Len() != 0 {
+		t.Errorf("expected sampled-out request not to be logged, got %s", out.String())
+	}
+
+	status = http.StatusInternalServerError
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	if !strings.Contains(out.String(), "500") {
+		t.Errorf("expected error response to always be logged, got %q", out.String())
+	}
+
+	out.Reset()
+	status = http.StatusOK
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/credentials", nil))
+	if !strings.Contains(out.String(), "/api/v1/credentials") {
+		t.Errorf("expected unsampled path to be logged, got %q", out.String())
+	}
+}
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+	"github.com/anyproto/any-sync/util/crypto"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/audit"
+	"github.com/matou-dao/backend/internal/trust"
+)
+
+// ACL reconcile actions
+const (
+	ReconcileGrant  = "grant"
+	ReconcileRevoke = "revoke"
+)
+
+// Reasons a member's access is revoked
+const (
+	ReconcileRevoked = "revoked"
+	ReconcileExpired = "expired"
+)
+
+// ACLReconcileAction is one grant or revocation made by a reconcile
+type ACLReconcileAction struct {
+	Action string `json:"action"`
+	AID    string `json:"aid"`
+	PeerID string `json:"peerId"`
+	Reason string `json:"reason,omitempty"`
+	// Spaces are the space IDs changed
+	Spaces []string `json:"spaces,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// ACLReconcileReport is the outcome of reconciling the community ACL
+// against membership credentials
+type ACLReconcileReport struct {
+	SpaceID string `json:"spaceId"`
+	// Members is how many AIDs hold a valid membership credential
+	Members int `json:"members"`
+	// Unmapped is how many of them have no peer ID mapped, so can't be granted
+	Unmapped  int                  `json:"unmapped"`
+	Granted   int                  `json:"granted"`
+	Revoked   int                  `json:"revoked"`
+	Actions   []ACLReconcileAction `json:"actions"`
+	CheckedAt time.Time            `json:"checkedAt"`
+}
+
+// SetReceipts reads credential revocations from the receipt ledger when
+// reconciling
+func (g *MemberAccessGranter) SetReceipts(receipts ReceiptRecorder) {
+	g.receipts = receipts
+}
+
+// membershipStanding sorts the AIDs holding membership credentials into
+// those with a valid one and those whose every membership is revoked or
+// expired, with the reason. A membership expires when its term ends or its
+// expiresAt passes. Without a receipt ledger revocations can't be seen, so
+// only expiry lapses a membership.
+func (g *MemberAccessGranter) membershipStanding(ctx context.Context, now time.Time) (map[string]bool, map[string]string, error) {
+	creds, err := g.store.GetCredentialsBySchema(ctx, membershipSchema)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading credentials: %w", err)
+	}
+	var memberships []*anystore.CachedCredential
+	for _, cred := range creds {
+		if cred.SubjectAID != "" {
+			memberships = append(memberships, cred)
+		}
+	}
+
+	// A renewed term still ends; the renewing credential carries the member on
+	expired := make(map[string]bool)
+	for _, term := range trust.EvaluateTerms(memberships, now, 0) {
+		if !now.Before(term.EndsAt) {
+			expired[term.CredentialID] = true
+		}
+	}
+	for _, cred := range memberships {
+		if trust.CredentialExpired(cred, now) {
+			expired[cred.ID] = true
+		}
+	}
+	var revocations receiptRevocations
+	checkRevoked := g.receipts != nil && g.receipts.Available()
+	if checkRevoked {
+		revocations = receiptRevocations{g.receipts}
+	}
+
+	valid := make(map[string]bool)
+	lapsed := make(map[string]string)
+	for _, cred := range memberships {
+		reason := ""
+		if checkRevoked {
+			revoked, err := revocations.CredentialRevoked(ctx, cred.ID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("checking revocation of %s: %w", cred.ID, err)
+			}
+			if revoked {
+				reason = ReconcileRevoked
+			}
+		}
+		if reason == "" && expired[cred.ID] {
+			reason = ReconcileExpired
+		}
+		if reason == "" {
+			valid[cred.SubjectAID] = true
+		} else if _, ok := lapsed[cred.SubjectAID]; !ok {
+			lapsed[cred.SubjectAID] = reason
+		}
+	}
+	for aid := range valid {
+		delete(lapsed, aid)
+	}
+	return valid, lapsed, nil
+}
+
+// planReconcile works out which AIDs to grant and which to revoke, given
+// membership standing, peer mappings and the community ACL's accounts keyed
+// by account address. Only accounts mapped to a lapsed AID are revoked;
+// accounts the backend can't attribute (e.g. joined by invite key) and
+// admins or the owner are left alone.
+func planReconcile(valid map[string]bool, lapsed map[string]string, mappings []*anystore.PeerMapping, accounts map[string]list.AclPermissions) []ACLReconcileAction {
+	var actions []ACLReconcileAction
+	for _, mapping := range mappings {
+		identity, err := anysync.DecodeACLIdentity(mapping.PeerID)
+		if err != nil {
+			continue
+		}
+		perms, inACL := accounts[identity.Account()]
+		switch {
+		case valid[mapping.AID] && !inACL:
+			actions = append(actions, ACLReconcileAction{Action: ReconcileGrant, AID: mapping.AID, PeerID: mapping.PeerID})
+		case lapsed[mapping.AID] != "" && inACL && !perms.IsOwner() && !perms.CanManageAccounts():
+			actions = append(actions, ACLReconcileAction{
+				Action: ReconcileRevoke,
+				AID:    mapping.AID,
+				PeerID: mapping.PeerID,
+				Reason: lapsed[mapping.AID],
+			})
+		}
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		if actions[i].Action != actions[j].Action {
+			return actions[i].Action < actions[j].Action
+		}
+		return actions[i].AID < actions[j].AID
+	})
+	return actions
+}
+
+// Reconcile compares valid membership credentials against the community
+// space ACL. Members mapped to a peer but missing from the ACL are granted
+// access to the community spaces; members whose credentials were all
+// revoked or expired are removed from them, rotating the read keys. Each
+// action is logged and reported; a failed action doesn't stop the others.
+func (g *MemberAccessGranter) Reconcile(ctx context.Context) (*ACLReconcileReport, error) {
+	spaceID := g.spaceManager.GetCommunitySpaceID()
+	if spaceID == "" {
+		return nil, fmt.Errorf("community space not configured")
+	}
+	aclManager := g.spaceManager.ACLManager()
+	if g.spaceManager.GetClient() == nil || aclManager == nil {
+		return nil, fmt.Errorf("any-sync client not available")
+	}
+
+	now := g.now()
+	valid, lapsed, err := g.membershipStanding(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := g.records.ListPeerMappings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading peer mappings: %w", err)
+	}
+	accounts, err := aclManager.Accounts(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ACLReconcileReport{
+		SpaceID:   spaceID,
+		Members:   len(valid),
+		Actions:   []ACLReconcileAction{},
+		CheckedAt: now,
+	}
+	mapped := make(map[string]bool)
+	for _, mapping := range mappings {
+		mapped[mapping.AID] = true
+	}
+	for aid := range valid {
+		if !mapped[aid] {
+			report.Unmapped++
+		}
+	}
+
+	for _, action := range planReconcile(valid, lapsed, mappings, accounts) {
+		switch action.Action {
+		case ReconcileGrant:
+			result := g.GrantMember(ctx, action.AID)
+			action.Spaces = result.Granted
+			action.Error = result.Error
+			if action.Error == "" {
+				report.Granted++
+				fmt.Printf("[ACLReconcile] Granted %s (peer %s) access\n", truncateAID(action.AID), action.PeerID)
+			}
+		case ReconcileRevoke:
+			action.Spaces, err = g.revokeMember(ctx, action.PeerID)
+			if err != nil {
+				action.Error = err.Error()
+			} else {
+				report.Revoked++
+				fmt.Printf("[ACLReconcile] Revoked %s (peer %s) access: membership %s\n", truncateAID(action.AID), action.PeerID, action.Reason)
+				for _, revokedSpace := range action.Spaces {
+					g.events.Broadcast(aclChangedEvent(revokedSpace, ACLMemberRemoved, action.AID))
+					g.audit.Record(ctx, audit.ActionACLRevoke, auditActor(ctx, nil), revokedSpace,
+						aclMember(action.AID, action.PeerID, nil), nil)
+				}
+			}
+		}
+		if action.Error != "" {
+			fmt.Printf("[ACLReconcile] Failed to %s %s: %s\n", action.Action, truncateAID(action.AID), action.Error)
+		}
+		report.Actions = append(report.Actions, action)
+	}
+	return report, nil
+}
+
+// revokeMember removes a peer from each community space it has access to
+func (g *MemberAccessGranter) revokeMember(ctx context.Context, peerID string) ([]string, error) {
+	identity, err := anysync.DecodeACLIdentity(peerID)
+	if err != nil {
+		return nil, err
+	}
+	aclManager := g.spaceManager.ACLManager()
+
+	var revoked []string
+	for _, spaceID := range []string{g.spaceManager.GetCommunitySpaceID(), g.spaceManager.GetCommunityReadOnlySpaceID()} {
+		if spaceID == "" {
+			continue
+		}
+		perms, err := aclManager.GetPermissions(ctx, spaceID, identity)
+		if err != nil {
+			return revoked, err
+		}
+		if perms.NoPermissions() {
+			continue
+		}
+		if err := aclManager.RemoveAccounts(ctx, spaceID, []crypto.PubKey{identity}); err != nil {
+			return revoked, err
+		}
+		revoked = append(revoked, spaceID)
+	}
+	return revoked, nil
+}
+
+// HandleReconcile handles POST /api/v1/admin/acl/reconcile
+func (g *MemberAccessGranter) HandleReconcile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	report, err := g.Reconcile(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// RegisterRoutes registers the ACL reconcile route on the mux
+func (g *MemberAccessGranter) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/admin/acl/reconcile", g.HandleReconcile)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+)
+
+func TestMemberAccessGranter_MembershipStanding(t *testing.T) {
+	granter, _ := setupTestMemberAccess(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+
+	for _, cred := range []*anystore.CachedCredential{
+		{ID: "EACTIVE", SubjectAID: "EALICE", SchemaID: membershipSchema},
+		{ID: "EREVOKED", SubjectAID: "EBOB", SchemaID: membershipSchema},
+		{ID: "EEXPIRED", SubjectAID: "ECAROL", SchemaID: membershipSchema,
+			Data: map[string]interface{}{"termEndsAt": "2026-10-01T00:00:00Z"}},
+		// A lapsed membership doesn't count against a member with a valid one
+		{ID: "EOLD", SubjectAID: "EALICE", SchemaID: membershipSchema,
+			Data: map[string]interface{}{"termEndsAt": "2026-01-01T00:00:00Z"}},
+		{ID: "EENDORSE", SubjectAID: "EDAVE", SchemaID: "EMatouEndorsementSchemaV1"},
+		{ID: "ELAPSED", SubjectAID: "EERIN", SchemaID: membershipSchema,
+			Data: map[string]interface{}{"expiresAt": "2026-10-19T00:00:00Z"}},
+	} {
+		if err := granter.store.StoreCredential(ctx, cred); err != nil {
+			t.Fatalf("StoreCredential failed: %v", err)
+		}
+	}
+	granter.SetReceipts(&fakeReceipts{receipts: []*anysync.ReceiptPayload{
+		{Action: anysync.ReceiptRevoked, SAID: "EREVOKED"},
+	}})
+
+	valid, lapsed, err := granter.membershipStanding(ctx, now)
+	if err != nil {
+		t.Fatalf("membershipStanding failed: %v", err)
+	}
+	if len(valid) != 1 || !valid["EALICE"] {
+		t.Errorf("expected only EALICE valid, got %v", valid)
+	}
+	want := map[string]string{"EBOB": ReconcileRevoked, "ECAROL": ReconcileExpired, "EERIN": ReconcileExpired}
+	if fmt.Sprint(lapsed) != fmt.Sprint(want) {
+		t.Errorf("expected lapsed %v, got %v", want, lapsed)
+	}
+}
+
+func TestPlanReconcile(t *testing.T) {
+	peers := make(map[string]string)
+	accounts := make(map[string]list.AclPermissions)
+	var mappings []*anystore.PeerMapping
+	for _, m := range []struct {
+		aid   string
+		perms list.AclPermissions
+	}{
+		{"EALICE", list.AclPermissionsNone},   // valid, not in the ACL
+		{"EBOB", list.AclPermissionsWriter},   // valid, already in the ACL
+		{"ECAROL", list.AclPermissionsWriter}, // revoked, in the ACL
+		{"EDAVE", list.AclPermissionsNone},    // revoked, already removed
+		{"EERIN", list.AclPermissionsAdmin},   // revoked, but an ACL admin
+	} {
+		peerID := testPeerID(t)
+		identity, err := anysync.DecodeACLIdentity(peerID)
+		if err != nil {
+			t.Fatalf("DecodeACLIdentity failed: %v", err)
+		}
+		peers[m.aid] = peerID
+		if !m.perms.NoPermissions() {
+			accounts[identity.Account()] = m.perms
+		}
+		mappings = append(mappings, &anystore.PeerMapping{AID: m.aid, PeerID: peerID})
+	}
+	mappings = append(mappings, &anystore.PeerMapping{AID: "EBAD", PeerID: "not-a-peer"})
+
+	valid := map[string]bool{"EALICE": true, "EBOB": true, "EBAD": true}
+	lapsed := map[string]string{"ECAROL": ReconcileRevoked, "EDAVE": ReconcileExpired, "EERIN": ReconcileRevoked}
+
+	actions := planReconcile(valid, lapsed, mappings, accounts)
+	if len(actions) != 2 {
+		t.Fatalf("expected 2 actions, got %+v", actions)
+	}
+	if a := actions[0]; a.Action != ReconcileGrant || a.AID != "EALICE" || a.PeerID != peers["EALICE"] {
+		t.Errorf("expected EALICE granted, got %+v", a)
+	}
+	if a := actions[1]; a.Action != ReconcileRevoke || a.AID != "ECAROL" || a.Reason != ReconcileRevoked {
+		t.Errorf("expected ECAROL revoked, got %+v", a)
+	}
+}
+
+func TestMemberAccessGranter_Reconcile_NoCommunitySpace(t *testing.T) {
+	granter, _ := setupTestMemberAccess(t)
+	granter.spaceManager.SetCommunitySpaceID("")
+
+	if _, err := granter.Reconcile(context.Background()); err == nil {
+		t.Error("expected error without a community space")
+	}
+}
@@ -0,0 +1,489 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/keri"
+)
+
+// Issuance approval states
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+	ApprovalIssued   = "issued"
+)
+
+// membershipSchema is the schema role credentials are issued under
+const membershipSchema = "EMatouMembershipSchemaV1"
+
+// ApprovalDecision records a steward approving or rejecting an issuance
+type ApprovalDecision struct {
+	AID      string `json:"aid"`
+	Decision string `json:"decision"` // approved or rejected
+	Note     string `json:"note,omitempty"`
+	At       string `json:"at"`
+	// Signed is set when the decision request carried a verified signify
+	// signature from AID. The headers are kept so auditors can check the
+	// signature against AID's KEL.
+	Signed         bool   `json:"signed"`
+	Signature      string `json:"signature,omitempty"`
+	SignatureInput string `json:"signatureInput,omitempty"`
+}
+
+// IssuanceApproval is the data stored for an IssuanceApproval object
+type IssuanceApproval struct {
+	Recipient   string                 `json:"recipient"`
+	Role        string                 `json:"role"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	Reason      string                 `json:"reason,omitempty"`
+	Status      string                 `json:"status"`
+	RequestedBy string                 `json:"requestedBy"`
+	RequestedAt string                 `json:"requestedAt"`
+	Decision    *ApprovalDecision      `json:"decision,omitempty"`
+	SAID        string                 `json:"said,omitempty"` // Credential SAID once issued
+	IssuedAt    string                 `json:"issuedAt,omitempty"`
+	UpdatedAt   string                 `json:"updatedAt,omitempty"`
+}
+
+// IssuanceApprovalView is an approval with its object ID, and the
+// pre-filled credential once it is approved
+type IssuanceApprovalView struct {
+	ID string `json:"id"`
+	IssuanceApproval
+	Issuance *CredentialIssuance `json:"issuance,omitempty"`
+}
+
+// CreateApprovalRequest is the body for POST /api/v1/credentials/approvals
+type CreateApprovalRequest struct {
+	Recipient  string                 `json:"recipient"`
+	Role       string                 `json:"role"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Reason     string                 `json:"reason,omitempty"`
+}
+
+// DecideApprovalRequest is the body for POST .../approvals/{id}/approve
+// and .../reject
+type DecideApprovalRequest struct {
+	Note string `json:"note,omitempty"`
+}
+
+// MarkIssuedRequest is the body for POST .../approvals/{id}/issued
+type MarkIssuedRequest struct {
+	SAID string `json:"said"`
+}
+
+// ApprovalsHandler enforces the org's two-person rule for high-privilege
+// issuances. A steward requests an issuance; a different steward approves
+// it; only then does the backend hand out the pre-filled credential for the
+// steward's client to issue via KERIA. Requests live in the admin space and
+// every request and decision is recorded in the receipt ledger.
+type ApprovalsHandler struct {
+	spaceManager *anysync.SpaceManager
+	userIdentity *identity.UserIdentity
+	keriClient   *keri.Client
+	policy       keri.ApprovalPolicySource
+	receipts     ReceiptRecorder
+}
+
+// NewApprovalsHandler creates a new approvals handler
+func NewApprovalsHandler(
+	spaceManager *anysync.SpaceManager,
+	userIdentity *identity.UserIdentity,
+	keriClient *keri.Client,
+) *ApprovalsHandler {
+	return &ApprovalsHandler{
+		spaceManager: spaceManager,
+		userIdentity: userIdentity,
+		keriClient:   keriClient,
+	}
+}
+
+// SetPolicy sets where the approval policy is read from
+func (h *ApprovalsHandler) SetPolicy(source keri.ApprovalPolicySource) {
+	h.policy = source
+}
+
+// SetReceipts records approval requests and decisions in the receipt ledger
+func (h *ApprovalsHandler) SetReceipts(receipts ReceiptRecorder) {
+	h.receipts = receipts
+}
+
+// currentPolicy returns the approval policy, or nil if none is configured
+func (h *ApprovalsHandler) currentPolicy() *keri.ApprovalPolicy {
+	if h.policy == nil {
+		return nil
+	}
+	return h.policy.GetApprovalPolicy()
+}
+
+// decide applies a steward's decision to a pending approval. The requester
+// may withdraw (reject) their own request but not approve it.
+func (a *IssuanceApproval) decide(steward, decision, note string, now time.Time) (int, error) {
+	if a.Status != ApprovalPending {
+		return http.StatusConflict, fmt.Errorf("approval is already %s", a.Status)
+	}
+	if decision == ApprovalApproved && steward == a.RequestedBy {
+		return http.StatusForbidden, fmt.Errorf("a second steward must approve this issuance")
+	}
+	a.Decision = &ApprovalDecision{
+		AID:      steward,
+		Decision: decision,
+		Note:     note,
+		At:       now.UTC().Format(time.RFC3339),
+	}
+	a.Status = decision
+	return http.StatusOK, nil
+}
+
+// markIssued links an approved request to the credential issued for it.
+// Each approval covers a single issuance.
+func (a *IssuanceApproval) markIssued(said string, now time.Time) (int, error) {
+	if said == "" {
+		return http.StatusBadRequest, fmt.Errorf("said is required")
+	}
+	if a.Status != ApprovalApproved {
+		return http.StatusConflict, fmt.Errorf("cannot mark a %s approval issued", a.Status)
+	}
+	a.SAID = said
+	a.IssuedAt = now.UTC().Format(time.RFC3339)
+	a.Status = ApprovalIssued
+	return http.StatusOK, nil
+}
+
+// withIssuance fills in the pre-filled credential for approved requests
+func (h *ApprovalsHandler) withIssuance(a *IssuanceApprovalView) *IssuanceApprovalView {
+	if a.Status != ApprovalApproved {
+		return a
+	}
+	data := map[string]interface{}{
+		"role":        a.Role,
+		"permissions": keri.GetPermissionsForRole(a.Role),
+	}
+	if len(a.Attributes) > 0 {
+		data["attributes"] = a.Attributes
+	}
+	issuer := ""
+	if h.keriClient != nil {
+		issuer = h.keriClient.GetOrgAID()
+	}
+	a.Issuance = &CredentialIssuance{
+		Schema:    membershipSchema,
+		Issuer:    issuer,
+		Recipient: a.Recipient,
+		Data:      data,
+	}
+	return a
+}
+
+// handleApprovals routes /api/v1/credentials/approvals
+func (h *ApprovalsHandler) handleApprovals(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.HandleList(w, r)
+	case http.MethodPost:
+		h.HandleCreate(w, r)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// HandleList handles GET /api/v1/credentials/approvals (steward)
+// Query params:
+//   - status: Only approvals in this state, e.g. pending (optional)
+func (h *ApprovalsHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	adminSpaceID := h.spaceManager.GetAdminSpaceID()
+	if adminSpaceID == "" {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "admin space not available"})
+		return
+	}
+
+	objects, err := readLatestObjects(r.Context(), h.spaceManager, adminSpaceID, "IssuanceApproval")
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read approvals: %v", err),
+		})
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	approvals := make([]*IssuanceApprovalView, 0, len(objects))
+	for _, obj := range objects {
+		a := &IssuanceApprovalView{ID: obj.ID}
+		if err := json.Unmarshal(obj.Data, &a.IssuanceApproval); err != nil {
+			continue
+		}
+		if status != "" && a.Status != status {
+			continue
+		}
+		approvals = append(approvals, h.withIssuance(a))
+	}
+	sort.Slice(approvals, func(i, j int) bool {
+		return approvals[i].RequestedAt > approvals[j].RequestedAt
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"approvals": approvals,
+		"count":     len(approvals),
+		"roles":     h.currentPolicy().ApprovalRoles(),
+	})
+}
+
+// HandleCreate handles POST /api/v1/credentials/approvals (steward). Only
+// roles covered by the policy can be requested; others are issued directly.
+func (h *ApprovalsHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+	if err := checkSignedAID(r, me); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+		return
+	}
+
+	var req CreateApprovalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+	if req.Recipient == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "recipient is required"})
+		return
+	}
+	if !keri.IsValidRole(req.Role) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("invalid role: %q", req.Role)})
+		return
+	}
+	if !h.currentPolicy().RequiresApproval(req.Role) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("issuing %s does not require approval", req.Role),
+		})
+		return
+	}
+	if h.keriClient != nil {
+		if tmpl := h.keriClient.GetRoleTemplate(req.Role); tmpl != nil {
+			if err := tmpl.ValidateAttributes(req.Attributes); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+		} else if len(req.Attributes) > 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("role %s does not accept custom attributes", req.Role),
+			})
+			return
+		}
+	}
+
+	approval := &IssuanceApprovalView{
+		ID: fmt.Sprintf("IssuanceApproval-%d", time.Now().UnixMilli()),
+		IssuanceApproval: IssuanceApproval{
+			Recipient:   req.Recipient,
+			Role:        req.Role,
+			Attributes:  req.Attributes,
+			Reason:      strings.TrimSpace(req.Reason),
+			Status:      ApprovalPending,
+			RequestedBy: me,
+			RequestedAt: time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+
+	ctx := r.Context()
+	if status, err := h.record(ctx, anysync.ReceiptApprovalRequested, approval); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	if status, err := h.saveApproval(ctx, approval); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	fmt.Printf("[Approvals] %s requested %s for %s (%s)\n", me, approval.Role, approval.Recipient, approval.ID)
+	writeJSON(w, http.StatusCreated, approval)
+}
+
+// handleApproval routes /api/v1/credentials/approvals/{id}[/approve|reject|issued]
+func (h *ApprovalsHandler) handleApproval(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/credentials/approvals/")
+	parts := strings.Split(path, "/")
+	if parts[0] == "" || len(parts) > 2 {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+
+	if len(parts) == 1 {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		approval, status, err := h.loadApproval(r.Context(), parts[0])
+		if err != nil {
+			writeJSON(w, status, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, h.withIssuance(approval))
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	switch parts[1] {
+	case "approve":
+		h.handleDecide(w, r, parts[0], ApprovalApproved)
+	case "reject":
+		h.handleDecide(w, r, parts[0], ApprovalRejected)
+	case "issued":
+		h.handleIssued(w, r, parts[0])
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
+
+// handleDecide handles POST .../approvals/{id}/approve and .../reject
+// (steward). The response carries the pre-filled credential once approved.
+func (h *ApprovalsHandler) handleDecide(w http.ResponseWriter, r *http.Request, id, decision string) {
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+	if err := checkSignedAID(r, me); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+		return
+	}
+
+	var req DecideApprovalRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("invalid request: %v", err),
+			})
+			return
+		}
+	}
+
+	ctx := r.Context()
+	approval, status, err := h.loadApproval(ctx, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	if status, err := approval.decide(me, decision, strings.TrimSpace(req.Note), time.Now()); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	if _, verified := SignedAIDFromContext(ctx); verified {
+		approval.Decision.Signed = true
+		approval.Decision.Signature = r.Header.Get("Signature")
+		approval.Decision.SignatureInput = r.Header.Get("Signature-Input")
+	}
+
+	action := anysync.ReceiptApproved
+	if decision == ApprovalRejected {
+		action = anysync.ReceiptApprovalRejected
+	}
+	if status, err := h.record(ctx, action, approval); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	if status, err := h.saveApproval(ctx, approval); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	fmt.Printf("[Approvals] %s %s %s (%s for %s)\n", me, decision, approval.ID, approval.Role, approval.Recipient)
+	writeJSON(w, http.StatusOK, h.withIssuance(approval))
+}
+
+// handleIssued handles POST .../approvals/{id}/issued (steward), called by
+// the client after issuing the approved credential via KERIA
+func (h *ApprovalsHandler) handleIssued(w http.ResponseWriter, r *http.Request, id string) {
+	var req MarkIssuedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+
+	ctx := r.Context()
+	approval, status, err := h.loadApproval(ctx, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	if status, err := approval.markIssued(req.SAID, time.Now()); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	if status, err := h.saveApproval(ctx, approval); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	fmt.Printf("[Approvals] %s issued as %s\n", approval.ID, approval.SAID)
+	writeJSON(w, http.StatusOK, approval)
+}
+
+// record appends an approval receipt to the ledger. It runs before the
+// approval is saved, so a decision is never visible without its receipt;
+// a retry after a failed save finds the existing receipt.
+func (h *ApprovalsHandler) record(ctx context.Context, action string, approval *IssuanceApprovalView) (int, error) {
+	if h.receipts == nil || !h.receipts.Available() {
+		return http.StatusForbidden, fmt.Errorf("admin space not available")
+	}
+	if _, _, err := h.receipts.Record(ctx, action, approval.ID, membershipSchema, approval.Recipient); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to record receipt: %v", err)
+	}
+	return http.StatusOK, nil
+}
+
+// loadApproval reads the latest version of an approval
+func (h *ApprovalsHandler) loadApproval(ctx context.Context, id string) (*IssuanceApprovalView, int, error) {
+	adminSpaceID := h.spaceManager.GetAdminSpaceID()
+	if adminSpaceID == "" {
+		return nil, http.StatusForbidden, fmt.Errorf("admin space not available")
+	}
+
+	obj, err := h.spaceManager.ObjectTreeManager().ReadLatestByID(ctx, adminSpaceID, id)
+	if err != nil || obj.Type != "IssuanceApproval" {
+		return nil, http.StatusNotFound, fmt.Errorf("approval not found")
+	}
+
+	approval := &IssuanceApprovalView{ID: obj.ID}
+	if err := json.Unmarshal(obj.Data, &approval.IssuanceApproval); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("invalid approval: %v", err)
+	}
+	return approval, http.StatusOK, nil
+}
+
+// saveApproval writes a new version of an approval to the admin space
+func (h *ApprovalsHandler) saveApproval(ctx context.Context, approval *IssuanceApprovalView) (int, error) {
+	adminSpaceID := h.spaceManager.GetAdminSpaceID()
+	if adminSpaceID == "" {
+		return http.StatusForbidden, fmt.Errorf("admin space not available")
+	}
+	approval.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	if _, err := writeObject(ctx, h.spaceManager, adminSpaceID, approval.ID, "IssuanceApproval", approval.IssuanceApproval); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+// RegisterRoutes registers approval routes on the mux
+func (h *ApprovalsHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/credentials/approvals", h.handleApprovals)
+	mux.HandleFunc("/api/v1/credentials/approvals/", h.handleApproval)
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/keri"
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+type staticApprovalPolicy struct{ policy *keri.ApprovalPolicy }
+
+func (s staticApprovalPolicy) GetApprovalPolicy() *keri.ApprovalPolicy { return s.policy }
+
+func TestApprovalCreate_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		aid        string
+		policy     *keri.ApprovalPolicy
+		body       string
+		wantStatus int
+	}{
+		{"no identity", "", &keri.ApprovalPolicy{Enabled: true}, `{"recipient":"EBOB","role":"Admin"}`, http.StatusBadRequest},
+		{"invalid json", "EALICE", &keri.ApprovalPolicy{Enabled: true}, `{`, http.StatusBadRequest},
+		{"missing recipient", "EALICE", &keri.ApprovalPolicy{Enabled: true}, `{"role":"Admin"}`, http.StatusBadRequest},
+		{"unknown role", "EALICE", &keri.ApprovalPolicy{Enabled: true}, `{"recipient":"EBOB","role":"Wizard"}`, http.StatusBadRequest},
+		{"role not gated", "EALICE", &keri.ApprovalPolicy{Enabled: true}, `{"recipient":"EBOB","role":"Member"}`, http.StatusBadRequest},
+		{"policy off", "EALICE", nil, `{"recipient":"EBOB","role":"Admin"}`, http.StatusBadRequest},
+		// Gated requests pass validation and need the admin space
+		{"no admin space", "EALICE", &keri.ApprovalPolicy{Enabled: true}, `{"recipient":"EBOB","role":"Admin"}`, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userIdentity := identity.New(t.TempDir())
+			if tt.aid != "" {
+				if err := userIdentity.SetIdentity(tt.aid, secret.NewMnemonic("test mnemonic")); err != nil {
+					t.Fatalf("SetIdentity failed: %v", err)
+				}
+			}
+			handler := NewApprovalsHandler(nil, userIdentity, nil)
+			handler.SetPolicy(staticApprovalPolicy{tt.policy})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/credentials/approvals", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			handler.handleApprovals(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestIssuanceApproval_Decide(t *testing.T) {
+	now := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		status     string
+		steward    string
+		decision   string
+		wantStatus int
+	}{
+		{"second steward approves", ApprovalPending, "EBOB", ApprovalApproved, http.StatusOK},
+		{"requester cannot approve", ApprovalPending, "EALICE", ApprovalApproved, http.StatusForbidden},
+		{"requester may withdraw", ApprovalPending, "EALICE", ApprovalRejected, http.StatusOK},
+		{"already approved", ApprovalApproved, "EBOB", ApprovalRejected, http.StatusConflict},
+		{"already issued", ApprovalIssued, "EBOB", ApprovalApproved, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &IssuanceApproval{Recipient: "ECAROL", Role: "Admin", Status: tt.status, RequestedBy: "EALICE"}
+			status, err := a.decide(tt.steward, tt.decision, "ok", now)
+			if status != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d (%v)", tt.wantStatus, status, err)
+			}
+			if err != nil {
+				if a.Status != tt.status || a.Decision != nil {
+					t.Error("expected failed decision to leave the approval unchanged")
+				}
+				return
+			}
+			if a.Status != tt.decision || a.Decision.AID != tt.steward || a.Decision.At != "2026-10-20T00:00:00Z" {
+				t.Errorf("unexpected decision %+v", a.Decision)
+			}
+		})
+	}
+}
+
+func TestIssuanceApproval_Issuance(t *testing.T) {
+	h := NewApprovalsHandler(nil, nil, nil)
+	pending := &IssuanceApprovalView{ID: "IssuanceApproval-1", IssuanceApproval: IssuanceApproval{
+		Recipient: "ECAROL", Role: "Operations Steward", Status: ApprovalPending, RequestedBy: "EALICE",
+	}}
+	if h.withIssuance(pending).Issuance != nil {
+		t.Fatal("expected no issuance before approval")
+	}
+
+	if _, err := pending.decide("EBOB", ApprovalApproved, "", time.Now()); err != nil {
+		t.Fatalf("decide failed: %v", err)
+	}
+	issuance := h.withIssuance(pending).Issuance
+	if issuance == nil || issuance.Recipient != "ECAROL" || issuance.Data["role"] != "Operations Steward" {
+		t.Fatalf("expected pre-filled steward credential, got %+v", issuance)
+	}
+
+	if _, err := pending.markIssued("", time.Now()); err == nil {
+		t.Error("expected SAID required")
+	}
+	if _, err := pending.markIssued("ESAID", time.Now()); err != nil || pending.Status != ApprovalIssued {
+		t.Fatalf("expected approval marked issued, got %v", err)
+	}
+	if status, _ := pending.markIssued("ESAID2", time.Now()); status != http.StatusConflict {
+		t.Error("expected an approval to cover a single issuance")
+	}
+}
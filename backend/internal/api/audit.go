@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/audit"
+	"github.com/matou-dao/backend/internal/identity"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// AuditHandler serves the audit log of privileged operations
+type AuditHandler struct {
+	log *audit.Log
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(log *audit.Log) *AuditHandler {
+	return &AuditHandler{log: log}
+}
+
+// AuditResponse is the response for GET /api/v1/audit
+type AuditResponse struct {
+	Records []*anystore.AuditRecord `json:"records"`
+	Total   int                     `json:"total"`
+}
+
+// HandleList handles GET /api/v1/audit (admin)
+// Query params:
+//   - action: Only this action, or every action under a prefix such as
+//     "credential." (optional)
+//   - actor: Only operations by this AID (optional)
+//   - target: Only operations on this SAID, AID or space ID (optional)
+//   - since: RFC 3339 time of the earliest record (optional)
+//   - until: RFC 3339 time of the latest record (optional)
+//   - limit: Maximum records, newest first (default 100, max 1000)
+func (h *AuditHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	query := r.URL.Query()
+	q := anystore.AuditQuery{
+		Action:   query.Get("action"),
+		ActorAID: query.Get("actor"),
+		Target:   query.Get("target"),
+		Limit:    defaultAuditLimit,
+	}
+	if q.Action != "" && !strings.HasSuffix(q.Action, ".") && !isAuditAction(q.Action) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("action must be one of %s, or a prefix ending in \".\"",
+				strings.Join(audit.Actions(), ", ")),
+		})
+		return
+	}
+	for name, t := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("%s must be an RFC 3339 time", name),
+			})
+			return
+		}
+		*t = parsed
+	}
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 {
+		q.Limit = l
+	}
+	if q.Limit > maxAuditLimit {
+		q.Limit = maxAuditLimit
+	}
+
+	if h.log == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "audit log is not available",
+		})
+		return
+	}
+	records, err := h.log.List(r.Context(), q)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+	if records == nil {
+		records = []*anystore.AuditRecord{}
+	}
+	writeJSON(w, http.StatusOK, AuditResponse{
+		Records: records,
+		Total:   len(records),
+	})
+}
+
+// RegisterRoutes registers audit routes on the mux
+func (h *AuditHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/audit", h.HandleList)
+}
+
+// isAuditAction returns true if action is an audited action
+func isAuditAction(action string) bool {
+	for _, a := range audit.Actions() {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// aclMember is an ACL member's state as recorded in the audit log
+func aclMember(aid, peerID string, permissions []string) map[string]any {
+	member := map[string]any{"aid": aid, "peerId": peerID}
+	if len(permissions) > 0 {
+		member["permissions"] = permissions
+	}
+	return member
+}
+
+// auditActor names who performed an audited operation: the token's AID,
+// the API key, or the local user
+func auditActor(ctx context.Context, userIdentity *identity.UserIdentity) string {
+	if p := PrincipalFromContext(ctx); p != nil {
+		if p.AID != "" {
+			return p.AID
+		}
+		return p.Kind + ":" + p.Name
+	}
+	if userIdentity != nil {
+		if aid := userIdentity.GetAID(); aid != "" {
+			return aid
+		}
+	}
+	return "local"
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+)
+
+// AuditLedger replicates audit records into the admin space's audit tree,
+// the org's shared audit log. Only admins hold the admin space keys, so
+// every admin's backend replicates every admin's entries, and any of them
+// can verify each backend's chain and that its peer key signed it.
+// Implements audit.Replicator.
+type AuditLedger struct {
+	spaceManager *anysync.SpaceManager
+}
+
+// NewAuditLedger creates a new audit ledger
+func NewAuditLedger(spaceManager *anysync.SpaceManager) *AuditLedger {
+	return &AuditLedger{spaceManager: spaceManager}
+}
+
+// AuditLedgerResponse is the response for GET /api/v1/audit/ledger
+type AuditLedgerResponse struct {
+	Entries     []*anysync.AuditEntryPayload `json:"entries"`
+	Count       int                          `json:"count"`
+	Verified    bool                         `json:"verified"`
+	VerifyError string                       `json:"verifyError,omitempty"`
+}
+
+// Available reports whether this backend holds the admin space
+func (l *AuditLedger) Available() bool {
+	return l != nil && l.spaceManager != nil && l.spaceManager.GetAdminSpaceID() != ""
+}
+
+// Replicate appends an audit record to the admin space's audit tree
+func (l *AuditLedger) Replicate(ctx context.Context, record *anystore.AuditRecord) error {
+	adminSpaceID := l.spaceManager.GetAdminSpaceID()
+	if adminSpaceID == "" {
+		return fmt.Errorf("admin space not available")
+	}
+	client := l.spaceManager.GetClient()
+	if client == nil {
+		return fmt.Errorf("any-sync client not available")
+	}
+	keys, err := anysync.LoadSpaceKeySet(client.GetDataDir(), adminSpaceID)
+	if err != nil {
+		return fmt.Errorf("failed to load admin space keys: %w", err)
+	}
+
+	changes := make([]anysync.AuditEntryChange, 0, len(record.Changes))
+	for _, c := range record.Changes {
+		changes = append(changes, anysync.AuditEntryChange{Path: c.Path, Before: c.Before, After: c.After})
+	}
+	entry, err := l.spaceManager.AuditTreeManager().Append(ctx, adminSpaceID, &anysync.AuditEntryPayload{
+		RecordID:  record.ID,
+		Action:    record.Action,
+		Actor:     record.ActorAID,
+		Target:    record.Target,
+		Before:    record.Before,
+		After:     record.After,
+		Changes:   changes,
+		Timestamp: record.At.Unix(),
+	}, keys.SigningKey)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("[Audit] Replicated %s by %s to the admin space (seq %d)\n", record.Action, record.ActorAID, entry.Seq)
+	return nil
+}
+
+// HandleList handles GET /api/v1/audit/ledger (admin)
+// Query params:
+//   - action: Only this action, or every action under a prefix such as
+//     "credential." (optional)
+//
+// The whole chain is always verified, even when filtering.
+func (l *AuditLedger) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+	if !l.Available() {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "admin space not available"})
+		return
+	}
+
+	entries, err := l.spaceManager.AuditTreeManager().ReadEntries(r.Context(), l.spaceManager.GetAdminSpaceID())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read audit ledger: %v", err),
+		})
+		return
+	}
+
+	resp := AuditLedgerResponse{Verified: true}
+	if err := anysync.VerifyAuditEntries(entries); err != nil {
+		resp.Verified = false
+		resp.VerifyError = err.Error()
+	}
+
+	action := r.URL.Query().Get("action")
+	resp.Entries = make([]*anysync.AuditEntryPayload, 0, len(entries))
+	for _, entry := range entries {
+		if action == "" || entry.Action == action ||
+			(strings.HasSuffix(action, ".") && strings.HasPrefix(entry.Action, action)) {
+			resp.Entries = append(resp.Entries, entry)
+		}
+	}
+	resp.Count = len(resp.Entries)
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// RegisterRoutes registers the audit ledger route on the mux
+func (l *AuditLedger) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/audit/ledger", l.HandleList)
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/audit"
+)
+
+func TestAuditHandler_HandleList(t *testing.T) {
+	store, err := anystore.NewLocalStore(anystore.DefaultConfig(t.TempDir()))
+	if err != nil {
+		t.Fatalf("failed to create anystore: %v", err)
+	}
+	defer store.Close()
+
+	log := audit.NewLog(store)
+	ctx := context.Background()
+	log.Record(ctx, audit.ActionCredentialIssue, "EADMIN1", "ESAID1", nil, map[string]string{"said": "ESAID1"})
+	log.Record(ctx, audit.ActionCredentialRevoke, "EADMIN2", "ESAID1", nil, map[string]string{"said": "ESAID1"})
+	log.Record(ctx, audit.ActionOrgConfig, "EADMIN1", "org", map[string]string{"name": "Matou"}, map[string]string{"name": "Matou DAO"})
+
+	handler := NewAuditHandler(log)
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		want   int
+	}{
+		{"all", "", http.StatusOK, 3},
+		{"by action", "?action=org.config", http.StatusOK, 1},
+		{"by action prefix", "?action=credential.", http.StatusOK, 2},
+		{"by actor", "?actor=EADMIN1", http.StatusOK, 2},
+		{"by target", "?target=ESAID1&actor=EADMIN2", http.StatusOK, 1},
+		{"limit", "?limit=2", http.StatusOK, 2},
+		{"since in the future", "?since=2999-01-01T00:00:00Z", http.StatusOK, 0},
+		{"unknown action", "?action=credential.mint", http.StatusBadRequest, 0},
+		{"bad time", "?until=yesterday", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleList(w, httptest.NewRequest(http.MethodGet, "/api/v1/audit"+tt.query, nil))
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var resp AuditResponse
+			json.NewDecoder(w.Body).Decode(&resp)
+			if resp.Total != tt.want || len(resp.Records) != tt.want {
+				t.Errorf("expected %d records, got %+v", tt.want, resp)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	handler.HandleList(w, httptest.NewRequest(http.MethodGet, "/api/v1/audit?action=org.config", nil))
+	var resp AuditResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.Records) != 1 || len(resp.Records[0].Changes) != 1 || resp.Records[0].Changes[0].Path != "name" {
+		t.Errorf("expected the config change diffed, got %+v", resp.Records)
+	}
+
+	w = httptest.NewRecorder()
+	NewAuditHandler(nil).HandleList(w, httptest.NewRequest(http.MethodGet, "/api/v1/audit", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without an audit log, got %d", w.Code)
+	}
+}
+
+func TestAuditActor(t *testing.T) {
+	ctx := context.Background()
+	if got := auditActor(ctx, nil); got != "local" {
+		t.Errorf("expected local without a principal or identity, got %s", got)
+	}
+
+	withToken := context.WithValue(ctx, principalKey{}, &Principal{Kind: "token", AID: "EADMIN1"})
+	if got := auditActor(withToken, nil); got != "EADMIN1" {
+		t.Errorf("expected the token's AID, got %s", got)
+	}
+
+	withKey := context.WithValue(ctx, principalKey{}, &Principal{Kind: "apiKey", Name: "ops"})
+	if got := auditActor(withKey, nil); got != "apiKey:ops" {
+		t.Errorf("expected the API key, got %s", got)
+	}
+}
+
+func TestAuditLedger_Unavailable(t *testing.T) {
+	ledger := NewAuditLedger(nil)
+	if ledger.Available() {
+		t.Error("expected no ledger without a space manager")
+	}
+
+	w := httptest.NewRecorder()
+	ledger.HandleList(w, httptest.NewRequest(http.MethodGet, "/api/v1/audit/ledger", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 without the admin space, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	ledger.HandleList(w, httptest.NewRequest(http.MethodPost, "/api/v1/audit/ledger", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
@@ -0,0 +1,426 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/anyproto/any-sync/util/crypto"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+)
+
+// AuthLevel is the authentication a route requires
+type AuthLevel string
+
+const (
+	AuthPublic AuthLevel = "public" // No credentials needed
+	AuthUser   AuthLevel = "user"   // Any valid API key, service token or AID token
+	AuthAdmin  AuthLevel = "admin"  // An admin API key or service token, or a token for an org admin AID
+)
+
+// ParseAuthLevel parses a route requirement from config
+func ParseAuthLevel(s string) (AuthLevel, error) {
+	switch level := AuthLevel(s); level {
+	case AuthPublic, AuthUser, AuthAdmin:
+		return level, nil
+	}
+	return "", fmt.Errorf("unknown auth level %q (expected public, user or admin)", s)
+}
+
+// Principal is an authenticated caller
+type Principal struct {
+	Kind  string `json:"kind"`           // "apiKey", "serviceToken" or "token"
+	Name  string `json:"name,omitempty"` // API key or service token name
+	AID   string `json:"aid,omitempty"`  // Token holder's AID
+	Admin bool   `json:"admin"`
+	// Scopes limits a service token to some routes; nil allows every route
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// APIKey is a static key for automation and operator tooling
+type APIKey struct {
+	Name  string
+	Key   string
+	Admin bool
+}
+
+// AuthOptions configures the Authenticator
+type AuthOptions struct {
+	// Enabled turns authentication on; when off every request is served
+	Enabled bool
+	APIKeys []APIKey
+	// TokenMaxAge bounds how far ahead an AID token may expire, so a
+	// leaked token can't be valid for long
+	TokenMaxAge time.Duration
+	// DefaultLevel applies to routes without a requirement (AuthUser if unset)
+	DefaultLevel AuthLevel
+}
+
+// AdminSource reports whether an AID is an org admin.
+// Implemented by OrgConfigHandler.
+type AdminSource interface {
+	IsAdmin(aid string) bool
+}
+
+// authRule is one route requirement
+type authRule struct {
+	method string // Empty matches any method
+	path   string // Exact, or a prefix when it ends in "/"
+	level  AuthLevel
+}
+
+// apiKeyHash is a configured key, stored hashed so comparisons are
+// constant-time regardless of key length
+type apiKeyHash struct {
+	name  string
+	hash  [sha256.Size]byte
+	admin bool
+}
+
+// Authenticator checks API keys and AID tokens against per-route
+// requirements.
+//
+// An AID token is signed with the holder's any-sync peer key (derived from
+// the same mnemonic as their identity). It is accepted when the peer ID it
+// names is the one recorded for its AID: the local identity's own, or one
+// learned through member access grants.
+type Authenticator struct {
+	opts         AuthOptions
+	keys         []apiKeyHash
+	rules        []authRule
+	store        anystore.RecordStore
+	userIdentity *identity.UserIdentity
+	admins       AdminSource
+	tokens       *ServiceTokens
+	now          func() time.Time
+}
+
+// NewAuthenticator creates an authenticator. store and userIdentity resolve
+// the peer IDs AID tokens are checked against; either may be nil.
+func NewAuthenticator(opts AuthOptions, store anystore.RecordStore, userIdentity *identity.UserIdentity) *Authenticator {
+	if opts.DefaultLevel == "" {
+		opts.DefaultLevel = AuthUser
+	}
+	a := &Authenticator{
+		opts:         opts,
+		store:        store,
+		userIdentity: userIdentity,
+		now:          time.Now,
+	}
+	for _, k := range opts.APIKeys {
+		a.keys = append(a.keys, apiKeyHash{name: k.Name, hash: sha256.Sum256([]byte(k.Key)), admin: k.Admin})
+	}
+	return a
+}
+
+// SetAdminSource sets where org admin AIDs come from
+func (a *Authenticator) SetAdminSource(admins AdminSource) {
+	a.admins = admins
+}
+
+// SetServiceTokens sets where issued service tokens are checked
+func (a *Authenticator) SetServiceTokens(tokens *ServiceTokens) {
+	a.tokens = tokens
+}
+
+// Enabled reports whether requests are authenticated
+func (a *Authenticator) Enabled() bool {
+	return a.opts.Enabled
+}
+
+// Require sets the level for a route. The pattern is a path, optionally
+// preceded by a method ("POST /api/v1/org/config"); paths ending in "/"
+// match by prefix. Later calls for the same pattern replace earlier ones.
+func (a *Authenticator) Require(pattern string, level AuthLevel) {
+	rule := authRule{path: pattern, level: level}
+	if method, path, ok := strings.Cut(pattern, " "); ok {
+		rule.method, rule.path = strings.ToUpper(method), strings.TrimSpace(path)
+	}
+	for i, r := range a.rules {
+		if r.method == rule.method && r.path == rule.path {
+			a.rules[i] = rule
+			return
+		}
+	}
+	a.rules = append(a.rules, rule)
+}
+
+// LevelFor returns the level a request needs. The longest matching path
+// wins; at equal length a rule for the request's method beats one for any
+// method.
+func (a *Authenticator) LevelFor(r *http.Request) AuthLevel {
+	level := a.opts.DefaultLevel
+	bestLen, bestMethod := -1, false
+	for _, rule := range a.rules {
+		if rule.method != "" && rule.method != r.Method {
+			continue
+		}
+		if !matchesRoute(r.URL.Path, []string{rule.path}) {
+			continue
+		}
+		n, hasMethod := len(rule.path), rule.method != ""
+		if n > bestLen || (n == bestLen && hasMethod && !bestMethod) {
+			level, bestLen, bestMethod = rule.level, n, hasMethod
+		}
+	}
+	return level
+}
+
+// eventsPath is the event stream, whose browser clients (EventSource and
+// WebSocket) can't set an Authorization header
+const eventsPath = "/api/v1/events"
+
+// bearerProtocolPrefix marks a WebSocket subprotocol that carries a bearer
+// credential, e.g. "bearer.<token>"
+const bearerProtocolPrefix = "bearer."
+
+// Authenticate resolves the request's bearer credential. It returns nil and
+// no error when the request carries none. On the event stream the
+// credential may instead be sent as the access_token query parameter or as
+// a "bearer.<token>" WebSocket subprotocol.
+func (a *Authenticator) Authenticate(r *http.Request) (*Principal, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		credential := streamCredential(r)
+		if credential == "" {
+			return nil, nil
+		}
+		return a.authenticateCredential(r.Context(), credential)
+	}
+	scheme, credential, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || credential == "" {
+		return nil, fmt.Errorf("expected a Bearer credential")
+	}
+	return a.authenticateCredential(r.Context(), credential)
+}
+
+// authenticateCredential resolves an API key, service token or AID token
+func (a *Authenticator) authenticateCredential(ctx context.Context, credential string) (*Principal, error) {
+
+	// Configured keys are checked first, so a key may contain a "."
+	if p := a.matchAPIKey(credential); p != nil {
+		return p, nil
+	}
+	if a.tokens != nil && strings.HasPrefix(credential, serviceTokenPrefix) {
+		t, err := a.tokens.Verify(credential)
+		if err != nil {
+			return nil, err
+		}
+		return &Principal{
+			Kind:   "serviceToken",
+			Name:   t.Name,
+			Admin:  slices.Contains(t.Scopes, ScopeAdmin),
+			Scopes: t.Scopes,
+		}, nil
+	}
+	if strings.Contains(credential, ".") {
+		return a.verifyToken(ctx, credential)
+	}
+	return nil, fmt.Errorf("invalid API key")
+}
+
+// streamCredential returns the credential an event stream request carries
+// outside the Authorization header, or "" for none
+func streamCredential(r *http.Request) string {
+	if r.URL.Path != eventsPath {
+		return ""
+	}
+	if token := r.URL.Query().Get("access_token"); token != "" {
+		return token
+	}
+	for _, protocol := range webSocketProtocols(r) {
+		if token, ok := strings.CutPrefix(protocol, bearerProtocolPrefix); ok && token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// webSocketProtocols returns the subprotocols a WebSocket handshake offers
+func webSocketProtocols(r *http.Request) []string {
+	var protocols []string
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(header, ",") {
+			if protocol = strings.TrimSpace(protocol); protocol != "" {
+				protocols = append(protocols, protocol)
+			}
+		}
+	}
+	return protocols
+}
+
+func (a *Authenticator) matchAPIKey(key string) *Principal {
+	hash := sha256.Sum256([]byte(key))
+	var match *apiKeyHash
+	for i := range a.keys {
+		// Compare against every key so timing doesn't reveal which matched
+		if subtle.ConstantTimeCompare(hash[:], a.keys[i].hash[:]) == 1 {
+			match = &a.keys[i]
+		}
+	}
+	if match == nil {
+		return nil
+	}
+	return &Principal{Kind: "apiKey", Name: match.name, Admin: match.admin}
+}
+
+// authTokenPayload is the signed part of an AID token
+type authTokenPayload struct {
+	AID     string `json:"aid"`
+	PeerID  string `json:"peerId"`
+	Expires int64  `json:"exp"` // Unix seconds
+}
+
+// SignAuthToken creates an AID token signed with the holder's peer key,
+// valid until expires
+func SignAuthToken(key crypto.PrivKey, aid string, expires time.Time) (string, error) {
+	if key == nil || aid == "" {
+		return "", fmt.Errorf("peer key and AID are required")
+	}
+	payload, err := json.Marshal(authTokenPayload{
+		AID:     aid,
+		PeerID:  key.GetPublic().PeerId(),
+		Expires: expires.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	sig, err := key.Sign(payload)
+	if err != nil {
+		return "", fmt.Errorf("signing token: %w", err)
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(sig), nil
+}
+
+// verifyToken checks an AID token's signature, expiry and peer binding
+func (a *Authenticator) verifyToken(ctx context.Context, token string) (*Principal, error) {
+	enc := base64.RawURLEncoding
+	payloadPart, sigPart, _ := strings.Cut(token, ".")
+	payloadBytes, err := enc.DecodeString(payloadPart)
+	if err != nil {
+		return nil, fmt.Errorf("malformed token")
+	}
+	sig, err := enc.DecodeString(sigPart)
+	if err != nil {
+		return nil, fmt.Errorf("malformed token")
+	}
+	var payload authTokenPayload
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil || payload.AID == "" {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	now := a.now()
+	expires := time.Unix(payload.Expires, 0)
+	if !expires.After(now) {
+		return nil, fmt.Errorf("token expired")
+	}
+	if a.opts.TokenMaxAge > 0 && expires.Sub(now) > a.opts.TokenMaxAge {
+		return nil, fmt.Errorf("token lifetime exceeds %s", a.opts.TokenMaxAge)
+	}
+
+	pub, err := anysync.DecodeACLIdentity(payload.PeerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token peer ID")
+	}
+	if ok, err := pub.Verify(payloadBytes, sig); err != nil || !ok {
+		return nil, fmt.Errorf("bad token signature")
+	}
+	// Mappings may hold a peer ID or an account address, so compare keys
+	registered, err := anysync.DecodeACLIdentity(a.peerIDFor(ctx, payload.AID))
+	if err != nil || !registered.Equals(pub) {
+		return nil, fmt.Errorf("token peer is not registered for %s", payload.AID)
+	}
+
+	// Admin rights are per org: a tenant's admins come from its own config
+	admins := a.admins
+	if t := TenantFromContext(ctx); t != nil {
+		admins = t.Config
+	}
+	return &Principal{
+		Kind:  "token",
+		AID:   payload.AID,
+		Admin: admins != nil && admins.IsAdmin(payload.AID),
+	}, nil
+}
+
+// peerIDFor returns the peer ID recorded for an AID, or empty
+func (a *Authenticator) peerIDFor(ctx context.Context, aid string) string {
+	if a.userIdentity != nil && a.userIdentity.GetAID() == aid {
+		return a.userIdentity.GetPeerID()
+	}
+	if a.store == nil {
+		return ""
+	}
+	mapping, err := a.store.GetPeerMapping(ctx, aid)
+	if err != nil {
+		return ""
+	}
+	return mapping.PeerID
+}
+
+type principalKey struct{}
+
+// PrincipalFromContext returns the authenticated caller, or nil when auth
+// is disabled or the route is public and no credential was sent
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// isAdminCaller reports whether the caller may use an admin action the route
+// requirements can't express by path, such as one under a resource ID: an
+// admin, or anyone when authentication is off
+func isAdminCaller(r *http.Request) bool {
+	p := PrincipalFromContext(r.Context())
+	return p == nil || p.Admin
+}
+
+// AuthMiddleware enforces route requirements. Public routes are served
+// without credentials, but a credential sent to one is still checked so
+// handlers can see the caller. Preflight requests are always served.
+func AuthMiddleware(a *Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.Enabled() || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		level := a.LevelFor(r)
+		principal, err := a.Authenticate(r)
+		if err != nil && level != AuthPublic {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+			return
+		}
+
+		switch {
+		case level == AuthPublic:
+		case principal == nil:
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "authentication required"})
+			return
+		case principal.Scopes != nil && !ScopesAllow(principal.Scopes, r.Method, r.URL.Path):
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": "token scope does not cover this route"})
+			return
+		case level == AuthAdmin && !principal.Admin:
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": "admin required"})
+			return
+		}
+
+		if principal != nil {
+			r = r.WithContext(context.WithValue(r.Context(), principalKey{}, principal))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
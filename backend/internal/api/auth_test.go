@@ -0,0 +1,210 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/util/crypto"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+type stubAdmins map[string]bool
+
+func (s stubAdmins) IsAdmin(aid string) bool { return s[aid] }
+
+func newTestAuthenticator(t *testing.T) (*Authenticator, *anystore.LocalStore) {
+	t.Helper()
+	store, cleanup := setupTrustTestStore(t)
+	t.Cleanup(cleanup)
+
+	a := NewAuthenticator(AuthOptions{
+		Enabled: true,
+		APIKeys: []APIKey{
+			{Name: "ops", Key: "ops-key-0123456789", Admin: true},
+			{Name: "ci", Key: "ci-key-0123456789"},
+		},
+		TokenMaxAge: time.Hour,
+	}, store, nil)
+	a.SetAdminSource(stubAdmins{"EADMIN": true})
+	a.Require("/health", AuthPublic)
+	a.Require("/api/v1/admin/", AuthAdmin)
+	a.Require("POST /api/v1/org/config", AuthAdmin)
+	return a, store
+}
+
+func TestAuthenticator_LevelFor(t *testing.T) {
+	a, _ := newTestAuthenticator(t)
+	a.Require("GET /api/v1/admin/status", AuthUser)
+
+	tests := []struct {
+		method, path string
+		want         AuthLevel
+	}{
+		{"GET", "/health", AuthPublic},
+		{"GET", "/health/extra", AuthUser},
+		{"GET", "/api/v1/org/config", AuthUser},
+		{"POST", "/api/v1/org/config", AuthAdmin},
+		{"POST", "/api/v1/admin/backup", AuthAdmin},
+		{"GET", "/api/v1/admin/status", AuthUser},
+		{"POST", "/api/v1/admin/status", AuthAdmin},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if got := a.LevelFor(req); got != tt.want {
+			t.Errorf("%s %s: expected %s, got %s", tt.method, tt.path, tt.want, got)
+		}
+	}
+}
+
+func TestAuthMiddleware_APIKeys(t *testing.T) {
+	a, _ := newTestAuthenticator(t)
+	var seen *Principal
+	handler := AuthMiddleware(a, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = PrincipalFromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name, method, path, auth string
+		want                     int
+	}{
+		{"public without key", "GET", "/health", "", http.StatusOK},
+		{"public with bad key", "GET", "/health", "Bearer wrong", http.StatusOK},
+		{"missing credential", "GET", "/api/v1/org/config", "", http.StatusUnauthorized},
+		{"unknown key", "GET", "/api/v1/org/config", "Bearer wrong", http.StatusUnauthorized},
+		{"wrong scheme", "GET", "/api/v1/org/config", "Basic ci-key-0123456789", http.StatusUnauthorized},
+		{"user key", "GET", "/api/v1/org/config", "Bearer ci-key-0123456789", http.StatusOK},
+		{"user key on admin route", "POST", "/api/v1/org/config", "Bearer ci-key-0123456789", http.StatusForbidden},
+		{"admin key on admin route", "POST", "/api/v1/org/config", "Bearer ops-key-0123456789", http.StatusOK},
+		{"preflight", "OPTIONS", "/api/v1/admin/backup", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+			if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected WWW-Authenticate header")
+			}
+		})
+	}
+
+	req := httptest.NewRequest("POST", "/api/v1/org/config", nil)
+	req.Header.Set("Authorization", "Bearer ops-key-0123456789")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if seen == nil || seen.Kind != "apiKey" || seen.Name != "ops" || !seen.Admin {
+		t.Errorf("unexpected principal: %+v", seen)
+	}
+}
+
+func TestAuthMiddleware_EventStreamCredential(t *testing.T) {
+	a, _ := newTestAuthenticator(t)
+	var seen *Principal
+	handler := AuthMiddleware(a, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = PrincipalFromContext(r.Context())
+	}))
+
+	// EventSource and WebSocket clients can't set an Authorization header
+	tests := []struct {
+		name, path, protocol string
+		want                 int
+	}{
+		{"no credential", "/api/v1/events", "", http.StatusUnauthorized},
+		{"query parameter", "/api/v1/events?access_token=ci-key-0123456789", "", http.StatusOK},
+		{"bad query parameter", "/api/v1/events?access_token=wrong", "", http.StatusUnauthorized},
+		{"subprotocol", "/api/v1/events", "matou.events, bearer.ci-key-0123456789", http.StatusOK},
+		{"only on the event stream", "/api/v1/org/config?access_token=ci-key-0123456789", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.protocol != "" {
+				req.Header.Set("Sec-WebSocket-Protocol", tt.protocol)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	seen = nil
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/events?access_token=ops-key-0123456789", nil))
+	if seen == nil || seen.Name != "ops" {
+		t.Errorf("expected the query credential's principal, got %+v", seen)
+	}
+}
+
+func TestAuthMiddleware_Disabled(t *testing.T) {
+	a := NewAuthenticator(AuthOptions{}, nil, nil)
+	a.Require("/api/v1/admin/", AuthAdmin)
+	handler := AuthMiddleware(a, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/api/v1/admin/backup", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 with auth disabled, got %d", rec.Code)
+	}
+}
+
+func TestAuthMiddleware_Tokens(t *testing.T) {
+	a, store := newTestAuthenticator(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+	handler := AuthMiddleware(a, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ctx := context.Background()
+
+	memberKey, memberPub, _ := crypto.GenerateRandomEd25519KeyPair()
+	adminKey, adminPub, _ := crypto.GenerateRandomEd25519KeyPair()
+	otherKey, _, _ := crypto.GenerateRandomEd25519KeyPair()
+	// Mappings are recorded in either peer ID or account address form
+	for aid, peerID := range map[string]string{"EMEMBER": memberPub.PeerId(), "EADMIN": adminPub.Account()} {
+		if err := store.StorePeerMapping(ctx, &anystore.PeerMapping{AID: aid, PeerID: peerID}); err != nil {
+			t.Fatalf("StorePeerMapping failed: %v", err)
+		}
+	}
+
+	sign := func(key crypto.PrivKey, aid string, ttl time.Duration) string {
+		token, err := SignAuthToken(key, aid, now.Add(ttl))
+		if err != nil {
+			t.Fatalf("SignAuthToken failed: %v", err)
+		}
+		return token
+	}
+	memberToken := sign(memberKey, "EMEMBER", 30*time.Minute)
+
+	tests := []struct {
+		name, method, path, token string
+		want                      int
+	}{
+		{"member", "GET", "/api/v1/org/config", memberToken, http.StatusOK},
+		{"member on admin route", "POST", "/api/v1/org/config", memberToken, http.StatusForbidden},
+		{"admin on admin route", "POST", "/api/v1/org/config", sign(adminKey, "EADMIN", time.Minute), http.StatusOK},
+		{"expired", "GET", "/api/v1/org/config", sign(memberKey, "EMEMBER", -time.Minute), http.StatusUnauthorized},
+		{"lifetime over max age", "GET", "/api/v1/org/config", sign(memberKey, "EMEMBER", 2*time.Hour), http.StatusUnauthorized},
+		{"wrong peer for AID", "GET", "/api/v1/org/config", sign(otherKey, "EMEMBER", time.Minute), http.StatusUnauthorized},
+		{"unmapped AID", "GET", "/api/v1/org/config", sign(otherKey, "EUNKNOWN", time.Minute), http.StatusUnauthorized},
+		{"tampered", "GET", "/api/v1/org/config", "e30" + memberToken[3:], http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/matou-dao/backend/internal/backup"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+// maxBackupSize bounds an uploaded bundle. Key sets are small, so this
+// leaves room for thousands of spaces.
+const maxBackupSize = 16 << 20
+
+// BackupHandler exports and imports encrypted backups of the node's keys,
+// for moving to a new machine
+type BackupHandler struct {
+	dataDir      string
+	sealer       *secret.Sealer // At-rest sealer; may be nil
+	userIdentity *identity.UserIdentity
+	now          func() time.Time
+}
+
+// NewBackupHandler creates a backup handler for dataDir. sealer is the
+// at-rest sealer the data directory's files are encrypted with.
+func NewBackupHandler(dataDir string, sealer *secret.Sealer, userIdentity *identity.UserIdentity) *BackupHandler {
+	return &BackupHandler{
+		dataDir:      dataDir,
+		sealer:       sealer,
+		userIdentity: userIdentity,
+		now:          time.Now,
+	}
+}
+
+// ExportBackupRequest is the body for POST /api/v1/identity/backup/export
+type ExportBackupRequest struct {
+	Passphrase string `json:"passphrase"`
+}
+
+// ImportBackupRequest is the body for POST /api/v1/identity/backup/import
+type ImportBackupRequest struct {
+	Passphrase string `json:"passphrase"`
+	Bundle     []byte `json:"bundle"` // Base64 of the exported file
+	Overwrite  bool   `json:"overwrite,omitempty"`
+}
+
+// ImportBackupResponse describes a restored backup
+type ImportBackupResponse struct {
+	Success         bool             `json:"success"`
+	AID             string           `json:"aid"`
+	Manifest        *backup.Manifest `json:"manifest"`
+	RestartRequired bool             `json:"restartRequired"`
+}
+
+// authorized allows the local user, admins, and callers when authentication
+// is off. A backup holds the mnemonic, so other members' tokens are refused.
+func (h *BackupHandler) authorized(r *http.Request) bool {
+	p := PrincipalFromContext(r.Context())
+	if p == nil || p.Admin {
+		return true
+	}
+	return p.AID != "" && p.AID == h.userIdentity.GetAID()
+}
+
+// HandleExport handles POST /api/v1/identity/backup/export. The response is
+// the encrypted bundle as a file download.
+func (h *BackupHandler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if !h.authorized(r) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "only the local user can export a backup"})
+		return
+	}
+
+	var req ExportBackupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request body: %v", err),
+		})
+		return
+	}
+
+	now := h.now()
+	bundle, manifest, err := backup.Export(h.dataDir, h.sealer, req.Passphrase, now)
+	switch {
+	case errors.Is(err, backup.ErrPassphraseTooShort):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	case errors.Is(err, backup.ErrNoIdentity):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	case errors.Is(err, secret.ErrLocked), errors.Is(err, secret.ErrWrongPassphrase):
+		writeJSON(w, http.StatusConflict, map[string]string{
+			"error": fmt.Sprintf("identity is locked: %v", err),
+		})
+		return
+	case err != nil:
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to export backup: %v", err),
+		})
+		return
+	}
+
+	fmt.Printf("[Backup] Exported %d files (%d space key sets)\n", len(manifest.Files), manifest.SpaceKeys)
+	filename := fmt.Sprintf("matou-backup-%s.json", now.UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(bundle)
+}
+
+// HandleImport handles POST /api/v1/identity/backup/import. Files are
+// written to the data directory and the identity is reloaded; the peer key
+// and space clients only pick up the restored keys after a restart.
+func (h *BackupHandler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if !h.authorized(r) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "only the local user can import a backup"})
+		return
+	}
+
+	var req ImportBackupRequest
+	// Base64 inflates the bundle by a third
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBackupSize*4/3+1024)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request body: %v", err),
+		})
+		return
+	}
+	if len(req.Bundle) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "bundle is required"})
+		return
+	}
+
+	manifest, err := backup.Import(h.dataDir, req.Bundle, h.sealer, req.Passphrase, req.Overwrite)
+	switch {
+	case errors.Is(err, backup.ErrNotBackup):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	case errors.Is(err, secret.ErrWrongPassphrase):
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	case errors.Is(err, backup.ErrIdentityExists):
+		writeJSON(w, http.StatusConflict, map[string]string{
+			"error": err.Error() + "; set overwrite to replace it",
+		})
+		return
+	case err != nil:
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to import backup: %v", err),
+		})
+		return
+	}
+
+	h.userIdentity.Reload()
+	fmt.Printf("[Backup] Restored %d files (%d space key sets) for %s; restart to use them\n",
+		len(manifest.Files), manifest.SpaceKeys, h.userIdentity.GetAID())
+	writeJSON(w, http.StatusOK, ImportBackupResponse{
+		Success:         true,
+		AID:             h.userIdentity.GetAID(),
+		Manifest:        manifest,
+		RestartRequired: true,
+	})
+}
+
+// RegisterRoutes registers backup routes on the mux
+func (h *BackupHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/identity/backup/export", h.HandleExport)
+	mux.HandleFunc("/api/v1/identity/backup/import", h.HandleImport)
+}
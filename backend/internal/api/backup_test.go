@@ -0,0 +1,84 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+func TestBackupHandler_ExportImport(t *testing.T) {
+	srcDir := t.TempDir()
+	srcIdentity := identity.New(srcDir)
+	if err := srcIdentity.SetIdentity("EUSER", secret.NewMnemonic("test mnemonic")); err != nil {
+		t.Fatalf("SetIdentity failed: %v", err)
+	}
+	src := NewBackupHandler(srcDir, nil, srcIdentity)
+
+	w := httptest.NewRecorder()
+	src.HandleExport(w, httptest.NewRequest(http.MethodPost, "/api/v1/identity/backup/export", strings.NewReader(`{"passphrase":"short"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for short passphrase, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	src.HandleExport(w, httptest.NewRequest(http.MethodPost, "/api/v1/identity/backup/export", strings.NewReader(`{"passphrase":"a long passphrase"}`)))
+	if w.Code != http.StatusOK || !strings.Contains(w.Header().Get("Content-Disposition"), "matou-backup-") {
+		t.Fatalf("expected bundle download, got %d: %s", w.Code, w.Body.String())
+	}
+	bundle, _ := io.ReadAll(w.Body)
+
+	dstDir := t.TempDir()
+	dstIdentity := identity.New(dstDir)
+	dst := NewBackupHandler(dstDir, nil, dstIdentity)
+	importBody := func(passphrase string) io.Reader {
+		body, _ := json.Marshal(ImportBackupRequest{Passphrase: passphrase, Bundle: bundle})
+		return bytes.NewReader(body)
+	}
+
+	w = httptest.NewRecorder()
+	dst.HandleImport(w, httptest.NewRequest(http.MethodPost, "/api/v1/identity/backup/import", importBody("wrong passphrase")))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for wrong passphrase, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	dst.HandleImport(w, httptest.NewRequest(http.MethodPost, "/api/v1/identity/backup/import", importBody("a long passphrase")))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected import to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp ImportBackupResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.AID != "EUSER" || !resp.RestartRequired || dstIdentity.GetAID() != "EUSER" {
+		t.Errorf("expected restored identity EUSER, got %+v", resp)
+	}
+
+	// A second import would replace the identity
+	w = httptest.NewRecorder()
+	dst.HandleImport(w, httptest.NewRequest(http.MethodPost, "/api/v1/identity/backup/import", importBody("a long passphrase")))
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected 409 without overwrite, got %d", w.Code)
+	}
+}
+
+func TestBackupHandler_OtherMembersRefused(t *testing.T) {
+	dir := t.TempDir()
+	userIdentity := identity.New(dir)
+	userIdentity.SetIdentity("EUSER", secret.NewMnemonic("test mnemonic"))
+	h := NewBackupHandler(dir, nil, userIdentity)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/identity/backup/export", strings.NewReader(`{"passphrase":"a long passphrase"}`))
+	req = req.WithContext(context.WithValue(req.Context(), principalKey{}, &Principal{Kind: "token", AID: "EOTHER"}))
+	w := httptest.NewRecorder()
+	h.HandleExport(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for another member's token, got %d", w.Code)
+	}
+}
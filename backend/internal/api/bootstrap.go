@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/matou-dao/backend/internal/bootstrap"
+)
+
+// BootstrapHandler runs space bootstrap and reports its progress
+type BootstrapHandler struct {
+	orchestrator *bootstrap.Orchestrator
+}
+
+// NewBootstrapHandler creates a new bootstrap handler
+func NewBootstrapHandler(orchestrator *bootstrap.Orchestrator) *BootstrapHandler {
+	return &BootstrapHandler{orchestrator: orchestrator}
+}
+
+// HandleRun handles POST /api/v1/bootstrap. It creates or recovers every
+// space (private, community, read-only, admin) and writes the org config.
+func (h *BootstrapHandler) HandleRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	var req bootstrap.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+	if req.OrgAID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "orgAid is required"})
+		return
+	}
+
+	status, err := h.orchestrator.Run(r.Context(), req)
+	switch {
+	case errors.Is(err, bootstrap.ErrNoIdentity):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, bootstrap.ErrNoClient):
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+	case err != nil:
+		writeJSON(w, http.StatusInternalServerError, status)
+	default:
+		writeJSON(w, http.StatusOK, status)
+	}
+}
+
+// HandleStatus handles GET /api/v1/bootstrap/status
+func (h *BootstrapHandler) HandleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	writeJSON(w, http.StatusOK, h.orchestrator.Status())
+}
+
+// RegisterRoutes registers bootstrap routes on the mux
+func (h *BootstrapHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/bootstrap", h.HandleRun)
+	mux.HandleFunc("/api/v1/bootstrap/status", h.HandleStatus)
+}
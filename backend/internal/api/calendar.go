@@ -0,0 +1,623 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/trust"
+)
+
+// Calendar event statuses and RSVP responses
+const (
+	EventScheduled = "scheduled"
+	EventCancelled = "cancelled"
+	RSVPGoing      = "going"
+	RSVPMaybe      = "maybe"
+	RSVPNotGoing   = "not_going"
+)
+
+// maxEventTitle bounds the event title length
+const maxEventTitle = 200
+
+// CalendarHandler handles community calendar endpoints. Events live in the
+// community read-only space (admin-written); RSVPs live in the community
+// space so every member can respond.
+type CalendarHandler struct {
+	spaceManager *anysync.SpaceManager
+	userIdentity *identity.UserIdentity
+	trust        *TrustHandler
+}
+
+// NewCalendarHandler creates a new calendar handler
+func NewCalendarHandler(
+	spaceManager *anysync.SpaceManager,
+	userIdentity *identity.UserIdentity,
+	trustHandler *TrustHandler,
+) *CalendarHandler {
+	return &CalendarHandler{
+		spaceManager: spaceManager,
+		userIdentity: userIdentity,
+		trust:        trustHandler,
+	}
+}
+
+// EventAttendee records a member's verified attendance
+type EventAttendee struct {
+	AID        string `json:"aid"`
+	RecordedAt string `json:"recordedAt"`
+	RecordedBy string `json:"recordedBy"`
+}
+
+// CommunityEvent is the data stored for an Event object
+type CommunityEvent struct {
+	Title            string          `json:"title"`
+	Description      string          `json:"description,omitempty"`
+	StartsAt         string          `json:"startsAt"` // RFC3339
+	EndsAt           string          `json:"endsAt,omitempty"`
+	Location         string          `json:"location,omitempty"`
+	Capacity         int             `json:"capacity,omitempty"` // 0 means unlimited
+	Status           string          `json:"status"`
+	IssueCredentials bool            `json:"issueCredentials,omitempty"`
+	Attendees        []EventAttendee `json:"attendees,omitempty"`
+	CreatedBy        string          `json:"createdBy"`
+	CreatedAt        string          `json:"createdAt"`
+	UpdatedAt        string          `json:"updatedAt,omitempty"`
+}
+
+// EventRSVP is the data stored for an EventRSVP object
+type EventRSVP struct {
+	EventID   string `json:"eventId"`
+	AID       string `json:"aid"`
+	Response  string `json:"response"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// CommunityEventView is an event with its object ID and RSVP tallies
+type CommunityEventView struct {
+	ID string `json:"id"`
+	CommunityEvent
+	RSVPCounts map[string]int `json:"rsvpCounts"`
+	RSVPs      []*EventRSVP   `json:"rsvps,omitempty"`
+}
+
+// CreateEventRequest is the body for POST /api/v1/calendar/events
+type CreateEventRequest struct {
+	Title            string `json:"title"`
+	Description      string `json:"description,omitempty"`
+	StartsAt         string `json:"startsAt"`
+	EndsAt           string `json:"endsAt,omitempty"`
+	Location         string `json:"location,omitempty"`
+	Capacity         int    `json:"capacity,omitempty"`
+	IssueCredentials bool   `json:"issueCredentials,omitempty"`
+}
+
+// RSVPRequest is the body for POST /api/v1/calendar/events/{id}/rsvp
+type RSVPRequest struct {
+	Response string `json:"response"` // going, maybe, not_going
+}
+
+// RecordAttendanceRequest is the body for POST .../events/{id}/attendance
+type RecordAttendanceRequest struct {
+	AIDs []string `json:"aids"`
+}
+
+// Validate checks the event's required fields and times
+func (e *CommunityEvent) Validate() error {
+	if strings.TrimSpace(e.Title) == "" || len(e.Title) > maxEventTitle {
+		return fmt.Errorf("title is required (max %d characters)", maxEventTitle)
+	}
+	start, err := time.Parse(time.RFC3339, e.StartsAt)
+	if err != nil {
+		return fmt.Errorf("startsAt must be RFC3339")
+	}
+	if e.EndsAt != "" {
+		end, err := time.Parse(time.RFC3339, e.EndsAt)
+		if err != nil {
+			return fmt.Errorf("endsAt must be RFC3339")
+		}
+		if !end.After(start) {
+			return fmt.Errorf("endsAt must be after startsAt")
+		}
+	}
+	if e.Capacity < 0 {
+		return fmt.Errorf("capacity cannot be negative")
+	}
+	return nil
+}
+
+// hasStarted returns true once the event's start time has passed
+func (e *CommunityEvent) hasStarted(now time.Time) bool {
+	start, err := time.Parse(time.RFC3339, e.StartsAt)
+	return err == nil && !now.Before(start)
+}
+
+// countRSVPs tallies responses by type
+func countRSVPs(rsvps []*EventRSVP) map[string]int {
+	counts := map[string]int{RSVPGoing: 0, RSVPMaybe: 0, RSVPNotGoing: 0}
+	for _, r := range rsvps {
+		counts[r.Response]++
+	}
+	return counts
+}
+
+// checkRSVP applies the RSVP rules: only scheduled, upcoming events accept
+// responses, and "going" is refused once capacity is reached. Returns an
+// HTTP status alongside any error.
+func checkRSVP(event *CommunityEvent, rsvps []*EventRSVP, aid, response string, now time.Time) (int, error) {
+	switch response {
+	case RSVPGoing, RSVPMaybe, RSVPNotGoing:
+	default:
+		return http.StatusBadRequest, fmt.Errorf("response must be going, maybe or not_going")
+	}
+	if event.Status != EventScheduled {
+		return http.StatusConflict, fmt.Errorf("event is %s", event.Status)
+	}
+	if event.hasStarted(now) {
+		return http.StatusConflict, fmt.Errorf("event has already started")
+	}
+	if response == RSVPGoing && event.Capacity > 0 {
+		going := 0
+		for _, r := range rsvps {
+			if r.Response == RSVPGoing && r.AID != aid {
+				going++
+			}
+		}
+		if going >= event.Capacity {
+			return http.StatusConflict, fmt.Errorf("event is full")
+		}
+	}
+	return http.StatusOK, nil
+}
+
+// handleEvents routes /api/v1/calendar/events
+func (h *CalendarHandler) handleEvents(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.HandleList(w, r)
+	case http.MethodPost:
+		h.HandleCreate(w, r)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// HandleList handles GET /api/v1/calendar/events
+// Query params:
+//   - upcoming: "true" to only return scheduled events that haven't started
+func (h *CalendarHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	events, err := h.readEvents(ctx)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read events: %v", err),
+		})
+		return
+	}
+	rsvps, err := h.readRSVPs(ctx, "")
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read RSVPs: %v", err),
+		})
+		return
+	}
+
+	byEvent := make(map[string][]*EventRSVP)
+	for _, rsvp := range rsvps {
+		byEvent[rsvp.EventID] = append(byEvent[rsvp.EventID], rsvp)
+	}
+
+	upcoming := r.URL.Query().Get("upcoming") == "true"
+	now := time.Now().UTC()
+	result := make([]*CommunityEventView, 0)
+	for _, e := range events {
+		if upcoming && (e.Status != EventScheduled || e.hasStarted(now)) {
+			continue
+		}
+		e.RSVPCounts = countRSVPs(byEvent[e.ID])
+		result = append(result, e)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].StartsAt < result[j].StartsAt
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"events": result,
+		"count":  len(result),
+	})
+}
+
+// HandleCreate handles POST /api/v1/calendar/events (admin)
+func (h *CalendarHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+
+	var req CreateEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	event := &CommunityEventView{
+		ID: fmt.Sprintf("Event-%d", time.Now().UnixMilli()),
+		CommunityEvent: CommunityEvent{
+			Title:            strings.TrimSpace(req.Title),
+			Description:      req.Description,
+			StartsAt:         req.StartsAt,
+			EndsAt:           req.EndsAt,
+			Location:         req.Location,
+			Capacity:         req.Capacity,
+			Status:           EventScheduled,
+			IssueCredentials: req.IssueCredentials,
+			CreatedBy:        me,
+			CreatedAt:        now,
+		},
+	}
+	if err := event.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if status, err := h.saveEvent(r.Context(), event); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	fmt.Printf("[Calendar] %s scheduled %s (%s)\n", me, event.ID, event.Title)
+	event.RSVPCounts = countRSVPs(nil)
+	writeJSON(w, http.StatusCreated, event)
+}
+
+// handleEvent routes /api/v1/calendar/events/{id}[/...]
+func (h *CalendarHandler) handleEvent(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/calendar/events/")
+	parts := strings.Split(path, "/")
+	if parts[0] == "" || len(parts) > 2 {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+	id := parts[0]
+	action := ""
+	if len(parts) == 2 {
+		action = parts[1]
+	}
+
+	method := http.MethodPost
+	if action == "" || action == "credentials" {
+		method = http.MethodGet
+	}
+	if r.Method != method {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if (action == "cancel" || action == "attendance") && !isAdminCaller(r) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "admin required"})
+		return
+	}
+
+	switch action {
+	case "":
+		h.handleGet(w, r, id)
+	case "cancel":
+		h.handleCancel(w, r, id)
+	case "rsvp":
+		h.handleRSVP(w, r, id)
+	case "attendance":
+		h.handleRecordAttendance(w, r, id)
+	case "credentials":
+		h.handleCredentials(w, r, id)
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
+
+// handleGet handles GET /api/v1/calendar/events/{id}, including RSVPs
+func (h *CalendarHandler) handleGet(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+	event, status, err := h.loadEvent(ctx, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	rsvps, err := h.readRSVPs(ctx, id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read RSVPs: %v", err),
+		})
+		return
+	}
+	event.RSVPs = rsvps
+	event.RSVPCounts = countRSVPs(rsvps)
+	writeJSON(w, http.StatusOK, event)
+}
+
+// handleCancel handles POST /api/v1/calendar/events/{id}/cancel (admin)
+func (h *CalendarHandler) handleCancel(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+	event, status, err := h.loadEvent(ctx, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	if event.Status == EventCancelled {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "event is already cancelled"})
+		return
+	}
+
+	event.Status = EventCancelled
+	if status, err := h.saveEvent(ctx, event); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, event)
+}
+
+// handleRSVP handles POST /api/v1/calendar/events/{id}/rsvp
+func (h *CalendarHandler) handleRSVP(w http.ResponseWriter, r *http.Request, id string) {
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+
+	var req RSVPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+
+	ctx := r.Context()
+	event, status, err := h.loadEvent(ctx, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	rsvps, err := h.readRSVPs(ctx, id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read RSVPs: %v", err),
+		})
+		return
+	}
+	if status, err := checkRSVP(&event.CommunityEvent, rsvps, me, req.Response, time.Now().UTC()); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	rsvp := EventRSVP{
+		EventID:   id,
+		AID:       me,
+		Response:  req.Response,
+		UpdatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	objectID := fmt.Sprintf("EventRSVP-%s-%s", id, me)
+	if _, err := writeObject(ctx, h.spaceManager, h.spaceManager.GetCommunitySpaceID(), objectID, "EventRSVP", rsvp); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rsvp)
+}
+
+// handleRecordAttendance handles POST /api/v1/calendar/events/{id}/attendance
+// (admin). Adds members to the event's verified attendee list.
+func (h *CalendarHandler) handleRecordAttendance(w http.ResponseWriter, r *http.Request, id string) {
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+
+	var req RecordAttendanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+	if len(req.AIDs) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "aids is required"})
+		return
+	}
+
+	ctx := r.Context()
+	event, status, err := h.loadEvent(ctx, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	if event.Status != EventScheduled {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": fmt.Sprintf("event is %s", event.Status)})
+		return
+	}
+	if !event.hasStarted(time.Now().UTC()) {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "event has not started yet"})
+		return
+	}
+
+	recorded := make(map[string]bool)
+	for _, a := range event.Attendees {
+		recorded[a.AID] = true
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	added := 0
+	for _, aid := range req.AIDs {
+		if aid == "" || recorded[aid] {
+			continue
+		}
+		recorded[aid] = true
+		event.Attendees = append(event.Attendees, EventAttendee{AID: aid, RecordedAt: now, RecordedBy: me})
+		added++
+	}
+
+	if added > 0 {
+		if status, err := h.saveEvent(ctx, event); err != nil {
+			writeJSON(w, status, map[string]string{"error": err.Error()})
+			return
+		}
+	}
+
+	fmt.Printf("[Calendar] Recorded %d attendees for %s\n", added, event.ID)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"added":     added,
+		"attendees": event.Attendees,
+	})
+}
+
+// handleCredentials handles GET /api/v1/calendar/events/{id}/credentials.
+// Returns pre-filled attendance credentials for attendees who don't hold
+// one for this event yet; the admin's client issues them via KERIA.
+func (h *CalendarHandler) handleCredentials(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+	event, status, err := h.loadEvent(ctx, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	if !event.IssueCredentials {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "attendance credentials are not enabled for this event"})
+		return
+	}
+
+	issuer := ""
+	held := make(map[string]bool)
+	if h.trust != nil {
+		issuer = h.trust.orgAID
+		creds, err := h.trust.allCredentials(ctx)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"error": fmt.Sprintf("failed to read credentials: %v", err),
+			})
+			return
+		}
+		for _, cred := range creds {
+			if cred.SchemaID != trust.AttendanceSchema {
+				continue
+			}
+			if data, ok := cred.Data.(map[string]interface{}); ok && data["eventId"] == id {
+				held[cred.SubjectAID] = true
+			}
+		}
+	}
+
+	issuances := make([]CredentialIssuance, 0)
+	for _, a := range event.Attendees {
+		if held[a.AID] {
+			continue
+		}
+		issuances = append(issuances, CredentialIssuance{
+			Schema:    trust.AttendanceSchema,
+			Issuer:    issuer,
+			Recipient: a.AID,
+			Data: map[string]interface{}{
+				"eventId":    id,
+				"eventTitle": event.Title,
+				"attendedAt": event.StartsAt,
+				"recordedBy": a.RecordedBy,
+			},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuances": issuances,
+		"count":     len(issuances),
+	})
+}
+
+// readEvents returns the latest version of every event
+func (h *CalendarHandler) readEvents(ctx context.Context) ([]*CommunityEventView, error) {
+	roSpaceID := h.spaceManager.GetCommunityReadOnlySpaceID()
+	if roSpaceID == "" {
+		return nil, nil
+	}
+	objects, err := readLatestObjects(ctx, h.spaceManager, roSpaceID, "Event")
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*CommunityEventView, 0, len(objects))
+	for _, obj := range objects {
+		e := &CommunityEventView{ID: obj.ID}
+		if err := json.Unmarshal(obj.Data, &e.CommunityEvent); err != nil {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// readRSVPs returns the latest RSVPs, optionally for a single event
+func (h *CalendarHandler) readRSVPs(ctx context.Context, eventID string) ([]*EventRSVP, error) {
+	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
+	if communitySpaceID == "" {
+		return nil, nil
+	}
+	objects, err := readLatestObjects(ctx, h.spaceManager, communitySpaceID, "EventRSVP")
+	if err != nil {
+		return nil, err
+	}
+	rsvps := make([]*EventRSVP, 0)
+	for _, obj := range objects {
+		var rsvp EventRSVP
+		if err := json.Unmarshal(obj.Data, &rsvp); err != nil {
+			continue
+		}
+		if eventID != "" && rsvp.EventID != eventID {
+			continue
+		}
+		rsvps = append(rsvps, &rsvp)
+	}
+	return rsvps, nil
+}
+
+// loadEvent reads the latest version of an event
+func (h *CalendarHandler) loadEvent(ctx context.Context, id string) (*CommunityEventView, int, error) {
+	roSpaceID := h.spaceManager.GetCommunityReadOnlySpaceID()
+	if roSpaceID == "" {
+		return nil, http.StatusServiceUnavailable, fmt.Errorf("community-readonly space not configured")
+	}
+
+	obj, err := h.spaceManager.ObjectTreeManager().ReadLatestByID(ctx, roSpaceID, id)
+	if err != nil || obj.Type != "Event" {
+		return nil, http.StatusNotFound, fmt.Errorf("event not found")
+	}
+
+	event := &CommunityEventView{ID: obj.ID}
+	if err := json.Unmarshal(obj.Data, &event.CommunityEvent); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("invalid event: %v", err)
+	}
+	return event, http.StatusOK, nil
+}
+
+// saveEvent writes a new version of an event to the community read-only
+// space. Only admins hold its keys, so other members get an error here.
+func (h *CalendarHandler) saveEvent(ctx context.Context, event *CommunityEventView) (int, error) {
+	roSpaceID := h.spaceManager.GetCommunityReadOnlySpaceID()
+	if roSpaceID == "" {
+		return http.StatusConflict, fmt.Errorf("community-readonly space not configured")
+	}
+	event.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	if _, err := writeObject(ctx, h.spaceManager, roSpaceID, event.ID, "Event", event.CommunityEvent); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+// RegisterRoutes registers calendar routes on the mux
+func (h *CalendarHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/calendar/events", h.handleEvents)
+	mux.HandleFunc("/api/v1/calendar/events/", h.handleEvent)
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCommunityEvent_Validate(t *testing.T) {
+	valid := CommunityEvent{Title: "Hui", StartsAt: "2026-11-01T18:00:00Z", EndsAt: "2026-11-01T20:00:00Z"}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected valid event, got %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		event CommunityEvent
+	}{
+		{"missing title", CommunityEvent{Title: " ", StartsAt: "2026-11-01T18:00:00Z"}},
+		{"bad start", CommunityEvent{Title: "Hui", StartsAt: "next tuesday"}},
+		{"end before start", CommunityEvent{Title: "Hui", StartsAt: "2026-11-01T18:00:00Z", EndsAt: "2026-11-01T17:00:00Z"}},
+		{"negative capacity", CommunityEvent{Title: "Hui", StartsAt: "2026-11-01T18:00:00Z", Capacity: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.event.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestCheckRSVP(t *testing.T) {
+	now := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	event := &CommunityEvent{Title: "Hui", StartsAt: "2026-11-01T18:00:00Z", Status: EventScheduled, Capacity: 1}
+	full := []*EventRSVP{{AID: "EBOB", Response: RSVPGoing}}
+
+	tests := []struct {
+		name       string
+		event      *CommunityEvent
+		rsvps      []*EventRSVP
+		aid        string
+		response   string
+		wantStatus int
+	}{
+		{"going with space", event, nil, "EALICE", RSVPGoing, http.StatusOK},
+		{"going when full", event, full, "EALICE", RSVPGoing, http.StatusConflict},
+		{"maybe when full", event, full, "EALICE", RSVPMaybe, http.StatusOK},
+		{"re-confirm own seat", event, full, "EBOB", RSVPGoing, http.StatusOK},
+		{"invalid response", event, nil, "EALICE", "perhaps", http.StatusBadRequest},
+		{"cancelled", &CommunityEvent{StartsAt: event.StartsAt, Status: EventCancelled}, nil, "EALICE", RSVPGoing, http.StatusConflict},
+		{"already started", &CommunityEvent{StartsAt: "2026-10-19T00:00:00Z", Status: EventScheduled}, nil, "EALICE", RSVPGoing, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, _ := checkRSVP(tt.event, tt.rsvps, tt.aid, tt.response, now)
+			if status != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, status)
+			}
+		})
+	}
+}
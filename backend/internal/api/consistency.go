@@ -0,0 +1,208 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+// ConsistencyHeader carries a consistency token. Writes return one naming
+// the changes they made; a read that sends it back waits until those
+// changes are applied, so a client always reads its own writes.
+const ConsistencyHeader = "X-Consistency-Token"
+
+const (
+	// maxTokenSpaces bounds how many spaces a token names, dropping the
+	// least recently written first
+	maxTokenSpaces = 16
+	// maxTokenCredentials bounds how many credential SAIDs a token names,
+	// dropping the oldest first
+	maxTokenCredentials = 32
+	// consistencyPoll is how often a waiting read checks again
+	consistencyPoll = 25 * time.Millisecond
+)
+
+// ConsistencyToken names the changes a client has written: tree changes
+// by space, and cached credentials by SAID. Clients treat it as opaque and
+// send back the latest one they were given, which covers their earlier
+// writes too.
+type ConsistencyToken struct {
+	Spaces      []SpaceChanges `json:"t,omitempty"`
+	Credentials []string       `json:"c,omitempty"`
+}
+
+// SpaceChanges are tree changes written to one space. Only the latest
+// write's heads are kept, as a tree holding them holds everything before.
+type SpaceChanges struct {
+	SpaceID string   `json:"s"`
+	Changes []string `json:"h"`
+}
+
+// ParseConsistencyToken decodes a token from ConsistencyHeader
+func ParseConsistencyToken(s string) (*ConsistencyToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid consistency token")
+	}
+	var t ConsistencyToken
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid consistency token")
+	}
+	return &t, nil
+}
+
+// String encodes the token for ConsistencyHeader
+func (t *ConsistencyToken) String() string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// AddChanges records changes written to a space, replacing the ones
+// recorded for it before
+func (t *ConsistencyToken) AddChanges(spaceID string, changeIDs ...string) {
+	if spaceID == "" || len(changeIDs) == 0 {
+		return
+	}
+	for i, sc := range t.Spaces {
+		if sc.SpaceID == spaceID {
+			t.Spaces = append(t.Spaces[:i], t.Spaces[i+1:]...)
+			break
+		}
+	}
+	t.Spaces = append(t.Spaces, SpaceChanges{SpaceID: spaceID, Changes: changeIDs})
+	if len(t.Spaces) > maxTokenSpaces {
+		t.Spaces = t.Spaces[len(t.Spaces)-maxTokenSpaces:]
+	}
+}
+
+// AddCredentials records credentials written to the credential cache
+func (t *ConsistencyToken) AddCredentials(saids ...string) {
+	for _, said := range saids {
+		for i, existing := range t.Credentials {
+			if existing == said {
+				t.Credentials = append(t.Credentials[:i], t.Credentials[i+1:]...)
+				break
+			}
+		}
+		t.Credentials = append(t.Credentials, said)
+	}
+	if len(t.Credentials) > maxTokenCredentials {
+		t.Credentials = t.Credentials[len(t.Credentials)-maxTokenCredentials:]
+	}
+}
+
+// requestConsistencyToken returns the token a write request carried, to
+// add the write's changes to. A missing or unreadable token starts afresh.
+func requestConsistencyToken(r *http.Request) *ConsistencyToken {
+	if header := r.Header.Get(ConsistencyHeader); header != "" {
+		if t, err := ParseConsistencyToken(header); err == nil {
+			return t
+		}
+	}
+	return &ConsistencyToken{}
+}
+
+// setConsistencyToken returns a write's token. Call before writing the
+// response body.
+func setConsistencyToken(w http.ResponseWriter, t *ConsistencyToken) {
+	if len(t.Spaces) == 0 && len(t.Credentials) == 0 {
+		return
+	}
+	w.Header().Set(ConsistencyHeader, t.String())
+}
+
+// ChangeSource reports whether tree changes are applied. It is implemented
+// by anysync.ObjectTreeManager.
+type ChangeSource interface {
+	HasChanges(ctx context.Context, spaceID string, changeIDs []string) (bool, error)
+}
+
+// CredentialSource looks up cached credentials. It is implemented by
+// anystore.LocalStore.
+type CredentialSource interface {
+	GetCredential(ctx context.Context, said string) (*anystore.CachedCredential, error)
+}
+
+// Consistency waits for the changes named by consistency tokens. A nil
+// source means those changes aren't served here and are never waited for.
+type Consistency struct {
+	changes     ChangeSource
+	credentials CredentialSource
+	timeout     time.Duration
+}
+
+// NewConsistency creates a waiter that gives up after timeout
+func NewConsistency(changes ChangeSource, credentials CredentialSource, timeout time.Duration) *Consistency {
+	return &Consistency{
+		changes:     changes,
+		credentials: credentials,
+		timeout:     timeout,
+	}
+}
+
+// Wait blocks until every change the token names is applied. It gives up
+// once the timeout passes or ctx ends.
+func (c *Consistency) Wait(ctx context.Context, t *ConsistencyToken) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(consistencyPoll)
+	defer ticker.Stop()
+	for !c.applied(ctx, t) {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("changes not applied within %s", c.timeout)
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// applied reports whether every change the token names is applied
+func (c *Consistency) applied(ctx context.Context, t *ConsistencyToken) bool {
+	if c.changes != nil {
+		for _, sc := range t.Spaces {
+			if ok, err := c.changes.HasChanges(ctx, sc.SpaceID, sc.Changes); err != nil || !ok {
+				return false
+			}
+		}
+	}
+	if c.credentials != nil {
+		for _, said := range t.Credentials {
+			if _, err := c.credentials.GetCredential(ctx, said); err != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ConsistencyMiddleware holds GET requests carrying a consistency token
+// until the changes it names are applied. A read that can't catch up in
+// time gets 503 rather than a response missing the client's own writes.
+func ConsistencyMiddleware(c *Consistency, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get(ConsistencyHeader)
+		if header == "" || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		t, err := ParseConsistencyToken(header)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if err := c.Wait(r.Context(), t); err != nil {
+			w.Header().Set("Retry-After", "1")
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
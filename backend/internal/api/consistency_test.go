@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+// fakeChanges applies a space's changes after it has been asked a number
+// of times
+type fakeChanges struct {
+	after atomic.Int32
+	calls atomic.Int32
+}
+
+func (f *fakeChanges) HasChanges(ctx context.Context, spaceID string, changeIDs []string) (bool, error) {
+	return f.calls.Add(1) > f.after.Load(), nil
+}
+
+// fakeCredentials holds the SAIDs in the credential cache
+type fakeCredentials map[string]bool
+
+func (f fakeCredentials) GetCredential(ctx context.Context, said string) (*anystore.CachedCredential, error) {
+	if !f[said] {
+		return nil, fmt.Errorf("credential not found")
+	}
+	return &anystore.CachedCredential{ID: said}, nil
+}
+
+func TestConsistencyToken(t *testing.T) {
+	token := &ConsistencyToken{}
+	token.AddChanges("space-1", "head-1")
+	token.AddChanges("space-2", "head-2")
+	token.AddChanges("space-1", "head-3")
+	token.AddCredentials("ESAID1", "ESAID2", "ESAID1")
+
+	parsed, err := ParseConsistencyToken(token.String())
+	if err != nil {
+		t.Fatalf("ParseConsistencyToken: %v", err)
+	}
+	if len(parsed.Spaces) != 2 || parsed.Spaces[1].SpaceID != "space-1" || parsed.Spaces[1].Changes[0] != "head-3" {
+		t.Errorf("expected a space's latest write kept, got %+v", parsed.Spaces)
+	}
+	if len(parsed.Credentials) != 2 || parsed.Credentials[1] != "ESAID1" {
+		t.Errorf("expected credentials deduplicated, got %v", parsed.Credentials)
+	}
+
+	for i := 0; i < maxTokenCredentials+5; i++ {
+		token.AddCredentials(fmt.Sprintf("ESAID%d", i+10))
+	}
+	if len(token.Credentials) != maxTokenCredentials {
+		t.Errorf("expected %d credentials kept, got %d", maxTokenCredentials, len(token.Credentials))
+	}
+
+	if _, err := ParseConsistencyToken("not a token!"); err == nil {
+		t.Error("expected an invalid token rejected")
+	}
+}
+
+func TestRequestConsistencyToken(t *testing.T) {
+	earlier := &ConsistencyToken{}
+	earlier.AddCredentials("ESAID1")
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/profiles", nil)
+	req.Header.Set(ConsistencyHeader, earlier.String())
+
+	token := requestConsistencyToken(req)
+	token.AddChanges("space-1", "head-1")
+	w := httptest.NewRecorder()
+	setConsistencyToken(w, token)
+
+	got, err := ParseConsistencyToken(w.Header().Get(ConsistencyHeader))
+	if err != nil {
+		t.Fatalf("ParseConsistencyToken: %v", err)
+	}
+	if len(got.Credentials) != 1 || len(got.Spaces) != 1 {
+		t.Errorf("expected the write's token to cover the earlier write, got %+v", got)
+	}
+}
+
+func TestConsistencyMiddleware(t *testing.T) {
+	changes := &fakeChanges{}
+	credentials := fakeCredentials{"ESAID1": true}
+	consistency := NewConsistency(changes, credentials, 200*time.Millisecond)
+	handler := ConsistencyMiddleware(consistency, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(method string, token string) int {
+		req := httptest.NewRequest(method, "/api/v1/profiles/SharedProfile", nil)
+		if token != "" {
+			req.Header.Set(ConsistencyHeader, token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	written := &ConsistencyToken{}
+	written.AddChanges("space-1", "head-1")
+	written.AddCredentials("ESAID1")
+
+	// The change lands while the read waits
+	changes.after.Store(2)
+	if code := serve(http.MethodGet, written.String()); code != http.StatusOK {
+		t.Errorf("expected the read served once the change applied, got %d", code)
+	}
+	if changes.calls.Load() != 3 {
+		t.Errorf("expected the read to wait for the change, checked %d times", changes.calls.Load())
+	}
+
+	missing := &ConsistencyToken{}
+	missing.AddCredentials("ESAID2")
+	if code := serve(http.MethodGet, missing.String()); code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 for a write never applied, got %d", code)
+	}
+	if code := serve(http.MethodGet, "not a token!"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid token, got %d", code)
+	}
+	if code := serve(http.MethodPost, missing.String()); code != http.StatusOK {
+		t.Errorf("expected writes not held, got %d", code)
+	}
+	if code := serve(http.MethodGet, ""); code != http.StatusOK {
+		t.Errorf("expected reads without a token not held, got %d", code)
+	}
+}
@@ -0,0 +1,427 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/keri"
+)
+
+// Contribution categories
+const (
+	ContributionCode         = "code"
+	ContributionFacilitation = "facilitation"
+	ContributionContent      = "content"
+	ContributionOther        = "other"
+)
+
+// maxContributionTitle bounds the contribution title length
+const maxContributionTitle = 200
+
+// ContributionCategories returns the valid contribution categories
+func ContributionCategories() []string {
+	return []string{ContributionCode, ContributionFacilitation, ContributionContent, ContributionOther}
+}
+
+// ContributionsHandler handles the contribution ledger. Contributions live
+// in the community space; only stewards (roles with the admin permission)
+// may record them.
+type ContributionsHandler struct {
+	spaceManager *anysync.SpaceManager
+	userIdentity *identity.UserIdentity
+	trust        *TrustHandler
+}
+
+// NewContributionsHandler creates a new contributions handler
+func NewContributionsHandler(
+	spaceManager *anysync.SpaceManager,
+	userIdentity *identity.UserIdentity,
+	trustHandler *TrustHandler,
+) *ContributionsHandler {
+	return &ContributionsHandler{
+		spaceManager: spaceManager,
+		userIdentity: userIdentity,
+		trust:        trustHandler,
+	}
+}
+
+// Contribution is the data stored for a Contribution object
+type Contribution struct {
+	AID         string  `json:"aid"`
+	Category    string  `json:"category"`
+	Title       string  `json:"title"`
+	Description string  `json:"description,omitempty"`
+	Link        string  `json:"link,omitempty"`
+	Hours       float64 `json:"hours,omitempty"`
+	OccurredAt  string  `json:"occurredAt,omitempty"` // RFC3339
+	RecordedBy  string  `json:"recordedBy"`
+	RecordedAt  string  `json:"recordedAt"`
+}
+
+// ContributionView is a contribution with its object ID
+type ContributionView struct {
+	ID string `json:"id"`
+	Contribution
+}
+
+// ContributionSummary totals a member's recorded contributions
+type ContributionSummary struct {
+	AID                string         `json:"aid"`
+	Total              int            `json:"total"`
+	ByCategory         map[string]int `json:"byCategory"`
+	Hours              float64        `json:"hours"`
+	LastContributionAt string         `json:"lastContributionAt,omitempty"`
+}
+
+// RecordContributionRequest is the body for POST /api/v1/contributions
+type RecordContributionRequest struct {
+	AID         string  `json:"aid"`
+	Category    string  `json:"category"`
+	Title       string  `json:"title"`
+	Description string  `json:"description,omitempty"`
+	Link        string  `json:"link,omitempty"`
+	Hours       float64 `json:"hours,omitempty"`
+	OccurredAt  string  `json:"occurredAt,omitempty"`
+}
+
+// Validate checks the contribution's fields
+func (c *Contribution) Validate() error {
+	if c.AID == "" {
+		return fmt.Errorf("aid is required")
+	}
+	valid := false
+	for _, cat := range ContributionCategories() {
+		if c.Category == cat {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("category must be one of: %s", strings.Join(ContributionCategories(), ", "))
+	}
+	if c.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if len(c.Title) > maxContributionTitle {
+		return fmt.Errorf("title must be at most %d characters", maxContributionTitle)
+	}
+	if c.Hours < 0 {
+		return fmt.Errorf("hours cannot be negative")
+	}
+	if c.OccurredAt != "" {
+		if _, err := time.Parse(time.RFC3339, c.OccurredAt); err != nil {
+			return fmt.Errorf("occurredAt must be RFC3339")
+		}
+	}
+	return nil
+}
+
+// when returns the time the contribution is dated by
+func (c *Contribution) when() string {
+	if c.OccurredAt != "" {
+		return c.OccurredAt
+	}
+	return c.RecordedAt
+}
+
+// summarizeContributions totals contributions per member
+func summarizeContributions(contributions []*ContributionView) map[string]*ContributionSummary {
+	summaries := make(map[string]*ContributionSummary)
+	for _, c := range contributions {
+		s, ok := summaries[c.AID]
+		if !ok {
+			s = &ContributionSummary{AID: c.AID, ByCategory: make(map[string]int)}
+			summaries[c.AID] = s
+		}
+		s.Total++
+		s.ByCategory[c.Category]++
+		s.Hours += c.Hours
+		if when := c.when(); when > s.LastContributionAt {
+			s.LastContributionAt = when
+		}
+	}
+	return summaries
+}
+
+// handleContributions routes /api/v1/contributions
+func (h *ContributionsHandler) handleContributions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.HandleList(w, r)
+	case http.MethodPost:
+		h.HandleRecord(w, r)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// HandleList handles GET /api/v1/contributions
+// Query params:
+//   - aid: Only contributions by this member (optional)
+//   - category: Only contributions in this category (optional)
+func (h *ContributionsHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	contributions, err := h.readContributions(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read contributions: %v", err),
+		})
+		return
+	}
+
+	aid := r.URL.Query().Get("aid")
+	category := r.URL.Query().Get("category")
+	result := make([]*ContributionView, 0, len(contributions))
+	for _, c := range contributions {
+		if aid != "" && c.AID != aid {
+			continue
+		}
+		if category != "" && c.Category != category {
+			continue
+		}
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].when() > result[j].when()
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"contributions": result,
+		"count":         len(result),
+	})
+}
+
+// HandleRecord handles POST /api/v1/contributions (steward)
+func (h *ContributionsHandler) HandleRecord(w http.ResponseWriter, r *http.Request) {
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+
+	var req RecordContributionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+
+	contribution := &ContributionView{
+		ID: fmt.Sprintf("Contribution-%d", time.Now().UnixMilli()),
+		Contribution: Contribution{
+			AID:         req.AID,
+			Category:    req.Category,
+			Title:       strings.TrimSpace(req.Title),
+			Description: req.Description,
+			Link:        req.Link,
+			Hours:       req.Hours,
+			OccurredAt:  req.OccurredAt,
+			RecordedBy:  me,
+			RecordedAt:  time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+	if err := contribution.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+	if status, err := h.checkRecord(ctx, me, req.AID); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
+	if communitySpaceID == "" {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "community space not configured"})
+		return
+	}
+	if _, err := writeObject(ctx, h.spaceManager, communitySpaceID, contribution.ID, "Contribution", contribution.Contribution); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	fmt.Printf("[Contributions] %s recorded %s for %s (%s)\n", me, contribution.ID, req.AID, req.Category)
+	writeJSON(w, http.StatusCreated, contribution)
+}
+
+// handleContribution routes /api/v1/contributions/{id} and
+// /api/v1/contributions/summary[/{aid}]
+func (h *ContributionsHandler) handleContribution(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/contributions/")
+	parts := strings.Split(path, "/")
+	switch {
+	case parts[0] == "summary" && len(parts) == 1:
+		h.handleSummaries(w, r)
+	case parts[0] == "summary" && len(parts) == 2 && parts[1] != "":
+		h.handleSummary(w, r, parts[1])
+	case parts[0] != "" && len(parts) == 1:
+		h.handleGet(w, r, parts[0])
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
+
+// handleGet handles GET /api/v1/contributions/{id}
+func (h *ContributionsHandler) handleGet(w http.ResponseWriter, r *http.Request, id string) {
+	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
+	if communitySpaceID == "" {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "community space not configured"})
+		return
+	}
+
+	obj, err := h.spaceManager.ObjectTreeManager().ReadLatestByID(r.Context(), communitySpaceID, id)
+	if err != nil || obj.Type != "Contribution" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "contribution not found"})
+		return
+	}
+
+	contribution := &ContributionView{ID: obj.ID}
+	if err := json.Unmarshal(obj.Data, &contribution.Contribution); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("invalid contribution: %v", err),
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, contribution)
+}
+
+// handleSummaries handles GET /api/v1/contributions/summary
+func (h *ContributionsHandler) handleSummaries(w http.ResponseWriter, r *http.Request) {
+	contributions, err := h.readContributions(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read contributions: %v", err),
+		})
+		return
+	}
+
+	summaries := make([]*ContributionSummary, 0)
+	for _, s := range summarizeContributions(contributions) {
+		summaries = append(summaries, s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Total != summaries[j].Total {
+			return summaries[i].Total > summaries[j].Total
+		}
+		return summaries[i].AID < summaries[j].AID
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"summaries": summaries,
+		"count":     len(summaries),
+	})
+}
+
+// handleSummary handles GET /api/v1/contributions/summary/{aid}
+func (h *ContributionsHandler) handleSummary(w http.ResponseWriter, r *http.Request, aid string) {
+	summary, err := h.Summary(r.Context(), aid)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read contributions: %v", err),
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, summary)
+}
+
+// Summary returns a member's contribution summary. Members with no recorded
+// contributions get an empty summary.
+func (h *ContributionsHandler) Summary(ctx context.Context, aid string) (*ContributionSummary, error) {
+	contributions, err := h.readContributions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := summarizeContributions(contributions)[aid]; ok {
+		return s, nil
+	}
+	return &ContributionSummary{AID: aid, ByCategory: map[string]int{}}, nil
+}
+
+// ContributionCounts returns the number of recorded contributions per AID.
+// Implements ContributionCountSource for trust scoring.
+func (h *ContributionsHandler) ContributionCounts(ctx context.Context) (map[string]int, error) {
+	contributions, err := h.readContributions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, c := range contributions {
+		counts[c.AID]++
+	}
+	return counts, nil
+}
+
+// checkRecord verifies the recorder is a steward and the recipient is a
+// credentialed community member
+func (h *ContributionsHandler) checkRecord(ctx context.Context, recorder, recipient string) (int, error) {
+	if h.trust == nil {
+		return http.StatusOK, nil
+	}
+
+	graph, err := h.trust.buildGraph(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to build trust graph: %v", err)
+	}
+
+	steward := false
+	if node := graph.GetNode(recorder); node != nil {
+		for _, p := range keri.GetPermissionsForRole(node.Role) {
+			if p == "admin" {
+				steward = true
+				break
+			}
+		}
+	}
+	if !steward {
+		return http.StatusForbidden, fmt.Errorf("only stewards can record contributions")
+	}
+
+	node := graph.GetNode(recipient)
+	if node == nil || !node.IsMember() {
+		return http.StatusBadRequest, fmt.Errorf("%s is not a community member", recipient)
+	}
+	return http.StatusOK, nil
+}
+
+// readContributions returns the latest version of every contribution
+func (h *ContributionsHandler) readContributions(ctx context.Context) ([]*ContributionView, error) {
+	if h.spaceManager == nil {
+		return nil, nil
+	}
+	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
+	if communitySpaceID == "" {
+		return nil, nil
+	}
+	objects, err := readLatestObjects(ctx, h.spaceManager, communitySpaceID, "Contribution")
+	if err != nil {
+		return nil, err
+	}
+	contributions := make([]*ContributionView, 0, len(objects))
+	for _, obj := range objects {
+		c := &ContributionView{ID: obj.ID}
+		if err := json.Unmarshal(obj.Data, &c.Contribution); err != nil {
+			continue
+		}
+		contributions = append(contributions, c)
+	}
+	return contributions, nil
+}
+
+// RegisterRoutes registers contribution routes on the mux
+func (h *ContributionsHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/contributions", h.handleContributions)
+	mux.HandleFunc("/api/v1/contributions/", h.handleContribution)
+}
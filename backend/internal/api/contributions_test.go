@@ -0,0 +1,83 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+func TestContributionRecord_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		aid        string
+		body       string
+		wantStatus int
+	}{
+		{"no identity", "", `{"aid":"EBOB","category":"code","title":"Fix sync"}`, http.StatusBadRequest},
+		{"missing member", "EALICE", `{"category":"code","title":"Fix sync"}`, http.StatusBadRequest},
+		{"unknown category", "EALICE", `{"aid":"EBOB","category":"vibes","title":"Fix sync"}`, http.StatusBadRequest},
+		{"missing title", "EALICE", `{"aid":"EBOB","category":"code","title":" "}`, http.StatusBadRequest},
+		{"negative hours", "EALICE", `{"aid":"EBOB","category":"content","title":"Blog post","hours":-2}`, http.StatusBadRequest},
+		{"bad date", "EALICE", `{"aid":"EBOB","category":"facilitation","title":"Hui","occurredAt":"last week"}`, http.StatusBadRequest},
+		{"invalid json", "EALICE", `{`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "contributions_test")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			userIdentity := identity.New(tmpDir)
+			if tt.aid != "" {
+				if err := userIdentity.SetIdentity(tt.aid, secret.NewMnemonic("test mnemonic")); err != nil {
+					t.Fatalf("SetIdentity failed: %v", err)
+				}
+			}
+			handler := NewContributionsHandler(nil, userIdentity, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/contributions", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			handler.handleContributions(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSummarizeContributions(t *testing.T) {
+	contributions := []*ContributionView{
+		{ID: "C1", Contribution: Contribution{AID: "EALICE", Category: ContributionCode, Hours: 3, OccurredAt: "2026-09-01T00:00:00Z"}},
+		{ID: "C2", Contribution: Contribution{AID: "EALICE", Category: ContributionCode, Hours: 1.5, RecordedAt: "2026-10-02T00:00:00Z"}},
+		{ID: "C3", Contribution: Contribution{AID: "EALICE", Category: ContributionFacilitation, OccurredAt: "2026-08-01T00:00:00Z"}},
+		{ID: "C4", Contribution: Contribution{AID: "EBOB", Category: ContributionContent}},
+	}
+
+	summaries := summarizeContributions(contributions)
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 summaries, got %d", len(summaries))
+	}
+
+	alice := summaries["EALICE"]
+	if alice.Total != 3 {
+		t.Errorf("expected 3 contributions, got %d", alice.Total)
+	}
+	if alice.ByCategory[ContributionCode] != 2 || alice.ByCategory[ContributionFacilitation] != 1 {
+		t.Errorf("unexpected category counts: %v", alice.ByCategory)
+	}
+	if alice.Hours != 4.5 {
+		t.Errorf("expected 4.5 hours, got %f", alice.Hours)
+	}
+	if alice.LastContributionAt != "2026-10-02T00:00:00Z" {
+		t.Errorf("expected latest contribution 2026-10-02, got %s", alice.LastContributionAt)
+	}
+}
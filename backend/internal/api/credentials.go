@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
 	"github.com/matou-dao/backend/internal/keri"
+	"github.com/matou-dao/backend/internal/trust"
 )
 
 // CredentialsHandler handles credential-related HTTP requests.
@@ -18,6 +21,9 @@ import (
 type CredentialsHandler struct {
 	keriClient *keri.Client
 	store      *anystore.LocalStore
+	receipts   ReceiptRecorder
+	events     *EventBroker
+	schemas    *SchemasHandler
 }
 
 // NewCredentialsHandler creates a new credentials handler
@@ -28,6 +34,24 @@ func NewCredentialsHandler(keriClient *keri.Client, store *anystore.LocalStore)
 	}
 }
 
+// SetReceipts records an issuance receipt whenever an org-issued credential
+// is stored on a steward's backend
+func (h *CredentialsHandler) SetReceipts(receipts ReceiptRecorder) {
+	h.receipts = receipts
+}
+
+// SetEvents broadcasts credential:stored (and endorsement:synced) events
+// as credentials are stored
+func (h *CredentialsHandler) SetEvents(events *EventBroker) {
+	h.events = events
+}
+
+// SetSchemas validates credential data against the schema registry as
+// credentials are stored or validated
+func (h *CredentialsHandler) SetSchemas(schemas *SchemasHandler) {
+	h.schemas = schemas
+}
+
 // StoreRequest represents a credential storage request from frontend
 type StoreRequest struct {
 	Credential keri.Credential `json:"credential"`
@@ -42,7 +66,7 @@ type StoreResponse struct {
 
 // ValidateRequest represents a credential validation request
 type ValidateRequest struct {
-	Credential json.RawMessage `json:"credential"`
+	Credential json.RawMessage `json:"credential" validate:"required"`
 }
 
 // ValidateResponse represents a credential validation response
@@ -72,8 +96,9 @@ type RolesResponse struct {
 
 // RoleInfo describes a role and its permissions
 type RoleInfo struct {
-	Name        string   `json:"name"`
-	Permissions []string `json:"permissions"`
+	Name        string                   `json:"name"`
+	Permissions []string                 `json:"permissions"`
+	Attributes  []keri.AttributeTemplate `json:"attributes,omitempty"`
 }
 
 // HandleStore handles POST /api/v1/credentials - Store a credential from frontend
@@ -86,26 +111,33 @@ func (h *CredentialsHandler) HandleStore(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
 	var req StoreRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, StoreResponse{
-			Success: false,
-			Error:   fmt.Sprintf("invalid request: %v", err),
-		})
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeDecodeProblem(w, err)
 		return
 	}
 
 	// Validate credential structure
 	if err := h.keriClient.ValidateCredential(&req.Credential); err != nil {
-		writeJSON(w, http.StatusBadRequest, StoreResponse{
-			Success: false,
-			Error:   fmt.Sprintf("invalid credential: %v", err),
-		})
+		writeProblem(w, http.StatusBadRequest, "", FieldError{Field: "credential", Message: err.Error()})
 		return
 	}
 
-	// Store in anystore
+	// Validate data against the registered schema
 	ctx := context.Background()
+	var raw ValidateRequest
+	json.Unmarshal(body, &raw)
+	if err := h.schemas.ValidateCredentialData(ctx, req.Credential.Schema, credentialDataJSON(raw.Credential)); err != nil {
+		writeProblem(w, http.StatusBadRequest, "", FieldError{Field: "credential.data", Message: err.Error()})
+		return
+	}
+
+	// Store in anystore
 	cachedCred := &anystore.CachedCredential{
 		ID:         req.Credential.SAID,
 		IssuerAID:  req.Credential.Issuer,
@@ -123,6 +155,20 @@ func (h *CredentialsHandler) HandleStore(w http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
+	for _, event := range CredentialStoredEvents(cachedCred) {
+		h.events.Broadcast(event)
+	}
+
+	if h.receipts != nil && h.receipts.Available() && cachedCred.Verified {
+		cred := req.Credential
+		if _, _, err := h.receipts.Record(ctx, anysync.ReceiptIssued, cred.SAID, cred.Schema, cred.Recipient); err != nil {
+			fmt.Printf("[Credentials] Warning: failed to record receipt for %s: %v\n", cred.SAID, err)
+		}
+	}
+
+	token := requestConsistencyToken(r)
+	token.AddCredentials(req.Credential.SAID)
+	setConsistencyToken(w, token)
 
 	writeJSON(w, http.StatusOK, StoreResponse{
 		Success: true,
@@ -191,25 +237,20 @@ func (h *CredentialsHandler) HandleValidate(w http.ResponseWriter, r *http.Reque
 	}
 
 	var req ValidateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, ValidateResponse{
-			Valid: false,
-			Error: fmt.Sprintf("invalid request: %v", err),
-		})
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
-	if len(req.Credential) == 0 {
-		writeJSON(w, http.StatusBadRequest, ValidateResponse{
+	// Validate credential structure
+	cred, err := h.keriClient.ValidateCredentialJSON(string(req.Credential))
+	if err != nil {
+		writeJSON(w, http.StatusOK, ValidateResponse{
 			Valid: false,
-			Error: "credential is required",
+			Error: err.Error(),
 		})
 		return
 	}
-
-	// Validate credential structure
-	cred, err := h.keriClient.ValidateCredentialJSON(string(req.Credential))
-	if err != nil {
+	if err := h.schemas.ValidateCredentialData(r.Context(), cred.Schema, credentialDataJSON(req.Credential)); err != nil {
 		writeJSON(w, http.StatusOK, ValidateResponse{
 			Valid: false,
 			Error: err.Error(),
@@ -224,6 +265,61 @@ func (h *CredentialsHandler) HandleValidate(w http.ResponseWriter, r *http.Reque
 	})
 }
 
+// HandleVerify handles POST /api/v1/credentials/verify - Verify a
+// credential against its issuer's KEL, the schema registry and the
+// revocations in the receipt ledger
+func (h *CredentialsHandler) HandleVerify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	var req ValidateRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	var cred keri.Credential
+	if err := json.Unmarshal(req.Credential, &cred); err != nil {
+		writeProblem(w, http.StatusBadRequest, "", FieldError{Field: "credential", Message: fmt.Sprintf("is not a credential: %v", err)})
+		return
+	}
+
+	opts := keri.VerifyOptions{Data: credentialDataJSON(req.Credential)}
+	if h.schemas != nil {
+		opts.Schemas = h.schemas
+	}
+	if h.receipts != nil && h.receipts.Available() {
+		opts.Revocations = receiptRevocations{h.receipts}
+	}
+	writeJSON(w, http.StatusOK, h.keriClient.VerifyCredential(r.Context(), &cred, opts))
+}
+
+// receiptRevocations reads revocations from the receipt ledger, where
+// stewards record them after revoking in KERIA
+type receiptRevocations struct {
+	receipts ReceiptRecorder
+}
+
+func (r receiptRevocations) CredentialRevoked(ctx context.Context, said string) (bool, error) {
+	delivery, err := r.receipts.DeliveryStatus(ctx, said)
+	if err != nil {
+		return false, err
+	}
+	return delivery != nil && delivery.Revoked, nil
+}
+
+// credentialDataJSON extracts the data block of a credential as submitted,
+// so schema validation sees the caller's fields rather than a re-encoding
+func credentialDataJSON(cred json.RawMessage) json.RawMessage {
+	var c struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(cred, &c); err != nil {
+		return nil
+	}
+	return c.Data
+}
+
 // HandleRoles handles GET /api/v1/credentials/roles - List available roles
 func (h *CredentialsHandler) HandleRoles(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -235,15 +331,72 @@ func (h *CredentialsHandler) HandleRoles(w http.ResponseWriter, r *http.Request)
 
 	roles := make([]RoleInfo, 0, len(keri.ValidRoles()))
 	for _, role := range keri.ValidRoles() {
-		roles = append(roles, RoleInfo{
+		info := RoleInfo{
 			Name:        role,
 			Permissions: keri.GetPermissionsForRole(role),
-		})
+		}
+		if tmpl := h.keriClient.GetRoleTemplate(role); tmpl != nil {
+			info.Attributes = tmpl.Attributes
+		}
+		roles = append(roles, info)
 	}
 
 	writeJSON(w, http.StatusOK, RolesResponse{Roles: roles})
 }
 
+// ParticipationRequest is the body for POST /api/v1/credentials/participation
+type ParticipationRequest struct {
+	Recipient   string `json:"recipient" validate:"required"`
+	Kind        string `json:"kind" validate:"required"` // attendance or contribution
+	Description string `json:"description" validate:"required"`
+	Evidence    string `json:"evidence,omitempty"`                      // Link or reference to what was verified
+	OccurredAt  string `json:"occurredAt,omitempty" validate:"rfc3339"` // RFC3339
+}
+
+// Validate checks the kind against the participation kinds
+func (p *ParticipationRequest) Validate() error {
+	if !keri.IsParticipationKind(p.Kind) {
+		return ValidationErrors{{Field: "kind", Message: "must be one of: " + strings.Join(keri.ParticipationKinds(), ", ")}}
+	}
+	return nil
+}
+
+// HandleParticipation handles POST /api/v1/credentials/participation.
+// Returns a pre-filled, org-issued participation credential for verified
+// attendance or contribution; the steward's client issues it via KERIA.
+func (h *CredentialsHandler) HandleParticipation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	var req ParticipationRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	data := map[string]interface{}{
+		"kind":        req.Kind,
+		"description": req.Description,
+		"verifiedAt":  time.Now().UTC().Format(time.RFC3339),
+	}
+	if req.Evidence != "" {
+		data["evidence"] = req.Evidence
+	}
+	if req.OccurredAt != "" {
+		data["occurredAt"] = req.OccurredAt
+	}
+
+	writeJSON(w, http.StatusOK, CredentialIssuance{
+		Schema:    keri.ParticipationSchema,
+		Issuer:    h.keriClient.GetOrgAID(),
+		Recipient: req.Recipient,
+		Data:      data,
+	})
+}
+
 // HandleOrg handles GET /api/v1/org - Get organization info for frontend
 func (h *CredentialsHandler) HandleOrg(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -265,7 +418,9 @@ func (h *CredentialsHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/v1/credentials", h.handleCredentials)
 	mux.HandleFunc("/api/v1/credentials/", h.handleCredentialByID)
 	mux.HandleFunc("/api/v1/credentials/validate", h.HandleValidate)
+	mux.HandleFunc("/api/v1/credentials/verify", h.HandleVerify)
 	mux.HandleFunc("/api/v1/credentials/roles", h.HandleRoles)
+	mux.HandleFunc("/api/v1/credentials/participation", h.HandleParticipation)
 }
 
 // handleCredentials routes to Store (POST) or List (GET)
@@ -282,19 +437,63 @@ func (h *CredentialsHandler) handleCredentials(w http.ResponseWriter, r *http.Re
 	}
 }
 
-// handleCredentialByID routes to Get by SAID
+// handleCredentialByID routes to Get by SAID, or to the credential's
+// delivery status
 func (h *CredentialsHandler) handleCredentialByID(w http.ResponseWriter, r *http.Request) {
 	// Check if it's a sub-route like /validate or /roles
 	path := r.URL.Path
 	if strings.HasSuffix(path, "/validate") || strings.HasSuffix(path, "/roles") {
 		return // Let specific handlers handle these
 	}
+	if strings.HasSuffix(path, "/status") {
+		h.HandleStatus(w, r)
+		return
+	}
 	h.HandleGet(w, r)
 }
 
+// HandleStatus handles GET /api/v1/credentials/{said}/status (steward)
+// Returns the credential's delivery lifecycle from the receipt ledger:
+// issued → delivered → acknowledged → cached by the recipient.
+func (h *CredentialsHandler) HandleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if !isAdminCaller(r) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "admin required"})
+		return
+	}
+
+	said := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/credentials/"), "/status")
+	if said == "" || strings.Contains(said, "/") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "credential SAID required"})
+		return
+	}
+	if h.receipts == nil || !h.receipts.Available() {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "admin space not available"})
+		return
+	}
+
+	delivery, err := h.receipts.DeliveryStatus(r.Context(), said)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if delivery == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "no receipts for credential"})
+		return
+	}
+	writeJSON(w, http.StatusOK, delivery)
+}
+
 // handleList handles GET /api/v1/credentials - List all credentials
+// Query params:
+//   - includeExpired: "true" to list expired credentials too
 func (h *CredentialsHandler) handleList(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
+	includeExpired := r.URL.Query().Get("includeExpired") == "true"
+	now := time.Now()
 
 	// Query all credentials from anystore cache
 	cachedCreds, err := h.store.GetAllCredentials(ctx)
@@ -308,6 +507,10 @@ func (h *CredentialsHandler) handleList(w http.ResponseWriter, r *http.Request)
 	// Convert cached credentials to keri.Credential format
 	credentials := make([]keri.Credential, 0, len(cachedCreds))
 	for _, cached := range cachedCreds {
+		if !includeExpired && trust.CredentialExpired(cached, now) {
+			continue
+		}
+
 		// Try to convert data to CredentialData
 		var data keri.CredentialData
 		dataBytes, _ := json.Marshal(cached.Data)
@@ -2,13 +2,16 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
 	"github.com/matou-dao/backend/internal/keri"
 )
 
@@ -328,6 +331,36 @@ func TestHandleList(t *testing.T) {
 	}
 }
 
+func TestHandleList_Expired(t *testing.T) {
+	handler, cleanup := setupTestHandler(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	expired := time.Now().Add(-time.Hour)
+	for _, cred := range []*anystore.CachedCredential{
+		{ID: "ESAID001", IssuerAID: "EORG", SubjectAID: "EUSER1", SchemaID: "EMatouMembershipSchemaV1"},
+		{ID: "ESAID002", IssuerAID: "EORG", SubjectAID: "EUSER2", SchemaID: "EMatouMembershipSchemaV1",
+			ExpiredAt: &expired},
+	} {
+		if err := handler.store.StoreCredential(ctx, cred); err != nil {
+			t.Fatalf("failed to store credential: %v", err)
+		}
+	}
+
+	for query, want := range map[string]int{"": 1, "?includeExpired=true": 2} {
+		w := httptest.NewRecorder()
+		handler.handleList(w, httptest.NewRequest(http.MethodGet, "/api/v1/credentials"+query, nil))
+
+		var resp ListResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.Total != want {
+			t.Errorf("expected %d credentials for %q, got %d", want, query, resp.Total)
+		}
+	}
+}
+
 func TestRegisterRoutes(t *testing.T) {
 	handler, cleanup := setupTestHandler(t)
 	defer cleanup()
@@ -344,6 +377,7 @@ func TestRegisterRoutes(t *testing.T) {
 		{http.MethodGet, "/api/v1/credentials/roles"},
 		{http.MethodPost, "/api/v1/credentials"},
 		{http.MethodPost, "/api/v1/credentials/validate"},
+		{http.MethodPost, "/api/v1/credentials/verify"},
 	}
 
 	for _, p := range paths {
@@ -365,3 +399,120 @@ func TestRegisterRoutes(t *testing.T) {
 		})
 	}
 }
+
+// fakeReceipts serves delivery status from an in-memory receipt list
+type fakeReceipts struct {
+	receipts []*anysync.ReceiptPayload
+}
+
+func (f *fakeReceipts) Available() bool { return true }
+
+func (f *fakeReceipts) Record(ctx context.Context, action, said, schema, recipient string) (*anysync.ReceiptPayload, bool, error) {
+	r := &anysync.ReceiptPayload{Action: action, SAID: said, Schema: schema, Recipient: recipient}
+	f.receipts = append(f.receipts, r)
+	return r, true, nil
+}
+
+func (f *fakeReceipts) DeliveryStatus(ctx context.Context, said string) (*anysync.CredentialDelivery, error) {
+	return anysync.DeliveryFromReceipts(said, f.receipts), nil
+}
+
+func TestHandleStatus(t *testing.T) {
+	handler, cleanup := setupTestHandler(t)
+	defer cleanup()
+
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	// Without the admin space there is no ledger to read
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/credentials/ESAID001/status", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status %d without receipts, got %d", http.StatusForbidden, w.Code)
+	}
+
+	receipts := &fakeReceipts{}
+	receipts.Record(context.Background(), anysync.ReceiptIssued, "ESAID001", "EMatouMembershipSchemaV1", "EBOB")
+	receipts.Record(context.Background(), anysync.ReceiptDelivered, "ESAID001", "EMatouMembershipSchemaV1", "EBOB")
+	handler.SetReceipts(receipts)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/credentials/ESAID001/status", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var delivery anysync.CredentialDelivery
+	if err := json.NewDecoder(w.Body).Decode(&delivery); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if delivery.Status != anysync.ReceiptDelivered || delivery.Recipient != "EBOB" {
+		t.Errorf("unexpected delivery status: %+v", delivery)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/credentials/EUNKNOWN/status", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for unknown credential, got %d", http.StatusNotFound, w.Code)
+	}
+
+	// Delivery status is for stewards; a member's token is refused
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/credentials/ESAID001/status", nil)
+	req = req.WithContext(context.WithValue(req.Context(), principalKey{}, &Principal{Kind: "token", AID: "EBOB"}))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status %d for a non-admin caller, got %d", http.StatusForbidden, w.Code)
+	}
+}
+
+func TestHandleVerify(t *testing.T) {
+	handler, cleanup := setupTestHandler(t)
+	defer cleanup()
+
+	receipts := &fakeReceipts{}
+	receipts.Record(context.Background(), anysync.ReceiptRevoked, "ESAID123", "EMatouMembershipSchemaV1", "ERECIPIENT123")
+	handler.SetReceipts(receipts)
+
+	// No KERIA is configured, so the issuer's key state can't be read
+	body := `{
+		"credential": {
+			"said": "ESAID123",
+			"issuer": "EAID123456789",
+			"recipient": "ERECIPIENT123",
+			"schema": "EMatouMembershipSchemaV1",
+			"data": {"role": "Member"}
+		}
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/credentials/verify", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	handler.HandleVerify(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp keri.Verification
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Verified || !resp.OrgIssued {
+		t.Errorf("expected unverified org-issued credential, got %+v", resp)
+	}
+	want := map[string]string{
+		keri.CheckStructure:  keri.CheckPassed,
+		keri.CheckSchema:     keri.CheckSkipped,
+		keri.CheckKeyState:   keri.CheckFailed,
+		keri.CheckSignature:  keri.CheckSkipped,
+		keri.CheckRevocation: keri.CheckFailed,
+	}
+	for name, outcome := range want {
+		if check := resp.Check(name); check == nil || check.Outcome != outcome {
+			t.Errorf("expected %s %s, got %+v", name, outcome, check)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleVerify(w, httptest.NewRequest(http.MethodPost, "/api/v1/credentials/verify", bytes.NewBufferString(`{}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d without a credential, got %d", http.StatusBadRequest, w.Code)
+	}
+}
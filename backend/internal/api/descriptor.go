@@ -0,0 +1,185 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/keri"
+	"github.com/matou-dao/backend/internal/trust"
+)
+
+// maxDescriptorSize caps how much of a remote descriptor is read
+const maxDescriptorSize = 64 << 10
+
+// DescriptorHandler publishes this community's signed descriptor at
+// /.well-known/matou.json and reads other communities' descriptors so
+// stewards can federate with them.
+type DescriptorHandler struct {
+	orgConfig    *OrgConfigHandler
+	spaceManager *anysync.SpaceManager
+	httpClient   *http.Client
+	now          func() time.Time
+}
+
+// NewDescriptorHandler creates a new descriptor handler
+func NewDescriptorHandler(orgConfig *OrgConfigHandler, spaceManager *anysync.SpaceManager) *DescriptorHandler {
+	return &DescriptorHandler{
+		orgConfig:    orgConfig,
+		spaceManager: spaceManager,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		now:          time.Now,
+	}
+}
+
+// DiscoverRequest is the body for POST /api/v1/trust/federation/discover
+type DiscoverRequest struct {
+	URL string `json:"url"` // Community base URL or full descriptor URL
+}
+
+// DiscoverResponse is the response for POST /api/v1/trust/federation/discover
+type DiscoverResponse struct {
+	Descriptor *trust.CommunityDescriptor `json:"descriptor"`
+	Peer       trust.FederatedOrg         `json:"peer"`       // Ready to add to the org config's federation
+	Configured bool                       `json:"configured"` // Already a federated peer
+}
+
+// Descriptor builds and signs this community's descriptor. Returns an error
+// if publishing isn't enabled or the org isn't configured.
+func (h *DescriptorHandler) Descriptor() (*trust.CommunityDescriptor, error) {
+	config := h.orgConfig.GetConfig()
+	if config == nil || config.Publish == nil || !config.Publish.Enabled {
+		return nil, fmt.Errorf("community descriptor is not published")
+	}
+	if h.spaceManager == nil || h.spaceManager.GetClient() == nil {
+		return nil, fmt.Errorf("any-sync client not available")
+	}
+
+	d := &trust.CommunityDescriptor{
+		OrgAID:  config.Organization.AID,
+		Name:    config.Organization.Name,
+		OOBI:    config.Organization.OOBI,
+		Schemas: keri.IssuedSchemas(),
+		JoinURL: config.Publish.JoinURL,
+	}
+	if err := d.Sign(h.spaceManager.GetClient().GetSigningKey(), h.now()); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// HandleWellKnown handles GET /.well-known/matou.json
+func (h *DescriptorHandler) HandleWellKnown(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	d, err := h.Descriptor()
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+
+	// The descriptor is public; any site may read it
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	writeJSON(w, http.StatusOK, d)
+}
+
+// HandleDiscover handles POST /api/v1/trust/federation/discover
+// Fetches and verifies another community's descriptor. The returned peer can
+// be added to the org config's federation; the descriptor's OOBI is where
+// the frontend resolves the peer's KEL for POST /api/v1/trust/federation/kel.
+func (h *DescriptorHandler) HandleDiscover(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	var req DiscoverRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+	target, err := descriptorURL(req.URL)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	d, status, err := h.fetchDescriptor(r, target)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp := DiscoverResponse{Descriptor: d, Peer: d.FederatedOrg()}
+	for _, f := range h.orgConfig.GetFederation() {
+		if f.AID == d.OrgAID {
+			resp.Configured = true
+			break
+		}
+	}
+
+	fmt.Printf("[Federation] Discovered %s (%s) at %s\n", d.Name, d.OrgAID, target)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// fetchDescriptor downloads and verifies a remote descriptor
+func (h *DescriptorHandler) fetchDescriptor(r *http.Request, target string) (*trust.CommunityDescriptor, int, error) {
+	httpReq, err := http.NewRequestWithContext(r.Context(), http.MethodGet, target, nil)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid url: %v", err)
+	}
+	resp, err := h.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, http.StatusBadGateway, fmt.Errorf("fetching descriptor: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, http.StatusBadGateway, fmt.Errorf("fetching descriptor: status %d", resp.StatusCode)
+	}
+
+	var d trust.CommunityDescriptor
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDescriptorSize)).Decode(&d); err != nil {
+		return nil, http.StatusBadGateway, fmt.Errorf("invalid descriptor: %v", err)
+	}
+	if err := d.Verify(); err != nil {
+		return nil, http.StatusUnprocessableEntity, err
+	}
+	if config := h.orgConfig.GetConfig(); config != nil && d.OrgAID == config.Organization.AID {
+		return nil, http.StatusBadRequest, fmt.Errorf("descriptor is for this community")
+	}
+	return &d, http.StatusOK, nil
+}
+
+// descriptorURL resolves a community base URL to its well-known descriptor
+// URL. Full descriptor URLs are used as-is.
+func descriptorURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", fmt.Errorf("url must be an absolute http(s) URL")
+	}
+	if !strings.HasSuffix(u.Path, ".json") {
+		u.Path = strings.TrimSuffix(u.Path, "/") + trust.WellKnownDescriptorPath
+	}
+	u.RawQuery, u.Fragment = "", ""
+	return u.String(), nil
+}
+
+// RegisterRoutes registers descriptor routes on the mux
+func (h *DescriptorHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc(trust.WellKnownDescriptorPath, h.HandleWellKnown)
+	mux.HandleFunc("/api/v1/trust/federation/discover", h.HandleDiscover)
+}
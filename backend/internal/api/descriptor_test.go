@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/util/crypto"
+
+	"github.com/matou-dao/backend/internal/trust"
+)
+
+func newTestDescriptorHandler(t *testing.T, config *OrgConfigData) *DescriptorHandler {
+	t.Helper()
+	orgConfig := NewOrgConfigHandler(t.TempDir(), nil)
+	orgConfig.cache = config
+	return NewDescriptorHandler(orgConfig, nil)
+}
+
+func TestDescriptorURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"https://peer.example", "https://peer.example/.well-known/matou.json"},
+		{"https://peer.example/", "https://peer.example/.well-known/matou.json"},
+		{"https://peer.example/community?x=1", "https://peer.example/community/.well-known/matou.json"},
+		{"https://peer.example/custom/matou.json", "https://peer.example/custom/matou.json"},
+	}
+	for _, tt := range tests {
+		got, err := descriptorURL(tt.in)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "peer.example", "ftp://peer.example", "file:///etc/passwd"} {
+		if _, err := descriptorURL(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+func TestHandleWellKnown_NotPublished(t *testing.T) {
+	h := newTestDescriptorHandler(t, &OrgConfigData{
+		Organization: OrgInfo{AID: "EORG123", Name: "Matou"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/matou.json", nil)
+	w := httptest.NewRecorder()
+	h.HandleWellKnown(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 when publishing is disabled, got %d", w.Code)
+	}
+}
+
+func TestHandleDiscover(t *testing.T) {
+	key, _, _ := crypto.GenerateRandomEd25519KeyPair()
+	peer := &trust.CommunityDescriptor{
+		OrgAID:  "EPEER1",
+		Name:    "Peer One",
+		Schemas: []string{"EMatouMembershipSchemaV1"},
+	}
+	if err := peer.Sign(key, time.Now()); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	tampered := *peer
+	tampered.Name = "Someone Else"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/matou.json", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, peer)
+	})
+	mux.HandleFunc("/tampered/.well-known/matou.json", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, tampered)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	h := newTestDescriptorHandler(t, &OrgConfigData{
+		Organization: OrgInfo{AID: "EORG123", Name: "Matou"},
+		Federation:   []trust.FederatedOrg{{AID: "EPEER1", Schemas: []string{"EMatouMembershipSchemaV1"}}},
+	})
+
+	discover := func(url string) *httptest.ResponseRecorder {
+		body := `{"url":"` + url + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/trust/federation/discover", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		h.HandleDiscover(w, req)
+		return w
+	}
+
+	w := discover(server.URL)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp DiscoverResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Peer.AID != "EPEER1" || resp.Peer.Name != "Peer One" {
+		t.Errorf("unexpected peer: %+v", resp.Peer)
+	}
+	if !resp.Configured {
+		t.Error("expected peer to be reported as configured")
+	}
+
+	if w := discover(server.URL + "/tampered"); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 for tampered descriptor, got %d", w.Code)
+	}
+	if w := discover(server.URL + "/missing"); w.Code != http.StatusBadGateway {
+		t.Errorf("expected 502 for missing descriptor, got %d", w.Code)
+	}
+}
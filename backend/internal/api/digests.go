@@ -0,0 +1,501 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/email"
+	"github.com/matou-dao/backend/internal/identity"
+)
+
+// Digest defaults, in the member's local time
+const (
+	defaultDigestDay  = time.Monday
+	defaultDigestHour = 8
+)
+
+// digestGrace is how late a digest may still go out. A backend offline for
+// longer skips that week rather than sending a stale digest.
+const digestGrace = 24 * time.Hour
+
+// DigestPreferences are the digest settings kept under appPreferences.digest
+// in the member's PrivateProfile
+type DigestPreferences struct {
+	Enabled bool `json:"enabled"`
+	// Email defaults to the member's public email
+	Email string `json:"email,omitempty"`
+	// Day is the weekday the digest is sent, e.g. "monday"
+	Day string `json:"day,omitempty"`
+	// Hour is the local hour the digest is sent, 0-23
+	Hour *int `json:"hour,omitempty"`
+}
+
+// DigestSchedule is when a member's digest is sent, resolved from their
+// preferences
+type DigestSchedule struct {
+	Enabled  bool   `json:"enabled"`
+	Email    string `json:"email,omitempty"`
+	Timezone string `json:"timezone"`
+	Day      string `json:"day"`
+	Hour     int    `json:"hour"`
+
+	weekday  time.Weekday
+	location *time.Location
+}
+
+// parseDigestSchedule resolves the digest schedule from a PrivateProfile's
+// appPreferences. Timezone is an IANA name and defaults to UTC.
+func parseDigestSchedule(appPreferences json.RawMessage, fallbackEmail string) (*DigestSchedule, error) {
+	var prefs struct {
+		Timezone string             `json:"timezone"`
+		Digest   *DigestPreferences `json:"digest"`
+	}
+	if len(appPreferences) > 0 {
+		if err := json.Unmarshal(appPreferences, &prefs); err != nil {
+			return nil, fmt.Errorf("invalid app preferences: %v", err)
+		}
+	}
+
+	schedule := &DigestSchedule{
+		Timezone: "UTC",
+		Email:    fallbackEmail,
+		Hour:     defaultDigestHour,
+		weekday:  defaultDigestDay,
+		location: time.UTC,
+	}
+	if prefs.Timezone != "" {
+		loc, err := time.LoadLocation(prefs.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown timezone %q", prefs.Timezone)
+		}
+		schedule.Timezone = prefs.Timezone
+		schedule.location = loc
+	}
+	if d := prefs.Digest; d != nil {
+		schedule.Enabled = d.Enabled
+		if d.Email != "" {
+			schedule.Email = d.Email
+		}
+		if d.Day != "" {
+			weekday, ok := parseWeekday(d.Day)
+			if !ok {
+				return nil, fmt.Errorf("unknown digest day %q", d.Day)
+			}
+			schedule.weekday = weekday
+		}
+		if d.Hour != nil {
+			if *d.Hour < 0 || *d.Hour > 23 {
+				return nil, fmt.Errorf("digest hour must be 0-23")
+			}
+			schedule.Hour = *d.Hour
+		}
+	}
+	schedule.Day = strings.ToLower(schedule.weekday.String())
+	return schedule, nil
+}
+
+// parseWeekday parses a weekday name, e.g. "monday" or "Mon"
+func parseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(name)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// latestSlot returns the most recent scheduled send time at or before now.
+// Slots are wall-clock times in the member's timezone, so they follow
+// daylight saving changes.
+func (s *DigestSchedule) latestSlot(now time.Time) time.Time {
+	local := now.In(s.location)
+	back := (int(local.Weekday()) - int(s.weekday) + 7) % 7
+	slot := time.Date(local.Year(), local.Month(), local.Day()-back, s.Hour, 0, 0, 0, s.location)
+	if slot.After(now) {
+		slot = slot.AddDate(0, 0, -7)
+	}
+	return slot
+}
+
+// nextSlot returns the next scheduled send time after now
+func (s *DigestSchedule) nextSlot(now time.Time) time.Time {
+	return s.latestSlot(now).AddDate(0, 0, 7)
+}
+
+// digestState is what the local store remembers between digests
+type digestState struct {
+	LastSlot   time.Time  `json:"lastSlot"`
+	LastSentAt *time.Time `json:"lastSentAt,omitempty"`
+}
+
+// DigestEndorsementRequest is a pending endorsement request in a digest
+type DigestEndorsementRequest struct {
+	ID            string `json:"id"`
+	RequesterAID  string `json:"requesterAid"`
+	RequesterName string `json:"requesterName,omitempty"`
+	Category      string `json:"category"`
+	Message       string `json:"message,omitempty"`
+	CreatedAt     string `json:"createdAt"`
+}
+
+// Digest is a member's weekly activity summary
+type Digest struct {
+	AID                  string                     `json:"aid"`
+	Name                 string                     `json:"name,omitempty"`
+	PeriodStart          time.Time                  `json:"periodStart"`
+	PeriodEnd            time.Time                  `json:"periodEnd"`
+	NewMembers           []string                   `json:"newMembers"`
+	EndorsementsReceived int                        `json:"endorsementsReceived"`
+	PendingRequests      []DigestEndorsementRequest `json:"pendingRequests"`
+}
+
+// empty reports whether the digest has nothing to tell
+func (d *Digest) empty() bool {
+	return len(d.NewMembers) == 0 && d.EndorsementsReceived == 0 && len(d.PendingRequests) == 0
+}
+
+// DigestHandler builds and emails the local member's weekly digest, at the
+// time their preferences set in their own timezone.
+type DigestHandler struct {
+	spaceManager *anysync.SpaceManager
+	userIdentity *identity.UserIdentity
+	endorsements *EndorsementsHandler
+	store        anystore.RecordStore
+	sender       *email.Sender
+	now          func() time.Time
+
+	// mu serialises sends so a scheduled and a manual send can't overlap
+	mu sync.Mutex
+}
+
+// NewDigestHandler creates a new digest handler
+func NewDigestHandler(
+	spaceManager *anysync.SpaceManager,
+	userIdentity *identity.UserIdentity,
+	endorsements *EndorsementsHandler,
+	store anystore.RecordStore,
+	sender *email.Sender,
+) *DigestHandler {
+	return &DigestHandler{
+		spaceManager: spaceManager,
+		userIdentity: userIdentity,
+		endorsements: endorsements,
+		store:        store,
+		sender:       sender,
+		now:          time.Now,
+	}
+}
+
+// DigestStatusResponse is the response for GET /api/v1/digest
+type DigestStatusResponse struct {
+	Schedule   *DigestSchedule `json:"schedule"`
+	NextAt     *time.Time      `json:"nextAt,omitempty"`
+	LastSentAt *time.Time      `json:"lastSentAt,omitempty"`
+	// Preview is the digest so far for the coming send
+	Preview *Digest `json:"preview"`
+}
+
+// digestStateKey is the preference holding an AID's digest state
+func digestStateKey(aid string) string {
+	return "digest:" + aid
+}
+
+// communityProfile is the part of a SharedProfile a digest uses
+type communityProfile struct {
+	AID         string `json:"aid"`
+	DisplayName string `json:"displayName"`
+	PublicEmail string `json:"publicEmail"`
+	CreatedAt   string `json:"createdAt"`
+}
+
+// communityProfiles returns the community's SharedProfiles keyed by AID
+func (h *DigestHandler) communityProfiles(ctx context.Context) map[string]communityProfile {
+	profiles := make(map[string]communityProfile)
+	spaceID := h.spaceManager.GetCommunitySpaceID()
+	if spaceID == "" {
+		return profiles
+	}
+	objects, err := readLatestObjects(ctx, h.spaceManager, spaceID, "SharedProfile")
+	if err != nil {
+		return profiles
+	}
+	for _, obj := range objects {
+		var p communityProfile
+		if err := json.Unmarshal(obj.Data, &p); err == nil && p.AID != "" {
+			profiles[p.AID] = p
+		}
+	}
+	return profiles
+}
+
+// schedule reads the local member's digest schedule from their PrivateProfile
+func (h *DigestHandler) schedule(ctx context.Context, profiles map[string]communityProfile) (*DigestSchedule, error) {
+	aid := h.userIdentity.GetAID()
+	var appPreferences json.RawMessage
+	if spaceID := h.userIdentity.GetPrivateSpaceID(); spaceID != "" {
+		objects, err := readLatestObjects(ctx, h.spaceManager, spaceID, "PrivateProfile")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private profile: %v", err)
+		}
+		for _, obj := range objects {
+			var p struct {
+				AppPreferences json.RawMessage `json:"appPreferences"`
+			}
+			if json.Unmarshal(obj.Data, &p) == nil && (obj.ID == "PrivateProfile-"+aid || appPreferences == nil) {
+				appPreferences = p.AppPreferences
+			}
+		}
+	}
+	return parseDigestSchedule(appPreferences, profiles[aid].PublicEmail)
+}
+
+// loadState returns the local member's digest state
+func (h *DigestHandler) loadState(ctx context.Context, aid string) digestState {
+	var state digestState
+	if h.store == nil {
+		return state
+	}
+	value, err := h.store.GetPreference(ctx, digestStateKey(aid))
+	if err != nil {
+		return state
+	}
+	data, _ := json.Marshal(value)
+	json.Unmarshal(data, &state)
+	return state
+}
+
+// build assembles a member's digest for a period: members who joined,
+// endorsements they received and requests waiting on them
+func (h *DigestHandler) build(ctx context.Context, aid string, profiles map[string]communityProfile, start, end time.Time) (*Digest, error) {
+	digest := &Digest{
+		AID:             aid,
+		Name:            profiles[aid].DisplayName,
+		PeriodStart:     start,
+		PeriodEnd:       end,
+		NewMembers:      []string{},
+		PendingRequests: []DigestEndorsementRequest{},
+	}
+	within := func(ts string) bool {
+		t, err := time.Parse(time.RFC3339, ts)
+		return err == nil && !t.Before(start) && t.Before(end)
+	}
+
+	for _, p := range profiles {
+		if p.AID != aid && within(p.CreatedAt) {
+			digest.NewMembers = append(digest.NewMembers, p.DisplayName)
+		}
+	}
+	sort.Strings(digest.NewMembers)
+
+	requests, err := h.endorsements.readRequests(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read endorsement requests: %v", err)
+	}
+	for _, req := range requests {
+		if req.RequesterAID == aid && req.Status == EndorsementAccepted && within(req.RespondedAt) {
+			digest.EndorsementsReceived++
+		}
+	}
+
+	pending, err := h.endorsements.PendingIncoming(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read endorsement requests: %v", err)
+	}
+	for _, req := range pending {
+		digest.PendingRequests = append(digest.PendingRequests, DigestEndorsementRequest{
+			ID:            req.ID,
+			RequesterAID:  req.RequesterAID,
+			RequesterName: profiles[req.RequesterAID].DisplayName,
+			Category:      req.Category,
+			Message:       req.Message,
+			CreatedAt:     req.CreatedAt,
+		})
+	}
+	sort.Slice(digest.PendingRequests, func(i, j int) bool {
+		return digest.PendingRequests[i].CreatedAt < digest.PendingRequests[j].CreatedAt
+	})
+	return digest, nil
+}
+
+// send emails a digest and records it as sent for slot
+func (h *DigestHandler) send(ctx context.Context, schedule *DigestSchedule, digest *Digest, slot time.Time) error {
+	if h.sender == nil {
+		return fmt.Errorf("email not configured")
+	}
+	items := make([]email.DigestRequestItem, 0, len(digest.PendingRequests))
+	for _, req := range digest.PendingRequests {
+		name := req.RequesterName
+		if name == "" {
+			name = truncateAID(req.RequesterAID)
+		}
+		items = append(items, email.DigestRequestItem{RequesterName: name, Category: req.Category, Message: req.Message})
+	}
+	period := fmt.Sprintf("%s - %s",
+		digest.PeriodStart.In(schedule.location).Format("2 Jan"),
+		digest.PeriodEnd.In(schedule.location).Format("2 Jan"))
+	if err := h.sender.SendDigest(email.SendDigestRequest{
+		To:                   schedule.Email,
+		Name:                 digest.Name,
+		Period:               period,
+		NewMembers:           digest.NewMembers,
+		EndorsementsReceived: digest.EndorsementsReceived,
+		PendingRequests:      items,
+	}); err != nil {
+		return err
+	}
+
+	sentAt := h.now().UTC()
+	if h.store != nil {
+		state := digestState{LastSlot: slot, LastSentAt: &sentAt}
+		if err := h.store.SetPreference(ctx, digestStateKey(digest.AID), state); err != nil {
+			fmt.Printf("[Digest] Warning: failed to record digest sent: %v\n", err)
+		}
+	}
+	fmt.Printf("[Digest] Sent %s digest for %s to %s\n", period, truncateAID(digest.AID), schedule.Email)
+	return nil
+}
+
+// SendDue sends the local member's digest if a scheduled send time has
+// passed since the last one, within the grace period. A digest with nothing
+// to report is skipped but still counts for its slot. Returns whether a
+// digest was sent.
+func (h *DigestHandler) SendDue(ctx context.Context) (bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	aid := h.userIdentity.GetAID()
+	if aid == "" {
+		return false, nil
+	}
+	profiles := h.communityProfiles(ctx)
+	schedule, err := h.schedule(ctx, profiles)
+	if err != nil {
+		return false, err
+	}
+	if !schedule.Enabled || schedule.Email == "" {
+		return false, nil
+	}
+
+	now := h.now()
+	slot := schedule.latestSlot(now)
+	state := h.loadState(ctx, aid)
+	if !state.LastSlot.Before(slot) || now.Sub(slot) >= digestGrace {
+		return false, nil
+	}
+
+	digest, err := h.build(ctx, aid, profiles, slot.AddDate(0, 0, -7), slot)
+	if err != nil {
+		return false, err
+	}
+	if digest.empty() {
+		state.LastSlot = slot
+		if h.store != nil {
+			if err := h.store.SetPreference(ctx, digestStateKey(aid), state); err != nil {
+				return false, fmt.Errorf("failed to record digest slot: %v", err)
+			}
+		}
+		fmt.Printf("[Digest] Nothing to report for %s this week\n", truncateAID(aid))
+		return false, nil
+	}
+	if err := h.send(ctx, schedule, digest, slot); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// HandleStatus handles GET /api/v1/digest
+func (h *DigestHandler) HandleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	aid := h.userIdentity.GetAID()
+	if aid == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+
+	ctx := r.Context()
+	profiles := h.communityProfiles(ctx)
+	schedule, err := h.schedule(ctx, profiles)
+	if err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+		return
+	}
+	now := h.now()
+	preview, err := h.build(ctx, aid, profiles, schedule.latestSlot(now), now)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp := DigestStatusResponse{
+		Schedule:   schedule,
+		LastSentAt: h.loadState(ctx, aid).LastSentAt,
+		Preview:    preview,
+	}
+	if schedule.Enabled {
+		next := schedule.nextSlot(now)
+		resp.NextAt = &next
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// HandleSend handles POST /api/v1/digest/send, emailing the digest for the
+// past week now, whatever the schedule
+func (h *DigestHandler) HandleSend(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	aid := h.userIdentity.GetAID()
+	if aid == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ctx := r.Context()
+	profiles := h.communityProfiles(ctx)
+	schedule, err := h.schedule(ctx, profiles)
+	if err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+		return
+	}
+	if _, err := mail.ParseAddress(schedule.Email); err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": "no valid digest email address"})
+		return
+	}
+
+	now := h.now()
+	digest, err := h.build(ctx, aid, profiles, now.AddDate(0, 0, -7), now)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	// A manual send doesn't take the place of the scheduled one
+	if err := h.send(ctx, schedule, digest, h.loadState(ctx, aid).LastSlot); err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, digest)
+}
+
+// RegisterRoutes registers digest routes on the mux
+func (h *DigestHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/digest", h.HandleStatus)
+	mux.HandleFunc("/api/v1/digest/send", h.HandleSend)
+}
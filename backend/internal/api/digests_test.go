@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/identity"
+)
+
+func TestParseDigestSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefs    string
+		wantErr  bool
+		enabled  bool
+		email    string
+		timezone string
+		day      string
+		hour     int
+	}{
+		{"no preferences", ``, false, false, "public@example.com", "UTC", "monday", 8},
+		{"defaults", `{"digest":{"enabled":true}}`, false, true, "public@example.com", "UTC", "monday", 8},
+		{"custom", `{"timezone":"Pacific/Auckland","digest":{"enabled":true,"email":"me@example.com","day":"Fri","hour":17}}`,
+			false, true, "me@example.com", "Pacific/Auckland", "friday", 17},
+		{"unknown timezone", `{"timezone":"Mars/Olympus"}`, true, false, "", "", "", 0},
+		{"unknown day", `{"digest":{"day":"someday"}}`, true, false, "", "", "", 0},
+		{"hour out of range", `{"digest":{"hour":24}}`, true, false, "", "", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseDigestSchedule(json.RawMessage(tt.prefs), "public@example.com")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", s)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDigestSchedule failed: %v", err)
+			}
+			if s.Enabled != tt.enabled || s.Email != tt.email || s.Timezone != tt.timezone || s.Day != tt.day || s.Hour != tt.hour {
+				t.Errorf("unexpected schedule %+v", s)
+			}
+		})
+	}
+}
+
+func TestDigestSchedule_Slots(t *testing.T) {
+	s, err := parseDigestSchedule(json.RawMessage(`{"timezone":"Pacific/Auckland","digest":{"enabled":true,"day":"monday","hour":8}}`), "")
+	if err != nil {
+		t.Fatalf("parseDigestSchedule failed: %v", err)
+	}
+	auckland, _ := time.LoadLocation("Pacific/Auckland")
+
+	// Sunday 2026-10-18 20:00 UTC is Monday 09:00 in Auckland (NZDT)
+	now := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+	want := time.Date(2026, 10, 19, 8, 0, 0, 0, auckland)
+	if got := s.latestSlot(now); !got.Equal(want) {
+		t.Errorf("expected latest slot %v, got %v", want, got)
+	}
+	// Just before 08:00 local, the slot is the previous Monday
+	if got := s.latestSlot(want.Add(-time.Minute)); !got.Equal(want.AddDate(0, 0, -7)) {
+		t.Errorf("expected the previous week's slot, got %v", got)
+	}
+	if got := s.nextSlot(now); !got.Equal(time.Date(2026, 10, 26, 8, 0, 0, 0, auckland)) {
+		t.Errorf("unexpected next slot %v", got)
+	}
+
+	// Slots keep local wall-clock time across the April daylight saving
+	// change, so that week is an hour longer
+	before := s.latestSlot(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC))
+	after := s.nextSlot(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC))
+	if local := after.In(auckland); local.Hour() != 8 || local.Weekday() != time.Monday {
+		t.Errorf("expected Monday 08:00 local, got %v", local)
+	}
+	if gap := after.Sub(before); gap != 7*24*time.Hour+time.Hour {
+		t.Errorf("expected a 169h week across the change, got %v", gap)
+	}
+}
+
+func TestDigestHandler_Routes(t *testing.T) {
+	handler := &DigestHandler{userIdentity: identity.New(t.TempDir()), now: time.Now}
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	tests := []struct {
+		method, path string
+		wantStatus   int
+	}{
+		{http.MethodPost, "/api/v1/digest", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/api/v1/digest/send", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/api/v1/digest", http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/digest/send", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.path, tt.wantStatus, w.Code)
+		}
+	}
+
+	// Without an identity there is nothing to send
+	if sent, err := handler.SendDue(context.Background()); sent || err != nil {
+		t.Errorf("expected no digest without an identity, got %v, %v", sent, err)
+	}
+}
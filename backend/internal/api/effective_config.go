@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/matou-dao/backend/internal/config"
+)
+
+// EffectiveConfigHandler shows the configuration the server is running
+// with: config.yaml as last applied, and the org config
+type EffectiveConfigHandler struct {
+	server *config.Reloader
+	org    *OrgConfigHandler
+}
+
+// NewEffectiveConfigHandler creates a new effective config handler
+func NewEffectiveConfigHandler(server *config.Reloader, org *OrgConfigHandler) *EffectiveConfigHandler {
+	return &EffectiveConfigHandler{server: server, org: org}
+}
+
+// EffectiveOrgConfig is the org config part of the effective config
+type EffectiveOrgConfig struct {
+	Path string `json:"path"`
+	// LastError is why the last reload was refused, cleared by a good one
+	LastError string         `json:"lastError,omitempty"`
+	Settings  *OrgConfigData `json:"settings"`
+}
+
+// EffectiveConfigResponse is the response for GET /api/v1/config/effective
+type EffectiveConfigResponse struct {
+	Config *config.EffectiveConfig `json:"config"`
+	Org    EffectiveOrgConfig      `json:"org"`
+}
+
+// HandleEffective handles GET /api/v1/config/effective
+func (h *EffectiveConfigHandler) HandleEffective(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, EffectiveConfigResponse{
+		Config: h.server.Effective(),
+		Org: EffectiveOrgConfig{
+			Path:      h.org.Path(),
+			LastError: h.org.ReloadError(),
+			Settings:  h.org.GetConfig(),
+		},
+	})
+}
+
+// RegisterRoutes registers the effective config route on the mux
+func (h *EffectiveConfigHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/config/effective", CORSHandler(h.HandleEffective))
+}
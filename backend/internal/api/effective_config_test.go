@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/config"
+)
+
+func TestOrgConfigHandler_Reload(t *testing.T) {
+	dir := t.TempDir()
+	updates := 0
+	h := NewOrgConfigHandler(dir, func(*OrgConfigData) { updates++ })
+	path := filepath.Join(dir, "org-config.yaml")
+
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("organization:\n  aid: EORG\n  name: Org\ntrustAlgorithm: pagerank\n")
+	if changed, err := h.Reload(); !changed || err != nil {
+		t.Fatalf("expected the new file to apply, got %t %v", changed, err)
+	}
+	if h.GetTrustAlgorithm() != "pagerank" || updates != 1 {
+		t.Errorf("expected pagerank and one update, got %s and %d", h.GetTrustAlgorithm(), updates)
+	}
+
+	// Re-reading the same settings, e.g. after the handler's own save, is not a change
+	if err := h.saveToDisk(); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := h.Reload(); changed || updates != 1 {
+		t.Errorf("expected no change, got %t with %d updates", changed, updates)
+	}
+
+	// An invalid edit keeps the running config
+	write("organization:\n  aid: EORG\n  name: Org\ntrustAlgorithm: random\n")
+	if _, err := h.Reload(); err == nil {
+		t.Fatal("expected the invalid algorithm to be refused")
+	}
+	if h.GetTrustAlgorithm() != "pagerank" || h.ReloadError() == "" {
+		t.Errorf("expected the running config and a reload error, got %s %q", h.GetTrustAlgorithm(), h.ReloadError())
+	}
+}
+
+func TestEffectiveConfigHandler(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Auth.APIKeys = []config.APIKeyConfig{{Name: "ops", Key: "sk-live"}}
+	org := NewOrgConfigHandler(t.TempDir(), nil)
+	h := NewEffectiveConfigHandler(config.NewReloader("config.yaml", cfg, nil), org)
+
+	rec := httptest.NewRecorder()
+	h.HandleEffective(rec, httptest.NewRequest(http.MethodGet, "/api/v1/config/effective", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var resp struct {
+		Config struct {
+			Path     string `json:"path"`
+			Settings struct {
+				Auth struct {
+					APIKeys []map[string]interface{} `json:"apiKeys"`
+				} `json:"auth"`
+			} `json:"settings"`
+		} `json:"config"`
+		Org struct {
+			Path     string         `json:"path"`
+			Settings *OrgConfigData `json:"settings"`
+		} `json:"org"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Config.Path != "config.yaml" || resp.Org.Path != org.Path() || resp.Org.Settings != nil {
+		t.Errorf("unexpected response %s", rec.Body.String())
+	}
+	if keys := resp.Config.Settings.Auth.APIKeys; len(keys) != 1 || keys[0]["key"] != "[REDACTED]" {
+		t.Errorf("expected the API key to be redacted, got %v", keys)
+	}
+}
@@ -0,0 +1,564 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/keri"
+	"github.com/matou-dao/backend/internal/trust"
+)
+
+// Endorsement request statuses (declines are private and never change the request)
+const (
+	EndorsementPending  = "pending"
+	EndorsementAccepted = "accepted"
+)
+
+// EndorsementsHandler handles peer endorsement request workflow endpoints.
+// Requests are written to the community space so they reach the endorser's
+// backend; declines are written to the endorser's private space only.
+type EndorsementsHandler struct {
+	spaceManager *anysync.SpaceManager
+	userIdentity *identity.UserIdentity
+	trust        *TrustHandler
+	registry     keri.EndorsementTypeSource
+}
+
+// NewEndorsementsHandler creates a new endorsements handler
+func NewEndorsementsHandler(
+	spaceManager *anysync.SpaceManager,
+	userIdentity *identity.UserIdentity,
+	trustHandler *TrustHandler,
+) *EndorsementsHandler {
+	return &EndorsementsHandler{
+		spaceManager: spaceManager,
+		userIdentity: userIdentity,
+		trust:        trustHandler,
+	}
+}
+
+// SetEndorsementRegistry attaches the registry of endorsement types and
+// categories. Without one, the defaults apply.
+func (h *EndorsementsHandler) SetEndorsementRegistry(registry keri.EndorsementTypeSource) {
+	h.registry = registry
+}
+
+// endorsementTypes returns the registered endorsement types
+func (h *EndorsementsHandler) endorsementTypes() []keri.EndorsementType {
+	if h.registry != nil {
+		if types := h.registry.GetEndorsementTypes(); len(types) > 0 {
+			return types
+		}
+	}
+	return keri.DefaultEndorsementTypes()
+}
+
+// EndorsementRequest is the data stored for an endorsement request object
+type EndorsementRequest struct {
+	RequesterAID    string `json:"requesterAid"`
+	EndorserAID     string `json:"endorserAid"`
+	Category        string `json:"category"`
+	EndorsementType string `json:"endorsementType,omitempty"`
+	Message         string `json:"message,omitempty"`
+	Status          string `json:"status"`
+	CreatedAt       string `json:"createdAt"`
+	RespondedAt     string `json:"respondedAt,omitempty"`
+}
+
+// EndorsementDecline is the private record kept when an endorser declines
+type EndorsementDecline struct {
+	RequestID    string `json:"requestId"`
+	RequesterAID string `json:"requesterAid"`
+	Category     string `json:"category"`
+	Reason       string `json:"reason,omitempty"`
+	DeclinedAt   string `json:"declinedAt"`
+}
+
+// EndorsementRequestView is an endorsement request with its object ID
+type EndorsementRequestView struct {
+	ID string `json:"id"`
+	EndorsementRequest
+}
+
+// CreateEndorsementRequest is the body for POST /api/v1/endorsements/request
+type CreateEndorsementRequest struct {
+	EndorserAID string `json:"endorserAid" validate:"required"`
+	Category    string `json:"category" validate:"required,max=100"`
+	// EndorsementType is optional; the category determines it
+	EndorsementType string `json:"endorsementType,omitempty"`
+	Message         string `json:"message,omitempty"`
+}
+
+// EndorsementCategoryView is a registered category with its type, as
+// listed by GET /api/v1/endorsements/categories
+type EndorsementCategoryView struct {
+	keri.EndorsementCategory
+	Type string `json:"type"`
+}
+
+// AcceptEndorsementRequest is the optional body for POST .../requests/{id}/accept
+type AcceptEndorsementRequest struct {
+	// Confidence in [0, 1] scales the endorsement's trust weight; omitted
+	// means full confidence
+	Confidence *float64 `json:"confidence,omitempty" validate:"min=0,max=1"`
+}
+
+// DeclineEndorsementRequest is the body for POST .../requests/{id}/decline
+type DeclineEndorsementRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// CredentialIssuance pre-fills a credential for the issuer's client to issue
+// via KERIA, e.g. an endorsement after accepting a request
+type CredentialIssuance struct {
+	Schema    string                 `json:"schema"`
+	Issuer    string                 `json:"issuer"`
+	Recipient string                 `json:"recipient"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// HandleRequest handles POST /api/v1/endorsements/request
+func (h *EndorsementsHandler) HandleRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	requester := h.userIdentity.GetAID()
+	if requester == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+	if err := checkSignedAID(r, requester); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+		return
+	}
+
+	var req CreateEndorsementRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	req.Category = strings.TrimSpace(req.Category)
+
+	typ, category := keri.FindEndorsementCategory(h.endorsementTypes(), req.Category)
+	if category == nil {
+		writeProblem(w, http.StatusBadRequest, "", FieldError{Field: "category", Message: fmt.Sprintf("is not a known endorsement category: %s", req.Category)})
+		return
+	}
+	if req.EndorsementType != "" && !strings.EqualFold(req.EndorsementType, typ.ID) {
+		writeProblem(w, http.StatusBadRequest, "", FieldError{Field: "endorsementType", Message: fmt.Sprintf("does not match category %s (%s)", category.ID, typ.ID)})
+		return
+	}
+	req.Category, req.EndorsementType = category.ID, typ.ID
+
+	if req.EndorserAID == requester {
+		writeProblem(w, http.StatusBadRequest, "", FieldError{Field: "endorserAid", Message: "cannot be yourself"})
+		return
+	}
+
+	ctx := r.Context()
+
+	// Trust checks: both parties must be community members, and the endorser
+	// must not already have endorsed the requester in this category
+	status, err := h.checkEligibility(ctx, requester, req.EndorserAID, req.Category)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
+	if communitySpaceID == "" {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "community space not configured"})
+		return
+	}
+
+	existing, err := h.readRequests(ctx)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read endorsement requests: %v", err),
+		})
+		return
+	}
+	for _, e := range existing {
+		if e.RequesterAID == requester && e.EndorserAID == req.EndorserAID &&
+			strings.EqualFold(e.Category, req.Category) && e.Status == EndorsementPending {
+			writeJSON(w, http.StatusConflict, map[string]string{
+				"error": "an endorsement request in this category is already pending",
+			})
+			return
+		}
+	}
+
+	objectID := fmt.Sprintf("EndorsementRequest-%s-%d", requester, time.Now().UnixMilli())
+	data := EndorsementRequest{
+		RequesterAID:    requester,
+		EndorserAID:     req.EndorserAID,
+		Category:        req.Category,
+		EndorsementType: req.EndorsementType,
+		Message:         req.Message,
+		Status:          EndorsementPending,
+		CreatedAt:       time.Now().UTC().Format(time.RFC3339),
+	}
+	if _, err := writeObject(ctx, h.spaceManager, communitySpaceID, objectID, "EndorsementRequest", data); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	fmt.Printf("[Endorsements] %s requested %s endorsement from %s\n", requester, req.Category, req.EndorserAID)
+	writeJSON(w, http.StatusCreated, EndorsementRequestView{ID: objectID, EndorsementRequest: data})
+}
+
+// HandleList handles GET /api/v1/endorsements/requests
+// Query params:
+//   - direction: "incoming" (default, pending requests addressed to me that
+//     I haven't declined) or "outgoing" (requests I made, any status)
+func (h *EndorsementsHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+
+	direction := r.URL.Query().Get("direction")
+	if direction == "" {
+		direction = "incoming"
+	}
+	if direction != "incoming" && direction != "outgoing" {
+		writeProblem(w, http.StatusBadRequest, "", FieldError{Field: "direction", Message: "must be one of: incoming, outgoing"})
+		return
+	}
+
+	ctx := r.Context()
+	all, err := h.readRequests(ctx)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read endorsement requests: %v", err),
+		})
+		return
+	}
+
+	declined := map[string]bool{}
+	if direction == "incoming" {
+		declined = h.declinedRequestIDs(ctx)
+	}
+
+	result := make([]*EndorsementRequestView, 0)
+	for _, req := range all {
+		switch direction {
+		case "incoming":
+			if !awaitingEndorser(req, me, declined) {
+				continue
+			}
+		case "outgoing":
+			if req.RequesterAID != me {
+				continue
+			}
+		}
+		result = append(result, req)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"requests": result,
+		"count":    len(result),
+	})
+}
+
+// handleRequestAction routes POST /api/v1/endorsements/requests/{id}/{accept|decline}
+func (h *EndorsementsHandler) handleRequestAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/endorsements/requests/")
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[0] == "" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+	// Accepting or declining acts as the endorser
+	if err := checkSignedAID(r, h.userIdentity.GetAID()); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+		return
+	}
+
+	switch parts[1] {
+	case "accept":
+		h.handleAccept(w, r, parts[0])
+	case "decline":
+		h.handleDecline(w, r, parts[0])
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
+
+// handleAccept marks the request accepted and returns a pre-filled endorsement
+// credential for the endorser's client to issue.
+func (h *EndorsementsHandler) handleAccept(w http.ResponseWriter, r *http.Request, requestID string) {
+	var body AcceptEndorsementRequest
+	if r.Body != nil && r.ContentLength != 0 {
+		if !decodeRequest(w, r, &body) {
+			return
+		}
+	}
+
+	ctx := r.Context()
+	req, status, err := h.pendingRequestForMe(ctx, requestID)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	req.Status = EndorsementAccepted
+	req.RespondedAt = now
+	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
+	if _, err := writeObject(ctx, h.spaceManager, communitySpaceID, req.ID, "EndorsementRequest", req.EndorsementRequest); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	issuanceData := map[string]interface{}{
+		"category":   req.Category,
+		"endorsedAt": now,
+		"requestId":  req.ID,
+	}
+	if req.EndorsementType != "" {
+		issuanceData["endorsementType"] = req.EndorsementType
+	}
+	if req.Message != "" {
+		issuanceData["requestMessage"] = req.Message
+	}
+	if body.Confidence != nil {
+		issuanceData["confidence"] = *body.Confidence
+	}
+
+	fmt.Printf("[Endorsements] %s accepted request %s\n", req.EndorserAID, req.ID)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"request": req,
+		"issuance": CredentialIssuance{
+			Schema:    trust.EndorsementSchema,
+			Issuer:    req.EndorserAID,
+			Recipient: req.RequesterAID,
+			Data:      issuanceData,
+		},
+	})
+}
+
+// handleDecline records the decline in the endorser's private space. The
+// shared request is left untouched so the requester is not told.
+func (h *EndorsementsHandler) handleDecline(w http.ResponseWriter, r *http.Request, requestID string) {
+	var body DeclineEndorsementRequest
+	if r.Body != nil && r.ContentLength != 0 {
+		if !decodeRequest(w, r, &body) {
+			return
+		}
+	}
+
+	ctx := r.Context()
+	req, status, err := h.pendingRequestForMe(ctx, requestID)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	privateSpaceID := h.userIdentity.GetPrivateSpaceID()
+	if privateSpaceID == "" {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "private space not configured"})
+		return
+	}
+
+	decline := EndorsementDecline{
+		RequestID:    req.ID,
+		RequesterAID: req.RequesterAID,
+		Category:     req.Category,
+		Reason:       body.Reason,
+		DeclinedAt:   time.Now().UTC().Format(time.RFC3339),
+	}
+	if _, err := writeObject(ctx, h.spaceManager, privateSpaceID, "EndorsementDecline-"+req.ID, "EndorsementDecline", decline); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "declined"})
+}
+
+// pendingRequestForMe loads a request and checks it is pending and addressed
+// to the local user. Returns an HTTP status alongside any error.
+func (h *EndorsementsHandler) pendingRequestForMe(ctx context.Context, requestID string) (*EndorsementRequestView, int, error) {
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("identity not configured")
+	}
+
+	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
+	if communitySpaceID == "" {
+		return nil, http.StatusServiceUnavailable, fmt.Errorf("community space not configured")
+	}
+
+	obj, err := h.spaceManager.ObjectTreeManager().ReadLatestByID(ctx, communitySpaceID, requestID)
+	if err != nil || obj.Type != "EndorsementRequest" {
+		return nil, http.StatusNotFound, fmt.Errorf("endorsement request not found")
+	}
+
+	req := &EndorsementRequestView{ID: obj.ID}
+	if err := json.Unmarshal(obj.Data, &req.EndorsementRequest); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("invalid endorsement request: %v", err)
+	}
+	if req.EndorserAID != me {
+		return nil, http.StatusForbidden, fmt.Errorf("endorsement request is not addressed to you")
+	}
+	if req.Status != EndorsementPending || h.declinedRequestIDs(ctx)[req.ID] {
+		return nil, http.StatusConflict, fmt.Errorf("endorsement request is no longer pending")
+	}
+	return req, http.StatusOK, nil
+}
+
+// checkEligibility applies trust-graph checks before a request is sent
+func (h *EndorsementsHandler) checkEligibility(ctx context.Context, requester, endorser, category string) (int, error) {
+	if h.trust == nil {
+		return http.StatusOK, nil
+	}
+
+	graph, err := h.trust.buildGraph(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to build trust graph: %v", err)
+	}
+	for _, aid := range []string{requester, endorser} {
+		node := graph.GetNode(aid)
+		if node == nil || !node.IsMember() {
+			return http.StatusBadRequest, fmt.Errorf("%s is not a community member", aid)
+		}
+	}
+
+	creds, err := h.trust.credentialsIssuedBy(ctx, endorser)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to read credentials: %v", err)
+	}
+	for _, cred := range creds {
+		if cred.SchemaID != trust.EndorsementSchema || cred.SubjectAID != requester {
+			continue
+		}
+		if data, ok := cred.Data.(map[string]interface{}); ok {
+			if c, _ := data["category"].(string); strings.EqualFold(c, category) {
+				return http.StatusConflict, fmt.Errorf("already endorsed by this member in %s", category)
+			}
+		}
+	}
+	return http.StatusOK, nil
+}
+
+// awaitingEndorser reports whether a request is pending with the endorser,
+// who hasn't declined it
+func awaitingEndorser(req *EndorsementRequestView, endorser string, declined map[string]bool) bool {
+	return req.EndorserAID == endorser && req.Status == EndorsementPending && !declined[req.ID]
+}
+
+// PendingIncoming returns the requests waiting on the local user's response
+func (h *EndorsementsHandler) PendingIncoming(ctx context.Context) ([]*EndorsementRequestView, error) {
+	me := h.userIdentity.GetAID()
+	all, err := h.readRequests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	declined := h.declinedRequestIDs(ctx)
+	var pending []*EndorsementRequestView
+	for _, req := range all {
+		if awaitingEndorser(req, me, declined) {
+			pending = append(pending, req)
+		}
+	}
+	return pending, nil
+}
+
+// readRequests returns the latest version of every endorsement request
+func (h *EndorsementsHandler) readRequests(ctx context.Context) ([]*EndorsementRequestView, error) {
+	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
+	if communitySpaceID == "" {
+		return nil, nil
+	}
+	objects, err := readLatestObjects(ctx, h.spaceManager, communitySpaceID, "EndorsementRequest")
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*EndorsementRequestView, 0, len(objects))
+	for _, obj := range objects {
+		view := &EndorsementRequestView{ID: obj.ID}
+		if err := json.Unmarshal(obj.Data, &view.EndorsementRequest); err != nil {
+			continue
+		}
+		result = append(result, view)
+	}
+	return result, nil
+}
+
+// declinedRequestIDs returns the IDs of requests the local user has declined
+func (h *EndorsementsHandler) declinedRequestIDs(ctx context.Context) map[string]bool {
+	declined := make(map[string]bool)
+	privateSpaceID := h.userIdentity.GetPrivateSpaceID()
+	if privateSpaceID == "" {
+		return declined
+	}
+	objects, err := readLatestObjects(ctx, h.spaceManager, privateSpaceID, "EndorsementDecline")
+	if err != nil {
+		return declined
+	}
+	for _, obj := range objects {
+		var d EndorsementDecline
+		if err := json.Unmarshal(obj.Data, &d); err == nil {
+			declined[d.RequestID] = true
+		}
+	}
+	return declined
+}
+
+// HandleTypes handles GET /api/v1/endorsements/types, listing the
+// registered endorsement types with their categories
+func (h *EndorsementsHandler) HandleTypes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"types": h.endorsementTypes()})
+}
+
+// HandleCategories handles GET /api/v1/endorsements/categories, listing
+// every registered category with its type. Query params:
+//   - type: only list categories of this type
+func (h *EndorsementsHandler) HandleCategories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	filter := r.URL.Query().Get("type")
+	categories := []EndorsementCategoryView{}
+	for _, t := range h.endorsementTypes() {
+		if filter != "" && !strings.EqualFold(filter, t.ID) {
+			continue
+		}
+		for _, c := range t.Categories {
+			categories = append(categories, EndorsementCategoryView{EndorsementCategory: c, Type: t.ID})
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"categories": categories})
+}
+
+// RegisterRoutes registers endorsement routes on the mux
+func (h *EndorsementsHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/endorsements/request", h.HandleRequest)
+	mux.HandleFunc("/api/v1/endorsements/requests", h.HandleList)
+	mux.HandleFunc("/api/v1/endorsements/requests/", h.handleRequestAction)
+	mux.HandleFunc("/api/v1/endorsements/types", h.HandleTypes)
+	mux.HandleFunc("/api/v1/endorsements/categories", h.HandleCategories)
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/keri"
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+func newTestEndorsementsHandler(t *testing.T, aid string) *EndorsementsHandler {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "endorsements_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	userIdentity := identity.New(tmpDir)
+	if aid != "" {
+		if err := userIdentity.SetIdentity(aid, secret.NewMnemonic("test mnemonic")); err != nil {
+			t.Fatalf("SetIdentity failed: %v", err)
+		}
+	}
+	return NewEndorsementsHandler(nil, userIdentity, nil)
+}
+
+func TestEndorsementRequest_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		aid        string
+		body       string
+		wantStatus int
+	}{
+		{"no identity", "", `{"endorserAid":"EPEER","category":"facilitation"}`, http.StatusBadRequest},
+		{"missing endorser", "EALICE", `{"category":"facilitation"}`, http.StatusBadRequest},
+		{"missing category", "EALICE", `{"endorserAid":"EPEER","category":"  "}`, http.StatusBadRequest},
+		{"self endorsement", "EALICE", `{"endorserAid":"EALICE","category":"facilitation"}`, http.StatusBadRequest},
+		{"unknown category", "EALICE", `{"endorserAid":"EPEER","category":"juggling"}`, http.StatusBadRequest},
+		{"wrong type", "EALICE", `{"endorserAid":"EPEER","category":"facilitation","endorsementType":"character"}`, http.StatusBadRequest},
+		{"invalid json", "EALICE", `{`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestEndorsementsHandler(t, tt.aid)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/endorsements/request", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.HandleRequest(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestEndorsementRequest_MethodNotAllowed(t *testing.T) {
+	handler := newTestEndorsementsHandler(t, "EALICE")
+
+	w := httptest.NewRecorder()
+	handler.HandleRequest(w, httptest.NewRequest(http.MethodGet, "/api/v1/endorsements/request", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.handleRequestAction(w, httptest.NewRequest(http.MethodGet, "/api/v1/endorsements/requests/abc/accept", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestEndorsementRequestAction_UnknownAction(t *testing.T) {
+	handler := newTestEndorsementsHandler(t, "EALICE")
+
+	w := httptest.NewRecorder()
+	handler.handleRequestAction(w, httptest.NewRequest(http.MethodPost, "/api/v1/endorsements/requests/abc/frobnicate", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestEndorsementAccept_InvalidConfidence(t *testing.T) {
+	handler := newTestEndorsementsHandler(t, "EALICE")
+
+	for _, body := range []string{`{"confidence":1.5}`, `{"confidence":-0.1}`, `{"confidence":"high"}`} {
+		w := httptest.NewRecorder()
+		handler.handleRequestAction(w, httptest.NewRequest(http.MethodPost, "/api/v1/endorsements/requests/abc/accept", strings.NewReader(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", body, w.Code)
+		}
+	}
+}
+
+type staticEndorsementTypes []keri.EndorsementType
+
+func (s staticEndorsementTypes) GetEndorsementTypes() []keri.EndorsementType { return s }
+
+func TestEndorsementRegistry(t *testing.T) {
+	handler := newTestEndorsementsHandler(t, "EALICE")
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/endorsements/types", nil))
+	var types struct {
+		Types []keri.EndorsementType `json:"types"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &types); err != nil {
+		t.Fatalf("decode types: %v", err)
+	}
+	if w.Code != http.StatusOK || len(types.Types) != len(keri.DefaultEndorsementTypes()) {
+		t.Fatalf("expected the default types, got %d: %s", w.Code, w.Body.String())
+	}
+
+	handler.SetEndorsementRegistry(staticEndorsementTypes{
+		{ID: "craft", Label: "Craft", Categories: []keri.EndorsementCategory{{ID: "weaving", Label: "Weaving"}}},
+		{ID: "care", Label: "Care", Categories: []keri.EndorsementCategory{{ID: "kindness", Label: "Kindness"}}},
+	})
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/endorsements/categories?type=care", nil))
+	var categories struct {
+		Categories []EndorsementCategoryView `json:"categories"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &categories); err != nil {
+		t.Fatalf("decode categories: %v", err)
+	}
+	if len(categories.Categories) != 1 || categories.Categories[0].ID != "kindness" || categories.Categories[0].Type != "care" {
+		t.Errorf("expected only the care category, got %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleRequest(w, httptest.NewRequest(http.MethodPost, "/api/v1/endorsements/request",
+		strings.NewReader(`{"endorserAid":"EPEER","category":"facilitation"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected default category to be rejected with a configured registry, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/endorsements/types", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
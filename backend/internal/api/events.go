@@ -4,29 +4,61 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/trust"
+	"golang.org/x/net/websocket"
+)
+
+// Event types pushed to SSE and WebSocket clients, besides those broadcast
+// by the background sync worker and term watcher
+const (
+	EventCredentialStored   = "credential:stored"
+	EventCredentialRevoked  = "credential:revoked"
+	EventCredentialDelivery = "credential:delivery"
+	EventEndorsementSynced  = "endorsement:synced"
+	EventSpaceCreated       = "space:created"
+	EventACLChanged         = "acl:changed"
+	EventInboxItem          = "inbox:item"
 )
 
-// SSEEvent represents a server-sent event.
+// eventKeepalive is how often idle streams are sent a keepalive
+const eventKeepalive = 30 * time.Second
+
+// SSEEvent represents a server-sent event. WebSocket clients receive the
+// same event as a JSON message.
 type SSEEvent struct {
 	Type string      `json:"type"`
 	Data interface{} `json:"data"`
 }
 
-// EventBroker manages SSE connections and event broadcasting.
+// EventBroker manages SSE and WebSocket connections and event broadcasting.
 type EventBroker struct {
 	mu      sync.RWMutex
 	clients map[chan SSEEvent]struct{}
+
+	closeOnce sync.Once
+	done      chan struct{}
 }
 
 // NewEventBroker creates a new event broker.
 func NewEventBroker() *EventBroker {
 	return &EventBroker{
 		clients: make(map[chan SSEEvent]struct{}),
+		done:    make(chan struct{}),
 	}
 }
 
+// Close ends all SSE streams. Streams never go idle, so the server calls
+// this on shutdown to let them be drained.
+func (b *EventBroker) Close() {
+	b.closeOnce.Do(func() { close(b.done) })
+}
+
 // Subscribe adds a new client channel.
 func (b *EventBroker) Subscribe() chan SSEEvent {
 	ch := make(chan SSEEvent, 16)
@@ -44,8 +76,12 @@ func (b *EventBroker) Unsubscribe(ch chan SSEEvent) {
 	close(ch)
 }
 
-// Broadcast sends an event to all connected clients.
+// Broadcast sends an event to all connected clients. A nil broker drops
+// the event, so handlers don't need to check whether one is attached.
 func (b *EventBroker) Broadcast(event SSEEvent) {
+	if b == nil {
+		return
+	}
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 	for ch := range b.clients {
@@ -57,14 +93,67 @@ func (b *EventBroker) Broadcast(event SSEEvent) {
 	}
 }
 
-// ClientCount returns the number of connected SSE clients.
+// ClientCount returns the number of connected SSE and WebSocket clients.
 func (b *EventBroker) ClientCount() int {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 	return len(b.clients)
 }
 
-// EventsHandler handles the SSE endpoint.
+// ACL change actions reported in acl:changed events
+const (
+	ACLInviteCreated = "invite_created"
+	ACLInviteRevoked = "invite_revoked"
+	ACLJoined        = "joined"
+	ACLJoinRequested = "join_requested"
+	ACLMemberAdded   = "member_added"
+	ACLMemberRemoved = "member_removed"
+	ACLRekeyed       = "rekeyed"
+)
+
+// CredentialStoredEvents returns credential:stored for a newly stored
+// credential, plus endorsement:synced when it is an endorsement, so
+// clients can refresh trust and endorsement views without polling.
+func CredentialStoredEvents(cred *anystore.CachedCredential) []SSEEvent {
+	data := map[string]string{
+		"said":      cred.ID,
+		"issuer":    cred.IssuerAID,
+		"recipient": cred.SubjectAID,
+		"schema":    cred.SchemaID,
+	}
+	events := []SSEEvent{{Type: EventCredentialStored, Data: data}}
+	if cred.SchemaID == trust.EndorsementSchema {
+		events = append(events, SSEEvent{Type: EventEndorsementSynced, Data: data})
+	}
+	return events
+}
+
+// spaceCreatedEvent returns the space:created event for a new space
+func spaceCreatedEvent(space *anysync.Space) SSEEvent {
+	return SSEEvent{
+		Type: EventSpaceCreated,
+		Data: map[string]string{
+			"spaceId":   space.SpaceID,
+			"spaceType": space.SpaceType,
+			"ownerAid":  space.OwnerAID,
+		},
+	}
+}
+
+// aclChangedEvent returns the acl:changed event for a space. aid is the
+// member affected, if known.
+func aclChangedEvent(spaceID, action, aid string) SSEEvent {
+	data := map[string]string{
+		"spaceId": spaceID,
+		"action":  action,
+	}
+	if aid != "" {
+		data["aid"] = aid
+	}
+	return SSEEvent{Type: EventACLChanged, Data: data}
+}
+
+// EventsHandler handles the event stream endpoint.
 type EventsHandler struct {
 	broker *EventBroker
 }
@@ -74,7 +163,8 @@ func NewEventsHandler(broker *EventBroker) *EventsHandler {
 	return &EventsHandler{broker: broker}
 }
 
-// HandleEvents handles GET /api/v1/events (SSE stream).
+// HandleEvents handles GET /api/v1/events. WebSocket upgrade requests get
+// a WebSocket; all others get an SSE stream.
 func (h *EventsHandler) HandleEvents(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
@@ -82,6 +172,10 @@ func (h *EventsHandler) HandleEvents(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		h.serveWebSocket(w, r)
+		return
+	}
 
 	flusher, ok := w.(http.Flusher)
 	if !ok {
@@ -105,7 +199,7 @@ func (h *EventsHandler) HandleEvents(w http.ResponseWriter, r *http.Request) {
 	flusher.Flush()
 
 	// Keepalive ticker
-	ticker := time.NewTicker(30 * time.Second)
+	ticker := time.NewTicker(eventKeepalive)
 	defer ticker.Stop()
 
 	ctx := r.Context()
@@ -113,6 +207,8 @@ func (h *EventsHandler) HandleEvents(w http.ResponseWriter, r *http.Request) {
 		select {
 		case <-ctx.Done():
 			return
+		case <-h.broker.done:
+			return
 		case event, ok := <-ch:
 			if !ok {
 				return
@@ -130,6 +226,88 @@ func (h *EventsHandler) HandleEvents(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// serveWebSocket streams events as JSON messages ({"type": ..., "data": ...}).
+// Browsers don't apply CORS to WebSockets, so the origin is checked against
+// the same allow-list; clients without an Origin header (non-browser) are
+// accepted.
+func (h *EventsHandler) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	server := websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			if origin := r.Header.Get("Origin"); origin != "" && !isAllowedOrigin(origin) {
+				return fmt.Errorf("origin %s not allowed", origin)
+			}
+			config.Protocol = selectProtocol(webSocketProtocols(r))
+			return nil
+		},
+		Handler: h.streamWebSocket,
+	}
+	server.ServeHTTP(w, r)
+}
+
+// selectProtocol picks the subprotocol to accept from those offered. A
+// browser fails the handshake unless one it offered is echoed, so a bearer
+// credential is only echoed when it's all the client offered.
+func selectProtocol(offered []string) []string {
+	for _, protocol := range offered {
+		if !strings.HasPrefix(protocol, bearerProtocolPrefix) {
+			return []string{protocol}
+		}
+	}
+	if len(offered) > 0 {
+		return offered[:1]
+	}
+	return nil
+}
+
+func (h *EventsHandler) streamWebSocket(ws *websocket.Conn) {
+	defer ws.Close()
+
+	ch := h.broker.Subscribe()
+	defer h.broker.Unsubscribe(ch)
+
+	// The stream is send-only; reading detects the client closing it
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var discard string
+		for websocket.Message.Receive(ws, &discard) == nil {
+		}
+	}()
+
+	if err := websocket.JSON.Send(ws, SSEEvent{
+		Type: "connected",
+		Data: map[string]string{"status": "connected"},
+	}); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(eventKeepalive)
+	defer ticker.Stop()
+
+	ctx := ws.Request().Context()
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return
+		case <-closed:
+			return
+		case <-h.broker.done:
+			return
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			err = websocket.JSON.Send(ws, event)
+		case <-ticker.C:
+			err = websocket.JSON.Send(ws, SSEEvent{Type: "keepalive"})
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
 // RegisterRoutes registers the events route.
 func (h *EventsHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/v1/events", h.HandleEvents)
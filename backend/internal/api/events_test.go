@@ -0,0 +1,144 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/trust"
+	"golang.org/x/net/websocket"
+)
+
+func dialEvents(serverURL, origin string) (*websocket.Conn, error) {
+	return websocket.Dial(strings.Replace(serverURL, "http", "ws", 1)+"/api/v1/events", "", origin)
+}
+
+func receiveEvent(t *testing.T, ws *websocket.Conn) map[string]any {
+	t.Helper()
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var event map[string]any
+	if err := websocket.JSON.Receive(ws, &event); err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	return event
+}
+
+func TestEvents_WebSocket(t *testing.T) {
+	broker := NewEventBroker()
+	defer broker.Close()
+
+	var logs bytes.Buffer
+	mux := http.NewServeMux()
+	NewEventsHandler(broker).RegisterRoutes(mux)
+	server := httptest.NewServer(newAccessLogger(AccessLogOptions{}, &logs).wrap(mux))
+	defer server.Close()
+
+	ws, err := dialEvents(server.URL, "http://localhost:9000")
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer ws.Close()
+
+	if event := receiveEvent(t, ws); event["type"] != "connected" {
+		t.Fatalf("expected connected event first, got %v", event)
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); broker.ClientCount() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("websocket client never subscribed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	broker.Broadcast(aclChangedEvent("space-1", ACLJoined, "EUSER123"))
+
+	event := receiveEvent(t, ws)
+	data, _ := event["data"].(map[string]any)
+	if event["type"] != EventACLChanged || data["spaceId"] != "space-1" || data["action"] != ACLJoined || data["aid"] != "EUSER123" {
+		t.Errorf("unexpected event: %v", event)
+	}
+}
+
+func TestEvents_WebSocketRejectsOrigin(t *testing.T) {
+	broker := NewEventBroker()
+	defer broker.Close()
+
+	mux := http.NewServeMux()
+	NewEventsHandler(broker).RegisterRoutes(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	if ws, err := dialEvents(server.URL, "https://evil.example"); err == nil {
+		ws.Close()
+		t.Fatal("expected handshake from a disallowed origin to fail")
+	}
+
+	ws, err := dialEvents(server.URL, "http://localhost:9000")
+	if err != nil {
+		t.Fatalf("expected allowed origin to connect: %v", err)
+	}
+	ws.Close()
+}
+
+func TestEvents_WebSocketBearerProtocol(t *testing.T) {
+	broker := NewEventBroker()
+	defer broker.Close()
+
+	a, _ := newTestAuthenticator(t)
+	mux := http.NewServeMux()
+	NewEventsHandler(broker).RegisterRoutes(mux)
+	server := httptest.NewServer(AuthMiddleware(a, mux))
+	defer server.Close()
+
+	if ws, err := dialEvents(server.URL, "http://localhost:9000"); err == nil {
+		ws.Close()
+		t.Fatal("expected a handshake without a credential to fail")
+	}
+
+	config, err := websocket.NewConfig(strings.Replace(server.URL, "http", "ws", 1)+"/api/v1/events", "http://localhost:9000")
+	if err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	config.Protocol = []string{"matou.events", "bearer.ci-key-0123456789"}
+	ws, err := websocket.DialConfig(config)
+	if err != nil {
+		t.Fatalf("expected the bearer subprotocol to authenticate: %v", err)
+	}
+	defer ws.Close()
+
+	if event := receiveEvent(t, ws); event["type"] != "connected" {
+		t.Fatalf("expected connected event first, got %v", event)
+	}
+	if got := ws.Config().Protocol; len(got) != 1 || got[0] != "matou.events" {
+		t.Errorf("expected the non-credential subprotocol accepted, got %v", got)
+	}
+}
+
+func TestCredentialStoredEvents(t *testing.T) {
+	membership := CredentialStoredEvents(&anystore.CachedCredential{ID: "ESAID1", SchemaID: "EMatouMembershipSchemaV1"})
+	if len(membership) != 1 || membership[0].Type != EventCredentialStored {
+		t.Errorf("expected only credential:stored, got %+v", membership)
+	}
+
+	endorsement := CredentialStoredEvents(&anystore.CachedCredential{
+		ID:         "ESAID2",
+		IssuerAID:  "EALICE",
+		SubjectAID: "EBOB",
+		SchemaID:   trust.EndorsementSchema,
+	})
+	if len(endorsement) != 2 || endorsement[1].Type != EventEndorsementSynced {
+		t.Fatalf("expected credential:stored and endorsement:synced, got %+v", endorsement)
+	}
+	data := endorsement[1].Data.(map[string]string)
+	if data["said"] != "ESAID2" || data["issuer"] != "EALICE" || data["recipient"] != "EBOB" {
+		t.Errorf("unexpected endorsement data: %v", data)
+	}
+}
+
+func TestEventBroker_NilBroadcast(t *testing.T) {
+	var broker *EventBroker
+	broker.Broadcast(SSEEvent{Type: EventSpaceCreated}) // must not panic
+}
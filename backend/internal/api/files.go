@@ -2,23 +2,57 @@ package api
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/ipfs/go-cid"
 	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/imaging"
 )
 
 const maxFileSize = 5 << 20 // 5 MB
 
+// uploadImageTypes are the image formats accepted where only images are,
+// by the type their content sniffs as. SVG is excluded: it can carry script.
+var uploadImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// activeContentTypes are types a browser would render or run in the app's
+// origin. Uploads are never stored as one of them.
+var activeContentTypes = map[string]bool{
+	"text/html":              true,
+	"text/xml":               true,
+	"text/javascript":        true,
+	"application/javascript": true,
+	"application/xhtml+xml":  true,
+	"application/xml":        true,
+	"image/svg+xml":          true,
+}
+
 // FilesHandler handles file upload and download using the any-sync filenode.
 // Files are chunked into IPFS UnixFS DAG blocks, pushed to the filenode via
 // dRPC, and metadata is persisted in the community space's ObjectTree for P2P sync.
 type FilesHandler struct {
 	fileManager  *anysync.FileManager
 	spaceManager *anysync.SpaceManager
+	quota        int64
+	renditions   []imaging.Spec
+	maxUpload    int64
+
+	// quotaMu serializes quota checks with the uploads they admit
+	quotaMu sync.Mutex
+
+	uploadsMu sync.Mutex
+	uploads   map[string]*uploadSession // chunked uploads by ID
 }
 
 // NewFilesHandler creates a new files handler backed by the filenode.
@@ -29,9 +63,21 @@ func NewFilesHandler(fileManager *anysync.FileManager, spaceManager *anysync.Spa
 	}
 }
 
+// SetQuota caps the total bytes each user may upload, renditions
+// included (0 = unlimited)
+func (h *FilesHandler) SetQuota(bytes int64) {
+	h.quota = bytes
+}
+
+// SetRenditions sets the scaled copies stored alongside uploaded images
+func (h *FilesHandler) SetRenditions(specs []imaging.Spec) {
+	h.renditions = specs
+}
+
 // HandleUpload handles POST /api/v1/files/upload
-// Accepts multipart file upload (image/*, max 5MB).
-// Returns a fileRef (CID string) that can be stored in profile objects.
+// Accepts multipart file upload (JPEG, PNG, GIF or WebP by content, max 5MB).
+// Returns a fileRef (CID string) that can be stored in profile objects, and
+// the refs of any scaled renditions.
 func (h *FilesHandler) HandleUpload(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
@@ -45,49 +91,117 @@ func (h *FilesHandler) HandleUpload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	r.Body = http.MaxBytesReader(w, r.Body, maxFileSize+1024) // extra for form overhead
-
-	if err := r.ParseMultipartForm(maxFileSize); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{
-			"error": fmt.Sprintf("file too large or invalid form: %v", err),
-		})
+	data, contentType, ok := readUpload(w, r, true)
+	if !ok {
 		return
 	}
 
-	file, header, err := r.FormFile("file")
-	if err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{
-			"error": fmt.Sprintf("missing file field: %v", err),
+	// Determine target space (community space)
+	spaceID := h.spaceManager.GetCommunitySpaceID()
+	if spaceID == "" {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "community space not configured",
 		})
 		return
 	}
-	defer file.Close()
 
-	// Validate content type
-	contentType := header.Header.Get("Content-Type")
-	if !strings.HasPrefix(contentType, "image/") {
+	// Load signing key for the space
+	signingKey := h.spaceManager.GetClient().GetSigningKey()
+	owner := uploadOwner(r)
+
+	// Render scaled copies; formats the standard library can't decode
+	// (WebP) are stored as uploaded
+	renditions, err := imaging.Render(data, h.renditions)
+	if err != nil && !errors.Is(err, imaging.ErrUnsupported) {
 		writeJSON(w, http.StatusBadRequest, map[string]string{
-			"error": "only image files are accepted",
+			"error": fmt.Sprintf("invalid image: %v", err),
 		})
 		return
 	}
+	total := int64(len(data))
+	for _, rendition := range renditions {
+		total += int64(len(rendition.Data))
+	}
 
-	// Read file content (need to know size for metadata)
-	data, err := io.ReadAll(io.LimitReader(file, maxFileSize+1))
+	if h.quota > 0 {
+		h.quotaMu.Lock()
+		defer h.quotaMu.Unlock()
+		used, err := h.fileManager.Usage(r.Context(), spaceID, owner, signingKey.GetPublic().Account())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"error": fmt.Sprintf("failed to check upload quota: %v", err),
+			})
+			return
+		}
+		if used+total > h.quota {
+			writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{
+				"error": fmt.Sprintf("upload quota exceeded: %d of %d bytes used, upload needs %d", used, h.quota, total),
+			})
+			return
+		}
+	}
+
+	// Upload renditions first so the original's metadata can list them
+	refs := make(map[string]string, len(renditions))
+	for _, rendition := range renditions {
+		ref, err := h.fileManager.AddFileWithMeta(r.Context(), spaceID, bytes.NewReader(rendition.Data), &anysync.FileMeta{
+			ContentType: rendition.ContentType,
+			Size:        int64(len(rendition.Data)),
+			Owner:       owner,
+			Rendition:   rendition.Name,
+		}, signingKey)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"error": fmt.Sprintf("failed to upload %s rendition: %v", rendition.Name, err),
+			})
+			return
+		}
+		refs[rendition.Name] = ref
+	}
+
+	// Upload to filenode
+	meta := &anysync.FileMeta{
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Owner:       owner,
+	}
+	if len(refs) > 0 {
+		meta.Renditions = refs
+	}
+	fileRef, err := h.fileManager.AddFileWithMeta(r.Context(), spaceID, bytes.NewReader(data), meta, signingKey)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{
-			"error": fmt.Sprintf("failed to read file: %v", err),
+			"error": fmt.Sprintf("failed to upload file: %v", err),
 		})
 		return
 	}
-	if len(data) > maxFileSize {
-		writeJSON(w, http.StatusBadRequest, map[string]string{
-			"error": "file exceeds 5MB limit",
+
+	resp := map[string]any{
+		"fileRef":     fileRef,
+		"contentType": contentType,
+		"size":        fmt.Sprintf("%d", len(data)),
+	}
+	if len(refs) > 0 {
+		resp["renditions"] = refs
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// HandleQuota handles GET /api/v1/files/quota, reporting how much of the
+// caller's upload quota is used. A quota of 0 means unlimited.
+func (h *FilesHandler) HandleQuota(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	if h.fileManager == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "file storage not available (filenode not configured)",
 		})
 		return
 	}
 
-	// Determine target space (community space)
 	spaceID := h.spaceManager.GetCommunitySpaceID()
 	if spaceID == "" {
 		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
@@ -96,32 +210,76 @@ func (h *FilesHandler) HandleUpload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Load signing key for the space
-	signingKey := h.spaceManager.GetClient().GetSigningKey()
-
-	// Upload to filenode
-	fileRef, err := h.fileManager.AddFile(
-		r.Context(),
-		spaceID,
-		bytes.NewReader(data),
-		contentType,
-		int64(len(data)),
-		signingKey,
-	)
+	owner := uploadOwner(r)
+	used, err := h.fileManager.Usage(r.Context(), spaceID, owner, h.spaceManager.GetClient().GetSigningKey().GetPublic().Account())
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{
-			"error": fmt.Sprintf("failed to upload file: %v", err),
+			"error": fmt.Sprintf("failed to read upload usage: %v", err),
 		})
 		return
 	}
-
-	writeJSON(w, http.StatusOK, map[string]string{
-		"fileRef":     fileRef,
-		"contentType": contentType,
-		"size":        fmt.Sprintf("%d", len(data)),
+	writeJSON(w, http.StatusOK, map[string]any{
+		"owner": owner,
+		"used":  used,
+		"quota": h.quota,
 	})
 }
 
+// HandleCache handles GET and DELETE /api/v1/admin/files/cache[/{ref}]
+// (admin). GET reports the local block cache's size and hit counts; DELETE
+// drops one block, or every block when no ref is given.
+func (h *FilesHandler) HandleCache(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodDelete {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	var cache *anysync.BlockCache
+	if h.fileManager != nil {
+		cache = h.fileManager.BlockCache()
+	}
+	if cache == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "block cache not enabled",
+		})
+		return
+	}
+
+	ref := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/admin/files/cache"), "/")
+	switch {
+	case r.Method == http.MethodGet && ref == "":
+		writeJSON(w, http.StatusOK, cache.Stats())
+	case r.Method == http.MethodGet:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	case ref == "":
+		cache.Clear()
+		fmt.Println("[Files] Block cache cleared")
+		writeJSON(w, http.StatusOK, cache.Stats())
+	default:
+		c, err := cid.Decode(ref)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid ref (not a valid CID)"})
+			return
+		}
+		cache.Invalidate(c)
+		writeJSON(w, http.StatusOK, cache.Stats())
+	}
+}
+
+// uploadOwner returns who an upload counts against: the caller's AID, or
+// the name of the API key or service token it used. Requests without a
+// principal (auth disabled) belong to the backend's own user, "".
+func uploadOwner(r *http.Request) string {
+	p := PrincipalFromContext(r.Context())
+	switch {
+	case p == nil:
+		return ""
+	case p.AID != "":
+		return p.AID
+	}
+	return p.Kind + ":" + p.Name
+}
+
 // HandleDownload handles GET /api/v1/files/{ref}
 // Returns the file bytes with appropriate Content-Type.
 func (h *FilesHandler) HandleDownload(w http.ResponseWriter, r *http.Request) {
@@ -160,6 +318,14 @@ func (h *FilesHandler) HandleDownload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// ?rendition=thumb serves a scaled copy, or the original if the image
+	// was small enough not to need one
+	if name := r.URL.Query().Get("rendition"); name != "" {
+		if meta, err := h.fileManager.GetFileMeta(r.Context(), spaceID, ref); err == nil && meta.Renditions[name] != "" {
+			ref = meta.Renditions[name]
+		}
+	}
+
 	// Fetch from filenode
 	reader, contentType, err := h.fileManager.GetFile(r.Context(), spaceID, ref)
 	if err != nil {
@@ -171,13 +337,94 @@ func (h *FilesHandler) HandleDownload(w http.ResponseWriter, r *http.Request) {
 	defer reader.Close()
 
 	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
 	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
 	w.WriteHeader(http.StatusOK)
 	io.Copy(w, reader)
 }
 
+// readUpload reads the "file" field of a multipart upload (max 5MB), writing
+// an error response and returning ok=false if it is missing or invalid. The
+// returned content type comes from sniffContentType.
+func readUpload(w http.ResponseWriter, r *http.Request, imagesOnly bool) (data []byte, contentType string, ok bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxFileSize+1024) // extra for form overhead
+
+	if err := r.ParseMultipartForm(maxFileSize); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("file too large or invalid form: %v", err),
+		})
+		return nil, "", false
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("missing file field: %v", err),
+		})
+		return nil, "", false
+	}
+	defer file.Close()
+
+	// Read file content (need to know size for metadata)
+	data, err = io.ReadAll(io.LimitReader(file, maxFileSize+1))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read file: %v", err),
+		})
+		return nil, "", false
+	}
+	if len(data) > maxFileSize {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "file exceeds 5MB limit",
+		})
+		return nil, "", false
+	}
+
+	// Validate content type against the file's magic bytes
+	contentType, err = sniffContentType(header.Header.Get("Content-Type"), data, imagesOnly)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return nil, "", false
+	}
+
+	return data, contentType, true
+}
+
+// sniffContentType checks an upload's declared Content-Type against its
+// magic bytes and returns the type to store. Content with a recognised
+// signature is stored as what it is; the declared type is only used when
+// the content has none, and never when it is an active type.
+func sniffContentType(declared string, data []byte, imagesOnly bool) (string, error) {
+	sniffed := http.DetectContentType(data)
+	sniffedType, _, _ := mime.ParseMediaType(sniffed)
+	if imagesOnly {
+		if !uploadImageTypes[sniffedType] {
+			return "", fmt.Errorf("only JPEG, PNG, GIF and WebP images are accepted (content is %s)", sniffedType)
+		}
+		return sniffedType, nil
+	}
+
+	if activeContentTypes[sniffedType] {
+		// Served back as text, so it can't run in the app's origin
+		return "text/plain; charset=utf-8", nil
+	}
+	if sniffedType != "application/octet-stream" && sniffedType != "text/plain" {
+		return sniffed, nil
+	}
+	declaredType, _, err := mime.ParseMediaType(declared)
+	if err != nil || activeContentTypes[declaredType] {
+		return sniffed, nil
+	}
+	return declared, nil
+}
+
 // RegisterRoutes registers file routes on the mux.
 func (h *FilesHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/v1/files/upload", h.HandleUpload)
+	mux.HandleFunc("/api/v1/files/quota", h.HandleQuota)
+	mux.HandleFunc("/api/v1/files/uploads", h.HandleUploads)
+	mux.HandleFunc("/api/v1/files/uploads/", h.HandleUploadSession)
 	mux.HandleFunc("/api/v1/files/", h.HandleDownload)
+	mux.HandleFunc("/api/v1/admin/files/cache", h.HandleCache)
+	mux.HandleFunc("/api/v1/admin/files/cache/", h.HandleCache)
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"mime/multipart"
 	"net/http"
@@ -191,3 +192,70 @@ func TestFilesHandler_RegisterRoutes(t *testing.T) {
 		t.Error("download route not registered")
 	}
 }
+
+func TestSniffContentType(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	html := []byte("<!DOCTYPE html><script>alert(1)</script>")
+	svg := []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`)
+
+	tests := []struct {
+		name       string
+		declared   string
+		data       []byte
+		imagesOnly bool
+		want       string
+		wantErr    bool
+	}{
+		{"image by content", "image/jpeg", png, true, "image/png", false},
+		{"html declared as image", "image/png", html, true, "", true},
+		{"svg avatar", "image/svg+xml", svg, true, "", true},
+		{"text declared as image", "image/png", []byte("fake-image-data"), true, "", true},
+		{"recognised content wins", "text/plain", png, false, "image/png", false},
+		{"html stored as text", "text/html", html, false, "text/plain; charset=utf-8", false},
+		{"declared type for unrecognised content", "text/csv", []byte("a,b\n1,2\n"), false, "text/csv", false},
+		{"active declared type ignored", "application/javascript", []byte("alert(1)"), false, "text/plain; charset=utf-8", false},
+		{"no declared type", "", []byte{0x00, 0x01, 0x02}, false, "application/octet-stream", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := sniffContentType(tc.declared, tc.data, tc.imagesOnly)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestUploadOwner(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/files/upload", nil)
+	if owner := uploadOwner(req); owner != "" {
+		t.Errorf("expected the backend's own user without a principal, got %q", owner)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), principalKey{}, &Principal{Kind: "token", AID: "EMember"}))
+	if owner := uploadOwner(req); owner != "EMember" {
+		t.Errorf("expected token holder's AID, got %q", owner)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), principalKey{}, &Principal{Kind: "apiKey", Name: "ops"}))
+	if owner := uploadOwner(req); owner != "apiKey:ops" {
+		t.Errorf("expected API key name, got %q", owner)
+	}
+}
+
+func TestFilesHandler_Cache(t *testing.T) {
+	handler := NewFilesHandler(nil, nil)
+
+	w := httptest.NewRecorder()
+	handler.HandleCache(w, httptest.NewRequest(http.MethodPost, "/api/v1/admin/files/cache", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleCache(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/files/cache", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without a block cache, got %d", w.Code)
+	}
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/matou-dao/backend/internal/flags"
+)
+
+// FlagsHandler exposes runtime feature flag management
+type FlagsHandler struct {
+	flags *flags.Flags
+}
+
+// NewFlagsHandler creates a new feature flags handler
+func NewFlagsHandler(f *flags.Flags) *FlagsHandler {
+	return &FlagsHandler{flags: f}
+}
+
+// SetFlagRequest is the body for PUT /api/v1/admin/flags/{name}
+type SetFlagRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// HandleFlags handles GET /api/v1/admin/flags
+func (h *FlagsHandler) HandleFlags(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"flags": h.flags.All(),
+	})
+}
+
+// HandleFlag handles PUT and DELETE /api/v1/admin/flags/{name}.
+// PUT sets a runtime override; DELETE reverts the flag to its configured default.
+func (h *FlagsHandler) HandleFlag(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/api/v1/admin/flags/")
+	if name == "" || strings.Contains(name, "/") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "flag name required",
+		})
+		return
+	}
+	if !flags.IsKnown(name) {
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			"error": fmt.Sprintf("unknown feature flag: %s", name),
+		})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut, http.MethodPost:
+		var req SetFlagRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("invalid request body: %v", err),
+			})
+			return
+		}
+		if err := h.flags.Set(name, req.Enabled); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"error": err.Error(),
+			})
+			return
+		}
+		fmt.Printf("[Flags] %s set to %t\n", name, req.Enabled)
+	case http.MethodDelete:
+		if err := h.flags.Reset(name); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"error": err.Error(),
+			})
+			return
+		}
+		fmt.Printf("[Flags] %s reset to default\n", name)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"name":    name,
+		"enabled": h.flags.Enabled(name),
+	})
+}
+
+// RegisterRoutes registers feature flag routes on the mux
+func (h *FlagsHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/admin/flags", h.HandleFlags)
+	mux.HandleFunc("/api/v1/admin/flags/", h.HandleFlag)
+}
+
+// RequireFeature wraps a handler so it returns 404 unless the named feature
+// flag is enabled. Use it when registering routes for experimental subsystems.
+func RequireFeature(f *flags.Flags, name string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !f.Enabled(name) {
+			writeJSON(w, http.StatusNotFound, map[string]string{
+				"error": fmt.Sprintf("feature %q is not enabled", name),
+			})
+			return
+		}
+		handler(w, r)
+	}
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/flags"
+)
+
+func TestRequireFeature(t *testing.T) {
+	f := flags.New(map[string]bool{flags.Governance: false}, t.TempDir())
+	handler := RequireFeature(f, flags.Governance, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/api/v1/grants", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 while the flag is disabled, got %d", w.Code)
+	}
+
+	if err := f.Set(flags.Governance, true); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/api/v1/grants", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected the handler once the flag is enabled, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	RequireFeature(nil, flags.Governance, handler)(w, httptest.NewRequest(http.MethodGet, "/api/v1/grants", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without flags, got %d", w.Code)
+	}
+}
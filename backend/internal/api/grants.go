@@ -0,0 +1,457 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+)
+
+// Grant request review states
+const (
+	GrantSubmitted = "submitted"
+	GrantInReview  = "in_review"
+	GrantApproved  = "approved"
+	GrantRejected  = "rejected"
+	GrantPaid      = "paid"
+)
+
+// maxGrantPurpose bounds the grant purpose length
+const maxGrantPurpose = 2000
+
+// grantTransitions lists the states a grant may move to from each state
+var grantTransitions = map[string][]string{
+	GrantSubmitted: {GrantInReview, GrantRejected},
+	GrantInReview:  {GrantApproved, GrantRejected},
+	GrantApproved:  {GrantPaid},
+}
+
+// GrantsHandler handles treasury grant requests. Requests live in the admin
+// space, so only stewards holding its keys can read or review them; each
+// change publishes a redacted GrantSummary to the community read-only space.
+type GrantsHandler struct {
+	spaceManager *anysync.SpaceManager
+	userIdentity *identity.UserIdentity
+	trust        *TrustHandler
+}
+
+// NewGrantsHandler creates a new grants handler
+func NewGrantsHandler(
+	spaceManager *anysync.SpaceManager,
+	userIdentity *identity.UserIdentity,
+	trustHandler *TrustHandler,
+) *GrantsHandler {
+	return &GrantsHandler{
+		spaceManager: spaceManager,
+		userIdentity: userIdentity,
+		trust:        trustHandler,
+	}
+}
+
+// GrantReview records one steward review step
+type GrantReview struct {
+	AID  string `json:"aid"`
+	From string `json:"from"`
+	To   string `json:"to"`
+	Note string `json:"note,omitempty"`
+	At   string `json:"at"`
+}
+
+// GrantRequest is the data stored for a GrantRequest object
+type GrantRequest struct {
+	Recipient   string        `json:"recipient"`
+	Amount      float64       `json:"amount"`
+	Currency    string        `json:"currency"`
+	Purpose     string        `json:"purpose"`
+	Status      string        `json:"status"`
+	ProposalID  string        `json:"proposalId,omitempty"` // Governance proposal that voted on the grant
+	Reviews     []GrantReview `json:"reviews,omitempty"`
+	SubmittedBy string        `json:"submittedBy"`
+	SubmittedAt string        `json:"submittedAt"`
+	UpdatedAt   string        `json:"updatedAt,omitempty"`
+}
+
+// GrantRequestView is a grant request with its object ID
+type GrantRequestView struct {
+	ID string `json:"id"`
+	GrantRequest
+}
+
+// GrantSummary is the redacted public view of a grant request
+type GrantSummary struct {
+	GrantID    string  `json:"grantId"`
+	Amount     float64 `json:"amount"`
+	Currency   string  `json:"currency"`
+	Purpose    string  `json:"purpose"`
+	Status     string  `json:"status"`
+	ProposalID string  `json:"proposalId,omitempty"`
+	UpdatedAt  string  `json:"updatedAt"`
+}
+
+// CreateGrantRequest is the body for POST /api/v1/grants
+type CreateGrantRequest struct {
+	Recipient  string  `json:"recipient"`
+	Amount     float64 `json:"amount"`
+	Currency   string  `json:"currency"`
+	Purpose    string  `json:"purpose"`
+	ProposalID string  `json:"proposalId,omitempty"`
+}
+
+// ReviewGrantRequest is the body for POST /api/v1/grants/{id}/review
+type ReviewGrantRequest struct {
+	Status     string `json:"status"`
+	Note       string `json:"note,omitempty"`
+	ProposalID string `json:"proposalId,omitempty"`
+}
+
+// Validate checks the grant request's fields
+func (g *GrantRequest) Validate() error {
+	if g.Recipient == "" {
+		return fmt.Errorf("recipient is required")
+	}
+	if g.Amount <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+	if g.Currency == "" {
+		return fmt.Errorf("currency is required")
+	}
+	if g.Purpose == "" {
+		return fmt.Errorf("purpose is required")
+	}
+	if len(g.Purpose) > maxGrantPurpose {
+		return fmt.Errorf("purpose must be at most %d characters", maxGrantPurpose)
+	}
+	return nil
+}
+
+// Summary returns the redacted public view of the grant
+func (g *GrantRequestView) Summary() *GrantSummary {
+	return &GrantSummary{
+		GrantID:    g.ID,
+		Amount:     g.Amount,
+		Currency:   g.Currency,
+		Purpose:    g.Purpose,
+		Status:     g.Status,
+		ProposalID: g.ProposalID,
+		UpdatedAt:  g.UpdatedAt,
+	}
+}
+
+// transition moves the grant to a new review state. Approval must be backed
+// by a governance proposal, either already linked or given here.
+func (g *GrantRequest) transition(reviewer string, req ReviewGrantRequest, now time.Time) (int, error) {
+	allowed := false
+	for _, s := range grantTransitions[g.Status] {
+		if s == req.Status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return http.StatusConflict, fmt.Errorf("cannot move grant from %s to %q", g.Status, req.Status)
+	}
+
+	if req.ProposalID != "" {
+		g.ProposalID = req.ProposalID
+	}
+	if req.Status == GrantApproved && g.ProposalID == "" {
+		return http.StatusBadRequest, fmt.Errorf("approval requires a governance proposalId")
+	}
+
+	g.Reviews = append(g.Reviews, GrantReview{
+		AID:  reviewer,
+		From: g.Status,
+		To:   req.Status,
+		Note: req.Note,
+		At:   now.UTC().Format(time.RFC3339),
+	})
+	g.Status = req.Status
+	return http.StatusOK, nil
+}
+
+// handleGrants routes /api/v1/grants
+func (h *GrantsHandler) handleGrants(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.HandleList(w, r)
+	case http.MethodPost:
+		h.HandleCreate(w, r)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// HandleList handles GET /api/v1/grants (steward)
+// Query params:
+//   - status: Only grants in this state (optional)
+func (h *GrantsHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	adminSpaceID := h.spaceManager.GetAdminSpaceID()
+	if adminSpaceID == "" {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "admin space not available"})
+		return
+	}
+
+	objects, err := readLatestObjects(r.Context(), h.spaceManager, adminSpaceID, "GrantRequest")
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read grants: %v", err),
+		})
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	grants := make([]*GrantRequestView, 0, len(objects))
+	for _, obj := range objects {
+		g := &GrantRequestView{ID: obj.ID}
+		if err := json.Unmarshal(obj.Data, &g.GrantRequest); err != nil {
+			continue
+		}
+		if status != "" && g.Status != status {
+			continue
+		}
+		grants = append(grants, g)
+	}
+	sort.Slice(grants, func(i, j int) bool {
+		return grants[i].SubmittedAt > grants[j].SubmittedAt
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"grants": grants,
+		"count":  len(grants),
+	})
+}
+
+// HandleCreate handles POST /api/v1/grants (steward)
+func (h *GrantsHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+
+	var req CreateGrantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	grant := &GrantRequestView{
+		ID: fmt.Sprintf("GrantRequest-%d", time.Now().UnixMilli()),
+		GrantRequest: GrantRequest{
+			Recipient:   req.Recipient,
+			Amount:      req.Amount,
+			Currency:    strings.ToUpper(strings.TrimSpace(req.Currency)),
+			Purpose:     strings.TrimSpace(req.Purpose),
+			Status:      GrantSubmitted,
+			ProposalID:  req.ProposalID,
+			SubmittedBy: me,
+			SubmittedAt: now,
+		},
+	}
+	if err := grant.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+	if status, err := h.checkRecipient(ctx, req.Recipient); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if status, err := h.saveGrant(ctx, grant); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	fmt.Printf("[Grants] %s submitted %s (%.2f %s)\n", me, grant.ID, grant.Amount, grant.Currency)
+	writeJSON(w, http.StatusCreated, grant)
+}
+
+// handleGrant routes /api/v1/grants/{id}[/review] and /api/v1/grants/summaries
+func (h *GrantsHandler) handleGrant(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/grants/")
+	parts := strings.Split(path, "/")
+	if parts[0] == "" || len(parts) > 2 {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+
+	switch {
+	case parts[0] == "summaries" && len(parts) == 1:
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		h.handleSummaries(w, r)
+	case len(parts) == 1:
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		h.handleGet(w, r, parts[0])
+	case parts[1] == "review":
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		h.handleReview(w, r, parts[0])
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
+
+// handleGet handles GET /api/v1/grants/{id} (steward)
+func (h *GrantsHandler) handleGet(w http.ResponseWriter, r *http.Request, id string) {
+	grant, status, err := h.loadGrant(r.Context(), id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, grant)
+}
+
+// handleReview handles POST /api/v1/grants/{id}/review (steward)
+func (h *GrantsHandler) handleReview(w http.ResponseWriter, r *http.Request, id string) {
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+
+	var req ReviewGrantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+
+	ctx := r.Context()
+	grant, status, err := h.loadGrant(ctx, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	from := grant.Status
+	if status, err := grant.transition(me, req, time.Now()); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if status, err := h.saveGrant(ctx, grant); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	fmt.Printf("[Grants] %s moved %s from %s to %s\n", me, grant.ID, from, grant.Status)
+	writeJSON(w, http.StatusOK, grant)
+}
+
+// handleSummaries handles GET /api/v1/grants/summaries. Any member can read
+// the redacted summaries from the community read-only space.
+func (h *GrantsHandler) handleSummaries(w http.ResponseWriter, r *http.Request) {
+	summaries := make([]*GrantSummary, 0)
+
+	roSpaceID := h.spaceManager.GetCommunityReadOnlySpaceID()
+	if roSpaceID != "" {
+		objects, err := readLatestObjects(r.Context(), h.spaceManager, roSpaceID, "GrantSummary")
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"error": fmt.Sprintf("failed to read grant summaries: %v", err),
+			})
+			return
+		}
+		for _, obj := range objects {
+			var s GrantSummary
+			if err := json.Unmarshal(obj.Data, &s); err != nil {
+				continue
+			}
+			summaries = append(summaries, &s)
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].UpdatedAt > summaries[j].UpdatedAt
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"summaries": summaries,
+		"count":     len(summaries),
+	})
+}
+
+// checkRecipient verifies the grant recipient is a credentialed member
+func (h *GrantsHandler) checkRecipient(ctx context.Context, aid string) (int, error) {
+	if h.trust == nil {
+		return http.StatusOK, nil
+	}
+
+	graph, err := h.trust.buildGraph(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to build trust graph: %v", err)
+	}
+	node := graph.GetNode(aid)
+	if node == nil || !node.IsMember() {
+		return http.StatusBadRequest, fmt.Errorf("%s is not a community member", aid)
+	}
+	return http.StatusOK, nil
+}
+
+// loadGrant reads the latest version of a grant request
+func (h *GrantsHandler) loadGrant(ctx context.Context, id string) (*GrantRequestView, int, error) {
+	adminSpaceID := h.spaceManager.GetAdminSpaceID()
+	if adminSpaceID == "" {
+		return nil, http.StatusForbidden, fmt.Errorf("admin space not available")
+	}
+
+	obj, err := h.spaceManager.ObjectTreeManager().ReadLatestByID(ctx, adminSpaceID, id)
+	if err != nil || obj.Type != "GrantRequest" {
+		return nil, http.StatusNotFound, fmt.Errorf("grant request not found")
+	}
+
+	grant := &GrantRequestView{ID: obj.ID}
+	if err := json.Unmarshal(obj.Data, &grant.GrantRequest); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("invalid grant request: %v", err)
+	}
+	return grant, http.StatusOK, nil
+}
+
+// saveGrant writes a new version of a grant request to the admin space and
+// republishes its redacted summary. A failed summary write is only logged;
+// the next change will publish it again.
+func (h *GrantsHandler) saveGrant(ctx context.Context, grant *GrantRequestView) (int, error) {
+	adminSpaceID := h.spaceManager.GetAdminSpaceID()
+	if adminSpaceID == "" {
+		return http.StatusForbidden, fmt.Errorf("admin space not available")
+	}
+	grant.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	if _, err := writeObject(ctx, h.spaceManager, adminSpaceID, grant.ID, "GrantRequest", grant.GrantRequest); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	roSpaceID := h.spaceManager.GetCommunityReadOnlySpaceID()
+	if roSpaceID == "" {
+		fmt.Printf("[Grants] Warning: community-readonly space not configured, %s summary not published\n", grant.ID)
+		return http.StatusOK, nil
+	}
+	if _, err := writeObject(ctx, h.spaceManager, roSpaceID, "GrantSummary-"+grant.ID, "GrantSummary", grant.Summary()); err != nil {
+		fmt.Printf("[Grants] Warning: failed to publish %s summary: %v\n", grant.ID, err)
+	}
+	return http.StatusOK, nil
+}
+
+// RegisterRoutes registers grant routes on the mux
+func (h *GrantsHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/grants", h.handleGrants)
+	mux.HandleFunc("/api/v1/grants/", h.handleGrant)
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+func TestGrantCreate_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		aid        string
+		body       string
+		wantStatus int
+	}{
+		{"no identity", "", `{"recipient":"EBOB","amount":500,"currency":"NZD","purpose":"Seeds"}`, http.StatusBadRequest},
+		{"missing recipient", "EALICE", `{"amount":500,"currency":"NZD","purpose":"Seeds"}`, http.StatusBadRequest},
+		{"zero amount", "EALICE", `{"recipient":"EBOB","amount":0,"currency":"NZD","purpose":"Seeds"}`, http.StatusBadRequest},
+		{"missing currency", "EALICE", `{"recipient":"EBOB","amount":500,"purpose":"Seeds"}`, http.StatusBadRequest},
+		{"missing purpose", "EALICE", `{"recipient":"EBOB","amount":500,"currency":"NZD","purpose":" "}`, http.StatusBadRequest},
+		{"invalid json", "EALICE", `{`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "grants_test")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			userIdentity := identity.New(tmpDir)
+			if tt.aid != "" {
+				if err := userIdentity.SetIdentity(tt.aid, secret.NewMnemonic("test mnemonic")); err != nil {
+					t.Fatalf("SetIdentity failed: %v", err)
+				}
+			}
+			handler := NewGrantsHandler(nil, userIdentity, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/grants", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			handler.handleGrants(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGrantRequest_Transition(t *testing.T) {
+	now := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		grant      GrantRequest
+		req        ReviewGrantRequest
+		wantStatus int
+	}{
+		{"start review", GrantRequest{Status: GrantSubmitted}, ReviewGrantRequest{Status: GrantInReview}, http.StatusOK},
+		{"reject submitted", GrantRequest{Status: GrantSubmitted}, ReviewGrantRequest{Status: GrantRejected}, http.StatusOK},
+		{"skip review", GrantRequest{Status: GrantSubmitted}, ReviewGrantRequest{Status: GrantApproved, ProposalID: "P1"}, http.StatusConflict},
+		{"approve without proposal", GrantRequest{Status: GrantInReview}, ReviewGrantRequest{Status: GrantApproved}, http.StatusBadRequest},
+		{"approve with proposal", GrantRequest{Status: GrantInReview}, ReviewGrantRequest{Status: GrantApproved, ProposalID: "P1"}, http.StatusOK},
+		{"approve linked proposal", GrantRequest{Status: GrantInReview, ProposalID: "P1"}, ReviewGrantRequest{Status: GrantApproved}, http.StatusOK},
+		{"pay approved", GrantRequest{Status: GrantApproved, ProposalID: "P1"}, ReviewGrantRequest{Status: GrantPaid}, http.StatusOK},
+		{"reopen rejected", GrantRequest{Status: GrantRejected}, ReviewGrantRequest{Status: GrantInReview}, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grant := tt.grant
+			from := grant.Status
+			status, _ := grant.transition("ESTEWARD", tt.req, now)
+			if status != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, status)
+			}
+			if status != http.StatusOK {
+				if grant.Status != from || len(grant.Reviews) != 0 {
+					t.Error("rejected transition should leave the grant unchanged")
+				}
+				return
+			}
+			if grant.Status != tt.req.Status || len(grant.Reviews) != 1 || grant.Reviews[0].From != from {
+				t.Errorf("unexpected grant after transition: %+v", grant)
+			}
+		})
+	}
+}
+
+func TestGrantRequestView_Summary(t *testing.T) {
+	grant := &GrantRequestView{
+		ID: "GrantRequest-1",
+		GrantRequest: GrantRequest{
+			Recipient: "EBOB",
+			Amount:    500,
+			Currency:  "NZD",
+			Purpose:   "Seeds for the community garden",
+			Status:    GrantApproved,
+			Reviews:   []GrantReview{{AID: "ESTEWARD", Note: "private note"}},
+		},
+	}
+
+	summary := grant.Summary()
+	if summary.GrantID != grant.ID || summary.Amount != 500 || summary.Status != GrantApproved {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
@@ -0,0 +1,988 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/trust"
+)
+
+const (
+	// maxGraphQLDepth bounds how deeply selections may nest. Introspection
+	// fields are not counted.
+	maxGraphQLDepth = 8
+	// maxGraphQLResults bounds how many list items one query may resolve,
+	// summed over every list field, so nested lists can't fan out without
+	// limit
+	maxGraphQLResults = 10000
+	// defaultGraphQLLimit and maxGraphQLLimit are the default and largest
+	// page size of a list field
+	defaultGraphQLLimit = 50
+	maxGraphQLLimit     = 500
+)
+
+// GraphQLHandler serves trust graph queries over GraphQL: nodes, edges,
+// scores, credentials and endorsements, with filters and nested
+// resolution. Each request builds the graph at most once, however many
+// fields read it.
+type GraphQLHandler struct {
+	trust *TrustHandler
+}
+
+// NewGraphQLHandler creates a GraphQL handler over an org's trust graph
+func NewGraphQLHandler(trustHandler *TrustHandler) *GraphQLHandler {
+	return &GraphQLHandler{trust: trustHandler}
+}
+
+// GraphQLRequest is a GraphQL request. GET requests carry the same fields
+// as query parameters, with variables JSON-encoded.
+type GraphQLRequest struct {
+	Query         string                 `json:"query" validate:"required,max=20000"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// HandleQuery handles GET and POST /api/v1/graphql
+// Query params:
+//   - algorithm: Scoring algorithm for scores and the summary (optional,
+//     default: the org's configured algorithm)
+func (h *GraphQLHandler) HandleQuery(w http.ResponseWriter, r *http.Request) {
+	var req GraphQLRequest
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if v := query.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				writeProblem(w, http.StatusBadRequest, "", FieldError{Field: "variables", Message: "must be a JSON object"})
+				return
+			}
+		}
+		if !validRequest(w, &req) {
+			return
+		}
+	case http.MethodPost:
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	calculator, err := h.trust.requestCalculator(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+	q := &graphQuery{
+		calculator: calculator,
+		loadGraph: func() (*trust.Graph, error) {
+			graph, err := h.trust.buildGraph(ctx)
+			if err != nil {
+				return nil, err
+			}
+			h.trust.addContributions(ctx, graph)
+			return graph, nil
+		},
+		loadCredentials: func() ([]*anystore.CachedCredential, error) {
+			return h.trust.allCredentials(ctx)
+		},
+	}
+	result, ok := executeGraphQL(ctx, &req, q)
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, status, result)
+}
+
+// RegisterRoutes registers GraphQL routes on the mux
+func (h *GraphQLHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/graphql", h.HandleQuery)
+}
+
+// executeGraphQL runs a request against q. ok is false when the request
+// was refused before execution: unparseable, invalid against the schema or
+// nested too deeply.
+func executeGraphQL(ctx context.Context, req *GraphQLRequest, q *graphQuery) (*graphql.Result, bool) {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, false
+	}
+	if v := graphql.ValidateDocument(&graphQLSchema, doc, nil); !v.IsValid {
+		return &graphql.Result{Errors: v.Errors}, false
+	}
+	if depth := selectionDepth(doc); depth > maxGraphQLDepth {
+		msg := fmt.Sprintf("query nests %d levels deep; the limit is %d", depth, maxGraphQLDepth)
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(msg)}}, false
+	}
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        graphQLSchema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       context.WithValue(ctx, graphQueryKey{}, q),
+	}), true
+}
+
+// selectionDepth returns how deeply a document's selections nest, following
+// fragment spreads and skipping introspection fields
+func selectionDepth(doc *ast.Document) int {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		if frag, ok := def.(*ast.FragmentDefinition); ok {
+			fragments[frag.Name.Value] = frag
+		}
+	}
+
+	var depthOf func(set *ast.SelectionSet, visiting map[string]bool) int
+	depthOf = func(set *ast.SelectionSet, visiting map[string]bool) int {
+		if set == nil {
+			return 0
+		}
+		deepest := 0
+		for _, sel := range set.Selections {
+			d := 0
+			switch sel := sel.(type) {
+			case *ast.Field:
+				if strings.HasPrefix(sel.Name.Value, "__") {
+					continue
+				}
+				d = 1 + depthOf(sel.SelectionSet, visiting)
+			case *ast.InlineFragment:
+				d = depthOf(sel.SelectionSet, visiting)
+			case *ast.FragmentSpread:
+				name := sel.Name.Value
+				if frag := fragments[name]; frag != nil && !visiting[name] {
+					visiting[name] = true
+					d = depthOf(frag.SelectionSet, visiting)
+					delete(visiting, name)
+				}
+			}
+			if d > deepest {
+				deepest = d
+			}
+		}
+		return deepest
+	}
+
+	deepest := 0
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok {
+			if d := depthOf(op.SelectionSet, map[string]bool{}); d > deepest {
+				deepest = d
+			}
+		}
+	}
+	return deepest
+}
+
+// graphQueryKey is the context key for the request's graphQuery
+type graphQueryKey struct{}
+
+// graphQuery is the data one GraphQL request resolves against. The graph,
+// scores and credentials are loaded on first use and shared by every field.
+type graphQuery struct {
+	calculator      *trust.Calculator
+	loadGraph       func() (*trust.Graph, error)
+	loadCredentials func() ([]*anystore.CachedCredential, error)
+
+	mu          sync.Mutex
+	graph       *trust.Graph
+	scores      map[string]*trust.Score
+	credentials []*anystore.CachedCredential
+	bySAID      map[string]*anystore.CachedCredential
+	distances   map[string]map[string]int
+	resolved    int
+}
+
+func queryFrom(p graphql.ResolveParams) *graphQuery {
+	return p.Context.Value(graphQueryKey{}).(*graphQuery)
+}
+
+// trustGraph returns the request's graph, building it on first use
+func (q *graphQuery) trustGraph() (*trust.Graph, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.graph == nil {
+		graph, err := q.loadGraph()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build trust graph: %w", err)
+		}
+		q.graph = graph
+	}
+	return q.graph, nil
+}
+
+// score returns an AID's trust score, or nil for AIDs outside the graph
+func (q *graphQuery) score(aid string) (*trust.Score, error) {
+	graph, err := q.trustGraph()
+	if err != nil {
+		return nil, err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.scores == nil {
+		q.scores = q.calculator.CalculateAllScores(graph)
+	}
+	return q.scores[aid], nil
+}
+
+// distancesFrom returns the hop count from aid to every AID it reaches
+func (q *graphQuery) distancesFrom(aid string) (map[string]int, error) {
+	graph, err := q.trustGraph()
+	if err != nil {
+		return nil, err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.distances == nil {
+		q.distances = make(map[string]map[string]int)
+	}
+	if _, ok := q.distances[aid]; !ok {
+		q.distances[aid] = graph.Distances(aid)
+	}
+	return q.distances[aid], nil
+}
+
+// allCredentials returns the credentials behind the graph
+func (q *graphQuery) allCredentials() ([]*anystore.CachedCredential, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.bySAID == nil {
+		creds, err := q.loadCredentials()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read credentials: %w", err)
+		}
+		q.credentials = creds
+		q.bySAID = make(map[string]*anystore.CachedCredential, len(creds))
+		for _, c := range creds {
+			q.bySAID[c.ID] = c
+		}
+	}
+	return q.credentials, nil
+}
+
+// credential returns a credential by SAID, or nil
+func (q *graphQuery) credential(said string) (*anystore.CachedCredential, error) {
+	if _, err := q.allCredentials(); err != nil {
+		return nil, err
+	}
+	return q.bySAID[said], nil
+}
+
+// node returns the graph node for an AID, or a bare node carrying only the
+// AID when the graph doesn't hold it
+func (q *graphQuery) node(aid string) (*trust.Node, error) {
+	graph, err := q.trustGraph()
+	if err != nil {
+		return nil, err
+	}
+	if node := graph.GetNode(aid); node != nil {
+		return node, nil
+	}
+	return &trust.Node{AID: aid}, nil
+}
+
+// take charges n list items to the query's result budget
+func (q *graphQuery) take(n int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.resolved += n
+	if q.resolved > maxGraphQLResults {
+		return fmt.Errorf("query resolves more than %d list items; narrow it with filters or limit", maxGraphQLResults)
+	}
+	return nil
+}
+
+// endorsement is an endorsement edge with the category and time from its
+// credential
+type endorsement struct {
+	edge       *trust.Edge
+	category   string
+	endorsedAt time.Time
+}
+
+// endorsements returns the graph's endorsement edges that match a filter
+func (q *graphQuery) endorsements(match func(*trust.Edge) bool, category string) ([]*endorsement, error) {
+	graph, err := q.trustGraph()
+	if err != nil {
+		return nil, err
+	}
+	var result []*endorsement
+	for _, edge := range graph.Edges {
+		if edge.Type != trust.EdgeTypeEndorsement || !match(edge) {
+			continue
+		}
+		e := &endorsement{edge: edge, endorsedAt: edge.CreatedAt}
+		cred, err := q.credential(edge.CredentialID)
+		if err != nil {
+			return nil, err
+		}
+		if cred != nil {
+			if data, ok := cred.Data.(map[string]interface{}); ok {
+				e.category, _ = data["category"].(string)
+				if at, ok := data["endorsedAt"].(string); ok {
+					if t, err := time.Parse(time.RFC3339, at); err == nil {
+						e.endorsedAt = t
+					}
+				}
+			}
+		}
+		if category != "" && !strings.EqualFold(e.category, category) {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+// nodeFilter is the filter arguments shared by nodes and neighbors
+type nodeFilter struct {
+	role        string
+	membersOnly bool
+	minScore    *float64
+	maxScore    *float64
+	near        string
+	maxDepth    int
+}
+
+func nodeFilterFrom(args map[string]interface{}) nodeFilter {
+	f := nodeFilter{}
+	f.role, _ = args["role"].(string)
+	f.membersOnly, _ = args["membersOnly"].(bool)
+	if v, ok := args["minScore"].(float64); ok {
+		f.minScore = &v
+	}
+	if v, ok := args["maxScore"].(float64); ok {
+		f.maxScore = &v
+	}
+	f.near, _ = args["near"].(string)
+	f.maxDepth, _ = args["maxDepth"].(int)
+	return f
+}
+
+// filterNodes returns the graph nodes that match f, highest score first
+func (q *graphQuery) filterNodes(f nodeFilter) ([]*trust.Node, error) {
+	graph, err := q.trustGraph()
+	if err != nil {
+		return nil, err
+	}
+	var distances map[string]int
+	if f.near != "" {
+		if distances, err = q.distancesFrom(f.near); err != nil {
+			return nil, err
+		}
+	}
+
+	scores := make(map[string]float64)
+	var result []*trust.Node
+	for aid, node := range graph.Nodes {
+		if f.role != "" && !strings.EqualFold(node.Role, f.role) {
+			continue
+		}
+		if f.membersOnly && !node.IsMember() {
+			continue
+		}
+		if distances != nil {
+			d, ok := distances[aid]
+			if !ok || aid == f.near || (f.maxDepth > 0 && d > f.maxDepth) {
+				continue
+			}
+		}
+		score, err := q.score(aid)
+		if err != nil {
+			return nil, err
+		}
+		if score != nil {
+			scores[aid] = score.Score
+		}
+		if f.minScore != nil && scores[aid] < *f.minScore {
+			continue
+		}
+		if f.maxScore != nil && scores[aid] > *f.maxScore {
+			continue
+		}
+		result = append(result, node)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if scores[result[i].AID] != scores[result[j].AID] {
+			return scores[result[i].AID] > scores[result[j].AID]
+		}
+		return result[i].AID < result[j].AID
+	})
+	return result, nil
+}
+
+// page returns the bounds of the page of n items a list field's offset
+// and limit arguments select, and charges its items to the query's budget
+func (q *graphQuery) page(n int, args map[string]interface{}) (int, int, error) {
+	offset, _ := args["offset"].(int)
+	limit, ok := args["limit"].(int)
+	if !ok {
+		limit = defaultGraphQLLimit
+	}
+	if offset < 0 || limit < 0 {
+		return 0, 0, fmt.Errorf("offset and limit must not be negative")
+	}
+	if limit > maxGraphQLLimit {
+		return 0, 0, fmt.Errorf("limit must be at most %d", maxGraphQLLimit)
+	}
+	start := offset
+	if start > n {
+		start = n
+	}
+	end := start + limit
+	if end > n {
+		end = n
+	}
+	if err := q.take(end - start); err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// filterEdges returns the graph edges that match the edge filter arguments
+func (q *graphQuery) filterEdges(args map[string]interface{}, match func(*trust.Edge) bool) ([]*trust.Edge, error) {
+	graph, err := q.trustGraph()
+	if err != nil {
+		return nil, err
+	}
+	edgeType, _ := args["type"].(string)
+	from, _ := args["from"].(string)
+	to, _ := args["to"].(string)
+	var result []*trust.Edge
+	for _, edge := range graph.Edges {
+		if (edgeType != "" && edge.Type != edgeType) || (from != "" && edge.From != from) || (to != "" && edge.To != to) {
+			continue
+		}
+		if match != nil && !match(edge) {
+			continue
+		}
+		result = append(result, edge)
+	}
+	start, end, err := q.page(len(result), args)
+	if err != nil {
+		return nil, err
+	}
+	return result[start:end], nil
+}
+
+// filterCredentials returns the credentials that match the credential
+// filter arguments, or the issuer and subject given in their place
+func (q *graphQuery) filterCredentials(args map[string]interface{}, issuer, subject string) ([]*anystore.CachedCredential, error) {
+	creds, err := q.allCredentials()
+	if err != nil {
+		return nil, err
+	}
+	if issuer == "" {
+		issuer, _ = args["issuer"].(string)
+	}
+	if subject == "" {
+		subject, _ = args["subject"].(string)
+	}
+	schema, _ := args["schema"].(string)
+	var result []*anystore.CachedCredential
+	for _, c := range creds {
+		if (issuer != "" && c.IssuerAID != issuer) || (subject != "" && c.SubjectAID != subject) || (schema != "" && c.SchemaID != schema) {
+			continue
+		}
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	start, end, err := q.page(len(result), args)
+	if err != nil {
+		return nil, err
+	}
+	return result[start:end], nil
+}
+
+// nullableTime returns t, or nil for the zero time
+func nullableTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// jsonScalar passes arbitrary JSON values, such as credential data,
+// through unchanged
+var jsonScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "JSON",
+	Description: "An arbitrary JSON value",
+	Serialize:   func(value interface{}) interface{} { return value },
+	ParseValue:  func(value interface{}) interface{} { return value },
+	ParseLiteral: func(value ast.Value) interface{} {
+		return value.GetValue()
+	},
+})
+
+// graphQLSchema is the trust graph schema. See docs/API.md for a
+// description of each type.
+var graphQLSchema = newGraphQLSchema()
+
+func newGraphQLSchema() graphql.Schema {
+	pageArgs := func(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+		args["limit"] = &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultGraphQLLimit}
+		args["offset"] = &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0}
+		return args
+	}
+	nodeArgs := func() graphql.FieldConfigArgument {
+		return pageArgs(graphql.FieldConfigArgument{
+			"role":        &graphql.ArgumentConfig{Type: graphql.String},
+			"membersOnly": &graphql.ArgumentConfig{Type: graphql.Boolean},
+			"minScore":    &graphql.ArgumentConfig{Type: graphql.Float},
+			"maxScore":    &graphql.ArgumentConfig{Type: graphql.Float},
+			"maxDepth":    &graphql.ArgumentConfig{Type: graphql.Int},
+		})
+	}
+	edgeArgs := func() graphql.FieldConfigArgument {
+		return pageArgs(graphql.FieldConfigArgument{
+			"type": &graphql.ArgumentConfig{Type: graphql.String},
+		})
+	}
+
+	scoreType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Score",
+		Fields: graphql.Fields{
+			"aid":                      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"alias":                    &graphql.Field{Type: graphql.String},
+			"role":                     &graphql.Field{Type: graphql.String},
+			"score":                    &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"incomingCredentials":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"participationCredentials": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"federatedCredentials":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"contributions":            &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"endorsements":             &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"outgoingCredentials":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"uniqueIssuers":            &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"bidirectionalRelations":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"graphDepth":               &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	summaryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "TrustSummary",
+		Fields: graphql.Fields{
+			"totalNodes":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"totalEdges":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"averageScore":       &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"maxScore":           &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"minScore":           &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"medianDepth":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"bidirectionalCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"algorithm":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	// Node, Edge, Credential and Endorsement refer to each other, so their
+	// fields are declared once all four exist
+	nodeType := graphql.NewObject(graphql.ObjectConfig{Name: "Node", Fields: graphql.Fields{}})
+	edgeType := graphql.NewObject(graphql.ObjectConfig{Name: "Edge", Fields: graphql.Fields{}})
+	credentialType := graphql.NewObject(graphql.ObjectConfig{Name: "Credential", Fields: graphql.Fields{}})
+	endorsementType := graphql.NewObject(graphql.ObjectConfig{Name: "Endorsement", Fields: graphql.Fields{}})
+
+	nodeFor := func(aid string) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			return queryFrom(p).node(aid)
+		}
+	}
+	credentialFor := func(p graphql.ResolveParams, said string) (interface{}, error) {
+		cred, err := queryFrom(p).credential(said)
+		if cred == nil || err != nil {
+			return nil, err
+		}
+		return cred, nil
+	}
+	endorsementsMatching := func(match func(p graphql.ResolveParams, e *trust.Edge) bool) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			q := queryFrom(p)
+			category, _ := p.Args["category"].(string)
+			endorsements, err := q.endorsements(func(e *trust.Edge) bool { return match(p, e) }, category)
+			if err != nil {
+				return nil, err
+			}
+			if minConfidence, ok := p.Args["minConfidence"].(float64); ok {
+				kept := endorsements[:0]
+				for _, e := range endorsements {
+					if e.edge.Strength() >= minConfidence {
+						kept = append(kept, e)
+					}
+				}
+				endorsements = kept
+			}
+			start, end, err := q.page(len(endorsements), p.Args)
+			if err != nil {
+				return nil, err
+			}
+			return endorsements[start:end], nil
+		}
+	}
+	endorsementArgs := func(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+		args["category"] = &graphql.ArgumentConfig{Type: graphql.String}
+		args["minConfidence"] = &graphql.ArgumentConfig{Type: graphql.Float}
+		return pageArgs(args)
+	}
+
+	nodeFields := graphql.Fields{
+		"aid":             &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"alias":           &graphql.Field{Type: graphql.String},
+		"role":            &graphql.Field{Type: graphql.String},
+		"credentialCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"contributions":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"attributes":      &graphql.Field{Type: jsonScalar},
+		"isMember": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Boolean),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*trust.Node).IsMember(), nil
+			},
+		},
+		"joinedAt": &graphql.Field{
+			Type: graphql.DateTime,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return nullableTime(p.Source.(*trust.Node).JoinedAt), nil
+			},
+		},
+		"score": &graphql.Field{
+			Type: scoreType,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				score, err := queryFrom(p).score(p.Source.(*trust.Node).AID)
+				if score == nil || err != nil {
+					return nil, err
+				}
+				return score, nil
+			},
+		},
+		"distance": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "Hops from another AID, or null if it can't be reached",
+			Args: graphql.FieldConfigArgument{
+				"from": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				distances, err := queryFrom(p).distancesFrom(p.Args["from"].(string))
+				if err != nil {
+					return nil, err
+				}
+				if d, ok := distances[p.Source.(*trust.Node).AID]; ok {
+					return d, nil
+				}
+				return nil, nil
+			},
+		},
+		"incoming": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edgeType))),
+			Args: edgeArgs(),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				aid := p.Source.(*trust.Node).AID
+				return queryFrom(p).filterEdges(p.Args, func(e *trust.Edge) bool { return e.To == aid })
+			},
+		},
+		"outgoing": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edgeType))),
+			Args: edgeArgs(),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				aid := p.Source.(*trust.Node).AID
+				return queryFrom(p).filterEdges(p.Args, func(e *trust.Edge) bool { return e.From == aid })
+			},
+		},
+		"neighbors": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(nodeType))),
+			Description: "Nodes within maxDepth hops (default 1), highest score first",
+			Args:        nodeArgs(),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				q := queryFrom(p)
+				f := nodeFilterFrom(p.Args)
+				f.near = p.Source.(*trust.Node).AID
+				if f.maxDepth == 0 {
+					f.maxDepth = 1
+				}
+				nodes, err := q.filterNodes(f)
+				if err != nil {
+					return nil, err
+				}
+				start, end, err := q.page(len(nodes), p.Args)
+				if err != nil {
+					return nil, err
+				}
+				return nodes[start:end], nil
+			},
+		},
+		"credentials": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(credentialType))),
+			Description: "Credentials the node holds",
+			Args:        pageArgs(graphql.FieldConfigArgument{"schema": &graphql.ArgumentConfig{Type: graphql.String}}),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return queryFrom(p).filterCredentials(p.Args, "", p.Source.(*trust.Node).AID)
+			},
+		},
+		"issuedCredentials": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(credentialType))),
+			Description: "Credentials the node issued",
+			Args:        pageArgs(graphql.FieldConfigArgument{"schema": &graphql.ArgumentConfig{Type: graphql.String}}),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return queryFrom(p).filterCredentials(p.Args, p.Source.(*trust.Node).AID, "")
+			},
+		},
+		"endorsementsReceived": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(endorsementType))),
+			Args: endorsementArgs(graphql.FieldConfigArgument{}),
+			Resolve: endorsementsMatching(func(p graphql.ResolveParams, e *trust.Edge) bool {
+				return e.To == p.Source.(*trust.Node).AID
+			}),
+		},
+		"endorsementsGiven": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(endorsementType))),
+			Args: endorsementArgs(graphql.FieldConfigArgument{}),
+			Resolve: endorsementsMatching(func(p graphql.ResolveParams, e *trust.Edge) bool {
+				return e.From == p.Source.(*trust.Node).AID
+			}),
+		},
+	}
+	for name, field := range nodeFields {
+		nodeType.AddFieldConfig(name, field)
+	}
+
+	edgeFields := graphql.Fields{
+		"credentialId":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"type":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"bidirectional": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		"confidence":    &graphql.Field{Type: graphql.Float},
+		"from": &graphql.Field{
+			Type: graphql.NewNonNull(nodeType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return nodeFor(p.Source.(*trust.Edge).From)(p)
+			},
+		},
+		"to": &graphql.Field{
+			Type: graphql.NewNonNull(nodeType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return nodeFor(p.Source.(*trust.Edge).To)(p)
+			},
+		},
+		"strength": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "The share of a full credential's weight the edge carries",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*trust.Edge).Strength(), nil
+			},
+		},
+		"createdAt": &graphql.Field{
+			Type: graphql.DateTime,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return nullableTime(p.Source.(*trust.Edge).CreatedAt), nil
+			},
+		},
+		"credential": &graphql.Field{
+			Type: credentialType,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return credentialFor(p, p.Source.(*trust.Edge).CredentialID)
+			},
+		},
+	}
+	for name, field := range edgeFields {
+		edgeType.AddFieldConfig(name, field)
+	}
+
+	credentialFields := graphql.Fields{
+		"data":     &graphql.Field{Type: jsonScalar},
+		"verified": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		"said": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*anystore.CachedCredential).ID, nil
+			},
+		},
+		"schema": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*anystore.CachedCredential).SchemaID, nil
+			},
+		},
+		"issuer": &graphql.Field{
+			Type: graphql.NewNonNull(nodeType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return nodeFor(p.Source.(*anystore.CachedCredential).IssuerAID)(p)
+			},
+		},
+		"subject": &graphql.Field{
+			Type: graphql.NewNonNull(nodeType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return nodeFor(p.Source.(*anystore.CachedCredential).SubjectAID)(p)
+			},
+		},
+	}
+	for name, field := range credentialFields {
+		credentialType.AddFieldConfig(name, field)
+	}
+
+	endorsementFields := graphql.Fields{
+		"credentialId": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*endorsement).edge.CredentialID, nil
+			},
+		},
+		"category": &graphql.Field{
+			Type: graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if category := p.Source.(*endorsement).category; category != "" {
+					return category, nil
+				}
+				return nil, nil
+			},
+		},
+		"confidence": &graphql.Field{
+			Type: graphql.Float,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*endorsement).edge.Confidence, nil
+			},
+		},
+		"endorsedAt": &graphql.Field{
+			Type: graphql.DateTime,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return nullableTime(p.Source.(*endorsement).endorsedAt), nil
+			},
+		},
+		"endorser": &graphql.Field{
+			Type: graphql.NewNonNull(nodeType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return nodeFor(p.Source.(*endorsement).edge.From)(p)
+			},
+		},
+		"endorsee": &graphql.Field{
+			Type: graphql.NewNonNull(nodeType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return nodeFor(p.Source.(*endorsement).edge.To)(p)
+			},
+		},
+		"credential": &graphql.Field{
+			Type: credentialType,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return credentialFor(p, p.Source.(*endorsement).edge.CredentialID)
+			},
+		},
+	}
+	for name, field := range endorsementFields {
+		endorsementType.AddFieldConfig(name, field)
+	}
+
+	nodesArgs := nodeArgs()
+	nodesArgs["near"] = &graphql.ArgumentConfig{Type: graphql.String, Description: "Only nodes reachable from this AID, within maxDepth hops when set"}
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"algorithm": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The scoring algorithm scores and the summary use",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return string(queryFrom(p).calculator.Algorithm()), nil
+				},
+			},
+			"node": &graphql.Field{
+				Type: nodeType,
+				Args: graphql.FieldConfigArgument{
+					"aid": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					graph, err := queryFrom(p).trustGraph()
+					if err != nil {
+						return nil, err
+					}
+					if node := graph.GetNode(p.Args["aid"].(string)); node != nil {
+						return node, nil
+					}
+					return nil, nil
+				},
+			},
+			"nodes": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(nodeType))),
+				Description: "Nodes matching every filter given, highest score first",
+				Args:        nodesArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					q := queryFrom(p)
+					nodes, err := q.filterNodes(nodeFilterFrom(p.Args))
+					if err != nil {
+						return nil, err
+					}
+					start, end, err := q.page(len(nodes), p.Args)
+					if err != nil {
+						return nil, err
+					}
+					return nodes[start:end], nil
+				},
+			},
+			"edges": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edgeType))),
+				Args: pageArgs(graphql.FieldConfigArgument{
+					"type": &graphql.ArgumentConfig{Type: graphql.String},
+					"from": &graphql.ArgumentConfig{Type: graphql.String},
+					"to":   &graphql.ArgumentConfig{Type: graphql.String},
+				}),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return queryFrom(p).filterEdges(p.Args, nil)
+				},
+			},
+			"credentials": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(credentialType))),
+				Args: pageArgs(graphql.FieldConfigArgument{
+					"issuer":  &graphql.ArgumentConfig{Type: graphql.String},
+					"subject": &graphql.ArgumentConfig{Type: graphql.String},
+					"schema":  &graphql.ArgumentConfig{Type: graphql.String},
+				}),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return queryFrom(p).filterCredentials(p.Args, "", "")
+				},
+			},
+			"endorsements": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(endorsementType))),
+				Args: endorsementArgs(graphql.FieldConfigArgument{
+					"endorser": &graphql.ArgumentConfig{Type: graphql.String},
+					"endorsee": &graphql.ArgumentConfig{Type: graphql.String},
+				}),
+				Resolve: endorsementsMatching(func(p graphql.ResolveParams, e *trust.Edge) bool {
+					endorser, _ := p.Args["endorser"].(string)
+					endorsee, _ := p.Args["endorsee"].(string)
+					return (endorser == "" || e.From == endorser) && (endorsee == "" || e.To == endorsee)
+				}),
+			},
+			"summary": &graphql.Field{
+				Type: graphql.NewNonNull(summaryType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					q := queryFrom(p)
+					graph, err := q.trustGraph()
+					if err != nil {
+						return nil, err
+					}
+					return q.calculator.CalculateSummary(graph), nil
+				},
+			},
+		},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+	if err != nil {
+		panic(fmt.Sprintf("graphql: invalid trust graph schema: %v", err))
+	}
+	return schema
+}
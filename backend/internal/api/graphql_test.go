@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/trust"
+)
+
+// graphQLTestQuery returns a query over org -> alice -> bob -> carol, with
+// alice endorsing bob
+func graphQLTestQuery() *graphQuery {
+	graph := trust.NewGraph("EORG")
+	graph.AddNode(&trust.Node{AID: "EORG", Role: "Organization"})
+	for _, aid := range []string{"EALICE", "EBOB", "ECAROL"} {
+		graph.AddNode(&trust.Node{AID: aid, Role: "Member"})
+	}
+	graph.AddNode(&trust.Node{AID: "EALICE", Role: "Steward"})
+	confidence := 0.5
+	graph.AddEdge(&trust.Edge{From: "EORG", To: "EALICE", CredentialID: "S1", Type: trust.EdgeTypeMembership})
+	graph.AddEdge(&trust.Edge{From: "EALICE", To: "EBOB", CredentialID: "S2", Type: trust.EdgeTypeInvitation})
+	graph.AddEdge(&trust.Edge{From: "EBOB", To: "ECAROL", CredentialID: "S3", Type: trust.EdgeTypeInvitation})
+	graph.AddEdge(&trust.Edge{From: "EALICE", To: "EBOB", CredentialID: "S4", Type: trust.EdgeTypeEndorsement, Confidence: &confidence})
+
+	creds := []*anystore.CachedCredential{
+		{ID: "S1", IssuerAID: "EORG", SubjectAID: "EALICE", SchemaID: "membership"},
+		{ID: "S2", IssuerAID: "EALICE", SubjectAID: "EBOB", SchemaID: "invitation"},
+		{ID: "S3", IssuerAID: "EBOB", SubjectAID: "ECAROL", SchemaID: "invitation"},
+		{ID: "S4", IssuerAID: "EALICE", SubjectAID: "EBOB", SchemaID: "endorsement", Data: map[string]interface{}{
+			"category":   "facilitation",
+			"endorsedAt": "2026-01-02T03:04:05Z",
+		}},
+	}
+
+	builds := 0
+	return &graphQuery{
+		calculator: trust.NewDefaultCalculator(),
+		loadGraph: func() (*trust.Graph, error) {
+			builds++
+			if builds > 1 {
+				panic("graph built twice")
+			}
+			return graph, nil
+		},
+		loadCredentials: func() ([]*anystore.CachedCredential, error) { return creds, nil },
+	}
+}
+
+func runGraphQL(t *testing.T, query string, variables map[string]interface{}) (map[string]interface{}, bool) {
+	t.Helper()
+	result, ok := executeGraphQL(context.Background(), &GraphQLRequest{Query: query, Variables: variables}, graphQLTestQuery())
+	if ok && len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ := json.Marshal(result)
+	var decoded map[string]interface{}
+	json.Unmarshal(data, &decoded)
+	return decoded, ok
+}
+
+func TestGraphQL_NodesNearWithFilters(t *testing.T) {
+	resp, _ := runGraphQL(t, `query($aid: String!) {
+		nodes(near: $aid, maxDepth: 2, membersOnly: true) {
+			aid
+			distance(from: $aid)
+			score { score }
+		}
+	}`, map[string]interface{}{"aid": "EALICE"})
+
+	nodes := resp["data"].(map[string]interface{})["nodes"].([]interface{})
+	got := map[string]float64{}
+	for _, n := range nodes {
+		node := n.(map[string]interface{})
+		got[node["aid"].(string)] = node["distance"].(float64)
+	}
+	// The org is not a member, and alice herself is excluded
+	if len(got) != 2 || got["EBOB"] != 1 || got["ECAROL"] != 2 {
+		t.Errorf("expected bob at 1 and carol at 2, got %v", got)
+	}
+
+	resp, _ = runGraphQL(t, `{ nodes(near: "EALICE", maxDepth: 1) { aid } }`, nil)
+	nodes = resp["data"].(map[string]interface{})["nodes"].([]interface{})
+	if len(nodes) != 2 {
+		t.Errorf("expected the org and bob within one hop, got %v", nodes)
+	}
+
+	resp, _ = runGraphQL(t, `{ nodes(role: "steward") { aid } }`, nil)
+	nodes = resp["data"].(map[string]interface{})["nodes"].([]interface{})
+	if len(nodes) != 1 || nodes[0].(map[string]interface{})["aid"] != "EALICE" {
+		t.Errorf("expected only alice to be a steward, got %v", nodes)
+	}
+}
+
+func TestGraphQL_NestedResolution(t *testing.T) {
+	resp, _ := runGraphQL(t, `{
+		node(aid: "EBOB") {
+			endorsementsReceived(category: "FACILITATION") {
+				category
+				confidence
+				endorsedAt
+				endorser { aid role }
+				credential { schema }
+			}
+			incoming(type: "invitation") { from { aid } credential { said } }
+			credentials(limit: 1) { said }
+		}
+	}`, nil)
+
+	node := resp["data"].(map[string]interface{})["node"].(map[string]interface{})
+	endorsements := node["endorsementsReceived"].([]interface{})
+	if len(endorsements) != 1 {
+		t.Fatalf("expected one endorsement, got %v", endorsements)
+	}
+	e := endorsements[0].(map[string]interface{})
+	if e["category"] != "facilitation" || e["confidence"] != 0.5 || e["endorsedAt"] != "2026-01-02T03:04:05Z" {
+		t.Errorf("unexpected endorsement %v", e)
+	}
+	if endorser := e["endorser"].(map[string]interface{}); endorser["aid"] != "EALICE" || endorser["role"] != "Steward" {
+		t.Errorf("unexpected endorser %v", endorser)
+	}
+	incoming := node["incoming"].([]interface{})
+	if len(incoming) != 1 || incoming[0].(map[string]interface{})["credential"].(map[string]interface{})["said"] != "S2" {
+		t.Errorf("expected alice's invitation, got %v", incoming)
+	}
+	if creds := node["credentials"].([]interface{}); len(creds) != 1 {
+		t.Errorf("expected the limit to apply, got %v", creds)
+	}
+}
+
+func TestGraphQL_Refused(t *testing.T) {
+	resp, ok := runGraphQL(t, `{ nodes { unknownField } }`, nil)
+	if ok || resp["errors"] == nil {
+		t.Errorf("expected a schema error, got %v", resp)
+	}
+
+	deep := `{ node(aid: "EALICE") { ` + strings.Repeat("neighbors { ", 8) + "aid" + strings.Repeat(" }", 9) + ` }`
+	resp, ok = runGraphQL(t, deep, nil)
+	if ok || !strings.Contains(resp["errors"].([]interface{})[0].(map[string]interface{})["message"].(string), "limit is 8") {
+		t.Errorf("expected the depth limit, got %v", resp)
+	}
+
+	result, ok := executeGraphQL(context.Background(), &GraphQLRequest{Query: `{ nodes(limit: 501) { aid } }`}, graphQLTestQuery())
+	if !ok || len(result.Errors) != 1 {
+		t.Errorf("expected the page size limit to fail the field, got %v", result.Errors)
+	}
+}
@@ -3,10 +3,12 @@ package api
 import (
 	"context"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/matou-dao/backend/internal/anysync"
 	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/flags"
 	"github.com/matou-dao/backend/internal/trust"
 )
 
@@ -16,6 +18,12 @@ type HealthHandler struct {
 	spaceStore anysync.SpaceStore
 	orgAID     string
 	adminAID   string
+	flags      *flags.Flags
+	shedder    *LoadShedder
+
+	// lastTrust is reported while the shedder's budget is used up
+	mu        sync.Mutex
+	lastTrust *TrustStatus
 }
 
 // NewHealthHandler creates a new health handler
@@ -33,6 +41,17 @@ func NewHealthHandler(
 	}
 }
 
+// SetFlags attaches feature flags so enabled features are reported in health
+func (h *HealthHandler) SetFlags(f *flags.Flags) {
+	h.flags = f
+}
+
+// SetLoadShedder reports load shedding in health, and keeps the health
+// check's own trust graph build within the shedder's budget
+func (h *HealthHandler) SetLoadShedder(s *LoadShedder) {
+	h.shedder = s
+}
+
 // HealthResponse represents the health check response
 type HealthResponse struct {
 	Status       string       `json:"status"`
@@ -40,6 +59,8 @@ type HealthResponse struct {
 	Admin        string       `json:"admin"`
 	Sync         *SyncStatus  `json:"sync,omitempty"`
 	Trust        *TrustStatus `json:"trust,omitempty"`
+	Features     []string     `json:"features,omitempty"`
+	Load         *LoadStatus  `json:"load,omitempty"`
 }
 
 // SyncStatus represents sync-related statistics
@@ -87,6 +108,14 @@ func (h *HealthHandler) HandleHealth(w http.ResponseWriter, r *http.Request) {
 		response.Trust = trustStatus
 	}
 
+	if h.flags != nil {
+		response.Features = h.flags.EnabledNames()
+	}
+
+	if h.shedder != nil {
+		response.Load = h.shedder.Status()
+	}
+
 	writeJSON(w, http.StatusOK, response)
 }
 
@@ -117,8 +146,19 @@ func (h *HealthHandler) getSyncStatus(ctx context.Context) *SyncStatus {
 	return status
 }
 
-// getTrustStatus calculates trust graph statistics
+// getTrustStatus calculates trust graph statistics. While the load
+// shedder's budget is used up, the last statistics are reported instead.
 func (h *HealthHandler) getTrustStatus(ctx context.Context) *TrustStatus {
+	if h.shedder != nil {
+		release, ok := h.shedder.TryAcquire()
+		if !ok {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			return h.lastTrust
+		}
+		defer release()
+	}
+
 	builder := trust.NewBuilder(h.store, h.orgAID)
 	graph, err := builder.Build(ctx)
 	if err != nil {
@@ -128,9 +168,13 @@ func (h *HealthHandler) getTrustStatus(ctx context.Context) *TrustStatus {
 	calculator := trust.NewDefaultCalculator()
 	summary := calculator.CalculateSummary(graph)
 
-	return &TrustStatus{
+	status := &TrustStatus{
 		TotalNodes:   summary.TotalNodes,
 		TotalEdges:   summary.TotalEdges,
 		AverageScore: summary.AverageScore,
 	}
+	h.mu.Lock()
+	h.lastTrust = status
+	h.mu.Unlock()
+	return status
 }
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anysync"
+)
+
+// HistoryHandler serves the change history of objects in a space's tree.
+type HistoryHandler struct {
+	spaceManager *anysync.SpaceManager
+}
+
+// NewHistoryHandler creates a new history handler.
+func NewHistoryHandler(spaceManager *anysync.SpaceManager) *HistoryHandler {
+	return &HistoryHandler{spaceManager: spaceManager}
+}
+
+// ObjectHistoryEntry is one version of an object
+type ObjectHistoryEntry struct {
+	ChangeID  string `json:"changeId"`
+	Version   int    `json:"version"`
+	AuthorKey string `json:"authorKey,omitempty"` // Account key that signed the tree change
+	OwnerKey  string `json:"ownerKey,omitempty"`  // Signing key recorded in the object
+	ChangedAt string `json:"changedAt"`
+	// ChangedFields lists the top-level data fields added, removed or
+	// modified since the previous version; the first version lists all
+	ChangedFields []string        `json:"changedFields"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// ObjectHistoryResponse is the response for GET /api/v1/objects/{spaceId}/{objectId}/history
+type ObjectHistoryResponse struct {
+	SpaceID  string                `json:"spaceId"`
+	ObjectID string                `json:"objectId"`
+	Type     string                `json:"type"`
+	Changes  []*ObjectHistoryEntry `json:"changes"`
+	Count    int                   `json:"count"`
+}
+
+// HandleObjectHistory handles GET /api/v1/objects/{spaceId}/{objectId}/history
+// Returns every version of the object, oldest first.
+func (h *HistoryHandler) HandleObjectHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/objects/")
+	parts := strings.Split(path, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] != "history" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+	spaceID, objectID := parts[0], parts[1]
+
+	history, err := h.spaceManager.ObjectTreeManager().ReadObjectHistory(r.Context(), spaceID, objectID)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			"error": fmt.Sprintf("object %s not found in space %s", objectID, spaceID),
+		})
+		return
+	}
+
+	resp := &ObjectHistoryResponse{
+		SpaceID:  spaceID,
+		ObjectID: objectID,
+		Type:     history[len(history)-1].Object.Type,
+		Changes:  make([]*ObjectHistoryEntry, 0, len(history)),
+		Count:    len(history),
+	}
+	var previous json.RawMessage
+	for _, change := range history {
+		obj := change.Object
+		ts := change.Timestamp
+		if ts == 0 {
+			ts = obj.Timestamp
+		}
+		resp.Changes = append(resp.Changes, &ObjectHistoryEntry{
+			ChangeID:      change.ChangeID,
+			Version:       obj.Version,
+			AuthorKey:     change.AuthorKey,
+			OwnerKey:      obj.OwnerKey,
+			ChangedAt:     time.Unix(ts, 0).UTC().Format(time.RFC3339),
+			ChangedFields: changedFields(previous, obj.Data),
+			Data:          obj.Data,
+		})
+		previous = obj.Data
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// changedFields returns the sorted top-level fields that differ between two
+// versions of an object's data. Non-object data has no fields.
+func changedFields(previous, current json.RawMessage) []string {
+	var before, after map[string]json.RawMessage
+	if previous != nil {
+		json.Unmarshal(previous, &before)
+	}
+	json.Unmarshal(current, &after)
+
+	fields := []string{}
+	for key, value := range after {
+		if old, ok := before[key]; !ok || !jsonEqual(old, value) {
+			fields = append(fields, key)
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			fields = append(fields, key)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// jsonEqual compares two JSON values ignoring insignificant whitespace
+func jsonEqual(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+// RegisterRoutes registers object history routes on the mux
+func (h *HistoryHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/objects/", h.HandleObjectHistory)
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/anysync"
+)
+
+func TestHandleObjectHistory_NotFound(t *testing.T) {
+	spaceManager := anysync.NewSpaceManager(newMockSyncAnySyncClient(), &anysync.SpaceManagerConfig{})
+	handler := NewHistoryHandler(spaceManager)
+
+	tests := []string{
+		"/api/v1/objects/space-1/SharedProfile-EUSER123",
+		"/api/v1/objects/space-1//history",
+		"/api/v1/objects/space-1/a/b/history",
+		"/api/v1/objects/space-1/SharedProfile-EUSER123/history", // no tree for the space
+	}
+	for _, path := range tests {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		handler.HandleObjectHistory(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("GET %s: expected status 404, got %d", path, w.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/objects/space-1/SharedProfile-EUSER123/history", nil)
+	w := httptest.NewRecorder()
+	handler.HandleObjectHistory(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+}
+
+func TestChangedFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous json.RawMessage
+		current  json.RawMessage
+		want     []string
+	}{
+		{"first version", nil, json.RawMessage(`{"name":"Alice","bio":"Weaver"}`), []string{"bio", "name"}},
+		{"modified", json.RawMessage(`{"name":"Alice","bio":"Weaver"}`), json.RawMessage(`{"name": "Alice", "bio":"Teacher"}`), []string{"bio"}},
+		{"added and removed", json.RawMessage(`{"name":"Alice","bio":"Weaver"}`), json.RawMessage(`{"name":"Alice","avatar":"bafy"}`), []string{"avatar", "bio"}},
+		{"unchanged", json.RawMessage(`{"tags":["a","b"]}`), json.RawMessage(`{"tags": ["a", "b"]}`), []string{}},
+		{"not an object", json.RawMessage(`"v1"`), json.RawMessage(`"v2"`), []string{}},
+	}
+	for _, tt := range tests {
+		if got := changedFields(tt.previous, tt.current); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: changedFields = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -8,7 +8,10 @@ import (
 	"time"
 
 	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/audit"
+	"github.com/matou-dao/backend/internal/bootstrap"
 	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/secret"
 	"github.com/matou-dao/backend/internal/types"
 )
 
@@ -18,6 +21,8 @@ type IdentityHandler struct {
 	sdkClient    *anysync.SDKClient
 	spaceManager *anysync.SpaceManager
 	spaceStore   anysync.SpaceStore
+	bootstrap    *bootstrap.Orchestrator
+	audit        *audit.Log
 }
 
 // NewIdentityHandler creates a new identity handler.
@@ -32,19 +37,40 @@ func NewIdentityHandler(
 		sdkClient:    sdkClient,
 		spaceManager: spaceManager,
 		spaceStore:   spaceStore,
+		bootstrap:    bootstrap.NewOrchestrator(spaceManager, spaceStore, userIdentity),
 	}
 }
 
+// SetBootstrap sets the orchestrator that creates and recovers spaces, so
+// its status covers the private space created here
+func (h *IdentityHandler) SetBootstrap(o *bootstrap.Orchestrator) {
+	h.bootstrap = o
+}
+
+// SetAudit records identity resets in the audit log
+func (h *IdentityHandler) SetAudit(log *audit.Log) {
+	h.audit = log
+}
+
+// orchestrator returns the bootstrap orchestrator, or a standalone one for
+// handlers built without NewIdentityHandler
+func (h *IdentityHandler) orchestrator() *bootstrap.Orchestrator {
+	if h.bootstrap != nil {
+		return h.bootstrap
+	}
+	return bootstrap.NewOrchestrator(h.spaceManager, h.spaceStore, h.userIdentity)
+}
+
 // SetIdentityRequest is the request body for POST /api/v1/identity/set.
 type SetIdentityRequest struct {
-	AID              string `json:"aid"`
-	Mnemonic         string `json:"mnemonic"`
-	OrgAID           string `json:"orgAid,omitempty"`
-	CommunitySpaceID string `json:"communitySpaceId,omitempty"`
-	ReadOnlySpaceID  string `json:"readOnlySpaceId,omitempty"`
-	AdminSpaceID     string `json:"adminSpaceId,omitempty"`
-	CredentialSAID   string `json:"credentialSaid,omitempty"`
-	Mode             string `json:"mode,omitempty"`
+	AID              string           `json:"aid" validate:"required"`
+	Mnemonic         *secret.Mnemonic `json:"mnemonic" validate:"required"`
+	OrgAID           string           `json:"orgAid,omitempty"`
+	CommunitySpaceID string           `json:"communitySpaceId,omitempty"`
+	ReadOnlySpaceID  string           `json:"readOnlySpaceId,omitempty"`
+	AdminSpaceID     string           `json:"adminSpaceId,omitempty"`
+	CredentialSAID   string           `json:"credentialSaid,omitempty"`
+	Mode             string           `json:"mode,omitempty"`
 }
 
 // SetIdentityResponse is the response for POST /api/v1/identity/set.
@@ -65,6 +91,9 @@ type GetIdentityResponse struct {
 	CommunityReadOnlySpaceID string `json:"communityReadOnlySpaceId,omitempty"`
 	AdminSpaceID             string `json:"adminSpaceId,omitempty"`
 	PrivateSpaceID           string `json:"privateSpaceId,omitempty"`
+	// Locked is true when identity.json is encrypted and can't be opened
+	// with the configured passphrase
+	Locked bool `json:"locked,omitempty"`
 }
 
 // HandleSetIdentity handles POST /api/v1/identity/set.
@@ -83,25 +112,21 @@ func (h *IdentityHandler) HandleSetIdentity(w http.ResponseWriter, r *http.Reque
 	}
 
 	var req SetIdentityRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, SetIdentityResponse{
-			Error: fmt.Sprintf("invalid request: %v", err),
-		})
+	// Wipe the request's copy of the mnemonic once the handler is done
+	defer func() { req.Mnemonic.Zero() }()
+	if !decodeRequest(w, r, &req) {
 		return
 	}
-
-	if req.AID == "" || req.Mnemonic == "" {
-		writeJSON(w, http.StatusBadRequest, SetIdentityResponse{
-			Error: "aid and mnemonic are required",
+	if err := checkSignedAID(r, req.AID); err != nil {
+		writeJSON(w, http.StatusForbidden, SetIdentityResponse{
+			Error: err.Error(),
 		})
 		return
 	}
 
 	// Validate mnemonic
-	if err := anysync.ValidateMnemonic(req.Mnemonic); err != nil {
-		writeJSON(w, http.StatusBadRequest, SetIdentityResponse{
-			Error: fmt.Sprintf("invalid mnemonic: %v", err),
-		})
+	if err := anysync.ValidateSecretMnemonic(req.Mnemonic); err != nil {
+		writeProblem(w, http.StatusBadRequest, "", FieldError{Field: "mnemonic", Message: fmt.Sprintf("is invalid: %v", err)})
 		return
 	}
 
@@ -168,70 +193,14 @@ func (h *IdentityHandler) HandleSetIdentity(w http.ResponseWriter, r *http.Reque
 	}
 
 	// 5. Recover or create the user's private space with mnemonic-derived keys
-	var privateSpaceID string
 	ctx := r.Context()
-	client := h.sdkClient
 	isClaim := req.Mode == "claim"
-
-	keys, err := anysync.DeriveSpaceKeySet(req.Mnemonic, 0)
+	privateSpaceID, err := h.orchestrator().EnsurePrivateSpace(ctx, req.AID, req.Mnemonic, isClaim)
 	if err != nil {
-		fmt.Printf("[Identity] Failed to derive private space keys: %v\n", err)
-	} else {
-		// Derive deterministic space ID from keys (used for recovery lookups)
-		derivedID, err := client.DeriveSpaceIDWithKeys(ctx, req.AID, anysync.SpaceTypePrivate, keys)
-		if err != nil {
-			fmt.Printf("[Identity] Failed to derive private space ID: %v\n", err)
-		} else {
-			// actualID tracks the space ID to use for all downstream operations.
-			// After CreateSpaceWithKeys we use its result (the coordinator-assigned
-			// ID) instead of derivedID, matching the pattern used by community
-			// space creation.
-			actualID := derivedID
-
-			if isClaim {
-				// Claim mode: create directly, treat failure as hard error
-				fmt.Printf("[Identity] Claim mode: creating private space directly\n")
-				result, createErr := client.CreateSpaceWithKeys(ctx, req.AID, anysync.SpaceTypePrivate, keys)
-				if createErr != nil {
-					writeJSON(w, http.StatusInternalServerError, SetIdentityResponse{
-						Error: fmt.Sprintf("failed to create private space: %v", createErr),
-					})
-					return
-				}
-				actualID = result.SpaceID
-				if actualID != derivedID {
-					fmt.Printf("[Identity] Warning: derived ID %s != created ID %s, using created ID\n", derivedID, actualID)
-				}
-			} else {
-				// Recovery mode: try to recover existing space, fall back to create.
-				// Use a short timeout so a slow/unreachable network doesn't block the response.
-				recoverCtx, recoverCancel := context.WithTimeout(ctx, 10*time.Second)
-				_, getErr := client.GetSpace(recoverCtx, derivedID)
-				recoverCancel()
-				if getErr != nil {
-					fmt.Printf("[Identity] Private space not on network, creating new: %v\n", getErr)
-					result, createErr := client.CreateSpaceWithKeys(ctx, req.AID, anysync.SpaceTypePrivate, keys)
-					if createErr != nil {
-						writeJSON(w, http.StatusInternalServerError, SetIdentityResponse{
-							Error: fmt.Sprintf("failed to create private space: %v", createErr),
-						})
-						return
-					}
-					actualID = result.SpaceID
-				} else {
-					fmt.Printf("[Identity] Recovered private space from network: %s\n", derivedID)
-				}
-			}
-			// Persist keys and space record using the actual space ID
-			anysync.PersistSpaceKeySet(client.GetDataDir(), actualID, keys)
-			h.spaceStore.SaveSpace(ctx, &anysync.Space{
-				SpaceID:   actualID,
-				OwnerAID:  req.AID,
-				SpaceType: anysync.SpaceTypePrivate,
-			})
-			h.userIdentity.SetPrivateSpaceID(actualID)
-			privateSpaceID = actualID
-		}
+		writeJSON(w, http.StatusInternalServerError, SetIdentityResponse{
+			Error: err.Error(),
+		})
+		return
 	}
 
 	if privateSpaceID != "" {
@@ -247,52 +216,9 @@ func (h *IdentityHandler) HandleSetIdentity(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
-	// 6. Recover community space (if configured) — skip in claim mode.
-	// Use a short timeout per space so slow network doesn't block the response.
-	const spaceRecoverTimeout = 10 * time.Second
-	if req.CommunitySpaceID != "" && !isClaim {
-		communityCtx, communityCancel := context.WithTimeout(ctx, spaceRecoverTimeout)
-		_, err := client.GetSpace(communityCtx, req.CommunitySpaceID)
-		communityCancel()
-		if err != nil {
-			fmt.Printf("[Identity] Failed to sync community space %s: %v\n", req.CommunitySpaceID, err)
-		} else {
-			fmt.Printf("[Identity] Recovered community space: %s\n", req.CommunitySpaceID)
-			// Persist key set for future writes (peer key as signing key)
-			communityKeys, _ := anysync.GenerateSpaceKeySet()
-			communityKeys.SigningKey = client.GetSigningKey()
-			anysync.PersistSpaceKeySet(client.GetDataDir(), req.CommunitySpaceID, communityKeys)
-		}
-	}
-
-	// 7. Recover read-only space (if configured) — skip in claim mode
-	if req.ReadOnlySpaceID != "" && !isClaim {
-		roCtx, roCancel := context.WithTimeout(ctx, spaceRecoverTimeout)
-		_, err := client.GetSpace(roCtx, req.ReadOnlySpaceID)
-		roCancel()
-		if err != nil {
-			fmt.Printf("[Identity] Failed to sync readonly space %s: %v\n", req.ReadOnlySpaceID, err)
-		} else {
-			fmt.Printf("[Identity] Recovered readonly space: %s\n", req.ReadOnlySpaceID)
-			roKeys, _ := anysync.GenerateSpaceKeySet()
-			roKeys.SigningKey = client.GetSigningKey()
-			anysync.PersistSpaceKeySet(client.GetDataDir(), req.ReadOnlySpaceID, roKeys)
-		}
-	}
-
-	// 8. Recover admin space (if configured) — skip in claim mode
-	if adminSpaceID := h.spaceManager.GetAdminSpaceID(); adminSpaceID != "" && !isClaim {
-		adminCtx, adminCancel := context.WithTimeout(ctx, spaceRecoverTimeout)
-		_, err := client.GetSpace(adminCtx, adminSpaceID)
-		adminCancel()
-		if err != nil {
-			fmt.Printf("[Identity] Failed to sync admin space %s: %v\n", adminSpaceID, err)
-		} else {
-			fmt.Printf("[Identity] Recovered admin space: %s\n", adminSpaceID)
-			adminKeys, _ := anysync.GenerateSpaceKeySet()
-			adminKeys.SigningKey = client.GetSigningKey()
-			anysync.PersistSpaceKeySet(client.GetDataDir(), adminSpaceID, adminKeys)
-		}
+	// 6. Recover the org spaces the user has joined — skip in claim mode
+	if !isClaim {
+		h.orchestrator().RecoverOrgSpaces(ctx, req.CommunitySpaceID, req.ReadOnlySpaceID, h.spaceManager.GetAdminSpaceID())
 	}
 
 	writeJSON(w, http.StatusOK, SetIdentityResponse{
@@ -381,6 +307,7 @@ func (h *IdentityHandler) HandleGetIdentity(w http.ResponseWriter, r *http.Reque
 		CommunityReadOnlySpaceID: h.userIdentity.GetCommunityReadOnlySpaceID(),
 		AdminSpaceID:             h.userIdentity.GetAdminSpaceID(),
 		PrivateSpaceID:           h.userIdentity.GetPrivateSpaceID(),
+		Locked:                   h.userIdentity.Locked() != nil,
 	})
 }
 
@@ -393,12 +320,21 @@ func (h *IdentityHandler) HandleDeleteIdentity(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Read who is resetting, and what, before the identity is gone
+	actor := auditActor(r.Context(), h.userIdentity)
+	previous := map[string]string{
+		"aid":    h.userIdentity.GetAID(),
+		"peerId": h.userIdentity.GetPeerID(),
+		"orgAid": h.userIdentity.GetOrgAID(),
+	}
+
 	if err := h.userIdentity.Clear(); err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{
 			"error": fmt.Sprintf("failed to clear identity: %v", err),
 		})
 		return
 	}
+	h.audit.Record(r.Context(), audit.ActionIdentityReset, actor, previous["aid"], previous, nil)
 
 	writeJSON(w, http.StatusOK, map[string]string{
 		"status": "identity cleared",
@@ -0,0 +1,509 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/anyproto/any-sync/util/crypto"
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+)
+
+// maxInboxPayload bounds the size of a deposited item before sealing
+const maxInboxPayload = 64 << 10
+
+// InboxHandler manages the local member's inbox space and delivery to other
+// members' inboxes. Items are drained from the inbox space into the local
+// store; the tree keeps them, so draining is idempotent by item ID.
+type InboxHandler struct {
+	spaceManager *anysync.SpaceManager
+	store        *anystore.LocalStore
+	records      anystore.RecordStore
+	userIdentity *identity.UserIdentity
+	events       *EventBroker
+	receipts     ReceiptRecorder
+
+	mu sync.Mutex // serializes Drain
+}
+
+// NewInboxHandler creates a new inbox handler
+func NewInboxHandler(spaceManager *anysync.SpaceManager, store *anystore.LocalStore, userIdentity *identity.UserIdentity) *InboxHandler {
+	h := &InboxHandler{
+		spaceManager: spaceManager,
+		store:        store,
+		userIdentity: userIdentity,
+	}
+	if store != nil {
+		h.records = store
+	}
+	return h
+}
+
+// SetRecordStore keeps drained items and the inbox space record in records
+// instead of the local store
+func (h *InboxHandler) SetRecordStore(records anystore.RecordStore) {
+	h.records = records
+}
+
+// SetEvents broadcasts inbox:item (and credential:stored for delivered
+// credentials) as items are drained
+func (h *InboxHandler) SetEvents(events *EventBroker) {
+	h.events = events
+}
+
+// SetReceipts records delivered receipts as credentials are deposited in
+// inboxes, and cached receipts as recipients confirm them
+func (h *InboxHandler) SetReceipts(receipts ReceiptRecorder) {
+	h.receipts = receipts
+}
+
+// DeliverRequest is the body for POST /api/v1/inbox/deliver
+type DeliverRequest struct {
+	RecipientAID string          `json:"recipientAid"`
+	Kind         string          `json:"kind"` // credential, notification or message
+	Payload      json.RawMessage `json:"payload"`
+}
+
+// InboxResponse is the response for GET /api/v1/inbox
+type InboxResponse struct {
+	SpaceID string                  `json:"spaceId,omitempty"`
+	Items   []*anystore.InboxRecord `json:"items"`
+	Count   int                     `json:"count"`
+}
+
+// handleInbox routes /api/v1/inbox
+func (h *InboxHandler) handleInbox(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.HandleList(w, r)
+	case http.MethodPost:
+		h.HandleCreate(w, r)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// HandleCreate handles POST /api/v1/inbox
+// Creates the member's inbox space and publishes its address in the
+// community space. Idempotent: returns the existing address if there is one.
+func (h *InboxHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
+	if communitySpaceID == "" {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "community space not configured"})
+		return
+	}
+
+	ctx := r.Context()
+
+	// Reuse a published address, e.g. after a restore that lost identity.json
+	if addr, err := h.readAddress(ctx, me); err == nil {
+		if h.userIdentity.GetInboxSpaceID() != addr.SpaceID {
+			if err := h.userIdentity.SetInboxSpaceID(addr.SpaceID); err != nil {
+				fmt.Printf("Warning: failed to persist inbox space ID: %v\n", err)
+			}
+		}
+		writeJSON(w, http.StatusOK, addr)
+		return
+	}
+
+	mnemonic := h.userIdentity.GetMnemonic()
+	defer mnemonic.Zero()
+	encryptionKey, err := anysync.DeriveInboxKey(mnemonic)
+	if err != nil {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+		return
+	}
+
+	space, inviteKey, err := h.spaceManager.CreateInboxSpace(ctx, me, mnemonic)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if err := anystore.NewSpaceStoreAdapter(h.records).SaveSpace(ctx, space); err != nil {
+		fmt.Printf("Warning: failed to save inbox space record: %v\n", err)
+	}
+	if err := h.userIdentity.SetInboxSpaceID(space.SpaceID); err != nil {
+		fmt.Printf("Warning: failed to persist inbox space ID: %v\n", err)
+	}
+	h.events.Broadcast(spaceCreatedEvent(space))
+
+	inviteKeyBytes, err := inviteKey.Marshall()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to encode invite key: %v", err),
+		})
+		return
+	}
+	addr := &anysync.InboxAddress{
+		AID:           me,
+		SpaceID:       space.SpaceID,
+		InviteKey:     base64.StdEncoding.EncodeToString(inviteKeyBytes),
+		EncryptionKey: encryptionKey.GetPublic().Account(),
+		UpdatedAt:     time.Now().UTC().Format(time.RFC3339),
+	}
+	if _, err := writeObject(ctx, h.spaceManager, communitySpaceID, anysync.InboxAddressID(me), anysync.InboxAddressType, addr); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to publish inbox address: %v", err),
+		})
+		return
+	}
+
+	fmt.Printf("[Inbox] Created inbox %s for %s\n", space.SpaceID, me)
+	writeJSON(w, http.StatusCreated, addr)
+}
+
+// HandleList handles GET /api/v1/inbox
+// Returns drained items, newest first. Optional ?kind= filter.
+func (h *InboxHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	records, err := h.records.ListInboxItems(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	kind := r.URL.Query().Get("kind")
+	items := make([]*anystore.InboxRecord, 0, len(records))
+	for _, record := range records {
+		if kind == "" || record.Kind == kind {
+			items = append(items, record)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+
+	writeJSON(w, http.StatusOK, InboxResponse{
+		SpaceID: h.userIdentity.GetInboxSpaceID(),
+		Items:   items,
+		Count:   len(items),
+	})
+}
+
+// HandleDeliver handles POST /api/v1/inbox/deliver
+// Joins the recipient's inbox as a writer if needed and deposits the payload
+// sealed to their inbox key.
+func (h *InboxHandler) HandleDeliver(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+
+	var req DeliverRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 2*maxInboxPayload)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+	if req.RecipientAID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "recipientAid is required"})
+		return
+	}
+	if !anysync.IsInboxKind(req.Kind) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("kind must be %s, %s or %s", anysync.InboxKindCredential, anysync.InboxKindNotification, anysync.InboxKindMessage),
+		})
+		return
+	}
+	if len(req.Payload) == 0 || len(req.Payload) > maxInboxPayload {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("payload is required (max %d bytes)", maxInboxPayload),
+		})
+		return
+	}
+
+	objectID, spaceID, err := h.deliver(r.Context(), me, req.RecipientAID, req.Kind, req.Payload)
+	switch {
+	case errors.Is(err, errNoInbox):
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			"error": fmt.Sprintf("no inbox published for %s", req.RecipientAID),
+		})
+		return
+	case errors.Is(err, errBadInboxAddress):
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if req.Kind == anysync.InboxKindCredential && h.receipts != nil && h.receipts.Available() {
+		var cred anysync.Credential
+		if json.Unmarshal(req.Payload, &cred) == nil && cred.SAID != "" {
+			if _, _, err := h.receipts.Record(r.Context(), anysync.ReceiptDelivered, cred.SAID, cred.Schema, req.RecipientAID); err != nil {
+				fmt.Printf("[Inbox] Warning: failed to record delivery of %s: %v\n", cred.SAID, err)
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]string{
+		"id":      objectID,
+		"spaceId": spaceID,
+	})
+}
+
+// Errors from deliver that callers map to a status
+var (
+	errNoInbox         = errors.New("no inbox published")
+	errBadInboxAddress = errors.New("recipient's inbox address is malformed")
+)
+
+// deliver joins recipientAID's inbox as a writer if needed and deposits
+// payload sealed to their inbox key. Returns the item and inbox space IDs.
+func (h *InboxHandler) deliver(ctx context.Context, me, recipientAID, kind string, payload []byte) (string, string, error) {
+	addr, err := h.readAddress(ctx, recipientAID)
+	if err != nil {
+		return "", "", fmt.Errorf("%w for %s: %v", errNoInbox, recipientAID, err)
+	}
+
+	inviteKeyBytes, err := base64.StdEncoding.DecodeString(addr.InviteKey)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: invite key: %v", errBadInboxAddress, err)
+	}
+	inviteKey, err := crypto.UnmarshalEd25519PrivateKeyProto(inviteKeyBytes)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: invite key: %v", errBadInboxAddress, err)
+	}
+	metadata := []byte(fmt.Sprintf(`{"aid":"%s","inbox":"%s","joinedAt":"%s"}`,
+		me, recipientAID, time.Now().UTC().Format(time.RFC3339)))
+	if err := h.spaceManager.JoinInboxSpace(ctx, addr.SpaceID, inviteKey, metadata); err != nil {
+		return "", "", err
+	}
+
+	sealed, err := anysync.SealInboxItem(addr.EncryptionKey, payload)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", errBadInboxAddress, err)
+	}
+
+	now := time.Now().UTC()
+	objectID := fmt.Sprintf("InboxItem-%s-%d", me, now.UnixMilli())
+	item := &anysync.InboxItem{
+		RecipientAID: recipientAID,
+		SenderAID:    me,
+		Kind:         kind,
+		Sealed:       sealed,
+		CreatedAt:    now.Format(time.RFC3339),
+	}
+	if _, err := writeObject(ctx, h.spaceManager, addr.SpaceID, objectID, anysync.InboxItemType, item); err != nil {
+		return "", "", err
+	}
+
+	fmt.Printf("[Inbox] %s delivered %s to %s\n", me, kind, recipientAID)
+	return objectID, addr.SpaceID, nil
+}
+
+// HandleDrain handles POST /api/v1/inbox/drain
+// Drains the inbox now instead of waiting for the background watcher.
+func (h *InboxHandler) HandleDrain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	drained, err := h.Drain(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"drained": drained})
+}
+
+// Drain opens every item in the member's inbox space that isn't in the local
+// store yet and stores it, returning how many were new. Delivered
+// credentials are also added to the credential cache. A backend without an
+// inbox drains nothing.
+func (h *InboxHandler) Drain(ctx context.Context) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	me := h.userIdentity.GetAID()
+	spaceID := h.userIdentity.GetInboxSpaceID()
+	if me == "" || spaceID == "" {
+		return 0, nil
+	}
+
+	objects, err := readLatestObjects(ctx, h.spaceManager, spaceID, anysync.InboxItemType)
+	if err != nil {
+		return 0, fmt.Errorf("reading inbox: %w", err)
+	}
+
+	var key crypto.PrivKey
+	drained := 0
+	for _, obj := range objects {
+		if _, err := h.records.GetInboxItem(ctx, obj.ID); err == nil {
+			continue
+		}
+		var item anysync.InboxItem
+		if err := json.Unmarshal(obj.Data, &item); err != nil || item.RecipientAID != me {
+			continue
+		}
+
+		if key == nil {
+			mnemonic := h.userIdentity.GetMnemonic()
+			key, err = anysync.DeriveInboxKey(mnemonic)
+			mnemonic.Zero()
+			if err != nil {
+				return drained, err
+			}
+		}
+		payload, err := anysync.OpenInboxItem(key, item.Sealed)
+		if err != nil || !json.Valid(payload) {
+			fmt.Printf("[Inbox] Skipping unreadable item %s from %s\n", obj.ID, item.SenderAID)
+			continue
+		}
+
+		createdAt, _ := time.Parse(time.RFC3339, item.CreatedAt)
+		record := &anystore.InboxRecord{
+			ID:         obj.ID,
+			SenderAID:  item.SenderAID,
+			Kind:       item.Kind,
+			Payload:    payload,
+			CreatedAt:  createdAt,
+			ReceivedAt: time.Now().UTC(),
+		}
+		var cached *anysync.Credential
+		if item.Kind == anysync.InboxKindCredential {
+			cached = h.cacheCredential(ctx, payload)
+		}
+		if err := h.records.StoreInboxItem(ctx, record); err != nil {
+			return drained, fmt.Errorf("storing inbox item: %w", err)
+		}
+		drained++
+
+		if cached != nil && item.SenderAID != me {
+			h.confirmCached(ctx, me, item.SenderAID, cached)
+		}
+		if item.Kind == anysync.InboxKindNotification {
+			h.recordConfirmation(ctx, item.SenderAID, payload)
+		}
+
+		h.events.Broadcast(SSEEvent{
+			Type: EventInboxItem,
+			Data: map[string]string{
+				"id":     record.ID,
+				"sender": record.SenderAID,
+				"kind":   record.Kind,
+			},
+		})
+	}
+
+	if drained > 0 {
+		fmt.Printf("[Inbox] Drained %d item(s)\n", drained)
+	}
+	return drained, nil
+}
+
+// cacheCredential stores a credential delivered through the inbox in the
+// credential cache, like one synced from the private space. Returns the
+// credential, or nil if it wasn't cached.
+func (h *InboxHandler) cacheCredential(ctx context.Context, payload []byte) *anysync.Credential {
+	var cred anysync.Credential
+	if err := json.Unmarshal(payload, &cred); err != nil || cred.SAID == "" {
+		return nil
+	}
+	cached := &anystore.CachedCredential{
+		ID:         cred.SAID,
+		IssuerAID:  cred.Issuer,
+		SubjectAID: cred.Recipient,
+		SchemaID:   cred.Schema,
+		Data:       cred.Data,
+		CachedAt:   time.Now().UTC(),
+	}
+	if err := h.store.StoreCredential(ctx, cached); err != nil {
+		fmt.Printf("[Inbox] Failed to cache delivered credential %s: %v\n", cred.SAID, err)
+		return nil
+	}
+	for _, event := range CredentialStoredEvents(cached) {
+		h.events.Broadcast(event)
+	}
+	return &cred
+}
+
+// noticeCredentialCached is the notification type a recipient's backend
+// sends back to the sender's inbox once a delivered credential is cached
+const noticeCredentialCached = "credential:cached"
+
+// deliveryConfirmation is the payload of a credential:cached notification
+type deliveryConfirmation struct {
+	Type   string `json:"type"`
+	SAID   string `json:"said"`
+	Schema string `json:"schema,omitempty"`
+}
+
+// confirmCached tells the sender of a credential that it is now cached
+// here, so their receipt ledger can mark it cached. A sender without an
+// inbox can still see the IPEX admit, so failures are only logged.
+func (h *InboxHandler) confirmCached(ctx context.Context, me, senderAID string, cred *anysync.Credential) {
+	payload, err := json.Marshal(deliveryConfirmation{
+		Type:   noticeCredentialCached,
+		SAID:   cred.SAID,
+		Schema: cred.Schema,
+	})
+	if err != nil {
+		return
+	}
+	if _, _, err := h.deliver(ctx, me, senderAID, anysync.InboxKindNotification, payload); err != nil {
+		fmt.Printf("[Inbox] Could not confirm %s to %s: %v\n", cred.SAID, senderAID, err)
+	}
+}
+
+// recordConfirmation records a cached receipt when a drained notification
+// is a recipient's credential:cached confirmation. Only stewards keep the
+// receipt ledger; other backends ignore confirmations.
+func (h *InboxHandler) recordConfirmation(ctx context.Context, senderAID string, payload []byte) {
+	if h.receipts == nil || !h.receipts.Available() {
+		return
+	}
+	var notice deliveryConfirmation
+	if err := json.Unmarshal(payload, &notice); err != nil || notice.Type != noticeCredentialCached || notice.SAID == "" {
+		return
+	}
+	if _, _, err := h.receipts.Record(ctx, anysync.ReceiptCached, notice.SAID, notice.Schema, senderAID); err != nil {
+		fmt.Printf("[Inbox] Warning: failed to record %s cached by %s: %v\n", notice.SAID, senderAID, err)
+	}
+}
+
+// readAddress reads a member's published inbox address from the community space
+func (h *InboxHandler) readAddress(ctx context.Context, aid string) (*anysync.InboxAddress, error) {
+	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
+	if communitySpaceID == "" {
+		return nil, fmt.Errorf("community space not configured")
+	}
+	obj, err := h.spaceManager.ObjectTreeManager().ReadLatestByID(ctx, communitySpaceID, anysync.InboxAddressID(aid))
+	if err != nil {
+		return nil, err
+	}
+	var addr anysync.InboxAddress
+	if err := json.Unmarshal(obj.Data, &addr); err != nil {
+		return nil, fmt.Errorf("invalid inbox address: %w", err)
+	}
+	if addr.SpaceID == "" || addr.InviteKey == "" || addr.EncryptionKey == "" {
+		return nil, fmt.Errorf("incomplete inbox address for %s", aid)
+	}
+	return &addr, nil
+}
+
+// RegisterRoutes registers inbox routes on the mux
+func (h *InboxHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/inbox", h.handleInbox)
+	mux.HandleFunc("/api/v1/inbox/deliver", h.HandleDeliver)
+	mux.HandleFunc("/api/v1/inbox/drain", h.HandleDrain)
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+func newTestInboxHandler(t *testing.T, aid string) (*InboxHandler, *anystore.LocalStore) {
+	t.Helper()
+
+	store, cleanup := setupTrustTestStore(t)
+	t.Cleanup(cleanup)
+
+	tmpDir, err := os.MkdirTemp("", "inbox_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	userIdentity := identity.New(tmpDir)
+	if aid != "" {
+		if err := userIdentity.SetIdentity(aid, secret.NewMnemonic("test mnemonic")); err != nil {
+			t.Fatalf("SetIdentity failed: %v", err)
+		}
+	}
+
+	spaceManager := anysync.NewSpaceManager(newMockSyncAnySyncClient(), &anysync.SpaceManagerConfig{
+		CommunitySpaceID: "space-community-test",
+	})
+	return NewInboxHandler(spaceManager, store, userIdentity), store
+}
+
+func TestInboxDeliver_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		aid        string
+		body       string
+		wantStatus int
+	}{
+		{"no identity", "", `{"recipientAid":"EBOB","kind":"message","payload":{}}`, http.StatusBadRequest},
+		{"missing recipient", "EALICE", `{"kind":"message","payload":{}}`, http.StatusBadRequest},
+		{"unknown kind", "EALICE", `{"recipientAid":"EBOB","kind":"gift","payload":{}}`, http.StatusBadRequest},
+		{"missing payload", "EALICE", `{"recipientAid":"EBOB","kind":"message"}`, http.StatusBadRequest},
+		{"no published inbox", "EALICE", `{"recipientAid":"EBOB","kind":"message","payload":{"text":"hi"}}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		handler, _ := newTestInboxHandler(t, tt.aid)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/inbox/deliver", strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		handler.HandleDeliver(w, req)
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.name, tt.wantStatus, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestInboxDrain_NoInbox(t *testing.T) {
+	handler, _ := newTestInboxHandler(t, "EALICE")
+
+	drained, err := handler.Drain(context.Background())
+	if err != nil || drained != 0 {
+		t.Errorf("expected nothing drained without an inbox, got %d (%v)", drained, err)
+	}
+}
+
+func TestInboxList_FiltersAndSorts(t *testing.T) {
+	handler, store := newTestInboxHandler(t, "EALICE")
+	ctx := context.Background()
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	records := []*anystore.InboxRecord{
+		{ID: "InboxItem-EORG-1", SenderAID: "EORG", Kind: anysync.InboxKindNotification, Payload: []byte(`{}`), CreatedAt: base},
+		{ID: "InboxItem-EBOB-2", SenderAID: "EBOB", Kind: anysync.InboxKindMessage, Payload: []byte(`{}`), CreatedAt: base.Add(time.Hour)},
+		{ID: "InboxItem-EORG-3", SenderAID: "EORG", Kind: anysync.InboxKindNotification, Payload: []byte(`{}`), CreatedAt: base.Add(2 * time.Hour)},
+	}
+	for _, record := range records {
+		if err := store.StoreInboxItem(ctx, record); err != nil {
+			t.Fatalf("StoreInboxItem failed: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/inbox?kind=notification", nil)
+	w := httptest.NewRecorder()
+	handler.handleInbox(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var resp InboxResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Count != 2 {
+		t.Fatalf("expected 2 notifications, got %d", resp.Count)
+	}
+	if resp.Items[0].ID != "InboxItem-EORG-3" {
+		t.Errorf("expected newest item first, got %s", resp.Items[0].ID)
+	}
+}
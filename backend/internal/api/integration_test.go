@@ -60,8 +60,8 @@ func (m *mockAnySyncClientForIntegration) AddToACL(ctx context.Context, spaceID
 	return nil
 }
 
-func (m *mockAnySyncClientForIntegration) SyncDocument(ctx context.Context, spaceID string, docID string, data []byte) error {
-	return nil
+func (m *mockAnySyncClientForIntegration) SyncDocument(ctx context.Context, spaceID string, docID string, data []byte) ([]string, error) {
+	return nil, nil
 }
 
 func (m *mockAnySyncClientForIntegration) GetNetworkID() string        { return "test-network" }
@@ -1,23 +1,467 @@
 package api
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/mail"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+	"github.com/anyproto/any-sync/util/crypto"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
 	"github.com/matou-dao/backend/internal/email"
 )
 
+// Invite scopes: which space an invite joins
+const (
+	InviteScopeCommunity = "community" // The community space
+	InviteScopeReadOnly  = "readonly"  // The community read-only space
+	InviteScopeSpace     = "space"     // Any space this backend administers
+)
+
+// Invite states. Anything but active has been revoked in the space ACL.
+const (
+	InviteActive  = "active"
+	InviteUsed    = "used"
+	InviteExpired = "expired"
+	InviteRevoked = "revoked"
+)
+
+// Invite email delivery states
+const (
+	EmailPending = "pending"
+	EmailSent    = "sent"
+	EmailFailed  = "failed"
+)
+
+// DefaultInviteTTL is how long an invite lasts when no TTL is given
+const DefaultInviteTTL = 7 * 24 * time.Hour
+
+// MaxInviteTTL is the longest an invite may last
+const MaxInviteTTL = 90 * 24 * time.Hour
+
 // InvitesHandler handles invite-related HTTP requests
 type InvitesHandler struct {
-	emailSender *email.Sender
+	emailSender  *email.Sender
+	spaceManager *anysync.SpaceManager
+	store        anystore.RecordStore
+	events       *EventBroker
+	now          func() time.Time
+
+	// sendInviteLink emails an invite link; retry paces its attempts
+	sendInviteLink func(req email.SendInviteLinkRequest) error
+	retry          email.RetryPolicy
 }
 
 // NewInvitesHandler creates a new invites handler
-func NewInvitesHandler(emailSender *email.Sender) *InvitesHandler {
-	return &InvitesHandler{
-		emailSender: emailSender,
+func NewInvitesHandler(emailSender *email.Sender, spaceManager *anysync.SpaceManager, store anystore.RecordStore) *InvitesHandler {
+	h := &InvitesHandler{
+		emailSender:  emailSender,
+		spaceManager: spaceManager,
+		store:        store,
+		now:          time.Now,
+		retry:        email.DefaultRetryPolicy,
+	}
+	if emailSender != nil {
+		h.sendInviteLink = emailSender.SendInviteLink
+	}
+	return h
+}
+
+// SetEvents broadcasts acl:changed events when invites are created or revoked
+func (h *InvitesHandler) SetEvents(events *EventBroker) {
+	h.events = events
+}
+
+// InviteToken is what a shareable invite carries: everything a member's
+// backend needs to join the space. The expiry only lets the joiner fail
+// fast; the issuer enforces it by revoking the invite.
+type InviteToken struct {
+	Version   int    `json:"v"`
+	SpaceID   string `json:"s"`
+	InviteID  string `json:"i"`
+	Key       string `json:"k"` // base64-encoded invite private key
+	ExpiresAt int64  `json:"e"` // Unix seconds
+}
+
+// EncodeInviteToken serializes an invite into a URL-safe token
+func EncodeInviteToken(spaceID, inviteID string, inviteKey crypto.PrivKey, expiresAt time.Time) (string, error) {
+	keyBytes, err := inviteKey.Marshall()
+	if err != nil {
+		return "", fmt.Errorf("marshalling invite key: %w", err)
+	}
+	data, err := json.Marshal(InviteToken{
+		Version:   1,
+		SpaceID:   spaceID,
+		InviteID:  inviteID,
+		Key:       base64.StdEncoding.EncodeToString(keyBytes),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeInviteToken parses a token from EncodeInviteToken and its invite key
+func DecodeInviteToken(token string) (*InviteToken, crypto.PrivKey, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(token))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid invite token encoding")
+	}
+	var t InviteToken
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, nil, fmt.Errorf("invalid invite token")
+	}
+	if t.Version != 1 || t.SpaceID == "" || t.Key == "" {
+		return nil, nil, fmt.Errorf("unsupported invite token")
+	}
+	keyBytes, err := base64.StdEncoding.DecodeString(t.Key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid invite key encoding")
+	}
+	key, err := crypto.UnmarshalEd25519PrivateKeyProto(keyBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid invite key: %v", err)
+	}
+	return &t, key, nil
+}
+
+// inviteURL appends the token to a link base as the "invite" query parameter
+func inviteURL(base, token string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil || u.Scheme == "" {
+		return "", fmt.Errorf("baseUrl must be an absolute URL")
+	}
+	q := u.Query()
+	q.Set("invite", token)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// CreateInviteRequest is the body for POST /api/v1/invites
+type CreateInviteRequest struct {
+	Scope      string `json:"scope,omitempty"`      // community (default), readonly or space
+	SpaceID    string `json:"spaceId,omitempty"`    // required for the space scope
+	Permission string `json:"permission,omitempty"` // reader or writer; defaults by scope
+	TTL        string `json:"ttl,omitempty"`        // Go duration, e.g. "72h" (default 7 days)
+	MaxUses    *int   `json:"maxUses,omitempty"`    // joins allowed (default 1, 0 = unlimited)
+	BaseURL    string `json:"baseUrl,omitempty"`    // link base to build a shareable URL from
+}
+
+// CreateInviteResponse is returned when an invite is created. The token is
+// only ever returned when the invite is created.
+type CreateInviteResponse struct {
+	Success bool                   `json:"success"`
+	Invite  *anystore.InviteRecord `json:"invite,omitempty"`
+	Token   string                 `json:"token,omitempty"`
+	URL     string                 `json:"url,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+// inviteSpace resolves the space and permissions an invite request asks for
+func (h *InvitesHandler) inviteSpace(req *CreateInviteRequest) (string, string, list.AclPermissions, error) {
+	scope := req.Scope
+	if scope == "" {
+		scope = InviteScopeCommunity
+	}
+	var spaceID, permission string
+	switch scope {
+	case InviteScopeCommunity:
+		spaceID, permission = h.spaceManager.GetCommunitySpaceID(), "writer"
+	case InviteScopeReadOnly:
+		spaceID, permission = h.spaceManager.GetCommunityReadOnlySpaceID(), "reader"
+	case InviteScopeSpace:
+		if req.SpaceID == "" {
+			return "", "", list.AclPermissionsNone, fmt.Errorf("spaceId is required for the space scope")
+		}
+		spaceID, permission = req.SpaceID, "writer"
+	default:
+		return "", "", list.AclPermissionsNone, fmt.Errorf("scope must be community, readonly or space")
+	}
+	if spaceID == "" {
+		return "", "", list.AclPermissionsNone, fmt.Errorf("%s space not configured", scope)
+	}
+	if req.Permission != "" {
+		permission = req.Permission
+	}
+	switch {
+	case permission == "reader":
+		return spaceID, scope, list.AclPermissionsReader, nil
+	case permission == "writer" && scope != InviteScopeReadOnly:
+		return spaceID, scope, list.AclPermissionsWriter, nil
+	case permission == "writer":
+		return "", "", list.AclPermissionsNone, fmt.Errorf("readonly invites can only grant reader")
+	default:
+		return "", "", list.AclPermissionsNone, fmt.Errorf("permission must be reader or writer")
+	}
+}
+
+// HandleCreate handles POST /api/v1/invites
+func (h *InvitesHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	var req CreateInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, CreateInviteResponse{Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+
+	resp, status, err := h.issue(r.Context(), &req)
+	if err != nil {
+		writeJSON(w, status, CreateInviteResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, status, resp)
+}
+
+// issue creates an invite in its space ACL and records it, returning the
+// HTTP status to answer with
+func (h *InvitesHandler) issue(ctx context.Context, req *CreateInviteRequest) (*CreateInviteResponse, int, error) {
+	ttl := DefaultInviteTTL
+	if req.TTL != "" {
+		d, err := time.ParseDuration(req.TTL)
+		if err != nil || d <= 0 || d > MaxInviteTTL {
+			return nil, http.StatusBadRequest, fmt.Errorf("ttl must be a duration between 0 and %s", MaxInviteTTL)
+		}
+		ttl = d
+	}
+	maxUses := 1
+	if req.MaxUses != nil {
+		maxUses = *req.MaxUses
+	}
+	if maxUses < 0 {
+		return nil, http.StatusBadRequest, fmt.Errorf("maxUses must not be negative")
+	}
+	spaceID, scope, permissions, err := h.inviteSpace(req)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	client := h.spaceManager.GetClient()
+	if client == nil {
+		return nil, http.StatusServiceUnavailable, fmt.Errorf("any-sync client not available")
+	}
+	// Invites need the space shareable on the coordinator (idempotent)
+	if err := client.MakeSpaceShareable(ctx, spaceID); err != nil {
+		fmt.Printf("[Invites] Warning: MakeSpaceShareable: %v\n", err)
+	}
+
+	inviteID, inviteKey, err := h.spaceManager.ACLManager().CreateInvite(ctx, spaceID, permissions)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to create invite: %v", err)
+	}
+
+	now := h.now().UTC()
+	record := &anystore.InviteRecord{
+		ID:         inviteID,
+		SpaceID:    spaceID,
+		Scope:      scope,
+		Permission: "writer",
+		MaxUses:    maxUses,
+		Status:     InviteActive,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	if permissions == list.AclPermissionsReader {
+		record.Permission = "reader"
+	}
+	if p := PrincipalFromContext(ctx); p != nil {
+		record.CreatedBy = p.AID
+	}
+
+	resp := CreateInviteResponse{Success: true, Invite: record}
+	resp.Token, err = EncodeInviteToken(spaceID, inviteID, inviteKey, record.ExpiresAt)
+	if err == nil && req.BaseURL != "" {
+		resp.URL, err = inviteURL(req.BaseURL, resp.Token)
+	}
+	if err == nil {
+		err = h.store.StoreInvite(ctx, record)
+	}
+	if err != nil {
+		// An invite nobody can track or share must not stay usable
+		if revokeErr := h.spaceManager.ACLManager().RevokeInvite(ctx, spaceID, inviteID); revokeErr != nil {
+			fmt.Printf("[Invites] Warning: failed to revoke untracked invite %s: %v\n", inviteID, revokeErr)
+		}
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to issue invite: %v", err)
+	}
+
+	h.events.Broadcast(aclChangedEvent(spaceID, ACLInviteCreated, ""))
+	fmt.Printf("[Invites] Created %s invite %s for space %s (max uses %d, expires %s)\n",
+		record.Permission, inviteID, spaceID, maxUses, record.ExpiresAt.Format(time.RFC3339))
+	return &resp, http.StatusCreated, nil
+}
+
+// inviteClosing returns the state an active invite should move to, or ""
+// while it stays usable
+func inviteClosing(record *anystore.InviteRecord, now time.Time) string {
+	switch {
+	case record.Status != InviteActive:
+		return ""
+	case record.MaxUses > 0 && record.Uses >= record.MaxUses:
+		return InviteUsed
+	case !now.Before(record.ExpiresAt):
+		return InviteExpired
+	}
+	return ""
+}
+
+// close revokes an invite in its space ACL and records why. The record is
+// only updated once the revoke has been accepted, so a failed revoke is
+// retried by the next sweep.
+func (h *InvitesHandler) close(ctx context.Context, record *anystore.InviteRecord, status string) error {
+	if err := h.spaceManager.ACLManager().RevokeInvite(ctx, record.SpaceID, record.ID); err != nil {
+		return err
+	}
+	closedAt := h.now().UTC()
+	record.Status, record.ClosedAt = status, &closedAt
+	if err := h.store.StoreInvite(ctx, record); err != nil {
+		return err
+	}
+	h.events.Broadcast(aclChangedEvent(record.SpaceID, ACLInviteRevoked, ""))
+	return nil
+}
+
+// Sweep counts each active invite's joins from its space ACL and revokes
+// invites that are used up or expired. It returns how many were revoked.
+// Joins racing a revoke can still land, so an invite may be used more than
+// its maximum by the joins made while it was being revoked.
+func (h *InvitesHandler) Sweep(ctx context.Context) (int, error) {
+	records, err := h.store.ListInvites(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := h.now()
+	redemptions := make(map[string]map[string]int)
+	closed := 0
+	var errs []string
+	for _, record := range records {
+		if record.Status != InviteActive {
+			continue
+		}
+		uses, ok := redemptions[record.SpaceID]
+		if !ok {
+			if uses, err = h.spaceManager.ACLManager().InviteRedemptions(ctx, record.SpaceID); err != nil {
+				errs = append(errs, err.Error())
+				uses = nil
+			}
+			redemptions[record.SpaceID] = uses
+		}
+		if uses != nil && uses[record.ID] != record.Uses {
+			record.Uses = uses[record.ID]
+			if err := h.store.StoreInvite(ctx, record); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		status := inviteClosing(record, now)
+		if status == "" {
+			continue
+		}
+		if err := h.close(ctx, record, status); err != nil {
+			errs = append(errs, fmt.Sprintf("revoking invite %s: %v", record.ID, err))
+			continue
+		}
+		closed++
+		fmt.Printf("[Invites] Revoked %s invite %s for space %s\n", status, record.ID, record.SpaceID)
+	}
+	if len(errs) > 0 {
+		return closed, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return closed, nil
+}
+
+// HandleList handles GET /api/v1/invites. Invites are swept first so use
+// counts and states are current; ?status= filters by state.
+func (h *InvitesHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if _, err := h.Sweep(ctx); err != nil {
+		fmt.Printf("[Invites] Sweep before listing: %v\n", err)
+	}
+	records, err := h.store.ListInvites(ctx)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	invites := make([]*anystore.InviteRecord, 0, len(records))
+	for _, record := range records {
+		if status == "" || record.Status == status {
+			invites = append(invites, record)
+		}
+	}
+	sort.Slice(invites, func(i, j int) bool {
+		return invites[i].CreatedAt.After(invites[j].CreatedAt)
+	})
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"invites": invites,
+		"total":   len(invites),
+	})
+}
+
+// HandleGet handles GET /api/v1/invites/{id}
+func (h *InvitesHandler) HandleGet(w http.ResponseWriter, r *http.Request, id string) {
+	record, err := h.store.GetInvite(r.Context(), id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "invite not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, record)
+}
+
+// HandleRevoke handles DELETE /api/v1/invites/{id}
+func (h *InvitesHandler) HandleRevoke(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+	record, err := h.store.GetInvite(ctx, id)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "invite not found"})
+		return
+	}
+	if record.Status != InviteActive {
+		writeJSON(w, http.StatusOK, record)
+		return
+	}
+	if err := h.close(ctx, record, InviteRevoked); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("failed to revoke invite: %v", err)})
+		return
+	}
+	fmt.Printf("[Invites] Revoked invite %s for space %s\n", record.ID, record.SpaceID)
+	writeJSON(w, http.StatusOK, record)
+}
+
+func (h *InvitesHandler) handleInvites(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.HandleList(w, r)
+	case http.MethodPost:
+		h.HandleCreate(w, r)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+func (h *InvitesHandler) handleInvite(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/invites/")
+	if id == "" || strings.Contains(id, "/") {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		h.HandleGet(w, r, id)
+	case http.MethodDelete:
+		h.HandleRevoke(w, r, id)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
 	}
 }
 
@@ -112,7 +556,194 @@ func (h *InvitesHandler) HandleSendEmail(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// EmailInviteRequest is the body for POST /api/v1/invites/email. Without a
+// token a new invite is created from the embedded fields; with one, that
+// existing invite is sent again.
+type EmailInviteRequest struct {
+	CreateInviteRequest
+	Email       string `json:"email"`
+	InviterName string `json:"inviterName"`
+	InviteeName string `json:"inviteeName,omitempty"`
+	Token       string `json:"token,omitempty"`
+}
+
+// EmailInviteResponse is returned when an invite email is queued. The token
+// is returned so a failed delivery can be sent again.
+type EmailInviteResponse struct {
+	Success  bool                          `json:"success"`
+	Invite   *anystore.InviteRecord        `json:"invite,omitempty"`
+	Delivery *anystore.EmailDeliveryRecord `json:"delivery,omitempty"`
+	Token    string                        `json:"token,omitempty"`
+	URL      string                        `json:"url,omitempty"`
+	Error    string                        `json:"error,omitempty"`
+}
+
+// HandleEmail handles POST /api/v1/invites/email. The email is sent in the
+// background with retries; its delivery record tracks the outcome.
+func (h *InvitesHandler) HandleEmail(w http.ResponseWriter, r *http.Request) {
+	var req EmailInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, EmailInviteResponse{Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+	if _, err := mail.ParseAddress(req.Email); err != nil {
+		writeJSON(w, http.StatusBadRequest, EmailInviteResponse{Error: "a valid email is required"})
+		return
+	}
+	if req.InviterName == "" {
+		writeJSON(w, http.StatusBadRequest, EmailInviteResponse{Error: "inviterName is required"})
+		return
+	}
+	if _, err := inviteURL(req.BaseURL, ""); err != nil {
+		writeJSON(w, http.StatusBadRequest, EmailInviteResponse{Error: err.Error()})
+		return
+	}
+	if h.sendInviteLink == nil {
+		writeJSON(w, http.StatusServiceUnavailable, EmailInviteResponse{Error: "email not configured"})
+		return
+	}
+
+	ctx := r.Context()
+	resp := EmailInviteResponse{Success: true, Token: req.Token}
+	if req.Token != "" {
+		token, _, err := DecodeInviteToken(req.Token)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, EmailInviteResponse{Error: err.Error()})
+			return
+		}
+		record, err := h.store.GetInvite(ctx, token.InviteID)
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, EmailInviteResponse{Error: "invite not found"})
+			return
+		}
+		if record.Status != InviteActive {
+			writeJSON(w, http.StatusGone, EmailInviteResponse{Error: fmt.Sprintf("invite is %s", record.Status)})
+			return
+		}
+		resp.Invite = record
+	} else {
+		created, status, err := h.issue(ctx, &req.CreateInviteRequest)
+		if err != nil {
+			writeJSON(w, status, EmailInviteResponse{Error: err.Error()})
+			return
+		}
+		resp.Invite, resp.Token = created.Invite, created.Token
+	}
+	resp.URL, _ = inviteURL(req.BaseURL, resp.Token)
+
+	now := h.now().UTC()
+	delivery := &anystore.EmailDeliveryRecord{
+		ID:        newDeliveryID(),
+		InviteID:  resp.Invite.ID,
+		To:        req.Email,
+		Status:    EmailPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if p := PrincipalFromContext(ctx); p != nil {
+		delivery.CreatedBy = p.AID
+	}
+	if err := h.store.StoreEmailDelivery(ctx, delivery); err != nil {
+		writeJSON(w, http.StatusInternalServerError, EmailInviteResponse{Error: fmt.Sprintf("failed to record delivery: %v", err)})
+		return
+	}
+	resp.Delivery = delivery
+
+	// The goroutine works on its own copy, so the response isn't raced
+	queued := *delivery
+	go h.deliver(&queued, email.SendInviteLinkRequest{
+		To:          req.Email,
+		InviterName: req.InviterName,
+		InviteeName: req.InviteeName,
+		InviteURL:   resp.URL,
+		ExpiresAt:   resp.Invite.ExpiresAt,
+		SingleUse:   resp.Invite.MaxUses == 1,
+	})
+	writeJSON(w, http.StatusAccepted, resp)
+}
+
+// deliver sends an invite email with retries, recording each attempt. A
+// restart while retrying leaves the delivery pending; send it again with
+// the invite's token.
+func (h *InvitesHandler) deliver(record *anystore.EmailDeliveryRecord, req email.SendInviteLinkRequest) {
+	ctx := context.Background()
+	err := email.Retry(ctx, h.retry, func() error {
+		return h.sendInviteLink(req)
+	}, func(attempt int, err error) {
+		record.Attempts, record.UpdatedAt = attempt, h.now().UTC()
+		switch {
+		case err == nil:
+			sentAt := record.UpdatedAt
+			record.Status, record.LastError, record.SentAt = EmailSent, "", &sentAt
+		case email.Permanent(err) || attempt == h.retry.Attempts:
+			record.Status, record.LastError = EmailFailed, err.Error()
+		default:
+			record.LastError = err.Error()
+		}
+		if storeErr := h.store.StoreEmailDelivery(ctx, record); storeErr != nil {
+			fmt.Printf("[Invites] Warning: failed to record delivery %s: %v\n", record.ID, storeErr)
+		}
+	})
+	if err != nil {
+		if record.Status != EmailFailed {
+			record.Status = EmailFailed
+			if storeErr := h.store.StoreEmailDelivery(ctx, record); storeErr != nil {
+				fmt.Printf("[Invites] Warning: failed to record delivery %s: %v\n", record.ID, storeErr)
+			}
+		}
+		fmt.Printf("[Invites] Email for invite %s failed after %d attempt(s): %v\n", record.InviteID, record.Attempts, err)
+		return
+	}
+	fmt.Printf("[Invites] Emailed invite %s (attempt %d)\n", record.InviteID, record.Attempts)
+}
+
+// newDeliveryID returns a random ID for an email delivery record
+func newDeliveryID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// HandleListEmails handles GET /api/v1/invites/email. ?inviteId= and
+// ?status= filter the deliveries.
+func (h *InvitesHandler) HandleListEmails(w http.ResponseWriter, r *http.Request) {
+	records, err := h.store.ListEmailDeliveries(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	inviteID, status := r.URL.Query().Get("inviteId"), r.URL.Query().Get("status")
+	deliveries := make([]*anystore.EmailDeliveryRecord, 0, len(records))
+	for _, record := range records {
+		if (inviteID == "" || record.InviteID == inviteID) && (status == "" || record.Status == status) {
+			deliveries = append(deliveries, record)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"deliveries": deliveries,
+		"total":      len(deliveries),
+	})
+}
+
+func (h *InvitesHandler) handleEmail(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.HandleListEmails(w, r)
+	case http.MethodPost:
+		h.HandleEmail(w, r)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
 // RegisterRoutes registers invite routes on the mux
 func (h *InvitesHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/v1/invites/send-email", CORSHandler(h.HandleSendEmail))
+	mux.HandleFunc("/api/v1/invites/email", h.handleEmail)
+	mux.HandleFunc("/api/v1/invites", h.handleInvites)
+	mux.HandleFunc("/api/v1/invites/", h.handleInvite)
 }
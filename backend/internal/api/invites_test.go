@@ -0,0 +1,306 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+	"github.com/anyproto/any-sync/util/crypto"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/email"
+)
+
+func setupTestInvites(t *testing.T) *InvitesHandler {
+	t.Helper()
+
+	store, cleanup := setupTrustTestStore(t)
+	t.Cleanup(cleanup)
+
+	spaceManager := anysync.NewSpaceManager(newMockClient(), &anysync.SpaceManagerConfig{
+		CommunitySpaceID:         "test-community-space",
+		CommunityReadOnlySpaceID: "test-readonly-space",
+		OrgAID:                   "EORG123456789",
+	})
+	return NewInvitesHandler(nil, spaceManager, store)
+}
+
+func TestInviteToken_RoundTrip(t *testing.T) {
+	key, _, err := crypto.GenerateRandomEd25519KeyPair()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	expires := time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC)
+
+	token, err := EncodeInviteToken("space-1", "invite-1", key, expires)
+	if err != nil {
+		t.Fatalf("EncodeInviteToken failed: %v", err)
+	}
+	if url.QueryEscape(token) != token {
+		t.Errorf("expected a URL-safe token, got %q", token)
+	}
+
+	decoded, gotKey, err := DecodeInviteToken(token)
+	if err != nil {
+		t.Fatalf("DecodeInviteToken failed: %v", err)
+	}
+	if decoded.SpaceID != "space-1" || decoded.InviteID != "invite-1" || decoded.ExpiresAt != expires.Unix() {
+		t.Errorf("unexpected token %+v", decoded)
+	}
+	if !gotKey.Equals(key) {
+		t.Error("decoded invite key does not match")
+	}
+
+	for _, bad := range []string{"", "not a token", "e30"} {
+		if _, _, err := DecodeInviteToken(bad); err == nil {
+			t.Errorf("expected error decoding %q", bad)
+		}
+	}
+}
+
+func TestInviteURL(t *testing.T) {
+	got, err := inviteURL("https://app.matou.nz/join?ref=email", "abc-123")
+	if err != nil {
+		t.Fatalf("inviteURL failed: %v", err)
+	}
+	if got != "https://app.matou.nz/join?invite=abc-123&ref=email" {
+		t.Errorf("unexpected URL %q", got)
+	}
+	if _, err := inviteURL("/join", "abc-123"); err == nil {
+		t.Error("expected error for a relative base URL")
+	}
+}
+
+func TestInvitesHandler_InviteSpace(t *testing.T) {
+	handler := setupTestInvites(t)
+
+	tests := []struct {
+		name      string
+		req       CreateInviteRequest
+		wantErr   bool
+		wantSpace string
+		wantPerms list.AclPermissions
+	}{
+		{"community default", CreateInviteRequest{}, false, "test-community-space", list.AclPermissionsWriter},
+		{"community reader", CreateInviteRequest{Permission: "reader"}, false, "test-community-space", list.AclPermissionsReader},
+		{"readonly", CreateInviteRequest{Scope: "readonly"}, false, "test-readonly-space", list.AclPermissionsReader},
+		{"readonly writer", CreateInviteRequest{Scope: "readonly", Permission: "writer"}, true, "", list.AclPermissionsNone},
+		{"space", CreateInviteRequest{Scope: "space", SpaceID: "project-space"}, false, "project-space", list.AclPermissionsWriter},
+		{"space without ID", CreateInviteRequest{Scope: "space"}, true, "", list.AclPermissionsNone},
+		{"admin", CreateInviteRequest{Permission: "admin"}, true, "", list.AclPermissionsNone},
+		{"unknown scope", CreateInviteRequest{Scope: "everyone"}, true, "", list.AclPermissionsNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spaceID, _, perms, err := handler.inviteSpace(&tt.req)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %s %v", spaceID, perms)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("inviteSpace failed: %v", err)
+			}
+			if spaceID != tt.wantSpace || perms != tt.wantPerms {
+				t.Errorf("expected %s %v, got %s %v", tt.wantSpace, tt.wantPerms, spaceID, perms)
+			}
+		})
+	}
+}
+
+func TestInviteClosing(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		record anystore.InviteRecord
+		want   string
+	}{
+		{"unused", anystore.InviteRecord{Status: InviteActive, MaxUses: 1, ExpiresAt: now.Add(time.Hour)}, ""},
+		{"used up", anystore.InviteRecord{Status: InviteActive, MaxUses: 1, Uses: 1, ExpiresAt: now.Add(time.Hour)}, InviteUsed},
+		{"unlimited", anystore.InviteRecord{Status: InviteActive, Uses: 5, ExpiresAt: now.Add(time.Hour)}, ""},
+		{"expired", anystore.InviteRecord{Status: InviteActive, ExpiresAt: now}, InviteExpired},
+		{"already revoked", anystore.InviteRecord{Status: InviteRevoked, ExpiresAt: now.Add(-time.Hour)}, ""},
+	}
+	for _, tt := range tests {
+		if got := inviteClosing(&tt.record, now); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestInvitesHandler_Routes(t *testing.T) {
+	handler := setupTestInvites(t)
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	tests := []struct {
+		method, path, body string
+		wantStatus         int
+	}{
+		{http.MethodPut, "/api/v1/invites", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/api/v1/invites", "{", http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/invites", `{"ttl":"2400h"}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/invites", `{"maxUses":-1}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/invites", `{"scope":"space"}`, http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/invites", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/invites/missing", "", http.StatusNotFound},
+		{http.MethodDelete, "/api/v1/invites/missing", "", http.StatusNotFound},
+		{http.MethodPost, "/api/v1/invites/missing", "", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body)))
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s %s %s: expected %d, got %d: %s", tt.method, tt.path, tt.body, tt.wantStatus, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestInvitesHandler_Sweep_SkipsClosed(t *testing.T) {
+	handler := setupTestInvites(t)
+	ctx := context.Background()
+
+	// Closed invites are never looked up in their space again
+	if err := handler.store.StoreInvite(ctx, &anystore.InviteRecord{
+		ID: "invite-1", SpaceID: "unknown-space", Status: InviteUsed, MaxUses: 1, Uses: 1,
+	}); err != nil {
+		t.Fatalf("StoreInvite failed: %v", err)
+	}
+	if closed, err := handler.Sweep(ctx); closed != 0 || err != nil {
+		t.Errorf("expected nothing swept, got %d, %v", closed, err)
+	}
+
+	// An active invite whose space can't be read stays active
+	if err := handler.store.StoreInvite(ctx, &anystore.InviteRecord{
+		ID: "invite-2", SpaceID: "unknown-space", Status: InviteActive, ExpiresAt: time.Now().Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("StoreInvite failed: %v", err)
+	}
+	if _, err := handler.Sweep(ctx); err == nil {
+		t.Error("expected error when the space ACL can't be read")
+	}
+	record, err := handler.store.GetInvite(ctx, "invite-2")
+	if err != nil || record.Status != InviteActive {
+		t.Errorf("expected invite-2 still active, got %+v, %v", record, err)
+	}
+}
+
+func TestInvitesHandler_Email_Validation(t *testing.T) {
+	handler := setupTestInvites(t)
+	handler.sendInviteLink = func(email.SendInviteLinkRequest) error { return nil }
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	tests := []struct {
+		body       string
+		wantStatus int
+	}{
+		{"{", http.StatusBadRequest},
+		{`{"email":"not an address","inviterName":"Aroha","baseUrl":"https://app.matou.nz/join"}`, http.StatusBadRequest},
+		{`{"email":"tama@example.com","baseUrl":"https://app.matou.nz/join"}`, http.StatusBadRequest},
+		{`{"email":"tama@example.com","inviterName":"Aroha"}`, http.StatusBadRequest},
+		{`{"email":"tama@example.com","inviterName":"Aroha","baseUrl":"https://app.matou.nz/join","token":"bad"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/invites/email", bytes.NewBufferString(tt.body)))
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s: expected %d, got %d: %s", tt.body, tt.wantStatus, w.Code, w.Body.String())
+		}
+	}
+
+	handler.sendInviteLink = nil
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/invites/email",
+		bytes.NewBufferString(`{"email":"tama@example.com","inviterName":"Aroha","baseUrl":"https://app.matou.nz/join"}`)))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without email configured, got %d", w.Code)
+	}
+}
+
+func TestInvitesHandler_Email_Resend(t *testing.T) {
+	handler := setupTestInvites(t)
+	handler.retry = email.RetryPolicy{Attempts: 3, InitialDelay: time.Millisecond}
+	ctx := context.Background()
+
+	sent := make(chan email.SendInviteLinkRequest, 1)
+	calls := 0
+	handler.sendInviteLink = func(req email.SendInviteLinkRequest) error {
+		if calls++; calls < 2 {
+			return errors.New("connection refused")
+		}
+		sent <- req
+		return nil
+	}
+
+	key, _, err := crypto.GenerateRandomEd25519KeyPair()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	expires := time.Now().Add(time.Hour)
+	if err := handler.store.StoreInvite(ctx, &anystore.InviteRecord{
+		ID: "invite-1", SpaceID: "test-community-space", Status: InviteActive, MaxUses: 1, ExpiresAt: expires,
+	}); err != nil {
+		t.Fatalf("StoreInvite failed: %v", err)
+	}
+	token, _ := EncodeInviteToken("test-community-space", "invite-1", key, expires)
+
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/invites/email", bytes.NewBufferString(
+		`{"email":"tama@example.com","inviterName":"Aroha","baseUrl":"https://app.matou.nz/join","token":"`+token+`"}`)))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+
+	select {
+	case req := <-sent:
+		if req.To != "tama@example.com" || !req.SingleUse || req.InviteURL != "https://app.matou.nz/join?invite="+token {
+			t.Errorf("unexpected email %+v", req)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("invite email was not sent")
+	}
+
+	// The delivery is recorded once the successful attempt is stored
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		deliveries, _ := handler.store.ListEmailDeliveries(ctx)
+		if len(deliveries) == 1 && deliveries[0].Status == EmailSent {
+			if deliveries[0].Attempts != 2 || deliveries[0].InviteID != "invite-1" || deliveries[0].SentAt == nil {
+				t.Errorf("unexpected delivery %+v", deliveries[0])
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a sent delivery, got %+v", deliveries)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Closed invites aren't sent again
+	record, _ := handler.store.GetInvite(ctx, "invite-1")
+	record.Status = InviteRevoked
+	handler.store.StoreInvite(ctx, record)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/invites/email", bytes.NewBufferString(
+		`{"email":"tama@example.com","inviterName":"Aroha","baseUrl":"https://app.matou.nz/join","token":"`+token+`"}`)))
+	if w.Code != http.StatusGone {
+		t.Errorf("expected 410 for a revoked invite, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/invites/email?inviteId=invite-1&status=sent", nil))
+	if w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte(`"total":1`)) {
+		t.Errorf("expected 1 sent delivery listed, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,271 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/matou-dao/backend/internal/identity"
+)
+
+// keriaPrefix is where the KERIA proxy is mounted. signify-ts is pointed at
+// it for both the admin and boot URLs, and signs paths relative to it.
+const keriaPrefix = "/api/v1/keria"
+
+// signifyResponseHeaders are read by signify-ts to verify KERIA's responses,
+// and Content-Range to page lists, so the browser must be allowed to see them
+const signifyResponseHeaders = "Signature, Signature-Input, Signify-Resource, Signify-Timestamp, Content-Range"
+
+// KERIABinding records which AID a signify controller belongs to
+type KERIABinding struct {
+	Controller string `json:"controller"`
+	Owner      string `json:"owner"`
+	BoundAt    string `json:"boundAt"`
+}
+
+// KERIAProxyHandler forwards signify requests to KERIA so the frontend only
+// needs the backend's port.
+//
+// Requests under /api/v1/keria go to the KERIA admin API with the prefix
+// removed; /api/v1/keria/boot goes to the boot API. Each signify controller
+// (the Signify-Resource header) is bound to the first AID that uses it, and
+// other AIDs are refused. KERIA still checks the request signatures.
+type KERIAProxyHandler struct {
+	admin        *httputil.ReverseProxy
+	boot         *httputil.ReverseProxy
+	userIdentity *identity.UserIdentity
+	limit        int // Requests per minute per AID; 0 is unlimited
+	limiter      *rateLimiter
+	now          func() time.Time
+
+	mu           sync.Mutex
+	bindingsPath string
+	bindings     map[string]KERIABinding // By controller AID
+}
+
+// NewKERIAProxyHandler creates a proxy to the KERIA admin and boot URLs.
+// Controller bindings are persisted to {dataDir}/keria-bindings.json.
+func NewKERIAProxyHandler(adminURL, bootURL, dataDir string, userIdentity *identity.UserIdentity, limit int) (*KERIAProxyHandler, error) {
+	h := &KERIAProxyHandler{
+		userIdentity: userIdentity,
+		limit:        limit,
+		limiter:      newRateLimiter(),
+		now:          time.Now,
+		bindings:     make(map[string]KERIABinding),
+	}
+	var err error
+	if h.admin, err = newKERIAReverseProxy(adminURL); err != nil {
+		return nil, fmt.Errorf("KERIA admin URL: %w", err)
+	}
+	if h.boot, err = newKERIAReverseProxy(bootURL); err != nil {
+		return nil, fmt.Errorf("KERIA boot URL: %w", err)
+	}
+	if dataDir != "" {
+		h.bindingsPath = filepath.Join(dataDir, "keria-bindings.json")
+		h.loadBindings()
+	}
+	return h, nil
+}
+
+func newKERIAReverseProxy(rawURL string) (*httputil.ReverseProxy, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if target.Scheme == "" || target.Host == "" {
+		return nil, fmt.Errorf("%q is not an absolute URL", rawURL)
+	}
+	return &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			// signify signed the path without the proxy prefix
+			r.Out.URL.Path = strings.TrimPrefix(r.In.URL.Path, keriaPrefix)
+			r.Out.URL.RawPath = ""
+			r.SetURL(target)
+			r.SetXForwarded()
+			// Backend credentials are for the backend, not KERIA
+			r.Out.Header.Del("Authorization")
+			r.Out.Header.Del("Cookie")
+		},
+		ModifyResponse: func(res *http.Response) error {
+			// CORS is the backend's job; KERIA's own headers would duplicate it
+			for name := range res.Header {
+				if strings.HasPrefix(name, "Access-Control-") {
+					res.Header.Del(name)
+				}
+			}
+			res.Header.Set("Access-Control-Expose-Headers", signifyResponseHeaders)
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			writeJSON(w, http.StatusBadGateway, map[string]string{
+				"error": fmt.Sprintf("KERIA unavailable: %v", err),
+			})
+		},
+	}, nil
+}
+
+// ServeHTTP handles /api/v1/keria/*
+func (h *KERIAProxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	owner, unrestricted := h.owner(r)
+
+	if !unrestricted {
+		limitKey := owner
+		if limitKey == "" {
+			limitKey = "client|" + clientKey(r)
+		}
+		if ok, retry := h.limiter.allow(limitKey, h.limit, h.now()); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retry.Seconds())+1))
+			writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": "rate limit exceeded"})
+			return
+		}
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, keriaPrefix)
+	if path == "/boot" {
+		h.boot.ServeHTTP(w, r)
+		return
+	}
+
+	controller := r.Header.Get("Signify-Resource")
+	if controller == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "signed signify request required"})
+		return
+	}
+	if !unrestricted {
+		if err := h.bind(controller, owner); err != nil {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+			return
+		}
+	}
+	h.admin.ServeHTTP(w, r)
+}
+
+// owner returns the AID a request acts for. Admin API keys and service tokens are
+// unrestricted. Without authentication the caller is the local user.
+func (h *KERIAProxyHandler) owner(r *http.Request) (owner string, unrestricted bool) {
+	if p := PrincipalFromContext(r.Context()); p != nil {
+		switch {
+		case p.AID != "":
+			return p.AID, false
+		case p.Admin:
+			return "", true
+		default:
+			return p.Kind + ":" + p.Name, false
+		}
+	}
+	if h.userIdentity != nil {
+		return h.userIdentity.GetAID(), false
+	}
+	return "", false
+}
+
+// bind checks controller belongs to owner, binding it on first use. An
+// empty owner (no identity yet, as during onboarding) is allowed but
+// doesn't bind.
+func (h *KERIAProxyHandler) bind(controller, owner string) error {
+	if owner == "" {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if binding, ok := h.bindings[controller]; ok {
+		if binding.Owner != owner {
+			return fmt.Errorf("signify controller %s belongs to another AID", controller)
+		}
+		return nil
+	}
+	h.bindings[controller] = KERIABinding{
+		Controller: controller,
+		Owner:      owner,
+		BoundAt:    h.now().UTC().Format(time.RFC3339),
+	}
+	if err := h.saveBindings(); err != nil {
+		fmt.Printf("[KERIA] Warning: failed to save controller bindings: %v\n", err)
+	}
+	return nil
+}
+
+// Bindings returns the controller bindings, sorted by controller
+func (h *KERIAProxyHandler) Bindings() []KERIABinding {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	bindings := make([]KERIABinding, 0, len(h.bindings))
+	for _, b := range h.bindings {
+		bindings = append(bindings, b)
+	}
+	sort.Slice(bindings, func(i, j int) bool { return bindings[i].Controller < bindings[j].Controller })
+	return bindings
+}
+
+// Unbind removes a controller's binding, returning false if it had none
+func (h *KERIAProxyHandler) Unbind(controller string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.bindings[controller]; !ok {
+		return false
+	}
+	delete(h.bindings, controller)
+	if err := h.saveBindings(); err != nil {
+		fmt.Printf("[KERIA] Warning: failed to save controller bindings: %v\n", err)
+	}
+	return true
+}
+
+// HandleBindings handles GET /api/v1/admin/keria/bindings and
+// DELETE /api/v1/admin/keria/bindings/{controller}
+func (h *KERIAProxyHandler) HandleBindings(w http.ResponseWriter, r *http.Request) {
+	controller := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/admin/keria/bindings"), "/")
+	switch {
+	case r.Method == http.MethodGet && controller == "":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"bindings": h.Bindings()})
+	case r.Method == http.MethodDelete && controller != "":
+		if !h.Unbind(controller) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "binding not found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// RegisterRoutes registers the proxy and binding routes on the mux
+func (h *KERIAProxyHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.Handle(keriaPrefix+"/", h)
+	mux.HandleFunc("/api/v1/admin/keria/bindings", h.HandleBindings)
+	mux.HandleFunc("/api/v1/admin/keria/bindings/", h.HandleBindings)
+}
+
+// saveBindings writes the bindings to disk. Caller must hold h.mu.
+func (h *KERIAProxyHandler) saveBindings() error {
+	if h.bindingsPath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(h.bindings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(h.bindingsPath, data, 0600)
+}
+
+func (h *KERIAProxyHandler) loadBindings() {
+	data, err := os.ReadFile(h.bindingsPath)
+	if err != nil {
+		return // No bindings yet
+	}
+	if err := json.Unmarshal(data, &h.bindings); err != nil {
+		fmt.Printf("[KERIA] Warning: failed to parse %s: %v\n", h.bindingsPath, err)
+		h.bindings = make(map[string]KERIABinding)
+	}
+}
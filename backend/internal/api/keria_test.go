@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+// keriaUpstream records the last request it served
+type keriaUpstream struct {
+	*httptest.Server
+	path string
+	auth string
+}
+
+func newKERIAUpstream(t *testing.T, name string) *keriaUpstream {
+	t.Helper()
+	u := &keriaUpstream{}
+	u.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u.path, u.auth = r.URL.Path, r.Header.Get("Authorization")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Signify-Resource", "EAGENT")
+		w.Write([]byte(name))
+	}))
+	t.Cleanup(u.Close)
+	return u
+}
+
+func newTestKERIAProxy(t *testing.T, dataDir, aid string, limit int) (*KERIAProxyHandler, *keriaUpstream, *keriaUpstream) {
+	t.Helper()
+	admin, boot := newKERIAUpstream(t, "admin"), newKERIAUpstream(t, "boot")
+	userIdentity := identity.New(t.TempDir())
+	if aid != "" {
+		if err := userIdentity.SetIdentity(aid, secret.NewMnemonic("test mnemonic")); err != nil {
+			t.Fatalf("SetIdentity failed: %v", err)
+		}
+	}
+	h, err := NewKERIAProxyHandler(admin.URL, boot.URL, dataDir, userIdentity, limit)
+	if err != nil {
+		t.Fatalf("NewKERIAProxyHandler failed: %v", err)
+	}
+	return h, admin, boot
+}
+
+func keriaRequest(method, path, controller string, principal *Principal) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	if controller != "" {
+		req.Header.Set("Signify-Resource", controller)
+	}
+	req.Header.Set("Authorization", "Bearer backend-token")
+	if principal != nil {
+		req = req.WithContext(context.WithValue(req.Context(), principalKey{}, principal))
+	}
+	return req
+}
+
+func TestKERIAProxy_Forwards(t *testing.T) {
+	h, admin, boot := newTestKERIAProxy(t, t.TempDir(), "EUSER", 0)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, keriaRequest(http.MethodGet, "/api/v1/keria/identifiers", "ECONTROLLER", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "admin" {
+		t.Fatalf("expected admin response, got %d: %s", w.Code, w.Body.String())
+	}
+	if admin.path != "/identifiers" {
+		t.Errorf("expected prefix stripped, got %s", admin.path)
+	}
+	if admin.auth != "" {
+		t.Error("expected backend Authorization header removed")
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Error("expected KERIA's CORS headers removed")
+	}
+	if w.Header().Get("Signify-Resource") != "EAGENT" || w.Header().Get("Access-Control-Expose-Headers") == "" {
+		t.Errorf("expected signify headers exposed, got %v", w.Header())
+	}
+
+	// Boot doesn't need a controller header
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, keriaRequest(http.MethodPost, "/api/v1/keria/boot", "", nil))
+	if w.Body.String() != "boot" || boot.path != "/boot" {
+		t.Errorf("expected boot server to get /boot, got %s at %s", w.Body.String(), boot.path)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, keriaRequest(http.MethodGet, "/api/v1/keria/identifiers", "", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for unsigned request, got %d", w.Code)
+	}
+}
+
+func TestKERIAProxy_ControllerBinding(t *testing.T) {
+	dataDir := t.TempDir()
+	h, _, _ := newTestKERIAProxy(t, dataDir, "", 0)
+	alice := &Principal{Kind: "token", AID: "EALICE"}
+	bob := &Principal{Kind: "token", AID: "EBOB"}
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		want      int
+	}{
+		{"first use binds", alice, http.StatusOK},
+		{"same AID", alice, http.StatusOK},
+		{"other AID refused", bob, http.StatusForbidden},
+		{"non-admin key refused", &Principal{Kind: "apiKey", Name: "ci"}, http.StatusForbidden},
+		{"admin key unrestricted", &Principal{Kind: "apiKey", Name: "ops", Admin: true}, http.StatusOK},
+		{"no identity allowed", nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, keriaRequest(http.MethodGet, "/api/v1/keria/agent/ECTRL", "ECTRL", tt.principal))
+			if w.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// Bindings survive a restart
+	reloaded, _, _ := newTestKERIAProxy(t, dataDir, "", 0)
+	if bindings := reloaded.Bindings(); len(bindings) != 1 || bindings[0].Owner != "EALICE" {
+		t.Fatalf("expected persisted binding to EALICE, got %+v", bindings)
+	}
+
+	// Admins can release a binding
+	w := httptest.NewRecorder()
+	reloaded.HandleBindings(w, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/keria/bindings/ECTRL", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected unbind to succeed, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	reloaded.HandleBindings(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/keria/bindings", nil))
+	var resp struct {
+		Bindings []KERIABinding `json:"bindings"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.Bindings) != 0 {
+		t.Errorf("expected no bindings, got %+v", resp.Bindings)
+	}
+}
+
+func TestKERIAProxy_RateLimit(t *testing.T) {
+	h, _, _ := newTestKERIAProxy(t, "", "EUSER", 1)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, keriaRequest(http.MethodGet, "/api/v1/keria/operations", "ECTRL", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected first request served, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, keriaRequest(http.MethodGet, "/api/v1/keria/operations", "ECTRL", nil))
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("expected 429 with Retry-After, got %d", w.Code)
+	}
+}
@@ -0,0 +1,435 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+)
+
+// Lock timing
+const (
+	// guardLockTTL bounds a lease taken for a single guarded request, in
+	// case the request never finishes
+	guardLockTTL = 2 * time.Minute
+	// defaultLockTTL and maxLockTTL apply to leases taken explicitly
+	defaultLockTTL = 5 * time.Minute
+	maxLockTTL     = time.Hour
+)
+
+// lockObjectType is the admin space object type for shared leases
+const lockObjectType = "OrgLock"
+
+// Lease is a held lock on an org resource such as "org-config"
+type Lease struct {
+	Resource   string    `json:"resource"`
+	Holder     string    `json:"holder"` // AID, "apiKey:<name>", "serviceToken:<name>" or "local"
+	Operation  string    `json:"operation,omitempty"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Released   bool      `json:"released,omitempty"`
+	// Remote is set for leases learned from another backend
+	Remote bool `json:"remote,omitempty"`
+}
+
+// activeAt reports whether the lease still holds its resource
+func (l *Lease) activeAt(now time.Time) bool {
+	return l != nil && !l.Released && now.Before(l.ExpiresAt)
+}
+
+// LockConflictError is returned when another holder has the resource
+type LockConflictError struct {
+	Lease *Lease
+}
+
+func (e *LockConflictError) Error() string {
+	return fmt.Sprintf("%s is locked by %s until %s", e.Lease.Resource, e.Lease.Holder, e.Lease.ExpiresAt.Format(time.RFC3339))
+}
+
+// LockStore shares leases with other backends
+type LockStore interface {
+	// ReadLock returns the latest lease for a resource, or nil
+	ReadLock(ctx context.Context, resource string) (*Lease, error)
+	WriteLock(ctx context.Context, lease *Lease) error
+}
+
+// lockRule guards a route with a resource lock
+type lockRule struct {
+	method   string
+	path     string // Exact, or a prefix when it ends in "/"
+	resource string
+}
+
+// LockManager coordinates admins changing org state. Leases are held in
+// memory and, with a LockStore, mirrored to the admin space so admins on
+// other backends see them. Sync is not instant, so two backends can still
+// race inside the sync delay; the mirror narrows the window rather than
+// closing it.
+type LockManager struct {
+	userIdentity *identity.UserIdentity
+	store        LockStore
+	rules        []lockRule
+	now          func() time.Time
+
+	mu     sync.Mutex
+	leases map[string]*Lease // By resource
+}
+
+// NewLockManager creates a lock manager. userIdentity names the holder for
+// unauthenticated requests and may be nil.
+func NewLockManager(userIdentity *identity.UserIdentity) *LockManager {
+	return &LockManager{
+		userIdentity: userIdentity,
+		now:          time.Now,
+		leases:       make(map[string]*Lease),
+	}
+}
+
+// SetStore sets where leases are shared with other backends
+func (m *LockManager) SetStore(store LockStore) {
+	m.store = store
+}
+
+// Guard makes requests matching pattern hold resource while they run. The
+// pattern is a path, optionally preceded by a method; paths ending in "/"
+// match by prefix.
+func (m *LockManager) Guard(pattern, resource string) {
+	rule := lockRule{path: pattern, resource: resource}
+	if method, path, ok := strings.Cut(pattern, " "); ok {
+		rule.method, rule.path = strings.ToUpper(method), strings.TrimSpace(path)
+	}
+	m.rules = append(m.rules, rule)
+}
+
+// resourceFor returns the resource a request must hold, or empty
+func (m *LockManager) resourceFor(r *http.Request) string {
+	for _, rule := range m.rules {
+		if rule.method != "" && rule.method != r.Method {
+			continue
+		}
+		if matchesRoute(r.URL.Path, []string{rule.path}) {
+			return rule.resource
+		}
+	}
+	return ""
+}
+
+// Acquire takes resource for holder. If holder already has it, the lease
+// is extended and acquired is false, so nested callers don't release a
+// lease they didn't take. Returns a *LockConflictError if someone else
+// holds it, here or on another backend.
+func (m *LockManager) Acquire(ctx context.Context, resource, holder, operation string, ttl time.Duration) (lease *Lease, acquired bool, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if current := m.leases[resource]; current.activeAt(now) {
+		if current.Holder != holder {
+			return nil, false, &LockConflictError{Lease: current}
+		}
+		if expires := now.Add(ttl); expires.After(current.ExpiresAt) {
+			current.ExpiresAt = expires
+			m.publish(ctx, current)
+		}
+		copied := *current
+		return &copied, false, nil
+	}
+
+	if m.store != nil {
+		remote, err := m.store.ReadLock(ctx, resource)
+		if err != nil {
+			fmt.Printf("[Locks] Warning: failed to read shared lock %s: %v\n", resource, err)
+		} else if remote.activeAt(now) && remote.Holder != holder {
+			remote.Remote = true
+			return nil, false, &LockConflictError{Lease: remote}
+		}
+	}
+
+	lease = &Lease{
+		Resource:   resource,
+		Holder:     holder,
+		Operation:  operation,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	m.leases[resource] = lease
+	m.publish(ctx, lease)
+	copied := *lease
+	return &copied, true, nil
+}
+
+// Release frees resource if holder has it. force releases it whatever the
+// holder. Returns false if there was nothing to release.
+func (m *LockManager) Release(ctx context.Context, resource, holder string, force bool) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current := m.leases[resource]
+	if !current.activeAt(m.now()) {
+		delete(m.leases, resource)
+		return false, nil
+	}
+	if current.Holder != holder && !force {
+		return false, &LockConflictError{Lease: current}
+	}
+	delete(m.leases, resource)
+	released := *current
+	released.Released = true
+	released.ExpiresAt = m.now()
+	m.publish(ctx, &released)
+	return true, nil
+}
+
+// Lease returns the active lease on resource, checking other backends if
+// there is none here. Returns nil if the resource is free.
+func (m *LockManager) Lease(ctx context.Context, resource string) *Lease {
+	m.mu.Lock()
+	now := m.now()
+	if current := m.leases[resource]; current.activeAt(now) {
+		copied := *current
+		m.mu.Unlock()
+		return &copied
+	}
+	m.mu.Unlock()
+
+	if m.store == nil {
+		return nil
+	}
+	remote, err := m.store.ReadLock(ctx, resource)
+	if err != nil || !remote.activeAt(now) {
+		return nil
+	}
+	remote.Remote = true
+	return remote
+}
+
+// Resources returns every guarded resource and any with a local lease
+func (m *LockManager) Resources() []string {
+	seen := make(map[string]bool)
+	for _, rule := range m.rules {
+		seen[rule.resource] = true
+	}
+	m.mu.Lock()
+	for resource := range m.leases {
+		seen[resource] = true
+	}
+	m.mu.Unlock()
+
+	resources := make([]string, 0, len(seen))
+	for resource := range seen {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	return resources
+}
+
+// publish mirrors a lease to the store. Caller must hold m.mu. Failures
+// are logged: the local lease still protects this backend.
+func (m *LockManager) publish(ctx context.Context, lease *Lease) {
+	if m.store == nil {
+		return
+	}
+	if err := m.store.WriteLock(ctx, lease); err != nil {
+		fmt.Printf("[Locks] Warning: failed to share lock %s: %v\n", lease.Resource, err)
+	}
+}
+
+// holder names the caller: the token's AID, the API key, or the local user
+func (m *LockManager) holder(r *http.Request) string {
+	if p := PrincipalFromContext(r.Context()); p != nil {
+		if p.AID != "" {
+			return p.AID
+		}
+		return p.Kind + ":" + p.Name
+	}
+	if m.userIdentity != nil {
+		if aid := m.userIdentity.GetAID(); aid != "" {
+			return aid
+		}
+	}
+	return "local"
+}
+
+// writeLockConflict responds 409 with the holder's lease
+func writeLockConflict(w http.ResponseWriter, conflict *LockConflictError) {
+	if retry := time.Until(conflict.Lease.ExpiresAt); retry > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retry.Seconds())+1))
+	}
+	writeJSON(w, http.StatusConflict, map[string]interface{}{
+		"error": conflict.Error(),
+		"lock":  conflict.Lease,
+	})
+}
+
+// LockMiddleware holds the guarded resource for the length of each
+// matching request, answering 409 while another admin holds it
+func LockMiddleware(m *LockManager, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resource := m.resourceFor(r)
+		if resource == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// Tenants lock their own config, not the primary org's
+		if t := TenantFromContext(r.Context()); t != nil {
+			resource = t.AID + ":" + resource
+		}
+
+		holder := m.holder(r)
+		operation := r.Method + " " + r.URL.Path
+		_, acquired, err := m.Acquire(r.Context(), resource, holder, operation, guardLockTTL)
+		if conflict, ok := err.(*LockConflictError); ok {
+			writeLockConflict(w, conflict)
+			return
+		}
+		if acquired {
+			defer m.Release(context.WithoutCancel(r.Context()), resource, holder, false)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// acquireLockRequest is the body for POST /api/v1/admin/locks/{resource}
+type acquireLockRequest struct {
+	Operation  string `json:"operation,omitempty"`
+	TTLSeconds int    `json:"ttlSeconds,omitempty"`
+}
+
+// HandleLocks handles GET /api/v1/admin/locks
+func (m *LockManager) HandleLocks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	locks := make([]*Lease, 0)
+	for _, resource := range m.Resources() {
+		if lease := m.Lease(r.Context(), resource); lease != nil {
+			locks = append(locks, lease)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"resources": m.Resources(),
+		"locks":     locks,
+	})
+}
+
+// HandleLock handles GET, POST and DELETE /api/v1/admin/locks/{resource}.
+// POST takes a lease that spans several requests; guarded requests from the
+// same holder pass while it is held. DELETE releases it, and ?force=true
+// releases another holder's lease.
+func (m *LockManager) HandleLock(w http.ResponseWriter, r *http.Request) {
+	resource := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/admin/locks/"), "/")
+	if resource == "" || strings.Contains(resource, "/") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "resource is required"})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		lease := m.Lease(r.Context(), resource)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"resource": resource,
+			"locked":   lease != nil,
+			"lock":     lease,
+		})
+
+	case http.MethodPost:
+		var req acquireLockRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{
+					"error": fmt.Sprintf("invalid request body: %v", err),
+				})
+				return
+			}
+		}
+		ttl := defaultLockTTL
+		if req.TTLSeconds > 0 {
+			ttl = time.Duration(req.TTLSeconds) * time.Second
+		}
+		if ttl > maxLockTTL {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("ttlSeconds must be at most %d", int(maxLockTTL.Seconds())),
+			})
+			return
+		}
+		lease, _, err := m.Acquire(r.Context(), resource, m.holder(r), req.Operation, ttl)
+		if conflict, ok := err.(*LockConflictError); ok {
+			writeLockConflict(w, conflict)
+			return
+		}
+		writeJSON(w, http.StatusOK, lease)
+
+	case http.MethodDelete:
+		released, err := m.Release(r.Context(), resource, m.holder(r), r.URL.Query().Get("force") == "true")
+		if conflict, ok := err.(*LockConflictError); ok {
+			writeLockConflict(w, conflict)
+			return
+		}
+		if !released {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "no lock held on " + resource})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// RegisterRoutes registers lock status routes on the mux
+func (m *LockManager) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/admin/locks", m.HandleLocks)
+	mux.HandleFunc("/api/v1/admin/locks/", m.HandleLock)
+}
+
+// AdminSpaceLockStore shares leases as OrgLock objects in the admin space,
+// one object per resource
+type AdminSpaceLockStore struct {
+	spaceManager *anysync.SpaceManager
+}
+
+// NewAdminSpaceLockStore creates a lock store backed by the admin space
+func NewAdminSpaceLockStore(spaceManager *anysync.SpaceManager) *AdminSpaceLockStore {
+	return &AdminSpaceLockStore{spaceManager: spaceManager}
+}
+
+func lockObjectID(resource string) string {
+	return "OrgLock-" + resource
+}
+
+// ReadLock implements LockStore. Without an admin space there is nothing
+// to share, so it reports no lease.
+func (s *AdminSpaceLockStore) ReadLock(ctx context.Context, resource string) (*Lease, error) {
+	adminSpaceID := s.spaceManager.GetAdminSpaceID()
+	if adminSpaceID == "" {
+		return nil, nil
+	}
+	obj, err := s.spaceManager.ObjectTreeManager().ReadLatestByID(ctx, adminSpaceID, lockObjectID(resource))
+	if err != nil {
+		return nil, nil // Never locked
+	}
+	var lease Lease
+	if err := json.Unmarshal(obj.Data, &lease); err != nil {
+		return nil, fmt.Errorf("parsing lock object: %w", err)
+	}
+	return &lease, nil
+}
+
+// WriteLock implements LockStore
+func (s *AdminSpaceLockStore) WriteLock(ctx context.Context, lease *Lease) error {
+	adminSpaceID := s.spaceManager.GetAdminSpaceID()
+	if adminSpaceID == "" {
+		return nil
+	}
+	_, err := writeObject(ctx, s.spaceManager, adminSpaceID, lockObjectID(lease.Resource), lockObjectType, lease)
+	return err
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memLockStore stands in for the admin space
+type memLockStore struct {
+	leases map[string]Lease
+}
+
+func (s *memLockStore) ReadLock(ctx context.Context, resource string) (*Lease, error) {
+	lease, ok := s.leases[resource]
+	if !ok {
+		return nil, nil
+	}
+	return &lease, nil
+}
+
+func (s *memLockStore) WriteLock(ctx context.Context, lease *Lease) error {
+	s.leases[lease.Resource] = *lease
+	return nil
+}
+
+func asAID(req *http.Request, aid string) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), principalKey{}, &Principal{Kind: "token", AID: aid}))
+}
+
+func TestLockManager_AcquireRelease(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewLockManager(nil)
+	m.now = func() time.Time { return now }
+
+	if _, acquired, err := m.Acquire(ctx, "org-config", "EALICE", "edit", time.Minute); err != nil || !acquired {
+		t.Fatalf("expected lock acquired, got %v", err)
+	}
+	// Reentrant for the same holder, but not a new acquisition
+	if _, acquired, err := m.Acquire(ctx, "org-config", "EALICE", "edit", time.Minute); err != nil || acquired {
+		t.Fatalf("expected reentrant acquire, got acquired=%v err=%v", acquired, err)
+	}
+
+	var conflict *LockConflictError
+	if _, _, err := m.Acquire(ctx, "org-config", "EBOB", "edit", time.Minute); !errors.As(err, &conflict) || conflict.Lease.Holder != "EALICE" {
+		t.Fatalf("expected conflict with EALICE, got %v", err)
+	}
+	if _, err := m.Release(ctx, "org-config", "EBOB", false); !errors.As(err, &conflict) {
+		t.Errorf("expected non-holder release refused, got %v", err)
+	}
+
+	// Expired leases free the resource
+	now = now.Add(2 * time.Minute)
+	if _, _, err := m.Acquire(ctx, "org-config", "EBOB", "edit", time.Minute); err != nil {
+		t.Fatalf("expected expired lease replaced, got %v", err)
+	}
+	if released, err := m.Release(ctx, "org-config", "EALICE", true); err != nil || !released {
+		t.Errorf("expected force release, got %v", err)
+	}
+	if m.Lease(ctx, "org-config") != nil {
+		t.Error("expected resource free")
+	}
+}
+
+func TestLockManager_SharedStore(t *testing.T) {
+	ctx := context.Background()
+	store := &memLockStore{leases: make(map[string]Lease)}
+	here, there := NewLockManager(nil), NewLockManager(nil)
+	here.SetStore(store)
+	there.SetStore(store)
+
+	if _, _, err := there.Acquire(ctx, "issuance", "EBOB", "issue", time.Minute); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	if lease := here.Lease(ctx, "issuance"); lease == nil || !lease.Remote || lease.Holder != "EBOB" {
+		t.Fatalf("expected remote lease held by EBOB, got %+v", lease)
+	}
+	var conflict *LockConflictError
+	if _, _, err := here.Acquire(ctx, "issuance", "EALICE", "issue", time.Minute); !errors.As(err, &conflict) {
+		t.Fatalf("expected conflict with remote lease, got %v", err)
+	}
+
+	if _, err := there.Release(ctx, "issuance", "EBOB", false); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, _, err := here.Acquire(ctx, "issuance", "EALICE", "issue", time.Minute); err != nil {
+		t.Errorf("expected released remote lease ignored, got %v", err)
+	}
+}
+
+func TestLockMiddleware(t *testing.T) {
+	m := NewLockManager(nil)
+	m.Guard("POST /api/v1/org/config", "org-config")
+
+	var heldDuring *Lease
+	handler := LockMiddleware(m, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		heldDuring = m.Lease(r.Context(), "org-config")
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, asAID(httptest.NewRequest(http.MethodPost, "/api/v1/org/config", nil), "EALICE"))
+	if w.Code != http.StatusOK || heldDuring == nil || heldDuring.Holder != "EALICE" {
+		t.Fatalf("expected lock held during request, got %d, %+v", w.Code, heldDuring)
+	}
+	if m.Lease(context.Background(), "org-config") != nil {
+		t.Fatal("expected lock released after request")
+	}
+
+	// An explicit lease blocks other admins until released
+	w = httptest.NewRecorder()
+	m.HandleLock(w, asAID(httptest.NewRequest(http.MethodPost, "/api/v1/admin/locks/org-config", strings.NewReader(`{"operation":"migration","ttlSeconds":60}`)), "EALICE"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected lease taken, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, asAID(httptest.NewRequest(http.MethodPost, "/api/v1/org/config", nil), "EBOB"))
+	if w.Code != http.StatusConflict || w.Header().Get("Retry-After") == "" {
+		t.Errorf("expected 409 with Retry-After, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, asAID(httptest.NewRequest(http.MethodGet, "/api/v1/org/config", nil), "EBOB"))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected reads unguarded, got %d", w.Code)
+	}
+
+	// The holder's own requests pass and keep the lease
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, asAID(httptest.NewRequest(http.MethodPost, "/api/v1/org/config", nil), "EALICE"))
+	if w.Code != http.StatusOK || m.Lease(context.Background(), "org-config") == nil {
+		t.Errorf("expected holder's request served and lease kept, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	m.HandleLock(w, asAID(httptest.NewRequest(http.MethodDelete, "/api/v1/admin/locks/org-config", nil), "EBOB"))
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected 409 releasing another admin's lease, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	m.HandleLock(w, asAID(httptest.NewRequest(http.MethodDelete, "/api/v1/admin/locks/org-config?force=true", nil), "EBOB"))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected force release, got %d", w.Code)
+	}
+}
@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultMaintenanceMessage is returned when maintenance is enabled without a message
+const defaultMaintenanceMessage = "The server is undergoing maintenance. Please try again shortly."
+
+// MaintenanceMode tracks whether the API is in maintenance mode.
+// Background jobs call Checkpoint between units of work so they pause
+// at safe points while maintenance is active.
+type MaintenanceMode struct {
+	mu         sync.RWMutex
+	enabled    bool
+	message    string
+	allowReads bool
+	since      time.Time
+	resume     chan struct{} // closed when maintenance is disabled
+}
+
+// MaintenanceStatus is the JSON representation of maintenance state
+type MaintenanceStatus struct {
+	Enabled    bool       `json:"enabled"`
+	Message    string     `json:"message,omitempty"`
+	AllowReads bool       `json:"allowReads"`
+	Since      *time.Time `json:"since,omitempty"`
+}
+
+// NewMaintenanceMode creates a maintenance mode tracker (initially disabled)
+func NewMaintenanceMode() *MaintenanceMode {
+	return &MaintenanceMode{}
+}
+
+// Enable puts the API into maintenance mode. If allowReads is true,
+// GET/HEAD requests continue to be served.
+func (m *MaintenanceMode) Enable(message string, allowReads bool) {
+	if message == "" {
+		message = defaultMaintenanceMessage
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.enabled {
+		m.since = time.Now().UTC()
+		m.resume = make(chan struct{})
+	}
+	m.enabled = true
+	m.message = message
+	m.allowReads = allowReads
+}
+
+// Disable takes the API out of maintenance mode and releases paused jobs
+func (m *MaintenanceMode) Disable() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.enabled {
+		return
+	}
+	m.enabled = false
+	m.message = ""
+	m.allowReads = false
+	close(m.resume)
+	m.resume = nil
+}
+
+// Enabled reports whether maintenance mode is active
+func (m *MaintenanceMode) Enabled() bool {
+	if m == nil {
+		return false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enabled
+}
+
+// Status returns the current maintenance state
+func (m *MaintenanceMode) Status() MaintenanceStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	status := MaintenanceStatus{
+		Enabled:    m.enabled,
+		Message:    m.message,
+		AllowReads: m.allowReads,
+	}
+	if m.enabled {
+		since := m.since
+		status.Since = &since
+	}
+	return status
+}
+
+// Checkpoint blocks while maintenance mode is active. Background jobs call it
+// before starting a unit of work. Returns ctx.Err() if the context is cancelled
+// while waiting.
+func (m *MaintenanceMode) Checkpoint(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	resume := m.resume
+	m.mu.RUnlock()
+
+	if resume == nil {
+		return nil
+	}
+	select {
+	case <-resume:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// refuses reports whether a request should be refused under current state
+func (m *MaintenanceMode) refuses(r *http.Request) (bool, string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.enabled {
+		return false, ""
+	}
+	if m.allowReads && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+		return false, ""
+	}
+	return true, m.message
+}
+
+// MaintenanceMiddleware returns 503 for requests refused by maintenance mode.
+// Health checks, metrics, preflight requests, and the maintenance endpoint
+// itself are always served so operators can observe and lift maintenance.
+func MaintenanceMiddleware(m *MaintenanceMode, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions ||
+			r.URL.Path == "/health" ||
+			r.URL.Path == "/metrics" ||
+			strings.HasPrefix(r.URL.Path, "/api/v1/admin/maintenance") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if refuse, message := m.refuses(r); refuse {
+			w.Header().Set("Retry-After", "60")
+			writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{
+				"error":       message,
+				"maintenance": true,
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// MaintenanceHandler exposes the maintenance toggle
+type MaintenanceHandler struct {
+	mode *MaintenanceMode
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(mode *MaintenanceMode) *MaintenanceHandler {
+	return &MaintenanceHandler{mode: mode}
+}
+
+// SetMaintenanceRequest is the body for POST /api/v1/admin/maintenance
+type SetMaintenanceRequest struct {
+	Enabled    bool   `json:"enabled"`
+	Message    string `json:"message,omitempty"`
+	AllowReads bool   `json:"allowReads"`
+}
+
+// HandleMaintenance handles GET and POST /api/v1/admin/maintenance
+func (h *MaintenanceHandler) HandleMaintenance(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, h.mode.Status())
+	case http.MethodPost, http.MethodPut:
+		var req SetMaintenanceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("invalid request body: %v", err),
+			})
+			return
+		}
+		if req.Enabled {
+			h.mode.Enable(req.Message, req.AllowReads)
+			fmt.Printf("[Maintenance] Enabled (allowReads=%t)\n", req.AllowReads)
+		} else {
+			h.mode.Disable()
+			fmt.Println("[Maintenance] Disabled")
+		}
+		writeJSON(w, http.StatusOK, h.mode.Status())
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+	}
+}
+
+// RegisterRoutes registers maintenance routes on the mux
+func (h *MaintenanceHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/admin/maintenance", h.HandleMaintenance)
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceMiddleware_RefusesMutations(t *testing.T) {
+	mode := NewMaintenanceMode()
+	mode.Enable("", true)
+
+	handler := MaintenanceMiddleware(mode, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodGet, "/api/v1/credentials", http.StatusOK},
+		{http.MethodPost, "/api/v1/credentials", http.StatusServiceUnavailable},
+		{http.MethodDelete, "/api/v1/identity", http.StatusServiceUnavailable},
+		{http.MethodPost, "/api/v1/admin/maintenance", http.StatusOK},
+		{http.MethodGet, "/health", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.path, tt.want, w.Code)
+		}
+	}
+}
+
+func TestMaintenanceMiddleware_RefusesReadsWhenNotAllowed(t *testing.T) {
+	mode := NewMaintenanceMode()
+	mode.Enable("back soon", false)
+
+	handler := MaintenanceMiddleware(mode, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/trust/summary", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "back soon") {
+		t.Errorf("expected maintenance message in body, got %s", w.Body.String())
+	}
+
+	mode.Disable()
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200 after disabling, got %d", w.Code)
+	}
+}
+
+func TestMaintenanceMode_Checkpoint(t *testing.T) {
+	mode := NewMaintenanceMode()
+	if err := mode.Checkpoint(context.Background()); err != nil {
+		t.Fatalf("expected no wait when disabled, got %v", err)
+	}
+
+	mode.Enable("", false)
+	released := make(chan error, 1)
+	go func() {
+		released <- mode.Checkpoint(context.Background())
+	}()
+
+	select {
+	case <-released:
+		t.Fatal("checkpoint should block while maintenance is enabled")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	mode.Disable()
+	select {
+	case err := <-released:
+		if err != nil {
+			t.Errorf("expected nil after disable, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("checkpoint was not released after disable")
+	}
+
+	mode.Enable("", false)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := mode.Checkpoint(ctx); err == nil {
+		t.Error("expected context error while paused")
+	}
+}
@@ -0,0 +1,278 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/taxonomy"
+)
+
+// Match ranking weights: skill overlap dominates, trust proximity breaks ties
+// between similarly skilled members
+const (
+	matchSkillWeight     = 0.7
+	matchProximityWeight = 0.3
+	defaultMatchLimit    = 20
+	maxMatchLimit        = 100
+)
+
+// MatchHandler finds collaborators by skill overlap and trust proximity
+type MatchHandler struct {
+	spaceManager *anysync.SpaceManager
+	userIdentity *identity.UserIdentity
+	trust        *TrustHandler
+	taxonomy     *TaxonomyHandler
+}
+
+// NewMatchHandler creates a new match handler
+func NewMatchHandler(
+	spaceManager *anysync.SpaceManager,
+	userIdentity *identity.UserIdentity,
+	trustHandler *TrustHandler,
+	taxonomyHandler *TaxonomyHandler,
+) *MatchHandler {
+	return &MatchHandler{
+		spaceManager: spaceManager,
+		userIdentity: userIdentity,
+		trust:        trustHandler,
+		taxonomy:     taxonomyHandler,
+	}
+}
+
+// MemberMatch is a ranked collaborator suggestion
+type MemberMatch struct {
+	AID           string   `json:"aid"`
+	DisplayName   string   `json:"displayName,omitempty"`
+	Role          string   `json:"role,omitempty"`
+	MatchedSkills []string `json:"matchedSkills"`
+	SkillOverlap  float64  `json:"skillOverlap"` // Fraction of requested skills the member has
+	Hops          int      `json:"hops"`         // Trust graph distance from you, -1 if unconnected
+	TrustScore    float64  `json:"trustScore"`   // Member's trust score
+	MatchScore    float64  `json:"matchScore"`   // Combined ranking score
+}
+
+// matchCandidate is a member's profile skills joined with trust data
+type matchCandidate struct {
+	aid         string
+	displayName string
+	role        string
+	skills      []string
+	hops        int
+	trustScore  float64
+}
+
+// HandleMatch handles GET /api/v1/members/match
+// Query params:
+//   - skills: Comma-separated skills to look for (required)
+//   - minScore: Minimum trust score (optional)
+//   - limit: Max results (optional, default 20, max 100)
+func (h *MatchHandler) HandleMatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+
+	query := r.URL.Query()
+	wanted := splitTags(query.Get("skills"))
+	if len(wanted) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "skills is required"})
+		return
+	}
+
+	minScore := 0.0
+	if s := query.Get("minScore"); s != "" {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "minScore must be a number"})
+			return
+		}
+		minScore = v
+	}
+
+	limit := defaultMatchLimit
+	if l := query.Get("limit"); l != "" {
+		if v, err := strconv.Atoi(l); err == nil && v > 0 {
+			limit = v
+		}
+	}
+	if limit > maxMatchLimit {
+		limit = maxMatchLimit
+	}
+
+	ctx := r.Context()
+
+	// Resolve requested skills to canonical taxonomy IDs where possible
+	vocab := h.taxonomy.Vocabulary(ctx, taxonomy.KindSkills)
+	if vocab != nil {
+		wanted = resolveTags(vocab, wanted)
+	}
+
+	candidates, err := h.candidates(ctx, me, vocab)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	matches := rankMatches(candidates, wanted, minScore)
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"skills":  wanted,
+		"matches": matches,
+		"count":   len(matches),
+	})
+}
+
+// candidates joins SharedProfile skills with trust graph position
+func (h *MatchHandler) candidates(ctx context.Context, me string, vocab *taxonomy.Vocabulary) ([]matchCandidate, error) {
+	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
+	if communitySpaceID == "" {
+		return nil, fmt.Errorf("community space not configured")
+	}
+
+	profiles, err := readLatestObjects(ctx, h.spaceManager, communitySpaceID, "SharedProfile")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %v", err)
+	}
+
+	graph, err := h.trust.buildGraph(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trust graph: %v", err)
+	}
+	h.trust.addContributions(ctx, graph)
+	scores := h.trust.scoreCalculator().CalculateAllScores(graph)
+	distances := graph.Distances(me)
+
+	var result []matchCandidate
+	for _, obj := range profiles {
+		var p struct {
+			AID         string   `json:"aid"`
+			DisplayName string   `json:"displayName"`
+			Skills      []string `json:"skills"`
+		}
+		if err := json.Unmarshal(obj.Data, &p); err != nil || p.AID == "" || p.AID == me {
+			continue
+		}
+		node := graph.GetNode(p.AID)
+		if node == nil {
+			continue // not a credentialed member
+		}
+
+		c := matchCandidate{
+			aid:         p.AID,
+			displayName: p.DisplayName,
+			role:        node.Role,
+			skills:      p.Skills,
+			hops:        -1,
+		}
+		if vocab != nil {
+			c.skills = resolveTags(vocab, p.Skills)
+		}
+		if d, ok := distances[p.AID]; ok {
+			c.hops = d
+		}
+		if s, ok := scores[p.AID]; ok {
+			c.trustScore = s.Score
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// rankMatches scores candidates by skill overlap and trust proximity. Members
+// with no requested skills or below minScore are dropped.
+func rankMatches(candidates []matchCandidate, wanted []string, minScore float64) []*MemberMatch {
+	wantedSet := make(map[string]bool, len(wanted))
+	for _, s := range wanted {
+		wantedSet[strings.ToLower(s)] = true
+	}
+
+	matches := make([]*MemberMatch, 0)
+	for _, c := range candidates {
+		if c.trustScore < minScore {
+			continue
+		}
+
+		matched := make([]string, 0)
+		seen := make(map[string]bool)
+		for _, s := range c.skills {
+			key := strings.ToLower(s)
+			if wantedSet[key] && !seen[key] {
+				seen[key] = true
+				matched = append(matched, s)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		overlap := float64(len(matched)) / float64(len(wantedSet))
+		proximity := 0.0
+		if c.hops > 0 {
+			proximity = 1 / float64(c.hops)
+		}
+
+		matches = append(matches, &MemberMatch{
+			AID:           c.aid,
+			DisplayName:   c.displayName,
+			Role:          c.role,
+			MatchedSkills: matched,
+			SkillOverlap:  overlap,
+			Hops:          c.hops,
+			TrustScore:    c.trustScore,
+			MatchScore:    matchSkillWeight*overlap + matchProximityWeight*proximity,
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].MatchScore != matches[j].MatchScore {
+			return matches[i].MatchScore > matches[j].MatchScore
+		}
+		return matches[i].TrustScore > matches[j].TrustScore
+	})
+	return matches
+}
+
+// splitTags splits a comma-separated list, trimming blanks
+func splitTags(s string) []string {
+	var tags []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			tags = append(tags, part)
+		}
+	}
+	return tags
+}
+
+// resolveTags maps tags to canonical taxonomy IDs, keeping unknown tags as-is
+func resolveTags(vocab *taxonomy.Vocabulary, tags []string) []string {
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if id, ok := vocab.Resolve(tag); ok {
+			result = append(result, id)
+		} else {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// RegisterRoutes registers match routes on the mux
+func (h *MatchHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/members/match", h.HandleMatch)
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestRankMatches(t *testing.T) {
+	candidates := []matchCandidate{
+		{aid: "EFAR", skills: []string{"coding", "design"}, hops: 3, trustScore: 5},
+		{aid: "ENEAR", skills: []string{"coding"}, hops: 1, trustScore: 2},
+		{aid: "ENONE", skills: []string{"cooking"}, hops: 1, trustScore: 9},
+		{aid: "ELOW", skills: []string{"coding", "design"}, hops: 1, trustScore: 0.5},
+		{aid: "EUNCONNECTED", skills: []string{"Coding"}, hops: -1, trustScore: 3},
+	}
+
+	matches := rankMatches(candidates, []string{"coding", "design"}, 1)
+
+	order := make([]string, 0, len(matches))
+	for _, m := range matches {
+		order = append(order, m.AID)
+	}
+	want := []string{"EFAR", "ENEAR", "EUNCONNECTED"}
+	if len(order) != len(want) {
+		t.Fatalf("expected %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, order)
+		}
+	}
+
+	if matches[0].SkillOverlap != 1 {
+		t.Errorf("expected full overlap for EFAR, got %v", matches[0].SkillOverlap)
+	}
+	if matches[2].Hops != -1 || matches[2].MatchScore != matchSkillWeight*0.5 {
+		t.Errorf("expected no proximity bonus for unconnected member, got %+v", matches[2])
+	}
+}
+
+func TestSplitTags(t *testing.T) {
+	tags := splitTags(" coding, ,design ,")
+	if len(tags) != 2 || tags[0] != "coding" || tags[1] != "design" {
+		t.Errorf("unexpected tags: %v", tags)
+	}
+}
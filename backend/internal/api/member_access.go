@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/audit"
+)
+
+// MemberAccessGranter adds newly credentialed members to the community
+// spaces' ACLs. Members are identified in ACLs by their backend's peer ID,
+// so the granter keeps an AID to peer ID mapping.
+type MemberAccessGranter struct {
+	spaceManager *anysync.SpaceManager
+	store        *anystore.LocalStore
+	records      anystore.RecordStore
+	events       *EventBroker
+	receipts     ReceiptRecorder
+	audit        *audit.Log
+	now          func() time.Time
+}
+
+// NewMemberAccessGranter creates a member access granter
+func NewMemberAccessGranter(spaceManager *anysync.SpaceManager, store *anystore.LocalStore) *MemberAccessGranter {
+	g := &MemberAccessGranter{
+		spaceManager: spaceManager,
+		store:        store,
+		now:          time.Now,
+	}
+	if store != nil {
+		g.records = store
+	}
+	return g
+}
+
+// SetRecordStore keeps peer mappings in records instead of the local store
+func (g *MemberAccessGranter) SetRecordStore(records anystore.RecordStore) {
+	g.records = records
+}
+
+// SetEvents broadcasts an acl:changed event for each grant
+func (g *MemberAccessGranter) SetEvents(events *EventBroker) {
+	g.events = events
+}
+
+// SetAudit records each grant and revocation in the audit log
+func (g *MemberAccessGranter) SetAudit(log *audit.Log) {
+	g.audit = log
+}
+
+// MemberAccessResult reports the ACL grants made for a member
+type MemberAccessResult struct {
+	PeerID  string   `json:"peerId,omitempty"`
+	Granted []string `json:"granted,omitempty"` // Space IDs the peer was added to
+	Skipped string   `json:"skipped,omitempty"` // Why no grant was attempted
+	Error   string   `json:"error,omitempty"`
+}
+
+// errPeerMapped is returned when recording a peer would replace the one an
+// AID is already mapped to without an admin's approval
+var errPeerMapped = errors.New("a different peer is already mapped to this AID")
+
+// RecordPeer maps an AID to the peer ID its backend signs with. AID tokens
+// are accepted from the mapped peer, so an existing mapping to a different
+// peer is only replaced when an admin approves it.
+func (g *MemberAccessGranter) RecordPeer(ctx context.Context, aid, peerID string, approved bool) error {
+	pub, err := anysync.DecodeACLIdentity(peerID)
+	if err != nil {
+		return err
+	}
+	if existing, err := g.records.GetPeerMapping(ctx, aid); err == nil && !approved {
+		// Mappings may hold a peer ID or an account address, so compare keys
+		if mapped, err := anysync.DecodeACLIdentity(existing.PeerID); err != nil || !mapped.Equals(pub) {
+			return errPeerMapped
+		}
+	}
+	return g.records.StorePeerMapping(ctx, &anystore.PeerMapping{
+		AID:       aid,
+		PeerID:    peerID,
+		UpdatedAt: time.Now().UTC(),
+	})
+}
+
+// GrantMember adds the AID's mapped peer to the community space as a writer
+// and to the community-readonly space as a reader. Granting is idempotent,
+// so it is safe to repeat after a partial failure. Members without a mapped
+// peer are skipped; they can still join with the invite key sent alongside
+// their credential.
+func (g *MemberAccessGranter) GrantMember(ctx context.Context, aid string) MemberAccessResult {
+	mapping, err := g.records.GetPeerMapping(ctx, aid)
+	if err != nil {
+		return MemberAccessResult{Skipped: "no peer ID mapped to this AID"}
+	}
+	result := MemberAccessResult{PeerID: mapping.PeerID}
+
+	client := g.spaceManager.GetClient()
+	if client == nil {
+		result.Error = "any-sync client not available"
+		return result
+	}
+
+	grants := []struct {
+		spaceID     string
+		permissions []string
+	}{
+		{g.spaceManager.GetCommunitySpaceID(), []string{"write"}},
+		{g.spaceManager.GetCommunityReadOnlySpaceID(), []string{"read"}},
+	}
+	for _, grant := range grants {
+		if grant.spaceID == "" {
+			continue
+		}
+		if err := client.AddToACL(ctx, grant.spaceID, mapping.PeerID, grant.permissions); err != nil {
+			result.Error = fmt.Sprintf("granting access to space %s: %v", grant.spaceID, err)
+			return result
+		}
+		result.Granted = append(result.Granted, grant.spaceID)
+		g.events.Broadcast(aclChangedEvent(grant.spaceID, ACLMemberAdded, aid))
+		g.audit.Record(ctx, audit.ActionACLGrant, auditActor(ctx, nil), grant.spaceID,
+			nil, aclMember(aid, mapping.PeerID, grant.permissions))
+	}
+
+	fmt.Printf("[MemberAccess] Granted %s (peer %s) access to %v\n", truncateAID(aid), mapping.PeerID, result.Granted)
+	return result
+}
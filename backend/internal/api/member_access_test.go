@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/anyproto/any-sync/util/crypto"
+	"github.com/matou-dao/backend/internal/anysync"
+)
+
+func setupTestMemberAccess(t *testing.T) (*MemberAccessGranter, *mockAnySyncClient) {
+	t.Helper()
+
+	store, cleanup := setupTrustTestStore(t)
+	t.Cleanup(cleanup)
+
+	mockClient := newMockClient()
+	spaceManager := anysync.NewSpaceManager(mockClient, &anysync.SpaceManagerConfig{
+		CommunitySpaceID:         "test-community-space",
+		CommunityReadOnlySpaceID: "test-readonly-space",
+		OrgAID:                   "EORG123456789",
+	})
+	return NewMemberAccessGranter(spaceManager, store), mockClient
+}
+
+func testPeerID(t *testing.T) string {
+	t.Helper()
+
+	_, pub, err := crypto.GenerateRandomEd25519KeyPair()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	peerID, err := crypto.IdFromSigningPubKey(pub)
+	if err != nil {
+		t.Fatalf("deriving peer ID: %v", err)
+	}
+	return peerID.String()
+}
+
+func TestMemberAccessGranter_RecordPeer_Invalid(t *testing.T) {
+	granter, _ := setupTestMemberAccess(t)
+
+	if err := granter.RecordPeer(context.Background(), "EUSER123", "not-a-peer", false); err == nil {
+		t.Error("expected error for invalid peer ID")
+	}
+}
+
+func TestMemberAccessGranter_RecordPeer_Replace(t *testing.T) {
+	granter, _ := setupTestMemberAccess(t)
+	ctx := context.Background()
+
+	peerID := testPeerID(t)
+	if err := granter.RecordPeer(ctx, "EUSER123", peerID, false); err != nil {
+		t.Fatalf("RecordPeer failed: %v", err)
+	}
+	if err := granter.RecordPeer(ctx, "EUSER123", peerID, false); err != nil {
+		t.Errorf("expected recording the same peer again to succeed, got %v", err)
+	}
+
+	// Tokens are accepted from the mapped peer, so it can't be swapped unapproved
+	other := testPeerID(t)
+	if err := granter.RecordPeer(ctx, "EUSER123", other, false); !errors.Is(err, errPeerMapped) {
+		t.Fatalf("expected errPeerMapped, got %v", err)
+	}
+	if mapping, _ := granter.records.GetPeerMapping(ctx, "EUSER123"); mapping.PeerID != peerID {
+		t.Errorf("expected the mapping kept, got %s", mapping.PeerID)
+	}
+
+	if err := granter.RecordPeer(ctx, "EUSER123", other, true); err != nil {
+		t.Fatalf("expected an approved replacement to succeed, got %v", err)
+	}
+	if mapping, _ := granter.records.GetPeerMapping(ctx, "EUSER123"); mapping.PeerID != other {
+		t.Errorf("expected the approved peer, got %s", mapping.PeerID)
+	}
+}
+
+func TestMemberAccessGranter_GrantMember(t *testing.T) {
+	granter, mockClient := setupTestMemberAccess(t)
+	ctx := context.Background()
+
+	// Unmapped members are skipped
+	result := granter.GrantMember(ctx, "EUSER123")
+	if result.Skipped == "" || len(mockClient.aclGrants) != 0 {
+		t.Fatalf("expected skip for unmapped AID, got %+v", result)
+	}
+
+	peerID := testPeerID(t)
+	if err := granter.RecordPeer(ctx, "EUSER123", peerID, false); err != nil {
+		t.Fatalf("RecordPeer failed: %v", err)
+	}
+
+	result = granter.GrantMember(ctx, "EUSER123")
+	if result.Error != "" {
+		t.Fatalf("GrantMember error: %s", result.Error)
+	}
+	if result.PeerID != peerID || len(result.Granted) != 2 {
+		t.Errorf("expected grants to both community spaces, got %+v", result)
+	}
+	want := []string{"test-community-space:" + peerID, "test-readonly-space:" + peerID}
+	if fmt.Sprint(mockClient.aclGrants) != fmt.Sprint(want) {
+		t.Errorf("expected ACL grants %v, got %v", want, mockClient.aclGrants)
+	}
+}
+
+func TestMemberAccessGranter_GrantMember_ACLError(t *testing.T) {
+	granter, mockClient := setupTestMemberAccess(t)
+	ctx := context.Background()
+
+	if err := granter.RecordPeer(ctx, "EUSER123", testPeerID(t), false); err != nil {
+		t.Fatalf("RecordPeer failed: %v", err)
+	}
+	mockClient.addToACLErr = fmt.Errorf("not an admin")
+
+	if result := granter.GrantMember(ctx, "EUSER123"); result.Error == "" || len(result.Granted) != 0 {
+		t.Errorf("expected ACL error, got %+v", result)
+	}
+}
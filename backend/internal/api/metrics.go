@@ -0,0 +1,28 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/matou-dao/backend/internal/metrics"
+)
+
+// unmatchedRoute labels requests that matched no registered pattern, so
+// scans of random paths can't grow the route label set
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware records each request's latency against the mux pattern
+// it was routed to (e.g. "/api/v1/profiles/"), never the raw path.
+func MetricsMiddleware(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &loggingResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		route := unmatchedRoute
+		if _, pattern := mux.Handler(r); pattern != "" {
+			route = pattern
+		}
+		metrics.ObserveHTTPRequest(route, r.Method, rec.status, time.Since(start))
+	})
+}
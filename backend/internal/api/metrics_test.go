@@ -0,0 +1,59 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/metrics"
+)
+
+func TestMetricsMiddleware_LabelsByRoutePattern(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/metrics-test/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := MetricsMiddleware(mux, mux)
+
+	for _, path := range []string{"/api/v1/metrics-test/a", "/api/v1/metrics-test/b", "/no/such/route"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(w.Body)
+
+	for _, want := range []string{
+		`route="/api/v1/metrics-test/",status="418"} 2`,
+		`route="unmatched",status="404"} 1`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics output missing %q", want)
+		}
+	}
+	if strings.Contains(string(body), "/api/v1/metrics-test/a") {
+		t.Error("raw request paths must not be used as route labels")
+	}
+}
+
+func TestAccessMiddleware_ServesMetricsToGuests(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	a := newTestAccessControl(t, "", NewTrustHandler(store, "EORG", nil), 1)
+	handler := AccessMiddleware(a, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// Scrapes are anonymous and frequent, so neither the member gate nor
+	// the guest rate limit applies
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("scrape %d: expected 200, got %d", i, w.Code)
+		}
+	}
+}
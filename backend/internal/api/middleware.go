@@ -63,7 +63,8 @@ func CORSMiddleware(next http.Handler) http.Handler {
 
 		// Allow common headers and methods
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization, X-Requested-With")
+		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization, X-Requested-With, Signature, Signature-Input, Signify-Resource, Signify-Timestamp, X-Org-AID, X-Consistency-Token")
+		w.Header().Set("Access-Control-Expose-Headers", ConsistencyHeader+", "+DataVersionHeader)
 		w.Header().Set("Access-Control-Max-Age", "86400")
 
 		// Handle preflight requests
@@ -86,7 +87,8 @@ func CORSHandler(handler http.HandlerFunc) http.HandlerFunc {
 		}
 
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization, X-Requested-With")
+		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization, X-Requested-With, Signature, Signature-Input, Signify-Resource, Signify-Timestamp, X-Org-AID, X-Consistency-Token")
+		w.Header().Set("Access-Control-Expose-Headers", ConsistencyHeader+", "+DataVersionHeader)
 
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusOK)
@@ -126,7 +128,8 @@ func (m *CORSMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if isAllowedOrigin(origin) {
 		w.Header().Set("Access-Control-Allow-Origin", origin)
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization, X-Requested-With")
+		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization, X-Requested-With, Signature, Signature-Input, Signify-Resource, Signify-Timestamp, X-Org-AID, X-Consistency-Token")
+		w.Header().Set("Access-Control-Expose-Headers", ConsistencyHeader+", "+DataVersionHeader)
 	}
 
 	if r.Method == http.MethodOptions {
@@ -136,3 +139,21 @@ func (m *CORSMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	m.mux.ServeHTTP(w, r)
 }
+
+// RouteGroupMiddleware restricts a handler to the given path prefixes,
+// returning 404 for anything else. An empty prefix list allows all paths.
+// Used to expose only some route groups on a listener (e.g. admin-only port).
+func RouteGroupMiddleware(prefixes []string, next http.Handler) http.Handler {
+	if len(prefixes) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+}
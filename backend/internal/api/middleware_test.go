@@ -335,7 +335,7 @@ func TestCORSMiddleware_AllowedHeaders(t *testing.T) {
 	wrapped.ServeHTTP(w, req)
 
 	headers := w.Header().Get("Access-Control-Allow-Headers")
-	expectedHeaders := []string{"Accept", "Content-Type", "Authorization"}
+	expectedHeaders := []string{"Accept", "Content-Type", "Authorization", "Signature-Input", "Signify-Resource"}
 
 	for _, header := range expectedHeaders {
 		if !containsMethod(headers, header) {
@@ -362,3 +362,23 @@ func containsMiddle(s, substr string) bool {
 	}
 	return false
 }
+
+func TestRouteGroupMiddleware(t *testing.T) {
+	handler := RouteGroupMiddleware([]string{"/api/v1/admin/", "/health"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := map[string]int{
+		"/api/v1/admin/flags": http.StatusOK,
+		"/health":             http.StatusOK,
+		"/api/v1/credentials": http.StatusNotFound,
+	}
+	for path, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, w.Code)
+		}
+	}
+}
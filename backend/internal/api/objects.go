@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anysync"
+)
+
+// writeObject stores a typed object in a space's ObjectTree, signed with the
+// space's signing key. Writing an existing objectID appends a new version.
+func writeObject(ctx context.Context, sm *anysync.SpaceManager, spaceID, objectID, typeName string, data interface{}) (*anysync.ObjectPayload, error) {
+	client := sm.GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("any-sync client not available")
+	}
+
+	keys, err := anysync.LoadSpaceKeySet(client.GetDataDir(), spaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load space keys: %w", err)
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal object: %w", err)
+	}
+
+	objMgr := sm.ObjectTreeManager()
+	version := 1
+	if existing, err := objMgr.ReadLatestByID(ctx, spaceID, objectID); err == nil {
+		version = existing.Version + 1
+	}
+
+	ownerKey := ""
+	if keys.SigningKey != nil {
+		if pubKeyBytes, err := keys.SigningKey.GetPublic().Marshall(); err == nil {
+			ownerKey = fmt.Sprintf("%x", pubKeyBytes)
+		}
+	}
+
+	payload := &anysync.ObjectPayload{
+		ID:        objectID,
+		Type:      typeName,
+		OwnerKey:  ownerKey,
+		Data:      raw,
+		Timestamp: time.Now().Unix(),
+		Version:   version,
+	}
+
+	if _, err := objMgr.AddObject(ctx, spaceID, payload, keys.SigningKey); err != nil {
+		return nil, fmt.Errorf("failed to write object: %w", err)
+	}
+	return payload, nil
+}
+
+// readLatestObjects returns the latest version of every object of a type in a space.
+func readLatestObjects(ctx context.Context, sm *anysync.SpaceManager, spaceID, typeName string) ([]*anysync.ObjectPayload, error) {
+	objects, err := sm.ObjectTreeManager().ReadObjectsByType(ctx, spaceID, typeName)
+	if err != nil {
+		return nil, err
+	}
+	return deduplicateObjects(objects), nil
+}
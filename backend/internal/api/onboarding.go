@@ -0,0 +1,297 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+)
+
+// Onboarding steps, in order
+const (
+	StepIdentitySet        = "identity_set"
+	StepOOBIExchanged      = "oobi_exchanged"
+	StepCredentialReceived = "credential_received"
+	StepProfileCreated     = "profile_created"
+	StepSpacesJoined       = "spaces_joined"
+)
+
+// onboardingSteps is the onboarding state machine's step order
+var onboardingSteps = []string{
+	StepIdentitySet,
+	StepOOBIExchanged,
+	StepCredentialReceived,
+	StepProfileCreated,
+	StepSpacesJoined,
+}
+
+// How a step was completed
+const (
+	StepSourceObserved = "observed" // Seen in backend data
+	StepSourceReported = "reported" // Advanced by the frontend
+	StepSourceImplied  = "implied"  // A later step was observed
+)
+
+// OnboardingHandler computes the local user's onboarding progress from
+// backend data and records steps the frontend reports.
+type OnboardingHandler struct {
+	store        *anystore.LocalStore
+	records      anystore.RecordStore
+	spaceManager *anysync.SpaceManager
+	userIdentity *identity.UserIdentity
+}
+
+// NewOnboardingHandler creates a new onboarding handler
+func NewOnboardingHandler(store *anystore.LocalStore, spaceManager *anysync.SpaceManager, userIdentity *identity.UserIdentity) *OnboardingHandler {
+	h := &OnboardingHandler{
+		store:        store,
+		spaceManager: spaceManager,
+		userIdentity: userIdentity,
+	}
+	if store != nil {
+		h.records = store
+	}
+	return h
+}
+
+// SetRecordStore keeps reported steps in records instead of the local store
+func (h *OnboardingHandler) SetRecordStore(records anystore.RecordStore) {
+	h.records = records
+}
+
+// OnboardingStep is one step of the onboarding state machine
+type OnboardingStep struct {
+	Name        string `json:"name"`
+	Complete    bool   `json:"complete"`
+	Source      string `json:"source,omitempty"`
+	CompletedAt string `json:"completedAt,omitempty"` // Set for reported steps
+}
+
+// OnboardingState is the response for GET /api/v1/onboarding/state
+type OnboardingState struct {
+	AID      string           `json:"aid,omitempty"`
+	Steps    []OnboardingStep `json:"steps"`
+	Current  string           `json:"current,omitempty"` // First incomplete step
+	Complete bool             `json:"complete"`
+}
+
+// AdvanceOnboardingRequest is the body for POST /api/v1/onboarding/advance
+type AdvanceOnboardingRequest struct {
+	Step string `json:"step"`
+}
+
+// State computes the local user's onboarding state. A step is complete when
+// its evidence is in backend data or the frontend reported it; steps before
+// an observed step are implied, since the sequence can't be skipped.
+func (h *OnboardingHandler) State(ctx context.Context) *OnboardingState {
+	aid := ""
+	if h.userIdentity != nil {
+		aid = h.userIdentity.GetAID()
+	}
+	state := &OnboardingState{AID: aid, Steps: make([]OnboardingStep, len(onboardingSteps))}
+
+	reported := h.reportedSteps(ctx, aid)
+	for i, name := range onboardingSteps {
+		step := OnboardingStep{Name: name}
+		if h.observe(ctx, name, aid) {
+			step.Complete, step.Source = true, StepSourceObserved
+			for j := 0; j < i; j++ {
+				if !state.Steps[j].Complete {
+					state.Steps[j].Complete, state.Steps[j].Source = true, StepSourceImplied
+				}
+			}
+		} else if at, ok := reported[name]; ok {
+			step.Complete, step.Source, step.CompletedAt = true, StepSourceReported, at
+		}
+		state.Steps[i] = step
+	}
+
+	for _, step := range state.Steps {
+		if !step.Complete {
+			state.Current = step.Name
+			break
+		}
+	}
+	state.Complete = state.Current == ""
+	return state
+}
+
+// observe checks backend data for a step's evidence
+func (h *OnboardingHandler) observe(ctx context.Context, step, aid string) bool {
+	if aid == "" {
+		return false
+	}
+	switch step {
+	case StepIdentitySet:
+		return true
+	case StepOOBIExchanged:
+		return h.hasKEL(ctx, aid)
+	case StepCredentialReceived:
+		return h.hasMembershipCredential(ctx, aid)
+	case StepProfileCreated:
+		return h.hasProfile(ctx, aid)
+	case StepSpacesJoined:
+		return h.spaceManager != nil && verifyCommunityAccess(ctx, h.spaceManager, aid).HasAccess
+	}
+	return false
+}
+
+// hasKEL reports whether the user's KEL is cached. The frontend syncs the
+// KEL it resolved from the user's OOBI.
+func (h *OnboardingHandler) hasKEL(ctx context.Context, aid string) bool {
+	if h.store == nil {
+		return false
+	}
+	coll, err := h.store.KELCache(ctx)
+	if err != nil {
+		return false
+	}
+	query := anystore.MustParseJSON(fmt.Sprintf(`{"userAid": %q}`, aid))
+	count, err := coll.Find(query).Count(ctx)
+	return err == nil && count > 0
+}
+
+// hasMembershipCredential reports whether a membership credential for the
+// user is cached
+func (h *OnboardingHandler) hasMembershipCredential(ctx context.Context, aid string) bool {
+	if h.store == nil {
+		return false
+	}
+	coll, err := h.store.CredentialsCache(ctx)
+	if err != nil {
+		return false
+	}
+	query := anystore.MustParseJSON(fmt.Sprintf(`{"schemaID": "EMatouMembershipSchemaV1", "subjectAID": %q}`, aid))
+	count, err := coll.Find(query).Count(ctx)
+	return err == nil && count > 0
+}
+
+// hasProfile reports whether the user's shared profile is in the community
+// space or their private profile is in their private space
+func (h *OnboardingHandler) hasProfile(ctx context.Context, aid string) bool {
+	if h.spaceManager == nil || h.spaceManager.GetClient() == nil {
+		return false
+	}
+	objMgr := h.spaceManager.ObjectTreeManager()
+	if spaceID := h.spaceManager.GetCommunitySpaceID(); spaceID != "" {
+		if _, err := objMgr.ReadLatestByID(ctx, spaceID, fmt.Sprintf("SharedProfile-%s", aid)); err == nil {
+			return true
+		}
+	}
+	if spaceID := h.userIdentity.GetPrivateSpaceID(); spaceID != "" {
+		if _, err := objMgr.ReadLatestByID(ctx, spaceID, fmt.Sprintf("PrivateProfile-%s", aid)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// onboardingPreferenceKey is the preference holding an AID's reported steps
+func onboardingPreferenceKey(aid string) string {
+	return "onboarding:" + aid
+}
+
+// reportedSteps loads the steps the frontend reported, keyed by step name
+// with the time they were reported
+func (h *OnboardingHandler) reportedSteps(ctx context.Context, aid string) map[string]string {
+	steps := make(map[string]string)
+	if h.records == nil || aid == "" {
+		return steps
+	}
+	value, err := h.records.GetPreference(ctx, onboardingPreferenceKey(aid))
+	if err != nil {
+		return steps
+	}
+	data, _ := json.Marshal(value)
+	json.Unmarshal(data, &steps)
+	return steps
+}
+
+// HandleState handles GET /api/v1/onboarding/state
+func (h *OnboardingHandler) HandleState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, h.State(r.Context()))
+}
+
+// HandleAdvance handles POST /api/v1/onboarding/advance
+// Records that the frontend completed a step. Only the current step can be
+// advanced; completed steps are accepted as a no-op.
+func (h *OnboardingHandler) HandleAdvance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	var req AdvanceOnboardingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+
+	ctx := r.Context()
+	status, err := h.advance(ctx, req.Step)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, h.State(ctx))
+}
+
+// advance records a reported step
+func (h *OnboardingHandler) advance(ctx context.Context, step string) (int, error) {
+	known := false
+	for _, name := range onboardingSteps {
+		if name == step {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return http.StatusBadRequest, fmt.Errorf("unknown onboarding step %q", step)
+	}
+
+	state := h.State(ctx)
+	if state.AID == "" {
+		return http.StatusConflict, fmt.Errorf("identity not set; call POST /api/v1/identity/set first")
+	}
+	for _, s := range state.Steps {
+		if s.Name == step && s.Complete {
+			return http.StatusOK, nil
+		}
+	}
+	if step != state.Current {
+		return http.StatusConflict, fmt.Errorf("cannot advance %s before %s", step, state.Current)
+	}
+	if h.records == nil {
+		return http.StatusServiceUnavailable, fmt.Errorf("local store not available")
+	}
+
+	reported := h.reportedSteps(ctx, state.AID)
+	reported[step] = time.Now().UTC().Format(time.RFC3339)
+	if err := h.records.SetPreference(ctx, onboardingPreferenceKey(state.AID), reported); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to save onboarding progress: %v", err)
+	}
+
+	fmt.Printf("[Onboarding] %s reported %s\n", state.AID, step)
+	return http.StatusOK, nil
+}
+
+// RegisterRoutes registers onboarding routes on the mux
+func (h *OnboardingHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/onboarding/state", h.HandleState)
+	mux.HandleFunc("/api/v1/onboarding/advance", h.HandleAdvance)
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+func newTestOnboardingHandler(t *testing.T, aid string) (*OnboardingHandler, *anystore.LocalStore) {
+	t.Helper()
+
+	store, cleanup := setupTrustTestStore(t)
+	t.Cleanup(cleanup)
+
+	tmpDir, err := os.MkdirTemp("", "onboarding_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	userIdentity := identity.New(tmpDir)
+	if aid != "" {
+		if err := userIdentity.SetIdentity(aid, secret.NewMnemonic("test mnemonic")); err != nil {
+			t.Fatalf("SetIdentity failed: %v", err)
+		}
+	}
+	return NewOnboardingHandler(store, nil, userIdentity), store
+}
+
+func stepSource(state *OnboardingState, name string) string {
+	for _, s := range state.Steps {
+		if s.Name == name && s.Complete {
+			return s.Source
+		}
+	}
+	return ""
+}
+
+func TestOnboardingState_NoIdentity(t *testing.T) {
+	handler, _ := newTestOnboardingHandler(t, "")
+
+	state := handler.State(context.Background())
+	if state.Current != StepIdentitySet || state.Complete {
+		t.Errorf("expected current step %s, got %+v", StepIdentitySet, state)
+	}
+	if len(state.Steps) != len(onboardingSteps) {
+		t.Errorf("expected %d steps, got %d", len(onboardingSteps), len(state.Steps))
+	}
+}
+
+func TestOnboardingState_ObservedCredentialImpliesEarlierSteps(t *testing.T) {
+	handler, store := newTestOnboardingHandler(t, "EALICE")
+	ctx := context.Background()
+
+	if state := handler.State(ctx); state.Current != StepOOBIExchanged {
+		t.Fatalf("expected current step %s, got %s", StepOOBIExchanged, state.Current)
+	}
+
+	if err := store.StoreCredential(ctx, &anystore.CachedCredential{
+		ID:         "ESAID1",
+		IssuerAID:  "EORG",
+		SubjectAID: "EALICE",
+		SchemaID:   "EMatouMembershipSchemaV1",
+	}); err != nil {
+		t.Fatalf("StoreCredential failed: %v", err)
+	}
+
+	state := handler.State(ctx)
+	if got := stepSource(state, StepCredentialReceived); got != StepSourceObserved {
+		t.Errorf("expected credential step observed, got %q", got)
+	}
+	if got := stepSource(state, StepOOBIExchanged); got != StepSourceImplied {
+		t.Errorf("expected OOBI step implied, got %q", got)
+	}
+	if state.Current != StepProfileCreated {
+		t.Errorf("expected current step %s, got %s", StepProfileCreated, state.Current)
+	}
+}
+
+func TestOnboardingState_ObservedKEL(t *testing.T) {
+	handler, store := newTestOnboardingHandler(t, "EALICE")
+	ctx := context.Background()
+
+	if _, err := storeKELEvents(ctx, store, "EALICE", []KELEvent{{Type: "icp", Sequence: 0, Digest: "EALICE"}}); err != nil {
+		t.Fatalf("storeKELEvents failed: %v", err)
+	}
+
+	state := handler.State(ctx)
+	if got := stepSource(state, StepOOBIExchanged); got != StepSourceObserved {
+		t.Errorf("expected OOBI step observed, got %q", got)
+	}
+	if state.Current != StepCredentialReceived {
+		t.Errorf("expected current step %s, got %s", StepCredentialReceived, state.Current)
+	}
+}
+
+func TestHandleAdvance(t *testing.T) {
+	handler, _ := newTestOnboardingHandler(t, "EALICE")
+
+	advance := func(step string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/onboarding/advance", strings.NewReader(`{"step":"`+step+`"}`))
+		w := httptest.NewRecorder()
+		handler.HandleAdvance(w, req)
+		return w
+	}
+
+	if w := advance("dancing"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unknown step, got %d", w.Code)
+	}
+	if w := advance(StepCredentialReceived); w.Code != http.StatusConflict {
+		t.Errorf("expected status 409 when skipping a step, got %d", w.Code)
+	}
+	if w := advance(StepIdentitySet); w.Code != http.StatusOK {
+		t.Errorf("expected completed step to be a no-op, got %d", w.Code)
+	}
+
+	w := advance(StepOOBIExchanged)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var state OnboardingState
+	if err := json.NewDecoder(w.Body).Decode(&state); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got := stepSource(&state, StepOOBIExchanged); got != StepSourceReported {
+		t.Errorf("expected OOBI step reported, got %q", got)
+	}
+	if state.Current != StepCredentialReceived {
+		t.Errorf("expected current step %s, got %s", StepCredentialReceived, state.Current)
+	}
+
+	// Reported progress is persisted
+	if state := handler.State(context.Background()); state.Current != StepCredentialReceived {
+		t.Errorf("expected persisted progress, got current %s", state.Current)
+	}
+}
+
+func TestHandleAdvance_NoIdentity(t *testing.T) {
+	handler, _ := newTestOnboardingHandler(t, "")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/onboarding/advance", strings.NewReader(`{"step":"identity_set"}`))
+	w := httptest.NewRecorder()
+	handler.HandleAdvance(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status 409, got %d", w.Code)
+	}
+}
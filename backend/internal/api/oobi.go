@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/matou-dao/backend/internal/keri"
+)
+
+// OOBIHandler exchanges OOBIs with new members: it publishes the org's
+// OOBI and resolves a member's, so their KEL is known before a credential
+// is issued to them
+type OOBIHandler struct {
+	keriClient *keri.Client
+}
+
+// NewOOBIHandler creates a new OOBI handler
+func NewOOBIHandler(keriClient *keri.Client) *OOBIHandler {
+	return &OOBIHandler{keriClient: keriClient}
+}
+
+// GenerateOOBIResponse is the response for GET /api/v1/oobi/generate
+type GenerateOOBIResponse struct {
+	AID  string `json:"aid"`
+	OOBI string `json:"oobi"`
+}
+
+// ResolveOOBIRequest is the body for POST /api/v1/oobi/resolve
+type ResolveOOBIRequest struct {
+	OOBI string `json:"oobi"`
+}
+
+// HandleGenerate handles GET /api/v1/oobi/generate[?aid=...]. Without an
+// aid it returns the org's OOBI.
+func (h *OOBIHandler) HandleGenerate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	aid := r.URL.Query().Get("aid")
+	if aid == "" {
+		aid = h.keriClient.GetOrgAID()
+	}
+	oobi, err := h.keriClient.GenerateOOBI(aid)
+	if err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, GenerateOOBIResponse{AID: aid, OOBI: oobi})
+}
+
+// HandleResolve handles POST /api/v1/oobi/resolve
+func (h *OOBIHandler) HandleResolve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	var req ResolveOOBIRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request body: %v", err),
+		})
+		return
+	}
+	if _, _, err := keri.ParseOOBI(req.OOBI); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	resolved, err := h.keriClient.ResolveOOBI(r.Context(), req.OOBI)
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	}
+	fmt.Printf("[OOBI] Resolved %s at sequence %d\n", resolved.AID, resolved.KeyState.Sequence)
+	writeJSON(w, http.StatusOK, resolved)
+}
+
+// RegisterRoutes registers OOBI routes on the mux
+func (h *OOBIHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/oobi/generate", CORSHandler(h.HandleGenerate))
+	mux.HandleFunc("/api/v1/oobi/resolve", CORSHandler(h.HandleResolve))
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/keri"
+)
+
+func TestOOBIHandler_Generate(t *testing.T) {
+	client, _ := keri.NewClient(&keri.Config{OrgAID: "EORG", CESRURL: "http://keria:3902"})
+	h := NewOOBIHandler(client)
+
+	w := httptest.NewRecorder()
+	h.HandleGenerate(w, httptest.NewRequest(http.MethodGet, "/api/v1/oobi/generate", nil))
+	var resp GenerateOOBIResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if w.Code != http.StatusOK || resp.AID != "EORG" || resp.OOBI != "http://keria:3902/oobi/EORG" {
+		t.Fatalf("expected org OOBI, got %d %+v", w.Code, resp)
+	}
+
+	unconfigured, _ := keri.NewClient(&keri.Config{})
+	w = httptest.NewRecorder()
+	NewOOBIHandler(unconfigured).HandleGenerate(w, httptest.NewRequest(http.MethodGet, "/api/v1/oobi/generate", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without an org, got %d", w.Code)
+	}
+}
+
+func TestOOBIHandler_Resolve(t *testing.T) {
+	keria := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oobi/EMEMBER" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"v":"KERI10JSON00012b_","t":"icp","d":"EDIGEST","i":"EMEMBER","s":"0","k":["DKEY0"]}-AABAAB`))
+	}))
+	defer keria.Close()
+	client, _ := keri.NewClient(&keri.Config{})
+	h := NewOOBIHandler(client)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"resolves", `{"oobi":"` + keria.URL + `/oobi/EMEMBER"}`, http.StatusOK},
+		{"invalid json", `{`, http.StatusBadRequest},
+		{"not an OOBI", `{"oobi":"` + keria.URL + `/members/EMEMBER"}`, http.StatusBadRequest},
+		{"unreachable", `{"oobi":"` + keria.URL + `/oobi/EMISSING"}`, http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.HandleResolve(w, httptest.NewRequest(http.MethodPost, "/api/v1/oobi/resolve", strings.NewReader(tt.body)))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
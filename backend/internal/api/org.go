@@ -1,13 +1,18 @@
 package api
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sync"
 
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/audit"
+	"github.com/matou-dao/backend/internal/keri"
+	"github.com/matou-dao/backend/internal/trust"
 	"gopkg.in/yaml.v3"
 )
 
@@ -19,6 +24,8 @@ type OrgConfigHandler struct {
 	mu         sync.RWMutex
 	cache      *OrgConfigData
 	onUpdate   func(*OrgConfigData) // Callback when config is updated
+	reloadErr  string               // Why the last reload from disk was refused
+	audit      *audit.Log           // Records saves and deletes through the API
 }
 
 // OrgConfigData represents the organization configuration
@@ -32,13 +39,41 @@ type OrgConfigData struct {
 	ReadOnlySpaceID  string `json:"readOnlySpaceId,omitempty" yaml:"readOnlySpaceId,omitempty"`
 	AdminSpaceID     string `json:"adminSpaceId,omitempty" yaml:"adminSpaceId,omitempty"`
 
+	// Custom credential attributes per role (committee, region, term length, ...)
+	RoleTemplates []keri.RoleTemplate `json:"roleTemplates,omitempty" yaml:"roleTemplates,omitempty"`
+
+	// Endorsement types and categories members can endorse each other for;
+	// omitted means the defaults
+	EndorsementTypes []keri.EndorsementType `json:"endorsementTypes,omitempty" yaml:"endorsementTypes,omitempty"`
+
+	// Two-person rule for issuing high-privilege roles; omitted means off
+	IssuanceApproval *keri.ApprovalPolicy `json:"issuanceApproval,omitempty" yaml:"issuanceApproval,omitempty"`
+
+	// Witnesses the org AID should use; omitted means the server's default pool
+	Witnesses *keri.WitnessPool `json:"witnesses,omitempty" yaml:"witnesses,omitempty"`
+
+	// Trust score weights; omitted fields fall back to the defaults
+	TrustWeights *trust.ScoreWeights `json:"trustWeights,omitempty" yaml:"trustWeights,omitempty"`
+
+	// Default trust scoring algorithm ("linear" or "pagerank"); empty is linear
+	TrustAlgorithm trust.Algorithm `json:"trustAlgorithm,omitempty" yaml:"trustAlgorithm,omitempty"`
+
+	// Trusted peer organizations whose credentials are recognized
+	Federation []trust.FederatedOrg `json:"federation,omitempty" yaml:"federation,omitempty"`
+
+	// Public community descriptor served at /.well-known/matou.json
+	Publish *PublishConfig `json:"publish,omitempty" yaml:"publish,omitempty"`
+
+	// Preferred tree and file nodes per space type, applied at space creation
+	SpacePlacement map[string]*anysync.SpacePlacement `json:"spacePlacement,omitempty" yaml:"spacePlacement,omitempty"`
+
 	Generated string `json:"generated,omitempty" yaml:"generated,omitempty"`
 }
 
 // OrgInfo holds organization identity info
 type OrgInfo struct {
-	AID  string `json:"aid" yaml:"aid"`
-	Name string `json:"name" yaml:"name"`
+	AID  string `json:"aid" yaml:"aid" validate:"required"`
+	Name string `json:"name" yaml:"name" validate:"required"`
 	OOBI string `json:"oobi,omitempty" yaml:"oobi,omitempty"`
 }
 
@@ -49,6 +84,66 @@ type AdminData struct {
 	OOBI string `json:"oobi,omitempty" yaml:"oobi,omitempty"`
 }
 
+// PublishConfig controls publication of the community descriptor
+type PublishConfig struct {
+	Enabled bool   `json:"enabled" yaml:"enabled"`
+	JoinURL string `json:"joinUrl,omitempty" yaml:"joinUrl,omitempty"` // Where prospective members register
+}
+
+// Validate checks the join URL is an absolute http(s) URL
+func (p *PublishConfig) Validate() error {
+	if !p.Enabled && p.JoinURL == "" {
+		return nil
+	}
+	if p.JoinURL == "" {
+		return ValidationErrors{{Field: "publish.joinUrl", Message: "is required"}}
+	}
+	u, err := url.Parse(p.JoinURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return ValidationErrors{{Field: "publish.joinUrl", Message: "must be an absolute http(s) URL"}}
+	}
+	return nil
+}
+
+// Validate checks the required fields and every org policy in the config
+func (c *OrgConfigData) Validate() error {
+	if errs := validateStruct(c); len(errs) > 0 {
+		return errs
+	}
+	if err := validateRoleTemplates(c.RoleTemplates); err != nil {
+		return err
+	}
+	if err := keri.ValidateEndorsementTypes(c.EndorsementTypes); err != nil {
+		return err
+	}
+	if err := c.IssuanceApproval.Validate(); err != nil {
+		return err
+	}
+	if err := c.Witnesses.Validate(); err != nil {
+		return err
+	}
+	if c.TrustWeights != nil {
+		if err := c.TrustWeights.Validate(); err != nil {
+			return err
+		}
+	}
+	if _, err := trust.ParseAlgorithm(string(c.TrustAlgorithm)); err != nil {
+		return err
+	}
+	if err := trust.ValidateFederation(c.Federation, c.Organization.AID); err != nil {
+		return err
+	}
+	if c.Publish != nil {
+		if err := c.Publish.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := anysync.ValidateSpacePlacements(c.SpacePlacement); err != nil {
+		return err
+	}
+	return nil
+}
+
 // Registry holds credential registry info
 type Registry struct {
 	ID   string `json:"id" yaml:"id"`
@@ -67,6 +162,12 @@ func NewOrgConfigHandler(dataDir string, onUpdate func(*OrgConfigData)) *OrgConf
 	return h
 }
 
+// SetAudit records config saves and deletes made through the API in the
+// audit log
+func (h *OrgConfigHandler) SetAudit(log *audit.Log) {
+	h.audit = log
+}
+
 // loadFromDisk loads config from disk into cache
 func (h *OrgConfigHandler) loadFromDisk() {
 	h.mu.Lock()
@@ -108,6 +209,74 @@ func (h *OrgConfigHandler) saveToDisk() error {
 	return nil
 }
 
+// Reload re-reads org-config.yaml after it changed on disk and applies it
+// as a save through the API would. A missing, unparseable or invalid file
+// leaves the running config unchanged. It reports whether anything changed;
+// the handler's own saves re-read as unchanged.
+func (h *OrgConfigHandler) Reload() (bool, error) {
+	changed, err := h.reload()
+	h.mu.Lock()
+	h.reloadErr = ""
+	if err != nil {
+		h.reloadErr = err.Error()
+	}
+	h.mu.Unlock()
+	return changed, err
+}
+
+func (h *OrgConfigHandler) reload() (bool, error) {
+	data, err := os.ReadFile(h.configPath)
+	if os.IsNotExist(err) {
+		// Deleted through the API, or not configured yet
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading org config: %w", err)
+	}
+
+	var config OrgConfigData
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return false, fmt.Errorf("parsing org config: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return false, fmt.Errorf("invalid org config: %w", err)
+	}
+
+	// Compare as saved, so nil and empty lists don't count as changes
+	next, err := yaml.Marshal(&config)
+	if err != nil {
+		return false, err
+	}
+	h.mu.Lock()
+	if h.cache != nil {
+		if current, err := yaml.Marshal(h.cache); err == nil && bytes.Equal(current, next) {
+			h.mu.Unlock()
+			return false, nil
+		}
+	}
+	h.cache = &config
+	onUpdate := h.onUpdate
+	h.mu.Unlock()
+
+	if onUpdate != nil {
+		onUpdate(&config)
+	}
+	fmt.Printf("[OrgConfig] Reloaded config for: %s\n", config.Organization.Name)
+	return true, nil
+}
+
+// Path returns the org config file
+func (h *OrgConfigHandler) Path() string {
+	return h.configPath
+}
+
+// ReloadError returns why the last reload was refused, or "" when it wasn't
+func (h *OrgConfigHandler) ReloadError() string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.reloadErr
+}
+
 // HandleGetConfig handles GET /api/v1/org/config
 func (h *OrgConfigHandler) HandleGetConfig(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -141,28 +310,12 @@ func (h *OrgConfigHandler) HandleSaveConfig(w http.ResponseWriter, r *http.Reque
 	}
 
 	var config OrgConfigData
-	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{
-			"error": fmt.Sprintf("invalid request: %v", err),
-		})
-		return
-	}
-
-	// Validate required fields
-	if config.Organization.AID == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{
-			"error": "organization.aid is required",
-		})
-		return
-	}
-	if config.Organization.Name == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{
-			"error": "organization.name is required",
-		})
+	if !decodeRequest(w, r, &config) {
 		return
 	}
 
 	h.mu.Lock()
+	previous := h.cache
 	h.cache = &config
 	err := h.saveToDisk()
 	onUpdate := h.onUpdate
@@ -174,6 +327,8 @@ func (h *OrgConfigHandler) HandleSaveConfig(w http.ResponseWriter, r *http.Reque
 		})
 		return
 	}
+	h.audit.Record(r.Context(), audit.ActionOrgConfig, auditActor(r.Context(), nil),
+		config.Organization.AID, previous, &config)
 
 	// Notify listeners that config was updated
 	if onUpdate != nil {
@@ -233,6 +388,7 @@ func (h *OrgConfigHandler) HandleDeleteConfig(w http.ResponseWriter, r *http.Req
 	}
 
 	h.mu.Lock()
+	previous := h.cache
 	h.cache = nil
 	// Remove config file
 	err := os.Remove(h.configPath)
@@ -244,6 +400,10 @@ func (h *OrgConfigHandler) HandleDeleteConfig(w http.ResponseWriter, r *http.Req
 		})
 		return
 	}
+	if previous != nil {
+		h.audit.Record(r.Context(), audit.ActionOrgConfig, auditActor(r.Context(), nil),
+			previous.Organization.AID, previous, nil)
+	}
 
 	fmt.Println("[OrgConfig] Deleted org config")
 	writeJSON(w, http.StatusOK, map[string]string{
@@ -285,6 +445,16 @@ func (h *OrgConfigHandler) GetOrgName() string {
 	return h.cache.Organization.Name
 }
 
+// GetOrgOOBI returns the org's published OOBI, or empty string if not configured
+func (h *OrgConfigHandler) GetOrgOOBI() string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.cache == nil {
+		return ""
+	}
+	return h.cache.Organization.OOBI
+}
+
 // GetAdminAID returns the first admin's AID, or empty string if not configured
 func (h *OrgConfigHandler) GetAdminAID() string {
 	h.mu.RLock()
@@ -295,6 +465,22 @@ func (h *OrgConfigHandler) GetAdminAID() string {
 	return h.cache.Admins[0].AID
 }
 
+// IsAdmin reports whether aid is one of the org's admins.
+// Implements AdminSource.
+func (h *OrgConfigHandler) IsAdmin(aid string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.cache == nil || aid == "" {
+		return false
+	}
+	for _, a := range h.cache.Admins {
+		if a.AID == aid {
+			return true
+		}
+	}
+	return false
+}
+
 // GetCommunitySpaceID returns the community space ID, or empty string if not configured
 func (h *OrgConfigHandler) GetCommunitySpaceID() string {
 	h.mu.RLock()
@@ -304,3 +490,193 @@ func (h *OrgConfigHandler) GetCommunitySpaceID() string {
 	}
 	return h.cache.CommunitySpaceID
 }
+
+// SetSpaceIDs records the org's space IDs in the saved config. Empty IDs
+// leave the saved ones unchanged. Implements bootstrap.OrgConfigWriter.
+func (h *OrgConfigHandler) SetSpaceIDs(communitySpaceID, readOnlySpaceID, adminSpaceID string) error {
+	h.mu.Lock()
+	if h.cache == nil {
+		h.mu.Unlock()
+		return fmt.Errorf("organization not configured")
+	}
+	config := *h.cache
+	if communitySpaceID != "" {
+		config.CommunitySpaceID = communitySpaceID
+	}
+	if readOnlySpaceID != "" {
+		config.ReadOnlySpaceID = readOnlySpaceID
+	}
+	if adminSpaceID != "" {
+		config.AdminSpaceID = adminSpaceID
+	}
+	h.cache = &config
+	err := h.saveToDisk()
+	onUpdate := h.onUpdate
+	h.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if onUpdate != nil {
+		onUpdate(&config)
+	}
+	return nil
+}
+
+// GetRoleTemplates returns the configured role attribute templates.
+// Implements keri.RoleTemplateSource.
+func (h *OrgConfigHandler) GetRoleTemplates() []keri.RoleTemplate {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.cache == nil {
+		return nil
+	}
+	return h.cache.RoleTemplates
+}
+
+// GetEndorsementTypes returns the configured endorsement registry, or nil
+// to use the defaults. Implements keri.EndorsementTypeSource.
+func (h *OrgConfigHandler) GetEndorsementTypes() []keri.EndorsementType {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.cache == nil {
+		return nil
+	}
+	return h.cache.EndorsementTypes
+}
+
+// GetApprovalPolicy returns the org's issuance approval policy, or nil if
+// none is configured. Implements keri.ApprovalPolicySource.
+func (h *OrgConfigHandler) GetApprovalPolicy() *keri.ApprovalPolicy {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.cache == nil {
+		return nil
+	}
+	return h.cache.IssuanceApproval
+}
+
+// GetWitnessPool returns the org's witness pool, or nil if none is
+// configured. Implements keri.WitnessPoolSource.
+func (h *OrgConfigHandler) GetWitnessPool() *keri.WitnessPool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.cache == nil {
+		return nil
+	}
+	return h.cache.Witnesses
+}
+
+// SetWitnessPool records the org's witness pool in the saved config
+func (h *OrgConfigHandler) SetWitnessPool(pool *keri.WitnessPool) error {
+	if err := pool.Validate(); err != nil {
+		return err
+	}
+	h.mu.Lock()
+	if h.cache == nil {
+		h.mu.Unlock()
+		return fmt.Errorf("organization not configured")
+	}
+	config := *h.cache
+	config.Witnesses = pool
+	h.cache = &config
+	err := h.saveToDisk()
+	onUpdate := h.onUpdate
+	h.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if onUpdate != nil {
+		onUpdate(&config)
+	}
+	return nil
+}
+
+// GetTrustWeights returns the org's trust score weights, or nil to use the
+// defaults. Implements TrustWeightsSource.
+func (h *OrgConfigHandler) GetTrustWeights() *trust.ScoreWeights {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.cache == nil {
+		return nil
+	}
+	return h.cache.TrustWeights
+}
+
+// SetTrustWeights saves the org's trust score weights; nil removes them so
+// the server's defaults apply. Implements TrustWeightsWriter.
+func (h *OrgConfigHandler) SetTrustWeights(weights *trust.ScoreWeights) error {
+	if weights != nil {
+		if err := weights.Validate(); err != nil {
+			return err
+		}
+	}
+	h.mu.Lock()
+	if h.cache == nil {
+		h.mu.Unlock()
+		return fmt.Errorf("organization not configured")
+	}
+	config := *h.cache
+	config.TrustWeights = weights
+	h.cache = &config
+	err := h.saveToDisk()
+	onUpdate := h.onUpdate
+	h.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if onUpdate != nil {
+		onUpdate(&config)
+	}
+	return nil
+}
+
+// GetTrustAlgorithm returns the org's default trust scoring algorithm, or
+// empty to use the linear algorithm. Implements TrustAlgorithmSource.
+func (h *OrgConfigHandler) GetTrustAlgorithm() trust.Algorithm {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.cache == nil {
+		return ""
+	}
+	return h.cache.TrustAlgorithm
+}
+
+// GetFederation returns the configured peer organizations.
+// Implements FederationSource.
+func (h *OrgConfigHandler) GetFederation() []trust.FederatedOrg {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.cache == nil {
+		return nil
+	}
+	return h.cache.Federation
+}
+
+// GetSpacePlacement returns the node placement per space type.
+// Implements anysync.PlacementSource.
+func (h *OrgConfigHandler) GetSpacePlacement() map[string]*anysync.SpacePlacement {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.cache == nil {
+		return nil
+	}
+	return h.cache.SpacePlacement
+}
+
+// validateRoleTemplates checks each template and rejects duplicate roles
+func validateRoleTemplates(templates []keri.RoleTemplate) error {
+	seen := make(map[string]bool)
+	for i := range templates {
+		if err := templates[i].Validate(); err != nil {
+			return err
+		}
+		if seen[templates[i].Role] {
+			return fmt.Errorf("duplicate template for role: %s", templates[i].Role)
+		}
+		seen[templates[i].Role] = true
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// OrgHeader selects the organization a request is for when the backend
+// serves more than one
+const OrgHeader = "X-Org-AID"
+
+// orgPathPrefix is the path form of OrgHeader: /api/v1/orgs/{aid}/...
+const orgPathPrefix = "/api/v1/orgs/"
+
+// orgAIDPattern matches a KERI AID: qb64 characters only, so it is also
+// safe to use as a directory name
+var orgAIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{4,128}$`)
+
+// Tenant is an additional organization served by this backend. Its config,
+// credential cache and trust graph live under {dataDir}/orgs/{aid}.
+type Tenant struct {
+	AID     string
+	Dir     string
+	Config  *OrgConfigHandler
+	Handler http.Handler
+}
+
+// TenantFactory builds the org-scoped routes for a tenant. dir is where
+// the tenant keeps its local state.
+type TenantFactory func(config *OrgConfigHandler, dir string) (http.Handler, error)
+
+// OrgSummary is one entry in GET /api/v1/orgs
+type OrgSummary struct {
+	AID     string `json:"aid"`
+	Name    string `json:"name"`
+	Primary bool   `json:"primary"`
+}
+
+// OrgRegistry keeps the organizations this backend serves, keyed by org
+// AID. The primary org is the one configured in {dataDir}/org-config.yaml
+// and is served by the main routes; requests that name no org, or name the
+// primary, go there unchanged.
+type OrgRegistry struct {
+	dir     string
+	primary *OrgConfigHandler
+	factory TenantFactory
+
+	mu      sync.RWMutex
+	tenants map[string]*Tenant
+}
+
+// NewOrgRegistry creates a registry storing tenants under {dataDir}/orgs
+func NewOrgRegistry(dataDir string, primary *OrgConfigHandler, factory TenantFactory) *OrgRegistry {
+	return &OrgRegistry{
+		dir:     filepath.Join(dataDir, "orgs"),
+		primary: primary,
+		factory: factory,
+		tenants: make(map[string]*Tenant),
+	}
+}
+
+// Load registers every tenant with a saved config. A tenant that fails to
+// start is skipped so one bad org doesn't take the others down.
+func (o *OrgRegistry) Load() (int, error) {
+	entries, err := os.ReadDir(o.dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reading orgs directory: %w", err)
+	}
+
+	loaded := 0
+	for _, e := range entries {
+		if !e.IsDir() || !orgAIDPattern.MatchString(e.Name()) {
+			continue
+		}
+		config := NewOrgConfigHandler(filepath.Join(o.dir, e.Name()), nil)
+		if config.GetOrgAID() != e.Name() {
+			fmt.Printf("[Orgs] Skipping %s: config is for %q\n", e.Name(), config.GetOrgAID())
+			continue
+		}
+		if _, err := o.add(config); err != nil {
+			fmt.Printf("[Orgs] Failed to start %s: %v\n", e.Name(), err)
+			continue
+		}
+		loaded++
+	}
+	return loaded, nil
+}
+
+// Register saves config as a new tenant and starts serving it
+func (o *OrgRegistry) Register(data *OrgConfigData) (*Tenant, error) {
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+	aid := data.Organization.AID
+	if !orgAIDPattern.MatchString(aid) {
+		return nil, fmt.Errorf("organization.aid is not a valid AID")
+	}
+	if aid == o.primary.GetOrgAID() {
+		return nil, fmt.Errorf("%s is the primary organization", aid)
+	}
+	if _, ok := o.Tenant(aid); ok {
+		return nil, fmt.Errorf("%s is already registered", aid)
+	}
+
+	config := NewOrgConfigHandler(filepath.Join(o.dir, aid), nil)
+	config.mu.Lock()
+	config.cache = data
+	err := config.saveToDisk()
+	config.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return o.add(config)
+}
+
+// add builds the tenant's routes and makes it reachable
+func (o *OrgRegistry) add(config *OrgConfigHandler) (*Tenant, error) {
+	aid := config.GetOrgAID()
+	dir := filepath.Dir(config.configPath)
+	handler, err := o.factory(config, dir)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tenant{AID: aid, Dir: dir, Config: config, Handler: handler}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, exists := o.tenants[aid]; exists {
+		return nil, fmt.Errorf("%s is already registered", aid)
+	}
+	o.tenants[aid] = t
+	fmt.Printf("[Orgs] Serving %s (%s)\n", config.GetOrgName(), aid)
+	return t, nil
+}
+
+// Remove stops serving a tenant. Its data directory is kept.
+func (o *OrgRegistry) Remove(aid string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.tenants[aid]; !ok {
+		return false
+	}
+	delete(o.tenants, aid)
+	return true
+}
+
+// Tenant returns the tenant for an org AID
+func (o *OrgRegistry) Tenant(aid string) (*Tenant, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	t, ok := o.tenants[aid]
+	return t, ok
+}
+
+// List returns the primary org (if configured) followed by the tenants
+func (o *OrgRegistry) List() []OrgSummary {
+	var orgs []OrgSummary
+	if o.primary.IsConfigured() {
+		orgs = append(orgs, OrgSummary{AID: o.primary.GetOrgAID(), Name: o.primary.GetOrgName(), Primary: true})
+	}
+
+	o.mu.RLock()
+	tenants := make([]OrgSummary, 0, len(o.tenants))
+	for _, t := range o.tenants {
+		tenants = append(tenants, OrgSummary{AID: t.AID, Name: t.Config.GetOrgName()})
+	}
+	o.mu.RUnlock()
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].AID < tenants[j].AID })
+	return append(orgs, tenants...)
+}
+
+type tenantKey struct{}
+
+// TenantFromContext returns the tenant a request was routed to, or nil for
+// the primary org
+func TenantFromContext(ctx context.Context) *Tenant {
+	t, _ := ctx.Value(tenantKey{}).(*Tenant)
+	return t
+}
+
+// splitOrgPath splits /api/v1/orgs/{aid}/rest into aid and /api/v1/rest.
+// ok is false for paths without a sub-route, which are the registry's own.
+func splitOrgPath(path string) (aid, rest string, ok bool) {
+	if !strings.HasPrefix(path, orgPathPrefix) {
+		return "", "", false
+	}
+	aid, sub, found := strings.Cut(strings.TrimPrefix(path, orgPathPrefix), "/")
+	if !found || sub == "" {
+		return "", "", false
+	}
+	return aid, "/api/v1/" + sub, true
+}
+
+// OrgMiddleware resolves which org a request is for, from the path prefix
+// or OrgHeader, and strips the prefix so the routes below see the plain
+// path. It sits outside auth so route requirements apply per org; the
+// request reaches the tenant's routes through Dispatch.
+func OrgMiddleware(o *OrgRegistry, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		aid := r.Header.Get(OrgHeader)
+		if pathAID, rest, ok := splitOrgPath(r.URL.Path); ok {
+			if aid != "" && aid != pathAID {
+				writeJSON(w, http.StatusBadRequest, map[string]string{
+					"error": OrgHeader + " does not match the org in the path",
+				})
+				return
+			}
+			aid = pathAID
+			r = r.Clone(r.Context())
+			r.URL.Path = rest
+			r.URL.RawPath = ""
+		}
+
+		if aid == "" || aid == o.primary.GetOrgAID() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		t, ok := o.Tenant(aid)
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{
+				"error": fmt.Sprintf("organization %s is not served here", aid),
+			})
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tenantKey{}, t)))
+	})
+}
+
+// Dispatch serves tenant requests from the tenant's routes and everything
+// else from primary
+func (o *OrgRegistry) Dispatch(primary http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if t := TenantFromContext(r.Context()); t != nil {
+			t.Handler.ServeHTTP(w, r)
+			return
+		}
+		primary.ServeHTTP(w, r)
+	})
+}
+
+// HandleOrgs handles GET and POST /api/v1/orgs
+func (o *OrgRegistry) HandleOrgs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"orgs": o.List(),
+		})
+	case http.MethodPost:
+		var data OrgConfigData
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("invalid request body: %v", err),
+			})
+			return
+		}
+		t, err := o.Register(&data)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusCreated, OrgSummary{AID: t.AID, Name: t.Config.GetOrgName()})
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+	}
+}
+
+// HandleOrg handles GET and DELETE /api/v1/orgs/{aid}
+func (o *OrgRegistry) HandleOrg(w http.ResponseWriter, r *http.Request) {
+	aid := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, orgPathPrefix), "/")
+	if aid == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "org AID required"})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if aid == o.primary.GetOrgAID() {
+			writeJSON(w, http.StatusOK, OrgSummary{AID: aid, Name: o.primary.GetOrgName(), Primary: true})
+			return
+		}
+		t, ok := o.Tenant(aid)
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "organization not found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, OrgSummary{AID: t.AID, Name: t.Config.GetOrgName()})
+	case http.MethodDelete:
+		if aid == o.primary.GetOrgAID() {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": "the primary organization cannot be removed",
+			})
+			return
+		}
+		if !o.Remove(aid) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "organization not found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "removed"})
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+	}
+}
+
+// RegisterRoutes registers the org registry routes on the mux
+func (o *OrgRegistry) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/orgs", CORSHandler(o.HandleOrgs))
+	mux.HandleFunc(orgPathPrefix, CORSHandler(o.HandleOrg))
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// echoTenants builds tenants that answer with their org AID and the path
+// they were asked for
+func echoTenants(config *OrgConfigHandler, dir string) (http.Handler, error) {
+	aid := config.GetOrgAID()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"org": aid, "path": r.URL.Path})
+	}), nil
+}
+
+func newTestOrgRegistry(t *testing.T) (*OrgRegistry, string) {
+	t.Helper()
+	dataDir := t.TempDir()
+	primary := NewOrgConfigHandler(dataDir, nil)
+	primary.cache = &OrgConfigData{Organization: OrgInfo{AID: "EPRIMARY", Name: "Primary"}}
+	return NewOrgRegistry(dataDir, primary, echoTenants), dataDir
+}
+
+func TestOrgMiddleware_Routing(t *testing.T) {
+	orgs, _ := newTestOrgRegistry(t)
+	if _, err := orgs.Register(&OrgConfigData{Organization: OrgInfo{AID: "ESECOND", Name: "Second"}}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	primary := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"org": "primary", "path": r.URL.Path})
+	})
+	handler := OrgMiddleware(orgs, orgs.Dispatch(primary))
+
+	tests := []struct {
+		name       string
+		path       string
+		header     string
+		wantStatus int
+		wantBody   string
+	}{
+		{"no org", "/api/v1/trust/graph", "", http.StatusOK, `{"org":"primary","path":"/api/v1/trust/graph"}`},
+		{"primary by header", "/api/v1/trust/graph", "EPRIMARY", http.StatusOK, `{"org":"primary","path":"/api/v1/trust/graph"}`},
+		{"primary by path", "/api/v1/orgs/EPRIMARY/trust/graph", "", http.StatusOK, `{"org":"primary","path":"/api/v1/trust/graph"}`},
+		{"tenant by header", "/api/v1/trust/graph", "ESECOND", http.StatusOK, `{"org":"ESECOND","path":"/api/v1/trust/graph"}`},
+		{"tenant by path", "/api/v1/orgs/ESECOND/org/config", "", http.StatusOK, `{"org":"ESECOND","path":"/api/v1/org/config"}`},
+		{"registry route", "/api/v1/orgs/ESECOND", "", http.StatusOK, `{"org":"primary","path":"/api/v1/orgs/ESECOND"}`},
+		{"unknown org", "/api/v1/orgs/EUNKNOWN/trust/graph", "", http.StatusNotFound, ""},
+		{"header and path disagree", "/api/v1/orgs/ESECOND/trust/graph", "EPRIMARY", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(OrgHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody+"\n" {
+				t.Errorf("expected %s, got %s", tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestOrgRegistry_Register(t *testing.T) {
+	orgs, dataDir := newTestOrgRegistry(t)
+
+	bad := []*OrgConfigData{
+		{Organization: OrgInfo{AID: "ESECOND"}},
+		{Organization: OrgInfo{AID: "../etc", Name: "Escape"}},
+		{Organization: OrgInfo{AID: "EPRIMARY", Name: "Primary"}},
+	}
+	for _, data := range bad {
+		if _, err := orgs.Register(data); err == nil {
+			t.Errorf("expected %q rejected", data.Organization.AID)
+		}
+	}
+
+	if _, err := orgs.Register(&OrgConfigData{Organization: OrgInfo{AID: "ESECOND", Name: "Second"}}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if _, err := orgs.Register(&OrgConfigData{Organization: OrgInfo{AID: "ESECOND", Name: "Again"}}); err == nil {
+		t.Error("expected duplicate org rejected")
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "orgs", "ESECOND", "org-config.yaml")); err != nil {
+		t.Fatalf("expected tenant config saved: %v", err)
+	}
+
+	list := orgs.List()
+	if len(list) != 2 || !list[0].Primary || list[1].AID != "ESECOND" {
+		t.Errorf("unexpected org list %+v", list)
+	}
+
+	// A restart picks the tenant back up from disk
+	reloaded := NewOrgRegistry(dataDir, orgs.primary, echoTenants)
+	if n, err := reloaded.Load(); err != nil || n != 1 {
+		t.Fatalf("expected 1 tenant loaded, got %d (%v)", n, err)
+	}
+	if tenant, ok := reloaded.Tenant("ESECOND"); !ok || tenant.Config.GetOrgName() != "Second" {
+		t.Error("expected tenant restored from disk")
+	}
+}
@@ -0,0 +1,323 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+)
+
+// Presence statuses reported in the member directory
+const (
+	PresenceActive   = "active"   // Seen within presenceActiveWindow
+	PresenceRecent   = "recent"   // Seen within presenceRecentWindow
+	PresenceInactive = "inactive" // Not seen for longer
+)
+
+const (
+	presenceActiveWindow = 30 * time.Minute
+	presenceRecentWindow = 7 * 24 * time.Hour
+
+	// heartbeatResolution is how stale the local member's SharedProfile
+	// lastActiveAt may get before it is rewritten. Each rewrite is a tree
+	// change synced to every member, so it is kept well below the active
+	// window but not per-refresh.
+	heartbeatResolution = 10 * time.Minute
+
+	// profileActivityResolution is how far a member's activity must advance
+	// before the admin backend rewrites their CommunityProfile
+	profileActivityResolution = time.Hour
+)
+
+// PresenceTracker tracks when members were last seen active. Member
+// backends only stream to sync nodes, never to each other, so activity is
+// read from what does propagate:
+//   - ACL records in the community spaces, signed by the member's peer key
+//     (resolved to an AID through the peer mappings kept by the admin backend)
+//   - SharedProfile.lastActiveAt, which each member's backend refreshes while
+//     it is syncing
+//   - the local member, whenever this backend reaches the network
+//
+// Presence is persisted locally. On a backend that can write the
+// community-readonly space (the admin's) it is also mirrored into each
+// member's CommunityProfile.lastActiveAt.
+type PresenceTracker struct {
+	spaceManager *anysync.SpaceManager
+	store        anystore.RecordStore
+	userIdentity *identity.UserIdentity
+	now          func() time.Time
+
+	mu sync.Mutex // serializes Refresh and Touch
+}
+
+// NewPresenceTracker creates a presence tracker
+func NewPresenceTracker(spaceManager *anysync.SpaceManager, store anystore.RecordStore, userIdentity *identity.UserIdentity) *PresenceTracker {
+	return &PresenceTracker{
+		spaceManager: spaceManager,
+		store:        store,
+		userIdentity: userIdentity,
+		now:          time.Now,
+	}
+}
+
+// Touch records activity for an AID if it is newer than what was last seen,
+// reporting whether the record advanced
+func (p *PresenceTracker) Touch(ctx context.Context, aid string, at time.Time, source string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.touch(ctx, aid, at, source)
+}
+
+func (p *PresenceTracker) touch(ctx context.Context, aid string, at time.Time, source string) (bool, error) {
+	if aid == "" || at.IsZero() {
+		return false, nil
+	}
+	at = at.UTC()
+	if existing, err := p.store.GetMemberPresence(ctx, aid); err == nil && !at.After(existing.LastActiveAt) {
+		return false, nil
+	}
+	if err := p.store.StoreMemberPresence(ctx, &anystore.MemberPresenceRecord{
+		AID:          aid,
+		LastActiveAt: at,
+		Source:       source,
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Records returns the persisted presence records keyed by AID
+func (p *PresenceTracker) Records(ctx context.Context) (map[string]*anystore.MemberPresenceRecord, error) {
+	records, err := p.store.ListMemberPresence(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byAID := make(map[string]*anystore.MemberPresenceRecord, len(records))
+	for _, record := range records {
+		byAID[record.AID] = record
+	}
+	return byAID, nil
+}
+
+// Status classifies a last-seen time relative to now
+func (p *PresenceTracker) Status(lastActiveAt time.Time) string {
+	return presenceStatus(lastActiveAt, p.now())
+}
+
+func presenceStatus(lastActiveAt, now time.Time) string {
+	switch idle := now.Sub(lastActiveAt); {
+	case idle <= presenceActiveWindow:
+		return PresenceActive
+	case idle <= presenceRecentWindow:
+		return PresenceRecent
+	default:
+		return PresenceInactive
+	}
+}
+
+// Refresh gathers activity from every source, persists it, and propagates
+// it to the member's SharedProfile and (on the admin backend) to each
+// CommunityProfile. Sources that are unavailable are skipped; errors from
+// the rest are returned together.
+func (p *PresenceTracker) Refresh(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now().UTC()
+	return errors.Join(
+		p.observeSelf(ctx, now),
+		p.observeACL(ctx),
+		p.observeHeartbeats(ctx),
+		p.updateCommunityProfiles(ctx),
+	)
+}
+
+// observeSelf marks the local member active while this backend can reach
+// the network, and refreshes their SharedProfile heartbeat
+func (p *PresenceTracker) observeSelf(ctx context.Context, now time.Time) error {
+	if p.userIdentity == nil || p.userIdentity.GetAID() == "" {
+		return nil
+	}
+	client := p.spaceManager.GetClient()
+	if client == nil || client.Ping() != nil {
+		return nil
+	}
+	aid := p.userIdentity.GetAID()
+	if _, err := p.touch(ctx, aid, now, "sync"); err != nil {
+		return fmt.Errorf("recording local presence: %w", err)
+	}
+
+	communitySpaceID := p.spaceManager.GetCommunitySpaceID()
+	if communitySpaceID == "" {
+		return nil
+	}
+	// Members without a SharedProfile have nothing to refresh yet
+	profile, err := p.spaceManager.ObjectTreeManager().ReadLatestByID(ctx, communitySpaceID, "SharedProfile-"+aid)
+	if err != nil {
+		return nil
+	}
+	data, last := profileActivity(profile, "aid")
+	if data == nil || now.Sub(last) < heartbeatResolution {
+		return nil
+	}
+	data["lastActiveAt"] = now.Format(time.RFC3339)
+	if _, err := writeObject(ctx, p.spaceManager, communitySpaceID, profile.ID, profile.Type, data); err != nil {
+		return fmt.Errorf("refreshing SharedProfile heartbeat: %w", err)
+	}
+	return nil
+}
+
+// observeACL records the ACL activity of every member with a known peer ID
+func (p *PresenceTracker) observeACL(ctx context.Context) error {
+	mappings, err := p.store.ListPeerMappings(ctx)
+	if err != nil {
+		return fmt.Errorf("listing peer mappings: %w", err)
+	}
+	aclManager := p.spaceManager.ACLManager()
+	if len(mappings) == 0 || aclManager == nil {
+		return nil
+	}
+
+	aidByAccount := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		key, err := anysync.DecodeACLIdentity(mapping.PeerID)
+		if err != nil {
+			continue
+		}
+		aidByAccount[key.Account()] = mapping.AID
+	}
+
+	var errs []error
+	for _, spaceID := range p.communitySpaceIDs() {
+		activity, err := aclManager.LastActivity(ctx, spaceID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for account, at := range activity {
+			if aid, ok := aidByAccount[account]; ok {
+				if _, err := p.touch(ctx, aid, at, "acl"); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// observeHeartbeats records the lastActiveAt each member's backend writes to
+// their SharedProfile
+func (p *PresenceTracker) observeHeartbeats(ctx context.Context) error {
+	communitySpaceID := p.spaceManager.GetCommunitySpaceID()
+	if communitySpaceID == "" || !p.spaceManager.ObjectTreeManager().HasObjectTree(ctx, communitySpaceID) {
+		return nil
+	}
+	profiles, err := readLatestObjects(ctx, p.spaceManager, communitySpaceID, "SharedProfile")
+	if err != nil {
+		return fmt.Errorf("reading SharedProfiles: %w", err)
+	}
+
+	var errs []error
+	for _, profile := range profiles {
+		data, last := profileActivity(profile, "aid")
+		if data == nil {
+			continue
+		}
+		aid, _ := data["aid"].(string)
+		if _, err := p.touch(ctx, aid, last, "heartbeat"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// updateCommunityProfiles mirrors presence into CommunityProfile.lastActiveAt.
+// Only a backend with write access to the community-readonly space (the
+// admin's) does this; on member backends it is a no-op.
+func (p *PresenceTracker) updateCommunityProfiles(ctx context.Context) error {
+	roSpaceID := p.spaceManager.GetCommunityReadOnlySpaceID()
+	client := p.spaceManager.GetClient()
+	aclManager := p.spaceManager.ACLManager()
+	if roSpaceID == "" || client == nil || client.GetSigningKey() == nil || aclManager == nil {
+		return nil
+	}
+	perms, err := aclManager.GetPermissions(ctx, roSpaceID, client.GetSigningKey().GetPublic())
+	if err != nil || !perms.CanWrite() {
+		return nil
+	}
+
+	records, err := p.store.ListMemberPresence(ctx)
+	if err != nil {
+		return fmt.Errorf("listing member presence: %w", err)
+	}
+	seen := make(map[string]time.Time, len(records))
+	for _, record := range records {
+		seen[record.AID] = record.LastActiveAt
+	}
+
+	profiles, err := readLatestObjects(ctx, p.spaceManager, roSpaceID, "CommunityProfile")
+	if err != nil {
+		return fmt.Errorf("reading CommunityProfiles: %w", err)
+	}
+
+	var errs []error
+	updated := 0
+	for _, profile := range profiles {
+		data, last := profileActivity(profile, "userAID")
+		if data == nil {
+			continue
+		}
+		aid, _ := data["userAID"].(string)
+		at, ok := seen[aid]
+		if !ok || at.Sub(last) < profileActivityResolution {
+			continue
+		}
+		data["lastActiveAt"] = at.Format(time.RFC3339)
+		if _, err := writeObject(ctx, p.spaceManager, roSpaceID, profile.ID, profile.Type, data); err != nil {
+			errs = append(errs, fmt.Errorf("updating %s: %w", profile.ID, err))
+			continue
+		}
+		updated++
+	}
+	if updated > 0 {
+		fmt.Printf("[Presence] Updated lastActiveAt on %d CommunityProfile(s)\n", updated)
+	}
+	return errors.Join(errs...)
+}
+
+// communitySpaceIDs returns the configured community and community-readonly
+// space IDs
+func (p *PresenceTracker) communitySpaceIDs() []string {
+	var ids []string
+	for _, id := range []string{
+		p.spaceManager.GetCommunitySpaceID(),
+		p.spaceManager.GetCommunityReadOnlySpaceID(),
+	} {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// profileActivity decodes a profile's data and its lastActiveAt. It returns
+// nil data for profiles without the AID field, and a zero time when
+// lastActiveAt is missing or malformed.
+func profileActivity(profile *anysync.ObjectPayload, aidField string) (map[string]interface{}, time.Time) {
+	var data map[string]interface{}
+	if err := json.Unmarshal(profile.Data, &data); err != nil {
+		return nil, time.Time{}
+	}
+	if aid, _ := data[aidField].(string); aid == "" {
+		return nil, time.Time{}
+	}
+	raw, _ := data["lastActiveAt"].(string)
+	last, _ := time.Parse(time.RFC3339, raw)
+	return data, last
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/util/crypto"
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+func setupTestPresence(t *testing.T, aid string) (*PresenceTracker, *anystore.LocalStore) {
+	t.Helper()
+
+	store, cleanup := setupTrustTestStore(t)
+	t.Cleanup(cleanup)
+
+	tmpDir, err := os.MkdirTemp("", "presence_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	userIdentity := identity.New(tmpDir)
+	if aid != "" {
+		if err := userIdentity.SetIdentity(aid, secret.NewMnemonic("test mnemonic")); err != nil {
+			t.Fatalf("SetIdentity failed: %v", err)
+		}
+	}
+
+	spaceManager := anysync.NewSpaceManager(newMockSyncAnySyncClient(), &anysync.SpaceManagerConfig{
+		CommunitySpaceID:         "space-community-test",
+		CommunityReadOnlySpaceID: "space-readonly-test",
+		OrgAID:                   "EORG123456789",
+	})
+	return NewPresenceTracker(spaceManager, store, userIdentity), store
+}
+
+func TestPresenceStatus(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		lastActiveAt time.Time
+		want         string
+	}{
+		{now.Add(-5 * time.Minute), PresenceActive},
+		{now.Add(-presenceActiveWindow), PresenceActive},
+		{now.Add(-2 * time.Hour), PresenceRecent},
+		{now.Add(-30 * 24 * time.Hour), PresenceInactive},
+	}
+	for _, tt := range tests {
+		if got := presenceStatus(tt.lastActiveAt, now); got != tt.want {
+			t.Errorf("presenceStatus(%s) = %s, want %s", now.Sub(tt.lastActiveAt), got, tt.want)
+		}
+	}
+}
+
+func TestPresenceTracker_TouchKeepsLatest(t *testing.T) {
+	tracker, store := setupTestPresence(t, "")
+	ctx := context.Background()
+
+	later := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if advanced, err := tracker.Touch(ctx, "EUSER123", later, "acl"); err != nil || !advanced {
+		t.Fatalf("expected first touch to advance, got %v (%v)", advanced, err)
+	}
+	if advanced, _ := tracker.Touch(ctx, "EUSER123", later.Add(-time.Hour), "heartbeat"); advanced {
+		t.Error("expected older activity to be ignored")
+	}
+
+	record, err := store.GetMemberPresence(ctx, "EUSER123")
+	if err != nil {
+		t.Fatalf("GetMemberPresence failed: %v", err)
+	}
+	if !record.LastActiveAt.Equal(later) || record.Source != "acl" {
+		t.Errorf("expected latest acl activity, got %+v", record)
+	}
+}
+
+func TestPresenceTracker_RefreshRecordsLocalMember(t *testing.T) {
+	tracker, store := setupTestPresence(t, "EUSER123")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// A mapped peer is resolved but its ACL is unavailable on the mock client
+	_, pub, _ := crypto.GenerateRandomEd25519KeyPair()
+	if err := store.StorePeerMapping(ctx, &anystore.PeerMapping{AID: "EOTHER", PeerID: pub.Account()}); err != nil {
+		t.Fatalf("StorePeerMapping failed: %v", err)
+	}
+	if err := tracker.Refresh(ctx); err == nil {
+		t.Error("expected error for unavailable community space ACLs")
+	}
+
+	record, err := store.GetMemberPresence(ctx, "EUSER123")
+	if err != nil {
+		t.Fatalf("expected local member to be recorded: %v", err)
+	}
+	if !record.LastActiveAt.Equal(now) || record.Source != "sync" {
+		t.Errorf("expected sync activity at %s, got %+v", now, record)
+	}
+}
+
+func TestHandleGetCommunityMembers_Presence(t *testing.T) {
+	handler, store, cleanup := setupSyncTestHandler(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, aid := range []string{"EUSER123", "EUSER456"} {
+		if err := store.StoreCredential(ctx, &anystore.CachedCredential{
+			ID:         "ESAID-" + aid,
+			IssuerAID:  "EAID123456789",
+			SubjectAID: aid,
+			SchemaID:   "EMatouMembershipSchemaV1",
+			Data:       map[string]interface{}{"role": "Member"},
+		}); err != nil {
+			t.Fatalf("failed to store credential: %v", err)
+		}
+	}
+
+	tracker := NewPresenceTracker(handler.spaceManager, store, nil)
+	seen := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	if _, err := tracker.Touch(ctx, "EUSER123", seen, "heartbeat"); err != nil {
+		t.Fatalf("Touch failed: %v", err)
+	}
+	handler.SetPresence(tracker)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/community/members", nil)
+	w := httptest.NewRecorder()
+	handler.HandleGetCommunityMembers(w, req)
+
+	var resp CommunityMembersResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	for _, m := range resp.Members {
+		switch m.AID {
+		case "EUSER123":
+			if m.Presence != PresenceActive || m.LastActiveAt != seen.Format(time.RFC3339) {
+				t.Errorf("expected active presence at %s, got %+v", seen, m)
+			}
+		case "EUSER456":
+			if m.Presence != "" || m.LastActiveAt != "" {
+				t.Errorf("expected no presence for unseen member, got %+v", m)
+			}
+		}
+	}
+}
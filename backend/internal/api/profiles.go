@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -14,9 +15,12 @@ import (
 
 // ProfilesHandler handles profile and type definition HTTP requests.
 type ProfilesHandler struct {
-	spaceManager *anysync.SpaceManager
-	userIdentity *identity.UserIdentity
-	registry     *types.Registry
+	spaceManager  *anysync.SpaceManager
+	userIdentity  *identity.UserIdentity
+	registry      *types.Registry
+	taxonomy      *TaxonomyHandler
+	contributions *ContributionsHandler
+	memberAccess  *MemberAccessGranter
 }
 
 // NewProfilesHandler creates a new profiles handler.
@@ -32,6 +36,23 @@ func NewProfilesHandler(
 	}
 }
 
+// SetTaxonomy enables validation of SharedProfile skills and interests
+// against the community's controlled vocabularies.
+func (h *ProfilesHandler) SetTaxonomy(t *TaxonomyHandler) {
+	h.taxonomy = t
+}
+
+// SetContributions adds the member's contribution summary to their profiles.
+func (h *ProfilesHandler) SetContributions(c *ContributionsHandler) {
+	h.contributions = c
+}
+
+// SetMemberAccess grants new members space access when their profiles are
+// initialized after credential issuance.
+func (h *ProfilesHandler) SetMemberAccess(g *MemberAccessGranter) {
+	h.memberAccess = g
+}
+
 // HandleListTypes handles GET /api/v1/types — list all type definitions.
 func (h *ProfilesHandler) HandleListTypes(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -105,6 +126,19 @@ func (h *ProfilesHandler) HandleCreateProfile(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Normalize tags against the taxonomy (synonyms -> canonical IDs)
+	if req.Type == "SharedProfile" && h.taxonomy != nil {
+		normalized, tagErrs := h.taxonomy.NormalizeProfileTags(r.Context(), req.Data)
+		if len(tagErrs) > 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"error":            "validation failed",
+				"validationErrors": tagErrs,
+			})
+			return
+		}
+		req.Data = normalized
+	}
+
 	if errs, err := h.registry.Validate(req.Type, req.Data); err != nil {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
 		return
@@ -189,6 +223,10 @@ func (h *ProfilesHandler) HandleCreateProfile(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	token := requestConsistencyToken(r)
+	token.AddChanges(spaceID, headID)
+	setConsistencyToken(w, token)
+
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"success":  true,
 		"objectId": objectID,
@@ -337,6 +375,12 @@ func (h *ProfilesHandler) HandleMyProfiles(w http.ResponseWriter, r *http.Reques
 		}
 	}
 
+	if h.contributions != nil {
+		if summary, err := h.contributions.Summary(ctx, aid); err == nil {
+			result["contributions"] = summary
+		}
+	}
+
 	writeJSON(w, http.StatusOK, result)
 }
 
@@ -351,11 +395,16 @@ type InitMemberProfilesRequest struct {
 	Bio            string          `json:"bio,omitempty"`
 	Interests      []string        `json:"interests,omitempty"`
 	ProfileData    json.RawMessage `json:"profileData,omitempty"` // Optional registration data
+	PeerID         string          `json:"peerId,omitempty"`      // Member's any-sync peer ID, if known
+	// ReplacePeer approves replacing a different peer already mapped to
+	// the member's AID
+	ReplacePeer bool `json:"replacePeer,omitempty"`
 }
 
 // HandleInitMemberProfiles handles POST /api/v1/profiles/init-member.
 // Called by admin after credential issuance + space invite to create the
-// member's CommunityProfile in the read-only space.
+// member's CommunityProfile in the read-only space. Members with a known
+// peer ID are also added to the community spaces' ACLs.
 func (h *ProfilesHandler) HandleInitMemberProfiles(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
@@ -381,6 +430,22 @@ func (h *ProfilesHandler) HandleInitMemberProfiles(w http.ResponseWriter, r *htt
 		req.Role = "Member"
 	}
 
+	if req.PeerID != "" && h.memberAccess != nil {
+		err := h.memberAccess.RecordPeer(r.Context(), req.MemberAID, req.PeerID, req.ReplacePeer && isAdminCaller(r))
+		if errors.Is(err, errPeerMapped) {
+			writeJSON(w, http.StatusConflict, map[string]string{
+				"error": err.Error() + "; set replacePeer to replace it",
+			})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("invalid peerId: %v", err),
+			})
+			return
+		}
+	}
+
 	roSpaceID := h.spaceManager.GetCommunityReadOnlySpaceID()
 	if roSpaceID == "" {
 		writeJSON(w, http.StatusConflict, map[string]string{
@@ -476,6 +541,8 @@ func (h *ProfilesHandler) HandleInitMemberProfiles(w http.ResponseWriter, r *htt
 		"headId":   headID,
 		"spaceId":  roSpaceID,
 	}
+	token := requestConsistencyToken(r)
+	token.AddChanges(roSpaceID, headID)
 
 	// Also create SharedProfile in community writable space
 	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
@@ -528,12 +595,20 @@ func (h *ProfilesHandler) HandleInitMemberProfiles(w http.ResponseWriter, r *htt
 					result["sharedProfileObjectId"] = sharedObjectID
 					result["sharedProfileHeadId"] = sharedHeadID
 					result["sharedProfileSpaceId"] = communitySpaceID
+					token.AddChanges(communitySpaceID, sharedHeadID)
 					fmt.Printf("[Profiles] Created SharedProfile %s in community space %s\n", sharedObjectID, communitySpaceID)
 				}
 			}
 		}
 	}
 
+	// The credential is issued, so its holder gets space access without a
+	// separate invite/join round trip
+	if h.memberAccess != nil {
+		result["access"] = h.memberAccess.GrantMember(ctx, req.MemberAID)
+	}
+
+	setConsistencyToken(w, token)
 	writeJSON(w, http.StatusOK, result)
 }
 
@@ -584,7 +659,9 @@ func filterObjectsByAID(objects []*anysync.ObjectPayload, aid string) []*anysync
 func deduplicateObjects(objects []*anysync.ObjectPayload) []*anysync.ObjectPayload {
 	byID := make(map[string]*anysync.ObjectPayload)
 	for _, obj := range objects {
-		if existing, ok := byID[obj.ID]; !ok || obj.Version > existing.Version {
+		// Same tie-break as ObjectTreeManager.ReadLatestByID for concurrent versions
+		if existing, ok := byID[obj.ID]; !ok || obj.Version > existing.Version ||
+			(obj.Version == existing.Version && obj.Timestamp > existing.Timestamp) {
 			byID[obj.ID] = obj
 		}
 	}
@@ -0,0 +1,837 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/anyproto/any-sync/commonspace/object/acl/list"
+	"github.com/anyproto/any-sync/util/crypto"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/keri"
+)
+
+// Project statuses and roster roles
+const (
+	ProjectActive     = "active"
+	ProjectArchived   = "archived"
+	ProjectRoleLead   = "lead"
+	ProjectRoleMember = "member"
+)
+
+// maxProjectName bounds the project name length
+const maxProjectName = 100
+
+// ProjectsHandler handles project and working-group endpoints. Project
+// objects (name, roster, file index) live in the community space; each
+// project can also own a dedicated space holding its files, shared with
+// roster members through ACL invites.
+type ProjectsHandler struct {
+	spaceManager *anysync.SpaceManager
+	userIdentity *identity.UserIdentity
+	trust        *TrustHandler
+	events       *EventBroker
+}
+
+// NewProjectsHandler creates a new projects handler
+func NewProjectsHandler(
+	spaceManager *anysync.SpaceManager,
+	userIdentity *identity.UserIdentity,
+	trustHandler *TrustHandler,
+) *ProjectsHandler {
+	return &ProjectsHandler{
+		spaceManager: spaceManager,
+		userIdentity: userIdentity,
+		trust:        trustHandler,
+	}
+}
+
+// SetEvents broadcasts space:created and acl:changed events for project spaces
+func (h *ProjectsHandler) SetEvents(events *EventBroker) {
+	h.events = events
+}
+
+// ProjectMember is an entry on a project roster
+type ProjectMember struct {
+	AID      string `json:"aid"`
+	Role     string `json:"role"` // lead or member
+	JoinedAt string `json:"joinedAt"`
+}
+
+// ProjectFile indexes a file stored in the project space
+type ProjectFile struct {
+	Ref         string `json:"ref"` // CID in the project space
+	Name        string `json:"name"`
+	ContentType string `json:"contentType"`
+	Size        int    `json:"size"`
+	UploadedBy  string `json:"uploadedBy"`
+	UploadedAt  string `json:"uploadedAt"`
+}
+
+// Project is the data stored for a Project object
+type Project struct {
+	Name          string          `json:"name"`
+	Description   string          `json:"description,omitempty"`
+	Status        string          `json:"status"`
+	RequiredRoles []string        `json:"requiredRoles,omitempty"` // Credential roles allowed to join; empty means any member
+	Members       []ProjectMember `json:"members"`
+	SpaceID       string          `json:"spaceId,omitempty"`
+	Files         []ProjectFile   `json:"files,omitempty"`
+	CreatedBy     string          `json:"createdBy"`
+	CreatedAt     string          `json:"createdAt"`
+	UpdatedAt     string          `json:"updatedAt,omitempty"`
+}
+
+// ProjectView is a project with its object ID
+type ProjectView struct {
+	ID string `json:"id"`
+	Project
+}
+
+// CreateProjectRequest is the body for POST /api/v1/projects
+type CreateProjectRequest struct {
+	Name          string   `json:"name"`
+	Description   string   `json:"description,omitempty"`
+	RequiredRoles []string `json:"requiredRoles,omitempty"`
+}
+
+// AddProjectMemberRequest is the body for POST /api/v1/projects/{id}/members
+type AddProjectMemberRequest struct {
+	AID  string `json:"aid"`
+	Role string `json:"role,omitempty"` // Defaults to member
+}
+
+// ProjectSpaceInviteRequest is the body for POST /api/v1/projects/{id}/space/invite
+type ProjectSpaceInviteRequest struct {
+	AID string `json:"aid"`
+}
+
+// JoinProjectSpaceRequest is the body for POST /api/v1/projects/{id}/space/join
+type JoinProjectSpaceRequest struct {
+	InviteKey string `json:"inviteKey"` // base64-encoded invite private key
+}
+
+// Member returns the roster entry for aid, or nil
+func (p *Project) Member(aid string) *ProjectMember {
+	for i := range p.Members {
+		if p.Members[i].AID == aid {
+			return &p.Members[i]
+		}
+	}
+	return nil
+}
+
+// IsLead returns true if aid leads the project
+func (p *Project) IsLead(aid string) bool {
+	m := p.Member(aid)
+	return m != nil && m.Role == ProjectRoleLead
+}
+
+// AllowsRole returns true if a member holding role may join the project
+func (p *Project) AllowsRole(role string) bool {
+	if len(p.RequiredRoles) == 0 {
+		return true
+	}
+	for _, r := range p.RequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// removeMember drops aid from the roster, returning false if absent
+func (p *Project) removeMember(aid string) bool {
+	for i := range p.Members {
+		if p.Members[i].AID == aid {
+			p.Members = append(p.Members[:i], p.Members[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// leadCount returns the number of leads on the roster
+func (p *Project) leadCount() int {
+	n := 0
+	for _, m := range p.Members {
+		if m.Role == ProjectRoleLead {
+			n++
+		}
+	}
+	return n
+}
+
+// validateRequiredRoles checks every required role is a known credential role
+func validateRequiredRoles(roles []string) error {
+	for _, role := range roles {
+		if !keri.IsValidRole(role) {
+			return fmt.Errorf("invalid role: %s", role)
+		}
+	}
+	return nil
+}
+
+// handleProjects routes /api/v1/projects
+func (h *ProjectsHandler) handleProjects(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.HandleList(w, r)
+	case http.MethodPost:
+		h.HandleCreate(w, r)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// HandleList handles GET /api/v1/projects
+// Query params:
+//   - member: Only projects with this AID on the roster
+//   - status: Filter by status (active, archived)
+func (h *ProjectsHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
+	if communitySpaceID == "" {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "community space not configured"})
+		return
+	}
+
+	objects, err := readLatestObjects(r.Context(), h.spaceManager, communitySpaceID, "Project")
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read projects: %v", err),
+		})
+		return
+	}
+
+	member := r.URL.Query().Get("member")
+	status := r.URL.Query().Get("status")
+
+	projects := make([]*ProjectView, 0)
+	for _, obj := range objects {
+		p := &ProjectView{ID: obj.ID}
+		if err := json.Unmarshal(obj.Data, &p.Project); err != nil {
+			continue
+		}
+		if member != "" && p.Member(member) == nil {
+			continue
+		}
+		if status != "" && p.Status != status {
+			continue
+		}
+		projects = append(projects, p)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"projects": projects,
+		"count":    len(projects),
+	})
+}
+
+// HandleCreate handles POST /api/v1/projects. The creator becomes the first
+// lead and a project space is created for the project's files.
+func (h *ProjectsHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+
+	var req CreateProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > maxProjectName {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("name is required (max %d characters)", maxProjectName),
+		})
+		return
+	}
+	if err := validateRequiredRoles(req.RequiredRoles); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
+	if communitySpaceID == "" {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "community space not configured"})
+		return
+	}
+
+	ctx := r.Context()
+	if status, err := h.checkMember(ctx, me, nil); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	project := &ProjectView{
+		ID: fmt.Sprintf("Project-%s-%d", me, time.Now().UnixMilli()),
+		Project: Project{
+			Name:          req.Name,
+			Description:   req.Description,
+			Status:        ProjectActive,
+			RequiredRoles: req.RequiredRoles,
+			Members:       []ProjectMember{{AID: me, Role: ProjectRoleLead, JoinedAt: now}},
+			CreatedBy:     me,
+			CreatedAt:     now,
+		},
+	}
+
+	// A missing project space isn't fatal; a lead can retry via POST .../space
+	if space, err := h.spaceManager.CreateProjectSpace(ctx, me, req.Name); err != nil {
+		fmt.Printf("[Projects] Warning: failed to create space for %s: %v\n", project.ID, err)
+	} else {
+		project.SpaceID = space.SpaceID
+		h.events.Broadcast(spaceCreatedEvent(space))
+	}
+
+	if _, err := writeObject(ctx, h.spaceManager, communitySpaceID, project.ID, "Project", project.Project); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	fmt.Printf("[Projects] %s created project %s (%s)\n", me, project.ID, req.Name)
+	writeJSON(w, http.StatusCreated, project)
+}
+
+// handleProject routes /api/v1/projects/{id}[/...]
+func (h *ProjectsHandler) handleProject(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/projects/")
+	parts := strings.Split(path, "/")
+	if parts[0] == "" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+	id := parts[0]
+	action := strings.Join(parts[1:], "/")
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.handleGet(w, r, id)
+	case action == "join" && r.Method == http.MethodPost:
+		h.handleJoin(w, r, id)
+	case action == "leave" && r.Method == http.MethodPost:
+		h.handleLeave(w, r, id)
+	case action == "members" && r.Method == http.MethodPost:
+		h.handleAddMember(w, r, id)
+	case strings.HasPrefix(action, "members/") && r.Method == http.MethodDelete:
+		h.handleRemoveMember(w, r, id, strings.TrimPrefix(action, "members/"))
+	case action == "space" && r.Method == http.MethodPost:
+		h.handleCreateSpace(w, r, id)
+	case action == "space/invite" && r.Method == http.MethodPost:
+		h.handleSpaceInvite(w, r, id)
+	case action == "space/join" && r.Method == http.MethodPost:
+		h.handleSpaceJoin(w, r, id)
+	case action == "files" && r.Method == http.MethodGet:
+		h.handleListFiles(w, r, id)
+	case action == "files" && r.Method == http.MethodPost:
+		h.handleUploadFile(w, r, id)
+	case strings.HasPrefix(action, "files/") && r.Method == http.MethodGet:
+		h.handleDownloadFile(w, r, id, strings.TrimPrefix(action, "files/"))
+	case action == "" || action == "join" || action == "leave" || action == "members" ||
+		strings.HasPrefix(action, "members/") || strings.HasPrefix(action, "space") || strings.HasPrefix(action, "files"):
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
+
+// handleGet handles GET /api/v1/projects/{id}
+func (h *ProjectsHandler) handleGet(w http.ResponseWriter, r *http.Request, id string) {
+	project, status, err := h.loadProject(r.Context(), id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, project)
+}
+
+// handleJoin handles POST /api/v1/projects/{id}/join. The joiner's
+// credential role must be one of the project's required roles.
+func (h *ProjectsHandler) handleJoin(w http.ResponseWriter, r *http.Request, id string) {
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+
+	ctx := r.Context()
+	project, status, err := h.loadProject(ctx, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	if project.Status != ProjectActive {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "project is archived"})
+		return
+	}
+	if project.Member(me) != nil {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "already a project member"})
+		return
+	}
+	if status, err := h.checkMember(ctx, me, &project.Project); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	project.Members = append(project.Members, ProjectMember{
+		AID:      me,
+		Role:     ProjectRoleMember,
+		JoinedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+	if err := h.saveProject(ctx, project); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	fmt.Printf("[Projects] %s joined %s\n", me, project.ID)
+	writeJSON(w, http.StatusOK, project)
+}
+
+// handleLeave handles POST /api/v1/projects/{id}/leave
+func (h *ProjectsHandler) handleLeave(w http.ResponseWriter, r *http.Request, id string) {
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+	h.removeFromRoster(w, r, id, me)
+}
+
+// handleAddMember handles POST /api/v1/projects/{id}/members (leads only)
+func (h *ProjectsHandler) handleAddMember(w http.ResponseWriter, r *http.Request, id string) {
+	var req AddProjectMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+	if req.AID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "aid is required"})
+		return
+	}
+	if req.Role == "" {
+		req.Role = ProjectRoleMember
+	}
+	if req.Role != ProjectRoleMember && req.Role != ProjectRoleLead {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "role must be lead or member"})
+		return
+	}
+
+	ctx := r.Context()
+	project, status, err := h.loadProjectAsLead(ctx, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	// Promoting an existing member to lead needs no credential check
+	if existing := project.Member(req.AID); existing != nil {
+		if existing.Role == req.Role {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "already a project member"})
+			return
+		}
+		existing.Role = req.Role
+	} else {
+		if status, err := h.checkMember(ctx, req.AID, &project.Project); err != nil {
+			writeJSON(w, status, map[string]string{"error": err.Error()})
+			return
+		}
+		project.Members = append(project.Members, ProjectMember{
+			AID:      req.AID,
+			Role:     req.Role,
+			JoinedAt: time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+
+	if project.leadCount() == 0 {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "project must keep at least one lead"})
+		return
+	}
+	if err := h.saveProject(ctx, project); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, project)
+}
+
+// handleRemoveMember handles DELETE /api/v1/projects/{id}/members/{aid}.
+// Leads can remove anyone; members can only remove themselves.
+func (h *ProjectsHandler) handleRemoveMember(w http.ResponseWriter, r *http.Request, id, aid string) {
+	if aid == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "aid is required"})
+		return
+	}
+	h.removeFromRoster(w, r, id, aid)
+}
+
+// removeFromRoster removes aid from a project, keeping at least one lead
+func (h *ProjectsHandler) removeFromRoster(w http.ResponseWriter, r *http.Request, id, aid string) {
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+
+	ctx := r.Context()
+	project, status, err := h.loadProject(ctx, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	if aid != me && !project.IsLead(me) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "only project leads can remove members"})
+		return
+	}
+	if !project.removeMember(aid) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not a project member"})
+		return
+	}
+	if project.leadCount() == 0 {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "project must keep at least one lead"})
+		return
+	}
+	if err := h.saveProject(ctx, project); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	fmt.Printf("[Projects] %s removed from %s\n", aid, project.ID)
+	writeJSON(w, http.StatusOK, project)
+}
+
+// handleCreateSpace handles POST /api/v1/projects/{id}/space (leads only),
+// creating the project space if it doesn't exist yet
+func (h *ProjectsHandler) handleCreateSpace(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+	project, status, err := h.loadProjectAsLead(ctx, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	if project.SpaceID != "" {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "project already has a space"})
+		return
+	}
+
+	space, err := h.spaceManager.CreateProjectSpace(ctx, h.userIdentity.GetAID(), project.Name)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	project.SpaceID = space.SpaceID
+	h.events.Broadcast(spaceCreatedEvent(space))
+	if err := h.saveProject(ctx, project); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, project)
+}
+
+// handleSpaceInvite handles POST /api/v1/projects/{id}/space/invite (leads
+// only). Returns an invite key the roster member passes to .../space/join.
+// Must be called on the backend that created the project space.
+func (h *ProjectsHandler) handleSpaceInvite(w http.ResponseWriter, r *http.Request, id string) {
+	var req ProjectSpaceInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+
+	ctx := r.Context()
+	project, status, err := h.loadProjectAsLead(ctx, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	if project.SpaceID == "" {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "project has no space"})
+		return
+	}
+	if project.Member(req.AID) == nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "only roster members can be invited to the project space"})
+		return
+	}
+
+	inviteKey, err := h.spaceManager.ACLManager().CreateOpenInvite(ctx, project.SpaceID, list.AclPermissionsWriter)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to create invite: %v", err),
+		})
+		return
+	}
+	h.events.Broadcast(aclChangedEvent(project.SpaceID, ACLInviteCreated, req.AID))
+	inviteKeyBytes, err := inviteKey.Marshall()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to marshal invite key: %v", err),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"spaceId":   project.SpaceID,
+		"aid":       req.AID,
+		"inviteKey": base64.StdEncoding.EncodeToString(inviteKeyBytes),
+	})
+}
+
+// handleSpaceJoin handles POST /api/v1/projects/{id}/space/join (roster
+// members only), joining the project space with a lead's invite key
+func (h *ProjectsHandler) handleSpaceJoin(w http.ResponseWriter, r *http.Request, id string) {
+	var req JoinProjectSpaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+	inviteKeyBytes, err := base64.StdEncoding.DecodeString(req.InviteKey)
+	if err != nil || len(inviteKeyBytes) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "inviteKey must be base64-encoded"})
+		return
+	}
+	inviteKey, err := crypto.UnmarshalEd25519PrivateKeyProto(inviteKeyBytes)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid invite key: %v", err),
+		})
+		return
+	}
+
+	ctx := r.Context()
+	project, status, err := h.loadProjectAsMember(ctx, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	if project.SpaceID == "" {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "project has no space"})
+		return
+	}
+
+	metadata := []byte(fmt.Sprintf(`{"aid":"%s","project":"%s","joinedAt":"%s"}`,
+		h.userIdentity.GetAID(), project.ID, time.Now().UTC().Format(time.RFC3339)))
+	if err := h.spaceManager.JoinProjectSpace(ctx, project.SpaceID, inviteKey, metadata); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	h.events.Broadcast(aclChangedEvent(project.SpaceID, ACLJoined, h.userIdentity.GetAID()))
+
+	writeJSON(w, http.StatusOK, map[string]string{"spaceId": project.SpaceID})
+}
+
+// handleListFiles handles GET /api/v1/projects/{id}/files
+func (h *ProjectsHandler) handleListFiles(w http.ResponseWriter, r *http.Request, id string) {
+	project, status, err := h.loadProject(r.Context(), id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	files := project.Files
+	if files == nil {
+		files = []ProjectFile{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"spaceId": project.SpaceID,
+		"files":   files,
+		"count":   len(files),
+	})
+}
+
+// handleUploadFile handles POST /api/v1/projects/{id}/files (roster members
+// only). Stores the file in the project space and indexes it on the project.
+func (h *ProjectsHandler) handleUploadFile(w http.ResponseWriter, r *http.Request, id string) {
+	fileManager := h.spaceManager.FileManager()
+	if fileManager == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "file storage not available (filenode not configured)",
+		})
+		return
+	}
+
+	ctx := r.Context()
+	project, status, err := h.loadProjectAsMember(ctx, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	if project.SpaceID == "" {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "project has no space"})
+		return
+	}
+
+	data, contentType, ok := readUpload(w, r, false)
+	if !ok {
+		return
+	}
+	name := "file"
+	if headers := r.MultipartForm.File["file"]; len(headers) > 0 && headers[0].Filename != "" {
+		name = headers[0].Filename
+	}
+
+	fileRef, err := fileManager.AddFile(ctx, project.SpaceID, bytes.NewReader(data), contentType,
+		int64(len(data)), h.spaceManager.GetClient().GetSigningKey())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to upload file: %v", err),
+		})
+		return
+	}
+
+	file := ProjectFile{
+		Ref:         fileRef,
+		Name:        name,
+		ContentType: contentType,
+		Size:        len(data),
+		UploadedBy:  h.userIdentity.GetAID(),
+		UploadedAt:  time.Now().UTC().Format(time.RFC3339),
+	}
+	project.Files = append(project.Files, file)
+	if err := h.saveProject(ctx, project); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, file)
+}
+
+// handleDownloadFile handles GET /api/v1/projects/{id}/files/{ref}
+func (h *ProjectsHandler) handleDownloadFile(w http.ResponseWriter, r *http.Request, id, ref string) {
+	fileManager := h.spaceManager.FileManager()
+	if fileManager == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "file storage not available (filenode not configured)",
+		})
+		return
+	}
+
+	ctx := r.Context()
+	project, status, err := h.loadProject(ctx, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	var file *ProjectFile
+	for i := range project.Files {
+		if project.Files[i].Ref == ref {
+			file = &project.Files[i]
+			break
+		}
+	}
+	if file == nil || project.SpaceID == "" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "file not found"})
+		return
+	}
+
+	reader, contentType, err := fileManager.GetFile(ctx, project.SpaceID, ref)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			"error": fmt.Sprintf("file not found: %v", err),
+		})
+		return
+	}
+	defer reader.Close()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.Name))
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, reader)
+}
+
+// checkMember verifies aid is a credentialed community member and, when a
+// project is given, that their credential role allows joining it
+func (h *ProjectsHandler) checkMember(ctx context.Context, aid string, project *Project) (int, error) {
+	if h.trust == nil {
+		return http.StatusOK, nil
+	}
+
+	graph, err := h.trust.buildGraph(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to build trust graph: %v", err)
+	}
+	node := graph.GetNode(aid)
+	if node == nil || !node.IsMember() {
+		return http.StatusForbidden, fmt.Errorf("%s is not a community member", aid)
+	}
+	if project != nil && !project.AllowsRole(node.Role) {
+		return http.StatusForbidden, fmt.Errorf("joining requires one of the roles: %s",
+			strings.Join(project.RequiredRoles, ", "))
+	}
+	return http.StatusOK, nil
+}
+
+// loadProject reads the latest version of a project
+func (h *ProjectsHandler) loadProject(ctx context.Context, id string) (*ProjectView, int, error) {
+	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
+	if communitySpaceID == "" {
+		return nil, http.StatusServiceUnavailable, fmt.Errorf("community space not configured")
+	}
+
+	obj, err := h.spaceManager.ObjectTreeManager().ReadLatestByID(ctx, communitySpaceID, id)
+	if err != nil || obj.Type != "Project" {
+		return nil, http.StatusNotFound, fmt.Errorf("project not found")
+	}
+
+	project := &ProjectView{ID: obj.ID}
+	if err := json.Unmarshal(obj.Data, &project.Project); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("invalid project: %v", err)
+	}
+	return project, http.StatusOK, nil
+}
+
+// loadProjectAsMember loads a project the local user is on the roster of
+func (h *ProjectsHandler) loadProjectAsMember(ctx context.Context, id string) (*ProjectView, int, error) {
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("identity not configured")
+	}
+	project, status, err := h.loadProject(ctx, id)
+	if err != nil {
+		return nil, status, err
+	}
+	if project.Member(me) == nil {
+		return nil, http.StatusForbidden, fmt.Errorf("not a project member")
+	}
+	return project, http.StatusOK, nil
+}
+
+// loadProjectAsLead loads a project the local user leads
+func (h *ProjectsHandler) loadProjectAsLead(ctx context.Context, id string) (*ProjectView, int, error) {
+	project, status, err := h.loadProjectAsMember(ctx, id)
+	if err != nil {
+		return nil, status, err
+	}
+	if !project.IsLead(h.userIdentity.GetAID()) {
+		return nil, http.StatusForbidden, fmt.Errorf("only project leads can do this")
+	}
+	return project, http.StatusOK, nil
+}
+
+// saveProject writes a new version of a project to the community space
+func (h *ProjectsHandler) saveProject(ctx context.Context, project *ProjectView) error {
+	project.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	_, err := writeObject(ctx, h.spaceManager, h.spaceManager.GetCommunitySpaceID(), project.ID, "Project", project.Project)
+	return err
+}
+
+// RegisterRoutes registers project routes on the mux
+func (h *ProjectsHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/projects", h.handleProjects)
+	mux.HandleFunc("/api/v1/projects/", h.handleProject)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+func newTestProjectsHandler(t *testing.T, aid string) *ProjectsHandler {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "projects_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	userIdentity := identity.New(tmpDir)
+	if aid != "" {
+		if err := userIdentity.SetIdentity(aid, secret.NewMnemonic("test mnemonic")); err != nil {
+			t.Fatalf("SetIdentity failed: %v", err)
+		}
+	}
+	return NewProjectsHandler(nil, userIdentity, nil)
+}
+
+func TestProjectCreate_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		aid        string
+		body       string
+		wantStatus int
+	}{
+		{"no identity", "", `{"name":"Garden"}`, http.StatusBadRequest},
+		{"missing name", "EALICE", `{"name":"  "}`, http.StatusBadRequest},
+		{"name too long", "EALICE", `{"name":"` + strings.Repeat("x", maxProjectName+1) + `"}`, http.StatusBadRequest},
+		{"unknown role", "EALICE", `{"name":"Garden","requiredRoles":["Gardener"]}`, http.StatusBadRequest},
+		{"invalid json", "EALICE", `{`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestProjectsHandler(t, tt.aid)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/projects", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.handleProjects(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestProject_Roster(t *testing.T) {
+	p := &Project{
+		RequiredRoles: []string{"Contributor", "Trusted Member"},
+		Members: []ProjectMember{
+			{AID: "EALICE", Role: ProjectRoleLead},
+			{AID: "EBOB", Role: ProjectRoleMember},
+		},
+	}
+
+	if !p.IsLead("EALICE") || p.IsLead("EBOB") || p.IsLead("ECAROL") {
+		t.Error("unexpected lead check result")
+	}
+	if !p.AllowsRole("Contributor") || p.AllowsRole("Member") {
+		t.Error("unexpected role check result")
+	}
+	if !(&Project{}).AllowsRole("Member") {
+		t.Error("project without required roles should allow any member")
+	}
+
+	if !p.removeMember("EBOB") || p.Member("EBOB") != nil {
+		t.Error("expected EBOB to be removed")
+	}
+	if p.removeMember("ECAROL") {
+		t.Error("removing a non-member should return false")
+	}
+	if p.leadCount() != 1 {
+		t.Errorf("expected 1 lead, got %d", p.leadCount())
+	}
+}
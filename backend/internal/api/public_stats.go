@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/matou-dao/backend/internal/trust"
+)
+
+// publicStatsTTL is how long computed stats are served before the graph
+// is built again. The endpoint is public, so this bounds how often
+// anonymous callers can make the backend build the trust graph.
+const publicStatsTTL = time.Minute
+
+// PublicStatsHandler serves aggregate community statistics to anonymous
+// callers for public pages. It reads the trust graph but only ever
+// returns whole-community counts and averages, never per-AID data.
+type PublicStatsHandler struct {
+	trust *TrustHandler
+	now   func() time.Time
+
+	mu       sync.Mutex
+	cached   *trust.PublicStats
+	cachedAt time.Time
+}
+
+// NewPublicStatsHandler creates a public stats handler over an org's
+// trust graph
+func NewPublicStatsHandler(trustHandler *TrustHandler) *PublicStatsHandler {
+	return &PublicStatsHandler{
+		trust: trustHandler,
+		now:   time.Now,
+	}
+}
+
+// HandleStats handles GET /api/v1/public/stats
+func (h *PublicStatsHandler) HandleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := h.now()
+	if h.cached == nil || now.Sub(h.cachedAt) >= publicStatsTTL {
+		ctx := r.Context()
+		graph, err := h.trust.buildGraph(ctx)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to build trust graph"})
+			return
+		}
+		h.cached = trust.CalculatePublicStats(graph, now)
+		h.cachedAt = now
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	writeJSON(w, http.StatusOK, h.cached)
+}
+
+// RegisterRoutes registers public stats routes on the mux
+func (h *PublicStatsHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/public/stats", h.HandleStats)
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RouteLimit is a token-bucket budget shared by a group of expensive
+// routes. Each client address has its own bucket, and so does each
+// authenticated AID; a request is charged to both and refused when either
+// is empty.
+type RouteLimit struct {
+	Name string
+	// Routes are exact paths, or prefixes when they end in "/"
+	Routes []string
+	// Methods limits the budget to these methods; empty matches all
+	Methods []string
+	// PerIP and PerAID are the sustained requests per minute (0 = unlimited)
+	PerIP  int
+	PerAID int
+	// Burst is how many requests a full bucket allows at once; 0 uses the
+	// per-minute budget
+	Burst int
+}
+
+// DefaultRouteLimits budget trust graph builds, credential issuance and
+// file uploads
+var DefaultRouteLimits = []RouteLimit{
+	{Name: "trust", Routes: DefaultShedRoutes, PerIP: 30, PerAID: 20, Burst: 5},
+	{
+		Name:    "issuance",
+		Routes:  []string{"/api/v1/credentials", "/api/v1/credentials/participation", "/api/v1/credentials/approvals"},
+		Methods: []string{http.MethodPost},
+		PerIP:   20,
+		PerAID:  10,
+		Burst:   5,
+	},
+	{
+		Name:    "uploads",
+		Routes:  []string{"/api/v1/files/upload", "/api/v1/files/uploads"},
+		Methods: []string{http.MethodPost},
+		PerIP:   30,
+		PerAID:  20,
+		Burst:   10,
+	},
+}
+
+// MergeRouteLimits applies configured budgets to the defaults: one named
+// like a default replaces it, and the rest are added after them
+func MergeRouteLimits(defaults, configured []RouteLimit) []RouteLimit {
+	merged := append([]RouteLimit(nil), defaults...)
+	for _, limit := range configured {
+		replaced := false
+		for i := range merged {
+			if merged[i].Name == limit.Name {
+				merged[i], replaced = limit, true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, limit)
+		}
+	}
+	return merged
+}
+
+// tokenBucket holds tokens as of updated
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// RouteLimiter applies route budgets per client address and per AID
+type RouteLimiter struct {
+	limits []RouteLimit
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// NewRouteLimiter creates a limiter for the given budgets. The first
+// budget that matches a request applies.
+func NewRouteLimiter(limits []RouteLimit) *RouteLimiter {
+	return &RouteLimiter{
+		limits:  limits,
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// limitFor returns the budget for a request, or nil
+func (l *RouteLimiter) limitFor(r *http.Request) *RouteLimit {
+	for i := range l.limits {
+		limit := &l.limits[i]
+		if !matchesRoute(r.URL.Path, limit.Routes) {
+			continue
+		}
+		if len(limit.Methods) == 0 {
+			return limit
+		}
+		for _, method := range limit.Methods {
+			if method == r.Method {
+				return limit
+			}
+		}
+	}
+	return nil
+}
+
+// charge is one bucket a request is charged to
+type charge struct {
+	key       string
+	perMinute int
+}
+
+// take refills the charged buckets and takes a token from each if all
+// have one. Otherwise nothing is taken and it returns the wait until the
+// emptiest has a token.
+func (l *RouteLimiter) take(charges []charge, burst int, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// A bucket idle for an hour has refilled and holds nothing a fresh one
+	// wouldn't, so drop those now and then to keep the map from growing
+	// with every client
+	if now.Sub(l.lastSweep) >= time.Minute {
+		for key, bucket := range l.buckets {
+			if now.Sub(bucket.updated) >= time.Hour {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	var wait time.Duration
+	buckets := make([]*tokenBucket, len(charges))
+	for i, c := range charges {
+		capacity := float64(burst)
+		if burst <= 0 {
+			capacity = float64(c.perMinute)
+		}
+		perSecond := float64(c.perMinute) / 60
+
+		bucket := l.buckets[c.key]
+		if bucket == nil {
+			bucket = &tokenBucket{tokens: capacity, updated: now}
+			l.buckets[c.key] = bucket
+		}
+		bucket.tokens = math.Min(capacity, bucket.tokens+now.Sub(bucket.updated).Seconds()*perSecond)
+		bucket.updated = now
+		buckets[i] = bucket
+
+		if bucket.tokens < 1 {
+			if w := time.Duration((1 - bucket.tokens) / perSecond * float64(time.Second)); w > wait {
+				wait = w
+			}
+		}
+	}
+	if wait > 0 {
+		return false, wait
+	}
+	for _, bucket := range buckets {
+		bucket.tokens--
+	}
+	return true, 0
+}
+
+// Allow charges a request to its route budget. It returns false, the
+// budget's name and the time until a token is free when the caller's
+// address or AID has used up the budget.
+func (l *RouteLimiter) Allow(r *http.Request) (bool, string, time.Duration) {
+	limit := l.limitFor(r)
+	if limit == nil {
+		return true, "", 0
+	}
+
+	var charges []charge
+	if limit.PerIP > 0 {
+		charges = append(charges, charge{key: limit.Name + "|ip|" + clientKey(r), perMinute: limit.PerIP})
+	}
+	if p := PrincipalFromContext(r.Context()); p != nil && p.AID != "" && limit.PerAID > 0 {
+		charges = append(charges, charge{key: limit.Name + "|aid|" + p.AID, perMinute: limit.PerAID})
+	}
+	if len(charges) == 0 {
+		return true, limit.Name, 0
+	}
+	ok, wait := l.take(charges, limit.Burst, l.now())
+	return ok, limit.Name, wait
+}
+
+// RouteLimitMiddleware refuses requests over their route budget with 429
+// and Retry-After. It runs after authentication so AID budgets see the
+// caller. A nil limiter limits nothing.
+func RouteLimitMiddleware(l *RouteLimiter, next http.Handler) http.Handler {
+	if l == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if ok, name, wait := l.Allow(r); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeJSON(w, http.StatusTooManyRequests, map[string]string{
+				"error": "rate limit exceeded",
+				"limit": name,
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRouteLimitMiddleware(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewRouteLimiter([]RouteLimit{
+		{Name: "issuance", Routes: []string{"/api/v1/credentials"}, Methods: []string{http.MethodPost}, PerIP: 60, PerAID: 2, Burst: 2},
+	})
+	limiter.now = func() time.Time { return now }
+	handler := RouteLimitMiddleware(limiter, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	send := func(method, aid, addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/credentials", nil)
+		req.RemoteAddr = addr
+		if aid != "" {
+			req = req.WithContext(context.WithValue(req.Context(), principalKey{}, &Principal{Kind: "token", AID: aid}))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// The AID's burst is used up whichever address it calls from
+	for _, addr := range []string{"10.0.0.1:1000", "10.0.0.2:1000"} {
+		if w := send(http.MethodPost, "EAlice", addr); w.Code != http.StatusOK {
+			t.Fatalf("expected request within the burst to pass, got %d", w.Code)
+		}
+	}
+	w := send(http.MethodPost, "EAlice", "10.0.0.3:1000")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 over the AID budget, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("expected Retry-After 30 at 2/min, got %q", got)
+	}
+
+	// Other AIDs, reads and other routes aren't affected
+	if w := send(http.MethodPost, "EBob", "10.0.0.3:1000"); w.Code != http.StatusOK {
+		t.Errorf("expected another AID to pass, got %d", w.Code)
+	}
+	if w := send(http.MethodGet, "EAlice", "10.0.0.3:1000"); w.Code != http.StatusOK {
+		t.Errorf("expected reads to pass, got %d", w.Code)
+	}
+
+	// Tokens refill at the sustained rate
+	now = now.Add(30 * time.Second)
+	if w := send(http.MethodPost, "EAlice", "10.0.0.3:1000"); w.Code != http.StatusOK {
+		t.Errorf("expected a refilled token to pass, got %d", w.Code)
+	}
+}
+
+func TestRouteLimiter_PerIP(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewRouteLimiter([]RouteLimit{{Name: "trust", Routes: []string{"/api/v1/trust/"}, PerIP: 1, PerAID: 10}})
+	limiter.now = func() time.Time { return now }
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/trust/graph", nil)
+	req.RemoteAddr = "10.0.0.1:1000"
+	if ok, _, _ := limiter.Allow(req); !ok {
+		t.Fatal("expected the first request to pass")
+	}
+
+	// A refused request takes nothing from the AID budget
+	authed := req.WithContext(context.WithValue(req.Context(), principalKey{}, &Principal{Kind: "token", AID: "EAlice"}))
+	if ok, name, wait := limiter.Allow(authed); ok || name != "trust" || wait != time.Minute {
+		t.Errorf("expected the address to be out of budget for a minute, got %v %q %s", ok, name, wait)
+	}
+	if tokens := limiter.buckets["trust|aid|EAlice"].tokens; tokens != 10 {
+		t.Errorf("expected the AID bucket untouched, got %v tokens", tokens)
+	}
+}
+
+func TestMergeRouteLimits(t *testing.T) {
+	merged := MergeRouteLimits(DefaultRouteLimits, []RouteLimit{
+		{Name: "trust", Routes: []string{"/api/v1/trust/"}, PerIP: 5},
+		{Name: "exports", Routes: []string{"/api/v1/admin/export"}, PerAID: 1},
+	})
+	if len(merged) != len(DefaultRouteLimits)+1 {
+		t.Fatalf("expected one added budget, got %d", len(merged))
+	}
+	if merged[0].PerIP != 5 || merged[0].PerAID != 0 {
+		t.Errorf("expected trust to be replaced, got %+v", merged[0])
+	}
+	if merged[len(merged)-1].Name != "exports" || DefaultRouteLimits[0].PerIP != 30 {
+		t.Error("expected the new budget appended and the defaults unchanged")
+	}
+}
@@ -0,0 +1,272 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/audit"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/metrics"
+)
+
+// ReceiptsHandler exposes the credential receipt ledger: an append-only,
+// hash-chained tree in the admin space recording every issuance, delivery
+// stage and revocation. Only stewards hold the admin space keys.
+type ReceiptsHandler struct {
+	spaceManager *anysync.SpaceManager
+	userIdentity *identity.UserIdentity
+	events       *EventBroker
+	audit        *audit.Log
+}
+
+// NewReceiptsHandler creates a new receipts handler
+func NewReceiptsHandler(spaceManager *anysync.SpaceManager, userIdentity *identity.UserIdentity) *ReceiptsHandler {
+	return &ReceiptsHandler{
+		spaceManager: spaceManager,
+		userIdentity: userIdentity,
+	}
+}
+
+// SetEvents broadcasts credential:revoked when a revocation receipt is
+// recorded, and credential:delivery when a later delivery stage is
+func (h *ReceiptsHandler) SetEvents(events *EventBroker) {
+	h.events = events
+}
+
+// SetAudit records issuance and revocation receipts in the audit log
+func (h *ReceiptsHandler) SetAudit(log *audit.Log) {
+	h.audit = log
+}
+
+// ReceiptRecorder records credential receipts. The credentials handler uses
+// it to log issuances as they are stored and report delivery status; the
+// inbox handler logs deliveries and recipients' confirmations.
+type ReceiptRecorder interface {
+	Available() bool
+	Record(ctx context.Context, action, said, schema, recipient string) (*anysync.ReceiptPayload, bool, error)
+	DeliveryStatus(ctx context.Context, said string) (*anysync.CredentialDelivery, error)
+}
+
+// RecordReceiptRequest is the body for POST /api/v1/receipts
+type RecordReceiptRequest struct {
+	Action    string `json:"action"` // issued, delivered, acknowledged, cached or revoked
+	SAID      string `json:"said"`
+	Schema    string `json:"schema,omitempty"`
+	Recipient string `json:"recipient"`
+}
+
+// ReceiptsResponse is the response for GET /api/v1/receipts
+type ReceiptsResponse struct {
+	Receipts    []*anysync.ReceiptPayload `json:"receipts"`
+	Count       int                       `json:"count"`
+	Verified    bool                      `json:"verified"`
+	VerifyError string                    `json:"verifyError,omitempty"`
+}
+
+// Available reports whether this backend holds the admin space, i.e. is run
+// by a steward who can write receipts
+func (h *ReceiptsHandler) Available() bool {
+	return h.spaceManager != nil && h.spaceManager.GetAdminSpaceID() != ""
+}
+
+// Record appends a receipt for the local user, unless one already exists for
+// the same SAID and action. Returns the receipt and whether it was created.
+func (h *ReceiptsHandler) Record(ctx context.Context, action, said, schema, recipient string) (*anysync.ReceiptPayload, bool, error) {
+	if !anysync.IsReceiptAction(action) && !anysync.IsApprovalAction(action) {
+		return nil, false, fmt.Errorf("unknown receipt action: %s", action)
+	}
+	if said == "" || recipient == "" {
+		return nil, false, fmt.Errorf("said and recipient are required")
+	}
+	me := h.userIdentity.GetAID()
+	if me == "" {
+		return nil, false, fmt.Errorf("identity not configured")
+	}
+
+	adminSpaceID := h.spaceManager.GetAdminSpaceID()
+	if adminSpaceID == "" {
+		return nil, false, fmt.Errorf("admin space not available")
+	}
+	client := h.spaceManager.GetClient()
+	if client == nil {
+		return nil, false, fmt.Errorf("any-sync client not available")
+	}
+	keys, err := anysync.LoadSpaceKeySet(client.GetDataDir(), adminSpaceID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load admin space keys: %w", err)
+	}
+
+	ledger := h.spaceManager.ReceiptTreeManager()
+	existing, err := ledger.ReadReceipts(ctx, adminSpaceID)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, r := range existing {
+		if r.SAID == said && r.Action == action {
+			return r, false, nil
+		}
+	}
+
+	receipt, err := ledger.Append(ctx, adminSpaceID, &anysync.ReceiptPayload{
+		Action:    action,
+		SAID:      said,
+		Schema:    schema,
+		Recipient: recipient,
+		Actor:     me,
+	}, keys.SigningKey)
+	metrics.CountCredentialOperation(action, err)
+	if err != nil {
+		return nil, false, err
+	}
+
+	fmt.Printf("[Receipts] %s %s %s for %s (seq %d)\n", me, action, said, recipient, receipt.Seq)
+	credential := func(status string) map[string]string {
+		return map[string]string{"said": said, "schema": schema, "recipient": recipient, "status": status}
+	}
+	switch action {
+	case anysync.ReceiptIssued:
+		h.audit.Record(ctx, audit.ActionCredentialIssue, auditActor(ctx, h.userIdentity), said,
+			nil, credential(anysync.ReceiptIssued))
+	case anysync.ReceiptRevoked:
+		h.audit.Record(ctx, audit.ActionCredentialRevoke, auditActor(ctx, h.userIdentity), said,
+			credential(anysync.ReceiptIssued), credential(anysync.ReceiptRevoked))
+		h.events.Broadcast(SSEEvent{
+			Type: EventCredentialRevoked,
+			Data: map[string]string{
+				"said":      said,
+				"recipient": recipient,
+				"schema":    schema,
+				"revokedBy": me,
+			},
+		})
+	case anysync.ReceiptDelivered, anysync.ReceiptAcknowledged, anysync.ReceiptCached:
+		h.events.Broadcast(SSEEvent{
+			Type: EventCredentialDelivery,
+			Data: map[string]string{
+				"said":      said,
+				"recipient": recipient,
+				"stage":     action,
+			},
+		})
+	}
+	return receipt, true, nil
+}
+
+// DeliveryStatus returns a credential's delivery lifecycle from the ledger,
+// or nil if the ledger has no receipts for it
+func (h *ReceiptsHandler) DeliveryStatus(ctx context.Context, said string) (*anysync.CredentialDelivery, error) {
+	adminSpaceID := h.spaceManager.GetAdminSpaceID()
+	if adminSpaceID == "" {
+		return nil, fmt.Errorf("admin space not available")
+	}
+	receipts, err := h.spaceManager.ReceiptTreeManager().ReadReceipts(ctx, adminSpaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read receipts: %w", err)
+	}
+	return anysync.DeliveryFromReceipts(said, receipts), nil
+}
+
+// handleReceipts routes /api/v1/receipts
+func (h *ReceiptsHandler) handleReceipts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.HandleList(w, r)
+	case http.MethodPost:
+		h.HandleRecord(w, r)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// HandleList handles GET /api/v1/receipts (steward)
+// Query params:
+//   - said: Only receipts for this credential (optional)
+//
+// The whole chain is always verified, even when filtering.
+func (h *ReceiptsHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	adminSpaceID := h.spaceManager.GetAdminSpaceID()
+	if adminSpaceID == "" {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "admin space not available"})
+		return
+	}
+
+	receipts, err := h.spaceManager.ReceiptTreeManager().ReadReceipts(r.Context(), adminSpaceID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read receipts: %v", err),
+		})
+		return
+	}
+
+	resp := ReceiptsResponse{Verified: true}
+	if err := anysync.VerifyReceipts(receipts); err != nil {
+		resp.Verified = false
+		resp.VerifyError = err.Error()
+	}
+
+	said := r.URL.Query().Get("said")
+	resp.Receipts = make([]*anysync.ReceiptPayload, 0, len(receipts))
+	for _, receipt := range receipts {
+		if said == "" || receipt.SAID == said {
+			resp.Receipts = append(resp.Receipts, receipt)
+		}
+	}
+	resp.Count = len(resp.Receipts)
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// HandleRecord handles POST /api/v1/receipts (steward). Clients call this
+// after issuing or revoking a credential in KERIA, after sending its IPEX
+// grant (delivered) and on seeing the recipient's IPEX admit
+// (acknowledged). Recording the same SAID and action twice returns the
+// existing receipt.
+func (h *ReceiptsHandler) HandleRecord(w http.ResponseWriter, r *http.Request) {
+	var req RecordReceiptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+	if !anysync.IsReceiptAction(req.Action) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("action must be one of %s or %s",
+				strings.Join(anysync.DeliveryStages(), ", "), anysync.ReceiptRevoked),
+		})
+		return
+	}
+	if req.SAID == "" || req.Recipient == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "said and recipient are required"})
+		return
+	}
+	if h.userIdentity.GetAID() == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "identity not configured"})
+		return
+	}
+	if h.spaceManager.GetAdminSpaceID() == "" {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "admin space not available"})
+		return
+	}
+
+	receipt, created, err := h.Record(r.Context(), req.Action, req.SAID, req.Schema, req.Recipient)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, receipt)
+}
+
+// RegisterRoutes registers receipt routes on the mux
+func (h *ReceiptsHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/receipts", h.handleReceipts)
+}
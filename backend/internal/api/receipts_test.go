@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/identity"
+)
+
+func TestReceiptRecord_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid json", `{`},
+		{"unknown action", `{"action":"minted","said":"ESAID","recipient":"EBOB"}`},
+		{"missing said", `{"action":"issued","recipient":"EBOB"}`},
+		{"missing recipient", `{"action":"revoked","said":"ESAID"}`},
+		{"no identity", `{"action":"issued","said":"ESAID","recipient":"EBOB"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "receipts_test")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			handler := NewReceiptsHandler(nil, identity.New(tmpDir))
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/receipts", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			handler.handleReceipts(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
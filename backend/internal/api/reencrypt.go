@@ -0,0 +1,209 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/anyproto/any-sync/util/crypto"
+	"github.com/matou-dao/backend/internal/anysync"
+)
+
+// Re-encryption job states
+const (
+	ReencryptRunning   = "running"
+	ReencryptCompleted = "completed"
+	ReencryptFailed    = "failed"
+)
+
+// SpaceReencryptor re-encrypts a space's content under a new read key.
+// Implemented by anysync.SpaceManager.
+type SpaceReencryptor interface {
+	ReencryptSpace(ctx context.Context, spaceID string, opts anysync.ReencryptOptions) (*anysync.ReencryptProgress, error)
+	GetCommunityReadOnlySpaceID() string
+}
+
+// ReencryptRequest is the body for POST /api/v1/spaces/reencrypt
+type ReencryptRequest struct {
+	// SpaceID is the space to re-encrypt; empty means the community
+	// read-only space
+	SpaceID string `json:"spaceId,omitempty"`
+	// Revoke lists peer IDs or account addresses that lose access
+	Revoke []string `json:"revoke,omitempty"`
+}
+
+// ReencryptJob is the state of the latest re-encryption
+type ReencryptJob struct {
+	Status      string                    `json:"status"`
+	Error       string                    `json:"error,omitempty"`
+	TreesFailed int                       `json:"treesFailed"`
+	StartedAt   time.Time                 `json:"startedAt"`
+	CompletedAt *time.Time                `json:"completedAt,omitempty"`
+	Progress    anysync.ReencryptProgress `json:"progress"`
+}
+
+// ReencryptHandler re-protects a space after its content leaked, as a
+// background job whose progress can be polled. One job runs at a time.
+type ReencryptHandler struct {
+	spaces SpaceReencryptor
+	events *EventBroker
+
+	mu     sync.Mutex
+	job    *ReencryptJob
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewReencryptHandler creates a new re-encryption handler
+func NewReencryptHandler(spaces SpaceReencryptor) *ReencryptHandler {
+	return &ReencryptHandler{spaces: spaces}
+}
+
+// SetEvents broadcasts acl:changed when a space has been re-keyed
+func (h *ReencryptHandler) SetEvents(events *EventBroker) {
+	h.events = events
+}
+
+// HandleStart handles POST /api/v1/spaces/reencrypt. It validates the
+// request, starts the job and returns 202 with its initial state.
+func (h *ReencryptHandler) HandleStart(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	var req ReencryptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request body: %v", err),
+		})
+		return
+	}
+	spaceID := req.SpaceID
+	if spaceID == "" {
+		spaceID = h.spaces.GetCommunityReadOnlySpaceID()
+	}
+	if spaceID == "" {
+		writeJSON(w, http.StatusConflict, map[string]string{
+			"error": "community-readonly space not configured",
+		})
+		return
+	}
+	revoke := make([]crypto.PubKey, 0, len(req.Revoke))
+	for _, id := range req.Revoke {
+		key, err := anysync.DecodeACLIdentity(id)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		revoke = append(revoke, key)
+	}
+
+	h.mu.Lock()
+	if h.job != nil && h.job.Status == ReencryptRunning {
+		h.mu.Unlock()
+		writeJSON(w, http.StatusConflict, map[string]string{
+			"error": fmt.Sprintf("re-encryption of space %s is already running", h.job.Progress.SpaceID),
+		})
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	h.job = &ReencryptJob{
+		Status:    ReencryptRunning,
+		StartedAt: time.Now().UTC(),
+		Progress:  anysync.ReencryptProgress{SpaceID: spaceID},
+	}
+	done := make(chan struct{})
+	h.cancel, h.done = cancel, done
+	job := *h.job
+	h.mu.Unlock()
+
+	fmt.Printf("[Reencrypt] Re-encrypting space %s (%d accounts revoked)\n", spaceID, len(revoke))
+	go func() {
+		defer close(done)
+		defer cancel()
+		h.run(ctx, spaceID, revoke)
+	}()
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// run performs the job and records its outcome
+func (h *ReencryptHandler) run(ctx context.Context, spaceID string, revoke []crypto.PubKey) {
+	progress, err := h.spaces.ReencryptSpace(ctx, spaceID, anysync.ReencryptOptions{
+		Revoke: revoke,
+		Progress: func(p anysync.ReencryptProgress) {
+			h.mu.Lock()
+			h.job.Progress = p
+			h.mu.Unlock()
+		},
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := time.Now().UTC()
+	h.job.CompletedAt = &now
+	if progress != nil {
+		h.job.Progress = *progress
+	}
+	for _, tree := range h.job.Progress.Trees {
+		if tree.Error != "" {
+			h.job.TreesFailed++
+		}
+	}
+	if err != nil {
+		h.job.Status = ReencryptFailed
+		h.job.Error = err.Error()
+		fmt.Printf("[Reencrypt] Re-encryption of space %s failed: %v\n", spaceID, err)
+		return
+	}
+	h.job.Status = ReencryptCompleted
+	fmt.Printf("[Reencrypt] Re-encrypted space %s: %d trees, %d changes, %d failed\n",
+		spaceID, h.job.Progress.TreesDone, h.job.Progress.ChangesCopied, h.job.TreesFailed)
+	if h.events != nil {
+		h.events.Broadcast(aclChangedEvent(spaceID, ACLRekeyed, ""))
+	}
+}
+
+// HandleStatus handles GET /api/v1/spaces/reencrypt/status
+func (h *ReencryptHandler) HandleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.job == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			"error": "no re-encryption has run",
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, h.job)
+}
+
+// Shutdown cancels a running job and waits for it to stop
+func (h *ReencryptHandler) Shutdown() {
+	h.mu.Lock()
+	cancel, done := h.cancel, h.done
+	h.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// RegisterRoutes registers re-encryption routes on the mux
+func (h *ReencryptHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/spaces/reencrypt", CORSHandler(h.HandleStart))
+	mux.HandleFunc("/api/v1/spaces/reencrypt/status", CORSHandler(h.HandleStatus))
+}
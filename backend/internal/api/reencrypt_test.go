@@ -0,0 +1,133 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/util/crypto"
+	"github.com/matou-dao/backend/internal/anysync"
+)
+
+type fakeReencryptor struct {
+	readOnlySpaceID string
+	release         chan struct{}
+	err             error
+	spaceID         string
+	revoked         int
+}
+
+func (f *fakeReencryptor) GetCommunityReadOnlySpaceID() string { return f.readOnlySpaceID }
+
+func (f *fakeReencryptor) ReencryptSpace(ctx context.Context, spaceID string, opts anysync.ReencryptOptions) (*anysync.ReencryptProgress, error) {
+	f.spaceID = spaceID
+	f.revoked = len(opts.Revoke)
+	progress := &anysync.ReencryptProgress{SpaceID: spaceID, Phase: anysync.ReencryptCopyingTrees, TreesTotal: 2}
+	opts.Progress(*progress)
+	select {
+	case <-f.release:
+	case <-ctx.Done():
+		return progress, ctx.Err()
+	}
+	if f.err != nil {
+		return progress, f.err
+	}
+	progress.Phase = anysync.ReencryptDone
+	progress.TreesDone = 2
+	progress.Trees = []anysync.TreeReencryption{{OldID: "t1", NewID: "t1b", Changes: 3}, {OldID: "t2", Error: "loading tree: gone"}}
+	return progress, nil
+}
+
+func TestReencryptHandler(t *testing.T) {
+	spaces := &fakeReencryptor{readOnlySpaceID: "space-ro", release: make(chan struct{})}
+	h := NewReencryptHandler(spaces)
+	broker := NewEventBroker()
+	events := broker.Subscribe()
+	defer broker.Unsubscribe(events)
+	h.SetEvents(broker)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	do := func(method, path, body string) (*httptest.ResponseRecorder, ReencryptJob) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+		var job ReencryptJob
+		json.Unmarshal(w.Body.Bytes(), &job)
+		return w, job
+	}
+	status := func() ReencryptJob {
+		_, job := do(http.MethodGet, "/api/v1/spaces/reencrypt/status", "")
+		return job
+	}
+
+	if w, _ := do(http.MethodGet, "/api/v1/spaces/reencrypt/status", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 before any job, got %d", w.Code)
+	}
+	if w, _ := do(http.MethodPost, "/api/v1/spaces/reencrypt", `{"revoke":["not-a-peer"]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected invalid identity rejected, got %d", w.Code)
+	}
+
+	_, pub, _ := crypto.GenerateRandomEd25519KeyPair()
+	w, job := do(http.MethodPost, "/api/v1/spaces/reencrypt", `{"revoke":["`+pub.PeerId()+`"]}`)
+	if w.Code != http.StatusAccepted || job.Status != ReencryptRunning || job.Progress.SpaceID != "space-ro" {
+		t.Fatalf("expected job started on the read-only space, got %d: %s", w.Code, w.Body.String())
+	}
+	if w, _ := do(http.MethodPost, "/api/v1/spaces/reencrypt", ""); w.Code != http.StatusConflict {
+		t.Errorf("expected a second job refused while one runs, got %d", w.Code)
+	}
+	deadline := time.Now().Add(time.Second)
+	for status().Progress.Phase != anysync.ReencryptCopyingTrees && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if job := status(); job.Progress.TreesTotal != 2 {
+		t.Errorf("expected progress reported while running, got %+v", job.Progress)
+	}
+
+	close(spaces.release)
+	for status().Status == ReencryptRunning && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	job = status()
+	if job.Status != ReencryptCompleted || job.TreesFailed != 1 || job.CompletedAt == nil {
+		t.Errorf("expected completed job with one failed tree, got %+v", job)
+	}
+	if spaces.spaceID != "space-ro" || spaces.revoked != 1 {
+		t.Errorf("expected read-only space re-encrypted with one revocation, got %s/%d", spaces.spaceID, spaces.revoked)
+	}
+	select {
+	case event := <-events:
+		if event.Type != EventACLChanged {
+			t.Errorf("expected acl:changed, got %s", event.Type)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected acl:changed broadcast")
+	}
+
+	spaces.err = errors.New("rotating read key: denied")
+	spaces.release = make(chan struct{})
+	close(spaces.release)
+	do(http.MethodPost, "/api/v1/spaces/reencrypt", `{"spaceId":"space-other"}`)
+	h.Shutdown()
+	if job := status(); job.Status != ReencryptFailed || job.Error == "" || job.Progress.SpaceID != "space-other" {
+		t.Errorf("expected failed job recorded, got %+v", job)
+	}
+}
+
+func TestReencryptHandlerNoReadOnlySpace(t *testing.T) {
+	h := NewReencryptHandler(&fakeReencryptor{})
+	w := httptest.NewRecorder()
+	h.HandleStart(w, httptest.NewRequest(http.MethodPost, "/api/v1/spaces/reencrypt", nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected 409 without a read-only space, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	h.HandleStart(w, httptest.NewRequest(http.MethodGet, "/api/v1/spaces/reencrypt", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+)
+
+// Leadership reports which replica of a cluster leads
+type Leadership interface {
+	IsLeader() bool
+	// LeaderURL is the leader's node URL, or "" while there is none
+	LeaderURL() string
+	// NodeURL is this replica's node URL
+	NodeURL() string
+}
+
+// forwardedByHeader marks a request a follower forwarded to the leader, so
+// a replica that has just lost leadership doesn't forward it again
+const forwardedByHeader = "X-Matou-Forwarded-By"
+
+// replicaRoleHeader tells clients whether the leader or a follower answered
+const replicaRoleHeader = "X-Matou-Replica"
+
+// leaderRoutes go to the leader for every method: their reads change
+// state (listing invites sweeps them) or only the leader has the state
+// (event streams, locks, admin toggles)
+var leaderRoutes = []string{
+	"/api/v1/events",
+	"/api/v1/invites",
+	"/api/v1/invites/",
+	"/api/v1/admin/",
+}
+
+// ReplicaRouter lets several API replicas share one org. Followers serve
+// reads, such as trust and member queries, from their own caches and
+// forward writes to the leader, so only one replica ever writes to the
+// spaces.
+type ReplicaRouter struct {
+	leadership Leadership
+
+	mu      sync.Mutex
+	target  string
+	forward *httputil.ReverseProxy
+}
+
+// NewReplicaRouter creates a router that follows leadership
+func NewReplicaRouter(leadership Leadership) *ReplicaRouter {
+	return &ReplicaRouter{leadership: leadership}
+}
+
+// leaderWrite reports whether a request must be served by the leader
+func leaderWrite(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return matchesRoute(r.URL.Path, leaderRoutes)
+	}
+	return true
+}
+
+// proxy returns a reverse proxy to the leader, rebuilt when it changes
+func (rr *ReplicaRouter) proxy(leaderURL string) (*httputil.ReverseProxy, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	if rr.forward != nil && rr.target == leaderURL {
+		return rr.forward, nil
+	}
+	target, err := url.Parse(leaderURL)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return nil, fmt.Errorf("invalid leader URL %q", leaderURL)
+	}
+	nodeURL := rr.leadership.NodeURL()
+	rr.target = leaderURL
+	rr.forward = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(target)
+			r.SetXForwarded()
+			r.Out.Header.Set(forwardedByHeader, nodeURL)
+		},
+		// Event streams must reach the client as they are written
+		FlushInterval: -1,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			w.Header().Set("Retry-After", "1")
+			writeJSON(w, http.StatusBadGateway, map[string]string{
+				"error": fmt.Sprintf("leader unavailable: %v", err),
+			})
+		},
+	}
+	return rr.forward, nil
+}
+
+// ReplicaMiddleware forwards writes to the leader when this replica is a
+// follower. The leader authenticates forwarded requests itself. While no
+// replica leads, writes get 503 with Retry-After.
+func ReplicaMiddleware(rr *ReplicaRouter, next http.Handler) http.Handler {
+	if rr == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rr.leadership.IsLeader() {
+			w.Header().Set(replicaRoleHeader, "leader")
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !leaderWrite(r) {
+			w.Header().Set(replicaRoleHeader, "follower")
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		leaderURL := rr.leadership.LeaderURL()
+		if leaderURL == "" || r.Header.Get(forwardedByHeader) != "" {
+			w.Header().Set("Retry-After", "1")
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+				"error": "no leader available for writes",
+			})
+			return
+		}
+		proxy, err := rr.proxy(leaderURL)
+		if err != nil {
+			writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	})
+}
+
+// ClusterStatus is the response for GET /api/v1/cluster
+type ClusterStatus struct {
+	Enabled   bool   `json:"enabled"`
+	Role      string `json:"role"` // leader, follower or standalone
+	NodeURL   string `json:"nodeUrl,omitempty"`
+	LeaderURL string `json:"leaderUrl,omitempty"`
+}
+
+// Status reports this replica's role. A nil router is a standalone backend.
+func (rr *ReplicaRouter) Status() ClusterStatus {
+	if rr == nil {
+		return ClusterStatus{Role: "standalone"}
+	}
+	status := ClusterStatus{
+		Enabled:   true,
+		Role:      "follower",
+		NodeURL:   rr.leadership.NodeURL(),
+		LeaderURL: rr.leadership.LeaderURL(),
+	}
+	if rr.leadership.IsLeader() {
+		status.Role = "leader"
+	}
+	return status
+}
+
+// HandleStatus handles GET /api/v1/cluster
+func (rr *ReplicaRouter) HandleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	writeJSON(w, http.StatusOK, rr.Status())
+}
+
+// RegisterRoutes registers the cluster status route. It is served on every
+// replica, so it is safe to call on a nil router.
+func (rr *ReplicaRouter) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/cluster", rr.HandleStatus)
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// staticLeadership is a fixed Leadership for tests
+type staticLeadership struct {
+	leader    bool
+	leaderURL string
+}
+
+func (s *staticLeadership) IsLeader() bool    { return s.leader }
+func (s *staticLeadership) LeaderURL() string { return s.leaderURL }
+func (s *staticLeadership) NodeURL() string   { return "http://follower:8080" }
+
+func TestReplicaMiddleware(t *testing.T) {
+	var forwardedBy string
+	leader := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwardedBy = r.Header.Get(forwardedByHeader)
+		w.Header().Set("X-Served-By", "leader")
+	}))
+	defer leader.Close()
+
+	leadership := &staticLeadership{leaderURL: leader.URL}
+	handler := ReplicaMiddleware(NewReplicaRouter(leadership), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Served-By", "local")
+	}))
+
+	tests := []struct {
+		method, path string
+		want         string
+	}{
+		{http.MethodGet, "/api/v1/trust/summary", "local"},
+		{http.MethodOptions, "/api/v1/credentials", "local"},
+		{http.MethodPost, "/api/v1/credentials", "leader"},
+		{http.MethodDelete, "/api/v1/spaces/abc", "leader"},
+		{http.MethodGet, "/api/v1/invites", "leader"},
+		{http.MethodGet, "/api/v1/events", "leader"},
+		{http.MethodGet, "/api/v1/admin/locks", "leader"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if got := w.Header().Get("X-Served-By"); got != tt.want {
+			t.Errorf("%s %s: expected %s, got %q (%d)", tt.method, tt.path, tt.want, got, w.Code)
+		}
+	}
+	if forwardedBy != "http://follower:8080" {
+		t.Errorf("expected forwarded requests to name the follower, got %q", forwardedBy)
+	}
+
+	// A request already forwarded once isn't forwarded again
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/credentials", nil)
+	req.Header.Set(forwardedByHeader, "http://other:8080")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 for a re-forwarded request, got %d", w.Code)
+	}
+
+	// Without a leader writes wait, reads don't
+	leadership.leaderURL = ""
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/credentials", nil))
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") == "" {
+		t.Errorf("expected 503 with Retry-After without a leader, got %d", w.Code)
+	}
+
+	// The leader serves everything itself
+	leadership.leader = true
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/credentials", nil))
+	if w.Header().Get("X-Served-By") != "local" || w.Header().Get(replicaRoleHeader) != "leader" {
+		t.Errorf("expected the leader to serve writes, got %v", w.Header())
+	}
+}
+
+func TestReplicaRouter_Status(t *testing.T) {
+	var standalone *ReplicaRouter
+	if status := standalone.Status(); status.Enabled || status.Role != "standalone" {
+		t.Errorf("unexpected standalone status %+v", status)
+	}
+
+	status := NewReplicaRouter(&staticLeadership{leaderURL: "http://leader:8080"}).Status()
+	if !status.Enabled || status.Role != "follower" || status.LeaderURL != "http://leader:8080" {
+		t.Errorf("unexpected follower status %+v", status)
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/matou-dao/backend/internal/keri"
+)
+
+// KeyStateCache caches AIDs' key state. Implemented by
+// keri.KeyStateResolver.
+type KeyStateCache interface {
+	Forget(aid string)
+}
+
+// RotationHandler records key rotations of the org and admin AIDs. The
+// backend doesn't hold their keys: signify-ts rotates with
+// identifiers().rotate(alias), then calls this so the backend reads the
+// new establishment event from KERIA and records when the keys changed.
+type RotationHandler struct {
+	keriClient *keri.Client
+	orgConfig  *OrgConfigHandler
+	keyStates  KeyStateCache
+}
+
+// NewRotationHandler creates a new rotation handler
+func NewRotationHandler(keriClient *keri.Client, orgConfig *OrgConfigHandler) *RotationHandler {
+	return &RotationHandler{
+		keriClient: keriClient,
+		orgConfig:  orgConfig,
+	}
+}
+
+// SetKeyStateCache attaches the key state cache used to verify request
+// signatures, so a rotation takes effect there at once
+func (h *RotationHandler) SetKeyStateCache(cache KeyStateCache) {
+	h.keyStates = cache
+}
+
+// RotateRequest is the body for POST /api/v1/keri/rotate
+type RotateRequest struct {
+	// AID is the AID that rotated; empty means the org AID
+	AID string `json:"aid"`
+}
+
+// HandleRotate handles POST /api/v1/keri/rotate
+func (h *RotationHandler) HandleRotate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	var req RotateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request body: %v", err),
+		})
+		return
+	}
+	orgAID := h.keriClient.GetOrgAID()
+	if orgAID == "" {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "org not configured"})
+		return
+	}
+	if req.AID == "" {
+		req.AID = orgAID
+	}
+	if req.AID != orgAID && !h.orgConfig.IsAdmin(req.AID) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "aid must be the org AID or an admin AID",
+		})
+		return
+	}
+
+	rotation, err := h.keriClient.RecordKeyState(r.Context(), req.AID)
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	}
+	if h.keyStates != nil {
+		h.keyStates.Forget(req.AID)
+	}
+	if rotation.Recorded {
+		fmt.Printf("[Rotation] Recorded keys of %s at establishment event %d\n", req.AID, rotation.Current.Sequence)
+	}
+	writeJSON(w, http.StatusOK, rotation)
+}
+
+// RegisterRoutes registers rotation routes on the mux
+func (h *RotationHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/keri/rotate", CORSHandler(h.HandleRotate))
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/keri"
+)
+
+type forgetRecorder struct{ forgot []string }
+
+func (f *forgetRecorder) Forget(aid string) { f.forgot = append(f.forgot, aid) }
+
+func TestRotationHandler(t *testing.T) {
+	var rotated atomic.Bool
+	keria := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oobi/EORG":
+			w.Write([]byte(`{"t":"icp","d":"EORG","i":"EORG","s":"0","k":["DKEY0"]}`))
+			if rotated.Load() {
+				w.Write([]byte(`{"t":"rot","d":"EROT","i":"EORG","s":"1","k":["DKEY1"]}`))
+			}
+		case "/oobi/EADMIN":
+			w.Write([]byte(`{"t":"icp","d":"EADMIN","i":"EADMIN","s":"0","k":["DADMIN"]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer keria.Close()
+
+	orgConfig := NewOrgConfigHandler(t.TempDir(), nil)
+	orgConfig.cache = &OrgConfigData{
+		Organization: OrgInfo{AID: "EORG", Name: "Org"},
+		Admins:       []AdminData{{AID: "EADMIN"}},
+	}
+	client, _ := keri.NewClient(&keri.Config{OrgAID: "EORG", CESRURL: keria.URL})
+	client.SetKeyHistory(keri.NewKeyHistory(""))
+	cache := &forgetRecorder{}
+	h := NewRotationHandler(client, orgConfig)
+	h.SetKeyStateCache(cache)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	rotate := func(body string) (*httptest.ResponseRecorder, keri.KeyRotation) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/keri/rotate", bytes.NewBufferString(body)))
+		var resp keri.KeyRotation
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp
+	}
+
+	w, resp := rotate("")
+	if w.Code != http.StatusOK || !resp.Recorded || resp.Current.Sequence != 0 {
+		t.Fatalf("expected org inception keys recorded, got %d: %s", w.Code, w.Body.String())
+	}
+	rotated.Store(true)
+	w, resp = rotate(`{"aid":"EORG"}`)
+	if w.Code != http.StatusOK || !resp.Recorded || resp.Previous == nil || resp.Current.Keys[0] != "DKEY1" {
+		t.Fatalf("expected rotation recorded, got %d: %s", w.Code, w.Body.String())
+	}
+	if w, resp := rotate(`{"aid":"EORG"}`); w.Code != http.StatusOK || resp.Recorded {
+		t.Errorf("expected repeat call not to record, got %d: %s", w.Code, w.Body.String())
+	}
+	if w, _ := rotate(`{"aid":"EADMIN"}`); w.Code != http.StatusOK {
+		t.Errorf("expected admin AID accepted, got %d: %s", w.Code, w.Body.String())
+	}
+	if w, _ := rotate(`{"aid":"ESTRANGER"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected other AIDs rejected, got %d", w.Code)
+	}
+	if len(cache.forgot) != 4 || cache.forgot[0] != "EORG" {
+		t.Errorf("expected cached key state dropped on each call, got %v", cache.forgot)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/keri/rotate", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for GET, got %d", w.Code)
+	}
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/schemas"
+)
+
+// SchemaObjectType is the object type of registered credential schemas
+const SchemaObjectType = "Schema"
+
+// SchemasHandler manages the community's credential schema registry.
+// Schemas are stored as Schema objects in the community read-only space, so
+// only admins (who hold its write keys) can register or change them.
+type SchemasHandler struct {
+	spaceManager *anysync.SpaceManager
+	userIdentity *identity.UserIdentity
+}
+
+// NewSchemasHandler creates a new schemas handler
+func NewSchemasHandler(spaceManager *anysync.SpaceManager, userIdentity *identity.UserIdentity) *SchemasHandler {
+	return &SchemasHandler{
+		spaceManager: spaceManager,
+		userIdentity: userIdentity,
+	}
+}
+
+// PutSchemaRequest is the body for PUT /api/v1/schemas/{said}
+type PutSchemaRequest struct {
+	Title       string          `json:"title,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Version     string          `json:"version"`
+	Body        json.RawMessage `json:"body"`
+}
+
+// SchemasResponse is the response for GET /api/v1/schemas
+type SchemasResponse struct {
+	Schemas []*schemas.Schema `json:"schemas"`
+	Count   int               `json:"count"`
+}
+
+// Schema returns the registered schema for said, or nil if none is registered
+func (h *SchemasHandler) Schema(ctx context.Context, said string) *schemas.Schema {
+	if h == nil || h.spaceManager == nil || said == "" {
+		return nil
+	}
+	roSpaceID := h.spaceManager.GetCommunityReadOnlySpaceID()
+	if roSpaceID == "" {
+		return nil
+	}
+	obj, err := h.spaceManager.ObjectTreeManager().ReadLatestByID(ctx, roSpaceID, schemaObjectID(said))
+	if err != nil {
+		return nil
+	}
+	var schema schemas.Schema
+	if err := json.Unmarshal(obj.Data, &schema); err != nil {
+		return nil
+	}
+	return &schema
+}
+
+// ValidateCredentialData checks credential data against its registered
+// schema. Credentials whose schema isn't registered only get the built-in
+// checks, so this returns nil for them.
+func (h *SchemasHandler) ValidateCredentialData(ctx context.Context, said string, data json.RawMessage) error {
+	schema := h.Schema(ctx, said)
+	if schema == nil {
+		return nil
+	}
+	if len(data) == 0 {
+		data = json.RawMessage(`{}`)
+	}
+	if err := schema.ValidateData(data); err != nil {
+		return fmt.Errorf("does not match schema %s v%s: %w", schema.SAID, schema.Version, err)
+	}
+	return nil
+}
+
+// HandleList handles GET /api/v1/schemas
+func (h *SchemasHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	list := make([]*schemas.Schema, 0)
+	if h.spaceManager != nil {
+		if roSpaceID := h.spaceManager.GetCommunityReadOnlySpaceID(); roSpaceID != "" {
+			objects, err := readLatestObjects(r.Context(), h.spaceManager, roSpaceID, SchemaObjectType)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			for _, obj := range objects {
+				var schema schemas.Schema
+				if err := json.Unmarshal(obj.Data, &schema); err != nil {
+					continue
+				}
+				list = append(list, &schema)
+			}
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].SAID < list[j].SAID })
+
+	writeJSON(w, http.StatusOK, SchemasResponse{Schemas: list, Count: len(list)})
+}
+
+// HandleSchema handles /api/v1/schemas/{said}
+//   - GET: Return a registered schema
+//   - PUT: Register or update a schema (admin)
+func (h *SchemasHandler) HandleSchema(w http.ResponseWriter, r *http.Request) {
+	said := strings.TrimPrefix(r.URL.Path, "/api/v1/schemas/")
+	if said == "" || strings.Contains(said, "/") {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "schema SAID required"})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		schema := h.Schema(r.Context(), said)
+		if schema == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("schema not registered: %s", said)})
+			return
+		}
+		writeJSON(w, http.StatusOK, schema)
+	case http.MethodPut:
+		h.handlePut(w, r, said)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// handlePut registers or updates a schema. Changing the body of an existing
+// schema requires a new version so credentials can say which rules they met.
+func (h *SchemasHandler) handlePut(w http.ResponseWriter, r *http.Request, said string) {
+	var req PutSchemaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+
+	schema := &schemas.Schema{
+		SAID:        said,
+		Title:       req.Title,
+		Description: req.Description,
+		Version:     req.Version,
+		Body:        req.Body,
+		UpdatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}
+	if h.userIdentity != nil {
+		schema.UpdatedBy = h.userIdentity.GetAID()
+	}
+	if err := schema.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if h.spaceManager == nil || h.spaceManager.GetCommunityReadOnlySpaceID() == "" {
+		writeJSON(w, http.StatusConflict, map[string]string{
+			"error": "community-readonly space not configured",
+		})
+		return
+	}
+	roSpaceID := h.spaceManager.GetCommunityReadOnlySpaceID()
+
+	if existing := h.Schema(r.Context(), said); existing != nil &&
+		existing.Version == schema.Version && !sameJSON(existing.Body, schema.Body) {
+		writeJSON(w, http.StatusConflict, map[string]string{
+			"error": fmt.Sprintf("schema %s v%s already registered with a different body; bump the version", said, schema.Version),
+		})
+		return
+	}
+
+	if _, err := writeObject(r.Context(), h.spaceManager, roSpaceID, schemaObjectID(said), SchemaObjectType, schema); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	fmt.Printf("[Schemas] Registered %s v%s\n", said, schema.Version)
+	writeJSON(w, http.StatusOK, schema)
+}
+
+// schemaObjectID returns the object ID a schema is stored under
+func schemaObjectID(said string) string {
+	return "Schema-" + said
+}
+
+// sameJSON compares two JSON documents ignoring formatting and key order
+func sameJSON(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return bytes.Equal(a, b)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// RegisterRoutes registers schema routes on the mux
+func (h *SchemasHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/schemas", h.HandleList)
+	mux.HandleFunc("/api/v1/schemas/", h.HandleSchema)
+}
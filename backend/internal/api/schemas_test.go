@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSchemas_ListWithoutRegistry(t *testing.T) {
+	handler := NewSchemasHandler(nil, nil)
+
+	w := httptest.NewRecorder()
+	handler.HandleList(w, httptest.NewRequest(http.MethodGet, "/api/v1/schemas", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp SchemasResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Count != 0 || resp.Schemas == nil {
+		t.Errorf("expected an empty schema list, got %+v", resp)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleSchema(w, httptest.NewRequest(http.MethodGet, "/api/v1/schemas/EUnknownSchema", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unregistered schema, got %d", w.Code)
+	}
+}
+
+func TestSchemas_PutValidation(t *testing.T) {
+	handler := NewSchemasHandler(nil, nil)
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"missing version", "/api/v1/schemas/ESchema", `{"body":{"type":"object"}}`, http.StatusBadRequest},
+		{"missing body", "/api/v1/schemas/ESchema", `{"version":"1.0.0"}`, http.StatusBadRequest},
+		{"unsupported keyword", "/api/v1/schemas/ESchema", `{"version":"1.0.0","body":{"$ref":"#/x"}}`, http.StatusBadRequest},
+		{"nested path", "/api/v1/schemas/ESchema/extra", `{"version":"1.0.0","body":{}}`, http.StatusNotFound},
+		{"no readonly space", "/api/v1/schemas/ESchema", `{"version":"1.0.0","body":{"type":"object"}}`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleSchema(w, httptest.NewRequest(http.MethodPut, tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSchemas_UnregisteredSchemaSkipsValidation(t *testing.T) {
+	var handler *SchemasHandler
+
+	ctx := httptest.NewRequest(http.MethodGet, "/", nil).Context()
+	if err := handler.ValidateCredentialData(ctx, "EMatouMembershipSchemaV1", json.RawMessage(`{"anything":true}`)); err != nil {
+		t.Errorf("expected no error without a registry, got %v", err)
+	}
+}
+
+func TestCredentialDataJSON(t *testing.T) {
+	data := credentialDataJSON(json.RawMessage(`{"said":"ESAID","data":{"role":"Member","category":"skill"}}`))
+	if string(data) != `{"role":"Member","category":"skill"}` {
+		t.Errorf("unexpected data: %s", data)
+	}
+	if credentialDataJSON(json.RawMessage(`not json`)) != nil {
+		t.Error("expected nil for malformed credential")
+	}
+}
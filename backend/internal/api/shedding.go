@@ -0,0 +1,253 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/matou-dao/backend/internal/metrics"
+)
+
+// maxCachedResponse bounds a response kept for serving while shedding
+const maxCachedResponse = 4 << 20
+
+// maxCachedResponses bounds how many distinct requests are kept
+const maxCachedResponses = 256
+
+// DefaultShedRoutes are the routes that build the full trust graph. Entries
+// ending in "/" match by prefix. Only GET requests are shed, so GraphQL
+// queries sent by POST are bounded by the route limits alone.
+var DefaultShedRoutes = []string{
+	"/api/v1/trust/graph",
+	"/api/v1/trust/graph/export",
+	"/api/v1/trust/snapshot",
+	"/api/v1/trust/score/",
+	"/api/v1/trust/scores",
+	"/api/v1/trust/path",
+	"/api/v1/trust/anomalies",
+	"/api/v1/trust/summary",
+	"/api/v1/public/stats",
+	"/api/v1/graphql",
+}
+
+// LoadShedder keeps expensive requests from starving the rest of the API.
+// Requests to shed routes run only while a slot in the concurrency budget
+// is free; the rest are turned away at once, with the last good response
+// for the same request if it is recent enough, or 503. Other routes are
+// never shed.
+type LoadShedder struct {
+	slots    chan struct{}
+	staleFor time.Duration
+	routes   []string
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]*cachedResponse
+
+	shed  atomic.Int64
+	stale atomic.Int64
+}
+
+type cachedResponse struct {
+	status      int
+	contentType string
+	body        []byte
+	storedAt    time.Time
+}
+
+// LoadStatus reports the shedder's budget and what it has turned away
+type LoadStatus struct {
+	Budget      int   `json:"budget"`
+	InFlight    int   `json:"inFlight"`
+	Saturated   bool  `json:"saturated"`
+	Shed        int64 `json:"shed"`        // Requests turned away since startup
+	ServedStale int64 `json:"servedStale"` // Of those, answered from cache
+}
+
+// NewLoadShedder creates a shedder that runs at most maxConcurrent shed
+// requests at once and serves cached responses up to staleFor old
+func NewLoadShedder(maxConcurrent int, staleFor time.Duration) *LoadShedder {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	return &LoadShedder{
+		slots:    make(chan struct{}, maxConcurrent),
+		staleFor: staleFor,
+		now:      time.Now,
+		cache:    make(map[string]*cachedResponse),
+	}
+}
+
+// Shed adds routes to the shed routes
+func (s *LoadShedder) Shed(routes ...string) {
+	s.routes = append(s.routes, routes...)
+}
+
+// routeFor returns the shed route a path matches, or empty
+func (s *LoadShedder) routeFor(path string) string {
+	for _, route := range s.routes {
+		if matchesRoute(path, []string{route}) {
+			return route
+		}
+	}
+	return ""
+}
+
+// TryAcquire takes a slot in the budget without waiting. The caller must
+// call release once done if ok is true.
+func (s *LoadShedder) TryAcquire() (release func(), ok bool) {
+	select {
+	case s.slots <- struct{}{}:
+		return func() { <-s.slots }, true
+	default:
+		return nil, false
+	}
+}
+
+// Status returns the budget, requests in flight and shed counts
+func (s *LoadShedder) Status() *LoadStatus {
+	inFlight := len(s.slots)
+	return &LoadStatus{
+		Budget:      cap(s.slots),
+		InFlight:    inFlight,
+		Saturated:   inFlight >= cap(s.slots),
+		Shed:        s.shed.Load(),
+		ServedStale: s.stale.Load(),
+	}
+}
+
+// cacheKey identifies a request's response: the org it is for and its
+// path and query
+func cacheKey(r *http.Request) string {
+	key := r.URL.RequestURI()
+	if tenant := TenantFromContext(r.Context()); tenant != nil {
+		key = tenant.AID + " " + key
+	}
+	return key
+}
+
+// cached returns the response stored for key if it is recent enough
+func (s *LoadShedder) cached(key string) (*cachedResponse, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.cache[key]
+	if c == nil {
+		return nil, 0
+	}
+	age := s.now().Sub(c.storedAt)
+	if age > s.staleFor {
+		return nil, 0
+	}
+	return c, age
+}
+
+// store keeps a response, making room by dropping the oldest if full
+func (s *LoadShedder) store(key string, c *cachedResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.cache[key]; !ok && len(s.cache) >= maxCachedResponses {
+		oldest := ""
+		for k, v := range s.cache {
+			if oldest == "" || v.storedAt.Before(s.cache[oldest].storedAt) {
+				oldest = k
+			}
+		}
+		delete(s.cache, oldest)
+	}
+	s.cache[key] = c
+}
+
+// LoadSheddingMiddleware sheds GET requests to the shedder's routes that
+// arrive while its budget is used up. Responses from shed routes are
+// cached so they can be served in place of a 503. A nil shedder sheds
+// nothing.
+func LoadSheddingMiddleware(s *LoadShedder, next http.Handler) http.Handler {
+	if s == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+		route := s.routeFor(r.URL.Path)
+		if route == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := cacheKey(r)
+		release, ok := s.TryAcquire()
+		if !ok {
+			s.shed.Add(1)
+			if c, age := s.cached(key); c != nil {
+				s.stale.Add(1)
+				metrics.CountShedRequest(route, "stale")
+				w.Header().Set("Content-Type", c.contentType)
+				w.Header().Set("Age", strconv.Itoa(int(age.Seconds())))
+				w.Header().Set("X-Load-Shed", "stale")
+				w.WriteHeader(c.status)
+				w.Write(c.body)
+				return
+			}
+			metrics.CountShedRequest(route, "rejected")
+			w.Header().Set("Retry-After", "1")
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+				"error": "server busy, try again shortly",
+			})
+			return
+		}
+		defer release()
+
+		rec := &cachingResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		if rec.status == http.StatusOK && !rec.overflow {
+			s.store(key, &cachedResponse{
+				status:      rec.status,
+				contentType: w.Header().Get("Content-Type"),
+				body:        rec.body.Bytes(),
+				storedAt:    s.now(),
+			})
+		}
+	})
+}
+
+// cachingResponseWriter keeps a copy of the response body, up to
+// maxCachedResponse
+type cachingResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+	overflow    bool
+}
+
+func (w *cachingResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *cachingResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	if !w.overflow {
+		if w.body.Len()+n > maxCachedResponse {
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b[:n])
+		}
+	}
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *cachingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
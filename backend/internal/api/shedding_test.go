@@ -0,0 +1,77 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLoadSheddingMiddleware(t *testing.T) {
+	shedder := NewLoadShedder(1, time.Minute)
+	shedder.Shed(DefaultShedRoutes...)
+	now := time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)
+	shedder.now = func() time.Time { return now }
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	builds := 0
+	handler := LoadSheddingMiddleware(shedder, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("slow") == "true" {
+			close(started)
+			<-unblock
+		}
+		builds++
+		writeJSON(w, http.StatusOK, map[string]int{"build": builds})
+	}))
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	// Cache a summary while the budget is free
+	if w := get("/api/v1/trust/summary"); w.Code != http.StatusOK || w.Header().Get("X-Load-Shed") != "" {
+		t.Fatalf("expected summary served, got %d", w.Code)
+	}
+
+	// Hold the only slot with a slow graph build
+	done := make(chan struct{})
+	go func() {
+		get("/api/v1/trust/graph?slow=true")
+		close(done)
+	}()
+	<-started
+	if status := shedder.Status(); !status.Saturated || status.InFlight != 1 {
+		t.Errorf("expected budget used up, got %+v", status)
+	}
+
+	now = now.Add(30 * time.Second)
+	w := get("/api/v1/trust/summary")
+	if w.Code != http.StatusOK || w.Header().Get("X-Load-Shed") != "stale" || w.Header().Get("Age") != "30" {
+		t.Errorf("expected cached summary, got %d %v", w.Code, w.Header())
+	}
+	if w.Body.String() != `{"build":1}`+"\n" {
+		t.Errorf("expected first build's body, got %s", w.Body.String())
+	}
+	if w := get("/api/v1/trust/scores"); w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") == "" {
+		t.Errorf("expected 503 without a cached response, got %d", w.Code)
+	}
+	if w := get("/api/v1/org"); w.Code != http.StatusOK {
+		t.Errorf("expected cheap route served, got %d", w.Code)
+	}
+
+	now = now.Add(time.Minute)
+	if w := get("/api/v1/trust/summary"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected cache too old to serve, got %d", w.Code)
+	}
+
+	close(unblock)
+	<-done
+	if status := shedder.Status(); status.Saturated || status.Shed != 3 || status.ServedStale != 1 {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if w := get("/api/v1/trust/scores"); w.Code != http.StatusOK {
+		t.Errorf("expected scores served once the slot is free, got %d", w.Code)
+	}
+}
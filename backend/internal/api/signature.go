@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/matou-dao/backend/internal/keri"
+)
+
+// SignedRoutes are the routes whose requests act as an AID. With signature
+// verification on, their write requests must be signed by that AID.
+var SignedRoutes = []string{
+	"/api/v1/identity/set",
+	"/api/v1/sync/credentials",
+	"/api/v1/sync/kel",
+	"/api/v1/endorsements/request",
+	"/api/v1/endorsements/requests/",
+	"/api/v1/credentials/approvals",
+	"/api/v1/credentials/approvals/",
+}
+
+// KeyStateSource resolves an AID's current signing keys.
+// Implemented by keri.KeyStateResolver.
+type KeyStateSource interface {
+	KeyState(ctx context.Context, aid string) (*keri.KeyState, error)
+}
+
+// SignatureVerifier checks signify-style HTTP signatures against the
+// signing AID's current keys
+type SignatureVerifier struct {
+	keys    KeyStateSource
+	maxSkew time.Duration
+	routes  []string
+	now     func() time.Time
+}
+
+// NewSignatureVerifier creates a verifier for routes. A nil verifier (or
+// one without a key source) verifies nothing.
+func NewSignatureVerifier(keys KeyStateSource, maxSkew time.Duration, routes []string) *SignatureVerifier {
+	return &SignatureVerifier{keys: keys, maxSkew: maxSkew, routes: routes, now: time.Now}
+}
+
+// Enabled reports whether requests are verified
+func (v *SignatureVerifier) Enabled() bool {
+	return v != nil && v.keys != nil
+}
+
+// Verify checks r's signature and returns the AID that signed it
+func (v *SignatureVerifier) Verify(r *http.Request) (string, error) {
+	sig, err := keri.ParseRequestSignature(r)
+	if err != nil {
+		return "", err
+	}
+	if skew := v.now().Sub(sig.Created); v.maxSkew > 0 && (skew > v.maxSkew || skew < -v.maxSkew) {
+		return "", fmt.Errorf("signature created %s is outside the allowed clock skew", sig.Created.UTC().Format(time.RFC3339))
+	}
+
+	state, err := v.keys.KeyState(r.Context(), sig.AID)
+	if err != nil {
+		return "", fmt.Errorf("resolving key state: %w", err)
+	}
+	if !state.HasKey(sig.KeyID) {
+		return "", fmt.Errorf("key %s is not a current signing key for %s", sig.KeyID, sig.AID)
+	}
+	if err := sig.Verify(); err != nil {
+		return "", err
+	}
+	return sig.AID, nil
+}
+
+type signedAIDKey struct{}
+
+// SignedAIDFromContext returns the AID that signed the request, and whether
+// the request went through signature verification
+func SignedAIDFromContext(ctx context.Context) (string, bool) {
+	aid, ok := ctx.Value(signedAIDKey{}).(string)
+	return aid, ok
+}
+
+// checkSignedAID returns an error when the request was verified but signed
+// by an AID other than the one it acts as
+func checkSignedAID(r *http.Request, aid string) error {
+	signed, ok := SignedAIDFromContext(r.Context())
+	if !ok || signed == aid {
+		return nil
+	}
+	return fmt.Errorf("request signed by %s cannot act as %s", signed, aid)
+}
+
+// SignatureMiddleware requires write requests to the verifier's routes to
+// carry a valid signature, and records the signing AID for handlers to
+// compare with the AID the request acts as
+func SignatureMiddleware(v *SignatureVerifier, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !v.Enabled() || !matchesRoute(r.URL.Path, v.routes) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		aid, err := v.Verify(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Signature")
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "signature required: " + err.Error()})
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), signedAIDKey{}, aid)))
+	})
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/keri"
+)
+
+type stubKeyStates map[string]*keri.KeyState
+
+func (s stubKeyStates) KeyState(ctx context.Context, aid string) (*keri.KeyState, error) {
+	if state, ok := s[aid]; ok {
+		return state, nil
+	}
+	return nil, fmt.Errorf("no KEL found for %s", aid)
+}
+
+func TestSignatureMiddleware(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	_, otherPriv, _ := ed25519.GenerateKey(rand.Reader)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	keys := stubKeyStates{"EUSER1": {AID: "EUSER1", Keys: []string{keri.EncodeVerKey(pub)}}}
+	verifier := NewSignatureVerifier(keys, 5*time.Minute, SignedRoutes)
+	verifier.now = func() time.Time { return now }
+
+	var signed string
+	var verified bool
+	handler := SignatureMiddleware(verifier, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signed, verified = SignedAIDFromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		sign   func(r *http.Request)
+		want   int
+	}{
+		{"signed", "POST", "/api/v1/sync/kel", func(r *http.Request) { keri.SignRequest(r, "EUSER1", priv, now) }, http.StatusOK},
+		{"unsigned", "POST", "/api/v1/sync/kel", func(r *http.Request) {}, http.StatusUnauthorized},
+		{"unsigned read", "GET", "/api/v1/endorsements/requests", func(r *http.Request) {}, http.StatusOK},
+		{"unsigned other route", "POST", "/api/v1/trust/snapshot/verify", func(r *http.Request) {}, http.StatusOK},
+		{"not a current key", "POST", "/api/v1/sync/kel", func(r *http.Request) { keri.SignRequest(r, "EUSER1", otherPriv, now) }, http.StatusUnauthorized},
+		{"unknown AID", "POST", "/api/v1/sync/kel", func(r *http.Request) { keri.SignRequest(r, "EUNKNOWN", priv, now) }, http.StatusUnauthorized},
+		{"stale", "POST", "/api/v1/sync/kel", func(r *http.Request) { keri.SignRequest(r, "EUSER1", priv, now.Add(-time.Hour)) }, http.StatusUnauthorized},
+		{"action route", "POST", "/api/v1/endorsements/requests/obj1/accept", func(r *http.Request) { keri.SignRequest(r, "EUSER1", priv, now) }, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signed, verified = "", false
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			tt.sign(req)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("expected %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+			if rec.Code == http.StatusOK && tt.method == "POST" && matchesRoute(tt.path, SignedRoutes) && (!verified || signed != "EUSER1") {
+				t.Errorf("expected signing AID in context, got %q (%v)", signed, verified)
+			}
+		})
+	}
+}
+
+func TestSignatureMiddleware_Disabled(t *testing.T) {
+	handler := SignatureMiddleware(NewSignatureVerifier(nil, time.Minute, SignedRoutes), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := SignedAIDFromContext(r.Context()); ok {
+			t.Error("expected no signing AID with verification off")
+		}
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/api/v1/identity/set", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 with verification off, got %d", rec.Code)
+	}
+}
+
+func TestCheckSignedAID(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/v1/identity/set", nil)
+	if err := checkSignedAID(req, "EUSER1"); err != nil {
+		t.Errorf("expected unverified request to pass, got %v", err)
+	}
+
+	req = req.WithContext(context.WithValue(req.Context(), signedAIDKey{}, "EUSER1"))
+	if err := checkSignedAID(req, "EUSER1"); err != nil {
+		t.Errorf("expected matching AID to pass, got %v", err)
+	}
+	if err := checkSignedAID(req, "EUSER2"); err == nil {
+		t.Error("expected error when acting as another AID")
+	}
+}
@@ -0,0 +1,397 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/audit"
+)
+
+// Join request states
+const (
+	JoinRequestPending  = "pending"
+	JoinRequestApproved = "approved"
+	JoinRequestRejected = "rejected"
+)
+
+// SpaceInvite records the access granted when a join request is approved
+type SpaceInvite struct {
+	SpaceID     string   `json:"spaceId"`
+	PeerID      string   `json:"peerId"`
+	Permissions []string `json:"permissions"`
+	GrantedBy   string   `json:"grantedBy"`
+	GrantedAt   string   `json:"grantedAt"`
+}
+
+// SpaceJoinRequest is the data stored for a SpaceJoinRequest object
+type SpaceJoinRequest struct {
+	SpaceID        string            `json:"spaceId"`
+	AID            string            `json:"aid"`
+	PeerID         string            `json:"peerId"`
+	CredentialSAID string            `json:"credentialSaid"`
+	Note           string            `json:"note,omitempty"`
+	Status         string            `json:"status"`
+	RequestedAt    string            `json:"requestedAt"`
+	Decision       *ApprovalDecision `json:"decision,omitempty"`
+	Invite         *SpaceInvite      `json:"invite,omitempty"` // Set once approved
+	UpdatedAt      string            `json:"updatedAt,omitempty"`
+}
+
+// SpaceJoinRequestView is a join request with its object ID
+type SpaceJoinRequestView struct {
+	ID string `json:"id"`
+	SpaceJoinRequest
+}
+
+// CreateJoinRequest is the body for POST /api/v1/spaces/{id}/requests
+type CreateJoinRequest struct {
+	AID            string `json:"aid"`
+	PeerID         string `json:"peerId"`
+	CredentialSAID string `json:"credentialSaid"`
+	Note           string `json:"note,omitempty"`
+}
+
+// DecideJoinRequest is the body for POST .../requests/{rid}/approve and
+// .../reject
+type DecideJoinRequest struct {
+	Note string `json:"note,omitempty"`
+	// Permissions granted on approval, "read" or "write". Defaults to
+	// read for the community read-only space and write elsewhere.
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// joinRequestID is the object ID of an AID's request to join a space. Each
+// AID has one request per space, so asking again updates it.
+func joinRequestID(spaceID, aid string) string {
+	return "SpaceJoinRequest-" + spaceID + "-" + aid
+}
+
+// decide applies an admin's decision to a pending join request
+func (j *SpaceJoinRequest) decide(admin, decision, note string, now time.Time) (int, error) {
+	if j.Status != JoinRequestPending {
+		return http.StatusConflict, fmt.Errorf("join request is already %s", j.Status)
+	}
+	j.Decision = &ApprovalDecision{
+		AID:      admin,
+		Decision: decision,
+		Note:     note,
+		At:       now.UTC().Format(time.RFC3339),
+	}
+	j.Status = decision
+	return http.StatusOK, nil
+}
+
+// handleJoinRequests routes /api/v1/spaces/{id}/requests[/{rid}[/approve|reject]]
+func (h *SpacesHandler) handleJoinRequests(w http.ResponseWriter, r *http.Request, spaceID, rest string) {
+	if rest == "" {
+		switch r.Method {
+		case http.MethodGet:
+			h.HandleListJoinRequests(w, r, spaceID)
+		case http.MethodPost:
+			h.HandleCreateJoinRequest(w, r, spaceID)
+		default:
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		}
+		return
+	}
+
+	parts := strings.Split(rest, "/")
+	if parts[0] == "" || len(parts) > 2 {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+	if len(parts) == 1 {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		request, status, err := h.loadJoinRequest(r.Context(), spaceID, parts[0])
+		if err != nil {
+			writeJSON(w, status, map[string]string{"error": err.Error()})
+			return
+		}
+		// The requester can check on their own request
+		if p := PrincipalFromContext(r.Context()); !isAdminCaller(r) && p.AID != request.AID {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": "only admins and the requester can view a join request"})
+			return
+		}
+		writeJSON(w, http.StatusOK, request)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	switch parts[1] {
+	case "approve":
+		h.handleDecideJoinRequest(w, r, spaceID, parts[0], JoinRequestApproved)
+	case "reject":
+		h.handleDecideJoinRequest(w, r, spaceID, parts[0], JoinRequestRejected)
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
+
+// HandleCreateJoinRequest handles POST /api/v1/spaces/{id}/requests. A
+// prospective member asks for their backend's peer to be added to a space,
+// on the strength of their membership credential. Asking again while a
+// request is pending returns it unchanged; a rejected request can be made
+// again.
+func (h *SpacesHandler) HandleCreateJoinRequest(w http.ResponseWriter, r *http.Request, spaceID string) {
+	var req CreateJoinRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+	if req.AID == "" || req.PeerID == "" || req.CredentialSAID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "aid, peerId and credentialSaid are required"})
+		return
+	}
+	if _, err := anysync.DecodeACLIdentity(req.PeerID); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if spaceID == h.spaceManager.GetAdminSpaceID() {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "the admin space can't be joined by request"})
+		return
+	}
+	if err := checkSignedAID(r, req.AID); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+	if status, err := h.validateMembershipCredential(ctx, req.AID, req.CredentialSAID); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	id := joinRequestID(spaceID, req.AID)
+	existing, status, err := h.loadJoinRequest(ctx, spaceID, id)
+	switch {
+	case status == http.StatusForbidden:
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	case err == nil && existing.Status != JoinRequestRejected:
+		writeJSON(w, http.StatusOK, existing)
+		return
+	}
+
+	request := &SpaceJoinRequestView{
+		ID: id,
+		SpaceJoinRequest: SpaceJoinRequest{
+			SpaceID:        spaceID,
+			AID:            req.AID,
+			PeerID:         req.PeerID,
+			CredentialSAID: req.CredentialSAID,
+			Note:           strings.TrimSpace(req.Note),
+			Status:         JoinRequestPending,
+			RequestedAt:    time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+	if status, err := h.saveJoinRequest(ctx, request); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	fmt.Printf("[JoinRequests] %s asked to join %s\n", truncateAID(req.AID), spaceID)
+	h.events.Broadcast(aclChangedEvent(spaceID, ACLJoinRequested, req.AID))
+	writeJSON(w, http.StatusCreated, request)
+}
+
+// HandleListJoinRequests handles GET /api/v1/spaces/{id}/requests (admin)
+// Query params:
+//   - status: Only requests in this state, e.g. pending (optional)
+func (h *SpacesHandler) HandleListJoinRequests(w http.ResponseWriter, r *http.Request, spaceID string) {
+	if !isAdminCaller(r) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "only admins can review join requests"})
+		return
+	}
+	adminSpaceID := h.spaceManager.GetAdminSpaceID()
+	if adminSpaceID == "" {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "admin space not available"})
+		return
+	}
+
+	objects, err := readLatestObjects(r.Context(), h.spaceManager, adminSpaceID, "SpaceJoinRequest")
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read join requests: %v", err),
+		})
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	requests := make([]*SpaceJoinRequestView, 0, len(objects))
+	for _, obj := range objects {
+		req := &SpaceJoinRequestView{ID: obj.ID}
+		if err := json.Unmarshal(obj.Data, &req.SpaceJoinRequest); err != nil {
+			continue
+		}
+		if req.SpaceID != spaceID || (status != "" && req.Status != status) {
+			continue
+		}
+		requests = append(requests, req)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].RequestedAt > requests[j].RequestedAt
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"requests": requests,
+		"count":    len(requests),
+	})
+}
+
+// handleDecideJoinRequest handles POST .../requests/{rid}/approve and
+// .../reject (admin). Approving adds the requester's peer to the space's
+// ACL, records their peer ID so their AID tokens are accepted, and stores
+// the grant on the request as its invite record.
+func (h *SpacesHandler) handleDecideJoinRequest(w http.ResponseWriter, r *http.Request, spaceID, id, decision string) {
+	if !isAdminCaller(r) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "only admins can review join requests"})
+		return
+	}
+	me := ""
+	if h.userIdentity != nil {
+		me = h.userIdentity.GetAID()
+	}
+	if p := PrincipalFromContext(r.Context()); p != nil && p.AID != "" {
+		me = p.AID
+	}
+	if err := checkSignedAID(r, me); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+		return
+	}
+
+	var req DecideJoinRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("invalid request: %v", err),
+			})
+			return
+		}
+	}
+	permissions := req.Permissions
+	if len(permissions) == 0 {
+		permissions = []string{"write"}
+		if spaceID == h.spaceManager.GetCommunityReadOnlySpaceID() {
+			permissions = []string{"read"}
+		}
+	}
+	for _, p := range permissions {
+		if p != "read" && p != "write" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("invalid permission %q (expected read or write)", p),
+			})
+			return
+		}
+	}
+
+	ctx := r.Context()
+	request, status, err := h.loadJoinRequest(ctx, spaceID, id)
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	now := time.Now()
+	if status, err := request.decide(me, decision, strings.TrimSpace(req.Note), now); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	if _, verified := SignedAIDFromContext(ctx); verified {
+		request.Decision.Signed = true
+		request.Decision.Signature = r.Header.Get("Signature")
+		request.Decision.SignatureInput = r.Header.Get("Signature-Input")
+	}
+
+	if decision == JoinRequestApproved {
+		client := h.spaceManager.GetClient()
+		if client == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "any-sync client not available"})
+			return
+		}
+		if err := client.AddToACL(ctx, spaceID, request.PeerID, permissions); err != nil {
+			writeJSON(w, http.StatusBadGateway, map[string]string{
+				"error": fmt.Sprintf("failed to grant access: %v", err),
+			})
+			return
+		}
+		if h.records != nil {
+			err := h.records.StorePeerMapping(ctx, &anystore.PeerMapping{
+				AID:       request.AID,
+				PeerID:    request.PeerID,
+				UpdatedAt: now.UTC(),
+			})
+			if err != nil {
+				fmt.Printf("[JoinRequests] Warning: failed to record peer of %s: %v\n", truncateAID(request.AID), err)
+			}
+		}
+		request.Invite = &SpaceInvite{
+			SpaceID:     spaceID,
+			PeerID:      request.PeerID,
+			Permissions: permissions,
+			GrantedBy:   me,
+			GrantedAt:   now.UTC().Format(time.RFC3339),
+		}
+	}
+
+	if status, err := h.saveJoinRequest(ctx, request); err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	fmt.Printf("[JoinRequests] %s %s %s joining %s\n", truncateAID(me), decision, truncateAID(request.AID), spaceID)
+	if decision == JoinRequestApproved {
+		h.events.Broadcast(aclChangedEvent(spaceID, ACLMemberAdded, request.AID))
+		h.audit.Record(ctx, audit.ActionACLGrant, auditActor(ctx, h.userIdentity), spaceID,
+			nil, aclMember(request.AID, request.PeerID, permissions))
+	}
+	writeJSON(w, http.StatusOK, request)
+}
+
+// loadJoinRequest reads the latest version of a space's join request
+func (h *SpacesHandler) loadJoinRequest(ctx context.Context, spaceID, id string) (*SpaceJoinRequestView, int, error) {
+	adminSpaceID := h.spaceManager.GetAdminSpaceID()
+	if adminSpaceID == "" {
+		return nil, http.StatusForbidden, fmt.Errorf("admin space not available")
+	}
+
+	obj, err := h.spaceManager.ObjectTreeManager().ReadLatestByID(ctx, adminSpaceID, id)
+	if err != nil || obj.Type != "SpaceJoinRequest" {
+		return nil, http.StatusNotFound, fmt.Errorf("join request not found")
+	}
+
+	request := &SpaceJoinRequestView{ID: obj.ID}
+	if err := json.Unmarshal(obj.Data, &request.SpaceJoinRequest); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("invalid join request: %v", err)
+	}
+	if request.SpaceID != spaceID {
+		return nil, http.StatusNotFound, fmt.Errorf("join request not found")
+	}
+	return request, http.StatusOK, nil
+}
+
+// saveJoinRequest writes a new version of a join request to the admin space
+func (h *SpacesHandler) saveJoinRequest(ctx context.Context, request *SpaceJoinRequestView) (int, error) {
+	adminSpaceID := h.spaceManager.GetAdminSpaceID()
+	if adminSpaceID == "" {
+		return http.StatusForbidden, fmt.Errorf("admin space not available")
+	}
+	request.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	if _, err := writeObject(ctx, h.spaceManager, adminSpaceID, request.ID, "SpaceJoinRequest", request.SpaceJoinRequest); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
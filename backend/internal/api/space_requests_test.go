@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/util/crypto"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+)
+
+func TestCreateJoinRequest_Validation(t *testing.T) {
+	_, pub, err := crypto.GenerateRandomEd25519KeyPair()
+	if err != nil {
+		t.Fatalf("GenerateRandomEd25519KeyPair failed: %v", err)
+	}
+	peerID := pub.PeerId()
+
+	store, err := anystore.NewLocalStore(anystore.DefaultConfig(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewLocalStore failed: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	for _, cred := range []*anystore.CachedCredential{
+		{ID: "EMEMBER", SubjectAID: "EALICE", SchemaID: membershipSchema},
+		{ID: "EENDORSE", SubjectAID: "EALICE", SchemaID: "EMatouEndorsementSchemaV1"},
+	} {
+		if err := store.StoreCredential(ctx, cred); err != nil {
+			t.Fatalf("StoreCredential failed: %v", err)
+		}
+	}
+
+	handler := &SpacesHandler{
+		spaceManager: anysync.NewSpaceManager(newMockClient(), &anysync.SpaceManagerConfig{
+			CommunitySpaceID: "test-community-space",
+		}),
+		store:        store,
+		records:      store,
+		userIdentity: identity.New(t.TempDir()),
+	}
+	handler.spaceManager.SetAdminSpaceID("test-admin-space")
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	tests := []struct {
+		name       string
+		spaceID    string
+		body       string
+		wantStatus int
+	}{
+		{"invalid json", "test-community-space", `{`, http.StatusBadRequest},
+		{"missing peer", "test-community-space", `{"aid":"EALICE","credentialSaid":"EMEMBER"}`, http.StatusBadRequest},
+		{"invalid peer", "test-community-space", `{"aid":"EALICE","peerId":"nope","credentialSaid":"EMEMBER"}`, http.StatusBadRequest},
+		{"admin space", "test-admin-space", `{"aid":"EALICE","peerId":"` + peerID + `","credentialSaid":"EMEMBER"}`, http.StatusForbidden},
+		{"unknown credential", "test-community-space", `{"aid":"EALICE","peerId":"` + peerID + `","credentialSaid":"EOTHER"}`, http.StatusForbidden},
+		{"not a membership", "test-community-space", `{"aid":"EALICE","peerId":"` + peerID + `","credentialSaid":"EENDORSE"}`, http.StatusForbidden},
+		{"someone else's credential", "test-community-space", `{"aid":"EBOB","peerId":"` + peerID + `","credentialSaid":"EMEMBER"}`, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/spaces/"+tt.spaceID+"/requests", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestJoinRequests_Review(t *testing.T) {
+	handler, _, _ := setupTestSpacesHandler(t)
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	member := &Principal{Kind: "token", AID: "EALICE"}
+	do := func(method, path string, p *Principal) int {
+		req := httptest.NewRequest(method, path, nil)
+		if p != nil {
+			req = req.WithContext(context.WithValue(req.Context(), principalKey{}, p))
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := do(http.MethodGet, "/api/v1/spaces/test-community-space/requests", member); code != http.StatusForbidden {
+		t.Errorf("expected members refused the request list, got %d", code)
+	}
+	if code := do(http.MethodPost, "/api/v1/spaces/test-community-space/requests/SpaceJoinRequest-1/approve", member); code != http.StatusForbidden {
+		t.Errorf("expected members refused approval, got %d", code)
+	}
+	// Admins pass review checks and need the admin space
+	if code := do(http.MethodGet, "/api/v1/spaces/test-community-space/requests", nil); code != http.StatusForbidden {
+		t.Errorf("expected 403 without an admin space, got %d", code)
+	}
+	if code := do(http.MethodPut, "/api/v1/spaces/test-community-space/requests", nil); code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", code)
+	}
+	if code := do(http.MethodPost, "/api/v1/spaces/test-community-space/requests/SpaceJoinRequest-1/ignore", nil); code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown action, got %d", code)
+	}
+}
+
+func TestSpaceJoinRequest_Decide(t *testing.T) {
+	now := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		status     string
+		decision   string
+		wantStatus int
+	}{
+		{"approve pending", JoinRequestPending, JoinRequestApproved, http.StatusOK},
+		{"reject pending", JoinRequestPending, JoinRequestRejected, http.StatusOK},
+		{"already approved", JoinRequestApproved, JoinRequestRejected, http.StatusConflict},
+		{"already rejected", JoinRequestRejected, JoinRequestApproved, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := &SpaceJoinRequest{SpaceID: "space-1", AID: "EALICE", Status: tt.status}
+			status, err := j.decide("EADMIN", tt.decision, "ok", now)
+			if status != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d (%v)", tt.wantStatus, status, err)
+			}
+			if status == http.StatusOK && (j.Status != tt.decision || j.Decision.AID != "EADMIN") {
+				t.Errorf("expected the decision recorded, got %+v", j)
+			}
+		})
+	}
+}
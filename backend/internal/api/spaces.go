@@ -4,15 +4,20 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/anyproto/any-sync/commonspace/object/acl/list"
 	"github.com/anyproto/any-sync/util/crypto"
 	"github.com/matou-dao/backend/internal/anystore"
 	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/audit"
+	"github.com/matou-dao/backend/internal/bootstrap"
 	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/secret"
 	"github.com/matou-dao/backend/internal/types"
 )
 
@@ -20,18 +25,62 @@ import (
 type SpacesHandler struct {
 	spaceManager *anysync.SpaceManager
 	store        *anystore.LocalStore
+	records      anystore.RecordStore
 	spaceStore   anysync.SpaceStore
 	userIdentity *identity.UserIdentity
+	events       *EventBroker
+	audit        *audit.Log
+	bootstrap    *bootstrap.Orchestrator
 }
 
 // NewSpacesHandler creates a new spaces handler
 func NewSpacesHandler(spaceManager *anysync.SpaceManager, store *anystore.LocalStore, userIdentity *identity.UserIdentity) *SpacesHandler {
-	return &SpacesHandler{
+	h := &SpacesHandler{
 		spaceManager: spaceManager,
 		store:        store,
 		spaceStore:   anystore.NewSpaceStoreAdapter(store),
 		userIdentity: userIdentity,
 	}
+	if store != nil {
+		h.records = store
+	}
+	h.SetBootstrap(bootstrap.NewOrchestrator(spaceManager, h.spaceStore, userIdentity))
+	return h
+}
+
+// SetRecordStore keeps space records and peer mappings in records instead of
+// the local store. Call it before SetBootstrap.
+func (h *SpacesHandler) SetRecordStore(records anystore.RecordStore) {
+	h.records = records
+	h.spaceStore = anystore.NewSpaceStoreAdapter(records)
+}
+
+// SetEvents broadcasts space:created and acl:changed events to stream clients
+func (h *SpacesHandler) SetEvents(events *EventBroker) {
+	h.events = events
+}
+
+// SetAudit records join request approvals in the audit log
+func (h *SpacesHandler) SetAudit(log *audit.Log) {
+	h.audit = log
+}
+
+// SetBootstrap sets the orchestrator that creates the org's spaces, so its
+// status covers spaces created here
+func (h *SpacesHandler) SetBootstrap(o *bootstrap.Orchestrator) {
+	o.OnSpaceCreated(func(space *anysync.Space) {
+		h.events.Broadcast(spaceCreatedEvent(space))
+	})
+	h.bootstrap = o
+}
+
+// orchestrator returns the bootstrap orchestrator, or a standalone one for
+// handlers built without NewSpacesHandler
+func (h *SpacesHandler) orchestrator() *bootstrap.Orchestrator {
+	if h.bootstrap != nil {
+		return h.bootstrap
+	}
+	return bootstrap.NewOrchestrator(h.spaceManager, h.spaceStore, h.userIdentity)
 }
 
 // CreateCommunityRequest represents a request to create a community space
@@ -76,8 +125,8 @@ type GetCommunityResponse struct {
 
 // CreatePrivateRequest represents a request to create a private space
 type CreatePrivateRequest struct {
-	UserAID  string `json:"userAid"`
-	Mnemonic string `json:"mnemonic,omitempty"`
+	UserAID  string           `json:"userAid"`
+	Mnemonic *secret.Mnemonic `json:"mnemonic,omitempty"`
 }
 
 // CreatePrivateResponse represents the response for private space creation
@@ -238,115 +287,35 @@ func (h *SpacesHandler) HandleCreateCommunity(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Check if community space already exists
-	existingSpace, err := h.spaceManager.GetCommunitySpace(r.Context())
-	if err == nil && existingSpace != nil {
-		// Verify the space still exists on the network (e.g. after any-sync restart).
-		// MakeSpaceShareable talks to the coordinator — if it fails the space is gone.
-		client := h.spaceManager.GetClient()
-		spaceValid := false
-		if client != nil {
-			if verifyErr := client.MakeSpaceShareable(r.Context(), existingSpace.SpaceID); verifyErr == nil {
-				spaceValid = true
-			} else {
-				fmt.Printf("[CreateCommunity] Cached space %s no longer valid: %v — will recreate\n", existingSpace.SpaceID, verifyErr)
-			}
-		}
-		if spaceValid {
-			writeJSON(w, http.StatusOK, CreateCommunityResponse{
-				Success:          true,
-				CommunitySpaceID: existingSpace.SpaceID,
-				ReadOnlySpaceID:  h.spaceManager.GetCommunityReadOnlySpaceID(),
-				AdminSpaceID:     h.spaceManager.GetAdminSpaceID(),
-				SpaceID:          existingSpace.SpaceID, // backward compat
-			})
-			return
-		}
-		// Clear stale cached IDs so we fall through to recreation
-		h.spaceManager.SetCommunitySpaceID("")
-		h.spaceManager.SetCommunityReadOnlySpaceID("")
-		h.spaceManager.SetAdminSpaceID("")
-	}
-
-	// Create new community space via any-sync client using mnemonic-derived keys.
-	// The admin's identity must be set first (POST /api/v1/identity/set) so we
-	// can derive deterministic keys from the stored mnemonic. This makes the admin
-	// the recoverable owner of the community space.
+	// Create (or reuse) the community, read-only and admin spaces. Keys are
+	// derived from the admin's stored mnemonic, so the identity must be set
+	// first (POST /api/v1/identity/set); this makes the admin the
+	// recoverable owner of the org's spaces.
 	ctx := r.Context()
-	client := h.spaceManager.GetClient()
-	if client == nil {
-		writeJSON(w, http.StatusServiceUnavailable, CreateCommunityResponse{
-			Success: false,
-			Error:   "any-sync client not available",
-		})
-		return
-	}
-
-	// Derive community space keys from stored mnemonic (index 1; index 0 = private space)
-	mnemonic := ""
-	if h.userIdentity != nil {
-		mnemonic = h.userIdentity.GetMnemonic()
-	}
-	if mnemonic == "" {
-		writeJSON(w, http.StatusConflict, CreateCommunityResponse{
-			Success: false,
-			Error:   "identity must be configured before creating community space (call POST /api/v1/identity/set first)",
-		})
-		return
-	}
-
-	keys, err := anysync.DeriveSpaceKeySet(mnemonic, 1)
-	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, CreateCommunityResponse{
-			Success: false,
-			Error:   fmt.Sprintf("failed to derive community space keys: %v", err),
-		})
-		return
-	}
-
-	// Use the peer key as the space signing key so the SDK client's account
-	// identity (peer key) matches the ACL owner. This ensures ACL operations
-	// like BuildInviteAnyone succeed when the admin creates invites later.
-	keys.SigningKey = client.GetSigningKey()
-
-	result, err := client.CreateSpaceWithKeys(ctx, req.OrgAID, anysync.SpaceTypeCommunity, keys)
+	status, err := h.orchestrator().CreateOrgSpaces(ctx, bootstrap.Request{OrgAID: req.OrgAID, OrgName: req.OrgName})
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, CreateCommunityResponse{
+		code := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, bootstrap.ErrNoClient):
+			code = http.StatusServiceUnavailable
+		case errors.Is(err, bootstrap.ErrNoIdentity):
+			code = http.StatusConflict
+		}
+		writeJSON(w, code, CreateCommunityResponse{
 			Success: false,
-			Error:   fmt.Sprintf("failed to create community space: %v", err),
+			Error:   err.Error(),
 		})
 		return
 	}
-
-	// Make space shareable on coordinator (required before CreateOpenInvite)
-	if err := client.MakeSpaceShareable(ctx, result.SpaceID); err != nil {
-		fmt.Printf("Warning: failed to make space shareable: %v\n", err)
-	}
-
-	// Save space record to local store
-	space := &anysync.Space{
-		SpaceID:   result.SpaceID,
-		OwnerAID:  req.OrgAID,
-		SpaceType: anysync.SpaceTypeCommunity,
-		SpaceName: req.OrgName + " Community",
-		CreatedAt: result.CreatedAt,
-		LastSync:  result.CreatedAt,
-	}
-
-	if err := h.spaceStore.SaveSpace(ctx, space); err != nil {
-		// Log but don't fail - space was created in any-sync
-		fmt.Printf("Warning: failed to save community space record: %v\n", err)
-	}
-
-	// Update space manager with the new community space ID
-	h.spaceManager.SetCommunitySpaceID(result.SpaceID)
+	community := status.Step(bootstrap.StepCommunitySpace)
+	readOnly := status.Step(bootstrap.StepReadOnlySpace)
 
 	// Collect seeded objects across all spaces
 	var allObjects []CreatedObject
 
-	// Seed community space with type definition + admin SharedProfile
-	if req.AdminAID != "" {
-		communityObjects, seedErr := h.seedSpace(ctx, result.SpaceID, types.SharedProfileType(), map[string]interface{}{
+	// Seed a new community space with type definition + admin SharedProfile
+	if req.AdminAID != "" && community.Created {
+		communityObjects, seedErr := h.seedSpace(ctx, community.SpaceID, types.SharedProfileType(), map[string]interface{}{
 			"aid":         req.AdminAID,
 			"displayName": req.AdminName,
 			"bio":         "",
@@ -364,111 +333,45 @@ func (h *SpacesHandler) HandleCreateCommunity(w http.ResponseWriter, r *http.Req
 		}
 	}
 
-	// Create community read-only space (key derivation index 2)
-	roKeys, err := anysync.DeriveSpaceKeySet(mnemonic, 2)
-	if err != nil {
-		fmt.Printf("Warning: failed to derive community-readonly space keys: %v\n", err)
-	} else {
-		roKeys.SigningKey = client.GetSigningKey()
-		roResult, err := client.CreateSpaceWithKeys(ctx, req.OrgAID, anysync.SpaceTypeCommunityReadOnly, roKeys)
-		if err != nil {
-			fmt.Printf("Warning: failed to create community-readonly space: %v\n", err)
+	// Seed a new readonly space with CommunityProfile type def + admin's CommunityProfile
+	if req.AdminAID != "" && readOnly.Created {
+		now := time.Now().UTC().Format(time.RFC3339)
+		roObjects, seedErr := h.seedSpace(ctx, readOnly.SpaceID, types.CommunityProfileType(), map[string]interface{}{
+			"userAID":    req.AdminAID,
+			"credential": req.CredentialSAID,
+			"role":       "Operations Steward",
+			"memberSince": now,
+			"lastActiveAt": now,
+			"credentials":  []string{req.CredentialSAID},
+			"permissions":  []string{"participate", "vote", "propose"},
+		}, fmt.Sprintf("CommunityProfile-%s", req.AdminAID))
+		if seedErr != nil {
+			fmt.Printf("Warning: failed to seed community-readonly space: %v\n", seedErr)
 		} else {
-			if err := client.MakeSpaceShareable(ctx, roResult.SpaceID); err != nil {
-				fmt.Printf("Warning: failed to make community-readonly space shareable: %v\n", err)
-			}
-			roSpace := &anysync.Space{
-				SpaceID:   roResult.SpaceID,
-				OwnerAID:  req.OrgAID,
-				SpaceType: anysync.SpaceTypeCommunityReadOnly,
-				SpaceName: req.OrgName + " Community (Read-Only)",
-				CreatedAt: roResult.CreatedAt,
-				LastSync:  roResult.CreatedAt,
-			}
-			if err := h.spaceStore.SaveSpace(ctx, roSpace); err != nil {
-				fmt.Printf("Warning: failed to save community-readonly space record: %v\n", err)
-			}
-			h.spaceManager.SetCommunityReadOnlySpaceID(roResult.SpaceID)
-			if h.userIdentity != nil {
-				if err := h.userIdentity.SetCommunityReadOnlySpaceID(roResult.SpaceID); err != nil {
-					fmt.Printf("Warning: failed to persist community-readonly space ID: %v\n", err)
-				}
-			}
-
-			// Seed readonly space with CommunityProfile type def + admin's CommunityProfile
-			if req.AdminAID != "" {
-				now := time.Now().UTC().Format(time.RFC3339)
-				roObjects, seedErr := h.seedSpace(ctx, roResult.SpaceID, types.CommunityProfileType(), map[string]interface{}{
-					"userAID":    req.AdminAID,
-					"credential": req.CredentialSAID,
-					"role":       "Operations Steward",
-					"memberSince": now,
-					"lastActiveAt": now,
-					"credentials":  []string{req.CredentialSAID},
-					"permissions":  []string{"participate", "vote", "propose"},
-				}, fmt.Sprintf("CommunityProfile-%s", req.AdminAID))
-				if seedErr != nil {
-					fmt.Printf("Warning: failed to seed community-readonly space: %v\n", seedErr)
-				} else {
-					allObjects = append(allObjects, roObjects...)
-				}
-
-				// Seed readonly space with OrgProfile type def + Matou OrgProfile
-				orgProfileObjects, orgSeedErr := h.seedSpace(ctx, roResult.SpaceID, types.OrgProfileType(), map[string]interface{}{
-					"communityName": req.OrgName,
-					"contactEmail":  req.AdminEmail,
-					"logo":          req.AdminAvatar,
-					"createdAt":     now,
-				}, fmt.Sprintf("OrgProfile-%s", req.OrgAID))
-				if orgSeedErr != nil {
-					fmt.Printf("Warning: failed to seed OrgProfile: %v\n", orgSeedErr)
-				} else {
-					allObjects = append(allObjects, orgProfileObjects...)
-				}
-			}
+			allObjects = append(allObjects, roObjects...)
 		}
-	}
 
-	// Create admin space (key derivation index 3)
-	adminKeys, err := anysync.DeriveSpaceKeySet(mnemonic, 3)
-	if err != nil {
-		fmt.Printf("Warning: failed to derive admin space keys: %v\n", err)
-	} else {
-		adminKeys.SigningKey = client.GetSigningKey()
-		adminResult, err := client.CreateSpaceWithKeys(ctx, req.OrgAID, anysync.SpaceTypeAdmin, adminKeys)
-		if err != nil {
-			fmt.Printf("Warning: failed to create admin space: %v\n", err)
+		// Seed readonly space with OrgProfile type def + Matou OrgProfile
+		orgProfileObjects, orgSeedErr := h.seedSpace(ctx, readOnly.SpaceID, types.OrgProfileType(), map[string]interface{}{
+			"communityName": req.OrgName,
+			"contactEmail":  req.AdminEmail,
+			"logo":          req.AdminAvatar,
+			"createdAt":     now,
+		}, fmt.Sprintf("OrgProfile-%s", req.OrgAID))
+		if orgSeedErr != nil {
+			fmt.Printf("Warning: failed to seed OrgProfile: %v\n", orgSeedErr)
 		} else {
-			if err := client.MakeSpaceShareable(ctx, adminResult.SpaceID); err != nil {
-				fmt.Printf("Warning: failed to make admin space shareable: %v\n", err)
-			}
-			adminSpace := &anysync.Space{
-				SpaceID:   adminResult.SpaceID,
-				OwnerAID:  req.OrgAID,
-				SpaceType: anysync.SpaceTypeAdmin,
-				SpaceName: req.OrgName + " Admin",
-				CreatedAt: adminResult.CreatedAt,
-				LastSync:  adminResult.CreatedAt,
-			}
-			if err := h.spaceStore.SaveSpace(ctx, adminSpace); err != nil {
-				fmt.Printf("Warning: failed to save admin space record: %v\n", err)
-			}
-			h.spaceManager.SetAdminSpaceID(adminResult.SpaceID)
-			if h.userIdentity != nil {
-				if err := h.userIdentity.SetAdminSpaceID(adminResult.SpaceID); err != nil {
-					fmt.Printf("Warning: failed to persist admin space ID: %v\n", err)
-				}
-			}
+			allObjects = append(allObjects, orgProfileObjects...)
 		}
 	}
 
 	writeJSON(w, http.StatusOK, CreateCommunityResponse{
 		Success:          true,
-		CommunitySpaceID: result.SpaceID,
+		CommunitySpaceID: community.SpaceID,
 		ReadOnlySpaceID:  h.spaceManager.GetCommunityReadOnlySpaceID(),
 		AdminSpaceID:     h.spaceManager.GetAdminSpaceID(),
 		Objects:          allObjects,
-		SpaceID:          result.SpaceID, // backward compat
+		SpaceID:          community.SpaceID, // backward compat
 	})
 }
 
@@ -572,6 +475,7 @@ func (h *SpacesHandler) HandleCreatePrivate(w http.ResponseWriter, r *http.Reque
 		})
 		return
 	}
+	defer req.Mnemonic.Zero()
 
 	// In per-user mode, use local identity as fallback
 	if req.UserAID == "" && h.userIdentity != nil {
@@ -592,9 +496,9 @@ func (h *SpacesHandler) HandleCreatePrivate(w http.ResponseWriter, r *http.Reque
 	if err == nil && existingSpace != nil {
 		// Even if space exists, persist peer key if mnemonic is provided
 		// (handles upgrades where peer key wasn't stored on initial creation)
-		if req.Mnemonic != "" {
+		if !req.Mnemonic.IsEmpty() {
 			if client := h.spaceManager.GetClient(); client != nil {
-				if peerKey, peerErr := anysync.DeriveKeyFromMnemonic(req.Mnemonic, 0); peerErr == nil {
+				if peerKey, peerErr := anysync.DeriveKeyFromSecret(req.Mnemonic, 0); peerErr == nil {
 					anysync.PersistUserPeerKey(client.GetDataDir(), req.UserAID, peerKey)
 				}
 			}
@@ -608,8 +512,8 @@ func (h *SpacesHandler) HandleCreatePrivate(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Create new private space — use mnemonic-derived keys if provided
-	if req.Mnemonic != "" {
-		if err := anysync.ValidateMnemonic(req.Mnemonic); err != nil {
+	if !req.Mnemonic.IsEmpty() {
+		if err := anysync.ValidateSecretMnemonic(req.Mnemonic); err != nil {
 			writeJSON(w, http.StatusBadRequest, CreatePrivateResponse{
 				Success: false,
 				Error:   fmt.Sprintf("invalid mnemonic: %v", err),
@@ -617,7 +521,7 @@ func (h *SpacesHandler) HandleCreatePrivate(w http.ResponseWriter, r *http.Reque
 			return
 		}
 
-		keys, err := anysync.DeriveSpaceKeySet(req.Mnemonic, 0)
+		keys, err := anysync.DeriveSpaceKeySetFromSecret(req.Mnemonic, 0)
 		if err != nil {
 			writeJSON(w, http.StatusInternalServerError, CreatePrivateResponse{
 				Success: false,
@@ -636,7 +540,7 @@ func (h *SpacesHandler) HandleCreatePrivate(w http.ResponseWriter, r *http.Reque
 		}
 
 		// Derive and persist user's peer key for future operations (e.g. JoinWithInvite)
-		peerKey, peerErr := anysync.DeriveKeyFromMnemonic(req.Mnemonic, 0)
+		peerKey, peerErr := anysync.DeriveKeyFromSecret(req.Mnemonic, 0)
 		if peerErr != nil {
 			fmt.Printf("Warning: failed to derive peer key: %v\n", peerErr)
 		} else {
@@ -666,6 +570,7 @@ func (h *SpacesHandler) HandleCreatePrivate(w http.ResponseWriter, r *http.Reque
 		if err := h.spaceStore.SaveSpace(ctx, space); err != nil {
 			fmt.Printf("Warning: failed to save private space record: %v\n", err)
 		}
+		h.events.Broadcast(spaceCreatedEvent(space))
 
 		writeJSON(w, http.StatusOK, CreatePrivateResponse{
 			Success: true,
@@ -689,6 +594,7 @@ func (h *SpacesHandler) HandleCreatePrivate(w http.ResponseWriter, r *http.Reque
 	if err := h.spaceStore.SaveSpace(ctx, space); err != nil {
 		fmt.Printf("Warning: failed to save private space record: %v\n", err)
 	}
+	h.events.Broadcast(spaceCreatedEvent(space))
 
 	writeJSON(w, http.StatusOK, CreatePrivateResponse{
 		Success: true,
@@ -784,6 +690,8 @@ func (h *SpacesHandler) HandleInvite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.events.Broadcast(aclChangedEvent(communitySpace.SpaceID, ACLInviteCreated, ""))
+
 	resp := InviteResponse{
 		Success:          true,
 		CommunitySpaceID: communitySpace.SpaceID,
@@ -797,6 +705,7 @@ func (h *SpacesHandler) HandleInvite(w http.ResponseWriter, r *http.Request) {
 		if roErr != nil {
 			fmt.Printf("Warning: failed to create community-readonly invite: %v\n", roErr)
 		} else {
+			h.events.Broadcast(aclChangedEvent(roSpaceID, ACLInviteCreated, ""))
 			roKeyBytes, roMarshalErr := roInviteKey.Marshall()
 			if roMarshalErr == nil {
 				resp.ReadOnlyInviteKey = base64.StdEncoding.EncodeToString(roKeyBytes)
@@ -913,23 +822,12 @@ func (h *SpacesHandler) HandleJoinCommunity(w http.ResponseWriter, r *http.Reque
 		})
 		return
 	}
+	h.events.Broadcast(aclChangedEvent(communitySpace.SpaceID, ACLJoined, req.UserAID))
 
 	// Generate and persist space keys so this backend can write objects
 	// (e.g. SharedProfile) to the community space. Each member gets their
 	// own signing key; the ACL authorizes writes based on peer identity.
-	dataDir := client.GetDataDir()
-	communityKeys, err := anysync.GenerateSpaceKeySet()
-	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, JoinCommunityResponse{
-			Success: false,
-			Error:   fmt.Sprintf("failed to generate community space keys: %v", err),
-		})
-		return
-	}
-	// Use the peer key as the signing key so ObjectTree writes are authorized
-	// by the ACL (which registered the peer key during JoinWithInvite).
-	communityKeys.SigningKey = client.GetSigningKey()
-	if err := anysync.PersistSpaceKeySet(dataDir, communitySpace.SpaceID, communityKeys); err != nil {
+	if err := persistMemberSpaceKeys(client, communitySpace.SpaceID); err != nil {
 		writeJSON(w, http.StatusInternalServerError, JoinCommunityResponse{
 			Success: false,
 			Error:   fmt.Sprintf("failed to persist community space keys: %v", err),
@@ -949,16 +847,13 @@ func (h *SpacesHandler) HandleJoinCommunity(w http.ResponseWriter, r *http.Reque
 				} else {
 					h.spaceManager.SetCommunityReadOnlySpaceID(req.ReadOnlySpaceID)
 					fmt.Printf("[Spaces] User %s joined community-readonly space %s\n", req.UserAID, req.ReadOnlySpaceID)
+					h.events.Broadcast(aclChangedEvent(req.ReadOnlySpaceID, ACLJoined, req.UserAID))
 
 					// Persist keys for the readonly space too
-					roKeys, roKeyGenErr := anysync.GenerateSpaceKeySet()
-					if roKeyGenErr == nil {
-						roKeys.SigningKey = client.GetSigningKey()
-						if roPersistErr := anysync.PersistSpaceKeySet(dataDir, req.ReadOnlySpaceID, roKeys); roPersistErr != nil {
-							fmt.Printf("[JoinCommunity] Warning: failed to persist readonly space keys: %v\n", roPersistErr)
-						} else {
-							fmt.Printf("[JoinCommunity] Generated and persisted space keys for readonly space %s\n", req.ReadOnlySpaceID)
-						}
+					if roPersistErr := persistMemberSpaceKeys(client, req.ReadOnlySpaceID); roPersistErr != nil {
+						fmt.Printf("[JoinCommunity] Warning: failed to persist readonly space keys: %v\n", roPersistErr)
+					} else {
+						fmt.Printf("[JoinCommunity] Generated and persisted space keys for readonly space %s\n", req.ReadOnlySpaceID)
 					}
 				}
 			}
@@ -971,6 +866,250 @@ func (h *SpacesHandler) HandleJoinCommunity(w http.ResponseWriter, r *http.Reque
 	})
 }
 
+// persistMemberSpaceKeys generates and persists the key set this backend
+// writes a joined space with. The peer key is the signing key, so ObjectTree
+// writes are authorized by the ACL entry the join created for it.
+func persistMemberSpaceKeys(client anysync.AnySyncClient, spaceID string) error {
+	keys, err := anysync.GenerateSpaceKeySet()
+	if err != nil {
+		return fmt.Errorf("generating space keys: %w", err)
+	}
+	keys.SigningKey = client.GetSigningKey()
+	return anysync.PersistSpaceKeySet(client.GetDataDir(), spaceID, keys)
+}
+
+// Join approval waits for POST /api/v1/spaces/join. The maximum stays below
+// the /api/v1/spaces/ route timeout.
+const (
+	defaultJoinApprovalWait = 30 * time.Second
+	maxJoinApprovalWait     = 90 * time.Second
+	joinApprovalPoll        = 2 * time.Second
+)
+
+// Join statuses
+const (
+	JoinStatusJoined  = "joined"
+	JoinStatusPending = "pending" // Waiting for a space admin to approve
+)
+
+// JoinSpaceRequest represents a request to join a space as a member
+type JoinSpaceRequest struct {
+	CredentialSAID string `json:"credentialSaid"`        // the caller's membership credential
+	InviteKey      string `json:"inviteKey"`             // base64-encoded invite private key
+	SpaceID        string `json:"spaceId,omitempty"`     // defaults to the community space
+	Token          string `json:"token,omitempty"`       // invite token from POST /api/v1/invites, in place of inviteKey and spaceId
+	WaitSeconds    int    `json:"waitSeconds,omitempty"` // how long to wait for approval (default 30, max 90)
+}
+
+// JoinSpaceResponse represents the response for a space join
+type JoinSpaceResponse struct {
+	Success  bool   `json:"success"`
+	Status   string `json:"status,omitempty"`
+	SpaceID  string `json:"spaceId,omitempty"`
+	CanWrite bool   `json:"canWrite,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// HandleJoinSpace handles POST /api/v1/spaces/join
+// Validates the caller's membership credential, joins the space with their
+// peer key, waits for approval when the invite requires it, and persists the
+// space keys. Returns 202 with status "pending" if approval doesn't arrive in
+// time; calling again resumes waiting without submitting another request.
+func (h *SpacesHandler) HandleJoinSpace(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, JoinSpaceResponse{
+			Success: false,
+			Error:   "method not allowed",
+		})
+		return
+	}
+
+	var req JoinSpaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, JoinSpaceResponse{
+			Success: false,
+			Error:   fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+
+	if req.CredentialSAID == "" || (req.InviteKey == "" && req.Token == "") {
+		writeJSON(w, http.StatusBadRequest, JoinSpaceResponse{
+			Success: false,
+			Error:   "credentialSaid and inviteKey or token are required",
+		})
+		return
+	}
+
+	ctx := r.Context()
+
+	aid := ""
+	if h.userIdentity != nil {
+		aid = h.userIdentity.GetAID()
+	}
+	if aid == "" {
+		writeJSON(w, http.StatusConflict, JoinSpaceResponse{
+			Success: false,
+			Error:   "identity not set; call POST /api/v1/identity/set first",
+		})
+		return
+	}
+
+	if status, err := h.validateMembershipCredential(ctx, aid, req.CredentialSAID); err != nil {
+		writeJSON(w, status, JoinSpaceResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var invitePrivKey crypto.PrivKey
+	if req.Token != "" {
+		token, key, err := DecodeInviteToken(req.Token)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, JoinSpaceResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		if token.ExpiresAt > 0 && time.Now().Unix() >= token.ExpiresAt {
+			writeJSON(w, http.StatusGone, JoinSpaceResponse{
+				Success: false,
+				Error:   "invite has expired",
+			})
+			return
+		}
+		req.SpaceID, invitePrivKey = token.SpaceID, key
+	}
+
+	spaceID := req.SpaceID
+	if spaceID == "" {
+		spaceID = h.spaceManager.GetCommunitySpaceID()
+	}
+	if spaceID == "" {
+		writeJSON(w, http.StatusConflict, JoinSpaceResponse{
+			Success: false,
+			Error:   "community space not configured",
+		})
+		return
+	}
+
+	if invitePrivKey == nil {
+		inviteKeyBytes, err := base64.StdEncoding.DecodeString(req.InviteKey)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, JoinSpaceResponse{
+				Success: false,
+				Error:   "invalid invite key encoding",
+			})
+			return
+		}
+		invitePrivKey, err = crypto.UnmarshalEd25519PrivateKeyProto(inviteKeyBytes)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, JoinSpaceResponse{
+				Success: false,
+				Error:   fmt.Sprintf("invalid invite key: %v", err),
+			})
+			return
+		}
+	}
+
+	client := h.spaceManager.GetClient()
+	if client == nil {
+		writeJSON(w, http.StatusServiceUnavailable, JoinSpaceResponse{
+			Success: false,
+			Error:   "any-sync client not available",
+		})
+		return
+	}
+
+	// Ensure the space is shareable on the coordinator (required before join)
+	if err := client.MakeSpaceShareable(ctx, spaceID); err != nil {
+		fmt.Printf("[JoinSpace] Warning: MakeSpaceShareable: %v\n", err)
+	}
+
+	// Admins approving a join request see who is asking and on what grounds
+	metadata, _ := json.Marshal(map[string]string{
+		"aid":            aid,
+		"credentialSaid": req.CredentialSAID,
+		"joinedAt":       time.Now().UTC().Format(time.RFC3339),
+	})
+
+	aclMgr := h.spaceManager.ACLManager()
+	pending, err := aclMgr.RequestJoin(ctx, spaceID, invitePrivKey, metadata)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, JoinSpaceResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to join space: %v", err),
+		})
+		return
+	}
+
+	identity := client.GetSigningKey().GetPublic()
+	if pending {
+		h.events.Broadcast(aclChangedEvent(spaceID, ACLJoinRequested, aid))
+		wait := defaultJoinApprovalWait
+		if req.WaitSeconds > 0 {
+			wait = min(time.Duration(req.WaitSeconds)*time.Second, maxJoinApprovalWait)
+		}
+		waitCtx, cancel := context.WithTimeout(ctx, wait)
+		defer cancel()
+		if _, err := aclMgr.AwaitPermissions(waitCtx, spaceID, identity, joinApprovalPoll); err != nil {
+			fmt.Printf("[JoinSpace] %s waiting for approval to join %s\n", truncateAID(aid), spaceID)
+			writeJSON(w, http.StatusAccepted, JoinSpaceResponse{
+				Success: true,
+				Status:  JoinStatusPending,
+				SpaceID: spaceID,
+			})
+			return
+		}
+	}
+
+	if err := persistMemberSpaceKeys(client, spaceID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, JoinSpaceResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to persist space keys: %v", err),
+		})
+		return
+	}
+
+	perm, err := aclMgr.GetPermissions(ctx, spaceID, identity)
+	if err != nil {
+		fmt.Printf("[JoinSpace] Warning: reading permissions for %s: %v\n", spaceID, err)
+	}
+	fmt.Printf("[JoinSpace] %s joined space %s\n", truncateAID(aid), spaceID)
+	h.events.Broadcast(aclChangedEvent(spaceID, ACLJoined, aid))
+
+	writeJSON(w, http.StatusOK, JoinSpaceResponse{
+		Success:  true,
+		Status:   JoinStatusJoined,
+		SpaceID:  spaceID,
+		CanWrite: perm.CanWrite(),
+	})
+}
+
+// validateMembershipCredential checks that a cached credential is a
+// membership credential held by aid and, once the org is known, issued by it
+func (h *SpacesHandler) validateMembershipCredential(ctx context.Context, aid, said string) (int, error) {
+	if h.store == nil {
+		return http.StatusServiceUnavailable, fmt.Errorf("local store not available")
+	}
+	cred, err := h.store.GetCredential(ctx, said)
+	if err != nil {
+		return http.StatusForbidden, fmt.Errorf("credential %s not found; sync it with POST /api/v1/sync/credentials first", said)
+	}
+	if cred.SchemaID != "EMatouMembershipSchemaV1" {
+		return http.StatusForbidden, fmt.Errorf("only membership credentials can grant space access")
+	}
+	if cred.SubjectAID != aid {
+		return http.StatusForbidden, fmt.Errorf("credential was not issued to %s", aid)
+	}
+	if orgAID := h.userIdentity.GetOrgAID(); orgAID != "" && cred.IssuerAID != orgAID {
+		return http.StatusForbidden, fmt.Errorf("credential was not issued by the organization")
+	}
+	return http.StatusOK, nil
+}
+
 // VerifyAccessResponse represents the response for access verification
 type VerifyAccessResponse struct {
 	HasAccess bool   `json:"hasAccess"`
@@ -992,22 +1131,25 @@ func (h *SpacesHandler) HandleVerifyAccess(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	ctx := r.Context()
+	writeJSON(w, http.StatusOK, verifyCommunityAccess(r.Context(), h.spaceManager, aid))
+}
 
+// verifyCommunityAccess checks the community space ACL for an AID, either as
+// the space owner (via the space signing key) or as a joined member (via the
+// user's persisted peer key).
+func verifyCommunityAccess(ctx context.Context, spaceManager *anysync.SpaceManager, aid string) VerifyAccessResponse {
 	// Get community space
-	communitySpace, err := h.spaceManager.GetCommunitySpace(ctx)
+	communitySpace, err := spaceManager.GetCommunitySpace(ctx)
 	if err != nil {
-		writeJSON(w, http.StatusOK, VerifyAccessResponse{HasAccess: false})
-		return
+		return VerifyAccessResponse{HasAccess: false}
 	}
 
-	client := h.spaceManager.GetClient()
+	client := spaceManager.GetClient()
 	if client == nil {
-		writeJSON(w, http.StatusOK, VerifyAccessResponse{HasAccess: false})
-		return
+		return VerifyAccessResponse{HasAccess: false}
 	}
 	dataDir := client.GetDataDir()
-	aclMgr := h.spaceManager.ACLManager()
+	aclMgr := spaceManager.ACLManager()
 
 	// Step 1: Check via space signing key (space creator/owner).
 	// The signing key is the identity recorded in the ACL root when the space
@@ -1015,36 +1157,33 @@ func (h *SpacesHandler) HandleVerifyAccess(w http.ResponseWriter, r *http.Reques
 	if spaceKeys, loadErr := anysync.LoadSpaceKeySet(dataDir, communitySpace.SpaceID); loadErr == nil {
 		perms, permErr := aclMgr.GetPermissions(ctx, communitySpace.SpaceID, spaceKeys.SigningKey.GetPublic())
 		if permErr == nil && !perms.NoPermissions() {
-			writeJSON(w, http.StatusOK, VerifyAccessResponse{
+			return VerifyAccessResponse{
 				HasAccess: true,
 				SpaceID:   communitySpace.SpaceID,
 				CanRead:   true,
 				CanWrite:  perms.CanWrite(),
-			})
-			return
+			}
 		}
 	}
 
 	// Step 2: Check via user peer key against ACL (joined member).
 	userPeerKey, err := anysync.LoadUserPeerKey(dataDir, aid)
 	if err != nil {
-		writeJSON(w, http.StatusOK, VerifyAccessResponse{HasAccess: false})
-		return
+		return VerifyAccessResponse{HasAccess: false}
 	}
 
 	perms, err := aclMgr.GetPermissions(ctx, communitySpace.SpaceID, userPeerKey.GetPublic())
 	if err != nil {
-		writeJSON(w, http.StatusOK, VerifyAccessResponse{HasAccess: false})
-		return
+		return VerifyAccessResponse{HasAccess: false}
 	}
 
 	hasAccess := !perms.NoPermissions()
-	writeJSON(w, http.StatusOK, VerifyAccessResponse{
+	return VerifyAccessResponse{
 		HasAccess: hasAccess,
 		SpaceID:   communitySpace.SpaceID,
 		CanRead:   hasAccess,
 		CanWrite:  perms.CanWrite(),
-	})
+	}
 }
 
 // handleVerifyAccessOrJoin routes /api/v1/spaces/community/verify-access
@@ -1096,6 +1235,7 @@ func (h *SpacesHandler) HandleCommunityReadOnlyInvite(w http.ResponseWriter, r *
 		})
 		return
 	}
+	h.events.Broadcast(aclChangedEvent(roSpaceID, ACLInviteCreated, ""))
 
 	inviteKeyBytes, err := inviteKey.Marshall()
 	if err != nil {
@@ -1180,16 +1320,144 @@ func (h *SpacesHandler) HandleSyncStatus(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// ListSpacesResponse is the response for GET /api/v1/spaces
+type ListSpacesResponse struct {
+	Spaces []*anysync.SpaceStatus `json:"spaces"`
+	Count  int                    `json:"count"`
+}
+
+// HandleListSpaces handles GET /api/v1/spaces. Lists every space this
+// backend knows of, from its space records and its local storage, with
+// head state, last sync time and coordinator status.
+func (h *SpacesHandler) HandleListSpaces(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	ctx := r.Context()
+	var records []*anysync.Space
+	if h.spaceStore != nil {
+		var err error
+		if records, err = h.spaceStore.ListAllSpaces(ctx); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+	}
+	spaces, err := h.spaceManager.ListSpaces(ctx, records)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, ListSpacesResponse{Spaces: spaces, Count: len(spaces)})
+}
+
+// handleSpace routes /api/v1/spaces/{id} and its join requests
+func (h *SpacesHandler) handleSpace(w http.ResponseWriter, r *http.Request) {
+	spaceID, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/v1/spaces/"), "/")
+	if spaceID != "" && (rest == "requests" || strings.HasPrefix(rest, "requests/")) {
+		h.handleJoinRequests(w, r, spaceID, strings.TrimPrefix(strings.TrimPrefix(rest, "requests"), "/"))
+		return
+	}
+	h.HandleDeleteSpace(w, r)
+}
+
+// HandleDeleteSpace handles DELETE /api/v1/spaces/{id}. The space is
+// deleted on the coordinator, its files unbound and its local storage
+// removed. With ?local=true only the local copy is removed.
+func (h *SpacesHandler) HandleDeleteSpace(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	spaceID := strings.TrimPrefix(r.URL.Path, "/api/v1/spaces/")
+	if spaceID == "" || strings.Contains(spaceID, "/") {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+	localOnly := r.URL.Query().Get("local") == "true"
+
+	result, err := h.spaceManager.DeleteSpace(r.Context(), spaceID, localOnly)
+	switch {
+	case errors.Is(err, anysync.ErrProtectedSpace):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+		return
+	case errors.Is(err, anysync.ErrSpaceRemovalUnsupported):
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+		return
+	case err != nil && result == nil:
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	case err != nil:
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if h.records != nil {
+		if err := h.records.DeleteSpaceRecord(r.Context(), spaceID); err != nil {
+			fmt.Printf("[Spaces] Warning: failed to delete space record %s: %v\n", spaceID, err)
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// SpaceGCResponse is the response for POST /api/v1/admin/spaces/gc
+type SpaceGCResponse struct {
+	Removed []string `json:"removed"`
+}
+
+// CollectSpaces removes the local storage of spaces deleted on the
+// coordinator, and their space records. Run on demand from the admin API
+// and on a schedule by the space collector.
+func (h *SpacesHandler) CollectSpaces(ctx context.Context) ([]string, error) {
+	removed, err := h.spaceManager.CollectGarbage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if h.records != nil {
+		for _, spaceID := range removed {
+			if err := h.records.DeleteSpaceRecord(ctx, spaceID); err != nil {
+				fmt.Printf("[Spaces] Warning: failed to delete space record %s: %v\n", spaceID, err)
+			}
+		}
+	}
+	if len(removed) > 0 {
+		fmt.Printf("[Spaces] Collected %d orphaned spaces\n", len(removed))
+	}
+	return removed, nil
+}
+
+// HandleCollectSpaces handles POST /api/v1/admin/spaces/gc
+func (h *SpacesHandler) HandleCollectSpaces(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	removed, err := h.CollectSpaces(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if removed == nil {
+		removed = []string{}
+	}
+	writeJSON(w, http.StatusOK, SpaceGCResponse{Removed: removed})
+}
+
 // RegisterRoutes registers space routes on the mux
 func (h *SpacesHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/spaces", h.HandleListSpaces)
 	mux.HandleFunc("/api/v1/spaces/community", h.handleCommunitySpace)
 	mux.HandleFunc("/api/v1/spaces/community/invite", h.HandleInvite)
 	mux.HandleFunc("/api/v1/spaces/community/join", h.HandleJoinCommunity)
+	mux.HandleFunc("/api/v1/spaces/join", h.HandleJoinSpace)
 	mux.HandleFunc("/api/v1/spaces/community/verify-access", h.handleVerifyAccess)
 	mux.HandleFunc("/api/v1/spaces/community-readonly/invite", h.HandleCommunityReadOnlyInvite)
 	mux.HandleFunc("/api/v1/spaces/private", h.HandleCreatePrivate)
 	mux.HandleFunc("/api/v1/spaces/user", h.HandleGetUserSpaces)
 	mux.HandleFunc("/api/v1/spaces/sync-status", h.HandleSyncStatus)
+	mux.HandleFunc("/api/v1/spaces/", h.handleSpace)
+	mux.HandleFunc("/api/v1/admin/spaces/gc", h.HandleCollectSpaces)
 }
 
 // truncateAID returns the first 12 characters of an AID for display purposes
@@ -20,7 +20,10 @@ import (
 	"github.com/anyproto/any-sync/net/pool"
 	"github.com/anyproto/any-sync/nodeconf"
 	"github.com/anyproto/any-sync/util/crypto"
+	"github.com/matou-dao/backend/internal/anystore"
 	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/secret"
 	"go.uber.org/mock/gomock"
 )
 
@@ -29,6 +32,7 @@ type mockAnySyncClient struct {
 	spaces         map[string]*anysync.SpaceCreateResult
 	createSpaceErr error
 	addToACLErr    error
+	aclGrants      []string // "spaceID:peerID" for each AddToACL call
 	networkID      string
 	coordinatorURL string
 	peerID         string
@@ -71,11 +75,15 @@ func (m *mockAnySyncClient) DeriveSpaceID(ctx context.Context, ownerAID string,
 }
 
 func (m *mockAnySyncClient) AddToACL(ctx context.Context, spaceID string, peerID string, permissions []string) error {
-	return m.addToACLErr
+	if m.addToACLErr != nil {
+		return m.addToACLErr
+	}
+	m.aclGrants = append(m.aclGrants, spaceID+":"+peerID)
+	return nil
 }
 
-func (m *mockAnySyncClient) SyncDocument(ctx context.Context, spaceID string, docID string, data []byte) error {
-	return nil
+func (m *mockAnySyncClient) SyncDocument(ctx context.Context, spaceID string, docID string, data []byte) ([]string, error) {
+	return nil, nil
 }
 
 func (m *mockAnySyncClient) GetNetworkID() string        { return m.networkID }
@@ -705,6 +713,7 @@ func TestSpacesHandler_RegisterRoutes(t *testing.T) {
 		{http.MethodGet, "/api/v1/spaces/community", http.StatusOK},
 		{http.MethodPost, "/api/v1/spaces/private", http.StatusBadRequest}, // No body
 		{http.MethodPost, "/api/v1/spaces/community/invite", http.StatusBadRequest}, // No body
+		{http.MethodPost, "/api/v1/spaces/join", http.StatusBadRequest},             // No body
 	}
 
 	for _, tc := range testCases {
@@ -760,3 +769,223 @@ func TestInviteRequest(t *testing.T) {
 		t.Errorf("Schema mismatch")
 	}
 }
+
+// setupJoinSpaceHandler returns a spaces handler with a local store and, when
+// aid is set, a user identity belonging to the org EORG123456789
+func setupJoinSpaceHandler(t *testing.T, aid string) (*SpacesHandler, *anystore.LocalStore) {
+	t.Helper()
+
+	handler, _, _ := setupTestSpacesHandler(t)
+	store, cleanup := setupTrustTestStore(t)
+	t.Cleanup(cleanup)
+	handler.store = store
+
+	userIdentity := identity.New(t.TempDir())
+	if aid != "" {
+		if err := userIdentity.SetIdentity(aid, secret.NewMnemonic("test mnemonic")); err != nil {
+			t.Fatalf("SetIdentity failed: %v", err)
+		}
+		if err := userIdentity.SetOrgConfig("EORG123456789", "test-community-space"); err != nil {
+			t.Fatalf("SetOrgConfig failed: %v", err)
+		}
+	}
+	handler.userIdentity = userIdentity
+	return handler, store
+}
+
+func postJoinSpace(handler *SpacesHandler, reqBody JoinSpaceRequest) (*httptest.ResponseRecorder, JoinSpaceResponse) {
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/spaces/join", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	handler.HandleJoinSpace(w, req)
+
+	var resp JoinSpaceResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	return w, resp
+}
+
+func TestHandleJoinSpace_MissingFields(t *testing.T) {
+	handler, _ := setupJoinSpaceHandler(t, "EUSER123456789")
+
+	w, resp := postJoinSpace(handler, JoinSpaceRequest{InviteKey: "a2V5"})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+	if resp.Success {
+		t.Error("expected success=false")
+	}
+}
+
+func TestHandleJoinSpace_NoIdentity(t *testing.T) {
+	handler, _ := setupJoinSpaceHandler(t, "")
+
+	w, _ := postJoinSpace(handler, JoinSpaceRequest{CredentialSAID: "ESAID123", InviteKey: "a2V5"})
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status 409, got %d", w.Code)
+	}
+}
+
+func TestHandleJoinSpace_CredentialValidation(t *testing.T) {
+	handler, store := setupJoinSpaceHandler(t, "EUSER123456789")
+	ctx := context.Background()
+
+	creds := []*anystore.CachedCredential{
+		{ID: "ESAID_OTHER_SCHEMA", IssuerAID: "EORG123456789", SubjectAID: "EUSER123456789", SchemaID: "EOperationsStewardSchemaV1"},
+		{ID: "ESAID_OTHER_SUBJECT", IssuerAID: "EORG123456789", SubjectAID: "EOTHER123456789", SchemaID: "EMatouMembershipSchemaV1"},
+		{ID: "ESAID_OTHER_ISSUER", IssuerAID: "EROGUE12345678", SubjectAID: "EUSER123456789", SchemaID: "EMatouMembershipSchemaV1"},
+	}
+	for _, cred := range creds {
+		if err := store.StoreCredential(ctx, cred); err != nil {
+			t.Fatalf("StoreCredential failed: %v", err)
+		}
+	}
+
+	for _, said := range []string{"ESAID_MISSING", "ESAID_OTHER_SCHEMA", "ESAID_OTHER_SUBJECT", "ESAID_OTHER_ISSUER"} {
+		w, resp := postJoinSpace(handler, JoinSpaceRequest{CredentialSAID: said, InviteKey: "a2V5"})
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s: expected status 403, got %d (%s)", said, w.Code, resp.Error)
+		}
+	}
+}
+
+func TestHandleJoinSpace_InvalidInviteKey(t *testing.T) {
+	handler, store := setupJoinSpaceHandler(t, "EUSER123456789")
+
+	if err := store.StoreCredential(context.Background(), &anystore.CachedCredential{
+		ID:         "ESAID123",
+		IssuerAID:  "EORG123456789",
+		SubjectAID: "EUSER123456789",
+		SchemaID:   "EMatouMembershipSchemaV1",
+	}); err != nil {
+		t.Fatalf("StoreCredential failed: %v", err)
+	}
+
+	// A valid credential gets as far as decoding the invite key
+	w, _ := postJoinSpace(handler, JoinSpaceRequest{CredentialSAID: "ESAID123", InviteKey: "not base64!"})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+// removingClient is a mock client that keeps spaces in local storage
+type removingClient struct {
+	*mockAnySyncClient
+	markErr  error
+	marked   []string
+	removed  []string
+	orphaned []string
+}
+
+func (m *removingClient) MarkSpaceDeleted(ctx context.Context, spaceID string) error {
+	if m.markErr != nil {
+		return m.markErr
+	}
+	m.marked = append(m.marked, spaceID)
+	return nil
+}
+
+func (m *removingClient) RemoveSpaceStorage(ctx context.Context, spaceID string) error {
+	m.removed = append(m.removed, spaceID)
+	return nil
+}
+
+func (m *removingClient) OrphanedSpaces(ctx context.Context) ([]string, error) {
+	return m.orphaned, nil
+}
+
+func TestHandleDeleteSpace(t *testing.T) {
+	client := &removingClient{mockAnySyncClient: newMockClient()}
+	handler := &SpacesHandler{
+		spaceManager: anysync.NewSpaceManager(client, &anysync.SpaceManagerConfig{
+			CommunitySpaceID: "test-community-space",
+		}),
+	}
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux)
+
+	do := func(method, path string) int {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w.Code
+	}
+
+	if code := do(http.MethodDelete, "/api/v1/spaces/project-space.1"); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+	if len(client.marked) != 1 || len(client.removed) != 1 {
+		t.Errorf("expected the space deleted on the coordinator and locally, got %v %v", client.marked, client.removed)
+	}
+
+	if code := do(http.MethodDelete, "/api/v1/spaces/cached-space.1?local=true"); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+	if len(client.marked) != 1 || len(client.removed) != 2 {
+		t.Errorf("expected only the local copy removed, got %v %v", client.marked, client.removed)
+	}
+
+	if code := do(http.MethodDelete, "/api/v1/spaces/test-community-space"); code != http.StatusConflict {
+		t.Errorf("expected status 409 for the community space, got %d", code)
+	}
+	client.markErr = fmt.Errorf("not the space owner")
+	if code := do(http.MethodDelete, "/api/v1/spaces/other-space.1"); code != http.StatusBadGateway {
+		t.Errorf("expected status 502 when the coordinator refuses, got %d", code)
+	}
+	if len(client.removed) != 2 {
+		t.Errorf("expected a space the coordinator kept left in place, got %v", client.removed)
+	}
+	if code := do(http.MethodGet, "/api/v1/spaces/project-space.1"); code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", code)
+	}
+
+	client.orphaned = []string{"deleted-space.1", "test-community-space"}
+	removed, err := handler.CollectSpaces(context.Background())
+	if err != nil {
+		t.Fatalf("CollectSpaces: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != "deleted-space.1" {
+		t.Errorf("expected only the deleted project space collected, got %v", removed)
+	}
+}
+
+func TestHandleDeleteSpace_Unsupported(t *testing.T) {
+	handler, _, _ := setupTestSpacesHandler(t)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/spaces/project-space.1", nil)
+	w := httptest.NewRecorder()
+	handler.HandleDeleteSpace(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 for a client without local storage, got %d", w.Code)
+	}
+}
+
+func TestHandleListSpaces(t *testing.T) {
+	handler, _, store := setupTestSpacesHandler(t)
+	store.spaces["private-space"] = &anysync.Space{
+		SpaceID:   "private-space",
+		OwnerAID:  "EUSER123",
+		SpaceType: anysync.SpaceTypePrivate,
+	}
+
+	w := httptest.NewRecorder()
+	handler.HandleListSpaces(w, httptest.NewRequest(http.MethodGet, "/api/v1/spaces", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var resp ListSpacesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	// The recorded private space and the configured community space
+	if resp.Count != 2 || len(resp.Spaces) != 2 {
+		t.Fatalf("expected 2 spaces, got %+v", resp)
+	}
+	if resp.Spaces[0].SpaceType != anysync.SpaceTypeCommunity || resp.Spaces[0].Recorded {
+		t.Errorf("expected the unrecorded community space first, got %+v", resp.Spaces[0])
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleListSpaces(w, httptest.NewRequest(http.MethodPost, "/api/v1/spaces", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+}
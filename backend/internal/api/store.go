@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+// errVacuumRunning is returned when a vacuum is requested while one runs
+var errVacuumRunning = errors.New("a vacuum is already running")
+
+// StoreHandler reports local store usage and vacuums it. The same vacuum
+// runs on demand from the admin API and on a schedule from the store
+// vacuumer; only one runs at a time.
+type StoreHandler struct {
+	store   *anystore.LocalStore
+	options anystore.VacuumOptions
+
+	running sync.Mutex
+	mu      sync.RWMutex
+	last    *anystore.VacuumResult
+}
+
+// NewStoreHandler creates a new store handler. opts sets how long prunable
+// data is kept.
+func NewStoreHandler(store *anystore.LocalStore, opts anystore.VacuumOptions) *StoreHandler {
+	return &StoreHandler{
+		store:   store,
+		options: opts,
+	}
+}
+
+// StoreStatsResponse is the response for GET /api/v1/admin/store/stats
+type StoreStatsResponse struct {
+	*anystore.StoreStats
+	LastVacuum *anystore.VacuumResult `json:"lastVacuum,omitempty"`
+}
+
+// Vacuum prunes the store with the configured retention. It returns
+// errVacuumRunning if another vacuum hasn't finished.
+func (h *StoreHandler) Vacuum(ctx context.Context) (*anystore.VacuumResult, error) {
+	if h.store == nil {
+		return nil, fmt.Errorf("local store not available")
+	}
+	if !h.running.TryLock() {
+		return nil, errVacuumRunning
+	}
+	defer h.running.Unlock()
+
+	result, err := h.store.Vacuum(ctx, h.options)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	h.last = result
+	h.mu.Unlock()
+
+	removed := 0
+	for _, n := range result.Removed {
+		removed += n
+	}
+	fmt.Printf("[Store] Vacuum removed %d documents (%d -> %d bytes, %d free)\n",
+		removed, result.SizeBefore, result.SizeAfter, result.FreeAfter)
+	return result, nil
+}
+
+// LastVacuum returns the result of the most recent vacuum, or nil
+func (h *StoreHandler) LastVacuum() *anystore.VacuumResult {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.last
+}
+
+// HandleStats handles GET /api/v1/admin/store/stats
+func (h *StoreHandler) HandleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if h.store == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "local store not available"})
+		return
+	}
+
+	stats, err := h.store.Stats(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, StoreStatsResponse{StoreStats: stats, LastVacuum: h.LastVacuum()})
+}
+
+// HandleVacuum handles POST /api/v1/admin/store/vacuum
+func (h *StoreHandler) HandleVacuum(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if h.store == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "local store not available"})
+		return
+	}
+
+	result, err := h.Vacuum(r.Context())
+	if errors.Is(err, errVacuumRunning) {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// RegisterRoutes registers store routes on the mux
+func (h *StoreHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/admin/store/stats", h.HandleStats)
+	mux.HandleFunc("/api/v1/admin/store/vacuum", h.HandleVacuum)
+}
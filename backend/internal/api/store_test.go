@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+func TestStoreHandler_NoStore(t *testing.T) {
+	handler := NewStoreHandler(nil, anystore.VacuumOptions{})
+
+	w := httptest.NewRecorder()
+	handler.HandleStats(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/store/stats", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleVacuum(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/store/vacuum", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestStoreHandler_VacuumAndStats(t *testing.T) {
+	store, err := anystore.NewLocalStore(anystore.DefaultConfig(t.TempDir()))
+	if err != nil {
+		t.Fatalf("failed to create anystore: %v", err)
+	}
+	defer store.Close()
+
+	now := time.Now().UTC()
+	if err := store.StoreCredential(context.Background(), &anystore.CachedCredential{
+		ID:        "ESAID-expired",
+		CachedAt:  now.Add(-48 * time.Hour),
+		ExpiresAt: now.Add(-time.Hour),
+	}); err != nil {
+		t.Fatalf("failed to store credential: %v", err)
+	}
+
+	handler := NewStoreHandler(store, anystore.VacuumOptions{})
+
+	w := httptest.NewRecorder()
+	handler.HandleVacuum(w, httptest.NewRequest(http.MethodPost, "/api/v1/admin/store/vacuum", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result anystore.VacuumResult
+	json.NewDecoder(w.Body).Decode(&result)
+	if result.Removed[anystore.CollectionCredentialsCache] != 1 {
+		t.Errorf("expected the expired credential removed, got %v", result.Removed)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleStats(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/store/stats", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var stats StoreStatsResponse
+	json.NewDecoder(w.Body).Decode(&stats)
+	if stats.StoreStats == nil || len(stats.Collections) != len(anystore.Collections()) {
+		t.Errorf("expected stats for every collection, got %+v", stats.StoreStats)
+	}
+	if stats.LastVacuum == nil {
+		t.Error("expected the last vacuum in stats")
+	}
+}
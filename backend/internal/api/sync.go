@@ -2,15 +2,21 @@ package api
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/matou-dao/backend/internal/anysync"
 	"github.com/matou-dao/backend/internal/anystore"
 	"github.com/matou-dao/backend/internal/identity"
 	"github.com/matou-dao/backend/internal/keri"
+	"github.com/matou-dao/backend/internal/trust"
 )
 
 // SyncHandler handles sync-related HTTP requests.
@@ -22,6 +28,8 @@ type SyncHandler struct {
 	spaceManager  *anysync.SpaceManager
 	spaceStore    anysync.SpaceStore
 	userIdentity  *identity.UserIdentity
+	presence      *PresenceTracker
+	events        *EventBroker
 }
 
 // NewSyncHandler creates a new sync handler
@@ -41,6 +49,18 @@ func NewSyncHandler(
 	}
 }
 
+// SetPresence attaches the presence tracker used to report member activity
+// in the community directory
+func (h *SyncHandler) SetPresence(p *PresenceTracker) {
+	h.presence = p
+}
+
+// SetEvents broadcasts credential:stored (and endorsement:synced) events
+// as credentials are synced
+func (h *SyncHandler) SetEvents(events *EventBroker) {
+	h.events = events
+}
+
 // SyncCredentialsRequest represents a credential sync request from frontend.
 // UserAID is optional in per-user mode (falls back to userIdentity).
 type SyncCredentialsRequest struct {
@@ -92,6 +112,8 @@ type CommunityMember struct {
 	Permissions        []string `json:"permissions"`
 	JoinedAt           string   `json:"joinedAt"`
 	CredentialSAID     string   `json:"credentialSaid"`
+	LastActiveAt       string   `json:"lastActiveAt,omitempty"`
+	Presence           string   `json:"presence,omitempty"` // active, recent or inactive
 }
 
 // CommunityMembersResponse represents the community members list
@@ -138,12 +160,20 @@ func (h *SyncHandler) HandleSyncCredentials(w http.ResponseWriter, r *http.Reque
 		})
 		return
 	}
+	if err := checkSignedAID(r, userAID); err != nil {
+		writeJSON(w, http.StatusForbidden, SyncCredentialsResponse{
+			Success: false,
+			Errors:  []string{err.Error()},
+		})
+		return
+	}
 
 	ctx := context.Background()
 	var errors []string
 	synced := 0
 	failed := 0
 	spaceSet := make(map[string]bool)
+	token := requestConsistencyToken(r)
 
 	// Get or create user's private space
 	privateSpace, err := h.spaceManager.GetOrCreatePrivateSpace(ctx, userAID, h.spaceStore)
@@ -179,6 +209,10 @@ func (h *SyncHandler) HandleSyncCredentials(w http.ResponseWriter, r *http.Reque
 			failed++
 			continue
 		}
+		token.AddCredentials(cred.SAID)
+		for _, event := range CredentialStoredEvents(cachedCred) {
+			h.events.Broadcast(event)
+		}
 
 		// Route credential to appropriate spaces
 		anysyncCred := &anysync.Credential{
@@ -228,6 +262,7 @@ func (h *SyncHandler) HandleSyncCredentials(w http.ResponseWriter, r *http.Reque
 		status = http.StatusBadRequest
 	}
 
+	setConsistencyToken(w, token)
 	writeJSON(w, status, resp)
 }
 
@@ -263,6 +298,13 @@ func (h *SyncHandler) HandleSyncKEL(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	if err := checkSignedAID(r, kelUserAID); err != nil {
+		writeJSON(w, http.StatusForbidden, SyncKELResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
 
 	if len(req.KEL) == 0 {
 		writeJSON(w, http.StatusBadRequest, SyncKELResponse{
@@ -285,21 +327,36 @@ func (h *SyncHandler) HandleSyncKEL(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Store KEL events in anystore
-	kelCollection, err := h.store.KELCache(ctx)
+	eventsStored, err := storeKELEvents(ctx, h.store, kelUserAID, req.KEL)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, SyncKELResponse{
 			Success: false,
-			Error:   fmt.Sprintf("failed to get KEL collection: %v", err),
+			Error:   err.Error(),
 		})
 		return
 	}
 
+	writeJSON(w, http.StatusOK, SyncKELResponse{
+		Success:      true,
+		EventsStored: eventsStored,
+		PrivateSpace: privateSpace.SpaceID,
+	})
+}
+
+// storeKELEvents caches an AID's KEL events in anystore, returning how many
+// were stored
+func storeKELEvents(ctx context.Context, store *anystore.LocalStore, aid string, events []KELEvent) (int, error) {
+	kelCollection, err := store.KELCache(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get KEL collection: %v", err)
+	}
+
 	eventsStored := 0
-	for _, event := range req.KEL {
+	for _, event := range events {
 		// Create KEL record
 		record := map[string]interface{}{
-			"id":        fmt.Sprintf("%s-%d", kelUserAID, event.Sequence),
-			"userAid":   kelUserAID,
+			"id":        fmt.Sprintf("%s-%d", aid, event.Sequence),
+			"userAid":   aid,
 			"type":      event.Type,
 			"sequence":  event.Sequence,
 			"digest":    event.Digest,
@@ -319,18 +376,15 @@ func (h *SyncHandler) HandleSyncKEL(w http.ResponseWriter, r *http.Request) {
 		}
 		eventsStored++
 	}
-
-	writeJSON(w, http.StatusOK, SyncKELResponse{
-		Success:      true,
-		EventsStored: eventsStored,
-		PrivateSpace: privateSpace.SpaceID,
-	})
+	return eventsStored, nil
 }
 
 // HandleGetCommunityMembers handles GET /api/v1/community/members
 // Returns all members with community-visible membership credentials.
 // Tries AnySync community space ObjectTree first (P2P synced data),
 // falls back to anystore cache if tree is not available.
+// Query params:
+//   - includeExpired: "true" to list members whose membership expired too
 func (h *SyncHandler) HandleGetCommunityMembers(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
@@ -338,16 +392,30 @@ func (h *SyncHandler) HandleGetCommunityMembers(w http.ResponseWriter, r *http.R
 		})
 		return
 	}
+	includeExpired := r.URL.Query().Get("includeExpired") == "true"
+	now := time.Now()
 
-	ctx := context.Background()
+	// Every store read below goes through one snapshot, so presence and
+	// cached credentials come from the same point in time
+	snapshot, err := h.store.Snapshot(context.Background(), anystore.CollectionMemberPresence, anystore.CollectionCredentialsCache)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer snapshot.Close()
+	ctx := snapshot.Context()
 	members := []CommunityMember{}
+	// Read before the credentials query below holds the store open
+	presence := h.memberPresence(ctx)
 
 	// Try reading from AnySync community space ObjectTree first
 	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
 	if communitySpaceID != "" {
 		treeMgr := h.spaceManager.CredentialTreeManager()
 		if treeMgr != nil {
-			creds, err := treeMgr.ReadCredentials(ctx, communitySpaceID)
+			creds, heads, err := treeMgr.ReadCredentialsAtHeads(ctx, communitySpaceID)
 			if err == nil && len(creds) > 0 {
 				for _, cred := range creds {
 					if cred.Schema != "EMatouMembershipSchemaV1" {
@@ -357,6 +425,9 @@ func (h *SyncHandler) HandleGetCommunityMembers(w http.ResponseWriter, r *http.R
 					if cred.Data != nil {
 						json.Unmarshal(cred.Data, &data)
 					}
+					if !includeExpired && data.Expired(now) {
+						continue
+					}
 					members = append(members, CommunityMember{
 						AID:                cred.Recipient,
 						Role:               data.Role,
@@ -366,6 +437,8 @@ func (h *SyncHandler) HandleGetCommunityMembers(w http.ResponseWriter, r *http.R
 						CredentialSAID:     cred.SAID,
 					})
 				}
+				h.annotatePresence(members, presence)
+				setDirectoryVersion(w, graphVersion(heads), snapshot.Revision)
 				writeJSON(w, http.StatusOK, CommunityMembersResponse{
 					Members: members,
 					Total:   len(members),
@@ -376,32 +449,16 @@ func (h *SyncHandler) HandleGetCommunityMembers(w http.ResponseWriter, r *http.R
 	}
 
 	// Fallback: query anystore cache
-	credCollection, err := h.store.CredentialsCache(ctx)
-	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{
-			"error": fmt.Sprintf("failed to get credentials collection: %v", err),
-		})
-		return
-	}
-
-	query := anystore.MustParseJSON(`{"schemaID": "EMatouMembershipSchemaV1"}`)
-	iter, err := credCollection.Find(query).Iter(ctx)
+	cachedCreds, err := h.store.GetCredentialsBySchema(ctx, "EMatouMembershipSchemaV1")
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{
 			"error": fmt.Sprintf("failed to query credentials: %v", err),
 		})
 		return
 	}
-	defer iter.Close()
 
-	for iter.Next() {
-		doc, err := iter.Doc()
-		if err != nil {
-			continue
-		}
-
-		var cached anystore.CachedCredential
-		if err := json.Unmarshal([]byte(doc.Value().String()), &cached); err != nil {
+	for _, cached := range cachedCreds {
+		if !includeExpired && trust.CredentialExpired(cached, now) {
 			continue
 		}
 
@@ -419,16 +476,64 @@ func (h *SyncHandler) HandleGetCommunityMembers(w http.ResponseWriter, r *http.R
 		})
 	}
 
+	h.annotatePresence(members, presence)
+	setDirectoryVersion(w, graphVersionCache, snapshot.Revision)
 	writeJSON(w, http.StatusOK, CommunityMembersResponse{
 		Members: members,
 		Total:   len(members),
 	})
 }
 
+// graphVersionCache is the graph version of a directory read from the
+// local cache rather than the community credential tree
+const graphVersionCache = "cache"
+
+// graphVersion identifies the community credential tree by its heads
+func graphVersion(heads []string) string {
+	sorted := append([]string(nil), heads...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, ",")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// setDirectoryVersion echoes the point in time a directory response was
+// read at: the credential tree's version and the store revision
+func setDirectoryVersion(w http.ResponseWriter, graph string, revision uint64) {
+	w.Header().Set("X-Graph-Version", graph)
+	w.Header().Set("X-Store-Revision", strconv.FormatUint(revision, 10))
+}
+
+// memberPresence returns the presence records keyed by AID, or nil when
+// presence isn't tracked
+func (h *SyncHandler) memberPresence(ctx context.Context) map[string]*anystore.MemberPresenceRecord {
+	if h.presence == nil {
+		return nil
+	}
+	records, err := h.presence.Records(ctx)
+	if err != nil {
+		fmt.Printf("[Sync] Failed to read member presence: %v\n", err)
+		return nil
+	}
+	return records
+}
+
+// annotatePresence fills in each member's last activity and presence status.
+// Members never seen active are left without either.
+func (h *SyncHandler) annotatePresence(members []CommunityMember, records map[string]*anystore.MemberPresenceRecord) {
+	for i := range members {
+		if record, ok := records[members[i].AID]; ok {
+			members[i].LastActiveAt = record.LastActiveAt.Format(time.RFC3339)
+			members[i].Presence = h.presence.Status(record.LastActiveAt)
+		}
+	}
+}
+
 // HandleGetCommunityCredentials handles GET /api/v1/community/credentials
 // Returns all community-visible credentials (memberships, roles).
 // Tries AnySync community space ObjectTree first (P2P synced data),
 // falls back to anystore cache if tree is not available.
+// Query params:
+//   - includeExpired: "true" to list expired credentials too
 func (h *SyncHandler) HandleGetCommunityCredentials(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
@@ -438,6 +543,8 @@ func (h *SyncHandler) HandleGetCommunityCredentials(w http.ResponseWriter, r *ht
 	}
 
 	ctx := context.Background()
+	includeExpired := r.URL.Query().Get("includeExpired") == "true"
+	now := time.Now()
 	credentials := []keri.Credential{}
 
 	// Try reading from AnySync community space ObjectTree first
@@ -456,6 +563,9 @@ func (h *SyncHandler) HandleGetCommunityCredentials(w http.ResponseWriter, r *ht
 					if cred.Data != nil {
 						json.Unmarshal(cred.Data, &data)
 					}
+					if !includeExpired && data.Expired(now) {
+						continue
+					}
 					credentials = append(credentials, keri.Credential{
 						SAID:      cred.SAID,
 						Issuer:    cred.Issuer,
@@ -508,6 +618,9 @@ func (h *SyncHandler) HandleGetCommunityCredentials(w http.ResponseWriter, r *ht
 		if !anysync.IsCommunityVisible(anysyncCred) {
 			continue
 		}
+		if !includeExpired && trust.CredentialExpired(&cached, now) {
+			continue
+		}
 
 		// Convert to keri.Credential
 		var data keri.CredentialData
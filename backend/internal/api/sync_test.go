@@ -62,8 +62,8 @@ func (m *mockSyncAnySyncClient) AddToACL(ctx context.Context, spaceID string, pe
 	return nil
 }
 
-func (m *mockSyncAnySyncClient) SyncDocument(ctx context.Context, spaceID string, docID string, data []byte) error {
-	return nil
+func (m *mockSyncAnySyncClient) SyncDocument(ctx context.Context, spaceID string, docID string, data []byte) ([]string, error) {
+	return nil, nil
 }
 
 func (m *mockSyncAnySyncClient) GetNetworkID() string        { return "test-network" }
@@ -645,6 +645,42 @@ func TestHandleGetCommunityMembers_WithMembers(t *testing.T) {
 	if resp.Members[0].AID != "EUSER123" {
 		t.Errorf("expected AID EUSER123, got %s", resp.Members[0].AID)
 	}
+	if v := w.Header().Get("X-Graph-Version"); v != graphVersionCache {
+		t.Errorf("expected graph version %q for a cached directory, got %q", graphVersionCache, v)
+	}
+	if v := w.Header().Get("X-Store-Revision"); v != "1" {
+		t.Errorf("expected store revision 1, got %q", v)
+	}
+}
+
+func TestHandleGetCommunityMembers_ExcludesExpired(t *testing.T) {
+	handler, store, cleanup := setupSyncTestHandler(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, cred := range []*anystore.CachedCredential{
+		{ID: "ESAID001", IssuerAID: "EAID123456789", SubjectAID: "EUSER1", SchemaID: "EMatouMembershipSchemaV1",
+			Data: map[string]interface{}{"role": "Member"}},
+		{ID: "ESAID002", IssuerAID: "EAID123456789", SubjectAID: "EUSER2", SchemaID: "EMatouMembershipSchemaV1",
+			Data: map[string]interface{}{"role": "Member", "expiresAt": "2026-01-01T00:00:00Z"}},
+	} {
+		if err := store.StoreCredential(ctx, cred); err != nil {
+			t.Fatalf("failed to store credential: %v", err)
+		}
+	}
+
+	for query, want := range map[string]int{"": 1, "?includeExpired=true": 2} {
+		w := httptest.NewRecorder()
+		handler.HandleGetCommunityMembers(w, httptest.NewRequest(http.MethodGet, "/api/v1/community/members"+query, nil))
+
+		var resp CommunityMembersResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.Total != want {
+			t.Errorf("expected %d members for %q, got %d", want, query, resp.Total)
+		}
+	}
 }
 
 func TestHandleGetCommunityMembers_MethodNotAllowed(t *testing.T) {
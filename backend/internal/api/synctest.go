@@ -0,0 +1,318 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/metrics"
+)
+
+// SyncProbeType is the object type written by sync tests
+const SyncProbeType = "SyncProbe"
+
+const (
+	// maxSyncTestTimeout caps the per-request timeout so a test always
+	// finishes inside the route's request deadline
+	maxSyncTestTimeout = 90 * time.Second
+	syncProbeInterval  = 250 * time.Millisecond
+)
+
+// SyncTestHandler measures end-to-end sync latency: it writes a probe object
+// to a space and times how long it takes to reach each of the space's
+// responsible tree nodes and, when configured, another backend.
+type SyncTestHandler struct {
+	spaceManager *anysync.SpaceManager
+	peerURL      string
+	timeout      time.Duration
+	httpClient   *http.Client
+	now          func() time.Time
+	interval     time.Duration
+}
+
+// NewSyncTestHandler creates a new sync test handler. peerURL is another
+// backend's base URL, or empty to only test propagation to tree nodes.
+func NewSyncTestHandler(spaceManager *anysync.SpaceManager, peerURL string, timeout time.Duration) *SyncTestHandler {
+	if timeout <= 0 || timeout > maxSyncTestTimeout {
+		timeout = maxSyncTestTimeout
+	}
+	return &SyncTestHandler{
+		spaceManager: spaceManager,
+		peerURL:      strings.TrimSuffix(peerURL, "/"),
+		timeout:      timeout,
+		httpClient:   &http.Client{Timeout: 5 * time.Second},
+		now:          time.Now,
+		interval:     syncProbeInterval,
+	}
+}
+
+// SyncProbe is the data of a SyncProbe object
+type SyncProbe struct {
+	ProbeID string `json:"probeId"`
+	SentAt  string `json:"sentAt"`
+}
+
+// SyncTestRequest is the body for POST /api/v1/admin/sync-test
+type SyncTestRequest struct {
+	SpaceID        string `json:"spaceId,omitempty"`        // Defaults to the community space
+	TimeoutSeconds int    `json:"timeoutSeconds,omitempty"` // Defaults to the configured timeout
+}
+
+// SyncTestTarget is the propagation result for one node or peer backend
+type SyncTestTarget struct {
+	PeerID    string `json:"peerId,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Synced    bool   `json:"synced"`
+	LatencyMs int64  `json:"latencyMs,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// SyncTestResponse is the response for POST /api/v1/admin/sync-test
+type SyncTestResponse struct {
+	Success  bool              `json:"success"`
+	ProbeID  string            `json:"probeId"`
+	SpaceID  string            `json:"spaceId"`
+	ObjectID string            `json:"objectId"`
+	TreeID   string            `json:"treeId"`
+	Heads    []string          `json:"heads"`
+	WriteMs  int64             `json:"writeMs"`
+	TotalMs  int64             `json:"totalMs"`
+	Nodes    []*SyncTestTarget `json:"nodes"`
+	Peer     *SyncTestTarget   `json:"peer,omitempty"`
+}
+
+// HandleSyncTest handles POST /api/v1/admin/sync-test
+// Writes a probe to the space and waits until every responsible node reports
+// the tree at the probe's heads, and the peer backend (if configured) can
+// read the probe. Success is false if any target missed the timeout.
+func (h *SyncTestHandler) HandleSyncTest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	var req SyncTestRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("invalid request body: %v", err),
+			})
+			return
+		}
+	}
+
+	spaceID := req.SpaceID
+	if spaceID == "" {
+		spaceID = h.spaceManager.GetCommunitySpaceID()
+	}
+	if spaceID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "spaceId is required when no community space is configured",
+		})
+		return
+	}
+
+	client := h.spaceManager.GetClient()
+	if client == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "any-sync client not available",
+		})
+		return
+	}
+
+	timeout := h.timeout
+	if req.TimeoutSeconds > 0 {
+		timeout = min(time.Duration(req.TimeoutSeconds)*time.Second, maxSyncTestTimeout)
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	start := h.now()
+	probe := SyncProbe{
+		ProbeID: fmt.Sprintf("%s-%d", client.GetPeerID(), start.UnixNano()),
+		SentAt:  start.UTC().Format(time.RFC3339Nano),
+	}
+	objectID := fmt.Sprintf("%s-%s", SyncProbeType, client.GetPeerID())
+	if _, err := writeObject(ctx, h.spaceManager, spaceID, objectID, SyncProbeType, probe); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to write probe: %v", err),
+		})
+		return
+	}
+	treeID, heads, err := h.spaceManager.ObjectTreeManager().TreeHeads(ctx, spaceID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read probe heads: %v", err),
+		})
+		return
+	}
+	written := h.now()
+
+	resp := &SyncTestResponse{
+		ProbeID:  probe.ProbeID,
+		SpaceID:  spaceID,
+		ObjectID: objectID,
+		TreeID:   treeID,
+		Heads:    heads,
+		WriteMs:  written.Sub(start).Milliseconds(),
+	}
+
+	var wg sync.WaitGroup
+	for _, peerID := range anysync.ResponsibleNodes(client, spaceID) {
+		target := &SyncTestTarget{PeerID: peerID}
+		resp.Nodes = append(resp.Nodes, target)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.await(ctx, target, start, "node", func() (bool, error) {
+				return anysync.NodeHasHeads(ctx, client, peerID, spaceID, treeID, heads)
+			})
+		}()
+	}
+	if h.peerURL != "" {
+		resp.Peer = &SyncTestTarget{URL: h.peerURL}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.await(ctx, resp.Peer, start, "peer", func() (bool, error) {
+				return h.peerHasProbe(ctx, spaceID, objectID, probe.ProbeID)
+			})
+		}()
+	}
+	wg.Wait()
+
+	resp.Success = len(resp.Nodes) > 0
+	for _, target := range resp.Nodes {
+		resp.Success = resp.Success && target.Synced
+	}
+	if resp.Peer != nil {
+		resp.Success = resp.Success && resp.Peer.Synced
+	}
+	resp.TotalMs = h.now().Sub(start).Milliseconds()
+
+	fmt.Printf("[SyncTest] Probe %s in space %s: success=%t write=%dms total=%dms\n",
+		probe.ProbeID, spaceID, resp.Success, resp.WriteMs, resp.TotalMs)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// await polls check until it reports the probe or ctx expires, recording the
+// latency from start on success and the last error otherwise
+func (h *SyncTestHandler) await(ctx context.Context, target *SyncTestTarget, start time.Time, kind string, check func() (bool, error)) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		synced, err := check()
+		if synced {
+			latency := h.now().Sub(start)
+			target.Synced = true
+			target.LatencyMs = latency.Milliseconds()
+			target.Error = ""
+			metrics.ObserveSyncProbe(kind, latency)
+			return
+		}
+		// A check cut short by the deadline says nothing about the target
+		if err != nil && ctx.Err() == nil {
+			target.Error = err.Error()
+		}
+
+		select {
+		case <-ctx.Done():
+			if target.Error == "" {
+				target.Error = "timed out waiting for probe"
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// peerHasProbe asks the peer backend whether it has received the probe
+func (h *SyncTestHandler) peerHasProbe(ctx context.Context, spaceID, objectID, probeID string) (bool, error) {
+	probeURL := fmt.Sprintf("%s/api/v1/admin/sync-test/probes/%s?spaceId=%s",
+		h.peerURL, url.PathEscape(objectID), url.QueryEscape(spaceID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probeURL, nil)
+	if err != nil {
+		return false, err
+	}
+	res, err := h.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("querying peer: %w", err)
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("peer returned HTTP %d", res.StatusCode)
+	}
+
+	var probe SyncProbe
+	if err := json.NewDecoder(res.Body).Decode(&probe); err != nil {
+		return false, fmt.Errorf("decoding peer probe: %w", err)
+	}
+	return probe.ProbeID == probeID, nil
+}
+
+// HandleGetProbe handles GET /api/v1/admin/sync-test/probes/{objectId}?spaceId=
+// Returns the latest version of a sync probe as seen by this backend, so a
+// peer running a sync test can tell when its probe has arrived.
+func (h *SyncTestHandler) HandleGetProbe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	objectID := strings.TrimPrefix(r.URL.Path, "/api/v1/admin/sync-test/probes/")
+	if !strings.HasPrefix(objectID, SyncProbeType+"-") || strings.Contains(objectID, "/") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "invalid probe object ID",
+		})
+		return
+	}
+	spaceID := r.URL.Query().Get("spaceId")
+	if spaceID == "" {
+		spaceID = h.spaceManager.GetCommunitySpaceID()
+	}
+	if spaceID == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "spaceId is required",
+		})
+		return
+	}
+
+	obj, err := h.spaceManager.ObjectTreeManager().ReadLatestByID(r.Context(), spaceID, objectID)
+	if err != nil || obj.Type != SyncProbeType {
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			"error": "probe not found",
+		})
+		return
+	}
+
+	var probe SyncProbe
+	if err := json.Unmarshal(obj.Data, &probe); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("invalid probe data: %v", err),
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, probe)
+}
+
+// RegisterRoutes registers sync test routes on the mux
+func (h *SyncTestHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/admin/sync-test", h.HandleSyncTest)
+	mux.HandleFunc("/api/v1/admin/sync-test/probes/", h.HandleGetProbe)
+}
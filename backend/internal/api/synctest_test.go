@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anysync"
+)
+
+func setupSyncTest(t *testing.T, peerURL string) *SyncTestHandler {
+	t.Helper()
+	spaceManager := anysync.NewSpaceManager(newMockSyncAnySyncClient(), &anysync.SpaceManagerConfig{
+		CommunitySpaceID: "space-community-test",
+		OrgAID:           "EORG123456789",
+	})
+	return NewSyncTestHandler(spaceManager, peerURL, 5*time.Second)
+}
+
+func TestHandleSyncTest_MethodNotAllowed(t *testing.T) {
+	handler := setupSyncTest(t, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/sync-test", nil)
+	w := httptest.NewRecorder()
+	handler.HandleSyncTest(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+}
+
+func TestHandleSyncTest_ProbeWriteFails(t *testing.T) {
+	handler := setupSyncTest(t, "")
+
+	// The mock client has no keys for the community space
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/sync-test", strings.NewReader(`{"timeoutSeconds": 1}`))
+	w := httptest.NewRecorder()
+	handler.HandleSyncTest(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "failed to write probe") {
+		t.Errorf("expected probe write error, got %s", w.Body.String())
+	}
+}
+
+func TestHandleGetProbe_Validation(t *testing.T) {
+	handler := setupSyncTest(t, "")
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/api/v1/admin/sync-test/probes/Profile-EUSER123", http.StatusBadRequest},
+		{"/api/v1/admin/sync-test/probes/SyncProbe-peer/other", http.StatusBadRequest},
+		{"/api/v1/admin/sync-test/probes/SyncProbe-peer", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		handler.HandleGetProbe(w, req)
+		if w.Code != tt.want {
+			t.Errorf("GET %s: expected status %d, got %d", tt.path, tt.want, w.Code)
+		}
+	}
+}
+
+func TestSyncTest_AwaitPeer(t *testing.T) {
+	// The peer sees an older probe first, then ours
+	var calls atomic.Int32
+	peer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/admin/sync-test/probes/SyncProbe-peerA" || r.URL.Query().Get("spaceId") != "space-1" {
+			http.NotFound(w, r)
+			return
+		}
+		probeID := "probe-old"
+		if calls.Add(1) >= 3 {
+			probeID = "probe-new"
+		}
+		json.NewEncoder(w).Encode(SyncProbe{ProbeID: probeID})
+	}))
+	defer peer.Close()
+
+	handler := setupSyncTest(t, peer.URL+"/")
+	handler.interval = time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	target := &SyncTestTarget{URL: handler.peerURL}
+	handler.await(ctx, target, time.Now(), "peer", func() (bool, error) {
+		return handler.peerHasProbe(ctx, "space-1", "SyncProbe-peerA", "probe-new")
+	})
+
+	if !target.Synced || target.Error != "" {
+		t.Errorf("expected peer to sync, got %+v", target)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 polls, got %d", calls.Load())
+	}
+}
+
+func TestSyncTest_AwaitTimeout(t *testing.T) {
+	peer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer peer.Close()
+
+	handler := setupSyncTest(t, peer.URL)
+	handler.interval = time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	target := &SyncTestTarget{URL: handler.peerURL}
+	handler.await(ctx, target, time.Now(), "peer", func() (bool, error) {
+		return handler.peerHasProbe(ctx, "space-1", "SyncProbe-peerA", "probe-new")
+	})
+
+	if target.Synced {
+		t.Error("expected peer not to sync")
+	}
+	if !strings.Contains(target.Error, "HTTP 502") {
+		t.Errorf("expected last peer error to be reported, got %q", target.Error)
+	}
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/taxonomy"
+)
+
+// TaxonomyHandler serves the community's skills and interests vocabularies.
+// Vocabularies are stored as Taxonomy objects in the community read-only
+// space, so only admins (who hold its write keys) can change them.
+type TaxonomyHandler struct {
+	spaceManager *anysync.SpaceManager
+	userIdentity *identity.UserIdentity
+}
+
+// NewTaxonomyHandler creates a new taxonomy handler
+func NewTaxonomyHandler(spaceManager *anysync.SpaceManager, userIdentity *identity.UserIdentity) *TaxonomyHandler {
+	return &TaxonomyHandler{
+		spaceManager: spaceManager,
+		userIdentity: userIdentity,
+	}
+}
+
+// UpdateTaxonomyRequest is the body for PUT /api/v1/taxonomy/{kind}
+type UpdateTaxonomyRequest struct {
+	Terms []taxonomy.Term `json:"terms"`
+}
+
+// Vocabulary returns the stored vocabulary for kind, or nil if none is set
+func (h *TaxonomyHandler) Vocabulary(ctx context.Context, kind string) *taxonomy.Vocabulary {
+	if h == nil || h.spaceManager == nil {
+		return nil
+	}
+	roSpaceID := h.spaceManager.GetCommunityReadOnlySpaceID()
+	if roSpaceID == "" {
+		return nil
+	}
+	obj, err := h.spaceManager.ObjectTreeManager().ReadLatestByID(ctx, roSpaceID, "Taxonomy-"+kind)
+	if err != nil {
+		return nil
+	}
+	var vocab taxonomy.Vocabulary
+	if err := json.Unmarshal(obj.Data, &vocab); err != nil {
+		return nil
+	}
+	return &vocab
+}
+
+// HandleGet handles GET /api/v1/taxonomy
+// Query params:
+//   - kind: Return a single vocabulary (skills, interests)
+func (h *TaxonomyHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	ctx := r.Context()
+	if kind := r.URL.Query().Get("kind"); kind != "" {
+		if !taxonomy.IsKind(kind) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("unknown kind: %s", kind)})
+			return
+		}
+		writeJSON(w, http.StatusOK, h.vocabularyOrEmpty(ctx, kind))
+		return
+	}
+
+	vocabularies := make(map[string]*taxonomy.Vocabulary)
+	for _, kind := range taxonomy.Kinds() {
+		vocabularies[kind] = h.vocabularyOrEmpty(ctx, kind)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"vocabularies": vocabularies,
+	})
+}
+
+// HandleUpdate handles PUT /api/v1/taxonomy/{kind} — replace a vocabulary
+func (h *TaxonomyHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	kind := strings.TrimPrefix(r.URL.Path, "/api/v1/taxonomy/")
+	if !taxonomy.IsKind(kind) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("unknown kind: %s", kind)})
+		return
+	}
+
+	var req UpdateTaxonomyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+
+	vocab := &taxonomy.Vocabulary{
+		Kind:      kind,
+		Terms:     req.Terms,
+		UpdatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if h.userIdentity != nil {
+		vocab.UpdatedBy = h.userIdentity.GetAID()
+	}
+	if err := vocab.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	vocab.Sort()
+
+	roSpaceID := h.spaceManager.GetCommunityReadOnlySpaceID()
+	if roSpaceID == "" {
+		writeJSON(w, http.StatusConflict, map[string]string{
+			"error": "community-readonly space not configured",
+		})
+		return
+	}
+
+	if _, err := writeObject(r.Context(), h.spaceManager, roSpaceID, "Taxonomy-"+kind, "Taxonomy", vocab); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	fmt.Printf("[Taxonomy] Updated %s vocabulary (%d terms)\n", kind, len(vocab.Terms))
+	writeJSON(w, http.StatusOK, vocab)
+}
+
+// NormalizeProfileTags resolves SharedProfile skills and interests against
+// the configured vocabularies, rewriting synonyms to canonical IDs. Returns
+// validation errors for unknown tags. Kinds without a vocabulary are left
+// free-form.
+func (h *TaxonomyHandler) NormalizeProfileTags(ctx context.Context, data json.RawMessage) (json.RawMessage, []string) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return data, nil // type validation reports malformed data
+	}
+
+	var errs []string
+	changed := false
+	for _, kind := range taxonomy.Kinds() {
+		field := taxonomy.ProfileField(kind)
+		raw, ok := m[field].([]interface{})
+		if !ok || len(raw) == 0 {
+			continue
+		}
+		vocab := h.Vocabulary(ctx, kind)
+		if vocab == nil || len(vocab.Terms) == 0 {
+			continue
+		}
+
+		tags := make([]string, 0, len(raw))
+		for _, v := range raw {
+			if s, ok := v.(string); ok {
+				tags = append(tags, s)
+			}
+		}
+		canonical, unknown := vocab.Normalize(tags)
+		for _, tag := range unknown {
+			errs = append(errs, fmt.Sprintf("field %q: unknown %s tag %q", field, kind, tag))
+		}
+		m[field] = canonical
+		changed = true
+	}
+
+	if len(errs) > 0 || !changed {
+		return data, errs
+	}
+	normalized, err := json.Marshal(m)
+	if err != nil {
+		return data, nil
+	}
+	return normalized, nil
+}
+
+// vocabularyOrEmpty returns the stored vocabulary or an empty one
+func (h *TaxonomyHandler) vocabularyOrEmpty(ctx context.Context, kind string) *taxonomy.Vocabulary {
+	if vocab := h.Vocabulary(ctx, kind); vocab != nil {
+		return vocab
+	}
+	return &taxonomy.Vocabulary{Kind: kind, Terms: []taxonomy.Term{}}
+}
+
+// RegisterRoutes registers taxonomy routes on the mux
+func (h *TaxonomyHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/taxonomy", h.HandleGet)
+	mux.HandleFunc("/api/v1/taxonomy/", h.HandleUpdate)
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTaxonomy_GetWithoutVocabularies(t *testing.T) {
+	handler := NewTaxonomyHandler(nil, nil)
+
+	w := httptest.NewRecorder()
+	handler.HandleGet(w, httptest.NewRequest(http.MethodGet, "/api/v1/taxonomy", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp struct {
+		Vocabularies map[string]struct {
+			Kind  string        `json:"kind"`
+			Terms []interface{} `json:"terms"`
+		} `json:"vocabularies"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.Vocabularies) != 2 {
+		t.Errorf("expected skills and interests vocabularies, got %v", resp.Vocabularies)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleGet(w, httptest.NewRequest(http.MethodGet, "/api/v1/taxonomy?kind=colours", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown kind, got %d", w.Code)
+	}
+}
+
+func TestTaxonomy_UpdateValidation(t *testing.T) {
+	handler := NewTaxonomyHandler(nil, nil)
+
+	w := httptest.NewRecorder()
+	handler.HandleUpdate(w, httptest.NewRequest(http.MethodPut, "/api/v1/taxonomy/colours", strings.NewReader(`{"terms":[]}`)))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown kind, got %d", w.Code)
+	}
+
+	body := `{"terms":[{"id":"a","synonyms":["design"]},{"id":"b","synonyms":["Design"]}]}`
+	w = httptest.NewRecorder()
+	handler.HandleUpdate(w, httptest.NewRequest(http.MethodPut, "/api/v1/taxonomy/skills", strings.NewReader(body)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for conflicting synonyms, got %d", w.Code)
+	}
+}
+
+func TestTaxonomy_NormalizeWithoutVocabularyIsFreeForm(t *testing.T) {
+	handler := NewTaxonomyHandler(nil, nil)
+
+	data := json.RawMessage(`{"skills":["anything goes"]}`)
+	out, errs := handler.NormalizeProfileTags(httptest.NewRequest(http.MethodGet, "/", nil).Context(), data)
+	if len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+	if string(out) != string(data) {
+		t.Errorf("expected data unchanged, got %s", out)
+	}
+}
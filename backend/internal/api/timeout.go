@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RouteTimeouts resolves per-request deadline budgets by path prefix.
+type RouteTimeouts struct {
+	defaultBudget time.Duration
+	routes        map[string]time.Duration
+}
+
+// NewRouteTimeouts creates a resolver with a default budget and per-prefix
+// overrides. A zero budget means the route has no deadline.
+func NewRouteTimeouts(defaultBudget time.Duration, routes map[string]time.Duration) *RouteTimeouts {
+	copied := make(map[string]time.Duration, len(routes))
+	for prefix, budget := range routes {
+		copied[prefix] = budget
+	}
+	return &RouteTimeouts{
+		defaultBudget: defaultBudget,
+		routes:        copied,
+	}
+}
+
+// BudgetFor returns the deadline budget for a request path.
+// The longest matching prefix wins; otherwise the default applies.
+func (t *RouteTimeouts) BudgetFor(path string) time.Duration {
+	budget := t.defaultBudget
+	longest := -1
+	for prefix, b := range t.routes {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			budget = b
+			longest = len(prefix)
+		}
+	}
+	return budget
+}
+
+// TimeoutMiddleware applies the route budget to each request. The request
+// context gets a deadline so handlers and downstream calls observe it, and
+// the connection's read/write deadlines are moved to match so routes can
+// run longer (or shorter) than the server-wide timeouts. A zero budget
+// clears the connection deadlines, which long-lived streams like SSE need.
+func TimeoutMiddleware(timeouts *RouteTimeouts, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		budget := timeouts.BudgetFor(r.URL.Path)
+		rc := http.NewResponseController(w)
+
+		if budget <= 0 {
+			// Errors mean the writer doesn't support deadlines; nothing to clear
+			_ = rc.SetReadDeadline(time.Time{})
+			_ = rc.SetWriteDeadline(time.Time{})
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		deadline := time.Now().Add(budget)
+		_ = rc.SetReadDeadline(deadline)
+		// Leave a little room after the context deadline to write the error response
+		_ = rc.SetWriteDeadline(deadline.Add(time.Second))
+
+		ctx, cancel := context.WithDeadline(r.Context(), deadline)
+		defer cancel()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRouteTimeouts_BudgetFor(t *testing.T) {
+	timeouts := NewRouteTimeouts(30*time.Second, map[string]time.Duration{
+		"/api/v1/events":       0,
+		"/api/v1/spaces/":      2 * time.Minute,
+		"/api/v1/spaces/user":  5 * time.Second,
+		"/api/v1/files/upload": time.Minute,
+	})
+
+	tests := []struct {
+		path string
+		want time.Duration
+	}{
+		{"/api/v1/credentials", 30 * time.Second},
+		{"/api/v1/events", 0},
+		{"/api/v1/spaces/community", 2 * time.Minute},
+		{"/api/v1/spaces/user", 5 * time.Second},
+		{"/api/v1/files/upload", time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := timeouts.BudgetFor(tt.path); got != tt.want {
+			t.Errorf("BudgetFor(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestTimeoutMiddleware_SetsContextDeadline(t *testing.T) {
+	timeouts := NewRouteTimeouts(10*time.Second, map[string]time.Duration{
+		"/api/v1/events": 0,
+	})
+
+	var hasDeadline bool
+	handler := TimeoutMiddleware(timeouts, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline = r.Context().Deadline()
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/trust/summary", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if !hasDeadline {
+		t.Error("expected request context to carry a deadline")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/events", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if hasDeadline {
+		t.Error("expected no deadline for zero-budget route")
+	}
+}
@@ -0,0 +1,352 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// serviceTokenPrefix marks a service token, so the authenticator can tell
+// one apart from API keys and AID tokens without trying each
+const serviceTokenPrefix = "mst_"
+
+// ScopeAdmin grants every route, with admin rights
+const ScopeAdmin = "admin"
+
+// scopePattern matches "{area}:read" and "{area}:write", where area is the
+// first path segment after /api/v1/ (or after / for other routes)
+var scopePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*:(read|write)$`)
+
+// maxServiceTokenTTL bounds how long a token can be issued for
+const maxServiceTokenTTL = 365 * 24 * time.Hour
+
+// ServiceToken is a token issued to an integration (CI bot, bridge) that
+// can't sign AID tokens. Only a hash of the secret is kept.
+type ServiceToken struct {
+	ID        string     `json:"id" yaml:"id"`
+	Name      string     `json:"name" yaml:"name"`
+	Scopes    []string   `json:"scopes" yaml:"scopes"`
+	Hash      string     `json:"-" yaml:"hash"` // hex SHA-256 of the secret
+	CreatedBy string     `json:"createdBy,omitempty" yaml:"createdBy,omitempty"`
+	CreatedAt time.Time  `json:"createdAt" yaml:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty" yaml:"revokedAt,omitempty"`
+}
+
+// activeAt reports whether the token can still be used
+func (t *ServiceToken) activeAt(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
+
+// ValidateScopes checks a token's scopes are well formed and not repeated
+func ValidateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+	seen := make(map[string]bool)
+	for _, s := range scopes {
+		if s != ScopeAdmin && !scopePattern.MatchString(s) {
+			return fmt.Errorf("invalid scope %q (expected admin, {area}:read or {area}:write)", s)
+		}
+		if seen[s] {
+			return fmt.Errorf("duplicate scope %q", s)
+		}
+		seen[s] = true
+	}
+	return nil
+}
+
+// scopeArea returns the area a path belongs to: "trust" for
+// /api/v1/trust/graph, "health" for /health
+func scopeArea(path string) string {
+	rest := strings.TrimPrefix(path, "/")
+	if after, ok := strings.CutPrefix(rest, "api/v1/"); ok {
+		rest = after
+	}
+	area, _, _ := strings.Cut(rest, "/")
+	return area
+}
+
+// ScopesAllow reports whether scopes cover a request. A write scope also
+// allows reads in its area.
+func ScopesAllow(scopes []string, method, path string) bool {
+	area := scopeArea(path)
+	read := method == http.MethodGet || method == http.MethodHead
+	for _, s := range scopes {
+		if s == ScopeAdmin {
+			return true
+		}
+		granted, access, _ := strings.Cut(s, ":")
+		if granted == area && (access == "write" || read) {
+			return true
+		}
+	}
+	return false
+}
+
+// ServiceTokens stores issued service tokens in dataDir/api-tokens.yaml
+type ServiceTokens struct {
+	mu     sync.RWMutex
+	path   string
+	tokens map[string]*ServiceToken
+	now    func() time.Time
+}
+
+// NewServiceTokens loads the tokens saved in dataDir. An empty dataDir
+// keeps tokens in memory only.
+func NewServiceTokens(dataDir string) *ServiceTokens {
+	s := &ServiceTokens{
+		tokens: make(map[string]*ServiceToken),
+		now:    time.Now,
+	}
+	if dataDir != "" {
+		s.path = filepath.Join(dataDir, "api-tokens.yaml")
+		s.loadFromDisk()
+	}
+	return s
+}
+
+// loadFromDisk reads saved tokens, if any
+func (s *ServiceTokens) loadFromDisk() {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return
+	}
+	var tokens []*ServiceToken
+	if err := yaml.Unmarshal(data, &tokens); err != nil {
+		fmt.Printf("[Tokens] Failed to parse %s: %v\n", s.path, err)
+		return
+	}
+	for _, t := range tokens {
+		s.tokens[t.ID] = t
+	}
+}
+
+// saveToDisk writes every token. Caller must hold s.mu.
+func (s *ServiceTokens) saveToDisk() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := yaml.Marshal(s.listLocked())
+	if err != nil {
+		return fmt.Errorf("marshaling tokens: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("writing tokens: %w", err)
+	}
+	return nil
+}
+
+// Create issues a token and returns it with its secret, which is not
+// stored and can't be shown again. ttl of zero means no expiry.
+func (s *ServiceTokens) Create(name string, scopes []string, createdBy string, ttl time.Duration) (*ServiceToken, string, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, "", fmt.Errorf("name is required")
+	}
+	if err := ValidateScopes(scopes); err != nil {
+		return nil, "", err
+	}
+	if ttl < 0 || ttl > maxServiceTokenTTL {
+		return nil, "", fmt.Errorf("expiry must be between 0 and %d days", int(maxServiceTokenTTL.Hours()/24))
+	}
+
+	idBytes := make([]byte, 8)
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, "", fmt.Errorf("generating token: %w", err)
+	}
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, "", fmt.Errorf("generating token: %w", err)
+	}
+	secret := base64.RawURLEncoding.EncodeToString(secretBytes)
+	hash := sha256.Sum256([]byte(secret))
+
+	now := s.now().UTC()
+	t := &ServiceToken{
+		ID:        hex.EncodeToString(idBytes),
+		Name:      name,
+		Scopes:    scopes,
+		Hash:      hex.EncodeToString(hash[:]),
+		CreatedBy: createdBy,
+		CreatedAt: now,
+	}
+	if ttl > 0 {
+		expires := now.Add(ttl)
+		t.ExpiresAt = &expires
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[t.ID] = t
+	if err := s.saveToDisk(); err != nil {
+		delete(s.tokens, t.ID)
+		return nil, "", err
+	}
+	return t, serviceTokenPrefix + t.ID + "_" + secret, nil
+}
+
+// Revoke stops a token being accepted. Revoked tokens stay listed.
+func (s *ServiceTokens) Revoke(id string) (*ServiceToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tokens[id]
+	if !ok {
+		return nil, nil
+	}
+	if t.RevokedAt == nil {
+		now := s.now().UTC()
+		t.RevokedAt = &now
+		if err := s.saveToDisk(); err != nil {
+			t.RevokedAt = nil
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Verify returns the token a credential belongs to, or an error if it is
+// unknown, revoked or expired
+func (s *ServiceTokens) Verify(credential string) (*ServiceToken, error) {
+	id, secret, ok := strings.Cut(strings.TrimPrefix(credential, serviceTokenPrefix), "_")
+	if !ok || secret == "" {
+		return nil, fmt.Errorf("malformed service token")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t := s.tokens[id]
+	if t == nil {
+		return nil, fmt.Errorf("invalid service token")
+	}
+	hash := sha256.Sum256([]byte(secret))
+	stored, err := hex.DecodeString(t.Hash)
+	if err != nil || subtle.ConstantTimeCompare(hash[:], stored) != 1 {
+		return nil, fmt.Errorf("invalid service token")
+	}
+	if !t.activeAt(s.now()) {
+		return nil, fmt.Errorf("service token is revoked or expired")
+	}
+	return t, nil
+}
+
+// List returns every token, newest first
+func (s *ServiceTokens) List() []*ServiceToken {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.listLocked()
+}
+
+func (s *ServiceTokens) listLocked() []*ServiceToken {
+	list := make([]*ServiceToken, 0, len(s.tokens))
+	for _, t := range s.tokens {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.After(list[j].CreatedAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// CreateServiceTokenRequest is the body for POST /api/v1/admin/tokens
+type CreateServiceTokenRequest struct {
+	Name   string   `json:"name" validate:"required,max=100"`
+	Scopes []string `json:"scopes" validate:"required"`
+	// ExpiresInDays is optional; zero means the token doesn't expire
+	ExpiresInDays int `json:"expiresInDays,omitempty" validate:"min=0,max=365"`
+}
+
+// Validate checks the scopes are well formed
+func (req *CreateServiceTokenRequest) Validate() error {
+	if err := ValidateScopes(req.Scopes); err != nil {
+		return ValidationErrors{{Field: "scopes", Message: err.Error()}}
+	}
+	return nil
+}
+
+// CreateServiceTokenResponse returns the token's secret, once
+type CreateServiceTokenResponse struct {
+	*ServiceToken
+	Token string `json:"token"`
+}
+
+// HandleTokens handles GET and POST /api/v1/admin/tokens
+func (s *ServiceTokens) HandleTokens(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"tokens": s.List(),
+		})
+	case http.MethodPost:
+		var req CreateServiceTokenRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+		createdBy := ""
+		if p := PrincipalFromContext(r.Context()); p != nil {
+			createdBy = p.AID
+			if createdBy == "" {
+				createdBy = p.Kind + ":" + p.Name
+			}
+		}
+		t, secret, err := s.Create(req.Name, req.Scopes, createdBy, time.Duration(req.ExpiresInDays)*24*time.Hour)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		fmt.Printf("[Tokens] Created %s (%s) with scopes %s\n", t.ID, t.Name, strings.Join(t.Scopes, ", "))
+		writeJSON(w, http.StatusCreated, CreateServiceTokenResponse{ServiceToken: t, Token: secret})
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+	}
+}
+
+// HandleToken handles DELETE /api/v1/admin/tokens/{id}
+func (s *ServiceTokens) HandleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/admin/tokens/")
+	if id == "" || strings.Contains(id, "/") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "token ID required"})
+		return
+	}
+
+	t, err := s.Revoke(id)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if t == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "token not found"})
+		return
+	}
+	fmt.Printf("[Tokens] Revoked %s (%s)\n", t.ID, t.Name)
+	writeJSON(w, http.StatusOK, t)
+}
+
+// RegisterRoutes registers the service token routes on the mux
+func (s *ServiceTokens) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/admin/tokens", CORSHandler(s.HandleTokens))
+	mux.HandleFunc("/api/v1/admin/tokens/", CORSHandler(s.HandleToken))
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScopesAllow(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		method string
+		path   string
+		want   bool
+	}{
+		{"read scope reads", []string{"trust:read"}, "GET", "/api/v1/trust/graph", true},
+		{"read scope cannot write", []string{"trust:read"}, "POST", "/api/v1/trust/federation/kel", false},
+		{"write scope reads", []string{"receipts:write"}, "GET", "/api/v1/receipts", true},
+		{"write scope writes", []string{"receipts:write"}, "POST", "/api/v1/receipts", true},
+		{"other area", []string{"trust:read"}, "GET", "/api/v1/credentials", false},
+		{"prefix is not an area", []string{"org:read"}, "GET", "/api/v1/orgs", false},
+		{"non-api route", []string{"metrics:read"}, "GET", "/metrics", true},
+		{"admin covers all", []string{ScopeAdmin}, "DELETE", "/api/v1/admin/locks/issuance", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScopesAllow(tt.scopes, tt.method, tt.path); got != tt.want {
+				t.Errorf("ScopesAllow(%v, %s %s) = %v, want %v", tt.scopes, tt.method, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceTokens_Lifecycle(t *testing.T) {
+	dataDir := t.TempDir()
+	tokens := NewServiceTokens(dataDir)
+
+	for _, scopes := range [][]string{nil, {"trust"}, {"Trust:read"}, {"trust:read", "trust:read"}} {
+		if _, _, err := tokens.Create("ci", scopes, "EADMIN", 0); err == nil {
+			t.Errorf("expected scopes %v rejected", scopes)
+		}
+	}
+
+	created, secret, err := tokens.Create("ci", []string{"credentials:write"}, "EADMIN", 0)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if !strings.HasPrefix(secret, serviceTokenPrefix) {
+		t.Fatalf("unexpected token format %q", secret)
+	}
+
+	// Only the hash is written to disk
+	saved, err := os.ReadFile(filepath.Join(dataDir, "api-tokens.yaml"))
+	if err != nil {
+		t.Fatalf("expected tokens saved: %v", err)
+	}
+	_, rawSecret, _ := strings.Cut(strings.TrimPrefix(secret, serviceTokenPrefix), "_")
+	if strings.Contains(string(saved), rawSecret) {
+		t.Error("expected secret not stored")
+	}
+
+	if _, err := tokens.Verify(secret); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if _, err := tokens.Verify(serviceTokenPrefix + created.ID + "_wrong"); err == nil {
+		t.Error("expected wrong secret rejected")
+	}
+
+	// Tokens survive a restart
+	reloaded := NewServiceTokens(dataDir)
+	if _, err := reloaded.Verify(secret); err != nil {
+		t.Fatalf("expected token valid after reload: %v", err)
+	}
+
+	if revoked, err := reloaded.Revoke(created.ID); err != nil || revoked == nil || revoked.RevokedAt == nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if _, err := reloaded.Verify(secret); err == nil {
+		t.Error("expected revoked token rejected")
+	}
+	if missing, _ := reloaded.Revoke("unknown"); missing != nil {
+		t.Error("expected unknown token not found")
+	}
+}
+
+func TestServiceTokens_Expiry(t *testing.T) {
+	tokens := NewServiceTokens("")
+	now := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	tokens.now = func() time.Time { return now }
+
+	_, secret, err := tokens.Create("bridge", []string{"events:read"}, "", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := tokens.Verify(secret); err != nil {
+		t.Fatalf("expected token valid: %v", err)
+	}
+	now = now.Add(25 * time.Hour)
+	if _, err := tokens.Verify(secret); err == nil {
+		t.Error("expected expired token rejected")
+	}
+	if _, _, err := tokens.Create("bridge", []string{"events:read"}, "", 2*maxServiceTokenTTL); err == nil {
+		t.Error("expected expiry past the maximum rejected")
+	}
+}
+
+func TestAuthMiddleware_ServiceTokens(t *testing.T) {
+	tokens := NewServiceTokens("")
+	_, reader, _ := tokens.Create("dashboard", []string{"trust:read"}, "", 0)
+	_, admin, _ := tokens.Create("ops-bot", []string{ScopeAdmin}, "", 0)
+
+	a := NewAuthenticator(AuthOptions{Enabled: true}, nil, nil)
+	a.SetServiceTokens(tokens)
+	a.Require("/health", AuthPublic)
+	a.Require("/api/v1/admin/", AuthAdmin)
+	var seen *Principal
+	handler := AuthMiddleware(a, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = PrincipalFromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name, method, path, token string
+		want                      int
+	}{
+		{"in scope", "GET", "/api/v1/trust/graph", reader, http.StatusOK},
+		{"out of scope", "GET", "/api/v1/credentials", reader, http.StatusForbidden},
+		{"read-only scope writing", "POST", "/api/v1/trust/federation/kel", reader, http.StatusForbidden},
+		{"public route", "GET", "/health", reader, http.StatusOK},
+		{"scoped token on admin route", "GET", "/api/v1/admin/locks", reader, http.StatusForbidden},
+		{"admin token", "GET", "/api/v1/admin/locks", admin, http.StatusOK},
+		{"unknown token", "GET", "/api/v1/trust/graph", serviceTokenPrefix + "0000_bad", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = nil
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+			if tt.want == http.StatusOK && tt.path != "/health" && (seen == nil || seen.Kind != "serviceToken") {
+				t.Errorf("expected service token principal, got %+v", seen)
+			}
+		})
+	}
+}
+
+func TestServiceTokens_HandleTokensValidation(t *testing.T) {
+	tokens := NewServiceTokens("")
+
+	tests := []struct {
+		name, body, field string
+	}{
+		{"missing name", `{"scopes":["trust:read"]}`, "name"},
+		{"missing scopes", `{"name":"ci"}`, "scopes"},
+		{"bad scope", `{"name":"ci","scopes":["Trust:read"]}`, "scopes"},
+		{"negative expiry", `{"name":"ci","scopes":["trust:read"],"expiresInDays":-1}`, "expiresInDays"},
+		{"expiry too long", `{"name":"ci","scopes":["trust:read"],"expiresInDays":400}`, "expiresInDays"},
+		{"expiry not a number", `{"name":"ci","scopes":["trust:read"],"expiresInDays":"30"}`, "expiresInDays"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tokens.HandleTokens(w, httptest.NewRequest(http.MethodPost, "/api/v1/admin/tokens", strings.NewReader(tt.body)))
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+			}
+			var problem Problem
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if len(problem.Errors) != 1 || problem.Errors[0].Field != tt.field {
+				t.Errorf("expected a field error for %s, got %+v", tt.field, problem.Errors)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	tokens.HandleTokens(w, httptest.NewRequest(http.MethodPost, "/api/v1/admin/tokens",
+		strings.NewReader(`{"name":"ci","scopes":["trust:read"],"expiresInDays":30}`)))
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected 201 for a valid request, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -3,21 +3,83 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/matou-dao/backend/internal/anysync"
 	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/flags"
 	"github.com/matou-dao/backend/internal/trust"
 )
 
 // TrustHandler handles trust graph related HTTP requests
 type TrustHandler struct {
-	store        *anystore.LocalStore
-	orgAID       string
-	calculator   *trust.Calculator
-	spaceManager *anysync.SpaceManager
+	store         *anystore.LocalStore
+	orgAID        string
+	spaceManager  *anysync.SpaceManager
+	noticeWindow  time.Duration
+	weights       TrustWeightsSource
+	algorithm     TrustAlgorithmSource
+	contributions ContributionCountSource
+	federation    FederationSource
+	cache         CommunityCredentialCache
+	defaults      *trustDefaults
+	materialized  *materializedGraph
+	flags         *flags.Flags
+}
+
+// trustDefaults are the weights used when an org sets none. Every org's
+// handler shares the server's, so a config reload reaches them all.
+type trustDefaults struct {
+	mu      sync.RWMutex
+	weights trust.ScoreWeights
+}
+
+func (d *trustDefaults) get() trust.ScoreWeights {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.weights
+}
+
+// TrustWeightsSource supplies per-org trust score weights. The org config
+// handler implements this so weight changes apply without a restart.
+type TrustWeightsSource interface {
+	GetTrustWeights() *trust.ScoreWeights
+}
+
+// TrustWeightsWriter saves per-org trust score weights, nil removing them.
+// The org config handler implements this.
+type TrustWeightsWriter interface {
+	SetTrustWeights(weights *trust.ScoreWeights) error
+}
+
+// TrustAlgorithmSource supplies the org's default trust scoring algorithm.
+// The org config handler implements this.
+type TrustAlgorithmSource interface {
+	GetTrustAlgorithm() trust.Algorithm
+}
+
+// ContributionCountSource supplies recorded contribution counts per AID so
+// orgs can opt into crediting contributions in trust scores.
+type ContributionCountSource interface {
+	ContributionCounts(ctx context.Context) (map[string]int, error)
+}
+
+// FederationSource supplies the org's trusted peer organizations. The org
+// config handler implements this.
+type FederationSource interface {
+	GetFederation() []trust.FederatedOrg
+}
+
+// CommunityCredentialCache reports whether the local credential cache is
+// up to date with the community credential tree, so requests can skip
+// reading the tree. The sync package's CredentialHydrator implements this.
+type CommunityCredentialCache interface {
+	CommunityCredentialsCached() bool
 }
 
 // NewTrustHandler creates a new trust handler
@@ -25,9 +87,137 @@ func NewTrustHandler(store *anystore.LocalStore, orgAID string, spaceManager *an
 	return &TrustHandler{
 		store:        store,
 		orgAID:       orgAID,
-		calculator:   trust.NewDefaultCalculator(),
 		spaceManager: spaceManager,
+		noticeWindow: trust.DefaultTermNoticeWindow,
+		defaults:     &trustDefaults{weights: trust.DefaultWeights()},
+	}
+}
+
+// SetDefaultWeights sets the weights used when the org sets none, e.g.
+// from trust.weights in config.yaml
+func (h *TrustHandler) SetDefaultWeights(weights trust.ScoreWeights) {
+	h.defaults.mu.Lock()
+	h.defaults.weights = weights
+	h.defaults.mu.Unlock()
+}
+
+// ShareDefaultWeights makes h use other's default weights, including later
+// changes to them. Other orgs' handlers share the primary org's.
+func (h *TrustHandler) ShareDefaultWeights(other *TrustHandler) {
+	h.defaults = other.defaults
+}
+
+// SetTermNoticeWindow sets how far ahead terms are reported as expiring
+func (h *TrustHandler) SetTermNoticeWindow(window time.Duration) {
+	h.noticeWindow = window
+}
+
+// SetWeightsSource attaches the source of per-org trust score weights
+func (h *TrustHandler) SetWeightsSource(source TrustWeightsSource) {
+	h.weights = source
+}
+
+// SetAlgorithmSource attaches the source of the org's default scoring algorithm
+func (h *TrustHandler) SetAlgorithmSource(source TrustAlgorithmSource) {
+	h.algorithm = source
+}
+
+// SetContributionSource attaches the source of recorded contribution counts
+func (h *TrustHandler) SetContributionSource(source ContributionCountSource) {
+	h.contributions = source
+}
+
+// SetFederationSource attaches the source of trusted peer organizations
+func (h *TrustHandler) SetFederationSource(source FederationSource) {
+	h.federation = source
+}
+
+// SetFlags attaches the feature flags; ?algorithm=pagerank needs trust_v2
+func (h *TrustHandler) SetFlags(f *flags.Flags) {
+	h.flags = f
+}
+
+// SetCommunityCache attaches the hydrated community credential cache
+func (h *TrustHandler) SetCommunityCache(cache CommunityCredentialCache) {
+	h.cache = cache
+}
+
+// federatedOrgs returns the configured peer organizations, if any
+func (h *TrustHandler) federatedOrgs() []trust.FederatedOrg {
+	if h.federation == nil {
+		return nil
+	}
+	return h.federation.GetFederation()
+}
+
+// addContributions copies recorded contribution counts onto graph nodes.
+// Failures are logged and leave the counts at zero.
+func (h *TrustHandler) addContributions(ctx context.Context, graph *trust.Graph) {
+	if h.contributions == nil || graph == nil {
+		return
+	}
+	counts, err := h.contributions.ContributionCounts(ctx)
+	if err != nil {
+		fmt.Printf("[Trust] Warning: failed to read contributions: %v\n", err)
+		return
 	}
+	for aid, n := range counts {
+		if node := graph.GetNode(aid); node != nil {
+			node.Contributions = n
+		}
+	}
+}
+
+// scoreCalculator returns a calculator using the org's configured weights
+// and algorithm, falling back to the defaults. A configured PageRank falls
+// back to linear while the trust_v2 flag is off.
+func (h *TrustHandler) scoreCalculator() *trust.Calculator {
+	algorithm := trust.AlgorithmLinear
+	if h.algorithm != nil {
+		if a := h.algorithm.GetTrustAlgorithm(); a != "" {
+			algorithm = a
+		}
+	}
+	if algorithm == trust.AlgorithmPageRank && !h.flags.Enabled(flags.TrustV2) {
+		algorithm = trust.AlgorithmLinear
+	}
+	return h.calculatorFor(algorithm)
+}
+
+// calculatorFor returns a calculator using the active weights with the
+// given algorithm
+func (h *TrustHandler) calculatorFor(algorithm trust.Algorithm) *trust.Calculator {
+	weights, _ := h.activeWeights()
+	return trust.NewCalculator(weights).WithAlgorithm(algorithm)
+}
+
+// activeWeights returns the org's configured weights, falling back to the
+// server's defaults, and whether they are the org's
+func (h *TrustHandler) activeWeights() (trust.ScoreWeights, bool) {
+	if h.weights != nil {
+		if w := h.weights.GetTrustWeights(); w != nil {
+			return *w, true
+		}
+	}
+	return h.defaults.get(), false
+}
+
+// requestCalculator returns the calculator for a request. The optional
+// algorithm query parameter overrides the org's configured algorithm;
+// choosing PageRank that way is experimental, behind the trust_v2 flag.
+func (h *TrustHandler) requestCalculator(r *http.Request) (*trust.Calculator, error) {
+	name := r.URL.Query().Get("algorithm")
+	if name == "" {
+		return h.scoreCalculator(), nil
+	}
+	algorithm, err := trust.ParseAlgorithm(name)
+	if err != nil {
+		return nil, err
+	}
+	if algorithm == trust.AlgorithmPageRank && !h.flags.Enabled(flags.TrustV2) {
+		return nil, fmt.Errorf("algorithm %s requires the %s feature flag", algorithm, flags.TrustV2)
+	}
+	return h.calculatorFor(algorithm), nil
 }
 
 // GraphResponse represents the trust graph API response
@@ -38,21 +228,27 @@ type GraphResponse struct {
 
 // ScoreResponse represents a single trust score response
 type ScoreResponse struct {
-	Score *trust.Score `json:"score"`
+	Score     *trust.Score    `json:"score"`
+	Algorithm trust.Algorithm `json:"algorithm"`
 }
 
 // ScoresResponse represents multiple trust scores response
 type ScoresResponse struct {
-	Scores []*trust.Score `json:"scores"`
-	Total  int            `json:"total"`
+	Scores    []*trust.Score  `json:"scores"`
+	Total     int             `json:"total"`
+	Algorithm trust.Algorithm `json:"algorithm"`
 }
 
 // getCommunityCredentials fetches credentials from the AnySync community space
 // ObjectTree and converts them to CachedCredential format for the trust builder.
+// Returns nil when the community cache already holds them.
 func (h *TrustHandler) getCommunityCredentials(ctx context.Context) []*anystore.CachedCredential {
 	if h.spaceManager == nil {
 		return nil
 	}
+	if h.cache != nil && h.cache.CommunityCredentialsCached() {
+		return nil
+	}
 	communitySpaceID := h.spaceManager.GetCommunitySpaceID()
 	if communitySpaceID == "" {
 		return nil
@@ -82,12 +278,65 @@ func (h *TrustHandler) getCommunityCredentials(ctx context.Context) []*anystore.
 	return result
 }
 
+// allCredentials returns cached credentials merged with AnySync community
+// credentials, deduplicated by SAID.
+func (h *TrustHandler) allCredentials(ctx context.Context) ([]*anystore.CachedCredential, error) {
+	creds, err := h.store.GetAllCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return h.withCommunityCredentials(ctx, creds, nil), nil
+}
+
+// credentialsIssuedBy returns the credentials an AID issued, looked up by
+// the cache's issuer index, merged with matching community credentials.
+func (h *TrustHandler) credentialsIssuedBy(ctx context.Context, aid string) ([]*anystore.CachedCredential, error) {
+	creds, err := h.store.GetCredentialsByIssuer(ctx, aid)
+	if err != nil {
+		return nil, err
+	}
+	return h.withCommunityCredentials(ctx, creds, func(c *anystore.CachedCredential) bool {
+		return c.IssuerAID == aid
+	}), nil
+}
+
+// credentialsHeldBy returns the credentials issued to an AID, looked up by
+// the cache's subject index, merged with matching community credentials.
+func (h *TrustHandler) credentialsHeldBy(ctx context.Context, aid string) ([]*anystore.CachedCredential, error) {
+	creds, err := h.store.GetCredentialsBySubject(ctx, aid)
+	if err != nil {
+		return nil, err
+	}
+	return h.withCommunityCredentials(ctx, creds, func(c *anystore.CachedCredential) bool {
+		return c.SubjectAID == aid
+	}), nil
+}
+
+// withCommunityCredentials adds the AnySync community credentials that
+// match (all of them for a nil match) to creds, deduplicated by SAID.
+func (h *TrustHandler) withCommunityCredentials(ctx context.Context, creds []*anystore.CachedCredential, match func(*anystore.CachedCredential) bool) []*anystore.CachedCredential {
+	seen := make(map[string]bool, len(creds))
+	for _, c := range creds {
+		seen[c.ID] = true
+	}
+	for _, c := range h.getCommunityCredentials(ctx) {
+		if !seen[c.ID] && (match == nil || match(c)) {
+			creds = append(creds, c)
+			seen[c.ID] = true
+		}
+	}
+	return creds
+}
+
 // newBuilder creates a trust.Builder with AnySync community credentials injected.
 func (h *TrustHandler) newBuilder(ctx context.Context) *trust.Builder {
 	builder := trust.NewBuilder(h.store, h.orgAID)
 	if extras := h.getCommunityCredentials(ctx); len(extras) > 0 {
 		builder.WithExtraCredentials(extras)
 	}
+	if peers := h.federatedOrgs(); len(peers) > 0 {
+		builder.WithFederation(peers)
+	}
 	return builder
 }
 
@@ -96,6 +345,8 @@ func (h *TrustHandler) newBuilder(ctx context.Context) *trust.Builder {
 //   - aid: Focus on specific AID (optional)
 //   - depth: Depth limit for subgraph (optional, default: full graph)
 //   - summary: Include summary stats (optional, default: false)
+//   - algorithm: Scoring algorithm for the summary, "linear" or "pagerank"
+//     (optional, default: the org's configured algorithm)
 func (h *TrustHandler) HandleGetGraph(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
@@ -104,34 +355,15 @@ func (h *TrustHandler) HandleGetGraph(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx := r.Context()
+	calculator, err := h.requestCalculator(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
 
-	// Parse query parameters
-	aidFilter := r.URL.Query().Get("aid")
-	depthStr := r.URL.Query().Get("depth")
 	includeSummary := r.URL.Query().Get("summary") == "true"
 
-	// Create builder
-	builder := h.newBuilder(ctx)
-
-	var graph *trust.Graph
-	var err error
-
-	// Build graph
-	if aidFilter != "" {
-		// Build subgraph focused on specific AID
-		depth := 2 // Default depth
-		if depthStr != "" {
-			if d, parseErr := strconv.Atoi(depthStr); parseErr == nil && d > 0 {
-				depth = d
-			}
-		}
-		graph, err = builder.BuildForAID(ctx, aidFilter, depth)
-	} else {
-		// Build full graph
-		graph, err = builder.Build(ctx)
-	}
-
+	graph, err := h.requestGraph(r)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{
 			"error": "failed to build trust graph: " + err.Error(),
@@ -146,14 +378,176 @@ func (h *TrustHandler) HandleGetGraph(w http.ResponseWriter, r *http.Request) {
 
 	// Include summary if requested
 	if includeSummary {
-		resp.Summary = h.calculator.CalculateSummary(graph)
+		resp.Summary = calculator.CalculateSummary(graph)
 	}
 
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// requestGraph builds the graph a request asks for: the subgraph around
+// the aid query parameter to depth hops (default 2), or the full graph
+func (h *TrustHandler) requestGraph(r *http.Request) (*trust.Graph, error) {
+	ctx := r.Context()
+	graph, err := h.buildGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if aidFilter := r.URL.Query().Get("aid"); aidFilter != "" {
+		// Subgraph focused on specific AID
+		depth := 2 // Default depth
+		if d, parseErr := strconv.Atoi(r.URL.Query().Get("depth")); parseErr == nil && d > 0 {
+			depth = d
+		}
+		graph = graph.Subgraph(aidFilter, depth)
+	}
+	h.addContributions(ctx, graph)
+	return graph, nil
+}
+
+// HandleExportGraph handles GET /api/v1/trust/graph/export
+// Query params:
+//   - format: "graphml" (default), "dot" or "csv"
+//   - table: For CSV, "edges" (default) or "nodes"
+//   - aid, depth: Export the subgraph around an AID, as for /trust/graph
+//   - algorithm: Scoring algorithm for node scores (optional)
+//
+// Node scores are always computed over the full graph, so a subgraph
+// export shows the same scores as /trust/score.
+func (h *TrustHandler) HandleExportGraph(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	format, err := trust.ParseExportFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	table := trust.CSVTable(r.URL.Query().Get("table"))
+	if table != "" && table != trust.CSVNodes && table != trust.CSVEdges {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("unknown table %q (expected nodes or edges)", table),
+		})
+		return
+	}
+	calculator, err := h.requestCalculator(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	graph, err := h.requestGraph(r)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "failed to build trust graph: " + err.Error(),
+		})
+		return
+	}
+	full := graph
+	if r.URL.Query().Get("aid") != "" {
+		if full, err = h.buildGraph(r.Context()); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"error": "failed to build trust graph: " + err.Error(),
+			})
+			return
+		}
+		h.addContributions(r.Context(), full)
+	}
+
+	filename := "trust-graph." + string(format)
+	if format == trust.ExportCSV {
+		if table == "" {
+			table = trust.CSVEdges
+		}
+		filename = fmt.Sprintf("trust-graph-%s.csv", table)
+	}
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+
+	exporter := &trust.Exporter{Graph: graph, Scores: calculator.CalculateAllScores(full)}
+	if err := exporter.Write(w, format, table); err != nil {
+		fmt.Printf("[Trust] Graph export failed: %v\n", err)
+	}
+}
+
+// HandleGetSnapshot handles GET /api/v1/trust/snapshot
+// Query params:
+//   - algorithm: Scoring algorithm (optional, default: the org's configured algorithm)
+//
+// The snapshot records the full graph, weights and algorithm along with
+// the resulting scores, so auditors can verify published scores.
+func (h *TrustHandler) HandleGetSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	calculator, err := h.requestCalculator(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	graph, err := h.buildGraph(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "failed to build trust graph: " + err.Error(),
+		})
+		return
+	}
+	h.addContributions(r.Context(), graph)
+
+	snapshot, err := trust.NewSnapshot(graph, calculator)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="trust-snapshot.json"`)
+	writeJSON(w, http.StatusOK, snapshot)
+}
+
+// HandleVerifySnapshot handles POST /api/v1/trust/snapshot/verify. The
+// body is a snapshot, from this or any other community; it is checked on
+// its own contents, without reference to the local graph.
+func (h *TrustHandler) HandleVerifySnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	var snapshot trust.Snapshot
+	if err := json.NewDecoder(r.Body).Decode(&snapshot); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "invalid snapshot: " + err.Error(),
+		})
+		return
+	}
+
+	result, err := trust.VerifySnapshot(&snapshot)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
 // HandleGetScore handles GET /api/v1/trust/score/{aid}
+// Query params:
+//   - algorithm: "linear" or "pagerank" (optional, default: the org's configured algorithm)
 func (h *TrustHandler) HandleGetScore(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/history") {
+		h.HandleGetScoreHistory(w, r)
+		return
+	}
 	if r.Method != http.MethodGet {
 		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
 			"error": "method not allowed",
@@ -161,6 +555,12 @@ func (h *TrustHandler) HandleGetScore(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	calculator, err := h.requestCalculator(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
 	// Extract AID from path
 	// Expected path: /api/v1/trust/score/{aid}
 	path := r.URL.Path
@@ -176,14 +576,14 @@ func (h *TrustHandler) HandleGetScore(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// Build graph
-	builder := h.newBuilder(ctx)
-	graph, err := builder.Build(ctx)
+	graph, err := h.buildGraph(ctx)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{
 			"error": "failed to build trust graph: " + err.Error(),
 		})
 		return
 	}
+	h.addContributions(ctx, graph)
 
 	// Check if AID exists in graph
 	if graph.GetNode(aid) == nil {
@@ -194,10 +594,11 @@ func (h *TrustHandler) HandleGetScore(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Calculate score
-	score := h.calculator.CalculateScore(aid, graph)
+	score := calculator.CalculateScore(aid, graph)
 
 	writeJSON(w, http.StatusOK, ScoreResponse{
-		Score: score,
+		Score:     score,
+		Algorithm: calculator.Algorithm(),
 	})
 }
 
@@ -205,6 +606,7 @@ func (h *TrustHandler) HandleGetScore(w http.ResponseWriter, r *http.Request) {
 // Query params:
 //   - limit: Maximum number of scores to return (optional, default: 10)
 //   - sort: Sort order - "score" (default), "depth", "credentials"
+//   - algorithm: "linear" or "pagerank" (optional, default: the org's configured algorithm)
 func (h *TrustHandler) HandleGetScores(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
@@ -213,6 +615,12 @@ func (h *TrustHandler) HandleGetScores(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	calculator, err := h.requestCalculator(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
 	ctx := r.Context()
 
 	// Parse query parameters
@@ -225,25 +633,28 @@ func (h *TrustHandler) HandleGetScores(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Build graph
-	builder := h.newBuilder(ctx)
-	graph, err := builder.Build(ctx)
+	graph, err := h.buildGraph(ctx)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{
 			"error": "failed to build trust graph: " + err.Error(),
 		})
 		return
 	}
+	h.addContributions(ctx, graph)
 
 	// Get top scores
-	scores := h.calculator.GetTopScores(graph, limit)
+	scores := h.topScores(ctx, graph, calculator, limit)
 
 	writeJSON(w, http.StatusOK, ScoresResponse{
-		Scores: scores,
-		Total:  len(scores),
+		Scores:    scores,
+		Total:     len(scores),
+		Algorithm: calculator.Algorithm(),
 	})
 }
 
 // HandleGetSummary handles GET /api/v1/trust/summary
+// Query params:
+//   - algorithm: "linear" or "pagerank" (optional, default: the org's configured algorithm)
 func (h *TrustHandler) HandleGetSummary(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
@@ -252,28 +663,376 @@ func (h *TrustHandler) HandleGetSummary(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	calculator, err := h.requestCalculator(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
 	ctx := r.Context()
 
 	// Build graph
-	builder := h.newBuilder(ctx)
-	graph, err := builder.Build(ctx)
+	graph, err := h.buildGraph(ctx)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{
 			"error": "failed to build trust graph: " + err.Error(),
 		})
 		return
 	}
+	h.addContributions(ctx, graph)
 
 	// Calculate summary
-	summary := h.calculator.CalculateSummary(graph)
+	summary := calculator.CalculateSummary(graph)
 
 	writeJSON(w, http.StatusOK, summary)
 }
 
+// Path limits for GET /api/v1/trust/path
+const (
+	defaultTrustPaths = 3
+	maxTrustPaths     = 10
+)
+
+// TrustPathResponse is the response for GET /api/v1/trust/path
+type TrustPathResponse struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Connected is false when no credential chain links the AIDs without
+	// passing through the org
+	Connected bool `json:"connected"`
+	// Distance is the number of hops on each path
+	Distance int           `json:"distance,omitempty"`
+	Paths    []*trust.Path `json:"paths"`
+}
+
+// HandleGetPath handles GET /api/v1/trust/path
+// Query params:
+//   - from, to: The AIDs to connect (required)
+//   - limit: Maximum number of shortest paths (optional, default: 3, max: 10)
+func (h *TrustHandler) HandleGetPath(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	from, to := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+	if from == "" || to == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "from and to are required",
+		})
+		return
+	}
+	limit := defaultTrustPaths
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if limit > maxTrustPaths {
+		limit = maxTrustPaths
+	}
+
+	ctx := r.Context()
+	graph, err := h.buildGraph(ctx)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "failed to build trust graph: " + err.Error(),
+		})
+		return
+	}
+	for _, aid := range []string{from, to} {
+		if graph.GetNode(aid) == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{
+				"error": fmt.Sprintf("%s not found in trust graph", aid),
+			})
+			return
+		}
+	}
+
+	resp := TrustPathResponse{From: from, To: to, Paths: []*trust.Path{}}
+	if paths := graph.ShortestPaths(from, to, limit); paths != nil {
+		resp.Connected = true
+		resp.Distance = len(paths[0].Hops)
+		resp.Paths = paths
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// AnomaliesResponse is the response for GET /api/v1/trust/anomalies
+type AnomaliesResponse struct {
+	Anomalies []*trust.Anomaly `json:"anomalies"`
+	Total     int              `json:"total"`
+	CheckedAt time.Time        `json:"checkedAt"`
+}
+
+// HandleGetAnomalies handles GET /api/v1/trust/anomalies
+// Query params:
+//   - kind: Only report one kind: "endorsement_ring", "mass_issuer" or "unrooted" (optional)
+//
+// Anomalies are patterns worth a moderator's look, such as Sybil rings;
+// they don't change scores.
+func (h *TrustHandler) HandleGetAnomalies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	kind := r.URL.Query().Get("kind")
+	switch kind {
+	case "", trust.AnomalyEndorsementRing, trust.AnomalyMassIssuer, trust.AnomalyUnrooted:
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("unknown anomaly kind %q", kind),
+		})
+		return
+	}
+
+	ctx := r.Context()
+	graph, err := h.buildGraph(ctx)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "failed to build trust graph: " + err.Error(),
+		})
+		return
+	}
+
+	now := time.Now().UTC()
+	anomalies := []*trust.Anomaly{}
+	for _, a := range trust.DetectAnomalies(graph, trust.DefaultAnomalyOptions(), now) {
+		if kind == "" || a.Kind == kind {
+			anomalies = append(anomalies, a)
+		}
+	}
+	writeJSON(w, http.StatusOK, AnomaliesResponse{
+		Anomalies: anomalies,
+		Total:     len(anomalies),
+		CheckedAt: now,
+	})
+}
+
+// TermsResponse represents the term-limited roles response
+type TermsResponse struct {
+	Terms []*trust.Term `json:"terms"`
+	Total int           `json:"total"`
+}
+
+// HandleGetTerms handles GET /api/v1/trust/terms
+// Query params:
+//   - status: Filter by status (active, expiring, expired, renewed)
+//   - aid: Filter by holder AID
+func (h *TrustHandler) HandleGetTerms(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	aid := r.URL.Query().Get("aid")
+
+	var creds []*anystore.CachedCredential
+	var err error
+	if aid != "" {
+		creds, err = h.credentialsHeldBy(r.Context(), aid)
+	} else {
+		creds, err = h.allCredentials(r.Context())
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "failed to read credentials: " + err.Error(),
+		})
+		return
+	}
+
+	terms := make([]*trust.Term, 0)
+	for _, term := range trust.EvaluateTerms(creds, time.Now(), h.noticeWindow) {
+		if status != "" && term.Status != status {
+			continue
+		}
+		if aid != "" && term.HolderAID != aid {
+			continue
+		}
+		terms = append(terms, term)
+	}
+
+	writeJSON(w, http.StatusOK, TermsResponse{Terms: terms, Total: len(terms)})
+}
+
+// FederationResponse is the response for GET /api/v1/trust/federation
+type FederationResponse struct {
+	Peers []*trust.FederationStatus `json:"peers"`
+	Total int                       `json:"total"`
+}
+
+// FederationKELRequest is the body for POST /api/v1/trust/federation/kel
+type FederationKELRequest struct {
+	AID string     `json:"aid"`
+	KEL []KELEvent `json:"kel"`
+}
+
+// AlgorithmsResponse lists the scoring algorithms a request can select
+type AlgorithmsResponse struct {
+	Algorithms []trust.Algorithm `json:"algorithms"`
+	Default    trust.Algorithm   `json:"default"` // The org's configured algorithm
+}
+
+// HandleGetAlgorithms handles GET /api/v1/trust/algorithms
+func (h *TrustHandler) HandleGetAlgorithms(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AlgorithmsResponse{
+		Algorithms: trust.Algorithms(),
+		Default:    h.scoreCalculator().Algorithm(),
+	})
+}
+
+// TrustWeightsResponse is the response for /api/v1/trust/weights
+type TrustWeightsResponse struct {
+	// Weights are the weights scores are calculated with
+	Weights trust.ScoreWeights `json:"weights"`
+	// Source is "org" when the org set its own weights, otherwise "server"
+	Source string `json:"source"`
+	// ServerWeights are the server's defaults, from trust.weights in
+	// config.yaml
+	ServerWeights trust.ScoreWeights `json:"serverWeights"`
+}
+
+// HandleWeights handles GET, PUT and DELETE /api/v1/trust/weights.
+// PUT saves the org's weights, omitted weights taking the built-in
+// defaults. DELETE removes them so the server's defaults apply. Scores
+// use the new weights from the next request.
+func (h *TrustHandler) HandleWeights(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodDelete:
+		writer, ok := h.weights.(TrustWeightsWriter)
+		if !ok {
+			writeJSON(w, http.StatusNotImplemented, map[string]string{
+				"error": "trust weights can't be changed on this server",
+			})
+			return
+		}
+		var weights *trust.ScoreWeights
+		if r.Method == http.MethodPut {
+			weights = &trust.ScoreWeights{}
+			if !decodeRequest(w, r, weights) {
+				return
+			}
+		}
+		if err := writer.SetTrustWeights(weights); err != nil {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+			return
+		}
+		if weights != nil {
+			fmt.Printf("[Trust] Org weights set: %+v\n", *weights)
+		} else {
+			fmt.Println("[Trust] Org weights removed; using the server's defaults")
+		}
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	weights, own := h.activeWeights()
+	resp := TrustWeightsResponse{Weights: weights, Source: "server"}
+	if own {
+		resp.Source = "org"
+	}
+	resp.ServerWeights = h.defaults.get()
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// HandleFederation handles GET /api/v1/trust/federation
+// Lists the configured peer orgs and whether each one's KEL has been verified.
+// Credentials from unverified peers are left out of the trust graph.
+func (h *TrustHandler) HandleFederation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	peers := trust.CheckFederation(r.Context(), h.store, h.federatedOrgs())
+	writeJSON(w, http.StatusOK, FederationResponse{Peers: peers, Total: len(peers)})
+}
+
+// HandleFederationKEL handles POST /api/v1/trust/federation/kel
+// Caches a peer org's KEL (resolved from its OOBI by the frontend) so its
+// credentials can be verified. Only configured peers are accepted.
+func (h *TrustHandler) HandleFederationKEL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	var req FederationKELRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("invalid request: %v", err),
+		})
+		return
+	}
+	if req.AID == "" || len(req.KEL) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "aid and kel are required",
+		})
+		return
+	}
+
+	var peer *trust.FederatedOrg
+	for _, f := range h.federatedOrgs() {
+		if f.AID == req.AID {
+			peer = &f
+			break
+		}
+	}
+	if peer == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			"error": fmt.Sprintf("%s is not a federated org", req.AID),
+		})
+		return
+	}
+
+	stored, err := storeKELEvents(r.Context(), h.store, req.AID, req.KEL)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	status := trust.CheckFederation(r.Context(), h.store, []trust.FederatedOrg{*peer})[0]
+	fmt.Printf("[Trust] Stored %d KEL events for federated org %s (verified: %v)\n", stored, req.AID, status.Verified)
+	writeJSON(w, http.StatusOK, status)
+}
+
 // RegisterRoutes registers trust routes on the mux
 func (h *TrustHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/v1/trust/graph", h.HandleGetGraph)
+	mux.HandleFunc("/api/v1/trust/graph/export", h.HandleExportGraph)
+	mux.HandleFunc("/api/v1/trust/snapshot", h.HandleGetSnapshot)
+	mux.HandleFunc("/api/v1/trust/snapshot/verify", h.HandleVerifySnapshot)
 	mux.HandleFunc("/api/v1/trust/score/", h.HandleGetScore)
 	mux.HandleFunc("/api/v1/trust/scores", h.HandleGetScores)
+	mux.HandleFunc("/api/v1/trust/path", h.HandleGetPath)
+	mux.HandleFunc("/api/v1/trust/anomalies", h.HandleGetAnomalies)
 	mux.HandleFunc("/api/v1/trust/summary", h.HandleGetSummary)
+	mux.HandleFunc("/api/v1/trust/algorithms", h.HandleGetAlgorithms)
+	mux.HandleFunc("/api/v1/trust/weights", h.HandleWeights)
+	mux.HandleFunc("/api/v1/trust/terms", h.HandleGetTerms)
+	mux.HandleFunc("/api/v1/trust/federation", h.HandleFederation)
+	mux.HandleFunc("/api/v1/trust/federation/kel", h.HandleFederationKEL)
 }
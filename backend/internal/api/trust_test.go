@@ -3,13 +3,16 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/flags"
 	"github.com/matou-dao/backend/internal/trust"
 )
 
@@ -48,8 +51,8 @@ func TestNewTrustHandler(t *testing.T) {
 	if handler.orgAID != "EORG123" {
 		t.Errorf("expected orgAID EORG123, got %s", handler.orgAID)
 	}
-	if handler.calculator == nil {
-		t.Error("expected calculator to be initialized")
+	if handler.defaults.get() != trust.DefaultWeights() {
+		t.Error("expected the default weights")
 	}
 }
 
@@ -424,6 +427,275 @@ func TestHandleGetScores_WithLimit(t *testing.T) {
 	}
 }
 
+func TestHandleGetScores_Algorithm(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	store.StoreCredential(context.Background(), &anystore.CachedCredential{
+		ID:         "ESAID001",
+		IssuerAID:  "EORG123",
+		SubjectAID: "EUSER1",
+		SchemaID:   "EMatouMembershipSchemaV1",
+		CachedAt:   time.Now(),
+		Data: map[string]interface{}{
+			"role": "Member",
+		},
+	})
+
+	handler := NewTrustHandler(store, "EORG123", nil)
+
+	// PageRank is experimental, behind the trust_v2 flag
+	w := httptest.NewRecorder()
+	handler.HandleGetScores(w, httptest.NewRequest(http.MethodGet, "/api/v1/trust/scores?algorithm=pagerank", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 without trust_v2, got %d", w.Code)
+	}
+	handler.SetFlags(flags.New(map[string]bool{flags.TrustV2: true}, t.TempDir()))
+
+	w = httptest.NewRecorder()
+	handler.HandleGetScores(w, httptest.NewRequest(http.MethodGet, "/api/v1/trust/scores?algorithm=pagerank", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var result ScoresResponse
+	json.NewDecoder(w.Body).Decode(&result)
+	if result.Algorithm != trust.AlgorithmPageRank {
+		t.Errorf("expected pagerank, got %q", result.Algorithm)
+	}
+	total := 0.0
+	for _, s := range result.Scores {
+		total += s.Score
+	}
+	if len(result.Scores) != 2 || total < 1.99 || total > 2.01 {
+		t.Errorf("expected PageRank scores averaging 1, got %+v", result.Scores)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleGetScores(w, httptest.NewRequest(http.MethodGet, "/api/v1/trust/scores?algorithm=eigen", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown algorithm, got %d", w.Code)
+	}
+}
+
+// fixedAlgorithm is an org config that always chooses one algorithm
+type fixedAlgorithm trust.Algorithm
+
+func (a fixedAlgorithm) GetTrustAlgorithm() trust.Algorithm { return trust.Algorithm(a) }
+
+func TestHandleGetScores_ConfiguredPageRank(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	handler := NewTrustHandler(store, "EORG123", nil)
+	handler.SetAlgorithmSource(fixedAlgorithm(trust.AlgorithmPageRank))
+
+	get := func() ScoresResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		handler.HandleGetScores(w, httptest.NewRequest(http.MethodGet, "/api/v1/trust/scores", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+		var result ScoresResponse
+		json.NewDecoder(w.Body).Decode(&result)
+		return result
+	}
+
+	// The org config can't turn on PageRank while trust_v2 is off
+	if result := get(); result.Algorithm != trust.AlgorithmLinear {
+		t.Errorf("expected linear without trust_v2, got %q", result.Algorithm)
+	}
+
+	handler.SetFlags(flags.New(map[string]bool{flags.TrustV2: true}, t.TempDir()))
+	if result := get(); result.Algorithm != trust.AlgorithmPageRank {
+		t.Errorf("expected the configured pagerank with trust_v2, got %q", result.Algorithm)
+	}
+}
+
+func TestHandleGetAlgorithms(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	handler := NewTrustHandler(store, "EORG123", nil)
+
+	w := httptest.NewRecorder()
+	handler.HandleGetAlgorithms(w, httptest.NewRequest(http.MethodGet, "/api/v1/trust/algorithms", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var result AlgorithmsResponse
+	json.NewDecoder(w.Body).Decode(&result)
+	if result.Default != trust.AlgorithmLinear {
+		t.Errorf("expected linear default, got %q", result.Default)
+	}
+	found := map[trust.Algorithm]bool{}
+	for _, a := range result.Algorithms {
+		found[a] = true
+	}
+	if !found[trust.AlgorithmLinear] || !found[trust.AlgorithmPageRank] {
+		t.Errorf("expected built-in algorithms, got %v", result.Algorithms)
+	}
+}
+
+func TestHandleWeights(t *testing.T) {
+	orgConfig := NewOrgConfigHandler(t.TempDir(), nil)
+	handler := NewTrustHandler(nil, "EORG123", nil)
+	handler.SetWeightsSource(orgConfig)
+	serverWeights := trust.DefaultWeights()
+	serverWeights.Contribution = 0.5
+	handler.SetDefaultWeights(serverWeights)
+
+	call := func(method, body string) (int, TrustWeightsResponse) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		handler.HandleWeights(w, httptest.NewRequest(method, "/api/v1/trust/weights", strings.NewReader(body)))
+		var resp TrustWeightsResponse
+		json.NewDecoder(w.Body).Decode(&resp)
+		return w.Code, resp
+	}
+
+	code, resp := call(http.MethodGet, "")
+	if code != http.StatusOK || resp.Source != "server" || resp.Weights != serverWeights {
+		t.Fatalf("expected the server weights, got %d %+v", code, resp)
+	}
+	if handler.scoreCalculator().Weights() != serverWeights {
+		t.Error("expected scores to use the server weights")
+	}
+
+	// The org must be configured to hold its own weights
+	if code, _ := call(http.MethodPut, `{"uniqueIssuer": 1.5}`); code != http.StatusConflict {
+		t.Errorf("expected 409 without an org, got %d", code)
+	}
+	orgConfig.cache = &OrgConfigData{Organization: OrgInfo{AID: "EORG123", Name: "Org"}}
+
+	if code, _ := call(http.MethodPut, `{"uniqueIssuer": -1}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a negative weight, got %d", code)
+	}
+	code, resp = call(http.MethodPut, `{"uniqueIssuer": 1.5}`)
+	want := trust.DefaultWeights()
+	want.UniqueIssuer = 1.5
+	if code != http.StatusOK || resp.Source != "org" || resp.Weights != want || resp.ServerWeights != serverWeights {
+		t.Fatalf("expected the org weights over the built-in defaults, got %d %+v", code, resp)
+	}
+	if handler.scoreCalculator().Weights() != want {
+		t.Error("expected scores to use the org weights")
+	}
+
+	code, resp = call(http.MethodDelete, "")
+	if code != http.StatusOK || resp.Source != "server" || orgConfig.GetTrustWeights() != nil {
+		t.Errorf("expected the org weights to be removed, got %d %+v", code, resp)
+	}
+
+	// Other orgs' handlers follow the server weights
+	tenant := NewTrustHandler(nil, "EORG456", nil)
+	tenant.ShareDefaultWeights(handler)
+	handler.SetDefaultWeights(want)
+	if tenant.scoreCalculator().Weights() != want {
+		t.Error("expected a shared default weights change to reach the other handler")
+	}
+}
+
+func TestHandleExportGraph(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	store.StoreCredential(context.Background(), &anystore.CachedCredential{
+		ID:         "ESAID001",
+		IssuerAID:  "EORG123",
+		SubjectAID: "EUSER1",
+		SchemaID:   "EMatouMembershipSchemaV1",
+		CachedAt:   time.Now(),
+		Data: map[string]interface{}{
+			"role": "Member",
+		},
+	})
+
+	handler := NewTrustHandler(store, "EORG123", nil)
+
+	tests := []struct {
+		query       string
+		contentType string
+		contains    string
+	}{
+		{"", "application/graphml+xml; charset=utf-8", `<edge id="e0" source="EORG123" target="EUSER1">`},
+		{"?format=dot", "text/vnd.graphviz; charset=utf-8", `"EORG123" -> "EUSER1"`},
+		{"?format=csv&table=nodes", "text/csv; charset=utf-8", "EUSER1,"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		handler.HandleExportGraph(w, httptest.NewRequest(http.MethodGet, "/api/v1/trust/graph/export"+tt.query, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", tt.query, w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != tt.contentType {
+			t.Errorf("%s: expected content type %q, got %q", tt.query, tt.contentType, ct)
+		}
+		if !strings.Contains(w.Body.String(), tt.contains) {
+			t.Errorf("%s: expected %q in export, got:\n%s", tt.query, tt.contains, w.Body.String())
+		}
+	}
+
+	for _, query := range []string{"?format=gexf", "?format=csv&table=both", "?algorithm=eigen"} {
+		w := httptest.NewRecorder()
+		handler.HandleExportGraph(w, httptest.NewRequest(http.MethodGet, "/api/v1/trust/graph/export"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestHandleSnapshot(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	store.StoreCredential(context.Background(), &anystore.CachedCredential{
+		ID:         "ESAID001",
+		IssuerAID:  "EORG123",
+		SubjectAID: "EUSER1",
+		SchemaID:   "EMatouMembershipSchemaV1",
+		CachedAt:   time.Now(),
+		Data: map[string]interface{}{
+			"role": "Member",
+		},
+	})
+
+	handler := NewTrustHandler(store, "EORG123", nil)
+	handler.SetFlags(flags.New(map[string]bool{flags.TrustV2: true}, t.TempDir()))
+
+	w := httptest.NewRecorder()
+	handler.HandleGetSnapshot(w, httptest.NewRequest(http.MethodGet, "/api/v1/trust/snapshot?algorithm=pagerank", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var snapshot trust.Snapshot
+	if err := json.Unmarshal(w.Body.Bytes(), &snapshot); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+	if snapshot.Algorithm != trust.AlgorithmPageRank || len(snapshot.Edges) != 1 || !strings.HasPrefix(snapshot.Hash, "sha256:") {
+		t.Errorf("unexpected snapshot: %+v", snapshot)
+	}
+
+	// The exported snapshot verifies as-is
+	exported := w.Body.String()
+	w = httptest.NewRecorder()
+	handler.HandleVerifySnapshot(w, httptest.NewRequest(http.MethodPost, "/api/v1/trust/snapshot/verify", strings.NewReader(exported)))
+	var result trust.SnapshotVerification
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("verify failed: %d %s", w.Code, w.Body.String())
+	}
+	if !result.Valid {
+		t.Errorf("expected exported snapshot to verify, got %+v", result)
+	}
+
+	for _, body := range []string{"not json", `{"version": 99}`} {
+		w := httptest.NewRecorder()
+		handler.HandleVerifySnapshot(w, httptest.NewRequest(http.MethodPost, "/api/v1/trust/snapshot/verify", strings.NewReader(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", body, w.Code)
+		}
+	}
+}
+
 func TestHandleGetSummary(t *testing.T) {
 	store, cleanup := setupTrustTestStore(t)
 	defer cleanup()
@@ -517,6 +789,108 @@ func TestHandleGetSummary_MethodNotAllowed(t *testing.T) {
 	}
 }
 
+func TestHandleGetPath(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for i, aid := range []string{"EUSER1", "EUSER2", "EUSER3"} {
+		store.StoreCredential(ctx, &anystore.CachedCredential{
+			ID:         fmt.Sprintf("ESAIDM%d", i),
+			IssuerAID:  "EORG123",
+			SubjectAID: aid,
+			SchemaID:   "EMatouMembershipSchemaV1",
+			CachedAt:   time.Now(),
+			Data:       map[string]interface{}{"role": "Member"},
+		})
+	}
+	store.StoreCredential(ctx, &anystore.CachedCredential{
+		ID:         "ESAIDI1",
+		IssuerAID:  "EUSER1",
+		SubjectAID: "EUSER2",
+		SchemaID:   "EInvitationSchemaV1",
+		CachedAt:   time.Now(),
+	})
+
+	handler := NewTrustHandler(store, "EORG123", nil)
+	get := func(query string) (int, TrustPathResponse) {
+		w := httptest.NewRecorder()
+		handler.HandleGetPath(w, httptest.NewRequest(http.MethodGet, "/api/v1/trust/path?"+query, nil))
+		var result TrustPathResponse
+		json.NewDecoder(w.Result().Body).Decode(&result)
+		return w.Result().StatusCode, result
+	}
+
+	status, result := get("from=EUSER2&to=EUSER1")
+	if status != http.StatusOK || !result.Connected || result.Distance != 1 || len(result.Paths) != 1 {
+		t.Fatalf("expected one direct path, got %d %+v", status, result)
+	}
+	if creds := result.Paths[0].Hops[0].Credentials; len(creds) != 1 || creds[0].CredentialID != "ESAIDI1" {
+		t.Errorf("expected the invitation to form the hop, got %+v", creds)
+	}
+
+	// EUSER3 is only connected through the org
+	status, result = get("from=EUSER1&to=EUSER3")
+	if status != http.StatusOK || result.Connected || result.Paths == nil || len(result.Paths) != 0 {
+		t.Errorf("expected no path, got %d %+v", status, result)
+	}
+
+	if status, _ := get("from=EUSER1&to=ENONEXISTENT"); status != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", status)
+	}
+	if status, _ := get("from=EUSER1"); status != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", status)
+	}
+}
+
+func TestHandleGetAnomalies(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	store.StoreCredential(ctx, &anystore.CachedCredential{
+		ID:         "ESAID001",
+		IssuerAID:  "EORG123",
+		SubjectAID: "EUSER1",
+		SchemaID:   "EMatouMembershipSchemaV1",
+		CachedAt:   time.Now(),
+		Data:       map[string]interface{}{"role": "Member"},
+	})
+	store.StoreCredential(ctx, &anystore.CachedCredential{
+		ID:         "ESAID002",
+		IssuerAID:  "EOUTSIDER",
+		SubjectAID: "EUSER2",
+		SchemaID:   "EInvitationSchemaV1",
+		CachedAt:   time.Now(),
+	})
+
+	handler := NewTrustHandler(store, "EORG123", nil)
+	get := func(query string) (int, AnomaliesResponse) {
+		w := httptest.NewRecorder()
+		handler.HandleGetAnomalies(w, httptest.NewRequest(http.MethodGet, "/api/v1/trust/anomalies"+query, nil))
+		var result AnomaliesResponse
+		json.NewDecoder(w.Result().Body).Decode(&result)
+		return w.Result().StatusCode, result
+	}
+
+	status, result := get("?kind=unrooted")
+	if status != http.StatusOK || result.Total != 2 {
+		t.Fatalf("expected EOUTSIDER and EUSER2 to be unrooted, got %d %+v", status, result)
+	}
+	for _, a := range result.Anomalies {
+		if a.AIDs[0] == "EUSER1" {
+			t.Error("EUSER1 holds a membership from the org and is rooted")
+		}
+	}
+
+	if _, result := get("?kind=endorsement_ring"); result.Anomalies == nil || result.Total != 0 {
+		t.Errorf("expected an empty list of rings, got %+v", result)
+	}
+	if status, _ := get("?kind=bogus"); status != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", status)
+	}
+}
+
 func TestTrustHandler_RegisterRoutes(t *testing.T) {
 	store, cleanup := setupTrustTestStore(t)
 	defer cleanup()
@@ -532,8 +906,15 @@ func TestTrustHandler_RegisterRoutes(t *testing.T) {
 		status int
 	}{
 		{"/api/v1/trust/graph", http.StatusOK},
+		{"/api/v1/trust/graph/export", http.StatusOK},
+		{"/api/v1/trust/snapshot", http.StatusOK},
+		{"/api/v1/trust/snapshot/verify", http.StatusMethodNotAllowed},
 		{"/api/v1/trust/scores", http.StatusOK},
 		{"/api/v1/trust/summary", http.StatusOK},
+		{"/api/v1/trust/algorithms", http.StatusOK},
+		{"/api/v1/trust/path", http.StatusBadRequest},
+		{"/api/v1/trust/anomalies", http.StatusOK},
+		{"/api/v1/trust/federation", http.StatusOK},
 	}
 
 	for _, tt := range tests {
@@ -548,3 +929,128 @@ func TestTrustHandler_RegisterRoutes(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleGetTerms(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	store.StoreCredential(ctx, &anystore.CachedCredential{
+		ID:         "ESTEWARD1",
+		IssuerAID:  "EORG123",
+		SubjectAID: "EUSER1",
+		SchemaID:   "EMatouStewardSchemaV1",
+		Data: map[string]interface{}{
+			"role":       "Operations Steward",
+			"termEndsAt": now.Add(48 * time.Hour).Format(time.RFC3339),
+		},
+	})
+	store.StoreCredential(ctx, &anystore.CachedCredential{
+		ID:         "ESTEWARD2",
+		IssuerAID:  "EORG123",
+		SubjectAID: "EUSER2",
+		SchemaID:   "EMatouStewardSchemaV1",
+		Data: map[string]interface{}{
+			"role":       "Operations Steward",
+			"termEndsAt": now.Add(200 * 24 * time.Hour).Format(time.RFC3339),
+		},
+	})
+
+	handler := NewTrustHandler(store, "EORG123", nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/trust/terms?status=expiring", nil)
+	w := httptest.NewRecorder()
+	handler.HandleGetTerms(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var result TermsResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	if result.Total != 1 {
+		t.Fatalf("expected 1 expiring term, got %d", result.Total)
+	}
+	if result.Terms[0].HolderAID != "EUSER1" {
+		t.Errorf("expected EUSER1, got %s", result.Terms[0].HolderAID)
+	}
+}
+
+// staticFederation is a fixed FederationSource for tests
+type staticFederation []trust.FederatedOrg
+
+func (s staticFederation) GetFederation() []trust.FederatedOrg { return s }
+
+func TestHandleFederationKEL(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	handler := NewTrustHandler(store, "EORG123", nil)
+	handler.SetFederationSource(staticFederation{
+		{AID: "EPEER1", Name: "Peer One", Schemas: []string{"EMatouMembershipSchemaV1"}},
+	})
+
+	ctx := context.Background()
+	store.StoreCredential(ctx, &anystore.CachedCredential{
+		ID:         "ESAID001",
+		IssuerAID:  "EPEER1",
+		SubjectAID: "EUSER1",
+		SchemaID:   "EMatouMembershipSchemaV1",
+		CachedAt:   time.Now(),
+	})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/trust/federation/kel", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		handler.HandleFederationKEL(w, req)
+		return w
+	}
+
+	// Unconfigured orgs are rejected
+	if w := post(`{"aid":"EOTHER","kel":[{"type":"icp","sequence":0,"digest":"EOTHER"}]}`); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown peer, got %d", w.Code)
+	}
+
+	// Before the KEL is synced the peer is unverified and its credentials ignored
+	graph, err := handler.newBuilder(ctx).Build(ctx)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if graph.EdgeCount() != 0 {
+		t.Errorf("expected no edges from unverified peer, got %d", graph.EdgeCount())
+	}
+
+	w := post(`{"aid":"EPEER1","kel":[{"type":"icp","sequence":0,"digest":"EPEER1"}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var status trust.FederationStatus
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !status.Verified {
+		t.Errorf("expected peer to be verified, got error %q", status.Error)
+	}
+
+	graph, err = handler.newBuilder(ctx).Build(ctx)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if graph.EdgeCount() != 1 || graph.Edges[0].Type != trust.EdgeTypeFederated {
+		t.Errorf("expected one federated edge, got %+v", graph.Edges)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/trust/federation", nil)
+	rec := httptest.NewRecorder()
+	handler.HandleFederation(rec, req)
+	var list FederationResponse
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if list.Total != 1 || !list.Peers[0].Verified {
+		t.Errorf("unexpected federation list: %+v", list)
+	}
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/matou-dao/backend/internal/trust"
+)
+
+// errNotMaterialized is returned when materializing is asked of a handler
+// that doesn't keep a materialized graph
+var errNotMaterialized = errors.New("trust graph materialization is not enabled")
+
+// materializedGraph tracks the trust graph kept in the store's trust node
+// and edge collections, so reads can load it instead of rebuilding the
+// graph from every credential
+type materializedGraph struct {
+	// maxAge is how long a materialized graph is used without a credential
+	// write, since terms lapse with time alone
+	maxAge time.Duration
+
+	// mu is held for reading while the stored graph is read and for
+	// writing while it is updated, so reads never see half an update
+	mu    sync.RWMutex
+	state *materializedState
+}
+
+// materializedState is what the stored graph was built from
+type materializedState struct {
+	revision   uint64 // Store credential revision
+	federation string // Trusted peer orgs
+	weights    trust.ScoreWeights
+	algorithm  trust.Algorithm
+	at         time.Time
+}
+
+// EnableMaterializedGraph makes the handler read the trust graph from the
+// store once MaterializeGraph has stored it, for as long as no credential
+// is written, the federation is unchanged and maxAge hasn't passed
+func (h *TrustHandler) EnableMaterializedGraph(maxAge time.Duration) {
+	h.materialized = &materializedGraph{maxAge: maxAge}
+}
+
+// MaterializeGraph builds the trust graph from the credentials and stores
+// it with the org's scores, writing only what changed since the last time
+func (h *TrustHandler) MaterializeGraph(ctx context.Context) (*trust.MaterializeResult, error) {
+	m := h.materialized
+	if m == nil || h.store == nil {
+		return nil, errNotMaterialized
+	}
+
+	// Read the revision first, so a write made during the build leaves
+	// the result stale rather than marking it current
+	state := &materializedState{
+		revision:   h.store.CredentialRevision(),
+		federation: fmt.Sprint(h.federatedOrgs()),
+	}
+	graph, err := h.newBuilder(ctx).Build(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trust graph: %w", err)
+	}
+	calculator := h.scoreCalculator()
+	state.weights, state.algorithm = calculator.Weights(), calculator.Algorithm()
+	scores := calculator.CalculateAllScores(graph)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result, err := trust.Materialize(ctx, h.store, graph, scores)
+	if err != nil {
+		m.state = nil
+		return nil, err
+	}
+	state.at = time.Now()
+	m.state = state
+	return result, nil
+}
+
+// NeedsMaterializing reports whether the stored graph is missing or out of
+// date with the credentials, federation, score settings or maxAge
+func (h *TrustHandler) NeedsMaterializing() bool {
+	m := h.materialized
+	if m == nil {
+		return false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return !h.graphCurrent(m.state) || !h.scoresCurrent(m.state, h.scoreCalculator())
+}
+
+// graphCurrent reports whether the stored graph matches what a build
+// would give now. Credentials still only in the community tree aren't
+// tracked by the store's revision, so it is never current until they are
+// cached.
+func (h *TrustHandler) graphCurrent(state *materializedState) bool {
+	return state != nil &&
+		state.revision == h.store.CredentialRevision() &&
+		state.federation == fmt.Sprint(h.federatedOrgs()) &&
+		time.Since(state.at) < h.materialized.maxAge
+}
+
+// scoresCurrent reports whether the stored scores are the ones calculator
+// gives. Contributions aren't stored, so scores crediting them never are.
+func (h *TrustHandler) scoresCurrent(state *materializedState, calculator *trust.Calculator) bool {
+	return state != nil &&
+		state.weights == calculator.Weights() &&
+		state.algorithm == calculator.Algorithm() &&
+		state.weights.Contribution == 0
+}
+
+// communityCached reports whether every credential a build reads is in the
+// store, rather than some coming from the community tree
+func (h *TrustHandler) communityCached() bool {
+	return h.spaceManager == nil || (h.cache != nil && h.cache.CommunityCredentialsCached())
+}
+
+// buildGraph returns the full trust graph, loading the materialized graph
+// when it is current and building it from the credentials otherwise
+func (h *TrustHandler) buildGraph(ctx context.Context) (*trust.Graph, error) {
+	if m := h.materialized; m != nil && h.communityCached() {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		if h.graphCurrent(m.state) {
+			graph, err := trust.LoadMaterialized(ctx, h.store, h.orgAID)
+			if err == nil {
+				return graph, nil
+			}
+			fmt.Printf("[Trust] Warning: failed to load materialized graph: %v\n", err)
+		}
+	}
+	return h.newBuilder(ctx).Build(ctx)
+}
+
+// topScores returns the limit highest scores in graph. When graph is the
+// current materialized graph and calculator scores as it was stored with,
+// the top AIDs are queried from the store and only they are scored.
+func (h *TrustHandler) topScores(ctx context.Context, graph *trust.Graph, calculator *trust.Calculator, limit int) []*trust.Score {
+	if m := h.materialized; m != nil && h.communityCached() {
+		m.mu.RLock()
+		current := h.graphCurrent(m.state) && h.scoresCurrent(m.state, calculator)
+		var top []string
+		if current {
+			nodes, err := h.store.TopGraphNodes(ctx, limit)
+			for _, n := range nodes {
+				// The graph may predate the stored one
+				if graph.GetNode(n.AID) == nil {
+					err = errNotMaterialized
+				}
+				top = append(top, n.AID)
+			}
+			current = err == nil
+		}
+		m.mu.RUnlock()
+		if current && len(top) == min(limit, graph.NodeCount()) {
+			return calculator.CalculateScores(graph, top)
+		}
+	}
+	return calculator.GetTopScores(graph, limit)
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/trust"
+)
+
+func TestMaterializedGraph(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, cred := range []*anystore.CachedCredential{
+		{ID: "ESAID001", IssuerAID: "EORG123", SubjectAID: "EUSER1", SchemaID: "EMatouMembershipSchemaV1",
+			CachedAt: time.Now(), Data: map[string]interface{}{"role": "Member"}},
+		{ID: "ESAID002", IssuerAID: "EORG123", SubjectAID: "EUSER2", SchemaID: "EMatouMembershipSchemaV1",
+			CachedAt: time.Now(), Data: map[string]interface{}{"role": "Member"}},
+		{ID: "ESAID003", IssuerAID: "EUSER2", SubjectAID: "EUSER1", SchemaID: "EInvitationSchemaV1",
+			CachedAt: time.Now(), Data: map[string]interface{}{"role": "Member"}},
+	} {
+		store.StoreCredential(ctx, cred)
+	}
+
+	handler := NewTrustHandler(store, "EORG123", nil)
+	if _, err := handler.MaterializeGraph(ctx); err != errNotMaterialized {
+		t.Fatalf("expected materializing to be off by default, got %v", err)
+	}
+
+	handler.EnableMaterializedGraph(time.Hour)
+	if !handler.NeedsMaterializing() {
+		t.Fatal("expected the graph to need materializing before the first time")
+	}
+	result, err := handler.MaterializeGraph(ctx)
+	if err != nil {
+		t.Fatalf("MaterializeGraph failed: %v", err)
+	}
+	if result.NodesWritten != 3 || result.EdgesWritten != 3 || handler.NeedsMaterializing() {
+		t.Fatalf("expected the whole graph stored and current, got %+v", result)
+	}
+
+	// Reads come from the stored graph, so a node removed from it is missing
+	store.DeleteGraphNodes(ctx, []string{"EUSER2"})
+	graph, err := handler.buildGraph(ctx)
+	if err != nil {
+		t.Fatalf("buildGraph failed: %v", err)
+	}
+	if graph.GetNode("EUSER2") != nil || graph.EdgeCount() != 3 {
+		t.Errorf("expected the stored graph to be read, got %d nodes", graph.NodeCount())
+	}
+	handler.MaterializeGraph(ctx)
+	graph, _ = handler.buildGraph(ctx)
+
+	w := httptest.NewRecorder()
+	handler.HandleGetScores(w, httptest.NewRequest(http.MethodGet, "/api/v1/trust/scores?limit=2", nil))
+	var scores ScoresResponse
+	json.NewDecoder(w.Result().Body).Decode(&scores)
+	want := trust.NewDefaultCalculator().GetTopScores(graph, 2)
+	if len(scores.Scores) != 2 || scores.Scores[0].AID != want[0].AID || scores.Scores[1].Score != want[1].Score {
+		t.Errorf("expected the stored top scores to match calculated ones, got %+v", scores.Scores)
+	}
+
+	// A credential write makes reads rebuild until it is materialized
+	store.StoreCredential(ctx, &anystore.CachedCredential{
+		ID: "ESAID004", IssuerAID: "EORG123", SubjectAID: "EUSER3", SchemaID: "EMatouMembershipSchemaV1",
+		CachedAt: time.Now(), Data: map[string]interface{}{"role": "Member"},
+	})
+	if !handler.NeedsMaterializing() {
+		t.Error("expected a credential write to need materializing")
+	}
+	if graph, _ := handler.buildGraph(ctx); graph.GetNode("EUSER3") == nil {
+		t.Error("expected the new member in the rebuilt graph")
+	}
+	result, err = handler.MaterializeGraph(ctx)
+	if err != nil {
+		t.Fatalf("MaterializeGraph failed: %v", err)
+	}
+	if result.EdgesWritten != 1 || result.EdgesDeleted != 0 {
+		t.Errorf("expected only the new credential's edge written, got %+v", result)
+	}
+
+	// Changed weights leave the stored scores stale
+	weights := trust.DefaultWeights()
+	weights.UniqueIssuer = 5
+	handler.SetDefaultWeights(weights)
+	if !handler.NeedsMaterializing() {
+		t.Error("expected new weights to need materializing")
+	}
+}
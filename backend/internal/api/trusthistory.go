@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+// errNoHistoryStore is returned when score history is used without a
+// local store
+var errNoHistoryStore = errors.New("trust score history is not available")
+
+// ScoreHistoryPoint is an AID's score as recorded by one history snapshot
+type ScoreHistoryPoint struct {
+	TakenAt    time.Time `json:"takenAt"`
+	Score      float64   `json:"score"`
+	GraphDepth int       `json:"graphDepth"`
+	Algorithm  string    `json:"algorithm"`
+}
+
+// ScoreHistoryResponse is the response for GET /api/v1/trust/score/{aid}/history
+type ScoreHistoryResponse struct {
+	AID    string              `json:"aid"`
+	Points []ScoreHistoryPoint `json:"points"`
+	Total  int                 `json:"total"`
+}
+
+// RecordScoreHistory records every AID's current score, calculated with
+// the org's weights and algorithm, and returns how many were recorded
+func (h *TrustHandler) RecordScoreHistory(ctx context.Context) (int, error) {
+	if h.store == nil {
+		return 0, errNoHistoryStore
+	}
+
+	graph, err := h.buildGraph(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build trust graph: %w", err)
+	}
+	h.addContributions(ctx, graph)
+
+	calculator := h.scoreCalculator()
+	takenAt := time.Now()
+	scores := calculator.CalculateAllScores(graph)
+	points := make([]*anystore.TrustScorePoint, 0, len(scores))
+	for aid, score := range scores {
+		points = append(points, &anystore.TrustScorePoint{
+			AID:       aid,
+			Score:     score.Score,
+			Depth:     score.GraphDepth,
+			Algorithm: string(calculator.Algorithm()),
+			TakenAt:   takenAt,
+		})
+	}
+	if err := h.store.StoreTrustScores(ctx, points); err != nil {
+		return 0, err
+	}
+	return len(points), nil
+}
+
+// LastScoreHistory returns when scores were last recorded, or the zero
+// time if they never have been
+func (h *TrustHandler) LastScoreHistory(ctx context.Context) (time.Time, error) {
+	if h.store == nil {
+		return time.Time{}, errNoHistoryStore
+	}
+	return h.store.LastTrustScoresAt(ctx)
+}
+
+// HandleGetScoreHistory handles GET /api/v1/trust/score/{aid}/history
+// Query params:
+//   - since: RFC 3339 time of the earliest point (optional)
+//   - until: RFC 3339 time of the latest point (optional)
+func (h *TrustHandler) HandleGetScoreHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+		return
+	}
+
+	aid := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/trust/score/"), "/history")
+	if aid == "" || strings.Contains(aid, "/") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": "AID is required in path: /api/v1/trust/score/{aid}/history",
+		})
+		return
+	}
+
+	var since, until time.Time
+	for name, t := range map[string]*time.Time{"since": &since, "until": &until} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("%s must be an RFC 3339 time", name),
+			})
+			return
+		}
+		*t = parsed
+	}
+
+	if h.store == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": errNoHistoryStore.Error(),
+		})
+		return
+	}
+	recorded, err := h.store.ListTrustScores(r.Context(), aid, since, until)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	points := make([]ScoreHistoryPoint, 0, len(recorded))
+	for _, p := range recorded {
+		points = append(points, ScoreHistoryPoint{
+			TakenAt:    p.TakenAt,
+			Score:      p.Score,
+			GraphDepth: p.Depth,
+			Algorithm:  p.Algorithm,
+		})
+	}
+	writeJSON(w, http.StatusOK, ScoreHistoryResponse{
+		AID:    aid,
+		Points: points,
+		Total:  len(points),
+	})
+}
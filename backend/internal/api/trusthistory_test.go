@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+func TestScoreHistory(t *testing.T) {
+	store, cleanup := setupTrustTestStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	store.StoreCredential(ctx, &anystore.CachedCredential{
+		ID:         "ESAID001",
+		IssuerAID:  "EORG123",
+		SubjectAID: "EUSER1",
+		SchemaID:   "EMatouMembershipSchemaV1",
+		CachedAt:   time.Now(),
+		Data:       map[string]interface{}{"role": "Member"},
+	})
+	lastWeek := time.Now().UTC().Add(-7 * 24 * time.Hour).Truncate(time.Second)
+	store.StoreTrustScores(ctx, []*anystore.TrustScorePoint{
+		{AID: "EUSER1", Score: 1, Algorithm: "linear", TakenAt: lastWeek},
+	})
+
+	handler := NewTrustHandler(store, "EORG123", nil)
+	recorded, err := handler.RecordScoreHistory(ctx)
+	if err != nil {
+		t.Fatalf("RecordScoreHistory failed: %v", err)
+	}
+	if recorded != 2 {
+		t.Errorf("expected the org and EUSER1 to be recorded, got %d", recorded)
+	}
+	if last, err := handler.LastScoreHistory(ctx); err != nil || time.Since(last) > time.Minute {
+		t.Errorf("expected the snapshot just taken, got %v (%v)", last, err)
+	}
+
+	get := func(target string) (*http.Response, ScoreHistoryResponse) {
+		w := httptest.NewRecorder()
+		handler.HandleGetScore(w, httptest.NewRequest(http.MethodGet, target, nil))
+		var result ScoreHistoryResponse
+		json.NewDecoder(w.Result().Body).Decode(&result)
+		return w.Result(), result
+	}
+
+	resp, result := get("/api/v1/trust/score/EUSER1/history")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if result.AID != "EUSER1" || result.Total != 2 {
+		t.Fatalf("expected two points for EUSER1, got %+v", result)
+	}
+	if !result.Points[0].TakenAt.Equal(lastWeek) || result.Points[1].Score <= result.Points[0].Score {
+		t.Errorf("expected last week's point first and a higher score now, got %+v", result.Points)
+	}
+
+	_, result = get("/api/v1/trust/score/EUSER1/history?since=" + lastWeek.Add(time.Hour).Format(time.RFC3339))
+	if result.Total != 1 || result.Points[0].GraphDepth != 1 {
+		t.Errorf("expected only the new point, got %+v", result.Points)
+	}
+
+	_, result = get("/api/v1/trust/score/EUNKNOWN/history")
+	if result.Total != 0 || result.Points == nil {
+		t.Errorf("expected an empty history, got %+v", result)
+	}
+
+	for _, target := range []string{"/api/v1/trust/score//history", "/api/v1/trust/score/EUSER1/history?until=yesterday"} {
+		if resp, _ := get(target); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", target, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anysync"
+)
+
+const (
+	// maxChunkBytes bounds the body of one chunk append
+	maxChunkBytes = 8 * anysync.UploadChunkSize
+	// uploadTTL is how long an idle chunked upload is kept before it is
+	// discarded
+	uploadTTL = 24 * time.Hour
+	// sniffBytes is how much of a file's start is kept to check its type
+	sniffBytes = 512
+)
+
+// uploadSession is a chunked upload in progress
+type uploadSession struct {
+	// mu serializes appends and completion
+	mu          sync.Mutex
+	upload      *anysync.ChunkedUpload
+	owner       string
+	size        int64
+	contentType string
+	head        []byte
+	expiresAt   time.Time
+	done        bool
+}
+
+// SetMaxUploadSize caps the size of a chunked upload (0 = no chunked
+// uploads)
+func (h *FilesHandler) SetMaxUploadSize(bytes int64) {
+	h.maxUpload = bytes
+}
+
+// HandleUploads handles POST /api/v1/files/uploads, starting a chunked
+// upload of a file of known size. Parts are then appended with PUT and the
+// upload completed with POST .../complete.
+func (h *FilesHandler) HandleUploads(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	if h.fileManager == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "file storage not available (filenode not configured)",
+		})
+		return
+	}
+
+	var req struct {
+		Size        int64  `json:"size"`
+		ContentType string `json:"contentType"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+	if req.Size <= 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "size must be positive"})
+		return
+	}
+	if req.Size > h.maxUpload {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{
+			"error": fmt.Sprintf("file exceeds the %d byte upload limit", h.maxUpload),
+		})
+		return
+	}
+
+	spaceID := h.spaceManager.GetCommunitySpaceID()
+	if spaceID == "" {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "community space not configured",
+		})
+		return
+	}
+
+	owner := uploadOwner(r)
+	if h.quota > 0 {
+		used, err := h.fileManager.Usage(r.Context(), spaceID, owner, h.spaceManager.GetClient().GetSigningKey().GetPublic().Account())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"error": fmt.Sprintf("failed to check upload quota: %v", err),
+			})
+			return
+		}
+		if used+req.Size > h.quota {
+			writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{
+				"error": fmt.Sprintf("upload quota exceeded: %d of %d bytes used, upload needs %d", used, h.quota, req.Size),
+			})
+			return
+		}
+	}
+
+	session := &uploadSession{
+		upload:      h.fileManager.NewChunkedUpload(spaceID),
+		owner:       owner,
+		size:        req.Size,
+		contentType: req.ContentType,
+		expiresAt:   time.Now().Add(uploadTTL),
+	}
+	resp := session.status()
+	h.uploadsMu.Lock()
+	h.expireUploads()
+	if h.uploads == nil {
+		h.uploads = make(map[string]*uploadSession)
+	}
+	h.uploads[session.upload.FileID] = session
+	h.uploadsMu.Unlock()
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// HandleUploadSession routes /api/v1/files/uploads/{id}[/complete]
+func (h *FilesHandler) HandleUploadSession(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/files/uploads/")
+	id, action, _ := strings.Cut(path, "/")
+
+	session := h.uploadSession(r, id)
+	if session == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "upload not found"})
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		session.mu.Lock()
+		defer session.mu.Unlock()
+		writeJSON(w, http.StatusOK, session.status())
+	case action == "" && r.Method == http.MethodPut:
+		h.handleAppend(w, r, session)
+	case action == "" && r.Method == http.MethodDelete:
+		h.handleAbort(w, r, session)
+	case action == "complete" && r.Method == http.MethodPost:
+		h.handleComplete(w, r, session)
+	case action == "" || action == "complete":
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
+
+// handleAppend handles PUT /api/v1/files/uploads/{id}?offset=N. The body is
+// the next part of the file, starting at offset, which must be the number
+// of bytes received so far; after an interruption the client reads it with
+// GET and resumes from there. Every part but the last must be a multiple
+// of chunkSize.
+func (h *FilesHandler) handleAppend(w http.ResponseWriter, r *http.Request, session *uploadSession) {
+	offset, err := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "offset is required"})
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxChunkBytes))
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{
+			"error": fmt.Sprintf("chunk exceeds %d bytes", maxChunkBytes),
+		})
+		return
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.done {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "upload already completed"})
+		return
+	}
+	received := session.upload.Received
+	if offset != received {
+		writeJSON(w, http.StatusConflict, map[string]any{
+			"error":    fmt.Sprintf("offset %d does not match the %d bytes received", offset, received),
+			"received": received,
+		})
+		return
+	}
+	switch end := received + int64(len(data)); {
+	case len(data) == 0:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "chunk is empty"})
+		return
+	case end > session.size:
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("chunk runs past the declared size of %d bytes", session.size),
+		})
+		return
+	case end < session.size && len(data)%anysync.UploadChunkSize != 0:
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("chunks before the last must be a multiple of %d bytes", anysync.UploadChunkSize),
+		})
+		return
+	}
+
+	if err := h.fileManager.AppendChunk(r.Context(), session.upload, data); err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]string{
+			"error": fmt.Sprintf("failed to store chunk: %v", err),
+		})
+		return
+	}
+	if received == 0 {
+		session.head = append([]byte(nil), data[:min(len(data), sniffBytes)]...)
+	}
+	session.expiresAt = time.Now().Add(uploadTTL)
+	writeJSON(w, http.StatusOK, session.status())
+}
+
+// handleComplete handles POST /api/v1/files/uploads/{id}/complete once every
+// byte is received, storing the file like a single upload. Chunked uploads
+// are stored as uploaded, without renditions.
+func (h *FilesHandler) handleComplete(w http.ResponseWriter, r *http.Request, session *uploadSession) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.done {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "upload already completed"})
+		return
+	}
+	if session.upload.Received != session.size {
+		writeJSON(w, http.StatusConflict, map[string]any{
+			"error":    fmt.Sprintf("upload incomplete: %d of %d bytes received", session.upload.Received, session.size),
+			"received": session.upload.Received,
+		})
+		return
+	}
+
+	contentType, err := sniffContentType(session.contentType, session.head, false)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	signingKey := h.spaceManager.GetClient().GetSigningKey()
+	if h.quota > 0 {
+		h.quotaMu.Lock()
+		defer h.quotaMu.Unlock()
+		used, err := h.fileManager.Usage(r.Context(), session.upload.SpaceID, session.owner, signingKey.GetPublic().Account())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{
+				"error": fmt.Sprintf("failed to check upload quota: %v", err),
+			})
+			return
+		}
+		if used+session.size > h.quota {
+			writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{
+				"error": fmt.Sprintf("upload quota exceeded: %d of %d bytes used, upload needs %d", used, h.quota, session.size),
+			})
+			return
+		}
+	}
+
+	fileRef, err := h.fileManager.CompleteChunkedUpload(r.Context(), session.upload, &anysync.FileMeta{
+		ContentType: contentType,
+		Owner:       session.owner,
+	}, signingKey)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to upload file: %v", err),
+		})
+		return
+	}
+	session.done = true
+	h.removeUpload(session.upload.FileID)
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"fileRef":     fileRef,
+		"contentType": contentType,
+		"size":        fmt.Sprintf("%d", session.size),
+	})
+}
+
+// handleAbort handles DELETE /api/v1/files/uploads/{id}, discarding the
+// upload and the parts stored for it
+func (h *FilesHandler) handleAbort(w http.ResponseWriter, r *http.Request, session *uploadSession) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.done {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "upload already completed"})
+		return
+	}
+	session.done = true
+	h.removeUpload(session.upload.FileID)
+
+	if err := h.fileManager.AbortChunkedUpload(r.Context(), session.upload); err != nil {
+		fmt.Printf("[Files] Warning: failed to delete parts of upload %s: %v\n", session.upload.FileID, err)
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "aborted"})
+}
+
+// uploadSession returns the caller's upload with the given ID, or nil
+func (h *FilesHandler) uploadSession(r *http.Request, id string) *uploadSession {
+	h.uploadsMu.Lock()
+	defer h.uploadsMu.Unlock()
+	session := h.uploads[id]
+	if session == nil || session.owner != uploadOwner(r) {
+		return nil
+	}
+	return session
+}
+
+// removeUpload forgets an upload
+func (h *FilesHandler) removeUpload(id string) {
+	h.uploadsMu.Lock()
+	defer h.uploadsMu.Unlock()
+	delete(h.uploads, id)
+}
+
+// expireUploads discards uploads idle for longer than uploadTTL, deleting
+// their parts in the background. Callers hold uploadsMu.
+func (h *FilesHandler) expireUploads() {
+	now := time.Now()
+	for id, session := range h.uploads {
+		if !session.mu.TryLock() {
+			continue // in use
+		}
+		expired := now.After(session.expiresAt) && !session.done
+		if expired {
+			session.done = true
+			delete(h.uploads, id)
+		}
+		session.mu.Unlock()
+		if expired && h.fileManager != nil {
+			go func(upload *anysync.ChunkedUpload) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				defer cancel()
+				if err := h.fileManager.AbortChunkedUpload(ctx, upload); err != nil {
+					fmt.Printf("[Files] Warning: failed to delete parts of expired upload %s: %v\n", upload.FileID, err)
+				}
+			}(session.upload)
+		}
+	}
+}
+
+// status is the response describing an upload. Callers hold mu.
+func (s *uploadSession) status() map[string]any {
+	return map[string]any{
+		"uploadId":  s.upload.FileID,
+		"size":      s.size,
+		"received":  s.upload.Received,
+		"chunkSize": anysync.UploadChunkSize,
+		"expiresAt": s.expiresAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anysync"
+)
+
+func TestFilesHandler_Uploads_NilFileManager(t *testing.T) {
+	handler := NewFilesHandler(nil, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/files/uploads", strings.NewReader(`{"size":10}`))
+	w := httptest.NewRecorder()
+	handler.HandleUploads(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", w.Code)
+	}
+}
+
+// withUpload registers a chunked upload owned by aid, with received bytes
+// already stored
+func withUpload(h *FilesHandler, aid string, size, received int64) *uploadSession {
+	session := &uploadSession{
+		upload:    &anysync.ChunkedUpload{SpaceID: "space-1", FileID: "upload-1", Received: received},
+		owner:     aid,
+		size:      size,
+		expiresAt: time.Now().Add(uploadTTL),
+	}
+	h.uploads = map[string]*uploadSession{session.upload.FileID: session}
+	return session
+}
+
+func uploadRequest(method, target, aid string, body []byte) *http.Request {
+	req := httptest.NewRequest(method, target, bytes.NewReader(body))
+	return req.WithContext(context.WithValue(req.Context(), principalKey{}, &Principal{Kind: "token", AID: aid}))
+}
+
+func TestFilesHandler_UploadSession(t *testing.T) {
+	handler := NewFilesHandler(nil, nil)
+	withUpload(handler, "EMember", 3*anysync.UploadChunkSize, anysync.UploadChunkSize)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		aid    string
+		body   []byte
+		status int
+	}{
+		{"status", http.MethodGet, "/api/v1/files/uploads/upload-1", "EMember", nil, http.StatusOK},
+		{"another user's upload", http.MethodGet, "/api/v1/files/uploads/upload-1", "EOther", nil, http.StatusNotFound},
+		{"unknown upload", http.MethodGet, "/api/v1/files/uploads/upload-2", "EMember", nil, http.StatusNotFound},
+		{"stale offset", http.MethodPut, "/api/v1/files/uploads/upload-1?offset=0", "EMember", make([]byte, 10), http.StatusConflict},
+		{"missing offset", http.MethodPut, "/api/v1/files/uploads/upload-1", "EMember", make([]byte, 10), http.StatusBadRequest},
+		{"empty chunk", http.MethodPut, "/api/v1/files/uploads/upload-1?offset=1048576", "EMember", nil, http.StatusBadRequest},
+		{"unaligned chunk", http.MethodPut, "/api/v1/files/uploads/upload-1?offset=1048576", "EMember", make([]byte, 10), http.StatusBadRequest},
+		{"past declared size", http.MethodPut, "/api/v1/files/uploads/upload-1?offset=1048576", "EMember", make([]byte, 2*anysync.UploadChunkSize+1), http.StatusBadRequest},
+		{"complete early", http.MethodPost, "/api/v1/files/uploads/upload-1/complete", "EMember", nil, http.StatusConflict},
+		{"wrong method", http.MethodPost, "/api/v1/files/uploads/upload-1", "EMember", nil, http.StatusMethodNotAllowed},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleUploadSession(w, uploadRequest(tc.method, tc.target, tc.aid, tc.body))
+			if w.Code != tc.status {
+				t.Errorf("expected %d, got %d: %s", tc.status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// A client resuming after an interruption learns where to continue
+	w := httptest.NewRecorder()
+	handler.HandleUploadSession(w, uploadRequest(http.MethodPut, "/api/v1/files/uploads/upload-1?offset=0", "EMember", []byte("x")))
+	var resp map[string]any
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp["received"] != float64(anysync.UploadChunkSize) {
+		t.Errorf("expected stale offset to report %d bytes received, got %v", anysync.UploadChunkSize, resp["received"])
+	}
+}
+
+func TestFilesHandler_ExpireUploads(t *testing.T) {
+	handler := NewFilesHandler(nil, nil)
+	session := withUpload(handler, "EMember", 10, 0)
+	session.expiresAt = time.Now().Add(-time.Minute)
+
+	handler.uploadsMu.Lock()
+	handler.expireUploads()
+	handler.uploadsMu.Unlock()
+
+	if len(handler.uploads) != 0 || !session.done {
+		t.Error("expected idle upload discarded")
+	}
+}
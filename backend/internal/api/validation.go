@@ -0,0 +1,311 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Problem is an RFC 7807 problem details body
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Errors lists the invalid request fields
+	Errors []FieldError `json:"errors,omitempty"`
+	// Error repeats Detail for clients that read the {"error": ...} bodies
+	// other endpoints return
+	Error string `json:"error"`
+}
+
+// FieldError is one invalid request field. Field is its JSON path, such as
+// "organization.aid" or "admins[1].name".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors is the field errors for a request. It is an error so
+// Validate methods can return it.
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, e := range v {
+		msgs[i] = e.Field + " " + e.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// writeProblem writes a problem+json response
+func writeProblem(w http.ResponseWriter, status int, detail string, fields ...FieldError) {
+	if detail == "" && len(fields) > 0 {
+		detail = ValidationErrors(fields).Error()
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Errors: fields,
+		Error:  detail,
+	})
+}
+
+// writeInvalid writes a 400 problem for err, with field errors when err
+// holds them
+func writeInvalid(w http.ResponseWriter, err error) {
+	var fields ValidationErrors
+	if errors.As(err, &fields) {
+		writeProblem(w, http.StatusBadRequest, "", fields...)
+		return
+	}
+	writeProblem(w, http.StatusBadRequest, err.Error())
+}
+
+// requestValidator is a request body with checks beyond its struct tags
+type requestValidator interface {
+	Validate() error
+}
+
+// decodeRequest decodes a JSON request body into v and validates it. It
+// writes a 400 problem and returns false when the body is malformed or
+// invalid.
+func decodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeDecodeProblem(w, err)
+		return false
+	}
+	return validRequest(w, v)
+}
+
+// writeDecodeProblem writes a 400 problem for a JSON decoding error,
+// naming the field when the error is a type mismatch
+func writeDecodeProblem(w http.ResponseWriter, err error) {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		writeProblem(w, http.StatusBadRequest, "invalid request: "+err.Error(), FieldError{
+			Field:   typeErr.Field,
+			Message: "must be " + jsonKind(typeErr.Type),
+		})
+		return
+	}
+	if err == io.EOF {
+		err = errors.New("empty body")
+	}
+	writeProblem(w, http.StatusBadRequest, "invalid request: "+err.Error())
+}
+
+// validRequest checks v's validate tags and Validate method. It writes a
+// 400 problem and returns false when v is invalid.
+func validRequest(w http.ResponseWriter, v interface{}) bool {
+	if errs := validateStruct(v); len(errs) > 0 {
+		writeProblem(w, http.StatusBadRequest, "", errs...)
+		return false
+	}
+	if validator, ok := v.(requestValidator); ok {
+		if err := validator.Validate(); err != nil {
+			writeInvalid(w, err)
+			return false
+		}
+	}
+	return true
+}
+
+// jsonKind names a Go type the way a JSON client would see it
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	}
+	return "an object"
+}
+
+// emptyChecker is a field type that knows when it is empty, such as a
+// mnemonic
+type emptyChecker interface {
+	IsEmpty() bool
+}
+
+// validateStruct checks the validate tags on v's fields, descending into
+// nested structs and slices of structs. The rules, comma-separated, are:
+//
+//	required        not zero; strings must not be blank
+//	max=N, min=N    string length or slice length, or the value of a number
+//	oneof=a b c     one of the space-separated strings
+//	rfc3339         a string that is empty or an RFC 3339 timestamp
+//	url             a string that is empty or an absolute http(s) URL
+//
+// Rules other than required are skipped for empty values.
+func validateStruct(v interface{}) ValidationErrors {
+	var errs ValidationErrors
+	validateValue(reflect.ValueOf(v), "", &errs)
+	return errs
+}
+
+func validateValue(v reflect.Value, path string, errs *ValidationErrors) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+	case reflect.Slice, reflect.Array:
+		switch v.Type().Elem().Kind() {
+		case reflect.Struct, reflect.Pointer, reflect.Interface, reflect.Slice:
+		default:
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+		return
+	default:
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fieldPath := name
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			fieldPath = path
+		} else if path != "" {
+			fieldPath = path + "." + name
+		}
+
+		fv := v.Field(i)
+		if rules := field.Tag.Get("validate"); rules != "" {
+			if msg := checkRules(fv, rules); msg != "" {
+				*errs = append(*errs, FieldError{Field: fieldPath, Message: msg})
+				continue
+			}
+		}
+		validateValue(fv, fieldPath, errs)
+	}
+}
+
+// isEmpty reports whether a field value counts as missing
+func isEmpty(v reflect.Value) bool {
+	if v.CanInterface() {
+		if checker, ok := v.Interface().(emptyChecker); ok {
+			if v.Kind() == reflect.Pointer && v.IsNil() {
+				return true
+			}
+			return checker.IsEmpty()
+		}
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// checkRules returns the first rule a field value breaks, or ""
+func checkRules(v reflect.Value, rules string) string {
+	if isEmpty(v) {
+		if strings.Contains(","+rules+",", ",required,") {
+			return "is required"
+		}
+		return ""
+	}
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+		case "max", "min":
+			limit, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				panic(fmt.Sprintf("validate: bad %s rule %q", name, rule))
+			}
+			size, unit := measure(v)
+			if name == "max" && size > limit {
+				return fmt.Sprintf("must be at most %s%s", arg, unit)
+			}
+			if name == "min" && size < limit {
+				return fmt.Sprintf("must be at least %s%s", arg, unit)
+			}
+		case "oneof":
+			options := strings.Fields(arg)
+			if !containsString(options, v.String()) {
+				return "must be one of: " + strings.Join(options, ", ")
+			}
+		case "rfc3339":
+			if _, err := time.Parse(time.RFC3339, v.String()); err != nil {
+				return "must be an RFC 3339 timestamp"
+			}
+		case "url":
+			u, err := url.Parse(v.String())
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return "must be an absolute http(s) URL"
+			}
+		default:
+			panic(fmt.Sprintf("validate: unknown rule %q", rule))
+		}
+	}
+	return ""
+}
+
+// measure returns what min and max compare for a value, and its unit
+func measure(v reflect.Value) (float64, string) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(len(v.String())), " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), ""
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), ""
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), ""
+	}
+	panic(fmt.Sprintf("validate: min/max on %s", v.Kind()))
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
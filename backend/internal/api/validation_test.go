@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type validationAdmin struct {
+	Name string `json:"name" validate:"required,max=5"`
+}
+
+type validationRequest struct {
+	Kind       string            `json:"kind" validate:"required,oneof=a b"`
+	Score      float64           `json:"score" validate:"min=0,max=1"`
+	OccurredAt string            `json:"occurredAt" validate:"rfc3339"`
+	Link       string            `json:"link" validate:"url"`
+	Admins     []validationAdmin `json:"admins"`
+}
+
+func (v *validationRequest) Validate() error {
+	if v.Kind == "b" && v.Link == "" {
+		return ValidationErrors{{Field: "link", Message: "is required for b"}}
+	}
+	return nil
+}
+
+func TestValidateStruct(t *testing.T) {
+	errs := validateStruct(&validationRequest{
+		Kind:       "c",
+		Score:      1.5,
+		OccurredAt: "yesterday",
+		Link:       "ftp://example.com",
+		Admins:     []validationAdmin{{Name: "ok"}, {Name: " "}, {Name: "too long"}},
+	})
+	want := map[string]string{
+		"kind":           "must be one of: a, b",
+		"score":          "must be at most 1",
+		"occurredAt":     "must be an RFC 3339 timestamp",
+		"link":           "must be an absolute http(s) URL",
+		"admins[1].name": "is required",
+		"admins[2].name": "must be at most 5 characters",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d field errors, got %v", len(want), errs)
+	}
+	for _, e := range errs {
+		if want[e.Field] != e.Message {
+			t.Errorf("%s: expected %q, got %q", e.Field, want[e.Field], e.Message)
+		}
+	}
+
+	if errs := validateStruct(&validationRequest{Kind: "a"}); len(errs) != 0 {
+		t.Errorf("expected empty optional fields to pass, got %v", errs)
+	}
+}
+
+func decodeProblem(t *testing.T, body string) (*httptest.ResponseRecorder, Problem) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	var v validationRequest
+	if decodeRequest(w, req, &v) {
+		t.Fatalf("expected %s to be refused", body)
+	}
+	var problem Problem
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatal(err)
+	}
+	return w, problem
+}
+
+func TestDecodeRequest(t *testing.T) {
+	w, problem := decodeProblem(t, `{"kind":"a","score":"high"}`)
+	if w.Code != http.StatusBadRequest || w.Header().Get("Content-Type") != "application/problem+json" {
+		t.Fatalf("expected a 400 problem, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	if len(problem.Errors) != 1 || problem.Errors[0].Field != "score" || problem.Errors[0].Message != "must be a number" {
+		t.Errorf("expected a score type error, got %+v", problem.Errors)
+	}
+	if problem.Error == "" || problem.Error != problem.Detail {
+		t.Errorf("expected error to repeat detail, got %+v", problem)
+	}
+
+	// Validate runs after the tags pass
+	_, problem = decodeProblem(t, `{"kind":"b"}`)
+	if len(problem.Errors) != 1 || problem.Errors[0].Field != "link" || problem.Error != "link is required for b" {
+		t.Errorf("expected the Validate field error, got %+v", problem)
+	}
+
+	_, problem = decodeProblem(t, ``)
+	if problem.Detail != "invalid request: empty body" || problem.Title != "Bad Request" {
+		t.Errorf("unexpected empty body problem %+v", problem)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"kind":"a","score":0.5}`))
+	var v validationRequest
+	if !decodeRequest(httptest.NewRecorder(), req, &v) || v.Score != 0.5 {
+		t.Errorf("expected a valid request to decode, got %+v", v)
+	}
+}
+
+func TestWriteInvalid(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeInvalid(w, errors.New("quorum exceeds approvers"))
+	var problem Problem
+	json.NewDecoder(w.Body).Decode(&problem)
+	if problem.Status != http.StatusBadRequest || problem.Detail != "quorum exceeds approvers" || len(problem.Errors) != 0 {
+		t.Errorf("unexpected problem %+v", problem)
+	}
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+)
+
+// DataVersionHeader carries the version of the data a watchable route
+// served. Send it back as ?version= with ?watch=true to wait for a change.
+const DataVersionHeader = "X-Data-Version"
+
+// watchPoll is how often a waiting request checks the version again
+const watchPoll = 250 * time.Millisecond
+
+// DefaultWatchRoutes are the routes that accept ?watch=true
+var DefaultWatchRoutes = []string{
+	"/api/v1/trust/summary",
+	"/api/v1/community/members",
+}
+
+// DataVersionSource reports the version of an org's data. The version
+// changes whenever anything the watchable routes read from might have.
+type DataVersionSource interface {
+	DataVersion() string
+}
+
+// DataVersion versions an org's data by its store revision and the heads
+// of its community credential tree, which together cover what the member
+// directory and the trust graph are built from
+type DataVersion struct {
+	store        *anystore.LocalStore
+	spaceManager *anysync.SpaceManager
+}
+
+// NewDataVersion creates a version source for an org. spaceManager may be
+// nil for orgs whose credentials are only cached locally.
+func NewDataVersion(store *anystore.LocalStore, spaceManager *anysync.SpaceManager) *DataVersion {
+	return &DataVersion{
+		store:        store,
+		spaceManager: spaceManager,
+	}
+}
+
+// DataVersion returns the current version
+func (v *DataVersion) DataVersion() string {
+	graph := graphVersionCache
+	if v.spaceManager != nil {
+		if spaceID := v.spaceManager.GetCommunitySpaceID(); spaceID != "" {
+			if heads := v.spaceManager.CredentialTreeManager().Heads(spaceID); len(heads) > 0 {
+				graph = graphVersion(heads)
+			}
+		}
+	}
+	return fmt.Sprintf("%d-%s", v.store.Revision(), graph)
+}
+
+// Watcher holds watch requests until their org's data version changes.
+// Each org has its own version source; requests for an org without one
+// are served at once.
+type Watcher struct {
+	primary DataVersionSource
+	timeout time.Duration
+	routes  []string
+
+	mu      sync.RWMutex
+	tenants map[string]DataVersionSource
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// NewWatcher creates a watcher for the primary org's data. Requests wait
+// at most timeout; a non-positive timeout disables watching.
+func NewWatcher(primary DataVersionSource, timeout time.Duration) *Watcher {
+	return &Watcher{
+		primary: primary,
+		timeout: timeout,
+		routes:  DefaultWatchRoutes,
+		tenants: make(map[string]DataVersionSource),
+		closed:  make(chan struct{}),
+	}
+}
+
+// SetOrgVersion sets the version source for an additional org
+func (wt *Watcher) SetOrgVersion(aid string, source DataVersionSource) {
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+	wt.tenants[aid] = source
+}
+
+// Close releases every waiting request, for server shutdown
+func (wt *Watcher) Close() {
+	wt.closeOnce.Do(func() { close(wt.closed) })
+}
+
+// sourceFor returns the version source for the org a request is for
+func (wt *Watcher) sourceFor(r *http.Request) DataVersionSource {
+	t := TenantFromContext(r.Context())
+	if t == nil {
+		return wt.primary
+	}
+	wt.mu.RLock()
+	defer wt.mu.RUnlock()
+	return wt.tenants[t.AID]
+}
+
+// Wait blocks until source's version differs from since, the timeout
+// passes, ctx ends or the watcher closes. It returns the version then.
+func (wt *Watcher) Wait(ctx context.Context, source DataVersionSource, since string, timeout time.Duration) string {
+	version := source.DataVersion()
+	if version != since || timeout <= 0 {
+		return version
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(watchPoll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return version
+		case <-wt.closed:
+			return version
+		case <-timer.C:
+			return version
+		case <-ticker.C:
+			if version = source.DataVersion(); version != since {
+				return version
+			}
+		}
+	}
+}
+
+// WatchMiddleware serves ?watch=true on the watchable routes as a long
+// poll: the request is held until the data version differs from ?version=
+// (or, without one, from the version when the request arrived), then
+// served as usual. After the timeout it is served unchanged, so clients
+// compare DataVersionHeader rather than relying on a change. ?timeout=
+// shortens the wait. It sits outside load shedding and route timeouts, so
+// a waiting request holds neither a slot nor its deadline budget.
+func WatchMiddleware(wt *Watcher, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || !matchesRoute(r.URL.Path, wt.routes) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		source := wt.sourceFor(r)
+		if source == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		query := r.URL.Query()
+		version := source.DataVersion()
+		if query.Get("watch") == "true" {
+			timeout := wt.timeout
+			if s := query.Get("timeout"); s != "" {
+				d, err := time.ParseDuration(s)
+				if err != nil || d < 0 {
+					writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid timeout"})
+					return
+				}
+				timeout = min(d, timeout)
+			}
+			since := query.Get("version")
+			if since == "" {
+				since = version
+			}
+			version = wt.Wait(r.Context(), source, since, timeout)
+			if r.Context().Err() != nil {
+				return
+			}
+		}
+
+		w.Header().Set(DataVersionHeader, version)
+		next.ServeHTTP(w, r)
+	})
+}
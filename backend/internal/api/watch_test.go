@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeVersion is a version source whose version the test bumps
+type fakeVersion struct {
+	n atomic.Int64
+}
+
+func (f *fakeVersion) DataVersion() string {
+	return strconv.FormatInt(f.n.Load(), 10)
+}
+
+func TestWatchMiddleware(t *testing.T) {
+	source := &fakeVersion{}
+	watcher := NewWatcher(source, time.Second)
+	var served atomic.Int32
+	handler := WatchMiddleware(watcher, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	w := serve("/api/v1/trust/summary")
+	if w.Header().Get(DataVersionHeader) != "0" {
+		t.Errorf("expected version 0 on a plain read, got %q", w.Header().Get(DataVersionHeader))
+	}
+
+	// A watch is held until the data changes
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		source.n.Add(1)
+	}()
+	start := time.Now()
+	w = serve("/api/v1/community/members?watch=true&version=0")
+	if w.Header().Get(DataVersionHeader) != "1" {
+		t.Errorf("expected the changed version, got %q", w.Header().Get(DataVersionHeader))
+	}
+	if time.Since(start) < 100*time.Millisecond {
+		t.Error("expected the watch held until the change")
+	}
+
+	// An older version is answered at once
+	start = time.Now()
+	serve("/api/v1/trust/summary?watch=true&version=0")
+	if time.Since(start) > watchPoll {
+		t.Error("expected a stale version answered without waiting")
+	}
+
+	// Without a change the watch is served after the timeout
+	w = serve("/api/v1/trust/summary?watch=true&timeout=50ms")
+	if w.Code != http.StatusOK || w.Header().Get(DataVersionHeader) != "1" {
+		t.Errorf("expected the unchanged data after the timeout, got %d %q", w.Code, w.Header().Get(DataVersionHeader))
+	}
+
+	if w := serve("/api/v1/trust/summary?watch=true&timeout=soon"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid timeout, got %d", w.Code)
+	}
+	if w := serve("/api/v1/trust/graph?watch=true"); w.Header().Get(DataVersionHeader) != "" {
+		t.Error("expected routes that can't be watched left alone")
+	}
+	if served.Load() != 5 {
+		t.Errorf("expected 5 requests served, got %d", served.Load())
+	}
+}
+
+func TestWatcherClose(t *testing.T) {
+	watcher := NewWatcher(&fakeVersion{}, time.Minute)
+	handler := WatchMiddleware(watcher, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/trust/summary?watch=true", nil))
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	watcher.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Close to release waiting requests")
+	}
+}
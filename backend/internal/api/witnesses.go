@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/matou-dao/backend/internal/keri"
+)
+
+const witnessesPath = "/api/v1/keri/witnesses"
+
+// WitnessesHandler manages the org AID's witness pool. Pool edits are saved
+// to the org config; the org's signify client applies them to the KEL by
+// rotating with the plan this handler reports.
+type WitnessesHandler struct {
+	keriClient *keri.Client
+	orgConfig  *OrgConfigHandler
+}
+
+// NewWitnessesHandler creates a new witnesses handler
+func NewWitnessesHandler(keriClient *keri.Client, orgConfig *OrgConfigHandler) *WitnessesHandler {
+	return &WitnessesHandler{
+		keriClient: keriClient,
+		orgConfig:  orgConfig,
+	}
+}
+
+// WitnessesResponse is the pool, the org AID's current witnesses and the
+// rotation that would bring them in line with the pool
+type WitnessesResponse struct {
+	Pool      *keri.WitnessPool     `json:"pool"`
+	Threshold int                   `json:"threshold"` // Receipts the pool requires
+	KeyState  *keri.KeyState        `json:"keyState,omitempty"`
+	Rotation  *keri.WitnessRotation `json:"rotation,omitempty"`
+	// KeyStateError explains a missing keyState (org not configured,
+	// KERIA unreachable)
+	KeyStateError string `json:"keyStateError,omitempty"`
+}
+
+// SetWitnessThresholdRequest is the body for PUT /api/v1/keri/witnesses/threshold
+type SetWitnessThresholdRequest struct {
+	Threshold int `json:"threshold"`
+}
+
+// status builds the response for the current pool
+func (h *WitnessesHandler) status(ctx context.Context) WitnessesResponse {
+	pool := h.keriClient.GetWitnessPool()
+	if pool == nil {
+		pool = &keri.WitnessPool{Witnesses: []keri.Witness{}}
+	}
+	resp := WitnessesResponse{Pool: pool, Threshold: pool.EffectiveThreshold()}
+	state, err := h.keriClient.OrgKeyState(ctx)
+	if err != nil {
+		resp.KeyStateError = err.Error()
+		return resp
+	}
+	resp.KeyState = state
+	resp.Rotation = keri.PlanWitnessRotation(pool, state)
+	return resp
+}
+
+// savePool stores an edited pool and responds with the new status
+func (h *WitnessesHandler) savePool(w http.ResponseWriter, r *http.Request, status int, pool *keri.WitnessPool, err error) bool {
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return false
+	}
+	if err := h.orgConfig.SetWitnessPool(pool); err != nil {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+		return false
+	}
+	writeJSON(w, status, h.status(r.Context()))
+	return true
+}
+
+// HandleWitnesses handles GET and POST /api/v1/keri/witnesses
+func (h *WitnessesHandler) HandleWitnesses(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, h.status(r.Context()))
+	case http.MethodPost:
+		var witness keri.Witness
+		if err := json.NewDecoder(r.Body).Decode(&witness); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("invalid request body: %v", err),
+			})
+			return
+		}
+		pool, err := h.keriClient.GetWitnessPool().WithWitness(witness)
+		if h.savePool(w, r, http.StatusCreated, pool, err) {
+			fmt.Printf("[Witnesses] Added %s (%s) to the pool\n", witness.AID, witness.URL)
+		}
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+	}
+}
+
+// HandleWitness handles GET /api/v1/keri/witnesses/receipts,
+// PUT /api/v1/keri/witnesses/threshold and DELETE /api/v1/keri/witnesses/{aid}
+func (h *WitnessesHandler) HandleWitness(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, witnessesPath+"/")
+	switch {
+	case name == "receipts" && r.Method == http.MethodGet:
+		h.handleReceipts(w, r)
+	case name == "threshold" && r.Method == http.MethodPut:
+		var req SetWitnessThresholdRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("invalid request body: %v", err),
+			})
+			return
+		}
+		pool, err := h.keriClient.GetWitnessPool().WithThreshold(req.Threshold)
+		if h.savePool(w, r, http.StatusOK, pool, err) {
+			fmt.Printf("[Witnesses] Threshold set to %d\n", pool.EffectiveThreshold())
+		}
+	case name != "" && name != "receipts" && name != "threshold" && !strings.Contains(name, "/") && r.Method == http.MethodDelete:
+		current := h.keriClient.GetWitnessPool()
+		if current.Find(name) == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "witness not in the pool"})
+			return
+		}
+		pool, err := current.WithoutWitness(name)
+		if h.savePool(w, r, http.StatusOK, pool, err) {
+			fmt.Printf("[Witnesses] Removed %s from the pool\n", name)
+		}
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+			"error": "method not allowed",
+		})
+	}
+}
+
+// handleReceipts reports which witnesses have receipted the org AID's
+// latest event
+func (h *WitnessesHandler) handleReceipts(w http.ResponseWriter, r *http.Request) {
+	state, err := h.keriClient.OrgKeyState(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, h.keriClient.WitnessReceipts(r.Context(), state))
+}
+
+// RegisterRoutes registers witness routes on the mux
+func (h *WitnessesHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc(witnessesPath, CORSHandler(h.HandleWitnesses))
+	mux.HandleFunc(witnessesPath+"/", CORSHandler(h.HandleWitness))
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/keri"
+)
+
+func newTestWitnessesHandler(t *testing.T) (*WitnessesHandler, *OrgConfigHandler) {
+	t.Helper()
+	keria := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oobi/EORG" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"t":"icp","i":"EORG","s":"0","k":["DKEY0"],"bt":"1","b":["BWAN"]}`))
+	}))
+	t.Cleanup(keria.Close)
+	// The pool's witness isn't running
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	orgConfig := NewOrgConfigHandler(t.TempDir(), nil)
+	orgConfig.cache = &OrgConfigData{Organization: OrgInfo{AID: "EORG", Name: "Org"}}
+	client, _ := keri.NewClient(&keri.Config{
+		OrgAID:    "EORG",
+		CESRURL:   keria.URL,
+		Witnesses: &keri.WitnessPool{Witnesses: []keri.Witness{{AID: "BWAN", URL: down.URL}}},
+	})
+	client.SetWitnessPoolSource(orgConfig)
+	return NewWitnessesHandler(client, orgConfig), orgConfig
+}
+
+func serveWitnesses(h *WitnessesHandler, method, path string, body interface{}) (*httptest.ResponseRecorder, WitnessesResponse) {
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(method, path, &buf))
+	var resp WitnessesResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return w, resp
+}
+
+func TestWitnessesHandler_Pool(t *testing.T) {
+	h, orgConfig := newTestWitnessesHandler(t)
+
+	w, resp := serveWitnesses(h, http.MethodGet, "/api/v1/keri/witnesses", nil)
+	if w.Code != http.StatusOK || len(resp.Pool.Witnesses) != 1 || resp.Rotation == nil || resp.Rotation.Needed {
+		t.Fatalf("expected default pool matching the KEL, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w, resp = serveWitnesses(h, http.MethodPost, "/api/v1/keri/witnesses", keri.Witness{AID: "BWIL", URL: "http://witness:5643"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if !resp.Rotation.Needed || len(resp.Rotation.Adds) != 1 || resp.Rotation.Adds[0] != "BWIL" {
+		t.Errorf("expected rotation adding BWIL, got %+v", resp.Rotation)
+	}
+	if pool := orgConfig.GetWitnessPool(); pool == nil || len(pool.Witnesses) != 2 {
+		t.Errorf("expected pool saved to org config, got %+v", pool)
+	}
+
+	if w, _ := serveWitnesses(h, http.MethodPost, "/api/v1/keri/witnesses", keri.Witness{AID: "BWIL", URL: "http://witness:5643"}); w.Code != http.StatusBadRequest {
+		t.Errorf("expected duplicate witness rejected, got %d", w.Code)
+	}
+
+	w, resp = serveWitnesses(h, http.MethodPut, "/api/v1/keri/witnesses/threshold", SetWitnessThresholdRequest{Threshold: 2})
+	if w.Code != http.StatusOK || resp.Threshold != 2 || resp.Rotation.Threshold != 2 {
+		t.Errorf("expected threshold 2, got %d: %s", w.Code, w.Body.String())
+	}
+	if w, _ := serveWitnesses(h, http.MethodPut, "/api/v1/keri/witnesses/threshold", SetWitnessThresholdRequest{Threshold: 3}); w.Code != http.StatusBadRequest {
+		t.Errorf("expected threshold above the pool size rejected, got %d", w.Code)
+	}
+
+	w, resp = serveWitnesses(h, http.MethodDelete, "/api/v1/keri/witnesses/BWAN", nil)
+	if w.Code != http.StatusOK || len(resp.Pool.Witnesses) != 1 || resp.Threshold != 1 {
+		t.Errorf("expected BWAN removed and threshold lowered, got %d: %s", w.Code, w.Body.String())
+	}
+	if w, _ := serveWitnesses(h, http.MethodDelete, "/api/v1/keri/witnesses/BWAN", nil); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a witness not in the pool, got %d", w.Code)
+	}
+}
+
+func TestWitnessesHandler_Receipts(t *testing.T) {
+	h, _ := newTestWitnessesHandler(t)
+
+	w, _ := serveWitnesses(h, http.MethodGet, "/api/v1/keri/witnesses/receipts", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var status keri.WitnessReceipts
+	json.Unmarshal(w.Body.Bytes(), &status)
+	if status.AID != "EORG" || status.Threshold != 1 || len(status.Witnesses) != 1 || status.Met {
+		t.Errorf("expected unreachable witness reported, got %+v", status)
+	}
+}
@@ -0,0 +1,176 @@
+// Package audit records privileged operations - credential issuance and
+// revocation, org config changes, ACL grants and revocations, and identity
+// resets - with the actor, the time, and what changed, in the local store's
+// audit log.
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+// Audited actions
+const (
+	ActionCredentialIssue  = "credential.issue"
+	ActionCredentialRevoke = "credential.revoke"
+	ActionOrgConfig        = "org.config"
+	ActionACLGrant         = "acl.grant"
+	ActionACLRevoke        = "acl.revoke"
+	ActionIdentityReset    = "identity.reset"
+)
+
+// Actions lists every audited action
+func Actions() []string {
+	return []string{
+		ActionCredentialIssue,
+		ActionCredentialRevoke,
+		ActionOrgConfig,
+		ActionACLGrant,
+		ActionACLRevoke,
+		ActionIdentityReset,
+	}
+}
+
+// Replicator copies audit records to a log shared beyond this backend,
+// such as the admin space's audit tree
+type Replicator interface {
+	// Available reports whether records can be replicated from here
+	Available() bool
+	Replicate(ctx context.Context, record *anystore.AuditRecord) error
+}
+
+// Log records audited operations to the local store. A nil Log records
+// nothing, so components work without one.
+type Log struct {
+	store      *anystore.LocalStore
+	replicator Replicator
+	now        func() time.Time
+}
+
+// NewLog creates an audit log backed by the local store
+func NewLog(store *anystore.LocalStore) *Log {
+	return &Log{store: store, now: time.Now}
+}
+
+// SetReplicator also copies each record to a shared log. Records made while
+// the replicator is unavailable are only kept locally.
+func (l *Log) SetReplicator(r Replicator) {
+	l.replicator = r
+}
+
+// Record records that actorAID performed action on target, changing its
+// state from before to after. Either state may be nil when the operation
+// created or removed it. Failures are logged as well as returned, since
+// most callers have already done the operation and carry on regardless.
+func (l *Log) Record(ctx context.Context, action, actorAID, target string, before, after any) error {
+	if l == nil {
+		return nil
+	}
+
+	record := &anystore.AuditRecord{
+		ID:       newRecordID(),
+		Action:   action,
+		ActorAID: actorAID,
+		Target:   target,
+		At:       l.now().UTC(),
+		Before:   normalize(before),
+		After:    normalize(after),
+	}
+	record.Changes = diff("", record.Before, record.After)
+
+	err := l.store.StoreAuditRecord(ctx, record)
+	if err != nil {
+		fmt.Printf("[Audit] Failed to record %s on %s by %s: %v\n", action, target, actorAID, err)
+	}
+	// The shared log gets the record even if the local store failed
+	if l.replicator != nil && l.replicator.Available() {
+		if rerr := l.replicator.Replicate(ctx, record); rerr != nil {
+			fmt.Printf("[Audit] Failed to replicate %s on %s by %s: %v\n", action, target, actorAID, rerr)
+			if err == nil {
+				err = rerr
+			}
+		}
+	}
+	return err
+}
+
+// List returns the audit records matching a query, newest first
+func (l *Log) List(ctx context.Context, q anystore.AuditQuery) ([]*anystore.AuditRecord, error) {
+	if l == nil {
+		return nil, nil
+	}
+	return l.store.ListAuditRecords(ctx, q)
+}
+
+// Diff returns the fields that differ between two states, by dotted path.
+// States are compared as their JSON encoding, so structs and maps with the
+// same fields compare equal.
+func Diff(before, after any) []anystore.AuditChange {
+	return diff("", normalize(before), normalize(after))
+}
+
+func diff(path string, before, after any) []anystore.AuditChange {
+	beforeMap, beforeIsMap := before.(map[string]any)
+	afterMap, afterIsMap := after.(map[string]any)
+	if !beforeIsMap || !afterIsMap {
+		if reflect.DeepEqual(before, after) {
+			return nil
+		}
+		return []anystore.AuditChange{{Path: path, Before: before, After: after}}
+	}
+
+	keys := make(map[string]bool, len(beforeMap)+len(afterMap))
+	for k := range beforeMap {
+		keys[k] = true
+	}
+	for k := range afterMap {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var changes []anystore.AuditChange
+	for _, k := range sorted {
+		field := k
+		if path != "" {
+			field = path + "." + k
+		}
+		changes = append(changes, diff(field, beforeMap[k], afterMap[k])...)
+	}
+	return changes
+}
+
+// normalize converts a state to its generic JSON form, so it is stored and
+// compared the same way however the caller typed it
+func normalize(v any) any {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return fmt.Sprint(v)
+	}
+	return out
+}
+
+// newRecordID generates a random audit record ID
+func newRecordID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
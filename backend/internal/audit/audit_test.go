@@ -0,0 +1,175 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+func newTestLog(t *testing.T) *Log {
+	t.Helper()
+
+	store, err := anystore.NewLocalStore(anystore.DefaultConfig(t.TempDir()))
+	if err != nil {
+		t.Fatalf("failed to create anystore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return NewLog(store)
+}
+
+func TestDiff(t *testing.T) {
+	type config struct {
+		Name     string            `json:"name"`
+		Admins   []string          `json:"admins"`
+		Settings map[string]string `json:"settings,omitempty"`
+	}
+
+	before := config{Name: "Matou", Admins: []string{"EADMIN1"}, Settings: map[string]string{"theme": "light"}}
+	after := config{Name: "Matou DAO", Admins: []string{"EADMIN1"}, Settings: map[string]string{"theme": "dark", "lang": "mi"}}
+
+	changes := Diff(before, after)
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", changes)
+	}
+	// Sorted by path
+	if changes[0].Path != "name" || changes[0].Before != "Matou" || changes[0].After != "Matou DAO" {
+		t.Errorf("unexpected name change: %+v", changes[0])
+	}
+	if changes[1].Path != "settings.lang" || changes[1].Before != nil || changes[1].After != "mi" {
+		t.Errorf("unexpected settings.lang change: %+v", changes[1])
+	}
+	if changes[2].Path != "settings.theme" || changes[2].After != "dark" {
+		t.Errorf("unexpected settings.theme change: %+v", changes[2])
+	}
+
+	if changes := Diff(before, before); len(changes) != 0 {
+		t.Errorf("expected no changes for equal states, got %+v", changes)
+	}
+
+	// Creating or removing a state records it as one change
+	changes = Diff(nil, map[string]string{"said": "ESAID1", "schema": "EMEMBER"})
+	if len(changes) != 1 || changes[0].Path != "" || changes[0].Before != nil {
+		t.Errorf("expected a single root change from nil, got %+v", changes)
+	}
+}
+
+func TestLog_RecordAndList(t *testing.T) {
+	log := newTestLog(t)
+	ctx := context.Background()
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := base
+	log.now = func() time.Time { return at }
+
+	if err := log.Record(ctx, ActionCredentialIssue, "EADMIN1", "ESAID1", nil,
+		map[string]string{"said": "ESAID1", "recipient": "EMEMBER1"}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	at = base.Add(time.Hour)
+	if err := log.Record(ctx, ActionCredentialRevoke, "EADMIN2", "ESAID1",
+		map[string]string{"status": "issued"}, map[string]string{"status": "revoked"}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	at = base.Add(2 * time.Hour)
+	if err := log.Record(ctx, ActionACLGrant, "EADMIN1", "space-1", nil,
+		map[string]string{"aid": "EMEMBER1"}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	all, err := log.List(ctx, anystore.AuditQuery{})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(all))
+	}
+	if all[0].Action != ActionACLGrant || all[2].Action != ActionCredentialIssue {
+		t.Errorf("expected newest first, got %s ... %s", all[0].Action, all[2].Action)
+	}
+	if all[1].ID == "" || len(all[1].Changes) != 1 || all[1].Changes[0].Path != "status" {
+		t.Errorf("expected the revocation recorded with its diff, got %+v", all[1])
+	}
+
+	tests := []struct {
+		name  string
+		query anystore.AuditQuery
+		want  int
+	}{
+		{"by action", anystore.AuditQuery{Action: ActionCredentialRevoke}, 1},
+		{"by action prefix", anystore.AuditQuery{Action: "credential."}, 2},
+		{"by actor", anystore.AuditQuery{ActorAID: "EADMIN1"}, 2},
+		{"by target", anystore.AuditQuery{Target: "ESAID1"}, 2},
+		{"since", anystore.AuditQuery{Since: base.Add(30 * time.Minute)}, 2},
+		{"until", anystore.AuditQuery{Until: base.Add(30 * time.Minute)}, 1},
+		{"limit", anystore.AuditQuery{Limit: 1}, 1},
+		{"no match", anystore.AuditQuery{ActorAID: "EUNKNOWN"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := log.List(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if len(records) != tt.want {
+				t.Errorf("expected %d records, got %d", tt.want, len(records))
+			}
+		})
+	}
+}
+
+func TestLog_Nil(t *testing.T) {
+	var log *Log
+	if err := log.Record(context.Background(), ActionOrgConfig, "EADMIN1", "org", nil, nil); err != nil {
+		t.Errorf("expected a nil log to record nothing, got %v", err)
+	}
+	if records, err := log.List(context.Background(), anystore.AuditQuery{}); err != nil || records != nil {
+		t.Errorf("expected no records from a nil log, got %v, %v", records, err)
+	}
+}
+
+type fakeReplicator struct {
+	available  bool
+	replicated []*anystore.AuditRecord
+}
+
+func (r *fakeReplicator) Available() bool { return r.available }
+
+func (r *fakeReplicator) Replicate(ctx context.Context, record *anystore.AuditRecord) error {
+	r.replicated = append(r.replicated, record)
+	return nil
+}
+
+func TestLog_Replicates(t *testing.T) {
+	log := newTestLog(t)
+	ctx := context.Background()
+
+	replicator := &fakeReplicator{}
+	log.SetReplicator(replicator)
+	if err := log.Record(ctx, ActionACLGrant, "EADMIN1", "space-1", nil, map[string]string{"aid": "EMEMBER1"}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if len(replicator.replicated) != 0 {
+		t.Errorf("expected nothing replicated while unavailable, got %d", len(replicator.replicated))
+	}
+
+	replicator.available = true
+	if err := log.Record(ctx, ActionACLRevoke, "EADMIN1", "space-1", map[string]string{"aid": "EMEMBER1"}, nil); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if len(replicator.replicated) != 1 || replicator.replicated[0].Action != ActionACLRevoke {
+		t.Fatalf("expected the revocation replicated, got %+v", replicator.replicated)
+	}
+
+	local, err := log.List(ctx, anystore.AuditQuery{})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(local) != 2 {
+		t.Fatalf("expected both records kept locally, got %d", len(local))
+	}
+	if local[0].ID != replicator.replicated[0].ID && local[1].ID != replicator.replicated[0].ID {
+		t.Errorf("expected the replicated record to keep its local ID %s", replicator.replicated[0].ID)
+	}
+}
@@ -0,0 +1,232 @@
+// Package backup moves a MATOU node's keys to another machine. A backup
+// bundle holds everything needed to keep access to the user's spaces: the
+// identity, the peer key, the org config and every persisted space key set.
+//
+// Bundles are encrypted with a passphrase chosen at export, separate from
+// the at-rest passphrase. Files sealed at rest are decrypted into the bundle
+// and sealed again with the importing node's at-rest passphrase, so the two
+// machines don't need to share one.
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+// Format identifies a backup bundle
+const Format = "matou-backup-v1"
+
+// MinPassphraseLength is the shortest bundle passphrase accepted
+const MinPassphraseLength = 8
+
+// Files in the data directory
+const (
+	identityFile  = "identity.json"
+	peerKeyFile   = "peer.key"
+	orgConfigFile = "org-config.yaml"
+	keysDir       = "keys"
+)
+
+var (
+	// ErrPassphraseTooShort is returned for a bundle passphrase under
+	// MinPassphraseLength
+	ErrPassphraseTooShort = fmt.Errorf("backup passphrase must be at least %d characters", MinPassphraseLength)
+	// ErrNoIdentity is returned when exporting a node without an identity
+	ErrNoIdentity = errors.New("no identity to back up")
+	// ErrNotBackup is returned when importing data that isn't a bundle
+	ErrNotBackup = errors.New("not a MATOU backup bundle")
+	// ErrIdentityExists is returned when importing over a configured
+	// identity without overwrite
+	ErrIdentityExists = errors.New("an identity is already configured on this node")
+)
+
+// bundle is the plaintext inside the encrypted envelope
+type bundle struct {
+	Format    string            `json:"format"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Files     map[string][]byte `json:"files"` // By path relative to the data directory
+}
+
+// Manifest describes a bundle's contents
+type Manifest struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Files     []string  `json:"files"`
+	SpaceKeys int       `json:"spaceKeys"`
+}
+
+func newManifest(b *bundle) *Manifest {
+	m := &Manifest{CreatedAt: b.CreatedAt, Files: make([]string, 0, len(b.Files))}
+	for path := range b.Files {
+		m.Files = append(m.Files, path)
+		if strings.HasPrefix(path, keysDir+"/") {
+			m.SpaceKeys++
+		}
+	}
+	sort.Strings(m.Files)
+	return m
+}
+
+// sealedAtRest reports whether a file may be sealed with the at-rest
+// passphrase
+func sealedAtRest(path string) bool {
+	return path == identityFile || strings.HasPrefix(path, keysDir+"/")
+}
+
+// validPath accepts only the files a bundle may contain, so an import
+// can't write anywhere else in the data directory
+func validPath(path string) bool {
+	switch path {
+	case identityFile, peerKeyFile, orgConfigFile:
+		return true
+	}
+	name, ok := strings.CutPrefix(path, keysDir+"/")
+	return ok && strings.HasSuffix(name, ".keys") && len(name) > len(".keys") &&
+		!strings.ContainsAny(name, `/\`) && !strings.HasPrefix(name, ".")
+}
+
+// Export reads the backup files from dataDir and encrypts them with
+// passphrase. atRest opens files sealed at rest and may be nil.
+func Export(dataDir string, atRest *secret.Sealer, passphrase string, now time.Time) ([]byte, *Manifest, error) {
+	if len(passphrase) < MinPassphraseLength {
+		return nil, nil, ErrPassphraseTooShort
+	}
+
+	b := &bundle{Format: Format, CreatedAt: now.UTC(), Files: make(map[string][]byte)}
+	paths := []string{identityFile, peerKeyFile, orgConfigFile}
+	entries, err := os.ReadDir(filepath.Join(dataDir, keysDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("reading keys directory: %w", err)
+	}
+	for _, entry := range entries {
+		if path := keysDir + "/" + entry.Name(); !entry.IsDir() && validPath(path) {
+			paths = append(paths, path)
+		}
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(filepath.Join(dataDir, filepath.FromSlash(path)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		if sealedAtRest(path) {
+			if data, err = atRest.Open(data); err != nil {
+				return nil, nil, fmt.Errorf("opening %s: %w", path, err)
+			}
+		}
+		b.Files[path] = data
+	}
+	if _, ok := b.Files[identityFile]; !ok {
+		return nil, nil, ErrNoIdentity
+	}
+
+	plaintext, err := json.Marshal(b)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding bundle: %w", err)
+	}
+	sealer, err := secret.NewSealer(passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	sealed, err := sealer.Seal(plaintext)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encrypting bundle: %w", err)
+	}
+	return sealed, newManifest(b), nil
+}
+
+// open decrypts and checks a bundle
+func open(data []byte, passphrase string) (*bundle, error) {
+	if !secret.IsSealed(data) {
+		return nil, ErrNotBackup
+	}
+	sealer, err := secret.NewSealer(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if sealer == nil {
+		return nil, secret.ErrWrongPassphrase
+	}
+	plaintext, err := sealer.Open(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bundle
+	if err := json.Unmarshal(plaintext, &b); err != nil || b.Format != Format {
+		return nil, ErrNotBackup
+	}
+	if _, ok := b.Files[identityFile]; !ok {
+		return nil, fmt.Errorf("%w: bundle has no %s", ErrNotBackup, identityFile)
+	}
+	for path := range b.Files {
+		if !validPath(path) {
+			return nil, fmt.Errorf("%w: unexpected file %q", ErrNotBackup, path)
+		}
+	}
+	return &b, nil
+}
+
+// Inspect decrypts a bundle and describes it without writing anything
+func Inspect(data []byte, passphrase string) (*Manifest, error) {
+	b, err := open(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return newManifest(b), nil
+}
+
+// Import decrypts a bundle and writes its files into dataDir, sealing them
+// with atRest where applicable. It refuses to replace a configured identity
+// unless overwrite is set. The backend must be restarted to use the
+// imported keys.
+func Import(dataDir string, data []byte, atRest *secret.Sealer, passphrase string, overwrite bool) (*Manifest, error) {
+	b, err := open(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if !overwrite {
+		if _, err := os.Stat(filepath.Join(dataDir, identityFile)); err == nil {
+			return nil, ErrIdentityExists
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Join(dataDir, keysDir), 0700); err != nil {
+		return nil, fmt.Errorf("creating keys directory: %w", err)
+	}
+	// Key sets first and the identity last, so an interrupted import
+	// leaves the node unconfigured rather than without its keys
+	paths := make([]string, 0, len(b.Files))
+	for path := range b.Files {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if (paths[i] == identityFile) != (paths[j] == identityFile) {
+			return paths[j] == identityFile
+		}
+		return paths[i] < paths[j]
+	})
+	for _, path := range paths {
+		data := b.Files[path]
+		if sealedAtRest(path) {
+			if data, err = atRest.Seal(data); err != nil {
+				return nil, fmt.Errorf("sealing %s: %w", path, err)
+			}
+		}
+		if err := os.WriteFile(filepath.Join(dataDir, filepath.FromSlash(path)), data, 0600); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", path, err)
+		}
+	}
+	return newManifest(b), nil
+}
@@ -0,0 +1,126 @@
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+const bundlePassphrase = "moving day passphrase"
+
+func writeFile(t *testing.T, dir, path string, data []byte) {
+	t.Helper()
+	full := filepath.Join(dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(full), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, dir, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestExportImport_ResealsAtRest(t *testing.T) {
+	oldRest, _ := secret.NewSealer("old machine")
+	newRest, _ := secret.NewSealer("new machine")
+
+	src := t.TempDir()
+	identity := []byte(`{"aid":"EAID","mnemonic":"abandon about"}`)
+	keys := []byte(`{"signingKey":"k"}`)
+	sealedIdentity, _ := oldRest.Seal(identity)
+	sealedKeys, _ := oldRest.Seal(keys)
+	writeFile(t, src, "identity.json", sealedIdentity)
+	writeFile(t, src, "keys/bafyspace.keys", sealedKeys)
+	writeFile(t, src, "peer.key", []byte{1, 2, 3})
+	writeFile(t, src, "org-config.yaml", []byte("organization:\n  name: Test\n"))
+	writeFile(t, src, "keys/notes.txt", []byte("ignored"))
+
+	bundle, manifest, err := Export(src, oldRest, bundlePassphrase, time.Now())
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if manifest.SpaceKeys != 1 || len(manifest.Files) != 4 {
+		t.Fatalf("unexpected manifest %+v", manifest)
+	}
+	if bytes.Contains(bundle, []byte("abandon")) {
+		t.Fatal("bundle leaked the mnemonic")
+	}
+
+	dst := t.TempDir()
+	writeFile(t, dst, "peer.key", []byte("fresh node key"))
+	if _, err := Import(dst, bundle, newRest, bundlePassphrase, false); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	for path, want := range map[string][]byte{"identity.json": identity, "keys/bafyspace.keys": keys} {
+		data := readFile(t, dst, path)
+		if !secret.IsSealed(data) {
+			t.Errorf("expected %s sealed at rest", path)
+		}
+		if opened, err := newRest.Open(data); err != nil || !bytes.Equal(opened, want) {
+			t.Errorf("expected %s to open with the new passphrase, got %v", path, err)
+		}
+	}
+	if !bytes.Equal(readFile(t, dst, "peer.key"), []byte{1, 2, 3}) {
+		t.Error("expected peer key replaced")
+	}
+}
+
+func TestImport_Refusals(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, src, "identity.json", []byte(`{"aid":"EAID"}`))
+	bundle, _, err := Export(src, nil, bundlePassphrase, time.Now())
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	if _, err := Import(t.TempDir(), bundle, nil, "wrong passphrase", false); !errors.Is(err, secret.ErrWrongPassphrase) {
+		t.Errorf("expected ErrWrongPassphrase, got %v", err)
+	}
+	if _, err := Import(t.TempDir(), []byte(`{"aid":"EAID"}`), nil, bundlePassphrase, false); !errors.Is(err, ErrNotBackup) {
+		t.Errorf("expected ErrNotBackup for plaintext, got %v", err)
+	}
+	// Configured nodes need overwrite
+	if _, err := Import(src, bundle, nil, bundlePassphrase, false); !errors.Is(err, ErrIdentityExists) {
+		t.Errorf("expected ErrIdentityExists, got %v", err)
+	}
+	if _, err := Import(src, bundle, nil, bundlePassphrase, true); err != nil {
+		t.Errorf("expected overwrite to succeed, got %v", err)
+	}
+
+	// Paths outside the allowed set are rejected
+	evil, _ := secret.NewSealer(bundlePassphrase)
+	sealed, _ := evil.Seal([]byte(`{"format":"matou-backup-v1","files":{"identity.json":"e30=","keys/../../x.keys":"e30="}}`))
+	if _, err := Import(t.TempDir(), sealed, nil, bundlePassphrase, false); !errors.Is(err, ErrNotBackup) {
+		t.Errorf("expected traversal rejected, got %v", err)
+	}
+}
+
+func TestExport_Refusals(t *testing.T) {
+	if _, _, err := Export(t.TempDir(), nil, "short", time.Now()); !errors.Is(err, ErrPassphraseTooShort) {
+		t.Errorf("expected ErrPassphraseTooShort, got %v", err)
+	}
+	if _, _, err := Export(t.TempDir(), nil, bundlePassphrase, time.Now()); !errors.Is(err, ErrNoIdentity) {
+		t.Errorf("expected ErrNoIdentity, got %v", err)
+	}
+
+	locked := t.TempDir()
+	rest, _ := secret.NewSealer("at rest")
+	sealed, _ := rest.Seal([]byte(`{"aid":"EAID"}`))
+	writeFile(t, locked, "identity.json", sealed)
+	if _, _, err := Export(locked, nil, bundlePassphrase, time.Now()); !errors.Is(err, secret.ErrLocked) {
+		t.Errorf("expected ErrLocked without the at-rest passphrase, got %v", err)
+	}
+}
@@ -0,0 +1,562 @@
+// Package bootstrap sets up the any-sync spaces a backend needs: the user's
+// private space and the org's community, read-only and admin spaces. It
+// derives each space's keys from the user's mnemonic, creates or recovers
+// the space, persists its key set and records its ID in the identity and
+// org config. Progress is kept per step for GET /api/v1/bootstrap/status.
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+// Steps, in the order Run performs them
+const (
+	StepPrivateSpace   = "private-space"
+	StepCommunitySpace = "community-space"
+	StepReadOnlySpace  = "readonly-space"
+	StepAdminSpace     = "admin-space"
+	StepOrgConfig      = "org-config"
+)
+
+// Steps returns the bootstrap steps in order
+func Steps() []string {
+	return []string{StepPrivateSpace, StepCommunitySpace, StepReadOnlySpace, StepAdminSpace, StepOrgConfig}
+}
+
+// Step and run states
+const (
+	StatePending = "pending"
+	StateRunning = "running"
+	StateDone    = "done"
+	StateSkipped = "skipped"
+	StateFailed  = "failed"
+)
+
+// Key derivation indexes of each space in the user's mnemonic
+const (
+	privateSpaceIndex   = 0
+	communitySpaceIndex = 1
+	readOnlySpaceIndex  = 2
+	adminSpaceIndex     = 3
+)
+
+// recoverTimeout bounds each attempt to find an existing space on the
+// network, so a slow network doesn't hold up setup
+const recoverTimeout = 10 * time.Second
+
+var (
+	// ErrNoClient is returned when the any-sync client isn't available
+	ErrNoClient = errors.New("any-sync client not available")
+	// ErrNoIdentity is returned when spaces must be created before the
+	// user's identity (and so their mnemonic) is set
+	ErrNoIdentity = errors.New("identity must be configured before creating spaces (call POST /api/v1/identity/set first)")
+)
+
+// Step is the outcome of one bootstrap step
+type Step struct {
+	Name       string `json:"name"`
+	State      string `json:"state"`
+	SpaceID    string `json:"spaceId,omitempty"`
+	Created    bool   `json:"created,omitempty"` // false when an existing space was reused or recovered
+	Error      string `json:"error,omitempty"`
+	StartedAt  string `json:"startedAt,omitempty"`
+	FinishedAt string `json:"finishedAt,omitempty"`
+}
+
+// Status is the state of every bootstrap step. Steps keep their latest
+// outcome, so the status covers setup done over several calls (identity
+// set, then community creation).
+type Status struct {
+	State     string `json:"state"` // pending, running, done or failed
+	OrgAID    string `json:"orgAid,omitempty"`
+	UpdatedAt string `json:"updatedAt,omitempty"`
+	Steps     []Step `json:"steps"`
+}
+
+// Step returns the named step
+func (s *Status) Step(name string) Step {
+	for _, step := range s.Steps {
+		if step.Name == name {
+			return step
+		}
+	}
+	return Step{Name: name, State: StatePending}
+}
+
+// Request names the org whose spaces are bootstrapped
+type Request struct {
+	OrgAID  string `json:"orgAid"`
+	OrgName string `json:"orgName"`
+}
+
+// OrgConfigWriter records the org's space IDs in its config.
+// Implemented by api.OrgConfigHandler.
+type OrgConfigWriter interface {
+	IsConfigured() bool
+	SetSpaceIDs(communitySpaceID, readOnlySpaceID, adminSpaceID string) error
+}
+
+// spaceIDDeriver is implemented by clients that can compute a space's ID
+// from its keys without creating it (anysync.SDKClient)
+type spaceIDDeriver interface {
+	DeriveSpaceIDWithKeys(ctx context.Context, ownerAID string, spaceType string, keys *anysync.SpaceKeySet) (string, error)
+}
+
+// Orchestrator runs the bootstrap steps. Runs are serialized.
+type Orchestrator struct {
+	spaces    *anysync.SpaceManager
+	store     anysync.SpaceStore
+	identity  *identity.UserIdentity
+	orgConfig OrgConfigWriter
+	onCreated func(*anysync.Space)
+
+	run    sync.Mutex // Held for the length of a run
+	mu     sync.RWMutex
+	status Status
+}
+
+// NewOrchestrator creates an orchestrator for the space manager's client
+func NewOrchestrator(spaces *anysync.SpaceManager, store anysync.SpaceStore, userIdentity *identity.UserIdentity) *Orchestrator {
+	o := &Orchestrator{spaces: spaces, store: store, identity: userIdentity}
+	for _, name := range Steps() {
+		o.status.Steps = append(o.status.Steps, Step{Name: name, State: StatePending})
+	}
+	o.status.State = StatePending
+	return o
+}
+
+// SetOrgConfig attaches the org config the space IDs are written to. Without
+// one, the org-config step is skipped.
+func (o *Orchestrator) SetOrgConfig(w OrgConfigWriter) {
+	o.orgConfig = w
+}
+
+// OnSpaceCreated registers a callback for each newly created space
+// (e.g. to broadcast space:created events)
+func (o *Orchestrator) OnSpaceCreated(fn func(*anysync.Space)) {
+	o.onCreated = fn
+}
+
+// Status returns a copy of the current status
+func (o *Orchestrator) Status() Status {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	status := o.status
+	status.Steps = append([]Step(nil), o.status.Steps...)
+	return status
+}
+
+// Run performs every step: the user's private space, the org's three
+// spaces, and the org config. It returns the first step error.
+func (o *Orchestrator) Run(ctx context.Context, req Request) (Status, error) {
+	o.run.Lock()
+	defer o.run.Unlock()
+
+	aid := ""
+	if o.identity != nil {
+		aid = o.identity.GetAID()
+	}
+	mnemonic := o.mnemonic()
+	defer mnemonic.Zero()
+	if aid == "" || mnemonic.IsEmpty() {
+		return o.Status(), ErrNoIdentity
+	}
+
+	o.begin(req.OrgAID, Steps()...)
+	_, privateErr := o.privateSpace(ctx, aid, mnemonic, false)
+	orgErr := o.orgSpaces(ctx, req, mnemonic)
+	o.finish()
+	if privateErr != nil {
+		return o.Status(), privateErr
+	}
+	return o.Status(), orgErr
+}
+
+// CreateOrgSpaces creates the org's community, read-only and admin spaces
+// (reusing any that still exist) and records their IDs in the org config.
+// It fails only if the community space can't be created; the outcome of
+// the other steps is in the returned status.
+func (o *Orchestrator) CreateOrgSpaces(ctx context.Context, req Request) (Status, error) {
+	o.run.Lock()
+	defer o.run.Unlock()
+
+	mnemonic := o.mnemonic()
+	defer mnemonic.Zero()
+
+	o.begin(req.OrgAID, StepCommunitySpace, StepReadOnlySpace, StepAdminSpace, StepOrgConfig)
+	err := o.orgSpaces(ctx, req, mnemonic)
+	o.finish()
+	return o.Status(), err
+}
+
+// EnsurePrivateSpace recovers aid's private space from the network, or
+// creates it. In claim mode the space is created without looking for an
+// existing one. Returns the space ID.
+func (o *Orchestrator) EnsurePrivateSpace(ctx context.Context, aid string, mnemonic *secret.Mnemonic, claim bool) (string, error) {
+	o.run.Lock()
+	defer o.run.Unlock()
+
+	o.begin("", StepPrivateSpace)
+	spaceID, err := o.privateSpace(ctx, aid, mnemonic, claim)
+	o.finish()
+	return spaceID, err
+}
+
+// RecoverOrgSpaces opens org spaces the user has joined, and persists a key
+// set for each (with the peer key as signing key) so the user can write to
+// them. Empty IDs are skipped. Failures are recorded in the status but not
+// returned: the spaces sync later once the network is reachable.
+func (o *Orchestrator) RecoverOrgSpaces(ctx context.Context, communitySpaceID, readOnlySpaceID, adminSpaceID string) Status {
+	o.run.Lock()
+	defer o.run.Unlock()
+
+	o.begin("", StepCommunitySpace, StepReadOnlySpace, StepAdminSpace)
+	for _, s := range []struct{ step, spaceID string }{
+		{StepCommunitySpace, communitySpaceID},
+		{StepReadOnlySpace, readOnlySpaceID},
+		{StepAdminSpace, adminSpaceID},
+	} {
+		if s.spaceID == "" {
+			o.skip(s.step, "")
+			continue
+		}
+		o.start(s.step)
+		if err := o.recoverSpace(ctx, s.spaceID); err != nil {
+			fmt.Printf("[Bootstrap] Failed to sync %s %s: %v\n", s.step, s.spaceID, err)
+			o.fail(s.step, err)
+			continue
+		}
+		fmt.Printf("[Bootstrap] Recovered %s: %s\n", s.step, s.spaceID)
+		o.done(s.step, s.spaceID, false)
+	}
+	o.finish()
+	return o.Status()
+}
+
+// privateSpace runs the private-space step
+func (o *Orchestrator) privateSpace(ctx context.Context, aid string, mnemonic *secret.Mnemonic, claim bool) (string, error) {
+	o.start(StepPrivateSpace)
+	spaceID, created, err := o.createPrivateSpace(ctx, aid, mnemonic, claim)
+	if err != nil {
+		o.fail(StepPrivateSpace, err)
+		return "", err
+	}
+	o.done(StepPrivateSpace, spaceID, created)
+	return spaceID, nil
+}
+
+func (o *Orchestrator) createPrivateSpace(ctx context.Context, aid string, mnemonic *secret.Mnemonic, claim bool) (string, bool, error) {
+	client := o.client()
+	if client == nil {
+		return "", false, ErrNoClient
+	}
+	keys, err := anysync.DeriveSpaceKeySetFromSecret(mnemonic, privateSpaceIndex)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to derive private space keys: %w", err)
+	}
+
+	// Recovery: the space ID follows from the keys, so a user restoring
+	// from their mnemonic finds their existing space
+	spaceID, created := "", true
+	if deriver, ok := client.(spaceIDDeriver); ok && !claim {
+		derivedID, err := deriver.DeriveSpaceIDWithKeys(ctx, aid, anysync.SpaceTypePrivate, keys)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to derive private space ID: %w", err)
+		}
+		recoverCtx, cancel := context.WithTimeout(ctx, recoverTimeout)
+		_, getErr := client.GetSpace(recoverCtx, derivedID)
+		cancel()
+		if getErr == nil {
+			fmt.Printf("[Bootstrap] Recovered private space from network: %s\n", derivedID)
+			spaceID, created = derivedID, false
+		} else {
+			fmt.Printf("[Bootstrap] Private space not on network, creating new: %v\n", getErr)
+		}
+	}
+	if created {
+		// Use the created (coordinator-assigned) ID from here on
+		result, err := client.CreateSpaceWithKeys(ctx, aid, anysync.SpaceTypePrivate, keys)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to create private space: %w", err)
+		}
+		spaceID = result.SpaceID
+	}
+
+	if err := anysync.PersistSpaceKeySet(client.GetDataDir(), spaceID, keys); err != nil {
+		fmt.Printf("[Bootstrap] Warning: failed to persist private space keys: %v\n", err)
+	}
+	space := &anysync.Space{SpaceID: spaceID, OwnerAID: aid, SpaceType: anysync.SpaceTypePrivate}
+	if err := o.store.SaveSpace(ctx, space); err != nil {
+		fmt.Printf("[Bootstrap] Warning: failed to save private space record: %v\n", err)
+	}
+	if o.identity != nil {
+		if err := o.identity.SetPrivateSpaceID(spaceID); err != nil {
+			fmt.Printf("[Bootstrap] Warning: failed to persist private space ID: %v\n", err)
+		}
+	}
+	if created {
+		o.spaceCreated(space)
+	}
+	return spaceID, created, nil
+}
+
+// orgSpace describes one of the org's spaces
+type orgSpace struct {
+	step      string
+	spaceType string
+	index     uint32
+	suffix    string // Appended to the org name for the space name
+	current   func() string
+	set       func(spaceID string)       // Sets the ID on the space manager
+	persist   func(spaceID string) error // Saves the ID with the user's identity
+}
+
+// orgSpaces runs the community, read-only, admin and org-config steps
+func (o *Orchestrator) orgSpaces(ctx context.Context, req Request, mnemonic *secret.Mnemonic) error {
+	community := orgSpace{
+		step: StepCommunitySpace, spaceType: anysync.SpaceTypeCommunity, index: communitySpaceIndex, suffix: " Community",
+		current: o.spaces.GetCommunitySpaceID,
+		set:     o.spaces.SetCommunitySpaceID,
+		persist: func(spaceID string) error { return o.identity.SetOrgConfig(req.OrgAID, spaceID) },
+	}
+	readOnly := orgSpace{
+		step: StepReadOnlySpace, spaceType: anysync.SpaceTypeCommunityReadOnly, index: readOnlySpaceIndex, suffix: " Community (Read-Only)",
+		current: o.spaces.GetCommunityReadOnlySpaceID,
+		set:     o.spaces.SetCommunityReadOnlySpaceID,
+		persist: o.identity.SetCommunityReadOnlySpaceID,
+	}
+	admin := orgSpace{
+		step: StepAdminSpace, spaceType: anysync.SpaceTypeAdmin, index: adminSpaceIndex, suffix: " Admin",
+		current: o.spaces.GetAdminSpaceID,
+		set:     o.spaces.SetAdminSpaceID,
+		persist: o.identity.SetAdminSpaceID,
+	}
+
+	// The community space goes first: without it the others are stale
+	// (e.g. after an any-sync reset), so their cached IDs are dropped
+	o.start(StepCommunitySpace)
+	communityID, created, err := o.ensureOrgSpace(ctx, req, mnemonic, community)
+	if err != nil {
+		o.fail(StepCommunitySpace, err)
+		for _, step := range []string{StepReadOnlySpace, StepAdminSpace, StepOrgConfig} {
+			o.skip(step, "community space was not created")
+		}
+		return err
+	}
+	o.done(StepCommunitySpace, communityID, created)
+	if created {
+		o.spaces.SetCommunityReadOnlySpaceID("")
+		o.spaces.SetAdminSpaceID("")
+	}
+
+	for _, space := range []orgSpace{readOnly, admin} {
+		o.start(space.step)
+		spaceID, created, err := o.ensureOrgSpace(ctx, req, mnemonic, space)
+		if err != nil {
+			fmt.Printf("[Bootstrap] Warning: %v\n", err)
+			o.fail(space.step, err)
+			continue
+		}
+		o.done(space.step, spaceID, created)
+	}
+
+	o.writeOrgConfig()
+	return nil
+}
+
+// ensureOrgSpace reuses the org space if it still exists on the network,
+// or creates it with keys derived from the mnemonic. The peer key signs, so
+// the client's account identity is the ACL owner and can build invites.
+func (o *Orchestrator) ensureOrgSpace(ctx context.Context, req Request, mnemonic *secret.Mnemonic, space orgSpace) (string, bool, error) {
+	client := o.client()
+	if client == nil {
+		return "", false, ErrNoClient
+	}
+
+	// MakeSpaceShareable talks to the coordinator; if it fails the space is gone
+	if existing := space.current(); existing != "" {
+		err := client.MakeSpaceShareable(ctx, existing)
+		if err == nil {
+			return existing, false, nil
+		}
+		fmt.Printf("[Bootstrap] Cached %s %s no longer valid: %v — will recreate\n", space.step, existing, err)
+		space.set("")
+	}
+
+	if mnemonic.IsEmpty() {
+		return "", false, ErrNoIdentity
+	}
+	keys, err := anysync.DeriveSpaceKeySetFromSecret(mnemonic, space.index)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to derive %s space keys: %w", space.spaceType, err)
+	}
+	keys.SigningKey = client.GetSigningKey()
+
+	result, err := client.CreateSpaceWithKeys(ctx, req.OrgAID, space.spaceType, keys)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create %s space: %w", space.spaceType, err)
+	}
+	// Shareable is required before CreateOpenInvite
+	if err := client.MakeSpaceShareable(ctx, result.SpaceID); err != nil {
+		fmt.Printf("[Bootstrap] Warning: failed to make %s space shareable: %v\n", space.spaceType, err)
+	}
+
+	created := &anysync.Space{
+		SpaceID:   result.SpaceID,
+		OwnerAID:  req.OrgAID,
+		SpaceType: space.spaceType,
+		SpaceName: req.OrgName + space.suffix,
+		CreatedAt: result.CreatedAt,
+		LastSync:  result.CreatedAt,
+	}
+	if err := o.store.SaveSpace(ctx, created); err != nil {
+		// The space exists in any-sync; the record is only a local index
+		fmt.Printf("[Bootstrap] Warning: failed to save %s space record: %v\n", space.spaceType, err)
+	}
+	space.set(result.SpaceID)
+	if o.identity != nil {
+		if err := space.persist(result.SpaceID); err != nil {
+			fmt.Printf("[Bootstrap] Warning: failed to persist %s space ID: %v\n", space.spaceType, err)
+		}
+	}
+	o.spaceCreated(created)
+	return result.SpaceID, true, nil
+}
+
+// recoverSpace opens a joined space and persists a key set for writing to it
+func (o *Orchestrator) recoverSpace(ctx context.Context, spaceID string) error {
+	client := o.client()
+	if client == nil {
+		return ErrNoClient
+	}
+	recoverCtx, cancel := context.WithTimeout(ctx, recoverTimeout)
+	_, err := client.GetSpace(recoverCtx, spaceID)
+	cancel()
+	if err != nil {
+		return err
+	}
+	keys, err := anysync.GenerateSpaceKeySet()
+	if err != nil {
+		return fmt.Errorf("generating key set: %w", err)
+	}
+	keys.SigningKey = client.GetSigningKey()
+	return anysync.PersistSpaceKeySet(client.GetDataDir(), spaceID, keys)
+}
+
+// writeOrgConfig runs the org-config step
+func (o *Orchestrator) writeOrgConfig() {
+	if o.orgConfig == nil {
+		o.skip(StepOrgConfig, "")
+		return
+	}
+	if !o.orgConfig.IsConfigured() {
+		// The space IDs are saved with the org config when it's first posted
+		o.skip(StepOrgConfig, "organization not configured yet")
+		return
+	}
+	o.start(StepOrgConfig)
+	err := o.orgConfig.SetSpaceIDs(o.spaces.GetCommunitySpaceID(), o.spaces.GetCommunityReadOnlySpaceID(), o.spaces.GetAdminSpaceID())
+	if err != nil {
+		o.fail(StepOrgConfig, err)
+		return
+	}
+	o.done(StepOrgConfig, "", false)
+}
+
+func (o *Orchestrator) client() anysync.AnySyncClient {
+	if o.spaces == nil {
+		return nil
+	}
+	return o.spaces.GetClient()
+}
+
+// mnemonic returns a copy of the user's mnemonic; the caller zeroes it
+func (o *Orchestrator) mnemonic() *secret.Mnemonic {
+	if o.identity == nil {
+		return nil
+	}
+	return o.identity.GetMnemonic()
+}
+
+func (o *Orchestrator) spaceCreated(space *anysync.Space) {
+	if o.onCreated != nil {
+		o.onCreated(space)
+	}
+}
+
+// begin marks steps pending and the run as running
+func (o *Orchestrator) begin(orgAID string, steps ...string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if orgAID != "" {
+		o.status.OrgAID = orgAID
+	}
+	o.status.State = StateRunning
+	for _, name := range steps {
+		o.setLocked(Step{Name: name, State: StatePending})
+	}
+}
+
+func (o *Orchestrator) start(name string) {
+	o.update(Step{Name: name, State: StateRunning, StartedAt: now()})
+}
+
+func (o *Orchestrator) done(name, spaceID string, created bool) {
+	o.update(Step{Name: name, State: StateDone, SpaceID: spaceID, Created: created, FinishedAt: now()})
+}
+
+func (o *Orchestrator) skip(name, reason string) {
+	o.update(Step{Name: name, State: StateSkipped, Error: reason, FinishedAt: now()})
+}
+
+func (o *Orchestrator) fail(name string, err error) {
+	o.update(Step{Name: name, State: StateFailed, Error: err.Error(), FinishedAt: now()})
+}
+
+// update records a step, keeping its start time
+func (o *Orchestrator) update(step Step) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if step.StartedAt == "" {
+		step.StartedAt = o.status.Step(step.Name).StartedAt
+	}
+	o.setLocked(step)
+}
+
+func (o *Orchestrator) setLocked(step Step) {
+	for i := range o.status.Steps {
+		if o.status.Steps[i].Name == step.Name {
+			o.status.Steps[i] = step
+		}
+	}
+	o.status.UpdatedAt = now()
+}
+
+// finish sets the overall state from the steps: failed if any failed, done
+// once none are pending
+func (o *Orchestrator) finish() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.status.State = StateDone
+	for _, step := range o.status.Steps {
+		switch step.State {
+		case StateFailed:
+			o.status.State = StateFailed
+			return
+		case StatePending:
+			o.status.State = StatePending
+		}
+	}
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,229 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/anyproto/any-sync/commonspace"
+	"github.com/anyproto/any-sync/net/pool"
+	"github.com/anyproto/any-sync/nodeconf"
+	"github.com/anyproto/any-sync/util/crypto"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/identity"
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// fakeClient creates spaces in memory. Methods bootstrap doesn't use are
+// left to the embedded (nil) interface.
+type fakeClient struct {
+	anysync.AnySyncClient
+	dataDir string
+	key     crypto.PrivKey
+	spaces  map[string]bool // Space IDs that exist on the "network"
+	created []string        // Space types, in creation order
+}
+
+func newFakeClient(t *testing.T) *fakeClient {
+	t.Helper()
+	key, _, err := crypto.GenerateRandomEd25519KeyPair()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	return &fakeClient{dataDir: t.TempDir(), key: key, spaces: make(map[string]bool)}
+}
+
+func (c *fakeClient) CreateSpaceWithKeys(ctx context.Context, ownerAID string, spaceType string, keys *anysync.SpaceKeySet) (*anysync.SpaceCreateResult, error) {
+	id := fmt.Sprintf("%s-%d", spaceType, len(c.created))
+	c.created = append(c.created, spaceType)
+	c.spaces[id] = true
+	return &anysync.SpaceCreateResult{SpaceID: id, OwnerAID: ownerAID, SpaceType: spaceType}, nil
+}
+
+func (c *fakeClient) GetSpace(ctx context.Context, spaceID string) (commonspace.Space, error) {
+	if !c.spaces[spaceID] {
+		return nil, fmt.Errorf("space %s not found", spaceID)
+	}
+	return nil, nil
+}
+
+func (c *fakeClient) MakeSpaceShareable(ctx context.Context, spaceID string) error {
+	if !c.spaces[spaceID] {
+		return fmt.Errorf("space %s not found", spaceID)
+	}
+	return nil
+}
+
+func (c *fakeClient) GetSigningKey() crypto.PrivKey { return c.key }
+func (c *fakeClient) GetDataDir() string            { return c.dataDir }
+func (c *fakeClient) GetPool() pool.Pool            { return nil }
+func (c *fakeClient) GetNodeConf() nodeconf.Service { return nil }
+
+type fakeStore struct {
+	spaces map[string]*anysync.Space
+}
+
+func (s *fakeStore) GetUserSpace(ctx context.Context, userAID string) (*anysync.Space, error) {
+	return nil, nil
+}
+
+func (s *fakeStore) SaveSpace(ctx context.Context, space *anysync.Space) error {
+	s.spaces[space.SpaceID] = space
+	return nil
+}
+
+func (s *fakeStore) ListAllSpaces(ctx context.Context) ([]*anysync.Space, error) {
+	return nil, nil
+}
+
+type fakeOrgConfig struct {
+	configured bool
+	ids        [3]string
+}
+
+func (c *fakeOrgConfig) IsConfigured() bool { return c.configured }
+
+func (c *fakeOrgConfig) SetSpaceIDs(communitySpaceID, readOnlySpaceID, adminSpaceID string) error {
+	c.ids = [3]string{communitySpaceID, readOnlySpaceID, adminSpaceID}
+	return nil
+}
+
+func newTestOrchestrator(t *testing.T, withIdentity bool) (*Orchestrator, *fakeClient, *identity.UserIdentity) {
+	t.Helper()
+	client := newFakeClient(t)
+	userIdentity := identity.New(t.TempDir())
+	if withIdentity {
+		if err := userIdentity.SetIdentity("EADMIN", secret.NewMnemonic(testMnemonic)); err != nil {
+			t.Fatalf("SetIdentity failed: %v", err)
+		}
+	}
+	spaces := anysync.NewSpaceManager(client, &anysync.SpaceManagerConfig{})
+	return NewOrchestrator(spaces, &fakeStore{spaces: make(map[string]*anysync.Space)}, userIdentity), client, userIdentity
+}
+
+func TestRun(t *testing.T) {
+	o, client, userIdentity := newTestOrchestrator(t, true)
+	orgConfig := &fakeOrgConfig{configured: true}
+	o.SetOrgConfig(orgConfig)
+	var announced int
+	o.OnSpaceCreated(func(*anysync.Space) { announced++ })
+
+	status, err := o.Run(context.Background(), Request{OrgAID: "EORG", OrgName: "Test Org"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if status.State != StateDone || status.OrgAID != "EORG" {
+		t.Errorf("expected done for EORG, got %s for %s", status.State, status.OrgAID)
+	}
+	for _, step := range status.Steps {
+		if step.State != StateDone {
+			t.Errorf("step %s: expected done, got %s (%s)", step.Name, step.State, step.Error)
+		}
+	}
+	if len(client.created) != 4 || announced != 4 {
+		t.Errorf("expected 4 spaces created and announced, got %v and %d", client.created, announced)
+	}
+
+	community := status.Step(StepCommunitySpace)
+	if !community.Created || userIdentity.GetCommunitySpaceID() != community.SpaceID {
+		t.Errorf("expected community space recorded in identity, got %+v", community)
+	}
+	if userIdentity.GetPrivateSpaceID() != status.Step(StepPrivateSpace).SpaceID {
+		t.Error("expected private space recorded in identity")
+	}
+	want := [3]string{community.SpaceID, status.Step(StepReadOnlySpace).SpaceID, status.Step(StepAdminSpace).SpaceID}
+	if orgConfig.ids != want {
+		t.Errorf("expected org config space IDs %v, got %v", want, orgConfig.ids)
+	}
+	if _, err := anysync.LoadSpaceKeySet(client.dataDir, status.Step(StepPrivateSpace).SpaceID); err != nil {
+		t.Errorf("expected private space keys persisted: %v", err)
+	}
+}
+
+func TestCreateOrgSpaces_ReusesExisting(t *testing.T) {
+	o, client, _ := newTestOrchestrator(t, true)
+	ctx := context.Background()
+	req := Request{OrgAID: "EORG", OrgName: "Test Org"}
+
+	first, err := o.CreateOrgSpaces(ctx, req)
+	if err != nil {
+		t.Fatalf("CreateOrgSpaces failed: %v", err)
+	}
+	second, err := o.CreateOrgSpaces(ctx, req)
+	if err != nil {
+		t.Fatalf("second CreateOrgSpaces failed: %v", err)
+	}
+	if len(client.created) != 3 {
+		t.Errorf("expected 3 spaces created once, got %v", client.created)
+	}
+	for _, name := range []string{StepCommunitySpace, StepReadOnlySpace, StepAdminSpace} {
+		if step := second.Step(name); step.Created || step.SpaceID != first.Step(name).SpaceID {
+			t.Errorf("%s: expected existing space reused, got %+v", name, step)
+		}
+	}
+	if step := second.Step(StepOrgConfig); step.State != StateSkipped {
+		t.Errorf("expected org-config skipped without a writer, got %s", step.State)
+	}
+	if step := second.Step(StepPrivateSpace); step.State != StatePending || second.State != StatePending {
+		t.Errorf("expected private space still pending, got %s (run %s)", step.State, second.State)
+	}
+}
+
+func TestCreateOrgSpaces_RecreatesStaleSpaces(t *testing.T) {
+	o, client, _ := newTestOrchestrator(t, true)
+	ctx := context.Background()
+	req := Request{OrgAID: "EORG", OrgName: "Test Org"}
+	if _, err := o.CreateOrgSpaces(ctx, req); err != nil {
+		t.Fatalf("CreateOrgSpaces failed: %v", err)
+	}
+
+	// The network was reset
+	client.spaces = make(map[string]bool)
+	status, err := o.CreateOrgSpaces(ctx, req)
+	if err != nil {
+		t.Fatalf("CreateOrgSpaces failed: %v", err)
+	}
+	if len(client.created) != 6 || !status.Step(StepCommunitySpace).Created || !status.Step(StepAdminSpace).Created {
+		t.Errorf("expected all three spaces recreated, got %v", client.created)
+	}
+}
+
+func TestCreateOrgSpaces_NoIdentity(t *testing.T) {
+	o, _, _ := newTestOrchestrator(t, false)
+
+	status, err := o.CreateOrgSpaces(context.Background(), Request{OrgAID: "EORG"})
+	if !errors.Is(err, ErrNoIdentity) {
+		t.Fatalf("expected ErrNoIdentity, got %v", err)
+	}
+	if status.State != StateFailed || status.Step(StepCommunitySpace).State != StateFailed {
+		t.Errorf("expected community step failed, got %+v", status)
+	}
+	if step := status.Step(StepAdminSpace); step.State != StateSkipped {
+		t.Errorf("expected admin step skipped, got %s", step.State)
+	}
+}
+
+func TestRecoverOrgSpaces(t *testing.T) {
+	o, client, _ := newTestOrchestrator(t, true)
+	client.spaces["community-joined"] = true
+
+	status := o.RecoverOrgSpaces(context.Background(), "community-joined", "readonly-missing", "")
+	if step := status.Step(StepCommunitySpace); step.State != StateDone || step.Created {
+		t.Errorf("expected community recovered, got %+v", step)
+	}
+	if step := status.Step(StepReadOnlySpace); step.State != StateFailed {
+		t.Errorf("expected readonly failed, got %+v", step)
+	}
+	if step := status.Step(StepAdminSpace); step.State != StateSkipped {
+		t.Errorf("expected admin skipped, got %+v", step)
+	}
+	if _, err := anysync.LoadSpaceKeySet(client.dataDir, "community-joined"); err != nil {
+		t.Errorf("expected recovered space keys persisted: %v", err)
+	}
+	if len(client.created) != 0 {
+		t.Errorf("expected no spaces created, got %v", client.created)
+	}
+}
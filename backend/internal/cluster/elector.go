@@ -0,0 +1,167 @@
+// Package cluster elects a leader among API replicas sharing a record
+// store. The leader holds a lease in the shared store and renews it; the
+// other replicas see who holds it so they can forward writes there.
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LeaderLease is the lease name the leader holds
+const LeaderLease = "leader"
+
+// LeaseStore holds leases shared by the replicas
+type LeaseStore interface {
+	// AcquireLease takes or renews a lease for holder. It reports false
+	// while another holder's lease is unexpired.
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	// ReleaseLease gives up holder's lease so another replica can take it
+	ReleaseLease(ctx context.Context, name, holder string) error
+	// LeaseHolder returns the unexpired lease holder, or ""
+	LeaseHolder(ctx context.Context, name string) (string, error)
+}
+
+// Elector campaigns for the leader lease on behalf of this replica. The
+// holder is the replica's node URL, so followers learn where to forward
+// writes from the lease itself.
+type Elector struct {
+	store   LeaseStore
+	nodeURL string
+	ttl     time.Duration
+
+	mu        sync.RWMutex
+	leader    bool
+	leaderURL string
+	onChange  []func(leader bool)
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewElector creates an elector for the replica reachable at nodeURL
+func NewElector(store LeaseStore, nodeURL string, ttl time.Duration) *Elector {
+	return &Elector{
+		store:   store,
+		nodeURL: nodeURL,
+		ttl:     ttl,
+	}
+}
+
+// OnChange calls fn whenever this replica gains or loses leadership. Set
+// callbacks before Start.
+func (e *Elector) OnChange(fn func(leader bool)) {
+	e.onChange = append(e.onChange, fn)
+}
+
+// IsLeader reports whether this replica holds the leader lease
+func (e *Elector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leader
+}
+
+// LeaderURL returns the leader's node URL, or "" while there is none
+func (e *Elector) LeaderURL() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leaderURL
+}
+
+// NodeURL returns this replica's node URL
+func (e *Elector) NodeURL() string {
+	return e.nodeURL
+}
+
+// Start campaigns once and then renews or retries every third of the
+// lease TTL
+func (e *Elector) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	e.done = make(chan struct{})
+
+	e.Campaign(ctx)
+	go e.run(ctx)
+	fmt.Printf("[Cluster] Started leader election as %s (lease %s)\n", e.nodeURL, e.ttl)
+}
+
+// Stop ends the campaign and releases the lease if this replica holds it,
+// so another replica takes over without waiting for it to expire
+func (e *Elector) Stop() {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	if e.done != nil {
+		<-e.done
+	}
+	if e.IsLeader() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := e.store.ReleaseLease(ctx, LeaderLease, e.nodeURL); err != nil {
+			fmt.Printf("[Cluster] Warning: failed to release leader lease: %v\n", err)
+		}
+		e.set(false, "")
+	}
+	fmt.Println("[Cluster] Stopped leader election")
+}
+
+func (e *Elector) run(ctx context.Context) {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.Campaign(ctx)
+		}
+	}
+}
+
+// Campaign makes one attempt to take or renew the leader lease. A replica
+// that can't reach the store steps down at once rather than risk two
+// leaders once its lease expires.
+func (e *Elector) Campaign(ctx context.Context) {
+	acquired, err := e.store.AcquireLease(ctx, LeaderLease, e.nodeURL, e.ttl)
+	if err != nil {
+		if ctx.Err() == nil {
+			fmt.Printf("[Cluster] Leader lease unavailable: %v\n", err)
+		}
+		e.set(false, "")
+		return
+	}
+	if acquired {
+		e.set(true, e.nodeURL)
+		return
+	}
+	holder, err := e.store.LeaseHolder(ctx, LeaderLease)
+	if err != nil {
+		holder = ""
+	}
+	e.set(false, holder)
+}
+
+// set records the leader and notifies callbacks when leadership changes
+func (e *Elector) set(leader bool, leaderURL string) {
+	e.mu.Lock()
+	changed := e.leader != leader
+	e.leader, e.leaderURL = leader, leaderURL
+	e.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	if leader {
+		fmt.Println("[Cluster] This replica is now the leader")
+	} else {
+		fmt.Printf("[Cluster] This replica is now a follower (leader %q)\n", leaderURL)
+	}
+	for _, fn := range e.onChange {
+		fn(leader)
+	}
+}
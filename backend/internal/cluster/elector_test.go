@@ -0,0 +1,100 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memLeases is an in-memory LeaseStore
+type memLeases struct {
+	mu      sync.Mutex
+	holder  string
+	expires time.Time
+	err     error
+}
+
+func (m *memLeases) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return false, m.err
+	}
+	if m.holder != holder && time.Now().Before(m.expires) {
+		return false, nil
+	}
+	m.holder, m.expires = holder, time.Now().Add(ttl)
+	return true, nil
+}
+
+func (m *memLeases) ReleaseLease(ctx context.Context, name, holder string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.holder == holder {
+		m.holder, m.expires = "", time.Time{}
+	}
+	return nil
+}
+
+func (m *memLeases) LeaseHolder(ctx context.Context, name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if time.Now().Before(m.expires) {
+		return m.holder, nil
+	}
+	return "", nil
+}
+
+func TestElector_Campaign(t *testing.T) {
+	leases := &memLeases{}
+	ctx := context.Background()
+	a := NewElector(leases, "http://replica-a:8080", time.Minute)
+	b := NewElector(leases, "http://replica-b:8080", time.Minute)
+
+	var changes []bool
+	a.OnChange(func(leader bool) { changes = append(changes, leader) })
+
+	a.Campaign(ctx)
+	b.Campaign(ctx)
+	if !a.IsLeader() || b.IsLeader() {
+		t.Fatalf("expected a to lead, got a=%v b=%v", a.IsLeader(), b.IsLeader())
+	}
+	if b.LeaderURL() != "http://replica-a:8080" {
+		t.Errorf("expected b to know the leader, got %q", b.LeaderURL())
+	}
+
+	// Renewing keeps leadership without another change
+	a.Campaign(ctx)
+	if len(changes) != 1 || !changes[0] {
+		t.Errorf("expected one change to leader, got %v", changes)
+	}
+
+	// A leader that can't reach the store steps down
+	leases.err = errors.New("connection refused")
+	a.Campaign(ctx)
+	if a.IsLeader() || a.LeaderURL() != "" {
+		t.Error("expected a to step down when the store is unreachable")
+	}
+	if len(changes) != 2 || changes[1] {
+		t.Errorf("expected a change to follower, got %v", changes)
+	}
+}
+
+func TestElector_StopReleasesLease(t *testing.T) {
+	leases := &memLeases{}
+	a := NewElector(leases, "http://replica-a:8080", time.Minute)
+	b := NewElector(leases, "http://replica-b:8080", time.Minute)
+
+	a.Start()
+	a.Stop()
+	if a.IsLeader() {
+		t.Error("expected a stopped elector not to lead")
+	}
+
+	b.Campaign(context.Background())
+	if !b.IsLeader() {
+		t.Error("expected b to take over the released lease at once")
+	}
+}
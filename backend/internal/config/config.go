@@ -2,10 +2,16 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/matou-dao/backend/internal/flags"
 )
 
 // SMTPConfig holds SMTP relay configuration for sending emails
@@ -25,25 +31,469 @@ type Config struct {
 	AnySync   AnySyncConfig   `yaml:"anysync"`
 	Bootstrap BootstrapConfig `yaml:"bootstrap"`
 	SMTP      SMTPConfig      `yaml:"smtp"`
+	Logging   LoggingConfig   `yaml:"logging"`
+	Terms     TermsConfig     `yaml:"terms"`
+	Trust     TrustConfig     `yaml:"trust"`
+	Access    AccessConfig    `yaml:"access"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	SyncTest  SyncTestConfig  `yaml:"syncTest"`
+	Store     StoreConfig     `yaml:"store"`
+	Auth      AuthConfig      `yaml:"auth"`
+	AtRest    AtRestConfig    `yaml:"atRest"`
+	Outbound  OutboundConfig  `yaml:"outbound"`
+	Files     FilesConfig     `yaml:"files"`
+	Cluster   ClusterConfig   `yaml:"cluster"`
+	GRPC      GRPCConfig      `yaml:"grpc"`
+
+	// Features holds default feature flag state for this deployment.
+	// Runtime overrides are managed by the flags package.
+	Features map[string]bool `yaml:"features"`
+}
+
+// LoggingConfig holds HTTP access log configuration
+type LoggingConfig struct {
+	AccessLog bool `yaml:"accessLog"`
+	// LogBodies includes JSON bodies (with secrets redacted) in access logs
+	LogBodies bool `yaml:"logBodies"`
+	// SampleRates maps path prefixes to the fraction of requests logged.
+	// Error responses are always logged.
+	SampleRates map[string]float64 `yaml:"sampleRates"`
+	// RedactFields adds JSON keys to the built-in redaction list
+	RedactFields []string `yaml:"redactFields,omitempty"`
+}
+
+// TermsConfig holds settings for term-limited role credentials and
+// credentials that expire
+type TermsConfig struct {
+	// CheckInterval is how often terms are checked for expiry
+	CheckInterval time.Duration `yaml:"checkInterval"`
+	// NoticeWindow is how long before expiry holders and stewards are notified
+	NoticeWindow time.Duration `yaml:"noticeWindow"`
+	// ExpiryInterval is how often cached credentials are checked for a
+	// passed expiresAt and marked expired; 0 turns the check off
+	ExpiryInterval time.Duration `yaml:"expiryInterval"`
+}
+
+// TrustConfig holds the server's trust scoring settings
+type TrustConfig struct {
+	// Weights overrides the built-in score weights by name, e.g.
+	// uniqueIssuer: 1.5. An org's trustWeights replace them for that org.
+	Weights map[string]float64 `yaml:"weights,omitempty"`
+	// HistoryInterval is how often every AID's score is recorded for
+	// score history (0 = no history). Read at startup.
+	HistoryInterval time.Duration `yaml:"historyInterval"`
+	// MaterializeInterval is the longest the trust graph stored in the
+	// local store is used without rebuilding it; credential writes rebuild
+	// it sooner (0 = always build from credentials). Read at startup.
+	MaterializeInterval time.Duration `yaml:"materializeInterval"`
+}
+
+// AccessConfig holds per-tier request limits. Guests are identities without
+// a membership credential; they get a stricter limit than members.
+type AccessConfig struct {
+	// GuestRequestsPerMinute limits guests and anonymous callers (0 = unlimited)
+	GuestRequestsPerMinute int `yaml:"guestRequestsPerMinute"`
+	// MemberRequestsPerMinute limits members (0 = unlimited)
+	MemberRequestsPerMinute int `yaml:"memberRequestsPerMinute"`
+	// KERIAProxyRequestsPerMinute limits each AID's requests through the
+	// KERIA proxy (0 = unlimited). signify polls operations, so this is
+	// higher than the tier limits.
+	KERIAProxyRequestsPerMinute int `yaml:"keriaProxyRequestsPerMinute"`
+
+	// ExpensiveConcurrency bounds how many trust graph builds (graph,
+	// scores, summary, snapshot, export) run at once; more are shed with
+	// a cached response or 503 (0 = unlimited)
+	ExpensiveConcurrency int `yaml:"expensiveConcurrency"`
+	// ShedStaleFor is how old a cached response may be and still be served
+	// to a shed request
+	ShedStaleFor time.Duration `yaml:"shedStaleFor"`
+	// ShedRoutes replaces the routes that count against the budget
+	ShedRoutes []string `yaml:"shedRoutes,omitempty"`
+
+	// RouteLimits are token-bucket budgets for expensive routes. An entry
+	// named like a built-in budget (trust, issuance, uploads) replaces it;
+	// others are added.
+	RouteLimits []RouteLimitConfig `yaml:"routeLimits,omitempty"`
+}
+
+// RouteLimitConfig budgets a group of routes per client address and per
+// authenticated AID
+type RouteLimitConfig struct {
+	Name string `yaml:"name"`
+	// Routes are exact paths, or prefixes when they end in "/"
+	Routes []string `yaml:"routes"`
+	// Methods limits the budget to these methods; empty matches all
+	Methods []string `yaml:"methods,omitempty"`
+	// PerIP and PerAID are sustained requests per minute (0 = unlimited)
+	PerIP  int `yaml:"perIp"`
+	PerAID int `yaml:"perAid"`
+	// Burst is how many requests may arrive at once (0 = the per-minute budget)
+	Burst int `yaml:"burst"`
+}
+
+// MetricsConfig controls the Prometheus /metrics endpoint. The endpoint
+// is unauthenticated; to keep it off a public listener, serve it on a
+// dedicated listener with routes: ["/metrics"].
+type MetricsConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// SyncTestConfig holds settings for the admin sync health test
+type SyncTestConfig struct {
+	// PeerURL is another backend's base URL (e.g. "http://10.0.0.2:8080").
+	// When set, sync tests also wait for the probe to reach that backend.
+	PeerURL string `yaml:"peerUrl,omitempty"`
+	// Timeout is how long a sync test waits for the probe to propagate
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// StoreConfig holds settings for local anystore maintenance
+type StoreConfig struct {
+	// VacuumInterval is how often the store is vacuumed (0 = only on demand)
+	VacuumInterval time.Duration `yaml:"vacuumInterval"`
+	// Retention for prunable data; a zero duration keeps it forever.
+	// Expired credential cache entries are always pruned.
+	TrustCacheRetention   time.Duration `yaml:"trustCacheRetention"`
+	InboxRetention        time.Duration `yaml:"inboxRetention"`
+	PresenceRetention     time.Duration `yaml:"presenceRetention"`
+	TrustHistoryRetention time.Duration `yaml:"trustHistoryRetention"`
+	// Records selects where the backend's own records are kept
+	Records RecordsConfig `yaml:"records"`
+}
+
+// RecordsConfig selects the storage for space records, peer mappings,
+// presence, inbox items, invites and preferences. Personal nodes keep them
+// in the anystore file; org deployments can use a managed database.
+// Caches rebuilt from KERIA and the spaces always stay in the anystore file.
+type RecordsConfig struct {
+	// Driver is anystore (the default), sqlite or postgres
+	Driver string `yaml:"driver"`
+	// DSN is the SQLite file path or Postgres connection URL
+	DSN string `yaml:"dsn,omitempty"`
+}
+
+// Validate checks the driver and that a database driver has a DSN
+func (r RecordsConfig) Validate() error {
+	switch r.Driver {
+	case "", "anystore":
+		return nil
+	case "sqlite", "postgres":
+		if r.DSN == "" {
+			return fmt.Errorf("store records driver %s needs a dsn", r.Driver)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown store records driver %q (expected anystore, sqlite or postgres)", r.Driver)
+}
+
+// ClusterConfig runs several API replicas against a shared record store.
+// One replica at a time holds the leader lease and does all writes; the
+// others serve reads and forward writes to it.
+type ClusterConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// NodeURL is where the other replicas reach this one, e.g. http://10.0.0.5:8080
+	NodeURL string `yaml:"nodeUrl"`
+	// LeaseTTL is how long the leader lease lasts without renewal, bounding
+	// how long writes fail over after the leader dies
+	LeaseTTL time.Duration `yaml:"leaseTtl"`
+}
+
+// Validate checks the node URL, lease and that records are shared
+func (c ClusterConfig) Validate(records RecordsConfig) error {
+	if !c.Enabled {
+		return nil
+	}
+	if u, err := url.Parse(c.NodeURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("cluster node URL must be an absolute URL")
+	}
+	if c.LeaseTTL < 3*time.Second {
+		return fmt.Errorf("cluster lease TTL must be at least 3s")
+	}
+	if records.Driver != "sqlite" && records.Driver != "postgres" {
+		return fmt.Errorf("cluster mode needs a shared record store (store.records.driver sqlite or postgres)")
+	}
+	return nil
+}
+
+// GRPCConfig serves the gRPC API on its own listener. It is off unless
+// Address is set. Calls go through the same authentication and limits as
+// the REST routes they mirror, but aren't restricted by listener routes, so
+// bind it where only trusted services can reach it.
+type GRPCConfig struct {
+	Network string `yaml:"network"` // "tcp" (default), "tcp4", "tcp6" or "unix"
+	Address string `yaml:"address"` // host:port or socket path
+}
+
+// Enabled reports whether the gRPC API is served
+func (g GRPCConfig) Enabled() bool {
+	return g.Address != ""
+}
+
+// Listener returns the gRPC listener
+func (g GRPCConfig) Listener() ListenerConfig {
+	network := g.Network
+	if network == "" {
+		network = "tcp"
+	}
+	return ListenerConfig{Name: "grpc", Network: network, Address: g.Address}
+}
+
+// FilesConfig controls file uploads
+type FilesConfig struct {
+	// UserQuotaMB caps the total size of the files each user uploads,
+	// renditions included (0 = unlimited)
+	UserQuotaMB int `yaml:"userQuotaMB"`
+	// MaxUploadMB caps the size of a file uploaded in chunks (0 = chunked
+	// uploads disabled)
+	MaxUploadMB int `yaml:"maxUploadMB"`
+	// CacheMB caps the on-disk cache of file blocks fetched from the
+	// filenode (0 = no cache)
+	CacheMB int `yaml:"cacheMB"`
+	// Renditions are the scaled copies stored alongside uploaded images.
+	// An empty list stores only the original.
+	Renditions []RenditionConfig `yaml:"renditions"`
+}
+
+// RenditionConfig is a scaled copy of uploaded images, fitting a
+// Size×Size box
+type RenditionConfig struct {
+	Name string `yaml:"name"`
+	Size int    `yaml:"size"`
+}
+
+// minAPIKeyLength rejects keys short enough to guess
+const minAPIKeyLength = 16
+
+// AuthConfig controls API authentication. It is off by default: a backend
+// normally serves only its own user's frontend on localhost. Turn it on
+// before exposing a backend on a network.
+type AuthConfig struct {
+	Enabled bool           `yaml:"enabled"`
+	APIKeys []APIKeyConfig `yaml:"apiKeys,omitempty"`
+	// TokenMaxAge bounds how far ahead an AID token may expire
+	TokenMaxAge time.Duration `yaml:"tokenMaxAge"`
+	// Routes overrides the built-in route requirements, mapping a path
+	// (optionally preceded by a method, e.g. "POST /api/v1/org/config") to
+	// public, user or admin
+	Routes map[string]string `yaml:"routes,omitempty"`
+}
+
+// APIKeyConfig is a static API key sent as "Authorization: Bearer <key>"
+type APIKeyConfig struct {
+	Name  string `yaml:"name"`
+	Key   string `yaml:"key"`
+	Admin bool   `yaml:"admin"`
+}
+
+// AtRestConfig controls encryption of identity.json and space key bundles.
+// Encryption is on when a passphrase is available. The desktop app keeps a
+// passphrase in the OS keyring and passes it as MATOU_IDENTITY_PASSPHRASE.
+type AtRestConfig struct {
+	// PassphraseFile holds the passphrase, e.g. a systemd credential
+	PassphraseFile string `yaml:"passphraseFile,omitempty"`
+	// Passphrase is only taken from MATOU_IDENTITY_PASSPHRASE, never YAML
+	Passphrase string `yaml:"-"`
+}
+
+// OutboundConfig routes outbound connections (KERIA, OOBIs, peer
+// descriptors, SMTP) through a proxy and trusts extra CAs, for deployments
+// behind a corporate proxy. Without Proxy, the standard HTTPS_PROXY,
+// HTTP_PROXY and NO_PROXY environment variables apply.
+type OutboundConfig struct {
+	// Proxy is an http, https or socks5 proxy URL, e.g. http://proxy:3128
+	Proxy string `yaml:"proxy,omitempty"`
+	// NoProxy lists hosts, domains and CIDRs that bypass Proxy
+	// (comma-separated); loopback addresses always do
+	NoProxy string `yaml:"noProxy,omitempty"`
+	// CABundle is a PEM file of CAs to trust besides the system's
+	CABundle string `yaml:"caBundle,omitempty"`
+}
+
+// Validate checks the proxy URL
+func (o OutboundConfig) Validate() error {
+	if o.Proxy == "" {
+		return nil
+	}
+	u, err := url.Parse(o.Proxy)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("outbound proxy must be a URL like http://proxy:3128")
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return nil
+	}
+	return fmt.Errorf("outbound proxy scheme %q is not supported (expected http, https or socks5)", u.Scheme)
+}
+
+// ResolvePassphrase returns the passphrase, preferring the environment over
+// PassphraseFile. It is empty when encryption is off.
+func (a AtRestConfig) ResolvePassphrase() (string, error) {
+	if a.Passphrase != "" || a.PassphraseFile == "" {
+		return a.Passphrase, nil
+	}
+	data, err := os.ReadFile(a.PassphraseFile)
+	if err != nil {
+		return "", fmt.Errorf("reading passphrase file: %w", err)
+	}
+	passphrase := strings.TrimRight(string(data), "\r\n")
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase file %s is empty", a.PassphraseFile)
+	}
+	return passphrase, nil
 }
 
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
 	Host string `yaml:"host"`
 	Port int    `yaml:"port"`
+
+	// Connection-level timeouts applied to the http.Server
+	ReadTimeout       time.Duration `yaml:"readTimeout"`
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout"`
+	WriteTimeout      time.Duration `yaml:"writeTimeout"`
+	IdleTimeout       time.Duration `yaml:"idleTimeout"`
+
+	// RequestTimeout is the default per-request deadline budget.
+	// RouteTimeouts overrides it by path prefix (longest match wins);
+	// a zero duration disables the deadline for that route (e.g. SSE).
+	RequestTimeout time.Duration            `yaml:"requestTimeout"`
+	RouteTimeouts  map[string]time.Duration `yaml:"routeTimeouts"`
+
+	// ShutdownTimeout bounds how long in-flight requests are drained on
+	// SIGINT/SIGTERM before connections are closed
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+
+	// ConsistencyTimeout bounds how long a read carrying a consistency
+	// token waits for the client's writes to be applied
+	ConsistencyTimeout time.Duration `yaml:"consistencyTimeout"`
+
+	// WatchTimeout bounds how long a ?watch=true request is held waiting
+	// for its data to change (0 = watching disabled)
+	WatchTimeout time.Duration `yaml:"watchTimeout"`
+
+	// Listeners binds additional addresses. When empty, a single TCP
+	// listener on Host:Port serving all routes is used.
+	Listeners []ListenerConfig `yaml:"listeners,omitempty"`
+
+	// LogFile writes server output to a rotating file as well as stdout
+	LogFile LogFileConfig `yaml:"logFile"`
+}
+
+// LogFileConfig holds log file output and rotation settings. Logging to a
+// file is off unless Path is set.
+type LogFileConfig struct {
+	Path string `yaml:"path"`
+	// MaxSizeMB rotates the file once it reaches this size (0 = no size limit)
+	MaxSizeMB int `yaml:"maxSizeMB"`
+	// MaxAge rotates the file once it has been written for this long (0 = no age limit)
+	MaxAge time.Duration `yaml:"maxAge"`
+	// MaxBackups is how many rotated files are kept (0 = keep all)
+	MaxBackups int `yaml:"maxBackups"`
+	// Compress gzips rotated files
+	Compress bool `yaml:"compress"`
+	// Quiet stops output also being written to stdout
+	Quiet bool `yaml:"quiet"`
+}
+
+// ListenerConfig describes one address the HTTP server listens on
+type ListenerConfig struct {
+	Name    string `yaml:"name"`
+	Network string `yaml:"network"` // "tcp" (default), "tcp4", "tcp6" or "unix"
+	Address string `yaml:"address"` // host:port, [ipv6]:port, or socket path
+	// Routes restricts the listener to these path prefixes (e.g. "/api/v1/admin/").
+	// Empty means all routes are served.
+	Routes []string `yaml:"routes,omitempty"`
+}
+
+// ResolvedListeners returns the configured listeners, or a single default
+// listener on Host:Port when none are configured. IPv6 hosts are bracketed.
+func (s ServerConfig) ResolvedListeners() []ListenerConfig {
+	if len(s.Listeners) > 0 {
+		listeners := make([]ListenerConfig, len(s.Listeners))
+		for i, l := range s.Listeners {
+			if l.Network == "" {
+				l.Network = "tcp"
+			}
+			if l.Name == "" {
+				l.Name = fmt.Sprintf("listener-%d", i)
+			}
+			listeners[i] = l
+		}
+		return listeners
+	}
+	return []ListenerConfig{{
+		Name:    "default",
+		Network: "tcp",
+		Address: net.JoinHostPort(s.Host, strconv.Itoa(s.Port)),
+	}}
+}
+
+// Validate checks the listener configuration
+func (l ListenerConfig) Validate() error {
+	switch l.Network {
+	case "tcp", "tcp4", "tcp6", "unix":
+	default:
+		return fmt.Errorf("listener %s: unsupported network %q", l.Name, l.Network)
+	}
+	if l.Address == "" {
+		return fmt.Errorf("listener %s: address is required", l.Name)
+	}
+	return nil
 }
 
-// KERIConfig holds KERI/KERIA connection configuration
+// KERIConfig holds KERI/KERIA connection configuration. The frontend drives
+// KERIA through signify-ts (there is no kli or docker dependency). The
+// backend reads public KELs from the CESR URL to verify signed requests, and
+// its KERIA reverse proxy (/api/v1/keria/) forwards signify traffic to the
+// admin URL, and agent boot requests to the boot URL, so the frontend only
+// needs to reach the backend.
 type KERIConfig struct {
 	AdminURL string `yaml:"adminUrl"`
 	BootURL  string `yaml:"bootUrl"`
 	CESRURL  string `yaml:"cesrUrl"`
+
+	// RequireSignatures makes requests that act as an AID (identity set,
+	// credential and KEL sync, endorsements) carry a signify-style HTTP
+	// signature from that AID's current keys
+	RequireSignatures bool `yaml:"requireSignatures"`
+	// SignatureMaxSkew is how far a signature's timestamp may be from now
+	SignatureMaxSkew time.Duration `yaml:"signatureMaxSkew"`
+	// KeyStateTTL is how long an AID's key state from KERIA is cached
+	KeyStateTTL time.Duration `yaml:"keyStateTtl"`
+
+	// Witnesses is the org AID's witness pool until the org config sets
+	// its own
+	Witnesses []WitnessConfig `yaml:"witnesses"`
+	// WitnessThreshold is the receipts that pool requires; zero means a
+	// majority
+	WitnessThreshold int `yaml:"witnessThreshold"`
+}
+
+// WitnessConfig is a KERI witness: its AID and the URL it serves on
+type WitnessConfig struct {
+	AID string `yaml:"aid"`
+	URL string `yaml:"url"`
 }
 
 // AnySyncConfig holds any-sync connection configuration
 type AnySyncConfig struct {
 	ClientConfigPath string `yaml:"clientConfigPath"`
 	NetworkID        string `yaml:"networkId"`
+	// ChangeEncoding is how new object and credential changes are encoded:
+	// "json" (default) or "compact". Readers accept both, but peers on older
+	// versions can't read compact changes.
+	ChangeEncoding string `yaml:"changeEncoding"`
+	// MigrateChanges rewrites existing trees into ChangeEncoding at startup
+	MigrateChanges bool `yaml:"migrateChanges"`
+	// SpaceGCInterval is how often the local storage of spaces deleted on
+	// the coordinator is removed (0 = only on demand)
+	SpaceGCInterval time.Duration `yaml:"spaceGcInterval"`
+	// ACLReconcileInterval is how often the community space ACL is
+	// reconciled against membership credentials (0 = only on demand)
+	ACLReconcileInterval time.Duration `yaml:"aclReconcileInterval"`
+	// InviteSweepInterval is how often issued invites are checked for use
+	// and expiry and revoked once used up or expired (0 = only when listed)
+	InviteSweepInterval time.Duration `yaml:"inviteSweepInterval"`
 }
 
 // BootstrapConfig holds bootstrap identity information
@@ -103,16 +553,98 @@ func Load(configPath, bootstrapPath string) (*Config, error) {
 	cfg := &Config{
 		// Default values
 		Server: ServerConfig{
-			Host: "localhost",
-			Port: 8080,
+			Host:              "localhost",
+			Port:              8080,
+			ReadTimeout:       30 * time.Second,
+			ReadHeaderTimeout: 10 * time.Second,
+			WriteTimeout:      60 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			RequestTimeout:    30 * time.Second,
+			RouteTimeouts: map[string]time.Duration{
+				"/api/v1/events":       0,               // SSE stream is long-lived
+				"/api/v1/identity/set": 2 * time.Minute, // SDK restart + space recovery
+				"/api/v1/spaces/":      2 * time.Minute, // space creation talks to the network
+				"/api/v1/files/upload": 2 * time.Minute,
+				// inbox creation and first delivery join spaces on the network
+				"/api/v1/inbox": 2 * time.Minute,
+				// sync tests wait for the probe to propagate
+				"/api/v1/admin/sync-test": 2 * time.Minute,
+				// vacuum scans every prunable collection
+				"/api/v1/admin/store/vacuum": 2 * time.Minute,
+			},
+			ShutdownTimeout:    15 * time.Second,
+			ConsistencyTimeout: 5 * time.Second,
+			WatchTimeout:       time.Minute,
+			LogFile: LogFileConfig{
+				MaxSizeMB:  100,
+				MaxAge:     24 * time.Hour,
+				MaxBackups: 7,
+				Compress:   true,
+			},
 		},
 		KERI: KERIConfig{
 			AdminURL: "http://localhost:3901",
 			BootURL:  "http://localhost:3903",
 			CESRURL:  "http://localhost:3902",
+
+			SignatureMaxSkew: 5 * time.Minute,
+			KeyStateTTL:      time.Minute,
 		},
 		AnySync: AnySyncConfig{
-			ClientConfigPath: "config/client.yml",
+			ClientConfigPath:     "config/client.yml",
+			SpaceGCInterval:      24 * time.Hour,
+			ACLReconcileInterval: 15 * time.Minute,
+			InviteSweepInterval:  time.Minute,
+		},
+		Logging: LoggingConfig{
+			SampleRates: map[string]float64{
+				// Polled endpoints would otherwise drown out everything else
+				"/health":                    0.01,
+				"/metrics":                   0.01,
+				"/api/v1/spaces/sync-status": 0.1,
+			},
+		},
+		Terms: TermsConfig{
+			CheckInterval:  time.Hour,
+			NoticeWindow:   14 * 24 * time.Hour,
+			ExpiryInterval: time.Hour,
+		},
+		Access: AccessConfig{
+			GuestRequestsPerMinute:      120,
+			KERIAProxyRequestsPerMinute: 600,
+			ExpensiveConcurrency:        4,
+			ShedStaleFor:                5 * time.Minute,
+		},
+		Trust: TrustConfig{
+			HistoryInterval:     24 * time.Hour,
+			MaterializeInterval: time.Hour,
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+		},
+		SyncTest: SyncTestConfig{
+			Timeout: 30 * time.Second,
+		},
+		Store: StoreConfig{
+			VacuumInterval:        24 * time.Hour,
+			TrustCacheRetention:   7 * 24 * time.Hour,
+			InboxRetention:        90 * 24 * time.Hour,
+			PresenceRetention:     180 * 24 * time.Hour,
+			TrustHistoryRetention: 2 * 365 * 24 * time.Hour,
+		},
+		Auth: AuthConfig{
+			TokenMaxAge: time.Hour,
+		},
+		Cluster: ClusterConfig{
+			LeaseTTL: 15 * time.Second,
+		},
+		Files: FilesConfig{
+			MaxUploadMB: 100,
+			CacheMB:     256,
+			Renditions: []RenditionConfig{
+				{Name: "thumb", Size: 128},
+				{Name: "medium", Size: 512},
+			},
 		},
 		SMTP: SMTPConfig{
 			Host:        "localhost",
@@ -150,9 +682,206 @@ func Load(configPath, bootstrapPath string) (*Config, error) {
 		}
 	}
 
+	if limitStr := os.Getenv("MATOU_GUEST_RATE_LIMIT"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			cfg.Access.GuestRequestsPerMinute = limit
+		}
+	}
+	if limitStr := os.Getenv("MATOU_KERIA_PROXY_RATE_LIMIT"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			cfg.Access.KERIAProxyRequestsPerMinute = limit
+		}
+	}
+	if limitStr := os.Getenv("MATOU_EXPENSIVE_CONCURRENCY"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			cfg.Access.ExpensiveConcurrency = limit
+		}
+	}
+	if quotaStr := os.Getenv("MATOU_FILE_QUOTA_MB"); quotaStr != "" {
+		if quota, err := strconv.Atoi(quotaStr); err == nil {
+			cfg.Files.UserQuotaMB = quota
+		}
+	}
+	if maxStr := os.Getenv("MATOU_FILE_MAX_UPLOAD_MB"); maxStr != "" {
+		if maxUpload, err := strconv.Atoi(maxStr); err == nil {
+			cfg.Files.MaxUploadMB = maxUpload
+		}
+	}
+	if cacheStr := os.Getenv("MATOU_FILE_CACHE_MB"); cacheStr != "" {
+		if cacheMB, err := strconv.Atoi(cacheStr); err == nil {
+			cfg.Files.CacheMB = cacheMB
+		}
+	}
+
+	// Apply server timeout env var overrides (Go duration strings, e.g. "45s")
+	applyDurationEnv("MATOU_SERVER_READ_TIMEOUT", &cfg.Server.ReadTimeout)
+	applyDurationEnv("MATOU_SERVER_WRITE_TIMEOUT", &cfg.Server.WriteTimeout)
+	applyDurationEnv("MATOU_SERVER_IDLE_TIMEOUT", &cfg.Server.IdleTimeout)
+	applyDurationEnv("MATOU_REQUEST_TIMEOUT", &cfg.Server.RequestTimeout)
+	applyDurationEnv("MATOU_SERVER_SHUTDOWN_TIMEOUT", &cfg.Server.ShutdownTimeout)
+	applyDurationEnv("MATOU_CONSISTENCY_TIMEOUT", &cfg.Server.ConsistencyTimeout)
+	applyDurationEnv("MATOU_WATCH_TIMEOUT", &cfg.Server.WatchTimeout)
+
+	// Log file output: MATOU_LOG_FILE enables it, MATOU_LOG_MAX_AGE tunes rotation
+	if path := os.Getenv("MATOU_LOG_FILE"); path != "" {
+		cfg.Server.LogFile.Path = path
+	}
+	if sizeStr := os.Getenv("MATOU_LOG_MAX_SIZE_MB"); sizeStr != "" {
+		if size, err := strconv.Atoi(sizeStr); err == nil {
+			cfg.Server.LogFile.MaxSizeMB = size
+		}
+	}
+	applyDurationEnv("MATOU_LOG_MAX_AGE", &cfg.Server.LogFile.MaxAge)
+	applyDurationEnv("MATOU_TERM_NOTICE_WINDOW", &cfg.Terms.NoticeWindow)
+	applyDurationEnv("MATOU_CREDENTIAL_EXPIRY_INTERVAL", &cfg.Terms.ExpiryInterval)
+	applyDurationEnv("MATOU_TRUST_HISTORY_INTERVAL", &cfg.Trust.HistoryInterval)
+	applyDurationEnv("MATOU_TRUST_MATERIALIZE_INTERVAL", &cfg.Trust.MaterializeInterval)
+	applyDurationEnv("MATOU_STORE_VACUUM_INTERVAL", &cfg.Store.VacuumInterval)
+	if driver := os.Getenv("MATOU_RECORDS_DRIVER"); driver != "" {
+		cfg.Store.Records.Driver = driver
+	}
+	if dsn := os.Getenv("MATOU_RECORDS_DSN"); dsn != "" {
+		cfg.Store.Records.DSN = dsn
+	}
+
+	// MATOU_API_KEY adds an admin API key and turns authentication on;
+	// MATOU_AUTH=0 or 1 turns it off or on explicitly
+	if key := os.Getenv("MATOU_API_KEY"); key != "" {
+		cfg.Auth.Enabled = true
+		cfg.Auth.APIKeys = append(cfg.Auth.APIKeys, APIKeyConfig{Name: "env", Key: key, Admin: true})
+	}
+	switch os.Getenv("MATOU_AUTH") {
+	case "0", "false":
+		cfg.Auth.Enabled = false
+	case "1", "true":
+		cfg.Auth.Enabled = true
+	}
+	applyDurationEnv("MATOU_AUTH_TOKEN_MAX_AGE", &cfg.Auth.TokenMaxAge)
+
+	// At-rest encryption passphrase, directly or from a file
+	cfg.AtRest.Passphrase = os.Getenv("MATOU_IDENTITY_PASSPHRASE")
+	if path := os.Getenv("MATOU_IDENTITY_PASSPHRASE_FILE"); path != "" {
+		cfg.AtRest.PassphraseFile = path
+	}
+
+	// Outbound proxy and CA bundle; HTTPS_PROXY and NO_PROXY also work
+	// without these
+	if proxy := os.Getenv("MATOU_OUTBOUND_PROXY"); proxy != "" {
+		cfg.Outbound.Proxy = proxy
+	}
+	if noProxy := os.Getenv("MATOU_OUTBOUND_NO_PROXY"); noProxy != "" {
+		cfg.Outbound.NoProxy = noProxy
+	}
+	if bundle := os.Getenv("MATOU_CA_BUNDLE"); bundle != "" {
+		cfg.Outbound.CABundle = bundle
+	}
+
+	switch os.Getenv("MATOU_REQUIRE_SIGNATURES") {
+	case "0", "false":
+		cfg.KERI.RequireSignatures = false
+	case "1", "true":
+		cfg.KERI.RequireSignatures = true
+	}
+
+	if encoding := os.Getenv("MATOU_CHANGE_ENCODING"); encoding != "" {
+		cfg.AnySync.ChangeEncoding = encoding
+	}
+	applyDurationEnv("MATOU_SPACE_GC_INTERVAL", &cfg.AnySync.SpaceGCInterval)
+	applyDurationEnv("MATOU_ACL_RECONCILE_INTERVAL", &cfg.AnySync.ACLReconcileInterval)
+	applyDurationEnv("MATOU_INVITE_SWEEP_INTERVAL", &cfg.AnySync.InviteSweepInterval)
+
+	if peerURL := os.Getenv("MATOU_SYNC_TEST_PEER"); peerURL != "" {
+		cfg.SyncTest.PeerURL = peerURL
+	}
+
+	// MATOU_METRICS=0 disables the /metrics endpoint
+	switch os.Getenv("MATOU_METRICS") {
+	case "0", "false":
+		cfg.Metrics.Enabled = false
+	case "1", "true":
+		cfg.Metrics.Enabled = true
+	}
+
+	// Cluster mode: MATOU_CLUSTER_NODE_URL enables it for this replica
+	if nodeURL := os.Getenv("MATOU_CLUSTER_NODE_URL"); nodeURL != "" {
+		cfg.Cluster.Enabled = true
+		cfg.Cluster.NodeURL = nodeURL
+	}
+	applyDurationEnv("MATOU_CLUSTER_LEASE_TTL", &cfg.Cluster.LeaseTTL)
+
+	// MATOU_GRPC_LISTEN serves the gRPC API, e.g. "127.0.0.1:9090" or
+	// "unix:/run/matou-grpc.sock"
+	if listen := os.Getenv("MATOU_GRPC_LISTEN"); listen != "" {
+		if listeners := ParseListenList(listen); len(listeners) > 0 {
+			cfg.GRPC.Network, cfg.GRPC.Address = listeners[0].Network, listeners[0].Address
+		}
+	}
+
+	// Access logging: MATOU_ACCESS_LOG=1 enables, MATOU_ACCESS_LOG=bodies also logs redacted bodies
+	switch os.Getenv("MATOU_ACCESS_LOG") {
+	case "1", "true":
+		cfg.Logging.AccessLog = true
+	case "bodies":
+		cfg.Logging.AccessLog = true
+		cfg.Logging.LogBodies = true
+	}
+
+	// MATOU_LISTEN replaces the listener set with a comma-separated list of
+	// addresses, e.g. "127.0.0.1:8080,[::1]:8080,unix:/run/matou.sock"
+	if listen := os.Getenv("MATOU_LISTEN"); listen != "" {
+		cfg.Server.Listeners = ParseListenList(listen)
+	}
+
+	// Apply feature flag overrides, e.g. MATOU_FEATURES=governance,-messaging
+	if features := os.Getenv("MATOU_FEATURES"); features != "" {
+		if cfg.Features == nil {
+			cfg.Features = make(map[string]bool)
+		}
+		for name, enabled := range flags.ParseList(features) {
+			cfg.Features[name] = enabled
+		}
+	}
+
 	return cfg, nil
 }
 
+// applyDurationEnv sets target from the named env var if it holds a valid duration
+func applyDurationEnv(name string, target *time.Duration) {
+	value := os.Getenv(name)
+	if value == "" {
+		return
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		fmt.Printf("Ignoring invalid %s=%q: %v\n", name, value, err)
+		return
+	}
+	*target = d
+}
+
+// ParseListenList parses a comma-separated address list into listeners.
+// Entries prefixed with "unix:" are Unix sockets; all others are TCP.
+func ParseListenList(list string) []ListenerConfig {
+	listeners := make([]ListenerConfig, 0)
+	for _, addr := range strings.Split(list, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		l := ListenerConfig{
+			Name:    fmt.Sprintf("listener-%d", len(listeners)),
+			Network: "tcp",
+			Address: addr,
+		}
+		if strings.HasPrefix(addr, "unix:") {
+			l.Network = "unix"
+			l.Address = strings.TrimPrefix(addr, "unix:")
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners
+}
+
 // loadYAML loads a YAML file into a struct
 func loadYAML(path string, target interface{}) error {
 	data, err := os.ReadFile(path)
@@ -173,7 +902,168 @@ func (c *Config) Validate() error {
 	if c.KERI.AdminURL == "" {
 		return fmt.Errorf("KERI admin URL is required")
 	}
+	if c.KERI.RequireSignatures && c.KERI.CESRURL == "" {
+		return fmt.Errorf("KERI CESR URL is required to verify request signatures")
+	}
+	if c.KERI.SignatureMaxSkew < 0 || c.KERI.KeyStateTTL < 0 {
+		return fmt.Errorf("KERI signature max skew and key state TTL must not be negative")
+	}
+	if c.KERI.WitnessThreshold < 0 || c.KERI.WitnessThreshold > len(c.KERI.Witnesses) {
+		return fmt.Errorf("KERI witness threshold must be between 0 and the number of witnesses")
+	}
+
+	if c.Terms.ExpiryInterval < 0 {
+		return fmt.Errorf("credential expiry interval must not be negative")
+	}
+
+	for name, weight := range c.Trust.Weights {
+		if weight < 0 {
+			return fmt.Errorf("trust weight %s cannot be negative", name)
+		}
+	}
+	if c.Trust.HistoryInterval < 0 {
+		return fmt.Errorf("trust history interval must not be negative")
+	}
+	if c.Trust.MaterializeInterval < 0 {
+		return fmt.Errorf("trust materialize interval must not be negative")
+	}
+
+	if c.Access.GuestRequestsPerMinute < 0 || c.Access.MemberRequestsPerMinute < 0 ||
+		c.Access.KERIAProxyRequestsPerMinute < 0 {
+		return fmt.Errorf("access rate limits must not be negative")
+	}
+	if c.Access.ExpensiveConcurrency < 0 || c.Access.ShedStaleFor < 0 {
+		return fmt.Errorf("access expensive concurrency and shed staleness must not be negative")
+	}
+	for _, limit := range c.Access.RouteLimits {
+		if limit.Name == "" {
+			return fmt.Errorf("access route limits need a name")
+		}
+		if limit.PerIP < 0 || limit.PerAID < 0 || limit.Burst < 0 {
+			return fmt.Errorf("access route limit %s must not be negative", limit.Name)
+		}
+	}
+
+	switch c.AnySync.ChangeEncoding {
+	case "", "json", "compact":
+	default:
+		return fmt.Errorf("any-sync change encoding must be json or compact, got %q", c.AnySync.ChangeEncoding)
+	}
+	if c.AnySync.SpaceGCInterval < 0 {
+		return fmt.Errorf("any-sync space GC interval must not be negative")
+	}
+	if c.AnySync.ACLReconcileInterval < 0 {
+		return fmt.Errorf("any-sync ACL reconcile interval must not be negative")
+	}
+	if c.AnySync.InviteSweepInterval < 0 {
+		return fmt.Errorf("any-sync invite sweep interval must not be negative")
+	}
+
+	if c.Store.VacuumInterval < 0 || c.Store.TrustCacheRetention < 0 ||
+		c.Store.InboxRetention < 0 || c.Store.PresenceRetention < 0 ||
+		c.Store.TrustHistoryRetention < 0 {
+		return fmt.Errorf("store vacuum interval and retentions must not be negative")
+	}
+	if err := c.Store.Records.Validate(); err != nil {
+		return err
+	}
+	if err := c.Cluster.Validate(c.Store.Records); err != nil {
+		return err
+	}
+	if c.GRPC.Enabled() {
+		if err := c.GRPC.Listener().Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Auth.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Files.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Outbound.Validate(); err != nil {
+		return err
+	}
+
+	if c.Server.ConsistencyTimeout < 0 {
+		return fmt.Errorf("server consistency timeout must not be negative")
+	}
+	if c.Server.WatchTimeout < 0 {
+		return fmt.Errorf("server watch timeout must not be negative")
+	}
+
+	if lf := c.Server.LogFile; lf.MaxSizeMB < 0 || lf.MaxAge < 0 || lf.MaxBackups < 0 {
+		return fmt.Errorf("log file size, age and backup limits must not be negative")
+	}
+
+	for _, l := range c.Server.Listeners {
+		if l.Network == "" {
+			l.Network = "tcp"
+		}
+		if err := l.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the quota, upload limit and cache size, and that
+// renditions have distinct names and positive sizes
+func (f FilesConfig) Validate() error {
+	if f.UserQuotaMB < 0 {
+		return fmt.Errorf("file upload quota must not be negative")
+	}
+	if f.MaxUploadMB < 0 {
+		return fmt.Errorf("file upload size limit must not be negative")
+	}
+	if f.CacheMB < 0 {
+		return fmt.Errorf("file block cache size must not be negative")
+	}
+	seen := make(map[string]bool)
+	for _, r := range f.Renditions {
+		if r.Name == "" || strings.Trim(r.Name, "abcdefghijklmnopqrstuvwxyz0123456789-") != "" {
+			return fmt.Errorf("file rendition name %q must be lowercase letters, digits and dashes", r.Name)
+		}
+		if seen[r.Name] {
+			return fmt.Errorf("duplicate file rendition %q", r.Name)
+		}
+		seen[r.Name] = true
+		if r.Size <= 0 {
+			return fmt.Errorf("file rendition %q must have a positive size", r.Name)
+		}
+	}
+	return nil
+}
 
+// Validate checks API keys and route requirements
+func (a *AuthConfig) Validate() error {
+	if a.TokenMaxAge < 0 {
+		return fmt.Errorf("auth token max age must not be negative")
+	}
+	names := make(map[string]bool)
+	for _, k := range a.APIKeys {
+		if k.Name == "" {
+			return fmt.Errorf("auth API keys need a name")
+		}
+		if names[k.Name] {
+			return fmt.Errorf("auth API key %s: duplicate name", k.Name)
+		}
+		names[k.Name] = true
+		if len(k.Key) < minAPIKeyLength {
+			return fmt.Errorf("auth API key %s: key must be at least %d characters", k.Name, minAPIKeyLength)
+		}
+	}
+	for route, level := range a.Routes {
+		switch level {
+		case "public", "user", "admin":
+		default:
+			return fmt.Errorf("auth route %q: unknown level %q (expected public, user or admin)", route, level)
+		}
+	}
 	return nil
 }
 
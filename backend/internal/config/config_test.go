@@ -1,7 +1,10 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestConfigValidation(t *testing.T) {
@@ -29,3 +32,377 @@ func TestConfigValidation(t *testing.T) {
 		t.Errorf("Expected valid config, got error: %v", err)
 	}
 }
+
+func TestResolvedListeners_Default(t *testing.T) {
+	server := ServerConfig{Host: "::1", Port: 8080}
+	listeners := server.ResolvedListeners()
+	if len(listeners) != 1 {
+		t.Fatalf("expected 1 listener, got %d", len(listeners))
+	}
+	if listeners[0].Address != "[::1]:8080" {
+		t.Errorf("expected bracketed IPv6 address, got %s", listeners[0].Address)
+	}
+	if listeners[0].Network != "tcp" {
+		t.Errorf("expected tcp network, got %s", listeners[0].Network)
+	}
+}
+
+func TestParseListenList(t *testing.T) {
+	listeners := ParseListenList("127.0.0.1:8080, [::1]:8080,unix:/tmp/matou.sock")
+	if len(listeners) != 3 {
+		t.Fatalf("expected 3 listeners, got %d", len(listeners))
+	}
+	if listeners[1].Address != "[::1]:8080" || listeners[1].Network != "tcp" {
+		t.Errorf("unexpected IPv6 listener: %+v", listeners[1])
+	}
+	if listeners[2].Network != "unix" || listeners[2].Address != "/tmp/matou.sock" {
+		t.Errorf("unexpected unix listener: %+v", listeners[2])
+	}
+}
+
+func TestConfigValidation_Listeners(t *testing.T) {
+	cfg := &Config{
+		KERI: KERIConfig{AdminURL: "http://localhost:3901"},
+		Server: ServerConfig{
+			Listeners: []ListenerConfig{{Name: "bad", Network: "udp", Address: ":9000"}},
+		},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for udp listener")
+	}
+}
+
+func TestConfigValidation_AccessLimits(t *testing.T) {
+	cfg := &Config{
+		KERI:   KERIConfig{AdminURL: "http://localhost:3901"},
+		Access: AccessConfig{GuestRequestsPerMinute: -1},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for negative guest rate limit")
+	}
+}
+
+func TestConfigValidation_TrustWeights(t *testing.T) {
+	cfg := &Config{
+		KERI:  KERIConfig{AdminURL: "http://localhost:3901"},
+		Trust: TrustConfig{Weights: map[string]float64{"uniqueIssuer": 1.5}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	cfg.Trust.Weights["depthPenalty"] = -0.1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for negative trust weight")
+	}
+	cfg.Trust.Weights["depthPenalty"] = 0.1
+	cfg.Trust.HistoryInterval = -time.Hour
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for negative trust history interval")
+	}
+	cfg.Trust.HistoryInterval = 24 * time.Hour
+	cfg.Trust.MaterializeInterval = -time.Hour
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for negative trust materialize interval")
+	}
+	cfg.Trust.MaterializeInterval = time.Hour
+	cfg.Terms.ExpiryInterval = -time.Hour
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for negative credential expiry interval")
+	}
+}
+
+func TestConfigValidation_RouteLimits(t *testing.T) {
+	cfg := &Config{
+		KERI: KERIConfig{AdminURL: "http://localhost:3901"},
+		Access: AccessConfig{RouteLimits: []RouteLimitConfig{
+			{Name: "trust", Routes: []string{"/api/v1/trust/"}, PerIP: 10, PerAID: -1},
+		}},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a negative route budget")
+	}
+	cfg.Access.RouteLimits[0].PerAID = 5
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected valid config, got error: %v", err)
+	}
+	cfg.Access.RouteLimits[0].Name = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for an unnamed route budget")
+	}
+}
+
+func TestConfigValidation_GRPC(t *testing.T) {
+	cfg := &Config{
+		KERI: KERIConfig{AdminURL: "http://localhost:3901"},
+		GRPC: GRPCConfig{Network: "udp", Address: "127.0.0.1:9090"},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for an unsupported gRPC network")
+	}
+	cfg.GRPC.Network = ""
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected valid config, got error: %v", err)
+	}
+	if l := cfg.GRPC.Listener(); l.Network != "tcp" || l.Name != "grpc" {
+		t.Errorf("Expected a tcp listener named grpc, got %+v", l)
+	}
+}
+
+func TestConfigValidation_RequireSignatures(t *testing.T) {
+	cfg := &Config{
+		KERI: KERIConfig{AdminURL: "http://localhost:3901", RequireSignatures: true},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for signatures without a CESR URL")
+	}
+	cfg.KERI.CESRURL = "http://localhost:3902"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected valid config, got error: %v", err)
+	}
+}
+
+func TestConfigValidation_StoreRetention(t *testing.T) {
+	cfg := &Config{
+		KERI:  KERIConfig{AdminURL: "http://localhost:3901"},
+		Store: StoreConfig{InboxRetention: -time.Hour},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for negative inbox retention")
+	}
+}
+
+func TestConfigValidation_ChangeEncoding(t *testing.T) {
+	cfg := &Config{
+		KERI:    KERIConfig{AdminURL: "http://localhost:3901"},
+		AnySync: AnySyncConfig{ChangeEncoding: "cbor"},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for unknown change encoding")
+	}
+	cfg.AnySync.ChangeEncoding = "compact"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected compact encoding accepted, got error: %v", err)
+	}
+}
+
+func TestConfigValidation_Files(t *testing.T) {
+	cfg := &Config{
+		KERI:  KERIConfig{AdminURL: "http://localhost:3901"},
+		Files: FilesConfig{Renditions: []RenditionConfig{{Name: "thumb", Size: 128}, {Name: "thumb", Size: 256}}},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for duplicate rendition names")
+	}
+	cfg.Files.Renditions = []RenditionConfig{{Name: "Thumb/1", Size: 128}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a rendition name that isn't URL-safe")
+	}
+	cfg.Files.Renditions = []RenditionConfig{{Name: "thumb", Size: 128}}
+	cfg.Files.UserQuotaMB = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a negative quota")
+	}
+	cfg.Files.UserQuotaMB = 0
+	cfg.Files.MaxUploadMB = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a negative upload size limit")
+	}
+	cfg.Files.MaxUploadMB = 0
+	cfg.Files.CacheMB = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a negative block cache size")
+	}
+}
+
+func TestConfigValidation_LogFile(t *testing.T) {
+	cfg := &Config{
+		KERI: KERIConfig{AdminURL: "http://localhost:3901"},
+		Server: ServerConfig{
+			LogFile: LogFileConfig{Path: "logs/matou.log", MaxBackups: -1},
+		},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for negative log backups")
+	}
+}
+
+func TestConfigValidation_ConsistencyTimeout(t *testing.T) {
+	cfg := &Config{
+		KERI:   KERIConfig{AdminURL: "http://localhost:3901"},
+		Server: ServerConfig{ConsistencyTimeout: -time.Second},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a negative consistency timeout")
+	}
+}
+
+func TestConfigValidation_WatchTimeout(t *testing.T) {
+	cfg := &Config{
+		KERI:   KERIConfig{AdminURL: "http://localhost:3901"},
+		Server: ServerConfig{WatchTimeout: -time.Second},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a negative watch timeout")
+	}
+}
+
+func TestConfigValidation_SpaceGCInterval(t *testing.T) {
+	cfg := &Config{
+		KERI:    KERIConfig{AdminURL: "http://localhost:3901"},
+		AnySync: AnySyncConfig{SpaceGCInterval: -time.Hour},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a negative space GC interval")
+	}
+}
+
+func TestConfigValidation_ACLReconcileInterval(t *testing.T) {
+	cfg := &Config{
+		KERI:    KERIConfig{AdminURL: "http://localhost:3901"},
+		AnySync: AnySyncConfig{ACLReconcileInterval: -time.Minute},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a negative ACL reconcile interval")
+	}
+}
+
+func TestConfigValidation_InviteSweepInterval(t *testing.T) {
+	cfg := &Config{
+		KERI:    KERIConfig{AdminURL: "http://localhost:3901"},
+		AnySync: AnySyncConfig{InviteSweepInterval: -time.Minute},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a negative invite sweep interval")
+	}
+}
+
+func TestConfigValidation_Cluster(t *testing.T) {
+	shared := RecordsConfig{Driver: "postgres", DSN: "postgres://matou@db/matou"}
+	tests := []struct {
+		name    string
+		cluster ClusterConfig
+		records RecordsConfig
+		wantErr bool
+	}{
+		{"disabled", ClusterConfig{}, RecordsConfig{}, false},
+		{"enabled", ClusterConfig{Enabled: true, NodeURL: "http://10.0.0.5:8080", LeaseTTL: 15 * time.Second}, shared, false},
+		{"relative node URL", ClusterConfig{Enabled: true, NodeURL: "10.0.0.5:8080", LeaseTTL: 15 * time.Second}, shared, true},
+		{"short lease", ClusterConfig{Enabled: true, NodeURL: "http://10.0.0.5:8080", LeaseTTL: time.Second}, shared, true},
+		{"anystore records", ClusterConfig{Enabled: true, NodeURL: "http://10.0.0.5:8080", LeaseTTL: 15 * time.Second}, RecordsConfig{}, true},
+	}
+	for _, tt := range tests {
+		cfg := &Config{
+			KERI:    KERIConfig{AdminURL: "http://localhost:3901"},
+			Store:   StoreConfig{Records: tt.records},
+			Cluster: tt.cluster,
+		}
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestConfigValidation_Records(t *testing.T) {
+	tests := []struct {
+		name    string
+		records RecordsConfig
+		wantErr bool
+	}{
+		{"default", RecordsConfig{}, false},
+		{"anystore", RecordsConfig{Driver: "anystore"}, false},
+		{"sqlite", RecordsConfig{Driver: "sqlite", DSN: "/var/lib/matou/records.db"}, false},
+		{"postgres without dsn", RecordsConfig{Driver: "postgres"}, true},
+		{"unknown driver", RecordsConfig{Driver: "mysql", DSN: "db"}, true},
+	}
+	for _, tt := range tests {
+		cfg := &Config{
+			KERI:  KERIConfig{AdminURL: "http://localhost:3901"},
+			Store: StoreConfig{Records: tt.records},
+		}
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestConfigValidation_Auth(t *testing.T) {
+	tests := []struct {
+		name string
+		auth AuthConfig
+		ok   bool
+	}{
+		{"disabled", AuthConfig{}, true},
+		{"valid key", AuthConfig{Enabled: true, APIKeys: []APIKeyConfig{{Name: "ci", Key: "0123456789abcdef"}}}, true},
+		{"short key", AuthConfig{APIKeys: []APIKeyConfig{{Name: "ci", Key: "short"}}}, false},
+		{"unnamed key", AuthConfig{APIKeys: []APIKeyConfig{{Key: "0123456789abcdef"}}}, false},
+		{"duplicate name", AuthConfig{APIKeys: []APIKeyConfig{
+			{Name: "ci", Key: "0123456789abcdef"},
+			{Name: "ci", Key: "fedcba9876543210"},
+		}}, false},
+		{"valid route", AuthConfig{Routes: map[string]string{"GET /api/v1/trust/": "public"}}, true},
+		{"unknown level", AuthConfig{Routes: map[string]string{"/api/v1/trust/": "root"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{KERI: KERIConfig{AdminURL: "http://localhost:3901"}, Auth: tt.auth}
+			if err := cfg.Validate(); (err == nil) != tt.ok {
+				t.Errorf("expected ok=%v, got %v", tt.ok, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_WitnessThreshold(t *testing.T) {
+	witnesses := []WitnessConfig{{AID: "BWAN", URL: "http://witness:5642"}}
+	for threshold, ok := range map[int]bool{0: true, 1: true, 2: false, -1: false} {
+		cfg := &Config{KERI: KERIConfig{AdminURL: "http://localhost:3901", Witnesses: witnesses, WitnessThreshold: threshold}}
+		if err := cfg.Validate(); (err == nil) != ok {
+			t.Errorf("threshold %d: expected ok=%v, got %v", threshold, ok, err)
+		}
+	}
+}
+
+func TestConfigValidation_Outbound(t *testing.T) {
+	for proxy, ok := range map[string]bool{
+		"":                       true,
+		"http://proxy:3128":      true,
+		"socks5://proxy:1080":    true,
+		"proxy:3128":             false,
+		"ftp://proxy:21":         false,
+		"http://user@:3128/path": false,
+	} {
+		cfg := &Config{KERI: KERIConfig{AdminURL: "http://localhost:3901"}, Outbound: OutboundConfig{Proxy: proxy}}
+		if err := cfg.Validate(); (err == nil) != ok {
+			t.Errorf("proxy %q: expected ok=%v, got %v", proxy, ok, err)
+		}
+	}
+}
+
+func TestAtRestConfig_ResolvePassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "passphrase")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0600); err != nil {
+		t.Fatalf("writing passphrase file: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		atRest AtRestConfig
+		want   string
+		ok     bool
+	}{
+		{"off", AtRestConfig{}, "", true},
+		{"env", AtRestConfig{Passphrase: "from-env"}, "from-env", true},
+		{"file", AtRestConfig{PassphraseFile: path}, "from-file", true},
+		{"env wins", AtRestConfig{Passphrase: "from-env", PassphraseFile: path}, "from-env", true},
+		{"missing file", AtRestConfig{PassphraseFile: path + ".missing"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.atRest.ResolvePassphrase()
+			if (err == nil) != tt.ok || got != tt.want {
+				t.Errorf("expected %q (ok=%v), got %q, %v", tt.want, tt.ok, got, err)
+			}
+		})
+	}
+}
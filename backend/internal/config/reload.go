@@ -0,0 +1,208 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secrets in the effective config
+const redacted = "[REDACTED]"
+
+var dsnPassword = regexp.MustCompile(`(password=)\S+`)
+
+// Reloader holds the running server config and applies config.yaml when it
+// changes on disk. Only the smtp and logging sections and the trust weights
+// are applied; changes to the rest are reported as needing a restart.
+type Reloader struct {
+	path string
+	load func() (*Config, error)
+
+	mu              sync.RWMutex
+	current         *Config
+	loadedAt        time.Time
+	restartRequired []string
+	lastError       string
+	apply           []func(*Config)
+}
+
+// EffectiveConfig is the running config as served by the effective config
+// endpoint, with secrets redacted
+type EffectiveConfig struct {
+	Path     string    `json:"path"`
+	LoadedAt time.Time `json:"loadedAt"`
+	// RestartRequired lists sections changed on disk that the running
+	// server hasn't applied
+	RestartRequired []string `json:"restartRequired"`
+	// LastError is why the last reload was refused, cleared by a good one
+	LastError string                 `json:"lastError,omitempty"`
+	Settings  map[string]interface{} `json:"settings"`
+}
+
+// NewReloader creates a reloader for the config loaded from path. load
+// re-reads it the way the server did at startup, environment overrides
+// included.
+func NewReloader(path string, current *Config, load func() (*Config, error)) *Reloader {
+	running := *current
+	return &Reloader{
+		path:     path,
+		load:     load,
+		current:  &running,
+		loadedAt: time.Now(),
+	}
+}
+
+// OnReload registers a function called with the new config after a reload
+// changed a live section
+func (r *Reloader) OnReload(apply func(*Config)) {
+	r.mu.Lock()
+	r.apply = append(r.apply, apply)
+	r.mu.Unlock()
+}
+
+// Reload re-reads config.yaml. A missing, unparseable or invalid file is
+// refused and the running config kept.
+func (r *Reloader) Reload() error {
+	next, err := r.read()
+	if err != nil {
+		r.mu.Lock()
+		r.lastError = err.Error()
+		r.mu.Unlock()
+		fmt.Printf("[Config] Keeping running config: %v\n", err)
+		return err
+	}
+
+	r.mu.Lock()
+	changed := changedSections(r.current, next)
+	running := *r.current
+	var applied []string
+	for _, section := range changed {
+		switch section {
+		case "smtp":
+			running.SMTP = next.SMTP
+		case "logging":
+			running.Logging = next.Logging
+		case "trust":
+			if reflect.DeepEqual(running.Trust.Weights, next.Trust.Weights) {
+				continue
+			}
+			running.Trust.Weights = next.Trust.Weights
+		default:
+			continue
+		}
+		applied = append(applied, section)
+	}
+	r.restartRequired = changedSections(&running, next)
+	r.lastError = ""
+	r.loadedAt = time.Now()
+	if len(applied) > 0 {
+		r.current = &running
+	}
+	restart := r.restartRequired
+	apply := append([]func(*Config){}, r.apply...)
+	r.mu.Unlock()
+
+	if len(applied) > 0 {
+		for _, fn := range apply {
+			fn(&running)
+		}
+		fmt.Printf("[Config] Applied %s from %s\n", strings.Join(applied, ", "), r.path)
+	}
+	if len(restart) > 0 {
+		fmt.Printf("[Config] Restart required to apply %s\n", strings.Join(restart, ", "))
+	}
+	return nil
+}
+
+// read loads and validates config.yaml
+func (r *Reloader) read() (*Config, error) {
+	if _, err := os.Stat(r.path); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", r.path, err)
+	}
+	// Load falls back to the defaults on a bad file; check it parses first
+	if err := loadYAML(r.path, &Config{}); err != nil {
+		return nil, err
+	}
+	next, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	if err := next.Validate(); err != nil {
+		return nil, fmt.Errorf("validating %s: %w", r.path, err)
+	}
+	return next, nil
+}
+
+// Effective returns the running config with API keys, database passwords
+// and proxy credentials redacted. The bootstrap section is left out; the
+// org config is the source of truth for it.
+func (r *Reloader) Effective() *EffectiveConfig {
+	r.mu.RLock()
+	cfg := *r.current
+	eff := &EffectiveConfig{
+		Path:            r.path,
+		LoadedAt:        r.loadedAt,
+		RestartRequired: append([]string{}, r.restartRequired...),
+		LastError:       r.lastError,
+	}
+	r.mu.RUnlock()
+
+	cfg.Auth.APIKeys = append([]APIKeyConfig{}, cfg.Auth.APIKeys...)
+	for i := range cfg.Auth.APIKeys {
+		cfg.Auth.APIKeys[i].Key = redacted
+	}
+	cfg.Store.Records.DSN = redactURL(cfg.Store.Records.DSN)
+	cfg.Outbound.Proxy = redactURL(cfg.Outbound.Proxy)
+
+	eff.Settings = sections(&cfg)
+	delete(eff.Settings, "bootstrap")
+	return eff
+}
+
+// redactURL hides the password in a URL or key=value connection string
+func redactURL(s string) string {
+	if u, err := url.Parse(s); err == nil && u.User != nil {
+		return u.Redacted()
+	}
+	return dsnPassword.ReplaceAllString(s, "${1}"+redacted)
+}
+
+// sections returns cfg's top-level sections keyed by their YAML names
+func sections(cfg *Config) map[string]interface{} {
+	out := make(map[string]interface{})
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return out
+	}
+	yaml.Unmarshal(data, &out)
+	return out
+}
+
+// changedSections returns the names of the top-level sections that differ.
+// bootstrap is skipped: the running server fills it from the org config.
+func changedSections(a, b *Config) []string {
+	before, after := sections(a), sections(b)
+	delete(before, "bootstrap")
+	delete(after, "bootstrap")
+	var changed []string
+	for name, value := range after {
+		if !reflect.DeepEqual(before[name], value) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
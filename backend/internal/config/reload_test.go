@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func writeConfig(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloader_AppliesLiveSections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "smtp:\n  host: mail.one\nserver:\n  port: 8080\n")
+	load := func() (*Config, error) { return Load(path, "") }
+	cfg, _ := load()
+
+	r := NewReloader(path, cfg, load)
+	var applied *Config
+	r.OnReload(func(c *Config) { applied = c })
+
+	writeConfig(t, path, "smtp:\n  host: mail.two\nserver:\n  port: 9090\nlogging:\n  accessLog: true\ntrust:\n  historyInterval: 1h\n  weights:\n    uniqueIssuer: 1.5\n")
+	if err := r.Reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if applied == nil || applied.SMTP.Host != "mail.two" || !applied.Logging.AccessLog || applied.Trust.Weights["uniqueIssuer"] != 1.5 {
+		t.Fatalf("expected smtp, logging and trust weights to be applied, got %+v", applied)
+	}
+	if applied.Server.Port != 8080 || applied.Trust.HistoryInterval != 24*time.Hour {
+		t.Errorf("expected the running port and history interval to be kept, got %+v", applied)
+	}
+	eff := r.Effective()
+	if strings.Join(eff.RestartRequired, ",") != "server,trust" {
+		t.Errorf("expected server and trust to need a restart, got %v", eff.RestartRequired)
+	}
+
+	// A bad edit keeps the running config
+	applied = nil
+	writeConfig(t, path, "smtp: [\n")
+	if err := r.Reload(); err == nil {
+		t.Fatal("expected the unparseable file to be refused")
+	}
+	eff = r.Effective()
+	if applied != nil || eff.LastError == "" {
+		t.Errorf("expected the reload to be refused, got %+v", eff)
+	}
+	if smtp := eff.Settings["smtp"].(map[string]interface{}); smtp["host"] != "mail.two" {
+		t.Errorf("expected the running smtp host, got %v", smtp["host"])
+	}
+}
+
+func TestReloader_EffectiveRedactsSecrets(t *testing.T) {
+	cfg := &Config{}
+	cfg.Auth.APIKeys = []APIKeyConfig{{Name: "ops", Key: "sk-live"}}
+	cfg.Store.Records.DSN = "postgres://matou:hunter2@db/matou"
+	cfg.Outbound.Proxy = "http://user:pw@proxy:3128"
+	cfg.Bootstrap.Organization.Name = "Org"
+
+	eff := NewReloader("config.yaml", cfg, nil).Effective()
+	if _, ok := eff.Settings["bootstrap"]; ok {
+		t.Error("expected the bootstrap section to be left out")
+	}
+	data, _ := yaml.Marshal(eff.Settings)
+	for _, secret := range []string{"sk-live", "hunter2", ":pw@"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("effective config leaks %q", secret)
+		}
+	}
+	if cfg.Auth.APIKeys[0].Key != "sk-live" {
+		t.Error("redaction must not change the running config")
+	}
+	if got := redactURL("host=db password=hunter2 dbname=matou"); got != "host=db password=[REDACTED] dbname=matou" {
+		t.Errorf("unexpected key=value redaction %q", got)
+	}
+}
+
+func TestWatcher_DebouncesAndSeesReplacedFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeConfig(t, path, "a: 1\n")
+
+	w, err := NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.debounce = 50 * time.Millisecond
+	changes := make(chan struct{}, 10)
+	if err := w.Watch(path, func() { changes <- struct{}{} }); err != nil {
+		t.Fatal(err)
+	}
+	go w.Run()
+	defer w.Close()
+
+	// Several writes in a burst are one change
+	for i := 0; i < 3; i++ {
+		writeConfig(t, path, "a: 2\n")
+	}
+	expectChanges(t, changes, 1)
+
+	// Editors save by renaming a new file over the old one
+	tmp := filepath.Join(dir, ".config.yaml.swp")
+	writeConfig(t, tmp, "a: 3\n")
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	expectChanges(t, changes, 1)
+
+	// Other files in the directory are ignored
+	writeConfig(t, filepath.Join(dir, "other.yaml"), "b: 1\n")
+	expectChanges(t, changes, 0)
+}
+
+func expectChanges(t *testing.T, changes chan struct{}, want int) {
+	t.Helper()
+	got := 0
+	timeout := time.After(300 * time.Millisecond)
+	for {
+		select {
+		case <-changes:
+			got++
+		case <-timeout:
+			if got != want {
+				t.Errorf("expected %d changes, got %d", want, got)
+			}
+			return
+		}
+	}
+}
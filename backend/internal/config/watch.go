@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce collapses the burst of events an editor save produces
+// (truncate, write, chmod, or a rename over the file) into one reload
+const watchDebounce = 250 * time.Millisecond
+
+// Watcher calls a function when a watched file changes. It watches each
+// file's directory rather than the file, so files replaced by a rename, as
+// most editors and config management tools do, are still seen, and a file
+// that doesn't exist yet is picked up when it is created.
+type Watcher struct {
+	fs       *fsnotify.Watcher
+	debounce time.Duration
+
+	mu      sync.Mutex
+	files   map[string]func()
+	pending map[string]*time.Timer
+	closed  bool
+}
+
+// NewWatcher creates a watcher. Call Run to start delivering changes.
+func NewWatcher() (*Watcher, error) {
+	fs, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("creating file watcher: %w", err)
+	}
+	return &Watcher{
+		fs:       fs,
+		debounce: watchDebounce,
+		files:    make(map[string]func()),
+		pending:  make(map[string]*time.Timer),
+	}, nil
+}
+
+// Watch calls onChange after path is created, written, replaced or removed.
+// The file's directory must exist.
+func (w *Watcher) Watch(path string, onChange func()) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(abs)
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("watching %s: %w", path, err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	watched := false
+	for file := range w.files {
+		if filepath.Dir(file) == dir {
+			watched = true
+			break
+		}
+	}
+	if !watched {
+		if err := w.fs.Add(dir); err != nil {
+			return fmt.Errorf("watching %s: %w", dir, err)
+		}
+	}
+	w.files[abs] = onChange
+	return nil
+}
+
+// Run delivers changes until Close is called
+func (w *Watcher) Run() {
+	for {
+		select {
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			w.changed(filepath.Clean(event.Name))
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return
+			}
+			fmt.Printf("[Config] File watcher error: %v\n", err)
+		}
+	}
+}
+
+// changed schedules path's callback, restarting the debounce timer when a
+// change is already pending
+func (w *Watcher) changed(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	onChange, ok := w.files[path]
+	if !ok || w.closed {
+		return
+	}
+	if timer, ok := w.pending[path]; ok {
+		timer.Stop()
+	}
+	w.pending[path] = time.AfterFunc(w.debounce, func() {
+		w.mu.Lock()
+		delete(w.pending, path)
+		closed := w.closed
+		w.mu.Unlock()
+		if !closed {
+			onChange()
+		}
+	})
+}
+
+// Close stops watching. Pending changes are dropped.
+func (w *Watcher) Close() error {
+	w.mu.Lock()
+	w.closed = true
+	for path, timer := range w.pending {
+		timer.Stop()
+		delete(w.pending, path)
+	}
+	w.mu.Unlock()
+	return w.fs.Close()
+}
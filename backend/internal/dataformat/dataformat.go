@@ -0,0 +1,196 @@
+// Package dataformat records which format a data directory is in and checks
+// it against the formats this binary reads, so an upgrade or downgrade
+// never runs a server over data it would misread.
+//
+// The format is a single number in {dataDir}/data-format.json covering
+// everything in the directory, tenant directories included. A release that
+// changes how it lays out or encodes stored data raises Current and adds a
+// Migration from the previous format. The server refuses to start on any
+// other format; "matouctl migrate" applies the migrations.
+package dataformat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the format record in the data directory
+const FileName = "data-format.json"
+
+const (
+	// Current is the format this binary writes and serves
+	Current = 1
+	// Unversioned is the format of data directories from before formats
+	// were recorded
+	Unversioned = 1
+)
+
+// Migration upgrades a data directory from one format to the next
+type Migration struct {
+	// From is the format the migration reads; it leaves From+1
+	From        int
+	Description string
+	// Apply rewrites the directory. It is called with the server stopped
+	// and must be safe to run again if it fails part way.
+	Apply func(dataDir string) error
+}
+
+// migrations upgrade each format before Current to the next, in order
+var migrations []Migration
+
+// current is Current, swappable in tests
+var current = Current
+
+var (
+	// ErrNewerFormat is returned for data written by a newer release
+	ErrNewerFormat = errors.New("data directory is from a newer release")
+	// ErrNeedsMigration is returned for data an older release wrote that
+	// matouctl migrate can upgrade
+	ErrNeedsMigration = errors.New("data directory needs migrating")
+	// ErrUnsupportedFormat is returned for data too old to migrate
+	ErrUnsupportedFormat = errors.New("data directory format is no longer supported")
+)
+
+// Record is the contents of FileName
+type Record struct {
+	Format    int       `json:"format"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Read returns the data directory's format record, or nil when it has none
+func Read(dataDir string) (*Record, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, FileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rec Record
+	if err := json.Unmarshal(data, &rec); err != nil || rec.Format < 1 {
+		return nil, fmt.Errorf("%s is not a valid format record", FileName)
+	}
+	return &rec, nil
+}
+
+// write saves the format record, replacing it atomically
+func write(dataDir string, format int, now time.Time) error {
+	data, err := json.MarshalIndent(Record{Format: format, UpdatedAt: now.UTC()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dataDir, FileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// resolve returns the data directory's format. A directory without a
+// record is stamped: with Current when it is empty, since this binary is
+// about to fill it, and with Unversioned otherwise.
+func resolve(dataDir string, now time.Time) (int, error) {
+	rec, err := Read(dataDir)
+	if err != nil {
+		return 0, err
+	}
+	if rec != nil {
+		return rec.Format, nil
+	}
+	entries, err := os.ReadDir(dataDir)
+	if err != nil {
+		return 0, err
+	}
+	format := Unversioned
+	if len(entries) == 0 {
+		format = current
+	}
+	if err := write(dataDir, format, now); err != nil {
+		return 0, fmt.Errorf("recording data format: %w", err)
+	}
+	return format, nil
+}
+
+// pending returns the migrations from format to Current
+func pending(format int) ([]Migration, error) {
+	var steps []Migration
+	for f := format; f < current; f++ {
+		var step *Migration
+		for i := range migrations {
+			if migrations[i].From == f {
+				step = &migrations[i]
+				break
+			}
+		}
+		if step == nil {
+			return nil, fmt.Errorf("%w: format %d can't be migrated by this release; upgrade through an earlier release first", ErrUnsupportedFormat, format)
+		}
+		steps = append(steps, *step)
+	}
+	return steps, nil
+}
+
+// Check returns the data directory's format, or an error explaining why
+// this binary can't serve it: ErrNewerFormat, ErrNeedsMigration or
+// ErrUnsupportedFormat. Nothing is migrated.
+func Check(dataDir string) (int, error) {
+	format, err := resolve(dataDir, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case format > current:
+		return format, fmt.Errorf("%w: %s is in format %d and this release reads up to %d; run a release that supports it, or restore a snapshot taken before the upgrade", ErrNewerFormat, dataDir, format, current)
+	case format < current:
+		if _, err := pending(format); err != nil {
+			return format, err
+		}
+		return format, fmt.Errorf("%w: %s is in format %d and this release reads %d; stop the server, take a snapshot and run \"matouctl migrate\"", ErrNeedsMigration, dataDir, format, current)
+	}
+	return format, nil
+}
+
+// Plan returns the data directory's format and the migrations that would
+// bring it to Current
+func Plan(dataDir string) (int, []Migration, error) {
+	format, err := resolve(dataDir, time.Now())
+	if err != nil {
+		return 0, nil, err
+	}
+	if format > current {
+		return format, nil, fmt.Errorf("%w: %s is in format %d and this release reads up to %d", ErrNewerFormat, dataDir, format, current)
+	}
+	steps, err := pending(format)
+	return format, steps, err
+}
+
+// Migrate brings the data directory to Current, recording the format after
+// each step so a failed run resumes where it stopped. progress, when set,
+// is called before each step. It returns the migrations applied.
+func Migrate(dataDir string, progress func(Migration)) ([]Migration, error) {
+	_, steps, err := Plan(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	for i, step := range steps {
+		if progress != nil {
+			progress(step)
+		}
+		if err := step.Apply(dataDir); err != nil {
+			return steps[:i], fmt.Errorf("migrating format %d to %d: %w", step.From, step.From+1, err)
+		}
+		if err := write(dataDir, step.From+1, time.Now()); err != nil {
+			return steps[:i], fmt.Errorf("recording data format: %w", err)
+		}
+	}
+	return steps, nil
+}
@@ -0,0 +1,91 @@
+package dataformat
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// withFormats makes the binary read format to and migrate with steps
+func withFormats(t *testing.T, to int, steps []Migration) {
+	t.Helper()
+	oldCurrent, oldMigrations := current, migrations
+	current, migrations = to, steps
+	t.Cleanup(func() { current, migrations = oldCurrent, oldMigrations })
+}
+
+func TestCheck_StampsNewAndUnversionedData(t *testing.T) {
+	withFormats(t, 2, []Migration{{From: 1, Apply: func(string) error { return nil }}})
+
+	fresh := t.TempDir()
+	if format, err := Check(fresh); err != nil || format != 2 {
+		t.Fatalf("expected an empty directory to start at the current format, got %d %v", format, err)
+	}
+
+	legacy := t.TempDir()
+	os.WriteFile(filepath.Join(legacy, "matou.db"), []byte("pages"), 0600)
+	if format, err := Check(legacy); !errors.Is(err, ErrNeedsMigration) || format != Unversioned {
+		t.Fatalf("expected unversioned data to need migrating, got %d %v", format, err)
+	}
+	if rec, err := Read(legacy); err != nil || rec.Format != Unversioned {
+		t.Errorf("expected the unversioned format to be recorded, got %+v %v", rec, err)
+	}
+}
+
+func TestCheck_RefusesNewerAndUnsupportedData(t *testing.T) {
+	withFormats(t, 3, []Migration{{From: 2, Apply: func(string) error { return nil }}})
+
+	newer := t.TempDir()
+	if err := write(newer, 4, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Check(newer); !errors.Is(err, ErrNewerFormat) {
+		t.Errorf("expected ErrNewerFormat, got %v", err)
+	}
+	if _, err := Migrate(newer, nil); !errors.Is(err, ErrNewerFormat) {
+		t.Errorf("expected migrate to refuse newer data, got %v", err)
+	}
+
+	// Nothing migrates format 1 to 2
+	old := t.TempDir()
+	write(old, 1, time.Now())
+	if _, err := Check(old); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	var applied []int
+	fail := true
+	withFormats(t, 3, []Migration{
+		{From: 1, Apply: func(string) error { applied = append(applied, 1); return nil }},
+		{From: 2, Apply: func(string) error {
+			if fail {
+				return errors.New("disk full")
+			}
+			applied = append(applied, 2)
+			return nil
+		}},
+	})
+	dir := t.TempDir()
+	write(dir, 1, time.Now())
+
+	// A failed step keeps the steps before it
+	if done, err := Migrate(dir, nil); err == nil || len(done) != 1 {
+		t.Fatalf("expected the second step to fail after the first, got %d %v", len(done), err)
+	}
+	if rec, _ := Read(dir); rec.Format != 2 {
+		t.Fatalf("expected format 2 recorded after the first step, got %d", rec.Format)
+	}
+
+	fail = false
+	done, err := Migrate(dir, nil)
+	if err != nil || len(done) != 1 || len(applied) != 2 {
+		t.Fatalf("expected the rerun to apply only the second step, got %d %v %v", len(done), applied, err)
+	}
+	if format, err := Check(dir); err != nil || format != 3 {
+		t.Errorf("expected migrated data to pass the check, got %d %v", format, err)
+	}
+}
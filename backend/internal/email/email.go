@@ -1,12 +1,14 @@
 package email
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"html/template"
 	"net"
 	"net/smtp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/matou-dao/backend/internal/config"
@@ -22,17 +24,23 @@ type SendInviteRequest struct {
 
 // Sender handles sending emails via SMTP
 type Sender struct {
-	host       string
-	port       int
-	from       string
-	fromName   string
-	logoURL    template.URL
-	textURL    template.URL
+	mu   sync.RWMutex
+	smtp smtpSettings
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
 }
 
-// NewSender creates a new email Sender from SMTP config.
-func NewSender(cfg config.SMTPConfig) *Sender {
-	return &Sender{
+// smtpSettings are the parts of the SMTP config a Sender uses
+type smtpSettings struct {
+	host     string
+	port     int
+	from     string
+	fromName string
+	logoURL  template.URL
+	textURL  template.URL
+}
+
+func newSMTPSettings(cfg config.SMTPConfig) smtpSettings {
+	return smtpSettings{
 		host:     cfg.Host,
 		port:     cfg.Port,
 		from:     cfg.From,
@@ -42,14 +50,48 @@ func NewSender(cfg config.SMTPConfig) *Sender {
 	}
 }
 
+// NewSender creates a new email Sender from SMTP config.
+func NewSender(cfg config.SMTPConfig) *Sender {
+	return &Sender{
+		smtp: newSMTPSettings(cfg),
+		dial: (&net.Dialer{}).DialContext,
+	}
+}
+
+// SetConfig replaces the SMTP settings, e.g. when config.yaml is reloaded.
+// Emails already being sent finish with the old settings.
+func (s *Sender) SetConfig(cfg config.SMTPConfig) {
+	s.mu.Lock()
+	s.smtp = newSMTPSettings(cfg)
+	s.mu.Unlock()
+}
+
+// settings returns the current SMTP settings
+func (s *Sender) settings() smtpSettings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.smtp
+}
+
+func (st smtpSettings) addr() string {
+	return fmt.Sprintf("%s:%d", st.host, st.port)
+}
+
+// SetDialer sets how connections to the SMTP server are made, e.g. through
+// an outbound proxy
+func (s *Sender) SetDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error)) {
+	s.dial = dial
+}
+
 // SendInvite sends an invite code email to the specified recipient
 func (s *Sender) SendInvite(req SendInviteRequest) error {
+	st := s.settings()
 	body, err := renderInviteTemplate(inviteTemplateData{
 		InviterName: req.InviterName,
 		InviteeName: req.InviteeName,
 		InviteCode:  req.InviteCode,
-		LogoURL:     s.logoURL,
-		TextURL:     s.textURL,
+		LogoURL:     st.logoURL,
+		TextURL:     st.textURL,
 	})
 	if err != nil {
 		return fmt.Errorf("rendering email template: %w", err)
@@ -57,7 +99,43 @@ func (s *Sender) SendInvite(req SendInviteRequest) error {
 
 	msg := s.buildMIMEMessage(req.To, "Your MATOU invite code", body)
 
-	addr := fmt.Sprintf("%s:%d", s.host, s.port)
+	addr := st.addr()
+	if err := s.sendMail(addr, req.To, []byte(msg)); err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+
+	return nil
+}
+
+// SendInviteLinkRequest contains the data needed to email an invite link
+type SendInviteLinkRequest struct {
+	To          string
+	InviterName string
+	InviteeName string
+	InviteURL   string
+	ExpiresAt   time.Time
+	SingleUse   bool
+}
+
+// SendInviteLink sends an invite link email to the specified recipient
+func (s *Sender) SendInviteLink(req SendInviteLinkRequest) error {
+	st := s.settings()
+	body, err := renderInviteLinkTemplate(inviteLinkTemplateData{
+		InviterName: req.InviterName,
+		InviteeName: req.InviteeName,
+		InviteURL:   req.InviteURL,
+		Expires:     req.ExpiresAt.UTC().Format("2 January 2006, 15:04 UTC"),
+		SingleUse:   req.SingleUse,
+		LogoURL:     st.logoURL,
+		TextURL:     st.textURL,
+	})
+	if err != nil {
+		return fmt.Errorf("rendering email template: %w", err)
+	}
+
+	msg := s.buildMIMEMessage(req.To, "You're invited to join MATOU", body)
+
+	addr := st.addr()
 	if err := s.sendMail(addr, req.To, []byte(msg)); err != nil {
 		return fmt.Errorf("sending email: %w", err)
 	}
@@ -67,6 +145,7 @@ func (s *Sender) SendInvite(req SendInviteRequest) error {
 
 // SendBookingConfirmation sends a booking confirmation email with calendar invite
 func (s *Sender) SendBookingConfirmation(to, name string, startTime time.Time, dateTimeNZT, dateTimeLocal string) error {
+	st := s.settings()
 	// Generate ICS content
 	endTime := startTime.Add(30 * time.Minute) // 30-minute session
 	icsContent := s.generateICSWithFrom(startTime, endTime, name, "invites@matou.nz")
@@ -76,8 +155,8 @@ func (s *Sender) SendBookingConfirmation(to, name string, startTime time.Time, d
 		Name:          name,
 		DateTimeNZT:   dateTimeNZT,
 		DateTimeLocal: dateTimeLocal,
-		LogoURL:       s.logoURL,
-		TextURL:       s.textURL,
+		LogoURL:       st.logoURL,
+		TextURL:       st.textURL,
 	})
 	if err != nil {
 		return fmt.Errorf("rendering booking email template: %w", err)
@@ -87,7 +166,7 @@ func (s *Sender) SendBookingConfirmation(to, name string, startTime time.Time, d
 	toHeader := strings.Join(recipients, ", ")
 	msg := s.buildMIMEMessageWithCalendarFrom(toHeader, "MATOU - Whakawhānaunga Session", body, icsContent, "invites@matou.nz")
 
-	addr := fmt.Sprintf("%s:%d", s.host, s.port)
+	addr := st.addr()
 	if err := s.sendMailFromMulti(addr, "invites@matou.nz", recipients, []byte(msg)); err != nil {
 		return fmt.Errorf("sending email: %w", err)
 	}
@@ -97,7 +176,7 @@ func (s *Sender) SendBookingConfirmation(to, name string, startTime time.Time, d
 
 // generateICS creates an ICS calendar event
 func (s *Sender) generateICS(startTime, endTime time.Time, attendeeName string) string {
-	return s.generateICSWithFrom(startTime, endTime, attendeeName, s.from)
+	return s.generateICSWithFrom(startTime, endTime, attendeeName, s.settings().from)
 }
 
 // generateICSWithFrom creates an ICS calendar event with a specific organizer email
@@ -145,6 +224,7 @@ type SendRegistrationNotificationRequest struct {
 
 // SendRegistrationNotification sends a notification email to contact@matou.nz about a new registration
 func (s *Sender) SendRegistrationNotification(req SendRegistrationNotificationRequest) error {
+	st := s.settings()
 	body, err := renderRegistrationNotificationTemplate(registrationNotificationTemplateData{
 		ApplicantName:   req.ApplicantName,
 		ApplicantEmail:  req.ApplicantEmail,
@@ -155,8 +235,8 @@ func (s *Sender) SendRegistrationNotification(req SendRegistrationNotificationRe
 		Interests:       formatInterests(req.Interests),
 		CustomInterests: req.CustomInterests,
 		SubmittedAt:     req.SubmittedAt,
-		LogoURL:         s.logoURL,
-		TextURL:         s.textURL,
+		LogoURL:         st.logoURL,
+		TextURL:         st.textURL,
 	})
 	if err != nil {
 		return fmt.Errorf("rendering email template: %w", err)
@@ -165,7 +245,7 @@ func (s *Sender) SendRegistrationNotification(req SendRegistrationNotificationRe
 	subject := fmt.Sprintf("New Registration - %s", req.ApplicantName)
 	msg := s.buildMIMEMessage("contact@matou.nz", subject, body)
 
-	addr := fmt.Sprintf("%s:%d", s.host, s.port)
+	addr := st.addr()
 	if err := s.sendMail(addr, "contact@matou.nz", []byte(msg)); err != nil {
 		return fmt.Errorf("sending email: %w", err)
 	}
@@ -181,10 +261,11 @@ type SendApprovalNotificationRequest struct {
 
 // SendApprovalNotification sends an approval notification email to the applicant
 func (s *Sender) SendApprovalNotification(req SendApprovalNotificationRequest) error {
+	st := s.settings()
 	body, err := renderApprovalNotificationTemplate(approvalNotificationTemplateData{
 		ApplicantName: req.ApplicantName,
-		LogoURL:       s.logoURL,
-		TextURL:       s.textURL,
+		LogoURL:       st.logoURL,
+		TextURL:       st.textURL,
 	})
 	if err != nil {
 		return fmt.Errorf("rendering email template: %w", err)
@@ -192,7 +273,50 @@ func (s *Sender) SendApprovalNotification(req SendApprovalNotificationRequest) e
 
 	msg := s.buildMIMEMessage(req.To, "Welcome to MATOU!", body)
 
-	addr := fmt.Sprintf("%s:%d", s.host, s.port)
+	addr := st.addr()
+	if err := s.sendMail(addr, req.To, []byte(msg)); err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+
+	return nil
+}
+
+// DigestRequestItem is a pending endorsement request listed in a digest
+type DigestRequestItem struct {
+	RequesterName string
+	Category      string
+	Message       string
+}
+
+// SendDigestRequest contains the data needed to send a member's weekly digest
+type SendDigestRequest struct {
+	To                   string
+	Name                 string
+	Period               string // e.g. "9 Oct - 16 Oct", in the member's timezone
+	NewMembers           []string
+	EndorsementsReceived int
+	PendingRequests      []DigestRequestItem
+}
+
+// SendDigest sends a member's weekly activity digest
+func (s *Sender) SendDigest(req SendDigestRequest) error {
+	st := s.settings()
+	body, err := renderDigestTemplate(digestTemplateData{
+		Name:                 req.Name,
+		Period:               req.Period,
+		NewMembers:           req.NewMembers,
+		EndorsementsReceived: req.EndorsementsReceived,
+		PendingRequests:      req.PendingRequests,
+		LogoURL:              st.logoURL,
+		TextURL:              st.textURL,
+	})
+	if err != nil {
+		return fmt.Errorf("rendering email template: %w", err)
+	}
+
+	msg := s.buildMIMEMessage(req.To, "Your MATOU week - "+req.Period, body)
+
+	addr := st.addr()
 	if err := s.sendMail(addr, req.To, []byte(msg)); err != nil {
 		return fmt.Errorf("sending email: %w", err)
 	}
@@ -202,12 +326,13 @@ func (s *Sender) SendApprovalNotification(req SendApprovalNotificationRequest) e
 
 // sendMailFromMulti connects to the SMTP server and sends a single message to multiple recipients
 func (s *Sender) sendMailFromMulti(addr, from string, recipients []string, msg []byte) error {
-	conn, err := net.Dial("tcp", addr)
+	st := s.settings()
+	conn, err := s.dial(context.Background(), "tcp", addr)
 	if err != nil {
 		return fmt.Errorf("connecting to SMTP server: %w", err)
 	}
 
-	c, err := smtp.NewClient(conn, s.host)
+	c, err := smtp.NewClient(conn, st.host)
 	if err != nil {
 		conn.Close()
 		return fmt.Errorf("creating SMTP client: %w", err)
@@ -217,7 +342,7 @@ func (s *Sender) sendMailFromMulti(addr, from string, recipients []string, msg [
 	// STARTTLS with skip-verify for local relay's self-signed cert
 	if ok, _ := c.Extension("STARTTLS"); ok {
 		tlsConfig := &tls.Config{
-			ServerName:         s.host,
+			ServerName:         st.host,
 			InsecureSkipVerify: true,
 		}
 		if err := c.StartTLS(tlsConfig); err != nil {
@@ -250,12 +375,13 @@ func (s *Sender) sendMailFromMulti(addr, from string, recipients []string, msg [
 
 // sendMailFrom connects to the SMTP server and sends the message with a specific from address
 func (s *Sender) sendMailFrom(addr, from, to string, msg []byte) error {
-	conn, err := net.Dial("tcp", addr)
+	st := s.settings()
+	conn, err := s.dial(context.Background(), "tcp", addr)
 	if err != nil {
 		return fmt.Errorf("connecting to SMTP server: %w", err)
 	}
 
-	c, err := smtp.NewClient(conn, s.host)
+	c, err := smtp.NewClient(conn, st.host)
 	if err != nil {
 		conn.Close()
 		return fmt.Errorf("creating SMTP client: %w", err)
@@ -265,7 +391,7 @@ func (s *Sender) sendMailFrom(addr, from, to string, msg []byte) error {
 	// STARTTLS with skip-verify for local relay's self-signed cert
 	if ok, _ := c.Extension("STARTTLS"); ok {
 		tlsConfig := &tls.Config{
-			ServerName:         s.host,
+			ServerName:         st.host,
 			InsecureSkipVerify: true,
 		}
 		if err := c.StartTLS(tlsConfig); err != nil {
@@ -299,7 +425,7 @@ func (s *Sender) buildMIMEMessageWithCalendarFrom(to, subject, htmlBody, icsCont
 	var b strings.Builder
 	boundary := "----=_Part_0_Calendar"
 
-	fromHeader := fmt.Sprintf("%s <%s>", s.fromName, fromEmail)
+	fromHeader := fmt.Sprintf("%s <%s>", s.settings().fromName, fromEmail)
 
 	b.WriteString(fmt.Sprintf("From: %s\r\n", fromHeader))
 	b.WriteString(fmt.Sprintf("To: %s\r\n", to))
@@ -331,16 +457,51 @@ func (s *Sender) buildMIMEMessageWithCalendarFrom(to, subject, htmlBody, icsCont
 	return b.String()
 }
 
+// Ping connects to the SMTP server, negotiates STARTTLS when offered and
+// quits without sending, to check the server is reachable
+func (s *Sender) Ping(ctx context.Context) error {
+	st := s.settings()
+	addr := st.addr()
+	conn, err := s.dial(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("connecting to SMTP server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, st.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("creating SMTP client: %w", err)
+	}
+	defer c.Close()
+
+	// STARTTLS with skip-verify for local relay's self-signed cert
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		tlsConfig := &tls.Config{
+			ServerName:         st.host,
+			InsecureSkipVerify: true,
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS: %w", err)
+		}
+	}
+
+	return c.Quit()
+}
+
 // sendMail connects to the SMTP server and sends the message.
 // Uses STARTTLS with InsecureSkipVerify for local relay containers
 // that present self-signed certificates.
 func (s *Sender) sendMail(addr, to string, msg []byte) error {
-	conn, err := net.Dial("tcp", addr)
+	st := s.settings()
+	conn, err := s.dial(context.Background(), "tcp", addr)
 	if err != nil {
 		return fmt.Errorf("connecting to SMTP server: %w", err)
 	}
 
-	c, err := smtp.NewClient(conn, s.host)
+	c, err := smtp.NewClient(conn, st.host)
 	if err != nil {
 		conn.Close()
 		return fmt.Errorf("creating SMTP client: %w", err)
@@ -350,7 +511,7 @@ func (s *Sender) sendMail(addr, to string, msg []byte) error {
 	// STARTTLS with skip-verify for local relay's self-signed cert
 	if ok, _ := c.Extension("STARTTLS"); ok {
 		tlsConfig := &tls.Config{
-			ServerName:         s.host,
+			ServerName:         st.host,
 			InsecureSkipVerify: true,
 		}
 		if err := c.StartTLS(tlsConfig); err != nil {
@@ -358,7 +519,7 @@ func (s *Sender) sendMail(addr, to string, msg []byte) error {
 		}
 	}
 
-	if err := c.Mail(s.from); err != nil {
+	if err := c.Mail(st.from); err != nil {
 		return fmt.Errorf("MAIL FROM: %w", err)
 	}
 	if err := c.Rcpt(to); err != nil {
@@ -381,9 +542,10 @@ func (s *Sender) sendMail(addr, to string, msg []byte) error {
 
 // buildMIMEMessage constructs a MIME email message with HTML content
 func (s *Sender) buildMIMEMessage(to, subject, htmlBody string) string {
+	st := s.settings()
 	var b strings.Builder
 
-	fromHeader := fmt.Sprintf("%s <%s>", s.fromName, s.from)
+	fromHeader := fmt.Sprintf("%s <%s>", st.fromName, st.from)
 
 	b.WriteString(fmt.Sprintf("From: %s\r\n", fromHeader))
 	b.WriteString(fmt.Sprintf("To: %s\r\n", to))
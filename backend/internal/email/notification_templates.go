@@ -243,3 +243,107 @@ func renderApprovalNotificationTemplate(data approvalNotificationTemplateData) (
 	}
 	return buf.String(), nil
 }
+
+// Weekly digest template (sent to a member in their timezone)
+
+type digestTemplateData struct {
+	Name                 string
+	Period               string
+	NewMembers           []string
+	EndorsementsReceived int
+	PendingRequests      []DigestRequestItem
+	LogoURL              template.URL
+	TextURL              template.URL
+}
+
+const digestHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+</head>
+<body style="margin:0; padding:0; background-color:#f4f4f5; font-family:Arial, Helvetica, sans-serif;">
+  <table role="presentation" width="100%" cellspacing="0" cellpadding="0" border="0" style="background-color:#f4f4f5;">
+    <tr>
+      <td align="center" style="padding:40px 20px;">
+        <table role="presentation" width="480" cellspacing="0" cellpadding="0" border="0" style="background-color:#ffffff; border-radius:12px; overflow:hidden;">
+          <!-- Header -->
+          <tr>
+            <td style="background-color:#1e5f74; padding:24px 32px; text-align:center;">
+              <table role="presentation" cellspacing="0" cellpadding="0" border="0" align="center">
+                <tr>
+                  <td style="vertical-align:middle; padding-right:12px;">
+                    <img src="{{.LogoURL}}" alt="" width="80" height="40" style="display:block; border:0;" />
+                  </td>
+                  <td style="vertical-align:middle;">
+                    <img src="{{.TextURL}}" alt="MATOU" width="140" height="40" style="display:block; border:0;" />
+                  </td>
+                </tr>
+              </table>
+            </td>
+          </tr>
+          <!-- Body -->
+          <tr>
+            <td style="padding:32px;">
+              <p style="margin:0 0 20px; color:#1a1a1a; font-size:16px; line-height:1.5;">
+                Kia ora{{if .Name}} <strong>{{.Name}}</strong>{{end}},
+              </p>
+              <p style="margin:0 0 24px; color:#374151; font-size:15px; line-height:1.6;">
+                Here is what happened in the MATOU community from {{.Period}}.
+              </p>
+              <!-- Activity -->
+              <table role="presentation" width="100%" cellspacing="0" cellpadding="0" border="0">
+                <tr>
+                  <td style="background-color:#f0f9fa; border:1px solid #d1e7ea; border-radius:8px; padding:20px;">
+                    <p style="margin:0 0 12px; color:#6b7280; font-size:12px; text-transform:uppercase; letter-spacing:1px;">This Week</p>
+                    <p style="margin:0 0 8px; color:#1a1a1a; font-size:15px; line-height:1.5;">
+                      <strong>New members:</strong> {{len .NewMembers}}{{if .NewMembers}} &mdash; {{range $i, $m := .NewMembers}}{{if $i}}, {{end}}{{$m}}{{end}}{{end}}
+                    </p>
+                    <p style="margin:0; color:#1a1a1a; font-size:15px; line-height:1.5;">
+                      <strong>Endorsements you received:</strong> {{.EndorsementsReceived}}
+                    </p>
+                  </td>
+                </tr>
+              </table>
+              {{if .PendingRequests}}<!-- Pending Requests -->
+              <table role="presentation" width="100%" cellspacing="0" cellpadding="0" border="0" style="margin-top:16px;">
+                <tr>
+                  <td style="background-color:#f0f9fa; border:1px solid #d1e7ea; border-radius:8px; padding:20px;">
+                    <p style="margin:0 0 12px; color:#6b7280; font-size:12px; text-transform:uppercase; letter-spacing:1px;">Waiting for your endorsement</p>
+                    <table role="presentation" width="100%" cellspacing="0" cellpadding="0" border="0">
+                      {{range .PendingRequests}}<tr>
+                        <td style="padding:6px 0; color:#374151; font-size:14px; line-height:1.5;">
+                          <strong style="color:#1e5f74;">{{.RequesterName}}</strong> &mdash; {{.Category}}{{if .Message}}<br /><span style="color:#6b7280;">{{.Message}}</span>{{end}}
+                        </td>
+                      </tr>{{end}}
+                    </table>
+                  </td>
+                </tr>
+              </table>{{end}}
+              <p style="margin:24px 0 0; color:#374151; font-size:14px; line-height:1.6;">
+                Open the MATOU app to catch up. You can change when this digest arrives, or turn it off, in your preferences.
+              </p>
+            </td>
+          </tr>
+          <!-- Footer -->
+          <tr>
+            <td style="background-color:#f9fafb; padding:20px 32px; border-top:1px solid #e5e7eb; text-align:center;">
+              <p style="margin:0; color:#9ca3af; font-size:12px;">MATOU &mdash; Connection &vert; Collaboration &vert; Innovation</p>
+            </td>
+          </tr>
+        </table>
+      </td>
+    </tr>
+  </table>
+</body>
+</html>`
+
+var digestTemplate = template.Must(template.New("digest").Parse(digestHTML))
+
+func renderDigestTemplate(data digestTemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := digestTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
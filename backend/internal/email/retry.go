@@ -0,0 +1,64 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"net/textproto"
+	"time"
+)
+
+// RetryPolicy controls how often a failed send is retried. The delay
+// doubles after each attempt, up to MaxDelay.
+type RetryPolicy struct {
+	Attempts     int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+}
+
+// DefaultRetryPolicy tries a send five times over about a minute and a half
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:     5,
+	InitialDelay: 5 * time.Second,
+	MaxDelay:     time.Minute,
+}
+
+// Permanent reports whether err is an SMTP rejection that retrying won't
+// fix, such as an unknown recipient (5xx reply codes)
+func Permanent(err error) bool {
+	var tpErr *textproto.Error
+	return errors.As(err, &tpErr) && tpErr.Code >= 500
+}
+
+// Retry calls send until it succeeds, fails permanently, the attempts run
+// out or ctx is done. onAttempt, if set, is called with the attempt number
+// and its error after each attempt. It returns the last error.
+func Retry(ctx context.Context, policy RetryPolicy, send func() error, onAttempt func(attempt int, err error)) error {
+	attempts := policy.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := policy.InitialDelay
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = send()
+		if onAttempt != nil {
+			onAttempt(attempt, err)
+		}
+		if err == nil || Permanent(err) || attempt == attempts {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		if delay *= 2; policy.MaxDelay > 0 && delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/textproto"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{Attempts: 3, InitialDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+	transient := errors.New("connection refused")
+
+	tests := []struct {
+		name         string
+		failures     int
+		err          error
+		wantAttempts int
+		wantErr      bool
+	}{
+		{"first try", 0, transient, 1, false},
+		{"recovers", 2, transient, 3, false},
+		{"gives up", 5, transient, 3, true},
+		{"permanent", 5, fmt.Errorf("RCPT TO: %w", &textproto.Error{Code: 550, Msg: "no such user"}), 1, true},
+	}
+	for _, tt := range tests {
+		calls, reported := 0, 0
+		err := Retry(context.Background(), policy, func() error {
+			calls++
+			if calls <= tt.failures {
+				return tt.err
+			}
+			return nil
+		}, func(attempt int, err error) {
+			reported = attempt
+		})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+		if calls != tt.wantAttempts || reported != tt.wantAttempts {
+			t.Errorf("%s: expected %d attempts, got %d (reported %d)", tt.name, tt.wantAttempts, calls, reported)
+		}
+	}
+}
+
+func TestRetry_ContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := Retry(ctx, RetryPolicy{Attempts: 5, InitialDelay: time.Hour}, func() error {
+		calls++
+		return errors.New("timeout")
+	}, nil)
+	if err == nil || calls != 1 {
+		t.Errorf("expected one failed attempt, got %d, %v", calls, err)
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	if !Permanent(fmt.Errorf("sending email: %w", &textproto.Error{Code: 554, Msg: "rejected"})) {
+		t.Error("expected a 5xx reply to be permanent")
+	}
+	if Permanent(&textproto.Error{Code: 451, Msg: "try again later"}) {
+		t.Error("expected a 4xx reply to be transient")
+	}
+	if Permanent(errors.New("connection reset")) {
+		t.Error("expected a network error to be transient")
+	}
+}
@@ -0,0 +1,30 @@
+package email
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderInviteLinkTemplate(t *testing.T) {
+	body, err := renderInviteLinkTemplate(inviteLinkTemplateData{
+		InviterName: "Aroha",
+		InviteeName: "Tama",
+		InviteURL:   "https://app.matou.nz/join?invite=abc&ref=email",
+		Expires:     "23 October 2026, 00:00 UTC",
+		SingleUse:   true,
+		LogoURL:     "https://matou.nz/logo.png",
+	})
+	if err != nil {
+		t.Fatalf("renderInviteLinkTemplate failed: %v", err)
+	}
+	for _, want := range []string{
+		`href="https://app.matou.nz/join?invite=abc&amp;ref=email"`,
+		`src="https://matou.nz/logo.png"`,
+		"can only be used once",
+		"23 October 2026",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected body to contain %q", want)
+		}
+	}
+}
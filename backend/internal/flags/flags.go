@@ -0,0 +1,206 @@
+// Package flags provides per-deployment feature flags for experimental
+// subsystems. Defaults come from config; runtime overrides set through the
+// admin API are persisted to feature-flags.yaml in the data directory.
+package flags
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Known feature flags
+const (
+	// Governance enables proposals, voting, and other governance endpoints
+	Governance = "governance"
+	// Messaging enables member-to-member messaging
+	Messaging = "messaging"
+	// TrustV2 enables the experimental trust scoring algorithm
+	TrustV2 = "trust_v2"
+)
+
+// Definition describes a known feature flag
+type Definition struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Definitions lists every flag the backend understands, in display order.
+var Definitions = []Definition{
+	{Name: Governance, Description: "Governance proposals and voting"},
+	{Name: Messaging, Description: "Member-to-member messaging"},
+	{Name: TrustV2, Description: "Experimental trust scoring algorithm"},
+}
+
+// IsKnown returns true if name is a defined feature flag
+func IsKnown(name string) bool {
+	for _, def := range Definitions {
+		if def.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Flag is the resolved state of a single feature flag
+type Flag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	Overridden  bool   `json:"overridden"`
+}
+
+// Flags holds feature flag state for the running backend.
+// All methods are safe for concurrent use. A nil *Flags reports every
+// flag as disabled so handlers can check flags without a nil guard.
+type Flags struct {
+	mu        sync.RWMutex
+	defaults  map[string]bool
+	overrides map[string]bool
+	path      string
+}
+
+// New creates a Flags set with the given defaults. Unknown names in defaults
+// are ignored with a warning. If dataDir is non-empty, runtime overrides are
+// loaded from and persisted to dataDir/feature-flags.yaml.
+func New(defaults map[string]bool, dataDir string) *Flags {
+	f := &Flags{
+		defaults:  make(map[string]bool),
+		overrides: make(map[string]bool),
+	}
+	for name, enabled := range defaults {
+		if !IsKnown(name) {
+			fmt.Printf("[Flags] Ignoring unknown feature flag %q\n", name)
+			continue
+		}
+		f.defaults[name] = enabled
+	}
+	if dataDir != "" {
+		f.path = filepath.Join(dataDir, "feature-flags.yaml")
+		f.loadFromDisk()
+	}
+	return f
+}
+
+// loadFromDisk reads persisted overrides, if any
+func (f *Flags) loadFromDisk() {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return
+	}
+
+	var overrides map[string]bool
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		fmt.Printf("[Flags] Failed to parse %s: %v\n", f.path, err)
+		return
+	}
+	for name, enabled := range overrides {
+		if IsKnown(name) {
+			f.overrides[name] = enabled
+		}
+	}
+}
+
+// saveToDisk writes the current overrides. Caller must hold f.mu.
+func (f *Flags) saveToDisk() error {
+	if f.path == "" {
+		return nil
+	}
+	data, err := yaml.Marshal(f.overrides)
+	if err != nil {
+		return fmt.Errorf("marshaling feature flags: %w", err)
+	}
+	if err := os.WriteFile(f.path, data, 0644); err != nil {
+		return fmt.Errorf("writing feature flags: %w", err)
+	}
+	return nil
+}
+
+// Enabled reports whether the named flag is on
+func (f *Flags) Enabled(name string) bool {
+	if f == nil {
+		return false
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if enabled, ok := f.overrides[name]; ok {
+		return enabled
+	}
+	return f.defaults[name]
+}
+
+// Set records a runtime override for the named flag and persists it
+func (f *Flags) Set(name string, enabled bool) error {
+	if !IsKnown(name) {
+		return fmt.Errorf("unknown feature flag: %s", name)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.overrides[name] = enabled
+	return f.saveToDisk()
+}
+
+// Reset removes a runtime override so the flag falls back to its default
+func (f *Flags) Reset(name string) error {
+	if !IsKnown(name) {
+		return fmt.Errorf("unknown feature flag: %s", name)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.overrides, name)
+	return f.saveToDisk()
+}
+
+// All returns the resolved state of every known flag
+func (f *Flags) All() []Flag {
+	result := make([]Flag, 0, len(Definitions))
+	for _, def := range Definitions {
+		flag := Flag{Name: def.Name, Description: def.Description}
+		if f != nil {
+			f.mu.RLock()
+			_, flag.Overridden = f.overrides[def.Name]
+			f.mu.RUnlock()
+		}
+		flag.Enabled = f.Enabled(def.Name)
+		result = append(result, flag)
+	}
+	return result
+}
+
+// EnabledNames returns the sorted names of all enabled flags
+func (f *Flags) EnabledNames() []string {
+	names := make([]string, 0)
+	for _, def := range Definitions {
+		if f.Enabled(def.Name) {
+			names = append(names, def.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseList parses a comma-separated flag list such as "governance,-messaging"
+// into a defaults map. A leading "-" disables the flag.
+func ParseList(list string) map[string]bool {
+	result := make(map[string]bool)
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if strings.HasPrefix(item, "-") {
+			result[strings.TrimPrefix(item, "-")] = false
+		} else {
+			result[item] = true
+		}
+	}
+	return result
+}
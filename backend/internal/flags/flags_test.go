@@ -0,0 +1,74 @@
+package flags
+
+import (
+	"os"
+	"testing"
+)
+
+func TestFlags_Defaults(t *testing.T) {
+	f := New(map[string]bool{Governance: true, "bogus": true}, "")
+
+	if !f.Enabled(Governance) {
+		t.Error("expected governance enabled from defaults")
+	}
+	if f.Enabled(Messaging) {
+		t.Error("expected messaging disabled by default")
+	}
+	if f.Enabled("bogus") {
+		t.Error("unknown flags should never be enabled")
+	}
+}
+
+func TestFlags_NilIsDisabled(t *testing.T) {
+	var f *Flags
+	if f.Enabled(Governance) {
+		t.Error("nil flags should report disabled")
+	}
+	if len(f.All()) != len(Definitions) {
+		t.Errorf("expected %d flags, got %d", len(Definitions), len(f.All()))
+	}
+}
+
+func TestFlags_OverridesPersist(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "flags_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	f := New(map[string]bool{Messaging: true}, tmpDir)
+	if err := f.Set(Governance, true); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := f.Set(Messaging, false); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := f.Set("bogus", true); err == nil {
+		t.Error("expected error setting unknown flag")
+	}
+
+	reloaded := New(map[string]bool{Messaging: true}, tmpDir)
+	if !reloaded.Enabled(Governance) {
+		t.Error("expected governance override to persist")
+	}
+	if reloaded.Enabled(Messaging) {
+		t.Error("expected messaging override to win over default")
+	}
+
+	if err := reloaded.Reset(Messaging); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if !reloaded.Enabled(Messaging) {
+		t.Error("expected messaging to revert to default after reset")
+	}
+}
+
+func TestParseList(t *testing.T) {
+	got := ParseList(" governance, -messaging,,trust_v2 ")
+	if len(got) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(got))
+	}
+	if !got[Governance] || got[Messaging] || !got[TrustV2] {
+		t.Errorf("unexpected parse result: %v", got)
+	}
+}
@@ -4,20 +4,29 @@ package identity
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
+
+	"github.com/matou-dao/backend/internal/secret"
 )
 
 // UserIdentity holds the local user's AID and mnemonic with thread-safe access.
-// It persists to {dataDir}/identity.json so it survives restarts.
+// It persists to {dataDir}/identity.json so it survives restarts. With a
+// Sealer the file is encrypted at rest.
 type UserIdentity struct {
 	mu       sync.RWMutex
 	aid      string
-	mnemonic string
+	mnemonic *secret.Mnemonic
 	peerID   string
 	dataDir  string
+	sealer   *secret.Sealer
+
+	// locked is set when identity.json is encrypted but can't be opened.
+	// Writes are refused so the file isn't overwritten.
+	locked error
 
 	// Runtime config fields (set by frontend after fetching org config)
 	orgAID                   string
@@ -25,6 +34,7 @@ type UserIdentity struct {
 	communityReadOnlySpaceID string
 	adminSpaceID             string
 	privateSpaceID           string
+	inboxSpaceID             string
 }
 
 // persistedIdentity is the JSON structure written to disk.
@@ -37,23 +47,32 @@ type persistedIdentity struct {
 	CommunityReadOnlySpaceID string `json:"communityReadOnlySpaceId,omitempty"`
 	AdminSpaceID             string `json:"adminSpaceId,omitempty"`
 	PrivateSpaceID           string `json:"privateSpaceId,omitempty"`
+	InboxSpaceID             string `json:"inboxSpaceId,omitempty"`
 }
 
 // New creates a new UserIdentity bound to the given data directory.
 // If an identity file exists on disk, it is loaded automatically.
 func New(dataDir string) *UserIdentity {
-	ui := &UserIdentity{dataDir: dataDir}
+	return NewSealed(dataDir, nil)
+}
+
+// NewSealed is New with identity.json encrypted by sealer. A plaintext file
+// from before encryption was turned on is loaded and rewritten encrypted.
+func NewSealed(dataDir string, sealer *secret.Sealer) *UserIdentity {
+	ui := &UserIdentity{dataDir: dataDir, sealer: sealer}
 	ui.load()
 	return ui
 }
 
 // SetIdentity sets the user's AID and mnemonic and persists to disk.
-func (u *UserIdentity) SetIdentity(aid, mnemonic string) error {
+// The mnemonic is copied, so the caller remains free to zero its own value.
+func (u *UserIdentity) SetIdentity(aid string, mnemonic *secret.Mnemonic) error {
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
 	u.aid = aid
-	u.mnemonic = mnemonic
+	u.mnemonic.Zero()
+	u.mnemonic = mnemonic.Clone()
 	return u.persist()
 }
 
@@ -92,11 +111,12 @@ func (u *UserIdentity) GetAID() string {
 	return u.aid
 }
 
-// GetMnemonic returns the stored mnemonic (empty if not configured).
-func (u *UserIdentity) GetMnemonic() string {
+// GetMnemonic returns a copy of the stored mnemonic (nil if not configured).
+// Callers should Zero the copy once key derivation is done.
+func (u *UserIdentity) GetMnemonic() *secret.Mnemonic {
 	u.mu.RLock()
 	defer u.mu.RUnlock()
-	return u.mnemonic
+	return u.mnemonic.Clone()
 }
 
 // GetPeerID returns the stored peer ID.
@@ -159,11 +179,36 @@ func (u *UserIdentity) GetPrivateSpaceID() string {
 	return u.privateSpaceID
 }
 
+// GetInboxSpaceID returns the user's inbox space ID.
+func (u *UserIdentity) GetInboxSpaceID() string {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.inboxSpaceID
+}
+
+// SetInboxSpaceID stores the user's inbox space ID.
+func (u *UserIdentity) SetInboxSpaceID(spaceID string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.inboxSpaceID = spaceID
+	return u.persist()
+}
+
 // IsConfigured returns true if an AID and mnemonic have been set.
 func (u *UserIdentity) IsConfigured() bool {
 	u.mu.RLock()
 	defer u.mu.RUnlock()
-	return u.aid != "" && u.mnemonic != ""
+	return u.aid != "" && !u.mnemonic.IsEmpty()
+}
+
+// Locked returns why identity.json couldn't be opened, or nil. A locked
+// identity reads as unconfigured until the right passphrase is supplied or
+// the identity is cleared.
+func (u *UserIdentity) Locked() error {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.locked
 }
 
 // Clear removes the identity and deletes the persisted file.
@@ -171,14 +216,18 @@ func (u *UserIdentity) Clear() error {
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
+	u.locked = nil
+
 	u.aid = ""
-	u.mnemonic = ""
+	u.mnemonic.Zero()
+	u.mnemonic = nil
 	u.peerID = ""
 	u.orgAID = ""
 	u.communitySpaceID = ""
 	u.communityReadOnlySpaceID = ""
 	u.adminSpaceID = ""
 	u.privateSpaceID = ""
+	u.inboxSpaceID = ""
 
 	path := u.filePath()
 	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
@@ -187,6 +236,26 @@ func (u *UserIdentity) Clear() error {
 	return nil
 }
 
+// Reload discards the in-memory identity and reads identity.json again,
+// e.g. after a backup was restored over it.
+func (u *UserIdentity) Reload() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.locked = nil
+	u.aid = ""
+	u.mnemonic.Zero()
+	u.mnemonic = nil
+	u.peerID = ""
+	u.orgAID = ""
+	u.communitySpaceID = ""
+	u.communityReadOnlySpaceID = ""
+	u.adminSpaceID = ""
+	u.privateSpaceID = ""
+	u.inboxSpaceID = ""
+	u.load()
+}
+
 // filePath returns the path to the identity JSON file.
 func (u *UserIdentity) filePath() string {
 	return filepath.Join(u.dataDir, "identity.json")
@@ -194,21 +263,29 @@ func (u *UserIdentity) filePath() string {
 
 // persist writes the current state to disk. Caller must hold u.mu.
 func (u *UserIdentity) persist() error {
+	if u.locked != nil {
+		return fmt.Errorf("identity file is locked: %w", u.locked)
+	}
+
 	data := persistedIdentity{
 		AID:                      u.aid,
-		Mnemonic:                 u.mnemonic,
+		Mnemonic:                 u.mnemonic.Reveal(),
 		PeerID:                   u.peerID,
 		OrgAID:                   u.orgAID,
 		CommunitySpaceID:         u.communitySpaceID,
 		CommunityReadOnlySpaceID: u.communityReadOnlySpaceID,
 		AdminSpaceID:             u.adminSpaceID,
 		PrivateSpaceID:           u.privateSpaceID,
+		InboxSpaceID:             u.inboxSpaceID,
 	}
 
 	bytes, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshaling identity: %w", err)
 	}
+	if bytes, err = u.sealer.Seal(bytes); err != nil {
+		return fmt.Errorf("encrypting identity: %w", err)
+	}
 
 	if err := os.MkdirAll(u.dataDir, 0755); err != nil {
 		return fmt.Errorf("creating data directory: %w", err)
@@ -229,6 +306,16 @@ func (u *UserIdentity) load() {
 		return // File doesn't exist yet — normal for first boot
 	}
 
+	sealed := secret.IsSealed(bytes)
+	if bytes, err = u.sealer.Open(bytes); err != nil {
+		if errors.Is(err, secret.ErrLocked) {
+			err = fmt.Errorf("identity.json is encrypted; set MATOU_IDENTITY_PASSPHRASE")
+		}
+		fmt.Printf("Warning: failed to open identity.json: %v\n", err)
+		u.locked = err
+		return
+	}
+
 	var data persistedIdentity
 	if err := json.Unmarshal(bytes, &data); err != nil {
 		fmt.Printf("Warning: failed to parse identity.json: %v\n", err)
@@ -236,11 +323,21 @@ func (u *UserIdentity) load() {
 	}
 
 	u.aid = data.AID
-	u.mnemonic = data.Mnemonic
+	u.mnemonic = secret.NewMnemonic(data.Mnemonic)
 	u.peerID = data.PeerID
 	u.orgAID = data.OrgAID
 	u.communitySpaceID = data.CommunitySpaceID
 	u.communityReadOnlySpaceID = data.CommunityReadOnlySpaceID
 	u.adminSpaceID = data.AdminSpaceID
 	u.privateSpaceID = data.PrivateSpaceID
+	u.inboxSpaceID = data.InboxSpaceID
+
+	// Migrate a plaintext file now that a passphrase is configured
+	if !sealed && u.sealer.Enabled() {
+		if err := u.persist(); err != nil {
+			fmt.Printf("Warning: failed to encrypt identity.json: %v\n", err)
+			return
+		}
+		fmt.Println("Encrypted identity.json at rest")
+	}
 }
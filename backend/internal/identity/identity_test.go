@@ -0,0 +1,81 @@
+package identity
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+const testPhrase = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestNewSealed_MigratesPlaintext(t *testing.T) {
+	dir := t.TempDir()
+	plain := New(dir)
+	if err := plain.SetIdentity("EAID", secret.NewMnemonic(testPhrase)); err != nil {
+		t.Fatalf("SetIdentity failed: %v", err)
+	}
+
+	sealer, _ := secret.NewSealer("passphrase")
+	sealed := NewSealed(dir, sealer)
+	if sealed.GetAID() != "EAID" || sealed.GetMnemonic().Reveal() != testPhrase {
+		t.Fatal("expected plaintext identity to load")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "identity.json"))
+	if err != nil {
+		t.Fatalf("reading identity.json: %v", err)
+	}
+	if strings.Contains(string(data), "abandon") || !secret.IsSealed(data) {
+		t.Fatalf("expected identity.json rewritten encrypted, got %s", data)
+	}
+
+	// Reopens with the same passphrase
+	reopened := NewSealed(dir, sealer)
+	if !reopened.IsConfigured() || reopened.Locked() != nil {
+		t.Error("expected encrypted identity to reopen")
+	}
+}
+
+func TestNewSealed_LockedWithoutPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	sealer, _ := secret.NewSealer("passphrase")
+	if err := NewSealed(dir, sealer).SetIdentity("EAID", secret.NewMnemonic(testPhrase)); err != nil {
+		t.Fatalf("SetIdentity failed: %v", err)
+	}
+
+	locked := New(dir)
+	if locked.IsConfigured() || locked.Locked() == nil {
+		t.Fatal("expected identity locked without a passphrase")
+	}
+	if err := locked.SetPeerID("peer"); err == nil {
+		t.Error("expected writes to a locked identity to fail")
+	}
+	if !NewSealed(dir, sealer).IsConfigured() {
+		t.Error("expected encrypted file left intact")
+	}
+
+	// Clearing discards the file and unlocks
+	if err := locked.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if err := locked.SetIdentity("ENEW", secret.NewMnemonic(testPhrase)); err != nil {
+		t.Errorf("expected cleared identity writable, got %v", err)
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	ui := New(dir)
+	if err := New(dir).SetIdentity("ERESTORED", secret.NewMnemonic(testPhrase)); err != nil {
+		t.Fatalf("SetIdentity failed: %v", err)
+	}
+	if ui.IsConfigured() {
+		t.Fatal("expected stale in-memory identity")
+	}
+	ui.Reload()
+	if ui.GetAID() != "ERESTORED" {
+		t.Errorf("expected reloaded AID, got %q", ui.GetAID())
+	}
+}
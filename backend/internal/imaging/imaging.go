@@ -0,0 +1,135 @@
+// Package imaging renders scaled-down copies of uploaded images, such as
+// avatar thumbnails. It uses only the standard library decoders, so JPEG,
+// PNG and GIF images can be rendered; other formats are stored as uploaded.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+
+	// Registers the GIF decoder with image.Decode
+	_ "image/gif"
+)
+
+// MaxPixels bounds the decoded size of an image, so a small file that
+// declares huge dimensions can't exhaust memory
+const MaxPixels = 40_000_000
+
+// jpegQuality is the quality renditions without transparency are encoded at
+const jpegQuality = 85
+
+// ErrUnsupported is returned for images the standard library can't decode
+var ErrUnsupported = errors.New("unsupported image format")
+
+// Spec is one rendition to produce: the image scaled to fit within a
+// Size×Size box
+type Spec struct {
+	Name string `yaml:"name" json:"name"`
+	Size int    `yaml:"size" json:"size"`
+}
+
+// Rendition is a rendered copy of an image
+type Rendition struct {
+	Name        string
+	ContentType string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+// Render decodes an image and produces a rendition for each spec the image
+// is larger than; images are never scaled up. Renditions are JPEG, or PNG
+// when the source can be transparent, and carry no metadata from the
+// original.
+func Render(data []byte, specs []Spec) ([]Rendition, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > MaxPixels {
+		return nil, fmt.Errorf("image is %dx%d, larger than %d pixels", cfg.Width, cfg.Height, MaxPixels)
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", format, err)
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, cfg.Width, cfg.Height))
+	draw.Draw(rgba, rgba.Bounds(), src, src.Bounds().Min, draw.Src)
+	transparent := format != "jpeg" && !rgba.Opaque()
+
+	var renditions []Rendition
+	for _, spec := range specs {
+		if spec.Size <= 0 || (cfg.Width <= spec.Size && cfg.Height <= spec.Size) {
+			continue
+		}
+		width, height := fit(cfg.Width, cfg.Height, spec.Size)
+		scaled := scale(rgba, width, height)
+
+		var buf bytes.Buffer
+		contentType := "image/jpeg"
+		if transparent {
+			contentType = "image/png"
+			err = png.Encode(&buf, scaled)
+		} else {
+			err = jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: jpegQuality})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s rendition: %w", spec.Name, err)
+		}
+		renditions = append(renditions, Rendition{
+			Name:        spec.Name,
+			ContentType: contentType,
+			Width:       width,
+			Height:      height,
+			Data:        buf.Bytes(),
+		})
+	}
+	return renditions, nil
+}
+
+// fit returns the dimensions of width×height scaled to fit within a
+// size×size box, keeping the aspect ratio
+func fit(width, height, size int) (int, int) {
+	if width >= height {
+		return size, max(1, (height*size+width/2)/width)
+	}
+	return max(1, (width*size+height/2)/height), size
+}
+
+// scale shrinks src to width×height by averaging the source pixels each
+// destination pixel covers (a box filter), which avoids the aliasing of
+// nearest-neighbour sampling when downscaling
+func scale(src *image.RGBA, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	for y := 0; y < height; y++ {
+		y0, y1 := y*sh/height, max((y+1)*sh/height, y*sh/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*sw/width, max((x+1)*sw/width, x*sw/width+1)
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
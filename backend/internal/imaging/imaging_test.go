@@ -0,0 +1,103 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRender(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			src.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 80, A: 255})
+		}
+	}
+
+	renditions, err := Render(encodePNG(t, src), []Spec{{Name: "thumb", Size: 100}, {Name: "large", Size: 800}})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if len(renditions) != 1 {
+		t.Fatalf("expected only the thumb rendered (no upscaling), got %d", len(renditions))
+	}
+	thumb := renditions[0]
+	if thumb.Name != "thumb" || thumb.Width != 100 || thumb.Height != 50 {
+		t.Errorf("expected 100x50 thumb, got %s %dx%d", thumb.Name, thumb.Width, thumb.Height)
+	}
+	if thumb.ContentType != "image/jpeg" {
+		t.Errorf("expected opaque image rendered as JPEG, got %s", thumb.ContentType)
+	}
+	decoded, err := jpeg.Decode(bytes.NewReader(thumb.Data))
+	if err != nil {
+		t.Fatalf("decoding thumb: %v", err)
+	}
+	if b := decoded.Bounds(); b.Dx() != 100 || b.Dy() != 50 {
+		t.Errorf("expected encoded thumb 100x50, got %v", b)
+	}
+}
+
+func TestRenderKeepsTransparency(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 64, 128))
+	src.Set(0, 0, color.NRGBA{R: 255, A: 255})
+
+	renditions, err := Render(encodePNG(t, src), []Spec{{Name: "thumb", Size: 32}})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if len(renditions) != 1 || renditions[0].ContentType != "image/png" {
+		t.Fatalf("expected transparent image rendered as PNG, got %+v", renditions)
+	}
+	if r := renditions[0]; r.Width != 16 || r.Height != 32 {
+		t.Errorf("expected 16x32 for a portrait image, got %dx%d", r.Width, r.Height)
+	}
+}
+
+func TestRenderAveragesPixels(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			v := uint8(0)
+			if (x+y)%2 == 0 {
+				v = 200
+			}
+			src.Set(x, y, color.RGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+	dst := scale(src, 2, 2)
+	for i := 0; i < len(dst.Pix); i += 4 {
+		if dst.Pix[i] != 100 || dst.Pix[i+3] != 255 {
+			t.Fatalf("expected checkerboard averaged to 100, got %v", dst.Pix[i:i+4])
+		}
+	}
+}
+
+func TestRenderRejects(t *testing.T) {
+	if _, err := Render([]byte("RIFF....WEBPVP8 "), []Spec{{Name: "thumb", Size: 64}}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported for an undecodable format, got %v", err)
+	}
+
+	// A PNG header claiming 100000x100000 pixels
+	huge := encodePNG(t, image.NewGray(image.Rect(0, 0, 1, 1)))
+	huge[16], huge[17], huge[18], huge[19] = 0, 1, 0x86, 0xa0
+	huge[20], huge[21], huge[22], huge[23] = 0, 1, 0x86, 0xa0
+	binary.BigEndian.PutUint32(huge[29:], crc32.ChecksumIEEE(huge[12:29]))
+	if _, err := Render(huge, []Spec{{Name: "thumb", Size: 64}}); err == nil || errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected oversized image rejected, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package keri
+
+import "fmt"
+
+// ApprovalPolicy is the org's two-person rule: a credential for one of its
+// roles is only issued once a second steward has approved it
+type ApprovalPolicy struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Roles needing approval; empty means DefaultApprovalRoles
+	Roles []string `json:"roles,omitempty" yaml:"roles,omitempty"`
+}
+
+// ApprovalPolicySource supplies the org's approval policy. The org config
+// handler implements this so policy edits apply without a restart.
+type ApprovalPolicySource interface {
+	GetApprovalPolicy() *ApprovalPolicy
+}
+
+// DefaultApprovalRoles returns the roles that need approval when the policy
+// names none: those that can administer the org or issue membership
+func DefaultApprovalRoles() []string {
+	return []string{"Admin", "Operations Steward"}
+}
+
+// ApprovalRoles returns the roles the policy applies to, or nil if it is off
+func (p *ApprovalPolicy) ApprovalRoles() []string {
+	if p == nil || !p.Enabled {
+		return nil
+	}
+	if len(p.Roles) == 0 {
+		return DefaultApprovalRoles()
+	}
+	return p.Roles
+}
+
+// RequiresApproval reports whether issuing role needs a second steward's
+// approval. A nil policy requires none.
+func (p *ApprovalPolicy) RequiresApproval(role string) bool {
+	for _, r := range p.ApprovalRoles() {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the policy names only known roles
+func (p *ApprovalPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, role := range p.Roles {
+		if !IsValidRole(role) {
+			return fmt.Errorf("approval policy: unknown role %q", role)
+		}
+		if seen[role] {
+			return fmt.Errorf("approval policy: duplicate role %q", role)
+		}
+		seen[role] = true
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package keri
+
+import "testing"
+
+func TestApprovalPolicy_RequiresApproval(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *ApprovalPolicy
+		role   string
+		want   bool
+	}{
+		{"nil policy", nil, "Admin", false},
+		{"disabled", &ApprovalPolicy{Roles: []string{"Admin"}}, "Admin", false},
+		{"default roles", &ApprovalPolicy{Enabled: true}, "Operations Steward", true},
+		{"default excludes member", &ApprovalPolicy{Enabled: true}, "Member", false},
+		{"custom roles", &ApprovalPolicy{Enabled: true, Roles: []string{"Moderator"}}, "Moderator", true},
+		{"custom replaces defaults", &ApprovalPolicy{Enabled: true, Roles: []string{"Moderator"}}, "Admin", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.RequiresApproval(tt.role); got != tt.want {
+				t.Errorf("RequiresApproval(%q) = %v, want %v", tt.role, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApprovalPolicy_Validate(t *testing.T) {
+	if err := (&ApprovalPolicy{Enabled: true, Roles: []string{"Admin", "Moderator"}}).Validate(); err != nil {
+		t.Errorf("expected valid policy, got %v", err)
+	}
+	if err := (&ApprovalPolicy{Roles: []string{"Wizard"}}).Validate(); err == nil {
+		t.Error("expected unknown role rejected")
+	}
+	if err := (&ApprovalPolicy{Roles: []string{"Admin", "Admin"}}).Validate(); err == nil {
+		t.Error("expected duplicate role rejected")
+	}
+}
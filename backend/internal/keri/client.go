@@ -3,15 +3,32 @@ package keri
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/metrics"
 )
 
 // Client provides KERI configuration and credential utilities.
 // Note: Credential issuance is handled by the frontend via signify-ts.
-// This client provides org info, role definitions, and credential validation.
+// This client provides org info, role definitions, credential validation
+// and OOBI exchange.
 type Client struct {
-	orgAID   string
-	orgAlias string
-	orgName  string
+	orgAID     string
+	orgAlias   string
+	orgName    string
+	orgOOBI    string
+	cesrURL    string
+	httpClient *http.Client
+
+	templates    RoleTemplateSource
+	endorsements EndorsementTypeSource
+
+	witnesses        WitnessPoolSource
+	defaultWitnesses *WitnessPool
+
+	keyHistory *KeyHistory
 }
 
 // Config holds KERI client configuration
@@ -19,6 +36,14 @@ type Config struct {
 	OrgAID   string
 	OrgAlias string
 	OrgName  string
+	// OrgOOBI is the org's published OOBI, from org config (optional)
+	OrgOOBI string
+	// CESRURL is KERIA's public CESR/OOBI URL, used to generate OOBIs for
+	// AIDs without a published one (optional)
+	CESRURL string
+	// Witnesses is the witness pool used when the org config sets none
+	// (optional)
+	Witnesses *WitnessPool
 }
 
 // CredentialData contains ACDC credential attributes
@@ -29,6 +54,74 @@ type CredentialData struct {
 	Permissions        []string `json:"permissions"`
 	JoinedAt           string   `json:"joinedAt"`
 	ExpiresAt          string   `json:"expiresAt,omitempty"`
+
+	// TermEndsAt limits a role to a term (RFC3339); at expiry the holder is
+	// demoted to DowngradeTo (default "Member") unless renewed via governance
+	TermEndsAt  string `json:"termEndsAt,omitempty"`
+	DowngradeTo string `json:"downgradeTo,omitempty"`
+
+	// Attributes holds custom per-role attributes defined by a RoleTemplate
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+
+	// Extra holds schema-specific claims not modelled above (endorsement
+	// category, event ID, participation kind, ...). They round-trip as
+	// top-level data fields.
+	Extra map[string]interface{} `json:"-"`
+}
+
+// Expired reports whether the credential's expiresAt has passed at now. A
+// credential without a parseable expiresAt doesn't expire.
+func (d CredentialData) Expired(now time.Time) bool {
+	if d.ExpiresAt == "" {
+		return false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, d.ExpiresAt)
+	return err == nil && !now.Before(expiresAt)
+}
+
+// credentialDataFields are the JSON names of CredentialData's own fields
+var credentialDataFields = []string{
+	"communityName", "role", "verificationStatus", "permissions", "joinedAt",
+	"expiresAt", "termEndsAt", "downgradeTo", "attributes",
+}
+
+// MarshalJSON flattens Extra claims alongside the known fields
+func (d CredentialData) MarshalJSON() ([]byte, error) {
+	type plain CredentialData
+	raw, err := json.Marshal(plain(d))
+	if err != nil || len(d.Extra) == 0 {
+		return raw, err
+	}
+	merged := make(map[string]interface{}, len(d.Extra)+len(credentialDataFields))
+	for k, v := range d.Extra {
+		merged[k] = v
+	}
+	// Known fields win over extras with the same name
+	if err := json.Unmarshal(raw, &merged); err != nil {
+		return nil, err
+	}
+	return json.Marshal(merged)
+}
+
+// UnmarshalJSON keeps unknown data fields in Extra
+func (d *CredentialData) UnmarshalJSON(data []byte) error {
+	type plain CredentialData
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	for _, k := range credentialDataFields {
+		delete(all, k)
+	}
+	*d = CredentialData(p)
+	if len(all) > 0 {
+		d.Extra = all
+	}
+	return nil
 }
 
 // Credential represents an ACDC credential
@@ -66,9 +159,14 @@ func NewClient(cfg *Config) (*Client, error) {
 	}
 
 	return &Client{
-		orgAID:   cfg.OrgAID,
-		orgAlias: cfg.OrgAlias,
-		orgName:  cfg.OrgName,
+		orgAID:     cfg.OrgAID,
+		orgAlias:   cfg.OrgAlias,
+		orgName:    cfg.OrgName,
+		orgOOBI:    cfg.OrgOOBI,
+		cesrURL:    strings.TrimSuffix(cfg.CESRURL, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+
+		defaultWitnesses: cfg.Witnesses,
 	}, nil
 }
 
@@ -88,9 +186,33 @@ func (c *Client) GetOrgAID() string {
 	return c.orgAID
 }
 
+// Close detaches the client from the org config it reads role templates
+// and the witness pool from. The client holds no KERIA connection (issuance happens in
+// signify-ts), so there is nothing else to release.
+func (c *Client) Close() error {
+	c.templates = nil
+	c.witnesses = nil
+	return nil
+}
+
 // ValidateCredential performs basic validation on a credential
 // Note: Cryptographic signature verification should be done by signify-ts
 func (c *Client) ValidateCredential(cred *Credential) error {
+	err := c.validateCredential(cred)
+	metrics.CountCredentialOperation("verified", err)
+	return err
+}
+
+func (c *Client) validateCredential(cred *Credential) error {
+	if err := c.validateStructure(cred); err != nil {
+		return err
+	}
+	return c.validateIssuerKeys(cred)
+}
+
+// validateStructure checks a credential's fields and attributes, without
+// looking at its signature
+func (c *Client) validateStructure(cred *Credential) error {
 	if cred == nil {
 		return fmt.Errorf("credential is nil")
 	}
@@ -106,8 +228,39 @@ func (c *Client) ValidateCredential(cred *Credential) error {
 	if cred.Schema == "" {
 		return fmt.Errorf("credential schema is required")
 	}
-	if !IsValidRole(cred.Data.Role) {
-		return fmt.Errorf("invalid role: %s", cred.Data.Role)
+	if cred.Data.Role != "" || !roleOptionalSchemas[cred.Schema] {
+		if !IsValidRole(cred.Data.Role) {
+			return fmt.Errorf("invalid role: %s", cred.Data.Role)
+		}
+	}
+	if err := validateParticipation(cred); err != nil {
+		return err
+	}
+	if err := c.validateEndorsement(cred); err != nil {
+		return err
+	}
+	if err := validateTerm(&cred.Data); err != nil {
+		return err
+	}
+	if err := c.validateCredentialAttributes(cred); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateTerm checks the term end date and downgrade role, if set
+func validateTerm(data *CredentialData) error {
+	if data.TermEndsAt == "" {
+		if data.DowngradeTo != "" {
+			return fmt.Errorf("downgradeTo requires termEndsAt")
+		}
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, data.TermEndsAt); err != nil {
+		return fmt.Errorf("termEndsAt must be an RFC3339 timestamp: %w", err)
+	}
+	if data.DowngradeTo != "" && !IsValidRole(data.DowngradeTo) {
+		return fmt.Errorf("invalid downgradeTo role: %s", data.DowngradeTo)
 	}
 	return nil
 }
@@ -1,7 +1,9 @@
 package keri
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 )
 
 func TestGetPermissionsForRole(t *testing.T) {
@@ -217,6 +219,32 @@ func TestValidateCredential(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "participation credential without role",
+			cred: &Credential{
+				SAID:      "ESAID456",
+				Issuer:    "EAID123456789",
+				Recipient: "ERECIPIENT123",
+				Schema:    ParticipationSchema,
+				Data: CredentialData{
+					Extra: map[string]interface{}{"kind": ParticipationAttendance},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "participation credential with invalid kind",
+			cred: &Credential{
+				SAID:      "ESAID789",
+				Issuer:    "EAID123456789",
+				Recipient: "ERECIPIENT123",
+				Schema:    ParticipationSchema,
+				Data: CredentialData{
+					Extra: map[string]interface{}{"kind": "lurking"},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -298,3 +326,53 @@ func TestValidateCredentialJSON(t *testing.T) {
 		t.Error("expected error for invalid JSON")
 	}
 }
+
+func TestCredentialData_ExtraRoundTrip(t *testing.T) {
+	raw := `{"role":"Member","kind":"contribution","description":"Ran the garden working bee"}`
+
+	var data CredentialData
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if data.Role != "Member" {
+		t.Errorf("expected role Member, got %s", data.Role)
+	}
+	if data.Extra["kind"] != "contribution" {
+		t.Errorf("expected extra kind contribution, got %v", data.Extra["kind"])
+	}
+	if _, ok := data.Extra["role"]; ok {
+		t.Error("known fields should not be duplicated into Extra")
+	}
+
+	out, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatalf("Unmarshal of output failed: %v", err)
+	}
+	if decoded["description"] != "Ran the garden working bee" || decoded["role"] != "Member" {
+		t.Errorf("unexpected round-trip output: %s", out)
+	}
+}
+
+func TestCredentialData_Expired(t *testing.T) {
+	now := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expiresAt string
+		want      bool
+	}{
+		{"", false},
+		{"not a date", false},
+		{"2026-10-19T00:00:00Z", true},
+		{"2026-10-20T00:00:00Z", true},
+		{"2026-10-21T00:00:00Z", false},
+	}
+	for _, tt := range tests {
+		if got := (CredentialData{ExpiresAt: tt.expiresAt}).Expired(now); got != tt.want {
+			t.Errorf("Expired() with expiresAt %q = %t, want %t", tt.expiresAt, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package keri
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EndorsementCategory is one thing a member can be endorsed for, e.g.
+// "facilitation" or "weaving"
+type EndorsementCategory struct {
+	ID          string `json:"id" yaml:"id"`
+	Label       string `json:"label" yaml:"label"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// EndorsementType groups endorsement categories, e.g. skills or character.
+// Category IDs are unique across all types, so a category alone identifies
+// its type.
+type EndorsementType struct {
+	ID          string                `json:"id" yaml:"id"`
+	Label       string                `json:"label" yaml:"label"`
+	Description string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Categories  []EndorsementCategory `json:"categories" yaml:"categories"`
+}
+
+// EndorsementTypeSource supplies the community's endorsement types. The org
+// config handler implements this so registry edits apply without a restart.
+type EndorsementTypeSource interface {
+	GetEndorsementTypes() []EndorsementType
+}
+
+// DefaultEndorsementTypes returns the registry used when the org config
+// defines none
+func DefaultEndorsementTypes() []EndorsementType {
+	return []EndorsementType{
+		{
+			ID:          "skill",
+			Label:       "Skill",
+			Description: "Practical knowledge the member has shown",
+			Categories: []EndorsementCategory{
+				{ID: "facilitation", Label: "Facilitation"},
+				{ID: "software", Label: "Software"},
+				{ID: "design", Label: "Design"},
+				{ID: "legal", Label: "Legal"},
+				{ID: "accounting", Label: "Accounting"},
+				{ID: "teaching", Label: "Teaching"},
+				{ID: "writing", Label: "Writing"},
+				{ID: "translation", Label: "Translation"},
+				{ID: "project_management", Label: "Project management"},
+				{ID: "community_organising", Label: "Community organising"},
+				{ID: "carving", Label: "Carving"},
+				{ID: "weaving", Label: "Weaving"},
+			},
+		},
+		{
+			ID:          "character",
+			Label:       "Character",
+			Description: "How the member works with others",
+			Categories: []EndorsementCategory{
+				{ID: "reliability", Label: "Reliability"},
+				{ID: "integrity", Label: "Integrity"},
+				{ID: "collaboration", Label: "Collaboration"},
+			},
+		},
+	}
+}
+
+// ValidateEndorsementTypes checks a registry is well formed: every type and
+// category has an ID and label, and no ID is used twice
+func ValidateEndorsementTypes(types []EndorsementType) error {
+	seenTypes := make(map[string]bool)
+	seenCategories := make(map[string]string)
+	for _, t := range types {
+		if t.ID == "" || t.Label == "" {
+			return fmt.Errorf("endorsement type needs an id and label")
+		}
+		if seenTypes[strings.ToLower(t.ID)] {
+			return fmt.Errorf("duplicate endorsement type: %s", t.ID)
+		}
+		seenTypes[strings.ToLower(t.ID)] = true
+		if len(t.Categories) == 0 {
+			return fmt.Errorf("endorsement type %s has no categories", t.ID)
+		}
+		for _, c := range t.Categories {
+			if c.ID == "" || c.Label == "" {
+				return fmt.Errorf("endorsement type %s: category needs an id and label", t.ID)
+			}
+			if other, ok := seenCategories[strings.ToLower(c.ID)]; ok {
+				return fmt.Errorf("endorsement category %s is in both %s and %s", c.ID, other, t.ID)
+			}
+			seenCategories[strings.ToLower(c.ID)] = t.ID
+		}
+	}
+	return nil
+}
+
+// FindEndorsementCategory returns the type and category for a category ID,
+// compared case-insensitively, or nil if it isn't registered
+func FindEndorsementCategory(types []EndorsementType, category string) (*EndorsementType, *EndorsementCategory) {
+	for i := range types {
+		for j := range types[i].Categories {
+			if strings.EqualFold(types[i].Categories[j].ID, category) {
+				return &types[i], &types[i].Categories[j]
+			}
+		}
+	}
+	return nil, nil
+}
+
+// SetEndorsementTypeSource attaches the source of the endorsement registry
+func (c *Client) SetEndorsementTypeSource(source EndorsementTypeSource) {
+	c.endorsements = source
+}
+
+// GetEndorsementTypes returns the configured endorsement types, or the
+// defaults if none are configured
+func (c *Client) GetEndorsementTypes() []EndorsementType {
+	if c.endorsements != nil {
+		if types := c.endorsements.GetEndorsementTypes(); len(types) > 0 {
+			return types
+		}
+	}
+	return DefaultEndorsementTypes()
+}
+
+// validateEndorsement checks an endorsement credential's category is
+// registered, its endorsementType (if given) is the category's type, and its
+// confidence (if given) is in [0, 1]
+func (c *Client) validateEndorsement(cred *Credential) error {
+	if cred.Schema != EndorsementSchema {
+		return nil
+	}
+	category, _ := cred.Data.Extra["category"].(string)
+	typ, _ := FindEndorsementCategory(c.GetEndorsementTypes(), strings.TrimSpace(category))
+	if typ == nil {
+		return fmt.Errorf("unknown endorsement category: %q", category)
+	}
+	if value, ok := cred.Data.Extra["endorsementType"]; ok {
+		if s, _ := value.(string); !strings.EqualFold(s, typ.ID) {
+			return fmt.Errorf("endorsement category %s is not of type %v", category, value)
+		}
+	}
+	if value, ok := cred.Data.Extra["confidence"]; ok {
+		confidence, isNumber := value.(float64)
+		if !isNumber || confidence < 0 || confidence > 1 {
+			return fmt.Errorf("endorsement confidence must be a number between 0 and 1")
+		}
+	}
+	return nil
+}
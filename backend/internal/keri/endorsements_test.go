@@ -0,0 +1,84 @@
+package keri
+
+import "testing"
+
+type staticEndorsementTypes []EndorsementType
+
+func (s staticEndorsementTypes) GetEndorsementTypes() []EndorsementType { return s }
+
+func TestValidateEndorsementTypes(t *testing.T) {
+	if err := ValidateEndorsementTypes(DefaultEndorsementTypes()); err != nil {
+		t.Fatalf("default registry is invalid: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		types []EndorsementType
+	}{
+		{"missing label", []EndorsementType{{ID: "skill", Categories: []EndorsementCategory{{ID: "a", Label: "A"}}}}},
+		{"no categories", []EndorsementType{{ID: "skill", Label: "Skill"}}},
+		{"duplicate type", []EndorsementType{
+			{ID: "skill", Label: "Skill", Categories: []EndorsementCategory{{ID: "a", Label: "A"}}},
+			{ID: "Skill", Label: "Skill", Categories: []EndorsementCategory{{ID: "b", Label: "B"}}},
+		}},
+		{"category in two types", []EndorsementType{
+			{ID: "skill", Label: "Skill", Categories: []EndorsementCategory{{ID: "a", Label: "A"}}},
+			{ID: "character", Label: "Character", Categories: []EndorsementCategory{{ID: "A", Label: "A"}}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateEndorsementTypes(tt.types); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestValidateCredential_Endorsement(t *testing.T) {
+	client, _ := NewClient(&Config{OrgAID: "EAID123456789"})
+	endorsement := func(extra map[string]interface{}) *Credential {
+		return &Credential{
+			SAID:      "ESAID123",
+			Issuer:    "EPEER",
+			Recipient: "ERECIPIENT123",
+			Schema:    EndorsementSchema,
+			Data:      CredentialData{Extra: extra},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		extra   map[string]interface{}
+		wantErr bool
+	}{
+		{"registered category", map[string]interface{}{"category": "facilitation"}, false},
+		{"category case-insensitive", map[string]interface{}{"category": "Facilitation"}, false},
+		{"matching type", map[string]interface{}{"category": "integrity", "endorsementType": "character"}, false},
+		{"with confidence", map[string]interface{}{"category": "weaving", "confidence": 0.5}, false},
+		{"unknown category", map[string]interface{}{"category": "juggling"}, true},
+		{"missing category", map[string]interface{}{}, true},
+		{"wrong type", map[string]interface{}{"category": "integrity", "endorsementType": "skill"}, true},
+		{"confidence out of range", map[string]interface{}{"category": "weaving", "confidence": 1.5}, true},
+		{"confidence not a number", map[string]interface{}{"category": "weaving", "confidence": "high"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.ValidateCredential(endorsement(tt.extra))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCredential() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// A configured registry replaces the defaults
+	client.SetEndorsementTypeSource(staticEndorsementTypes{
+		{ID: "craft", Label: "Craft", Categories: []EndorsementCategory{{ID: "juggling", Label: "Juggling"}}},
+	})
+	if err := client.ValidateCredential(endorsement(map[string]interface{}{"category": "juggling"})); err != nil {
+		t.Errorf("expected configured category to validate, got %v", err)
+	}
+	if err := client.ValidateCredential(endorsement(map[string]interface{}{"category": "facilitation"})); err == nil {
+		t.Error("expected default category to be rejected once the registry is configured")
+	}
+}
@@ -0,0 +1,63 @@
+package keri
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignRequest_Verify(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	req := httptest.NewRequest("POST", "/api/v1/sync/kel?x=1", nil)
+	SignRequest(req, "EAID", priv, now)
+
+	sig, err := ParseRequestSignature(req)
+	if err != nil {
+		t.Fatalf("ParseRequestSignature failed: %v", err)
+	}
+	if sig.AID != "EAID" || sig.KeyID != EncodeVerKey(pub) || !sig.Created.Equal(now) {
+		t.Errorf("unexpected signature: %+v", sig)
+	}
+	if err := sig.Verify(); err != nil {
+		t.Errorf("expected signature to verify: %v", err)
+	}
+
+	// The signature covers the method, path and claimed AID
+	tampered := []func(r *http.Request){
+		func(r *http.Request) { r.Method = "DELETE" },
+		func(r *http.Request) { r.URL.Path = "/api/v1/identity/set" },
+		func(r *http.Request) { r.Header.Set(HeaderSignifyResource, "EOTHER") },
+	}
+	for i, tamper := range tampered {
+		req := httptest.NewRequest("POST", "/api/v1/sync/kel", nil)
+		SignRequest(req, "EAID", priv, now)
+		tamper(req)
+		if sig, err := ParseRequestSignature(req); err == nil && sig.Verify() == nil {
+			t.Errorf("tamper %d: expected verification to fail", i)
+		}
+	}
+}
+
+func TestParseRequestSignature_Invalid(t *testing.T) {
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+
+	unsigned := httptest.NewRequest("POST", "/api/v1/sync/kel", nil)
+	if _, err := ParseRequestSignature(unsigned); err == nil {
+		t.Error("expected error for unsigned request")
+	}
+
+	// Leaving the AID out of the covered fields is rejected
+	req := httptest.NewRequest("POST", "/api/v1/sync/kel", nil)
+	SignRequest(req, "EAID", priv, time.Now())
+	input := req.Header.Get(HeaderSignatureInput)
+	req.Header.Set(HeaderSignatureInput, strings.Replace(input, ` "signify-resource"`, "", 1))
+	if _, err := ParseRequestSignature(req); err == nil || !strings.Contains(err.Error(), "signify-resource") {
+		t.Errorf("expected uncovered AID to be rejected, got %v", err)
+	}
+}
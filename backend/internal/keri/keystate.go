@@ -0,0 +1,286 @@
+package keri
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeyState is an AID's current signing keys, from its latest establishment
+// event
+type KeyState struct {
+	AID      string   `json:"aid"`
+	Sequence int      `json:"sequence"` // Sequence number of the latest event
+	Keys     []string `json:"keys"`     // Current signing keys (qb64)
+
+	// KeySequence and KeyDigest identify the establishment event that set
+	// Keys
+	KeySequence int    `json:"keySequence"`
+	KeyDigest   string `json:"keyDigest,omitempty"`
+
+	// Witnesses are the current witness AIDs, and WitnessThreshold how many
+	// of them must receipt an event (the KEL's "toad")
+	Witnesses        []string `json:"witnesses,omitempty"`
+	WitnessThreshold int      `json:"witnessThreshold"`
+}
+
+// HasKey reports whether qb64 is one of the current signing keys
+func (k *KeyState) HasKey(qb64 string) bool {
+	for _, key := range k.Keys {
+		if key == qb64 {
+			return true
+		}
+	}
+	return false
+}
+
+// keyEvent holds the key event fields key state is derived from
+type keyEvent struct {
+	Type     string   `json:"t"`
+	Digest   string   `json:"d"`
+	AID      string   `json:"i"`
+	Sequence string   `json:"s"` // Hex
+	Keys     []string `json:"k"`
+
+	Witnesses        []string `json:"b"`  // Inception
+	WitnessThreshold string   `json:"bt"` // Hex
+	WitnessCuts      []string `json:"br"` // Rotation
+	WitnessAdds      []string `json:"ba"` // Rotation
+}
+
+// applyWitnesses updates state's witnesses and threshold from an
+// establishment event
+func (e *keyEvent) applyWitnesses(state *KeyState) error {
+	switch e.Type {
+	case "icp", "dip":
+		state.Witnesses = append([]string(nil), e.Witnesses...)
+	default:
+		cut := make(map[string]bool, len(e.WitnessCuts))
+		for _, w := range e.WitnessCuts {
+			cut[w] = true
+		}
+		var witnesses []string
+		for _, w := range state.Witnesses {
+			if !cut[w] {
+				witnesses = append(witnesses, w)
+			}
+		}
+		state.Witnesses = append(witnesses, e.WitnessAdds...)
+	}
+	if e.WitnessThreshold != "" {
+		toad, err := strconv.ParseInt(e.WitnessThreshold, 16, 64)
+		if err != nil {
+			return fmt.Errorf("KEL event has invalid witness threshold %q", e.WitnessThreshold)
+		}
+		state.WitnessThreshold = int(toad)
+	}
+	return nil
+}
+
+// isEstablishment reports whether the event sets the signing keys
+func (e *keyEvent) isEstablishment() bool {
+	switch e.Type {
+	case "icp", "rot", "dip", "drt":
+		return true
+	}
+	return false
+}
+
+// ParseKeyState derives aid's key state from a CESR stream of its KEL, as
+// served by KERIA's OOBI endpoint. Event signatures are verified by KERIA
+// when it accepts the events; this checks the KEL starts with aid's
+// inception event, has no gaps, and returns the keys of its latest
+// establishment event. Events for other AIDs in the stream (witnesses, the
+// agent) are ignored.
+func ParseKeyState(aid string, stream []byte) (*KeyState, error) {
+	events := make(map[int]*keyEvent)
+	for len(stream) > 0 {
+		// Events are JSON; attachments between them are qb64 text, which
+		// never contains "{"
+		start := bytes.IndexByte(stream, '{')
+		if start < 0 {
+			break
+		}
+		dec := json.NewDecoder(bytes.NewReader(stream[start:]))
+		var e keyEvent
+		if err := dec.Decode(&e); err != nil {
+			return nil, fmt.Errorf("malformed KEL event: %w", err)
+		}
+		stream = stream[start+int(dec.InputOffset()):]
+
+		if e.AID != aid {
+			continue
+		}
+		sn, err := strconv.ParseInt(e.Sequence, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("KEL event has invalid sequence number %q", e.Sequence)
+		}
+		event := e
+		events[int(sn)] = &event
+	}
+
+	if len(events) == 0 {
+		return nil, fmt.Errorf("no KEL found for %s", aid)
+	}
+	sequences := make([]int, 0, len(events))
+	for sn := range events {
+		sequences = append(sequences, sn)
+	}
+	sort.Ints(sequences)
+
+	if icp := events[0]; icp == nil || (icp.Type != "icp" && icp.Type != "dip") {
+		return nil, fmt.Errorf("KEL for %s does not start with an inception event", aid)
+	}
+	state := &KeyState{AID: aid}
+	for i, sn := range sequences {
+		if sn != i {
+			return nil, fmt.Errorf("KEL for %s is missing event %d", aid, i)
+		}
+		if e := events[sn]; e.isEstablishment() {
+			state.Keys = e.Keys
+			state.KeySequence, state.KeyDigest = sn, e.Digest
+			if err := e.applyWitnesses(state); err != nil {
+				return nil, err
+			}
+		}
+		state.Sequence = sn
+	}
+	if len(state.Keys) == 0 {
+		return nil, fmt.Errorf("KEL for %s has no signing keys", aid)
+	}
+	return state, nil
+}
+
+// decodeQB64 decodes a CESR primitive with a codeSize-character code and
+// rawSize bytes of raw material
+func decodeQB64(qb64 string, codeSize, rawSize int) ([]byte, error) {
+	pad := (3 - rawSize%3) % 3
+	if len(qb64) != (rawSize+pad)*4/3 || pad != codeSize%4 {
+		return nil, fmt.Errorf("invalid length for qb64 primitive")
+	}
+	// The code replaces the leading pad characters, which encode zero bytes
+	full, err := base64.RawURLEncoding.DecodeString(strings.Repeat("A", codeSize) + qb64[codeSize:])
+	if err != nil {
+		return nil, err
+	}
+	return full[pad:], nil
+}
+
+// DecodeVerKey decodes a qb64 Ed25519 public key: "D" (transferable) or
+// "B" (non-transferable)
+func DecodeVerKey(qb64 string) (ed25519.PublicKey, error) {
+	if qb64 == "" || (qb64[0] != 'D' && qb64[0] != 'B') {
+		return nil, fmt.Errorf("unsupported verification key %q (expected Ed25519)", qb64)
+	}
+	raw, err := decodeQB64(qb64, 1, ed25519.PublicKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid verification key: %w", err)
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// DecodeSignature decodes a qb64 Ed25519 signature: "0B" (unindexed) or
+// "A" followed by an index character (indexed)
+func DecodeSignature(qb64 string) ([]byte, error) {
+	if !strings.HasPrefix(qb64, "0B") && !strings.HasPrefix(qb64, "A") {
+		return nil, fmt.Errorf("unsupported signature (expected Ed25519)")
+	}
+	raw, err := decodeQB64(qb64, 2, ed25519.SignatureSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	return raw, nil
+}
+
+// KeyStateResolver fetches AIDs' key state from KERIA's public OOBI
+// endpoint, caching it briefly so a burst of signed requests costs one
+// fetch
+type KeyStateResolver struct {
+	baseURL string
+	ttl     time.Duration
+	client  *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedKeyState
+}
+
+type cachedKeyState struct {
+	state   *KeyState
+	fetched time.Time
+}
+
+// NewKeyStateResolver creates a resolver for KERIA's CESR URL
+// (e.g. http://localhost:3902). A ttl of zero disables caching.
+func NewKeyStateResolver(cesrURL string, ttl time.Duration) *KeyStateResolver {
+	return &KeyStateResolver{
+		baseURL: strings.TrimSuffix(cesrURL, "/"),
+		ttl:     ttl,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		cache:   make(map[string]cachedKeyState),
+	}
+}
+
+// KeyState returns aid's current key state
+func (r *KeyStateResolver) KeyState(ctx context.Context, aid string) (*KeyState, error) {
+	r.mu.Lock()
+	cached, ok := r.cache[aid]
+	r.mu.Unlock()
+	if ok && time.Since(cached.fetched) < r.ttl {
+		return cached.state, nil
+	}
+
+	stream, err := fetchKEL(ctx, r.client, r.baseURL+"/oobi/"+url.PathEscape(aid), aid)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := ParseKeyState(aid, stream)
+	if err != nil {
+		return nil, err
+	}
+	if r.ttl > 0 {
+		r.mu.Lock()
+		r.cache[aid] = cachedKeyState{state: state, fetched: time.Now()}
+		r.mu.Unlock()
+	}
+	return state, nil
+}
+
+// Forget drops aid's cached key state, so the next lookup sees a rotation
+func (r *KeyStateResolver) Forget(aid string) {
+	r.mu.Lock()
+	delete(r.cache, aid)
+	r.mu.Unlock()
+}
+
+// fetchKEL fetches the CESR stream an OOBI URL serves for aid
+func fetchKEL(ctx context.Context, client *http.Client, oobiURL, aid string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, oobiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching KEL for %s: %w", aid, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching KEL for %s: KERIA returned %s", aid, resp.Status)
+	}
+	stream, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, fmt.Errorf("reading KEL for %s: %w", aid, err)
+	}
+	return stream, nil
+}
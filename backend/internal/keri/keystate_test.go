@@ -0,0 +1,100 @@
+package keri
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testKEL builds a CESR-style KEL stream with an attachment after each event
+func testKEL(events ...string) []byte {
+	var b strings.Builder
+	for _, e := range events {
+		b.WriteString(e)
+		b.WriteString("-AABAAB8xYJxk0e2TFDHnJmPTPy6GtaPIcKUBwYtBgI9xnQ9-1kUHvyLsYj3Z2j_GAw")
+	}
+	return []byte(b.String())
+}
+
+func keyEventJSON(t, aid string, sn int, keys ...string) string {
+	return fmt.Sprintf(`{"v":"KERI10JSON00012b_","t":%q,"d":"EDIGEST%d","i":%q,"s":"%x","k":["%s"]}`, t, sn, aid, sn, strings.Join(keys, `","`))
+}
+
+func TestParseKeyState(t *testing.T) {
+	stream := testKEL(
+		keyEventJSON("icp", "EAID", 0, "DKEY0"),
+		keyEventJSON("icp", "BWITNESS", 0, "BWITNESS"),
+		`{"v":"KERI10JSON0000cb_","t":"ixn","d":"EDIGEST1","i":"EAID","s":"1","a":[]}`,
+		keyEventJSON("rot", "EAID", 2, "DKEY1", "DKEY2"),
+	)
+	state, err := ParseKeyState("EAID", stream)
+	if err != nil {
+		t.Fatalf("ParseKeyState failed: %v", err)
+	}
+	if state.Sequence != 2 || !state.HasKey("DKEY2") || state.HasKey("DKEY0") {
+		t.Errorf("expected rotated keys at sequence 2, got %+v", state)
+	}
+
+	tests := map[string][]byte{
+		"no events":     testKEL(keyEventJSON("icp", "EOTHER", 0, "DKEY0")),
+		"no inception":  testKEL(keyEventJSON("rot", "EAID", 0, "DKEY0")),
+		"missing event": testKEL(keyEventJSON("icp", "EAID", 0, "DKEY0"), keyEventJSON("rot", "EAID", 2, "DKEY1")),
+		"malformed":     []byte(`{"t":"icp","i":"EAID"`),
+	}
+	for name, stream := range tests {
+		if _, err := ParseKeyState("EAID", stream); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestDecodeVerKey(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	qb64 := EncodeVerKey(pub)
+	if len(qb64) != 44 || qb64[0] != 'D' {
+		t.Fatalf("unexpected qb64 key %q", qb64)
+	}
+	decoded, err := DecodeVerKey(qb64)
+	if err != nil || !decoded.Equal(pub) {
+		t.Errorf("round trip failed: %v", err)
+	}
+
+	for _, bad := range []string{"", "EAID", "D" + strings.Repeat("A", 10)} {
+		if _, err := DecodeVerKey(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestKeyStateResolver(t *testing.T) {
+	fetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oobi/EAID" {
+			http.NotFound(w, r)
+			return
+		}
+		fetches++
+		w.Write(testKEL(keyEventJSON("icp", "EAID", 0, "DKEY0")))
+	}))
+	defer srv.Close()
+
+	resolver := NewKeyStateResolver(srv.URL+"/", time.Minute)
+	for i := 0; i < 2; i++ {
+		state, err := resolver.KeyState(context.Background(), "EAID")
+		if err != nil || !state.HasKey("DKEY0") {
+			t.Fatalf("KeyState failed: %v", err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("expected cached key state, got %d fetches", fetches)
+	}
+	if _, err := resolver.KeyState(context.Background(), "EUNKNOWN"); err == nil {
+		t.Error("expected error for unknown AID")
+	}
+}
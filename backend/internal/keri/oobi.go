@@ -0,0 +1,85 @@
+package keri
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ResolvedOOBI is an AID whose KEL was fetched through its OOBI
+type ResolvedOOBI struct {
+	AID        string    `json:"aid"`
+	OOBI       string    `json:"oobi"`
+	Role       string    `json:"role,omitempty"` // "agent", "witness" or "controller"
+	KeyState   *KeyState `json:"keyState"`
+	ResolvedAt string    `json:"resolvedAt"`
+}
+
+// ParseOOBI checks an OOBI URL and returns the AID and role it names.
+// OOBIs have the form {base}/oobi/{aid}[/{role}[/{eid}]].
+func ParseOOBI(oobi string) (aid, role string, err error) {
+	u, err := url.Parse(oobi)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", fmt.Errorf("OOBI must be an absolute http(s) URL")
+	}
+	_, rest, ok := strings.Cut(u.Path, "/oobi/")
+	if !ok {
+		return "", "", fmt.Errorf("OOBI URL has no /oobi/ path")
+	}
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	aid = parts[0]
+	if aid == "" {
+		return "", "", fmt.Errorf("OOBI URL names no AID")
+	}
+	if len(parts) > 1 {
+		role = parts[1]
+	}
+	return aid, role, nil
+}
+
+// GenerateOOBI returns the OOBI others can resolve aid through. The org's
+// published OOBI is preferred for the org AID; otherwise the OOBI is
+// KERIA's public endpoint for aid, which serves its KEL.
+func (c *Client) GenerateOOBI(aid string) (string, error) {
+	if aid == "" {
+		aid = c.orgAID
+	}
+	if aid == "" {
+		return "", fmt.Errorf("aid is required (org not configured)")
+	}
+	if aid == c.orgAID && c.orgOOBI != "" {
+		return c.orgOOBI, nil
+	}
+	if c.cesrURL == "" {
+		return "", fmt.Errorf("no OOBI published for %s and no CESR URL configured", aid)
+	}
+	return c.cesrURL + "/oobi/" + url.PathEscape(aid), nil
+}
+
+// ResolveOOBI fetches the KEL an OOBI serves and checks it is a complete
+// KEL for the AID the OOBI names, returning that AID's current key state.
+// The KEL's signatures are not verified here; KERIA verifies them when the
+// OOBI is resolved into an agent.
+func (c *Client) ResolveOOBI(ctx context.Context, oobi string) (*ResolvedOOBI, error) {
+	aid, role, err := ParseOOBI(oobi)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := fetchKEL(ctx, c.httpClient, oobi, aid)
+	if err != nil {
+		return nil, err
+	}
+	state, err := ParseKeyState(aid, stream)
+	if err != nil {
+		return nil, err
+	}
+	return &ResolvedOOBI{
+		AID:        aid,
+		OOBI:       oobi,
+		Role:       role,
+		KeyState:   state,
+		ResolvedAt: time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
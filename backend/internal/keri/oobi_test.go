@@ -0,0 +1,89 @@
+package keri
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseOOBI(t *testing.T) {
+	tests := []struct {
+		oobi     string
+		wantAID  string
+		wantRole string
+		wantErr  bool
+	}{
+		{"http://keria:3902/oobi/EAID", "EAID", "", false},
+		{"https://keria.example/oobi/EAID/agent/EAGENT", "EAID", "agent", false},
+		{"http://witness:5642/oobi/EAID/witness/BWIT?name=alice", "EAID", "witness", false},
+		{"ftp://keria/oobi/EAID", "", "", true},
+		{"/oobi/EAID", "", "", true},
+		{"http://keria:3902/kel/EAID", "", "", true},
+		{"http://keria:3902/oobi/", "", "", true},
+	}
+	for _, tt := range tests {
+		aid, role, err := ParseOOBI(tt.oobi)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseOOBI(%q) error = %v, wantErr %v", tt.oobi, err, tt.wantErr)
+			continue
+		}
+		if aid != tt.wantAID || role != tt.wantRole {
+			t.Errorf("ParseOOBI(%q) = %q, %q, want %q, %q", tt.oobi, aid, role, tt.wantAID, tt.wantRole)
+		}
+	}
+}
+
+func TestClient_GenerateOOBI(t *testing.T) {
+	client, _ := NewClient(&Config{OrgAID: "EORG", OrgOOBI: "https://org.example/oobi/EORG/agent/EAGENT", CESRURL: "http://keria:3902/"})
+
+	if oobi, _ := client.GenerateOOBI(""); oobi != "https://org.example/oobi/EORG/agent/EAGENT" {
+		t.Errorf("expected org's published OOBI, got %q", oobi)
+	}
+	if oobi, _ := client.GenerateOOBI("EMEMBER"); oobi != "http://keria:3902/oobi/EMEMBER" {
+		t.Errorf("expected KERIA OOBI, got %q", oobi)
+	}
+
+	unconfigured, _ := NewClient(&Config{})
+	if _, err := unconfigured.GenerateOOBI(""); err == nil {
+		t.Error("expected error without an org AID")
+	}
+	if _, err := unconfigured.GenerateOOBI("EMEMBER"); err == nil {
+		t.Error("expected error without a CESR URL")
+	}
+}
+
+func TestClient_ResolveOOBI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oobi/EMEMBER/agent/EAGENT":
+			w.Write(testKEL(
+				keyEventJSON("icp", "EMEMBER", 0, "DKEY0"),
+				keyEventJSON("icp", "EAGENT", 0, "DAGENTKEY"),
+				keyEventJSON("rot", "EMEMBER", 1, "DKEY1"),
+			))
+		case "/oobi/EOTHER":
+			// Serves someone else's KEL
+			w.Write(testKEL(keyEventJSON("icp", "EMEMBER", 0, "DKEY0")))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	client, _ := NewClient(&Config{})
+
+	resolved, err := client.ResolveOOBI(context.Background(), srv.URL+"/oobi/EMEMBER/agent/EAGENT")
+	if err != nil {
+		t.Fatalf("ResolveOOBI failed: %v", err)
+	}
+	if resolved.AID != "EMEMBER" || resolved.Role != "agent" || resolved.KeyState.Sequence != 1 || !resolved.KeyState.HasKey("DKEY1") {
+		t.Errorf("unexpected resolution %+v", resolved)
+	}
+
+	if _, err := client.ResolveOOBI(context.Background(), srv.URL+"/oobi/EOTHER"); err == nil {
+		t.Error("expected error when the OOBI serves another AID's KEL")
+	}
+	if _, err := client.ResolveOOBI(context.Background(), srv.URL+"/oobi/EMISSING"); err == nil {
+		t.Error("expected error for an OOBI that isn't served")
+	}
+}
@@ -0,0 +1,200 @@
+package keri
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KeyEpoch is a set of signing keys an AID held, from the establishment
+// event that set them until the next one
+type KeyEpoch struct {
+	Sequence int      `json:"sequence" yaml:"sequence"`
+	Digest   string   `json:"digest,omitempty" yaml:"digest,omitempty"`
+	Keys     []string `json:"keys" yaml:"keys"`
+	// Since is when the keys took effect: zero for inception keys,
+	// otherwise when the rotation was recorded. KERI events carry no
+	// timestamps, so rotations should be recorded as soon as they are made.
+	Since time.Time `json:"since,omitempty" yaml:"since,omitempty"`
+}
+
+// KeyRotation is the result of recording an AID's key state
+type KeyRotation struct {
+	AID      string    `json:"aid"`
+	Previous *KeyEpoch `json:"previous,omitempty"`
+	Current  *KeyEpoch `json:"current"`
+	// Recorded is false when the KEL's latest establishment event was
+	// already recorded
+	Recorded bool `json:"recorded"`
+}
+
+// KeyHistory records the signing keys of the org and admin AIDs over time,
+// in dataDir/key-history.yaml, so credentials can be checked against the
+// keys their issuer held when they were issued
+type KeyHistory struct {
+	mu     sync.RWMutex
+	path   string
+	epochs map[string][]KeyEpoch
+	now    func() time.Time
+}
+
+// NewKeyHistory loads the history saved in dataDir. An empty dataDir keeps
+// the history in memory only.
+func NewKeyHistory(dataDir string) *KeyHistory {
+	h := &KeyHistory{
+		epochs: make(map[string][]KeyEpoch),
+		now:    time.Now,
+	}
+	if dataDir != "" {
+		h.path = filepath.Join(dataDir, "key-history.yaml")
+		h.loadFromDisk()
+	}
+	return h
+}
+
+// loadFromDisk reads the saved history, if any
+func (h *KeyHistory) loadFromDisk() {
+	data, err := os.ReadFile(h.path)
+	if err != nil {
+		return
+	}
+	var epochs map[string][]KeyEpoch
+	if err := yaml.Unmarshal(data, &epochs); err != nil {
+		fmt.Printf("[KeyHistory] Failed to parse %s: %v\n", h.path, err)
+		return
+	}
+	for aid, e := range epochs {
+		h.epochs[aid] = e
+	}
+}
+
+// saveToDisk writes the history. Caller must hold h.mu.
+func (h *KeyHistory) saveToDisk() error {
+	if h.path == "" {
+		return nil
+	}
+	data, err := yaml.Marshal(h.epochs)
+	if err != nil {
+		return fmt.Errorf("marshaling key history: %w", err)
+	}
+	if err := os.WriteFile(h.path, data, 0644); err != nil {
+		return fmt.Errorf("writing key history: %w", err)
+	}
+	return nil
+}
+
+// Record adds state's keys to the history if its establishment event is
+// newer than the last one recorded for the AID
+func (h *KeyHistory) Record(state *KeyState) (*KeyRotation, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rotation := &KeyRotation{AID: state.AID}
+	epochs := h.epochs[state.AID]
+	if n := len(epochs); n > 0 {
+		last := epochs[n-1]
+		if state.KeySequence < last.Sequence {
+			return nil, fmt.Errorf("KEL for %s is behind the recorded establishment event %d", state.AID, last.Sequence)
+		}
+		if state.KeySequence == last.Sequence {
+			rotation.Current = &last
+			return rotation, nil
+		}
+		rotation.Previous = &last
+	}
+
+	epoch := KeyEpoch{
+		Sequence: state.KeySequence,
+		Digest:   state.KeyDigest,
+		Keys:     append([]string(nil), state.Keys...),
+	}
+	if epoch.Sequence > 0 {
+		epoch.Since = h.now().UTC()
+	}
+	h.epochs[state.AID] = append(epochs, epoch)
+	if err := h.saveToDisk(); err != nil {
+		h.epochs[state.AID] = epochs
+		return nil, err
+	}
+	rotation.Current = &epoch
+	rotation.Recorded = true
+	return rotation, nil
+}
+
+// Epochs returns the recorded key epochs for aid, oldest first
+func (h *KeyHistory) Epochs(aid string) []KeyEpoch {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return append([]KeyEpoch(nil), h.epochs[aid]...)
+}
+
+// KeysAt returns the epoch in effect for aid at t. ok is false if nothing
+// is recorded for aid, or t is before the earliest recorded rotation and
+// the keys before it are unknown.
+func (h *KeyHistory) KeysAt(aid string, t time.Time) (epoch KeyEpoch, ok bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, e := range h.epochs[aid] {
+		if e.Since.After(t) {
+			break
+		}
+		epoch, ok = e, true
+	}
+	return epoch, ok
+}
+
+// SetKeyHistory attaches the key history used to check credential
+// signatures against the issuer's keys at issuance
+func (c *Client) SetKeyHistory(history *KeyHistory) {
+	c.keyHistory = history
+}
+
+// RecordKeyState fetches aid's key state from KERIA and records it in the
+// key history
+func (c *Client) RecordKeyState(ctx context.Context, aid string) (*KeyRotation, error) {
+	if c.keyHistory == nil {
+		return nil, fmt.Errorf("no key history configured")
+	}
+	state, err := c.KeyState(ctx, aid)
+	if err != nil {
+		return nil, err
+	}
+	return c.keyHistory.Record(state)
+}
+
+// validateIssuerKeys checks a signed credential was signed with keys its
+// issuer held when it was issued. Only Ed25519 qb64 signatures over the
+// SAID are checked, and only for issuers with a recorded key history.
+func (c *Client) validateIssuerKeys(cred *Credential) error {
+	if c.keyHistory == nil || cred.Signature == "" || cred.Timestamp == "" {
+		return nil
+	}
+	sig, err := DecodeSignature(cred.Signature)
+	if err != nil {
+		return nil
+	}
+	issuedAt, err := time.Parse(time.RFC3339, cred.Timestamp)
+	if err != nil {
+		return fmt.Errorf("timestamp must be an RFC3339 timestamp: %w", err)
+	}
+	epoch, ok := c.keyHistory.KeysAt(cred.Issuer, issuedAt)
+	if !ok {
+		return nil
+	}
+	for _, qb64 := range epoch.Keys {
+		key, err := DecodeVerKey(qb64)
+		if err != nil {
+			continue
+		}
+		if ed25519.Verify(key, []byte(cred.SAID), sig) {
+			return nil
+		}
+	}
+	return fmt.Errorf("credential signature is not from %s's keys at issuance (establishment event %d)", cred.Issuer, epoch.Sequence)
+}
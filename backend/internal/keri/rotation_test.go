@@ -0,0 +1,93 @@
+package keri
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+)
+
+func TestKeyHistory_Record(t *testing.T) {
+	dir := t.TempDir()
+	h := NewKeyHistory(dir)
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return start }
+
+	r, err := h.Record(&KeyState{AID: "EORG", Keys: []string{"DKEY0"}})
+	if err != nil || !r.Recorded || r.Previous != nil || !r.Current.Since.IsZero() {
+		t.Fatalf("expected inception keys recorded from the start, got %+v (%v)", r, err)
+	}
+	if r, _ := h.Record(&KeyState{AID: "EORG", Keys: []string{"DKEY0"}}); r.Recorded {
+		t.Error("recording the same establishment event twice should be a no-op")
+	}
+	r, err = h.Record(&KeyState{AID: "EORG", KeySequence: 3, Keys: []string{"DKEY1"}})
+	if err != nil || !r.Recorded || r.Previous.Sequence != 0 || !r.Current.Since.Equal(start) {
+		t.Fatalf("expected rotation recorded, got %+v (%v)", r, err)
+	}
+	if _, err := h.Record(&KeyState{AID: "EORG", KeySequence: 1, Keys: []string{"DKEY0"}}); err == nil {
+		t.Error("expected error for a KEL behind the history")
+	}
+
+	// Reloaded from disk
+	h = NewKeyHistory(dir)
+	if e, _ := h.KeysAt("EORG", start.Add(-time.Hour)); e.Sequence != 0 {
+		t.Errorf("expected inception keys before the rotation, got %+v", e)
+	}
+	if e, _ := h.KeysAt("EORG", start.Add(time.Hour)); e.Sequence != 3 {
+		t.Errorf("expected rotated keys after the rotation, got %+v", e)
+	}
+	if _, ok := h.KeysAt("EOTHER", start); ok {
+		t.Error("expected no keys for an unrecorded AID")
+	}
+}
+
+func TestClient_ValidateIssuerKeys(t *testing.T) {
+	oldPub, oldPriv, _ := ed25519.GenerateKey(rand.Reader)
+	newPub, newPriv, _ := ed25519.GenerateKey(rand.Reader)
+	rotatedAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	h := NewKeyHistory("")
+	h.Record(&KeyState{AID: "EORG", Keys: []string{EncodeVerKey(oldPub)}})
+	h.now = func() time.Time { return rotatedAt }
+	h.Record(&KeyState{AID: "EORG", KeySequence: 1, Keys: []string{EncodeVerKey(newPub)}})
+
+	client, _ := NewClient(&Config{OrgAID: "EORG"})
+	client.SetKeyHistory(h)
+
+	sign := func(priv ed25519.PrivateKey, at time.Time) *Credential {
+		return &Credential{
+			SAID:      "ESAID",
+			Issuer:    "EORG",
+			Recipient: "EUSER",
+			Schema:    "EMatouMembershipSchemaV1",
+			Data:      CredentialData{Role: "Member"},
+			Signature: encodeQB64(ed25519.Sign(priv, []byte("ESAID")), "0B"),
+			Timestamp: at.Format(time.RFC3339),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		cred    *Credential
+		wantErr bool
+	}{
+		{"old key before rotation", sign(oldPriv, rotatedAt.Add(-time.Hour)), false},
+		{"new key after rotation", sign(newPriv, rotatedAt.Add(time.Hour)), false},
+		{"old key after rotation", sign(oldPriv, rotatedAt.Add(time.Hour)), true},
+		{"new key before rotation", sign(newPriv, rotatedAt.Add(-time.Hour)), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.ValidateCredential(tt.cred)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCredential() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	unsigned := sign(oldPriv, rotatedAt.Add(time.Hour))
+	unsigned.Signature = "..."
+	if err := client.ValidateCredential(unsigned); err != nil {
+		t.Errorf("non-qb64 signatures should not be checked: %v", err)
+	}
+}
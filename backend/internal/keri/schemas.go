@@ -0,0 +1,63 @@
+package keri
+
+import "fmt"
+
+// Credential schemas that aren't membership grants. These carry no role.
+const (
+	EndorsementSchema   = "EMatouEndorsementSchemaV1"
+	AttendanceSchema    = "EMatouAttendanceSchemaV1"
+	ParticipationSchema = "EMatouParticipationSchemaV1"
+)
+
+// IssuedSchemas returns the credential schemas issued within a community,
+// as published in its community descriptor
+func IssuedSchemas() []string {
+	return []string{
+		"EMatouMembershipSchemaV1",
+		"EOperationsStewardSchemaV1",
+		"EInvitationSchemaV1",
+		EndorsementSchema,
+		AttendanceSchema,
+		ParticipationSchema,
+	}
+}
+
+// roleOptionalSchemas lists schemas whose credentials don't need a role
+var roleOptionalSchemas = map[string]bool{
+	EndorsementSchema:   true,
+	AttendanceSchema:    true,
+	ParticipationSchema: true,
+}
+
+// Participation credential kinds
+const (
+	ParticipationAttendance   = "attendance"
+	ParticipationContribution = "contribution"
+)
+
+// ParticipationKinds returns the valid participation credential kinds
+func ParticipationKinds() []string {
+	return []string{ParticipationAttendance, ParticipationContribution}
+}
+
+// IsParticipationKind checks if kind is a valid participation kind
+func IsParticipationKind(kind string) bool {
+	for _, k := range ParticipationKinds() {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// validateParticipation checks a participation credential names its kind
+func validateParticipation(cred *Credential) error {
+	if cred.Schema != ParticipationSchema {
+		return nil
+	}
+	kind, _ := cred.Data.Extra["kind"].(string)
+	if !IsParticipationKind(kind) {
+		return fmt.Errorf("invalid participation kind: %q", kind)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package keri
+
+import (
+	"fmt"
+	"time"
+)
+
+// Attribute types supported in role templates
+const (
+	AttributeTypeString = "string"
+	AttributeTypeNumber = "number"
+	AttributeTypeBool   = "bool"
+	AttributeTypeDate   = "date" // RFC3339 or YYYY-MM-DD
+)
+
+// AttributeTemplate defines one custom attribute allowed on a role credential
+type AttributeTemplate struct {
+	Name        string   `json:"name" yaml:"name"`
+	Type        string   `json:"type" yaml:"type"`
+	Required    bool     `json:"required,omitempty" yaml:"required,omitempty"`
+	Enum        []string `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// RoleTemplate lists the custom attributes a community attaches to a role,
+// e.g. committee and region for "Operations Steward".
+type RoleTemplate struct {
+	Role       string              `json:"role" yaml:"role"`
+	Attributes []AttributeTemplate `json:"attributes" yaml:"attributes"`
+}
+
+// RoleTemplateSource supplies the current role templates. The org config
+// handler implements this so template edits apply without a restart.
+type RoleTemplateSource interface {
+	GetRoleTemplates() []RoleTemplate
+}
+
+// Validate checks that the template itself is well formed
+func (t *RoleTemplate) Validate() error {
+	if !IsValidRole(t.Role) {
+		return fmt.Errorf("template for unknown role: %s", t.Role)
+	}
+	seen := make(map[string]bool)
+	for _, attr := range t.Attributes {
+		if attr.Name == "" {
+			return fmt.Errorf("role %s: attribute name is required", t.Role)
+		}
+		if seen[attr.Name] {
+			return fmt.Errorf("role %s: duplicate attribute %s", t.Role, attr.Name)
+		}
+		seen[attr.Name] = true
+		switch attr.Type {
+		case AttributeTypeString, AttributeTypeNumber, AttributeTypeBool, AttributeTypeDate:
+		default:
+			return fmt.Errorf("role %s: attribute %s has unsupported type %q", t.Role, attr.Name, attr.Type)
+		}
+		if len(attr.Enum) > 0 && attr.Type != AttributeTypeString {
+			return fmt.Errorf("role %s: enum is only supported for string attribute %s", t.Role, attr.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateAttributes checks credential attributes against the template:
+// required attributes are present, values match their declared type, and
+// attributes not in the template are rejected.
+func (t *RoleTemplate) ValidateAttributes(attrs map[string]interface{}) error {
+	byName := make(map[string]AttributeTemplate, len(t.Attributes))
+	for _, attr := range t.Attributes {
+		byName[attr.Name] = attr
+		if _, ok := attrs[attr.Name]; !ok && attr.Required {
+			return fmt.Errorf("attribute %s is required for role %s", attr.Name, t.Role)
+		}
+	}
+
+	for name, value := range attrs {
+		attr, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("attribute %s is not defined for role %s", name, t.Role)
+		}
+		if err := validateAttributeValue(attr, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateAttributeValue checks a single value against its template
+func validateAttributeValue(attr AttributeTemplate, value interface{}) error {
+	switch attr.Type {
+	case AttributeTypeString:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("attribute %s must be a string", attr.Name)
+		}
+		if len(attr.Enum) > 0 {
+			for _, allowed := range attr.Enum {
+				if s == allowed {
+					return nil
+				}
+			}
+			return fmt.Errorf("attribute %s must be one of %v", attr.Name, attr.Enum)
+		}
+	case AttributeTypeNumber:
+		switch value.(type) {
+		case float64, float32, int, int64:
+		default:
+			return fmt.Errorf("attribute %s must be a number", attr.Name)
+		}
+	case AttributeTypeBool:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("attribute %s must be a boolean", attr.Name)
+		}
+	case AttributeTypeDate:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("attribute %s must be a date string", attr.Name)
+		}
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			if _, err := time.Parse("2006-01-02", s); err != nil {
+				return fmt.Errorf("attribute %s must be an RFC3339 or YYYY-MM-DD date", attr.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// SetRoleTemplateSource attaches the source of role attribute templates
+func (c *Client) SetRoleTemplateSource(source RoleTemplateSource) {
+	c.templates = source
+}
+
+// GetRoleTemplate returns the template for a role, or nil if none is defined
+func (c *Client) GetRoleTemplate(role string) *RoleTemplate {
+	if c.templates == nil {
+		return nil
+	}
+	for _, t := range c.templates.GetRoleTemplates() {
+		if t.Role == role {
+			tmpl := t
+			return &tmpl
+		}
+	}
+	return nil
+}
+
+// GetRoleTemplates returns all configured role templates
+func (c *Client) GetRoleTemplates() []RoleTemplate {
+	if c.templates == nil {
+		return []RoleTemplate{}
+	}
+	return c.templates.GetRoleTemplates()
+}
+
+// validateCredentialAttributes applies the role template (if any) to a credential
+func (c *Client) validateCredentialAttributes(cred *Credential) error {
+	tmpl := c.GetRoleTemplate(cred.Data.Role)
+	if tmpl == nil {
+		if len(cred.Data.Attributes) > 0 {
+			return fmt.Errorf("role %s does not accept custom attributes", cred.Data.Role)
+		}
+		return nil
+	}
+	return tmpl.ValidateAttributes(cred.Data.Attributes)
+}
@@ -0,0 +1,105 @@
+package keri
+
+import (
+	"testing"
+)
+
+type staticTemplates []RoleTemplate
+
+func (s staticTemplates) GetRoleTemplates() []RoleTemplate { return s }
+
+func TestRoleTemplate_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    RoleTemplate
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			tmpl: RoleTemplate{Role: "Operations Steward", Attributes: []AttributeTemplate{
+				{Name: "committee", Type: AttributeTypeString, Enum: []string{"finance", "events"}},
+				{Name: "termMonths", Type: AttributeTypeNumber},
+			}},
+		},
+		{
+			name:    "unknown role",
+			tmpl:    RoleTemplate{Role: "Wizard"},
+			wantErr: true,
+		},
+		{
+			name: "duplicate attribute",
+			tmpl: RoleTemplate{Role: "Member", Attributes: []AttributeTemplate{
+				{Name: "region", Type: AttributeTypeString},
+				{Name: "region", Type: AttributeTypeString},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "unsupported type",
+			tmpl: RoleTemplate{Role: "Member", Attributes: []AttributeTemplate{
+				{Name: "region", Type: "object"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "enum on number",
+			tmpl: RoleTemplate{Role: "Member", Attributes: []AttributeTemplate{
+				{Name: "level", Type: AttributeTypeNumber, Enum: []string{"1"}},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tmpl.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCredential_Attributes(t *testing.T) {
+	client, _ := NewClient(&Config{OrgAID: "EAID123456789"})
+	client.SetRoleTemplateSource(staticTemplates{
+		{Role: "Operations Steward", Attributes: []AttributeTemplate{
+			{Name: "committee", Type: AttributeTypeString, Required: true, Enum: []string{"finance", "events"}},
+			{Name: "region", Type: AttributeTypeString},
+			{Name: "termEnds", Type: AttributeTypeDate},
+		}},
+	})
+
+	newCred := func(role string, attrs map[string]interface{}) *Credential {
+		return &Credential{
+			SAID:      "ESAID123",
+			Issuer:    "EAID123456789",
+			Recipient: "ERECIPIENT123",
+			Schema:    "EMatouStewardSchemaV1",
+			Data:      CredentialData{Role: role, Attributes: attrs},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		cred    *Credential
+		wantErr bool
+	}{
+		{"valid attributes", newCred("Operations Steward", map[string]interface{}{"committee": "finance", "termEnds": "2027-06-30"}), false},
+		{"missing required", newCred("Operations Steward", map[string]interface{}{"region": "Waikato"}), true},
+		{"value not in enum", newCred("Operations Steward", map[string]interface{}{"committee": "catering"}), true},
+		{"undefined attribute", newCred("Operations Steward", map[string]interface{}{"committee": "events", "color": "blue"}), true},
+		{"bad date", newCred("Operations Steward", map[string]interface{}{"committee": "events", "termEnds": "next year"}), true},
+		{"no template no attributes", newCred("Member", nil), false},
+		{"no template with attributes", newCred("Member", map[string]interface{}{"region": "Waikato"}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.ValidateCredential(tt.cred)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCredential() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package keri
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/matou-dao/backend/internal/metrics"
+)
+
+// Outcomes of a verification check
+const (
+	CheckPassed  = "passed"
+	CheckFailed  = "failed"
+	CheckSkipped = "skipped" // Not enough information to check
+)
+
+// Names of the verification checks, in the order they run
+const (
+	CheckStructure  = "structure"
+	CheckSchema     = "schema"
+	CheckKeyState   = "keyState"
+	CheckSignature  = "signature"
+	CheckRevocation = "revocation"
+)
+
+// VerificationCheck is the outcome of one check on a credential
+type VerificationCheck struct {
+	Name    string `json:"name"`
+	Outcome string `json:"outcome"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// Verification is the result of verifying a credential. Verified is true
+// when no check failed; skipped checks don't count against it.
+type Verification struct {
+	SAID      string              `json:"said"`
+	Issuer    string              `json:"issuer"`
+	Verified  bool                `json:"verified"`
+	OrgIssued bool                `json:"orgIssued"`
+	Checks    []VerificationCheck `json:"checks"`
+	// KeyState is the issuer's establishment event the signature was
+	// checked against
+	KeyState *KeyEpoch `json:"keyState,omitempty"`
+}
+
+// Check returns the named check, or nil if it didn't run
+func (v *Verification) Check(name string) *VerificationCheck {
+	for i := range v.Checks {
+		if v.Checks[i].Name == name {
+			return &v.Checks[i]
+		}
+	}
+	return nil
+}
+
+func (v *Verification) add(name, outcome, detail string) {
+	v.Checks = append(v.Checks, VerificationCheck{Name: name, Outcome: outcome, Detail: detail})
+}
+
+// SchemaValidator checks credential data against the schema registry.
+// Implemented by the API's schemas handler.
+type SchemaValidator interface {
+	ValidateCredentialData(ctx context.Context, schema string, data json.RawMessage) error
+}
+
+// RevocationSource reports whether a credential's issuer has revoked it in
+// its TEL. Implemented by the API's receipt ledger, where stewards record
+// revocations made through signify-ts.
+type RevocationSource interface {
+	CredentialRevoked(ctx context.Context, said string) (bool, error)
+}
+
+// VerifyOptions supplies the checks the client can't make on its own.
+// Checks without a source are skipped.
+type VerifyOptions struct {
+	Schemas     SchemaValidator
+	Revocations RevocationSource
+	// Data is the credential's data as submitted, for schema validation;
+	// if empty the parsed data is re-encoded
+	Data json.RawMessage
+}
+
+// VerifyCredential checks a credential's structure and schema, the keys
+// its issuer held when it was issued (from the key history, or the KEL on
+// KERIA), its signature over the SAID with those keys, and whether it has
+// been revoked. Every check runs, so the result shows each problem.
+func (c *Client) VerifyCredential(ctx context.Context, cred *Credential, opts VerifyOptions) *Verification {
+	v := &Verification{SAID: cred.SAID, Issuer: cred.Issuer, OrgIssued: c.IsOrgIssued(cred)}
+
+	if err := c.validateStructure(cred); err != nil {
+		v.add(CheckStructure, CheckFailed, err.Error())
+	} else {
+		v.add(CheckStructure, CheckPassed, "")
+	}
+
+	switch {
+	case opts.Schemas == nil:
+		v.add(CheckSchema, CheckSkipped, "no schema registry")
+	default:
+		data := opts.Data
+		if len(data) == 0 {
+			data, _ = json.Marshal(cred.Data)
+		}
+		if err := opts.Schemas.ValidateCredentialData(ctx, cred.Schema, data); err != nil {
+			v.add(CheckSchema, CheckFailed, err.Error())
+		} else {
+			v.add(CheckSchema, CheckPassed, "")
+		}
+	}
+
+	epoch := c.verifyKeyState(ctx, cred, v)
+	v.KeyState = epoch
+	c.verifySignature(cred, epoch, v)
+
+	switch {
+	case opts.Revocations == nil:
+		v.add(CheckRevocation, CheckSkipped, "no revocation source")
+	default:
+		revoked, err := opts.Revocations.CredentialRevoked(ctx, cred.SAID)
+		switch {
+		case err != nil:
+			v.add(CheckRevocation, CheckSkipped, err.Error())
+		case revoked:
+			v.add(CheckRevocation, CheckFailed, "credential has been revoked")
+		default:
+			v.add(CheckRevocation, CheckPassed, "")
+		}
+	}
+
+	v.Verified = true
+	for _, check := range v.Checks {
+		if check.Outcome == CheckFailed {
+			v.Verified = false
+		}
+	}
+	var err error
+	if !v.Verified {
+		err = fmt.Errorf("credential failed verification")
+	}
+	metrics.CountCredentialOperation("verified", err)
+	return v
+}
+
+// verifyKeyState finds the keys the issuer held when the credential was
+// issued. The key history knows when recorded rotations happened; without
+// it, the KEL can only vouch for the issuer's inception keys, which were
+// in force from the start.
+func (c *Client) verifyKeyState(ctx context.Context, cred *Credential, v *Verification) *KeyEpoch {
+	if cred.Issuer == "" {
+		v.add(CheckKeyState, CheckFailed, "credential issuer is required")
+		return nil
+	}
+	var issuedAt time.Time
+	if cred.Timestamp != "" {
+		t, err := time.Parse(time.RFC3339, cred.Timestamp)
+		if err != nil {
+			v.add(CheckKeyState, CheckFailed, "timestamp must be an RFC3339 timestamp")
+			return nil
+		}
+		issuedAt = t
+	}
+
+	if c.keyHistory != nil && !issuedAt.IsZero() {
+		if epoch, ok := c.keyHistory.KeysAt(cred.Issuer, issuedAt); ok {
+			v.add(CheckKeyState, CheckPassed, fmt.Sprintf("keys from establishment event %d, in force at issuance", epoch.Sequence))
+			return &epoch
+		}
+	}
+
+	state, err := c.KeyState(ctx, cred.Issuer)
+	if err != nil {
+		v.add(CheckKeyState, CheckFailed, err.Error())
+		return nil
+	}
+	epoch := &KeyEpoch{Sequence: state.KeySequence, Digest: state.KeyDigest, Keys: state.Keys}
+	switch {
+	case state.KeySequence == 0:
+		v.add(CheckKeyState, CheckPassed, "issuer has not rotated; inception keys in force at issuance")
+	case issuedAt.IsZero():
+		v.add(CheckKeyState, CheckSkipped, fmt.Sprintf("no issuance timestamp; using current keys from establishment event %d", state.KeySequence))
+	default:
+		v.add(CheckKeyState, CheckSkipped, fmt.Sprintf("issuer rotated and the rotation time isn't recorded; using current keys from establishment event %d", state.KeySequence))
+	}
+	return epoch
+}
+
+// verifySignature checks the credential's signature over its SAID with
+// the issuer's keys
+func (c *Client) verifySignature(cred *Credential, epoch *KeyEpoch, v *Verification) {
+	if cred.Signature == "" {
+		v.add(CheckSignature, CheckSkipped, "credential has no signature")
+		return
+	}
+	sig, err := DecodeSignature(cred.Signature)
+	if err != nil {
+		v.add(CheckSignature, CheckSkipped, err.Error())
+		return
+	}
+	if epoch == nil {
+		v.add(CheckSignature, CheckFailed, "issuer keys unknown")
+		return
+	}
+	for _, qb64 := range epoch.Keys {
+		key, err := DecodeVerKey(qb64)
+		if err != nil {
+			continue
+		}
+		if ed25519.Verify(key, []byte(cred.SAID), sig) {
+			v.add(CheckSignature, CheckPassed, "")
+			return
+		}
+	}
+	v.add(CheckSignature, CheckFailed, fmt.Sprintf("signature does not match the issuer's keys from establishment event %d", epoch.Sequence))
+}
@@ -0,0 +1,124 @@
+package keri
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeSchemas struct{ err error }
+
+func (f fakeSchemas) ValidateCredentialData(context.Context, string, json.RawMessage) error {
+	return f.err
+}
+
+type fakeRevocations map[string]bool
+
+func (f fakeRevocations) CredentialRevoked(_ context.Context, said string) (bool, error) {
+	return f[said], nil
+}
+
+func TestClient_VerifyCredential(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	_, otherPriv, _ := ed25519.GenerateKey(rand.Reader)
+	inception := keyEventJSON("icp", "EORG", 0, EncodeVerKey(pub))
+	keria := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oobi/EORG" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(testKEL(inception))
+	}))
+	defer keria.Close()
+	client, _ := NewClient(&Config{OrgAID: "EORG", CESRURL: keria.URL})
+
+	credential := func(priv ed25519.PrivateKey) *Credential {
+		return &Credential{
+			SAID:      "ESAID",
+			Issuer:    "EORG",
+			Recipient: "EUSER",
+			Schema:    "EMatouMembershipSchemaV1",
+			Data:      CredentialData{Role: "Member"},
+			Signature: encodeQB64(ed25519.Sign(priv, []byte("ESAID")), "0B"),
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+		}
+	}
+	opts := VerifyOptions{Schemas: fakeSchemas{}, Revocations: fakeRevocations{}}
+
+	v := client.VerifyCredential(context.Background(), credential(priv), opts)
+	if !v.Verified || !v.OrgIssued || len(v.Checks) != 5 {
+		t.Fatalf("expected verified credential, got %+v", v)
+	}
+	for _, check := range v.Checks {
+		if check.Outcome != CheckPassed {
+			t.Errorf("expected %s to pass, got %+v", check.Name, check)
+		}
+	}
+
+	v = client.VerifyCredential(context.Background(), credential(otherPriv), opts)
+	if v.Verified || v.Check(CheckSignature).Outcome != CheckFailed {
+		t.Errorf("expected signature from other keys to fail, got %+v", v)
+	}
+
+	v = client.VerifyCredential(context.Background(), credential(priv), VerifyOptions{
+		Schemas:     fakeSchemas{err: errors.New("statement: is required")},
+		Revocations: fakeRevocations{"ESAID": true},
+	})
+	if v.Verified || v.Check(CheckSchema).Outcome != CheckFailed || v.Check(CheckRevocation).Outcome != CheckFailed {
+		t.Errorf("expected schema and revocation failures, got %+v", v)
+	}
+
+	unsigned := credential(priv)
+	unsigned.Signature = ""
+	unsigned.Issuer = "EUNKNOWN"
+	v = client.VerifyCredential(context.Background(), unsigned, VerifyOptions{})
+	if v.Verified || v.Check(CheckKeyState).Outcome != CheckFailed || v.Check(CheckSignature).Outcome != CheckSkipped ||
+		v.Check(CheckSchema).Outcome != CheckSkipped || v.Check(CheckRevocation).Outcome != CheckSkipped {
+		t.Errorf("expected unknown issuer to fail key state with other checks skipped, got %+v", v)
+	}
+}
+
+func TestClient_VerifyCredential_RotatedIssuer(t *testing.T) {
+	oldPub, oldPriv, _ := ed25519.GenerateKey(rand.Reader)
+	newPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	keria := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(testKEL(
+			keyEventJSON("icp", "EORG", 0, EncodeVerKey(oldPub)),
+			keyEventJSON("rot", "EORG", 1, EncodeVerKey(newPub)),
+		))
+	}))
+	defer keria.Close()
+	client, _ := NewClient(&Config{OrgAID: "EORG", CESRURL: keria.URL})
+	cred := &Credential{
+		SAID:      "ESAID",
+		Issuer:    "EORG",
+		Recipient: "EUSER",
+		Schema:    "EMatouMembershipSchemaV1",
+		Data:      CredentialData{Role: "Member"},
+		Signature: encodeQB64(ed25519.Sign(oldPriv, []byte("ESAID")), "0B"),
+		Timestamp: "2026-01-01T00:00:00Z",
+	}
+
+	// Without a recorded history only the current keys are known
+	v := client.VerifyCredential(context.Background(), cred, VerifyOptions{})
+	if v.Verified || v.Check(CheckKeyState).Outcome != CheckSkipped {
+		t.Errorf("expected unrecorded rotation to fail the old signature, got %+v", v)
+	}
+
+	history := NewKeyHistory("")
+	history.Record(&KeyState{AID: "EORG", Keys: []string{EncodeVerKey(oldPub)}})
+	history.now = func() time.Time { return time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC) }
+	history.Record(&KeyState{AID: "EORG", KeySequence: 1, Keys: []string{EncodeVerKey(newPub)}})
+	client.SetKeyHistory(history)
+
+	v = client.VerifyCredential(context.Background(), cred, VerifyOptions{})
+	if !v.Verified || v.KeyState.Sequence != 0 {
+		t.Errorf("expected signature checked against the keys at issuance, got %+v", v)
+	}
+}
@@ -0,0 +1,292 @@
+package keri
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Witness is a witness the org AID can use: its non-transferable AID and
+// the URL it serves receipts and OOBIs on
+type Witness struct {
+	AID string `json:"aid" yaml:"aid"`
+	URL string `json:"url" yaml:"url"`
+}
+
+// WitnessPool is the set of witnesses the org AID should have, and how many
+// of them must receipt each event. The org's KEL only changes when the org
+// rotates; the pool is the target that rotation moves towards.
+type WitnessPool struct {
+	Witnesses []Witness `json:"witnesses" yaml:"witnesses"`
+	// Threshold is the receipts needed (the KEL's "toad"); zero means a
+	// majority of the pool
+	Threshold int `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+}
+
+// WitnessPoolSource supplies the org's witness pool. The org config handler
+// implements this so pool edits apply without a restart.
+type WitnessPoolSource interface {
+	GetWitnessPool() *WitnessPool
+}
+
+// EffectiveThreshold returns the receipts the pool requires
+func (p *WitnessPool) EffectiveThreshold() int {
+	if p == nil {
+		return 0
+	}
+	if p.Threshold > 0 {
+		return p.Threshold
+	}
+	if len(p.Witnesses) == 0 {
+		return 0
+	}
+	return len(p.Witnesses)/2 + 1
+}
+
+// Find returns the witness with aid, or nil if it isn't in the pool
+func (p *WitnessPool) Find(aid string) *Witness {
+	if p == nil {
+		return nil
+	}
+	for i := range p.Witnesses {
+		if p.Witnesses[i].AID == aid {
+			return &p.Witnesses[i]
+		}
+	}
+	return nil
+}
+
+// Validate checks each witness has a non-transferable AID and an http(s)
+// URL, none is repeated, and the threshold can be met
+func (p *WitnessPool) Validate() error {
+	if p == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, w := range p.Witnesses {
+		if !strings.HasPrefix(w.AID, "B") || len(w.AID) < 2 {
+			return fmt.Errorf("witness pool: %q is not a non-transferable witness AID", w.AID)
+		}
+		if seen[w.AID] {
+			return fmt.Errorf("witness pool: duplicate witness %s", w.AID)
+		}
+		seen[w.AID] = true
+		u, err := url.Parse(w.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("witness pool: witness %s needs an http(s) URL", w.AID)
+		}
+	}
+	if p.Threshold < 0 || p.Threshold > len(p.Witnesses) {
+		return fmt.Errorf("witness pool: threshold must be between 0 and %d", len(p.Witnesses))
+	}
+	return nil
+}
+
+// WithWitness returns a copy of the pool with w added
+func (p *WitnessPool) WithWitness(w Witness) (*WitnessPool, error) {
+	next := p.clone()
+	if next.Find(w.AID) != nil {
+		return nil, fmt.Errorf("witness %s is already in the pool", w.AID)
+	}
+	next.Witnesses = append(next.Witnesses, w)
+	return next, next.Validate()
+}
+
+// WithoutWitness returns a copy of the pool without aid. A threshold the
+// smaller pool can't meet is lowered to its size.
+func (p *WitnessPool) WithoutWitness(aid string) (*WitnessPool, error) {
+	if p.Find(aid) == nil {
+		return nil, fmt.Errorf("witness %s is not in the pool", aid)
+	}
+	next := p.clone()
+	next.Witnesses = next.Witnesses[:0]
+	for _, w := range p.Witnesses {
+		if w.AID != aid {
+			next.Witnesses = append(next.Witnesses, w)
+		}
+	}
+	if next.Threshold > len(next.Witnesses) {
+		next.Threshold = len(next.Witnesses)
+	}
+	return next, next.Validate()
+}
+
+// WithThreshold returns a copy of the pool requiring threshold receipts
+func (p *WitnessPool) WithThreshold(threshold int) (*WitnessPool, error) {
+	next := p.clone()
+	next.Threshold = threshold
+	return next, next.Validate()
+}
+
+func (p *WitnessPool) clone() *WitnessPool {
+	if p == nil {
+		return &WitnessPool{}
+	}
+	return &WitnessPool{
+		Witnesses: append([]Witness(nil), p.Witnesses...),
+		Threshold: p.Threshold,
+	}
+}
+
+// WitnessRotation is the change a rotation must make to the org AID's
+// witnesses to match the pool. The backend doesn't hold the org's keys, so
+// the org's signify client performs it, e.g.
+// identifiers().rotate(alias, {cuts, adds, toad}).
+type WitnessRotation struct {
+	Cuts      []string `json:"cuts"`
+	Adds      []string `json:"adds"`
+	Threshold int      `json:"toad"`
+	// Needed is false when the KEL already matches the pool
+	Needed bool `json:"needed"`
+}
+
+// PlanWitnessRotation compares the pool with the org AID's current key
+// state
+func PlanWitnessRotation(pool *WitnessPool, state *KeyState) *WitnessRotation {
+	plan := &WitnessRotation{
+		Cuts:      []string{},
+		Adds:      []string{},
+		Threshold: pool.EffectiveThreshold(),
+	}
+	current := make(map[string]bool, len(state.Witnesses))
+	for _, w := range state.Witnesses {
+		current[w] = true
+		if pool.Find(w) == nil {
+			plan.Cuts = append(plan.Cuts, w)
+		}
+	}
+	if pool != nil {
+		for _, w := range pool.Witnesses {
+			if !current[w.AID] {
+				plan.Adds = append(plan.Adds, w.AID)
+			}
+		}
+	}
+	plan.Needed = len(plan.Cuts) > 0 || len(plan.Adds) > 0 || plan.Threshold != state.WitnessThreshold
+	return plan
+}
+
+// WitnessReceipt is whether one witness has receipted the org AID's latest
+// event
+type WitnessReceipt struct {
+	AID       string `json:"aid"`
+	URL       string `json:"url,omitempty"`
+	Receipted bool   `json:"receipted"`
+	Error     string `json:"error,omitempty"`
+}
+
+// WitnessReceipts is the receipt status of the org AID's latest event
+type WitnessReceipts struct {
+	AID       string           `json:"aid"`
+	Sequence  int              `json:"sequence"`
+	Threshold int              `json:"threshold"`
+	Receipted int              `json:"receipted"`
+	Met       bool             `json:"met"` // Receipted >= Threshold
+	Witnesses []WitnessReceipt `json:"witnesses"`
+}
+
+// SetWitnessPoolSource attaches the source of the org's witness pool
+func (c *Client) SetWitnessPoolSource(source WitnessPoolSource) {
+	c.witnesses = source
+}
+
+// GetWitnessPool returns the org's witness pool, or the configured default
+// if the org sets none
+func (c *Client) GetWitnessPool() *WitnessPool {
+	if c.witnesses != nil {
+		if pool := c.witnesses.GetWitnessPool(); pool != nil {
+			return pool
+		}
+	}
+	return c.defaultWitnesses
+}
+
+// OrgKeyState fetches the org AID's current key state from KERIA
+func (c *Client) OrgKeyState(ctx context.Context) (*KeyState, error) {
+	if c.orgAID == "" {
+		return nil, fmt.Errorf("org not configured")
+	}
+	return c.KeyState(ctx, c.orgAID)
+}
+
+// KeyState fetches aid's current key state from KERIA
+func (c *Client) KeyState(ctx context.Context, aid string) (*KeyState, error) {
+	if c.cesrURL == "" {
+		return nil, fmt.Errorf("no CESR URL configured")
+	}
+	stream, err := fetchKEL(ctx, c.httpClient, c.cesrURL+"/oobi/"+url.PathEscape(aid), aid)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKeyState(aid, stream)
+}
+
+// WitnessReceipts asks each of the org AID's current witnesses whether it
+// has receipted the latest event. Witnesses are queried on their
+// /receipts endpoint at the URL the pool gives them; witnesses missing
+// from the pool can't be asked and count as not receipted.
+func (c *Client) WitnessReceipts(ctx context.Context, state *KeyState) *WitnessReceipts {
+	pool := c.GetWitnessPool()
+	status := &WitnessReceipts{
+		AID:       state.AID,
+		Sequence:  state.Sequence,
+		Threshold: state.WitnessThreshold,
+		Witnesses: make([]WitnessReceipt, len(state.Witnesses)),
+	}
+
+	var wg sync.WaitGroup
+	for i, aid := range state.Witnesses {
+		receipt := &status.Witnesses[i]
+		receipt.AID = aid
+		w := pool.Find(aid)
+		if w == nil {
+			receipt.Error = "witness URL unknown (not in the pool)"
+			continue
+		}
+		receipt.URL = w.URL
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.fetchReceipt(ctx, w.URL, state.AID, state.Sequence); err != nil {
+				receipt.Error = err.Error()
+				return
+			}
+			receipt.Receipted = true
+		}()
+	}
+	wg.Wait()
+
+	for _, r := range status.Witnesses {
+		if r.Receipted {
+			status.Receipted++
+		}
+	}
+	status.Met = status.Receipted >= status.Threshold
+	return status
+}
+
+// fetchReceipt asks a witness for its receipt of aid's event at sn
+func (c *Client) fetchReceipt(ctx context.Context, witnessURL, aid string, sn int) error {
+	q := url.Values{"pre": {aid}, "sn": {strconv.Itoa(sn)}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(witnessURL, "/")+"/receipts?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("querying witness: %w", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("no receipt for event %d", sn)
+	default:
+		return fmt.Errorf("witness returned %s", resp.Status)
+	}
+}
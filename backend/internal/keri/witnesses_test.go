@@ -0,0 +1,106 @@
+package keri
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseKeyState_Witnesses(t *testing.T) {
+	stream := testKEL(
+		`{"t":"icp","i":"EORG","s":"0","k":["DKEY0"],"bt":"2","b":["BWAN","BWIL","BWES"]}`,
+		`{"t":"rot","i":"EORG","s":"1","k":["DKEY1"],"bt":"1","br":["BWIL"],"ba":["BWOK"]}`,
+		`{"t":"ixn","i":"EORG","s":"2","a":[]}`,
+	)
+	state, err := ParseKeyState("EORG", stream)
+	if err != nil {
+		t.Fatalf("ParseKeyState failed: %v", err)
+	}
+	if state.WitnessThreshold != 1 || len(state.Witnesses) != 3 || state.Witnesses[1] != "BWES" || state.Witnesses[2] != "BWOK" {
+		t.Errorf("unexpected witnesses %v (threshold %d)", state.Witnesses, state.WitnessThreshold)
+	}
+}
+
+func TestWitnessPool_Edits(t *testing.T) {
+	var pool *WitnessPool
+	pool, err := pool.WithWitness(Witness{AID: "BWAN", URL: "http://witness:5642"})
+	if err != nil {
+		t.Fatalf("WithWitness failed: %v", err)
+	}
+	pool, _ = pool.WithWitness(Witness{AID: "BWIL", URL: "http://witness:5643"})
+	pool, _ = pool.WithWitness(Witness{AID: "BWES", URL: "http://witness:5644"})
+	if pool.EffectiveThreshold() != 2 {
+		t.Errorf("expected majority threshold 2, got %d", pool.EffectiveThreshold())
+	}
+
+	for _, w := range []Witness{
+		{AID: "BWAN", URL: "http://witness:5642"},
+		{AID: "EWAN", URL: "http://witness:5645"},
+		{AID: "BNEW", URL: "witness:5645"},
+	} {
+		if _, err := pool.WithWitness(w); err == nil {
+			t.Errorf("expected %+v rejected", w)
+		}
+	}
+	if _, err := pool.WithThreshold(4); err == nil {
+		t.Error("expected threshold above the pool size rejected")
+	}
+
+	pool, _ = pool.WithThreshold(3)
+	smaller, err := pool.WithoutWitness("BWIL")
+	if err != nil {
+		t.Fatalf("WithoutWitness failed: %v", err)
+	}
+	if len(smaller.Witnesses) != 2 || smaller.Threshold != 2 || len(pool.Witnesses) != 3 {
+		t.Errorf("unexpected pools %+v, %+v", smaller, pool)
+	}
+	if _, err := smaller.WithoutWitness("BWIL"); err == nil {
+		t.Error("expected unknown witness rejected")
+	}
+}
+
+func TestPlanWitnessRotation(t *testing.T) {
+	pool := &WitnessPool{Witnesses: []Witness{
+		{AID: "BWAN", URL: "http://witness:5642"},
+		{AID: "BWOK", URL: "http://witness:5645"},
+	}}
+	state := &KeyState{AID: "EORG", Witnesses: []string{"BWAN", "BWIL"}, WitnessThreshold: 2}
+
+	plan := PlanWitnessRotation(pool, state)
+	if !plan.Needed || len(plan.Cuts) != 1 || plan.Cuts[0] != "BWIL" || len(plan.Adds) != 1 || plan.Adds[0] != "BWOK" || plan.Threshold != 2 {
+		t.Errorf("unexpected plan %+v", plan)
+	}
+
+	state.Witnesses = []string{"BWAN", "BWOK"}
+	if plan := PlanWitnessRotation(pool, state); plan.Needed {
+		t.Errorf("expected no rotation needed, got %+v", plan)
+	}
+}
+
+func TestClient_WitnessReceipts(t *testing.T) {
+	witness := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/receipts" && r.URL.Query().Get("pre") == "EORG" && r.URL.Query().Get("sn") == "1" {
+			w.Write([]byte(`{"t":"rct","i":"EORG","s":"1"}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer witness.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	client, _ := NewClient(&Config{OrgAID: "EORG", Witnesses: &WitnessPool{Witnesses: []Witness{
+		{AID: "BWAN", URL: witness.URL},
+		{AID: "BWIL", URL: down.URL},
+	}}})
+	state := &KeyState{AID: "EORG", Sequence: 1, Witnesses: []string{"BWAN", "BWIL", "BWES"}, WitnessThreshold: 1}
+
+	status := client.WitnessReceipts(context.Background(), state)
+	if status.Receipted != 1 || !status.Met {
+		t.Errorf("expected one receipt meeting the threshold, got %+v", status)
+	}
+	if !status.Witnesses[0].Receipted || status.Witnesses[1].Error == "" || status.Witnesses[2].Error == "" {
+		t.Errorf("unexpected witness status %+v", status.Witnesses)
+	}
+}
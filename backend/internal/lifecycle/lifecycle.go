@@ -0,0 +1,174 @@
+// Package lifecycle runs the backend's HTTP and gRPC servers and shuts
+// the server down gracefully: on SIGINT/SIGTERM (or a server failure) it
+// stops accepting connections, drains in-flight requests, then stops
+// components in the order they were registered.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Server is a server the manager runs and drains, such as *http.Server
+type Server interface {
+	Serve(ln net.Listener) error
+	// Shutdown stops accepting work and waits for what is in flight
+	Shutdown(ctx context.Context) error
+	// Close stops the server at once
+	Close() error
+}
+
+// Manager owns the servers and the shutdown sequence
+type Manager struct {
+	shutdownTimeout time.Duration
+	signals         []os.Signal
+
+	servers    []server
+	components []component
+}
+
+type server struct {
+	name     string
+	srv      Server
+	listener net.Listener
+}
+
+type component struct {
+	name string
+	stop func() error
+}
+
+// NewManager creates a lifecycle manager. shutdownTimeout bounds both the
+// HTTP drain and the component shutdown; 0 waits indefinitely.
+func NewManager(shutdownTimeout time.Duration) *Manager {
+	return &Manager{
+		shutdownTimeout: shutdownTimeout,
+		signals:         []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+	}
+}
+
+// Serve registers a server to run on a listener. Servers are started by
+// Run.
+func (m *Manager) Serve(name string, srv Server, ln net.Listener) {
+	m.servers = append(m.servers, server{name: name, srv: srv, listener: ln})
+}
+
+// OnShutdown registers a component to stop after HTTP connections are
+// drained. Components stop one at a time in registration order, so register
+// consumers before the stores they use.
+func (m *Manager) OnShutdown(name string, stop func() error) {
+	m.components = append(m.components, component{name: name, stop: stop})
+}
+
+// Run starts the registered servers and blocks until a shutdown signal
+// arrives, ctx is cancelled, or a server fails. It then shuts everything
+// down and returns the server failure and any shutdown errors.
+func (m *Manager) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, m.signals...)
+	defer stop()
+
+	serveErr := make(chan error, len(m.servers))
+	for _, s := range m.servers {
+		go func(s server) {
+			// gRPC servers return nil once stopped; HTTP servers return
+			// ErrServerClosed
+			if err := s.srv.Serve(s.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serveErr <- fmt.Errorf("%s: %w", s.name, err)
+			}
+		}(s)
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		fmt.Println("\n[Lifecycle] Shutdown requested")
+	case runErr = <-serveErr:
+		fmt.Printf("[Lifecycle] Server failed: %v\n", runErr)
+	}
+	// A second signal while draining falls back to the default behaviour
+	// (terminate immediately)
+	stop()
+
+	return errors.Join(runErr, m.Shutdown())
+}
+
+// Shutdown drains the HTTP servers, then stops components in order. It
+// keeps going past failures so every component gets a chance to close.
+func (m *Manager) Shutdown() error {
+	var errs []error
+
+	drainCtx, cancel := m.timeoutContext()
+	defer cancel()
+	if err := m.drain(drainCtx); err != nil {
+		errs = append(errs, err)
+	}
+
+	stopCtx, cancel := m.timeoutContext()
+	defer cancel()
+	for _, c := range m.components {
+		if err := stopComponent(stopCtx, c); err != nil {
+			fmt.Printf("[Lifecycle] Failed to stop %s: %v\n", c.name, err)
+			errs = append(errs, fmt.Errorf("stopping %s: %w", c.name, err))
+			continue
+		}
+		fmt.Printf("[Lifecycle] Stopped %s\n", c.name)
+	}
+
+	fmt.Println("[Lifecycle] Shutdown complete")
+	return errors.Join(errs...)
+}
+
+// drain gracefully shuts the servers down in parallel. Connections still
+// open when ctx expires are closed.
+func (m *Manager) drain(ctx context.Context) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, s := range m.servers {
+		wg.Add(1)
+		go func(s server) {
+			defer wg.Done()
+			err := s.srv.Shutdown(ctx)
+			if err != nil {
+				s.srv.Close()
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("draining %s: %w", s.name, err))
+				mu.Unlock()
+				return
+			}
+			fmt.Printf("[Lifecycle] Drained %s\n", s.name)
+		}(s)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// stopComponent runs a component's stop function, giving up when ctx
+// expires so one hung component can't block the rest of the shutdown
+func stopComponent(ctx context.Context, c component) error {
+	done := make(chan error, 1)
+	go func() { done <- c.stop() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *Manager) timeoutContext() (context.Context, context.CancelFunc) {
+	if m.shutdownTimeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), m.shutdownTimeout)
+}
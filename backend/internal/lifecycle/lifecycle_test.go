@@ -0,0 +1,117 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, handler http.Handler) (*http.Server, net.Listener) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	return &http.Server{Handler: handler}, ln
+}
+
+func TestManager_DrainsRequestsThenStopsComponentsInOrder(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv, ln := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	var order []string
+	m := NewManager(5 * time.Second)
+	m.Serve("test", srv, ln)
+	m.OnShutdown("worker", func() error { order = append(order, "worker"); return nil })
+	m.OnShutdown("store", func() error { order = append(order, "store"); return nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- m.Run(ctx) }()
+
+	status := make(chan int, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			status <- 0
+			return
+		}
+		resp.Body.Close()
+		status <- resp.StatusCode
+	}()
+
+	<-started
+	cancel()
+
+	// Components wait for the in-flight request
+	time.Sleep(50 * time.Millisecond)
+	if len(order) != 0 {
+		t.Fatalf("components stopped before requests drained: %v", order)
+	}
+	close(release)
+
+	if code := <-status; code != http.StatusOK {
+		t.Errorf("expected in-flight request to complete with 200, got %d", code)
+	}
+	if err := <-runErr; err != nil {
+		t.Errorf("Run returned error: %v", err)
+	}
+	if strings.Join(order, ",") != "worker,store" {
+		t.Errorf("expected components stopped in order worker,store, got %v", order)
+	}
+}
+
+func TestManager_ServerFailureShutsDown(t *testing.T) {
+	srv, ln := newTestServer(t, http.NotFoundHandler())
+	ln.Close() // Serve fails immediately
+
+	stopped := false
+	m := NewManager(time.Second)
+	m.Serve("broken", srv, ln)
+	m.OnShutdown("store", func() error { stopped = true; return nil })
+
+	err := m.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("expected server failure, got %v", err)
+	}
+	if !stopped {
+		t.Error("expected components to stop after server failure")
+	}
+}
+
+func TestManager_ShutdownContinuesPastFailures(t *testing.T) {
+	stopped := false
+	m := NewManager(time.Second)
+	m.OnShutdown("sdk", func() error { return errors.New("boom") })
+	m.OnShutdown("store", func() error { stopped = true; return nil })
+
+	err := m.Shutdown()
+	if err == nil || !strings.Contains(err.Error(), "stopping sdk") {
+		t.Errorf("expected sdk error, got %v", err)
+	}
+	if !stopped {
+		t.Error("expected store to stop after sdk failure")
+	}
+}
+
+func TestManager_ShutdownTimesOutHungComponent(t *testing.T) {
+	hang := make(chan struct{})
+	defer close(hang)
+
+	m := NewManager(50 * time.Millisecond)
+	m.OnShutdown("hung", func() error { <-hang; return nil })
+
+	if err := m.Shutdown(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"io"
+	"log"
+	"os"
+	"sync"
+)
+
+// Output copies everything the process writes to stdout and stderr into a
+// log file. The server logs with fmt.Print and the log package, and the
+// any-sync SDK logs to stderr, so redirecting the standard streams captures
+// all of it without changing call sites.
+type Output struct {
+	file   io.WriteCloser
+	stdout *os.File
+	stderr *os.File
+	pipes  []*os.File // write ends installed as os.Stdout and os.Stderr
+	wg     sync.WaitGroup
+}
+
+// Redirect replaces os.Stdout and os.Stderr with pipes that copy into file.
+// With echo, output still reaches the original streams too; without it,
+// output only goes to the file.
+func Redirect(file io.WriteCloser, echo bool) (*Output, error) {
+	o := &Output{file: file, stdout: os.Stdout, stderr: os.Stderr}
+
+	streams := []struct {
+		target   **os.File
+		original *os.File
+	}{
+		{&os.Stdout, o.stdout},
+		{&os.Stderr, o.stderr},
+	}
+	for _, s := range streams {
+		r, w, err := os.Pipe()
+		if err != nil {
+			o.restore()
+			return nil, err
+		}
+		dst := &teeWriter{file: file}
+		if echo {
+			dst.echo = s.original
+		}
+		o.wg.Add(1)
+		go func() {
+			defer o.wg.Done()
+			defer r.Close()
+			io.Copy(dst, r)
+		}()
+		o.pipes = append(o.pipes, w)
+		*s.target = w
+	}
+	log.SetOutput(os.Stderr)
+	return o, nil
+}
+
+// Close restores the original streams, flushes what is still in the pipes
+// to the file and closes it
+func (o *Output) Close() error {
+	o.restore()
+	o.wg.Wait()
+	return o.file.Close()
+}
+
+func (o *Output) restore() {
+	os.Stdout = o.stdout
+	os.Stderr = o.stderr
+	log.SetOutput(os.Stderr)
+	for _, w := range o.pipes {
+		w.Close()
+	}
+	o.pipes = nil
+}
+
+// teeWriter writes to the original stream and the log file. Write errors
+// are ignored so a full disk never stops output or blocks the process.
+type teeWriter struct {
+	echo io.Writer
+	file io.Writer
+}
+
+func (t *teeWriter) Write(p []byte) (int, error) {
+	if t.echo != nil {
+		t.echo.Write(p)
+	}
+	t.file.Write(p)
+	return len(p), nil
+}
@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRedirect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "matou.log")
+	f, err := OpenRotatingFile(path, RotateOptions{})
+	if err != nil {
+		t.Fatalf("failed to open log file: %v", err)
+	}
+
+	stdout := os.Stdout
+	out, err := Redirect(f, false)
+	if err != nil {
+		t.Fatalf("redirect failed: %v", err)
+	}
+	fmt.Println("[Test] to stdout")
+	fmt.Fprintln(os.Stderr, "[Test] to stderr")
+	log.Print("[Test] from log")
+	if err := out.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	if os.Stdout != stdout {
+		t.Error("expected stdout restored")
+	}
+	data, _ := os.ReadFile(path)
+	for _, want := range []string{"to stdout", "to stderr", "from log"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in log file, got %q", want, data)
+		}
+	}
+}
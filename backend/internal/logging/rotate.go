@@ -0,0 +1,250 @@
+// Package logging writes the server's output to size- and age-rotated log
+// files alongside stdout.
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the timestamp in rotated file names. It sorts
+// lexically and contains no characters that are awkward in file names.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotateOptions controls when a log file is rotated and which rotated
+// files are kept. Zero values disable the corresponding limit.
+type RotateOptions struct {
+	MaxSize    int64         // Rotate once the file would exceed this many bytes
+	MaxAge     time.Duration // Rotate once the file has been written for this long
+	MaxBackups int           // Rotated files to keep; older ones are deleted
+	Compress   bool          // Gzip rotated files
+}
+
+// RotatingFile is an io.Writer that appends to a log file and rotates it
+// to a timestamped backup (e.g. matou-2026-01-02T15-04-05.000.log) when it
+// grows too large or too old. It is safe for concurrent use.
+type RotatingFile struct {
+	path string
+	opts RotateOptions
+	now  func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	finishing sync.Mutex     // serializes compression and pruning
+	pending   sync.WaitGroup // compression and pruning after rotation
+}
+
+// OpenRotatingFile opens (or creates) the log file at path for appending.
+// An existing file is continued; its age counts from when it was opened.
+func OpenRotatingFile(path string, opts RotateOptions) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, opts: opts, now: time.Now}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p, rotating first if the file is full or too old
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate moves the current file to a backup and starts a new one
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rotate()
+}
+
+// Close closes the current file, waiting for rotated files to be
+// compressed and pruned
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+
+	f.pending.Wait()
+	return err
+}
+
+// shouldRotate reports whether writing n more bytes needs a new file. An
+// empty file is never rotated, so a single oversized write still lands.
+func (f *RotatingFile) shouldRotate(n int64) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.opts.MaxSize > 0 && f.size+n > f.opts.MaxSize {
+		return true
+	}
+	return f.opts.MaxAge > 0 && f.now().Sub(f.openedAt) >= f.opts.MaxAge
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = f.now()
+	return nil
+}
+
+func (f *RotatingFile) rotate() error {
+	if f.file != nil {
+		if err := f.file.Close(); err != nil {
+			return fmt.Errorf("failed to close log file: %w", err)
+		}
+		f.file = nil
+	}
+
+	backup := f.backupName(f.now())
+	if err := os.Rename(f.path, backup); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	// Compression and pruning don't block writers
+	f.pending.Add(1)
+	go func() {
+		defer f.pending.Done()
+		f.finishRotation(backup)
+	}()
+	return nil
+}
+
+// finishRotation compresses the new backup and removes old ones
+func (f *RotatingFile) finishRotation(backup string) {
+	f.finishing.Lock()
+	defer f.finishing.Unlock()
+
+	if f.opts.Compress {
+		// A later rotation may already have pruned this backup
+		if err := compressFile(backup); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "[Logging] Failed to compress %s: %v\n", backup, err)
+		}
+	}
+	if err := f.pruneBackups(); err != nil {
+		fmt.Fprintf(os.Stderr, "[Logging] Failed to prune log backups: %v\n", err)
+	}
+}
+
+// backupName returns the rotated file name for t
+func (f *RotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := f.nameParts()
+	return filepath.Join(dir, prefix+"-"+t.UTC().Format(backupTimeFormat)+ext)
+}
+
+func (f *RotatingFile) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(f.path)
+	base := filepath.Base(f.path)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext), ext
+}
+
+// Backups lists rotated files, oldest first
+func (f *RotatingFile) Backups() ([]string, error) {
+	dir, prefix, ext := f.nameParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var backups []string
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".gz")
+		if e.IsDir() || !strings.HasPrefix(name, prefix+"-") || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix+"-"), ext)
+		if _, err := time.Parse(backupTimeFormat, stamp); err != nil {
+			continue
+		}
+		backups = append(backups, filepath.Join(dir, e.Name()))
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// pruneBackups deletes the oldest backups beyond MaxBackups
+func (f *RotatingFile) pruneBackups() error {
+	if f.opts.MaxBackups <= 0 {
+		return nil
+	}
+	backups, err := f.Backups()
+	if err != nil {
+		return err
+	}
+	for len(backups) > f.opts.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// compressFile gzips path to path.gz and removes the original
+func compressFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		out.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		out.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,123 @@
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a clock that advances by a millisecond per call, so
+// every rotation gets a distinct backup name
+func fakeClock(start time.Time) func() time.Time {
+	now := start
+	return func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+}
+
+func openTestFile(t *testing.T, opts RotateOptions) (*RotatingFile, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "logs", "matou.log")
+	f, err := OpenRotatingFile(path, opts)
+	if err != nil {
+		t.Fatalf("failed to open log file: %v", err)
+	}
+	f.now = fakeClock(time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC))
+	f.openedAt = f.now()
+	return f, path
+}
+
+func TestRotatingFile_SizeRotation(t *testing.T) {
+	f, path := openTestFile(t, RotateOptions{MaxSize: 10})
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	current, _ := os.ReadFile(path)
+	if string(current) != "third\n" {
+		t.Errorf("expected only the last line in the current file, got %q", current)
+	}
+	backups, err := f.Backups()
+	if err != nil || len(backups) != 2 {
+		t.Fatalf("expected 2 backups, got %v (%v)", backups, err)
+	}
+	first, _ := os.ReadFile(backups[0])
+	if string(first) != "first\n" {
+		t.Errorf("expected oldest backup first, got %q", first)
+	}
+	if !strings.HasPrefix(filepath.Base(backups[0]), "matou-2026-01-02T15-04-05.") {
+		t.Errorf("unexpected backup name: %s", backups[0])
+	}
+}
+
+func TestRotatingFile_AgeRotation(t *testing.T) {
+	f, path := openTestFile(t, RotateOptions{MaxAge: time.Hour})
+	defer f.Close()
+
+	f.Write([]byte("yesterday\n"))
+	f.now = fakeClock(time.Date(2026, 1, 3, 15, 4, 5, 0, time.UTC))
+	f.Write([]byte("today\n"))
+
+	current, _ := os.ReadFile(path)
+	if string(current) != "today\n" {
+		t.Errorf("expected a new file after MaxAge, got %q", current)
+	}
+}
+
+func TestRotatingFile_ContinuesExistingFile(t *testing.T) {
+	f, path := openTestFile(t, RotateOptions{MaxSize: 100})
+	f.Write([]byte("before restart\n"))
+	f.Close()
+
+	f, err := OpenRotatingFile(path, RotateOptions{MaxSize: 100})
+	if err != nil {
+		t.Fatalf("failed to reopen: %v", err)
+	}
+	f.Write([]byte("after restart\n"))
+	f.Close()
+
+	current, _ := os.ReadFile(path)
+	if string(current) != "before restart\nafter restart\n" {
+		t.Errorf("expected history kept across restarts, got %q", current)
+	}
+}
+
+func TestRotatingFile_PruneAndCompress(t *testing.T) {
+	f, _ := openTestFile(t, RotateOptions{MaxSize: 8, MaxBackups: 2, Compress: true})
+
+	for i := 0; i < 5; i++ {
+		fmt.Fprintf(f, "line %d\n", i)
+	}
+	f.Close()
+
+	backups, err := f.Backups()
+	if err != nil || len(backups) != 2 {
+		t.Fatalf("expected 2 backups kept, got %v (%v)", backups, err)
+	}
+	if !strings.HasSuffix(backups[1], ".log.gz") {
+		t.Fatalf("expected compressed backups, got %s", backups[1])
+	}
+
+	file, _ := os.Open(backups[1])
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("backup is not gzip: %v", err)
+	}
+	data, _ := io.ReadAll(gz)
+	if string(data) != "line 3\n" {
+		t.Errorf("unexpected newest backup content %q", data)
+	}
+}
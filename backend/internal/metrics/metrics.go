@@ -0,0 +1,220 @@
+// Package metrics exposes Prometheus metrics for the backend: HTTP latency
+// per route and shed requests, any-sync space operations, credential
+// verification and issuance, coordinator reachability, anystore query
+// times and the file block cache.
+//
+// Metrics are registered on a package registry served by Handler, so they
+// can be recorded from any package without threading a collector through
+// constructors.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "matou"
+
+// Results recorded on operation counters
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route pattern, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	spaceOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "anysync",
+		Name:      "space_operations_total",
+		Help:      "any-sync space operations by operation and result.",
+	}, []string{"operation", "result"})
+
+	spaceOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "anysync",
+		Name:      "space_operation_duration_seconds",
+		Help:      "any-sync space operation latency by operation.",
+		// Space creation and ACL changes round-trip to the coordinator and
+		// consensus nodes, so they run well past the default buckets
+		Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"operation"})
+
+	credentialOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "credentials",
+		Name:      "operations_total",
+		Help:      "Credential verifications, issuances and revocations by result.",
+	}, []string{"operation", "result"})
+
+	coordinatorPingFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "anysync",
+		Name:      "coordinator_ping_failures_total",
+		Help:      "Failed connectivity checks against the any-sync coordinator.",
+	})
+
+	syncProbeLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "anysync",
+		Name:      "sync_probe_latency_seconds",
+		Help:      "Time for a sync test probe to reach a tree node or peer backend.",
+		Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"target"})
+
+	shedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "shed_requests_total",
+		Help:      "Expensive requests turned away under load, by route and outcome (stale or rejected).",
+	}, []string{"route", "outcome"})
+
+	storeQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "anystore",
+		Name:      "query_duration_seconds",
+		Help:      "Local anystore query latency by operation.",
+		Buckets:   []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1},
+	}, []string{"operation"})
+
+	blockCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "files",
+		Name:      "block_cache_lookups_total",
+		Help:      "File block cache lookups by result (hit or miss).",
+	}, []string{"result"})
+
+	blockCacheEvictions = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "files",
+		Name:      "block_cache_evictions_total",
+		Help:      "File blocks evicted from the cache to stay under its size limit.",
+	})
+
+	blockCacheBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "files",
+		Name:      "block_cache_bytes",
+		Help:      "Bytes of file blocks held in the cache.",
+	})
+
+	blockCacheBlocks = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "files",
+		Name:      "block_cache_blocks",
+		Help:      "File blocks held in the cache.",
+	})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		spaceOperations,
+		spaceOperationDuration,
+		credentialOperations,
+		coordinatorPingFailures,
+		syncProbeLatency,
+		shedRequests,
+		storeQueryDuration,
+		blockCacheLookups,
+		blockCacheEvictions,
+		blockCacheBytes,
+		blockCacheBlocks,
+	)
+}
+
+// Handler serves the registered metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest records a served request. route must be the matched
+// route pattern, not the raw path, to keep label cardinality bounded.
+func ObserveHTTPRequest(route, method string, status int, duration time.Duration) {
+	httpRequestDuration.WithLabelValues(route, method, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// CountShedRequest records a request turned away by load shedding: "stale"
+// if a cached response was served, "rejected" if it got a 503
+func CountShedRequest(route, outcome string) {
+	shedRequests.WithLabelValues(route, outcome).Inc()
+}
+
+// ObserveSpaceOperation records an any-sync space operation that started at
+// start. Intended for use with defer and a named error return:
+//
+//	defer metrics.ObserveSpaceOperation("create_space", time.Now(), &err)
+func ObserveSpaceOperation(operation string, start time.Time, err *error) {
+	spaceOperationDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	spaceOperations.WithLabelValues(operation, result(err)).Inc()
+}
+
+// CountCredentialOperation records a credential operation: "verified", a
+// receipt action ("issued", "delivered", "acknowledged", "cached") or
+// "revoked"
+func CountCredentialOperation(operation string, err error) {
+	credentialOperations.WithLabelValues(operation, result(&err)).Inc()
+}
+
+// CountCoordinatorPingFailure records a failed coordinator connectivity check
+func CountCoordinatorPingFailure() {
+	coordinatorPingFailures.Inc()
+}
+
+// ObserveSyncProbe records how long a sync test probe took to reach a
+// target: "node" for a responsible tree node, "peer" for a peer backend
+func ObserveSyncProbe(target string, latency time.Duration) {
+	syncProbeLatency.WithLabelValues(target).Observe(latency.Seconds())
+}
+
+// ObserveStoreQuery records an anystore query that started at start.
+// Intended for use with defer:
+//
+//	defer metrics.ObserveStoreQuery("get_credential", time.Now())
+func ObserveStoreQuery(operation string, start time.Time) {
+	storeQueryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// CountBlockCacheLookup records a file block cache lookup
+func CountBlockCacheLookup(hit bool) {
+	if hit {
+		blockCacheLookups.WithLabelValues("hit").Inc()
+	} else {
+		blockCacheLookups.WithLabelValues("miss").Inc()
+	}
+}
+
+// CountBlockCacheEviction records a block evicted from the file block cache
+func CountBlockCacheEviction() {
+	blockCacheEvictions.Inc()
+}
+
+// SetBlockCacheSize records how many blocks and bytes the file block cache
+// holds
+func SetBlockCacheSize(blocks int, bytes int64) {
+	blockCacheBlocks.Set(float64(blocks))
+	blockCacheBytes.Set(float64(bytes))
+}
+
+func result(err *error) string {
+	if err != nil && *err != nil {
+		return ResultError
+	}
+	return ResultSuccess
+}
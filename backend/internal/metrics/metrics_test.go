@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func scrape(t *testing.T) string {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(w.Body)
+	if err != nil {
+		t.Fatalf("reading metrics: %v", err)
+	}
+	return string(body)
+}
+
+func TestHandler_ExposesRecordedMetrics(t *testing.T) {
+	ObserveHTTPRequest("/api/v1/profiles/", "GET", 200, 20*time.Millisecond)
+	func() (err error) {
+		defer ObserveSpaceOperation("create_space", time.Now(), &err)
+		return errors.New("coordinator down")
+	}()
+	CountCredentialOperation("verified", nil)
+	CountCoordinatorPingFailure()
+	ObserveStoreQuery("get_credential", time.Now())
+	CountShedRequest("/api/v1/trust/graph", "stale")
+	CountBlockCacheLookup(true)
+	SetBlockCacheSize(3, 4096)
+
+	body := scrape(t)
+	for _, want := range []string{
+		`matou_http_request_duration_seconds_count{method="GET",route="/api/v1/profiles/",status="200"} 1`,
+		`matou_anysync_space_operations_total{operation="create_space",result="error"} 1`,
+		`matou_anysync_space_operation_duration_seconds_count{operation="create_space"} 1`,
+		`matou_credentials_operations_total{operation="verified",result="success"} 1`,
+		`matou_anysync_coordinator_ping_failures_total 1`,
+		`matou_anystore_query_duration_seconds_count{operation="get_credential"} 1`,
+		`matou_http_shed_requests_total{outcome="stale",route="/api/v1/trust/graph"} 1`,
+		`matou_files_block_cache_lookups_total{result="hit"} 1`,
+		`matou_files_block_cache_bytes 4096`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+// Package outbound configures the backend's outbound connections: the
+// proxy they go through and the CAs they trust. Every HTTP client in the
+// backend uses http.DefaultTransport, so Install configures KERIA, OOBI,
+// descriptor and sync-test requests in one place; SMTP dials through Dial.
+package outbound
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/config"
+)
+
+// maxResponseHead bounds a proxy's CONNECT response headers
+const maxResponseHead = 16 << 10
+
+// Settings are the resolved outbound proxy and trusted CAs
+type Settings struct {
+	proxy func(*http.Request) (*url.URL, error)
+	roots *x509.CertPool // nil means the system pool
+}
+
+// New resolves cfg. Without a configured proxy, the standard HTTPS_PROXY,
+// HTTP_PROXY and NO_PROXY environment variables apply.
+func New(cfg config.OutboundConfig) (*Settings, error) {
+	s := &Settings{proxy: http.ProxyFromEnvironment}
+
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid outbound proxy: %w", err)
+		}
+		bypass := ParseNoProxy(cfg.NoProxy)
+		s.proxy = func(r *http.Request) (*url.URL, error) {
+			if bypass.Match(r.URL.Host) {
+				return nil, nil
+			}
+			return proxyURL, nil
+		}
+	}
+
+	if cfg.CABundle != "" {
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %w", err)
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil || roots == nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s has no PEM certificates", cfg.CABundle)
+		}
+		s.roots = roots
+	}
+	return s, nil
+}
+
+// Transport returns an HTTP transport using the proxy and CAs
+func (s *Settings) Transport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = s.proxy
+	if s.roots != nil {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.RootCAs = s.roots
+	}
+	return t
+}
+
+// Install makes Transport the default for every HTTP client that doesn't
+// set its own
+func (s *Settings) Install() {
+	http.DefaultTransport = s.Transport()
+}
+
+// ProxyFor returns the proxy a connection to addr (host:port) goes
+// through, or nil for a direct connection
+func (s *Settings) ProxyFor(addr string) (*url.URL, error) {
+	req := &http.Request{URL: &url.URL{Scheme: "https", Host: addr}, Header: http.Header{}}
+	return s.proxy(req)
+}
+
+// Dial connects to addr, tunnelling through the proxy with HTTP CONNECT
+// when one applies. It is for protocols other than HTTP, such as SMTP.
+func (s *Settings) Dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	var d net.Dialer
+	proxyURL, err := s.ProxyFor(addr)
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL == nil {
+		return d.DialContext(ctx, network, addr)
+	}
+
+	proxyAddr := proxyURL.Host
+	if proxyURL.Port() == "" {
+		switch proxyURL.Scheme {
+		case "http":
+			proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "80")
+		case "https":
+			proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "443")
+		}
+	}
+	if proxyURL.Scheme != "http" && proxyURL.Scheme != "https" {
+		return nil, fmt.Errorf("proxy scheme %q can't tunnel %s (use an http or https proxy)", proxyURL.Scheme, addr)
+	}
+
+	conn, err := d.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to proxy: %w", err)
+	}
+	if proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname(), RootCAs: s.roots})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("proxy TLS handshake: %w", err)
+		}
+		conn = tlsConn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	connect := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		connect.SetBasicAuth(user.Username(), password)
+		connect.Header.Set("Proxy-Authorization", connect.Header.Get("Authorization"))
+		connect.Header.Del("Authorization")
+	}
+	if err := connect.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT: %w", err)
+	}
+	// The tunnel carries the target protocol once the proxy answers, so
+	// read no further than the response headers
+	head, err := readResponseHead(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT: %w", err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(head)), connect)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT to %s: %s", addr, resp.Status)
+	}
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// readResponseHead reads an HTTP response up to the blank line ending its
+// headers, one byte at a time so nothing after it is consumed
+func readResponseHead(conn net.Conn) ([]byte, error) {
+	var head []byte
+	b := make([]byte, 1)
+	for !bytes.HasSuffix(head, []byte("\r\n\r\n")) {
+		if len(head) > maxResponseHead {
+			return nil, fmt.Errorf("response headers too long")
+		}
+		if _, err := conn.Read(b); err != nil {
+			return nil, err
+		}
+		head = append(head, b[0])
+	}
+	return head, nil
+}
+
+// Redact returns proxyURL with any password masked, for logging
+func Redact(proxyURL string) string {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return proxyURL
+	}
+	return u.Redacted()
+}
+
+// NoProxy is a list of hosts that bypass the configured proxy
+type NoProxy []string
+
+// ParseNoProxy parses a comma-separated NO_PROXY style list: "*", host
+// names (matching subdomains too, with or without a leading "."), IPs and
+// CIDR ranges
+func ParseNoProxy(list string) NoProxy {
+	var entries NoProxy
+	for _, e := range strings.Split(list, ",") {
+		if e = strings.ToLower(strings.TrimSpace(e)); e != "" {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Match reports whether host (optionally with a port) bypasses the proxy.
+// Loopback addresses always do, so local KERIA and SMTP relays stay direct.
+func (n NoProxy) Match(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+	ip := net.ParseIP(host)
+	if host == "localhost" || (ip != nil && ip.IsLoopback()) {
+		return true
+	}
+
+	for _, e := range n {
+		switch {
+		case e == "*":
+			return true
+		case strings.Contains(e, "/"):
+			if _, cidr, err := net.ParseCIDR(e); err == nil && ip != nil && cidr.Contains(ip) {
+				return true
+			}
+		default:
+			if h, _, err := net.SplitHostPort(e); err == nil {
+				e = h
+			}
+			domain := strings.TrimPrefix(e, ".")
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+		}
+	}
+	return false
+}
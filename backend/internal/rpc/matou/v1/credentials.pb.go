@@ -0,0 +1,912 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: matou/v1/credentials.proto
+
+package matouv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Credential is an ACDC credential as the backend caches it.
+type Credential struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Said      string                 `protobuf:"bytes,1,opt,name=said,proto3" json:"said,omitempty"`
+	Issuer    string                 `protobuf:"bytes,2,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	Recipient string                 `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Schema    string                 `protobuf:"bytes,4,opt,name=schema,proto3" json:"schema,omitempty"`
+	// Data is the credential's attributes, shaped by its schema.
+	Data          *structpb.Struct `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	Signature     string           `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	Timestamp     string           `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Credential) Reset() {
+	*x = Credential{}
+	mi := &file_matou_v1_credentials_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Credential) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Credential) ProtoMessage() {}
+
+func (x *Credential) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_credentials_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Credential.ProtoReflect.Descriptor instead.
+func (*Credential) Descriptor() ([]byte, []int) {
+	return file_matou_v1_credentials_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Credential) GetSaid() string {
+	if x != nil {
+		return x.Said
+	}
+	return ""
+}
+
+func (x *Credential) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *Credential) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *Credential) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+func (x *Credential) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Credential) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *Credential) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+type StoreCredentialRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Credential    *Credential            `protobuf:"bytes,1,opt,name=credential,proto3" json:"credential,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreCredentialRequest) Reset() {
+	*x = StoreCredentialRequest{}
+	mi := &file_matou_v1_credentials_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreCredentialRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreCredentialRequest) ProtoMessage() {}
+
+func (x *StoreCredentialRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_credentials_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreCredentialRequest.ProtoReflect.Descriptor instead.
+func (*StoreCredentialRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_credentials_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StoreCredentialRequest) GetCredential() *Credential {
+	if x != nil {
+		return x.Credential
+	}
+	return nil
+}
+
+type StoreCredentialResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Said          string                 `protobuf:"bytes,2,opt,name=said,proto3" json:"said,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreCredentialResponse) Reset() {
+	*x = StoreCredentialResponse{}
+	mi := &file_matou_v1_credentials_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreCredentialResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreCredentialResponse) ProtoMessage() {}
+
+func (x *StoreCredentialResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_credentials_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreCredentialResponse.ProtoReflect.Descriptor instead.
+func (*StoreCredentialResponse) Descriptor() ([]byte, []int) {
+	return file_matou_v1_credentials_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StoreCredentialResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *StoreCredentialResponse) GetSaid() string {
+	if x != nil {
+		return x.Said
+	}
+	return ""
+}
+
+type GetCredentialRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Said          string                 `protobuf:"bytes,1,opt,name=said,proto3" json:"said,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCredentialRequest) Reset() {
+	*x = GetCredentialRequest{}
+	mi := &file_matou_v1_credentials_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCredentialRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCredentialRequest) ProtoMessage() {}
+
+func (x *GetCredentialRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_credentials_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCredentialRequest.ProtoReflect.Descriptor instead.
+func (*GetCredentialRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_credentials_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetCredentialRequest) GetSaid() string {
+	if x != nil {
+		return x.Said
+	}
+	return ""
+}
+
+type GetCredentialResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Credential    *Credential            `protobuf:"bytes,1,opt,name=credential,proto3" json:"credential,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCredentialResponse) Reset() {
+	*x = GetCredentialResponse{}
+	mi := &file_matou_v1_credentials_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCredentialResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCredentialResponse) ProtoMessage() {}
+
+func (x *GetCredentialResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_credentials_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCredentialResponse.ProtoReflect.Descriptor instead.
+func (*GetCredentialResponse) Descriptor() ([]byte, []int) {
+	return file_matou_v1_credentials_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetCredentialResponse) GetCredential() *Credential {
+	if x != nil {
+		return x.Credential
+	}
+	return nil
+}
+
+type ListCredentialsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCredentialsRequest) Reset() {
+	*x = ListCredentialsRequest{}
+	mi := &file_matou_v1_credentials_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCredentialsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCredentialsRequest) ProtoMessage() {}
+
+func (x *ListCredentialsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_credentials_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCredentialsRequest.ProtoReflect.Descriptor instead.
+func (*ListCredentialsRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_credentials_proto_rawDescGZIP(), []int{5}
+}
+
+type ListCredentialsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Credentials   []*Credential          `protobuf:"bytes,1,rep,name=credentials,proto3" json:"credentials,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCredentialsResponse) Reset() {
+	*x = ListCredentialsResponse{}
+	mi := &file_matou_v1_credentials_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCredentialsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCredentialsResponse) ProtoMessage() {}
+
+func (x *ListCredentialsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_credentials_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCredentialsResponse.ProtoReflect.Descriptor instead.
+func (*ListCredentialsResponse) Descriptor() ([]byte, []int) {
+	return file_matou_v1_credentials_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListCredentialsResponse) GetCredentials() []*Credential {
+	if x != nil {
+		return x.Credentials
+	}
+	return nil
+}
+
+func (x *ListCredentialsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type ValidateCredentialRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Credential is the credential as KERIA returns it.
+	Credential    *structpb.Struct `protobuf:"bytes,1,opt,name=credential,proto3" json:"credential,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateCredentialRequest) Reset() {
+	*x = ValidateCredentialRequest{}
+	mi := &file_matou_v1_credentials_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateCredentialRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateCredentialRequest) ProtoMessage() {}
+
+func (x *ValidateCredentialRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_credentials_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateCredentialRequest.ProtoReflect.Descriptor instead.
+func (*ValidateCredentialRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_credentials_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ValidateCredentialRequest) GetCredential() *structpb.Struct {
+	if x != nil {
+		return x.Credential
+	}
+	return nil
+}
+
+type ValidateCredentialResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Valid     bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	OrgIssued bool                   `protobuf:"varint,2,opt,name=org_issued,json=orgIssued,proto3" json:"org_issued,omitempty"`
+	Role      string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	// Error says why an invalid credential failed.
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateCredentialResponse) Reset() {
+	*x = ValidateCredentialResponse{}
+	mi := &file_matou_v1_credentials_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateCredentialResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateCredentialResponse) ProtoMessage() {}
+
+func (x *ValidateCredentialResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_credentials_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateCredentialResponse.ProtoReflect.Descriptor instead.
+func (*ValidateCredentialResponse) Descriptor() ([]byte, []int) {
+	return file_matou_v1_credentials_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ValidateCredentialResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateCredentialResponse) GetOrgIssued() bool {
+	if x != nil {
+		return x.OrgIssued
+	}
+	return false
+}
+
+func (x *ValidateCredentialResponse) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ValidateCredentialResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type VerifyCredentialRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Credential    *structpb.Struct       `protobuf:"bytes,1,opt,name=credential,proto3" json:"credential,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyCredentialRequest) Reset() {
+	*x = VerifyCredentialRequest{}
+	mi := &file_matou_v1_credentials_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyCredentialRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyCredentialRequest) ProtoMessage() {}
+
+func (x *VerifyCredentialRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_credentials_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyCredentialRequest.ProtoReflect.Descriptor instead.
+func (*VerifyCredentialRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_credentials_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *VerifyCredentialRequest) GetCredential() *structpb.Struct {
+	if x != nil {
+		return x.Credential
+	}
+	return nil
+}
+
+type Verification struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Said   string                 `protobuf:"bytes,1,opt,name=said,proto3" json:"said,omitempty"`
+	Issuer string                 `protobuf:"bytes,2,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	// Verified is true when no check failed.
+	Verified  bool                 `protobuf:"varint,3,opt,name=verified,proto3" json:"verified,omitempty"`
+	OrgIssued bool                 `protobuf:"varint,4,opt,name=org_issued,json=orgIssued,proto3" json:"org_issued,omitempty"`
+	Checks    []*VerificationCheck `protobuf:"bytes,5,rep,name=checks,proto3" json:"checks,omitempty"`
+	// KeyState is the issuer establishment event the signature was checked
+	// against.
+	KeyState      *KeyEpoch `protobuf:"bytes,6,opt,name=key_state,json=keyState,proto3" json:"key_state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Verification) Reset() {
+	*x = Verification{}
+	mi := &file_matou_v1_credentials_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Verification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Verification) ProtoMessage() {}
+
+func (x *Verification) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_credentials_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Verification.ProtoReflect.Descriptor instead.
+func (*Verification) Descriptor() ([]byte, []int) {
+	return file_matou_v1_credentials_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Verification) GetSaid() string {
+	if x != nil {
+		return x.Said
+	}
+	return ""
+}
+
+func (x *Verification) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *Verification) GetVerified() bool {
+	if x != nil {
+		return x.Verified
+	}
+	return false
+}
+
+func (x *Verification) GetOrgIssued() bool {
+	if x != nil {
+		return x.OrgIssued
+	}
+	return false
+}
+
+func (x *Verification) GetChecks() []*VerificationCheck {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+func (x *Verification) GetKeyState() *KeyEpoch {
+	if x != nil {
+		return x.KeyState
+	}
+	return nil
+}
+
+type VerificationCheck struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Outcome is passed, failed or skipped.
+	Outcome       string `protobuf:"bytes,2,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	Detail        string `protobuf:"bytes,3,opt,name=detail,proto3" json:"detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerificationCheck) Reset() {
+	*x = VerificationCheck{}
+	mi := &file_matou_v1_credentials_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerificationCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerificationCheck) ProtoMessage() {}
+
+func (x *VerificationCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_credentials_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerificationCheck.ProtoReflect.Descriptor instead.
+func (*VerificationCheck) Descriptor() ([]byte, []int) {
+	return file_matou_v1_credentials_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *VerificationCheck) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *VerificationCheck) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *VerificationCheck) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+type KeyEpoch struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Sequence int32                  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Digest   string                 `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+	Keys     []string               `protobuf:"bytes,3,rep,name=keys,proto3" json:"keys,omitempty"`
+	// Since is when the keys took effect (RFC 3339).
+	Since         string `protobuf:"bytes,4,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyEpoch) Reset() {
+	*x = KeyEpoch{}
+	mi := &file_matou_v1_credentials_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyEpoch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyEpoch) ProtoMessage() {}
+
+func (x *KeyEpoch) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_credentials_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyEpoch.ProtoReflect.Descriptor instead.
+func (*KeyEpoch) Descriptor() ([]byte, []int) {
+	return file_matou_v1_credentials_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *KeyEpoch) GetSequence() int32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *KeyEpoch) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+func (x *KeyEpoch) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *KeyEpoch) GetSince() string {
+	if x != nil {
+		return x.Since
+	}
+	return ""
+}
+
+var File_matou_v1_credentials_proto protoreflect.FileDescriptor
+
+const file_matou_v1_credentials_proto_rawDesc = "" +
+	"\n" +
+	"\x1amatou/v1/credentials.proto\x12\bmatou.v1\x1a\x1cgoogle/protobuf/struct.proto\"\xd7\x01\n" +
+	"\n" +
+	"Credential\x12\x12\n" +
+	"\x04said\x18\x01 \x01(\tR\x04said\x12\x16\n" +
+	"\x06issuer\x18\x02 \x01(\tR\x06issuer\x12\x1c\n" +
+	"\trecipient\x18\x03 \x01(\tR\trecipient\x12\x16\n" +
+	"\x06schema\x18\x04 \x01(\tR\x06schema\x12+\n" +
+	"\x04data\x18\x05 \x01(\v2\x17.google.protobuf.StructR\x04data\x12\x1c\n" +
+	"\tsignature\x18\x06 \x01(\tR\tsignature\x12\x1c\n" +
+	"\ttimestamp\x18\a \x01(\tR\ttimestamp\"N\n" +
+	"\x16StoreCredentialRequest\x124\n" +
+	"\n" +
+	"credential\x18\x01 \x01(\v2\x14.matou.v1.CredentialR\n" +
+	"credential\"G\n" +
+	"\x17StoreCredentialResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x12\n" +
+	"\x04said\x18\x02 \x01(\tR\x04said\"*\n" +
+	"\x14GetCredentialRequest\x12\x12\n" +
+	"\x04said\x18\x01 \x01(\tR\x04said\"M\n" +
+	"\x15GetCredentialResponse\x124\n" +
+	"\n" +
+	"credential\x18\x01 \x01(\v2\x14.matou.v1.CredentialR\n" +
+	"credential\"\x18\n" +
+	"\x16ListCredentialsRequest\"g\n" +
+	"\x17ListCredentialsResponse\x126\n" +
+	"\vcredentials\x18\x01 \x03(\v2\x14.matou.v1.CredentialR\vcredentials\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"T\n" +
+	"\x19ValidateCredentialRequest\x127\n" +
+	"\n" +
+	"credential\x18\x01 \x01(\v2\x17.google.protobuf.StructR\n" +
+	"credential\"{\n" +
+	"\x1aValidateCredentialResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x1d\n" +
+	"\n" +
+	"org_issued\x18\x02 \x01(\bR\torgIssued\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"R\n" +
+	"\x17VerifyCredentialRequest\x127\n" +
+	"\n" +
+	"credential\x18\x01 \x01(\v2\x17.google.protobuf.StructR\n" +
+	"credential\"\xdb\x01\n" +
+	"\fVerification\x12\x12\n" +
+	"\x04said\x18\x01 \x01(\tR\x04said\x12\x16\n" +
+	"\x06issuer\x18\x02 \x01(\tR\x06issuer\x12\x1a\n" +
+	"\bverified\x18\x03 \x01(\bR\bverified\x12\x1d\n" +
+	"\n" +
+	"org_issued\x18\x04 \x01(\bR\torgIssued\x123\n" +
+	"\x06checks\x18\x05 \x03(\v2\x1b.matou.v1.VerificationCheckR\x06checks\x12/\n" +
+	"\tkey_state\x18\x06 \x01(\v2\x12.matou.v1.KeyEpochR\bkeyState\"Y\n" +
+	"\x11VerificationCheck\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aoutcome\x18\x02 \x01(\tR\aoutcome\x12\x16\n" +
+	"\x06detail\x18\x03 \x01(\tR\x06detail\"h\n" +
+	"\bKeyEpoch\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x05R\bsequence\x12\x16\n" +
+	"\x06digest\x18\x02 \x01(\tR\x06digest\x12\x12\n" +
+	"\x04keys\x18\x03 \x03(\tR\x04keys\x12\x14\n" +
+	"\x05since\x18\x04 \x01(\tR\x05since2\xc5\x03\n" +
+	"\x11CredentialService\x12V\n" +
+	"\x0fStoreCredential\x12 .matou.v1.StoreCredentialRequest\x1a!.matou.v1.StoreCredentialResponse\x12P\n" +
+	"\rGetCredential\x12\x1e.matou.v1.GetCredentialRequest\x1a\x1f.matou.v1.GetCredentialResponse\x12V\n" +
+	"\x0fListCredentials\x12 .matou.v1.ListCredentialsRequest\x1a!.matou.v1.ListCredentialsResponse\x12_\n" +
+	"\x12ValidateCredential\x12#.matou.v1.ValidateCredentialRequest\x1a$.matou.v1.ValidateCredentialResponse\x12M\n" +
+	"\x10VerifyCredential\x12!.matou.v1.VerifyCredentialRequest\x1a\x16.matou.v1.VerificationB<Z:github.com/matou-dao/backend/internal/rpc/matou/v1;matouv1b\x06proto3"
+
+var (
+	file_matou_v1_credentials_proto_rawDescOnce sync.Once
+	file_matou_v1_credentials_proto_rawDescData []byte
+)
+
+func file_matou_v1_credentials_proto_rawDescGZIP() []byte {
+	file_matou_v1_credentials_proto_rawDescOnce.Do(func() {
+		file_matou_v1_credentials_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_matou_v1_credentials_proto_rawDesc), len(file_matou_v1_credentials_proto_rawDesc)))
+	})
+	return file_matou_v1_credentials_proto_rawDescData
+}
+
+var file_matou_v1_credentials_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_matou_v1_credentials_proto_goTypes = []any{
+	(*Credential)(nil),                 // 0: matou.v1.Credential
+	(*StoreCredentialRequest)(nil),     // 1: matou.v1.StoreCredentialRequest
+	(*StoreCredentialResponse)(nil),    // 2: matou.v1.StoreCredentialResponse
+	(*GetCredentialRequest)(nil),       // 3: matou.v1.GetCredentialRequest
+	(*GetCredentialResponse)(nil),      // 4: matou.v1.GetCredentialResponse
+	(*ListCredentialsRequest)(nil),     // 5: matou.v1.ListCredentialsRequest
+	(*ListCredentialsResponse)(nil),    // 6: matou.v1.ListCredentialsResponse
+	(*ValidateCredentialRequest)(nil),  // 7: matou.v1.ValidateCredentialRequest
+	(*ValidateCredentialResponse)(nil), // 8: matou.v1.ValidateCredentialResponse
+	(*VerifyCredentialRequest)(nil),    // 9: matou.v1.VerifyCredentialRequest
+	(*Verification)(nil),               // 10: matou.v1.Verification
+	(*VerificationCheck)(nil),          // 11: matou.v1.VerificationCheck
+	(*KeyEpoch)(nil),                   // 12: matou.v1.KeyEpoch
+	(*structpb.Struct)(nil),            // 13: google.protobuf.Struct
+}
+var file_matou_v1_credentials_proto_depIdxs = []int32{
+	13, // 0: matou.v1.Credential.data:type_name -> google.protobuf.Struct
+	0,  // 1: matou.v1.StoreCredentialRequest.credential:type_name -> matou.v1.Credential
+	0,  // 2: matou.v1.GetCredentialResponse.credential:type_name -> matou.v1.Credential
+	0,  // 3: matou.v1.ListCredentialsResponse.credentials:type_name -> matou.v1.Credential
+	13, // 4: matou.v1.ValidateCredentialRequest.credential:type_name -> google.protobuf.Struct
+	13, // 5: matou.v1.VerifyCredentialRequest.credential:type_name -> google.protobuf.Struct
+	11, // 6: matou.v1.Verification.checks:type_name -> matou.v1.VerificationCheck
+	12, // 7: matou.v1.Verification.key_state:type_name -> matou.v1.KeyEpoch
+	1,  // 8: matou.v1.CredentialService.StoreCredential:input_type -> matou.v1.StoreCredentialRequest
+	3,  // 9: matou.v1.CredentialService.GetCredential:input_type -> matou.v1.GetCredentialRequest
+	5,  // 10: matou.v1.CredentialService.ListCredentials:input_type -> matou.v1.ListCredentialsRequest
+	7,  // 11: matou.v1.CredentialService.ValidateCredential:input_type -> matou.v1.ValidateCredentialRequest
+	9,  // 12: matou.v1.CredentialService.VerifyCredential:input_type -> matou.v1.VerifyCredentialRequest
+	2,  // 13: matou.v1.CredentialService.StoreCredential:output_type -> matou.v1.StoreCredentialResponse
+	4,  // 14: matou.v1.CredentialService.GetCredential:output_type -> matou.v1.GetCredentialResponse
+	6,  // 15: matou.v1.CredentialService.ListCredentials:output_type -> matou.v1.ListCredentialsResponse
+	8,  // 16: matou.v1.CredentialService.ValidateCredential:output_type -> matou.v1.ValidateCredentialResponse
+	10, // 17: matou.v1.CredentialService.VerifyCredential:output_type -> matou.v1.Verification
+	13, // [13:18] is the sub-list for method output_type
+	8,  // [8:13] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_matou_v1_credentials_proto_init() }
+func file_matou_v1_credentials_proto_init() {
+	if File_matou_v1_credentials_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_matou_v1_credentials_proto_rawDesc), len(file_matou_v1_credentials_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_matou_v1_credentials_proto_goTypes,
+		DependencyIndexes: file_matou_v1_credentials_proto_depIdxs,
+		MessageInfos:      file_matou_v1_credentials_proto_msgTypes,
+	}.Build()
+	File_matou_v1_credentials_proto = out.File
+	file_matou_v1_credentials_proto_goTypes = nil
+	file_matou_v1_credentials_proto_depIdxs = nil
+}
@@ -0,0 +1,865 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: matou/v1/endorsements.proto
+
+package matouv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EndorsementRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RequesterAid    string                 `protobuf:"bytes,2,opt,name=requester_aid,json=requesterAid,proto3" json:"requester_aid,omitempty"`
+	EndorserAid     string                 `protobuf:"bytes,3,opt,name=endorser_aid,json=endorserAid,proto3" json:"endorser_aid,omitempty"`
+	Category        string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	EndorsementType string                 `protobuf:"bytes,5,opt,name=endorsement_type,json=endorsementType,proto3" json:"endorsement_type,omitempty"`
+	Message         string                 `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	// Status is pending or accepted.
+	Status        string `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt     string `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	RespondedAt   string `protobuf:"bytes,9,opt,name=responded_at,json=respondedAt,proto3" json:"responded_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EndorsementRequest) Reset() {
+	*x = EndorsementRequest{}
+	mi := &file_matou_v1_endorsements_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EndorsementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndorsementRequest) ProtoMessage() {}
+
+func (x *EndorsementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_endorsements_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndorsementRequest.ProtoReflect.Descriptor instead.
+func (*EndorsementRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_endorsements_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EndorsementRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *EndorsementRequest) GetRequesterAid() string {
+	if x != nil {
+		return x.RequesterAid
+	}
+	return ""
+}
+
+func (x *EndorsementRequest) GetEndorserAid() string {
+	if x != nil {
+		return x.EndorserAid
+	}
+	return ""
+}
+
+func (x *EndorsementRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *EndorsementRequest) GetEndorsementType() string {
+	if x != nil {
+		return x.EndorsementType
+	}
+	return ""
+}
+
+func (x *EndorsementRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *EndorsementRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *EndorsementRequest) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *EndorsementRequest) GetRespondedAt() string {
+	if x != nil {
+		return x.RespondedAt
+	}
+	return ""
+}
+
+type RequestEndorsementRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	EndorserAid string                 `protobuf:"bytes,1,opt,name=endorser_aid,json=endorserAid,proto3" json:"endorser_aid,omitempty"`
+	Category    string                 `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	// EndorsementType is optional; the category determines it.
+	EndorsementType string `protobuf:"bytes,3,opt,name=endorsement_type,json=endorsementType,proto3" json:"endorsement_type,omitempty"`
+	Message         string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RequestEndorsementRequest) Reset() {
+	*x = RequestEndorsementRequest{}
+	mi := &file_matou_v1_endorsements_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestEndorsementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestEndorsementRequest) ProtoMessage() {}
+
+func (x *RequestEndorsementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_endorsements_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestEndorsementRequest.ProtoReflect.Descriptor instead.
+func (*RequestEndorsementRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_endorsements_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RequestEndorsementRequest) GetEndorserAid() string {
+	if x != nil {
+		return x.EndorserAid
+	}
+	return ""
+}
+
+func (x *RequestEndorsementRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *RequestEndorsementRequest) GetEndorsementType() string {
+	if x != nil {
+		return x.EndorsementType
+	}
+	return ""
+}
+
+func (x *RequestEndorsementRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ListEndorsementRequestsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Direction is incoming (the default) or outgoing.
+	Direction     string `protobuf:"bytes,1,opt,name=direction,proto3" json:"direction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEndorsementRequestsRequest) Reset() {
+	*x = ListEndorsementRequestsRequest{}
+	mi := &file_matou_v1_endorsements_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEndorsementRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEndorsementRequestsRequest) ProtoMessage() {}
+
+func (x *ListEndorsementRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_endorsements_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEndorsementRequestsRequest.ProtoReflect.Descriptor instead.
+func (*ListEndorsementRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_endorsements_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListEndorsementRequestsRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+type ListEndorsementRequestsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Requests      []*EndorsementRequest  `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEndorsementRequestsResponse) Reset() {
+	*x = ListEndorsementRequestsResponse{}
+	mi := &file_matou_v1_endorsements_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEndorsementRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEndorsementRequestsResponse) ProtoMessage() {}
+
+func (x *ListEndorsementRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_endorsements_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEndorsementRequestsResponse.ProtoReflect.Descriptor instead.
+func (*ListEndorsementRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_matou_v1_endorsements_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListEndorsementRequestsResponse) GetRequests() []*EndorsementRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+func (x *ListEndorsementRequestsResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type AcceptEndorsementRequestRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Confidence in [0, 1] scales the endorsement's trust weight; unset
+	// means full confidence.
+	Confidence    *float64 `protobuf:"fixed64,2,opt,name=confidence,proto3,oneof" json:"confidence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptEndorsementRequestRequest) Reset() {
+	*x = AcceptEndorsementRequestRequest{}
+	mi := &file_matou_v1_endorsements_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptEndorsementRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptEndorsementRequestRequest) ProtoMessage() {}
+
+func (x *AcceptEndorsementRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_endorsements_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptEndorsementRequestRequest.ProtoReflect.Descriptor instead.
+func (*AcceptEndorsementRequestRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_endorsements_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AcceptEndorsementRequestRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AcceptEndorsementRequestRequest) GetConfidence() float64 {
+	if x != nil && x.Confidence != nil {
+		return *x.Confidence
+	}
+	return 0
+}
+
+type AcceptEndorsementRequestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Request       *EndorsementRequest    `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	Issuance      *CredentialIssuance    `protobuf:"bytes,2,opt,name=issuance,proto3" json:"issuance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptEndorsementRequestResponse) Reset() {
+	*x = AcceptEndorsementRequestResponse{}
+	mi := &file_matou_v1_endorsements_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptEndorsementRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptEndorsementRequestResponse) ProtoMessage() {}
+
+func (x *AcceptEndorsementRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_endorsements_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptEndorsementRequestResponse.ProtoReflect.Descriptor instead.
+func (*AcceptEndorsementRequestResponse) Descriptor() ([]byte, []int) {
+	return file_matou_v1_endorsements_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AcceptEndorsementRequestResponse) GetRequest() *EndorsementRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *AcceptEndorsementRequestResponse) GetIssuance() *CredentialIssuance {
+	if x != nil {
+		return x.Issuance
+	}
+	return nil
+}
+
+// CredentialIssuance pre-fills a credential for the issuer to issue via KERIA.
+type CredentialIssuance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schema        string                 `protobuf:"bytes,1,opt,name=schema,proto3" json:"schema,omitempty"`
+	Issuer        string                 `protobuf:"bytes,2,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	Recipient     string                 `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Data          *structpb.Struct       `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CredentialIssuance) Reset() {
+	*x = CredentialIssuance{}
+	mi := &file_matou_v1_endorsements_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CredentialIssuance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CredentialIssuance) ProtoMessage() {}
+
+func (x *CredentialIssuance) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_endorsements_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CredentialIssuance.ProtoReflect.Descriptor instead.
+func (*CredentialIssuance) Descriptor() ([]byte, []int) {
+	return file_matou_v1_endorsements_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CredentialIssuance) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+func (x *CredentialIssuance) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *CredentialIssuance) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *CredentialIssuance) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type DeclineEndorsementRequestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeclineEndorsementRequestRequest) Reset() {
+	*x = DeclineEndorsementRequestRequest{}
+	mi := &file_matou_v1_endorsements_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeclineEndorsementRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeclineEndorsementRequestRequest) ProtoMessage() {}
+
+func (x *DeclineEndorsementRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_endorsements_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeclineEndorsementRequestRequest.ProtoReflect.Descriptor instead.
+func (*DeclineEndorsementRequestRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_endorsements_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeclineEndorsementRequestRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeclineEndorsementRequestRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type DeclineEndorsementRequestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeclineEndorsementRequestResponse) Reset() {
+	*x = DeclineEndorsementRequestResponse{}
+	mi := &file_matou_v1_endorsements_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeclineEndorsementRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeclineEndorsementRequestResponse) ProtoMessage() {}
+
+func (x *DeclineEndorsementRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_endorsements_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeclineEndorsementRequestResponse.ProtoReflect.Descriptor instead.
+func (*DeclineEndorsementRequestResponse) Descriptor() ([]byte, []int) {
+	return file_matou_v1_endorsements_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeclineEndorsementRequestResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListEndorsementCategoriesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Type only lists categories of this endorsement type.
+	Type          string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEndorsementCategoriesRequest) Reset() {
+	*x = ListEndorsementCategoriesRequest{}
+	mi := &file_matou_v1_endorsements_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEndorsementCategoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEndorsementCategoriesRequest) ProtoMessage() {}
+
+func (x *ListEndorsementCategoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_endorsements_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEndorsementCategoriesRequest.ProtoReflect.Descriptor instead.
+func (*ListEndorsementCategoriesRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_endorsements_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListEndorsementCategoriesRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type ListEndorsementCategoriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Categories    []*EndorsementCategory `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEndorsementCategoriesResponse) Reset() {
+	*x = ListEndorsementCategoriesResponse{}
+	mi := &file_matou_v1_endorsements_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEndorsementCategoriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEndorsementCategoriesResponse) ProtoMessage() {}
+
+func (x *ListEndorsementCategoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_endorsements_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEndorsementCategoriesResponse.ProtoReflect.Descriptor instead.
+func (*ListEndorsementCategoriesResponse) Descriptor() ([]byte, []int) {
+	return file_matou_v1_endorsements_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListEndorsementCategoriesResponse) GetCategories() []*EndorsementCategory {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type EndorsementCategory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Label         string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EndorsementCategory) Reset() {
+	*x = EndorsementCategory{}
+	mi := &file_matou_v1_endorsements_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EndorsementCategory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndorsementCategory) ProtoMessage() {}
+
+func (x *EndorsementCategory) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_endorsements_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndorsementCategory.ProtoReflect.Descriptor instead.
+func (*EndorsementCategory) Descriptor() ([]byte, []int) {
+	return file_matou_v1_endorsements_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *EndorsementCategory) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *EndorsementCategory) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *EndorsementCategory) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *EndorsementCategory) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+var File_matou_v1_endorsements_proto protoreflect.FileDescriptor
+
+const file_matou_v1_endorsements_proto_rawDesc = "" +
+	"\n" +
+	"\x1bmatou/v1/endorsements.proto\x12\bmatou.v1\x1a\x1cgoogle/protobuf/struct.proto\"\xa7\x02\n" +
+	"\x12EndorsementRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
+	"\rrequester_aid\x18\x02 \x01(\tR\frequesterAid\x12!\n" +
+	"\fendorser_aid\x18\x03 \x01(\tR\vendorserAid\x12\x1a\n" +
+	"\bcategory\x18\x04 \x01(\tR\bcategory\x12)\n" +
+	"\x10endorsement_type\x18\x05 \x01(\tR\x0fendorsementType\x12\x18\n" +
+	"\amessage\x18\x06 \x01(\tR\amessage\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\x12!\n" +
+	"\fresponded_at\x18\t \x01(\tR\vrespondedAt\"\x9f\x01\n" +
+	"\x19RequestEndorsementRequest\x12!\n" +
+	"\fendorser_aid\x18\x01 \x01(\tR\vendorserAid\x12\x1a\n" +
+	"\bcategory\x18\x02 \x01(\tR\bcategory\x12)\n" +
+	"\x10endorsement_type\x18\x03 \x01(\tR\x0fendorsementType\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\">\n" +
+	"\x1eListEndorsementRequestsRequest\x12\x1c\n" +
+	"\tdirection\x18\x01 \x01(\tR\tdirection\"q\n" +
+	"\x1fListEndorsementRequestsResponse\x128\n" +
+	"\brequests\x18\x01 \x03(\v2\x1c.matou.v1.EndorsementRequestR\brequests\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\"e\n" +
+	"\x1fAcceptEndorsementRequestRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
+	"\n" +
+	"confidence\x18\x02 \x01(\x01H\x00R\n" +
+	"confidence\x88\x01\x01B\r\n" +
+	"\v_confidence\"\x94\x01\n" +
+	" AcceptEndorsementRequestResponse\x126\n" +
+	"\arequest\x18\x01 \x01(\v2\x1c.matou.v1.EndorsementRequestR\arequest\x128\n" +
+	"\bissuance\x18\x02 \x01(\v2\x1c.matou.v1.CredentialIssuanceR\bissuance\"\x8f\x01\n" +
+	"\x12CredentialIssuance\x12\x16\n" +
+	"\x06schema\x18\x01 \x01(\tR\x06schema\x12\x16\n" +
+	"\x06issuer\x18\x02 \x01(\tR\x06issuer\x12\x1c\n" +
+	"\trecipient\x18\x03 \x01(\tR\trecipient\x12+\n" +
+	"\x04data\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x04data\"J\n" +
+	" DeclineEndorsementRequestRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\";\n" +
+	"!DeclineEndorsementRequestResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"6\n" +
+	" ListEndorsementCategoriesRequest\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\"b\n" +
+	"!ListEndorsementCategoriesResponse\x12=\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\x1d.matou.v1.EndorsementCategoryR\n" +
+	"categories\"q\n" +
+	"\x13EndorsementCategory\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type2\xbc\x04\n" +
+	"\x12EndorsementService\x12W\n" +
+	"\x12RequestEndorsement\x12#.matou.v1.RequestEndorsementRequest\x1a\x1c.matou.v1.EndorsementRequest\x12n\n" +
+	"\x17ListEndorsementRequests\x12(.matou.v1.ListEndorsementRequestsRequest\x1a).matou.v1.ListEndorsementRequestsResponse\x12q\n" +
+	"\x18AcceptEndorsementRequest\x12).matou.v1.AcceptEndorsementRequestRequest\x1a*.matou.v1.AcceptEndorsementRequestResponse\x12t\n" +
+	"\x19DeclineEndorsementRequest\x12*.matou.v1.DeclineEndorsementRequestRequest\x1a+.matou.v1.DeclineEndorsementRequestResponse\x12t\n" +
+	"\x19ListEndorsementCategories\x12*.matou.v1.ListEndorsementCategoriesRequest\x1a+.matou.v1.ListEndorsementCategoriesResponseB<Z:github.com/matou-dao/backend/internal/rpc/matou/v1;matouv1b\x06proto3"
+
+var (
+	file_matou_v1_endorsements_proto_rawDescOnce sync.Once
+	file_matou_v1_endorsements_proto_rawDescData []byte
+)
+
+func file_matou_v1_endorsements_proto_rawDescGZIP() []byte {
+	file_matou_v1_endorsements_proto_rawDescOnce.Do(func() {
+		file_matou_v1_endorsements_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_matou_v1_endorsements_proto_rawDesc), len(file_matou_v1_endorsements_proto_rawDesc)))
+	})
+	return file_matou_v1_endorsements_proto_rawDescData
+}
+
+var file_matou_v1_endorsements_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_matou_v1_endorsements_proto_goTypes = []any{
+	(*EndorsementRequest)(nil),                // 0: matou.v1.EndorsementRequest
+	(*RequestEndorsementRequest)(nil),         // 1: matou.v1.RequestEndorsementRequest
+	(*ListEndorsementRequestsRequest)(nil),    // 2: matou.v1.ListEndorsementRequestsRequest
+	(*ListEndorsementRequestsResponse)(nil),   // 3: matou.v1.ListEndorsementRequestsResponse
+	(*AcceptEndorsementRequestRequest)(nil),   // 4: matou.v1.AcceptEndorsementRequestRequest
+	(*AcceptEndorsementRequestResponse)(nil),  // 5: matou.v1.AcceptEndorsementRequestResponse
+	(*CredentialIssuance)(nil),                // 6: matou.v1.CredentialIssuance
+	(*DeclineEndorsementRequestRequest)(nil),  // 7: matou.v1.DeclineEndorsementRequestRequest
+	(*DeclineEndorsementRequestResponse)(nil), // 8: matou.v1.DeclineEndorsementRequestResponse
+	(*ListEndorsementCategoriesRequest)(nil),  // 9: matou.v1.ListEndorsementCategoriesRequest
+	(*ListEndorsementCategoriesResponse)(nil), // 10: matou.v1.ListEndorsementCategoriesResponse
+	(*EndorsementCategory)(nil),               // 11: matou.v1.EndorsementCategory
+	(*structpb.Struct)(nil),                   // 12: google.protobuf.Struct
+}
+var file_matou_v1_endorsements_proto_depIdxs = []int32{
+	0,  // 0: matou.v1.ListEndorsementRequestsResponse.requests:type_name -> matou.v1.EndorsementRequest
+	0,  // 1: matou.v1.AcceptEndorsementRequestResponse.request:type_name -> matou.v1.EndorsementRequest
+	6,  // 2: matou.v1.AcceptEndorsementRequestResponse.issuance:type_name -> matou.v1.CredentialIssuance
+	12, // 3: matou.v1.CredentialIssuance.data:type_name -> google.protobuf.Struct
+	11, // 4: matou.v1.ListEndorsementCategoriesResponse.categories:type_name -> matou.v1.EndorsementCategory
+	1,  // 5: matou.v1.EndorsementService.RequestEndorsement:input_type -> matou.v1.RequestEndorsementRequest
+	2,  // 6: matou.v1.EndorsementService.ListEndorsementRequests:input_type -> matou.v1.ListEndorsementRequestsRequest
+	4,  // 7: matou.v1.EndorsementService.AcceptEndorsementRequest:input_type -> matou.v1.AcceptEndorsementRequestRequest
+	7,  // 8: matou.v1.EndorsementService.DeclineEndorsementRequest:input_type -> matou.v1.DeclineEndorsementRequestRequest
+	9,  // 9: matou.v1.EndorsementService.ListEndorsementCategories:input_type -> matou.v1.ListEndorsementCategoriesRequest
+	0,  // 10: matou.v1.EndorsementService.RequestEndorsement:output_type -> matou.v1.EndorsementRequest
+	3,  // 11: matou.v1.EndorsementService.ListEndorsementRequests:output_type -> matou.v1.ListEndorsementRequestsResponse
+	5,  // 12: matou.v1.EndorsementService.AcceptEndorsementRequest:output_type -> matou.v1.AcceptEndorsementRequestResponse
+	8,  // 13: matou.v1.EndorsementService.DeclineEndorsementRequest:output_type -> matou.v1.DeclineEndorsementRequestResponse
+	10, // 14: matou.v1.EndorsementService.ListEndorsementCategories:output_type -> matou.v1.ListEndorsementCategoriesResponse
+	10, // [10:15] is the sub-list for method output_type
+	5,  // [5:10] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_matou_v1_endorsements_proto_init() }
+func file_matou_v1_endorsements_proto_init() {
+	if File_matou_v1_endorsements_proto != nil {
+		return
+	}
+	file_matou_v1_endorsements_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_matou_v1_endorsements_proto_rawDesc), len(file_matou_v1_endorsements_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_matou_v1_endorsements_proto_goTypes,
+		DependencyIndexes: file_matou_v1_endorsements_proto_depIdxs,
+		MessageInfos:      file_matou_v1_endorsements_proto_msgTypes,
+	}.Build()
+	File_matou_v1_endorsements_proto = out.File
+	file_matou_v1_endorsements_proto_goTypes = nil
+	file_matou_v1_endorsements_proto_depIdxs = nil
+}
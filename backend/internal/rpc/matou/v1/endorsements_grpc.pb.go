@@ -0,0 +1,297 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: matou/v1/endorsements.proto
+
+package matouv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	EndorsementService_RequestEndorsement_FullMethodName        = "/matou.v1.EndorsementService/RequestEndorsement"
+	EndorsementService_ListEndorsementRequests_FullMethodName   = "/matou.v1.EndorsementService/ListEndorsementRequests"
+	EndorsementService_AcceptEndorsementRequest_FullMethodName  = "/matou.v1.EndorsementService/AcceptEndorsementRequest"
+	EndorsementService_DeclineEndorsementRequest_FullMethodName = "/matou.v1.EndorsementService/DeclineEndorsementRequest"
+	EndorsementService_ListEndorsementCategories_FullMethodName = "/matou.v1.EndorsementService/ListEndorsementCategories"
+)
+
+// EndorsementServiceClient is the client API for EndorsementService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// EndorsementService mirrors the /api/v1/endorsements routes.
+type EndorsementServiceClient interface {
+	// RequestEndorsement asks a member to endorse the local user. Mirrors
+	// POST /api/v1/endorsements/request.
+	RequestEndorsement(ctx context.Context, in *RequestEndorsementRequest, opts ...grpc.CallOption) (*EndorsementRequest, error)
+	// ListEndorsementRequests lists requests to or from the local user.
+	// Mirrors GET /api/v1/endorsements/requests.
+	ListEndorsementRequests(ctx context.Context, in *ListEndorsementRequestsRequest, opts ...grpc.CallOption) (*ListEndorsementRequestsResponse, error)
+	// AcceptEndorsementRequest accepts a request and returns the credential
+	// to issue. Mirrors POST /api/v1/endorsements/requests/{id}/accept.
+	AcceptEndorsementRequest(ctx context.Context, in *AcceptEndorsementRequestRequest, opts ...grpc.CallOption) (*AcceptEndorsementRequestResponse, error)
+	// DeclineEndorsementRequest privately declines a request. Mirrors
+	// POST /api/v1/endorsements/requests/{id}/decline.
+	DeclineEndorsementRequest(ctx context.Context, in *DeclineEndorsementRequestRequest, opts ...grpc.CallOption) (*DeclineEndorsementRequestResponse, error)
+	// ListEndorsementCategories lists the registered categories. Mirrors
+	// GET /api/v1/endorsements/categories.
+	ListEndorsementCategories(ctx context.Context, in *ListEndorsementCategoriesRequest, opts ...grpc.CallOption) (*ListEndorsementCategoriesResponse, error)
+}
+
+type endorsementServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEndorsementServiceClient(cc grpc.ClientConnInterface) EndorsementServiceClient {
+	return &endorsementServiceClient{cc}
+}
+
+func (c *endorsementServiceClient) RequestEndorsement(ctx context.Context, in *RequestEndorsementRequest, opts ...grpc.CallOption) (*EndorsementRequest, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EndorsementRequest)
+	err := c.cc.Invoke(ctx, EndorsementService_RequestEndorsement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *endorsementServiceClient) ListEndorsementRequests(ctx context.Context, in *ListEndorsementRequestsRequest, opts ...grpc.CallOption) (*ListEndorsementRequestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEndorsementRequestsResponse)
+	err := c.cc.Invoke(ctx, EndorsementService_ListEndorsementRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *endorsementServiceClient) AcceptEndorsementRequest(ctx context.Context, in *AcceptEndorsementRequestRequest, opts ...grpc.CallOption) (*AcceptEndorsementRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcceptEndorsementRequestResponse)
+	err := c.cc.Invoke(ctx, EndorsementService_AcceptEndorsementRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *endorsementServiceClient) DeclineEndorsementRequest(ctx context.Context, in *DeclineEndorsementRequestRequest, opts ...grpc.CallOption) (*DeclineEndorsementRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeclineEndorsementRequestResponse)
+	err := c.cc.Invoke(ctx, EndorsementService_DeclineEndorsementRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *endorsementServiceClient) ListEndorsementCategories(ctx context.Context, in *ListEndorsementCategoriesRequest, opts ...grpc.CallOption) (*ListEndorsementCategoriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEndorsementCategoriesResponse)
+	err := c.cc.Invoke(ctx, EndorsementService_ListEndorsementCategories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EndorsementServiceServer is the server API for EndorsementService service.
+// All implementations must embed UnimplementedEndorsementServiceServer
+// for forward compatibility.
+//
+// EndorsementService mirrors the /api/v1/endorsements routes.
+type EndorsementServiceServer interface {
+	// RequestEndorsement asks a member to endorse the local user. Mirrors
+	// POST /api/v1/endorsements/request.
+	RequestEndorsement(context.Context, *RequestEndorsementRequest) (*EndorsementRequest, error)
+	// ListEndorsementRequests lists requests to or from the local user.
+	// Mirrors GET /api/v1/endorsements/requests.
+	ListEndorsementRequests(context.Context, *ListEndorsementRequestsRequest) (*ListEndorsementRequestsResponse, error)
+	// AcceptEndorsementRequest accepts a request and returns the credential
+	// to issue. Mirrors POST /api/v1/endorsements/requests/{id}/accept.
+	AcceptEndorsementRequest(context.Context, *AcceptEndorsementRequestRequest) (*AcceptEndorsementRequestResponse, error)
+	// DeclineEndorsementRequest privately declines a request. Mirrors
+	// POST /api/v1/endorsements/requests/{id}/decline.
+	DeclineEndorsementRequest(context.Context, *DeclineEndorsementRequestRequest) (*DeclineEndorsementRequestResponse, error)
+	// ListEndorsementCategories lists the registered categories. Mirrors
+	// GET /api/v1/endorsements/categories.
+	ListEndorsementCategories(context.Context, *ListEndorsementCategoriesRequest) (*ListEndorsementCategoriesResponse, error)
+	mustEmbedUnimplementedEndorsementServiceServer()
+}
+
+// UnimplementedEndorsementServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEndorsementServiceServer struct{}
+
+func (UnimplementedEndorsementServiceServer) RequestEndorsement(context.Context, *RequestEndorsementRequest) (*EndorsementRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestEndorsement not implemented")
+}
+func (UnimplementedEndorsementServiceServer) ListEndorsementRequests(context.Context, *ListEndorsementRequestsRequest) (*ListEndorsementRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEndorsementRequests not implemented")
+}
+func (UnimplementedEndorsementServiceServer) AcceptEndorsementRequest(context.Context, *AcceptEndorsementRequestRequest) (*AcceptEndorsementRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptEndorsementRequest not implemented")
+}
+func (UnimplementedEndorsementServiceServer) DeclineEndorsementRequest(context.Context, *DeclineEndorsementRequestRequest) (*DeclineEndorsementRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeclineEndorsementRequest not implemented")
+}
+func (UnimplementedEndorsementServiceServer) ListEndorsementCategories(context.Context, *ListEndorsementCategoriesRequest) (*ListEndorsementCategoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEndorsementCategories not implemented")
+}
+func (UnimplementedEndorsementServiceServer) mustEmbedUnimplementedEndorsementServiceServer() {}
+func (UnimplementedEndorsementServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeEndorsementServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EndorsementServiceServer will
+// result in compilation errors.
+type UnsafeEndorsementServiceServer interface {
+	mustEmbedUnimplementedEndorsementServiceServer()
+}
+
+func RegisterEndorsementServiceServer(s grpc.ServiceRegistrar, srv EndorsementServiceServer) {
+	// If the following call pancis, it indicates UnimplementedEndorsementServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&EndorsementService_ServiceDesc, srv)
+}
+
+func _EndorsementService_RequestEndorsement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestEndorsementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EndorsementServiceServer).RequestEndorsement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EndorsementService_RequestEndorsement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EndorsementServiceServer).RequestEndorsement(ctx, req.(*RequestEndorsementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EndorsementService_ListEndorsementRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEndorsementRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EndorsementServiceServer).ListEndorsementRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EndorsementService_ListEndorsementRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EndorsementServiceServer).ListEndorsementRequests(ctx, req.(*ListEndorsementRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EndorsementService_AcceptEndorsementRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptEndorsementRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EndorsementServiceServer).AcceptEndorsementRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EndorsementService_AcceptEndorsementRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EndorsementServiceServer).AcceptEndorsementRequest(ctx, req.(*AcceptEndorsementRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EndorsementService_DeclineEndorsementRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeclineEndorsementRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EndorsementServiceServer).DeclineEndorsementRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EndorsementService_DeclineEndorsementRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EndorsementServiceServer).DeclineEndorsementRequest(ctx, req.(*DeclineEndorsementRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EndorsementService_ListEndorsementCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEndorsementCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EndorsementServiceServer).ListEndorsementCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EndorsementService_ListEndorsementCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EndorsementServiceServer).ListEndorsementCategories(ctx, req.(*ListEndorsementCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EndorsementService_ServiceDesc is the grpc.ServiceDesc for EndorsementService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EndorsementService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "matou.v1.EndorsementService",
+	HandlerType: (*EndorsementServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RequestEndorsement",
+			Handler:    _EndorsementService_RequestEndorsement_Handler,
+		},
+		{
+			MethodName: "ListEndorsementRequests",
+			Handler:    _EndorsementService_ListEndorsementRequests_Handler,
+		},
+		{
+			MethodName: "AcceptEndorsementRequest",
+			Handler:    _EndorsementService_AcceptEndorsementRequest_Handler,
+		},
+		{
+			MethodName: "DeclineEndorsementRequest",
+			Handler:    _EndorsementService_DeclineEndorsementRequest_Handler,
+		},
+		{
+			MethodName: "ListEndorsementCategories",
+			Handler:    _EndorsementService_ListEndorsementCategories_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "matou/v1/endorsements.proto",
+}
@@ -0,0 +1,507 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: matou/v1/identity.proto
+
+package matouv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SetIdentityRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Aid   string                 `protobuf:"bytes,1,opt,name=aid,proto3" json:"aid,omitempty"`
+	// Mnemonic is the BIP39 phrase the peer key is derived from.
+	Mnemonic         string `protobuf:"bytes,2,opt,name=mnemonic,proto3" json:"mnemonic,omitempty"`
+	OrgAid           string `protobuf:"bytes,3,opt,name=org_aid,json=orgAid,proto3" json:"org_aid,omitempty"`
+	CommunitySpaceId string `protobuf:"bytes,4,opt,name=community_space_id,json=communitySpaceId,proto3" json:"community_space_id,omitempty"`
+	ReadOnlySpaceId  string `protobuf:"bytes,5,opt,name=read_only_space_id,json=readOnlySpaceId,proto3" json:"read_only_space_id,omitempty"`
+	AdminSpaceId     string `protobuf:"bytes,6,opt,name=admin_space_id,json=adminSpaceId,proto3" json:"admin_space_id,omitempty"`
+	CredentialSaid   string `protobuf:"bytes,7,opt,name=credential_said,json=credentialSaid,proto3" json:"credential_said,omitempty"`
+	Mode             string `protobuf:"bytes,8,opt,name=mode,proto3" json:"mode,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SetIdentityRequest) Reset() {
+	*x = SetIdentityRequest{}
+	mi := &file_matou_v1_identity_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetIdentityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetIdentityRequest) ProtoMessage() {}
+
+func (x *SetIdentityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_identity_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetIdentityRequest.ProtoReflect.Descriptor instead.
+func (*SetIdentityRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_identity_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SetIdentityRequest) GetAid() string {
+	if x != nil {
+		return x.Aid
+	}
+	return ""
+}
+
+func (x *SetIdentityRequest) GetMnemonic() string {
+	if x != nil {
+		return x.Mnemonic
+	}
+	return ""
+}
+
+func (x *SetIdentityRequest) GetOrgAid() string {
+	if x != nil {
+		return x.OrgAid
+	}
+	return ""
+}
+
+func (x *SetIdentityRequest) GetCommunitySpaceId() string {
+	if x != nil {
+		return x.CommunitySpaceId
+	}
+	return ""
+}
+
+func (x *SetIdentityRequest) GetReadOnlySpaceId() string {
+	if x != nil {
+		return x.ReadOnlySpaceId
+	}
+	return ""
+}
+
+func (x *SetIdentityRequest) GetAdminSpaceId() string {
+	if x != nil {
+		return x.AdminSpaceId
+	}
+	return ""
+}
+
+func (x *SetIdentityRequest) GetCredentialSaid() string {
+	if x != nil {
+		return x.CredentialSaid
+	}
+	return ""
+}
+
+func (x *SetIdentityRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+type SetIdentityResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	PeerId         string                 `protobuf:"bytes,2,opt,name=peer_id,json=peerId,proto3" json:"peer_id,omitempty"`
+	PrivateSpaceId string                 `protobuf:"bytes,3,opt,name=private_space_id,json=privateSpaceId,proto3" json:"private_space_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SetIdentityResponse) Reset() {
+	*x = SetIdentityResponse{}
+	mi := &file_matou_v1_identity_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetIdentityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetIdentityResponse) ProtoMessage() {}
+
+func (x *SetIdentityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_identity_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetIdentityResponse.ProtoReflect.Descriptor instead.
+func (*SetIdentityResponse) Descriptor() ([]byte, []int) {
+	return file_matou_v1_identity_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SetIdentityResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SetIdentityResponse) GetPeerId() string {
+	if x != nil {
+		return x.PeerId
+	}
+	return ""
+}
+
+func (x *SetIdentityResponse) GetPrivateSpaceId() string {
+	if x != nil {
+		return x.PrivateSpaceId
+	}
+	return ""
+}
+
+type GetIdentityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIdentityRequest) Reset() {
+	*x = GetIdentityRequest{}
+	mi := &file_matou_v1_identity_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIdentityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIdentityRequest) ProtoMessage() {}
+
+func (x *GetIdentityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_identity_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIdentityRequest.ProtoReflect.Descriptor instead.
+func (*GetIdentityRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_identity_proto_rawDescGZIP(), []int{2}
+}
+
+type Identity struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Configured               bool                   `protobuf:"varint,1,opt,name=configured,proto3" json:"configured,omitempty"`
+	Aid                      string                 `protobuf:"bytes,2,opt,name=aid,proto3" json:"aid,omitempty"`
+	PeerId                   string                 `protobuf:"bytes,3,opt,name=peer_id,json=peerId,proto3" json:"peer_id,omitempty"`
+	OrgAid                   string                 `protobuf:"bytes,4,opt,name=org_aid,json=orgAid,proto3" json:"org_aid,omitempty"`
+	CommunitySpaceId         string                 `protobuf:"bytes,5,opt,name=community_space_id,json=communitySpaceId,proto3" json:"community_space_id,omitempty"`
+	CommunityReadOnlySpaceId string                 `protobuf:"bytes,6,opt,name=community_read_only_space_id,json=communityReadOnlySpaceId,proto3" json:"community_read_only_space_id,omitempty"`
+	AdminSpaceId             string                 `protobuf:"bytes,7,opt,name=admin_space_id,json=adminSpaceId,proto3" json:"admin_space_id,omitempty"`
+	PrivateSpaceId           string                 `protobuf:"bytes,8,opt,name=private_space_id,json=privateSpaceId,proto3" json:"private_space_id,omitempty"`
+	// Locked is true when the identity is encrypted and can't be opened with
+	// the configured passphrase.
+	Locked        bool `protobuf:"varint,9,opt,name=locked,proto3" json:"locked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Identity) Reset() {
+	*x = Identity{}
+	mi := &file_matou_v1_identity_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Identity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Identity) ProtoMessage() {}
+
+func (x *Identity) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_identity_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Identity.ProtoReflect.Descriptor instead.
+func (*Identity) Descriptor() ([]byte, []int) {
+	return file_matou_v1_identity_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Identity) GetConfigured() bool {
+	if x != nil {
+		return x.Configured
+	}
+	return false
+}
+
+func (x *Identity) GetAid() string {
+	if x != nil {
+		return x.Aid
+	}
+	return ""
+}
+
+func (x *Identity) GetPeerId() string {
+	if x != nil {
+		return x.PeerId
+	}
+	return ""
+}
+
+func (x *Identity) GetOrgAid() string {
+	if x != nil {
+		return x.OrgAid
+	}
+	return ""
+}
+
+func (x *Identity) GetCommunitySpaceId() string {
+	if x != nil {
+		return x.CommunitySpaceId
+	}
+	return ""
+}
+
+func (x *Identity) GetCommunityReadOnlySpaceId() string {
+	if x != nil {
+		return x.CommunityReadOnlySpaceId
+	}
+	return ""
+}
+
+func (x *Identity) GetAdminSpaceId() string {
+	if x != nil {
+		return x.AdminSpaceId
+	}
+	return ""
+}
+
+func (x *Identity) GetPrivateSpaceId() string {
+	if x != nil {
+		return x.PrivateSpaceId
+	}
+	return ""
+}
+
+func (x *Identity) GetLocked() bool {
+	if x != nil {
+		return x.Locked
+	}
+	return false
+}
+
+type DeleteIdentityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteIdentityRequest) Reset() {
+	*x = DeleteIdentityRequest{}
+	mi := &file_matou_v1_identity_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteIdentityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteIdentityRequest) ProtoMessage() {}
+
+func (x *DeleteIdentityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_identity_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteIdentityRequest.ProtoReflect.Descriptor instead.
+func (*DeleteIdentityRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_identity_proto_rawDescGZIP(), []int{4}
+}
+
+type DeleteIdentityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteIdentityResponse) Reset() {
+	*x = DeleteIdentityResponse{}
+	mi := &file_matou_v1_identity_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteIdentityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteIdentityResponse) ProtoMessage() {}
+
+func (x *DeleteIdentityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_identity_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteIdentityResponse.ProtoReflect.Descriptor instead.
+func (*DeleteIdentityResponse) Descriptor() ([]byte, []int) {
+	return file_matou_v1_identity_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteIdentityResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+var File_matou_v1_identity_proto protoreflect.FileDescriptor
+
+const file_matou_v1_identity_proto_rawDesc = "" +
+	"\n" +
+	"\x17matou/v1/identity.proto\x12\bmatou.v1\"\x99\x02\n" +
+	"\x12SetIdentityRequest\x12\x10\n" +
+	"\x03aid\x18\x01 \x01(\tR\x03aid\x12\x1a\n" +
+	"\bmnemonic\x18\x02 \x01(\tR\bmnemonic\x12\x17\n" +
+	"\aorg_aid\x18\x03 \x01(\tR\x06orgAid\x12,\n" +
+	"\x12community_space_id\x18\x04 \x01(\tR\x10communitySpaceId\x12+\n" +
+	"\x12read_only_space_id\x18\x05 \x01(\tR\x0freadOnlySpaceId\x12$\n" +
+	"\x0eadmin_space_id\x18\x06 \x01(\tR\fadminSpaceId\x12'\n" +
+	"\x0fcredential_said\x18\a \x01(\tR\x0ecredentialSaid\x12\x12\n" +
+	"\x04mode\x18\b \x01(\tR\x04mode\"r\n" +
+	"\x13SetIdentityResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x17\n" +
+	"\apeer_id\x18\x02 \x01(\tR\x06peerId\x12(\n" +
+	"\x10private_space_id\x18\x03 \x01(\tR\x0eprivateSpaceId\"\x14\n" +
+	"\x12GetIdentityRequest\"\xc4\x02\n" +
+	"\bIdentity\x12\x1e\n" +
+	"\n" +
+	"configured\x18\x01 \x01(\bR\n" +
+	"configured\x12\x10\n" +
+	"\x03aid\x18\x02 \x01(\tR\x03aid\x12\x17\n" +
+	"\apeer_id\x18\x03 \x01(\tR\x06peerId\x12\x17\n" +
+	"\aorg_aid\x18\x04 \x01(\tR\x06orgAid\x12,\n" +
+	"\x12community_space_id\x18\x05 \x01(\tR\x10communitySpaceId\x12>\n" +
+	"\x1ccommunity_read_only_space_id\x18\x06 \x01(\tR\x18communityReadOnlySpaceId\x12$\n" +
+	"\x0eadmin_space_id\x18\a \x01(\tR\fadminSpaceId\x12(\n" +
+	"\x10private_space_id\x18\b \x01(\tR\x0eprivateSpaceId\x12\x16\n" +
+	"\x06locked\x18\t \x01(\bR\x06locked\"\x17\n" +
+	"\x15DeleteIdentityRequest\"0\n" +
+	"\x16DeleteIdentityResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status2\xf3\x01\n" +
+	"\x0fIdentityService\x12J\n" +
+	"\vSetIdentity\x12\x1c.matou.v1.SetIdentityRequest\x1a\x1d.matou.v1.SetIdentityResponse\x12?\n" +
+	"\vGetIdentity\x12\x1c.matou.v1.GetIdentityRequest\x1a\x12.matou.v1.Identity\x12S\n" +
+	"\x0eDeleteIdentity\x12\x1f.matou.v1.DeleteIdentityRequest\x1a .matou.v1.DeleteIdentityResponseB<Z:github.com/matou-dao/backend/internal/rpc/matou/v1;matouv1b\x06proto3"
+
+var (
+	file_matou_v1_identity_proto_rawDescOnce sync.Once
+	file_matou_v1_identity_proto_rawDescData []byte
+)
+
+func file_matou_v1_identity_proto_rawDescGZIP() []byte {
+	file_matou_v1_identity_proto_rawDescOnce.Do(func() {
+		file_matou_v1_identity_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_matou_v1_identity_proto_rawDesc), len(file_matou_v1_identity_proto_rawDesc)))
+	})
+	return file_matou_v1_identity_proto_rawDescData
+}
+
+var file_matou_v1_identity_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_matou_v1_identity_proto_goTypes = []any{
+	(*SetIdentityRequest)(nil),     // 0: matou.v1.SetIdentityRequest
+	(*SetIdentityResponse)(nil),    // 1: matou.v1.SetIdentityResponse
+	(*GetIdentityRequest)(nil),     // 2: matou.v1.GetIdentityRequest
+	(*Identity)(nil),               // 3: matou.v1.Identity
+	(*DeleteIdentityRequest)(nil),  // 4: matou.v1.DeleteIdentityRequest
+	(*DeleteIdentityResponse)(nil), // 5: matou.v1.DeleteIdentityResponse
+}
+var file_matou_v1_identity_proto_depIdxs = []int32{
+	0, // 0: matou.v1.IdentityService.SetIdentity:input_type -> matou.v1.SetIdentityRequest
+	2, // 1: matou.v1.IdentityService.GetIdentity:input_type -> matou.v1.GetIdentityRequest
+	4, // 2: matou.v1.IdentityService.DeleteIdentity:input_type -> matou.v1.DeleteIdentityRequest
+	1, // 3: matou.v1.IdentityService.SetIdentity:output_type -> matou.v1.SetIdentityResponse
+	3, // 4: matou.v1.IdentityService.GetIdentity:output_type -> matou.v1.Identity
+	5, // 5: matou.v1.IdentityService.DeleteIdentity:output_type -> matou.v1.DeleteIdentityResponse
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_matou_v1_identity_proto_init() }
+func file_matou_v1_identity_proto_init() {
+	if File_matou_v1_identity_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_matou_v1_identity_proto_rawDesc), len(file_matou_v1_identity_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_matou_v1_identity_proto_goTypes,
+		DependencyIndexes: file_matou_v1_identity_proto_depIdxs,
+		MessageInfos:      file_matou_v1_identity_proto_msgTypes,
+	}.Build()
+	File_matou_v1_identity_proto = out.File
+	file_matou_v1_identity_proto_goTypes = nil
+	file_matou_v1_identity_proto_depIdxs = nil
+}
@@ -0,0 +1,560 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: matou/v1/spaces.proto
+
+package matouv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SpaceInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SpaceId       string                 `protobuf:"bytes,1,opt,name=space_id,json=spaceId,proto3" json:"space_id,omitempty"`
+	SpaceName     string                 `protobuf:"bytes,2,opt,name=space_name,json=spaceName,proto3" json:"space_name,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	KeysAvailable bool                   `protobuf:"varint,4,opt,name=keys_available,json=keysAvailable,proto3" json:"keys_available,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpaceInfo) Reset() {
+	*x = SpaceInfo{}
+	mi := &file_matou_v1_spaces_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpaceInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpaceInfo) ProtoMessage() {}
+
+func (x *SpaceInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_spaces_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpaceInfo.ProtoReflect.Descriptor instead.
+func (*SpaceInfo) Descriptor() ([]byte, []int) {
+	return file_matou_v1_spaces_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SpaceInfo) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *SpaceInfo) GetSpaceName() string {
+	if x != nil {
+		return x.SpaceName
+	}
+	return ""
+}
+
+func (x *SpaceInfo) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *SpaceInfo) GetKeysAvailable() bool {
+	if x != nil {
+		return x.KeysAvailable
+	}
+	return false
+}
+
+type GetUserSpacesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Aid defaults to the node's identity.
+	Aid           string `protobuf:"bytes,1,opt,name=aid,proto3" json:"aid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserSpacesRequest) Reset() {
+	*x = GetUserSpacesRequest{}
+	mi := &file_matou_v1_spaces_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserSpacesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserSpacesRequest) ProtoMessage() {}
+
+func (x *GetUserSpacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_spaces_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserSpacesRequest.ProtoReflect.Descriptor instead.
+func (*GetUserSpacesRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_spaces_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetUserSpacesRequest) GetAid() string {
+	if x != nil {
+		return x.Aid
+	}
+	return ""
+}
+
+type GetUserSpacesResponse struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	PrivateSpace           *SpaceInfo             `protobuf:"bytes,1,opt,name=private_space,json=privateSpace,proto3" json:"private_space,omitempty"`
+	CommunitySpace         *SpaceInfo             `protobuf:"bytes,2,opt,name=community_space,json=communitySpace,proto3" json:"community_space,omitempty"`
+	CommunityReadOnlySpace *SpaceInfo             `protobuf:"bytes,3,opt,name=community_read_only_space,json=communityReadOnlySpace,proto3" json:"community_read_only_space,omitempty"`
+	AdminSpace             *SpaceInfo             `protobuf:"bytes,4,opt,name=admin_space,json=adminSpace,proto3" json:"admin_space,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *GetUserSpacesResponse) Reset() {
+	*x = GetUserSpacesResponse{}
+	mi := &file_matou_v1_spaces_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserSpacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserSpacesResponse) ProtoMessage() {}
+
+func (x *GetUserSpacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_spaces_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserSpacesResponse.ProtoReflect.Descriptor instead.
+func (*GetUserSpacesResponse) Descriptor() ([]byte, []int) {
+	return file_matou_v1_spaces_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetUserSpacesResponse) GetPrivateSpace() *SpaceInfo {
+	if x != nil {
+		return x.PrivateSpace
+	}
+	return nil
+}
+
+func (x *GetUserSpacesResponse) GetCommunitySpace() *SpaceInfo {
+	if x != nil {
+		return x.CommunitySpace
+	}
+	return nil
+}
+
+func (x *GetUserSpacesResponse) GetCommunityReadOnlySpace() *SpaceInfo {
+	if x != nil {
+		return x.CommunityReadOnlySpace
+	}
+	return nil
+}
+
+func (x *GetUserSpacesResponse) GetAdminSpace() *SpaceInfo {
+	if x != nil {
+		return x.AdminSpace
+	}
+	return nil
+}
+
+type CreatePrivateSpaceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserAid       string                 `protobuf:"bytes,1,opt,name=user_aid,json=userAid,proto3" json:"user_aid,omitempty"`
+	Mnemonic      string                 `protobuf:"bytes,2,opt,name=mnemonic,proto3" json:"mnemonic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePrivateSpaceRequest) Reset() {
+	*x = CreatePrivateSpaceRequest{}
+	mi := &file_matou_v1_spaces_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePrivateSpaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePrivateSpaceRequest) ProtoMessage() {}
+
+func (x *CreatePrivateSpaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_spaces_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePrivateSpaceRequest.ProtoReflect.Descriptor instead.
+func (*CreatePrivateSpaceRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_spaces_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreatePrivateSpaceRequest) GetUserAid() string {
+	if x != nil {
+		return x.UserAid
+	}
+	return ""
+}
+
+func (x *CreatePrivateSpaceRequest) GetMnemonic() string {
+	if x != nil {
+		return x.Mnemonic
+	}
+	return ""
+}
+
+type CreatePrivateSpaceResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	SpaceId string                 `protobuf:"bytes,2,opt,name=space_id,json=spaceId,proto3" json:"space_id,omitempty"`
+	// Created is false when the space already existed.
+	Created       bool `protobuf:"varint,3,opt,name=created,proto3" json:"created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePrivateSpaceResponse) Reset() {
+	*x = CreatePrivateSpaceResponse{}
+	mi := &file_matou_v1_spaces_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePrivateSpaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePrivateSpaceResponse) ProtoMessage() {}
+
+func (x *CreatePrivateSpaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_spaces_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePrivateSpaceResponse.ProtoReflect.Descriptor instead.
+func (*CreatePrivateSpaceResponse) Descriptor() ([]byte, []int) {
+	return file_matou_v1_spaces_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreatePrivateSpaceResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CreatePrivateSpaceResponse) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *CreatePrivateSpaceResponse) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
+type InviteToCommunityRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	RecipientAid   string                 `protobuf:"bytes,1,opt,name=recipient_aid,json=recipientAid,proto3" json:"recipient_aid,omitempty"`
+	CredentialSaid string                 `protobuf:"bytes,2,opt,name=credential_said,json=credentialSaid,proto3" json:"credential_said,omitempty"`
+	Schema         string                 `protobuf:"bytes,3,opt,name=schema,proto3" json:"schema,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *InviteToCommunityRequest) Reset() {
+	*x = InviteToCommunityRequest{}
+	mi := &file_matou_v1_spaces_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteToCommunityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteToCommunityRequest) ProtoMessage() {}
+
+func (x *InviteToCommunityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_spaces_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteToCommunityRequest.ProtoReflect.Descriptor instead.
+func (*InviteToCommunityRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_spaces_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *InviteToCommunityRequest) GetRecipientAid() string {
+	if x != nil {
+		return x.RecipientAid
+	}
+	return ""
+}
+
+func (x *InviteToCommunityRequest) GetCredentialSaid() string {
+	if x != nil {
+		return x.CredentialSaid
+	}
+	return ""
+}
+
+func (x *InviteToCommunityRequest) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+type InviteToCommunityResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Success          bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	CommunitySpaceId string                 `protobuf:"bytes,2,opt,name=community_space_id,json=communitySpaceId,proto3" json:"community_space_id,omitempty"`
+	// InviteKey is the base64 community invite private key.
+	InviteKey         string `protobuf:"bytes,3,opt,name=invite_key,json=inviteKey,proto3" json:"invite_key,omitempty"`
+	ReadOnlyInviteKey string `protobuf:"bytes,4,opt,name=read_only_invite_key,json=readOnlyInviteKey,proto3" json:"read_only_invite_key,omitempty"`
+	ReadOnlySpaceId   string `protobuf:"bytes,5,opt,name=read_only_space_id,json=readOnlySpaceId,proto3" json:"read_only_space_id,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *InviteToCommunityResponse) Reset() {
+	*x = InviteToCommunityResponse{}
+	mi := &file_matou_v1_spaces_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteToCommunityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteToCommunityResponse) ProtoMessage() {}
+
+func (x *InviteToCommunityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_spaces_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteToCommunityResponse.ProtoReflect.Descriptor instead.
+func (*InviteToCommunityResponse) Descriptor() ([]byte, []int) {
+	return file_matou_v1_spaces_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *InviteToCommunityResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *InviteToCommunityResponse) GetCommunitySpaceId() string {
+	if x != nil {
+		return x.CommunitySpaceId
+	}
+	return ""
+}
+
+func (x *InviteToCommunityResponse) GetInviteKey() string {
+	if x != nil {
+		return x.InviteKey
+	}
+	return ""
+}
+
+func (x *InviteToCommunityResponse) GetReadOnlyInviteKey() string {
+	if x != nil {
+		return x.ReadOnlyInviteKey
+	}
+	return ""
+}
+
+func (x *InviteToCommunityResponse) GetReadOnlySpaceId() string {
+	if x != nil {
+		return x.ReadOnlySpaceId
+	}
+	return ""
+}
+
+var File_matou_v1_spaces_proto protoreflect.FileDescriptor
+
+const file_matou_v1_spaces_proto_rawDesc = "" +
+	"\n" +
+	"\x15matou/v1/spaces.proto\x12\bmatou.v1\"\x8b\x01\n" +
+	"\tSpaceInfo\x12\x19\n" +
+	"\bspace_id\x18\x01 \x01(\tR\aspaceId\x12\x1d\n" +
+	"\n" +
+	"space_name\x18\x02 \x01(\tR\tspaceName\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\x12%\n" +
+	"\x0ekeys_available\x18\x04 \x01(\bR\rkeysAvailable\"(\n" +
+	"\x14GetUserSpacesRequest\x12\x10\n" +
+	"\x03aid\x18\x01 \x01(\tR\x03aid\"\x95\x02\n" +
+	"\x15GetUserSpacesResponse\x128\n" +
+	"\rprivate_space\x18\x01 \x01(\v2\x13.matou.v1.SpaceInfoR\fprivateSpace\x12<\n" +
+	"\x0fcommunity_space\x18\x02 \x01(\v2\x13.matou.v1.SpaceInfoR\x0ecommunitySpace\x12N\n" +
+	"\x19community_read_only_space\x18\x03 \x01(\v2\x13.matou.v1.SpaceInfoR\x16communityReadOnlySpace\x124\n" +
+	"\vadmin_space\x18\x04 \x01(\v2\x13.matou.v1.SpaceInfoR\n" +
+	"adminSpace\"R\n" +
+	"\x19CreatePrivateSpaceRequest\x12\x19\n" +
+	"\buser_aid\x18\x01 \x01(\tR\auserAid\x12\x1a\n" +
+	"\bmnemonic\x18\x02 \x01(\tR\bmnemonic\"k\n" +
+	"\x1aCreatePrivateSpaceResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x19\n" +
+	"\bspace_id\x18\x02 \x01(\tR\aspaceId\x12\x18\n" +
+	"\acreated\x18\x03 \x01(\bR\acreated\"\x80\x01\n" +
+	"\x18InviteToCommunityRequest\x12#\n" +
+	"\rrecipient_aid\x18\x01 \x01(\tR\frecipientAid\x12'\n" +
+	"\x0fcredential_said\x18\x02 \x01(\tR\x0ecredentialSaid\x12\x16\n" +
+	"\x06schema\x18\x03 \x01(\tR\x06schema\"\xe0\x01\n" +
+	"\x19InviteToCommunityResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12,\n" +
+	"\x12community_space_id\x18\x02 \x01(\tR\x10communitySpaceId\x12\x1d\n" +
+	"\n" +
+	"invite_key\x18\x03 \x01(\tR\tinviteKey\x12/\n" +
+	"\x14read_only_invite_key\x18\x04 \x01(\tR\x11readOnlyInviteKey\x12+\n" +
+	"\x12read_only_space_id\x18\x05 \x01(\tR\x0freadOnlySpaceId2\x9f\x02\n" +
+	"\fSpaceService\x12P\n" +
+	"\rGetUserSpaces\x12\x1e.matou.v1.GetUserSpacesRequest\x1a\x1f.matou.v1.GetUserSpacesResponse\x12_\n" +
+	"\x12CreatePrivateSpace\x12#.matou.v1.CreatePrivateSpaceRequest\x1a$.matou.v1.CreatePrivateSpaceResponse\x12\\\n" +
+	"\x11InviteToCommunity\x12\".matou.v1.InviteToCommunityRequest\x1a#.matou.v1.InviteToCommunityResponseB<Z:github.com/matou-dao/backend/internal/rpc/matou/v1;matouv1b\x06proto3"
+
+var (
+	file_matou_v1_spaces_proto_rawDescOnce sync.Once
+	file_matou_v1_spaces_proto_rawDescData []byte
+)
+
+func file_matou_v1_spaces_proto_rawDescGZIP() []byte {
+	file_matou_v1_spaces_proto_rawDescOnce.Do(func() {
+		file_matou_v1_spaces_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_matou_v1_spaces_proto_rawDesc), len(file_matou_v1_spaces_proto_rawDesc)))
+	})
+	return file_matou_v1_spaces_proto_rawDescData
+}
+
+var file_matou_v1_spaces_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_matou_v1_spaces_proto_goTypes = []any{
+	(*SpaceInfo)(nil),                  // 0: matou.v1.SpaceInfo
+	(*GetUserSpacesRequest)(nil),       // 1: matou.v1.GetUserSpacesRequest
+	(*GetUserSpacesResponse)(nil),      // 2: matou.v1.GetUserSpacesResponse
+	(*CreatePrivateSpaceRequest)(nil),  // 3: matou.v1.CreatePrivateSpaceRequest
+	(*CreatePrivateSpaceResponse)(nil), // 4: matou.v1.CreatePrivateSpaceResponse
+	(*InviteToCommunityRequest)(nil),   // 5: matou.v1.InviteToCommunityRequest
+	(*InviteToCommunityResponse)(nil),  // 6: matou.v1.InviteToCommunityResponse
+}
+var file_matou_v1_spaces_proto_depIdxs = []int32{
+	0, // 0: matou.v1.GetUserSpacesResponse.private_space:type_name -> matou.v1.SpaceInfo
+	0, // 1: matou.v1.GetUserSpacesResponse.community_space:type_name -> matou.v1.SpaceInfo
+	0, // 2: matou.v1.GetUserSpacesResponse.community_read_only_space:type_name -> matou.v1.SpaceInfo
+	0, // 3: matou.v1.GetUserSpacesResponse.admin_space:type_name -> matou.v1.SpaceInfo
+	1, // 4: matou.v1.SpaceService.GetUserSpaces:input_type -> matou.v1.GetUserSpacesRequest
+	3, // 5: matou.v1.SpaceService.CreatePrivateSpace:input_type -> matou.v1.CreatePrivateSpaceRequest
+	5, // 6: matou.v1.SpaceService.InviteToCommunity:input_type -> matou.v1.InviteToCommunityRequest
+	2, // 7: matou.v1.SpaceService.GetUserSpaces:output_type -> matou.v1.GetUserSpacesResponse
+	4, // 8: matou.v1.SpaceService.CreatePrivateSpace:output_type -> matou.v1.CreatePrivateSpaceResponse
+	6, // 9: matou.v1.SpaceService.InviteToCommunity:output_type -> matou.v1.InviteToCommunityResponse
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_matou_v1_spaces_proto_init() }
+func file_matou_v1_spaces_proto_init() {
+	if File_matou_v1_spaces_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_matou_v1_spaces_proto_rawDesc), len(file_matou_v1_spaces_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_matou_v1_spaces_proto_goTypes,
+		DependencyIndexes: file_matou_v1_spaces_proto_depIdxs,
+		MessageInfos:      file_matou_v1_spaces_proto_msgTypes,
+	}.Build()
+	File_matou_v1_spaces_proto = out.File
+	file_matou_v1_spaces_proto_goTypes = nil
+	file_matou_v1_spaces_proto_depIdxs = nil
+}
@@ -0,0 +1,213 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: matou/v1/spaces.proto
+
+package matouv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SpaceService_GetUserSpaces_FullMethodName      = "/matou.v1.SpaceService/GetUserSpaces"
+	SpaceService_CreatePrivateSpace_FullMethodName = "/matou.v1.SpaceService/CreatePrivateSpace"
+	SpaceService_InviteToCommunity_FullMethodName  = "/matou.v1.SpaceService/InviteToCommunity"
+)
+
+// SpaceServiceClient is the client API for SpaceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SpaceService mirrors the /api/v1/spaces routes for a user's spaces and
+// community invites.
+type SpaceServiceClient interface {
+	// GetUserSpaces returns a user's spaces. Mirrors GET /api/v1/spaces/user.
+	GetUserSpaces(ctx context.Context, in *GetUserSpacesRequest, opts ...grpc.CallOption) (*GetUserSpacesResponse, error)
+	// CreatePrivateSpace creates a user's private space if it doesn't exist.
+	// Mirrors POST /api/v1/spaces/private.
+	CreatePrivateSpace(ctx context.Context, in *CreatePrivateSpaceRequest, opts ...grpc.CallOption) (*CreatePrivateSpaceResponse, error)
+	// InviteToCommunity invites a member to the community spaces. Mirrors
+	// POST /api/v1/spaces/community/invite.
+	InviteToCommunity(ctx context.Context, in *InviteToCommunityRequest, opts ...grpc.CallOption) (*InviteToCommunityResponse, error)
+}
+
+type spaceServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSpaceServiceClient(cc grpc.ClientConnInterface) SpaceServiceClient {
+	return &spaceServiceClient{cc}
+}
+
+func (c *spaceServiceClient) GetUserSpaces(ctx context.Context, in *GetUserSpacesRequest, opts ...grpc.CallOption) (*GetUserSpacesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserSpacesResponse)
+	err := c.cc.Invoke(ctx, SpaceService_GetUserSpaces_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spaceServiceClient) CreatePrivateSpace(ctx context.Context, in *CreatePrivateSpaceRequest, opts ...grpc.CallOption) (*CreatePrivateSpaceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreatePrivateSpaceResponse)
+	err := c.cc.Invoke(ctx, SpaceService_CreatePrivateSpace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *spaceServiceClient) InviteToCommunity(ctx context.Context, in *InviteToCommunityRequest, opts ...grpc.CallOption) (*InviteToCommunityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InviteToCommunityResponse)
+	err := c.cc.Invoke(ctx, SpaceService_InviteToCommunity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SpaceServiceServer is the server API for SpaceService service.
+// All implementations must embed UnimplementedSpaceServiceServer
+// for forward compatibility.
+//
+// SpaceService mirrors the /api/v1/spaces routes for a user's spaces and
+// community invites.
+type SpaceServiceServer interface {
+	// GetUserSpaces returns a user's spaces. Mirrors GET /api/v1/spaces/user.
+	GetUserSpaces(context.Context, *GetUserSpacesRequest) (*GetUserSpacesResponse, error)
+	// CreatePrivateSpace creates a user's private space if it doesn't exist.
+	// Mirrors POST /api/v1/spaces/private.
+	CreatePrivateSpace(context.Context, *CreatePrivateSpaceRequest) (*CreatePrivateSpaceResponse, error)
+	// InviteToCommunity invites a member to the community spaces. Mirrors
+	// POST /api/v1/spaces/community/invite.
+	InviteToCommunity(context.Context, *InviteToCommunityRequest) (*InviteToCommunityResponse, error)
+	mustEmbedUnimplementedSpaceServiceServer()
+}
+
+// UnimplementedSpaceServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSpaceServiceServer struct{}
+
+func (UnimplementedSpaceServiceServer) GetUserSpaces(context.Context, *GetUserSpacesRequest) (*GetUserSpacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserSpaces not implemented")
+}
+func (UnimplementedSpaceServiceServer) CreatePrivateSpace(context.Context, *CreatePrivateSpaceRequest) (*CreatePrivateSpaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePrivateSpace not implemented")
+}
+func (UnimplementedSpaceServiceServer) InviteToCommunity(context.Context, *InviteToCommunityRequest) (*InviteToCommunityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteToCommunity not implemented")
+}
+func (UnimplementedSpaceServiceServer) mustEmbedUnimplementedSpaceServiceServer() {}
+func (UnimplementedSpaceServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeSpaceServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SpaceServiceServer will
+// result in compilation errors.
+type UnsafeSpaceServiceServer interface {
+	mustEmbedUnimplementedSpaceServiceServer()
+}
+
+func RegisterSpaceServiceServer(s grpc.ServiceRegistrar, srv SpaceServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSpaceServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SpaceService_ServiceDesc, srv)
+}
+
+func _SpaceService_GetUserSpaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserSpacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpaceServiceServer).GetUserSpaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SpaceService_GetUserSpaces_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpaceServiceServer).GetUserSpaces(ctx, req.(*GetUserSpacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SpaceService_CreatePrivateSpace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePrivateSpaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpaceServiceServer).CreatePrivateSpace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SpaceService_CreatePrivateSpace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpaceServiceServer).CreatePrivateSpace(ctx, req.(*CreatePrivateSpaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SpaceService_InviteToCommunity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteToCommunityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SpaceServiceServer).InviteToCommunity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SpaceService_InviteToCommunity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SpaceServiceServer).InviteToCommunity(ctx, req.(*InviteToCommunityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SpaceService_ServiceDesc is the grpc.ServiceDesc for SpaceService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SpaceService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "matou.v1.SpaceService",
+	HandlerType: (*SpaceServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetUserSpaces",
+			Handler:    _SpaceService_GetUserSpaces_Handler,
+		},
+		{
+			MethodName: "CreatePrivateSpace",
+			Handler:    _SpaceService_CreatePrivateSpace_Handler,
+		},
+		{
+			MethodName: "InviteToCommunity",
+			Handler:    _SpaceService_InviteToCommunity_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "matou/v1/spaces.proto",
+}
@@ -0,0 +1,641 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: matou/v1/trust.proto
+
+package matouv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TrustScore struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Aid                      string                 `protobuf:"bytes,1,opt,name=aid,proto3" json:"aid,omitempty"`
+	Alias                    string                 `protobuf:"bytes,2,opt,name=alias,proto3" json:"alias,omitempty"`
+	Role                     string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	IncomingCredentials      int32                  `protobuf:"varint,4,opt,name=incoming_credentials,json=incomingCredentials,proto3" json:"incoming_credentials,omitempty"`
+	ParticipationCredentials int32                  `protobuf:"varint,5,opt,name=participation_credentials,json=participationCredentials,proto3" json:"participation_credentials,omitempty"`
+	FederatedCredentials     int32                  `protobuf:"varint,6,opt,name=federated_credentials,json=federatedCredentials,proto3" json:"federated_credentials,omitempty"`
+	Contributions            int32                  `protobuf:"varint,7,opt,name=contributions,proto3" json:"contributions,omitempty"`
+	Endorsements             int32                  `protobuf:"varint,8,opt,name=endorsements,proto3" json:"endorsements,omitempty"`
+	OutgoingCredentials      int32                  `protobuf:"varint,9,opt,name=outgoing_credentials,json=outgoingCredentials,proto3" json:"outgoing_credentials,omitempty"`
+	UniqueIssuers            int32                  `protobuf:"varint,10,opt,name=unique_issuers,json=uniqueIssuers,proto3" json:"unique_issuers,omitempty"`
+	BidirectionalRelations   int32                  `protobuf:"varint,11,opt,name=bidirectional_relations,json=bidirectionalRelations,proto3" json:"bidirectional_relations,omitempty"`
+	GraphDepth               int32                  `protobuf:"varint,12,opt,name=graph_depth,json=graphDepth,proto3" json:"graph_depth,omitempty"`
+	Score                    float64                `protobuf:"fixed64,13,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *TrustScore) Reset() {
+	*x = TrustScore{}
+	mi := &file_matou_v1_trust_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrustScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrustScore) ProtoMessage() {}
+
+func (x *TrustScore) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_trust_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrustScore.ProtoReflect.Descriptor instead.
+func (*TrustScore) Descriptor() ([]byte, []int) {
+	return file_matou_v1_trust_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TrustScore) GetAid() string {
+	if x != nil {
+		return x.Aid
+	}
+	return ""
+}
+
+func (x *TrustScore) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *TrustScore) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *TrustScore) GetIncomingCredentials() int32 {
+	if x != nil {
+		return x.IncomingCredentials
+	}
+	return 0
+}
+
+func (x *TrustScore) GetParticipationCredentials() int32 {
+	if x != nil {
+		return x.ParticipationCredentials
+	}
+	return 0
+}
+
+func (x *TrustScore) GetFederatedCredentials() int32 {
+	if x != nil {
+		return x.FederatedCredentials
+	}
+	return 0
+}
+
+func (x *TrustScore) GetContributions() int32 {
+	if x != nil {
+		return x.Contributions
+	}
+	return 0
+}
+
+func (x *TrustScore) GetEndorsements() int32 {
+	if x != nil {
+		return x.Endorsements
+	}
+	return 0
+}
+
+func (x *TrustScore) GetOutgoingCredentials() int32 {
+	if x != nil {
+		return x.OutgoingCredentials
+	}
+	return 0
+}
+
+func (x *TrustScore) GetUniqueIssuers() int32 {
+	if x != nil {
+		return x.UniqueIssuers
+	}
+	return 0
+}
+
+func (x *TrustScore) GetBidirectionalRelations() int32 {
+	if x != nil {
+		return x.BidirectionalRelations
+	}
+	return 0
+}
+
+func (x *TrustScore) GetGraphDepth() int32 {
+	if x != nil {
+		return x.GraphDepth
+	}
+	return 0
+}
+
+func (x *TrustScore) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type GetScoreRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Aid   string                 `protobuf:"bytes,1,opt,name=aid,proto3" json:"aid,omitempty"`
+	// Algorithm is linear or pagerank; empty uses the org's configured one.
+	Algorithm     string `protobuf:"bytes,2,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScoreRequest) Reset() {
+	*x = GetScoreRequest{}
+	mi := &file_matou_v1_trust_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScoreRequest) ProtoMessage() {}
+
+func (x *GetScoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_trust_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScoreRequest.ProtoReflect.Descriptor instead.
+func (*GetScoreRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_trust_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetScoreRequest) GetAid() string {
+	if x != nil {
+		return x.Aid
+	}
+	return ""
+}
+
+func (x *GetScoreRequest) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+type GetScoreResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Score         *TrustScore            `protobuf:"bytes,1,opt,name=score,proto3" json:"score,omitempty"`
+	Algorithm     string                 `protobuf:"bytes,2,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScoreResponse) Reset() {
+	*x = GetScoreResponse{}
+	mi := &file_matou_v1_trust_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScoreResponse) ProtoMessage() {}
+
+func (x *GetScoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_trust_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScoreResponse.ProtoReflect.Descriptor instead.
+func (*GetScoreResponse) Descriptor() ([]byte, []int) {
+	return file_matou_v1_trust_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetScoreResponse) GetScore() *TrustScore {
+	if x != nil {
+		return x.Score
+	}
+	return nil
+}
+
+func (x *GetScoreResponse) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+type ListScoresRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Limit is how many scores to return (default 10).
+	Limit         int32  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Algorithm     string `protobuf:"bytes,2,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScoresRequest) Reset() {
+	*x = ListScoresRequest{}
+	mi := &file_matou_v1_trust_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScoresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScoresRequest) ProtoMessage() {}
+
+func (x *ListScoresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_trust_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScoresRequest.ProtoReflect.Descriptor instead.
+func (*ListScoresRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_trust_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListScoresRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListScoresRequest) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+type ListScoresResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scores        []*TrustScore          `protobuf:"bytes,1,rep,name=scores,proto3" json:"scores,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Algorithm     string                 `protobuf:"bytes,3,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScoresResponse) Reset() {
+	*x = ListScoresResponse{}
+	mi := &file_matou_v1_trust_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScoresResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScoresResponse) ProtoMessage() {}
+
+func (x *ListScoresResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_trust_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScoresResponse.ProtoReflect.Descriptor instead.
+func (*ListScoresResponse) Descriptor() ([]byte, []int) {
+	return file_matou_v1_trust_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListScoresResponse) GetScores() []*TrustScore {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
+func (x *ListScoresResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListScoresResponse) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+type GetSummaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Algorithm     string                 `protobuf:"bytes,1,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSummaryRequest) Reset() {
+	*x = GetSummaryRequest{}
+	mi := &file_matou_v1_trust_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSummaryRequest) ProtoMessage() {}
+
+func (x *GetSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_trust_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSummaryRequest.ProtoReflect.Descriptor instead.
+func (*GetSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_matou_v1_trust_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetSummaryRequest) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+type TrustSummary struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	TotalNodes         int32                  `protobuf:"varint,1,opt,name=total_nodes,json=totalNodes,proto3" json:"total_nodes,omitempty"`
+	TotalEdges         int32                  `protobuf:"varint,2,opt,name=total_edges,json=totalEdges,proto3" json:"total_edges,omitempty"`
+	AverageScore       float64                `protobuf:"fixed64,3,opt,name=average_score,json=averageScore,proto3" json:"average_score,omitempty"`
+	MaxScore           float64                `protobuf:"fixed64,4,opt,name=max_score,json=maxScore,proto3" json:"max_score,omitempty"`
+	MinScore           float64                `protobuf:"fixed64,5,opt,name=min_score,json=minScore,proto3" json:"min_score,omitempty"`
+	MedianDepth        int32                  `protobuf:"varint,6,opt,name=median_depth,json=medianDepth,proto3" json:"median_depth,omitempty"`
+	BidirectionalCount int32                  `protobuf:"varint,7,opt,name=bidirectional_count,json=bidirectionalCount,proto3" json:"bidirectional_count,omitempty"`
+	Algorithm          string                 `protobuf:"bytes,8,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *TrustSummary) Reset() {
+	*x = TrustSummary{}
+	mi := &file_matou_v1_trust_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrustSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrustSummary) ProtoMessage() {}
+
+func (x *TrustSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_matou_v1_trust_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrustSummary.ProtoReflect.Descriptor instead.
+func (*TrustSummary) Descriptor() ([]byte, []int) {
+	return file_matou_v1_trust_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TrustSummary) GetTotalNodes() int32 {
+	if x != nil {
+		return x.TotalNodes
+	}
+	return 0
+}
+
+func (x *TrustSummary) GetTotalEdges() int32 {
+	if x != nil {
+		return x.TotalEdges
+	}
+	return 0
+}
+
+func (x *TrustSummary) GetAverageScore() float64 {
+	if x != nil {
+		return x.AverageScore
+	}
+	return 0
+}
+
+func (x *TrustSummary) GetMaxScore() float64 {
+	if x != nil {
+		return x.MaxScore
+	}
+	return 0
+}
+
+func (x *TrustSummary) GetMinScore() float64 {
+	if x != nil {
+		return x.MinScore
+	}
+	return 0
+}
+
+func (x *TrustSummary) GetMedianDepth() int32 {
+	if x != nil {
+		return x.MedianDepth
+	}
+	return 0
+}
+
+func (x *TrustSummary) GetBidirectionalCount() int32 {
+	if x != nil {
+		return x.BidirectionalCount
+	}
+	return 0
+}
+
+func (x *TrustSummary) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+var File_matou_v1_trust_proto protoreflect.FileDescriptor
+
+const file_matou_v1_trust_proto_rawDesc = "" +
+	"\n" +
+	"\x14matou/v1/trust.proto\x12\bmatou.v1\"\x81\x04\n" +
+	"\n" +
+	"TrustScore\x12\x10\n" +
+	"\x03aid\x18\x01 \x01(\tR\x03aid\x12\x14\n" +
+	"\x05alias\x18\x02 \x01(\tR\x05alias\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x121\n" +
+	"\x14incoming_credentials\x18\x04 \x01(\x05R\x13incomingCredentials\x12;\n" +
+	"\x19participation_credentials\x18\x05 \x01(\x05R\x18participationCredentials\x123\n" +
+	"\x15federated_credentials\x18\x06 \x01(\x05R\x14federatedCredentials\x12$\n" +
+	"\rcontributions\x18\a \x01(\x05R\rcontributions\x12\"\n" +
+	"\fendorsements\x18\b \x01(\x05R\fendorsements\x121\n" +
+	"\x14outgoing_credentials\x18\t \x01(\x05R\x13outgoingCredentials\x12%\n" +
+	"\x0eunique_issuers\x18\n" +
+	" \x01(\x05R\runiqueIssuers\x127\n" +
+	"\x17bidirectional_relations\x18\v \x01(\x05R\x16bidirectionalRelations\x12\x1f\n" +
+	"\vgraph_depth\x18\f \x01(\x05R\n" +
+	"graphDepth\x12\x14\n" +
+	"\x05score\x18\r \x01(\x01R\x05score\"A\n" +
+	"\x0fGetScoreRequest\x12\x10\n" +
+	"\x03aid\x18\x01 \x01(\tR\x03aid\x12\x1c\n" +
+	"\talgorithm\x18\x02 \x01(\tR\talgorithm\"\\\n" +
+	"\x10GetScoreResponse\x12*\n" +
+	"\x05score\x18\x01 \x01(\v2\x14.matou.v1.TrustScoreR\x05score\x12\x1c\n" +
+	"\talgorithm\x18\x02 \x01(\tR\talgorithm\"G\n" +
+	"\x11ListScoresRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x1c\n" +
+	"\talgorithm\x18\x02 \x01(\tR\talgorithm\"v\n" +
+	"\x12ListScoresResponse\x12,\n" +
+	"\x06scores\x18\x01 \x03(\v2\x14.matou.v1.TrustScoreR\x06scores\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x1c\n" +
+	"\talgorithm\x18\x03 \x01(\tR\talgorithm\"1\n" +
+	"\x11GetSummaryRequest\x12\x1c\n" +
+	"\talgorithm\x18\x01 \x01(\tR\talgorithm\"\xa1\x02\n" +
+	"\fTrustSummary\x12\x1f\n" +
+	"\vtotal_nodes\x18\x01 \x01(\x05R\n" +
+	"totalNodes\x12\x1f\n" +
+	"\vtotal_edges\x18\x02 \x01(\x05R\n" +
+	"totalEdges\x12#\n" +
+	"\raverage_score\x18\x03 \x01(\x01R\faverageScore\x12\x1b\n" +
+	"\tmax_score\x18\x04 \x01(\x01R\bmaxScore\x12\x1b\n" +
+	"\tmin_score\x18\x05 \x01(\x01R\bminScore\x12!\n" +
+	"\fmedian_depth\x18\x06 \x01(\x05R\vmedianDepth\x12/\n" +
+	"\x13bidirectional_count\x18\a \x01(\x05R\x12bidirectionalCount\x12\x1c\n" +
+	"\talgorithm\x18\b \x01(\tR\talgorithm2\xdd\x01\n" +
+	"\fTrustService\x12A\n" +
+	"\bGetScore\x12\x19.matou.v1.GetScoreRequest\x1a\x1a.matou.v1.GetScoreResponse\x12G\n" +
+	"\n" +
+	"ListScores\x12\x1b.matou.v1.ListScoresRequest\x1a\x1c.matou.v1.ListScoresResponse\x12A\n" +
+	"\n" +
+	"GetSummary\x12\x1b.matou.v1.GetSummaryRequest\x1a\x16.matou.v1.TrustSummaryB<Z:github.com/matou-dao/backend/internal/rpc/matou/v1;matouv1b\x06proto3"
+
+var (
+	file_matou_v1_trust_proto_rawDescOnce sync.Once
+	file_matou_v1_trust_proto_rawDescData []byte
+)
+
+func file_matou_v1_trust_proto_rawDescGZIP() []byte {
+	file_matou_v1_trust_proto_rawDescOnce.Do(func() {
+		file_matou_v1_trust_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_matou_v1_trust_proto_rawDesc), len(file_matou_v1_trust_proto_rawDesc)))
+	})
+	return file_matou_v1_trust_proto_rawDescData
+}
+
+var file_matou_v1_trust_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_matou_v1_trust_proto_goTypes = []any{
+	(*TrustScore)(nil),         // 0: matou.v1.TrustScore
+	(*GetScoreRequest)(nil),    // 1: matou.v1.GetScoreRequest
+	(*GetScoreResponse)(nil),   // 2: matou.v1.GetScoreResponse
+	(*ListScoresRequest)(nil),  // 3: matou.v1.ListScoresRequest
+	(*ListScoresResponse)(nil), // 4: matou.v1.ListScoresResponse
+	(*GetSummaryRequest)(nil),  // 5: matou.v1.GetSummaryRequest
+	(*TrustSummary)(nil),       // 6: matou.v1.TrustSummary
+}
+var file_matou_v1_trust_proto_depIdxs = []int32{
+	0, // 0: matou.v1.GetScoreResponse.score:type_name -> matou.v1.TrustScore
+	0, // 1: matou.v1.ListScoresResponse.scores:type_name -> matou.v1.TrustScore
+	1, // 2: matou.v1.TrustService.GetScore:input_type -> matou.v1.GetScoreRequest
+	3, // 3: matou.v1.TrustService.ListScores:input_type -> matou.v1.ListScoresRequest
+	5, // 4: matou.v1.TrustService.GetSummary:input_type -> matou.v1.GetSummaryRequest
+	2, // 5: matou.v1.TrustService.GetScore:output_type -> matou.v1.GetScoreResponse
+	4, // 6: matou.v1.TrustService.ListScores:output_type -> matou.v1.ListScoresResponse
+	6, // 7: matou.v1.TrustService.GetSummary:output_type -> matou.v1.TrustSummary
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_matou_v1_trust_proto_init() }
+func file_matou_v1_trust_proto_init() {
+	if File_matou_v1_trust_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_matou_v1_trust_proto_rawDesc), len(file_matou_v1_trust_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_matou_v1_trust_proto_goTypes,
+		DependencyIndexes: file_matou_v1_trust_proto_depIdxs,
+		MessageInfos:      file_matou_v1_trust_proto_msgTypes,
+	}.Build()
+	File_matou_v1_trust_proto = out.File
+	file_matou_v1_trust_proto_goTypes = nil
+	file_matou_v1_trust_proto_depIdxs = nil
+}
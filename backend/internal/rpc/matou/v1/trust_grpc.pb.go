@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: matou/v1/trust.proto
+
+package matouv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TrustService_GetScore_FullMethodName   = "/matou.v1.TrustService/GetScore"
+	TrustService_ListScores_FullMethodName = "/matou.v1.TrustService/ListScores"
+	TrustService_GetSummary_FullMethodName = "/matou.v1.TrustService/GetSummary"
+)
+
+// TrustServiceClient is the client API for TrustService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TrustService mirrors the /api/v1/trust score routes. Each call builds the
+// trust graph, so the trust route rate limits apply.
+type TrustServiceClient interface {
+	// GetScore scores one AID. Mirrors GET /api/v1/trust/score/{aid}.
+	GetScore(ctx context.Context, in *GetScoreRequest, opts ...grpc.CallOption) (*GetScoreResponse, error)
+	// ListScores returns the top scores. Mirrors GET /api/v1/trust/scores.
+	ListScores(ctx context.Context, in *ListScoresRequest, opts ...grpc.CallOption) (*ListScoresResponse, error)
+	// GetSummary summarizes the graph's scores. Mirrors GET /api/v1/trust/summary.
+	GetSummary(ctx context.Context, in *GetSummaryRequest, opts ...grpc.CallOption) (*TrustSummary, error)
+}
+
+type trustServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTrustServiceClient(cc grpc.ClientConnInterface) TrustServiceClient {
+	return &trustServiceClient{cc}
+}
+
+func (c *trustServiceClient) GetScore(ctx context.Context, in *GetScoreRequest, opts ...grpc.CallOption) (*GetScoreResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetScoreResponse)
+	err := c.cc.Invoke(ctx, TrustService_GetScore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trustServiceClient) ListScores(ctx context.Context, in *ListScoresRequest, opts ...grpc.CallOption) (*ListScoresResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListScoresResponse)
+	err := c.cc.Invoke(ctx, TrustService_ListScores_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trustServiceClient) GetSummary(ctx context.Context, in *GetSummaryRequest, opts ...grpc.CallOption) (*TrustSummary, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TrustSummary)
+	err := c.cc.Invoke(ctx, TrustService_GetSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TrustServiceServer is the server API for TrustService service.
+// All implementations must embed UnimplementedTrustServiceServer
+// for forward compatibility.
+//
+// TrustService mirrors the /api/v1/trust score routes. Each call builds the
+// trust graph, so the trust route rate limits apply.
+type TrustServiceServer interface {
+	// GetScore scores one AID. Mirrors GET /api/v1/trust/score/{aid}.
+	GetScore(context.Context, *GetScoreRequest) (*GetScoreResponse, error)
+	// ListScores returns the top scores. Mirrors GET /api/v1/trust/scores.
+	ListScores(context.Context, *ListScoresRequest) (*ListScoresResponse, error)
+	// GetSummary summarizes the graph's scores. Mirrors GET /api/v1/trust/summary.
+	GetSummary(context.Context, *GetSummaryRequest) (*TrustSummary, error)
+	mustEmbedUnimplementedTrustServiceServer()
+}
+
+// UnimplementedTrustServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTrustServiceServer struct{}
+
+func (UnimplementedTrustServiceServer) GetScore(context.Context, *GetScoreRequest) (*GetScoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScore not implemented")
+}
+func (UnimplementedTrustServiceServer) ListScores(context.Context, *ListScoresRequest) (*ListScoresResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScores not implemented")
+}
+func (UnimplementedTrustServiceServer) GetSummary(context.Context, *GetSummaryRequest) (*TrustSummary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSummary not implemented")
+}
+func (UnimplementedTrustServiceServer) mustEmbedUnimplementedTrustServiceServer() {}
+func (UnimplementedTrustServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeTrustServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TrustServiceServer will
+// result in compilation errors.
+type UnsafeTrustServiceServer interface {
+	mustEmbedUnimplementedTrustServiceServer()
+}
+
+func RegisterTrustServiceServer(s grpc.ServiceRegistrar, srv TrustServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTrustServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TrustService_ServiceDesc, srv)
+}
+
+func _TrustService_GetScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrustServiceServer).GetScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrustService_GetScore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrustServiceServer).GetScore(ctx, req.(*GetScoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrustService_ListScores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScoresRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrustServiceServer).ListScores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrustService_ListScores_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrustServiceServer).ListScores(ctx, req.(*ListScoresRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrustService_GetSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrustServiceServer).GetSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrustService_GetSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrustServiceServer).GetSummary(ctx, req.(*GetSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TrustService_ServiceDesc is the grpc.ServiceDesc for TrustService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TrustService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "matou.v1.TrustService",
+	HandlerType: (*TrustServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetScore",
+			Handler:    _TrustService_GetScore_Handler,
+		},
+		{
+			MethodName: "ListScores",
+			Handler:    _TrustService_ListScores_Handler,
+		},
+		{
+			MethodName: "GetSummary",
+			Handler:    _TrustService_GetSummary_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "matou/v1/trust.proto",
+}
@@ -0,0 +1,293 @@
+// Package rpc serves the backend's gRPC API, the matou.v1 services defined
+// in proto/matou/v1. Each method mirrors a REST route: the call is turned
+// into that route's request and run in-process through the same handler
+// chain as HTTP, so authentication, access tiers, rate limits, org routing,
+// validation and cluster forwarding behave the same over both.
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	matouv1 "github.com/matou-dao/backend/internal/rpc/matou/v1"
+)
+
+// forwardedHeaders are copied from call metadata onto the mirrored request:
+// credentials, the tenant, consistency tokens and request signatures. A
+// signature covers the method and path of the REST route the call mirrors.
+var forwardedHeaders = []string{
+	"Authorization",
+	"X-Org-AID",
+	"X-Consistency-Token",
+	"Signature",
+	"Signature-Input",
+	"Signify-Resource",
+	"Signify-Timestamp",
+}
+
+// returnedHeaders are copied from the mirrored response into the call's
+// header metadata
+var returnedHeaders = []string{"X-Consistency-Token", "X-Data-Version", "Retry-After"}
+
+// Server is the gRPC server. It satisfies lifecycle.Server.
+type Server struct {
+	handler http.Handler
+	srv     *grpc.Server
+}
+
+// NewServer creates a gRPC server whose calls are served by handler, the
+// backend's complete HTTP handler chain
+func NewServer(handler http.Handler, opts ...grpc.ServerOption) *Server {
+	s := &Server{handler: handler, srv: grpc.NewServer(opts...)}
+	matouv1.RegisterCredentialServiceServer(s.srv, &credentialService{s: s})
+	matouv1.RegisterTrustServiceServer(s.srv, &trustService{s: s})
+	matouv1.RegisterEndorsementServiceServer(s.srv, &endorsementService{s: s})
+	matouv1.RegisterIdentityServiceServer(s.srv, &identityService{s: s})
+	matouv1.RegisterSpaceServiceServer(s.srv, &spaceService{s: s})
+	return s
+}
+
+// Serve accepts gRPC connections on ln until the server is shut down
+func (s *Server) Serve(ln net.Listener) error {
+	return s.srv.Serve(ln)
+}
+
+// Shutdown stops accepting calls and waits for those in flight, cutting
+// them off when ctx expires
+func (s *Server) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.srv.Stop()
+		return ctx.Err()
+	}
+}
+
+// Close stops the server at once
+func (s *Server) Close() error {
+	s.srv.Stop()
+	return nil
+}
+
+// route is the REST request a method mirrors. {field} segments in the path
+// are filled from the call's message; its other fields go in the query
+// string for GET and DELETE and in the JSON body otherwise.
+type route struct {
+	method string
+	path   string
+}
+
+var pathField = regexp.MustCompile(`\{(\w+)\}`)
+
+var responseOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+
+// call runs the mirrored request for in and decodes the response into out
+func (s *Server) call(ctx context.Context, rt route, in, out proto.Message) error {
+	req, err := newRequest(ctx, rt, in)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	rec := newRecorder()
+	s.handler.ServeHTTP(rec, req)
+
+	var md metadata.MD
+	for _, name := range returnedHeaders {
+		if value := rec.header.Get(name); value != "" {
+			md = metadata.Join(md, metadata.Pairs(name, value))
+		}
+	}
+	if md != nil {
+		// Fails only outside a gRPC call, when there is nowhere to send it
+		grpc.SetHeader(ctx, md)
+	}
+
+	if rec.status >= 400 {
+		return statusError(rec.status, rec.body.Bytes())
+	}
+	if rec.body.Len() == 0 {
+		return nil
+	}
+	if err := responseOptions.Unmarshal(rec.body.Bytes(), out); err != nil {
+		return status.Errorf(codes.Internal, "decoding %s %s response: %v", rt.method, req.URL.Path, err)
+	}
+	return nil
+}
+
+// newRequest builds the REST request for a call
+func newRequest(ctx context.Context, rt route, in proto.Message) (*http.Request, error) {
+	data, err := protojson.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	var missing string
+	path := pathField.ReplaceAllStringFunc(rt.path, func(segment string) string {
+		name := segment[1 : len(segment)-1]
+		var value string
+		json.Unmarshal(fields[name], &value)
+		delete(fields, name)
+		if value == "" && missing == "" {
+			missing = name
+		}
+		return url.PathEscape(value)
+	})
+	if missing != "" {
+		return nil, fmt.Errorf("%s is required", missing)
+	}
+
+	var body io.Reader
+	if rt.method == http.MethodGet || rt.method == http.MethodDelete {
+		query := url.Values{}
+		for name, raw := range fields {
+			var value string
+			if json.Unmarshal(raw, &value) != nil {
+				value = string(raw)
+			}
+			query.Set(name, value)
+		}
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+	} else {
+		encoded, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, rt.method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		req.RemoteAddr = p.Addr.String()
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, name := range forwardedHeaders {
+			if values := md.Get(name); len(values) > 0 {
+				req.Header.Set(name, values[0])
+			}
+		}
+	}
+	return req, nil
+}
+
+// statusError converts an error response to a gRPC status. Field errors
+// from a problem+json body become a BadRequest detail.
+func statusError(httpStatus int, data []byte) error {
+	var body struct {
+		Error  string `json:"error"`
+		Errors []struct {
+			Field   string `json:"field"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	json.Unmarshal(data, &body)
+	msg := body.Error
+	if msg == "" {
+		msg = strings.ToLower(http.StatusText(httpStatus))
+	}
+
+	st := status.New(codeFor(httpStatus), msg)
+	if len(body.Errors) > 0 {
+		detail := &errdetails.BadRequest{}
+		for _, e := range body.Errors {
+			detail.FieldViolations = append(detail.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       e.Field,
+				Description: e.Message,
+			})
+		}
+		if withDetail, err := st.WithDetails(detail); err == nil {
+			st = withDetail
+		}
+	}
+	return st.Err()
+}
+
+// codeFor maps an HTTP error status to the closest gRPC code
+func codeFor(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	if httpStatus >= 500 {
+		return codes.Internal
+	}
+	return codes.Unknown
+}
+
+// recorder captures the mirrored response
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newRecorder() *recorder {
+	return &recorder{header: make(http.Header)}
+}
+
+func (r *recorder) Header() http.Header { return r.header }
+
+func (r *recorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(p)
+}
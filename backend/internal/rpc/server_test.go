@@ -0,0 +1,136 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	matouv1 "github.com/matou-dao/backend/internal/rpc/matou/v1"
+)
+
+// dial serves handler over an in-memory gRPC connection
+func dial(t *testing.T, handler http.Handler) *grpc.ClientConn {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	srv := NewServer(handler)
+	go srv.Serve(ln)
+	t.Cleanup(func() { srv.Close() })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestServer_MirrorsRESTRoutes(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/endorsements/requests/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/endorsements/requests/EReq1/accept" {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "unexpected " + r.Method + " " + r.URL.Path})
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"confidence":0}` || r.Header.Get("Authorization") != "Bearer token" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unexpected body " + string(body)})
+			return
+		}
+		w.Header().Set("X-Consistency-Token", "v42")
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"request":  map[string]string{"id": "EReq1", "requesterAid": "EAlice", "status": "accepted"},
+			"issuance": map[string]interface{}{"issuer": "EBob", "data": map[string]interface{}{"confidence": 0}},
+			"ignored":  true,
+		})
+	})
+	mux.HandleFunc("/api/v1/trust/scores", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Query().Get("limit") != "5" || r.URL.Query().Get("algorithm") != "pagerank" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unexpected query " + r.URL.RawQuery})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"scores":    []map[string]interface{}{{"aid": "EAlice", "score": 0.75, "graphDepth": 2}},
+			"total":     1,
+			"algorithm": "pagerank",
+		})
+	})
+	conn := dial(t, mux)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token")
+	var header metadata.MD
+	confidence := 0.0
+	accepted, err := matouv1.NewEndorsementServiceClient(conn).AcceptEndorsementRequest(ctx,
+		&matouv1.AcceptEndorsementRequestRequest{Id: "EReq1", Confidence: &confidence}, grpc.Header(&header))
+	if err != nil {
+		t.Fatalf("AcceptEndorsementRequest failed: %v", err)
+	}
+	if accepted.GetRequest().GetRequesterAid() != "EAlice" || accepted.GetIssuance().GetIssuer() != "EBob" {
+		t.Errorf("unexpected response %v", accepted)
+	}
+	if got := header.Get("x-consistency-token"); len(got) != 1 || got[0] != "v42" {
+		t.Errorf("expected the consistency token in the header metadata, got %v", got)
+	}
+
+	scores, err := matouv1.NewTrustServiceClient(conn).ListScores(ctx, &matouv1.ListScoresRequest{Limit: 5, Algorithm: "pagerank"})
+	if err != nil {
+		t.Fatalf("ListScores failed: %v", err)
+	}
+	if len(scores.Scores) != 1 || scores.Scores[0].GetScore() != 0.75 || scores.Scores[0].GetGraphDepth() != 2 {
+		t.Errorf("unexpected scores %v", scores)
+	}
+
+	// A path field is required
+	_, err = matouv1.NewEndorsementServiceClient(conn).DeclineEndorsementRequest(ctx, &matouv1.DeclineEndorsementRequestRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument without an id, got %v", err)
+	}
+}
+
+func TestServer_MapsErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/endorsements/request", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status":400,"detail":"category is required","errors":[{"field":"category","message":"is required"}],"error":"category is required"}`))
+	})
+	mux.HandleFunc("/api/v1/identity", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "authentication required"})
+	})
+	conn := dial(t, mux)
+
+	_, err := matouv1.NewEndorsementServiceClient(conn).RequestEndorsement(context.Background(), &matouv1.RequestEndorsementRequest{EndorserAid: "EBob"})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument || st.Message() != "category is required" {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+	if len(st.Details()) != 1 {
+		t.Fatalf("expected a BadRequest detail, got %v", st.Details())
+	}
+	if detail, ok := st.Details()[0].(*errdetails.BadRequest); !ok || detail.FieldViolations[0].Field != "category" {
+		t.Errorf("unexpected detail %v", st.Details()[0])
+	}
+
+	_, err = matouv1.NewIdentityServiceClient(conn).GetIdentity(context.Background(), &matouv1.GetIdentityRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated, got %v", err)
+	}
+}
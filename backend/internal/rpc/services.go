@@ -0,0 +1,188 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+
+	matouv1 "github.com/matou-dao/backend/internal/rpc/matou/v1"
+)
+
+// Each method names the REST route it mirrors; see route for how the
+// message's fields are placed
+
+type credentialService struct {
+	matouv1.UnimplementedCredentialServiceServer
+	s *Server
+}
+
+func (c *credentialService) StoreCredential(ctx context.Context, in *matouv1.StoreCredentialRequest) (*matouv1.StoreCredentialResponse, error) {
+	out := &matouv1.StoreCredentialResponse{}
+	if err := c.s.call(ctx, route{http.MethodPost, "/api/v1/credentials"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *credentialService) GetCredential(ctx context.Context, in *matouv1.GetCredentialRequest) (*matouv1.GetCredentialResponse, error) {
+	out := &matouv1.GetCredentialResponse{}
+	if err := c.s.call(ctx, route{http.MethodGet, "/api/v1/credentials/{said}"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *credentialService) ListCredentials(ctx context.Context, in *matouv1.ListCredentialsRequest) (*matouv1.ListCredentialsResponse, error) {
+	out := &matouv1.ListCredentialsResponse{}
+	if err := c.s.call(ctx, route{http.MethodGet, "/api/v1/credentials"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *credentialService) ValidateCredential(ctx context.Context, in *matouv1.ValidateCredentialRequest) (*matouv1.ValidateCredentialResponse, error) {
+	out := &matouv1.ValidateCredentialResponse{}
+	if err := c.s.call(ctx, route{http.MethodPost, "/api/v1/credentials/validate"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *credentialService) VerifyCredential(ctx context.Context, in *matouv1.VerifyCredentialRequest) (*matouv1.Verification, error) {
+	out := &matouv1.Verification{}
+	if err := c.s.call(ctx, route{http.MethodPost, "/api/v1/credentials/verify"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type trustService struct {
+	matouv1.UnimplementedTrustServiceServer
+	s *Server
+}
+
+func (t *trustService) GetScore(ctx context.Context, in *matouv1.GetScoreRequest) (*matouv1.GetScoreResponse, error) {
+	out := &matouv1.GetScoreResponse{}
+	if err := t.s.call(ctx, route{http.MethodGet, "/api/v1/trust/score/{aid}"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (t *trustService) ListScores(ctx context.Context, in *matouv1.ListScoresRequest) (*matouv1.ListScoresResponse, error) {
+	out := &matouv1.ListScoresResponse{}
+	if err := t.s.call(ctx, route{http.MethodGet, "/api/v1/trust/scores"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (t *trustService) GetSummary(ctx context.Context, in *matouv1.GetSummaryRequest) (*matouv1.TrustSummary, error) {
+	out := &matouv1.TrustSummary{}
+	if err := t.s.call(ctx, route{http.MethodGet, "/api/v1/trust/summary"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type endorsementService struct {
+	matouv1.UnimplementedEndorsementServiceServer
+	s *Server
+}
+
+func (e *endorsementService) RequestEndorsement(ctx context.Context, in *matouv1.RequestEndorsementRequest) (*matouv1.EndorsementRequest, error) {
+	out := &matouv1.EndorsementRequest{}
+	if err := e.s.call(ctx, route{http.MethodPost, "/api/v1/endorsements/request"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (e *endorsementService) ListEndorsementRequests(ctx context.Context, in *matouv1.ListEndorsementRequestsRequest) (*matouv1.ListEndorsementRequestsResponse, error) {
+	out := &matouv1.ListEndorsementRequestsResponse{}
+	if err := e.s.call(ctx, route{http.MethodGet, "/api/v1/endorsements/requests"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (e *endorsementService) AcceptEndorsementRequest(ctx context.Context, in *matouv1.AcceptEndorsementRequestRequest) (*matouv1.AcceptEndorsementRequestResponse, error) {
+	out := &matouv1.AcceptEndorsementRequestResponse{}
+	if err := e.s.call(ctx, route{http.MethodPost, "/api/v1/endorsements/requests/{id}/accept"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (e *endorsementService) DeclineEndorsementRequest(ctx context.Context, in *matouv1.DeclineEndorsementRequestRequest) (*matouv1.DeclineEndorsementRequestResponse, error) {
+	out := &matouv1.DeclineEndorsementRequestResponse{}
+	if err := e.s.call(ctx, route{http.MethodPost, "/api/v1/endorsements/requests/{id}/decline"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (e *endorsementService) ListEndorsementCategories(ctx context.Context, in *matouv1.ListEndorsementCategoriesRequest) (*matouv1.ListEndorsementCategoriesResponse, error) {
+	out := &matouv1.ListEndorsementCategoriesResponse{}
+	if err := e.s.call(ctx, route{http.MethodGet, "/api/v1/endorsements/categories"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type identityService struct {
+	matouv1.UnimplementedIdentityServiceServer
+	s *Server
+}
+
+func (i *identityService) SetIdentity(ctx context.Context, in *matouv1.SetIdentityRequest) (*matouv1.SetIdentityResponse, error) {
+	out := &matouv1.SetIdentityResponse{}
+	if err := i.s.call(ctx, route{http.MethodPost, "/api/v1/identity/set"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (i *identityService) GetIdentity(ctx context.Context, in *matouv1.GetIdentityRequest) (*matouv1.Identity, error) {
+	out := &matouv1.Identity{}
+	if err := i.s.call(ctx, route{http.MethodGet, "/api/v1/identity"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (i *identityService) DeleteIdentity(ctx context.Context, in *matouv1.DeleteIdentityRequest) (*matouv1.DeleteIdentityResponse, error) {
+	out := &matouv1.DeleteIdentityResponse{}
+	if err := i.s.call(ctx, route{http.MethodDelete, "/api/v1/identity"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type spaceService struct {
+	matouv1.UnimplementedSpaceServiceServer
+	s *Server
+}
+
+func (sp *spaceService) GetUserSpaces(ctx context.Context, in *matouv1.GetUserSpacesRequest) (*matouv1.GetUserSpacesResponse, error) {
+	out := &matouv1.GetUserSpacesResponse{}
+	if err := sp.s.call(ctx, route{http.MethodGet, "/api/v1/spaces/user"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (sp *spaceService) CreatePrivateSpace(ctx context.Context, in *matouv1.CreatePrivateSpaceRequest) (*matouv1.CreatePrivateSpaceResponse, error) {
+	out := &matouv1.CreatePrivateSpaceResponse{}
+	if err := sp.s.call(ctx, route{http.MethodPost, "/api/v1/spaces/private"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (sp *spaceService) InviteToCommunity(ctx context.Context, in *matouv1.InviteToCommunityRequest) (*matouv1.InviteToCommunityResponse, error) {
+	out := &matouv1.InviteToCommunityResponse{}
+	if err := sp.s.call(ctx, route{http.MethodPost, "/api/v1/spaces/community/invite"}, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
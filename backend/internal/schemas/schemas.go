@@ -0,0 +1,428 @@
+// Package schemas provides the community's registry of ACDC credential
+// schemas. Each schema is identified by its SAID and carries a versioned
+// JSON Schema body describing the credential's data (attribute) block.
+//
+// Only the subset of JSON Schema that MATOU schemas use is supported:
+// type, properties, required, additionalProperties (boolean), items, enum,
+// const, minLength, maxLength, pattern, format (date-time, date, email,
+// uri), minimum, maximum, minItems and maxItems. Bodies using composition
+// or references ($ref, oneOf, anyOf, allOf, not, if) are rejected rather
+// than silently left unchecked.
+package schemas
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Schema is a registered credential schema
+type Schema struct {
+	SAID        string          `json:"said"`
+	Title       string          `json:"title,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Version     string          `json:"version"`
+	Body        json.RawMessage `json:"body"` // JSON Schema for credential data
+	UpdatedAt   string          `json:"updatedAt,omitempty"`
+	UpdatedBy   string          `json:"updatedBy,omitempty"`
+}
+
+// Validate checks the schema is well formed: a SAID, a version and a body
+// that compiles
+func (s *Schema) Validate() error {
+	if strings.TrimSpace(s.SAID) == "" {
+		return fmt.Errorf("schema SAID is required")
+	}
+	if strings.ContainsAny(s.SAID, "/?# ") {
+		return fmt.Errorf("invalid schema SAID: %q", s.SAID)
+	}
+	if strings.TrimSpace(s.Version) == "" {
+		return fmt.Errorf("schema version is required")
+	}
+	if _, err := Compile(s.Body); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidateData checks credential data against the schema body
+func (s *Schema) ValidateData(data json.RawMessage) error {
+	n, err := Compile(s.Body)
+	if err != nil {
+		return fmt.Errorf("schema %s: %w", s.SAID, err)
+	}
+	return n.ValidateData(data)
+}
+
+// unsupportedKeywords are JSON Schema keywords this validator can't enforce
+var unsupportedKeywords = []string{"$ref", "oneOf", "anyOf", "allOf", "not", "if", "patternProperties"}
+
+// validTypes are the JSON Schema primitive types
+var validTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true,
+	"integer": true, "boolean": true, "null": true,
+}
+
+// validFormats are the string formats that are checked
+var validFormats = map[string]bool{
+	"date-time": true, "date": true, "email": true, "uri": true,
+}
+
+// Node is a compiled schema (or subschema)
+type Node struct {
+	types                []string
+	properties           map[string]*Node
+	required             []string
+	additionalProperties *bool
+	items                *Node
+	enum                 []interface{}
+	constant             interface{}
+	hasConst             bool
+	minLength, maxLength *int
+	pattern              *regexp.Regexp
+	format               string
+	minimum, maximum     *float64
+	minItems, maxItems   *int
+}
+
+// Compile parses a JSON Schema body
+func Compile(body json.RawMessage) (*Node, error) {
+	if len(body) == 0 {
+		return nil, fmt.Errorf("schema body is required")
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("schema body must be a JSON object: %w", err)
+	}
+	return compile(raw, "")
+}
+
+func compile(raw map[string]interface{}, path string) (*Node, error) {
+	for _, kw := range unsupportedKeywords {
+		if _, ok := raw[kw]; ok {
+			return nil, fmt.Errorf("%s: unsupported keyword %q", pathOrRoot(path), kw)
+		}
+	}
+
+	n := &Node{}
+	var err error
+
+	switch t := raw["type"].(type) {
+	case nil:
+	case string:
+		n.types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: type must be a string or list of strings", pathOrRoot(path))
+			}
+			n.types = append(n.types, s)
+		}
+	default:
+		return nil, fmt.Errorf("%s: type must be a string or list of strings", pathOrRoot(path))
+	}
+	for _, t := range n.types {
+		if !validTypes[t] {
+			return nil, fmt.Errorf("%s: unknown type %q", pathOrRoot(path), t)
+		}
+	}
+
+	if props, ok := raw["properties"]; ok {
+		m, ok := props.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: properties must be an object", pathOrRoot(path))
+		}
+		n.properties = make(map[string]*Node, len(m))
+		for name, sub := range m {
+			subRaw, ok := sub.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: property schema must be an object", joinPath(path, name))
+			}
+			if n.properties[name], err = compile(subRaw, joinPath(path, name)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if req, ok := raw["required"]; ok {
+		list, ok := req.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: required must be a list of property names", pathOrRoot(path))
+		}
+		for _, v := range list {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: required must be a list of property names", pathOrRoot(path))
+			}
+			n.required = append(n.required, s)
+		}
+	}
+
+	if ap, ok := raw["additionalProperties"]; ok {
+		b, ok := ap.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s: additionalProperties must be a boolean", pathOrRoot(path))
+		}
+		n.additionalProperties = &b
+	}
+
+	if items, ok := raw["items"]; ok {
+		itemsRaw, ok := items.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: items must be a schema object", pathOrRoot(path))
+		}
+		if n.items, err = compile(itemsRaw, path+"[]"); err != nil {
+			return nil, err
+		}
+	}
+
+	if enum, ok := raw["enum"]; ok {
+		list, ok := enum.([]interface{})
+		if !ok || len(list) == 0 {
+			return nil, fmt.Errorf("%s: enum must be a non-empty list", pathOrRoot(path))
+		}
+		n.enum = list
+	}
+	if c, ok := raw["const"]; ok {
+		n.constant, n.hasConst = c, true
+	}
+
+	if n.minLength, err = intKeyword(raw, "minLength", path); err != nil {
+		return nil, err
+	}
+	if n.maxLength, err = intKeyword(raw, "maxLength", path); err != nil {
+		return nil, err
+	}
+	if n.minItems, err = intKeyword(raw, "minItems", path); err != nil {
+		return nil, err
+	}
+	if n.maxItems, err = intKeyword(raw, "maxItems", path); err != nil {
+		return nil, err
+	}
+	if n.minimum, err = numberKeyword(raw, "minimum", path); err != nil {
+		return nil, err
+	}
+	if n.maximum, err = numberKeyword(raw, "maximum", path); err != nil {
+		return nil, err
+	}
+
+	if p, ok := raw["pattern"]; ok {
+		s, ok := p.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: pattern must be a string", pathOrRoot(path))
+		}
+		if n.pattern, err = regexp.Compile(s); err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern: %w", pathOrRoot(path), err)
+		}
+	}
+	if f, ok := raw["format"]; ok {
+		s, ok := f.(string)
+		if !ok || !validFormats[s] {
+			return nil, fmt.Errorf("%s: unsupported format %v", pathOrRoot(path), f)
+		}
+		n.format = s
+	}
+
+	return n, nil
+}
+
+// ValidateData checks JSON data against the compiled schema. All problems
+// are reported, one per field, in a single error.
+func (n *Node) ValidateData(data json.RawMessage) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("data is not valid JSON: %w", err)
+	}
+	if errs := n.validate(value, ""); len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (n *Node) validate(value interface{}, path string) []string {
+	at := pathOrRoot(path)
+
+	if len(n.types) > 0 && !n.matchesType(value) {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", at, strings.Join(n.types, " or "), typeOf(value))}
+	}
+
+	var errs []string
+	if n.hasConst && !reflect.DeepEqual(value, n.constant) {
+		errs = append(errs, fmt.Sprintf("%s: must be %v", at, n.constant))
+	}
+	if n.enum != nil && !containsValue(n.enum, value) {
+		errs = append(errs, fmt.Sprintf("%s: must be one of %v", at, n.enum))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range n.required {
+			if _, ok := v[name]; !ok {
+				errs = append(errs, fmt.Sprintf("%s: is required", joinPath(path, name)))
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			sub, ok := n.properties[name]
+			if !ok {
+				if n.additionalProperties != nil && !*n.additionalProperties {
+					errs = append(errs, fmt.Sprintf("%s: is not allowed", joinPath(path, name)))
+				}
+				continue
+			}
+			errs = append(errs, sub.validate(v[name], joinPath(path, name))...)
+		}
+
+	case []interface{}:
+		if n.minItems != nil && len(v) < *n.minItems {
+			errs = append(errs, fmt.Sprintf("%s: must have at least %d items", at, *n.minItems))
+		}
+		if n.maxItems != nil && len(v) > *n.maxItems {
+			errs = append(errs, fmt.Sprintf("%s: must have at most %d items", at, *n.maxItems))
+		}
+		if n.items != nil {
+			for i, item := range v {
+				errs = append(errs, n.items.validate(item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+
+	case string:
+		length := len([]rune(v))
+		if n.minLength != nil && length < *n.minLength {
+			errs = append(errs, fmt.Sprintf("%s: must be at least %d characters", at, *n.minLength))
+		}
+		if n.maxLength != nil && length > *n.maxLength {
+			errs = append(errs, fmt.Sprintf("%s: must be at most %d characters", at, *n.maxLength))
+		}
+		if n.pattern != nil && !n.pattern.MatchString(v) {
+			errs = append(errs, fmt.Sprintf("%s: must match %s", at, n.pattern))
+		}
+		if n.format != "" && !matchesFormat(n.format, v) {
+			errs = append(errs, fmt.Sprintf("%s: must be a valid %s", at, n.format))
+		}
+
+	case float64:
+		if n.minimum != nil && v < *n.minimum {
+			errs = append(errs, fmt.Sprintf("%s: must be at least %v", at, *n.minimum))
+		}
+		if n.maximum != nil && v > *n.maximum {
+			errs = append(errs, fmt.Sprintf("%s: must be at most %v", at, *n.maximum))
+		}
+	}
+	return errs
+}
+
+// matchesType reports whether value is one of the node's types
+func (n *Node) matchesType(value interface{}) bool {
+	actual := typeOf(value)
+	for _, t := range n.types {
+		if t == actual {
+			return true
+		}
+		if t == "number" && actual == "integer" {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf returns the JSON Schema type of a decoded JSON value
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// matchesFormat checks a string against a supported format
+func matchesFormat(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	case "email":
+		_, err := mail.ParseAddress(s)
+		return err == nil
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	}
+	return true
+}
+
+func containsValue(list []interface{}, value interface{}) bool {
+	for _, v := range list {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func intKeyword(raw map[string]interface{}, key, path string) (*int, error) {
+	v, ok := raw[key]
+	if !ok {
+		return nil, nil
+	}
+	f, ok := v.(float64)
+	if !ok || f < 0 || f != math.Trunc(f) {
+		return nil, fmt.Errorf("%s: %s must be a non-negative integer", pathOrRoot(path), key)
+	}
+	i := int(f)
+	return &i, nil
+}
+
+func numberKeyword(raw map[string]interface{}, key, path string) (*float64, error) {
+	v, ok := raw[key]
+	if !ok {
+		return nil, nil
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return nil, fmt.Errorf("%s: %s must be a number", pathOrRoot(path), key)
+	}
+	return &f, nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "data"
+	}
+	return path
+}
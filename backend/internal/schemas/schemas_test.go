@@ -0,0 +1,109 @@
+package schemas
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testSchema() *Schema {
+	return &Schema{
+		SAID:    "EMatouEndorsementSchemaV1",
+		Version: "1.0.0",
+		Body: json.RawMessage(`{
+			"type": "object",
+			"required": ["category", "statement"],
+			"properties": {
+				"category": {"type": "string", "enum": ["skill", "character", "contribution"]},
+				"statement": {"type": "string", "minLength": 1, "maxLength": 280},
+				"endorsedAt": {"type": "string", "format": "date-time"},
+				"weight": {"type": "integer", "minimum": 1, "maximum": 5},
+				"tags": {"type": "array", "maxItems": 2, "items": {"type": "string", "pattern": "^[a-z-]+$"}}
+			}
+		}`),
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	if err := testSchema().Validate(); err != nil {
+		t.Fatalf("expected valid schema, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		schema *Schema
+	}{
+		{"missing SAID", &Schema{Version: "1", Body: json.RawMessage(`{}`)}},
+		{"SAID with slash", &Schema{SAID: "a/b", Version: "1", Body: json.RawMessage(`{}`)}},
+		{"missing version", &Schema{SAID: "ES", Body: json.RawMessage(`{}`)}},
+		{"missing body", &Schema{SAID: "ES", Version: "1"}},
+		{"body not an object", &Schema{SAID: "ES", Version: "1", Body: json.RawMessage(`[]`)}},
+		{"unknown type", &Schema{SAID: "ES", Version: "1", Body: json.RawMessage(`{"type":"text"}`)}},
+		{"unsupported keyword", &Schema{SAID: "ES", Version: "1", Body: json.RawMessage(`{"properties":{"a":{"oneOf":[]}}}`)}},
+		{"bad pattern", &Schema{SAID: "ES", Version: "1", Body: json.RawMessage(`{"pattern":"("}`)}},
+		{"unsupported format", &Schema{SAID: "ES", Version: "1", Body: json.RawMessage(`{"format":"ipv4"}`)}},
+		{"negative minLength", &Schema{SAID: "ES", Version: "1", Body: json.RawMessage(`{"minLength":-1}`)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.schema.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestSchema_ValidateData(t *testing.T) {
+	schema := testSchema()
+
+	valid := `{"category":"skill","statement":"Great facilitator","endorsedAt":"2026-03-01T12:00:00Z","weight":3,"tags":["facilitation"],"extra":true}`
+	if err := schema.ValidateData(json.RawMessage(valid)); err != nil {
+		t.Fatalf("expected valid data, got %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"not an object", `"hello"`, "data: expected object"},
+		{"missing required", `{"category":"skill"}`, "statement: is required"},
+		{"bad enum", `{"category":"vibes","statement":"x"}`, "category: must be one of"},
+		{"empty string", `{"category":"skill","statement":""}`, "statement: must be at least 1 characters"},
+		{"bad format", `{"category":"skill","statement":"x","endorsedAt":"yesterday"}`, "endorsedAt: must be a valid date-time"},
+		{"not an integer", `{"category":"skill","statement":"x","weight":2.5}`, "weight: expected integer"},
+		{"above maximum", `{"category":"skill","statement":"x","weight":9}`, "weight: must be at most 5"},
+		{"too many items", `{"category":"skill","statement":"x","tags":["a","b","c"]}`, "tags: must have at most 2 items"},
+		{"item pattern", `{"category":"skill","statement":"x","tags":["Bad Tag"]}`, "tags[0]: must match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.ValidateData(json.RawMessage(tt.data))
+			if err == nil {
+				t.Fatal("expected validation error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %q", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSchema_AdditionalProperties(t *testing.T) {
+	schema := &Schema{
+		SAID:    "ES",
+		Version: "1",
+		Body:    json.RawMessage(`{"type":"object","additionalProperties":false,"properties":{"role":{"const":"Member"}}}`),
+	}
+
+	if err := schema.ValidateData(json.RawMessage(`{"role":"Member"}`)); err != nil {
+		t.Errorf("expected valid data, got %v", err)
+	}
+	err := schema.ValidateData(json.RawMessage(`{"role":"Admin","nickname":"x"}`))
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	if !strings.Contains(err.Error(), "role: must be Member") || !strings.Contains(err.Error(), "nickname: is not allowed") {
+		t.Errorf("expected both problems reported, got %q", err)
+	}
+}
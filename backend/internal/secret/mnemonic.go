@@ -0,0 +1,119 @@
+// Package secret provides types for handling sensitive values such as the
+// user's BIP39 mnemonic. Secrets refuse to be printed or serialized by
+// accident and can be wiped from memory once they are no longer needed.
+package secret
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+)
+
+// Redacted is what a secret renders as in logs, fmt output, and JSON
+const Redacted = "[REDACTED]"
+
+// Mnemonic holds a BIP39 mnemonic phrase in a zeroable byte buffer.
+//
+// fmt verbs, encoding/json, and text/YAML marshaling all produce Redacted.
+// The phrase is only available through Reveal, which callers should use at
+// the point of key derivation and not retain. A nil *Mnemonic is valid and
+// behaves as empty.
+//
+// Go strings are immutable, so the string returned by Reveal (and any string
+// the mnemonic was created from) cannot be wiped; Zero clears the copy held
+// here so long-lived holders such as UserIdentity don't keep it around.
+type Mnemonic struct {
+	b []byte
+}
+
+// NewMnemonic copies phrase into a new Mnemonic
+func NewMnemonic(phrase string) *Mnemonic {
+	if phrase == "" {
+		return nil
+	}
+	return &Mnemonic{b: []byte(phrase)}
+}
+
+// IsEmpty returns true if no phrase is held
+func (m *Mnemonic) IsEmpty() bool {
+	return m == nil || len(m.b) == 0
+}
+
+// Reveal returns the phrase. Keep the result local to the derivation call.
+func (m *Mnemonic) Reveal() string {
+	if m == nil {
+		return ""
+	}
+	return string(m.b)
+}
+
+// Clone returns an independent copy that can be zeroed separately
+func (m *Mnemonic) Clone() *Mnemonic {
+	if m.IsEmpty() {
+		return nil
+	}
+	b := make([]byte, len(m.b))
+	copy(b, m.b)
+	return &Mnemonic{b: b}
+}
+
+// Equal compares two mnemonics in constant time
+func (m *Mnemonic) Equal(other *Mnemonic) bool {
+	var a, b []byte
+	if m != nil {
+		a = m.b
+	}
+	if other != nil {
+		b = other.b
+	}
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// Zero overwrites the held phrase and empties the mnemonic
+func (m *Mnemonic) Zero() {
+	if m == nil {
+		return
+	}
+	for i := range m.b {
+		m.b[i] = 0
+	}
+	m.b = nil
+}
+
+// String implements fmt.Stringer
+func (m *Mnemonic) String() string {
+	return Redacted
+}
+
+// GoString implements fmt.GoStringer so %#v is redacted too
+func (m *Mnemonic) GoString() string {
+	return Redacted
+}
+
+// Format implements fmt.Formatter, redacting every verb
+func (m *Mnemonic) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, Redacted)
+}
+
+// MarshalJSON always emits the redacted placeholder
+func (m *Mnemonic) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
+
+// MarshalText always emits the redacted placeholder (covers YAML and text encoders)
+func (m *Mnemonic) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// UnmarshalJSON accepts a JSON string so request bodies can carry a mnemonic
+func (m *Mnemonic) UnmarshalJSON(data []byte) error {
+	var phrase string
+	if err := json.Unmarshal(data, &phrase); err != nil {
+		return fmt.Errorf("mnemonic must be a string: %w", err)
+	}
+	m.Zero()
+	if phrase != "" {
+		m.b = []byte(phrase)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const testPhrase = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestMnemonic_RedactedOutput(t *testing.T) {
+	m := NewMnemonic(testPhrase)
+
+	for _, verb := range []string{"%v", "%s", "%q", "%+v", "%#v", "%x"} {
+		out := fmt.Sprintf(verb, m)
+		if strings.Contains(out, "abandon") {
+			t.Errorf("%s leaked mnemonic: %s", verb, out)
+		}
+	}
+
+	type wrapper struct {
+		Mnemonic *Mnemonic `json:"mnemonic"`
+	}
+	data, err := json.Marshal(wrapper{Mnemonic: m})
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if strings.Contains(string(data), "abandon") {
+		t.Errorf("JSON leaked mnemonic: %s", data)
+	}
+	if out := fmt.Sprintf("%+v", wrapper{Mnemonic: m}); strings.Contains(out, "abandon") {
+		t.Errorf("struct formatting leaked mnemonic: %s", out)
+	}
+}
+
+func TestMnemonic_UnmarshalAndReveal(t *testing.T) {
+	var req struct {
+		AID      string    `json:"aid"`
+		Mnemonic *Mnemonic `json:"mnemonic"`
+	}
+	body := fmt.Sprintf(`{"aid":"EAlice","mnemonic":%q}`, testPhrase)
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if req.Mnemonic.Reveal() != testPhrase {
+		t.Errorf("expected phrase to round-trip, got %q", req.Mnemonic.Reveal())
+	}
+
+	if err := json.Unmarshal([]byte(`{"mnemonic":42}`), &req); err == nil {
+		t.Error("expected error for non-string mnemonic")
+	}
+}
+
+func TestMnemonic_ZeroAndClone(t *testing.T) {
+	m := NewMnemonic(testPhrase)
+	clone := m.Clone()
+	buf := m.b
+
+	m.Zero()
+	if !m.IsEmpty() {
+		t.Error("expected mnemonic to be empty after Zero")
+	}
+	for _, b := range buf {
+		if b != 0 {
+			t.Fatal("expected backing buffer to be wiped")
+		}
+	}
+	if clone.Reveal() != testPhrase {
+		t.Error("clone should be unaffected by zeroing the original")
+	}
+
+	var nilMnemonic *Mnemonic
+	if !nilMnemonic.IsEmpty() || nilMnemonic.Reveal() != "" {
+		t.Error("nil mnemonic should behave as empty")
+	}
+	nilMnemonic.Zero()
+
+	if !clone.Equal(NewMnemonic(testPhrase)) || clone.Equal(NewMnemonic("other")) {
+		t.Error("Equal returned wrong result")
+	}
+}
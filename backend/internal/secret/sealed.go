@@ -0,0 +1,164 @@
+package secret
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// sealedFormat marks a file written by Seal
+const sealedFormat = "matou-sealed-v1"
+
+// sealIterations is the PBKDF2-SHA256 work factor for new files. Files
+// record their own count, so it can be raised without breaking old ones.
+const sealIterations = 600_000
+
+// ErrLocked is returned when a sealed file is read without a Sealer
+var ErrLocked = errors.New("file is encrypted and no passphrase is configured")
+
+// ErrWrongPassphrase is returned when a sealed file doesn't decrypt
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted file")
+
+// sealedFile is the on-disk envelope. Byte fields encode as base64.
+type sealedFile struct {
+	Format     string `json:"format"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Sealer encrypts files at rest with AES-256-GCM under a key derived from
+// a passphrase. The passphrase usually comes from the OS keyring via the
+// desktop shell, or from the environment.
+//
+// A nil *Sealer is valid and leaves data in plaintext, so callers can hold
+// one unconditionally. Keys are derived once per salt and cached.
+type Sealer struct {
+	passphrase []byte
+	salt       []byte // Salt for files sealed by this process
+
+	mu   sync.Mutex
+	keys map[string][]byte // Derived key by salt
+}
+
+// NewSealer returns a Sealer for passphrase, or nil if it is empty
+func NewSealer(passphrase string) (*Sealer, error) {
+	if passphrase == "" {
+		return nil, nil
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generating salt: %w", err)
+	}
+	return &Sealer{
+		passphrase: []byte(passphrase),
+		salt:       salt,
+		keys:       make(map[string][]byte),
+	}, nil
+}
+
+// Enabled returns true if data is encrypted
+func (s *Sealer) Enabled() bool {
+	return s != nil
+}
+
+// Seal encrypts plaintext into a sealed envelope. With a nil Sealer the
+// plaintext is returned unchanged.
+func (s *Sealer) Seal(plaintext []byte) ([]byte, error) {
+	if s == nil {
+		return plaintext, nil
+	}
+	gcm, err := s.cipher(s.salt, sealIterations)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+	return json.MarshalIndent(sealedFile{
+		Format:     sealedFormat,
+		KDF:        "pbkdf2-sha256",
+		Iterations: sealIterations,
+		Salt:       s.salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, []byte(sealedFormat)),
+	}, "", "  ")
+}
+
+// Open decrypts data written by Seal. Data that isn't sealed is returned
+// unchanged, so plaintext files from before encryption was turned on still
+// load; use IsSealed to find files that need migrating.
+func (s *Sealer) Open(data []byte) ([]byte, error) {
+	envelope, ok := parseSealed(data)
+	if !ok {
+		return data, nil
+	}
+	if s == nil {
+		return nil, ErrLocked
+	}
+	if envelope.KDF != "pbkdf2-sha256" || envelope.Iterations <= 0 {
+		return nil, fmt.Errorf("unsupported key derivation %s", envelope.KDF)
+	}
+	gcm, err := s.cipher(envelope.Salt, envelope.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope.Nonce) != gcm.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+	plaintext, err := gcm.Open(nil, envelope.Nonce, envelope.Ciphertext, []byte(sealedFormat))
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+// IsSealed returns true if data was written by Seal
+func IsSealed(data []byte) bool {
+	_, ok := parseSealed(data)
+	return ok
+}
+
+func parseSealed(data []byte) (*sealedFile, bool) {
+	if !bytes.Contains(data, []byte(sealedFormat)) {
+		return nil, false
+	}
+	var envelope sealedFile
+	if err := json.Unmarshal(data, &envelope); err != nil || envelope.Format != sealedFormat {
+		return nil, false
+	}
+	return &envelope, true
+}
+
+// cipher returns AES-GCM keyed for salt, deriving the key on first use
+func (s *Sealer) cipher(salt []byte, iterations int) (cipher.AEAD, error) {
+	s.mu.Lock()
+	cacheKey := fmt.Sprintf("%x:%d", salt, iterations)
+	key, ok := s.keys[cacheKey]
+	if !ok {
+		var err error
+		key, err = pbkdf2.Key(sha256.New, string(s.passphrase), salt, iterations, 32)
+		if err != nil {
+			s.mu.Unlock()
+			return nil, fmt.Errorf("deriving key: %w", err)
+		}
+		s.keys[cacheKey] = key
+	}
+	s.mu.Unlock()
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
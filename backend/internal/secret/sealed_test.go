@@ -0,0 +1,78 @@
+package secret
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSealer_RoundTrip(t *testing.T) {
+	s, err := NewSealer("correct horse battery staple")
+	if err != nil {
+		t.Fatalf("NewSealer failed: %v", err)
+	}
+	plaintext := []byte(`{"mnemonic":"` + testPhrase + `"}`)
+
+	sealed, err := s.Seal(plaintext)
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+	if bytes.Contains(sealed, []byte("abandon")) {
+		t.Fatalf("sealed data leaked plaintext: %s", sealed)
+	}
+	if !IsSealed(sealed) || IsSealed(plaintext) {
+		t.Error("IsSealed misclassified data")
+	}
+
+	opened, err := s.Open(sealed)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Errorf("expected %s, got %s", plaintext, opened)
+	}
+
+	// Another process with the same passphrase uses a different salt but
+	// can still open the file
+	other, _ := NewSealer("correct horse battery staple")
+	if opened, err := other.Open(sealed); err != nil || !bytes.Equal(opened, plaintext) {
+		t.Errorf("expected a second sealer to open the file, got %v", err)
+	}
+}
+
+func TestSealer_WrongPassphraseAndLocked(t *testing.T) {
+	s, _ := NewSealer("one")
+	sealed, err := s.Seal([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+
+	wrong, _ := NewSealer("two")
+	if _, err := wrong.Open(sealed); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("expected ErrWrongPassphrase, got %v", err)
+	}
+
+	var none *Sealer
+	if _, err := none.Open(sealed); !errors.Is(err, ErrLocked) {
+		t.Errorf("expected ErrLocked, got %v", err)
+	}
+}
+
+func TestSealer_NilPassesThrough(t *testing.T) {
+	s, err := NewSealer("")
+	if err != nil || s != nil || s.Enabled() {
+		t.Fatalf("expected nil sealer for empty passphrase, got %v, %v", s, err)
+	}
+	data := []byte(`{"aid":"EAID"}`)
+	sealed, _ := s.Seal(data)
+	opened, _ := s.Open(sealed)
+	if !bytes.Equal(sealed, data) || !bytes.Equal(opened, data) {
+		t.Error("expected nil sealer to leave data unchanged")
+	}
+
+	// Plaintext files load through an enabled sealer too (migration)
+	enabled, _ := NewSealer("pass")
+	if opened, err := enabled.Open(data); err != nil || !bytes.Equal(opened, data) {
+		t.Errorf("expected plaintext passed through, got %s, %v", opened, err)
+	}
+}
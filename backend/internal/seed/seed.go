@@ -0,0 +1,474 @@
+// Package seed generates synthetic community data for development.
+// It produces members, credentials, endorsements, and profiles with
+// realistic distributions so frontend and performance work can run against
+// a populated backend without manual data entry. Never use in production.
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/keri"
+	"github.com/matou-dao/backend/internal/trust"
+)
+
+// Credential schema identifiers used for generated credentials.
+const (
+	SchemaMembership  = "EMatouMembershipSchemaV1"
+	SchemaSteward     = "EOperationsStewardSchemaV1"
+	SchemaInvitation  = "EInvitationSchemaV1"
+	SchemaSelfClaim   = "ESelfClaimSchemaV1"
+	SchemaEndorsement = trust.EndorsementSchema
+)
+
+// Options controls the size and shape of the generated dataset.
+type Options struct {
+	// Members is the number of synthetic members to generate.
+	Members int
+	// OrgAID is the issuing organization. Generated if empty.
+	OrgAID string
+	// Seed makes generation deterministic. Zero uses the current time.
+	Seed int64
+	// Span is how far back membership join dates are spread.
+	Span time.Duration
+	// MeanEndorsements is the average number of endorsements given per member.
+	MeanEndorsements float64
+	// StewardRatio is the fraction of members holding a steward credential.
+	StewardRatio float64
+	// InviteRatio is the fraction of members who were invited by another member.
+	InviteRatio float64
+}
+
+// DefaultOptions returns options producing a mid-sized community.
+func DefaultOptions() *Options {
+	return &Options{
+		Members:          50,
+		Span:             2 * 365 * 24 * time.Hour,
+		MeanEndorsements: 3,
+		StewardRatio:     0.04,
+		InviteRatio:      0.6,
+	}
+}
+
+// Member is a synthetic community member.
+type Member struct {
+	AID                string    `json:"aid"`
+	DisplayName        string    `json:"displayName"`
+	Role               string    `json:"role"`
+	VerificationStatus string    `json:"verificationStatus"`
+	JoinedAt           time.Time `json:"joinedAt"`
+	Interests          []string  `json:"interests"`
+	Skills             []string  `json:"skills"`
+	Location           string    `json:"location"`
+	InvitedBy          string    `json:"invitedBy,omitempty"`
+}
+
+// Dataset is the full generated dataset.
+type Dataset struct {
+	OrgAID       string                       `json:"orgAid"`
+	Members      []*Member                    `json:"members"`
+	Credentials  []*anystore.CachedCredential `json:"credentials"`
+	Endorsements int                          `json:"endorsements"`
+	Profiles     []*anysync.ObjectPayload     `json:"profiles"`
+}
+
+// roleWeights approximates how roles are spread in an established community:
+// most people are plain members and only a handful hold elevated roles.
+var roleWeights = []struct {
+	role   string
+	weight float64
+}{
+	{"Member", 0.55},
+	{"Verified Member", 0.2},
+	{"Trusted Member", 0.1},
+	{"Contributor", 0.08},
+	{"Expert Member", 0.04},
+	{"Moderator", 0.03},
+}
+
+var (
+	firstNames = []string{"Aroha", "Tama", "Mere", "Wiremu", "Hana", "Rangi", "Kiri", "Nikau", "Anahera", "Tane",
+		"Moana", "Ari", "Maia", "Rawiri", "Ngaio", "Hemi", "Ataahua", "Manaia", "Kahu", "Tui"}
+	lastNames = []string{"Ngata", "Parata", "Walker", "Tipene", "Horomona", "Te Awa", "Rewi", "Smith", "Karaka",
+		"Tamihana", "Wihongi", "Pomare", "Harawira", "Kereama", "Morgan"}
+	interests = []string{"governance", "land_restoration", "language", "education", "health", "technology",
+		"arts", "youth", "finance", "food_sovereignty", "storytelling", "research"}
+	skills = []string{"facilitation", "software", "design", "legal", "accounting", "teaching", "writing",
+		"translation", "project_management", "community_organising", "carving", "weaving"}
+	locations = []string{"Auckland, NZ", "Wellington, NZ", "Rotorua, NZ", "Gisborne, NZ", "Whangārei, NZ",
+		"Christchurch, NZ", "Sydney, AU", "Honolulu, US", "Vancouver, CA"}
+)
+
+// Generator produces synthetic datasets.
+type Generator struct {
+	opts *Options
+	rng  *rand.Rand
+	now  time.Time
+}
+
+// NewGenerator creates a generator. Nil options use DefaultOptions.
+func NewGenerator(opts *Options) *Generator {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	if opts.Span <= 0 {
+		opts.Span = DefaultOptions().Span
+	}
+	return &Generator{
+		opts: opts,
+		rng:  rand.New(rand.NewSource(seed)),
+		now:  time.Now().UTC(),
+	}
+}
+
+// Generate builds a dataset according to the generator options.
+func (g *Generator) Generate() *Dataset {
+	orgAID := g.opts.OrgAID
+	if orgAID == "" {
+		orgAID = g.aid()
+	}
+
+	ds := &Dataset{OrgAID: orgAID}
+
+	for i := 0; i < g.opts.Members; i++ {
+		ds.Members = append(ds.Members, g.member())
+	}
+
+	// Earlier joiners first so inviters always predate invitees
+	sortByJoined(ds.Members)
+
+	for i, m := range ds.Members {
+		// Anyone after the founding cohort may have been invited by an earlier member
+		if i > 0 && g.rng.Float64() < g.opts.InviteRatio {
+			inviter := ds.Members[g.rng.Intn(i)]
+			m.InvitedBy = inviter.AID
+			ds.Credentials = append(ds.Credentials, g.credential(SchemaInvitation, inviter.AID, m.AID, map[string]interface{}{
+				"invitedAt": m.JoinedAt.Add(-time.Duration(g.rng.Intn(72)) * time.Hour).Format(time.RFC3339),
+			}))
+		}
+
+		ds.Credentials = append(ds.Credentials, g.credential(SchemaMembership, orgAID, m.AID, keri.CredentialData{
+			CommunityName:      "MATOU",
+			Role:               m.Role,
+			VerificationStatus: m.VerificationStatus,
+			Permissions:        keri.GetPermissionsForRole(m.Role),
+			JoinedAt:           m.JoinedAt.Format(time.RFC3339),
+		}))
+
+		ds.Credentials = append(ds.Credentials, g.credential(SchemaSelfClaim, m.AID, m.AID, map[string]interface{}{
+			"displayName": m.DisplayName,
+		}))
+
+		if g.rng.Float64() < g.opts.StewardRatio {
+			m.Role = "Operations Steward"
+			ds.Credentials = append(ds.Credentials, g.credential(SchemaSteward, orgAID, m.AID, map[string]interface{}{
+				"role":      m.Role,
+				"grantedAt": g.between(m.JoinedAt, g.now).Format(time.RFC3339),
+			}))
+		}
+
+		ds.Profiles = append(ds.Profiles, g.sharedProfile(m), g.communityProfile(m, ds.Credentials))
+	}
+
+	ds.Endorsements = g.endorse(ds)
+	return ds
+}
+
+// endorse adds endorsement credentials using preferential attachment: members
+// who already hold endorsements are more likely to receive more, which yields
+// the long-tailed distribution seen in real reputation graphs.
+func (g *Generator) endorse(ds *Dataset) int {
+	if len(ds.Members) < 2 || g.opts.MeanEndorsements <= 0 {
+		return 0
+	}
+
+	received := make([]int, len(ds.Members))
+	seen := make(map[string]bool)
+	count := 0
+
+	for i, from := range ds.Members {
+		n := g.poisson(g.opts.MeanEndorsements)
+		for j := 0; j < n; j++ {
+			k := g.pickWeighted(received, i)
+			if k < 0 {
+				break
+			}
+			to := ds.Members[k]
+			key := from.AID + ":" + to.AID
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			received[k]++
+
+			endorsedAt := from.JoinedAt
+			if to.JoinedAt.After(endorsedAt) {
+				endorsedAt = to.JoinedAt
+			}
+			ds.Credentials = append(ds.Credentials, g.credential(SchemaEndorsement, from.AID, to.AID, map[string]interface{}{
+				"category":   skills[g.rng.Intn(len(skills))],
+				"endorsedAt": g.between(endorsedAt, g.now).Format(time.RFC3339),
+			}))
+			count++
+		}
+	}
+	return count
+}
+
+// pickWeighted picks a member index (excluding self) with probability
+// proportional to 1 + endorsements already received.
+func (g *Generator) pickWeighted(received []int, self int) int {
+	total := 0
+	for i, r := range received {
+		if i != self {
+			total += r + 1
+		}
+	}
+	if total == 0 {
+		return -1
+	}
+	target := g.rng.Intn(total)
+	for i, r := range received {
+		if i == self {
+			continue
+		}
+		target -= r + 1
+		if target < 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+func (g *Generator) member() *Member {
+	joined := g.joinDate()
+	role := g.role()
+	status := "unverified"
+	switch role {
+	case "Verified Member", "Contributor":
+		status = "community_verified"
+	case "Trusted Member", "Expert Member", "Moderator":
+		status = "expert_verified"
+	}
+	return &Member{
+		AID:                g.aid(),
+		DisplayName:        firstNames[g.rng.Intn(len(firstNames))] + " " + lastNames[g.rng.Intn(len(lastNames))],
+		Role:               role,
+		VerificationStatus: status,
+		JoinedAt:           joined,
+		Interests:          g.sample(interests, 1+g.rng.Intn(4)),
+		Skills:             g.sample(skills, g.rng.Intn(4)),
+		Location:           locations[g.rng.Intn(len(locations))],
+	}
+}
+
+// joinDate skews toward recent dates to mimic a growing community.
+func (g *Generator) joinDate() time.Time {
+	age := time.Duration(math.Pow(g.rng.Float64(), 2) * float64(g.opts.Span))
+	return g.now.Add(-age).Truncate(time.Second)
+}
+
+func (g *Generator) role() string {
+	r := g.rng.Float64()
+	for _, rw := range roleWeights {
+		if r < rw.weight {
+			return rw.role
+		}
+		r -= rw.weight
+	}
+	return "Member"
+}
+
+func (g *Generator) credential(schema, issuer, subject string, data interface{}) *anystore.CachedCredential {
+	// Round-trip through JSON so Data matches what the store reads back
+	var generic interface{}
+	if bytes, err := json.Marshal(data); err == nil {
+		json.Unmarshal(bytes, &generic)
+	}
+	return &anystore.CachedCredential{
+		ID:         g.said(),
+		IssuerAID:  issuer,
+		SubjectAID: subject,
+		SchemaID:   schema,
+		Data:       generic,
+		CachedAt:   g.now,
+		Verified:   true,
+	}
+}
+
+func (g *Generator) sharedProfile(m *Member) *anysync.ObjectPayload {
+	data, _ := json.Marshal(map[string]interface{}{
+		"aid":                    m.AID,
+		"displayName":            m.DisplayName,
+		"bio":                    fmt.Sprintf("Kia ora, I'm %s.", m.DisplayName),
+		"location":               m.Location,
+		"participationInterests": m.Interests,
+		"skills":                 m.Skills,
+		"createdAt":              m.JoinedAt.Format(time.RFC3339),
+		"updatedAt":              m.JoinedAt.Format(time.RFC3339),
+		"lastActiveAt":           g.between(m.JoinedAt, g.now).Format(time.RFC3339),
+		"typeVersion":            1,
+	})
+	return &anysync.ObjectPayload{
+		ID:        "SharedProfile-" + m.AID,
+		Type:      "SharedProfile",
+		Data:      data,
+		Timestamp: m.JoinedAt.Unix(),
+		Version:   1,
+	}
+}
+
+func (g *Generator) communityProfile(m *Member, creds []*anystore.CachedCredential) *anysync.ObjectPayload {
+	var held []string
+	for _, c := range creds {
+		if c.SubjectAID == m.AID && c.SchemaID == SchemaMembership {
+			held = append(held, c.ID)
+		}
+	}
+	credential := ""
+	if len(held) > 0 {
+		credential = held[0]
+	}
+	data, _ := json.Marshal(map[string]interface{}{
+		"userAID":      m.AID,
+		"displayName":  m.DisplayName,
+		"credential":   credential,
+		"credentials":  held,
+		"role":         m.Role,
+		"memberSince":  m.JoinedAt.Format(time.RFC3339),
+		"lastActiveAt": g.between(m.JoinedAt, g.now).Format(time.RFC3339),
+		"permissions":  []string{"participate", "vote", "propose"},
+	})
+	return &anysync.ObjectPayload{
+		ID:        "CommunityProfile-" + m.AID,
+		Type:      "CommunityProfile",
+		Data:      data,
+		Timestamp: m.JoinedAt.Unix(),
+		Version:   1,
+	}
+}
+
+const aidAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+// aid returns a random 44-character identifier shaped like a KERI AID.
+func (g *Generator) aid() string {
+	return "E" + g.randomString(43)
+}
+
+// said returns a random 44-character identifier shaped like a credential SAID.
+func (g *Generator) said() string {
+	return "E" + g.randomString(43)
+}
+
+func (g *Generator) randomString(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = aidAlphabet[g.rng.Intn(len(aidAlphabet))]
+	}
+	return string(b)
+}
+
+func (g *Generator) between(from, to time.Time) time.Time {
+	if !to.After(from) {
+		return from
+	}
+	return from.Add(time.Duration(g.rng.Int63n(int64(to.Sub(from))))).Truncate(time.Second)
+}
+
+func (g *Generator) sample(pool []string, n int) []string {
+	if n > len(pool) {
+		n = len(pool)
+	}
+	out := make([]string, 0, n)
+	for _, i := range g.rng.Perm(len(pool))[:n] {
+		out = append(out, pool[i])
+	}
+	return out
+}
+
+// poisson draws from a Poisson distribution using Knuth's algorithm.
+func (g *Generator) poisson(mean float64) int {
+	l := math.Exp(-mean)
+	k := 0
+	p := 1.0
+	for {
+		p *= g.rng.Float64()
+		if p <= l {
+			return k
+		}
+		k++
+	}
+}
+
+func sortByJoined(members []*Member) {
+	for i := 1; i < len(members); i++ {
+		for j := i; j > 0 && members[j].JoinedAt.Before(members[j-1].JoinedAt); j-- {
+			members[j], members[j-1] = members[j-1], members[j]
+		}
+	}
+}
+
+// WriteToStore caches all generated credentials in the local anystore.
+func (ds *Dataset) WriteToStore(ctx context.Context, store *anystore.LocalStore) error {
+	for _, cred := range ds.Credentials {
+		if err := store.StoreCredential(ctx, cred); err != nil {
+			return fmt.Errorf("storing credential %s: %w", cred.ID, err)
+		}
+	}
+	return nil
+}
+
+// WriteToSpaces routes credentials into members' private spaces and writes
+// profiles into the community spaces configured on the space manager. It is
+// intended for the in-memory mock client used by tests and benchmarks.
+func (ds *Dataset) WriteToSpaces(ctx context.Context, sm *anysync.SpaceManager, spaceStore anysync.SpaceStore) error {
+	for _, cred := range ds.Credentials {
+		if _, err := sm.RouteCredential(ctx, &anysync.Credential{
+			SAID:      cred.ID,
+			Issuer:    cred.IssuerAID,
+			Recipient: cred.SubjectAID,
+			Schema:    cred.SchemaID,
+			Data:      cred.Data,
+		}, spaceStore); err != nil {
+			return fmt.Errorf("routing credential %s: %w", cred.ID, err)
+		}
+	}
+
+	client := sm.GetClient()
+	for _, profile := range ds.Profiles {
+		spaceID := sm.GetCommunitySpaceID()
+		if profile.Type == "CommunityProfile" {
+			spaceID = sm.GetCommunityReadOnlySpaceID()
+		}
+		if spaceID == "" {
+			continue
+		}
+		data, err := json.Marshal(profile)
+		if err != nil {
+			return fmt.Errorf("marshaling profile %s: %w", profile.ID, err)
+		}
+		if _, err := client.SyncDocument(ctx, spaceID, profile.ID, data); err != nil {
+			return fmt.Errorf("writing profile %s: %w", profile.ID, err)
+		}
+	}
+	return nil
+}
+
+// Summary returns per-schema credential counts for reporting.
+func (ds *Dataset) Summary() map[string]int {
+	counts := make(map[string]int)
+	for _, cred := range ds.Credentials {
+		counts[cred.SchemaID]++
+	}
+	return counts
+}
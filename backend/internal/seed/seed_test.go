@@ -0,0 +1,155 @@
+package seed
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	anysyncTesting "github.com/matou-dao/backend/internal/anysync/testing"
+	"github.com/matou-dao/backend/internal/trust"
+)
+
+func TestGenerate_Deterministic(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Members = 20
+	opts.Seed = 42
+
+	a := NewGenerator(opts).Generate()
+
+	opts2 := DefaultOptions()
+	opts2.Members = 20
+	opts2.Seed = 42
+	b := NewGenerator(opts2).Generate()
+
+	if len(a.Credentials) != len(b.Credentials) {
+		t.Fatalf("expected same credential count, got %d and %d", len(a.Credentials), len(b.Credentials))
+	}
+	for i := range a.Members {
+		if a.Members[i].AID != b.Members[i].AID {
+			t.Errorf("member %d AID differs between runs", i)
+		}
+	}
+}
+
+func TestGenerate_Shape(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Members = 100
+	opts.Seed = 7
+	opts.OrgAID = "EOrgSeedTest"
+
+	ds := NewGenerator(opts).Generate()
+
+	if ds.OrgAID != "EOrgSeedTest" {
+		t.Errorf("expected org AID to be preserved, got %s", ds.OrgAID)
+	}
+	if len(ds.Members) != 100 {
+		t.Fatalf("expected 100 members, got %d", len(ds.Members))
+	}
+
+	summary := ds.Summary()
+	if summary[SchemaMembership] != 100 {
+		t.Errorf("expected one membership per member, got %d", summary[SchemaMembership])
+	}
+	if summary[SchemaSelfClaim] != 100 {
+		t.Errorf("expected one self-claim per member, got %d", summary[SchemaSelfClaim])
+	}
+	if summary[SchemaEndorsement] != ds.Endorsements {
+		t.Errorf("endorsement count mismatch: %d vs %d", summary[SchemaEndorsement], ds.Endorsements)
+	}
+	if ds.Endorsements == 0 {
+		t.Error("expected some endorsements")
+	}
+	if len(ds.Profiles) != 200 {
+		t.Errorf("expected shared and community profile per member, got %d", len(ds.Profiles))
+	}
+
+	for i := 1; i < len(ds.Members); i++ {
+		if ds.Members[i].JoinedAt.Before(ds.Members[i-1].JoinedAt) {
+			t.Fatal("members should be ordered by join date")
+		}
+	}
+
+	for _, cred := range ds.Credentials {
+		if cred.SchemaID == SchemaEndorsement && cred.IssuerAID == cred.SubjectAID {
+			t.Errorf("self-endorsement generated: %s", cred.ID)
+		}
+	}
+}
+
+func TestDataset_WriteToStoreBuildsTrustGraph(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "seed_test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	store, err := anystore.NewLocalStore(anystore.DefaultConfig(tmpDir))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	opts := DefaultOptions()
+	opts.Members = 15
+	opts.Seed = 3
+	ds := NewGenerator(opts).Generate()
+
+	ctx := context.Background()
+	if err := ds.WriteToStore(ctx, store); err != nil {
+		t.Fatalf("WriteToStore failed: %v", err)
+	}
+
+	count, err := store.CountCredentials(ctx)
+	if err != nil {
+		t.Fatalf("CountCredentials failed: %v", err)
+	}
+	if count != len(ds.Credentials) {
+		t.Errorf("expected %d credentials, got %d", len(ds.Credentials), count)
+	}
+
+	graph, err := trust.NewBuilder(store, ds.OrgAID).Build(ctx)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	// Org node plus every member
+	if graph.NodeCount() != 16 {
+		t.Errorf("expected 16 nodes, got %d", graph.NodeCount())
+	}
+	for _, m := range ds.Members {
+		if node := graph.GetNode(m.AID); node == nil || node.Alias != m.DisplayName {
+			t.Errorf("expected node for %s with alias %q", m.AID, m.DisplayName)
+		}
+	}
+}
+
+func TestDataset_WriteToSpaces(t *testing.T) {
+	client := anysyncTesting.NewMockAnySyncClient()
+	spaceStore := anysyncTesting.NewMockSpaceStore()
+	sm := anysync.NewSpaceManager(client, &anysync.SpaceManagerConfig{
+		CommunitySpaceID:         "community",
+		CommunityReadOnlySpaceID: "community-ro",
+	})
+
+	opts := DefaultOptions()
+	opts.Members = 10
+	opts.Seed = 11
+	ds := NewGenerator(opts).Generate()
+
+	if err := ds.WriteToSpaces(context.Background(), sm, spaceStore); err != nil {
+		t.Fatalf("WriteToSpaces failed: %v", err)
+	}
+
+	if got := len(client.Documents["community"]); got != 10 {
+		t.Errorf("expected 10 shared profiles, got %d", got)
+	}
+	if got := len(client.Documents["community-ro"]); got != 10 {
+		t.Errorf("expected 10 community profiles, got %d", got)
+	}
+
+	spaces, _ := spaceStore.ListAllSpaces(context.Background())
+	if len(spaces) != 10 {
+		t.Errorf("expected a private space per member, got %d", len(spaces))
+	}
+}
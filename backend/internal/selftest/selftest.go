@@ -0,0 +1,144 @@
+// Package selftest runs a list of named checks against a deployment and
+// prints a pass/fail report, for verifying an install with --selftest.
+package selftest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultTimeout bounds each check that doesn't set its own timeout
+const DefaultTimeout = 10 * time.Second
+
+// ErrSkipped is returned (or wrapped) by a check that doesn't apply to this
+// deployment, e.g. SMTP when no server is configured
+var ErrSkipped = errors.New("skipped")
+
+// Skip returns an error marking a check as skipped, with the reason
+func Skip(reason string) error {
+	return fmt.Errorf("%w: %s", ErrSkipped, reason)
+}
+
+// Check is a single named self-test
+type Check struct {
+	Name    string
+	Timeout time.Duration // Defaults to DefaultTimeout
+	Run     func(ctx context.Context) error
+}
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	StatusPass Status = "PASS"
+	StatusFail Status = "FAIL"
+	StatusSkip Status = "SKIP"
+)
+
+// Result is the outcome of running one check
+type Result struct {
+	Name     string
+	Status   Status
+	Err      error
+	Duration time.Duration
+}
+
+// Run runs the checks in order and returns their results. A check that
+// panics or overruns its timeout fails; later checks still run.
+func Run(ctx context.Context, checks []Check) []Result {
+	results := make([]Result, 0, len(checks))
+	for _, c := range checks {
+		results = append(results, runCheck(ctx, c))
+	}
+	return results
+}
+
+func runCheck(ctx context.Context, c Check) Result {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("panic: %v", p)
+			}
+		}()
+		done <- c.Run(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	result := Result{Name: c.Name, Status: StatusPass, Err: err, Duration: time.Since(start)}
+	switch {
+	case errors.Is(err, ErrSkipped):
+		result.Status = StatusSkip
+	case err != nil:
+		result.Status = StatusFail
+	}
+	return result
+}
+
+// Report writes one line per result and a summary, and reports whether
+// every check passed or was skipped
+func Report(w io.Writer, results []Result) bool {
+	var passed, failed, skipped int
+	for _, r := range results {
+		line := fmt.Sprintf("  [%s] %-24s %6dms", r.Status, r.Name, r.Duration.Milliseconds())
+		if r.Err != nil {
+			line += "  " + r.Err.Error()
+		}
+		fmt.Fprintln(w, line)
+
+		switch r.Status {
+		case StatusPass:
+			passed++
+		case StatusFail:
+			failed++
+		case StatusSkip:
+			skipped++
+		}
+	}
+	fmt.Fprintf(w, "\n%d passed, %d failed, %d skipped\n", passed, failed, skipped)
+	return failed == 0
+}
+
+// HTTPCheck returns a check that GETs url and passes on a 2xx response
+func HTTPCheck(name, url string) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			if url == "" {
+				return Skip("no URL configured")
+			}
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			io.Copy(io.Discard, resp.Body)
+			if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+				return fmt.Errorf("%s returned %s", url, resp.Status)
+			}
+			return nil
+		},
+	}
+}
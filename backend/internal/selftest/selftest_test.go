@@ -0,0 +1,64 @@
+package selftest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	checks := []Check{
+		{Name: "ok", Run: func(ctx context.Context) error { return nil }},
+		{Name: "broken", Run: func(ctx context.Context) error { return errors.New("boom") }},
+		{Name: "optional", Run: func(ctx context.Context) error { return Skip("not configured") }},
+		{Name: "panics", Run: func(ctx context.Context) error { panic("oops") }},
+		{Name: "hangs", Timeout: 10 * time.Millisecond, Run: func(ctx context.Context) error {
+			select {}
+		}},
+	}
+
+	results := Run(context.Background(), checks)
+	want := []Status{StatusPass, StatusFail, StatusSkip, StatusFail, StatusFail}
+	for i, r := range results {
+		if r.Status != want[i] {
+			t.Errorf("%s: expected %s, got %s (%v)", r.Name, want[i], r.Status, r.Err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if Report(&buf, results) {
+		t.Error("expected report to fail")
+	}
+	if !strings.Contains(buf.String(), "1 passed, 3 failed, 1 skipped") {
+		t.Errorf("unexpected report:\n%s", buf.String())
+	}
+	if !Report(&buf, results[:1]) {
+		t.Error("expected passing report")
+	}
+}
+
+func TestHTTPCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	results := Run(context.Background(), []Check{
+		HTTPCheck("up", srv.URL+"/health"),
+		HTTPCheck("missing", srv.URL+"/nope"),
+		HTTPCheck("unset", ""),
+	})
+	want := []Status{StatusPass, StatusFail, StatusSkip}
+	for i, r := range results {
+		if r.Status != want[i] {
+			t.Errorf("%s: expected %s, got %s (%v)", r.Name, want[i], r.Status, r.Err)
+		}
+	}
+}
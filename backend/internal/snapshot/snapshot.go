@@ -0,0 +1,463 @@
+// Package snapshot archives a whole MATOU deployment for disaster recovery.
+// A snapshot is a gzipped tar holding the data directory (anystore, space
+// storage and the other state files), the deployment's config files and
+// the identity and space keys. Keys go in as a backup bundle encrypted with
+// the snapshot passphrase; everything else is stored as-is.
+//
+// The manifest, written last, lists every file with its size and SHA-256 so
+// a restore or a recovery drill can tell a complete, untampered archive
+// from a truncated or altered one before anything is replaced.
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/backup"
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+// Format identifies the snapshot archive layout
+const Format = "matou-snapshot-v1"
+
+// Entries in the archive
+const (
+	manifestName = "manifest.json"
+	keysName     = "keys.bundle"
+	dataPrefix   = "data/"
+	configPrefix = "config/"
+)
+
+// keyFiles are carried, decrypted, in the key bundle rather than as data
+// files, so the archive never holds them sealed with a passphrase the
+// restoring machine doesn't know
+var keyFiles = []string{"identity.json", "peer.key", "org-config.yaml", "keys"}
+
+var (
+	// ErrNotSnapshot is returned for archives that aren't snapshots
+	ErrNotSnapshot = errors.New("not a MATOU snapshot")
+	// ErrCorrupt is returned when files don't match the manifest
+	ErrCorrupt = errors.New("snapshot does not match its manifest")
+	// ErrDataDirNotEmpty is returned when restoring over existing data
+	// without force
+	ErrDataDirNotEmpty = errors.New("data directory is not empty")
+)
+
+// File is one archived file
+type File struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest describes a snapshot's contents
+type Manifest struct {
+	Format    string    `json:"format"`
+	CreatedAt time.Time `json:"createdAt"`
+	Files     []File    `json:"files"`
+	// Keys describes the key bundle. It is nil for a node without an
+	// identity, and only filled in on restore and verify when the
+	// passphrase opens the bundle.
+	Keys *backup.Manifest `json:"keys,omitempty"`
+}
+
+// Options configures Create
+type Options struct {
+	DataDir string
+	// ConfigFiles are archived under their base names
+	ConfigFiles []string
+	// Exclude holds path.Match patterns, relative to DataDir, for files
+	// to leave out, such as log files
+	Exclude []string
+	// AtRest opens files sealed at rest and may be nil
+	AtRest *secret.Sealer
+	// Passphrase encrypts the key bundle
+	Passphrase string
+	Now        time.Time
+}
+
+// RestoreOptions configures Restore
+type RestoreOptions struct {
+	DataDir string
+	// ConfigDir receives the archived config files. They are skipped when
+	// it is empty.
+	ConfigDir string
+	// AtRest seals the restored keys and may be nil
+	AtRest     *secret.Sealer
+	Passphrase string
+	// Force replaces a data directory that isn't empty. The old one is
+	// kept next to it.
+	Force bool
+	Now   time.Time
+}
+
+// isKeyFile reports whether a data directory path is carried in the key
+// bundle
+func isKeyFile(rel string) bool {
+	for _, name := range keyFiles {
+		if rel == name || strings.HasPrefix(rel, name+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// excluded reports whether rel matches one of the exclude patterns
+func excluded(rel string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// writer adds files to the archive and records them for the manifest
+type writer struct {
+	tw       *tar.Writer
+	manifest *Manifest
+	modTime  time.Time
+}
+
+func (w *writer) add(name string, r io.Reader, size int64, mode fs.FileMode) error {
+	err := w.tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     int64(mode.Perm()),
+		Size:     size,
+		ModTime:  w.modTime,
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		return err
+	}
+	hash := sha256.New()
+	n, err := io.Copy(w.tw, io.TeeReader(r, hash))
+	if err != nil {
+		return err
+	}
+	if n != size {
+		return fmt.Errorf("%s changed while archiving", name)
+	}
+	w.manifest.Files = append(w.manifest.Files, File{Path: name, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))})
+	return nil
+}
+
+func (w *writer) addFile(name, src string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := w.add(name, f, info.Size(), info.Mode()); err != nil {
+		return fmt.Errorf("archiving %s: %w", name, err)
+	}
+	return nil
+}
+
+// Create writes a snapshot of the deployment to out. The backend should be
+// stopped, or in maintenance mode, so the store isn't written mid-copy.
+func Create(out io.Writer, opts Options) (*Manifest, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	manifest := &Manifest{Format: Format, CreatedAt: now.UTC(), Files: []File{}}
+
+	// Seal the keys first so a short passphrase fails before any copying
+	keys, keysManifest, err := backup.Export(opts.DataDir, opts.AtRest, opts.Passphrase, now)
+	if err != nil && !errors.Is(err, backup.ErrNoIdentity) {
+		return nil, fmt.Errorf("exporting keys: %w", err)
+	}
+	manifest.Keys = keysManifest
+
+	gz := gzip.NewWriter(out)
+	w := &writer{tw: tar.NewWriter(gz), manifest: manifest, modTime: manifest.CreatedAt}
+
+	if keys != nil {
+		if err := w.add(keysName, bytes.NewReader(keys), int64(len(keys)), 0600); err != nil {
+			return nil, fmt.Errorf("archiving keys: %w", err)
+		}
+	}
+
+	err = filepath.WalkDir(opts.DataDir, func(src string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(opts.DataDir, src)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if isKeyFile(rel) || excluded(rel, opts.Exclude) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// Sockets, symlinks and the like aren't state worth restoring
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		return w.addFile(dataPrefix+rel, src)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("archiving data directory: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, src := range opts.ConfigFiles {
+		name := filepath.Base(src)
+		if seen[name] {
+			return nil, fmt.Errorf("two config files are named %s", name)
+		}
+		seen[name] = true
+		if err := w.addFile(configPrefix+name, src); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding manifest: %w", err)
+	}
+	err = w.tw.WriteHeader(&tar.Header{Name: manifestName, Mode: 0600, Size: int64(len(data)), ModTime: w.modTime, Typeflag: tar.TypeReg})
+	if err == nil {
+		_, err = w.tw.Write(data)
+	}
+	if err == nil {
+		err = w.tw.Close()
+	}
+	if err == nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("writing snapshot: %w", err)
+	}
+	return manifest, nil
+}
+
+// validName accepts only the entries a snapshot may contain, so a restore
+// can't write outside the data and config directories
+func validName(name string) bool {
+	if name != path.Clean(name) || path.IsAbs(name) || strings.Contains(name, `\`) {
+		return false
+	}
+	switch {
+	case name == manifestName, name == keysName:
+		return true
+	case strings.HasPrefix(name, dataPrefix):
+		rel := strings.TrimPrefix(name, dataPrefix)
+		return rel != "" && rel != ".." && !strings.HasPrefix(rel, "../") && !isKeyFile(rel)
+	case strings.HasPrefix(name, configPrefix):
+		rel := strings.TrimPrefix(name, configPrefix)
+		return rel != "" && !strings.ContainsAny(rel, "/") && rel != ".."
+	}
+	return false
+}
+
+// read walks an archive, calling extract for each data file, and checks
+// every entry against the manifest. It returns the manifest, the key
+// bundle and the config files.
+func read(in io.Reader, extract func(rel string, r io.Reader, mode fs.FileMode) error) (*Manifest, []byte, map[string][]byte, error) {
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return nil, nil, nil, ErrNotSnapshot
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	var manifest *Manifest
+	var keys []byte
+	configs := make(map[string][]byte)
+	got := make(map[string]File)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+		}
+		if hdr.Typeflag != tar.TypeReg || !validName(hdr.Name) {
+			return nil, nil, nil, fmt.Errorf("%w: unexpected entry %q", ErrNotSnapshot, hdr.Name)
+		}
+		if _, dup := got[hdr.Name]; dup || manifest != nil {
+			return nil, nil, nil, fmt.Errorf("%w: unexpected entry %q", ErrCorrupt, hdr.Name)
+		}
+
+		if hdr.Name == manifestName {
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil || manifest.Format != Format {
+				return nil, nil, nil, fmt.Errorf("%w: unsupported manifest", ErrNotSnapshot)
+			}
+			continue
+		}
+
+		hash := sha256.New()
+		r := io.TeeReader(tr, hash)
+		switch {
+		case hdr.Name == keysName:
+			keys, err = io.ReadAll(r)
+		case strings.HasPrefix(hdr.Name, configPrefix):
+			configs[strings.TrimPrefix(hdr.Name, configPrefix)], err = io.ReadAll(r)
+		default:
+			err = extract(strings.TrimPrefix(hdr.Name, dataPrefix), r, hdr.FileInfo().Mode())
+		}
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("reading %s: %w", hdr.Name, err)
+		}
+		got[hdr.Name] = File{Path: hdr.Name, Size: hdr.Size, SHA256: hex.EncodeToString(hash.Sum(nil))}
+	}
+
+	if manifest == nil {
+		return nil, nil, nil, fmt.Errorf("%w: no manifest (truncated archive?)", ErrCorrupt)
+	}
+	if len(got) != len(manifest.Files) {
+		return nil, nil, nil, fmt.Errorf("%w: %d files, manifest lists %d", ErrCorrupt, len(got), len(manifest.Files))
+	}
+	for _, want := range manifest.Files {
+		if got[want.Path] != want {
+			return nil, nil, nil, fmt.Errorf("%w: %s", ErrCorrupt, want.Path)
+		}
+	}
+	return manifest, keys, configs, nil
+}
+
+// inspectKeys opens the key bundle, if there is one, with passphrase
+func inspectKeys(manifest *Manifest, keys []byte, passphrase string) error {
+	manifest.Keys = nil
+	if keys == nil {
+		return nil
+	}
+	keysManifest, err := backup.Inspect(keys, passphrase)
+	if err != nil {
+		return fmt.Errorf("opening keys: %w", err)
+	}
+	manifest.Keys = keysManifest
+	return nil
+}
+
+// Verify checks an archive against its manifest and that passphrase opens
+// its keys, without writing anything. Recovery drills run it on the
+// latest snapshot.
+func Verify(in io.Reader, passphrase string) (*Manifest, error) {
+	manifest, keys, _, err := read(in, func(rel string, r io.Reader, mode fs.FileMode) error {
+		_, err := io.Copy(io.Discard, r)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := inspectKeys(manifest, keys, passphrase); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// Restore unpacks a snapshot into a staging directory next to DataDir,
+// checks it and only then swaps it in, so a bad archive leaves the
+// existing data alone. The replaced data directory is kept as
+// {DataDir}.pre-restore-{time}.
+func Restore(in io.Reader, opts RestoreOptions) (*Manifest, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	dataDir := filepath.Clean(opts.DataDir)
+	existing, err := os.ReadDir(dataDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading data directory: %w", err)
+	}
+	if len(existing) > 0 && !opts.Force {
+		return nil, ErrDataDirNotEmpty
+	}
+
+	staging := dataDir + ".restoring"
+	if err := os.RemoveAll(staging); err != nil {
+		return nil, fmt.Errorf("clearing staging directory: %w", err)
+	}
+	if err := os.MkdirAll(staging, 0755); err != nil {
+		return nil, fmt.Errorf("creating staging directory: %w", err)
+	}
+	restored := false
+	defer func() {
+		if !restored {
+			os.RemoveAll(staging)
+		}
+	}()
+
+	manifest, keys, configs, err := read(in, func(rel string, r io.Reader, mode fs.FileMode) error {
+		dst := filepath.Join(staging, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, r); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := inspectKeys(manifest, keys, opts.Passphrase); err != nil {
+		return nil, err
+	}
+	if keys != nil {
+		if _, err := backup.Import(staging, keys, opts.AtRest, opts.Passphrase, true); err != nil {
+			return nil, fmt.Errorf("restoring keys: %w", err)
+		}
+	}
+
+	if len(existing) > 0 {
+		previous := fmt.Sprintf("%s.pre-restore-%s", dataDir, now.UTC().Format("20060102T150405Z"))
+		if err := os.Rename(dataDir, previous); err != nil {
+			return nil, fmt.Errorf("moving existing data directory aside: %w", err)
+		}
+	} else if err := os.RemoveAll(dataDir); err != nil {
+		return nil, fmt.Errorf("removing empty data directory: %w", err)
+	}
+	if err := os.Rename(staging, dataDir); err != nil {
+		return nil, fmt.Errorf("moving restored data into place: %w", err)
+	}
+	restored = true
+
+	if opts.ConfigDir != "" && len(configs) > 0 {
+		if err := os.MkdirAll(opts.ConfigDir, 0755); err != nil {
+			return nil, fmt.Errorf("creating config directory: %w", err)
+		}
+		names := make([]string, 0, len(configs))
+		for name := range configs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := os.WriteFile(filepath.Join(opts.ConfigDir, name), configs[name], 0600); err != nil {
+				return nil, fmt.Errorf("writing config %s: %w", name, err)
+			}
+		}
+	}
+	return manifest, nil
+}
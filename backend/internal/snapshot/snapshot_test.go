@@ -0,0 +1,220 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/secret"
+)
+
+const snapshotPassphrase = "disaster drill passphrase"
+
+func writeFile(t *testing.T, dir, path string, data []byte) {
+	t.Helper()
+	full := filepath.Join(dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(full), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, dir, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// deployment writes a data directory with sealed keys and a config file
+func deployment(t *testing.T, atRest *secret.Sealer) (dataDir, configFile string) {
+	t.Helper()
+	root := t.TempDir()
+	dataDir = filepath.Join(root, "data")
+	identity, _ := atRest.Seal([]byte(`{"aid":"EAID","mnemonic":"abandon about"}`))
+	keys, _ := atRest.Seal([]byte(`{"signingKey":"k"}`))
+	writeFile(t, dataDir, "identity.json", identity)
+	writeFile(t, dataDir, "keys/bafyspace.keys", keys)
+	writeFile(t, dataDir, "peer.key", []byte{1, 2, 3})
+	writeFile(t, dataDir, "matou.db", []byte("store pages"))
+	writeFile(t, dataDir, "spaces/bafyspace/tree", []byte("space storage"))
+	writeFile(t, dataDir, "logs/matou.log", []byte("log lines"))
+	configFile = filepath.Join(root, "client-dev.yml")
+	writeFile(t, root, "client-dev.yml", []byte("nodes: []\n"))
+	return dataDir, configFile
+}
+
+func TestCreateRestore(t *testing.T) {
+	oldRest, _ := secret.NewSealer("old machine")
+	newRest, _ := secret.NewSealer("new machine")
+	dataDir, configFile := deployment(t, oldRest)
+
+	var archive bytes.Buffer
+	manifest, err := Create(&archive, Options{
+		DataDir:     dataDir,
+		ConfigFiles: []string{configFile},
+		Exclude:     []string{"logs/matou*"},
+		AtRest:      oldRest,
+		Passphrase:  snapshotPassphrase,
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	// keys.bundle, matou.db, the space and the config
+	if len(manifest.Files) != 4 || manifest.Keys == nil || manifest.Keys.SpaceKeys != 1 {
+		t.Fatalf("unexpected manifest %+v", manifest)
+	}
+	if bytes.Contains(archive.Bytes(), []byte("abandon")) {
+		t.Fatal("snapshot leaked the mnemonic")
+	}
+
+	if _, err := Verify(bytes.NewReader(archive.Bytes()), snapshotPassphrase); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if _, err := Verify(bytes.NewReader(archive.Bytes()), "wrong passphrase"); !errors.Is(err, secret.ErrWrongPassphrase) {
+		t.Errorf("expected wrong passphrase, got %v", err)
+	}
+
+	target := filepath.Join(t.TempDir(), "data")
+	configDir := filepath.Join(t.TempDir(), "config")
+	if _, err := Restore(bytes.NewReader(archive.Bytes()), RestoreOptions{
+		DataDir:    target,
+		ConfigDir:  configDir,
+		AtRest:     newRest,
+		Passphrase: snapshotPassphrase,
+	}); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if got := readFile(t, target, "spaces/bafyspace/tree"); string(got) != "space storage" {
+		t.Errorf("unexpected space storage %q", got)
+	}
+	if got := readFile(t, configDir, "client-dev.yml"); string(got) != "nodes: []\n" {
+		t.Errorf("unexpected config %q", got)
+	}
+	identity, err := newRest.Open(readFile(t, target, "identity.json"))
+	if err != nil || !bytes.Contains(identity, []byte("EAID")) {
+		t.Errorf("expected the identity sealed with the new passphrase, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "logs", "matou.log")); !os.IsNotExist(err) {
+		t.Error("expected excluded log files to be left out")
+	}
+}
+
+func TestRestore_RefusesExistingData(t *testing.T) {
+	atRest, _ := secret.NewSealer("")
+	dataDir, _ := deployment(t, atRest)
+
+	var archive bytes.Buffer
+	if _, err := Create(&archive, Options{DataDir: dataDir, Passphrase: snapshotPassphrase}); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dataDir, "matou.db", []byte("newer pages"))
+
+	opts := RestoreOptions{DataDir: dataDir, Passphrase: snapshotPassphrase, Now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	if _, err := Restore(bytes.NewReader(archive.Bytes()), opts); !errors.Is(err, ErrDataDirNotEmpty) {
+		t.Fatalf("expected ErrDataDirNotEmpty, got %v", err)
+	}
+
+	opts.Force = true
+	if _, err := Restore(bytes.NewReader(archive.Bytes()), opts); err != nil {
+		t.Fatalf("forced Restore failed: %v", err)
+	}
+	if got := readFile(t, dataDir, "matou.db"); string(got) != "store pages" {
+		t.Errorf("expected the snapshot's store, got %q", got)
+	}
+	if got := readFile(t, dataDir+".pre-restore-20260301T120000Z", "matou.db"); string(got) != "newer pages" {
+		t.Errorf("expected the replaced data kept aside, got %q", got)
+	}
+}
+
+// rewrite copies an archive, letting edit change or drop entries
+func rewrite(t *testing.T, archive []byte, edit func(hdr *tar.Header, data []byte) []byte) []byte {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var out bytes.Buffer
+	gw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gw)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		if data = edit(hdr, data); data == nil {
+			continue
+		}
+		hdr.Size = int64(len(data))
+		tw.WriteHeader(hdr)
+		tw.Write(data)
+	}
+	tw.Close()
+	gw.Close()
+	return out.Bytes()
+}
+
+func TestVerify_DetectsDamage(t *testing.T) {
+	atRest, _ := secret.NewSealer("")
+	dataDir, _ := deployment(t, atRest)
+
+	var archive bytes.Buffer
+	if _, err := Create(&archive, Options{DataDir: dataDir, Passphrase: snapshotPassphrase}); err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := rewrite(t, archive.Bytes(), func(hdr *tar.Header, data []byte) []byte {
+		if hdr.Name == "data/matou.db" {
+			return []byte("altered pages")
+		}
+		return data
+	})
+	truncated := rewrite(t, archive.Bytes(), func(hdr *tar.Header, data []byte) []byte {
+		if hdr.Name == manifestName {
+			return nil
+		}
+		return data
+	})
+	escaping := rewrite(t, archive.Bytes(), func(hdr *tar.Header, data []byte) []byte {
+		if hdr.Name == "data/matou.db" {
+			hdr.Name = "data/../../etc/cron.d/matou"
+		}
+		return data
+	})
+
+	for name, data := range map[string][]byte{"tampered": tampered, "truncated": truncated} {
+		if _, err := Verify(bytes.NewReader(data), snapshotPassphrase); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("%s: expected ErrCorrupt, got %v", name, err)
+		}
+	}
+	if _, err := Verify(bytes.NewReader(escaping), snapshotPassphrase); !errors.Is(err, ErrNotSnapshot) {
+		t.Errorf("expected a path outside the data directory to be refused, got %v", err)
+	}
+
+	// A failed restore leaves no staging directory behind
+	target := filepath.Join(t.TempDir(), "data")
+	if _, err := Restore(bytes.NewReader(tampered), RestoreOptions{DataDir: target, Passphrase: snapshotPassphrase}); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("expected ErrCorrupt, got %v", err)
+	}
+	if _, err := os.Stat(target + ".restoring"); !os.IsNotExist(err) {
+		t.Error("expected the staging directory to be removed")
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Error("expected the data directory to be left alone")
+	}
+}
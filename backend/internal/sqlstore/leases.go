@@ -0,0 +1,56 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/matou-dao/backend/internal/cluster"
+)
+
+const leasesSchema = `CREATE TABLE IF NOT EXISTS matou_leases (
+	name TEXT NOT NULL PRIMARY KEY,
+	holder TEXT NOT NULL,
+	expires_at BIGINT NOT NULL
+)`
+
+// Ensure Store implements cluster.LeaseStore
+var _ cluster.LeaseStore = (*Store)(nil)
+
+// AcquireLease takes the lease if it is free or expired, or renews it if
+// holder already has it. Expiry uses each replica's clock, so replicas
+// should keep their clocks in sync.
+func (s *Store) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+	result, err := s.db.ExecContext(ctx, s.rebind(`INSERT INTO matou_leases (name, holder, expires_at) VALUES (?, ?, ?)
+ON CONFLICT (name) DO UPDATE SET holder = excluded.holder, expires_at = excluded.expires_at
+WHERE matou_leases.holder = excluded.holder OR matou_leases.expires_at <= ?`),
+		name, holder, now.Add(ttl).UnixNano(), now.UnixNano())
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// ReleaseLease gives up holder's lease. Releasing a lease held by someone
+// else does nothing.
+func (s *Store) ReleaseLease(ctx context.Context, name, holder string) error {
+	_, err := s.db.ExecContext(ctx, s.rebind(`DELETE FROM matou_leases WHERE name = ? AND holder = ?`), name, holder)
+	return err
+}
+
+// LeaseHolder returns who holds an unexpired lease, or ""
+func (s *Store) LeaseHolder(ctx context.Context, name string) (string, error) {
+	var holder string
+	err := s.db.QueryRowContext(ctx, s.rebind(`SELECT holder FROM matou_leases WHERE name = ? AND expires_at > ?`),
+		name, time.Now().UTC().UnixNano()).Scan(&holder)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return holder, err
+}
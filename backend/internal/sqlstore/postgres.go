@@ -0,0 +1,8 @@
+//go:build postgres
+
+package sqlstore
+
+// The Postgres driver is only built in for org deployments that use it:
+//
+//	go get github.com/jackc/pgx/v5 && go build -tags postgres ./cmd/server
+import _ "github.com/jackc/pgx/v5/stdlib"
@@ -0,0 +1,309 @@
+package sqlstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/metrics"
+)
+
+// SaveSpaceRecord saves a space record.
+func (s *Store) SaveSpaceRecord(ctx context.Context, record *anystore.SpaceRecord) error {
+	defer metrics.ObserveStoreQuery("save_space_record", time.Now())
+
+	if err := s.put(ctx, anystore.CollectionSpaces, record.ID, record); err != nil {
+		return fmt.Errorf("failed to save space record: %w", err)
+	}
+	return nil
+}
+
+// DeleteSpaceRecord removes a space record. A missing record is not an error.
+func (s *Store) DeleteSpaceRecord(ctx context.Context, spaceID string) error {
+	defer metrics.ObserveStoreQuery("delete_space_record", time.Now())
+
+	if err := s.delete(ctx, anystore.CollectionSpaces, spaceID); err != nil {
+		return fmt.Errorf("failed to delete space record: %w", err)
+	}
+	return nil
+}
+
+// GetSpaceByID retrieves a space record by space ID.
+func (s *Store) GetSpaceByID(ctx context.Context, spaceID string) (*anystore.SpaceRecord, error) {
+	defer metrics.ObserveStoreQuery("get_space_by_id", time.Now())
+
+	var record anystore.SpaceRecord
+	if err := s.get(ctx, anystore.CollectionSpaces, spaceID, &record); err != nil {
+		return nil, fmt.Errorf("space not found: %w", err)
+	}
+	return &record, nil
+}
+
+// GetUserSpaceRecord retrieves a user's private space record.
+func (s *Store) GetUserSpaceRecord(ctx context.Context, userAID string) (*anystore.SpaceRecord, error) {
+	defer metrics.ObserveStoreQuery("get_user_space_record", time.Now())
+
+	records, err := s.ListAllSpaceRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.UserAID == userAID && record.SpaceType == "private" {
+			return record, nil
+		}
+	}
+	return nil, fmt.Errorf("space not found for user: %s", userAID)
+}
+
+// ListAllSpaceRecords retrieves all space records.
+func (s *Store) ListAllSpaceRecords(ctx context.Context) ([]*anystore.SpaceRecord, error) {
+	defer metrics.ObserveStoreQuery("list_all_space_records", time.Now())
+
+	var records []*anystore.SpaceRecord
+	err := s.list(ctx, anystore.CollectionSpaces, func(data []byte) error {
+		var record anystore.SpaceRecord
+		if json.Unmarshal(data, &record) == nil {
+			records = append(records, &record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query spaces: %w", err)
+	}
+	return records, nil
+}
+
+// UpdateSpaceLastSync updates the last sync timestamp for a space.
+func (s *Store) UpdateSpaceLastSync(ctx context.Context, spaceID string) error {
+	record, err := s.GetSpaceByID(ctx, spaceID)
+	if err != nil {
+		return err
+	}
+
+	record.LastSync = time.Now().UTC()
+	return s.SaveSpaceRecord(ctx, record)
+}
+
+// StorePeerMapping records the any-sync peer ID for an AID.
+func (s *Store) StorePeerMapping(ctx context.Context, mapping *anystore.PeerMapping) error {
+	defer metrics.ObserveStoreQuery("store_peer_mapping", time.Now())
+
+	if err := s.put(ctx, anystore.CollectionPeerMappings, mapping.AID, mapping); err != nil {
+		return fmt.Errorf("failed to store peer mapping: %w", err)
+	}
+	return nil
+}
+
+// GetPeerMapping retrieves the peer mapping for an AID.
+func (s *Store) GetPeerMapping(ctx context.Context, aid string) (*anystore.PeerMapping, error) {
+	defer metrics.ObserveStoreQuery("get_peer_mapping", time.Now())
+
+	var mapping anystore.PeerMapping
+	if err := s.get(ctx, anystore.CollectionPeerMappings, aid, &mapping); err != nil {
+		return nil, fmt.Errorf("peer mapping not found: %w", err)
+	}
+	return &mapping, nil
+}
+
+// ListPeerMappings retrieves all peer mappings.
+func (s *Store) ListPeerMappings(ctx context.Context) ([]*anystore.PeerMapping, error) {
+	defer metrics.ObserveStoreQuery("list_peer_mappings", time.Now())
+
+	var mappings []*anystore.PeerMapping
+	err := s.list(ctx, anystore.CollectionPeerMappings, func(data []byte) error {
+		var mapping anystore.PeerMapping
+		if json.Unmarshal(data, &mapping) == nil {
+			mappings = append(mappings, &mapping)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query peer mappings: %w", err)
+	}
+	return mappings, nil
+}
+
+// StoreMemberPresence records when a member was last seen active.
+func (s *Store) StoreMemberPresence(ctx context.Context, record *anystore.MemberPresenceRecord) error {
+	defer metrics.ObserveStoreQuery("store_member_presence", time.Now())
+
+	if err := s.put(ctx, anystore.CollectionMemberPresence, record.AID, record); err != nil {
+		return fmt.Errorf("failed to store member presence: %w", err)
+	}
+	return nil
+}
+
+// GetMemberPresence retrieves a member's last-seen record.
+func (s *Store) GetMemberPresence(ctx context.Context, aid string) (*anystore.MemberPresenceRecord, error) {
+	defer metrics.ObserveStoreQuery("get_member_presence", time.Now())
+
+	var record anystore.MemberPresenceRecord
+	if err := s.get(ctx, anystore.CollectionMemberPresence, aid, &record); err != nil {
+		return nil, fmt.Errorf("member presence not found: %w", err)
+	}
+	return &record, nil
+}
+
+// ListMemberPresence retrieves all member last-seen records.
+func (s *Store) ListMemberPresence(ctx context.Context) ([]*anystore.MemberPresenceRecord, error) {
+	defer metrics.ObserveStoreQuery("list_member_presence", time.Now())
+
+	var records []*anystore.MemberPresenceRecord
+	err := s.list(ctx, anystore.CollectionMemberPresence, func(data []byte) error {
+		var record anystore.MemberPresenceRecord
+		if json.Unmarshal(data, &record) == nil {
+			records = append(records, &record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query member presence: %w", err)
+	}
+	return records, nil
+}
+
+// StoreInboxItem records a drained inbox item, replacing any previous copy.
+func (s *Store) StoreInboxItem(ctx context.Context, record *anystore.InboxRecord) error {
+	defer metrics.ObserveStoreQuery("store_inbox_item", time.Now())
+
+	if err := s.put(ctx, anystore.CollectionInbox, record.ID, record); err != nil {
+		return fmt.Errorf("failed to store inbox item: %w", err)
+	}
+	return nil
+}
+
+// GetInboxItem retrieves a drained inbox item by ID.
+func (s *Store) GetInboxItem(ctx context.Context, id string) (*anystore.InboxRecord, error) {
+	defer metrics.ObserveStoreQuery("get_inbox_item", time.Now())
+
+	var record anystore.InboxRecord
+	if err := s.get(ctx, anystore.CollectionInbox, id, &record); err != nil {
+		return nil, fmt.Errorf("inbox item not found: %w", err)
+	}
+	return &record, nil
+}
+
+// ListInboxItems retrieves all drained inbox items.
+func (s *Store) ListInboxItems(ctx context.Context) ([]*anystore.InboxRecord, error) {
+	defer metrics.ObserveStoreQuery("list_inbox_items", time.Now())
+
+	var records []*anystore.InboxRecord
+	err := s.list(ctx, anystore.CollectionInbox, func(data []byte) error {
+		var record anystore.InboxRecord
+		if json.Unmarshal(data, &record) == nil {
+			records = append(records, &record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query inbox: %w", err)
+	}
+	return records, nil
+}
+
+// StoreInvite records an issued invite, replacing any previous copy.
+func (s *Store) StoreInvite(ctx context.Context, record *anystore.InviteRecord) error {
+	defer metrics.ObserveStoreQuery("store_invite", time.Now())
+
+	if err := s.put(ctx, anystore.CollectionInvites, record.ID, record); err != nil {
+		return fmt.Errorf("failed to store invite: %w", err)
+	}
+	return nil
+}
+
+// GetInvite retrieves an issued invite by its ACL record ID.
+func (s *Store) GetInvite(ctx context.Context, id string) (*anystore.InviteRecord, error) {
+	defer metrics.ObserveStoreQuery("get_invite", time.Now())
+
+	var record anystore.InviteRecord
+	if err := s.get(ctx, anystore.CollectionInvites, id, &record); err != nil {
+		return nil, fmt.Errorf("invite not found: %w", err)
+	}
+	return &record, nil
+}
+
+// ListInvites retrieves all issued invites.
+func (s *Store) ListInvites(ctx context.Context) ([]*anystore.InviteRecord, error) {
+	defer metrics.ObserveStoreQuery("list_invites", time.Now())
+
+	var records []*anystore.InviteRecord
+	err := s.list(ctx, anystore.CollectionInvites, func(data []byte) error {
+		var record anystore.InviteRecord
+		if json.Unmarshal(data, &record) == nil {
+			records = append(records, &record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query invites: %w", err)
+	}
+	return records, nil
+}
+
+// StoreEmailDelivery records an invite email delivery, replacing any
+// previous copy.
+func (s *Store) StoreEmailDelivery(ctx context.Context, record *anystore.EmailDeliveryRecord) error {
+	defer metrics.ObserveStoreQuery("store_email_delivery", time.Now())
+
+	if err := s.put(ctx, anystore.CollectionEmailDeliveries, record.ID, record); err != nil {
+		return fmt.Errorf("failed to store email delivery: %w", err)
+	}
+	return nil
+}
+
+// GetEmailDelivery retrieves an invite email delivery by ID.
+func (s *Store) GetEmailDelivery(ctx context.Context, id string) (*anystore.EmailDeliveryRecord, error) {
+	defer metrics.ObserveStoreQuery("get_email_delivery", time.Now())
+
+	var record anystore.EmailDeliveryRecord
+	if err := s.get(ctx, anystore.CollectionEmailDeliveries, id, &record); err != nil {
+		return nil, fmt.Errorf("email delivery not found: %w", err)
+	}
+	return &record, nil
+}
+
+// ListEmailDeliveries retrieves all invite email deliveries.
+func (s *Store) ListEmailDeliveries(ctx context.Context) ([]*anystore.EmailDeliveryRecord, error) {
+	defer metrics.ObserveStoreQuery("list_email_deliveries", time.Now())
+
+	var records []*anystore.EmailDeliveryRecord
+	err := s.list(ctx, anystore.CollectionEmailDeliveries, func(data []byte) error {
+		var record anystore.EmailDeliveryRecord
+		if json.Unmarshal(data, &record) == nil {
+			records = append(records, &record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query email deliveries: %w", err)
+	}
+	return records, nil
+}
+
+// SetPreference stores a user preference.
+func (s *Store) SetPreference(ctx context.Context, key string, value any) error {
+	defer metrics.ObserveStoreQuery("set_preference", time.Now())
+
+	pref := anystore.UserPreference{
+		Key:       key,
+		Value:     value,
+		UpdatedAt: time.Now().UTC(),
+	}
+	if err := s.put(ctx, anystore.CollectionUserPreferences, key, pref); err != nil {
+		return fmt.Errorf("failed to store preference: %w", err)
+	}
+	return nil
+}
+
+// GetPreference retrieves a user preference.
+func (s *Store) GetPreference(ctx context.Context, key string) (any, error) {
+	defer metrics.ObserveStoreQuery("get_preference", time.Now())
+
+	var pref anystore.UserPreference
+	if err := s.get(ctx, anystore.CollectionUserPreferences, key, &pref); err != nil {
+		return nil, fmt.Errorf("preference not found: %w", err)
+	}
+	return pref.Value, nil
+}
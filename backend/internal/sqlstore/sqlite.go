@@ -0,0 +1,4 @@
+package sqlstore
+
+// The SQLite driver is pure Go, so it is always built in
+import _ "modernc.org/sqlite"
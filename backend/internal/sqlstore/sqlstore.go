@@ -0,0 +1,175 @@
+// Package sqlstore keeps MATOU's records in a SQL database instead of the
+// anystore file, so multi-user org deployments can run on a managed
+// database. It implements anystore.RecordStore on SQLite or Postgres.
+//
+// Records are stored as JSON documents in one table keyed by collection
+// and ID, mirroring the anystore collections, so both backends hold the
+// same data in the same shape.
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+// Supported drivers
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// ErrNotFound is returned when a record doesn't exist
+var ErrNotFound = errors.New("record not found")
+
+const schema = `CREATE TABLE IF NOT EXISTS matou_records (
+	collection TEXT NOT NULL,
+	id TEXT NOT NULL,
+	data TEXT NOT NULL,
+	updated_at BIGINT NOT NULL,
+	PRIMARY KEY (collection, id)
+)`
+
+// Store keeps records in a SQL database.
+type Store struct {
+	db     *sql.DB
+	driver string
+}
+
+// Ensure Store implements anystore.RecordStore
+var _ anystore.RecordStore = (*Store)(nil)
+
+// Open connects to the database and creates its tables if needed.
+// driver is sqlite (dsn is a file path) or postgres (dsn is a connection
+// URL). The Postgres driver is only built in with the postgres build tag.
+func Open(ctx context.Context, driver, dsn string) (*Store, error) {
+	var sqlDriver string
+	switch driver {
+	case DriverSQLite:
+		sqlDriver = "sqlite"
+	case DriverPostgres:
+		sqlDriver = "pgx"
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q (expected sqlite or postgres)", driver)
+	}
+	if !registered(sqlDriver) {
+		return nil, fmt.Errorf("storage driver %s is not built in (build with -tags %s)", driver, driver)
+	}
+
+	db, err := sql.Open(sqlDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s database: %w", driver, err)
+	}
+	if driver == DriverSQLite {
+		// SQLite allows one writer; a single connection avoids SQLITE_BUSY
+		db.SetMaxOpenConns(1)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to %s database: %w", driver, err)
+	}
+	for _, stmt := range []string{schema, leasesSchema} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to create tables: %w", err)
+		}
+	}
+
+	return &Store{db: db, driver: driver}, nil
+}
+
+func registered(name string) bool {
+	for _, d := range sql.Drivers() {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Driver returns the driver the store was opened with.
+func (s *Store) Driver() string {
+	return s.driver
+}
+
+// Close closes the database connection.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// rebind rewrites ? placeholders as $1, $2, ... for Postgres
+func (s *Store) rebind(query string) string {
+	if s.driver != DriverPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// put stores a record, replacing any previous copy
+func (s *Store) put(ctx context.Context, collection, id string, record any) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, s.rebind(`INSERT INTO matou_records (collection, id, data, updated_at) VALUES (?, ?, ?, ?)
+ON CONFLICT (collection, id) DO UPDATE SET data = excluded.data, updated_at = excluded.updated_at`),
+		collection, id, string(data), time.Now().UTC().UnixNano())
+	return err
+}
+
+// get reads a record into out, returning ErrNotFound if it doesn't exist
+func (s *Store) get(ctx context.Context, collection, id string, out any) error {
+	var data string
+	err := s.db.QueryRowContext(ctx, s.rebind(`SELECT data FROM matou_records WHERE collection = ? AND id = ?`),
+		collection, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(data), out)
+}
+
+// delete removes a record. A missing record is not an error.
+func (s *Store) delete(ctx context.Context, collection, id string) error {
+	_, err := s.db.ExecContext(ctx, s.rebind(`DELETE FROM matou_records WHERE collection = ? AND id = ?`), collection, id)
+	return err
+}
+
+// list calls fn with each record in a collection, in ID order
+func (s *Store) list(ctx context.Context, collection string, fn func(data []byte) error) error {
+	rows, err := s.db.QueryContext(ctx, s.rebind(`SELECT data FROM matou_records WHERE collection = ? ORDER BY id`), collection)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		if err := fn([]byte(data)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
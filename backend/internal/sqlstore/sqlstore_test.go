@@ -0,0 +1,179 @@
+package sqlstore
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+func setupTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	store, err := Open(context.Background(), DriverSQLite, filepath.Join(t.TempDir(), "records.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestOpen_UnknownDriver(t *testing.T) {
+	if _, err := Open(context.Background(), "mysql", ""); err == nil {
+		t.Error("expected error for an unknown driver")
+	}
+}
+
+func TestRebind(t *testing.T) {
+	store := &Store{driver: DriverPostgres}
+	if got := store.rebind("a = ? AND b = ?"); got != "a = $1 AND b = $2" {
+		t.Errorf("unexpected Postgres query %q", got)
+	}
+	store.driver = DriverSQLite
+	if got := store.rebind("a = ?"); got != "a = ?" {
+		t.Errorf("expected SQLite query unchanged, got %q", got)
+	}
+}
+
+func TestSpaceRecords(t *testing.T) {
+	store := setupTestStore(t)
+	ctx := context.Background()
+
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, record := range []*anystore.SpaceRecord{
+		{ID: "space-private", UserAID: "EALICE", SpaceType: "private", CreatedAt: created},
+		{ID: "space-community", UserAID: "EALICE", SpaceType: "community", CreatedAt: created},
+	} {
+		if err := store.SaveSpaceRecord(ctx, record); err != nil {
+			t.Fatalf("SaveSpaceRecord failed: %v", err)
+		}
+	}
+
+	record, err := store.GetUserSpaceRecord(ctx, "EALICE")
+	if err != nil || record.ID != "space-private" {
+		t.Fatalf("expected the private space, got %+v, %v", record, err)
+	}
+	if err := store.UpdateSpaceLastSync(ctx, "space-private"); err != nil {
+		t.Fatalf("UpdateSpaceLastSync failed: %v", err)
+	}
+	record, _ = store.GetSpaceByID(ctx, "space-private")
+	if !record.CreatedAt.Equal(created) || record.LastSync.IsZero() {
+		t.Errorf("unexpected record after sync %+v", record)
+	}
+
+	if err := store.DeleteSpaceRecord(ctx, "space-community"); err != nil {
+		t.Fatalf("DeleteSpaceRecord failed: %v", err)
+	}
+	if err := store.DeleteSpaceRecord(ctx, "space-community"); err != nil {
+		t.Errorf("expected deleting a missing record to succeed, got %v", err)
+	}
+	if _, err := store.GetSpaceByID(ctx, "space-community"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	records, err := store.ListAllSpaceRecords(ctx)
+	if err != nil || len(records) != 1 {
+		t.Errorf("expected 1 space record, got %d, %v", len(records), err)
+	}
+}
+
+func TestRecords_Upsert(t *testing.T) {
+	store := setupTestStore(t)
+	ctx := context.Background()
+
+	if err := store.StorePeerMapping(ctx, &anystore.PeerMapping{AID: "EALICE", PeerID: "peer-1"}); err != nil {
+		t.Fatalf("StorePeerMapping failed: %v", err)
+	}
+	if err := store.StorePeerMapping(ctx, &anystore.PeerMapping{AID: "EALICE", PeerID: "peer-2"}); err != nil {
+		t.Fatalf("StorePeerMapping failed: %v", err)
+	}
+	mapping, err := store.GetPeerMapping(ctx, "EALICE")
+	if err != nil || mapping.PeerID != "peer-2" {
+		t.Errorf("expected the replaced mapping, got %+v, %v", mapping, err)
+	}
+	mappings, _ := store.ListPeerMappings(ctx)
+	if len(mappings) != 1 {
+		t.Errorf("expected 1 peer mapping, got %d", len(mappings))
+	}
+
+	if err := store.StoreInvite(ctx, &anystore.InviteRecord{ID: "invite-1", Status: "active", MaxUses: 1}); err != nil {
+		t.Fatalf("StoreInvite failed: %v", err)
+	}
+	invite, err := store.GetInvite(ctx, "invite-1")
+	if err != nil || invite.MaxUses != 1 {
+		t.Errorf("unexpected invite %+v, %v", invite, err)
+	}
+
+	if err := store.StoreEmailDelivery(ctx, &anystore.EmailDeliveryRecord{ID: "invite-1", InviteID: "invite-1", Status: "sent"}); err != nil {
+		t.Fatalf("StoreEmailDelivery failed: %v", err)
+	}
+	deliveries, _ := store.ListEmailDeliveries(ctx)
+	if len(deliveries) != 1 || deliveries[0].Status != "sent" {
+		t.Errorf("unexpected deliveries %+v", deliveries)
+	}
+
+	// Other collections don't see each other's records
+	if _, err := store.GetMemberPresence(ctx, "EALICE"); err == nil {
+		t.Error("expected no presence record for EALICE")
+	}
+}
+
+func TestPreferences(t *testing.T) {
+	store := setupTestStore(t)
+	ctx := context.Background()
+
+	if _, err := store.GetPreference(ctx, "theme"); err == nil {
+		t.Error("expected error for a missing preference")
+	}
+	if err := store.SetPreference(ctx, "digest:EALICE", map[string]any{"lastSlot": "2026-10-19T08:00:00+13:00"}); err != nil {
+		t.Fatalf("SetPreference failed: %v", err)
+	}
+	value, err := store.GetPreference(ctx, "digest:EALICE")
+	if err != nil {
+		t.Fatalf("GetPreference failed: %v", err)
+	}
+	if m, ok := value.(map[string]any); !ok || m["lastSlot"] != "2026-10-19T08:00:00+13:00" {
+		t.Errorf("unexpected preference %v", value)
+	}
+}
+
+func TestLeases(t *testing.T) {
+	store := setupTestStore(t)
+	ctx := context.Background()
+
+	if ok, err := store.AcquireLease(ctx, "leader", "http://a", time.Minute); !ok || err != nil {
+		t.Fatalf("expected a to take the lease, got %v, %v", ok, err)
+	}
+	if ok, _ := store.AcquireLease(ctx, "leader", "http://b", time.Minute); ok {
+		t.Error("expected b not to take a held lease")
+	}
+	if ok, _ := store.AcquireLease(ctx, "leader", "http://a", time.Minute); !ok {
+		t.Error("expected a to renew its lease")
+	}
+	if holder, _ := store.LeaseHolder(ctx, "leader"); holder != "http://a" {
+		t.Errorf("expected a to hold the lease, got %q", holder)
+	}
+
+	// Releasing someone else's lease does nothing
+	store.ReleaseLease(ctx, "leader", "http://b")
+	if holder, _ := store.LeaseHolder(ctx, "leader"); holder != "http://a" {
+		t.Errorf("expected a to still hold the lease, got %q", holder)
+	}
+	if err := store.ReleaseLease(ctx, "leader", "http://a"); err != nil {
+		t.Fatalf("ReleaseLease failed: %v", err)
+	}
+	if ok, _ := store.AcquireLease(ctx, "leader", "http://b", time.Nanosecond); !ok {
+		t.Error("expected b to take the released lease")
+	}
+
+	// An expired lease can be taken over
+	time.Sleep(time.Millisecond)
+	if holder, _ := store.LeaseHolder(ctx, "leader"); holder != "" {
+		t.Errorf("expected no holder once expired, got %q", holder)
+	}
+	if ok, _ := store.AcquireLease(ctx, "leader", "http://a", time.Minute); !ok {
+		t.Error("expected a to take over the expired lease")
+	}
+}
@@ -0,0 +1,87 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matou-dao/backend/internal/api"
+)
+
+// ACLReconciler periodically reconciles the community space ACL against
+// membership credentials, so members credentialed or revoked while a grant
+// failed or the backend was down still end up with the right access.
+type ACLReconciler struct {
+	interval    time.Duration
+	access      *api.MemberAccessGranter
+	maintenance *api.MaintenanceMode
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewACLReconciler creates a new ACL reconciler.
+func NewACLReconciler(interval time.Duration, access *api.MemberAccessGranter) *ACLReconciler {
+	return &ACLReconciler{
+		interval: interval,
+		access:   access,
+	}
+}
+
+// SetMaintenance attaches maintenance mode so reconciling pauses while it is active.
+func (r *ACLReconciler) SetMaintenance(m *api.MaintenanceMode) {
+	r.maintenance = m
+}
+
+// Start begins the background reconcile loop. A non-positive interval
+// leaves the reconciler stopped; the ACL is then only reconciled from the
+// admin API.
+func (r *ACLReconciler) Start() {
+	if r.interval <= 0 {
+		fmt.Println("[ACLReconcile] Scheduled ACL reconciliation disabled")
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go r.run(ctx)
+	fmt.Printf("[ACLReconcile] Started ACL reconciler (every %s)\n", r.interval)
+}
+
+// Stop gracefully shuts down the reconciler.
+func (r *ACLReconciler) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	if r.done != nil {
+		<-r.done
+	}
+	fmt.Println("[ACLReconcile] Stopped ACL reconciler")
+}
+
+func (r *ACLReconciler) run(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if r.maintenance.Checkpoint(ctx) != nil {
+				return
+			}
+			report, err := r.access.Reconcile(ctx)
+			if err != nil {
+				fmt.Printf("[ACLReconcile] Reconcile failed: %v\n", err)
+				continue
+			}
+			if report.Granted > 0 || report.Revoked > 0 {
+				fmt.Printf("[ACLReconcile] Granted %d, revoked %d of %d members\n", report.Granted, report.Revoked, report.Members)
+			}
+		}
+	}
+}
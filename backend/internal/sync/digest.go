@@ -0,0 +1,80 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matou-dao/backend/internal/api"
+)
+
+// DefaultDigestInterval is how often the member's digest schedule is checked.
+// Digests go out within this long of their scheduled time.
+const DefaultDigestInterval = 15 * time.Minute
+
+// DigestScheduler checks whether the member's weekly digest is due in their
+// timezone and emails it. Check times don't matter: each scheduled send is
+// remembered, so it goes out once on the first check after it passes.
+type DigestScheduler struct {
+	interval    time.Duration
+	digests     *api.DigestHandler
+	maintenance *api.MaintenanceMode
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewDigestScheduler creates a new digest scheduler.
+func NewDigestScheduler(interval time.Duration, digests *api.DigestHandler) *DigestScheduler {
+	return &DigestScheduler{
+		interval: interval,
+		digests:  digests,
+	}
+}
+
+// SetMaintenance attaches maintenance mode so digests pause while it is active.
+func (s *DigestScheduler) SetMaintenance(m *api.MaintenanceMode) {
+	s.maintenance = m
+}
+
+// Start begins the background check loop.
+func (s *DigestScheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.run(ctx)
+	fmt.Println("[Digest] Started digest scheduler")
+}
+
+// Stop gracefully shuts down the scheduler.
+func (s *DigestScheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.done != nil {
+		<-s.done
+	}
+	fmt.Println("[Digest] Stopped digest scheduler")
+}
+
+func (s *DigestScheduler) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.maintenance.Checkpoint(ctx) != nil {
+				return
+			}
+			if _, err := s.digests.SendDue(ctx); err != nil {
+				fmt.Printf("[Digest] Digest not sent: %v\n", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/api"
+	"github.com/matou-dao/backend/internal/trust"
+)
+
+// CredentialExpirer periodically marks cached credentials whose expiresAt
+// has passed as expired and emits credential:expired for each. Expired
+// credentials are left out of the trust graph, membership ACL reconciling
+// and credential listings whether marked or not; marking them is a
+// credential write, so the materialized trust graph is rebuilt without them.
+type CredentialExpirer struct {
+	interval    time.Duration
+	store       *anystore.LocalStore
+	broker      *api.EventBroker
+	maintenance *api.MaintenanceMode
+	now         func() time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewCredentialExpirer creates a new credential expiration job.
+func NewCredentialExpirer(interval time.Duration, store *anystore.LocalStore, broker *api.EventBroker) *CredentialExpirer {
+	return &CredentialExpirer{
+		interval: interval,
+		store:    store,
+		broker:   broker,
+		now:      time.Now,
+	}
+}
+
+// SetMaintenance attaches maintenance mode so checks pause while it is active.
+func (e *CredentialExpirer) SetMaintenance(mode *api.MaintenanceMode) {
+	e.maintenance = mode
+}
+
+// Start begins the background check loop. A non-positive interval leaves
+// the job stopped; expired credentials are still left out when read.
+func (e *CredentialExpirer) Start() {
+	if e.interval <= 0 {
+		fmt.Println("[Expiry] Credential expiration job disabled")
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	e.done = make(chan struct{})
+
+	go e.run(ctx)
+	fmt.Printf("[Expiry] Started credential expiration job (every %s)\n", e.interval)
+}
+
+// Stop gracefully shuts down the job.
+func (e *CredentialExpirer) Stop() {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	if e.done != nil {
+		<-e.done
+	}
+	fmt.Println("[Expiry] Stopped credential expiration job")
+}
+
+func (e *CredentialExpirer) run(ctx context.Context) {
+	defer close(e.done)
+
+	if e.maintenance.Checkpoint(ctx) != nil {
+		return
+	}
+	e.expireOnce(ctx)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if e.maintenance.Checkpoint(ctx) != nil {
+				return
+			}
+			e.expireOnce(ctx)
+		}
+	}
+}
+
+// expireOnce marks every credential that has expired since the last run.
+func (e *CredentialExpirer) expireOnce(ctx context.Context) {
+	creds, err := e.store.GetAllCredentials(ctx)
+	if err != nil {
+		fmt.Printf("[Expiry] Failed to read credentials: %v\n", err)
+		return
+	}
+
+	now := e.now().UTC()
+	for _, cred := range creds {
+		if cred.ExpiredAt != nil || !trust.CredentialExpired(cred, now) {
+			continue
+		}
+		cred.ExpiredAt = &now
+		if err := e.store.StoreCredential(ctx, cred); err != nil {
+			fmt.Printf("[Expiry] Failed to mark credential %s expired: %v\n", cred.ID, err)
+			continue
+		}
+
+		expiresAt, _ := trust.CredentialExpiresAt(cred)
+		fmt.Printf("[Expiry] Credential %s held by %s expired %s\n",
+			cred.ID, cred.SubjectAID, expiresAt.Format(time.RFC3339))
+		e.broker.Broadcast(api.SSEEvent{
+			Type: "credential:expired",
+			Data: map[string]string{
+				"said":      cred.ID,
+				"issuer":    cred.IssuerAID,
+				"recipient": cred.SubjectAID,
+				"schema":    cred.SchemaID,
+				"expiresAt": expiresAt.Format(time.RFC3339),
+			},
+		})
+	}
+}
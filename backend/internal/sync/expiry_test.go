@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/api"
+)
+
+// expiringCredential is a membership credential that expired on 1 March 2026,
+// as the sync worker caches it
+func expiringCredential() *anystore.CachedCredential {
+	return &anystore.CachedCredential{
+		ID:         "ESAID1",
+		IssuerAID:  "EORG",
+		SubjectAID: "EMEMBER1",
+		SchemaID:   "EMatouMembershipSchemaV1",
+		Data:       map[string]any{"role": "Member", "expiresAt": "2026-03-01T00:00:00Z"},
+		CachedAt:   time.Now().UTC(),
+	}
+}
+
+func TestCredentialExpirer_MarkSurvivesRestore(t *testing.T) {
+	store, err := anystore.NewLocalStore(anystore.DefaultConfig(t.TempDir()))
+	if err != nil {
+		t.Fatalf("failed to create anystore: %v", err)
+	}
+	defer store.Close()
+
+	broker := api.NewEventBroker()
+	defer broker.Close()
+	events := broker.Subscribe()
+	defer broker.Unsubscribe(events)
+
+	ctx := context.Background()
+	if err := store.StoreCredential(ctx, expiringCredential()); err != nil {
+		t.Fatalf("StoreCredential failed: %v", err)
+	}
+
+	expirer := NewCredentialExpirer(time.Hour, store, broker)
+	expirer.now = func() time.Time { return time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC) }
+	expirer.expireOnce(ctx)
+
+	select {
+	case event := <-events:
+		data, ok := event.Data.(map[string]string)
+		if event.Type != "credential:expired" || !ok || data["said"] != "ESAID1" {
+			t.Fatalf("unexpected event: %+v", event)
+		}
+	default:
+		t.Fatal("expected credential:expired for the expired credential")
+	}
+
+	// After a restart the sync worker caches the credential again, without
+	// the expiration job's mark
+	if err := store.StoreCredential(ctx, expiringCredential()); err != nil {
+		t.Fatalf("StoreCredential failed: %v", err)
+	}
+	cred, err := store.GetCredential(ctx, "ESAID1")
+	if err != nil {
+		t.Fatalf("GetCredential failed: %v", err)
+	}
+	if cred.ExpiredAt == nil {
+		t.Fatal("expected re-storing the same credential to keep ExpiredAt")
+	}
+
+	expirer.expireOnce(ctx)
+	select {
+	case event := <-events:
+		t.Errorf("expected nothing broadcast for an already expired credential, got %+v", event)
+	default:
+	}
+}
@@ -0,0 +1,286 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/anysync"
+	"github.com/matou-dao/backend/internal/api"
+)
+
+// DefaultHydrateInterval is how often the credential cache is checked
+// against the community credential tree when no HeadUpdates arrive.
+const DefaultHydrateInterval = time.Minute
+
+// headUpdateSettle is how long to wait after a HeadUpdate before reading
+// the tree, so the changes it announced can be fetched and bursts of
+// updates coalesce into one read.
+const headUpdateSettle = 2 * time.Second
+
+// HydrateResult summarises one pass of the credential hydrator.
+type HydrateResult struct {
+	// Read is the number of distinct credentials in the tree
+	Read int `json:"read"`
+	// Stored is how many were written to the cache, new or updated
+	Stored int `json:"stored"`
+	// Conflicts is how many differed from a cached copy that was kept
+	Conflicts int `json:"conflicts"`
+}
+
+// CredentialHydrator keeps the anystore credential cache in step with the
+// community space's credential tree, so the trust graph can be built from
+// the cache instead of reading the tree on every request. It hydrates
+// periodically and shortly after a peer pushes a HeadUpdate for the space,
+// and skips the read when the tree's heads haven't moved.
+//
+// A credential can appear in the tree more than once, when it is re-shared
+// or published by more than one steward; the newest entry wins. A cached
+// copy that differs from the tree is replaced only if the tree entry is
+// newer and the copy isn't marked verified; org-issued credentials stored
+// through the API stay authoritative.
+type CredentialHydrator struct {
+	interval     time.Duration
+	spaceManager *anysync.SpaceManager
+	store        *anystore.LocalStore
+	maintenance  *api.MaintenanceMode
+
+	mu            sync.Mutex
+	hydratedSpace string
+	hydratedHeads string
+
+	trigger chan struct{}
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewCredentialHydrator creates a new credential hydrator.
+func NewCredentialHydrator(interval time.Duration, spaceManager *anysync.SpaceManager, store *anystore.LocalStore) *CredentialHydrator {
+	return &CredentialHydrator{
+		interval:     interval,
+		spaceManager: spaceManager,
+		store:        store,
+		trigger:      make(chan struct{}, 1),
+	}
+}
+
+// SetMaintenance attaches maintenance mode so hydration pauses while it is active.
+func (h *CredentialHydrator) SetMaintenance(m *api.MaintenanceMode) {
+	h.maintenance = m
+}
+
+// NotifyHeadUpdate schedules a hydration if spaceID is the community
+// space. Suitable for SDKClient.OnHeadUpdate; it never blocks.
+func (h *CredentialHydrator) NotifyHeadUpdate(spaceID string) {
+	if spaceID == "" || spaceID != h.spaceManager.GetCommunitySpaceID() {
+		return
+	}
+	select {
+	case h.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// CommunityCredentialsCached reports whether the cache holds the community
+// tree's credentials as of its current heads.
+func (h *CredentialHydrator) CommunityCredentialsCached() bool {
+	spaceID := h.spaceManager.GetCommunitySpaceID()
+	treeMgr := h.spaceManager.CredentialTreeManager()
+	if spaceID == "" || treeMgr == nil {
+		return false
+	}
+	heads := treeMgr.Heads(spaceID)
+	if len(heads) == 0 {
+		return false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.hydratedSpace == spaceID && h.hydratedHeads == headsKey(heads)
+}
+
+// Start begins the background hydration loop.
+func (h *CredentialHydrator) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	h.done = make(chan struct{})
+
+	go h.run(ctx)
+	fmt.Println("[CredentialHydrator] Started credential hydrator")
+}
+
+// Stop gracefully shuts down the hydrator.
+func (h *CredentialHydrator) Stop() {
+	if h.cancel != nil {
+		h.cancel()
+	}
+	if h.done != nil {
+		<-h.done
+	}
+	fmt.Println("[CredentialHydrator] Stopped credential hydrator")
+}
+
+func (h *CredentialHydrator) run(ctx context.Context) {
+	defer close(h.done)
+
+	if h.maintenance.Checkpoint(ctx) != nil {
+		return
+	}
+	h.hydrate(ctx)
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-h.trigger:
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(headUpdateSettle):
+			}
+		}
+		if h.maintenance.Checkpoint(ctx) != nil {
+			return
+		}
+		h.hydrate(ctx)
+	}
+}
+
+func (h *CredentialHydrator) hydrate(ctx context.Context) {
+	result, err := h.HydrateOnce(ctx)
+	if err != nil {
+		fmt.Printf("[CredentialHydrator] Hydration incomplete: %v\n", err)
+		return
+	}
+	if result != nil && (result.Stored > 0 || result.Conflicts > 0) {
+		fmt.Printf("[CredentialHydrator] Cached %d of %d community credentials (%d conflicts kept local copy)\n",
+			result.Stored, result.Read, result.Conflicts)
+	}
+}
+
+// HydrateOnce copies the community tree's credentials into the cache. It
+// returns a nil result without reading when the tree hasn't changed since
+// the last hydration, or there is no community space yet.
+func (h *CredentialHydrator) HydrateOnce(ctx context.Context) (*HydrateResult, error) {
+	spaceID := h.spaceManager.GetCommunitySpaceID()
+	treeMgr := h.spaceManager.CredentialTreeManager()
+	if spaceID == "" || treeMgr == nil {
+		return nil, nil
+	}
+	if h.CommunityCredentialsCached() {
+		return nil, nil
+	}
+
+	creds, heads, err := treeMgr.ReadCredentialsAtHeads(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	cached, err := h.store.GetAllCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*anystore.CachedCredential, len(cached))
+	for _, c := range cached {
+		byID[c.ID] = c
+	}
+
+	latest := dedupeCredentials(creds)
+	result := &HydrateResult{Read: len(latest)}
+	var failed int
+	for _, cred := range latest {
+		incoming := cachedFromPayload(cred)
+		existing := byID[cred.SAID]
+		switch {
+		case existing == nil:
+		case sameCredential(existing, incoming):
+			continue
+		case existing.Verified || !incoming.CachedAt.After(existing.CachedAt):
+			result.Conflicts++
+			continue
+		}
+		if err := h.store.StoreCredential(ctx, incoming); err != nil {
+			fmt.Printf("[CredentialHydrator] Failed to cache credential %s: %v\n", cred.SAID, err)
+			failed++
+			continue
+		}
+		result.Stored++
+	}
+	if failed > 0 {
+		// Leave the heads unrecorded so the next pass retries
+		return result, fmt.Errorf("%d credentials failed to cache", failed)
+	}
+
+	h.mu.Lock()
+	h.hydratedSpace = spaceID
+	h.hydratedHeads = headsKey(heads)
+	h.mu.Unlock()
+	return result, nil
+}
+
+// dedupeCredentials keeps the newest entry for each SAID. Tree order is
+// causal, so on equal timestamps the later entry wins.
+func dedupeCredentials(creds []*anysync.CredentialPayload) []*anysync.CredentialPayload {
+	index := make(map[string]int, len(creds))
+	var latest []*anysync.CredentialPayload
+	for _, cred := range creds {
+		if cred == nil || cred.SAID == "" {
+			continue
+		}
+		i, seen := index[cred.SAID]
+		if !seen {
+			index[cred.SAID] = len(latest)
+			latest = append(latest, cred)
+			continue
+		}
+		if cred.Timestamp >= latest[i].Timestamp {
+			latest[i] = cred
+		}
+	}
+	return latest
+}
+
+// cachedFromPayload converts a tree entry for the cache. CachedAt is when
+// the entry was published, so a later conflicting entry compares against
+// the content's age rather than when this backend happened to cache it.
+func cachedFromPayload(cred *anysync.CredentialPayload) *anystore.CachedCredential {
+	cachedAt := time.Now().UTC()
+	if cred.Timestamp > 0 {
+		cachedAt = time.Unix(cred.Timestamp, 0).UTC()
+	}
+	var data interface{}
+	if cred.Data != nil {
+		json.Unmarshal(cred.Data, &data)
+	}
+	return &anystore.CachedCredential{
+		ID:         cred.SAID,
+		IssuerAID:  cred.Issuer,
+		SubjectAID: cred.Recipient,
+		SchemaID:   cred.Schema,
+		Data:       data,
+		CachedAt:   cachedAt,
+	}
+}
+
+// sameCredential reports whether two cached credentials carry the same
+// content, ignoring cache bookkeeping
+func sameCredential(a, b *anystore.CachedCredential) bool {
+	return a.IssuerAID == b.IssuerAID &&
+		a.SubjectAID == b.SubjectAID &&
+		a.SchemaID == b.SchemaID &&
+		reflect.DeepEqual(a.Data, b.Data)
+}
+
+func headsKey(heads []string) string {
+	sorted := append([]string(nil), heads...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
@@ -0,0 +1,88 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matou-dao/backend/internal/api"
+)
+
+// DefaultInboxInterval is how often the member's inbox is drained.
+const DefaultInboxInterval = time.Minute
+
+// InboxWatcher drains the member's inbox space into local state: once as
+// soon as the backend comes online, then periodically while items deposited
+// by the org or other members keep syncing in.
+type InboxWatcher struct {
+	interval    time.Duration
+	inbox       *api.InboxHandler
+	maintenance *api.MaintenanceMode
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewInboxWatcher creates a new inbox watcher.
+func NewInboxWatcher(interval time.Duration, inbox *api.InboxHandler) *InboxWatcher {
+	return &InboxWatcher{
+		interval: interval,
+		inbox:    inbox,
+	}
+}
+
+// SetMaintenance attaches maintenance mode so draining pauses while it is active.
+func (w *InboxWatcher) SetMaintenance(m *api.MaintenanceMode) {
+	w.maintenance = m
+}
+
+// Start begins the background drain loop.
+func (w *InboxWatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go w.run(ctx)
+	fmt.Println("[InboxWatcher] Started inbox watcher")
+}
+
+// Stop gracefully shuts down the watcher.
+func (w *InboxWatcher) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	if w.done != nil {
+		<-w.done
+	}
+	fmt.Println("[InboxWatcher] Stopped inbox watcher")
+}
+
+func (w *InboxWatcher) run(ctx context.Context) {
+	defer close(w.done)
+
+	if w.maintenance.Checkpoint(ctx) != nil {
+		return
+	}
+	w.drain(ctx)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if w.maintenance.Checkpoint(ctx) != nil {
+				return
+			}
+			w.drain(ctx)
+		}
+	}
+}
+
+func (w *InboxWatcher) drain(ctx context.Context) {
+	if _, err := w.inbox.Drain(ctx); err != nil {
+		fmt.Printf("[InboxWatcher] Drain incomplete: %v\n", err)
+	}
+}
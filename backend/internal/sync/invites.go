@@ -0,0 +1,81 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matou-dao/backend/internal/api"
+)
+
+// InviteSweeper periodically counts joins made with issued invites and
+// revokes invites that are used up or have expired, so neither depends on
+// an admin listing invites.
+type InviteSweeper struct {
+	interval    time.Duration
+	invites     *api.InvitesHandler
+	maintenance *api.MaintenanceMode
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewInviteSweeper creates a new invite sweeper.
+func NewInviteSweeper(interval time.Duration, invites *api.InvitesHandler) *InviteSweeper {
+	return &InviteSweeper{
+		interval: interval,
+		invites:  invites,
+	}
+}
+
+// SetMaintenance attaches maintenance mode so sweeping pauses while it is active.
+func (s *InviteSweeper) SetMaintenance(m *api.MaintenanceMode) {
+	s.maintenance = m
+}
+
+// Start begins the background sweep loop. A non-positive interval leaves
+// the sweeper stopped; invites are then only swept when they are listed.
+func (s *InviteSweeper) Start() {
+	if s.interval <= 0 {
+		fmt.Println("[Invites] Scheduled invite sweeping disabled")
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.run(ctx)
+	fmt.Printf("[Invites] Started invite sweeper (every %s)\n", s.interval)
+}
+
+// Stop gracefully shuts down the sweeper.
+func (s *InviteSweeper) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.done != nil {
+		<-s.done
+	}
+	fmt.Println("[Invites] Stopped invite sweeper")
+}
+
+func (s *InviteSweeper) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.maintenance.Checkpoint(ctx) != nil {
+				return
+			}
+			if _, err := s.invites.Sweep(ctx); err != nil {
+				fmt.Printf("[Invites] Sweep failed: %v\n", err)
+			}
+		}
+	}
+}
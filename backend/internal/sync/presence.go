@@ -0,0 +1,88 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matou-dao/backend/internal/api"
+)
+
+// DefaultPresenceInterval is how often member presence is refreshed.
+const DefaultPresenceInterval = 5 * time.Minute
+
+// PresenceWatcher periodically refreshes member presence: it records the
+// local member as active, refreshes their SharedProfile heartbeat, and
+// collects activity seen from other members.
+type PresenceWatcher struct {
+	interval    time.Duration
+	tracker     *api.PresenceTracker
+	maintenance *api.MaintenanceMode
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewPresenceWatcher creates a new presence watcher.
+func NewPresenceWatcher(interval time.Duration, tracker *api.PresenceTracker) *PresenceWatcher {
+	return &PresenceWatcher{
+		interval: interval,
+		tracker:  tracker,
+	}
+}
+
+// SetMaintenance attaches maintenance mode so refreshes pause while it is active.
+func (w *PresenceWatcher) SetMaintenance(m *api.MaintenanceMode) {
+	w.maintenance = m
+}
+
+// Start begins the background refresh loop.
+func (w *PresenceWatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go w.run(ctx)
+	fmt.Println("[PresenceWatcher] Started presence watcher")
+}
+
+// Stop gracefully shuts down the watcher.
+func (w *PresenceWatcher) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	if w.done != nil {
+		<-w.done
+	}
+	fmt.Println("[PresenceWatcher] Stopped presence watcher")
+}
+
+func (w *PresenceWatcher) run(ctx context.Context) {
+	defer close(w.done)
+
+	if w.maintenance.Checkpoint(ctx) != nil {
+		return
+	}
+	w.refresh(ctx)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if w.maintenance.Checkpoint(ctx) != nil {
+				return
+			}
+			w.refresh(ctx)
+		}
+	}
+}
+
+func (w *PresenceWatcher) refresh(ctx context.Context) {
+	if err := w.tracker.Refresh(ctx); err != nil {
+		fmt.Printf("[PresenceWatcher] Refresh incomplete: %v\n", err)
+	}
+}
@@ -0,0 +1,81 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matou-dao/backend/internal/api"
+)
+
+// SpaceCollector periodically removes the local storage of spaces deleted
+// on the coordinator, which would otherwise stay on disk indefinitely.
+type SpaceCollector struct {
+	interval    time.Duration
+	spaces      *api.SpacesHandler
+	maintenance *api.MaintenanceMode
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSpaceCollector creates a new space collector.
+func NewSpaceCollector(interval time.Duration, spaces *api.SpacesHandler) *SpaceCollector {
+	return &SpaceCollector{
+		interval: interval,
+		spaces:   spaces,
+	}
+}
+
+// SetMaintenance attaches maintenance mode so collection pauses while it is active.
+func (c *SpaceCollector) SetMaintenance(m *api.MaintenanceMode) {
+	c.maintenance = m
+}
+
+// Start begins the background collection loop. A non-positive interval
+// leaves the collector stopped; spaces are then only collected from the
+// admin API.
+func (c *SpaceCollector) Start() {
+	if c.interval <= 0 {
+		fmt.Println("[SpaceCollector] Scheduled space GC disabled")
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	go c.run(ctx)
+	fmt.Printf("[SpaceCollector] Started space collector (every %s)\n", c.interval)
+}
+
+// Stop gracefully shuts down the collector.
+func (c *SpaceCollector) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	if c.done != nil {
+		<-c.done
+	}
+	fmt.Println("[SpaceCollector] Stopped space collector")
+}
+
+func (c *SpaceCollector) run(ctx context.Context) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if c.maintenance.Checkpoint(ctx) != nil {
+				return
+			}
+			if _, err := c.spaces.CollectSpaces(ctx); err != nil {
+				fmt.Printf("[SpaceCollector] Space GC failed: %v\n", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+	"github.com/matou-dao/backend/internal/api"
+	"github.com/matou-dao/backend/internal/trust"
+)
+
+// TermWatcherConfig configures the term expiry job.
+type TermWatcherConfig struct {
+	// Interval between term checks.
+	Interval time.Duration
+	// NoticeWindow is how long before expiry holders and stewards are notified.
+	NoticeWindow time.Duration
+}
+
+// DefaultTermWatcherConfig returns a default term watcher config.
+func DefaultTermWatcherConfig() *TermWatcherConfig {
+	return &TermWatcherConfig{
+		Interval:     time.Hour,
+		NoticeWindow: trust.DefaultTermNoticeWindow,
+	}
+}
+
+// TermWatcher periodically checks term-limited role credentials and emits
+// term:expiring and term:expired events. The trust graph demotes lapsed
+// holders on its own; the expired event lets steward clients revoke the
+// credential in KERIA, since issuance and revocation happen client-side.
+type TermWatcher struct {
+	config      *TermWatcherConfig
+	store       *anystore.LocalStore
+	broker      *api.EventBroker
+	maintenance *api.MaintenanceMode
+	now         func() time.Time
+
+	mu       sync.Mutex
+	notified map[string]string // credential SAID -> last status announced
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewTermWatcher creates a new term expiry watcher.
+func NewTermWatcher(config *TermWatcherConfig, store *anystore.LocalStore, broker *api.EventBroker) *TermWatcher {
+	return &TermWatcher{
+		config:   config,
+		store:    store,
+		broker:   broker,
+		now:      time.Now,
+		notified: make(map[string]string),
+	}
+}
+
+// SetMaintenance attaches maintenance mode so checks pause while it is active.
+func (w *TermWatcher) SetMaintenance(m *api.MaintenanceMode) {
+	w.maintenance = m
+}
+
+// Start begins the background check loop.
+func (w *TermWatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go w.run(ctx)
+	fmt.Println("[TermWatcher] Started term expiry watcher")
+}
+
+// Stop gracefully shuts down the watcher.
+func (w *TermWatcher) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	if w.done != nil {
+		<-w.done
+	}
+	fmt.Println("[TermWatcher] Stopped term expiry watcher")
+}
+
+func (w *TermWatcher) run(ctx context.Context) {
+	defer close(w.done)
+
+	if w.maintenance.Checkpoint(ctx) != nil {
+		return
+	}
+	w.checkOnce(ctx)
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if w.maintenance.Checkpoint(ctx) != nil {
+				return
+			}
+			w.checkOnce(ctx)
+		}
+	}
+}
+
+// checkOnce evaluates all terms and announces status changes once each.
+func (w *TermWatcher) checkOnce(ctx context.Context) {
+	creds, err := w.store.GetAllCredentials(ctx)
+	if err != nil {
+		fmt.Printf("[TermWatcher] Failed to read credentials: %v\n", err)
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, term := range trust.EvaluateTerms(creds, w.now(), w.config.NoticeWindow) {
+		if term.Status != trust.TermStatusExpiring && term.Status != trust.TermStatusExpired {
+			continue
+		}
+		if w.notified[term.CredentialID] == term.Status {
+			continue
+		}
+		w.notified[term.CredentialID] = term.Status
+
+		fmt.Printf("[TermWatcher] %s term for %s (%s) ends %s\n",
+			term.Role, term.HolderAID, term.Status, term.EndsAt.Format(time.RFC3339))
+		w.broker.Broadcast(api.SSEEvent{
+			Type: "term:" + term.Status,
+			Data: term,
+		})
+	}
+}
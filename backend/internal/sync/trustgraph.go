@@ -0,0 +1,101 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matou-dao/backend/internal/api"
+)
+
+// DefaultTrustGraphPoll is how often the materialized trust graph is checked
+// against the credentials. Until a change is materialized, trust reads
+// rebuild the graph from the credentials instead.
+const DefaultTrustGraphPoll = 5 * time.Second
+
+// TrustGraphMaterializer keeps the trust graph stored in the local store
+// up to date, so trust reads can load it rather than rebuild it from every
+// credential. It materializes after credential writes, federation or score
+// weight changes, and at least once per interval for lapsing terms.
+type TrustGraphMaterializer struct {
+	interval    time.Duration
+	trust       *api.TrustHandler
+	maintenance *api.MaintenanceMode
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewTrustGraphMaterializer creates a new trust graph materializer. The
+// handler must have had EnableMaterializedGraph called with interval.
+func NewTrustGraphMaterializer(interval time.Duration, trust *api.TrustHandler) *TrustGraphMaterializer {
+	return &TrustGraphMaterializer{
+		interval: interval,
+		trust:    trust,
+	}
+}
+
+// SetMaintenance attaches maintenance mode so materializing pauses while it is active.
+func (m *TrustGraphMaterializer) SetMaintenance(mode *api.MaintenanceMode) {
+	m.maintenance = mode
+}
+
+// Start begins the background materializing loop. A non-positive interval
+// leaves the materializer stopped and trust reads always rebuild the graph.
+func (m *TrustGraphMaterializer) Start() {
+	if m.interval <= 0 {
+		fmt.Println("[TrustGraph] Materialized trust graph disabled")
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.done = make(chan struct{})
+
+	go m.run(ctx)
+	fmt.Printf("[TrustGraph] Started trust graph materializer (refresh every %s)\n", m.interval)
+}
+
+// Stop gracefully shuts down the materializer.
+func (m *TrustGraphMaterializer) Stop() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	if m.done != nil {
+		<-m.done
+	}
+	fmt.Println("[TrustGraph] Stopped trust graph materializer")
+}
+
+func (m *TrustGraphMaterializer) run(ctx context.Context) {
+	defer close(m.done)
+
+	ticker := time.NewTicker(DefaultTrustGraphPoll)
+	defer ticker.Stop()
+
+	for {
+		if m.maintenance.Checkpoint(ctx) != nil {
+			return
+		}
+		if m.trust.NeedsMaterializing() {
+			m.materialize(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *TrustGraphMaterializer) materialize(ctx context.Context) {
+	result, err := m.trust.MaterializeGraph(ctx)
+	if err != nil {
+		fmt.Printf("[TrustGraph] Materializing trust graph failed: %v\n", err)
+		return
+	}
+	if result.Changed() {
+		fmt.Printf("[TrustGraph] Materialized trust graph: %d nodes and %d edges written, %d nodes and %d edges removed\n",
+			result.NodesWritten, result.EdgesWritten, result.NodesDeleted, result.EdgesDeleted)
+	}
+}
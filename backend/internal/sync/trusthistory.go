@@ -0,0 +1,96 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matou-dao/backend/internal/api"
+)
+
+// TrustHistoryRecorder periodically records every AID's trust score so
+// score history can be charted over time.
+type TrustHistoryRecorder struct {
+	interval    time.Duration
+	trust       *api.TrustHandler
+	maintenance *api.MaintenanceMode
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewTrustHistoryRecorder creates a new trust history recorder.
+func NewTrustHistoryRecorder(interval time.Duration, trust *api.TrustHandler) *TrustHistoryRecorder {
+	return &TrustHistoryRecorder{
+		interval: interval,
+		trust:    trust,
+	}
+}
+
+// SetMaintenance attaches maintenance mode so recording pauses while it is active.
+func (r *TrustHistoryRecorder) SetMaintenance(m *api.MaintenanceMode) {
+	r.maintenance = m
+}
+
+// Start begins the background recording loop. A non-positive interval
+// leaves the recorder stopped and no history is kept.
+func (r *TrustHistoryRecorder) Start() {
+	if r.interval <= 0 {
+		fmt.Println("[TrustHistory] Score history disabled")
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go r.run(ctx)
+	fmt.Printf("[TrustHistory] Started trust history recorder (every %s)\n", r.interval)
+}
+
+// Stop gracefully shuts down the recorder.
+func (r *TrustHistoryRecorder) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	if r.done != nil {
+		<-r.done
+	}
+	fmt.Println("[TrustHistory] Stopped trust history recorder")
+}
+
+func (r *TrustHistoryRecorder) run(ctx context.Context) {
+	defer close(r.done)
+
+	// Catch up on a snapshot missed while the server was down, so
+	// restarts more often than the interval don't leave gaps
+	if r.maintenance.Checkpoint(ctx) != nil {
+		return
+	}
+	if last, err := r.trust.LastScoreHistory(ctx); err != nil || time.Since(last) >= r.interval {
+		r.record(ctx)
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if r.maintenance.Checkpoint(ctx) != nil {
+				return
+			}
+			r.record(ctx)
+		}
+	}
+}
+
+func (r *TrustHistoryRecorder) record(ctx context.Context) {
+	recorded, err := r.trust.RecordScoreHistory(ctx)
+	if err != nil {
+		fmt.Printf("[TrustHistory] Recording scores failed: %v\n", err)
+		return
+	}
+	fmt.Printf("[TrustHistory] Recorded %d trust scores\n", recorded)
+}
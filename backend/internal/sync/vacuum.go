@@ -0,0 +1,80 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/matou-dao/backend/internal/api"
+)
+
+// StoreVacuumer periodically vacuums the local store so expired caches,
+// old inbox items and stale presence records don't grow it unbounded.
+type StoreVacuumer struct {
+	interval    time.Duration
+	store       *api.StoreHandler
+	maintenance *api.MaintenanceMode
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewStoreVacuumer creates a new store vacuumer.
+func NewStoreVacuumer(interval time.Duration, store *api.StoreHandler) *StoreVacuumer {
+	return &StoreVacuumer{
+		interval: interval,
+		store:    store,
+	}
+}
+
+// SetMaintenance attaches maintenance mode so vacuums pause while it is active.
+func (v *StoreVacuumer) SetMaintenance(m *api.MaintenanceMode) {
+	v.maintenance = m
+}
+
+// Start begins the background vacuum loop. A non-positive interval leaves
+// the vacuumer stopped; vacuums then only run from the admin API.
+func (v *StoreVacuumer) Start() {
+	if v.interval <= 0 {
+		fmt.Println("[StoreVacuumer] Scheduled vacuum disabled")
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	v.cancel = cancel
+	v.done = make(chan struct{})
+
+	go v.run(ctx)
+	fmt.Printf("[StoreVacuumer] Started store vacuumer (every %s)\n", v.interval)
+}
+
+// Stop gracefully shuts down the vacuumer.
+func (v *StoreVacuumer) Stop() {
+	if v.cancel != nil {
+		v.cancel()
+	}
+	if v.done != nil {
+		<-v.done
+	}
+	fmt.Println("[StoreVacuumer] Stopped store vacuumer")
+}
+
+func (v *StoreVacuumer) run(ctx context.Context) {
+	defer close(v.done)
+
+	ticker := time.NewTicker(v.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if v.maintenance.Checkpoint(ctx) != nil {
+				return
+			}
+			if _, err := v.store.Vacuum(ctx); err != nil {
+				fmt.Printf("[StoreVacuumer] Vacuum failed: %v\n", err)
+			}
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/matou-dao/backend/internal/anysync"
 	"github.com/matou-dao/backend/internal/anystore"
 	"github.com/matou-dao/backend/internal/api"
+	"github.com/matou-dao/backend/internal/identity"
 )
 
 // WorkerConfig configures the background sync worker.
@@ -34,9 +35,12 @@ type Worker struct {
 	spaceManager *anysync.SpaceManager
 	store        *anystore.LocalStore
 	broker       *api.EventBroker
+	maintenance  *api.MaintenanceMode
+	userIdentity *identity.UserIdentity
 
 	mu            sync.RWMutex
 	knownSAIDs    map[string]bool
+	knownRequests map[string]bool
 	cancel        context.CancelFunc
 	done          chan struct{}
 }
@@ -49,14 +53,27 @@ func NewWorker(
 	broker *api.EventBroker,
 ) *Worker {
 	return &Worker{
-		config:       config,
-		spaceManager: spaceManager,
-		store:        store,
-		broker:       broker,
-		knownSAIDs:   make(map[string]bool),
+		config:        config,
+		spaceManager:  spaceManager,
+		store:         store,
+		broker:        broker,
+		knownSAIDs:    make(map[string]bool),
+		knownRequests: make(map[string]bool),
 	}
 }
 
+// SetIdentity attaches the local user's identity so the worker can announce
+// endorsement requests addressed to them.
+func (w *Worker) SetIdentity(u *identity.UserIdentity) {
+	w.userIdentity = u
+}
+
+// SetMaintenance attaches maintenance mode so the worker pauses between
+// sync cycles while maintenance is active.
+func (w *Worker) SetMaintenance(m *api.MaintenanceMode) {
+	w.maintenance = m
+}
+
 // Start begins the background sync loop.
 func (w *Worker) Start() {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -82,6 +99,9 @@ func (w *Worker) run(ctx context.Context) {
 	defer close(w.done)
 
 	// Initial sync
+	if w.maintenance.Checkpoint(ctx) != nil {
+		return
+	}
 	w.syncOnce(ctx)
 
 	ticker := time.NewTicker(w.config.Interval)
@@ -92,6 +112,10 @@ func (w *Worker) run(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			// Pause here (a safe checkpoint) while maintenance is active
+			if w.maintenance.Checkpoint(ctx) != nil {
+				return
+			}
 			w.syncOnce(ctx)
 		}
 	}
@@ -108,6 +132,8 @@ func (w *Worker) syncOnce(ctx context.Context) {
 		return
 	}
 
+	w.notifyEndorsementRequests(ctx, communitySpaceID)
+
 	treeMgr := w.spaceManager.CredentialTreeManager()
 	if treeMgr == nil {
 		return
@@ -145,6 +171,10 @@ func (w *Worker) syncOnce(ctx context.Context) {
 		cacheCtx := context.Background()
 		if storeErr := w.store.StoreCredential(cacheCtx, cached); storeErr != nil {
 			fmt.Printf("[SyncWorker] Failed to cache credential %s: %v\n", cred.SAID, storeErr)
+		} else {
+			for _, event := range api.CredentialStoredEvents(cached) {
+				w.broker.Broadcast(event)
+			}
 		}
 
 		// Determine event type
@@ -166,3 +196,43 @@ func (w *Worker) syncOnce(ctx context.Context) {
 		})
 	}
 }
+
+// notifyEndorsementRequests broadcasts endorsement:request for new pending
+// requests addressed to the local user.
+func (w *Worker) notifyEndorsementRequests(ctx context.Context, communitySpaceID string) {
+	if w.userIdentity == nil {
+		return
+	}
+	me := w.userIdentity.GetAID()
+	objMgr := w.spaceManager.ObjectTreeManager()
+	if me == "" || objMgr == nil {
+		return
+	}
+
+	objects, err := objMgr.ReadObjectsByType(ctx, communitySpaceID, "EndorsementRequest")
+	if err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, obj := range objects {
+		if w.knownRequests[obj.ID] {
+			continue
+		}
+		var req api.EndorsementRequest
+		if err := json.Unmarshal(obj.Data, &req); err != nil {
+			continue
+		}
+		if req.EndorserAID != me || req.Status != api.EndorsementPending {
+			continue
+		}
+		w.knownRequests[obj.ID] = true
+
+		w.broker.Broadcast(api.SSEEvent{
+			Type: "endorsement:request",
+			Data: api.EndorsementRequestView{ID: obj.ID, EndorsementRequest: req},
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Package taxonomy provides the community's controlled vocabularies for
+// profile tags such as skills and participation interests. Each vocabulary
+// maps canonical terms to synonyms so free-text tags can be normalized.
+package taxonomy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Vocabulary kinds
+const (
+	KindSkills    = "skills"
+	KindInterests = "interests"
+)
+
+// Kinds lists the supported vocabulary kinds
+func Kinds() []string {
+	return []string{KindSkills, KindInterests}
+}
+
+// IsKind returns true if kind is a supported vocabulary kind
+func IsKind(kind string) bool {
+	for _, k := range Kinds() {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// ProfileField returns the SharedProfile field a vocabulary applies to
+func ProfileField(kind string) string {
+	switch kind {
+	case KindSkills:
+		return "skills"
+	case KindInterests:
+		return "participationInterests"
+	}
+	return ""
+}
+
+// Term is a canonical vocabulary entry
+type Term struct {
+	ID       string   `json:"id"`    // Canonical tag stored on profiles, e.g. "software-development"
+	Label    string   `json:"label"` // Display label, e.g. "Software Development"
+	Synonyms []string `json:"synonyms,omitempty"`
+}
+
+// Vocabulary is a controlled list of terms for one kind of tag
+type Vocabulary struct {
+	Kind      string `json:"kind"`
+	Terms     []Term `json:"terms"`
+	UpdatedAt string `json:"updatedAt,omitempty"`
+	UpdatedBy string `json:"updatedBy,omitempty"`
+}
+
+// normalizeKey lowercases and collapses whitespace for matching
+func normalizeKey(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// Validate checks the vocabulary is well formed: known kind, non-empty
+// unique IDs, and no label or synonym claimed by two terms.
+func (v *Vocabulary) Validate() error {
+	if !IsKind(v.Kind) {
+		return fmt.Errorf("unknown vocabulary kind: %s", v.Kind)
+	}
+	owner := make(map[string]string)
+	ids := make(map[string]bool)
+	for _, t := range v.Terms {
+		if strings.TrimSpace(t.ID) == "" {
+			return fmt.Errorf("term id is required")
+		}
+		if ids[t.ID] {
+			return fmt.Errorf("duplicate term id: %s", t.ID)
+		}
+		ids[t.ID] = true
+
+		for _, name := range append([]string{t.ID, t.Label}, t.Synonyms...) {
+			key := normalizeKey(name)
+			if key == "" {
+				continue
+			}
+			if other, ok := owner[key]; ok && other != t.ID {
+				return fmt.Errorf("%q is used by both %s and %s", name, other, t.ID)
+			}
+			owner[key] = t.ID
+		}
+	}
+	return nil
+}
+
+// Resolve maps a tag (ID, label, or synonym, case-insensitive) to its
+// canonical term ID.
+func (v *Vocabulary) Resolve(tag string) (string, bool) {
+	key := normalizeKey(tag)
+	if key == "" {
+		return "", false
+	}
+	for _, t := range v.Terms {
+		if normalizeKey(t.ID) == key || normalizeKey(t.Label) == key {
+			return t.ID, true
+		}
+		for _, syn := range t.Synonyms {
+			if normalizeKey(syn) == key {
+				return t.ID, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Normalize resolves each tag to its canonical ID, dropping duplicates.
+// Tags that don't resolve are returned separately.
+func (v *Vocabulary) Normalize(tags []string) (canonical []string, unknown []string) {
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		id, ok := v.Resolve(tag)
+		if !ok {
+			unknown = append(unknown, tag)
+			continue
+		}
+		if !seen[id] {
+			seen[id] = true
+			canonical = append(canonical, id)
+		}
+	}
+	return canonical, unknown
+}
+
+// Sort orders terms by label for stable display
+func (v *Vocabulary) Sort() {
+	sort.Slice(v.Terms, func(i, j int) bool {
+		return strings.ToLower(v.Terms[i].Label) < strings.ToLower(v.Terms[j].Label)
+	})
+}
@@ -0,0 +1,54 @@
+package taxonomy
+
+import (
+	"testing"
+)
+
+func testVocabulary() *Vocabulary {
+	return &Vocabulary{
+		Kind: KindSkills,
+		Terms: []Term{
+			{ID: "software-development", Label: "Software Development", Synonyms: []string{"coding", "programming"}},
+			{ID: "te-reo-maori", Label: "Te Reo Māori", Synonyms: []string{"te reo"}},
+		},
+	}
+}
+
+func TestVocabulary_Validate(t *testing.T) {
+	if err := testVocabulary().Validate(); err != nil {
+		t.Fatalf("expected valid vocabulary, got %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		vocab *Vocabulary
+	}{
+		{"unknown kind", &Vocabulary{Kind: "colours"}},
+		{"empty id", &Vocabulary{Kind: KindSkills, Terms: []Term{{Label: "Coding"}}}},
+		{"duplicate id", &Vocabulary{Kind: KindSkills, Terms: []Term{{ID: "a"}, {ID: "a"}}}},
+		{"shared synonym", &Vocabulary{Kind: KindSkills, Terms: []Term{
+			{ID: "a", Synonyms: []string{"Design"}},
+			{ID: "b", Synonyms: []string{"design"}},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.vocab.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestVocabulary_Normalize(t *testing.T) {
+	vocab := testVocabulary()
+
+	canonical, unknown := vocab.Normalize([]string{"Coding", "software-development", "  TE   REO ", "juggling"})
+
+	if len(canonical) != 2 || canonical[0] != "software-development" || canonical[1] != "te-reo-maori" {
+		t.Errorf("unexpected canonical tags: %v", canonical)
+	}
+	if len(unknown) != 1 || unknown[0] != "juggling" {
+		t.Errorf("unexpected unknown tags: %v", unknown)
+	}
+}
@@ -0,0 +1,132 @@
+package trust
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Algorithm names a scoring algorithm
+type Algorithm string
+
+const (
+	// AlgorithmLinear scores each AID by a weighted sum of its own
+	// credentials (see ScoreWeights). This is the default.
+	AlgorithmLinear Algorithm = "linear"
+
+	// AlgorithmPageRank scores each AID by personalized PageRank over the
+	// credential graph, seeded at the org. Trust flows from issuer to
+	// subject, so endorsements from well-trusted members count for more
+	// and deep endorsement chains contribute transitively.
+	AlgorithmPageRank Algorithm = "pagerank"
+)
+
+// Scorer returns an AID's score. The Score it is given has the AID's
+// credential counts and graph depth filled in.
+type Scorer func(s *Score) float64
+
+// ScoreAlgorithm is a pluggable way of turning the trust graph into scores.
+// Communities can experiment with scoring by registering their own
+// implementation with RegisterAlgorithm and selecting it by name, per
+// request or with trustAlgorithm in the org config.
+type ScoreAlgorithm interface {
+	// Name is the algorithm's name in requests and config
+	Name() Algorithm
+	// Scorer prepares to score graph with weights. Algorithms that score
+	// the graph as a whole (like PageRank) do that work here, once per
+	// graph, and return a lookup.
+	Scorer(graph *Graph, weights ScoreWeights) Scorer
+}
+
+// AlgorithmFunc adapts a function to a ScoreAlgorithm
+type AlgorithmFunc struct {
+	AlgorithmName Algorithm
+	Func          func(graph *Graph, weights ScoreWeights) Scorer
+}
+
+// Name implements ScoreAlgorithm
+func (f AlgorithmFunc) Name() Algorithm { return f.AlgorithmName }
+
+// Scorer implements ScoreAlgorithm
+func (f AlgorithmFunc) Scorer(graph *Graph, weights ScoreWeights) Scorer {
+	return f.Func(graph, weights)
+}
+
+var (
+	algorithmsMu sync.RWMutex
+	algorithms   = map[Algorithm]ScoreAlgorithm{
+		AlgorithmLinear:   linearAlgorithm{},
+		AlgorithmPageRank: pageRankAlgorithm{},
+	}
+)
+
+// RegisterAlgorithm makes a scoring algorithm available by name. It
+// replaces any algorithm already registered under that name, except the
+// built-in linear algorithm, which is the fallback for everything else.
+func RegisterAlgorithm(a ScoreAlgorithm) error {
+	name := a.Name()
+	if name == "" || strings.ToLower(string(name)) != string(name) {
+		return fmt.Errorf("trust algorithm name %q must be non-empty and lower case", name)
+	}
+	if name == AlgorithmLinear {
+		return fmt.Errorf("trust algorithm %q is built in and cannot be replaced", name)
+	}
+	algorithmsMu.Lock()
+	defer algorithmsMu.Unlock()
+	algorithms[name] = a
+	return nil
+}
+
+// Algorithms returns the registered algorithm names, sorted
+func Algorithms() []Algorithm {
+	algorithmsMu.RLock()
+	defer algorithmsMu.RUnlock()
+	names := make([]Algorithm, 0, len(algorithms))
+	for name := range algorithms {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// lookupAlgorithm returns the registered algorithm, falling back to linear
+func lookupAlgorithm(name Algorithm) ScoreAlgorithm {
+	algorithmsMu.RLock()
+	defer algorithmsMu.RUnlock()
+	if a, ok := algorithms[name]; ok {
+		return a
+	}
+	return algorithms[AlgorithmLinear]
+}
+
+// ParseAlgorithm parses an algorithm name, which must be registered. An
+// empty name is AlgorithmLinear.
+func ParseAlgorithm(name string) (Algorithm, error) {
+	if name == "" {
+		return AlgorithmLinear, nil
+	}
+	algorithmsMu.RLock()
+	_, ok := algorithms[Algorithm(name)]
+	algorithmsMu.RUnlock()
+	if !ok {
+		var names []string
+		for _, a := range Algorithms() {
+			names = append(names, string(a))
+		}
+		return "", fmt.Errorf("unknown trust algorithm %q (expected one of %s)", name, strings.Join(names, ", "))
+	}
+	return Algorithm(name), nil
+}
+
+// linearAlgorithm is AlgorithmLinear: a weighted sum of each AID's own
+// credentials
+type linearAlgorithm struct{}
+
+func (linearAlgorithm) Name() Algorithm { return AlgorithmLinear }
+
+func (linearAlgorithm) Scorer(graph *Graph, weights ScoreWeights) Scorer {
+	return func(s *Score) float64 {
+		return linearScore(weights, s, graph, scoredIncomingEdges(graph, s.AID))
+	}
+}
@@ -0,0 +1,55 @@
+package trust
+
+import (
+	"testing"
+)
+
+func TestRegisterAlgorithm(t *testing.T) {
+	// Scores each AID by its number of distinct issuers, ignoring weights
+	issuers := AlgorithmFunc{
+		AlgorithmName: "issuers",
+		Func: func(graph *Graph, weights ScoreWeights) Scorer {
+			return func(s *Score) float64 { return float64(s.UniqueIssuers) }
+		},
+	}
+	if err := RegisterAlgorithm(issuers); err != nil {
+		t.Fatalf("RegisterAlgorithm failed: %v", err)
+	}
+
+	name, err := ParseAlgorithm("issuers")
+	if err != nil || name != "issuers" {
+		t.Fatalf("expected registered algorithm to parse, got %q, %v", name, err)
+	}
+	found := false
+	for _, a := range Algorithms() {
+		found = found || a == "issuers"
+	}
+	if !found {
+		t.Errorf("expected issuers in %v", Algorithms())
+	}
+
+	graph := endorsementChainGraph()
+	graph.AddEdge(&Edge{From: "EZ", To: "EX", CredentialID: "ESAID4", Type: EdgeTypeEndorsement})
+	calc := NewDefaultCalculator().WithAlgorithm(name)
+	if score := calc.CalculateScore("EX", graph); score.Score != 2 || score.UniqueIssuers != 2 {
+		t.Errorf("expected custom score of 2, got %+v", score)
+	}
+	if summary := calc.CalculateSummary(graph); summary.Algorithm != "issuers" || summary.MaxScore != 2 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+
+	for _, bad := range []Algorithm{"", "Issuers", AlgorithmLinear} {
+		if err := RegisterAlgorithm(AlgorithmFunc{AlgorithmName: bad, Func: issuers.Func}); err == nil {
+			t.Errorf("expected error registering %q", bad)
+		}
+	}
+}
+
+func TestCalculator_UnregisteredAlgorithm(t *testing.T) {
+	graph := endorsementChainGraph()
+	linear := NewDefaultCalculator().CalculateScore("EA", graph)
+	unknown := NewDefaultCalculator().WithAlgorithm("missing").CalculateScore("EA", graph)
+	if unknown.Score != linear.Score {
+		t.Errorf("expected unregistered algorithm to score as linear, got %f != %f", unknown.Score, linear.Score)
+	}
+}
@@ -0,0 +1,244 @@
+package trust
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Anomaly kinds reported by DetectAnomalies
+const (
+	// AnomalyEndorsementRing is a group of new AIDs endorsing only each other
+	AnomalyEndorsementRing = "endorsement_ring"
+	// AnomalyMassIssuer is an AID issuing many invitations in a short time
+	AnomalyMassIssuer = "mass_issuer"
+	// AnomalyUnrooted is a member no credential chain from the org reaches
+	AnomalyUnrooted = "unrooted"
+)
+
+// Anomaly is a suspicious pattern in the trust graph, for a moderator to
+// review. It is a signal, not a verdict.
+type Anomaly struct {
+	Kind string `json:"kind"`
+	// AIDs are the AIDs involved, sorted; a mass issuer comes first
+	AIDs []string `json:"aids"`
+	// Credentials are the SAIDs of the credentials forming the pattern
+	Credentials []string `json:"credentials,omitempty"`
+	Detail      string   `json:"detail"`
+}
+
+// AnomalyOptions sets the thresholds anomaly detection uses
+type AnomalyOptions struct {
+	// NewWithin is how recently an AID must have joined to count as new.
+	// AIDs with no known join time count as new.
+	NewWithin time.Duration
+	// RingMinSize is the smallest endorsement ring reported
+	RingMinSize int
+	// MassIssueCount invitations from one issuer within MassIssueWindow
+	// are reported. Invitations without a date aren't counted.
+	MassIssueCount  int
+	MassIssueWindow time.Duration
+}
+
+// DefaultAnomalyOptions returns the default thresholds
+func DefaultAnomalyOptions() AnomalyOptions {
+	return AnomalyOptions{
+		NewWithin:       30 * 24 * time.Hour,
+		RingMinSize:     3,
+		MassIssueCount:  10,
+		MassIssueWindow: 7 * 24 * time.Hour,
+	}
+}
+
+// DetectAnomalies looks for endorsement rings, mass invitation issuers and
+// members without an org-rooted credential chain. Anomalies are sorted by
+// kind, then by their first AID.
+func DetectAnomalies(graph *Graph, opts AnomalyOptions, now time.Time) []*Anomaly {
+	var anomalies []*Anomaly
+	anomalies = append(anomalies, endorsementRings(graph, opts, now)...)
+	anomalies = append(anomalies, massIssuers(graph, opts)...)
+	anomalies = append(anomalies, unrootedMembers(graph)...)
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		if anomalies[i].Kind != anomalies[j].Kind {
+			return anomalies[i].Kind < anomalies[j].Kind
+		}
+		return anomalies[i].AIDs[0] < anomalies[j].AIDs[0]
+	})
+	return anomalies
+}
+
+// endorsementRings reports groups of AIDs connected only by endorsements
+// among themselves, where every AID endorses and is endorsed within the
+// group and none joined before opts.NewWithin
+func endorsementRings(graph *Graph, opts AnomalyOptions, now time.Time) []*Anomaly {
+	neighbors := make(map[string][]string)
+	for _, e := range graph.Edges {
+		if e.Type == EdgeTypeEndorsement && e.From != e.To {
+			neighbors[e.From] = append(neighbors[e.From], e.To)
+			neighbors[e.To] = append(neighbors[e.To], e.From)
+		}
+	}
+
+	var rings []*Anomaly
+	seen := make(map[string]bool)
+	for start := range neighbors {
+		if seen[start] {
+			continue
+		}
+		// Collect the endorsement component around start
+		component := []string{start}
+		seen[start] = true
+		for i := 0; i < len(component); i++ {
+			for _, next := range neighbors[component[i]] {
+				if !seen[next] {
+					seen[next] = true
+					component = append(component, next)
+				}
+			}
+		}
+		if len(component) < opts.RingMinSize {
+			continue
+		}
+
+		members := make(map[string]bool, len(component))
+		for _, aid := range component {
+			members[aid] = true
+		}
+		endorsed := make(map[string]bool)
+		endorsing := make(map[string]bool)
+		var creds []string
+		for _, e := range graph.Edges {
+			if e.Type == EdgeTypeEndorsement && members[e.From] {
+				endorsing[e.From] = true
+				endorsed[e.To] = true
+				creds = append(creds, e.CredentialID)
+			}
+		}
+		ring := true
+		for _, aid := range component {
+			if aid == graph.OrgAID || !endorsed[aid] || !endorsing[aid] || established(graph, aid, opts.NewWithin, now) {
+				ring = false
+				break
+			}
+		}
+		if !ring {
+			continue
+		}
+
+		sort.Strings(component)
+		sort.Strings(creds)
+		rings = append(rings, &Anomaly{
+			Kind:        AnomalyEndorsementRing,
+			AIDs:        component,
+			Credentials: creds,
+			Detail:      fmt.Sprintf("%d new AIDs whose endorsements are all between each other", len(component)),
+		})
+	}
+	return rings
+}
+
+// established reports whether aid joined more than newWithin before now
+func established(graph *Graph, aid string, newWithin time.Duration, now time.Time) bool {
+	joined := time.Time{}
+	if node := graph.GetNode(aid); node != nil {
+		joined = node.JoinedAt
+	}
+	for _, e := range graph.Edges {
+		if e.To == aid && !e.CreatedAt.IsZero() && (joined.IsZero() || e.CreatedAt.Before(joined)) {
+			joined = e.CreatedAt
+		}
+	}
+	return !joined.IsZero() && joined.Before(now.Add(-newWithin))
+}
+
+// massIssuers reports AIDs other than the org that issued at least
+// opts.MassIssueCount dated invitations within any opts.MassIssueWindow
+func massIssuers(graph *Graph, opts AnomalyOptions) []*Anomaly {
+	invitations := make(map[string][]*Edge)
+	for _, e := range graph.Edges {
+		if e.Type == EdgeTypeInvitation && e.From != graph.OrgAID && !e.CreatedAt.IsZero() {
+			invitations[e.From] = append(invitations[e.From], e)
+		}
+	}
+
+	var issuers []*Anomaly
+	for issuer, edges := range invitations {
+		if opts.MassIssueCount <= 0 || len(edges) < opts.MassIssueCount {
+			continue
+		}
+		sort.Slice(edges, func(i, j int) bool { return edges[i].CreatedAt.Before(edges[j].CreatedAt) })
+
+		// Find the busiest window
+		best, bestStart := 0, 0
+		start := 0
+		for end := range edges {
+			for edges[end].CreatedAt.Sub(edges[start].CreatedAt) > opts.MassIssueWindow {
+				start++
+			}
+			if n := end - start + 1; n > best {
+				best, bestStart = n, start
+			}
+		}
+		if best < opts.MassIssueCount {
+			continue
+		}
+
+		window := edges[bestStart : bestStart+best]
+		aids := []string{issuer}
+		creds := make([]string, 0, len(window))
+		for _, e := range window {
+			aids = append(aids, e.To)
+			creds = append(creds, e.CredentialID)
+		}
+		sort.Strings(aids[1:])
+		sort.Strings(creds)
+		issuers = append(issuers, &Anomaly{
+			Kind:        AnomalyMassIssuer,
+			AIDs:        aids,
+			Credentials: creds,
+			Detail: fmt.Sprintf("%s issued %d invitations between %s and %s", issuer, best,
+				window[0].CreatedAt.UTC().Format(time.RFC3339), window[len(window)-1].CreatedAt.UTC().Format(time.RFC3339)),
+		})
+	}
+	return issuers
+}
+
+// unrootedMembers reports members that no chain of credentials issued from
+// the org reaches
+func unrootedMembers(graph *Graph) []*Anomaly {
+	issued := make(map[string][]string)
+	for _, e := range graph.Edges {
+		issued[e.From] = append(issued[e.From], e.To)
+	}
+	rooted := map[string]bool{graph.OrgAID: true}
+	queue := []string{graph.OrgAID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range issued[current] {
+			if !rooted[next] {
+				rooted[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	var unrooted []*Anomaly
+	for aid, node := range graph.Nodes {
+		if rooted[aid] || !node.IsMember() {
+			continue
+		}
+		var creds []string
+		for _, e := range graph.GetEdgesTo(aid) {
+			creds = append(creds, e.CredentialID)
+		}
+		sort.Strings(creds)
+		unrooted = append(unrooted, &Anomaly{
+			Kind:        AnomalyUnrooted,
+			AIDs:        []string{aid},
+			Credentials: creds,
+			Detail:      "no credential chain from the organization reaches this member",
+		})
+	}
+	return unrooted
+}
@@ -0,0 +1,74 @@
+package trust
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDetectAnomalies(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-365 * 24 * time.Hour)
+	recent := now.Add(-24 * time.Hour)
+
+	graph := NewGraph("EORG")
+	graph.AddNode(&Node{AID: "EORG", Role: "Organization"})
+	member := func(aid string, joined time.Time) {
+		graph.AddNode(&Node{AID: aid, Role: "Member", JoinedAt: joined})
+		graph.AddEdge(&Edge{From: "EORG", To: aid, CredentialID: "M-" + aid, Type: EdgeTypeMembership, CreatedAt: joined})
+	}
+	endorse := func(from, to string) {
+		graph.AddEdge(&Edge{From: from, To: to, CredentialID: "E-" + from + "-" + to, Type: EdgeTypeEndorsement})
+	}
+
+	// A ring of three new members, and an older pair endorsing each other
+	for _, aid := range []string{"ER1", "ER2", "ER3"} {
+		member(aid, recent)
+	}
+	endorse("ER1", "ER2")
+	endorse("ER2", "ER3")
+	endorse("ER3", "ER1")
+	member("EOLD1", old)
+	member("EOLD2", old)
+	member("EOLD3", recent)
+	endorse("EOLD1", "EOLD2")
+	endorse("EOLD2", "EOLD3")
+	endorse("EOLD3", "EOLD1")
+
+	// An established member inviting twelve AIDs within two days
+	for i := 0; i < 12; i++ {
+		aid := fmt.Sprintf("EINV%02d", i)
+		graph.AddNode(&Node{AID: aid, Role: "Member"})
+		graph.AddEdge(&Edge{From: "EOLD1", To: aid, CredentialID: "I-" + aid, Type: EdgeTypeInvitation, CreatedAt: recent.Add(time.Duration(i) * 4 * time.Hour)})
+	}
+
+	// A member whose only credential comes from an AID outside the org's chain
+	graph.AddNode(&Node{AID: "ESTRAY", Role: "Member"})
+	graph.AddNode(&Node{AID: "EOUTSIDER", Role: "Member"})
+	graph.AddEdge(&Edge{From: "EOUTSIDER", To: "ESTRAY", CredentialID: "I-STRAY", Type: EdgeTypeInvitation})
+
+	anomalies := DetectAnomalies(graph, DefaultAnomalyOptions(), now)
+	got := make(map[string][]*Anomaly)
+	for _, a := range anomalies {
+		got[a.Kind] = append(got[a.Kind], a)
+	}
+
+	if rings := got[AnomalyEndorsementRing]; len(rings) != 1 || fmt.Sprint(rings[0].AIDs) != "[ER1 ER2 ER3]" || len(rings[0].Credentials) != 3 {
+		t.Errorf("expected only the new ring, got %+v", rings)
+	}
+	if issuers := got[AnomalyMassIssuer]; len(issuers) != 1 || issuers[0].AIDs[0] != "EOLD1" || len(issuers[0].Credentials) != 12 {
+		t.Errorf("expected EOLD1 as a mass issuer, got %+v", issuers)
+	}
+	// Neither the outsider nor the member it invited is reached from the org
+	if unrooted := got[AnomalyUnrooted]; len(unrooted) != 2 || unrooted[0].AIDs[0] != "EOUTSIDER" || unrooted[1].AIDs[0] != "ESTRAY" {
+		t.Errorf("expected EOUTSIDER and ESTRAY to be unrooted, got %+v", unrooted)
+	}
+
+	opts := DefaultAnomalyOptions()
+	opts.MassIssueWindow = 24 * time.Hour
+	for _, a := range DetectAnomalies(graph, opts, now) {
+		if a.Kind == AnomalyMassIssuer {
+			t.Errorf("expected no mass issuer with a one-day window, got %+v", a)
+		}
+	}
+}
@@ -2,7 +2,7 @@ package trust
 
 import (
 	"context"
-	"encoding/json"
+	"math"
 	"time"
 
 	"github.com/matou-dao/backend/internal/anystore"
@@ -13,6 +13,15 @@ type Builder struct {
 	store            *anystore.LocalStore
 	orgAID           string
 	extraCredentials []*anystore.CachedCredential
+	now              func() time.Time
+
+	// terms maps credential SAID to its term, for term-limited roles
+	terms map[string]*Term
+
+	// federation lists trusted peer orgs; verified holds those whose KEL
+	// checked out for this build
+	federation []FederatedOrg
+	verified   map[string]*FederatedOrg
 }
 
 // NewBuilder creates a new trust graph builder
@@ -20,6 +29,7 @@ func NewBuilder(store *anystore.LocalStore, orgAID string) *Builder {
 	return &Builder{
 		store:  store,
 		orgAID: orgAID,
+		now:    time.Now,
 	}
 }
 
@@ -30,6 +40,14 @@ func (b *Builder) WithExtraCredentials(creds []*anystore.CachedCredential) *Buil
 	return b
 }
 
+// WithFederation sets the trusted peer orgs whose credentials are recognized
+// as federated edges. Credentials from a peer whose KEL hasn't been synced and
+// verified are ignored.
+func (b *Builder) WithFederation(orgs []FederatedOrg) *Builder {
+	b.federation = orgs
+	return b
+}
+
 // Build constructs the trust graph from all cached credentials
 func (b *Builder) Build(ctx context.Context) (*Graph, error) {
 	graph := NewGraph(b.orgAID)
@@ -43,7 +61,7 @@ func (b *Builder) Build(ctx context.Context) (*Graph, error) {
 	})
 
 	// Get all cached credentials
-	credentials, err := b.getAllCredentials(ctx)
+	credentials, err := b.store.GetAllCredentials(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -62,6 +80,24 @@ func (b *Builder) Build(ctx context.Context) (*Graph, error) {
 		}
 	}
 
+	// Expired credentials give no edges, nor renew a term
+	credentials = WithoutExpired(credentials, b.now())
+
+	// Work out which term-limited roles have lapsed or been renewed
+	b.terms = make(map[string]*Term)
+	for _, t := range EvaluateTerms(credentials, b.now(), 0) {
+		b.terms[t.CredentialID] = t
+	}
+
+	// Only peers with a verified KEL are recognized
+	b.verified = make(map[string]*FederatedOrg)
+	for _, status := range CheckFederation(ctx, b.store, b.federation) {
+		if status.Verified {
+			peer := status.FederatedOrg
+			b.verified[peer.AID] = &peer
+		}
+	}
+
 	// Process each credential
 	for _, cred := range credentials {
 		b.processCredential(graph, cred)
@@ -76,36 +112,6 @@ func (b *Builder) Build(ctx context.Context) (*Graph, error) {
 	return graph, nil
 }
 
-// getAllCredentials retrieves all credentials from the cache
-func (b *Builder) getAllCredentials(ctx context.Context) ([]*anystore.CachedCredential, error) {
-	collection, err := b.store.CredentialsCache(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	iter, err := collection.Find(nil).Iter(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer iter.Close()
-
-	var credentials []*anystore.CachedCredential
-	for iter.Next() {
-		doc, err := iter.Doc()
-		if err != nil {
-			continue
-		}
-
-		var cred anystore.CachedCredential
-		if err := json.Unmarshal([]byte(doc.Value().String()), &cred); err != nil {
-			continue
-		}
-		credentials = append(credentials, &cred)
-	}
-
-	return credentials, nil
-}
-
 // processCredential adds nodes and edges from a credential
 func (b *Builder) processCredential(graph *Graph, cred *anystore.CachedCredential) {
 	// Extract credential data
@@ -114,14 +120,26 @@ func (b *Builder) processCredential(graph *Graph, cred *anystore.CachedCredentia
 	// Determine edge type from schema
 	edgeType := SchemaToEdgeType(cred.SchemaID)
 
+	// Credentials from federated peers become federated edges, provided the
+	// peer is verified and the schema is one we recognize from it
+	if b.isFederationPeer(cred.IssuerAID) {
+		peer, ok := b.verified[cred.IssuerAID]
+		if !ok || !peer.AllowsSchema(cred.SchemaID) {
+			return
+		}
+		b.processFederatedCredential(graph, cred, peer, data)
+		return
+	}
+
 	// Skip self-claims for edge creation (but still add nodes)
 	if edgeType == EdgeTypeSelfClaim {
 		// Add subject node only
 		graph.AddNode(&Node{
-			AID:      cred.SubjectAID,
-			Alias:    data.displayName,
-			Role:     data.role,
-			JoinedAt: data.joinedAt,
+			AID:        cred.SubjectAID,
+			Alias:      data.displayName,
+			Role:       data.role,
+			JoinedAt:   data.joinedAt,
+			Attributes: data.attributes,
 		})
 		return
 	}
@@ -138,15 +156,27 @@ func (b *Builder) processCredential(graph *Graph, cred *anystore.CachedCredentia
 	})
 
 	// Add subject node
+	// Participation credentials don't confer membership, so they never set a role
 	subjectRole := data.role
-	if subjectRole == "" {
+	if subjectRole == "" && edgeType != EdgeTypeParticipation {
 		subjectRole = "Member"
 	}
+	if term, ok := b.terms[cred.ID]; ok {
+		switch term.Status {
+		case TermStatusExpired:
+			// Lapsed term: holder is demoted until renewed via governance
+			subjectRole = term.DowngradeTo
+		case TermStatusRenewed:
+			// Superseded by a later term; let that credential set the role
+			subjectRole = ""
+		}
+	}
 	graph.AddNode(&Node{
-		AID:      cred.SubjectAID,
-		Alias:    data.displayName,
-		Role:     subjectRole,
-		JoinedAt: data.joinedAt,
+		AID:        cred.SubjectAID,
+		Alias:      data.displayName,
+		Role:       subjectRole,
+		JoinedAt:   data.joinedAt,
+		Attributes: data.attributes,
 	})
 
 	// Create edge
@@ -157,15 +187,55 @@ func (b *Builder) processCredential(graph *Graph, cred *anystore.CachedCredentia
 		Type:         edgeType,
 		CreatedAt:    data.joinedAt,
 	}
+	if edgeType == EdgeTypeEndorsement {
+		edge.Confidence = data.confidence
+	}
 
 	graph.AddEdge(edge)
 }
 
+// isFederationPeer checks if an AID is a configured peer org
+func (b *Builder) isFederationPeer(aid string) bool {
+	if aid == b.orgAID {
+		return false
+	}
+	for _, f := range b.federation {
+		if f.AID == aid {
+			return true
+		}
+	}
+	return false
+}
+
+// processFederatedCredential adds a federated edge from a peer org. Peer
+// credentials are recognized for trust, but never confer a role here.
+func (b *Builder) processFederatedCredential(graph *Graph, cred *anystore.CachedCredential, peer *FederatedOrg, data credentialData) {
+	graph.AddNode(&Node{
+		AID:   peer.AID,
+		Alias: peer.Name,
+		Role:  RoleFederatedOrganization,
+	})
+	graph.AddNode(&Node{
+		AID:      cred.SubjectAID,
+		Alias:    data.displayName,
+		JoinedAt: data.joinedAt,
+	})
+	graph.AddEdge(&Edge{
+		From:         cred.IssuerAID,
+		To:           cred.SubjectAID,
+		CredentialID: cred.ID,
+		Type:         EdgeTypeFederated,
+		CreatedAt:    data.joinedAt,
+	})
+}
+
 // credentialData holds extracted data from a credential
 type credentialData struct {
 	role        string
 	displayName string
 	joinedAt    time.Time
+	attributes  map[string]interface{}
+	confidence  *float64
 }
 
 // extractCredentialData extracts relevant data from credential
@@ -173,26 +243,11 @@ func (b *Builder) extractCredentialData(cred *anystore.CachedCredential) credent
 	data := credentialData{}
 
 	// Try to extract data from the credential
-	if cred.Data == nil {
+	dataMap := credentialDataMap(cred)
+	if dataMap == nil {
 		return data
 	}
 
-	// Convert data to map
-	var dataMap map[string]interface{}
-	switch v := cred.Data.(type) {
-	case map[string]interface{}:
-		dataMap = v
-	default:
-		// Try JSON unmarshal
-		bytes, err := json.Marshal(cred.Data)
-		if err != nil {
-			return data
-		}
-		if err := json.Unmarshal(bytes, &dataMap); err != nil {
-			return data
-		}
-	}
-
 	// Extract role
 	if role, ok := dataMap["role"].(string); ok {
 		data.role = role
@@ -217,6 +272,17 @@ func (b *Builder) extractCredentialData(cred *anystore.CachedCredential) credent
 		}
 	}
 
+	// Extract endorsement confidence, clamped to [0, 1]
+	if confidence, ok := dataMap["confidence"].(float64); ok {
+		confidence = math.Max(0, math.Min(1, confidence))
+		data.confidence = &confidence
+	}
+
+	// Extract custom role attributes
+	if attrs, ok := dataMap["attributes"].(map[string]interface{}); ok && len(attrs) > 0 {
+		data.attributes = attrs
+	}
+
 	return data
 }
 
@@ -233,64 +299,5 @@ func (b *Builder) BuildForAID(ctx context.Context, aid string, depth int) (*Grap
 		return fullGraph, nil
 	}
 
-	// Build subgraph using BFS from the target AID
-	subgraph := NewGraph(b.orgAID)
-
-	// BFS to find connected nodes within depth
-	visited := make(map[string]bool)
-	queue := []struct {
-		aid   string
-		depth int
-	}{{aid, 0}}
-
-	for len(queue) > 0 {
-		current := queue[0]
-		queue = queue[1:]
-
-		if visited[current.aid] {
-			continue
-		}
-		visited[current.aid] = true
-
-		// Add node to subgraph
-		if node := fullGraph.GetNode(current.aid); node != nil {
-			subgraph.AddNode(&Node{
-				AID:             node.AID,
-				Alias:           node.Alias,
-				Role:            node.Role,
-				JoinedAt:        node.JoinedAt,
-				CredentialCount: node.CredentialCount,
-			})
-		}
-
-		// If within depth limit, explore neighbors
-		if current.depth < depth {
-			// Add outgoing edges and neighbors
-			for _, edge := range fullGraph.GetEdgesFrom(current.aid) {
-				subgraph.AddEdge(edge)
-				if !visited[edge.To] {
-					queue = append(queue, struct {
-						aid   string
-						depth int
-					}{edge.To, current.depth + 1})
-				}
-			}
-
-			// Add incoming edges and neighbors
-			for _, edge := range fullGraph.GetEdgesTo(current.aid) {
-				subgraph.AddEdge(edge)
-				if !visited[edge.From] {
-					queue = append(queue, struct {
-						aid   string
-						depth int
-					}{edge.From, current.depth + 1})
-				}
-			}
-		}
-	}
-
-	subgraph.MarkBidirectionalEdges()
-	subgraph.Updated = time.Now().UTC()
-
-	return subgraph, nil
+	return fullGraph.Subgraph(aid, depth), nil
 }
@@ -484,3 +484,134 @@ func TestBuilder_Build_StoresPersistently(t *testing.T) {
 		}
 	}
 }
+
+func TestBuilder_Build_DemotesExpiredTerms(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	// Lapsed steward term with an explicit downgrade role
+	store.StoreCredential(ctx, &anystore.CachedCredential{
+		ID:         "ESTEWARD1",
+		IssuerAID:  "EORG123",
+		SubjectAID: "EUSER1",
+		SchemaID:   "EMatouStewardSchemaV1",
+		Data: map[string]interface{}{
+			"role":        "Operations Steward",
+			"termEndsAt":  now.Add(-time.Hour).Format(time.RFC3339),
+			"downgradeTo": "Trusted Member",
+		},
+	})
+	// Lapsed term renewed by a later credential
+	store.StoreCredential(ctx, &anystore.CachedCredential{
+		ID:         "ESTEWARD2",
+		IssuerAID:  "EORG123",
+		SubjectAID: "EUSER2",
+		SchemaID:   "EMatouStewardSchemaV1",
+		Data: map[string]interface{}{
+			"role":       "Operations Steward",
+			"termEndsAt": now.Add(-time.Hour).Format(time.RFC3339),
+		},
+	})
+	store.StoreCredential(ctx, &anystore.CachedCredential{
+		ID:         "ESTEWARD3",
+		IssuerAID:  "EORG123",
+		SubjectAID: "EUSER2",
+		SchemaID:   "EMatouStewardSchemaV1",
+		Data: map[string]interface{}{
+			"role":       "Operations Steward",
+			"termEndsAt": now.Add(365 * 24 * time.Hour).Format(time.RFC3339),
+		},
+	})
+
+	graph, err := NewBuilder(store, "EORG123").Build(ctx)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if role := graph.GetNode("EUSER1").Role; role != "Trusted Member" {
+		t.Errorf("expected lapsed steward demoted to Trusted Member, got %s", role)
+	}
+	if role := graph.GetNode("EUSER2").Role; role != "Operations Steward" {
+		t.Errorf("expected renewed steward to keep role, got %s", role)
+	}
+}
+
+func TestBuilder_Build_EndorsementConfidence(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	endorsements := map[string]interface{}{
+		"EEND1": 0.5,
+		"EEND2": 3.0, // Out of range, clamped to 1
+		"EEND3": nil, // No confidence stated
+	}
+	for id, confidence := range endorsements {
+		data := map[string]interface{}{"category": "facilitation"}
+		if confidence != nil {
+			data["confidence"] = confidence
+		}
+		store.StoreCredential(ctx, &anystore.CachedCredential{
+			ID:         id,
+			IssuerAID:  "EUSER1",
+			SubjectAID: "EUSER2",
+			SchemaID:   EndorsementSchema,
+			Data:       data,
+		})
+	}
+
+	graph, err := NewBuilder(store, "EORG123").Build(ctx)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	strengths := map[string]float64{}
+	for _, e := range graph.GetEdgesTo("EUSER2") {
+		if e.Type != EdgeTypeEndorsement {
+			t.Errorf("expected endorsement edge, got %s", e.Type)
+		}
+		strengths[e.CredentialID] = e.Strength()
+	}
+	want := map[string]float64{"EEND1": 0.5, "EEND2": 1, "EEND3": 1}
+	for id, s := range want {
+		if strengths[id] != s {
+			t.Errorf("expected %s strength %v, got %v", id, s, strengths[id])
+		}
+	}
+}
+
+func TestBuilder_Build_SkipsExpiredCredentials(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	store.StoreCredential(ctx, &anystore.CachedCredential{
+		ID: "EMEMBER1", IssuerAID: "EORG123", SubjectAID: "EUSER1", SchemaID: "EMatouMembershipSchemaV1",
+		Data: map[string]interface{}{"role": "Member", "expiresAt": now.Add(time.Hour).Format(time.RFC3339)},
+	})
+	store.StoreCredential(ctx, &anystore.CachedCredential{
+		ID: "EMEMBER2", IssuerAID: "EORG123", SubjectAID: "EUSER2", SchemaID: "EMatouMembershipSchemaV1",
+		Data: map[string]interface{}{"role": "Member", "expiresAt": now.Add(-time.Hour).Format(time.RFC3339)},
+	})
+	store.StoreCredential(ctx, &anystore.CachedCredential{
+		ID: "EEND1", IssuerAID: "EUSER2", SubjectAID: "EUSER1", SchemaID: EndorsementSchema,
+		ExpiredAt: &now,
+	})
+
+	graph, err := NewBuilder(store, "EORG123").Build(ctx)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if graph.GetNode("EUSER2") != nil {
+		t.Error("expected the member whose only credential expired to be left out")
+	}
+	if graph.EdgeCount() != 1 || graph.GetEdgesTo("EUSER1")[0].CredentialID != "EMEMBER1" {
+		t.Errorf("expected only the unexpired membership edge, got %d edges", graph.EdgeCount())
+	}
+}
@@ -0,0 +1,97 @@
+package trust
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/anyproto/any-sync/util/crypto"
+)
+
+// WellKnownDescriptorPath is where communities publish their descriptor
+const WellKnownDescriptorPath = "/.well-known/matou.json"
+
+// DescriptorVersion is the current community descriptor format
+const DescriptorVersion = 1
+
+// CommunityDescriptor is a community's public, signed self-description.
+// Other communities read it to set up federation.
+type CommunityDescriptor struct {
+	Version     int      `json:"version"`
+	OrgAID      string   `json:"orgAid"`
+	Name        string   `json:"name"`
+	OOBI        string   `json:"oobi,omitempty"`
+	Schemas     []string `json:"schemas"`
+	JoinURL     string   `json:"joinUrl,omitempty"`
+	PublishedAt string   `json:"publishedAt"`
+
+	// SignerKey is the publishing backend's account address; Signature is
+	// its base64 Ed25519 signature over the fields above
+	SignerKey string `json:"signerKey"`
+	Signature string `json:"signature"`
+}
+
+// signingBytes returns the JSON the signature covers
+func (d *CommunityDescriptor) signingBytes() ([]byte, error) {
+	content := *d
+	content.SignerKey, content.Signature = "", ""
+	return json.Marshal(content)
+}
+
+// Sign stamps and signs the descriptor
+func (d *CommunityDescriptor) Sign(key crypto.PrivKey, now time.Time) error {
+	if key == nil {
+		return fmt.Errorf("signing key is required")
+	}
+	d.Version = DescriptorVersion
+	d.PublishedAt = now.UTC().Format(time.RFC3339)
+
+	data, err := d.signingBytes()
+	if err != nil {
+		return fmt.Errorf("marshaling descriptor: %w", err)
+	}
+	sig, err := key.Sign(data)
+	if err != nil {
+		return fmt.Errorf("signing descriptor: %w", err)
+	}
+	d.SignerKey = key.GetPublic().Account()
+	d.Signature = base64.StdEncoding.EncodeToString(sig)
+	return nil
+}
+
+// Verify checks the descriptor's required fields and signature. The signature
+// proves the descriptor is intact and which backend key published it; it does
+// not prove control of the org AID, which is checked against the org's KEL.
+func (d *CommunityDescriptor) Verify() error {
+	if d.Version != DescriptorVersion {
+		return fmt.Errorf("unsupported descriptor version %d", d.Version)
+	}
+	if d.OrgAID == "" {
+		return fmt.Errorf("descriptor has no orgAid")
+	}
+	pub, err := crypto.DecodeAccountAddress(d.SignerKey)
+	if err != nil {
+		return fmt.Errorf("invalid signer key: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(d.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding")
+	}
+	data, err := d.signingBytes()
+	if err != nil {
+		return err
+	}
+	if ok, err := pub.Verify(data, sig); err != nil || !ok {
+		return fmt.Errorf("bad descriptor signature")
+	}
+	return nil
+}
+
+// FederatedOrg returns a federation entry for the described community,
+// recognizing every schema it publishes
+func (d *CommunityDescriptor) FederatedOrg() FederatedOrg {
+	schemas := make([]string, len(d.Schemas))
+	copy(schemas, d.Schemas)
+	return FederatedOrg{AID: d.OrgAID, Name: d.Name, Schemas: schemas}
+}
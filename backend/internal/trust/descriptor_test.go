@@ -0,0 +1,64 @@
+package trust
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anyproto/any-sync/util/crypto"
+)
+
+func TestCommunityDescriptor_SignVerify(t *testing.T) {
+	key, _, err := crypto.GenerateRandomEd25519KeyPair()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	d := &CommunityDescriptor{
+		OrgAID:  "EPEER1",
+		Name:    "Peer One",
+		OOBI:    "http://keria.example/oobi/EPEER1",
+		Schemas: []string{"EMatouMembershipSchemaV1", EndorsementSchema},
+		JoinURL: "https://peer.example/join",
+	}
+	if err := d.Sign(key, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if d.Version != DescriptorVersion || d.PublishedAt != "2026-10-01T00:00:00Z" {
+		t.Errorf("unexpected stamp: version=%d publishedAt=%s", d.Version, d.PublishedAt)
+	}
+	if err := d.Verify(); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	peer := d.FederatedOrg()
+	if peer.AID != "EPEER1" || peer.Name != "Peer One" || !peer.AllowsSchema(EndorsementSchema) {
+		t.Errorf("unexpected federated org: %+v", peer)
+	}
+
+	// Any change to the signed fields breaks the signature
+	tampered := *d
+	tampered.JoinURL = "https://evil.example/join"
+	if err := tampered.Verify(); err == nil {
+		t.Error("expected tampered descriptor to fail verification")
+	}
+
+	// A signature from a different key doesn't verify
+	other, _, _ := crypto.GenerateRandomEd25519KeyPair()
+	swapped := *d
+	swapped.SignerKey = other.GetPublic().Account()
+	if err := swapped.Verify(); err == nil {
+		t.Error("expected descriptor with swapped signer key to fail verification")
+	}
+}
+
+func TestCommunityDescriptor_VerifyRejectsIncomplete(t *testing.T) {
+	if err := (&CommunityDescriptor{Version: 2, OrgAID: "EPEER1"}).Verify(); err == nil {
+		t.Error("expected error for unsupported version")
+	}
+	if err := (&CommunityDescriptor{Version: DescriptorVersion}).Verify(); err == nil {
+		t.Error("expected error for missing orgAid")
+	}
+	if err := (&CommunityDescriptor{Version: DescriptorVersion, OrgAID: "EPEER1"}).Verify(); err == nil {
+		t.Error("expected error for unsigned descriptor")
+	}
+}
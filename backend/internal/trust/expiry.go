@@ -0,0 +1,47 @@
+package trust
+
+import (
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+// CredentialExpiresAt returns when a credential stops being valid according
+// to its expiresAt claim, and false when it has none that parses.
+func CredentialExpiresAt(cred *anystore.CachedCredential) (time.Time, bool) {
+	data := credentialDataMap(cred)
+	if data == nil {
+		return time.Time{}, false
+	}
+	raw, _ := data["expiresAt"].(string)
+	if raw == "" {
+		return time.Time{}, false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return expiresAt, true
+}
+
+// CredentialExpired reports whether a credential has expired at now, either
+// because the expiration job marked it or its expiresAt claim has passed
+// since. Unlike a lapsed term, an expired credential vouches for no one.
+func CredentialExpired(cred *anystore.CachedCredential, now time.Time) bool {
+	if cred.ExpiredAt != nil {
+		return true
+	}
+	expiresAt, ok := CredentialExpiresAt(cred)
+	return ok && !now.Before(expiresAt)
+}
+
+// WithoutExpired returns the credentials that haven't expired at now.
+func WithoutExpired(creds []*anystore.CachedCredential, now time.Time) []*anystore.CachedCredential {
+	kept := make([]*anystore.CachedCredential, 0, len(creds))
+	for _, cred := range creds {
+		if !CredentialExpired(cred, now) {
+			kept = append(kept, cred)
+		}
+	}
+	return kept
+}
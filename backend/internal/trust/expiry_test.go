@@ -0,0 +1,40 @@
+package trust
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+func TestCredentialExpired(t *testing.T) {
+	now := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	marked := now.Add(-time.Hour)
+
+	tests := []struct {
+		name string
+		cred *anystore.CachedCredential
+		want bool
+	}{
+		{"no expiry", &anystore.CachedCredential{ID: "E1"}, false},
+		{"future expiry", &anystore.CachedCredential{ID: "E2",
+			Data: map[string]interface{}{"expiresAt": "2026-11-01T00:00:00Z"}}, false},
+		{"passed expiry", &anystore.CachedCredential{ID: "E3",
+			Data: map[string]interface{}{"expiresAt": "2026-10-01T00:00:00Z"}}, true},
+		{"unparseable expiry", &anystore.CachedCredential{ID: "E4",
+			Data: map[string]interface{}{"expiresAt": "soon"}}, false},
+		{"marked expired", &anystore.CachedCredential{ID: "E5", ExpiredAt: &marked}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CredentialExpired(tt.cred, now); got != tt.want {
+				t.Errorf("CredentialExpired() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+
+	kept := WithoutExpired([]*anystore.CachedCredential{tests[0].cred, tests[2].cred, tests[1].cred}, now)
+	if len(kept) != 2 || kept[0].ID != "E1" || kept[1].ID != "E2" {
+		t.Errorf("expected the unexpired credentials in order, got %+v", kept)
+	}
+}
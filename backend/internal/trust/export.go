@@ -0,0 +1,248 @@
+package trust
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFormat is a file format the trust graph can be exported in
+type ExportFormat string
+
+const (
+	// ExportGraphML is GraphML, for Gephi, yEd and NetworkX
+	ExportGraphML ExportFormat = "graphml"
+	// ExportDOT is the Graphviz DOT language
+	ExportDOT ExportFormat = "dot"
+	// ExportCSV is a Gephi-style node or edge table
+	ExportCSV ExportFormat = "csv"
+)
+
+// CSVTable selects which table a CSV export contains
+type CSVTable string
+
+const (
+	CSVNodes CSVTable = "nodes" // One row per identity
+	CSVEdges CSVTable = "edges" // One row per credential (default)
+)
+
+// ParseExportFormat parses a format name. An empty name is GraphML.
+func ParseExportFormat(name string) (ExportFormat, error) {
+	switch ExportFormat(strings.ToLower(name)) {
+	case "", ExportGraphML:
+		return ExportGraphML, nil
+	case ExportDOT:
+		return ExportDOT, nil
+	case ExportCSV:
+		return ExportCSV, nil
+	}
+	return "", fmt.Errorf("unknown export format %q (expected graphml, dot or csv)", name)
+}
+
+// ContentType returns the MIME type for the format
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportDOT:
+		return "text/vnd.graphviz; charset=utf-8"
+	case ExportCSV:
+		return "text/csv; charset=utf-8"
+	}
+	return "application/graphml+xml; charset=utf-8"
+}
+
+// Exporter serializes a trust graph with per-node scores. Scores may be
+// nil, in which case score attributes are left out.
+type Exporter struct {
+	Graph  *Graph
+	Scores map[string]*Score
+}
+
+// Write serializes the graph in format. table is only used for CSV, where
+// nodes and edges are separate tables; it defaults to edges.
+func (e *Exporter) Write(w io.Writer, format ExportFormat, table CSVTable) error {
+	switch format {
+	case ExportGraphML:
+		return e.writeGraphML(w)
+	case ExportDOT:
+		return e.writeDOT(w)
+	case ExportCSV:
+		if table == CSVNodes {
+			return e.writeCSVNodes(w)
+		}
+		return e.writeCSVEdges(w)
+	}
+	return fmt.Errorf("unknown export format %q", format)
+}
+
+// sortedNodes returns the graph's nodes ordered by AID so exports are stable
+func (e *Exporter) sortedNodes() []*Node {
+	nodes := make([]*Node, 0, len(e.Graph.Nodes))
+	for _, n := range e.Graph.Nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].AID < nodes[j].AID })
+	return nodes
+}
+
+// score returns the node's score and whether one was computed
+func (e *Exporter) score(aid string) (float64, bool) {
+	if s, ok := e.Scores[aid]; ok && s != nil {
+		return s.Score, true
+	}
+	return 0, false
+}
+
+// nodeLabel is the node's display label: its alias, or its AID
+func nodeLabel(n *Node) string {
+	if n.Alias != "" {
+		return n.Alias
+	}
+	return n.AID
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// graphMLKeys declares the GraphML attributes, in output order
+var graphMLKeys = []struct{ id, target, name, typ string }{
+	{"n_label", "node", "label", "string"},
+	{"n_role", "node", "role", "string"},
+	{"n_score", "node", "score", "double"},
+	{"n_joined", "node", "joinedAt", "string"},
+	{"n_credentials", "node", "credentialCount", "int"},
+	{"e_type", "edge", "type", "string"},
+	{"e_credential", "edge", "credentialId", "string"},
+	{"e_bidirectional", "edge", "bidirectional", "boolean"},
+	{"e_created", "edge", "createdAt", "string"},
+	{"e_weight", "edge", "weight", "double"},
+}
+
+func (e *Exporter) writeGraphML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	esc := func(s string) string {
+		var b strings.Builder
+		xml.EscapeText(&b, []byte(s))
+		return b.String()
+	}
+	data := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(bw, "      <data key=\"%s\">%s</data>\n", key, esc(value))
+		}
+	}
+
+	bw.WriteString(xml.Header)
+	bw.WriteString("<graphml xmlns=\"http://graphml.graphdrawing.org/xmlns\">\n")
+	for _, k := range graphMLKeys {
+		fmt.Fprintf(bw, "  <key id=\"%s\" for=\"%s\" attr.name=\"%s\" attr.type=\"%s\"/>\n", k.id, k.target, k.name, k.typ)
+	}
+	fmt.Fprintf(bw, "  <graph id=\"%s\" edgedefault=\"directed\">\n", esc(e.Graph.OrgAID))
+
+	for _, n := range e.sortedNodes() {
+		fmt.Fprintf(bw, "    <node id=\"%s\">\n", esc(n.AID))
+		data("n_label", nodeLabel(n))
+		data("n_role", n.Role)
+		if s, ok := e.score(n.AID); ok {
+			data("n_score", formatFloat(s))
+		}
+		data("n_joined", formatTime(n.JoinedAt))
+		data("n_credentials", strconv.Itoa(n.CredentialCount))
+		bw.WriteString("    </node>\n")
+	}
+	for i, edge := range e.Graph.Edges {
+		fmt.Fprintf(bw, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\">\n", i, esc(edge.From), esc(edge.To))
+		data("e_type", edge.Type)
+		data("e_credential", edge.CredentialID)
+		data("e_bidirectional", strconv.FormatBool(edge.Bidirectional))
+		data("e_created", formatTime(edge.CreatedAt))
+		data("e_weight", formatFloat(edge.Strength()))
+		bw.WriteString("    </edge>\n")
+	}
+
+	bw.WriteString("  </graph>\n</graphml>\n")
+	return bw.Flush()
+}
+
+// dotQuote quotes s as a DOT string
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func (e *Exporter) writeDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString("digraph trust {\n")
+	for _, n := range e.sortedNodes() {
+		attrs := []string{"label=" + dotQuote(nodeLabel(n))}
+		if n.Role != "" {
+			attrs = append(attrs, "role="+dotQuote(n.Role))
+		}
+		if s, ok := e.score(n.AID); ok {
+			attrs = append(attrs, "score="+formatFloat(s))
+		}
+		if joined := formatTime(n.JoinedAt); joined != "" {
+			attrs = append(attrs, "joinedAt="+dotQuote(joined))
+		}
+		attrs = append(attrs, "credentialCount="+strconv.Itoa(n.CredentialCount))
+		fmt.Fprintf(bw, "  %s [%s];\n", dotQuote(n.AID), strings.Join(attrs, ", "))
+	}
+	for _, edge := range e.Graph.Edges {
+		attrs := []string{
+			"type=" + dotQuote(edge.Type),
+			"credentialId=" + dotQuote(edge.CredentialID),
+			"bidirectional=" + strconv.FormatBool(edge.Bidirectional),
+		}
+		if created := formatTime(edge.CreatedAt); created != "" {
+			attrs = append(attrs, "createdAt="+dotQuote(created))
+		}
+		if edge.Confidence != nil {
+			// Graphviz's own weight attribute must be an integer in dot
+			attrs = append(attrs, "confidence="+formatFloat(*edge.Confidence))
+		}
+		fmt.Fprintf(bw, "  %s -> %s [%s];\n", dotQuote(edge.From), dotQuote(edge.To), strings.Join(attrs, ", "))
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// writeCSVNodes writes a node table using Gephi's Id and Label columns
+func (e *Exporter) writeCSVNodes(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Id", "Label", "Role", "Score", "JoinedAt", "CredentialCount"})
+	for _, n := range e.sortedNodes() {
+		score := ""
+		if s, ok := e.score(n.AID); ok {
+			score = formatFloat(s)
+		}
+		cw.Write([]string{n.AID, nodeLabel(n), n.Role, score, formatTime(n.JoinedAt), strconv.Itoa(n.CredentialCount)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeCSVEdges writes an edge table using Gephi's Source, Target, Type and
+// Weight columns; Type is Gephi's edge direction, so the credential type has
+// its own column
+func (e *Exporter) writeCSVEdges(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Source", "Target", "Type", "Weight", "CredentialType", "CredentialId", "Bidirectional", "CreatedAt"})
+	for _, edge := range e.Graph.Edges {
+		cw.Write([]string{edge.From, edge.To, "Directed", formatFloat(edge.Strength()), edge.Type, edge.CredentialID,
+			strconv.FormatBool(edge.Bidirectional), formatTime(edge.CreatedAt)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,114 @@
+package trust
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func exportTestGraph() *Exporter {
+	graph := NewGraph("EORG")
+	graph.AddNode(&Node{AID: "EORG", Alias: "matou", Role: "Organization"})
+	graph.AddNode(&Node{AID: "EUSER1", Alias: `Ana "Kiwi" & co`, Role: "Member", JoinedAt: time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC)})
+	graph.AddEdge(&Edge{From: "EORG", To: "EUSER1", CredentialID: "ESAID1", Type: EdgeTypeMembership})
+	return &Exporter{
+		Graph:  graph,
+		Scores: map[string]*Score{"EUSER1": {AID: "EUSER1", Score: 4.9}},
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	for name, want := range map[string]ExportFormat{"": ExportGraphML, "GraphML": ExportGraphML, "dot": ExportDOT, "csv": ExportCSV} {
+		if got, err := ParseExportFormat(name); err != nil || got != want {
+			t.Errorf("ParseExportFormat(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseExportFormat("gexf"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestExporter_GraphML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := exportTestGraph().Write(&buf, ExportGraphML, ""); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	var doc struct {
+		Graph struct {
+			Nodes []struct {
+				ID   string `xml:"id,attr"`
+				Data []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("export is not valid XML: %v\n%s", err, buf.String())
+	}
+	if len(doc.Graph.Nodes) != 2 || len(doc.Graph.Edges) != 1 {
+		t.Fatalf("expected 2 nodes and 1 edge, got %+v", doc.Graph)
+	}
+	user := doc.Graph.Nodes[1]
+	attrs := map[string]string{}
+	for _, d := range user.Data {
+		attrs[d.Key] = d.Value
+	}
+	if attrs["n_label"] != `Ana "Kiwi" & co` || attrs["n_score"] != "4.9" || attrs["n_joined"] != "2026-01-19T00:00:00Z" {
+		t.Errorf("unexpected node attributes: %v", attrs)
+	}
+	if doc.Graph.Edges[0].Source != "EORG" || doc.Graph.Edges[0].Target != "EUSER1" {
+		t.Errorf("unexpected edge: %+v", doc.Graph.Edges[0])
+	}
+}
+
+func TestExporter_DOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := exportTestGraph().Write(&buf, ExportDOT, ""); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"digraph trust {",
+		`"EUSER1" [label="Ana \"Kiwi\" & co", role="Member", score=4.9, joinedAt="2026-01-19T00:00:00Z", credentialCount=1];`,
+		`"EORG" -> "EUSER1" [type="membership", credentialId="ESAID1", bidirectional=false];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in DOT output:\n%s", want, out)
+		}
+	}
+}
+
+func TestExporter_CSV(t *testing.T) {
+	exporter := exportTestGraph()
+
+	var buf bytes.Buffer
+	if err := exporter.Write(&buf, ExportCSV, CSVNodes); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(rows) != 3 {
+		t.Fatalf("expected header and 2 node rows, got %v (%v)", rows, err)
+	}
+	if rows[0][0] != "Id" || rows[2][1] != `Ana "Kiwi" & co` || rows[1][3] != "" {
+		t.Errorf("unexpected node rows: %v", rows)
+	}
+
+	buf.Reset()
+	if err := exporter.Write(&buf, ExportCSV, ""); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	rows, _ = csv.NewReader(&buf).ReadAll()
+	if len(rows) != 2 || rows[1][2] != "Directed" || rows[1][3] != "1" || rows[1][4] != EdgeTypeMembership || rows[1][5] != "ESAID1" {
+		t.Errorf("unexpected edge rows: %v", rows)
+	}
+}
@@ -0,0 +1,145 @@
+package trust
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+// RoleFederatedOrganization is the node role for trusted peer organizations
+const RoleFederatedOrganization = "Federated Organization"
+
+// FederatedOrg is a trusted peer organization whose credentials are
+// recognized in this community's trust graph
+type FederatedOrg struct {
+	AID     string   `json:"aid" yaml:"aid"`
+	Name    string   `json:"name,omitempty" yaml:"name,omitempty"`
+	Schemas []string `json:"schemas" yaml:"schemas"` // Credential schemas recognized from this org
+}
+
+// AllowsSchema checks if credentials of this schema are recognized
+func (f *FederatedOrg) AllowsSchema(schema string) bool {
+	for _, s := range f.Schemas {
+		if s == schema {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateFederation checks each peer and rejects duplicates or the org itself
+func ValidateFederation(orgs []FederatedOrg, orgAID string) error {
+	seen := make(map[string]bool, len(orgs))
+	for i, f := range orgs {
+		if f.AID == "" {
+			return fmt.Errorf("federation[%d]: aid is required", i)
+		}
+		if f.AID == orgAID {
+			return fmt.Errorf("federation[%d]: cannot federate with this org's own AID", i)
+		}
+		if seen[f.AID] {
+			return fmt.Errorf("federation[%d]: duplicate org %s", i, f.AID)
+		}
+		seen[f.AID] = true
+		if len(f.Schemas) == 0 {
+			return fmt.Errorf("federation[%d]: at least one schema is required", i)
+		}
+	}
+	return nil
+}
+
+// KELEvent is a cached Key Event Log event
+type KELEvent struct {
+	Type     string `json:"type"`
+	Sequence int    `json:"sequence"`
+	Digest   string `json:"digest"`
+}
+
+// VerifyKEL checks that events form a KEL for aid: it must start with an
+// inception event whose digest is the (self-addressing) AID, and sequence
+// numbers must be contiguous. Event signatures are verified by signify
+// before the KEL is synced.
+func VerifyKEL(aid string, events []KELEvent) error {
+	if len(events) == 0 {
+		return fmt.Errorf("no KEL synced for %s", aid)
+	}
+	sorted := make([]KELEvent, len(events))
+	copy(sorted, events)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Sequence < sorted[j].Sequence })
+
+	icp := sorted[0]
+	if icp.Sequence != 0 || icp.Type != "icp" {
+		return fmt.Errorf("KEL for %s does not start with an inception event", aid)
+	}
+	if icp.Digest != aid {
+		return fmt.Errorf("KEL inception digest does not match %s", aid)
+	}
+	for i, e := range sorted {
+		if e.Sequence != i {
+			return fmt.Errorf("KEL for %s is missing event %d", aid, i)
+		}
+	}
+	return nil
+}
+
+// FederationStatus reports whether a peer org's KEL has been verified
+type FederationStatus struct {
+	FederatedOrg
+	Verified bool   `json:"verified"`
+	Error    string `json:"error,omitempty"`
+}
+
+// CheckFederation verifies each peer org against its KEL in the local cache
+func CheckFederation(ctx context.Context, store *anystore.LocalStore, orgs []FederatedOrg) []*FederationStatus {
+	statuses := make([]*FederationStatus, 0, len(orgs))
+	for _, f := range orgs {
+		status := &FederationStatus{FederatedOrg: f}
+		events, err := loadKEL(ctx, store, f.AID)
+		if err == nil {
+			err = VerifyKEL(f.AID, events)
+		}
+		if err != nil {
+			status.Error = err.Error()
+		} else {
+			status.Verified = true
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// loadKEL reads an AID's cached KEL events
+func loadKEL(ctx context.Context, store *anystore.LocalStore, aid string) ([]KELEvent, error) {
+	if store == nil {
+		return nil, fmt.Errorf("no local store")
+	}
+	coll, err := store.KELCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := anystore.MustParseJSON(fmt.Sprintf(`{"userAid": %q}`, aid))
+	iter, err := coll.Find(query).Iter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var events []KELEvent
+	for iter.Next() {
+		doc, err := iter.Doc()
+		if err != nil {
+			continue
+		}
+
+		var e KELEvent
+		if err := json.Unmarshal([]byte(doc.Value().String()), &e); err != nil {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
@@ -0,0 +1,152 @@
+package trust
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+// storeTestKEL caches KEL events for an AID the way the sync API does
+func storeTestKEL(t *testing.T, store *anystore.LocalStore, aid string, events []KELEvent) {
+	t.Helper()
+	ctx := context.Background()
+	coll, err := store.KELCache(ctx)
+	if err != nil {
+		t.Fatalf("KELCache failed: %v", err)
+	}
+	for _, e := range events {
+		record, _ := json.Marshal(map[string]interface{}{
+			"id":       fmt.Sprintf("%s-%d", aid, e.Sequence),
+			"userAid":  aid,
+			"type":     e.Type,
+			"sequence": e.Sequence,
+			"digest":   e.Digest,
+		})
+		if err := coll.UpsertOne(ctx, anystore.MustParseJSON(string(record))); err != nil {
+			t.Fatalf("UpsertOne failed: %v", err)
+		}
+	}
+}
+
+func TestValidateFederation(t *testing.T) {
+	valid := []FederatedOrg{
+		{AID: "EPEER1", Name: "Peer One", Schemas: []string{"EMatouMembershipSchemaV1"}},
+		{AID: "EPEER2", Schemas: []string{EndorsementSchema}},
+	}
+	if err := ValidateFederation(valid, "EORG123"); err != nil {
+		t.Errorf("expected valid federation, got %v", err)
+	}
+
+	cases := map[string][]FederatedOrg{
+		"missing aid": {{Schemas: []string{"EMatouMembershipSchemaV1"}}},
+		"own aid":     {{AID: "EORG123", Schemas: []string{"EMatouMembershipSchemaV1"}}},
+		"no schemas":  {{AID: "EPEER1"}},
+		"duplicate": {
+			{AID: "EPEER1", Schemas: []string{"EMatouMembershipSchemaV1"}},
+			{AID: "EPEER1", Schemas: []string{EndorsementSchema}},
+		},
+	}
+	for name, orgs := range cases {
+		if err := ValidateFederation(orgs, "EORG123"); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestVerifyKEL(t *testing.T) {
+	good := []KELEvent{
+		{Type: "rot", Sequence: 1, Digest: "EROT1"},
+		{Type: "icp", Sequence: 0, Digest: "EPEER1"},
+		{Type: "ixn", Sequence: 2, Digest: "EIXN2"},
+	}
+	if err := VerifyKEL("EPEER1", good); err != nil {
+		t.Errorf("expected valid KEL, got %v", err)
+	}
+
+	cases := map[string][]KELEvent{
+		"empty":          nil,
+		"no inception":   {{Type: "rot", Sequence: 0, Digest: "EPEER1"}},
+		"wrong digest":   {{Type: "icp", Sequence: 0, Digest: "EOTHER"}},
+		"missing event":  {{Type: "icp", Sequence: 0, Digest: "EPEER1"}, {Type: "rot", Sequence: 2, Digest: "EROT2"}},
+		"starts after 0": {{Type: "icp", Sequence: 1, Digest: "EPEER1"}},
+	}
+	for name, events := range cases {
+		if err := VerifyKEL("EPEER1", events); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestBuilder_Build_FederatedCredentials(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// EPEER1 has a verified KEL; EPEER2 is configured but has none
+	storeTestKEL(t, store, "EPEER1", []KELEvent{{Type: "icp", Sequence: 0, Digest: "EPEER1"}})
+
+	creds := []*anystore.CachedCredential{
+		{ID: "ESAID001", IssuerAID: "EORG123", SubjectAID: "EUSER1", SchemaID: "EMatouMembershipSchemaV1",
+			Data: map[string]interface{}{"role": "Member"}},
+		{ID: "ESAID002", IssuerAID: "EPEER1", SubjectAID: "EUSER1", SchemaID: "EMatouMembershipSchemaV1",
+			Data: map[string]interface{}{"role": "Steward"}},
+		{ID: "ESAID003", IssuerAID: "EPEER1", SubjectAID: "EVISITOR", SchemaID: "EMatouMembershipSchemaV1",
+			Data: map[string]interface{}{"role": "Member"}},
+		// Schema not recognized from this peer
+		{ID: "ESAID004", IssuerAID: "EPEER1", SubjectAID: "EUSER1", SchemaID: "EOperationsStewardSchemaV1"},
+		// Peer without a verified KEL
+		{ID: "ESAID005", IssuerAID: "EPEER2", SubjectAID: "EUSER1", SchemaID: "EMatouMembershipSchemaV1"},
+	}
+	for _, c := range creds {
+		c.CachedAt = time.Now()
+		if err := store.StoreCredential(ctx, c); err != nil {
+			t.Fatalf("StoreCredential failed: %v", err)
+		}
+	}
+
+	graph, err := NewBuilder(store, "EORG123").WithFederation([]FederatedOrg{
+		{AID: "EPEER1", Name: "Peer One", Schemas: []string{"EMatouMembershipSchemaV1"}},
+		{AID: "EPEER2", Schemas: []string{"EMatouMembershipSchemaV1"}},
+	}).Build(ctx)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if graph.EdgeCount() != 3 {
+		t.Fatalf("expected 3 edges, got %d", graph.EdgeCount())
+	}
+	federated := 0
+	for _, e := range graph.Edges {
+		if e.Type == EdgeTypeFederated {
+			federated++
+			if e.From != "EPEER1" {
+				t.Errorf("unexpected federated issuer %s", e.From)
+			}
+		}
+	}
+	if federated != 2 {
+		t.Errorf("expected 2 federated edges, got %d", federated)
+	}
+
+	peer := graph.GetNode("EPEER1")
+	if peer == nil || peer.Role != RoleFederatedOrganization || peer.Alias != "Peer One" {
+		t.Errorf("unexpected peer node: %+v", peer)
+	}
+	if graph.GetNode("EPEER2") != nil {
+		t.Error("unverified peer should not be in the graph")
+	}
+
+	// Peer credentials don't confer a local role
+	if role := graph.GetNode("EUSER1").Role; role != "Member" {
+		t.Errorf("expected EUSER1 to keep role Member, got %s", role)
+	}
+	visitor := graph.GetNode("EVISITOR")
+	if visitor == nil || visitor.IsMember() {
+		t.Errorf("federated-only subject should not be a member: %+v", visitor)
+	}
+}
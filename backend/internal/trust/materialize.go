@@ -0,0 +1,161 @@
+package trust
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+// MaterializeResult reports how Materialize changed the stored graph
+type MaterializeResult struct {
+	NodesWritten int `json:"nodesWritten"`
+	NodesDeleted int `json:"nodesDeleted"`
+	EdgesWritten int `json:"edgesWritten"`
+	EdgesDeleted int `json:"edgesDeleted"`
+}
+
+// Changed reports whether the stored graph changed
+func (r *MaterializeResult) Changed() bool {
+	return r.NodesWritten+r.NodesDeleted+r.EdgesWritten+r.EdgesDeleted > 0
+}
+
+// Materialize stores a built graph and its scores in the store's trust node
+// and edge collections. Only nodes and edges that differ from what is
+// stored are written, and ones no longer in the graph are removed, so
+// materializing after each credential write touches little. Node
+// contributions aren't stored, as they come from outside the credentials.
+func Materialize(ctx context.Context, store *anystore.LocalStore, graph *Graph, scores map[string]*Score) (*MaterializeResult, error) {
+	storedNodes, err := store.ListGraphNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	storedEdges, err := store.ListGraphEdges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &MaterializeResult{}
+
+	// Nodes
+	oldNodes := make(map[string][]byte, len(storedNodes))
+	for _, n := range storedNodes {
+		oldNodes[n.AID] = encodeRecord(n)
+	}
+	var nodes []*anystore.GraphNodeRecord
+	for aid, node := range graph.Nodes {
+		record := &anystore.GraphNodeRecord{
+			AID:             aid,
+			Alias:           node.Alias,
+			Role:            node.Role,
+			JoinedAt:        node.JoinedAt,
+			CredentialCount: node.CredentialCount,
+			Attributes:      node.Attributes,
+		}
+		if s := scores[aid]; s != nil {
+			record.Score = s.Score
+			record.Depth = s.GraphDepth
+		}
+		if old, ok := oldNodes[aid]; !ok || !bytes.Equal(old, encodeRecord(record)) {
+			nodes = append(nodes, record)
+		}
+		delete(oldNodes, aid)
+	}
+	if err := store.StoreGraphNodes(ctx, nodes); err != nil {
+		return nil, fmt.Errorf("failed to materialize trust nodes: %w", err)
+	}
+	if err := store.DeleteGraphNodes(ctx, sortedIDs(oldNodes)); err != nil {
+		return nil, fmt.Errorf("failed to remove trust nodes: %w", err)
+	}
+	result.NodesWritten, result.NodesDeleted = len(nodes), len(oldNodes)
+
+	// Edges
+	oldEdges := make(map[string][]byte, len(storedEdges))
+	for _, e := range storedEdges {
+		oldEdges[e.CredentialID] = encodeRecord(e)
+	}
+	var edges []*anystore.GraphEdgeRecord
+	for _, edge := range graph.Edges {
+		record := &anystore.GraphEdgeRecord{
+			CredentialID:  edge.CredentialID,
+			From:          edge.From,
+			To:            edge.To,
+			Type:          edge.Type,
+			Bidirectional: edge.Bidirectional,
+			CreatedAt:     edge.CreatedAt,
+			Confidence:    edge.Confidence,
+		}
+		if old, ok := oldEdges[edge.CredentialID]; !ok || !bytes.Equal(old, encodeRecord(record)) {
+			edges = append(edges, record)
+		}
+		delete(oldEdges, edge.CredentialID)
+	}
+	if err := store.StoreGraphEdges(ctx, edges); err != nil {
+		return nil, fmt.Errorf("failed to materialize trust edges: %w", err)
+	}
+	if err := store.DeleteGraphEdges(ctx, sortedIDs(oldEdges)); err != nil {
+		return nil, fmt.Errorf("failed to remove trust edges: %w", err)
+	}
+	result.EdgesWritten, result.EdgesDeleted = len(edges), len(oldEdges)
+
+	return result, nil
+}
+
+// LoadMaterialized reads the graph last stored by Materialize. Edges are in
+// credential SAID order.
+func LoadMaterialized(ctx context.Context, store *anystore.LocalStore, orgAID string) (*Graph, error) {
+	nodes, err := store.ListGraphNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := store.ListGraphEdges(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := NewGraph(orgAID)
+	for _, n := range nodes {
+		graph.Nodes[n.AID] = &Node{
+			AID:             n.AID,
+			Alias:           n.Alias,
+			Role:            n.Role,
+			JoinedAt:        n.JoinedAt,
+			CredentialCount: n.CredentialCount,
+			Attributes:      n.Attributes,
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].CredentialID < edges[j].CredentialID })
+	for _, e := range edges {
+		graph.AddEdge(&Edge{
+			From:          e.From,
+			To:            e.To,
+			CredentialID:  e.CredentialID,
+			Type:          e.Type,
+			Bidirectional: e.Bidirectional,
+			CreatedAt:     e.CreatedAt,
+			Confidence:    e.Confidence,
+		})
+	}
+	graph.Updated = time.Now().UTC()
+	return graph, nil
+}
+
+// encodeRecord encodes a record for comparison; records round-trip
+// through the store as JSON, so equal encodings mean nothing changed
+func encodeRecord(record interface{}) []byte {
+	data, _ := json.Marshal(record)
+	return data
+}
+
+// sortedIDs returns a map's keys in order
+func sortedIDs(m map[string][]byte) []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,92 @@
+package trust
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+func TestMaterialize(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	joined := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, cred := range []*anystore.CachedCredential{
+		{ID: "ESAID001", IssuerAID: "EORG123", SubjectAID: "EUSER1", SchemaID: "EMatouMembershipSchemaV1",
+			Data: map[string]interface{}{"role": "Member", "displayName": "alice", "joinedAt": joined.Format(time.RFC3339)}},
+		{ID: "ESAID002", IssuerAID: "EORG123", SubjectAID: "EUSER2", SchemaID: "EMatouMembershipSchemaV1",
+			Data: map[string]interface{}{"role": "Member", "attributes": map[string]interface{}{"region": "north"}}},
+		{ID: "ESAID003", IssuerAID: "EUSER2", SubjectAID: "EUSER1", SchemaID: EndorsementSchema,
+			Data: map[string]interface{}{"confidence": 0.5}},
+	} {
+		store.StoreCredential(ctx, cred)
+	}
+
+	materialize := func() *MaterializeResult {
+		t.Helper()
+		graph, err := NewBuilder(store, "EORG123").Build(ctx)
+		if err != nil {
+			t.Fatalf("Build failed: %v", err)
+		}
+		result, err := Materialize(ctx, store, graph, NewDefaultCalculator().CalculateAllScores(graph))
+		if err != nil {
+			t.Fatalf("Materialize failed: %v", err)
+		}
+		return result
+	}
+
+	result := materialize()
+	if result.NodesWritten != 3 || result.EdgesWritten != 3 {
+		t.Fatalf("expected every node and edge written at first, got %+v", result)
+	}
+
+	loaded, err := LoadMaterialized(ctx, store, "EORG123")
+	if err != nil {
+		t.Fatalf("LoadMaterialized failed: %v", err)
+	}
+	if loaded.NodeCount() != 3 || loaded.EdgeCount() != 3 {
+		t.Fatalf("expected 3 nodes and 3 edges, got %d and %d", loaded.NodeCount(), loaded.EdgeCount())
+	}
+	alice := loaded.GetNode("EUSER1")
+	if alice.Alias != "alice" || !alice.JoinedAt.Equal(joined) || alice.CredentialCount != 2 {
+		t.Errorf("expected alice's node as built, got %+v", alice)
+	}
+	if loaded.GetNode("EUSER2").Attributes["region"] != "north" {
+		t.Errorf("expected attributes to be kept, got %+v", loaded.GetNode("EUSER2"))
+	}
+	endorsements := loaded.GetEdgesTo("EUSER1")
+	if len(endorsements) != 2 || endorsements[1].Confidence == nil || *endorsements[1].Confidence != 0.5 {
+		t.Errorf("expected the endorsement with its confidence, got %+v", endorsements)
+	}
+
+	// Nothing changed, so nothing is written
+	if result := materialize(); result.Changed() {
+		t.Errorf("expected no writes for an unchanged graph, got %+v", result)
+	}
+
+	// A new credential writes its edge and the nodes it touches
+	store.StoreCredential(ctx, &anystore.CachedCredential{
+		ID: "ESAID004", IssuerAID: "EUSER1", SubjectAID: "EUSER2", SchemaID: EndorsementSchema,
+	})
+	result = materialize()
+	if result.EdgesWritten != 2 || result.EdgesDeleted != 0 {
+		t.Errorf("expected the new endorsement and the now bidirectional one written, got %+v", result)
+	}
+	if result.NodesWritten < 2 || result.NodesDeleted != 0 {
+		t.Errorf("expected both members rewritten, got %+v", result)
+	}
+
+	// Removed credentials take their edges and members with them
+	store.ClearCache(ctx, anystore.CollectionCredentialsCache)
+	result = materialize()
+	if result.EdgesDeleted != 4 || result.NodesDeleted != 2 {
+		t.Errorf("expected every edge and member removed, got %+v", result)
+	}
+	top, _ := store.TopGraphNodes(ctx, 10)
+	if len(top) != 1 || top[0].AID != "EORG123" {
+		t.Errorf("expected only the org left, got %+v", top)
+	}
+}
@@ -0,0 +1,136 @@
+package trust
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// pageRankDamping is the probability a walk follows a credential edge
+	// rather than jumping back to the org
+	pageRankDamping = 0.85
+	// pageRankTolerance stops iterating once ranks change less than this
+	pageRankTolerance = 1e-9
+	// pageRankMaxIterations bounds iteration on graphs that converge slowly
+	pageRankMaxIterations = 100
+)
+
+// pageRankEdgeWeight returns how much trust an edge carries in PageRank. Edge
+// weights reuse the linear weights so orgs tune both algorithms the same
+// way: participation and federated credentials carry their own weights,
+// mutual relationships carry the bidirectional weight on top, and
+// endorsements are scaled by the endorser's confidence.
+func pageRankEdgeWeight(weights ScoreWeights, edge *Edge) float64 {
+	switch edge.Type {
+	case EdgeTypeParticipation:
+		return weights.ParticipationCredential
+	case EdgeTypeFederated:
+		return weights.FederatedCredential
+	}
+	w := weights.IncomingCredential
+	if edge.Bidirectional {
+		w += weights.BidirectionalRelation
+	}
+	return w * edge.Strength()
+}
+
+// pageRankAlgorithm is AlgorithmPageRank. An AID's score is its rank
+// scaled by the node count, so 1.0 is an average share of the community's
+// trust.
+type pageRankAlgorithm struct{}
+
+func (pageRankAlgorithm) Name() Algorithm { return AlgorithmPageRank }
+
+func (pageRankAlgorithm) Scorer(graph *Graph, weights ScoreWeights) Scorer {
+	ranks := pageRank(graph, weights)
+	n := float64(graph.NodeCount())
+	return func(s *Score) float64 {
+		return ranks[s.AID] * n
+	}
+}
+
+// pageRank computes personalized PageRank for every node. Random walks
+// restart at the org, and rank held by AIDs that issued no credentials
+// returns to the org too, so ranks sum to 1. Without an org node in the
+// graph, walks restart uniformly across all nodes.
+func pageRank(graph *Graph, weights ScoreWeights) map[string]float64 {
+	n := len(graph.Nodes)
+	ranks := make(map[string]float64, n)
+	if n == 0 {
+		return ranks
+	}
+
+	// Sorted AIDs keep the floating point sums deterministic
+	aids := make([]string, 0, n)
+	for aid := range graph.Nodes {
+		aids = append(aids, aid)
+	}
+	sort.Strings(aids)
+
+	restart := make(map[string]float64, n)
+	if graph.GetNode(graph.OrgAID) != nil {
+		restart[graph.OrgAID] = 1
+	} else {
+		for _, aid := range aids {
+			restart[aid] = 1 / float64(n)
+		}
+	}
+
+	// Outgoing edge weights per issuer, ignoring self-claims and edges to
+	// AIDs outside the graph
+	type link struct {
+		to     string
+		weight float64
+	}
+	links := make(map[string][]link, n)
+	totals := make(map[string]float64, n)
+	for _, edge := range graph.Edges {
+		if edge.From == edge.To || graph.GetNode(edge.From) == nil || graph.GetNode(edge.To) == nil {
+			continue
+		}
+		w := pageRankEdgeWeight(weights, edge)
+		if w <= 0 {
+			continue
+		}
+		links[edge.From] = append(links[edge.From], link{edge.To, w})
+		totals[edge.From] += w
+	}
+
+	for aid, r := range restart {
+		ranks[aid] = r
+	}
+	for i := 0; i < pageRankMaxIterations; i++ {
+		next := make(map[string]float64, n)
+		dangling := 0.0
+		for _, aid := range aids {
+			r := ranks[aid]
+			if totals[aid] == 0 {
+				dangling += r
+				continue
+			}
+			for _, l := range links[aid] {
+				next[l.to] += pageRankDamping * r * l.weight / totals[aid]
+			}
+		}
+		jump := (1 - pageRankDamping) + pageRankDamping*dangling
+		for aid, r := range restart {
+			next[aid] += jump * r
+		}
+
+		delta := 0.0
+		for _, aid := range aids {
+			delta += math.Abs(next[aid] - ranks[aid])
+		}
+		ranks = next
+		if delta < pageRankTolerance {
+			break
+		}
+	}
+
+	for _, aid := range aids {
+		if _, ok := ranks[aid]; !ok {
+			ranks[aid] = 0
+		}
+	}
+	return ranks
+}
@@ -0,0 +1,101 @@
+package trust
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseAlgorithm(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Algorithm
+		wantErr bool
+	}{
+		{"", AlgorithmLinear, false},
+		{"linear", AlgorithmLinear, false},
+		{"pagerank", AlgorithmPageRank, false},
+		{"eigen", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAlgorithm(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAlgorithm(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseAlgorithm(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// endorsementChainGraph has X endorsed by a member the org trusts, and Y
+// endorsed by an AID the org has no path to
+func endorsementChainGraph() *Graph {
+	graph := NewGraph("EORG")
+	for _, aid := range []string{"EORG", "EA", "EX", "EZ", "EY"} {
+		graph.AddNode(&Node{AID: aid, Role: "Member"})
+	}
+	graph.AddEdge(&Edge{From: "EORG", To: "EA", CredentialID: "ESAID1", Type: EdgeTypeMembership})
+	graph.AddEdge(&Edge{From: "EA", To: "EX", CredentialID: "ESAID2", Type: EdgeTypeEndorsement})
+	graph.AddEdge(&Edge{From: "EZ", To: "EY", CredentialID: "ESAID3", Type: EdgeTypeEndorsement})
+	return graph
+}
+
+func TestCalculator_PageRank_TransitiveTrust(t *testing.T) {
+	graph := endorsementChainGraph()
+
+	linear := NewDefaultCalculator()
+	if linear.CalculateScore("EX", graph).Score > linear.CalculateScore("EY", graph).Score {
+		t.Fatal("expected the linear algorithm not to credit the chain from the org")
+	}
+
+	calc := NewDefaultCalculator().WithAlgorithm(AlgorithmPageRank)
+	x := calc.CalculateScore("EX", graph)
+	y := calc.CalculateScore("EY", graph)
+	if x.Score <= y.Score {
+		t.Errorf("expected X (endorsed via the org) to outrank Y, got %f <= %f", x.Score, y.Score)
+	}
+	if y.Score != 0 {
+		t.Errorf("expected no trust for Y, unreachable from the org, got %f", y.Score)
+	}
+	if a := calc.CalculateScore("EA", graph); a.Score <= x.Score {
+		t.Errorf("expected A to hold more trust than the member it endorsed, got %f <= %f", a.Score, x.Score)
+	}
+
+	// Counts are still reported alongside the rank
+	if x.IncomingCredentials != 1 || x.GraphDepth != 2 {
+		t.Errorf("unexpected counts: %+v", x)
+	}
+}
+
+func TestCalculator_PageRank_ScoresAverageOne(t *testing.T) {
+	graph := endorsementChainGraph()
+	graph.AddEdge(&Edge{From: "EX", To: "EA", CredentialID: "ESAID4", Type: EdgeTypeEndorsement, Bidirectional: true})
+
+	scores := NewDefaultCalculator().WithAlgorithm(AlgorithmPageRank).CalculateAllScores(graph)
+	total := 0.0
+	for _, s := range scores {
+		total += s.Score
+	}
+	if math.Abs(total/float64(len(scores))-1) > 1e-6 {
+		t.Errorf("expected scores to average 1, got %f", total/float64(len(scores)))
+	}
+}
+
+func TestCalculator_PageRank_WithoutOrgNode(t *testing.T) {
+	graph := NewGraph("EORG")
+	graph.AddNode(&Node{AID: "EA", Role: "Member"})
+	graph.AddNode(&Node{AID: "EB", Role: "Member"})
+	graph.AddEdge(&Edge{From: "EA", To: "EB", CredentialID: "ESAID1", Type: EdgeTypeEndorsement})
+
+	calc := NewDefaultCalculator().WithAlgorithm(AlgorithmPageRank)
+	a := calc.CalculateScore("EA", graph)
+	b := calc.CalculateScore("EB", graph)
+	if a.Score <= 0 || b.Score <= a.Score {
+		t.Errorf("expected B to outrank A with uniform restarts, got A=%f B=%f", a.Score, b.Score)
+	}
+
+	summary := calc.CalculateSummary(graph)
+	if summary.Algorithm != AlgorithmPageRank {
+		t.Errorf("expected pagerank in summary, got %q", summary.Algorithm)
+	}
+}
@@ -0,0 +1,127 @@
+package trust
+
+import (
+	"sort"
+)
+
+// Path is a shortest chain of credentials connecting two AIDs
+type Path struct {
+	// Nodes are the AIDs along the path, starting with the first AID
+	Nodes []*Node `json:"nodes"`
+	// Hops connect consecutive nodes; there is one fewer than nodes
+	Hops []*PathHop `json:"hops"`
+}
+
+// PathHop is one step of a path
+type PathHop struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Credentials are every credential between the two AIDs, issued in
+	// either direction
+	Credentials []*Edge `json:"credentials"`
+}
+
+// ShortestPaths returns up to limit shortest paths from one AID to
+// another, following credentials in either direction. As in Distances,
+// paths through the organization root are not followed, since every member
+// is one hop from it. It returns nil when the AIDs aren't connected.
+func (g *Graph) ShortestPaths(from, to string, limit int) []*Path {
+	if limit <= 0 || g.Nodes[from] == nil || g.Nodes[to] == nil {
+		return nil
+	}
+
+	neighbors := make(map[string][]string)
+	between := make(map[[2]string][]*Edge)
+	for _, e := range g.Edges {
+		key := pairKey(e.From, e.To)
+		if len(between[key]) == 0 {
+			neighbors[e.From] = append(neighbors[e.From], e.To)
+			neighbors[e.To] = append(neighbors[e.To], e.From)
+		}
+		between[key] = append(between[key], e)
+	}
+	for _, next := range neighbors {
+		sort.Strings(next)
+	}
+
+	// Breadth-first search recording every predecessor on a shortest path
+	dist := map[string]int{from: 0}
+	parents := make(map[string][]string)
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			break
+		}
+		if current == g.OrgAID && current != from {
+			continue
+		}
+		for _, next := range neighbors[current] {
+			d, seen := dist[next]
+			if !seen {
+				dist[next] = dist[current] + 1
+				queue = append(queue, next)
+			} else if d != dist[current]+1 {
+				continue
+			}
+			parents[next] = append(parents[next], current)
+		}
+	}
+	if _, ok := dist[to]; !ok {
+		return nil
+	}
+
+	// Walk the predecessors back from the target, in AID order so the
+	// same graph always gives the same paths
+	for _, p := range parents {
+		sort.Strings(p)
+	}
+	var paths []*Path
+	route := []string{to}
+	var walk func(aid string)
+	walk = func(aid string) {
+		if len(paths) == limit {
+			return
+		}
+		if aid == from {
+			paths = append(paths, g.newPath(route, between))
+			return
+		}
+		for _, parent := range parents[aid] {
+			route = append(route, parent)
+			walk(parent)
+			route = route[:len(route)-1]
+		}
+	}
+	walk(to)
+	return paths
+}
+
+// newPath builds a path from AIDs listed target first
+func (g *Graph) newPath(reversed []string, between map[[2]string][]*Edge) *Path {
+	path := &Path{}
+	for i := len(reversed) - 1; i >= 0; i-- {
+		aid := reversed[i]
+		node := g.Nodes[aid]
+		if node == nil {
+			node = &Node{AID: aid}
+		}
+		path.Nodes = append(path.Nodes, node)
+		if i > 0 {
+			next := reversed[i-1]
+			creds := append([]*Edge{}, between[pairKey(aid, next)]...)
+			sort.Slice(creds, func(a, b int) bool { return creds[a].CredentialID < creds[b].CredentialID })
+			path.Hops = append(path.Hops, &PathHop{From: aid, To: next, Credentials: creds})
+		}
+	}
+	return path
+}
+
+// pairKey identifies the unordered pair of two AIDs
+func pairKey(a, b string) [2]string {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
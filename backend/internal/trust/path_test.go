@@ -0,0 +1,84 @@
+package trust
+
+import (
+	"testing"
+)
+
+// pathTestGraph links alice to dave through bob and through carol, with
+// everyone also holding a membership from the org
+func pathTestGraph() *Graph {
+	graph := NewGraph("EORG")
+	graph.AddNode(&Node{AID: "EORG", Role: "Organization"})
+	for _, aid := range []string{"EALICE", "EBOB", "ECAROL", "EDAVE", "EERIN"} {
+		graph.AddNode(&Node{AID: aid, Role: "Member"})
+		graph.AddEdge(&Edge{From: "EORG", To: aid, CredentialID: "M-" + aid, Type: EdgeTypeMembership})
+	}
+	graph.AddEdge(&Edge{From: "EALICE", To: "EBOB", CredentialID: "I1", Type: EdgeTypeInvitation})
+	graph.AddEdge(&Edge{From: "EBOB", To: "EALICE", CredentialID: "E1", Type: EdgeTypeEndorsement})
+	graph.AddEdge(&Edge{From: "EALICE", To: "ECAROL", CredentialID: "I2", Type: EdgeTypeInvitation})
+	graph.AddEdge(&Edge{From: "EDAVE", To: "EBOB", CredentialID: "E2", Type: EdgeTypeEndorsement})
+	graph.AddEdge(&Edge{From: "ECAROL", To: "EDAVE", CredentialID: "I3", Type: EdgeTypeInvitation})
+	return graph
+}
+
+func pathAIDs(p *Path) string {
+	var aids string
+	for i, n := range p.Nodes {
+		if i > 0 {
+			aids += ">"
+		}
+		aids += n.AID
+	}
+	return aids
+}
+
+func TestGraph_ShortestPaths(t *testing.T) {
+	graph := pathTestGraph()
+
+	paths := graph.ShortestPaths("EALICE", "EDAVE", 5)
+	if len(paths) != 2 {
+		t.Fatalf("expected two shortest paths, got %d", len(paths))
+	}
+	if got := pathAIDs(paths[0]); got != "EALICE>EBOB>EDAVE" {
+		t.Errorf("expected the path through bob first, got %s", got)
+	}
+	if got := pathAIDs(paths[1]); got != "EALICE>ECAROL>EDAVE" {
+		t.Errorf("expected the path through carol second, got %s", got)
+	}
+
+	hop := paths[0].Hops[0]
+	if hop.From != "EALICE" || hop.To != "EBOB" || len(hop.Credentials) != 2 ||
+		hop.Credentials[0].CredentialID != "E1" || hop.Credentials[1].CredentialID != "I1" {
+		t.Errorf("expected both credentials between alice and bob, got %+v", hop)
+	}
+	if hop := paths[0].Hops[1]; hop.Credentials[0].From != "EDAVE" {
+		t.Errorf("expected the credential's own direction to be kept, got %+v", hop.Credentials[0])
+	}
+
+	if paths := graph.ShortestPaths("EALICE", "EDAVE", 1); len(paths) != 1 {
+		t.Errorf("expected the limit to apply, got %d paths", len(paths))
+	}
+}
+
+func TestGraph_ShortestPaths_NotThroughOrg(t *testing.T) {
+	graph := pathTestGraph()
+
+	// Erin only holds a membership, so she is only connected via the org
+	if paths := graph.ShortestPaths("EALICE", "EERIN", 3); paths != nil {
+		t.Errorf("expected no path avoiding the org, got %s", pathAIDs(paths[0]))
+	}
+
+	paths := graph.ShortestPaths("EORG", "EERIN", 3)
+	if len(paths) != 1 || pathAIDs(paths[0]) != "EORG>EERIN" {
+		t.Errorf("expected the org's direct credential, got %v", paths)
+	}
+
+	paths = graph.ShortestPaths("EALICE", "EALICE", 3)
+	if len(paths) != 1 || len(paths[0].Nodes) != 1 || len(paths[0].Hops) != 0 {
+		t.Errorf("expected a single-node path to self, got %v", paths)
+	}
+
+	if paths := graph.ShortestPaths("EALICE", "EUNKNOWN", 3); paths != nil {
+		t.Errorf("expected no path to an unknown AID, got %v", paths)
+	}
+}
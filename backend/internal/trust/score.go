@@ -1,33 +1,115 @@
 package trust
 
-// ScoreWeights defines the weights for trust score calculation
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ScoreWeights defines the weights for trust score calculation. Orgs can
+// override the defaults via trustWeights in org-config.yaml.
 type ScoreWeights struct {
-	IncomingCredential    float64 // Weight per incoming credential
-	UniqueIssuer          float64 // Weight per unique issuer
-	BidirectionalRelation float64 // Weight per bidirectional relationship
-	DepthPenalty          float64 // Penalty per level of depth from org
-	OrgIssuedBonus        float64 // Bonus for credentials issued by org
+	IncomingCredential      float64 `json:"incomingCredential" yaml:"incomingCredential"`           // Weight per incoming credential
+	UniqueIssuer            float64 `json:"uniqueIssuer" yaml:"uniqueIssuer"`                       // Weight per unique issuer
+	BidirectionalRelation   float64 `json:"bidirectionalRelation" yaml:"bidirectionalRelation"`     // Weight per bidirectional relationship
+	DepthPenalty            float64 `json:"depthPenalty" yaml:"depthPenalty"`                       // Penalty per level of depth from org
+	OrgIssuedBonus          float64 `json:"orgIssuedBonus" yaml:"orgIssuedBonus"`                   // Bonus for credentials issued by org
+	ParticipationCredential float64 `json:"participationCredential" yaml:"participationCredential"` // Weight per attendance/participation credential
+	Contribution            float64 `json:"contribution" yaml:"contribution"`                       // Weight per recorded contribution (off by default)
+	FederatedCredential     float64 `json:"federatedCredential" yaml:"federatedCredential"`         // Weight per credential from a verified peer org
 }
 
 // DefaultWeights returns the default score weights
 func DefaultWeights() ScoreWeights {
 	return ScoreWeights{
-		IncomingCredential:    1.0,
-		UniqueIssuer:          2.0,
-		BidirectionalRelation: 3.0,
-		DepthPenalty:          0.1,
-		OrgIssuedBonus:        2.0,
+		IncomingCredential:      1.0,
+		UniqueIssuer:            2.0,
+		BidirectionalRelation:   3.0,
+		DepthPenalty:            0.1,
+		OrgIssuedBonus:          2.0,
+		ParticipationCredential: 0.25,
+		Contribution:            0,
+		FederatedCredential:     0.5,
+	}
+}
+
+// fields maps each weight's config name to the weight
+func (w *ScoreWeights) fields() map[string]*float64 {
+	return map[string]*float64{
+		"incomingCredential":      &w.IncomingCredential,
+		"uniqueIssuer":            &w.UniqueIssuer,
+		"bidirectionalRelation":   &w.BidirectionalRelation,
+		"depthPenalty":            &w.DepthPenalty,
+		"orgIssuedBonus":          &w.OrgIssuedBonus,
+		"participationCredential": &w.ParticipationCredential,
+		"contribution":            &w.Contribution,
+		"federatedCredential":     &w.FederatedCredential,
+	}
+}
+
+// Validate rejects negative weights
+func (w ScoreWeights) Validate() error {
+	for name, v := range w.fields() {
+		if *v < 0 {
+			return fmt.Errorf("trust weight %s cannot be negative", name)
+		}
+	}
+	return nil
+}
+
+// ParseWeights returns the default weights with the named weights
+// overridden, as set under trust.weights in config.yaml. Unknown names and
+// negative weights are rejected.
+func ParseWeights(overrides map[string]float64) (ScoreWeights, error) {
+	w := DefaultWeights()
+	fields := w.fields()
+	for name, v := range overrides {
+		field, ok := fields[name]
+		if !ok {
+			return ScoreWeights{}, fmt.Errorf("unknown trust weight %q", name)
+		}
+		*field = v
+	}
+	if err := w.Validate(); err != nil {
+		return ScoreWeights{}, err
+	}
+	return w, nil
+}
+
+// UnmarshalJSON decodes weights on top of the defaults, so a partial
+// trustWeights config only overrides the weights it names
+func (w *ScoreWeights) UnmarshalJSON(data []byte) error {
+	type plain ScoreWeights
+	p := plain(DefaultWeights())
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*w = ScoreWeights(p)
+	return nil
+}
+
+// UnmarshalYAML decodes weights on top of the defaults, like UnmarshalJSON
+func (w *ScoreWeights) UnmarshalYAML(value *yaml.Node) error {
+	type plain ScoreWeights
+	p := plain(DefaultWeights())
+	if err := value.Decode(&p); err != nil {
+		return err
 	}
+	*w = ScoreWeights(p)
+	return nil
 }
 
 // Calculator calculates trust scores from a graph
 type Calculator struct {
-	weights ScoreWeights
+	weights   ScoreWeights
+	algorithm Algorithm
 }
 
-// NewCalculator creates a new trust score calculator
+// NewCalculator creates a new trust score calculator using the linear algorithm
 func NewCalculator(weights ScoreWeights) *Calculator {
-	return &Calculator{weights: weights}
+	return &Calculator{weights: weights, algorithm: AlgorithmLinear}
 }
 
 // NewDefaultCalculator creates a calculator with default weights
@@ -35,18 +117,69 @@ func NewDefaultCalculator() *Calculator {
 	return NewCalculator(DefaultWeights())
 }
 
+// WithAlgorithm sets the scoring algorithm by name. Unregistered names
+// score with the linear algorithm; check names with ParseAlgorithm first.
+func (c *Calculator) WithAlgorithm(algorithm Algorithm) *Calculator {
+	c.algorithm = algorithm
+	return c
+}
+
+// Algorithm returns the scoring algorithm
+func (c *Calculator) Algorithm() Algorithm {
+	return c.algorithm
+}
+
+// Weights returns the calculator's score weights
+func (c *Calculator) Weights() ScoreWeights {
+	return c.weights
+}
+
+// scorer prepares the selected algorithm to score graph
+func (c *Calculator) scorer(graph *Graph) Scorer {
+	return lookupAlgorithm(c.algorithm).Scorer(graph, c.weights)
+}
+
 // CalculateScore calculates the trust score for a specific AID
 func (c *Calculator) CalculateScore(aid string, graph *Graph) *Score {
+	return c.calculateScore(aid, graph, c.scorer(graph))
+}
+
+// scoredIncomingEdges returns the credentials to aid that count toward its
+// issuers and relationships; participation and federated credentials are
+// scored separately
+func scoredIncomingEdges(graph *Graph, aid string) []*Edge {
+	var edges []*Edge
+	for _, edge := range graph.GetEdgesTo(aid) {
+		if edge.Type != EdgeTypeParticipation && edge.Type != EdgeTypeFederated {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// calculateScore fills in the credential counts for aid and scores it
+func (c *Calculator) calculateScore(aid string, graph *Graph, scorer Scorer) *Score {
 	score := &Score{AID: aid}
 
 	// Get node info
 	if node := graph.GetNode(aid); node != nil {
 		score.Alias = node.Alias
 		score.Role = node.Role
+		score.Contributions = node.Contributions
 	}
 
 	// Count incoming credentials
-	incomingEdges := graph.GetEdgesTo(aid)
+	for _, edge := range graph.GetEdgesTo(aid) {
+		switch edge.Type {
+		case EdgeTypeParticipation:
+			score.ParticipationCredentials++
+		case EdgeTypeFederated:
+			score.FederatedCredentials++
+		case EdgeTypeEndorsement:
+			score.Endorsements++
+		}
+	}
+	incomingEdges := scoredIncomingEdges(graph, aid)
 	score.IncomingCredentials = len(incomingEdges)
 
 	// Count outgoing credentials
@@ -71,7 +204,7 @@ func (c *Calculator) CalculateScore(aid string, graph *Graph) *Score {
 	score.GraphDepth = c.calculateDepth(aid, graph)
 
 	// Calculate final score
-	score.Score = c.computeScore(score, graph, incomingEdges)
+	score.Score = scorer(score)
 
 	return score
 }
@@ -105,6 +238,9 @@ func (c *Calculator) calculateDepth(aid string, graph *Graph) int {
 
 		// Add neighbors (following outgoing edges from org toward members)
 		for _, edge := range graph.GetEdgesFrom(current.aid) {
+			if edge.Type == EdgeTypeParticipation || edge.Type == EdgeTypeFederated {
+				continue
+			}
 			if !visited[edge.To] {
 				queue = append(queue, struct {
 					aid   string
@@ -118,29 +254,42 @@ func (c *Calculator) calculateDepth(aid string, graph *Graph) int {
 	return -1
 }
 
-// computeScore computes the final trust score
-func (c *Calculator) computeScore(s *Score, graph *Graph, incomingEdges []*Edge) float64 {
+// linearScore computes the linear algorithm's score from an AID's counts
+// and scored incoming credentials
+func linearScore(weights ScoreWeights, s *Score, graph *Graph, incomingEdges []*Edge) float64 {
 	score := 0.0
 
-	// Base score from incoming credentials
-	score += float64(s.IncomingCredentials) * c.weights.IncomingCredential
+	// Base score from incoming credentials, with endorsements scaled by
+	// the endorser's confidence
+	for _, edge := range incomingEdges {
+		score += edge.Strength() * weights.IncomingCredential
+	}
+
+	// Low-weight credit for verified attendance and contribution
+	score += float64(s.ParticipationCredentials) * weights.ParticipationCredential
+
+	// Credit for credentials recognized from federated peer orgs
+	score += float64(s.FederatedCredentials) * weights.FederatedCredential
+
+	// Optional credit for steward-recorded contributions
+	score += float64(s.Contributions) * weights.Contribution
 
 	// Bonus for unique issuers (diversity of trust sources)
-	score += float64(s.UniqueIssuers) * c.weights.UniqueIssuer
+	score += float64(s.UniqueIssuers) * weights.UniqueIssuer
 
 	// Bonus for bidirectional relationships (mutual trust)
-	score += float64(s.BidirectionalRelations) * c.weights.BidirectionalRelation
+	score += float64(s.BidirectionalRelations) * weights.BidirectionalRelation
 
 	// Bonus for org-issued credentials
 	for _, edge := range incomingEdges {
 		if edge.From == graph.OrgAID {
-			score += c.weights.OrgIssuedBonus
+			score += weights.OrgIssuedBonus
 		}
 	}
 
 	// Penalty for depth (closer to org = higher trust)
 	if s.GraphDepth > 0 {
-		score -= float64(s.GraphDepth) * c.weights.DepthPenalty
+		score -= float64(s.GraphDepth) * weights.DepthPenalty
 	}
 
 	// Ensure score is not negative
@@ -154,14 +303,25 @@ func (c *Calculator) computeScore(s *Score, graph *Graph, incomingEdges []*Edge)
 // CalculateAllScores calculates trust scores for all nodes in the graph
 func (c *Calculator) CalculateAllScores(graph *Graph) map[string]*Score {
 	scores := make(map[string]*Score)
+	scorer := c.scorer(graph)
 
 	for aid := range graph.Nodes {
-		scores[aid] = c.CalculateScore(aid, graph)
+		scores[aid] = c.calculateScore(aid, graph, scorer)
 	}
 
 	return scores
 }
 
+// CalculateScores calculates trust scores for the given AIDs, in order
+func (c *Calculator) CalculateScores(graph *Graph, aids []string) []*Score {
+	scorer := c.scorer(graph)
+	scores := make([]*Score, 0, len(aids))
+	for _, aid := range aids {
+		scores = append(scores, c.calculateScore(aid, graph, scorer))
+	}
+	return scores
+}
+
 // GetTopScores returns the top N nodes by trust score
 func (c *Calculator) GetTopScores(graph *Graph, limit int) []*Score {
 	allScores := c.CalculateAllScores(graph)
@@ -172,14 +332,14 @@ func (c *Calculator) GetTopScores(graph *Graph, limit int) []*Score {
 		scores = append(scores, s)
 	}
 
-	// Sort by score descending (simple bubble sort for small N)
-	for i := 0; i < len(scores)-1; i++ {
-		for j := i + 1; j < len(scores); j++ {
-			if scores[j].Score > scores[i].Score {
-				scores[i], scores[j] = scores[j], scores[i]
-			}
+	// Sort by score descending, then by AID so ties come out the same way
+	// every time
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
 		}
-	}
+		return scores[i].AID < scores[j].AID
+	})
 
 	// Return top N
 	if limit > len(scores) {
@@ -197,6 +357,7 @@ type ScoreSummary struct {
 	MinScore       float64 `json:"minScore"`
 	MedianDepth    int     `json:"medianDepth"`
 	BidirectionalCount int `json:"bidirectionalCount"`
+	Algorithm      Algorithm `json:"algorithm"`
 }
 
 // CalculateSummary calculates a summary of trust scores
@@ -204,6 +365,7 @@ func (c *Calculator) CalculateSummary(graph *Graph) *ScoreSummary {
 	summary := &ScoreSummary{
 		TotalNodes: graph.NodeCount(),
 		TotalEdges: graph.EdgeCount(),
+		Algorithm:  c.algorithm,
 	}
 
 	if summary.TotalNodes == 0 {
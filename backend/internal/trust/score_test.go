@@ -1,7 +1,10 @@
 package trust
 
 import (
+	"encoding/json"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestNewDefaultCalculator(t *testing.T) {
@@ -244,6 +247,47 @@ func TestCalculator_GetTopScores(t *testing.T) {
 	if len(allScores) != 3 {
 		t.Errorf("expected 3 scores, got %d", len(allScores))
 	}
+
+}
+
+func TestCalculator_GetTopScores_Ties(t *testing.T) {
+	graph := NewGraph("EORG123")
+	for _, aid := range []string{"EUSERC", "EUSERA", "EUSERB"} {
+		graph.AddNode(&Node{AID: aid, Role: "Member"})
+		graph.AddEdge(&Edge{From: "EORG123", To: aid, CredentialID: "M-" + aid, Type: EdgeTypeMembership})
+	}
+
+	topScores := NewDefaultCalculator().GetTopScores(graph, 3)
+	if len(topScores) != 3 {
+		t.Fatalf("expected 3 top scores, got %d", len(topScores))
+	}
+	for i, want := range []string{"EUSERA", "EUSERB", "EUSERC"} {
+		if topScores[i].AID != want {
+			t.Errorf("expected tied scores in AID order, got %s at %d", topScores[i].AID, i)
+		}
+	}
+}
+
+func TestCalculator_CalculateScores(t *testing.T) {
+	graph := benchmarkGraph(10)
+	calc := NewDefaultCalculator()
+
+	scores := calc.CalculateScores(graph, []string{"EUSER3", "EUSER1"})
+	if len(scores) != 2 || scores[0].AID != "EUSER3" || scores[1].AID != "EUSER1" {
+		t.Fatalf("expected scores for EUSER3 then EUSER1, got %+v", scores)
+	}
+	if scores[1].Score != calc.CalculateScore("EUSER1", graph).Score {
+		t.Errorf("expected the same score as CalculateScore, got %f", scores[1].Score)
+	}
+}
+
+func BenchmarkCalculator_GetTopScores(b *testing.B) {
+	graph := benchmarkGraph(500)
+	calc := NewDefaultCalculator()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		calc.GetTopScores(graph, 10)
+	}
 }
 
 func TestCalculator_CalculateSummary(t *testing.T) {
@@ -322,3 +366,159 @@ func TestCalculator_CustomWeights(t *testing.T) {
 		t.Errorf("expected score %f, got %f", expectedScore, score.Score)
 	}
 }
+
+func TestCalculator_CalculateScore_ParticipationEdges(t *testing.T) {
+	graph := NewGraph("EORG123")
+	graph.AddNode(&Node{AID: "EORG123", Role: "Organization"})
+	graph.AddNode(&Node{AID: "EUSER1", Role: "Member"})
+	graph.AddNode(&Node{AID: "EUSER2"})
+	graph.AddEdge(&Edge{From: "EORG123", To: "EUSER1", CredentialID: "E1", Type: EdgeTypeMembership})
+	graph.AddEdge(&Edge{From: "EORG123", To: "EUSER1", CredentialID: "E2", Type: EdgeTypeParticipation})
+	graph.AddEdge(&Edge{From: "EORG123", To: "EUSER1", CredentialID: "E3", Type: EdgeTypeParticipation})
+	graph.AddEdge(&Edge{From: "EORG123", To: "EUSER2", CredentialID: "E4", Type: EdgeTypeParticipation})
+
+	calc := NewDefaultCalculator()
+
+	score := calc.CalculateScore("EUSER1", graph)
+	if score.IncomingCredentials != 1 {
+		t.Errorf("expected 1 incoming credential, got %d", score.IncomingCredentials)
+	}
+	if score.ParticipationCredentials != 2 {
+		t.Errorf("expected 2 participation credentials, got %d", score.ParticipationCredentials)
+	}
+	if score.UniqueIssuers != 1 {
+		t.Errorf("expected 1 unique issuer, got %d", score.UniqueIssuers)
+	}
+
+	// Participation alone doesn't connect a node to the org
+	outsider := calc.CalculateScore("EUSER2", graph)
+	if outsider.GraphDepth != -1 {
+		t.Errorf("expected depth -1 for participation-only node, got %d", outsider.GraphDepth)
+	}
+	if outsider.IncomingCredentials != 0 || outsider.ParticipationCredentials != 1 {
+		t.Errorf("unexpected counts: incoming=%d participation=%d",
+			outsider.IncomingCredentials, outsider.ParticipationCredentials)
+	}
+}
+
+func TestCalculator_CalculateScore_EndorsementConfidence(t *testing.T) {
+	half := 0.5
+	graph := NewGraph("EORG123")
+	graph.AddNode(&Node{AID: "EORG123", Role: "Organization"})
+	graph.AddNode(&Node{AID: "EUSER1", Role: "Member"})
+	graph.AddNode(&Node{AID: "EUSER2", Role: "Member"})
+	graph.AddNode(&Node{AID: "EUSER3", Role: "Member"})
+	graph.AddEdge(&Edge{From: "EUSER1", To: "EUSER2", CredentialID: "E1", Type: EdgeTypeEndorsement})
+	graph.AddEdge(&Edge{From: "EUSER1", To: "EUSER3", CredentialID: "E2", Type: EdgeTypeEndorsement, Confidence: &half})
+
+	calc := NewDefaultCalculator()
+	full := calc.CalculateScore("EUSER2", graph)
+	partial := calc.CalculateScore("EUSER3", graph)
+
+	if full.Endorsements != 1 || partial.Endorsements != 1 {
+		t.Errorf("expected 1 endorsement each, got %d and %d", full.Endorsements, partial.Endorsements)
+	}
+	// Both have one issuer; only the incoming credential weight is scaled
+	if diff := full.Score - partial.Score; diff != 0.5*DefaultWeights().IncomingCredential {
+		t.Errorf("expected half-confidence endorsement to score %v less, got %v", 0.5*DefaultWeights().IncomingCredential, diff)
+	}
+}
+
+func TestCalculator_CalculateScore_FederatedEdges(t *testing.T) {
+	graph := NewGraph("EORG123")
+	graph.AddNode(&Node{AID: "EORG123", Role: "Organization"})
+	graph.AddNode(&Node{AID: "EPEER", Role: RoleFederatedOrganization})
+	graph.AddNode(&Node{AID: "EUSER1", Role: "Member"})
+	graph.AddEdge(&Edge{From: "EORG123", To: "EUSER1", CredentialID: "E1", Type: EdgeTypeMembership})
+	graph.AddEdge(&Edge{From: "EPEER", To: "EUSER1", CredentialID: "E2", Type: EdgeTypeFederated})
+
+	calc := NewDefaultCalculator()
+	score := calc.CalculateScore("EUSER1", graph)
+	if score.FederatedCredentials != 1 {
+		t.Errorf("expected 1 federated credential, got %d", score.FederatedCredentials)
+	}
+	if score.IncomingCredentials != 1 || score.UniqueIssuers != 1 {
+		t.Errorf("federated edge should not count as incoming: incoming=%d issuers=%d",
+			score.IncomingCredentials, score.UniqueIssuers)
+	}
+
+	// 1 incoming + 1 issuer*2 + org bonus 2 + federated 0.5 - depth 1*0.1
+	expected := 1.0 + 2.0 + 2.0 + 0.5 - 0.1
+	if score.Score != expected {
+		t.Errorf("expected score %f, got %f", expected, score.Score)
+	}
+
+	// A peer org never places anyone in the trust tree
+	graph.AddNode(&Node{AID: "EUSER2"})
+	graph.AddEdge(&Edge{From: "EPEER", To: "EUSER2", CredentialID: "E3", Type: EdgeTypeFederated})
+	if depth := calc.CalculateScore("EUSER2", graph).GraphDepth; depth != -1 {
+		t.Errorf("expected depth -1 for federated-only node, got %d", depth)
+	}
+}
+
+func TestCalculator_CalculateScore_Contributions(t *testing.T) {
+	graph := NewGraph("EORG123")
+	graph.AddNode(&Node{AID: "EORG123", Role: "Organization"})
+	graph.AddNode(&Node{AID: "EUSER1", Role: "Member", Contributions: 4})
+	graph.AddEdge(&Edge{From: "EORG123", To: "EUSER1", CredentialID: "E1", Type: EdgeTypeMembership})
+
+	base := NewDefaultCalculator().CalculateScore("EUSER1", graph)
+	if base.Contributions != 4 {
+		t.Errorf("expected 4 contributions, got %d", base.Contributions)
+	}
+
+	weights := DefaultWeights()
+	weights.Contribution = 0.5
+	weighted := NewCalculator(weights).CalculateScore("EUSER1", graph)
+	if weighted.Score != base.Score+2.0 {
+		t.Errorf("expected contributions to add 2.0, got %f -> %f", base.Score, weighted.Score)
+	}
+}
+
+func TestScoreWeights_PartialDecode(t *testing.T) {
+	var fromJSON ScoreWeights
+	if err := json.Unmarshal([]byte(`{"participationCredential": 0.5}`), &fromJSON); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v", err)
+	}
+	if fromJSON.ParticipationCredential != 0.5 {
+		t.Errorf("expected ParticipationCredential 0.5, got %f", fromJSON.ParticipationCredential)
+	}
+	if fromJSON.UniqueIssuer != DefaultWeights().UniqueIssuer {
+		t.Errorf("expected default UniqueIssuer, got %f", fromJSON.UniqueIssuer)
+	}
+
+	var fromYAML ScoreWeights
+	if err := yaml.Unmarshal([]byte("orgIssuedBonus: 1\n"), &fromYAML); err != nil {
+		t.Fatalf("yaml.Unmarshal failed: %v", err)
+	}
+	if fromYAML.OrgIssuedBonus != 1 {
+		t.Errorf("expected OrgIssuedBonus 1, got %f", fromYAML.OrgIssuedBonus)
+	}
+	if fromYAML.ParticipationCredential != DefaultWeights().ParticipationCredential {
+		t.Errorf("expected default ParticipationCredential, got %f", fromYAML.ParticipationCredential)
+	}
+
+	if err := (ScoreWeights{DepthPenalty: -1}).Validate(); err == nil {
+		t.Error("expected negative weight to be rejected")
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	weights, err := ParseWeights(map[string]float64{"contribution": 0.5, "depthPenalty": 0})
+	if err != nil {
+		t.Fatalf("ParseWeights failed: %v", err)
+	}
+	want := DefaultWeights()
+	want.Contribution = 0.5
+	want.DepthPenalty = 0
+	if weights != want {
+		t.Errorf("expected %+v, got %+v", want, weights)
+	}
+
+	if _, err := ParseWeights(map[string]float64{"incomingCredentials": 1}); err == nil {
+		t.Error("expected an unknown weight to be rejected")
+	}
+	if _, err := ParseWeights(map[string]float64{"uniqueIssuer": -1}); err == nil {
+		t.Error("expected a negative weight to be rejected")
+	}
+}
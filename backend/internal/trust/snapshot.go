@@ -0,0 +1,180 @@
+package trust
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// SnapshotVersion is the snapshot format version. It changes whenever the
+// format or the scoring rules change in a way that alters results.
+const SnapshotVersion = 1
+
+// snapshotTolerance is how far a recomputed score may drift from the
+// published one, to allow for floating point differences across platforms
+const snapshotTolerance = 1e-9
+
+// Snapshot is a self-contained, content-hashed record of everything that
+// went into a set of published trust scores: the nodes, the credential and
+// endorsement edges (with confidence weights), the score weights and the
+// algorithm. Anyone holding a snapshot can re-run the calculator with
+// VerifySnapshot and check the scores.
+//
+// Snapshots are deterministic: the same graph and calculator always produce
+// the same bytes and hash, so nothing time-dependent is recorded.
+type Snapshot struct {
+	Version   int             `json:"version"`
+	OrgAID    string          `json:"orgAid"`
+	Algorithm Algorithm       `json:"algorithm"`
+	Weights   ScoreWeights    `json:"weights"`
+	Nodes     []*Node         `json:"nodes"`  // Sorted by AID
+	Edges     []*Edge         `json:"edges"`  // Sorted by credential ID
+	Scores    []SnapshotScore `json:"scores"` // Sorted by AID
+	// Hash is "sha256:" and the hex SHA-256 of the snapshot's JSON encoding
+	// with Hash empty
+	Hash string `json:"hash"`
+}
+
+// SnapshotScore is a published score in a snapshot
+type SnapshotScore struct {
+	AID   string  `json:"aid"`
+	Score float64 `json:"score"`
+}
+
+// NewSnapshot records graph and the scores calc computes for it. The graph
+// is copied in canonical order and the scores are computed from the copy,
+// so recomputing them from the snapshot gives exactly the same results.
+func NewSnapshot(graph *Graph, calc *Calculator) (*Snapshot, error) {
+	s := &Snapshot{
+		Version:   SnapshotVersion,
+		OrgAID:    graph.OrgAID,
+		Algorithm: calc.algorithm,
+		Weights:   calc.weights,
+		Nodes:     make([]*Node, 0, len(graph.Nodes)),
+		Edges:     make([]*Edge, 0, len(graph.Edges)),
+	}
+	for _, n := range graph.Nodes {
+		node := *n
+		node.JoinedAt = node.JoinedAt.UTC()
+		s.Nodes = append(s.Nodes, &node)
+	}
+	sort.Slice(s.Nodes, func(i, j int) bool { return s.Nodes[i].AID < s.Nodes[j].AID })
+	for _, e := range graph.Edges {
+		edge := *e
+		edge.CreatedAt = edge.CreatedAt.UTC()
+		s.Edges = append(s.Edges, &edge)
+	}
+	sort.SliceStable(s.Edges, func(i, j int) bool { return s.Edges[i].CredentialID < s.Edges[j].CredentialID })
+
+	s.Scores = scoreList(calc.CalculateAllScores(s.Graph()))
+
+	hash, err := s.ComputeHash()
+	if err != nil {
+		return nil, err
+	}
+	s.Hash = hash
+	return s, nil
+}
+
+// scoreList returns scores as a list sorted by AID
+func scoreList(scores map[string]*Score) []SnapshotScore {
+	list := make([]SnapshotScore, 0, len(scores))
+	for aid, s := range scores {
+		list = append(list, SnapshotScore{AID: aid, Score: s.Score})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].AID < list[j].AID })
+	return list
+}
+
+// Graph rebuilds the trust graph the snapshot records
+func (s *Snapshot) Graph() *Graph {
+	graph := NewGraph(s.OrgAID)
+	for _, n := range s.Nodes {
+		node := *n
+		graph.Nodes[node.AID] = &node
+	}
+	for _, e := range s.Edges {
+		edge := *e
+		graph.AddEdge(&edge)
+	}
+	return graph
+}
+
+// ComputeHash returns the snapshot's content hash, ignoring its Hash field
+func (s *Snapshot) ComputeHash() (string, error) {
+	unhashed := *s
+	unhashed.Hash = ""
+	data, err := json.Marshal(&unhashed)
+	if err != nil {
+		return "", fmt.Errorf("encoding snapshot: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// SnapshotMismatch is a published score that recomputing didn't reproduce.
+// Published or Recomputed is nil when the AID is missing from that side.
+type SnapshotMismatch struct {
+	AID        string   `json:"aid"`
+	Published  *float64 `json:"published"`
+	Recomputed *float64 `json:"recomputed"`
+}
+
+// SnapshotVerification is the result of checking a snapshot
+type SnapshotVerification struct {
+	Valid      bool               `json:"valid"`
+	Hash       string             `json:"hash"` // Recomputed content hash
+	HashValid  bool               `json:"hashValid"`
+	Scores     int                `json:"scores"` // Published scores checked
+	Mismatches []SnapshotMismatch `json:"mismatches,omitempty"`
+}
+
+// VerifySnapshot checks a snapshot's content hash and recomputes its
+// scores from the recorded graph, weights and algorithm. The snapshot is
+// valid when the hash matches and every published score is reproduced.
+func VerifySnapshot(s *Snapshot) (*SnapshotVerification, error) {
+	if s.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d (expected %d)", s.Version, SnapshotVersion)
+	}
+	algorithm, err := ParseAlgorithm(string(s.Algorithm))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Weights.Validate(); err != nil {
+		return nil, err
+	}
+
+	hash, err := s.ComputeHash()
+	if err != nil {
+		return nil, err
+	}
+	result := &SnapshotVerification{Hash: hash, HashValid: hash == s.Hash, Scores: len(s.Scores)}
+
+	recomputed := NewCalculator(s.Weights).WithAlgorithm(algorithm).CalculateAllScores(s.Graph())
+	published := make(map[string]bool, len(s.Scores))
+	for _, p := range s.Scores {
+		published[p.AID] = true
+		score := p.Score
+		r, ok := recomputed[p.AID]
+		if !ok {
+			result.Mismatches = append(result.Mismatches, SnapshotMismatch{AID: p.AID, Published: &score})
+			continue
+		}
+		if math.Abs(r.Score-p.Score) > snapshotTolerance {
+			got := r.Score
+			result.Mismatches = append(result.Mismatches, SnapshotMismatch{AID: p.AID, Published: &score, Recomputed: &got})
+		}
+	}
+	for _, r := range scoreList(recomputed) {
+		if !published[r.AID] {
+			got := r.Score
+			result.Mismatches = append(result.Mismatches, SnapshotMismatch{AID: r.AID, Recomputed: &got})
+		}
+	}
+
+	result.Valid = result.HashValid && len(result.Mismatches) == 0
+	return result, nil
+}
@@ -0,0 +1,98 @@
+package trust
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func snapshotTestGraph(reversed bool) *Graph {
+	confidence := 0.5
+	edges := []*Edge{
+		{From: "EORG", To: "EUSER1", CredentialID: "ESAID1", Type: EdgeTypeMembership, CreatedAt: time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{From: "EORG", To: "EUSER2", CredentialID: "ESAID2", Type: EdgeTypeMembership},
+		{From: "EUSER1", To: "EUSER2", CredentialID: "ESAID3", Type: EdgeTypeEndorsement, Confidence: &confidence},
+		{From: "EUSER2", To: "EUSER1", CredentialID: "ESAID4", Type: EdgeTypeEndorsement},
+	}
+	if reversed {
+		for i, j := 0, len(edges)-1; i < j; i, j = i+1, j-1 {
+			edges[i], edges[j] = edges[j], edges[i]
+		}
+	}
+
+	graph := NewGraph("EORG")
+	graph.AddNode(&Node{AID: "EORG", Role: "Organization"})
+	graph.AddNode(&Node{AID: "EUSER1", Alias: "ana", Role: "Member", Attributes: map[string]interface{}{"region": "north"}})
+	graph.AddNode(&Node{AID: "EUSER2", Role: "Member", Contributions: 2})
+	for _, e := range edges {
+		graph.AddEdge(e)
+	}
+	graph.MarkBidirectionalEdges()
+	return graph
+}
+
+func TestNewSnapshot_Deterministic(t *testing.T) {
+	for _, algorithm := range []Algorithm{AlgorithmLinear, AlgorithmPageRank} {
+		calc := NewDefaultCalculator().WithAlgorithm(algorithm)
+		first, err := NewSnapshot(snapshotTestGraph(false), calc)
+		if err != nil {
+			t.Fatalf("NewSnapshot failed: %v", err)
+		}
+		second, err := NewSnapshot(snapshotTestGraph(true), calc)
+		if err != nil {
+			t.Fatalf("NewSnapshot failed: %v", err)
+		}
+
+		a, _ := json.Marshal(first)
+		b, _ := json.Marshal(second)
+		if string(a) != string(b) {
+			t.Errorf("%s: snapshots of the same graph differ:\n%s\n%s", algorithm, a, b)
+		}
+		if len(first.Scores) != 3 || first.Edges[0].CredentialID != "ESAID1" || first.Nodes[0].AID != "EORG" {
+			t.Errorf("%s: snapshot not in canonical order: %s", algorithm, a)
+		}
+	}
+}
+
+func TestVerifySnapshot(t *testing.T) {
+	calc := NewCalculator(ScoreWeights{IncomingCredential: 1, UniqueIssuer: 2, Contribution: 1}).WithAlgorithm(AlgorithmLinear)
+	snapshot, err := NewSnapshot(snapshotTestGraph(false), calc)
+	if err != nil {
+		t.Fatalf("NewSnapshot failed: %v", err)
+	}
+
+	// A third party receives the snapshot as JSON
+	data, _ := json.Marshal(snapshot)
+	var received Snapshot
+	if err := json.Unmarshal(data, &received); err != nil {
+		t.Fatalf("decoding snapshot failed: %v", err)
+	}
+	result, err := VerifySnapshot(&received)
+	if err != nil {
+		t.Fatalf("VerifySnapshot failed: %v", err)
+	}
+	if !result.Valid || !result.HashValid || result.Scores != 3 || result.Hash != snapshot.Hash {
+		t.Fatalf("expected valid snapshot, got %+v", result)
+	}
+
+	// Inflating a published score is caught by both the hash and the recompute
+	received.Scores[1].Score += 10
+	result, _ = VerifySnapshot(&received)
+	if result.Valid || result.HashValid || len(result.Mismatches) != 1 || result.Mismatches[0].AID != "EUSER1" {
+		t.Errorf("expected tampered score to fail, got %+v", result)
+	}
+
+	// Changing the inputs and re-hashing is caught by the recompute
+	received.Scores[1].Score -= 10
+	received.Weights.UniqueIssuer = 5
+	received.Hash, _ = received.ComputeHash()
+	result, _ = VerifySnapshot(&received)
+	if result.Valid || !result.HashValid || len(result.Mismatches) == 0 {
+		t.Errorf("expected changed weights to fail, got %+v", result)
+	}
+
+	received.Version = 99
+	if _, err := VerifySnapshot(&received); err == nil {
+		t.Error("expected error for unsupported version")
+	}
+}
@@ -0,0 +1,83 @@
+package trust
+
+import (
+	"math"
+	"time"
+)
+
+// PublicStats are aggregates of a trust graph for public pages. They are
+// counts and averages over the whole community only, never per-AID data.
+type PublicStats struct {
+	Members int `json:"members"`
+	// CredentialsIssuedThisMonth counts credentials dated (joinedAt or
+	// grantedAt) within Month. Undated credentials aren't counted.
+	CredentialsIssuedThisMonth int `json:"credentialsIssuedThisMonth"`
+	// AverageTrustDepth is the mean number of credential hops from the org
+	// to each member it reaches, to two decimal places
+	AverageTrustDepth float64 `json:"averageTrustDepth"`
+	// Month is the calendar month (UTC) the issuance count covers, "2006-01"
+	Month     string    `json:"month"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CalculatePublicStats computes the public aggregates of a graph as of now
+func CalculatePublicStats(graph *Graph, now time.Time) *PublicStats {
+	now = now.UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	stats := &PublicStats{
+		Month:     monthStart.Format("2006-01"),
+		UpdatedAt: now,
+	}
+
+	for _, node := range graph.Nodes {
+		if node.IsMember() {
+			stats.Members++
+		}
+	}
+	for _, edge := range graph.Edges {
+		if edge.Type == EdgeTypeFederated {
+			continue
+		}
+		if !edge.CreatedAt.Before(monthStart) && !edge.CreatedAt.After(now) {
+			stats.CredentialsIssuedThisMonth++
+		}
+	}
+
+	var total, reached int
+	for aid, depth := range depthsFromOrg(graph) {
+		if node := graph.Nodes[aid]; node != nil && node.IsMember() {
+			total += depth
+			reached++
+		}
+	}
+	if reached > 0 {
+		stats.AverageTrustDepth = math.Round(float64(total)/float64(reached)*100) / 100
+	}
+	return stats
+}
+
+// depthsFromOrg returns each reachable AID's depth from the org, following
+// the same edges as Calculator's per-AID depth
+func depthsFromOrg(graph *Graph) map[string]int {
+	outgoing := make(map[string][]*Edge)
+	for _, edge := range graph.Edges {
+		if edge.Type == EdgeTypeParticipation || edge.Type == EdgeTypeFederated {
+			continue
+		}
+		outgoing[edge.From] = append(outgoing[edge.From], edge)
+	}
+
+	depths := map[string]int{graph.OrgAID: 0}
+	queue := []string{graph.OrgAID}
+	for len(queue) > 0 {
+		aid := queue[0]
+		queue = queue[1:]
+		for _, edge := range outgoing[aid] {
+			if _, seen := depths[edge.To]; !seen {
+				depths[edge.To] = depths[aid] + 1
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+	return depths
+}
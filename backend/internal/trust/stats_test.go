@@ -0,0 +1,42 @@
+package trust
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculatePublicStats(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	graph := NewGraph("EORG")
+	graph.AddNode(&Node{AID: "EORG", Role: "Organization"})
+	graph.AddNode(&Node{AID: "EALICE", Role: "Member"})
+	graph.AddNode(&Node{AID: "EBOB", Role: "Trusted Member"})
+	graph.AddNode(&Node{AID: "EPEER", Role: RoleFederatedOrganization})
+	graph.AddNode(&Node{AID: "EGUEST"})
+	graph.AddEdge(&Edge{From: "EORG", To: "EALICE", CredentialID: "E1", Type: EdgeTypeMembership, CreatedAt: now.AddDate(0, -2, 0)})
+	graph.AddEdge(&Edge{From: "EALICE", To: "EBOB", CredentialID: "E2", Type: EdgeTypeInvitation, CreatedAt: now.AddDate(0, 0, -3)})
+	graph.AddEdge(&Edge{From: "EORG", To: "EBOB", CredentialID: "E3", Type: EdgeTypeSteward})
+	graph.AddEdge(&Edge{From: "EPEER", To: "EGUEST", CredentialID: "E4", Type: EdgeTypeFederated, CreatedAt: now})
+
+	stats := CalculatePublicStats(graph, now)
+	if stats.Members != 2 {
+		t.Errorf("expected 2 members, got %d", stats.Members)
+	}
+	if stats.CredentialsIssuedThisMonth != 1 {
+		t.Errorf("expected 1 credential issued this month, got %d", stats.CredentialsIssuedThisMonth)
+	}
+	// Both members are one hop from the org, Bob through his steward credential
+	if stats.AverageTrustDepth != 1 {
+		t.Errorf("expected average depth 1, got %v", stats.AverageTrustDepth)
+	}
+	if stats.Month != "2026-10" {
+		t.Errorf("expected month 2026-10, got %s", stats.Month)
+	}
+}
+
+func TestCalculatePublicStats_Empty(t *testing.T) {
+	stats := CalculatePublicStats(NewGraph("EORG"), time.Now())
+	if stats.Members != 0 || stats.AverageTrustDepth != 0 {
+		t.Errorf("expected empty stats, got %+v", stats)
+	}
+}
@@ -0,0 +1,119 @@
+package trust
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+// Term status values
+const (
+	TermStatusActive   = "active"
+	TermStatusExpiring = "expiring" // ends within the notice window
+	TermStatusExpired  = "expired"  // ended and not renewed
+	TermStatusRenewed  = "renewed"  // superseded by a later term for the same role
+)
+
+// DefaultTermNoticeWindow is how long before expiry a term is flagged
+const DefaultTermNoticeWindow = 14 * 24 * time.Hour
+
+// DefaultDowngradeRole is the role a holder falls back to when a term lapses
+const DefaultDowngradeRole = "Member"
+
+// Term is a term-limited role held through a credential that carries a
+// termEndsAt date (e.g. an Operations Steward serving a one-year term).
+type Term struct {
+	CredentialID string    `json:"credentialId"`
+	HolderAID    string    `json:"holderAid"`
+	IssuerAID    string    `json:"issuerAid"`
+	Role         string    `json:"role"`
+	DowngradeTo  string    `json:"downgradeTo"`
+	EndsAt       time.Time `json:"endsAt"`
+	Status       string    `json:"status"`
+	RenewedBy    string    `json:"renewedBy,omitempty"` // SAID of the renewing credential
+}
+
+// EvaluateTerms finds term-limited credentials and works out their status
+// at now. A term is renewed when the same holder has another credential for
+// the same role whose term ends later; renewals are issued via governance as
+// new credentials, so nothing here mutates the original.
+func EvaluateTerms(creds []*anystore.CachedCredential, now time.Time, window time.Duration) []*Term {
+	var terms []*Term
+	for _, cred := range creds {
+		data := credentialDataMap(cred)
+		if data == nil {
+			continue
+		}
+		endsRaw, _ := data["termEndsAt"].(string)
+		if endsRaw == "" {
+			continue
+		}
+		endsAt, err := time.Parse(time.RFC3339, endsRaw)
+		if err != nil {
+			continue
+		}
+		role, _ := data["role"].(string)
+		downgrade, _ := data["downgradeTo"].(string)
+		if downgrade == "" {
+			downgrade = DefaultDowngradeRole
+		}
+		terms = append(terms, &Term{
+			CredentialID: cred.ID,
+			HolderAID:    cred.SubjectAID,
+			IssuerAID:    cred.IssuerAID,
+			Role:         role,
+			DowngradeTo:  downgrade,
+			EndsAt:       endsAt,
+		})
+	}
+
+	// Latest term per holder+role decides renewal
+	latest := make(map[string]*Term)
+	for _, t := range terms {
+		key := t.HolderAID + "|" + t.Role
+		if cur, ok := latest[key]; !ok || t.EndsAt.After(cur.EndsAt) {
+			latest[key] = t
+		}
+	}
+
+	for _, t := range terms {
+		newest := latest[t.HolderAID+"|"+t.Role]
+		switch {
+		case newest != t:
+			t.Status = TermStatusRenewed
+			t.RenewedBy = newest.CredentialID
+		case !now.Before(t.EndsAt):
+			t.Status = TermStatusExpired
+		case t.EndsAt.Sub(now) <= window:
+			t.Status = TermStatusExpiring
+		default:
+			t.Status = TermStatusActive
+		}
+	}
+
+	sort.Slice(terms, func(i, j int) bool {
+		return terms[i].EndsAt.Before(terms[j].EndsAt)
+	})
+	return terms
+}
+
+// credentialDataMap returns the credential data as a map, or nil
+func credentialDataMap(cred *anystore.CachedCredential) map[string]interface{} {
+	if cred == nil || cred.Data == nil {
+		return nil
+	}
+	if m, ok := cred.Data.(map[string]interface{}); ok {
+		return m
+	}
+	bytes, err := json.Marshal(cred.Data)
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(bytes, &m); err != nil {
+		return nil
+	}
+	return m
+}
@@ -0,0 +1,64 @@
+package trust
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matou-dao/backend/internal/anystore"
+)
+
+func termCredential(id, holder, role string, endsAt time.Time) *anystore.CachedCredential {
+	return &anystore.CachedCredential{
+		ID:         id,
+		IssuerAID:  "EORG123",
+		SubjectAID: holder,
+		SchemaID:   "EMatouStewardSchemaV1",
+		Data: map[string]interface{}{
+			"role":       role,
+			"termEndsAt": endsAt.Format(time.RFC3339),
+		},
+	}
+}
+
+func TestEvaluateTerms(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	creds := []*anystore.CachedCredential{
+		termCredential("EACTIVE", "EUSER1", "Operations Steward", now.Add(90*day)),
+		termCredential("EEXPIRING", "EUSER2", "Operations Steward", now.Add(3*day)),
+		termCredential("EEXPIRED", "EUSER3", "Operations Steward", now.Add(-day)),
+		termCredential("EOLD", "EUSER4", "Moderator", now.Add(-30*day)),
+		termCredential("ENEW", "EUSER4", "Moderator", now.Add(300*day)),
+		{ID: "EPERMANENT", SubjectAID: "EUSER5", Data: map[string]interface{}{"role": "Member"}},
+	}
+
+	terms := EvaluateTerms(creds, now, 14*day)
+	if len(terms) != 5 {
+		t.Fatalf("expected 5 terms, got %d", len(terms))
+	}
+
+	byID := make(map[string]*Term)
+	for _, term := range terms {
+		byID[term.CredentialID] = term
+	}
+
+	want := map[string]string{
+		"EACTIVE":   TermStatusActive,
+		"EEXPIRING": TermStatusExpiring,
+		"EEXPIRED":  TermStatusExpired,
+		"EOLD":      TermStatusRenewed,
+		"ENEW":      TermStatusActive,
+	}
+	for id, status := range want {
+		if byID[id].Status != status {
+			t.Errorf("%s: expected status %s, got %s", id, status, byID[id].Status)
+		}
+	}
+	if byID["EOLD"].RenewedBy != "ENEW" {
+		t.Errorf("expected EOLD renewed by ENEW, got %q", byID["EOLD"].RenewedBy)
+	}
+	if byID["EEXPIRED"].DowngradeTo != DefaultDowngradeRole {
+		t.Errorf("expected default downgrade role, got %s", byID["EEXPIRED"].DowngradeTo)
+	}
+}
@@ -11,6 +11,19 @@ type Node struct {
 	Role            string    `json:"role"`
 	JoinedAt        time.Time `json:"joinedAt"`
 	CredentialCount int       `json:"credentialCount"`
+
+	// Custom role attributes from credentials (committee, region, ...)
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+
+	// Steward-recorded contributions; set by the caller after building
+	Contributions int `json:"contributions,omitempty"`
+}
+
+// IsMember reports whether the node holds a role in this community. Org
+// nodes, federated peers and AIDs known only through participation or
+// federated credentials have no local role.
+func (n *Node) IsMember() bool {
+	return n.Role != "" && n.Role != "Organization" && n.Role != RoleFederatedOrganization
 }
 
 // Edge represents a credential relationship between two identities
@@ -21,14 +34,33 @@ type Edge struct {
 	Type          string    `json:"type"`          // membership, invitation, steward
 	Bidirectional bool      `json:"bidirectional"` // Mutual relationship
 	CreatedAt     time.Time `json:"createdAt"`
+
+	// Endorser's confidence in [0, 1], from the endorsement credential's
+	// confidence field; nil means full confidence
+	Confidence *float64 `json:"confidence,omitempty"`
+}
+
+// Strength returns how much of a full credential's weight the edge carries:
+// its confidence for endorsements that state one, otherwise 1
+func (e *Edge) Strength() float64 {
+	if e.Type == EdgeTypeEndorsement && e.Confidence != nil {
+		return *e.Confidence
+	}
+	return 1
 }
 
-// Graph is the complete trust graph containing nodes and edges
+// Graph is the complete trust graph containing nodes and edges. Edges must
+// be added with AddEdge, which keeps the adjacency indexes in step.
 type Graph struct {
 	Nodes   map[string]*Node `json:"nodes"`
 	Edges   []*Edge          `json:"edges"`
 	OrgAID  string           `json:"orgAid"`
 	Updated time.Time        `json:"updated"`
+
+	// Adjacency indexes, so edge lookups don't scan every edge
+	outgoing    map[string][]*Edge // issuer AID -> edges
+	incoming    map[string][]*Edge // subject AID -> edges
+	credentials map[string]bool    // credential SAIDs already added
 }
 
 // NewGraph creates a new empty trust graph
@@ -38,6 +70,10 @@ func NewGraph(orgAID string) *Graph {
 		Edges:   make([]*Edge, 0),
 		OrgAID:  orgAID,
 		Updated: time.Now().UTC(),
+
+		outgoing:    make(map[string][]*Edge),
+		incoming:    make(map[string][]*Edge),
+		credentials: make(map[string]bool),
 	}
 }
 
@@ -51,6 +87,12 @@ func (g *Graph) AddNode(node *Node) {
 		if node.Role != "" {
 			existing.Role = node.Role
 		}
+		for k, v := range node.Attributes {
+			if existing.Attributes == nil {
+				existing.Attributes = make(map[string]interface{})
+			}
+			existing.Attributes[k] = v
+		}
 		existing.CredentialCount++
 	} else {
 		node.CredentialCount = 1
@@ -58,15 +100,20 @@ func (g *Graph) AddNode(node *Node) {
 	}
 }
 
-// AddEdge adds an edge to the graph
+// AddEdge adds an edge to the graph, ignoring a credential already added
 func (g *Graph) AddEdge(edge *Edge) {
-	// Check for duplicate edge
-	for _, e := range g.Edges {
-		if e.CredentialID == edge.CredentialID {
-			return // Edge already exists
-		}
+	if g.credentials == nil {
+		g.outgoing = make(map[string][]*Edge)
+		g.incoming = make(map[string][]*Edge)
+		g.credentials = make(map[string]bool)
+	}
+	if g.credentials[edge.CredentialID] {
+		return // Edge already exists
 	}
+	g.credentials[edge.CredentialID] = true
 	g.Edges = append(g.Edges, edge)
+	g.outgoing[edge.From] = append(g.outgoing[edge.From], edge)
+	g.incoming[edge.To] = append(g.incoming[edge.To], edge)
 }
 
 // GetNode returns a node by AID
@@ -76,41 +123,119 @@ func (g *Graph) GetNode(aid string) *Node {
 
 // GetEdgesFrom returns all edges from a given AID (outgoing)
 func (g *Graph) GetEdgesFrom(aid string) []*Edge {
-	edges := make([]*Edge, 0)
-	for _, e := range g.Edges {
-		if e.From == aid {
-			edges = append(edges, e)
-		}
-	}
-	return edges
+	return append(make([]*Edge, 0, len(g.outgoing[aid])), g.outgoing[aid]...)
 }
 
 // GetEdgesTo returns all edges to a given AID (incoming)
 func (g *Graph) GetEdgesTo(aid string) []*Edge {
-	edges := make([]*Edge, 0)
+	return append(make([]*Edge, 0, len(g.incoming[aid])), g.incoming[aid]...)
+}
+
+// Distances returns the hop count from one AID to every reachable AID,
+// following edges in either direction. Paths through the organization root
+// are not followed, since every member is one hop from it.
+func (g *Graph) Distances(from string) map[string]int {
+	neighbors := make(map[string][]string)
 	for _, e := range g.Edges {
-		if e.To == aid {
-			edges = append(edges, e)
+		neighbors[e.From] = append(neighbors[e.From], e.To)
+		neighbors[e.To] = append(neighbors[e.To], e.From)
+	}
+
+	dist := map[string]int{from: 0}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == g.OrgAID && current != from {
+			continue
+		}
+		for _, next := range neighbors[current] {
+			if _, seen := dist[next]; !seen {
+				dist[next] = dist[current] + 1
+				queue = append(queue, next)
+			}
 		}
 	}
-	return edges
+	return dist
 }
 
-// HasBidirectionalRelation checks if two AIDs have a bidirectional relationship
-func (g *Graph) HasBidirectionalRelation(aid1, aid2 string) bool {
-	hasForward := false
-	hasReverse := false
+// Subgraph returns the part of the graph within depth hops of an AID,
+// following edges in either direction
+func (g *Graph) Subgraph(aid string, depth int) *Graph {
+	subgraph := NewGraph(g.OrgAID)
 
-	for _, e := range g.Edges {
-		if e.From == aid1 && e.To == aid2 {
-			hasForward = true
+	// BFS to find connected nodes within depth
+	visited := make(map[string]bool)
+	queue := []struct {
+		aid   string
+		depth int
+	}{{aid, 0}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if visited[current.aid] {
+			continue
 		}
-		if e.From == aid2 && e.To == aid1 {
-			hasReverse = true
+		visited[current.aid] = true
+
+		// Add node to subgraph
+		if node := g.GetNode(current.aid); node != nil {
+			subgraph.AddNode(&Node{
+				AID:             node.AID,
+				Alias:           node.Alias,
+				Role:            node.Role,
+				JoinedAt:        node.JoinedAt,
+				CredentialCount: node.CredentialCount,
+			})
+		}
+
+		// If within depth limit, explore neighbors
+		if current.depth < depth {
+			// Add outgoing edges and neighbors
+			for _, edge := range g.GetEdgesFrom(current.aid) {
+				subgraph.AddEdge(edge)
+				if !visited[edge.To] {
+					queue = append(queue, struct {
+						aid   string
+						depth int
+					}{edge.To, current.depth + 1})
+				}
+			}
+
+			// Add incoming edges and neighbors
+			for _, edge := range g.GetEdgesTo(current.aid) {
+				subgraph.AddEdge(edge)
+				if !visited[edge.From] {
+					queue = append(queue, struct {
+						aid   string
+						depth int
+					}{edge.From, current.depth + 1})
+				}
+			}
 		}
 	}
 
-	return hasForward && hasReverse
+	subgraph.MarkBidirectionalEdges()
+	subgraph.Updated = time.Now().UTC()
+
+	return subgraph
+}
+
+// HasBidirectionalRelation checks if two AIDs have a bidirectional relationship
+func (g *Graph) HasBidirectionalRelation(aid1, aid2 string) bool {
+	return g.issued(aid1, aid2) && g.issued(aid2, aid1)
+}
+
+// issued reports whether from issued any credential to to
+func (g *Graph) issued(from, to string) bool {
+	for _, e := range g.outgoing[from] {
+		if e.To == to {
+			return true
+		}
+	}
+	return false
 }
 
 // MarkBidirectionalEdges updates edges to mark bidirectional relationships
@@ -144,25 +269,48 @@ func (g *Graph) EdgeCount() int {
 
 // Score represents a trust score for an individual AID
 type Score struct {
-	AID                    string  `json:"aid"`
-	Alias                  string  `json:"alias,omitempty"`
-	Role                   string  `json:"role,omitempty"`
-	IncomingCredentials    int     `json:"incomingCredentials"`
-	OutgoingCredentials    int     `json:"outgoingCredentials"`
-	UniqueIssuers          int     `json:"uniqueIssuers"`
-	BidirectionalRelations int     `json:"bidirectionalRelations"`
-	GraphDepth             int     `json:"graphDepth"`
-	Score                  float64 `json:"score"`
+	AID                      string  `json:"aid"`
+	Alias                    string  `json:"alias,omitempty"`
+	Role                     string  `json:"role,omitempty"`
+	IncomingCredentials      int     `json:"incomingCredentials"`
+	ParticipationCredentials int     `json:"participationCredentials"`
+	FederatedCredentials     int     `json:"federatedCredentials"`
+	Contributions            int     `json:"contributions"`
+	Endorsements             int     `json:"endorsements"`
+	OutgoingCredentials      int     `json:"outgoingCredentials"`
+	UniqueIssuers            int     `json:"uniqueIssuers"`
+	BidirectionalRelations   int     `json:"bidirectionalRelations"`
+	GraphDepth               int     `json:"graphDepth"`
+	Score                    float64 `json:"score"`
 }
 
 // EdgeType constants for credential types
 const (
-	EdgeTypeMembership = "membership"
-	EdgeTypeSteward    = "steward"
-	EdgeTypeInvitation = "invitation"
-	EdgeTypeSelfClaim  = "self_claim"
+	EdgeTypeMembership  = "membership"
+	EdgeTypeSteward     = "steward"
+	EdgeTypeInvitation  = "invitation"
+	EdgeTypeSelfClaim   = "self_claim"
+	EdgeTypeEndorsement = "endorsement"
+
+	// EdgeTypeParticipation is a low-weight edge for verified attendance or
+	// contribution; it never places a member in the org's trust tree
+	EdgeTypeParticipation = "participation"
+
+	// EdgeTypeFederated is a credential issued by a verified peer org; it is
+	// scored at its own weight and never places a member in the trust tree
+	EdgeTypeFederated = "federated"
 )
 
+// EndorsementSchema is the schema identifier for peer endorsement credentials
+const EndorsementSchema = "EMatouEndorsementSchemaV1"
+
+// AttendanceSchema is the schema identifier for event attendance credentials
+const AttendanceSchema = "EMatouAttendanceSchemaV1"
+
+// ParticipationSchema is the schema identifier for lightweight participation
+// credentials (verified contribution, facilitation, volunteering, ...)
+const ParticipationSchema = "EMatouParticipationSchemaV1"
+
 // SchemaToEdgeType maps credential schemas to edge types
 func SchemaToEdgeType(schema string) string {
 	switch schema {
@@ -174,6 +322,10 @@ func SchemaToEdgeType(schema string) string {
 		return EdgeTypeInvitation
 	case "ESelfClaimSchemaV1":
 		return EdgeTypeSelfClaim
+	case EndorsementSchema:
+		return EdgeTypeEndorsement
+	case AttendanceSchema, ParticipationSchema:
+		return EdgeTypeParticipation
 	default:
 		return "unknown"
 	}
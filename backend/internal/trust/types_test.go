@@ -1,6 +1,7 @@
 package trust
 
 import (
+	"fmt"
 	"testing"
 	"time"
 )
@@ -87,6 +88,9 @@ func TestGraph_AddEdge(t *testing.T) {
 	if len(graph.Edges) != 2 {
 		t.Errorf("expected 2 edges, got %d", len(graph.Edges))
 	}
+	if len(graph.GetEdgesFrom("EORG123")) != 2 || len(graph.GetEdgesTo("EUSER1")) != 1 {
+		t.Error("expected the adjacency indexes to skip the duplicate")
+	}
 }
 
 func TestGraph_GetNode(t *testing.T) {
@@ -316,3 +320,78 @@ func TestScore_Fields(t *testing.T) {
 		t.Errorf("expected Score 10.5, got %f", score.Score)
 	}
 }
+
+func TestGraph_AddNode_MergesAttributes(t *testing.T) {
+	graph := NewGraph("EORG123")
+
+	graph.AddNode(&Node{AID: "EUSER1", Role: "Member", Attributes: map[string]interface{}{"region": "Waikato"}})
+	graph.AddNode(&Node{AID: "EUSER1", Role: "Operations Steward", Attributes: map[string]interface{}{"committee": "finance"}})
+
+	attrs := graph.Nodes["EUSER1"].Attributes
+	if attrs["region"] != "Waikato" || attrs["committee"] != "finance" {
+		t.Errorf("expected merged attributes, got %v", attrs)
+	}
+}
+
+func TestGraph_Distances(t *testing.T) {
+	graph := NewGraph("EORG123")
+	graph.AddEdge(&Edge{From: "EORG123", To: "EUSER1", CredentialID: "C1"})
+	graph.AddEdge(&Edge{From: "EORG123", To: "EUSER2", CredentialID: "C2"})
+	graph.AddEdge(&Edge{From: "EORG123", To: "EUSER4", CredentialID: "C3"})
+	graph.AddEdge(&Edge{From: "EUSER1", To: "EUSER2", CredentialID: "C4"})
+	graph.AddEdge(&Edge{From: "EUSER3", To: "EUSER2", CredentialID: "C5"})
+
+	dist := graph.Distances("EUSER1")
+
+	if dist["EUSER2"] != 1 {
+		t.Errorf("expected EUSER2 at 1 hop, got %d", dist["EUSER2"])
+	}
+	if dist["EUSER3"] != 2 {
+		t.Errorf("expected EUSER3 at 2 hops (reverse edge), got %d", dist["EUSER3"])
+	}
+	if _, ok := dist["EUSER4"]; ok {
+		t.Error("expected EUSER4 unreachable without routing through the org")
+	}
+}
+
+// benchmarkGraph builds a graph of n members, each holding a membership from
+// the org plus an invitation and an endorsement from earlier members
+func benchmarkGraph(n int) *Graph {
+	graph := NewGraph("EORG")
+	graph.AddNode(&Node{AID: "EORG", Role: "Organization"})
+	for i := 0; i < n; i++ {
+		aid := fmt.Sprintf("EUSER%d", i)
+		graph.AddNode(&Node{AID: aid, Role: "Member"})
+		graph.AddEdge(&Edge{From: "EORG", To: aid, CredentialID: "M-" + aid, Type: EdgeTypeMembership})
+		if i > 0 {
+			inviter := fmt.Sprintf("EUSER%d", i/2)
+			graph.AddEdge(&Edge{From: inviter, To: aid, CredentialID: "I-" + aid, Type: EdgeTypeInvitation})
+			graph.AddEdge(&Edge{From: aid, To: inviter, CredentialID: "E-" + aid, Type: EdgeTypeEndorsement})
+		}
+	}
+	graph.MarkBidirectionalEdges()
+	return graph
+}
+
+func BenchmarkGraph_AddEdge(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		benchmarkGraph(5000)
+	}
+}
+
+func BenchmarkGraph_GetEdgesTo(b *testing.B) {
+	graph := benchmarkGraph(5000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		graph.GetEdgesTo(fmt.Sprintf("EUSER%d", i%5000))
+	}
+}
+
+func BenchmarkGraph_HasBidirectionalRelation(b *testing.B) {
+	graph := benchmarkGraph(5000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n := i%4999 + 1
+		graph.HasBidirectionalRelation(fmt.Sprintf("EUSER%d", n), fmt.Sprintf("EUSER%d", n/2))
+	}
+}
@@ -0,0 +1,92 @@
+package types
+
+// CalendarTypeDefinitions returns the built-in community calendar types.
+func CalendarTypeDefinitions() []*TypeDefinition {
+	return []*TypeDefinition{
+		EventType(),
+		EventRSVPType(),
+	}
+}
+
+// EventType returns the Event type definition.
+// Stored in the community read-only space so only admins can schedule
+// events and record attendance; members see them after sync.
+func EventType() *TypeDefinition {
+	maxTitle := 200
+	maxDescription := 2000
+	minCapacity := 0.0
+
+	return &TypeDefinition{
+		Name:        "Event",
+		Version:     1,
+		Description: "A community calendar event",
+		Space:       "community-readonly",
+		Fields: []FieldDef{
+			{Name: "title", Type: "string", Required: true,
+				Validation: &Validation{MaxLength: &maxTitle},
+				UIHints:    &UIHints{InputType: "text", Label: "Title", Section: "event"}},
+			{Name: "description", Type: "string",
+				Validation: &Validation{MaxLength: &maxDescription},
+				UIHints:    &UIHints{InputType: "textarea", Label: "Description", Section: "event"}},
+			{Name: "startsAt", Type: "datetime", Required: true,
+				UIHints: &UIHints{Label: "Starts", Section: "event"}},
+			{Name: "endsAt", Type: "datetime",
+				UIHints: &UIHints{Label: "Ends", Section: "event"}},
+			{Name: "location", Type: "string",
+				UIHints: &UIHints{InputType: "text", Label: "Location", Section: "event"}},
+			{Name: "capacity", Type: "number",
+				Validation: &Validation{Min: &minCapacity},
+				UIHints:    &UIHints{Label: "Capacity", Placeholder: "0 for unlimited", Section: "event"}},
+			{Name: "status", Type: "enum", Required: true,
+				Validation: &Validation{Enum: []string{"scheduled", "cancelled"}},
+				UIHints:    &UIHints{DisplayFormat: "badge", Label: "Status"}},
+			{Name: "issueCredentials", Type: "boolean",
+				UIHints: &UIHints{InputType: "toggle", Label: "Issue Attendance Credentials", Section: "attendance"}},
+			{Name: "attendees", Type: "array", ReadOnly: true,
+				UIHints: &UIHints{DisplayFormat: "chip-list", Label: "Attended", Section: "attendance"}},
+			{Name: "createdBy", Type: "string", ReadOnly: true,
+				UIHints: &UIHints{Label: "Organiser"}},
+			{Name: "createdAt", Type: "datetime", ReadOnly: true,
+				UIHints: &UIHints{DisplayFormat: "relative-date", Label: "Created"}},
+		},
+		Layouts: map[string]Layout{
+			"card":   {Fields: []string{"title", "startsAt", "location", "status"}},
+			"detail": {Fields: []string{"title", "description", "startsAt", "endsAt", "location", "capacity", "status", "attendees"}},
+			"form":   {Fields: []string{"title", "description", "startsAt", "endsAt", "location", "capacity", "issueCredentials"}},
+		},
+		Permissions: TypePermissions{
+			Read:  "community",
+			Write: "admin",
+		},
+	}
+}
+
+// EventRSVPType returns the EventRSVP type definition.
+// Stored in the community space because members can't write to the
+// read-only space; there is one object per member per event.
+func EventRSVPType() *TypeDefinition {
+	return &TypeDefinition{
+		Name:        "EventRSVP",
+		Version:     1,
+		Description: "A member's response to a community event",
+		Space:       "community",
+		Fields: []FieldDef{
+			{Name: "eventId", Type: "string", Required: true, ReadOnly: true,
+				UIHints: &UIHints{Label: "Event"}},
+			{Name: "aid", Type: "string", Required: true, ReadOnly: true,
+				UIHints: &UIHints{Label: "Member"}},
+			{Name: "response", Type: "enum", Required: true,
+				Validation: &Validation{Enum: []string{"going", "maybe", "not_going"}},
+				UIHints:    &UIHints{DisplayFormat: "badge", Label: "Response"}},
+			{Name: "updatedAt", Type: "datetime", ReadOnly: true,
+				UIHints: &UIHints{DisplayFormat: "relative-date", Label: "Responded"}},
+		},
+		Layouts: map[string]Layout{
+			"card": {Fields: []string{"aid", "response"}},
+		},
+		Permissions: TypePermissions{
+			Read:  "community",
+			Write: "owner",
+		},
+	}
+}
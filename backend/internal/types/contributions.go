@@ -0,0 +1,50 @@
+package types
+
+// ContributionType returns the Contribution type definition.
+// Contributions are recorded by stewards against a member's AID in the
+// community space so everyone can see who has done what.
+func ContributionType() *TypeDefinition {
+	maxTitle := 200
+	maxDescription := 2000
+	minHours := 0.0
+
+	return &TypeDefinition{
+		Name:        "Contribution",
+		Version:     1,
+		Description: "A steward-recorded contribution by a community member",
+		Space:       "community",
+		Fields: []FieldDef{
+			{Name: "aid", Type: "string", Required: true, ReadOnly: true,
+				UIHints: &UIHints{Label: "Member"}},
+			{Name: "category", Type: "enum", Required: true,
+				Validation: &Validation{Enum: []string{"code", "facilitation", "content", "other"}},
+				UIHints:    &UIHints{DisplayFormat: "badge", Label: "Category", Section: "contribution"}},
+			{Name: "title", Type: "string", Required: true,
+				Validation: &Validation{MaxLength: &maxTitle},
+				UIHints:    &UIHints{InputType: "text", Label: "Title", Section: "contribution"}},
+			{Name: "description", Type: "string",
+				Validation: &Validation{MaxLength: &maxDescription},
+				UIHints:    &UIHints{InputType: "textarea", Label: "Description", Section: "contribution"}},
+			{Name: "link", Type: "string",
+				UIHints: &UIHints{InputType: "text", Label: "Link", Section: "contribution"}},
+			{Name: "hours", Type: "number",
+				Validation: &Validation{Min: &minHours},
+				UIHints:    &UIHints{Label: "Hours", Section: "contribution"}},
+			{Name: "occurredAt", Type: "datetime",
+				UIHints: &UIHints{Label: "Date", Section: "contribution"}},
+			{Name: "recordedBy", Type: "string", ReadOnly: true,
+				UIHints: &UIHints{Label: "Recorded By"}},
+			{Name: "recordedAt", Type: "datetime", ReadOnly: true,
+				UIHints: &UIHints{DisplayFormat: "relative-date", Label: "Recorded"}},
+		},
+		Layouts: map[string]Layout{
+			"card":   {Fields: []string{"title", "category", "aid", "occurredAt"}},
+			"detail": {Fields: []string{"title", "category", "aid", "description", "link", "hours", "occurredAt", "recordedBy"}},
+			"form":   {Fields: []string{"aid", "category", "title", "description", "link", "hours", "occurredAt"}},
+		},
+		Permissions: TypePermissions{
+			Read:  "community",
+			Write: "admin",
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package types
+
+// EndorsementTypeDefinitions returns the built-in endorsement workflow types.
+func EndorsementTypeDefinitions() []*TypeDefinition {
+	return []*TypeDefinition{
+		EndorsementRequestType(),
+		EndorsementDeclineType(),
+	}
+}
+
+// EndorsementRequestType returns the EndorsementRequest type definition.
+// Stored in the community space so the endorser's backend can see it —
+// the requester writes it, the endorser updates its status on acceptance.
+func EndorsementRequestType() *TypeDefinition {
+	maxMessage := 500
+
+	return &TypeDefinition{
+		Name:        "EndorsementRequest",
+		Version:     1,
+		Description: "A member's request for an endorsement from a specific peer",
+		Space:       "community",
+		Fields: []FieldDef{
+			{Name: "requesterAid", Type: "string", Required: true, ReadOnly: true,
+				UIHints: &UIHints{Label: "Requested By", Section: "request"}},
+			{Name: "endorserAid", Type: "string", Required: true, ReadOnly: true,
+				UIHints: &UIHints{Label: "Endorser", Section: "request"}},
+			{Name: "category", Type: "string", Required: true,
+				UIHints: &UIHints{DisplayFormat: "badge", Label: "Category", Section: "request"}},
+			{Name: "message", Type: "string",
+				Validation: &Validation{MaxLength: &maxMessage},
+				UIHints:    &UIHints{InputType: "textarea", Label: "Message", Section: "request"}},
+			{Name: "status", Type: "enum", Required: true,
+				Validation: &Validation{Enum: []string{"pending", "accepted", "withdrawn"}},
+				UIHints:    &UIHints{DisplayFormat: "badge", Label: "Status"}},
+			{Name: "createdAt", Type: "datetime", ReadOnly: true,
+				UIHints: &UIHints{DisplayFormat: "relative-date", Label: "Requested"}},
+			{Name: "respondedAt", Type: "datetime", ReadOnly: true,
+				UIHints: &UIHints{DisplayFormat: "relative-date", Label: "Responded"}},
+		},
+		Layouts: map[string]Layout{
+			"card":   {Fields: []string{"requesterAid", "category", "status"}},
+			"detail": {Fields: []string{"requesterAid", "endorserAid", "category", "message", "status", "createdAt", "respondedAt"}},
+			"form":   {Fields: []string{"category", "message"}},
+		},
+		Permissions: TypePermissions{
+			Read:  "community",
+			Write: "owner",
+		},
+	}
+}
+
+// EndorsementDeclineType returns the EndorsementDecline type definition.
+// Stored in the endorser's personal space so declines are never visible
+// to the requester or the wider community.
+func EndorsementDeclineType() *TypeDefinition {
+	maxReason := 500
+
+	return &TypeDefinition{
+		Name:        "EndorsementDecline",
+		Version:     1,
+		Description: "Private record of a declined endorsement request",
+		Space:       "private",
+		Fields: []FieldDef{
+			{Name: "requestId", Type: "string", Required: true, ReadOnly: true,
+				UIHints: &UIHints{Label: "Request", Section: "request"}},
+			{Name: "requesterAid", Type: "string", Required: true, ReadOnly: true,
+				UIHints: &UIHints{Label: "Requested By", Section: "request"}},
+			{Name: "category", Type: "string", Required: true, ReadOnly: true,
+				UIHints: &UIHints{DisplayFormat: "badge", Label: "Category", Section: "request"}},
+			{Name: "reason", Type: "string",
+				Validation: &Validation{MaxLength: &maxReason},
+				UIHints:    &UIHints{InputType: "textarea", Label: "Private Note", Section: "request"}},
+			{Name: "declinedAt", Type: "datetime", ReadOnly: true,
+				UIHints: &UIHints{DisplayFormat: "relative-date", Label: "Declined"}},
+		},
+		Layouts: map[string]Layout{
+			"card":   {Fields: []string{"requesterAid", "category", "declinedAt"}},
+			"detail": {Fields: []string{"requesterAid", "category", "reason", "declinedAt"}},
+		},
+		Permissions: TypePermissions{
+			Read:  "owner",
+			Write: "owner",
+		},
+	}
+}
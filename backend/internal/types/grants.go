@@ -0,0 +1,94 @@
+package types
+
+// GrantTypeDefinitions returns the built-in treasury grant types.
+func GrantTypeDefinitions() []*TypeDefinition {
+	return []*TypeDefinition{
+		GrantRequestType(),
+		GrantSummaryType(),
+	}
+}
+
+// GrantRequestType returns the GrantRequest type definition.
+// Stored in the admin space so only stewards see recipients and review
+// notes; a redacted GrantSummary is published for members.
+func GrantRequestType() *TypeDefinition {
+	maxPurpose := 2000
+	minAmount := 0.0
+
+	return &TypeDefinition{
+		Name:        "GrantRequest",
+		Version:     1,
+		Description: "A treasury grant request under steward review",
+		Space:       "admin",
+		Fields: []FieldDef{
+			{Name: "recipient", Type: "string", Required: true,
+				UIHints: &UIHints{Label: "Recipient", Section: "grant"}},
+			{Name: "amount", Type: "number", Required: true,
+				Validation: &Validation{Min: &minAmount},
+				UIHints:    &UIHints{Label: "Amount", Section: "grant"}},
+			{Name: "currency", Type: "string", Required: true,
+				UIHints: &UIHints{InputType: "text", Label: "Currency", Placeholder: "NZD", Section: "grant"}},
+			{Name: "purpose", Type: "string", Required: true,
+				Validation: &Validation{MaxLength: &maxPurpose},
+				UIHints:    &UIHints{InputType: "textarea", Label: "Purpose", Section: "grant"}},
+			{Name: "status", Type: "enum", Required: true,
+				Validation: &Validation{Enum: []string{"submitted", "in_review", "approved", "rejected", "paid"}},
+				UIHints:    &UIHints{DisplayFormat: "badge", Label: "Status", Section: "review"}},
+			{Name: "proposalId", Type: "string",
+				UIHints: &UIHints{InputType: "text", Label: "Governance Proposal", Section: "review"}},
+			{Name: "reviews", Type: "array", ReadOnly: true,
+				UIHints: &UIHints{Label: "Review History", Section: "review"}},
+			{Name: "submittedBy", Type: "string", ReadOnly: true,
+				UIHints: &UIHints{Label: "Submitted By"}},
+			{Name: "submittedAt", Type: "datetime", ReadOnly: true,
+				UIHints: &UIHints{DisplayFormat: "relative-date", Label: "Submitted"}},
+			{Name: "updatedAt", Type: "datetime", ReadOnly: true,
+				UIHints: &UIHints{DisplayFormat: "relative-date", Label: "Updated"}},
+		},
+		Layouts: map[string]Layout{
+			"card":   {Fields: []string{"purpose", "amount", "currency", "status"}},
+			"detail": {Fields: []string{"recipient", "amount", "currency", "purpose", "status", "proposalId", "reviews", "submittedBy", "submittedAt"}},
+			"form":   {Fields: []string{"recipient", "amount", "currency", "purpose", "proposalId"}},
+		},
+		Permissions: TypePermissions{
+			Read:  "admin",
+			Write: "admin",
+		},
+	}
+}
+
+// GrantSummaryType returns the GrantSummary type definition.
+// The public face of a GrantRequest in the community read-only space,
+// without the recipient or review notes.
+func GrantSummaryType() *TypeDefinition {
+	return &TypeDefinition{
+		Name:        "GrantSummary",
+		Version:     1,
+		Description: "A redacted public summary of a treasury grant",
+		Space:       "community-readonly",
+		Fields: []FieldDef{
+			{Name: "grantId", Type: "string", Required: true, ReadOnly: true,
+				UIHints: &UIHints{Label: "Grant"}},
+			{Name: "amount", Type: "number", Required: true, ReadOnly: true,
+				UIHints: &UIHints{Label: "Amount"}},
+			{Name: "currency", Type: "string", Required: true, ReadOnly: true,
+				UIHints: &UIHints{Label: "Currency"}},
+			{Name: "purpose", Type: "string", Required: true, ReadOnly: true,
+				UIHints: &UIHints{Label: "Purpose"}},
+			{Name: "status", Type: "enum", Required: true, ReadOnly: true,
+				Validation: &Validation{Enum: []string{"submitted", "in_review", "approved", "rejected", "paid"}},
+				UIHints:    &UIHints{DisplayFormat: "badge", Label: "Status"}},
+			{Name: "proposalId", Type: "string", ReadOnly: true,
+				UIHints: &UIHints{Label: "Governance Proposal"}},
+			{Name: "updatedAt", Type: "datetime", ReadOnly: true,
+				UIHints: &UIHints{DisplayFormat: "relative-date", Label: "Updated"}},
+		},
+		Layouts: map[string]Layout{
+			"card": {Fields: []string{"purpose", "amount", "currency", "status"}},
+		},
+		Permissions: TypePermissions{
+			Read:  "community",
+			Write: "admin",
+		},
+	}
+}
@@ -0,0 +1,51 @@
+package types
+
+// ProjectType returns the Project type definition.
+// Projects and working groups live in the community space so every member
+// can browse them; each project may also own a dedicated any-sync space
+// (spaceId) that holds its files and is shared only with its roster.
+func ProjectType() *TypeDefinition {
+	maxName := 100
+	maxDescription := 2000
+
+	return &TypeDefinition{
+		Name:        "Project",
+		Version:     1,
+		Description: "A project or working group with a membership roster",
+		Space:       "community",
+		Fields: []FieldDef{
+			{Name: "name", Type: "string", Required: true,
+				Validation: &Validation{MaxLength: &maxName},
+				UIHints:    &UIHints{InputType: "text", Label: "Name", Section: "project"}},
+			{Name: "description", Type: "string",
+				Validation: &Validation{MaxLength: &maxDescription},
+				UIHints:    &UIHints{InputType: "textarea", Label: "Description", Section: "project"}},
+			{Name: "status", Type: "enum", Required: true,
+				Validation: &Validation{Enum: []string{"active", "archived"}},
+				UIHints:    &UIHints{DisplayFormat: "badge", Label: "Status", Section: "project"}},
+			{Name: "requiredRoles", Type: "array",
+				UIHints: &UIHints{InputType: "tags", DisplayFormat: "chip-list", Label: "Roles That Can Join", Section: "membership"}},
+			{Name: "members", Type: "array",
+				UIHints: &UIHints{DisplayFormat: "chip-list", Label: "Roster", Section: "membership"}},
+			{Name: "spaceId", Type: "string", ReadOnly: true,
+				UIHints: &UIHints{Label: "Project Space", Section: "files"}},
+			{Name: "files", Type: "array",
+				UIHints: &UIHints{DisplayFormat: "chip-list", Label: "Files", Section: "files"}},
+			{Name: "createdBy", Type: "string", ReadOnly: true,
+				UIHints: &UIHints{Label: "Created By"}},
+			{Name: "createdAt", Type: "datetime", ReadOnly: true,
+				UIHints: &UIHints{DisplayFormat: "relative-date", Label: "Created"}},
+			{Name: "updatedAt", Type: "datetime", ReadOnly: true,
+				UIHints: &UIHints{DisplayFormat: "relative-date", Label: "Updated"}},
+		},
+		Layouts: map[string]Layout{
+			"card":   {Fields: []string{"name", "status", "members"}},
+			"detail": {Fields: []string{"name", "description", "status", "requiredRoles", "members", "files", "createdBy", "createdAt"}},
+			"form":   {Fields: []string{"name", "description", "requiredRoles"}},
+		},
+		Permissions: TypePermissions{
+			Read:  "community",
+			Write: "owner",
+		},
+	}
+}
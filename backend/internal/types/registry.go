@@ -37,12 +37,24 @@ func NewRegistry() *Registry {
 }
 
 // Bootstrap registers the hardcoded meta-type (type_definition) and all
-// built-in profile type definitions. Call this during org setup.
+// built-in profile, endorsement, taxonomy, project, calendar, contribution and grant type definitions. Call this during org setup.
 func (r *Registry) Bootstrap() {
 	r.Register(MetaTypeDefinition())
 	for _, def := range ProfileTypeDefinitions() {
 		r.Register(def)
 	}
+	for _, def := range EndorsementTypeDefinitions() {
+		r.Register(def)
+	}
+	r.Register(TaxonomyType())
+	r.Register(ProjectType())
+	for _, def := range CalendarTypeDefinitions() {
+		r.Register(def)
+	}
+	r.Register(ContributionType())
+	for _, def := range GrantTypeDefinitions() {
+		r.Register(def)
+	}
 }
 
 // Register adds or replaces a type definition in the registry.
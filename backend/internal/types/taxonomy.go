@@ -0,0 +1,32 @@
+package types
+
+// TaxonomyType returns the Taxonomy type definition.
+// Stored in the community read-only space — admins write, all members read.
+// One object per vocabulary kind (e.g. "Taxonomy-skills").
+func TaxonomyType() *TypeDefinition {
+	return &TypeDefinition{
+		Name:        "Taxonomy",
+		Version:     1,
+		Description: "Controlled vocabulary for profile tags such as skills and interests",
+		Space:       "community-readonly",
+		Fields: []FieldDef{
+			{Name: "kind", Type: "enum", Required: true, ReadOnly: true,
+				Validation: &Validation{Enum: []string{"skills", "interests"}},
+				UIHints:    &UIHints{Label: "Vocabulary"}},
+			{Name: "terms", Type: "array", Required: true,
+				UIHints: &UIHints{InputType: "tags", DisplayFormat: "chip-list", Label: "Terms"}},
+			{Name: "updatedAt", Type: "datetime", ReadOnly: true,
+				UIHints: &UIHints{DisplayFormat: "relative-date", Label: "Updated"}},
+			{Name: "updatedBy", Type: "string", ReadOnly: true,
+				UIHints: &UIHints{Label: "Updated By"}},
+		},
+		Layouts: map[string]Layout{
+			"detail": {Fields: []string{"kind", "terms", "updatedAt"}},
+			"form":   {Fields: []string{"terms"}},
+		},
+		Permissions: TypePermissions{
+			Read:  "community",
+			Write: "admin",
+		},
+	}
+}
@@ -9,6 +9,7 @@ import path from 'path';
 import { fileURLToPath } from 'url';
 import net from 'net';
 import fs from 'fs';
+import crypto from 'crypto';
 
 // ESM compatibility: __dirname is not available in ES modules
 const __dirname = path.dirname(fileURLToPath(import.meta.url));
@@ -129,12 +130,16 @@ async function startBackend(): Promise<void> {
   console.log(`[Electron] Port: ${backendPort}, Data dir: ${dataDir}`);
   console.log(`[Electron] Production mode: ${isProduction}`);
 
+  const passphrase = getBackendPassphrase();
+  console.log(`[Electron] At-rest encryption: ${passphrase ? 'on' : 'off (no OS keyring)'}`);
+
   backendProcess = spawn(backendPath, [], {
     env: {
       ...process.env,
       MATOU_SERVER_PORT: String(backendPort),
       MATOU_DATA_DIR: dataDir,
       MATOU_CORS_MODE: 'bundled',
+      ...(passphrase && { MATOU_IDENTITY_PASSPHRASE: passphrase }),
       ...(isProduction && {
         MATOU_ENV: 'production',
         MATOU_CONFIG_SERVER_URL: process.env.PROD_CONFIG_SERVER_URL,
@@ -272,6 +277,35 @@ function writeSecureStore(store: Record<string, string>): void {
   fs.writeFileSync(secureStorePath, JSON.stringify(store, null, 2), 'utf-8');
 }
 
+const backendPassphraseKey = 'backend-identity-passphrase';
+
+/**
+ * Get the passphrase the backend encrypts identity.json and space keys with,
+ * creating it on first launch. It is kept in the secure store, so the OS
+ * keyring protects it. Returns undefined when no keyring is available: a
+ * plaintext passphrase next to the data would protect nothing.
+ */
+function getBackendPassphrase(): string | undefined {
+  if (!safeStorage.isEncryptionAvailable()) return undefined;
+
+  const store = readSecureStore();
+  const existing = store[backendPassphraseKey];
+  if (existing !== undefined) {
+    try {
+      return safeStorage.decryptString(Buffer.from(existing, 'base64'));
+    } catch (err) {
+      // Don't replace it: files sealed with the old passphrase would be lost
+      console.warn('[SecureStorage] Failed to decrypt backend passphrase:', err);
+      return undefined;
+    }
+  }
+
+  const passphrase = crypto.randomBytes(32).toString('base64');
+  store[backendPassphraseKey] = safeStorage.encryptString(passphrase).toString('base64');
+  writeSecureStore(store);
+  return passphrase;
+}
+
 ipcMain.handle('secure-storage-get', (_event, key: string): string | null => {
   const store = readSecureStore();
   const value = store[key];
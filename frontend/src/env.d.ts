@@ -11,6 +11,8 @@ interface ImportMetaEnv {
   readonly VITE_PROD_CONFIG_URL?: string;
   /** Backend API URL (Go server) */
   readonly VITE_BACKEND_URL?: string;
+  /** 'true' sends signify requests through the backend's KERIA proxy */
+  readonly VITE_KERIA_VIA_BACKEND?: string;
 }
 
 interface ImportMeta {
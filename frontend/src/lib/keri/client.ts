@@ -6,6 +6,7 @@ import { SignifyClient, Tier, randomPasscode, ready, Salter } from 'signify-ts';
 import { mnemonicToSeedSync, mnemonicToEntropy, entropyToMnemonic, validateMnemonic } from '@scure/bip39';
 import { wordlist } from '@scure/bip39/wordlists/english.js';
 import { fetchClientConfig, type ClientConfig } from '../clientConfig';
+import { getBackendUrlSync } from '../platform';
 
 export interface AIDInfo {
   prefix: string; // The AID string (e.g., "EAbcd...")
@@ -36,13 +37,20 @@ export async function initKeriConfig(): Promise<ClientConfig> {
 }
 
 /**
- * Get KERIA URLs from cached config or defaults
+ * Get KERIA URLs from cached config or defaults.
+ * With VITE_KERIA_VIA_BACKEND=true, signify goes through the backend's
+ * KERIA proxy, so only the backend port needs to be reachable.
  */
 function getKeriaUrls() {
+  const cesrUrl = clientConfig?.keri.cesr_url || 'http://localhost:3902';
+  if (import.meta.env.VITE_KERIA_VIA_BACKEND === 'true') {
+    const proxyUrl = `${getBackendUrlSync()}/api/v1/keria`;
+    return { adminUrl: proxyUrl, bootUrl: proxyUrl, cesrUrl };
+  }
   return {
     adminUrl: clientConfig?.keri.admin_url || 'http://localhost:3901',
     bootUrl: clientConfig?.keri.boot_url || 'http://localhost:3903',
-    cesrUrl: clientConfig?.keri.cesr_url || 'http://localhost:3902',
+    cesrUrl,
   };
 }
 